@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/capping"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/powerevent"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+// createDeviceControls returns the services controlling the devices of the
+// node, i.e. capping its power and watching the power events of its BMC, along
+// with the cap of the node and the counts of the power events, nil unless they
+// are watched
+func createDeviceControls(logger *slog.Logger, cfg *config.Config, apiServer *server.APIServer) (
+	services []service.Service, nodeCap func() device.Power, powerEvents func() []powerevent.Count, err error,
+) {
+	logger.Debug("Creating device controls")
+
+	// report the power events logged by the BMC in the logs, over REST and as
+	// metrics
+	if *cfg.Redfish.Enabled && *cfg.Redfish.Events.Enabled {
+		zone, err := createRedfishZone(cfg)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create Redfish zone for power events: %w", err)
+		}
+		watcher := powerevent.NewWatcher(zone, apiServer,
+			powerevent.WithLogger(logger),
+			powerevent.WithInterval(cfg.Redfish.Events.Interval),
+		)
+		services = append(services, watcher)
+		powerEvents = watcher.Counts
+	}
+
+	// cap the power of the node, set over the API by external controllers
+	nodeCap = func() device.Power { return device.Power(cfg.Headroom.CapWatts) * device.Watt }
+	if *cfg.PowerCap.Enabled {
+		limiter, err := createPowerLimiter(cfg)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create power limiter: %w", err)
+		}
+		controller := capping.NewController(limiter, apiServer,
+			capping.WithLogger(logger),
+			capping.WithCap(device.Power(cfg.PowerCap.Watts)*device.Watt),
+			capping.WithDryRun(*cfg.PowerCap.DryRun),
+			capping.WithWritableAPI(*cfg.PowerCap.AllowAPI),
+		)
+		services = append(services, controller)
+		nodeCap = controller.Cap
+	}
+
+	return services, nodeCap, powerEvents, nil
+}
+
+// createPowerLimiter returns the limiter the power of the node is capped with
+func createPowerLimiter(cfg *config.Config) (device.PowerLimiter, error) {
+	if cfg.PowerCap.Limiter == config.PowerCapLimiterRedfish {
+		return createRedfishZone(cfg)
+	}
+	return device.NewRaplPowerLimiter(cfg.Host.SysFS)
+}
@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/anomaly"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/exporter/live"
+	"github.com/sustainable-computing-io/kepler/internal/exporter/mqtt"
+	"github.com/sustainable-computing-io/kepler/internal/exporter/prometheus"
+	"github.com/sustainable-computing-io/kepler/internal/exporter/push"
+	"github.com/sustainable-computing-io/kepler/internal/exporter/stdout"
+	"github.com/sustainable-computing-io/kepler/internal/exporter/textfile"
+	"github.com/sustainable-computing-io/kepler/internal/exporter/vm"
+	"github.com/sustainable-computing-io/kepler/internal/exporter/webhook"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/powerevent"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+// createExporters returns the services exporting the power of the monitor, with
+// the series of the anomalies and the counts of the power events in the
+// metrics if they are reported
+func createExporters(logger *slog.Logger, cfg *config.Config, pm *monitor.PowerMonitor, apiServer *server.APIServer,
+	events *event.Bus, platformZone device.EnergyZone, anomalies func() []anomaly.Series, powerEvents func() []powerevent.Count,
+) ([]service.Service, error) {
+	logger.Debug("Creating exporters")
+	var services []service.Service
+
+	// the collectors are shared by the Prometheus and textfile exporters, as
+	// the power collector waits for the first collection of the monitor
+	var collectors map[string]prom.Collector
+	if *cfg.Exporter.Prometheus.Enabled || *cfg.Exporter.Textfile.Enabled {
+		var err error
+		collectors, err = createPrometheusCollectors(logger, cfg, pm, platformZone, anomalies, powerEvents)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Prometheus collectors: %w", err)
+		}
+	}
+
+	// Add Prometheus exporter if enabled
+	if *cfg.Exporter.Prometheus.Enabled {
+		services = append(services, prometheus.NewExporter(pm, apiServer,
+			prometheus.WithLogger(logger),
+			prometheus.WithCollectors(collectors),
+			prometheus.WithDebugCollectors(cfg.Exporter.Prometheus.DebugCollectors),
+		))
+	}
+
+	// write the metrics to a file read by node_exporter
+	if *cfg.Exporter.Textfile.Enabled {
+		services = append(services, textfile.NewExporter(pm, cfg.Exporter.Textfile.Path,
+			textfile.WithLogger(logger),
+			textfile.WithInterval(cfg.Exporter.Textfile.Interval),
+			textfile.WithCollectors(collectors),
+		))
+	}
+
+	// Add stdout exporter if enabled
+	if *cfg.Exporter.Stdout.Enabled {
+		stdoutExporter := stdout.NewExporter(pm,
+			stdout.WithLogger(logger),
+			stdout.WithInterval(cfg.Exporter.Stdout.Interval),
+		)
+		services = append(services, stdoutExporter)
+	}
+
+	// serve the power of each VM to Kepler running in the VM
+	if *cfg.Exporter.VM.Enabled {
+		services = append(services, vm.NewExporter(pm, apiServer, vm.WithLogger(logger)))
+	}
+
+	// push an energy summary on shutdown, e.g. from short-lived batch nodes
+	if *cfg.Exporter.Push.Enabled {
+		pushCfg := cfg.Exporter.Push
+		services = append(services, push.NewExporter(pm, pushCfg.URL,
+			push.WithLogger(logger),
+			push.WithFormat(pushCfg.Format),
+			push.WithJob(pushCfg.Job),
+			push.WithNodeName(cfg.Kube.Node),
+			push.WithMetricsLevel(cfg.Exporter.Prometheus.MetricsLevel),
+			push.WithInterval(pushCfg.Interval),
+			push.WithSampleInterval(cfg.Monitor.Interval),
+			push.WithMaxTerminated(cfg.Monitor.MaxTerminated),
+			push.WithEventBus(events),
+		))
+	}
+
+	// post an energy report of each container that exits, e.g. to CI systems
+	if *cfg.Exporter.Webhook.Enabled {
+		services = append(services, webhook.NewExporter(pm, cfg.Exporter.Webhook.URL,
+			webhook.WithLogger(logger),
+			webhook.WithNodeName(cfg.Kube.Node),
+			webhook.WithSampleInterval(cfg.Monitor.Interval),
+			webhook.WithEventBus(events),
+		))
+	}
+
+	// stream the power of each collection to real-time dashboards
+	if *cfg.Exporter.Live.Enabled {
+		services = append(services, live.NewExporter(pm, apiServer,
+			live.WithLogger(logger),
+			live.WithInterval(cfg.Monitor.Interval),
+			live.WithEventBus(events),
+		))
+	}
+
+	// publish the power to an MQTT broker, e.g. for edge and IoT fleets
+	if *cfg.Exporter.MQTT.Enabled {
+		mqttExporter, err := createMQTTExporter(logger, cfg, pm, events)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create MQTT exporter: %w", err)
+		}
+		services = append(services, mqttExporter)
+	}
+
+	return services, nil
+}
+
+// createPrometheusCollectors returns the collectors of the metrics of the
+// Prometheus and textfile exporters
+func createPrometheusCollectors(logger *slog.Logger, cfg *config.Config, pm *monitor.PowerMonitor, platformZone device.EnergyZone,
+	anomalies func() []anomaly.Series, powerEvents func() []powerevent.Count,
+) (map[string]prom.Collector, error) {
+	logger.Debug("Creating Prometheus collectors")
+
+	return prometheus.CreateCollectors(
+		pm,
+		prometheus.WithLogger(logger),
+		prometheus.WithProcFSPath(cfg.Host.ProcFS),
+		prometheus.WithSysFSPath(cfg.Host.SysFS),
+		prometheus.WithNodeName(cfg.Kube.Node),
+		prometheus.WithMetricsLevel(cfg.Exporter.Prometheus.MetricsLevel),
+		prometheus.WithCarbon(cfg.Carbon.Provider != config.CarbonProviderNone),
+		prometheus.WithCost(pricingEnabled(cfg)),
+		prometheus.WithPowerRange(cfg.Monitor.SampleInterval > 0),
+		prometheus.WithCalibration(len(cfg.Monitor.Calibration) > 0),
+		prometheus.WithHWCounters(*cfg.Monitor.PerfEvents),
+		prometheus.WithIO(*cfg.Monitor.IO.Enabled),
+		prometheus.WithProcessInfo(*cfg.Monitor.ProcessMetadata),
+		prometheus.WithContainerInfo(cfg.Host.CRI != "" || len(cfg.Host.Docker) > 0),
+		prometheus.WithPodInfo(podMetadataEnabled(cfg)),
+		prometheus.WithSystemdUnits(*cfg.Monitor.SystemdUnits),
+		prometheus.WithGroups(len(cfg.Monitor.Groups) > 0),
+		prometheus.WithAggregates(*cfg.Monitor.Aggregates),
+		prometheus.WithBudgets(cfg.Budget.Node > 0 || len(cfg.Budget.Namespaces) > 0),
+		prometheus.WithMaxProcesses(cfg.Exporter.Prometheus.MaxProcesses),
+		prometheus.WithPlatformSources(platformSources(platformZone)),
+		prometheus.WithAnomalies(anomalies),
+		prometheus.WithPowerEvents(powerEvents),
+		prometheus.WithZoneSources(pm.SourceOf),
+		prometheus.WithGPUZones(pm.IsGPUZone),
+	)
+}
+
+// platformSources returns the status of the sources of the platform zone, or
+// nil if the platform is not monitored
+func platformSources(zone device.EnergyZone) func() []device.PlatformSourceStatus {
+	if platform, ok := zone.(*device.PlatformZone); ok {
+		return platform.Sources
+	}
+	return nil
+}
+
+// createMQTTExporter returns the MQTT exporter configured, with the password
+// and the TLS certificates read from their files
+func createMQTTExporter(logger *slog.Logger, cfg *config.Config, pm *monitor.PowerMonitor, events *event.Bus) (*mqtt.Exporter, error) {
+	mqttCfg := cfg.Exporter.MQTT
+	var password string
+	if mqttCfg.PasswordFile != "" {
+		var err error
+		if password, err = readSecret(mqttCfg.PasswordFile); err != nil {
+			return nil, err
+		}
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: *mqttCfg.TLS.InsecureSkipVerify}
+	if mqttCfg.TLS.CAFile != "" {
+		pem, err := os.ReadFile(mqttCfg.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA file %s", mqttCfg.TLS.CAFile)
+		}
+	}
+	if mqttCfg.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(mqttCfg.TLS.CertFile, mqttCfg.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	opts := []mqtt.OptionFn{
+		mqtt.WithLogger(logger),
+		mqtt.WithNodeName(cfg.Kube.Node),
+		mqtt.WithTopic(mqttCfg.Topic),
+		mqtt.WithQoS(byte(mqttCfg.QoS)),
+		mqtt.WithRetain(*mqttCfg.Retain),
+		mqtt.WithClientID(mqttCfg.ClientID),
+		mqtt.WithCredentials(mqttCfg.Username, password),
+		mqtt.WithTLSConfig(tlsConfig),
+		mqtt.WithMetricsLevel(cfg.Exporter.Prometheus.MetricsLevel),
+		mqtt.WithInterval(mqttCfg.Interval),
+		mqtt.WithEventBus(events),
+	}
+	if *mqttCfg.Deltas {
+		opts = append(opts, mqtt.WithDeltas(mqttCfg.FullSnapshotEvery))
+	}
+	return mqtt.NewExporter(pm, mqttCfg.Broker, opts...), nil
+}
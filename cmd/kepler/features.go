@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/anomaly"
+	"github.com/sustainable-computing-io/kepler/internal/chargeback"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/federation"
+	"github.com/sustainable-computing-io/kepler/internal/headroom"
+	"github.com/sustainable-computing-io/kepler/internal/history"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/rightsizing"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+// createAPIFeatures returns the services analysing the power over REST and, if
+// served, as MCP tools, along with the series of the anomalies and the store
+// of the history, nil unless they are enabled
+func createAPIFeatures(logger *slog.Logger, cfg *config.Config, pm *monitor.PowerMonitor, apiServer *server.APIServer,
+	mcp *server.MCP, events *event.Bus, nodeCap func() device.Power,
+) (services []service.Service, anomalies func() []anomaly.Series, historyStore history.Store, err error) {
+	logger.Debug("Creating API features")
+
+	// flag abnormal jumps of the power of the node and its top workloads in
+	// the logs, over REST, as an MCP tool and as metrics
+	if *cfg.Anomaly.Enabled {
+		detector := anomaly.NewDetector(pm, apiServer,
+			anomaly.WithLogger(logger),
+			anomaly.WithSampleInterval(cfg.Monitor.Interval),
+			anomaly.WithThreshold(cfg.Anomaly.Threshold),
+			anomaly.WithAlpha(cfg.Anomaly.Alpha),
+			anomaly.WithWorkloads(cfg.Anomaly.Workloads),
+			anomaly.WithTools(mcp),
+			anomaly.WithEventBus(events),
+		)
+		services = append(services, detector)
+		anomalies = detector.Series
+	}
+
+	// report over-provisioned pods in the logs, over REST and as an MCP tool
+	if *cfg.Rightsizing.Enabled {
+		services = append(services, rightsizing.NewReporter(pm, apiServer,
+			rightsizing.WithLogger(logger),
+			rightsizing.WithInterval(cfg.Rightsizing.Interval),
+			rightsizing.WithSampleInterval(cfg.Monitor.Interval),
+			rightsizing.WithTools(mcp),
+			rightsizing.WithEventBus(events),
+		))
+	}
+
+	// report the energy of the pods of each namespace every period in the
+	// logs, over REST and as an MCP tool
+	if *cfg.Chargeback.Enabled {
+		services = append(services, chargeback.NewReporter(pm, apiServer,
+			chargeback.WithLogger(logger),
+			chargeback.WithInterval(cfg.Chargeback.Interval),
+			chargeback.WithSampleInterval(cfg.Monitor.Interval),
+			chargeback.WithTopPods(cfg.Chargeback.TopPods),
+			chargeback.WithTools(mcp),
+			chargeback.WithEventBus(events),
+		))
+	}
+
+	// aggregate the snapshots of other agents into the power of the cluster,
+	// served over REST and as MCP tools
+	if *cfg.Federation.Enabled {
+		agents := make([]federation.Agent, 0, len(cfg.Federation.Agents))
+		for _, a := range cfg.Federation.Agents {
+			agents = append(agents, federation.Agent{Name: a.Name, URL: a.URL})
+		}
+		services = append(services, federation.NewAggregator(agents, apiServer,
+			federation.WithLogger(logger),
+			federation.WithInterval(cfg.Federation.Interval),
+			federation.WithTimeout(cfg.Federation.Timeout),
+			federation.WithTools(mcp),
+		))
+	}
+
+	// record the power of each interval and roll it up by hour and day, served
+	// over REST and as MCP tools
+	if *cfg.History.Enabled {
+		historyStore, err = createHistoryStore(cfg)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create history store: %w", err)
+		}
+		services = append(services, history.NewRecorder(pm, historyStore, apiServer,
+			history.WithLogger(logger),
+			history.WithInterval(cfg.Monitor.Interval),
+			history.WithRetention(cfg.History.Retention),
+			history.WithRollupRetention(cfg.History.Rollups.HourlyRetention, cfg.History.Rollups.DailyRetention),
+			history.WithMetricsLevel(cfg.History.MetricsLevel),
+			history.WithTools(mcp),
+			history.WithEventBus(events),
+		))
+	}
+
+	// serve the power, cap and trend of the node to energy-aware schedulers,
+	// and whether it can take more load as an MCP tool
+	if *cfg.Headroom.Enabled {
+		headroomOpts := []headroom.OptionFn{
+			headroom.WithLogger(logger),
+			headroom.WithWindow(cfg.Headroom.Window),
+			headroom.WithSampleInterval(cfg.Monitor.Interval),
+			headroom.WithNodeName(cfg.Kube.Node),
+			headroom.WithCap(nodeCap),
+			headroom.WithTools(mcp),
+			headroom.WithEventBus(events),
+		}
+		// the capacity of the node is reported by its BMC
+		if *cfg.Redfish.Enabled {
+			zone, err := createRedfishZone(cfg)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to create Redfish zone: %w", err)
+			}
+			headroomOpts = append(headroomOpts, headroom.WithCapacity(zone.PowerCapacity))
+		}
+		services = append(services, headroom.NewReporter(pm, apiServer, headroomOpts...))
+	}
+
+	return services, anomalies, historyStore, nil
+}
+
+// createHistoryStore returns a SQLite store at the history path, or a memory
+// store if the path is empty
+func createHistoryStore(cfg *config.Config) (history.Store, error) {
+	if cfg.History.Path == "" {
+		return history.NewMemoryStore(), nil
+	}
+	return history.NewSQLiteStore(cfg.History.Path)
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/sustainable-computing-io/kepler/internal/inspect"
+)
+
+// inspectCommands are the commands that inspect a running Kepler instead of
+// running one
+var inspectCommands = []string{"top", "show"}
+
+// clearScreen moves the cursor to the top left and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// runInspect runs the inspect command of args, writing to out
+func runInspect(args []string, out io.Writer) error {
+	app := kingpin.New("kepler", "Inspect the power measured by a running Kepler.")
+	server := app.Flag("server", "URL of the web server of the Kepler to inspect").Default("http://localhost:28282").String()
+	timeout := app.Flag("timeout", "Timeout of requests to Kepler").Default("10s").Duration()
+
+	top := app.Command("top", "Show the power of the node and the workloads consuming the most power")
+	topKind := top.Flag("kind", "Kind of workloads shown").Default(inspect.KindPod).Enum(inspect.Kinds...)
+	topCount := top.Flag("count", "Number of workloads shown; 0 shows all").Short('n').Default("10").Int()
+	topZone := top.Flag("zone", "Zone workloads are ranked by, e.g. package; the zone each consumes the most in if empty").String()
+	topWatch := top.Flag("watch", "Interval between refreshes; 0 shows once").Short('w').Default("0s").Duration()
+
+	show := app.Command("show", "Show the power of the node in each zone, or the details of a workload")
+	showKind := show.Arg("kind", "node, process, container, vm or pod").Required().Enum(append([]string{inspect.KindNode}, inspect.Kinds...)...)
+	showID := show.Arg("id", "ID, ID prefix or name of the workload").String()
+
+	cmd, err := app.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	client := &http.Client{Timeout: *timeout}
+
+	switch cmd {
+	case top.FullCommand():
+		render := func() error {
+			p, err := inspect.Fetch(ctx, client, *server, *topKind)
+			if err != nil {
+				return err
+			}
+			inspect.WriteNode(out, p)
+			inspect.WriteWorkloads(out, inspect.Top(p.Workloads, *topZone, *topCount), *topZone)
+			return nil
+		}
+		if *topWatch <= 0 {
+			return render()
+		}
+
+		ticker := time.NewTicker(*topWatch)
+		defer ticker.Stop()
+		for {
+			_, _ = fmt.Fprint(out, clearScreen)
+			if err := render(); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+
+	case show.FullCommand():
+		if *showKind == inspect.KindNode {
+			p, err := inspect.Fetch(ctx, client, *server, inspect.KindNode)
+			if err != nil {
+				return err
+			}
+			inspect.WriteNode(out, p)
+			return nil
+		}
+		if *showID == "" {
+			return fmt.Errorf("the ID or name of the %s to show is required", *showKind)
+		}
+
+		p, err := inspect.Fetch(ctx, client, *server, *showKind)
+		if err != nil {
+			return err
+		}
+		w, err := inspect.Find(p.Workloads, *showKind, *showID)
+		if err != nil {
+			return err
+		}
+		inspect.WriteWorkload(out, w)
+	}
+	return nil
+}
@@ -4,29 +4,62 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/budget"
+	"github.com/sustainable-computing-io/kepler/internal/carbon"
 	"github.com/sustainable-computing-io/kepler/internal/device"
-	"github.com/sustainable-computing-io/kepler/internal/exporter/prometheus"
-	"github.com/sustainable-computing-io/kepler/internal/exporter/stdout"
+	"github.com/sustainable-computing-io/kepler/internal/device/gpu"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/inspect"
 	"github.com/sustainable-computing-io/kepler/internal/k8s/pod"
 	"github.com/sustainable-computing-io/kepler/internal/logger"
 	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/pricing"
+	"github.com/sustainable-computing-io/kepler/internal/query"
 	"github.com/sustainable-computing-io/kepler/internal/resource"
 	"github.com/sustainable-computing-io/kepler/internal/server"
 	"github.com/sustainable-computing-io/kepler/internal/service"
+	"github.com/sustainable-computing-io/kepler/internal/state"
 	"github.com/sustainable-computing-io/kepler/internal/version"
+	"k8s.io/utils/ptr"
 )
 
 func main() {
+	// top and show inspect a running Kepler
+	if len(os.Args) > 1 && slices.Contains(inspectCommands, os.Args[1]) {
+		if err := runInspect(os.Args[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "kepler: error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// measure runs a command and prints the energy it consumed, like time(1)
+	if len(os.Args) > 1 && os.Args[1] == measureCommand {
+		code, err := runMeasure(os.Args[1:], os.Stderr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kepler: error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(code)
+	}
+
 	// parse args and config and exit with error if there is an error
-	cfg, err := parseArgsAndConfig()
+	cfg, loadConfig, err := parseArgsAndConfig()
 	if err != nil {
 		os.Exit(1)
 	}
@@ -41,7 +74,7 @@ func main() {
 	logVersionInfo(logger)
 	printConfigInfo(logger, cfg)
 
-	services, err := createServices(logger, cfg)
+	services, err := createServices(logger, cfg, loadConfig)
 	if err != nil {
 		logger.Error("failed to create services", "error", err)
 		os.Exit(1)
@@ -57,10 +90,15 @@ func main() {
 
 	logger.Info("Starting Kepler")
 
-	if err := service.Run(context.Background(), logger, services); err != nil {
+	if err := service.Run(context.Background(), logger, services, restartPolicies(cfg)...); err != nil {
 		logger.Error("Kepler terminated with an error", "error", err)
 		os.Exit(1)
 	}
+	// log out of the BMCs once the services, which may restore their power
+	// limit on shutdown, are stopped
+	if err := device.CloseRedfishSessions(); err != nil {
+		logger.Warn("Failed to log out of the BMC", "error", err)
+	}
 	logger.Info("Graceful shutdown completed")
 }
 
@@ -77,35 +115,56 @@ func logVersionInfo(logger *slog.Logger) {
 	)
 }
 
-func parseArgsAndConfig() (*config.Config, error) {
+// configLoader loads the configuration file and applies command line flags
+type configLoader func(logger *slog.Logger) (*config.Config, error)
+
+func parseArgsAndConfig() (*config.Config, configLoader, error) {
 	const appName = "kepler"
 	app := kingpin.New(appName, "Power consumption monitoring exporter for Prometheus.")
 
 	configFile := app.Flag("config.file", "Path to YAML configuration file").String()
+	validateConfig := app.Flag("validate-config", "Validate the configuration, print the effective configuration and exit").Bool()
 	updateConfig := config.RegisterFlags(app)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
-	logger := logger.New("info", "text", os.Stdout)
-	cfg := config.DefaultConfig()
-	if *configFile != "" {
-		logger.Info("Loading configuration file", "path", *configFile)
-		loadedCfg, err := config.FromFile(*configFile)
-		if err != nil {
-			logger.Error("Error loading config file", "error", err.Error())
+	// load is called again to reload the configuration at runtime
+	load := func(logger *slog.Logger) (*config.Config, error) {
+		cfg := config.DefaultConfig()
+		if *configFile != "" {
+			logger.Info("Loading configuration file", "path", *configFile)
+			loadedCfg, err := config.FromFile(*configFile)
+			if err != nil {
+				logger.Error("Error loading config file", "error", err.Error())
+				return nil, err
+			}
+			// Replace default config with loaded config
+			cfg = loadedCfg
+			logger.Info("Completed loading of configuration file", "path", *configFile)
+		}
+
+		// Apply command line flags (these override config file settings)
+		if err := updateConfig(cfg); err != nil {
+			logger.Error("Error applying command line flags", "error", err.Error())
 			return nil, err
 		}
-		// Replace default config with loaded config
-		cfg = loadedCfg
-		logger.Info("Completed loading of configuration file", "path", *configFile)
+		return cfg, nil
 	}
 
-	// Apply command line flags (these override config file settings)
-	if err := updateConfig(cfg); err != nil {
-		logger.Error("Error applying command line flags", "error", err.Error())
-		return nil, err
+	// log to stderr when validating so that only the config is printed to stdout
+	logOut := os.Stdout
+	if *validateConfig {
+		logOut = os.Stderr
+	}
+	cfg, err := load(logger.New("info", "text", logOut))
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return cfg, nil
+	if *validateConfig {
+		fmt.Print(cfg)
+		os.Exit(0)
+	}
+	return cfg, load, nil
 }
 
 func printConfigInfo(logger *slog.Logger, cfg *config.Config) {
@@ -121,13 +180,21 @@ Configuration
 `, cfg)
 }
 
-func createServices(logger *slog.Logger, cfg *config.Config) ([]service.Service, error) {
+func createServices(logger *slog.Logger, cfg *config.Config, loadConfig configLoader) ([]service.Service, error) {
 	logger.Debug("Creating all services")
-	cpuPowerMeter, err := createCPUMeter(logger, cfg)
+	cpuPowerMeter, initialized, err := createCPUMeter(logger, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CPU power meter: %w", err)
 	}
 	var services []service.Service
+	if downloader := createModelDownloader(logger, cfg, cpuPowerMeter); downloader != nil {
+		services = append(services, downloader)
+	}
+
+	gpuMeters, err := createGPUMeters(logger, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GPU power meters: %w", err)
+	}
 
 	var podInformer pod.Informer
 	if *cfg.Kube.Enabled {
@@ -138,23 +205,94 @@ func createServices(logger *slog.Logger, cfg *config.Config) ([]service.Service,
 		)
 		services = append(services, podInformer)
 	}
+	cpuSockets := readCPUSockets(logger, cfg)
+	coreTypes := readCPUCoreTypes(logger, cfg)
 	resourceInformer, err := resource.NewInformer(
 		resource.WithLogger(logger),
 		resource.WithProcFSPath(cfg.Host.ProcFS),
+		resource.WithLibvirtPath(cfg.Host.Libvirt),
+		resource.WithCRIEndpoint(cfg.Host.CRI),
+		resource.WithDockerEndpoints(cfg.Host.Docker),
 		resource.WithPodInformer(podInformer),
+		resource.WithMemoryTracking(cfg.Monitor.Attribution != config.AttributionCPUTime),
+		resource.WithLastCPUTracking(len(cpuSockets) > 0 || (len(coreTypes) > 0 && coreTypesWeighted(cfg))),
+		resource.WithCPUCoreTypes(coreTypes),
+		resource.WithExitedProcessTracking(true),
+		resource.WithPodMetadata(podMetadataEnabled(cfg)),
+		resource.WithCgroupCPUAccounting(cgroupRoot(cfg)),
+		resource.WithContainerCPUSets(cpusetRoot(cfg, cpuSockets)),
+		resource.WithProcessTracking(processTrackingEnabled(cfg)),
+		resource.WithProcessEvents(*cfg.Monitor.ProcessEvents),
+		resource.WithEBPFCPUTime(cfg.Monitor.CPUAccounting == config.CPUAccountingEBPF),
+		resource.WithPerfEvents(*cfg.Monitor.PerfEvents),
+		resource.WithIOTracking(*cfg.Monitor.IO.Enabled),
+		resource.WithSystemdUnits(*cfg.Monitor.SystemdUnits),
+		resource.WithGroups(groupSpecs(cfg)...),
+		resource.WithProcessMetadata(*cfg.Monitor.ProcessMetadata),
+		resource.WithAggregates(*cfg.Monitor.Aggregates),
+		resource.WithFilter(createFilter(cfg)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource informer: %w", err)
 	}
 
+	platformZone, err := createPlatformZone(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create platform zone: %w", err)
+	}
+
+	carbonProvider, err := createCarbonProvider(logger, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create carbon intensity provider: %w", err)
+	}
+	if carbonProvider != nil {
+		services = append(services, carbonProvider)
+	}
+
+	tariff, err := createTariff(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create electricity tariff: %w", err)
+	}
+
+	attribution, err := createAttribution(logger, cfg, coreTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create power attribution: %w", err)
+	}
+
+	totals := restoreTotals(logger, cfg)
+
+	// the monitor publishes its events, e.g. a snapshot being ready, on the bus
+	// for the services reacting to them rather than polling
+	events := event.NewBus(event.WithLogger(logger))
+
 	pm := monitor.NewPowerMonitor(
 		cpuPowerMeter,
 		monitor.WithLogger(logger),
 		monitor.WithResourceInformer(resourceInformer),
 		monitor.WithInterval(cfg.Monitor.Interval),
+		monitor.WithSampleInterval(cfg.Monitor.SampleInterval),
 		monitor.WithMaxStaleness(cfg.Monitor.Staleness),
 		monitor.WithMaxTerminated(cfg.Monitor.MaxTerminated),
 		monitor.WithMinTerminatedEnergyThreshold(monitor.Energy(cfg.Monitor.MinTerminatedEnergyThreshold)*monitor.Joule),
+		monitor.WithMaxTerminatedAge(cfg.Monitor.MaxTerminatedAge),
+		monitor.WithGPUMeters(gpuMeters...),
+		monitor.WithIOModels(device.IOModel(cfg.Monitor.IO.Network), device.IOModel(cfg.Monitor.IO.Storage)),
+		monitor.WithPlatformZone(platformZone),
+		monitor.WithAttribution(attribution),
+		monitor.WithIdlePolicy(idlePolicy(cfg)),
+		monitor.WithCPUSockets(cpuSockets),
+		monitor.WithIntervalBackoff(backoffMaxInterval(cfg), monitor.Power(cfg.Monitor.Backoff.IdleThreshold)*monitor.Watt),
+		monitor.WithCarbonProvider(carbonProvider),
+		monitor.WithTariff(tariff),
+		monitor.WithBudgets(createBudgets(cfg)),
+		monitor.WithCalibrations(createCalibrations(cfg)),
+		monitor.WithBudgetNotifiers(createBudgetNotifiers(logger, cfg)...),
+		monitor.WithSystemdUnits(*cfg.Monitor.SystemdUnits),
+		monitor.WithGroups(len(cfg.Monitor.Groups) > 0),
+		monitor.WithAggregates(*cfg.Monitor.Aggregates),
+		monitor.WithOtherWorkloads(*cfg.Monitor.OtherWorkloads),
+		monitor.WithTotals(totals),
+		monitor.WithEventBus(events),
 	)
 
 	apiServer := server.NewAPIServer(
@@ -163,21 +301,38 @@ func createServices(logger *slog.Logger, cfg *config.Config) ([]service.Service,
 		server.WithWebConfig(cfg.Web.Config),
 	)
 
-	services = append(services,
-		resourceInformer,
-		cpuPowerMeter,
-		apiServer,
-		pm,
-	)
+	services = append(services, resourceInformer)
+	if !initialized {
+		services = append(services, cpuPowerMeter)
+	}
+	services = append(services, apiServer, pm)
 
-	// Add Prometheus exporter if enabled
-	if *cfg.Exporter.Prometheus.Enabled {
-		promExporter, err := createPrometheusExporter(logger, cfg, apiServer, pm)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Prometheus exporter: %w", err)
-		}
-		services = append(services, promExporter)
+	// the MCP tools are shared by the services serving them
+	var mcp *server.MCP
+	if *cfg.Rightsizing.Enabled || *cfg.Chargeback.Enabled || *cfg.Federation.Enabled || *cfg.History.Enabled || *cfg.Headroom.Enabled || *cfg.Anomaly.Enabled {
+		mcp = server.NewMCP(apiServer)
+		services = append(services, mcp, event.NewRecorder(events, mcp, event.WithLogger(logger)))
+	}
+
+	// the cap of the node and the power events of its BMC are reported by the
+	// other services, so the devices are controlled first
+	controls, nodeCap, powerEvents, err := createDeviceControls(logger, cfg, apiServer)
+	if err != nil {
+		return nil, err
+	}
+	services = append(services, controls...)
+
+	features, anomalies, historyStore, err := createAPIFeatures(logger, cfg, pm, apiServer, mcp, events, nodeCap)
+	if err != nil {
+		return nil, err
+	}
+	services = append(services, features...)
+
+	exporters, err := createExporters(logger, cfg, pm, apiServer, events, platformZone, anomalies, powerEvents)
+	if err != nil {
+		return nil, err
 	}
+	services = append(services, exporters...)
 
 	// Add pprof if enabled
 	if *cfg.Debug.Pprof.Enabled {
@@ -185,57 +340,699 @@ func createServices(logger *slog.Logger, cfg *config.Config) ([]service.Service,
 		services = append(services, pprof)
 	}
 
-	// Add stdout exporter if enabled
-	if *cfg.Exporter.Stdout.Enabled {
-		stdoutExporter := stdout.NewExporter(pm, stdout.WithLogger(logger))
-		services = append(services, stdoutExporter)
+	// save the counters so that they continue from their values on restart
+	if *cfg.State.Enabled {
+		services = append(services, state.NewSaver(pm, cfg.State.Path,
+			state.WithLogger(logger),
+			state.WithInterval(cfg.State.Interval),
+			state.WithEventBus(events),
+		))
+	}
+
+	// serve the current power as JSON for the top and show commands, and the
+	// energy zones over REST and, if MCP is served, as an MCP tool along with
+	// the status of all services
+	inspectOpts := []inspect.OptionFn{inspect.WithLogger(logger)}
+	if mcp != nil {
+		inspectOpts = append(inspectOpts,
+			inspect.WithTools(mcp),
+			inspect.WithServices(services...),
+			inspect.WithIntervals(statusIntervals(cfg)),
+		)
+	}
+	services = append(services, inspect.NewAPI(pm, apiServer, inspectOpts...))
+
+	// evaluate Prometheus-style queries over the snapshot and, with functions
+	// over time, the history
+	queryOpts := []query.OptionFn{query.WithLogger(logger)}
+	if historyStore != nil {
+		queryOpts = append(queryOpts, query.WithHistory(historyStore))
 	}
+	services = append(services, query.NewAPI(pm, apiServer, queryOpts...))
+
+	// serve the health and readiness of all services for probes
+	services = append(services, server.NewHealth(apiServer, services...))
+
+	// reload the configuration on SIGHUP or a request to /-/reload
+	services = append(services, server.NewControl(apiServer, logger,
+		configReloader(logger, cfg, loadConfig, pm, resourceInformer), *cfg.Web.EnableLifecycle))
 
 	return services, nil
 }
 
-func createPrometheusExporter(logger *slog.Logger, cfg *config.Config, apiServer *server.APIServer, pm *monitor.PowerMonitor) (*prometheus.Exporter, error) {
-	logger.Debug("Creating Prometheus exporter")
+// createCPUMeter returns the power meter of the CPU and whether it is already
+// initialized, so that it isn't initialized again as a service: with the
+// estimator enabled, RAPL is initialized to fall back to the estimator if it
+// is unavailable
+func createCPUMeter(logger *slog.Logger, cfg *config.Config) (device.CPUPowerMeter, bool, error) {
+	if fake := cfg.Dev.FakeCpuMeter; *fake.Enabled {
+		if fake.ReplayFile != "" {
+			meter, err := device.NewReplayCPUMeter(fake.ReplayFile, fake.Zones, device.WithReplayLogger(logger))
+			return meter, false, err
+		}
+		meter, err := device.NewFakeCPUMeter(fake.Zones, device.WithFakeLogger(logger))
+		return meter, false, err
+	}
+
+	if guest := cfg.Guest; *guest.Enabled {
+		meter, err := device.NewGuestPowerMeter(
+			cfg.Host.SysFS,
+			guest.Endpoint,
+			device.WithGuestLogger(logger),
+			device.WithVMID(guest.VMID),
+		)
+		return meter, false, err
+	}
 
-	// Use metrics level from configuration (already parsed)
-	metricsLevel := cfg.Exporter.Prometheus.MetricsLevel
+	if *cfg.Hwmon.Enabled {
+		rails := make([]device.HwmonRail, 0, len(cfg.Hwmon.Rails))
+		for _, rail := range cfg.Hwmon.Rails {
+			rails = append(rails, device.HwmonRail(rail))
+		}
+		return device.NewHwmonPowerMeter(cfg.Host.SysFS, rails, device.WithHwmonLogger(logger)), false, nil
+	}
+
+	if *cfg.Jetson.Enabled {
+		return device.NewJetsonPowerMeter(
+			cfg.Host.SysFS,
+			device.WithJetsonLogger(logger),
+			device.WithTegrastatsPath(cfg.Jetson.TegrastatsPath),
+		), false, nil
+	}
 
-	collectors, err := prometheus.CreateCollectors(
-		pm,
-		prometheus.WithLogger(logger),
-		prometheus.WithProcFSPath(cfg.Host.ProcFS),
-		prometheus.WithNodeName(cfg.Kube.Node),
-		prometheus.WithMetricsLevel(metricsLevel),
+	if *cfg.Powermetrics.Enabled {
+		return device.NewPowermetricsPowerMeter(
+			device.WithPowermetricsLogger(logger),
+			device.WithPowermetricsPath(cfg.Powermetrics.Path),
+		), false, nil
+	}
+
+	if len(cfg.Rapl.Zones) > 0 || len(cfg.Rapl.ExcludeZones) > 0 {
+		logger.Info("rapl zones are filtered", "zones-enabled", cfg.Rapl.Zones, "zones-excluded", cfg.Rapl.ExcludeZones)
+	}
+
+	rapl, err := device.NewCPUPowerMeter(
+		cfg.Host.SysFS,
+		device.WithRaplLogger(logger),
+		device.WithZoneFilter(cfg.Rapl.Zones),
+		device.WithExcludedZones(cfg.Rapl.ExcludeZones),
+		device.WithPerSocketZones(*cfg.Rapl.PerSocket),
 	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Prometheus collectors: %w", err)
+	if !*cfg.Estimator.Enabled {
+		return rapl, false, err
 	}
 
-	debugCollectors := cfg.Exporter.Prometheus.DebugCollectors
+	// fallback to estimating power only if RAPL is unavailable
+	if err == nil {
+		if err = rapl.Init(); err == nil {
+			return rapl, true, nil
+		}
+	}
+	logger.Info("RAPL is unavailable; estimating power using model", "reason", err)
+	estimator, err := createEstimatedCPUMeter(logger, cfg)
+	return estimator, false, err
+}
+
+func createEstimatedCPUMeter(logger *slog.Logger, cfg *config.Config) (device.CPUPowerMeter, error) {
+	// a downloaded model replaces the configured one before power is estimated
+	model := device.LinearModel(cfg.Estimator.Model)
+	if cfg.Estimator.ModelFile != "" && cfg.Estimator.ModelURL == "" {
+		var err error
+		if model, err = device.LoadLinearModel(cfg.Estimator.ModelFile); err != nil {
+			return nil, err
+		}
+	}
 
-	promExporter := prometheus.NewExporter(
-		pm,
-		apiServer,
-		prometheus.WithLogger(logger),
-		prometheus.WithCollectors(collectors),
-		prometheus.WithDebugCollectors(debugCollectors),
+	return device.NewEstimatedCPUMeter(
+		cfg.Host.ProcFS,
+		cfg.Host.SysFS,
+		model,
+		device.WithEstimatorLogger(logger),
 	)
+}
 
-	return promExporter, nil
+// createModelDownloader returns the downloader of the model of the estimator
+// if a model URL is configured and power is estimated; nil otherwise
+func createModelDownloader(logger *slog.Logger, cfg *config.Config, meter device.CPUPowerMeter) *device.ModelDownloader {
+	estimator, ok := meter.(device.ModelUpdater)
+	if !ok || cfg.Estimator.ModelURL == "" {
+		return nil
+	}
+	return device.NewModelDownloader(cfg.Estimator.ModelURL, estimator,
+		device.WithModelDownloaderLogger(logger),
+		device.WithModelRefreshInterval(cfg.Estimator.ModelRefreshInterval),
+	)
 }
 
-func createCPUMeter(logger *slog.Logger, cfg *config.Config) (device.CPUPowerMeter, error) {
-	if fake := cfg.Dev.FakeCpuMeter; *fake.Enabled {
-		return device.NewFakeCPUMeter(fake.Zones, device.WithFakeLogger(logger))
+// createAttribution returns the power attribution strategy selected in the config;
+// coreTypes holds the core type of each CPU of hybrid CPUs
+func createAttribution(logger *slog.Logger, cfg *config.Config, coreTypes map[int]string) (monitor.Attribution, error) {
+	switch cfg.Monitor.Attribution {
+	case config.AttributionCPUMemory:
+		return monitor.NewCPUMemoryAttribution(), nil
+	case config.AttributionModelBased:
+		model := cfg.Monitor.AttributionModel
+		return monitor.NewModelAttribution(model.CPUWeight, model.MemoryWeight, model.InstructionsWeight), nil
+	case config.AttributionExternal:
+		estimator := cfg.Monitor.AttributionEstimator
+		return monitor.NewExternalAttribution(estimator.Endpoint,
+			monitor.WithExternalLogger(logger),
+			monitor.WithExternalTimeout(estimator.Timeout),
+		)
+	default:
+		if len(coreTypes) > 0 && coreTypesWeighted(cfg) {
+			return monitor.NewCoreTypeAttribution(coreTypes, map[string]float64{
+				device.CoreTypePerformance: cfg.Monitor.CoreTypes.PerformanceWeight,
+				device.CoreTypeEfficiency:  cfg.Monitor.CoreTypes.EfficiencyWeight,
+			}), nil
+		}
+		return monitor.NewCPUTimeAttribution(), nil
 	}
+}
+
+// coreTypesWeighted returns true if CPU time on P-cores and E-cores is weighted
+// differently by the cpu-time attribution
+func coreTypesWeighted(cfg *config.Config) bool {
+	return cfg.Monitor.Attribution == config.AttributionCPUTime &&
+		cfg.Monitor.CoreTypes.PerformanceWeight != cfg.Monitor.CoreTypes.EfficiencyWeight
+}
 
-	if len(cfg.Rapl.Zones) > 0 {
-		logger.Info("rapl zones are filtered", "zones-enabled", cfg.Rapl.Zones)
+// idlePolicy returns the idle power attribution policy selected in the config
+func idlePolicy(cfg *config.Config) monitor.IdlePolicy {
+	switch cfg.Monitor.IdlePolicy {
+	case config.IdlePolicyEven:
+		return monitor.IdleEven
+	case config.IdlePolicyRequests:
+		return monitor.IdleByRequests
+	default:
+		return monitor.IdleExcluded
 	}
+}
 
-	return device.NewCPUPowerMeter(
-		cfg.Host.SysFS,
-		device.WithRaplLogger(logger),
-		device.WithZoneFilter(cfg.Rapl.Zones),
+// podMetadataEnabled returns true if pods are decorated with their labels and
+// owner workload, which requires kubernetes monitoring
+func podMetadataEnabled(cfg *config.Config) bool {
+	return *cfg.Kube.Enabled && *cfg.Kube.PodMetadata
+}
+
+// cgroupRoot returns the cgroup v2 mount point from which CPU time is read; empty
+// if CPU time is read from procfs
+func cgroupRoot(cfg *config.Config) string {
+	if cfg.Monitor.CPUAccounting != config.CPUAccountingCgroup {
+		return ""
+	}
+	return filepath.Join(cfg.Host.SysFS, "fs", "cgroup")
+}
+
+// cpusetRoot returns the cgroup v2 mount point the cpusets of containers are read
+// from, so that per-socket zones are attributed to containers pinned to the CPUs
+// of a socket; empty if the socket of CPUs is unknown
+func cpusetRoot(cfg *config.Config, cpuSockets map[int]int) string {
+	if len(cpuSockets) == 0 {
+		return ""
+	}
+	return filepath.Join(cfg.Host.SysFS, "fs", "cgroup")
+}
+
+// createFilter returns the filter of the workloads reported; nil if all
+// workloads are reported. Comm patterns are validated with the config.
+func createFilter(cfg *config.Config) *resource.Filter {
+	f := cfg.Monitor.Filter
+	if f.Include.Comm == "" && f.Exclude.Comm == "" &&
+		len(f.Include.Cgroups) == 0 && len(f.Exclude.Cgroups) == 0 &&
+		len(f.Include.Namespaces) == 0 && len(f.Exclude.Namespaces) == 0 &&
+		f.MinCPUTime == 0 {
+		return nil
+	}
+
+	filter := &resource.Filter{
+		IncludeCgroups:    f.Include.Cgroups,
+		ExcludeCgroups:    f.Exclude.Cgroups,
+		IncludeNamespaces: f.Include.Namespaces,
+		ExcludeNamespaces: f.Exclude.Namespaces,
+		MinCPUTimeDelta:   f.MinCPUTime.Seconds(),
+	}
+	if f.Include.Comm != "" {
+		filter.IncludeComm = regexp.MustCompile(f.Include.Comm)
+	}
+	if f.Exclude.Comm != "" {
+		filter.ExcludeComm = regexp.MustCompile(f.Exclude.Comm)
+	}
+	return filter
+}
+
+// processTrackingEnabled returns true if process level detail is needed, either
+// because processes or VMs are exported or for attribution
+func processTrackingEnabled(cfg *config.Config) bool {
+	level := cfg.Exporter.Prometheus.MetricsLevel
+	exported := *cfg.Exporter.Stdout.Enabled || *cfg.Exporter.VM.Enabled ||
+		((*cfg.Exporter.Prometheus.Enabled || *cfg.Exporter.Push.Enabled || *cfg.Exporter.MQTT.Enabled) && (level.IsProcessEnabled() || level.IsVMEnabled())) ||
+		(*cfg.History.Enabled && (cfg.History.MetricsLevel.IsProcessEnabled() || cfg.History.MetricsLevel.IsVMEnabled()))
+	return exported || cfg.Monitor.Attribution != config.AttributionCPUTime || *cfg.Rapl.PerSocket ||
+		*cfg.Monitor.SystemdUnits || *cfg.Monitor.Aggregates || *cfg.Monitor.IO.Enabled || len(cfg.Monitor.Groups) > 0 ||
+		*cfg.Redfish.Enabled || coreTypesWeighted(cfg)
+}
+
+// groupSpecs returns the groups of processes of cfg
+func groupSpecs(cfg *config.Config) []resource.GroupSpec {
+	specs := make([]resource.GroupSpec, 0, len(cfg.Monitor.Groups))
+	for _, g := range cfg.Monitor.Groups {
+		specs = append(specs, resource.GroupSpec(g))
+	}
+	return specs
+}
+
+// backoffMaxInterval returns the max collection interval; backoff is disabled
+// when it is not greater than the monitor interval
+func backoffMaxInterval(cfg *config.Config) time.Duration {
+	if !*cfg.Monitor.Backoff.Enabled {
+		return 0
+	}
+	return cfg.Monitor.Backoff.MaxInterval
+}
+
+// reloadableSettings returns a copy of cfg with the settings that can be
+// changed at runtime set to those of applied
+func reloadableSettings(cfg, applied *config.Config) *config.Config {
+	copied := *cfg
+	copied.Log.Level = applied.Log.Level
+	copied.Monitor.Interval = applied.Monitor.Interval
+	copied.Monitor.Filter = applied.Monitor.Filter
+	return &copied
+}
+
+// configReloader returns a function that reloads the configuration and applies
+// the log level, monitor interval and workload filter, which can be changed at
+// runtime. Other settings are only applied on restart.
+func configReloader(log *slog.Logger, cfg *config.Config, load configLoader,
+	pm *monitor.PowerMonitor, informer interface{ SetFilter(*resource.Filter) },
+) server.ReloadFn {
+	applied := cfg
+	return func() error {
+		reloaded, err := load(log)
+		if err != nil {
+			return err
+		}
+
+		// validate all settings before applying any, so that a failed reload
+		// leaves the running configuration as is
+		if err := pm.ValidateInterval(reloaded.Monitor.Interval); err != nil {
+			return err
+		}
+		if err := logger.ValidateLogLevel(reloaded.Log.Level); err != nil {
+			return err
+		}
+
+		if err := pm.SetInterval(reloaded.Monitor.Interval); err != nil {
+			return err
+		}
+		if err := logger.SetLogLevel(reloaded.Log.Level); err != nil {
+			return err
+		}
+		informer.SetFilter(createFilter(reloaded))
+
+		if !reflect.DeepEqual(reloadableSettings(reloaded, applied), applied) {
+			log.Warn("Configuration changes other than the log level, monitor interval and filter require a restart")
+		}
+
+		// settings that need a restart are still those of the running config
+		applied = reloadableSettings(applied, reloaded)
+		return nil
+	}
+}
+
+// statusIntervals returns the intervals of the monitor and of the enabled
+// services reading or writing periodically, keyed by configuration setting
+func statusIntervals(cfg *config.Config) map[string]time.Duration {
+	intervals := map[string]time.Duration{
+		inspect.MonitorIntervalKey: cfg.Monitor.Interval,
+		"monitor.staleness":        cfg.Monitor.Staleness,
+		"monitor.sampleInterval":   cfg.Monitor.SampleInterval,
+	}
+	if *cfg.Monitor.Backoff.Enabled {
+		intervals["monitor.backoff.maxInterval"] = cfg.Monitor.Backoff.MaxInterval
+	}
+	if *cfg.Redfish.Enabled {
+		intervals["redfish.minInterval"] = cfg.Redfish.MinInterval
+		if *cfg.Redfish.Events.Enabled {
+			intervals["redfish.events.interval"] = cfg.Redfish.Events.Interval
+		}
+	}
+	if cfg.Carbon.Provider != config.CarbonProviderNone {
+		intervals["carbon.refreshInterval"] = cfg.Carbon.RefreshInterval
+	}
+	if *cfg.Exporter.Stdout.Enabled {
+		intervals["exporter.stdout.interval"] = cfg.Exporter.Stdout.Interval
+	}
+	if *cfg.Exporter.Push.Enabled {
+		intervals["exporter.push.interval"] = cfg.Exporter.Push.Interval
+	}
+	if *cfg.Exporter.MQTT.Enabled {
+		intervals["exporter.mqtt.interval"] = cfg.Exporter.MQTT.Interval
+	}
+	if *cfg.Rightsizing.Enabled {
+		intervals["rightsizing.interval"] = cfg.Rightsizing.Interval
+	}
+	if *cfg.Chargeback.Enabled {
+		intervals["chargeback.interval"] = cfg.Chargeback.Interval
+	}
+	if *cfg.Federation.Enabled {
+		intervals["federation.interval"] = cfg.Federation.Interval
+	}
+	if *cfg.State.Enabled {
+		intervals["state.interval"] = cfg.State.Interval
+	}
+	return intervals
+}
+
+// restartPolicies returns the restart policy of all services and those of
+// services overriding it
+func restartPolicies(cfg *config.Config) []service.RunOptFn {
+	restart := cfg.Restart
+	opts := []service.RunOptFn{service.WithRestartPolicy(service.RestartPolicy{
+		Restart:    restart.Policy == config.RestartPolicyOnFailure,
+		MaxRetries: restart.MaxRetries,
+		Backoff:    restart.Backoff,
+		MaxBackoff: restart.MaxBackoff,
+	})}
+	for name, svc := range restart.Services {
+		opts = append(opts, service.WithServiceRestartPolicy(name, service.RestartPolicy{
+			Restart:    cmp.Or(svc.Policy, restart.Policy) == config.RestartPolicyOnFailure,
+			MaxRetries: ptr.Deref(svc.MaxRetries, restart.MaxRetries),
+			Backoff:    cmp.Or(svc.Backoff, restart.Backoff),
+			MaxBackoff: cmp.Or(svc.MaxBackoff, restart.MaxBackoff),
+		}))
+	}
+	return opts
+}
+
+// createCarbonProvider returns the carbon intensity provider selected in the
+// config or nil if carbon emissions are not computed
+func createCarbonProvider(logger *slog.Logger, cfg *config.Config) (carbon.Provider, error) {
+	opts := []carbon.OptionFn{
+		carbon.WithLogger(logger),
+		carbon.WithRefreshInterval(cfg.Carbon.RefreshInterval),
+	}
+
+	switch cfg.Carbon.Provider {
+	case config.CarbonProviderStatic:
+		return carbon.NewStaticProvider(carbon.Intensity(cfg.Carbon.Static.Intensity)), nil
+
+	case config.CarbonProviderElectricityMaps:
+		token, err := readSecret(cfg.Carbon.ElectricityMaps.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		return carbon.NewElectricityMapsProvider(cfg.Carbon.ElectricityMaps.Zone, token, opts...), nil
+
+	case config.CarbonProviderWattTime:
+		password, err := readSecret(cfg.Carbon.WattTime.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		wt := cfg.Carbon.WattTime
+		return carbon.NewWattTimeProvider(wt.Region, wt.Username, password, opts...), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// createPlatformZone returns the zone of the platform read from its sources,
+// the BMC with Redfish, the ACPI power meter, the batteries and the smart plug
+// of the node, in order of precedence, or nil if none is enabled
+func createPlatformZone(cfg *config.Config) (device.EnergyZone, error) {
+	plug := smartPlugOf(cfg)
+	enabled := map[string]bool{
+		config.PlatformSourceRedfish:   *cfg.Redfish.Enabled,
+		config.PlatformSourceACPI:      *cfg.Platform.ACPI,
+		config.PlatformSourceBattery:   *cfg.Platform.Battery,
+		config.PlatformSourceSmartPlug: plug != nil,
+	}
+	// sources not listed come last
+	order := slices.Clone(cfg.Platform.Precedence)
+	for _, name := range []string{config.PlatformSourceRedfish, config.PlatformSourceACPI, config.PlatformSourceBattery, config.PlatformSourceSmartPlug} {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+
+	var sources []device.PlatformSource
+	for _, name := range order {
+		if !enabled[name] {
+			continue
+		}
+		var zone device.EnergyZone
+		var err error
+		switch name {
+		case config.PlatformSourceRedfish:
+			zone, err = createRedfishZone(cfg)
+		case config.PlatformSourceACPI:
+			zone, err = device.NewACPIPowerZone(monitor.PlatformZone, cfg.Host.SysFS, nil)
+		case config.PlatformSourceBattery:
+			zone, err = device.NewBatteryPowerZone(monitor.PlatformZone, cfg.Host.SysFS, nil)
+		case config.PlatformSourceSmartPlug:
+			zone, err = createSmartPlugZone(plug)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s platform source: %w", name, err)
+		}
+		sources = append(sources, device.PlatformSource{Name: name, Zone: zone})
+	}
+	if len(sources) == 0 {
+		return nil, nil
+	}
+	return device.NewPlatformZone(monitor.PlatformZone, sources,
+		device.WithPlatformMaxLatency(cfg.Platform.MaxLatency),
+	), nil
+}
+
+// smartPlugOf returns the smart plug the node is powered through, looked up by
+// kube.nodeName or the hostname, or nil if it has none
+func smartPlugOf(cfg *config.Config) *config.SmartPlug {
+	if len(cfg.Platform.SmartPlugs) == 0 {
+		return nil
+	}
+	node := cfg.Kube.Node
+	if node == "" {
+		node, _ = os.Hostname()
+	}
+	for i, plug := range cfg.Platform.SmartPlugs {
+		if plug.Node == node {
+			return &cfg.Platform.SmartPlugs[i]
+		}
+	}
+	return nil
+}
+
+// createSmartPlugZone returns the zone of the platform read from plug
+func createSmartPlugZone(plug *config.SmartPlug) (device.EnergyZone, error) {
+	var password string
+	if plug.PasswordFile != "" {
+		var err error
+		if password, err = readSecret(plug.PasswordFile); err != nil {
+			return nil, err
+		}
+	}
+	return device.NewSmartPlugZone(monitor.PlatformZone, plug.Type, plug.Address, nil,
+		device.WithSmartPlugCredentials(plug.Username, password))
+}
+
+// createRedfishZone returns the zone of the platform of the BMC configured
+func createRedfishZone(cfg *config.Config) (*device.RedfishZone, error) {
+	redfish := cfg.Redfish
+	var password string
+	if redfish.PasswordFile != "" {
+		var err error
+		if password, err = readSecret(redfish.PasswordFile); err != nil {
+			return nil, err
+		}
+	}
+	return device.NewRedfishZone(monitor.PlatformZone, redfish.Endpoint,
+		device.WithRedfishChassis(redfish.Chassis),
+		device.WithRedfishCredentials(redfish.Username, password),
+		device.WithRedfishInsecureSkipVerify(*redfish.InsecureSkipVerify),
+		device.WithRedfishMinInterval(redfish.MinInterval),
+		device.WithRedfishJitter(redfish.Jitter),
+		device.WithRedfishPowerField(redfish.PowerField),
+		device.WithRedfishPowerScale(redfish.PowerScale),
+		device.WithRedfishAveragingInterval(redfish.AveragingInterval),
+		device.WithRedfishLogServices(redfish.Events.LogServices...),
 	)
 }
+
+// pricingEnabled returns true if a price or a schedule is set, i.e. the cost of
+// energy is computed
+func pricingEnabled(cfg *config.Config) bool {
+	return cfg.Pricing.Price > 0 || len(cfg.Pricing.Schedule) > 0
+}
+
+// restoreTotals returns the counters saved in the state file, or nil if the
+// state is not saved or can't be restored, in which case the counters start
+// from zero
+func restoreTotals(logger *slog.Logger, cfg *config.Config) *monitor.Totals {
+	if !*cfg.State.Enabled {
+		return nil
+	}
+	totals, savedAt, err := state.Load(cfg.State.Path)
+	switch {
+	case err != nil:
+		logger.Warn("Failed to restore the counters; they start from zero", "error", err)
+	case totals != nil:
+		logger.Info("Restoring the counters", "path", cfg.State.Path, "saved", savedAt)
+	}
+	return totals
+}
+
+// createTariff returns the electricity tariff of the config or nil if the cost
+// of energy is not computed
+func createTariff(cfg *config.Config) (*pricing.Tariff, error) {
+	if !pricingEnabled(cfg) {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(cfg.Pricing.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	tariff := &pricing.Tariff{
+		Price:    pricing.Price(cfg.Pricing.Price),
+		Location: loc,
+	}
+	for _, p := range cfg.Pricing.Schedule {
+		start, err := pricing.ParseTimeOfDay(p.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := pricing.ParseTimeOfDay(p.End)
+		if err != nil {
+			return nil, err
+		}
+		period := pricing.Period{Start: start, End: end, Price: pricing.Price(p.Price)}
+		for _, d := range p.Days {
+			day, err := pricing.ParseWeekday(d)
+			if err != nil {
+				return nil, err
+			}
+			period.Days = append(period.Days, day)
+		}
+		tariff.Periods = append(tariff.Periods, period)
+	}
+	return tariff, nil
+}
+
+// readSecret reads a secret such as an API token from a file
+func readSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// createBudgets returns the daily energy budgets set in the config
+func createBudgets(cfg *config.Config) monitor.Budgets {
+	budgets := monitor.Budgets{
+		Node:       monitor.Energy(cfg.Budget.Node) * monitor.Joule,
+		Namespaces: make(map[string]monitor.Energy, len(cfg.Budget.Namespaces)),
+	}
+	for ns, joules := range cfg.Budget.Namespaces {
+		budgets.Namespaces[ns] = monitor.Energy(joules) * monitor.Joule
+	}
+	return budgets
+}
+
+// createCalibrations returns the calibration of the power of zones keyed by
+// the name of the zones
+func createCalibrations(cfg *config.Config) monitor.Calibrations {
+	if len(cfg.Monitor.Calibration) == 0 {
+		return nil
+	}
+	calibrations := make(monitor.Calibrations, len(cfg.Monitor.Calibration))
+	for _, c := range cfg.Monitor.Calibration {
+		calibrations[c.Zone] = monitor.Calibration{Scale: c.Scale, Offset: monitor.Power(c.Offset * float64(monitor.Watt))}
+	}
+	return calibrations
+}
+
+// createBudgetNotifiers returns the notifiers called when a budget is exceeded
+func createBudgetNotifiers(logger *slog.Logger, cfg *config.Config) []monitor.BudgetNotifier {
+	if cfg.Budget.WebhookURL == "" {
+		return nil
+	}
+	return []monitor.BudgetNotifier{
+		budget.NewWebhookNotifier(cfg.Budget.WebhookURL,
+			budget.WithLogger(logger),
+			budget.WithNodeName(cfg.Kube.Node),
+		),
+	}
+}
+
+// readCPUSockets returns the socket of each CPU when per-socket zones are enabled.
+// Without the CPU topology, per-socket zones are attributed by node-wide CPU time.
+func readCPUSockets(logger *slog.Logger, cfg *config.Config) map[int]int {
+	if !*cfg.Rapl.PerSocket || *cfg.Dev.FakeCpuMeter.Enabled {
+		return nil
+	}
+
+	sockets, err := device.CPUSockets(cfg.Host.SysFS)
+	if err != nil {
+		logger.Warn("Failed to read CPU topology; per-socket zones will be attributed by node CPU time", "error", err)
+		return nil
+	}
+	return sockets
+}
+
+// readCPUCoreTypes returns the core type of each CPU of hybrid CPUs, which have
+// performance and efficiency cores; nil for other CPUs
+func readCPUCoreTypes(logger *slog.Logger, cfg *config.Config) map[int]string {
+	if *cfg.Dev.FakeCpuMeter.Enabled {
+		return nil
+	}
+
+	coreTypes, err := device.CPUCoreTypes(cfg.Host.SysFS)
+	if err != nil {
+		logger.Debug("No CPU core types; CPU time is not weighted by core type", "error", err)
+		return nil
+	}
+	return coreTypes
+}
+
+// createGPUMeters probes GPUs of all supported vendors and returns meters of
+// those that are present, so that nodes with GPUs of mixed vendors are supported
+func createGPUMeters(logger *slog.Logger, cfg *config.Config) ([]gpu.PowerMeter, error) {
+	if !*cfg.GPU.Enabled {
+		return nil, nil
+	}
+
+	opts := []gpu.OptionFn{
+		gpu.WithLogger(logger),
+		gpu.WithSysFSPath(cfg.Host.SysFS),
+		gpu.WithProcFSPath(cfg.Host.ProcFS),
+		gpu.WithNVIDIASMIPath(cfg.GPU.NVIDIA.SMIPath),
+		gpu.WithDevicePluginCheckpoint(cfg.GPU.NVIDIA.DevicePluginCheckpoint),
+		gpu.WithHabanaSMIPath(cfg.GPU.Habana.SMIPath),
+		// a hung driver mustn't stall collections
+		gpu.WithCommandTimeout(cfg.Monitor.Interval / 2),
+	}
+	candidates := []gpu.PowerMeter{
+		gpu.NewNVIDIAMeter(opts...),
+		gpu.NewAMDMeter(opts...),
+		gpu.NewIntelMeter(opts...),
+		gpu.NewGaudiMeter(opts...),
+	}
+
+	var meters []gpu.PowerMeter
+	for _, m := range candidates {
+		if err := m.Init(); err != nil {
+			logger.Info("GPU power meter unavailable", "meter", m.Name(), "reason", err)
+			continue
+		}
+		meters = append(meters, m)
+	}
+
+	if len(meters) == 0 {
+		return nil, fmt.Errorf("gpu monitoring is enabled but no supported GPUs were found")
+	}
+	return meters, nil
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/logger"
+	"github.com/sustainable-computing-io/kepler/internal/measure"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+// measureCommand runs a command and measures its energy
+const measureCommand = "measure"
+
+// runMeasure runs the command of args, measuring the energy consumed by it and
+// its descendants, writes a summary to out once it exits and returns its exit
+// code
+func runMeasure(args []string, out io.Writer) (int, error) {
+	app := kingpin.New("kepler", "Measure the energy consumed by a command.")
+	cmd := app.Command(measureCommand, "Run a command and print the energy consumed by it and its descendants")
+	configFile := cmd.Flag("config.file", "Path to YAML configuration file of the power meter and host").String()
+	interval := cmd.Flag("interval", "Interval between measurements").Default("1s").Duration()
+	logLevel := cmd.Flag("log.level", "Logging level: debug, info, warn, error").Default("warn").Enum("debug", "info", "warn", "error")
+	command := cmd.Arg("command", "Command to measure, after --").Required().Strings()
+	if _, err := app.Parse(args); err != nil {
+		return 0, err
+	}
+
+	cfg := config.DefaultConfig()
+	if *configFile != "" {
+		var err error
+		if cfg, err = config.FromFile(*configFile); err != nil {
+			return 0, err
+		}
+	}
+	if *interval <= 0 {
+		return 0, fmt.Errorf("invalid interval: %s; must be positive", *interval)
+	}
+	cfg.Monitor.Interval = *interval
+	logger := logger.New(*logLevel, cfg.Log.Format, os.Stderr)
+
+	c := exec.Command((*command)[0], (*command)[1:]...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	services, measurer, err := createMeasureServices(logger, cfg, c)
+	if err != nil {
+		return 0, err
+	}
+	if err := service.Init(logger, services); err != nil {
+		return 0, err
+	}
+
+	// interrupts reach the command, as they do with time(1); Kepler keeps
+	// measuring until the command exits. They are caught rather than ignored,
+	// as ignored signals would be ignored by the command too.
+	signal.Notify(make(chan os.Signal, 1), syscall.SIGINT)
+
+	if err := service.Run(context.Background(), logger, services); err != nil {
+		return 0, err
+	}
+	if c.ProcessState == nil {
+		return 0, fmt.Errorf("%s did not run", c.Path)
+	}
+
+	_, _ = fmt.Fprintln(out)
+	measure.WriteSummary(out, measurer.Summary())
+	return c.ProcessState.ExitCode(), nil
+}
+
+// createMeasureServices returns the services that measure the processes of cmd
+// with the power meters of cfg
+func createMeasureServices(logger *slog.Logger, cfg *config.Config, cmd *exec.Cmd) ([]service.Service, *measure.Measurer, error) {
+	cpuPowerMeter, initialized, err := createCPUMeter(logger, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CPU power meter: %w", err)
+	}
+	gpuMeters, err := createGPUMeters(logger, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create GPU power meters: %w", err)
+	}
+
+	coreTypes := readCPUCoreTypes(logger, cfg)
+	attribution, err := createAttribution(logger, cfg, coreTypes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create power attribution: %w", err)
+	}
+
+	// the processes of the command are tracked as a group, added once it starts
+	resourceInformer, err := resource.NewInformer(
+		resource.WithLogger(logger),
+		resource.WithProcFSPath(cfg.Host.ProcFS),
+		resource.WithMemoryTracking(cfg.Monitor.Attribution != config.AttributionCPUTime),
+		resource.WithLastCPUTracking(len(coreTypes) > 0 && coreTypesWeighted(cfg)),
+		resource.WithCPUCoreTypes(coreTypes),
+		resource.WithExitedProcessTracking(true),
+		resource.WithCgroupCPUAccounting(cgroupRoot(cfg)),
+		resource.WithProcessTracking(true),
+		resource.WithEBPFCPUTime(cfg.Monitor.CPUAccounting == config.CPUAccountingEBPF),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create resource informer: %w", err)
+	}
+
+	// every process that terminates is kept until it is measured
+	pm := monitor.NewPowerMonitor(
+		cpuPowerMeter,
+		monitor.WithLogger(logger),
+		monitor.WithResourceInformer(resourceInformer),
+		monitor.WithInterval(cfg.Monitor.Interval),
+		monitor.WithMaxStaleness(min(cfg.Monitor.Staleness, cfg.Monitor.Interval)),
+		monitor.WithMaxTerminated(-1),
+		monitor.WithMinTerminatedEnergyThreshold(0),
+		monitor.WithGPUMeters(gpuMeters...),
+		monitor.WithIOModels(device.IOModel(cfg.Monitor.IO.Network), device.IOModel(cfg.Monitor.IO.Storage)),
+		monitor.WithAttribution(attribution),
+		monitor.WithGroups(true),
+	)
+
+	measurer := measure.NewMeasurer(pm, resourceInformer, cmd,
+		measure.WithLogger(logger),
+		measure.WithSampleInterval(cfg.Monitor.Interval),
+	)
+	services := []service.Service{resourceInformer}
+	if !initialized {
+		services = append(services, cpuPowerMeter)
+	}
+	services = append(services, pm, measurer)
+	if downloader := createModelDownloader(logger, cfg, cpuPowerMeter); downloader != nil {
+		services = append([]service.Service{downloader}, services...)
+	}
+	return services, measurer, nil
+}
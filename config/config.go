@@ -4,15 +4,25 @@
 package config
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/sustainable-computing-io/kepler/internal/pricing"
 	"gopkg.in/yaml.v3"
 	"k8s.io/utils/ptr"
 )
@@ -26,29 +36,395 @@ type (
 	Host struct {
 		SysFS  string `yaml:"sysfs"`
 		ProcFS string `yaml:"procfs"`
+
+		// Libvirt is the directory where libvirt keeps the status of running QEMU
+		// domains, used to look up the UUID and name of VMs; empty disables lookups
+		Libvirt string `yaml:"libvirt"`
+
+		// CRI is the CRI socket of the container runtime (containerd or CRI-O),
+		// used to look up the name, image, labels and pod of containers; empty
+		// disables lookups
+		CRI string `yaml:"cri"`
+
+		// Docker are the Docker Engine API sockets of Docker and Podman, used
+		// to look up the name, image and compose project of containers; glob
+		// patterns match the sockets of rootless Podman
+		Docker []string `yaml:"docker"`
 	}
 
 	// Rapl configuration
 	Rapl struct {
 		Zones []string `yaml:"zones"`
+
+		// ExcludeZones are not monitored even if listed in Zones, e.g. core
+		// and uncore zones that are part of the package zone
+		ExcludeZones []string `yaml:"excludeZones"`
+
+		// PerSocket reports zones of each socket separately (e.g. package-0,
+		// package-1) instead of aggregating them across sockets
+		PerSocket *bool `yaml:"perSocket"`
+	}
+
+	// GPU configuration; disabled by default
+	GPU struct {
+		Enabled *bool  `yaml:"enabled"`
+		NVIDIA  NVIDIA `yaml:"nvidia"`
+		Habana  Habana `yaml:"habana"`
+	}
+
+	NVIDIA struct {
+		SMIPath string `yaml:"smiPath"` // path to nvidia-smi used to query NVML
+
+		// DevicePluginCheckpoint is the checkpoint of the device manager of the
+		// kubelet, used to find the pods MIG instances are allocated to; empty
+		// disables it
+		DevicePluginCheckpoint string `yaml:"devicePluginCheckpoint"`
+	}
+
+	// Habana configuration of Intel Gaudi accelerators
+	Habana struct {
+		SMIPath string `yaml:"smiPath"` // path to hl-smi
+	}
+
+	// Hwmon configuration; when enabled, node power is read from power rails
+	// measured by hwmon sensors (e.g. INA226, INA3221) instead of RAPL, as on
+	// ARM SBCs and edge devices
+	Hwmon struct {
+		Enabled *bool       `yaml:"enabled"`
+		Rails   []HwmonRail `yaml:"rails"`
+	}
+
+	HwmonRail struct {
+		Zone    string `yaml:"zone"`    // zone the rail is reported as; rails of the same zone are summed
+		Sensor  string `yaml:"sensor"`  // name of the hwmon sensor, e.g. ina3221
+		Label   string `yaml:"label"`   // label of the rail, e.g. VDD_CPU_GPU_CV
+		Channel int    `yaml:"channel"` // channel of the rail if label is empty; defaults to 1
+	}
+
+	// Jetson configuration; when enabled, node power is read from the power
+	// rails of NVIDIA Jetson modules instead of RAPL
+	Jetson struct {
+		Enabled *bool `yaml:"enabled"`
+
+		// TegrastatsPath is run to read the rails if they are not found in
+		// sysfs; empty disables it
+		TegrastatsPath string `yaml:"tegrastatsPath"`
+	}
+
+	// Powermetrics configuration; when enabled, node power is sampled with
+	// powermetrics on macOS instead of RAPL
+	Powermetrics struct {
+		Enabled *bool  `yaml:"enabled"`
+		Path    string `yaml:"path"` // path of powermetrics
+	}
+
+	// Guest configuration; when enabled, Kepler runs in a VM and node power
+	// is read from the power of the VM exported by Kepler on the host
+	Guest struct {
+		Enabled *bool `yaml:"enabled"`
+
+		// Endpoint is the metrics (/metrics) or VM exporter (/vms) endpoint
+		// of Kepler on the host; an http(s) URL or vsock://<cid>:<port>/<path>
+		Endpoint string `yaml:"endpoint"`
+
+		// VMID is the ID of the VM on the host; defaults to the system UUID
+		// of the VM
+		VMID string `yaml:"vmID"`
+	}
+
+	// Redfish configuration; when enabled, the power of the whole platform of
+	// a hypervisor is read from its BMC with Redfish, reported as the platform
+	// zone and attributed to VMs in proportion to their CPU time
+	Redfish struct {
+		Enabled  *bool  `yaml:"enabled"`
+		Endpoint string `yaml:"endpoint"` // URL of the BMC, e.g. https://10.0.0.2
+
+		// Chassis is the ID of the chassis whose power is read, e.g.
+		// System.Embedded.1; the first chassis of the BMC if empty
+		Chassis string `yaml:"chassis"`
+
+		Username     string `yaml:"username"`
+		PasswordFile string `yaml:"passwordFile"` // file containing the password
+
+		// InsecureSkipVerify skips the verification of the certificate of the
+		// BMC, which is often self-signed
+		InsecureSkipVerify *bool `yaml:"insecureSkipVerify"`
+
+		// MinInterval is the min interval between the requests to the BMC; the
+		// power read last is reported until it elapses
+		MinInterval time.Duration `yaml:"minInterval"`
+
+		// Jitter is the max random delay of the first request to the BMC and
+		// added to MinInterval, so that instances started together don't poll
+		// their BMCs at the same time
+		Jitter time.Duration `yaml:"jitter"`
+
+		// PowerField is the dot-separated path of the power in the Power
+		// resource of the chassis, for BMCs reporting it in an OEM field, e.g.
+		// PowerControl.0.Oem.Vendor.PowerMilliwatts; PowerConsumedWatts if empty
+		PowerField string `yaml:"powerField"`
+
+		// PowerScale converts the reported power to watts, e.g. 0.001 if the
+		// BMC reports milliwatts
+		PowerScale float64 `yaml:"powerScale"`
+
+		// AveragingInterval is the interval the BMC averages the reported power
+		// over; 0 if it reports the instantaneous power
+		AveragingInterval time.Duration `yaml:"averagingInterval"`
+
+		Events RedfishEvents `yaml:"events"`
+	}
+
+	// RedfishEvents configures the power events read from the logs of the BMC,
+	// e.g. failed power supplies, an engaged power cap or a brownout
+	RedfishEvents struct {
+		Enabled  *bool         `yaml:"enabled"`
+		Interval time.Duration `yaml:"interval"` // interval between reads of the logs
+
+		// LogServices are the paths of the log services read, e.g.
+		// /redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel; those of the
+		// first manager and system of the BMC if empty
+		LogServices []string `yaml:"logServices"`
+	}
+
+	// Platform configuration; the sources the power of the whole platform is
+	// read from when several are enabled, e.g. the BMC and the ACPI power meter
+	Platform struct {
+		ACPI *bool `yaml:"acpi"` // read the power of the platform from the ACPI power meter
+
+		// Battery reads the power of the platform from the rate its batteries
+		// discharge at, e.g. on laptops; it can't be read on AC power
+		Battery *bool `yaml:"battery"`
+
+		// Precedence is the order the sources are used in, e.g. [redfish, acpi];
+		// the first one that could be read is the primary and the power of the
+		// others is compared to it. Sources not listed come last.
+		Precedence []string `yaml:"precedence"`
+
+		// MaxLatency is the latency of reads over which a source is only the
+		// primary if no faster one could be read; 0 doesn't limit it
+		MaxLatency time.Duration `yaml:"maxLatency"`
+
+		// SmartPlugs map nodes to the smart plugs they are powered through;
+		// the wall power of the node is read from the plug of its name
+		SmartPlugs []SmartPlug `yaml:"smartPlugs"`
+	}
+
+	// SmartPlug is a smart plug with a power meter a node is powered through
+	SmartPlug struct {
+		Node string `yaml:"node"` // kube.nodeName of the node, or its hostname
+		Type string `yaml:"type"` // tasmota, shelly, shelly-gen2 or kasa
+
+		// Address is the base URL of the HTTP API of the plug, e.g.
+		// http://192.168.1.20, or the host of kasa plugs
+		Address string `yaml:"address"`
+
+		// Username and the password in PasswordFile authenticate to tasmota
+		// and shelly plugs; empty if authentication is disabled
+		Username     string `yaml:"username"`
+		PasswordFile string `yaml:"passwordFile"`
+	}
+
+	// Estimator configuration; when enabled, node power is estimated using a
+	// model if RAPL is unavailable (e.g. cloud VMs)
+	Estimator struct {
+		Enabled   *bool  `yaml:"enabled"`
+		ModelFile string `yaml:"modelFile"` // overrides model when set
+
+		// ModelURL is an http(s) URL the model is downloaded from at startup, in
+		// the format of the model file; overrides modelFile and model when set.
+		// The SHA-256 checksum of the model must be published at ModelURL.sha256.
+		ModelURL string `yaml:"modelURL"`
+		// ModelRefreshInterval is how often the model is downloaded again and
+		// replaced if it changed; 0 downloads it only at startup
+		ModelRefreshInterval time.Duration `yaml:"modelRefreshInterval"`
+
+		// Model estimates power (in watts) as:
+		// intercept + utilization × CPU utilization (0-1) + frequency × CPU frequency (GHz)
+		//   + deepIdle × ratio of time CPUs spent in deep idle states (0-1)
+		// with a relative uncertainty, e.g. 0.2 for ±20%; 0 if unknown
+		Model LinearModel `yaml:"model"`
+	}
+
+	LinearModel struct {
+		Intercept   float64 `yaml:"intercept"`
+		Utilization float64 `yaml:"utilization"`
+		Frequency   float64 `yaml:"frequency"`
+		DeepIdle    float64 `yaml:"deepIdle"`
+		Uncertainty float64 `yaml:"uncertainty"`
+	}
+
+	// Carbon configuration; when a provider is set, carbon emissions of the
+	// node and workloads are computed from the carbon intensity of the grid
+	Carbon struct {
+		// Provider of the grid carbon intensity:
+		// none: carbon emissions are not computed (default)
+		// static: a fixed carbon intensity
+		// electricitymaps: the Electricity Maps API
+		// watttime: the WattTime API
+		Provider        string        `yaml:"provider"`
+		RefreshInterval time.Duration `yaml:"refreshInterval"` // interval between API requests
+
+		Static          StaticCarbon          `yaml:"static"`
+		ElectricityMaps ElectricityMapsCarbon `yaml:"electricityMaps"`
+		WattTime        WattTimeCarbon        `yaml:"wattTime"`
+	}
+
+	StaticCarbon struct {
+		Intensity float64 `yaml:"intensity"` // in gCO2e/kWh
+	}
+
+	ElectricityMapsCarbon struct {
+		Zone      string `yaml:"zone"`      // e.g. DE, US-CAL-CISO
+		TokenFile string `yaml:"tokenFile"` // file containing the API token
+	}
+
+	WattTimeCarbon struct {
+		Region       string `yaml:"region"` // e.g. CAISO_NORTH
+		Username     string `yaml:"username"`
+		PasswordFile string `yaml:"passwordFile"` // file containing the password
+	}
+
+	// Pricing configuration; when a price or a schedule is set, the cost of
+	// the energy consumed by the node and workloads is computed
+	Pricing struct {
+		Price    float64       `yaml:"price"`    // per kWh outside of the schedule; 0 disables
+		Timezone string        `yaml:"timezone"` // IANA time zone of the schedule, e.g. Europe/Berlin
+		Schedule []PricePeriod `yaml:"schedule"` // time of day prices; the first matching period applies
+	}
+
+	PricePeriod struct {
+		Start string   `yaml:"start"` // HH:MM
+		End   string   `yaml:"end"`   // HH:MM; periods ending before they start end the next day
+		Days  []string `yaml:"days"`  // e.g. mon, tue; all days if empty
+		Price float64  `yaml:"price"` // per kWh
+	}
+
+	// Budget configuration; daily (UTC) energy budgets of the node and of the
+	// pods of namespaces. A log event is emitted and the webhook, if set, is
+	// called when a budget is exceeded
+	Budget struct {
+		Node       int64            `yaml:"node"`       // in joules per day; 0 disables
+		Namespaces map[string]int64 `yaml:"namespaces"` // namespace to joules per day
+		WebhookURL string           `yaml:"webhookURL"` // URL to POST budget exceeded events to
+	}
+
+	// PowerCap configuration; caps the power of the node by setting the power
+	// limit of RAPL or of the BMC when Kepler starts and, if allowed, on PUT
+	// requests to /-/powercap. The limit read when Kepler starts is restored
+	// on shutdown
+	PowerCap struct {
+		Enabled  *bool   `yaml:"enabled"`
+		Limiter  string  `yaml:"limiter"`  // rapl or redfish
+		Watts    float64 `yaml:"watts"`    // cap set when Kepler starts; 0 leaves the limit as is
+		DryRun   *bool   `yaml:"dryRun"`   // log the limits instead of setting them
+		AllowAPI *bool   `yaml:"allowAPI"` // allow the cap to be set at /-/powercap
+	}
+
+	// Headroom configuration; serves the power of the node, its cap and its
+	// trend at /headroom, to be polled by energy-aware schedulers, and as an
+	// MCP tool
+	Headroom struct {
+		Enabled *bool         `yaml:"enabled"`
+		Window  time.Duration `yaml:"window"` // the average, max and trend of the power are computed over
+
+		// CapWatts is the power the node should stay below, e.g. the share of
+		// its rack budget; the power cap is used instead if it is enabled
+		CapWatts float64 `yaml:"capWatts"`
+	}
+
+	// Anomaly configuration; flags abnormal jumps of the power of the node and
+	// its top workloads, served at /anomalies, as an MCP tool and as metrics
+	Anomaly struct {
+		Enabled   *bool   `yaml:"enabled"`
+		Threshold float64 `yaml:"threshold"` // z-score above which the power is abnormal
+		Alpha     float64 `yaml:"alpha"`     // smoothing factor of the moving average
+		Workloads int     `yaml:"workloads"` // containers, VMs and pods drawing the most power watched
+	}
+
+	// Rightsizing configuration; reports pods whose CPU requests are much
+	// higher than their CPU usage, and so are attributed idle power they don't
+	// need with the requests idle policy
+	Rightsizing struct {
+		Enabled  *bool         `yaml:"enabled"`
+		Interval time.Duration `yaml:"interval"` // interval between reports in the logs
+	}
+
+	// Chargeback configuration; reports the energy consumed by the pods of
+	// each namespace in periods of fixed length
+	Chargeback struct {
+		Enabled  *bool         `yaml:"enabled"`
+		Interval time.Duration `yaml:"interval"` // length of the period of a report
+		TopPods  int           `yaml:"topPods"`  // pods consuming the most energy reported for each namespace
+	}
+
+	// Federation configuration; scrapes the snapshots of other Kepler agents
+	// and serves the power of the cluster at /cluster and as MCP tools
+	Federation struct {
+		Enabled  *bool             `yaml:"enabled"`
+		Interval time.Duration     `yaml:"interval"` // interval between scrapes of the agents
+		Timeout  time.Duration     `yaml:"timeout"`  // timeout of a scrape of an agent
+		Agents   []FederationAgent `yaml:"agents"`
+	}
+
+	FederationAgent struct {
+		Name string `yaml:"name"` // node of the agent; the host of the URL if empty
+		URL  string `yaml:"url"`  // base URL of the web server of the agent, e.g. http://worker-1:28282
+	}
+
+	// History configuration; records the power of the node and workloads in
+	// every interval, served at /history and as an MCP tool
+	History struct {
+		Enabled      *bool         `yaml:"enabled"`
+		Path         string        `yaml:"path"`         // SQLite database file; rows are kept in memory if empty
+		Retention    time.Duration `yaml:"retention"`    // rows older than the retention are deleted
+		MetricsLevel Level         `yaml:"metricsLevel"` // levels recorded
+
+		Rollups HistoryRollups `yaml:"rollups"`
+	}
+
+	// HistoryRollups sum the energy of the node, namespaces and containers by
+	// hour and day; they are kept for longer than rows
+	HistoryRollups struct {
+		HourlyRetention time.Duration `yaml:"hourlyRetention"`
+		DailyRetention  time.Duration `yaml:"dailyRetention"`
+	}
+
+	// State configuration; saves the cumulative counters of the node and of
+	// containers, VMs and pods to a file, and restores them when Kepler
+	// restarts so that they don't start again from zero
+	State struct {
+		Enabled  *bool         `yaml:"enabled"`
+		Path     string        `yaml:"path"`     // file the counters are saved to
+		Interval time.Duration `yaml:"interval"` // interval between saves
 	}
 
 	// Development mode settings; disabled by default
 	Dev struct {
 		FakeCpuMeter struct {
-			Enabled *bool    `yaml:"enabled"`
-			Zones   []string `yaml:"zones"`
+			Enabled    *bool    `yaml:"enabled"`
+			Zones      []string `yaml:"zones"`
+			ReplayFile string   `yaml:"replayFile"` // RAPL readings to replay instead of synthesizing them
 		} `yaml:"fake-cpu-meter"`
 	}
 	Web struct {
 		Config          string   `yaml:"configFile"`
 		ListenAddresses []string `yaml:"listenAddresses"`
+
+		// EnableLifecycle serves the endpoints changing Kepler at runtime,
+		// /-/reload and PUT requests to /-/loglevel, which are not authenticated
+		EnableLifecycle *bool `yaml:"enableLifecycle"`
 	}
 
 	Monitor struct {
 		Interval  time.Duration `yaml:"interval"`  // Interval for monitoring resources
 		Staleness time.Duration `yaml:"staleness"` // Time after which calculated values are considered stale
 
+		// SampleInterval is the interval at which the power of zones is sampled
+		// to report their min and max power within each interval; 0 disables
+		// sampling. It must be less than the interval.
+		SampleInterval time.Duration `yaml:"sampleInterval"`
+
 		// MaxTerminated controls terminated workload tracking behavior:
 		// <0: Any negative value indicates to track unlimited terminated workloads (no capacity limit)
 		// =0: Disable terminated workload tracking completely
@@ -60,22 +436,265 @@ type (
 		// Value is in joules (e.g., 10 = 10 joules)
 		// TODO: Add support for parsing energy units like "10J", "500mJ", "2kJ"
 		MinTerminatedEnergyThreshold int64 `yaml:"minTerminatedEnergyThreshold"`
+
+		// MaxTerminatedAge controls how long terminated workloads are retained:
+		// =0: terminated workloads are retained only until they are exported (e.g. scraped)
+		// >0: terminated workloads are retained for the duration regardless of exports,
+		//     so that consumers with slow scrape intervals do not miss them
+		MaxTerminatedAge time.Duration `yaml:"maxTerminatedAge"`
+
+		// Attribution controls how the power of zones is attributed to workloads:
+		// cpu-time: all zones by the CPU time of workloads (default)
+		// cpu-memory: memory zones (dram, uncore) by the resident memory of
+		//             workloads and all other zones by CPU time
+		// model: all zones by a linear model over CPU time and resident memory
+		// external: all zones by the power estimated by an external estimator
+		Attribution string `yaml:"attribution"`
+
+		// AttributionModel holds the weights of the model used by model attribution
+		AttributionModel AttributionModel `yaml:"attributionModel"`
+
+		// AttributionEstimator is the external estimator used by external attribution
+		AttributionEstimator AttributionEstimator `yaml:"attributionEstimator"`
+
+		// CoreTypes weights the CPU time of workloads by the type of the cores it
+		// was used on by cpu-time attribution on hybrid CPUs
+		CoreTypes CoreTypes `yaml:"coreTypes"`
+
+		// IdlePolicy controls how the idle power of the node is attributed to workloads:
+		// exclude: idle power is not attributed to workloads (default)
+		// even: idle power is spread evenly across running workloads
+		// requests: idle power is distributed across pods in proportion to their
+		//           CPU requests (or limits); other workloads get an even share
+		IdlePolicy string `yaml:"idlePolicy"`
+
+		// CPUAccounting selects the source of the CPU time of containers, pods and the node:
+		// procfs: summed from the CPU time of processes read from procfs (default)
+		// cgroup: read from cpu.stat of the cgroup v2 hierarchy under sysfs;
+		//         processes are read only if process or VM metrics are exported
+		// ebpf: summed from the CPU time of processes accounted on every context
+		//       switch by an eBPF program; falls back to procfs if it can't be loaded
+		CPUAccounting string `yaml:"cpuAccounting"`
+
+		// ProcessEvents tracks processes through kernel process events (netlink
+		// proc connector) instead of scanning procfs on every refresh; requires
+		// CAP_NET_ADMIN and falls back to scanning procfs otherwise
+		ProcessEvents *bool `yaml:"processEvents"`
+
+		// PerfEvents counts the instructions, CPU cycles and last level cache
+		// misses of processes with perf events; requires CAP_PERFMON and a CPU
+		// with a performance monitoring unit
+		PerfEvents *bool `yaml:"perfEvents"`
+
+		// SystemdUnits attributes power to the systemd services and scopes
+		// processes run in, read from their cgroup
+		SystemdUnits *bool `yaml:"systemdUnits"`
+
+		// ProcessMetadata reads the command line (truncated), user and parent
+		// of processes; disabled by default as command lines may hold secrets
+		ProcessMetadata *bool `yaml:"processMetadata"`
+
+		// Aggregates attributes the power of kernel threads and of CPU time not
+		// used by any process to synthetic kernel and system aggregates
+		Aggregates *bool `yaml:"aggregates"`
+
+		// OtherWorkloads reports the power attributed to the processes,
+		// containers and pods that are filtered out as a synthetic __other__
+		// process, container and pod
+		OtherWorkloads *bool `yaml:"otherWorkloads"`
+
+		// IO tracks the bytes processes read from and write to disk and receive
+		// and send over the network, and estimates the power of the network
+		// interfaces and block devices of the node from them
+		IO IO `yaml:"io"`
+
+		// Backoff lowers the collection frequency while the node is idle
+		Backoff Backoff `yaml:"backoff"`
+
+		// Filter limits the workloads reported to workloads of interest
+		Filter Filter `yaml:"filter"`
+
+		// Groups attributes power to groups of processes, each the descendants
+		// of a process or the processes of a cgroup
+		Groups []Group `yaml:"groups"`
+
+		// Calibration corrects the power read from zones before it is
+		// attributed, e.g. with factors derived from comparing RAPL or the BMC
+		// against an external power meter
+		Calibration []ZoneCalibration `yaml:"calibration"`
+	}
+
+	// ZoneCalibration corrects the power of the zones of a name as:
+	// scale × power read + offset (in watts)
+	ZoneCalibration struct {
+		Zone   string  `yaml:"zone"`   // name of the zones, e.g. package or platform
+		Scale  float64 `yaml:"scale"`  // 1 if unset
+		Offset float64 `yaml:"offset"` // in watts
+	}
+
+	// Group is a group of processes whose power is attributed as a whole
+	Group struct {
+		Name   string `yaml:"name"`   // name the group is reported as
+		PID    int    `yaml:"pid"`    // process whose descendants, and itself, are the group
+		Cgroup string `yaml:"cgroup"` // cgroup path prefix of the processes of the group
+	}
+
+	// Filter selects the workloads that are reported; a workload is reported if
+	// it matches every include rule that is set and no exclude rule. Workloads
+	// that are filtered out still count towards the CPU time of the node.
+	Filter struct {
+		Include FilterRules `yaml:"include"`
+		Exclude FilterRules `yaml:"exclude"`
+
+		// MinCPUTime is the CPU time a process must use within a refresh before
+		// it is reported; it is reported from then on until it exits
+		MinCPUTime time.Duration `yaml:"minCPUTime"`
+	}
+
+	FilterRules struct {
+		Comm       string   `yaml:"comm"`       // regular expression matched against the command name of processes
+		Cgroups    []string `yaml:"cgroups"`    // cgroup path prefixes of processes
+		Namespaces []string `yaml:"namespaces"` // namespaces of pods; selects pods and their containers and processes
+	}
+
+	// IO reads the disk bytes of processes from procfs and counts their
+	// network bytes with eBPF programs (requires CAP_BPF and CAP_PERFMON);
+	// network and storage zones are added for the models that are set and
+	// are attributed to workloads by the bytes they transferred
+	IO struct {
+		Enabled *bool   `yaml:"enabled"`
+		Network IOModel `yaml:"network"`
+		Storage IOModel `yaml:"storage"`
+	}
+
+	// IOModel estimates power (in watts) as:
+	// idleWatts + wattsPerGBps × throughput (GB/s)
+	IOModel struct {
+		IdleWatts    float64 `yaml:"idleWatts"`
+		WattsPerGBps float64 `yaml:"wattsPerGBps"`
+	}
+
+	// Backoff doubles the collection interval after each collection in which the
+	// node power is below IdleThreshold, up to MaxInterval; the interval is
+	// restored as soon as the node power reaches IdleThreshold
+	Backoff struct {
+		Enabled       *bool         `yaml:"enabled"`
+		MaxInterval   time.Duration `yaml:"maxInterval"`
+		IdleThreshold float64       `yaml:"idleThreshold"` // in watts
+	}
+
+	AttributionModel struct {
+		CPUWeight    float64 `yaml:"cpuWeight"`
+		MemoryWeight float64 `yaml:"memoryWeight"`
+		// InstructionsWeight requires monitor.perfEvents
+		InstructionsWeight float64 `yaml:"instructionsWeight"`
+	}
+
+	// CoreTypes holds the weights of a CPU second on the performance (P-cores)
+	// and efficiency cores (E-cores) of hybrid CPUs; equal weights attribute
+	// energy by CPU time alone
+	CoreTypes struct {
+		PerformanceWeight float64 `yaml:"performanceWeight"`
+		EfficiencyWeight  float64 `yaml:"efficiencyWeight"`
+	}
+
+	AttributionEstimator struct {
+		// Endpoint is an http(s) URL or unix:///path/to/socket
+		Endpoint string        `yaml:"endpoint"`
+		Timeout  time.Duration `yaml:"timeout"` // of each estimate, after which power is attributed by CPU time
 	}
 
 	// Exporter configuration
 	StdoutExporter struct {
-		Enabled *bool `yaml:"enabled"`
+		Enabled  *bool         `yaml:"enabled"`
+		Interval time.Duration `yaml:"interval"` // interval between writes to stdout
 	}
 
 	PrometheusExporter struct {
 		Enabled         *bool    `yaml:"enabled"`
 		DebugCollectors []string `yaml:"debugCollectors"`
 		MetricsLevel    Level    `yaml:"metricsLevel"`
+
+		// MaxProcesses limits the running processes exported to the processes
+		// using the most power; the others are exported as a single series with
+		// pid "other". 0 exports all processes.
+		MaxProcesses int `yaml:"maxProcesses"`
+	}
+
+	// VMExporter serves the power of each VM to Kepler running in the VM
+	VMExporter struct {
+		Enabled *bool `yaml:"enabled"`
+	}
+
+	// PushExporter pushes a summary of the energy consumed by the node and its
+	// workloads on shutdown, e.g. from short-lived batch nodes
+	PushExporter struct {
+		Enabled  *bool         `yaml:"enabled"`
+		URL      string        `yaml:"url"`      // Pushgateway or HTTP endpoint URL
+		Format   string        `yaml:"format"`   // pushgateway or json
+		Job      string        `yaml:"job"`      // job the metrics are grouped by in the Pushgateway
+		Interval time.Duration `yaml:"interval"` // interval between pushes; 0 pushes only on shutdown
+	}
+
+	// WebhookExporter posts an energy report of each container that exits,
+	// e.g. to annotate CI jobs with their energy
+	WebhookExporter struct {
+		Enabled *bool  `yaml:"enabled"`
+		URL     string `yaml:"url"` // URL reports are posted to
+	}
+
+	// LiveExporter streams the power of each collection over a WebSocket as
+	// Grafana data frames, for real-time dashboards
+	LiveExporter struct {
+		Enabled *bool `yaml:"enabled"`
+	}
+
+	// TextfileExporter writes the metrics of the Prometheus exporter to a file
+	// read by the textfile collector of node_exporter
+	TextfileExporter struct {
+		Enabled  *bool         `yaml:"enabled"`
+		Path     string        `yaml:"path"`     // .prom file in the directory of the textfile collector
+		Interval time.Duration `yaml:"interval"` // interval between writes
+	}
+
+	// MQTTTLS configures TLS connections to the MQTT broker
+	MQTTTLS struct {
+		CAFile             string `yaml:"caFile"`   // CA certificates of the broker; the system's if empty
+		CertFile           string `yaml:"certFile"` // client certificate, for mutual TLS
+		KeyFile            string `yaml:"keyFile"`  // key of the client certificate
+		InsecureSkipVerify *bool  `yaml:"insecureSkipVerify"`
+	}
+
+	// MQTTExporter publishes the power of the node and its workloads to an
+	// MQTT broker, e.g. for edge and IoT fleets
+	MQTTExporter struct {
+		Enabled      *bool         `yaml:"enabled"`
+		Broker       string        `yaml:"broker"` // tcp, mqtt, ssl, tls or mqtts URL
+		Topic        string        `yaml:"topic"`  // topic messages are published under; {node} is the node name
+		QoS          int           `yaml:"qos"`    // 0, 1 or 2
+		Retain       *bool         `yaml:"retain"`
+		Interval     time.Duration `yaml:"interval"`     // interval between publishes
+		ClientID     string        `yaml:"clientID"`     // kepler-<node> if empty
+		Username     string        `yaml:"username"`     // empty if authentication is disabled
+		PasswordFile string        `yaml:"passwordFile"` // file containing the password
+		TLS          MQTTTLS       `yaml:"tls"`          // of ssl, tls and mqtts brokers
+
+		// Deltas publishes a full snapshot every FullSnapshotEvery messages and
+		// the deltas from the previous snapshot in between, which saves
+		// bandwidth on nodes with many idle workloads
+		Deltas            *bool `yaml:"deltas"`
+		FullSnapshotEvery int   `yaml:"fullSnapshotEvery"`
 	}
 
 	Exporter struct {
 		Stdout     StdoutExporter     `yaml:"stdout"`
 		Prometheus PrometheusExporter `yaml:"prometheus"`
+		VM         VMExporter         `yaml:"vm"`
+		Push       PushExporter       `yaml:"push"`
+		Webhook    WebhookExporter    `yaml:"webhook"`
+		Live       LiveExporter       `yaml:"live"`
+		Textfile   TextfileExporter   `yaml:"textfile"`
+		MQTT       MQTTExporter       `yaml:"mqtt"`
 	}
 
 	// Debug configuration
@@ -87,21 +706,68 @@ type (
 		Pprof PprofDebug `yaml:"pprof"`
 	}
 
+	// Restart configures restarting services that fail while running; by
+	// default a failing service stops Kepler
+	Restart struct {
+		// Policy is one of:
+		// never: a failing service stops Kepler (default)
+		// on-failure: a failing service is run again after a backoff
+		Policy     string        `yaml:"policy"`
+		MaxRetries int           `yaml:"maxRetries"` // consecutive restarts before Kepler is stopped; 0 for unlimited
+		Backoff    time.Duration `yaml:"backoff"`    // delay before the first restart, doubled on every restart
+		MaxBackoff time.Duration `yaml:"maxBackoff"` // max delay between restarts
+
+		// Services overrides the restart of services by name (e.g. monitor,
+		// api-server); unset fields are taken from above
+		Services map[string]ServiceRestart `yaml:"services"`
+	}
+
+	ServiceRestart struct {
+		Policy     string        `yaml:"policy"`
+		MaxRetries *int          `yaml:"maxRetries"`
+		Backoff    time.Duration `yaml:"backoff"`
+		MaxBackoff time.Duration `yaml:"maxBackoff"`
+	}
+
 	Kube struct {
 		Enabled *bool  `yaml:"enabled"`
 		Config  string `yaml:"config"`
 		Node    string `yaml:"nodeName"`
+
+		// PodMetadata decorates pods with their labels and owner workload
+		// (e.g. Deployment, StatefulSet) and exports the owner of pods
+		PodMetadata *bool `yaml:"podMetadata"`
 	}
 
 	Config struct {
-		Log      Log      `yaml:"log"`
-		Host     Host     `yaml:"host"`
-		Monitor  Monitor  `yaml:"monitor"`
-		Rapl     Rapl     `yaml:"rapl"`
-		Exporter Exporter `yaml:"exporter"`
-		Web      Web      `yaml:"web"`
-		Debug    Debug    `yaml:"debug"`
-		Dev      Dev      `yaml:"dev"` // WARN: do not expose dev settings as flags
+		Log          Log          `yaml:"log"`
+		Host         Host         `yaml:"host"`
+		Monitor      Monitor      `yaml:"monitor"`
+		Rapl         Rapl         `yaml:"rapl"`
+		GPU          GPU          `yaml:"gpu"`
+		Hwmon        Hwmon        `yaml:"hwmon"`
+		Jetson       Jetson       `yaml:"jetson"`
+		Powermetrics Powermetrics `yaml:"powermetrics"`
+		Guest        Guest        `yaml:"guest"`
+		Redfish      Redfish      `yaml:"redfish"`
+		Platform     Platform     `yaml:"platform"`
+		Estimator    Estimator    `yaml:"estimator"`
+		Carbon       Carbon       `yaml:"carbon"`
+		Pricing      Pricing      `yaml:"pricing"`
+		Budget       Budget       `yaml:"budget"`
+		PowerCap     PowerCap     `yaml:"powerCap"`
+		Headroom     Headroom     `yaml:"headroom"`
+		Anomaly      Anomaly      `yaml:"anomaly"`
+		Rightsizing  Rightsizing  `yaml:"rightsizing"`
+		Chargeback   Chargeback   `yaml:"chargeback"`
+		Federation   Federation   `yaml:"federation"`
+		History      History      `yaml:"history"`
+		State        State        `yaml:"state"`
+		Exporter     Exporter     `yaml:"exporter"`
+		Web          Web          `yaml:"web"`
+		Debug        Debug        `yaml:"debug"`
+		Restart      Restart      `yaml:"restart"`
+		Dev          Dev          `yaml:"dev"` // WARN: do not expose dev settings as flags
 
 		Kube Kube `yaml:"kube"`
 	}
@@ -148,6 +814,70 @@ func (m *MetricsLevelValue) IsCumulative() bool {
 
 type SkipValidation int
 
+// Idle power attribution policies
+const (
+	IdlePolicyExclude  = "exclude"
+	IdlePolicyEven     = "even"
+	IdlePolicyRequests = "requests"
+)
+
+// Restart policies of services
+const (
+	RestartPolicyNever     = "never"
+	RestartPolicyOnFailure = "on-failure"
+)
+
+// CPU time accounting sources
+const (
+	CPUAccountingProcFS = "procfs"
+	CPUAccountingCgroup = "cgroup"
+	CPUAccountingEBPF   = "ebpf"
+)
+
+// Power attribution strategies
+const (
+	AttributionCPUTime    = "cpu-time"
+	AttributionCPUMemory  = "cpu-memory"
+	AttributionModelBased = "model"
+	AttributionExternal   = "external"
+)
+
+// Sources of the power of the platform
+const (
+	PlatformSourceRedfish   = "redfish"
+	PlatformSourceACPI      = "acpi"
+	PlatformSourceBattery   = "battery"
+	PlatformSourceSmartPlug = "smartplug"
+)
+
+// Types of smart plugs
+const (
+	SmartPlugTasmota    = "tasmota"
+	SmartPlugShelly     = "shelly"
+	SmartPlugShellyGen2 = "shelly-gen2"
+	SmartPlugKasa       = "kasa"
+)
+
+// Power limiters of the power cap
+const (
+	PowerCapLimiterRAPL    = "rapl"
+	PowerCapLimiterRedfish = "redfish"
+)
+
+// Push exporter formats
+const (
+	PushFormatPushgateway = "pushgateway"
+	PushFormatJSON        = "json"
+)
+
+// Carbon intensity providers
+const (
+	CarbonProviderNone            = "none"
+	CarbonProviderStatic          = "static"
+	CarbonProviderElectricityMaps = "electricitymaps"
+	CarbonProviderWattTime        = "watttime"
+)
+
 const (
 	SkipHostValidation SkipValidation = 1
 	SkipKubeValidation SkipValidation = 2
@@ -160,31 +890,249 @@ const (
 
 	HostSysFSFlag  = "host.sysfs"
 	HostProcFSFlag = "host.procfs"
+	HostLibvirt    = "host.libvirt" // not a flag
+	HostCRI        = "host.cri"     // not a flag
+	HostDocker     = "host.docker"  // not a flag
 
 	MonitorIntervalFlag      = "monitor.interval"
-	MonitorStaleness         = "monitor.staleness" // not a flag
+	MonitorStaleness         = "monitor.staleness"       // not a flag
+	MonitorSampleInterval    = "monitor.sample-interval" // not a flag
 	MonitorMaxTerminatedFlag = "monitor.max-terminated"
+	MonitorMaxTerminatedAge  = "monitor.max-terminated-age" // not a flag
+	MonitorAttribution       = "monitor.attribution"        // not a flag
+	MonitorIdlePolicy        = "monitor.idle-policy"        // not a flag
+	MonitorCPUAccounting     = "monitor.cpu-accounting"     // not a flag
+	MonitorProcessEvents     = "monitor.process-events"     // not a flag
+	MonitorPerfEvents        = "monitor.perf-events"        // not a flag
+	MonitorSystemdUnits      = "monitor.systemd-units"      // not a flag
+	MonitorProcessMetadata   = "monitor.process-metadata"   // not a flag
+	MonitorAggregates        = "monitor.aggregates"         // not a flag
+	MonitorOtherWorkloads    = "monitor.other-workloads"    // not a flag
+
+	MonitorAttributionModelCPUWeight    = "monitor.attribution-model.cpu-weight"    // not a flag
+	MonitorAttributionModelMemoryWeight = "monitor.attribution-model.memory-weight" // not a flag
+
+	MonitorAttributionModelInstructionsWeight = "monitor.attribution-model.instructions-weight" // not a flag
+
+	MonitorAttributionEstimatorEndpoint = "monitor.attribution-estimator.endpoint" // not a flag
+	MonitorAttributionEstimatorTimeout  = "monitor.attribution-estimator.timeout"  // not a flag
+
+	MonitorCoreTypesPerformanceWeight = "monitor.core-types.performance-weight" // not a flag
+	MonitorCoreTypesEfficiencyWeight  = "monitor.core-types.efficiency-weight"  // not a flag
+
+	MonitorIOEnabled             = "monitor.io.enabled"                // not a flag
+	MonitorIONetworkIdleWatts    = "monitor.io.network.idle-watts"     // not a flag
+	MonitorIONetworkWattsPerGBps = "monitor.io.network.watts-per-gbps" // not a flag
+	MonitorIOStorageIdleWatts    = "monitor.io.storage.idle-watts"     // not a flag
+	MonitorIOStorageWattsPerGBps = "monitor.io.storage.watts-per-gbps" // not a flag
+
+	MonitorBackoffEnabled       = "monitor.backoff.enabled"        // not a flag
+	MonitorBackoffMaxInterval   = "monitor.backoff.max-interval"   // not a flag
+	MonitorBackoffIdleThreshold = "monitor.backoff.idle-threshold" // not a flag
+
+	MonitorFilterIncludeComm       = "monitor.filter.include.comm"       // not a flag
+	MonitorFilterIncludeCgroups    = "monitor.filter.include.cgroups"    // not a flag
+	MonitorFilterIncludeNamespaces = "monitor.filter.include.namespaces" // not a flag
+	MonitorFilterExcludeComm       = "monitor.filter.exclude.comm"       // not a flag
+	MonitorFilterExcludeCgroups    = "monitor.filter.exclude.cgroups"    // not a flag
+	MonitorFilterExcludeNamespaces = "monitor.filter.exclude.namespaces" // not a flag
+	MonitorFilterMinCPUTime        = "monitor.filter.min-cpu-time"       // not a flag
+
+	MonitorGroups = "monitor.groups" // not a flag
+
+	MonitorCalibration = "monitor.calibration" // not a flag
 
 	// RAPL
-	RaplZones = "rapl.zones" // not a flag
+	RaplZones        = "rapl.zones"         // not a flag
+	RaplExcludeZones = "rapl.exclude-zones" // not a flag
+	RaplPerSocket    = "rapl.per-socket"    // not a flag
+
+	// GPU
+	GPUEnabled       = "gpu.enabled"         // not a flag
+	GPUNVIDIASMIPath = "gpu.nvidia.smi-path" // not a flag
+
+	GPUNVIDIADevicePluginCheckpoint = "gpu.nvidia.device-plugin-checkpoint" // not a flag
+	GPUHabanaSMIPath                = "gpu.habana.smi-path"                 // not a flag
+
+	// Hwmon
+	HwmonEnabled = "hwmon.enabled" // not a flag
+	HwmonRails   = "hwmon.rails"   // not a flag
+
+	// Jetson
+	JetsonEnabled        = "jetson.enabled"         // not a flag
+	JetsonTegrastatsPath = "jetson.tegrastats-path" // not a flag
+
+	// Powermetrics
+	PowermetricsEnabled = "powermetrics.enabled" // not a flag
+	PowermetricsPath    = "powermetrics.path"    // not a flag
+
+	// Guest
+	GuestEnabled  = "guest.enabled"  // not a flag
+	GuestEndpoint = "guest.endpoint" // not a flag
+	GuestVMID     = "guest.vm-id"    // not a flag
+
+	// Redfish
+	RedfishEnabled            = "redfish.enabled"              // not a flag
+	RedfishEndpoint           = "redfish.endpoint"             // not a flag
+	RedfishChassis            = "redfish.chassis"              // not a flag
+	RedfishUsername           = "redfish.username"             // not a flag
+	RedfishPasswordFile       = "redfish.password-file"        // not a flag
+	RedfishInsecureSkipVerify = "redfish.insecure-skip-verify" // not a flag
+	RedfishMinInterval        = "redfish.min-interval"         // not a flag
+	RedfishJitter             = "redfish.jitter"               // not a flag
+	RedfishPowerField         = "redfish.power-field"          // not a flag
+	RedfishPowerScale         = "redfish.power-scale"          // not a flag
+	RedfishAveragingInterval  = "redfish.averaging-interval"   // not a flag
+	RedfishEventsEnabled      = "redfish.events.enabled"       // not a flag
+	RedfishEventsInterval     = "redfish.events.interval"      // not a flag
+	RedfishEventsLogServices  = "redfish.events.log-services"  // not a flag
+
+	// Platform
+	PlatformACPI       = "platform.acpi"        // not a flag
+	PlatformBattery    = "platform.battery"     // not a flag
+	PlatformPrecedence = "platform.precedence"  // not a flag
+	PlatformMaxLatency = "platform.max-latency" // not a flag
+	PlatformSmartPlugs = "platform.smart-plugs" // not a flag
+
+	// Estimator
+	EstimatorEnabled          = "estimator.enabled"                // not a flag
+	EstimatorModelFile        = "estimator.model-file"             // not a flag
+	EstimatorModelURL         = "estimator.model-url"              // not a flag
+	EstimatorModelRefresh     = "estimator.model-refresh-interval" // not a flag
+	EstimatorModelIntercept   = "estimator.model.intercept"        // not a flag
+	EstimatorModelUtilization = "estimator.model.utilization"      // not a flag
+	EstimatorModelFrequency   = "estimator.model.frequency"        // not a flag
+	EstimatorModelDeepIdle    = "estimator.model.deep-idle"        // not a flag
+	EstimatorModelUncertainty = "estimator.model.uncertainty"      // not a flag
+
+	// Carbon
+	CarbonProvider                 = "carbon.provider"                    // not a flag
+	CarbonRefreshInterval          = "carbon.refresh-interval"            // not a flag
+	CarbonStaticIntensity          = "carbon.static.intensity"            // not a flag
+	CarbonElectricityMapsZone      = "carbon.electricity-maps.zone"       // not a flag
+	CarbonElectricityMapsTokenFile = "carbon.electricity-maps.token-file" // not a flag
+	CarbonWattTimeRegion           = "carbon.watttime.region"             // not a flag
+	CarbonWattTimeUsername         = "carbon.watttime.username"           // not a flag
+	CarbonWattTimePasswordFile     = "carbon.watttime.password-file"      // not a flag
+
+	// Pricing
+	PricingPrice    = "pricing.price"    // not a flag
+	PricingTimezone = "pricing.timezone" // not a flag
+	PricingSchedule = "pricing.schedule" // not a flag
+
+	// Budget
+	BudgetNode       = "budget.node"        // not a flag
+	BudgetNamespaces = "budget.namespaces"  // not a flag
+	BudgetWebhookURL = "budget.webhook-url" // not a flag
+
+	// PowerCap
+	PowerCapEnabled  = "power-cap.enabled"   // not a flag
+	PowerCapLimiter  = "power-cap.limiter"   // not a flag
+	PowerCapWatts    = "power-cap.watts"     // not a flag
+	PowerCapDryRun   = "power-cap.dry-run"   // not a flag
+	PowerCapAllowAPI = "power-cap.allow-api" // not a flag
+
+	// Headroom
+	HeadroomEnabled  = "headroom.enabled"   // not a flag
+	HeadroomWindow   = "headroom.window"    // not a flag
+	HeadroomCapWatts = "headroom.cap-watts" // not a flag
+
+	// Anomaly
+	AnomalyEnabled   = "anomaly.enabled"   // not a flag
+	AnomalyThreshold = "anomaly.threshold" // not a flag
+	AnomalyAlpha     = "anomaly.alpha"     // not a flag
+	AnomalyWorkloads = "anomaly.workloads" // not a flag
+
+	// Rightsizing
+	RightsizingEnabled  = "rightsizing.enabled"  // not a flag
+	RightsizingInterval = "rightsizing.interval" // not a flag
+
+	// Chargeback
+	ChargebackEnabled  = "chargeback.enabled"  // not a flag
+	ChargebackInterval = "chargeback.interval" // not a flag
+	ChargebackTopPods  = "chargeback.top-pods" // not a flag
+
+	// Federation
+	FederationEnabled  = "federation.enabled"  // not a flag
+	FederationInterval = "federation.interval" // not a flag
+	FederationTimeout  = "federation.timeout"  // not a flag
+	FederationAgents   = "federation.agents"   // not a flag
+
+	// History
+	HistoryEnabled      = "history.enabled"       // not a flag
+	HistoryPath         = "history.path"          // not a flag
+	HistoryRetention    = "history.retention"     // not a flag
+	HistoryMetricsLevel = "history.metrics-level" // not a flag
+
+	HistoryHourlyRetention = "history.rollups.hourly-retention" // not a flag
+	HistoryDailyRetention  = "history.rollups.daily-retention"  // not a flag
+
+	// State
+	StateEnabled  = "state.enabled"  // not a flag
+	StatePath     = "state.path"     // not a flag
+	StateInterval = "state.interval" // not a flag
 
 	pprofEnabledFlag = "debug.pprof"
 
-	WebConfigFlag        = "web.config-file"
-	WebListenAddressFlag = "web.listen-address"
+	// Restart
+	RestartPolicy     = "restart.policy"      // not a flag
+	RestartMaxRetries = "restart.max-retries" // not a flag
+	RestartBackoff    = "restart.backoff"     // not a flag
+	RestartMaxBackoff = "restart.max-backoff" // not a flag
+	RestartServices   = "restart.services"    // not a flag
+
+	WebConfigFlag          = "web.config-file"
+	WebListenAddressFlag   = "web.listen-address"
+	WebEnableLifecycleFlag = "web.enable-lifecycle"
 
 	// Exporters
 	ExporterStdoutEnabledFlag = "exporter.stdout"
+	ExporterStdoutInterval    = "exporter.stdout.interval" // not a flag
 
 	ExporterPrometheusEnabledFlag = "exporter.prometheus"
 	// NOTE: not a flag
 	ExporterPrometheusDebugCollectors = "exporter.prometheus.debug-collectors"
 	ExporterPrometheusMetricsFlag     = "metrics"
+	ExporterPrometheusMaxProcesses    = "exporter.prometheus.max-processes" // not a flag
+
+	ExporterVMEnabledFlag = "exporter.vm"
+
+	ExporterPushEnabledFlag = "exporter.push"
+	ExporterPushURLFlag     = "exporter.push.url"
+	ExporterPushFormat      = "exporter.push.format"   // not a flag
+	ExporterPushJob         = "exporter.push.job"      // not a flag
+	ExporterPushInterval    = "exporter.push.interval" // not a flag
+
+	ExporterWebhookEnabledFlag = "exporter.webhook"
+	ExporterWebhookURLFlag     = "exporter.webhook.url"
+
+	ExporterLiveEnabledFlag = "exporter.live"
+
+	ExporterTextfileEnabledFlag = "exporter.textfile"
+	ExporterTextfilePathFlag    = "exporter.textfile.path"
+	ExporterTextfileInterval    = "exporter.textfile.interval" // not a flag
+
+	ExporterMQTTEnabledFlag           = "exporter.mqtt"
+	ExporterMQTTBrokerFlag            = "exporter.mqtt.broker"
+	ExporterMQTTTopic                 = "exporter.mqtt.topic"                    // not a flag
+	ExporterMQTTQoS                   = "exporter.mqtt.qos"                      // not a flag
+	ExporterMQTTRetain                = "exporter.mqtt.retain"                   // not a flag
+	ExporterMQTTInterval              = "exporter.mqtt.interval"                 // not a flag
+	ExporterMQTTClientID              = "exporter.mqtt.client-id"                // not a flag
+	ExporterMQTTUsername              = "exporter.mqtt.username"                 // not a flag
+	ExporterMQTTPasswordFile          = "exporter.mqtt.password-file"            // not a flag
+	ExporterMQTTTLSCAFile             = "exporter.mqtt.tls.ca-file"              // not a flag
+	ExporterMQTTTLSCertFile           = "exporter.mqtt.tls.cert-file"            // not a flag
+	ExporterMQTTTLSKeyFile            = "exporter.mqtt.tls.key-file"             // not a flag
+	ExporterMQTTTLSInsecureSkipVerify = "exporter.mqtt.tls.insecure-skip-verify" // not a flag
+	ExporterMQTTDeltas                = "exporter.mqtt.deltas"                   // not a flag
+	ExporterMQTTFullSnapshotEvery     = "exporter.mqtt.full-snapshot-every"      // not a flag
 
 	// kubernetes flags
 	KubernetesFlag   = "kube.enable"
 	KubeConfigFlag   = "kube.config"
 	KubeNodeNameFlag = "kube.node-name"
+	KubePodMetadata  = "kube.pod-metadata" // not a flag
 
 // WARN:  dev settings shouldn't be exposed as flags as flags are intended for end users
 )
@@ -197,11 +1145,115 @@ func DefaultConfig() *Config {
 			Format: "text",
 		},
 		Host: Host{
-			SysFS:  "/sys",
-			ProcFS: "/proc",
+			SysFS:   "/sys",
+			ProcFS:  "/proc",
+			Libvirt: "/run/libvirt/qemu",
 		},
 		Rapl: Rapl{
-			Zones: []string{},
+			Zones:        []string{},
+			ExcludeZones: []string{},
+			PerSocket:    ptr.To(false),
+		},
+		GPU: GPU{
+			Enabled: ptr.To(false),
+			NVIDIA: NVIDIA{
+				SMIPath:                "nvidia-smi",
+				DevicePluginCheckpoint: "/var/lib/kubelet/device-plugins/kubelet_internal_checkpoint",
+			},
+			Habana: Habana{
+				SMIPath: "hl-smi",
+			},
+		},
+		Hwmon: Hwmon{
+			Enabled: ptr.To(false),
+		},
+		Jetson: Jetson{
+			Enabled:        ptr.To(false),
+			TegrastatsPath: "tegrastats",
+		},
+		Powermetrics: Powermetrics{
+			Enabled: ptr.To(false),
+			Path:    "/usr/bin/powermetrics",
+		},
+		Guest: Guest{
+			Enabled:  ptr.To(false),
+			Endpoint: "vsock://2:28282/vms",
+		},
+		Redfish: Redfish{
+			Enabled:            ptr.To(false),
+			InsecureSkipVerify: ptr.To(false),
+			PowerScale:         1,
+			Events: RedfishEvents{
+				Enabled:  ptr.To(false),
+				Interval: time.Minute,
+			},
+		},
+		Platform: Platform{
+			ACPI:       ptr.To(false),
+			Battery:    ptr.To(false),
+			Precedence: []string{PlatformSourceRedfish, PlatformSourceACPI},
+		},
+		Estimator: Estimator{
+			Enabled: ptr.To(false),
+			Model: LinearModel{
+				Intercept:   10,
+				Utilization: 90,
+				Frequency:   0,
+			},
+		},
+		Carbon: Carbon{
+			Provider:        CarbonProviderNone,
+			RefreshInterval: 15 * time.Minute,
+		},
+		Pricing: Pricing{
+			Timezone: "UTC",
+		},
+		Budget: Budget{
+			Namespaces: map[string]int64{},
+		},
+		PowerCap: PowerCap{
+			Enabled:  ptr.To(false),
+			Limiter:  PowerCapLimiterRAPL,
+			DryRun:   ptr.To(false),
+			AllowAPI: ptr.To(false),
+		},
+		Headroom: Headroom{
+			Enabled: ptr.To(false),
+			Window:  5 * time.Minute,
+		},
+		Anomaly: Anomaly{
+			Enabled:   ptr.To(false),
+			Threshold: 4,
+			Alpha:     0.1,
+			Workloads: 10,
+		},
+		Rightsizing: Rightsizing{
+			Enabled:  ptr.To(false),
+			Interval: time.Hour,
+		},
+		Chargeback: Chargeback{
+			Enabled:  ptr.To(false),
+			Interval: 24 * time.Hour,
+			TopPods:  5,
+		},
+		Federation: Federation{
+			Enabled:  ptr.To(false),
+			Interval: 15 * time.Second,
+			Timeout:  5 * time.Second,
+		},
+		History: History{
+			Enabled:      ptr.To(false),
+			Retention:    24 * time.Hour,
+			MetricsLevel: MetricsLevelNode | MetricsLevelContainer | MetricsLevelVM | MetricsLevelPod,
+			Rollups: HistoryRollups{
+				HourlyRetention: 7 * 24 * time.Hour,
+				DailyRetention:  90 * 24 * time.Hour,
+			},
+		},
+		State: State{
+			Enabled:  ptr.To(false),
+			Path:     "/var/lib/kepler/state.json",
+			Interval: time.Minute,
 		},
 		Monitor: Monitor{
 			Interval:  5 * time.Second,
@@ -209,16 +1261,76 @@ func DefaultConfig() *Config {
 
 			MaxTerminated:                500,
 			MinTerminatedEnergyThreshold: 10, // 10 Joules
+
+			Attribution: AttributionCPUTime,
+			AttributionModel: AttributionModel{
+				CPUWeight:    0.8,
+				MemoryWeight: 0.2,
+			},
+			AttributionEstimator: AttributionEstimator{
+				Timeout: time.Second,
+			},
+			CoreTypes: CoreTypes{
+				PerformanceWeight: 1,
+				EfficiencyWeight:  1,
+			},
+			IdlePolicy:      IdlePolicyExclude,
+			CPUAccounting:   CPUAccountingProcFS,
+			ProcessEvents:   ptr.To(false),
+			PerfEvents:      ptr.To(false),
+			SystemdUnits:    ptr.To(false),
+			ProcessMetadata: ptr.To(false),
+			Aggregates:      ptr.To(false),
+			OtherWorkloads:  ptr.To(false),
+			IO: IO{
+				Enabled: ptr.To(false),
+			},
+			Backoff: Backoff{
+				Enabled:       ptr.To(false),
+				MaxInterval:   time.Minute,
+				IdleThreshold: 10,
+			},
 		},
 		Exporter: Exporter{
 			Stdout: StdoutExporter{
-				Enabled: ptr.To(false),
+				Enabled:  ptr.To(false),
+				Interval: 2 * time.Second,
 			},
 			Prometheus: PrometheusExporter{
 				Enabled:         ptr.To(true),
 				DebugCollectors: []string{"go"},
 				MetricsLevel:    MetricsLevelAll,
 			},
+			VM: VMExporter{
+				Enabled: ptr.To(false),
+			},
+			Push: PushExporter{
+				Enabled: ptr.To(false),
+				Format:  PushFormatPushgateway,
+				Job:     "kepler",
+			},
+			Webhook: WebhookExporter{
+				Enabled: ptr.To(false),
+			},
+			Live: LiveExporter{
+				Enabled: ptr.To(false),
+			},
+			Textfile: TextfileExporter{
+				Enabled:  ptr.To(false),
+				Path:     "/var/lib/node_exporter/textfile_collector/kepler.prom",
+				Interval: 15 * time.Second,
+			},
+			MQTT: MQTTExporter{
+				Enabled:  ptr.To(false),
+				Topic:    "kepler/{node}",
+				Retain:   ptr.To(false),
+				Interval: 15 * time.Second,
+				TLS: MQTTTLS{
+					InsecureSkipVerify: ptr.To(false),
+				},
+				Deltas:            ptr.To(false),
+				FullSnapshotEvery: 10,
+			},
 		},
 		Debug: Debug{
 			Pprof: PprofDebug{
@@ -227,9 +1339,18 @@ func DefaultConfig() *Config {
 		},
 		Web: Web{
 			ListenAddresses: []string{":28282"},
+			EnableLifecycle: ptr.To(false),
+		},
+		Restart: Restart{
+			Policy:     RestartPolicyNever,
+			MaxRetries: 5,
+			Backoff:    time.Second,
+			MaxBackoff: time.Minute,
+			Services:   map[string]ServiceRestart{},
 		},
 		Kube: Kube{
-			Enabled: ptr.To(false),
+			Enabled:     ptr.To(false),
+			PodMetadata: ptr.To(false),
 		},
 	}
 
@@ -246,7 +1367,10 @@ func Load(r io.Reader) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	if err := yaml.Unmarshal(data, cfg); err != nil {
+	// unknown fields are rejected so that misspelt settings aren't ignored
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 	cfg.sanitize()
@@ -279,6 +1403,15 @@ func FromFile(filePath string) (*Config, error) {
 
 type ConfigUpdaterFn func(*Config) error
 
+// hostDirFlag returns the value of a flag of a host directory, which must exist
+// except on windows and macOS, where there is no sysfs nor procfs
+func hostDirFlag(flag *kingpin.FlagClause) *string {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return flag.String()
+	}
+	return flag.ExistingDir()
+}
+
 // RegisterFlags registers command-line flags with kingpin app
 // and returns ConfigUpdaterFn that updates the config from parsed flags
 // as command line arguments override config file settings
@@ -302,8 +1435,8 @@ func RegisterFlags(app *kingpin.Application) ConfigUpdaterFn {
 	logLevel := app.Flag(LogLevelFlag, "Logging level: debug, info, warn, error").Default("info").Enum("debug", "info", "warn", "error")
 	logFormat := app.Flag(LogFormatFlag, "Logging format: text or json").Default("text").Enum("text", "json")
 	// host
-	hostSysFS := app.Flag(HostSysFSFlag, "Host sysfs path").Default("/sys").ExistingDir()
-	hostProcFS := app.Flag(HostProcFSFlag, "Host procfs path").Default("/proc").ExistingDir()
+	hostSysFS := hostDirFlag(app.Flag(HostSysFSFlag, "Host sysfs path").Default("/sys"))
+	hostProcFS := hostDirFlag(app.Flag(HostProcFSFlag, "Host procfs path").Default("/proc"))
 
 	// monitor
 	monitorInterval := app.Flag(MonitorIntervalFlag,
@@ -314,12 +1447,25 @@ func RegisterFlags(app *kingpin.Application) ConfigUpdaterFn {
 	enablePprof := app.Flag(pprofEnabledFlag, "Enable pprof debug endpoints").Default("false").Bool()
 	webConfig := app.Flag(WebConfigFlag, "Web config file path").Default("").String()
 	webListenAddresses := app.Flag(WebListenAddressFlag, "Web server listen addresses").Default(":28282").Strings()
+	webEnableLifecycle := app.Flag(WebEnableLifecycleFlag, "Enable reloading the configuration and changing the log level over HTTP").Default("false").Bool()
 
 	// exporters
 	stdoutExporterEnabled := app.Flag(ExporterStdoutEnabledFlag, "Enable stdout exporter").Default("false").Bool()
 
 	prometheusExporterEnabled := app.Flag(ExporterPrometheusEnabledFlag, "Enable Prometheus exporter").Default("true").Bool()
 
+	vmExporterEnabled := app.Flag(ExporterVMEnabledFlag, "Serve the power of each VM to Kepler running in the VM").Default("false").Bool()
+
+	pushExporterEnabled := app.Flag(ExporterPushEnabledFlag, "Push an energy summary to a Pushgateway or HTTP endpoint on shutdown").Default("false").Bool()
+	pushExporterURL := app.Flag(ExporterPushURLFlag, "Pushgateway or HTTP endpoint URL the energy summary is pushed to").Default("").String()
+	webhookExporterEnabled := app.Flag(ExporterWebhookEnabledFlag, "Post an energy report of each container that exits to a webhook").Default("false").Bool()
+	webhookExporterURL := app.Flag(ExporterWebhookURLFlag, "Webhook URL the energy reports of containers are posted to").Default("").String()
+	liveExporterEnabled := app.Flag(ExporterLiveEnabledFlag, "Stream the power of each collection over a WebSocket as Grafana data frames").Default("false").Bool()
+	textfileExporterEnabled := app.Flag(ExporterTextfileEnabledFlag, "Write the metrics to a file read by the textfile collector of node_exporter").Default("false").Bool()
+	textfileExporterPath := app.Flag(ExporterTextfilePathFlag, "Path of the .prom file the metrics are written to").Default("/var/lib/node_exporter/textfile_collector/kepler.prom").String()
+	mqttExporterEnabled := app.Flag(ExporterMQTTEnabledFlag, "Publish the power of the node and workloads to an MQTT broker").Default("false").Bool()
+	mqttExporterBroker := app.Flag(ExporterMQTTBrokerFlag, "URL of the MQTT broker, e.g. tcp://broker:1883 or ssl://broker:8883").Default("").String()
+
 	metricsLevel := MetricsLevelAll
 	app.Flag(ExporterPrometheusMetricsFlag, "Metrics levels to export (node,process,container,vm,pod)").SetValue(NewMetricsLevelValue(&metricsLevel))
 
@@ -365,6 +1511,10 @@ func RegisterFlags(app *kingpin.Application) ConfigUpdaterFn {
 			cfg.Web.ListenAddresses = *webListenAddresses
 		}
 
+		if flagsSet[WebEnableLifecycleFlag] {
+			cfg.Web.EnableLifecycle = webEnableLifecycle
+		}
+
 		if flagsSet[ExporterStdoutEnabledFlag] {
 			cfg.Exporter.Stdout.Enabled = stdoutExporterEnabled
 		}
@@ -377,6 +1527,46 @@ func RegisterFlags(app *kingpin.Application) ConfigUpdaterFn {
 			cfg.Exporter.Prometheus.MetricsLevel = metricsLevel
 		}
 
+		if flagsSet[ExporterVMEnabledFlag] {
+			cfg.Exporter.VM.Enabled = vmExporterEnabled
+		}
+
+		if flagsSet[ExporterPushEnabledFlag] {
+			cfg.Exporter.Push.Enabled = pushExporterEnabled
+		}
+
+		if flagsSet[ExporterPushURLFlag] {
+			cfg.Exporter.Push.URL = *pushExporterURL
+		}
+
+		if flagsSet[ExporterWebhookEnabledFlag] {
+			cfg.Exporter.Webhook.Enabled = webhookExporterEnabled
+		}
+
+		if flagsSet[ExporterWebhookURLFlag] {
+			cfg.Exporter.Webhook.URL = *webhookExporterURL
+		}
+
+		if flagsSet[ExporterLiveEnabledFlag] {
+			cfg.Exporter.Live.Enabled = liveExporterEnabled
+		}
+
+		if flagsSet[ExporterTextfileEnabledFlag] {
+			cfg.Exporter.Textfile.Enabled = textfileExporterEnabled
+		}
+
+		if flagsSet[ExporterTextfilePathFlag] {
+			cfg.Exporter.Textfile.Path = *textfileExporterPath
+		}
+
+		if flagsSet[ExporterMQTTEnabledFlag] {
+			cfg.Exporter.MQTT.Enabled = mqttExporterEnabled
+		}
+
+		if flagsSet[ExporterMQTTBrokerFlag] {
+			cfg.Exporter.MQTT.Broker = *mqttExporterBroker
+		}
+
 		if flagsSet[KubernetesFlag] {
 			cfg.Kube.Enabled = kubernetes
 		}
@@ -399,6 +1589,11 @@ func (c *Config) sanitize() {
 	c.Log.Format = strings.TrimSpace(c.Log.Format)
 	c.Host.SysFS = strings.TrimSpace(c.Host.SysFS)
 	c.Host.ProcFS = strings.TrimSpace(c.Host.ProcFS)
+	c.Host.Libvirt = strings.TrimSpace(c.Host.Libvirt)
+	c.Host.CRI = strings.TrimSpace(c.Host.CRI)
+	for i := range c.Host.Docker {
+		c.Host.Docker[i] = strings.TrimSpace(c.Host.Docker[i])
+	}
 	c.Web.Config = strings.TrimSpace(c.Web.Config)
 	for i := range c.Web.ListenAddresses {
 		c.Web.ListenAddresses[i] = strings.TrimSpace(c.Web.ListenAddresses[i])
@@ -407,11 +1602,113 @@ func (c *Config) sanitize() {
 	for i := range c.Rapl.Zones {
 		c.Rapl.Zones[i] = strings.TrimSpace(c.Rapl.Zones[i])
 	}
+	for i := range c.Rapl.ExcludeZones {
+		c.Rapl.ExcludeZones[i] = strings.TrimSpace(c.Rapl.ExcludeZones[i])
+	}
+	c.GPU.NVIDIA.SMIPath = strings.TrimSpace(c.GPU.NVIDIA.SMIPath)
+	c.GPU.NVIDIA.DevicePluginCheckpoint = strings.TrimSpace(c.GPU.NVIDIA.DevicePluginCheckpoint)
+	c.GPU.Habana.SMIPath = strings.TrimSpace(c.GPU.Habana.SMIPath)
+	for i := range c.Monitor.Groups {
+		g := &c.Monitor.Groups[i]
+		g.Name = strings.TrimSpace(g.Name)
+		g.Cgroup = strings.TrimSpace(g.Cgroup)
+	}
+	for i := range c.Monitor.Calibration {
+		cal := &c.Monitor.Calibration[i]
+		cal.Zone = strings.TrimSpace(cal.Zone)
+		if cal.Scale == 0 {
+			cal.Scale = 1
+		}
+	}
+	for i := range c.Hwmon.Rails {
+		rail := &c.Hwmon.Rails[i]
+		rail.Zone = strings.TrimSpace(rail.Zone)
+		rail.Sensor = strings.TrimSpace(rail.Sensor)
+		rail.Label = strings.TrimSpace(rail.Label)
+	}
+	c.Jetson.TegrastatsPath = strings.TrimSpace(c.Jetson.TegrastatsPath)
+	c.Powermetrics.Path = strings.TrimSpace(c.Powermetrics.Path)
+	c.Guest.Endpoint = strings.TrimSpace(c.Guest.Endpoint)
+	c.Guest.VMID = strings.TrimSpace(c.Guest.VMID)
+	c.Redfish.Endpoint = strings.TrimSpace(c.Redfish.Endpoint)
+	c.Redfish.Chassis = strings.TrimSpace(c.Redfish.Chassis)
+	c.Redfish.Username = strings.TrimSpace(c.Redfish.Username)
+	c.Redfish.PasswordFile = strings.TrimSpace(c.Redfish.PasswordFile)
+	c.Redfish.PowerField = strings.TrimSpace(c.Redfish.PowerField)
+	for i, path := range c.Redfish.Events.LogServices {
+		c.Redfish.Events.LogServices[i] = strings.TrimSpace(path)
+	}
+	for i := range c.Platform.Precedence {
+		c.Platform.Precedence[i] = strings.TrimSpace(c.Platform.Precedence[i])
+	}
+	for i := range c.Platform.SmartPlugs {
+		plug := &c.Platform.SmartPlugs[i]
+		plug.Node = strings.TrimSpace(plug.Node)
+		plug.Type = strings.TrimSpace(plug.Type)
+		plug.Address = strings.TrimSpace(plug.Address)
+		plug.Username = strings.TrimSpace(plug.Username)
+		plug.PasswordFile = strings.TrimSpace(plug.PasswordFile)
+	}
+	c.Estimator.ModelFile = strings.TrimSpace(c.Estimator.ModelFile)
+	c.Estimator.ModelURL = strings.TrimSpace(c.Estimator.ModelURL)
 
 	for i := range c.Exporter.Prometheus.DebugCollectors {
 		c.Exporter.Prometheus.DebugCollectors[i] = strings.TrimSpace(c.Exporter.Prometheus.DebugCollectors[i])
 	}
+	c.Exporter.Push.URL = strings.TrimSpace(c.Exporter.Push.URL)
+	c.Exporter.Push.Format = strings.TrimSpace(c.Exporter.Push.Format)
+	c.Exporter.Push.Job = strings.TrimSpace(c.Exporter.Push.Job)
+	c.Exporter.Webhook.URL = strings.TrimSpace(c.Exporter.Webhook.URL)
+	c.Exporter.Textfile.Path = strings.TrimSpace(c.Exporter.Textfile.Path)
+	c.Exporter.MQTT.Broker = strings.TrimSpace(c.Exporter.MQTT.Broker)
+	c.Exporter.MQTT.Topic = strings.TrimSpace(c.Exporter.MQTT.Topic)
+	c.Exporter.MQTT.ClientID = strings.TrimSpace(c.Exporter.MQTT.ClientID)
+	c.Exporter.MQTT.Username = strings.TrimSpace(c.Exporter.MQTT.Username)
+	c.Exporter.MQTT.PasswordFile = strings.TrimSpace(c.Exporter.MQTT.PasswordFile)
+	c.Exporter.MQTT.TLS.CAFile = strings.TrimSpace(c.Exporter.MQTT.TLS.CAFile)
+	c.Exporter.MQTT.TLS.CertFile = strings.TrimSpace(c.Exporter.MQTT.TLS.CertFile)
+	c.Exporter.MQTT.TLS.KeyFile = strings.TrimSpace(c.Exporter.MQTT.TLS.KeyFile)
 	c.Kube.Config = strings.TrimSpace(c.Kube.Config)
+	c.Monitor.Attribution = strings.TrimSpace(c.Monitor.Attribution)
+	c.Monitor.AttributionEstimator.Endpoint = strings.TrimSpace(c.Monitor.AttributionEstimator.Endpoint)
+	c.Monitor.IdlePolicy = strings.TrimSpace(c.Monitor.IdlePolicy)
+	c.Monitor.CPUAccounting = strings.TrimSpace(c.Monitor.CPUAccounting)
+
+	c.Carbon.Provider = strings.TrimSpace(c.Carbon.Provider)
+	c.Carbon.ElectricityMaps.Zone = strings.TrimSpace(c.Carbon.ElectricityMaps.Zone)
+	c.Carbon.ElectricityMaps.TokenFile = strings.TrimSpace(c.Carbon.ElectricityMaps.TokenFile)
+	c.Carbon.WattTime.Region = strings.TrimSpace(c.Carbon.WattTime.Region)
+	c.Carbon.WattTime.Username = strings.TrimSpace(c.Carbon.WattTime.Username)
+	c.Carbon.WattTime.PasswordFile = strings.TrimSpace(c.Carbon.WattTime.PasswordFile)
+
+	c.PowerCap.Limiter = strings.TrimSpace(c.PowerCap.Limiter)
+
+	c.Pricing.Timezone = strings.TrimSpace(c.Pricing.Timezone)
+	for i := range c.Pricing.Schedule {
+		p := &c.Pricing.Schedule[i]
+		p.Start = strings.TrimSpace(p.Start)
+		p.End = strings.TrimSpace(p.End)
+		for j := range p.Days {
+			p.Days[j] = strings.TrimSpace(p.Days[j])
+		}
+	}
+
+	c.Budget.WebhookURL = strings.TrimSpace(c.Budget.WebhookURL)
+
+	for i := range c.Federation.Agents {
+		a := &c.Federation.Agents[i]
+		a.Name = strings.TrimSpace(a.Name)
+		a.URL = strings.TrimSpace(a.URL)
+	}
+
+	c.History.Path = strings.TrimSpace(c.History.Path)
+	c.State.Path = strings.TrimSpace(c.State.Path)
+
+	c.Restart.Policy = strings.TrimSpace(c.Restart.Policy)
+	for name, restart := range c.Restart.Services {
+		restart.Policy = strings.TrimSpace(restart.Policy)
+		c.Restart.Services[name] = restart
+	}
 }
 
 // Validate checks for configuration errors
@@ -446,7 +1743,8 @@ func (c *Config) Validate(skips ...SkipValidation) error {
 	}
 
 	{ // Validate host settings
-		if _, skip := validationSkipped[SkipHostValidation]; !skip {
+		// there is no sysfs nor procfs on windows and macOS
+		if _, skip := validationSkipped[SkipHostValidation]; !skip && runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
 			if err := canReadDir(c.Host.SysFS); err != nil {
 				errs = append(errs, fmt.Sprintf("invalid sysfs path: %s: %s ", c.Host.SysFS, err.Error()))
 			}
@@ -454,6 +1752,11 @@ func (c *Config) Validate(skips ...SkipValidation) error {
 				errs = append(errs, fmt.Sprintf("invalid procfs path: %s: %s ", c.Host.ProcFS, err.Error()))
 			}
 		}
+		for _, socket := range c.Host.Docker {
+			if _, err := filepath.Match(socket, ""); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid docker socket pattern: %q: %s", socket, err))
+			}
+		}
 	}
 	{ // Web config file
 		if c.Web.Config != "" {
@@ -483,10 +1786,615 @@ func (c *Config) Validate(skips ...SkipValidation) error {
 		if c.Monitor.Staleness < 0 {
 			errs = append(errs, fmt.Sprintf("invalid monitor staleness: %s can't be negative", c.Monitor.Staleness))
 		}
+		if c.Monitor.SampleInterval < 0 {
+			errs = append(errs, fmt.Sprintf("invalid monitor sample interval: %s can't be negative", c.Monitor.SampleInterval))
+		} else if c.Monitor.SampleInterval > 0 && c.Monitor.SampleInterval >= c.Monitor.Interval {
+			errs = append(errs, fmt.Sprintf("invalid monitor sample interval: %s must be less than the monitor interval %s",
+				c.Monitor.SampleInterval, c.Monitor.Interval))
+		}
 
 		if c.Monitor.MinTerminatedEnergyThreshold < 0 {
 			errs = append(errs, fmt.Sprintf("invalid monitor min terminated energy threshold: %d can't be negative", c.Monitor.MinTerminatedEnergyThreshold))
 		}
+		if c.Monitor.MaxTerminatedAge < 0 {
+			errs = append(errs, fmt.Sprintf("invalid monitor max terminated age: %s can't be negative", c.Monitor.MaxTerminatedAge))
+		}
+		switch c.Monitor.Attribution {
+		case AttributionCPUTime, AttributionCPUMemory:
+		case AttributionModelBased:
+			model := c.Monitor.AttributionModel
+			if model.CPUWeight < 0 || model.MemoryWeight < 0 || model.InstructionsWeight < 0 {
+				errs = append(errs, fmt.Sprintf("invalid monitor attribution model: weights can't be negative; got cpu %v, memory %v, instructions %v",
+					model.CPUWeight, model.MemoryWeight, model.InstructionsWeight))
+			} else if model.CPUWeight+model.MemoryWeight+model.InstructionsWeight == 0 {
+				errs = append(errs, "invalid monitor attribution model: at least one weight must be positive")
+			}
+			if model.InstructionsWeight > 0 && !ptr.Deref(c.Monitor.PerfEvents, false) {
+				errs = append(errs, "invalid monitor attribution model: instructions weight requires monitor perf events")
+			}
+		case AttributionExternal:
+			estimator := c.Monitor.AttributionEstimator
+			if u, err := url.Parse(estimator.Endpoint); err != nil || !validEstimatorEndpoint(u) {
+				errs = append(errs, fmt.Sprintf("invalid monitor attribution estimator endpoint: %q; must be an http(s) URL or unix:///path/to/socket", estimator.Endpoint))
+			}
+			if estimator.Timeout <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid monitor attribution estimator timeout: %s; must be positive", estimator.Timeout))
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("invalid monitor attribution: %q; must be one of %s, %s, %s, %s",
+				c.Monitor.Attribution, AttributionCPUTime, AttributionCPUMemory, AttributionModelBased, AttributionExternal))
+		}
+		if coreTypes := c.Monitor.CoreTypes; coreTypes.PerformanceWeight <= 0 || coreTypes.EfficiencyWeight <= 0 {
+			errs = append(errs, fmt.Sprintf("invalid monitor core types: weights must be positive; got performance %v, efficiency %v",
+				coreTypes.PerformanceWeight, coreTypes.EfficiencyWeight))
+		}
+		switch c.Monitor.IdlePolicy {
+		case IdlePolicyExclude, IdlePolicyEven, IdlePolicyRequests:
+		default:
+			errs = append(errs, fmt.Sprintf("invalid monitor idle policy: %q; must be one of %s, %s, %s",
+				c.Monitor.IdlePolicy, IdlePolicyExclude, IdlePolicyEven, IdlePolicyRequests))
+		}
+		switch c.Monitor.CPUAccounting {
+		case CPUAccountingProcFS, CPUAccountingCgroup, CPUAccountingEBPF:
+		default:
+			errs = append(errs, fmt.Sprintf("invalid monitor cpu accounting: %q; must be one of %s, %s, %s",
+				c.Monitor.CPUAccounting, CPUAccountingProcFS, CPUAccountingCgroup, CPUAccountingEBPF))
+		}
+		io := c.Monitor.IO
+		for _, m := range []struct {
+			name  string
+			model IOModel
+		}{{"network", io.Network}, {"storage", io.Storage}} {
+			if m.model.IdleWatts < 0 || m.model.WattsPerGBps < 0 {
+				errs = append(errs, fmt.Sprintf("invalid monitor io %s model: idle watts %v and watts per GB/s %v can't be negative",
+					m.name, m.model.IdleWatts, m.model.WattsPerGBps))
+			} else if m.model != (IOModel{}) && !ptr.Deref(io.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("invalid monitor io %s model: requires monitor io to be enabled", m.name))
+			}
+		}
+		if backoff := c.Monitor.Backoff; ptr.Deref(backoff.Enabled, false) {
+			if backoff.MaxInterval < c.Monitor.Interval {
+				errs = append(errs, fmt.Sprintf("invalid monitor backoff max interval: %s can't be less than the monitor interval %s",
+					backoff.MaxInterval, c.Monitor.Interval))
+			}
+			if backoff.IdleThreshold < 0 {
+				errs = append(errs, fmt.Sprintf("invalid monitor backoff idle threshold: %v can't be negative", backoff.IdleThreshold))
+			}
+		}
+		filter := c.Monitor.Filter
+		for _, comm := range []string{filter.Include.Comm, filter.Exclude.Comm} {
+			if _, err := regexp.Compile(comm); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid monitor filter comm %q: %v", comm, err))
+			}
+		}
+		if filter.MinCPUTime < 0 {
+			errs = append(errs, fmt.Sprintf("invalid monitor filter min cpu time: %s can't be negative", filter.MinCPUTime))
+		}
+		names := map[string]bool{}
+		for i, g := range c.Monitor.Groups {
+			switch {
+			case g.Name == "":
+				errs = append(errs, fmt.Sprintf("invalid monitor group %d: name can't be empty", i))
+			case names[g.Name]:
+				errs = append(errs, fmt.Sprintf("invalid monitor group %q: name is not unique", g.Name))
+			}
+			names[g.Name] = true
+			switch {
+			case g.PID < 0:
+				errs = append(errs, fmt.Sprintf("invalid monitor group %q: pid %d can't be negative", g.Name, g.PID))
+			case (g.PID == 0) == (g.Cgroup == ""):
+				errs = append(errs, fmt.Sprintf("invalid monitor group %q: must set either a pid or a cgroup", g.Name))
+			case g.Cgroup != "" && !strings.HasPrefix(g.Cgroup, "/"):
+				errs = append(errs, fmt.Sprintf("invalid monitor group %q: cgroup %q must be an absolute path", g.Name, g.Cgroup))
+			}
+		}
+		zones := map[string]bool{}
+		for i, cal := range c.Monitor.Calibration {
+			switch {
+			case cal.Zone == "":
+				errs = append(errs, fmt.Sprintf("invalid monitor calibration %d: zone can't be empty", i))
+			case zones[cal.Zone]:
+				errs = append(errs, fmt.Sprintf("invalid monitor calibration of zone %q: zone is not unique", cal.Zone))
+			}
+			zones[cal.Zone] = true
+			if cal.Scale < 0 {
+				errs = append(errs, fmt.Sprintf("invalid monitor calibration of zone %q: scale %v can't be negative", cal.Zone, cal.Scale))
+			}
+		}
+	}
+	{ // RAPL
+		for _, zone := range c.Rapl.ExcludeZones {
+			if slices.ContainsFunc(c.Rapl.Zones, func(z string) bool { return strings.EqualFold(z, zone) }) {
+				errs = append(errs, fmt.Sprintf("invalid rapl zones: %q is both included and excluded", zone))
+			}
+		}
+	}
+	{ // GPU
+		if ptr.Deref(c.GPU.Enabled, false) && c.GPU.NVIDIA.SMIPath == "" {
+			errs = append(errs, fmt.Sprintf("%s can't be empty when %s is true", GPUNVIDIASMIPath, GPUEnabled))
+		}
+		if ptr.Deref(c.GPU.Enabled, false) && c.GPU.Habana.SMIPath == "" {
+			errs = append(errs, fmt.Sprintf("%s can't be empty when %s is true", GPUHabanaSMIPath, GPUEnabled))
+		}
+	}
+	{ // Dev
+		fake := c.Dev.FakeCpuMeter
+		if ptr.Deref(fake.Enabled, false) && fake.ReplayFile != "" {
+			if err := canReadFile(fake.ReplayFile); err != nil {
+				errs = append(errs, fmt.Sprintf("unreadable fake cpu meter replay file: %q", fake.ReplayFile))
+			}
+		}
+	}
+	{ // Hwmon
+		if ptr.Deref(c.Hwmon.Enabled, false) && len(c.Hwmon.Rails) == 0 {
+			errs = append(errs, fmt.Sprintf("%s can't be empty when %s is true", HwmonRails, HwmonEnabled))
+		}
+		for i, rail := range c.Hwmon.Rails {
+			if rail.Zone == "" || rail.Sensor == "" {
+				errs = append(errs, fmt.Sprintf("invalid hwmon rail %d: zone and sensor can't be empty", i))
+			}
+			if rail.Channel < 0 {
+				errs = append(errs, fmt.Sprintf("invalid hwmon rail %d: channel %d can't be negative", i, rail.Channel))
+			}
+		}
+	}
+	{ // Jetson
+		if ptr.Deref(c.Jetson.Enabled, false) && ptr.Deref(c.Hwmon.Enabled, false) {
+			errs = append(errs, fmt.Sprintf("%s and %s can't both be true", JetsonEnabled, HwmonEnabled))
+		}
+	}
+	{ // Powermetrics
+		if ptr.Deref(c.Powermetrics.Enabled, false) {
+			if ptr.Deref(c.Hwmon.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("%s and %s can't both be true", PowermetricsEnabled, HwmonEnabled))
+			}
+			if ptr.Deref(c.Jetson.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("%s and %s can't both be true", PowermetricsEnabled, JetsonEnabled))
+			}
+			if ptr.Deref(c.Guest.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("%s and %s can't both be true", PowermetricsEnabled, GuestEnabled))
+			}
+			if c.Powermetrics.Path == "" {
+				errs = append(errs, fmt.Sprintf("%s can't be empty", PowermetricsPath))
+			}
+		}
+	}
+	{ // Guest
+		if ptr.Deref(c.Guest.Enabled, false) {
+			if ptr.Deref(c.Hwmon.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("%s and %s can't both be true", GuestEnabled, HwmonEnabled))
+			}
+			if ptr.Deref(c.Jetson.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("%s and %s can't both be true", GuestEnabled, JetsonEnabled))
+			}
+			if u, err := url.Parse(c.Guest.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "vsock") || u.Host == "" {
+				errs = append(errs, fmt.Sprintf("invalid guest endpoint: %q; must be an http, https or vsock URL", c.Guest.Endpoint))
+			}
+		}
+	}
+	{ // Redfish
+		if redfish := c.Redfish; ptr.Deref(redfish.Enabled, false) {
+			if u, err := url.Parse(redfish.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = append(errs, fmt.Sprintf("invalid redfish endpoint: %q; must be an http or https URL", redfish.Endpoint))
+			}
+			if redfish.PasswordFile != "" {
+				if err := canReadFile(redfish.PasswordFile); err != nil {
+					errs = append(errs, fmt.Sprintf("unreadable redfish password file: %q", redfish.PasswordFile))
+				}
+			}
+			if redfish.MinInterval < 0 {
+				errs = append(errs, fmt.Sprintf("invalid redfish min interval: %s can't be negative", redfish.MinInterval))
+			}
+			if redfish.Jitter < 0 {
+				errs = append(errs, fmt.Sprintf("invalid redfish jitter: %s can't be negative", redfish.Jitter))
+			}
+			if redfish.PowerField != "" && slices.Contains(strings.Split(redfish.PowerField, "."), "") {
+				errs = append(errs, fmt.Sprintf("invalid redfish power field: %q; must be a dot-separated path", redfish.PowerField))
+			}
+			if redfish.PowerScale <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid redfish power scale: %g; must be positive", redfish.PowerScale))
+			}
+			if redfish.AveragingInterval < 0 {
+				errs = append(errs, fmt.Sprintf("invalid redfish averaging interval: %s can't be negative", redfish.AveragingInterval))
+			}
+		}
+		if events := c.Redfish.Events; ptr.Deref(events.Enabled, false) {
+			if !ptr.Deref(c.Redfish.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("invalid redfish events: requires %s to be true", RedfishEnabled))
+			}
+			if events.Interval <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid redfish events interval: %s; must be positive", events.Interval))
+			}
+			for _, path := range events.LogServices {
+				if !strings.HasPrefix(path, "/redfish/v1/") {
+					errs = append(errs, fmt.Sprintf("invalid redfish log service: %q; must be a path starting with /redfish/v1/", path))
+				}
+			}
+		}
+	}
+	{ // Platform
+		seen := map[string]bool{}
+		for _, source := range c.Platform.Precedence {
+			switch {
+			case source != PlatformSourceRedfish && source != PlatformSourceACPI && source != PlatformSourceBattery &&
+				source != PlatformSourceSmartPlug:
+				errs = append(errs, fmt.Sprintf("invalid platform source: %q; must be one of %s, %s, %s, %s",
+					source, PlatformSourceRedfish, PlatformSourceACPI, PlatformSourceBattery, PlatformSourceSmartPlug))
+			case seen[source]:
+				errs = append(errs, fmt.Sprintf("invalid platform precedence: %s is listed more than once", source))
+			}
+			seen[source] = true
+		}
+		if c.Platform.MaxLatency < 0 {
+			errs = append(errs, fmt.Sprintf("invalid platform max latency: %s can't be negative", c.Platform.MaxLatency))
+		}
+		nodes := map[string]bool{}
+		for i, plug := range c.Platform.SmartPlugs {
+			switch {
+			case plug.Node == "":
+				errs = append(errs, fmt.Sprintf("invalid smart plug %d: node can't be empty", i))
+			case nodes[plug.Node]:
+				errs = append(errs, fmt.Sprintf("invalid smart plug of node %q: node is not unique", plug.Node))
+			}
+			nodes[plug.Node] = true
+			switch plug.Type {
+			case SmartPlugKasa:
+				if plug.Address == "" {
+					errs = append(errs, fmt.Sprintf("invalid smart plug of node %q: address can't be empty", plug.Node))
+				}
+			case SmartPlugTasmota, SmartPlugShelly, SmartPlugShellyGen2:
+				if u, err := url.Parse(plug.Address); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					errs = append(errs, fmt.Sprintf("invalid smart plug of node %q: address %q must be an http or https URL",
+						plug.Node, plug.Address))
+				}
+			default:
+				errs = append(errs, fmt.Sprintf("invalid smart plug of node %q: type %q must be one of %s, %s, %s, %s",
+					plug.Node, plug.Type, SmartPlugTasmota, SmartPlugShelly, SmartPlugShellyGen2, SmartPlugKasa))
+			}
+			if plug.Username != "" && plug.Type != SmartPlugTasmota && plug.Type != SmartPlugShelly {
+				errs = append(errs, fmt.Sprintf("invalid smart plug of node %q: authentication is not supported by %s plugs", plug.Node, plug.Type))
+			}
+			if plug.PasswordFile != "" {
+				if err := canReadFile(plug.PasswordFile); err != nil {
+					errs = append(errs, fmt.Sprintf("unreadable smart plug password file: %q", plug.PasswordFile))
+				}
+			}
+		}
+	}
+	{ // Exporter
+		if c.Exporter.Stdout.Interval <= 0 {
+			errs = append(errs, fmt.Sprintf("invalid stdout exporter interval: %s; must be positive", c.Exporter.Stdout.Interval))
+		}
+		for _, name := range c.Exporter.Prometheus.DebugCollectors {
+			if name != "go" && name != "process" {
+				errs = append(errs, fmt.Sprintf("invalid prometheus debug collector: %q; must be one of go, process", name))
+			}
+		}
+		if c.Exporter.Prometheus.MaxProcesses < 0 {
+			errs = append(errs, fmt.Sprintf("invalid prometheus max processes: %d can't be negative", c.Exporter.Prometheus.MaxProcesses))
+		}
+		if push := c.Exporter.Push; ptr.Deref(push.Enabled, false) {
+			if u, err := url.Parse(push.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = append(errs, fmt.Sprintf("invalid push exporter URL: %q; must be an http or https URL", push.URL))
+			}
+			switch push.Format {
+			case PushFormatPushgateway:
+				if push.Job == "" {
+					errs = append(errs, fmt.Sprintf("invalid push exporter job: can't be empty with the %s format", PushFormatPushgateway))
+				}
+			case PushFormatJSON:
+			default:
+				errs = append(errs, fmt.Sprintf("invalid push exporter format: %q; must be one of %s, %s",
+					push.Format, PushFormatPushgateway, PushFormatJSON))
+			}
+			if push.Interval < 0 {
+				errs = append(errs, fmt.Sprintf("invalid push exporter interval: %s can't be negative", push.Interval))
+			}
+		}
+		if webhook := c.Exporter.Webhook; ptr.Deref(webhook.Enabled, false) {
+			if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = append(errs, fmt.Sprintf("invalid webhook exporter URL: %q; must be an http or https URL", webhook.URL))
+			}
+		}
+		if ptr.Deref(c.Exporter.Live.Enabled, false) && c.Monitor.Interval == 0 {
+			errs = append(errs, "invalid live exporter: requires a positive monitor interval to stream collections")
+		}
+		if textfile := c.Exporter.Textfile; ptr.Deref(textfile.Enabled, false) {
+			// node_exporter only reads files ending with .prom
+			if filepath.Ext(textfile.Path) != ".prom" {
+				errs = append(errs, fmt.Sprintf("invalid textfile exporter path: %q; must be a .prom file", textfile.Path))
+			}
+			if textfile.Interval <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid textfile exporter interval: %s; must be positive", textfile.Interval))
+			}
+		}
+		if mqtt := c.Exporter.MQTT; ptr.Deref(mqtt.Enabled, false) {
+			u, err := url.Parse(mqtt.Broker)
+			switch {
+			case err != nil || u.Hostname() == "":
+				errs = append(errs, fmt.Sprintf("invalid mqtt exporter broker: %q; must be a tcp, mqtt, ssl, tls or mqtts URL", mqtt.Broker))
+			case !slices.Contains([]string{"tcp", "mqtt", "ssl", "tls", "mqtts"}, u.Scheme):
+				errs = append(errs, fmt.Sprintf("invalid mqtt exporter broker: %q; scheme must be one of tcp, mqtt, ssl, tls, mqtts", mqtt.Broker))
+			case u.User != nil:
+				errs = append(errs, "invalid mqtt exporter broker: credentials must be set with username and passwordFile")
+			}
+			if mqtt.Topic == "" || strings.ContainsAny(mqtt.Topic, "+#") {
+				errs = append(errs, fmt.Sprintf("invalid mqtt exporter topic: %q; must be non-empty without wildcards", mqtt.Topic))
+			}
+			if mqtt.QoS < 0 || mqtt.QoS > 2 {
+				errs = append(errs, fmt.Sprintf("invalid mqtt exporter qos: %d; must be 0, 1 or 2", mqtt.QoS))
+			}
+			if mqtt.Interval <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid mqtt exporter interval: %s; must be positive", mqtt.Interval))
+			}
+			if mqtt.PasswordFile != "" {
+				if mqtt.Username == "" {
+					errs = append(errs, "invalid mqtt exporter password file: requires a username")
+				}
+				if err := canReadFile(mqtt.PasswordFile); err != nil {
+					errs = append(errs, fmt.Sprintf("unreadable mqtt exporter password file: %q", mqtt.PasswordFile))
+				}
+			}
+			if (mqtt.TLS.CertFile == "") != (mqtt.TLS.KeyFile == "") {
+				errs = append(errs, "invalid mqtt exporter tls: certFile and keyFile must be set together")
+			}
+			for _, file := range []string{mqtt.TLS.CAFile, mqtt.TLS.CertFile, mqtt.TLS.KeyFile} {
+				if file == "" {
+					continue
+				}
+				if err := canReadFile(file); err != nil {
+					errs = append(errs, fmt.Sprintf("unreadable mqtt exporter tls file: %q", file))
+				}
+			}
+			if ptr.Deref(mqtt.Deltas, false) && mqtt.FullSnapshotEvery < 1 {
+				errs = append(errs, fmt.Sprintf("invalid mqtt exporter full snapshot interval: %d; must be at least 1", mqtt.FullSnapshotEvery))
+			}
+		}
+	}
+	{ // Restart
+		validRestart := func(what, policy string, maxRetries int, backoff, maxBackoff time.Duration) {
+			switch policy {
+			case RestartPolicyNever, RestartPolicyOnFailure:
+			default:
+				errs = append(errs, fmt.Sprintf("invalid %s policy: %q; must be one of %s, %s",
+					what, policy, RestartPolicyNever, RestartPolicyOnFailure))
+			}
+			if maxRetries < 0 {
+				errs = append(errs, fmt.Sprintf("invalid %s max retries: %d can't be negative", what, maxRetries))
+			}
+			if backoff < 0 || maxBackoff < 0 {
+				errs = append(errs, fmt.Sprintf("invalid %s backoff: %s and max backoff %s can't be negative", what, backoff, maxBackoff))
+			}
+		}
+
+		restart := c.Restart
+		validRestart("restart", restart.Policy, restart.MaxRetries, restart.Backoff, restart.MaxBackoff)
+		if restart.MaxBackoff > 0 && restart.MaxBackoff < restart.Backoff {
+			errs = append(errs, fmt.Sprintf("invalid restart max backoff: %s can't be less than the backoff %s",
+				restart.MaxBackoff, restart.Backoff))
+		}
+		for name, svc := range restart.Services {
+			if name == "" {
+				errs = append(errs, "invalid restart service: name can't be empty")
+				continue
+			}
+			validRestart(fmt.Sprintf("restart of service %s", name),
+				cmp.Or(svc.Policy, restart.Policy), ptr.Deref(svc.MaxRetries, restart.MaxRetries), svc.Backoff, svc.MaxBackoff)
+		}
+	}
+	{ // Estimator
+		if ptr.Deref(c.Estimator.Enabled, false) && c.Estimator.ModelFile != "" {
+			if err := canReadFile(c.Estimator.ModelFile); err != nil {
+				errs = append(errs, fmt.Sprintf("unreadable estimator model file: %s", c.Estimator.ModelFile))
+			}
+		}
+		if c.Estimator.ModelURL != "" {
+			if u, err := url.Parse(c.Estimator.ModelURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = append(errs, fmt.Sprintf("invalid estimator model url: %q; must be an http(s) URL", c.Estimator.ModelURL))
+			}
+		}
+		if c.Estimator.Model.Uncertainty < 0 {
+			errs = append(errs, fmt.Sprintf("invalid estimator model uncertainty: %v can't be negative", c.Estimator.Model.Uncertainty))
+		}
+		if c.Estimator.ModelRefreshInterval < 0 {
+			errs = append(errs, fmt.Sprintf("invalid estimator model refresh interval: %s can't be negative", c.Estimator.ModelRefreshInterval))
+		}
+	}
+	{ // Carbon
+		carbon := c.Carbon
+		switch carbon.Provider {
+		case CarbonProviderNone:
+		case CarbonProviderStatic:
+			if carbon.Static.Intensity < 0 {
+				errs = append(errs, fmt.Sprintf("invalid carbon static intensity: %v can't be negative", carbon.Static.Intensity))
+			}
+		case CarbonProviderElectricityMaps:
+			if carbon.ElectricityMaps.Zone == "" {
+				errs = append(errs, fmt.Sprintf("%s can't be empty when %s is %s",
+					CarbonElectricityMapsZone, CarbonProvider, CarbonProviderElectricityMaps))
+			}
+			if err := canReadFile(carbon.ElectricityMaps.TokenFile); err != nil {
+				errs = append(errs, fmt.Sprintf("unreadable carbon electricity maps token file: %q", carbon.ElectricityMaps.TokenFile))
+			}
+		case CarbonProviderWattTime:
+			if carbon.WattTime.Region == "" {
+				errs = append(errs, fmt.Sprintf("%s can't be empty when %s is %s",
+					CarbonWattTimeRegion, CarbonProvider, CarbonProviderWattTime))
+			}
+			if carbon.WattTime.Username == "" {
+				errs = append(errs, fmt.Sprintf("%s can't be empty when %s is %s",
+					CarbonWattTimeUsername, CarbonProvider, CarbonProviderWattTime))
+			}
+			if err := canReadFile(carbon.WattTime.PasswordFile); err != nil {
+				errs = append(errs, fmt.Sprintf("unreadable carbon watttime password file: %q", carbon.WattTime.PasswordFile))
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("invalid carbon provider: %q; must be one of %s, %s, %s, %s",
+				carbon.Provider, CarbonProviderNone, CarbonProviderStatic, CarbonProviderElectricityMaps, CarbonProviderWattTime))
+		}
+		isAPI := carbon.Provider == CarbonProviderElectricityMaps || carbon.Provider == CarbonProviderWattTime
+		if isAPI && carbon.RefreshInterval <= 0 {
+			errs = append(errs, fmt.Sprintf("invalid carbon refresh interval: %s must be positive", carbon.RefreshInterval))
+		}
+	}
+	{ // Pricing
+		if c.Pricing.Price < 0 {
+			errs = append(errs, fmt.Sprintf("invalid price: %v can't be negative", c.Pricing.Price))
+		}
+		if _, err := time.LoadLocation(c.Pricing.Timezone); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid pricing timezone: %q; must be an IANA time zone, e.g. Europe/Berlin", c.Pricing.Timezone))
+		}
+		for i, p := range c.Pricing.Schedule {
+			start, startErr := pricing.ParseTimeOfDay(p.Start)
+			if startErr != nil {
+				errs = append(errs, fmt.Sprintf("invalid start of pricing schedule %d: %s", i, startErr))
+			}
+			end, endErr := pricing.ParseTimeOfDay(p.End)
+			if endErr != nil {
+				errs = append(errs, fmt.Sprintf("invalid end of pricing schedule %d: %s", i, endErr))
+			}
+			if startErr == nil && endErr == nil && start == end {
+				errs = append(errs, fmt.Sprintf("invalid pricing schedule %d: start and end can't be the same", i))
+			}
+			for _, d := range p.Days {
+				if _, err := pricing.ParseWeekday(d); err != nil {
+					errs = append(errs, fmt.Sprintf("invalid days of pricing schedule %d: %s", i, err))
+				}
+			}
+			if p.Price < 0 {
+				errs = append(errs, fmt.Sprintf("invalid price of pricing schedule %d: %v can't be negative", i, p.Price))
+			}
+		}
+	}
+	{ // Budget
+		if c.Budget.Node < 0 {
+			errs = append(errs, fmt.Sprintf("invalid node budget: %d can't be negative", c.Budget.Node))
+		}
+		for ns, budget := range c.Budget.Namespaces {
+			if strings.TrimSpace(ns) == "" {
+				errs = append(errs, "budget namespace can't be empty")
+			}
+			if budget < 0 {
+				errs = append(errs, fmt.Sprintf("invalid budget of namespace %q: %d can't be negative", ns, budget))
+			}
+		}
+		if c.Budget.WebhookURL != "" {
+			if u, err := url.Parse(c.Budget.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = append(errs, fmt.Sprintf("invalid budget webhook URL: %q; must be an http or https URL", c.Budget.WebhookURL))
+			}
+		}
+	}
+	{ // PowerCap
+		if ptr.Deref(c.PowerCap.Enabled, false) {
+			switch c.PowerCap.Limiter {
+			case PowerCapLimiterRAPL:
+			case PowerCapLimiterRedfish:
+				if !ptr.Deref(c.Redfish.Enabled, false) {
+					errs = append(errs, fmt.Sprintf("invalid power cap limiter: %s requires %s to be true", PowerCapLimiterRedfish, RedfishEnabled))
+				}
+			default:
+				errs = append(errs, fmt.Sprintf("invalid power cap limiter: %q; must be one of %s, %s",
+					c.PowerCap.Limiter, PowerCapLimiterRAPL, PowerCapLimiterRedfish))
+			}
+			if c.PowerCap.Watts < 0 {
+				errs = append(errs, fmt.Sprintf("invalid power cap: %v watts can't be negative", c.PowerCap.Watts))
+			}
+		}
+	}
+	{ // Headroom
+		if ptr.Deref(c.Headroom.Enabled, false) {
+			if c.Headroom.Window <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid headroom window: %s must be positive", c.Headroom.Window))
+			}
+			if c.Headroom.CapWatts < 0 {
+				errs = append(errs, fmt.Sprintf("invalid headroom cap: %v watts can't be negative", c.Headroom.CapWatts))
+			}
+		}
+	}
+	{ // Anomaly
+		if ptr.Deref(c.Anomaly.Enabled, false) {
+			if c.Anomaly.Threshold <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid anomaly threshold: %v must be positive", c.Anomaly.Threshold))
+			}
+			if c.Anomaly.Alpha <= 0 || c.Anomaly.Alpha > 1 {
+				errs = append(errs, fmt.Sprintf("invalid anomaly alpha: %v must be in (0, 1]", c.Anomaly.Alpha))
+			}
+			if c.Anomaly.Workloads < 0 {
+				errs = append(errs, fmt.Sprintf("invalid anomaly workloads: %d can't be negative", c.Anomaly.Workloads))
+			}
+		}
+	}
+	{ // Rightsizing
+		if ptr.Deref(c.Rightsizing.Enabled, false) {
+			if c.Rightsizing.Interval <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid rightsizing interval: %s must be positive", c.Rightsizing.Interval))
+			}
+			if !ptr.Deref(c.Kube.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("invalid rightsizing: requires %s to be true", KubernetesFlag))
+			}
+		}
+	}
+	{ // Chargeback
+		if ptr.Deref(c.Chargeback.Enabled, false) {
+			if c.Chargeback.Interval <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid chargeback interval: %s must be positive", c.Chargeback.Interval))
+			}
+			if c.Chargeback.TopPods < 0 {
+				errs = append(errs, fmt.Sprintf("invalid chargeback top pods: %d can't be negative", c.Chargeback.TopPods))
+			}
+			if !ptr.Deref(c.Kube.Enabled, false) {
+				errs = append(errs, fmt.Sprintf("invalid chargeback: requires %s to be true", KubernetesFlag))
+			}
+		}
+	}
+	{ // Federation
+		if ptr.Deref(c.Federation.Enabled, false) {
+			if c.Federation.Interval <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid federation interval: %s must be positive", c.Federation.Interval))
+			}
+			if c.Federation.Timeout <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid federation timeout: %s must be positive", c.Federation.Timeout))
+			}
+			if len(c.Federation.Agents) == 0 {
+				errs = append(errs, "invalid federation: no agents are set")
+			}
+			for i, a := range c.Federation.Agents {
+				if u, err := url.Parse(a.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					errs = append(errs, fmt.Sprintf("invalid url of federation agent %d: %q; must be an http or https URL", i, a.URL))
+				}
+			}
+		}
+	}
+	{ // History
+		if ptr.Deref(c.History.Enabled, false) {
+			// hourly rollups are computed from rows and daily rollups from hourly rollups
+			if c.History.Retention < time.Hour {
+				errs = append(errs, fmt.Sprintf("invalid history retention: %s; must be at least 1h", c.History.Retention))
+			}
+			if c.History.Rollups.HourlyRetention < 24*time.Hour {
+				errs = append(errs, fmt.Sprintf("invalid history hourly rollup retention: %s; must be at least 24h", c.History.Rollups.HourlyRetention))
+			}
+			if c.History.Rollups.DailyRetention <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid history daily rollup retention: %s; must be positive", c.History.Rollups.DailyRetention))
+			}
+			if c.History.Path != "" {
+				if info, err := os.Stat(filepath.Dir(c.History.Path)); err != nil || !info.IsDir() {
+					errs = append(errs, fmt.Sprintf("invalid history path: %q; its directory must exist", c.History.Path))
+				}
+			}
+		}
+	}
+	{ // State
+		if ptr.Deref(c.State.Enabled, false) {
+			if c.State.Path == "" {
+				errs = append(errs, fmt.Sprintf("invalid state path: %s can't be empty", StatePath))
+			} else if info, err := os.Stat(filepath.Dir(c.State.Path)); err != nil || !info.IsDir() {
+				errs = append(errs, fmt.Sprintf("invalid state path: %q; its directory must exist", c.State.Path))
+			}
+			if c.State.Interval <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid state interval: %s; must be positive", c.State.Interval))
+			}
+		}
 	}
 	{ // Kubernetes
 		if ptr.Deref(c.Kube.Enabled, false) {
@@ -546,11 +2454,25 @@ func canReadFile(path string) error {
 	return nil
 }
 
+// validEstimatorEndpoint returns true if u is an http(s) URL or a unix socket
+func validEstimatorEndpoint(u *url.URL) bool {
+	switch u.Scheme {
+	case "http", "https":
+		return u.Host != ""
+	case "unix":
+		return u.Path != ""
+	}
+	return false
+}
+
 func validateListenAddress(addr string) error {
 	if addr == "" {
 		return fmt.Errorf("address cannot be empty")
 	}
 
+	// vsock://:<port> listens on vsock, e.g. for Kepler running in VMs
+	addr = strings.TrimPrefix(addr, "vsock://")
+
 	// Use Go's standard library to parse host:port
 	_, port, err := net.SplitHostPort(addr)
 	if err != nil {
@@ -596,16 +2518,177 @@ func (c *Config) manualString() string {
 		{LogFormatFlag, c.Log.Format},
 		{HostSysFSFlag, c.Host.SysFS},
 		{HostProcFSFlag, c.Host.ProcFS},
+		{HostLibvirt, c.Host.Libvirt},
+		{HostCRI, c.Host.CRI},
+		{HostDocker, strings.Join(c.Host.Docker, ", ")},
 		{MonitorIntervalFlag, c.Monitor.Interval.String()},
 		{MonitorStaleness, c.Monitor.Staleness.String()},
+		{MonitorSampleInterval, c.Monitor.SampleInterval.String()},
 		{MonitorMaxTerminatedFlag, fmt.Sprintf("%d", c.Monitor.MaxTerminated)},
+		{MonitorMaxTerminatedAge, c.Monitor.MaxTerminatedAge.String()},
+		{MonitorAttribution, c.Monitor.Attribution},
+		{MonitorAttributionModelCPUWeight, fmt.Sprintf("%v", c.Monitor.AttributionModel.CPUWeight)},
+		{MonitorAttributionModelMemoryWeight, fmt.Sprintf("%v", c.Monitor.AttributionModel.MemoryWeight)},
+		{MonitorAttributionModelInstructionsWeight, fmt.Sprintf("%v", c.Monitor.AttributionModel.InstructionsWeight)},
+		{MonitorAttributionEstimatorEndpoint, c.Monitor.AttributionEstimator.Endpoint},
+		{MonitorAttributionEstimatorTimeout, c.Monitor.AttributionEstimator.Timeout.String()},
+		{MonitorCoreTypesPerformanceWeight, fmt.Sprintf("%v", c.Monitor.CoreTypes.PerformanceWeight)},
+		{MonitorCoreTypesEfficiencyWeight, fmt.Sprintf("%v", c.Monitor.CoreTypes.EfficiencyWeight)},
+		{MonitorIdlePolicy, c.Monitor.IdlePolicy},
+		{MonitorCPUAccounting, c.Monitor.CPUAccounting},
+		{MonitorProcessEvents, fmt.Sprintf("%v", ptr.Deref(c.Monitor.ProcessEvents, false))},
+		{MonitorPerfEvents, fmt.Sprintf("%v", ptr.Deref(c.Monitor.PerfEvents, false))},
+		{MonitorSystemdUnits, fmt.Sprintf("%v", ptr.Deref(c.Monitor.SystemdUnits, false))},
+		{MonitorProcessMetadata, fmt.Sprintf("%v", ptr.Deref(c.Monitor.ProcessMetadata, false))},
+		{MonitorAggregates, fmt.Sprintf("%v", ptr.Deref(c.Monitor.Aggregates, false))},
+		{MonitorOtherWorkloads, fmt.Sprintf("%v", ptr.Deref(c.Monitor.OtherWorkloads, false))},
+		{MonitorIOEnabled, fmt.Sprintf("%v", ptr.Deref(c.Monitor.IO.Enabled, false))},
+		{MonitorIONetworkIdleWatts, fmt.Sprintf("%v", c.Monitor.IO.Network.IdleWatts)},
+		{MonitorIONetworkWattsPerGBps, fmt.Sprintf("%v", c.Monitor.IO.Network.WattsPerGBps)},
+		{MonitorIOStorageIdleWatts, fmt.Sprintf("%v", c.Monitor.IO.Storage.IdleWatts)},
+		{MonitorIOStorageWattsPerGBps, fmt.Sprintf("%v", c.Monitor.IO.Storage.WattsPerGBps)},
+		{MonitorBackoffEnabled, fmt.Sprintf("%v", ptr.Deref(c.Monitor.Backoff.Enabled, false))},
+		{MonitorBackoffMaxInterval, c.Monitor.Backoff.MaxInterval.String()},
+		{MonitorBackoffIdleThreshold, fmt.Sprintf("%v", c.Monitor.Backoff.IdleThreshold)},
+		{MonitorFilterIncludeComm, c.Monitor.Filter.Include.Comm},
+		{MonitorFilterIncludeCgroups, strings.Join(c.Monitor.Filter.Include.Cgroups, ", ")},
+		{MonitorFilterIncludeNamespaces, strings.Join(c.Monitor.Filter.Include.Namespaces, ", ")},
+		{MonitorFilterExcludeComm, c.Monitor.Filter.Exclude.Comm},
+		{MonitorFilterExcludeCgroups, strings.Join(c.Monitor.Filter.Exclude.Cgroups, ", ")},
+		{MonitorFilterExcludeNamespaces, strings.Join(c.Monitor.Filter.Exclude.Namespaces, ", ")},
+		{MonitorFilterMinCPUTime, c.Monitor.Filter.MinCPUTime.String()},
+		{MonitorGroups, fmt.Sprintf("%v", c.Monitor.Groups)},
+		{MonitorCalibration, fmt.Sprintf("%v", c.Monitor.Calibration)},
 		{RaplZones, strings.Join(c.Rapl.Zones, ", ")},
+		{RaplExcludeZones, strings.Join(c.Rapl.ExcludeZones, ", ")},
+		{RaplPerSocket, fmt.Sprintf("%v", ptr.Deref(c.Rapl.PerSocket, false))},
+		{GPUEnabled, fmt.Sprintf("%v", c.GPU.Enabled)},
+		{GPUNVIDIASMIPath, c.GPU.NVIDIA.SMIPath},
+		{GPUNVIDIADevicePluginCheckpoint, c.GPU.NVIDIA.DevicePluginCheckpoint},
+		{GPUHabanaSMIPath, c.GPU.Habana.SMIPath},
+		{HwmonEnabled, fmt.Sprintf("%v", ptr.Deref(c.Hwmon.Enabled, false))},
+		{HwmonRails, fmt.Sprintf("%v", c.Hwmon.Rails)},
+		{JetsonEnabled, fmt.Sprintf("%v", ptr.Deref(c.Jetson.Enabled, false))},
+		{JetsonTegrastatsPath, c.Jetson.TegrastatsPath},
+		{PowermetricsEnabled, fmt.Sprintf("%v", ptr.Deref(c.Powermetrics.Enabled, false))},
+		{PowermetricsPath, c.Powermetrics.Path},
+		{GuestEnabled, fmt.Sprintf("%v", ptr.Deref(c.Guest.Enabled, false))},
+		{GuestEndpoint, c.Guest.Endpoint},
+		{GuestVMID, c.Guest.VMID},
+		{RedfishEnabled, fmt.Sprintf("%v", ptr.Deref(c.Redfish.Enabled, false))},
+		{RedfishEndpoint, c.Redfish.Endpoint},
+		{RedfishChassis, c.Redfish.Chassis},
+		{RedfishUsername, c.Redfish.Username},
+		{RedfishPasswordFile, c.Redfish.PasswordFile},
+		{RedfishInsecureSkipVerify, fmt.Sprintf("%v", ptr.Deref(c.Redfish.InsecureSkipVerify, false))},
+		{RedfishMinInterval, c.Redfish.MinInterval.String()},
+		{RedfishJitter, c.Redfish.Jitter.String()},
+		{RedfishPowerField, c.Redfish.PowerField},
+		{RedfishPowerScale, fmt.Sprintf("%g", c.Redfish.PowerScale)},
+		{RedfishAveragingInterval, c.Redfish.AveragingInterval.String()},
+		{RedfishEventsEnabled, fmt.Sprintf("%v", ptr.Deref(c.Redfish.Events.Enabled, false))},
+		{RedfishEventsInterval, c.Redfish.Events.Interval.String()},
+		{RedfishEventsLogServices, strings.Join(c.Redfish.Events.LogServices, ", ")},
+		{PlatformACPI, fmt.Sprintf("%v", ptr.Deref(c.Platform.ACPI, false))},
+		{PlatformBattery, fmt.Sprintf("%v", ptr.Deref(c.Platform.Battery, false))},
+		{PlatformPrecedence, strings.Join(c.Platform.Precedence, ", ")},
+		{PlatformMaxLatency, c.Platform.MaxLatency.String()},
+		{PlatformSmartPlugs, fmt.Sprintf("%v", c.Platform.SmartPlugs)},
+		{EstimatorEnabled, fmt.Sprintf("%v", ptr.Deref(c.Estimator.Enabled, false))},
+		{EstimatorModelFile, c.Estimator.ModelFile},
+		{EstimatorModelURL, c.Estimator.ModelURL},
+		{EstimatorModelRefresh, c.Estimator.ModelRefreshInterval.String()},
+		{EstimatorModelIntercept, fmt.Sprintf("%v", c.Estimator.Model.Intercept)},
+		{EstimatorModelUtilization, fmt.Sprintf("%v", c.Estimator.Model.Utilization)},
+		{EstimatorModelFrequency, fmt.Sprintf("%v", c.Estimator.Model.Frequency)},
+		{EstimatorModelDeepIdle, fmt.Sprintf("%v", c.Estimator.Model.DeepIdle)},
+		{EstimatorModelUncertainty, fmt.Sprintf("%v", c.Estimator.Model.Uncertainty)},
+		{CarbonProvider, c.Carbon.Provider},
+		{CarbonRefreshInterval, c.Carbon.RefreshInterval.String()},
+		{CarbonStaticIntensity, fmt.Sprintf("%v", c.Carbon.Static.Intensity)},
+		{CarbonElectricityMapsZone, c.Carbon.ElectricityMaps.Zone},
+		{CarbonElectricityMapsTokenFile, c.Carbon.ElectricityMaps.TokenFile},
+		{CarbonWattTimeRegion, c.Carbon.WattTime.Region},
+		{CarbonWattTimeUsername, c.Carbon.WattTime.Username},
+		{CarbonWattTimePasswordFile, c.Carbon.WattTime.PasswordFile},
+		{PricingPrice, fmt.Sprintf("%v", c.Pricing.Price)},
+		{PricingTimezone, c.Pricing.Timezone},
+		{PricingSchedule, fmt.Sprintf("%v", c.Pricing.Schedule)},
+		{BudgetNode, fmt.Sprintf("%d", c.Budget.Node)},
+		{BudgetNamespaces, fmt.Sprintf("%v", c.Budget.Namespaces)},
+		{BudgetWebhookURL, c.Budget.WebhookURL},
+		{PowerCapEnabled, fmt.Sprintf("%v", ptr.Deref(c.PowerCap.Enabled, false))},
+		{PowerCapLimiter, c.PowerCap.Limiter},
+		{PowerCapWatts, fmt.Sprintf("%v", c.PowerCap.Watts)},
+		{PowerCapDryRun, fmt.Sprintf("%v", ptr.Deref(c.PowerCap.DryRun, false))},
+		{PowerCapAllowAPI, fmt.Sprintf("%v", ptr.Deref(c.PowerCap.AllowAPI, false))},
+		{HeadroomEnabled, fmt.Sprintf("%v", ptr.Deref(c.Headroom.Enabled, false))},
+		{HeadroomWindow, c.Headroom.Window.String()},
+		{HeadroomCapWatts, fmt.Sprintf("%v", c.Headroom.CapWatts)},
+		{AnomalyEnabled, fmt.Sprintf("%v", ptr.Deref(c.Anomaly.Enabled, false))},
+		{AnomalyThreshold, fmt.Sprintf("%v", c.Anomaly.Threshold)},
+		{AnomalyAlpha, fmt.Sprintf("%v", c.Anomaly.Alpha)},
+		{AnomalyWorkloads, fmt.Sprintf("%d", c.Anomaly.Workloads)},
+		{RightsizingEnabled, fmt.Sprintf("%v", ptr.Deref(c.Rightsizing.Enabled, false))},
+		{RightsizingInterval, c.Rightsizing.Interval.String()},
+		{ChargebackEnabled, fmt.Sprintf("%v", ptr.Deref(c.Chargeback.Enabled, false))},
+		{ChargebackInterval, c.Chargeback.Interval.String()},
+		{ChargebackTopPods, fmt.Sprintf("%d", c.Chargeback.TopPods)},
+		{FederationEnabled, fmt.Sprintf("%v", ptr.Deref(c.Federation.Enabled, false))},
+		{FederationInterval, c.Federation.Interval.String()},
+		{FederationTimeout, c.Federation.Timeout.String()},
+		{FederationAgents, fmt.Sprintf("%v", c.Federation.Agents)},
+		{HistoryEnabled, fmt.Sprintf("%v", ptr.Deref(c.History.Enabled, false))},
+		{HistoryPath, c.History.Path},
+		{HistoryRetention, c.History.Retention.String()},
+		{HistoryMetricsLevel, c.History.MetricsLevel.String()},
+		{HistoryHourlyRetention, c.History.Rollups.HourlyRetention.String()},
+		{HistoryDailyRetention, c.History.Rollups.DailyRetention.String()},
+		{StateEnabled, fmt.Sprintf("%v", ptr.Deref(c.State.Enabled, false))},
+		{StatePath, c.State.Path},
+		{StateInterval, c.State.Interval.String()},
 		{ExporterStdoutEnabledFlag, fmt.Sprintf("%v", c.Exporter.Stdout.Enabled)},
+		{ExporterStdoutInterval, c.Exporter.Stdout.Interval.String()},
 		{ExporterPrometheusEnabledFlag, fmt.Sprintf("%v", c.Exporter.Prometheus.Enabled)},
 		{ExporterPrometheusDebugCollectors, strings.Join(c.Exporter.Prometheus.DebugCollectors, ", ")},
 		{ExporterPrometheusMetricsFlag, c.Exporter.Prometheus.MetricsLevel.String()},
+		{ExporterPrometheusMaxProcesses, fmt.Sprintf("%d", c.Exporter.Prometheus.MaxProcesses)},
+		{ExporterVMEnabledFlag, fmt.Sprintf("%v", ptr.Deref(c.Exporter.VM.Enabled, false))},
+		{ExporterPushEnabledFlag, fmt.Sprintf("%v", ptr.Deref(c.Exporter.Push.Enabled, false))},
+		{ExporterPushURLFlag, c.Exporter.Push.URL},
+		{ExporterPushFormat, c.Exporter.Push.Format},
+		{ExporterPushJob, c.Exporter.Push.Job},
+		{ExporterPushInterval, c.Exporter.Push.Interval.String()},
+		{ExporterWebhookEnabledFlag, fmt.Sprintf("%v", ptr.Deref(c.Exporter.Webhook.Enabled, false))},
+		{ExporterWebhookURLFlag, c.Exporter.Webhook.URL},
+		{ExporterLiveEnabledFlag, fmt.Sprintf("%v", ptr.Deref(c.Exporter.Live.Enabled, false))},
+		{ExporterTextfileEnabledFlag, fmt.Sprintf("%v", ptr.Deref(c.Exporter.Textfile.Enabled, false))},
+		{ExporterTextfilePathFlag, c.Exporter.Textfile.Path},
+		{ExporterTextfileInterval, c.Exporter.Textfile.Interval.String()},
+		{ExporterMQTTEnabledFlag, fmt.Sprintf("%v", ptr.Deref(c.Exporter.MQTT.Enabled, false))},
+		{ExporterMQTTBrokerFlag, c.Exporter.MQTT.Broker},
+		{ExporterMQTTTopic, c.Exporter.MQTT.Topic},
+		{ExporterMQTTQoS, fmt.Sprintf("%d", c.Exporter.MQTT.QoS)},
+		{ExporterMQTTRetain, fmt.Sprintf("%v", ptr.Deref(c.Exporter.MQTT.Retain, false))},
+		{ExporterMQTTInterval, c.Exporter.MQTT.Interval.String()},
+		{ExporterMQTTClientID, c.Exporter.MQTT.ClientID},
+		{ExporterMQTTUsername, c.Exporter.MQTT.Username},
+		{ExporterMQTTPasswordFile, c.Exporter.MQTT.PasswordFile},
+		{ExporterMQTTTLSCAFile, c.Exporter.MQTT.TLS.CAFile},
+		{ExporterMQTTTLSCertFile, c.Exporter.MQTT.TLS.CertFile},
+		{ExporterMQTTTLSKeyFile, c.Exporter.MQTT.TLS.KeyFile},
+		{ExporterMQTTTLSInsecureSkipVerify, fmt.Sprintf("%v", ptr.Deref(c.Exporter.MQTT.TLS.InsecureSkipVerify, false))},
+		{ExporterMQTTDeltas, fmt.Sprintf("%v", ptr.Deref(c.Exporter.MQTT.Deltas, false))},
+		{ExporterMQTTFullSnapshotEvery, fmt.Sprintf("%d", c.Exporter.MQTT.FullSnapshotEvery)},
 		{pprofEnabledFlag, fmt.Sprintf("%v", c.Debug.Pprof.Enabled)},
+		{WebEnableLifecycleFlag, fmt.Sprintf("%v", ptr.Deref(c.Web.EnableLifecycle, false))},
+		{RestartPolicy, c.Restart.Policy},
+		{RestartMaxRetries, fmt.Sprintf("%d", c.Restart.MaxRetries)},
+		{RestartBackoff, c.Restart.Backoff.String()},
+		{RestartMaxBackoff, c.Restart.MaxBackoff.String()},
+		{RestartServices, strings.Join(slices.Sorted(maps.Keys(c.Restart.Services)), ", ")},
 		{KubeConfigFlag, fmt.Sprintf("%v", c.Kube.Config)},
+		{KubePodMetadata, fmt.Sprintf("%v", ptr.Deref(c.Kube.PodMetadata, false))},
 	}
 	sb := strings.Builder{}
 
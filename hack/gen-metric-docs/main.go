@@ -41,6 +41,10 @@ func (m *MockMonitor) Snapshot() (*monitor.Snapshot, error) {
 	return &monitor.Snapshot{}, nil
 }
 
+func (m *MockMonitor) LatestSnapshot() (*monitor.Snapshot, error) {
+	return m.Snapshot()
+}
+
 // ZoneNames implements monitor.PowerDataProvider interface
 func (m *MockMonitor) ZoneNames() []string {
 	return []string{"package-0"}
@@ -153,6 +157,9 @@ func generateMarkdown(metrics []MetricInfo) string {
 	processMetrics := []MetricInfo{}
 	vmMetrics := []MetricInfo{}
 	podMetrics := []MetricInfo{}
+	systemdUnitMetrics := []MetricInfo{}
+	groupMetrics := []MetricInfo{}
+	aggregateMetrics := []MetricInfo{}
 	otherMetrics := []MetricInfo{}
 
 	for _, metric := range metrics {
@@ -167,6 +174,12 @@ func generateMarkdown(metrics []MetricInfo) string {
 			vmMetrics = append(vmMetrics, metric)
 		case strings.HasPrefix(metric.Name, "kepler_pod_"):
 			podMetrics = append(podMetrics, metric)
+		case strings.HasPrefix(metric.Name, "kepler_systemd_unit_"):
+			systemdUnitMetrics = append(systemdUnitMetrics, metric)
+		case strings.HasPrefix(metric.Name, "kepler_group_"):
+			groupMetrics = append(groupMetrics, metric)
+		case strings.HasPrefix(metric.Name, "kepler_aggregate_"):
+			aggregateMetrics = append(aggregateMetrics, metric)
 		default:
 			otherMetrics = append(otherMetrics, metric)
 		}
@@ -197,6 +210,21 @@ func generateMarkdown(metrics []MetricInfo) string {
 		md.WriteString("These metrics provide energy and power information for pods.\n\n")
 		writeMetricsSection(&md, podMetrics)
 	}
+	if len(systemdUnitMetrics) > 0 {
+		md.WriteString("### Systemd Unit Metrics\n\n")
+		md.WriteString("These metrics provide energy and power information for systemd services and scopes.\n\n")
+		writeMetricsSection(&md, systemdUnitMetrics)
+	}
+	if len(groupMetrics) > 0 {
+		md.WriteString("### Group Metrics\n\n")
+		md.WriteString("These metrics provide energy and power information for groups of processes, such as the descendants of a process or the processes of a cgroup.\n\n")
+		writeMetricsSection(&md, groupMetrics)
+	}
+	if len(aggregateMetrics) > 0 {
+		md.WriteString("### Aggregate Metrics\n\n")
+		md.WriteString("These metrics provide energy and power information for kernel threads and CPU time not used by any process.\n\n")
+		writeMetricsSection(&md, aggregateMetrics)
+	}
 	if len(otherMetrics) > 0 {
 		md.WriteString("### Other Metrics\n\n")
 		md.WriteString("Additional metrics provided by Kepler.\n\n")
@@ -261,7 +289,22 @@ func main() {
 	fmt.Println("Creating collectors...")
 	// Create a logger for the collectors
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	powerCollector := collector.NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelAll)
+	powerCollector := collector.NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelAll,
+		collector.WithCarbonMetrics(true),
+		collector.WithCostMetrics(true),
+		collector.WithPowerRangeMetrics(true),
+		collector.WithCalibrationMetrics(true),
+		collector.WithHWCounterMetrics(true),
+		collector.WithIOMetrics(true),
+		collector.WithProcessInfoMetrics(true),
+		collector.WithContainerInfoMetrics(true),
+		collector.WithPodInfoMetrics(true),
+		collector.WithSystemdUnitMetrics(true),
+		collector.WithGroupMetrics(true),
+		collector.WithAggregateMetrics(true),
+		collector.WithBudgetMetrics(true),
+		collector.WithZoneSources(func(monitor.EnergyZone) string { return "rapl" }),
+		collector.WithGPUZones(func(monitor.EnergyZone) bool { return false }))
 	fmt.Println("Created power collector")
 	buildInfoCollector := collector.NewKeplerBuildInfoCollector()
 	fmt.Println("Created build info collector")
@@ -272,6 +315,18 @@ func main() {
 		fmt.Println("Created CPU info collector")
 	}
 
+	cpuStateCollector := collector.NewCPUStateCollector("/sys", "test-node")
+	fmt.Println("Created CPU state collector")
+
+	platformSourceCollector := collector.NewPlatformSourceCollector(nil, "test-node")
+	fmt.Println("Created platform source collector")
+
+	anomalyCollector := collector.NewAnomalyCollector(nil, "test-node")
+	fmt.Println("Created anomaly collector")
+
+	powerEventCollector := collector.NewPowerEventCollector(nil, "test-node")
+	fmt.Println("Created power event collector")
+
 	// Extract metrics information from collectors
 	var allMetrics []MetricInfo
 
@@ -304,6 +359,42 @@ func main() {
 		allMetrics = append(allMetrics, cpuInfoMetrics...)
 	}
 
+	fmt.Println("Extracting metrics from CPU state collector...")
+	cpuStateMetrics, err := extractMetricsInfo(cpuStateCollector)
+	if err != nil {
+		fmt.Printf("Failed to extract CPU state metrics: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Extracted %d CPU state metrics\n", len(cpuStateMetrics))
+	allMetrics = append(allMetrics, cpuStateMetrics...)
+
+	fmt.Println("Extracting metrics from platform source collector...")
+	platformSourceMetrics, err := extractMetricsInfo(platformSourceCollector)
+	if err != nil {
+		fmt.Printf("Failed to extract platform source metrics: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Extracted %d platform source metrics\n", len(platformSourceMetrics))
+	allMetrics = append(allMetrics, platformSourceMetrics...)
+
+	fmt.Println("Extracting metrics from anomaly collector...")
+	anomalyMetrics, err := extractMetricsInfo(anomalyCollector)
+	if err != nil {
+		fmt.Printf("Failed to extract anomaly metrics: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Extracted %d anomaly metrics\n", len(anomalyMetrics))
+	allMetrics = append(allMetrics, anomalyMetrics...)
+
+	fmt.Println("Extracting metrics from power event collector...")
+	powerEventMetrics, err := extractMetricsInfo(powerEventCollector)
+	if err != nil {
+		fmt.Printf("Failed to extract power event metrics: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Extracted %d power event metrics\n", len(powerEventMetrics))
+	allMetrics = append(allMetrics, powerEventMetrics...)
+
 	fmt.Printf("Total metrics extracted: %d\n", len(allMetrics))
 
 	// Generate Markdown
@@ -0,0 +1,541 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package anomaly flags abnormal jumps in the power of the node and of the
+// workloads drawing the most power. The power of each is compared against an
+// exponentially weighted moving average (EWMA) of its mean and variance, and
+// samples whose z-score exceeds a threshold are reported as anomalies over
+// REST, as an MCP tool and as metrics.
+package anomaly
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+// Endpoint is the endpoint the recent anomalies are served at
+const Endpoint = "/anomalies"
+
+// ToolName is the name of the MCP tool returning the recent anomalies
+const ToolName = "get_power_anomalies"
+
+const (
+	// warmup is the number of samples of a series before its anomalies are
+	// flagged, so that the mean and variance have settled
+	warmup = 12
+
+	// minDeviationWatts is the least deviation from the mean flagged, so that
+	// the jitter of series whose power barely varies isn't flagged
+	minDeviationWatts = 1.0
+
+	// minStdWatts is the least standard deviation of the power of a series, so
+	// that jumps of series whose power was flat have a finite z-score
+	minStdWatts = 0.1
+
+	// maxAnomalies is the number of most recent anomalies kept
+	maxAnomalies = 100
+)
+
+// Kinds of series
+const (
+	KindNode      = "node"
+	KindContainer = "container"
+	KindVM        = "vm"
+	KindPod       = "pod"
+)
+
+type (
+	Monitor      = monitor.Service
+	ToolRegistry = server.ToolRegistry
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// Series is the power of the node in a zone or of a workload in the primary
+// zone of the node, with its expected power
+type Series struct {
+	Kind      string `json:"kind"`
+	ID        string `json:"id,omitempty"` // empty for the node
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"` // of pods
+	Zone      string `json:"zone"`
+
+	Watts         float64 `json:"watts"`         // in the last interval
+	ExpectedWatts float64 `json:"expectedWatts"` // moving average before the last interval
+	ZScore        float64 `json:"zScore"`        // deviation of watts in standard deviations
+
+	// Anomalous is true if the power in the last interval was abnormal
+	Anomalous bool `json:"anomalous"`
+}
+
+// Anomaly is an abnormal jump of the power of a series
+type Anomaly struct {
+	Timestamp time.Time `json:"timestamp"`
+	Series
+}
+
+// Anomalies is the response of the anomalies endpoint and tool
+type Anomalies struct {
+	Anomalies []Anomaly `json:"anomalies"` // oldest first
+}
+
+type Opts struct {
+	logger         *slog.Logger
+	clock          clock.WithTicker
+	sampleInterval time.Duration
+	threshold      float64
+	alpha          float64
+	workloads      int
+	tools          ToolRegistry
+	events         *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:         slog.Default(),
+		clock:          clock.RealClock{},
+		sampleInterval: 5 * time.Second,
+		threshold:      4,
+		alpha:          0.1,
+		workloads:      10,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Detector
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to sample the power
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithSampleInterval sets the interval between samples of the power; it
+// should be the interval of the monitor
+func WithSampleInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.sampleInterval = d
+	}
+}
+
+// WithThreshold sets the z-score above which the power is abnormal
+func WithThreshold(z float64) OptionFn {
+	return func(o *Opts) {
+		o.threshold = z
+	}
+}
+
+// WithAlpha sets the smoothing factor of the moving average, between 0 and 1;
+// higher values adapt faster to changes of the power
+func WithAlpha(alpha float64) OptionFn {
+	return func(o *Opts) {
+		o.alpha = alpha
+	}
+}
+
+// WithWorkloads sets the number of containers, VMs and pods drawing the most
+// power whose power is watched, of each kind
+func WithWorkloads(n int) OptionFn {
+	return func(o *Opts) {
+		o.workloads = n
+	}
+}
+
+// WithTools sets the registry the anomalies are served by as an MCP tool
+func WithTools(t ToolRegistry) OptionFn {
+	return func(o *Opts) {
+		o.tools = t
+	}
+}
+
+// WithEventBus samples the power whenever the monitor publishes a new snapshot
+// on bus, rather than every sample interval, and publishes the anomalies on it
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// seriesKey identifies a series
+type seriesKey struct {
+	kind, id, zone string
+}
+
+// ewma is the moving average of the mean and variance of the power of a
+// series
+type ewma struct {
+	mean, variance float64
+	samples        int
+	last           Series
+}
+
+// Detector samples the power of the node and its top workloads and flags
+// abnormal jumps
+type Detector struct {
+	logger         *slog.Logger
+	monitor        Monitor
+	api            APIRegistry
+	tools          ToolRegistry
+	clock          clock.WithTicker
+	sampleInterval time.Duration
+	threshold      float64
+	alpha          float64
+	workloads      int
+	events         *event.Bus
+	snapshots      *event.Subscription // nil if sampled every sample interval
+
+	mu           sync.Mutex
+	lastSnapshot time.Time
+	series       map[seriesKey]*ewma
+	anomalies    []Anomaly // most recent, oldest first
+}
+
+var (
+	_ service.Initializer = (*Detector)(nil)
+	_ service.Runner      = (*Detector)(nil)
+	_ service.Dependent   = (*Detector)(nil)
+)
+
+// NewDetector creates a new Detector of anomalies of the power of pm that
+// serves them using api
+func NewDetector(pm Monitor, api APIRegistry, applyOpts ...OptionFn) *Detector {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Detector{
+		logger:         opts.logger.With("service", "anomaly"),
+		monitor:        pm,
+		api:            api,
+		tools:          opts.tools,
+		clock:          opts.clock,
+		sampleInterval: opts.sampleInterval,
+		threshold:      opts.threshold,
+		alpha:          opts.alpha,
+		workloads:      opts.workloads,
+		events:         opts.events,
+		series:         map[seriesKey]*ewma{},
+	}
+}
+
+func (d *Detector) Name() string {
+	return "anomaly"
+}
+
+// Dependencies returns the monitor, and the API server and MCP tools the
+// anomalies are served by
+func (d *Detector) Dependencies() []service.Service {
+	deps := []service.Service{d.monitor}
+	if s, ok := d.api.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	if s, ok := d.tools.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (d *Detector) Init() error {
+	if d.events != nil {
+		d.snapshots = d.events.Subscribe(d.Name(), 1, event.SnapshotReady)
+	}
+	if err := d.api.Register(Endpoint, "Anomalies", "Recent abnormal jumps of the power of the node and its top workloads (?since=10m)", http.HandlerFunc(d.handleAnomalies)); err != nil {
+		return err
+	}
+	if d.tools == nil {
+		return nil
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"since": map[string]any{
+				"type":        "string",
+				"description": "Only return the anomalies of the last duration, e.g. 30m or 2h",
+			},
+		},
+	}
+	return d.tools.RegisterTool(ToolName,
+		"Recent abnormal jumps of the power of the zones of the node and of the containers, VMs and pods drawing the most power, "+
+			"with the power in watts, the expected power and the z-score of the jump",
+		schema, d.callTool)
+}
+
+// Run samples the power until ctx is cancelled
+func (d *Detector) Run(ctx context.Context) error {
+	var ready <-chan event.Event
+	if d.snapshots != nil {
+		defer d.snapshots.Close()
+		ready = d.snapshots.C()
+	}
+	var tick <-chan time.Time
+	if ready == nil {
+		ticker := d.clock.NewTicker(d.sampleInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-ready:
+		}
+		snapshot, err := d.monitor.LatestSnapshot()
+		if err != nil {
+			d.logger.Warn("Failed to get snapshot", "error", err)
+			continue
+		}
+		d.observe(snapshot)
+	}
+}
+
+// observe updates the series with the power of the node and its top workloads
+// in snapshot and records their anomalies; snapshots already observed are
+// ignored
+func (d *Detector) observe(snapshot *monitor.Snapshot) {
+	if snapshot.Node == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !snapshot.Timestamp.After(d.lastSnapshot) {
+		return
+	}
+	d.lastSnapshot = snapshot.Timestamp
+
+	observed := map[seriesKey]bool{}
+	add := func(s Series) {
+		key := seriesKey{s.Kind, s.ID, s.Zone}
+		observed[key] = true
+		if d.update(key, s) {
+			a := Anomaly{Timestamp: snapshot.Timestamp, Series: d.series[key].last}
+			d.logger.Warn("Abnormal power", "kind", s.Kind, "id", s.ID, "name", s.Name, "zone", s.Zone,
+				"watts", a.Watts, "expected", a.ExpectedWatts, "z", a.ZScore)
+			d.anomalies = append(d.anomalies, a)
+			d.publish(a)
+		}
+	}
+
+	// workloads are watched in the zone the node consumed the most energy in,
+	// e.g. platform, psys or package, since zones overlap on some platforms
+	nodeZones := map[string]float64{}
+	nodeEnergy := map[string]float64{}
+	for zone, usage := range snapshot.Node.Zones {
+		nodeZones[zone.Name()] += usage.Power.Watts()
+		nodeEnergy[zone.Name()] += usage.EnergyTotal.Joules()
+	}
+	primary := ""
+	for zone, joules := range nodeEnergy {
+		if primary == "" || joules > nodeEnergy[primary] || (joules == nodeEnergy[primary] && zone < primary) {
+			primary = zone
+		}
+	}
+	for zone, watts := range nodeZones {
+		add(Series{Kind: KindNode, Zone: zone, Watts: watts})
+	}
+
+	top := func(kind string, ws []Series) {
+		slices.SortFunc(ws, func(a, b Series) int {
+			return cmp.Or(cmp.Compare(b.Watts, a.Watts), cmp.Compare(a.ID, b.ID))
+		})
+		for _, s := range ws[:min(d.workloads, len(ws))] {
+			s.Kind, s.Zone = kind, primary
+			add(s)
+		}
+	}
+	var ws []Series
+	for _, c := range snapshot.Containers {
+		ws = append(ws, Series{ID: c.ID, Name: c.Name, Watts: zoneWatts(c.Zones, primary)})
+	}
+	top(KindContainer, ws)
+	ws = nil
+	for _, vm := range snapshot.VirtualMachines {
+		ws = append(ws, Series{ID: vm.ID, Name: vm.Name, Watts: zoneWatts(vm.Zones, primary)})
+	}
+	top(KindVM, ws)
+	ws = nil
+	for _, p := range snapshot.Pods {
+		ws = append(ws, Series{ID: p.ID, Name: p.Name, Namespace: p.Namespace, Watts: zoneWatts(p.Zones, primary)})
+	}
+	top(KindPod, ws)
+
+	// forget workloads no longer among the top ones, and zones no longer read
+	for key := range d.series {
+		if !observed[key] {
+			delete(d.series, key)
+		}
+	}
+	if n := len(d.anomalies); n > maxAnomalies {
+		d.anomalies = slices.Clone(d.anomalies[n-maxAnomalies:])
+	}
+}
+
+// publish publishes a on the event bus, if any
+func (d *Detector) publish(a Anomaly) {
+	if d.events == nil {
+		return
+	}
+	subject := a.Kind + "/" + a.ID
+	if a.Kind == KindNode {
+		subject = KindNode + "/" + a.Zone
+	}
+	attrs := map[string]string{
+		"name":      a.Name,
+		"namespace": a.Namespace,
+		"zone":      a.Zone,
+		"watts":     strconv.FormatFloat(a.Watts, 'f', 2, 64),
+		"expected":  strconv.FormatFloat(a.ExpectedWatts, 'f', 2, 64),
+		"zScore":    strconv.FormatFloat(a.ZScore, 'f', 2, 64),
+	}
+	maps.DeleteFunc(attrs, func(_, v string) bool { return v == "" })
+	d.events.Publish(event.Event{
+		Kind:    event.ThresholdCrossed,
+		Time:    a.Timestamp,
+		Subject: subject,
+		Message: "Abnormal power",
+		Attrs:   attrs,
+	})
+}
+
+// update adds the power of s to the moving average of the series of key and
+// returns true if it is abnormal
+func (d *Detector) update(key seriesKey, s Series) bool {
+	e, ok := d.series[key]
+	if !ok {
+		e = &ewma{mean: s.Watts}
+		d.series[key] = e
+	}
+
+	deviation := s.Watts - e.mean
+	s.ExpectedWatts = e.mean
+	s.ZScore = deviation / max(math.Sqrt(e.variance), minStdWatts)
+	s.Anomalous = e.samples >= warmup && math.Abs(deviation) >= minDeviationWatts && math.Abs(s.ZScore) >= d.threshold
+	e.last = s
+
+	// West's incremental exponentially weighted mean and variance
+	e.mean += d.alpha * deviation
+	e.variance = (1 - d.alpha) * (e.variance + d.alpha*deviation*deviation)
+	e.samples++
+	return s.Anomalous
+}
+
+// zoneWatts returns the power in zones of the name
+func zoneWatts(zones monitor.ZoneUsageMap, name string) float64 {
+	var watts float64
+	for zone, usage := range zones {
+		if zone.Name() == name {
+			watts += usage.Power.Watts()
+		}
+	}
+	return watts
+}
+
+// Series returns the series watched with their power in the last interval
+func (d *Detector) Series() []Series {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ret := make([]Series, 0, len(d.series))
+	for _, e := range d.series {
+		ret = append(ret, e.last)
+	}
+	slices.SortFunc(ret, func(a, b Series) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.ID, b.ID), cmp.Compare(a.Zone, b.Zone))
+	})
+	return ret
+}
+
+// Anomalies returns the anomalies recorded since since, oldest first; all if
+// since is zero
+func (d *Detector) Anomalies(since time.Time) []Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ret := []Anomaly{}
+	for _, a := range d.anomalies {
+		if since.IsZero() || !a.Timestamp.Before(since) {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// since returns the time a duration, e.g. 30m, before now; zero if empty
+func (d *Detector) since(duration string) (time.Time, error) {
+	if duration == "" {
+		return time.Time{}, nil
+	}
+	since, err := time.ParseDuration(duration)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since: %q; must be a duration, e.g. 30m", duration)
+	}
+	return d.clock.Now().Add(-since), nil
+}
+
+func (d *Detector) callTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		Since string `json:"since"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+	since, err := d.since(params.Since)
+	if err != nil {
+		return nil, err
+	}
+	return Anomalies{Anomalies: d.Anomalies(since)}, nil
+}
+
+// handleAnomalies serves the anomalies of the duration of the since query
+// parameter, or all recent ones
+func (d *Detector) handleAnomalies(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	since, err := d.since(req.URL.Query().Get("since"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Anomalies{Anomalies: d.Anomalies(since)}); err != nil {
+		d.logger.Error("Failed to write anomalies", "error", err)
+	}
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package anomaly
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var (
+	pkg  = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:1", 1000*device.Joule)
+)
+
+// snapshot returns a snapshot at ts of the node drawing nodeWatts in the
+// package zone and of pods drawing the watts keyed by their name
+func snapshot(ts time.Time, nodeWatts float64, pods map[string]float64) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = ts
+	s.Node = &monitor.Node{Timestamp: ts, Zones: monitor.NodeZoneUsageMap{
+		pkg:  {EnergyTotal: 1000 * device.Joule, Power: device.Power(nodeWatts) * device.Watt},
+		dram: {EnergyTotal: 100 * device.Joule, Power: 5 * device.Watt},
+	}}
+	for name, watts := range pods {
+		s.Pods[name] = &monitor.Pod{ID: name + "-uid", Name: name, Namespace: "prod", Zones: monitor.ZoneUsageMap{
+			pkg:  {Power: device.Power(watts) * device.Watt},
+			dram: {Power: device.Watt},
+		}}
+	}
+	return s
+}
+
+func TestDetector(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	registry := server.NewFakeRegistry()
+	d := NewDetector(monitor.NewFakeMonitor(nil), registry, WithClock(fakeClock), WithWorkloads(1), WithTools(registry))
+	assert.Equal(t, "anomaly", d.Name())
+	require.NoError(t, d.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+	require.Contains(t, registry.Tools, ToolName)
+
+	// the power of the node and the pods varies by ±2W
+	ts := start
+	for i := range 30 {
+		ts = start.Add(time.Duration(i) * 5 * time.Second)
+		jitter := float64(i%3 - 1)
+		d.observe(snapshot(ts, 100+2*jitter, map[string]float64{"api": 20 + jitter, "batch": 10}))
+	}
+	d.observe(snapshot(ts, 300, nil)) // already observed
+	assert.Empty(t, d.Anomalies(time.Time{}))
+
+	series := d.Series()
+	require.Len(t, series, 3, "the node zones and the top pod")
+	assert.Equal(t, Series{Kind: KindNode, Zone: "dram", Watts: 5, ExpectedWatts: 5}, series[0])
+	assert.Equal(t, "package", series[1].Zone)
+	assert.Equal(t, KindPod, series[2].Kind)
+	assert.Equal(t, "api-uid", series[2].ID)
+	assert.Equal(t, "package", series[2].Zone, "pods are watched in the zone the node consumed the most in")
+
+	// the package power of the node and the api pod jumps
+	jump := ts.Add(5 * time.Second)
+	d.observe(snapshot(jump, 180, map[string]float64{"api": 90, "batch": 10}))
+	anomalies := d.Anomalies(time.Time{})
+	require.Len(t, anomalies, 2)
+	for _, a := range anomalies {
+		assert.Equal(t, jump, a.Timestamp)
+		assert.True(t, a.Anomalous)
+		assert.Greater(t, a.ZScore, 4.0)
+	}
+	assert.Equal(t, KindNode, anomalies[0].Kind)
+	assert.InDelta(t, 100, anomalies[0].ExpectedWatts, 1)
+	assert.Equal(t, "api", anomalies[1].Name)
+	assert.Equal(t, "prod", anomalies[1].Namespace)
+
+	fakeClock.SetTime(jump.Add(time.Minute))
+
+	t.Run("REST", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/anomalies?since=2m", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got Anomalies
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Len(t, got.Anomalies, 2)
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/anomalies?since=30s", nil))
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Empty(t, got.Anomalies)
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/anomalies?since=recently", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/anomalies", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("MCP tool", func(t *testing.T) {
+		got, err := registry.Tools[ToolName](context.Background(), json.RawMessage(`{"since":"5m"}`))
+		require.NoError(t, err)
+		assert.Len(t, got.(Anomalies).Anomalies, 2)
+
+		_, err = registry.Tools[ToolName](context.Background(), json.RawMessage(`{"since":"yesterday"}`))
+		assert.ErrorContains(t, err, "invalid since")
+	})
+
+	t.Run("workloads no longer on top are forgotten", func(t *testing.T) {
+		d.observe(snapshot(jump.Add(5*time.Second), 180, map[string]float64{"batch": 10}))
+		series := d.Series()
+		require.Len(t, series, 3)
+		assert.Equal(t, "batch-uid", series[2].ID)
+		assert.False(t, series[2].Anomalous, "a new series has no baseline")
+	})
+}
+
+func TestDetectorWarmup(t *testing.T) {
+	start := time.Now()
+	d := NewDetector(monitor.NewFakeMonitor(nil), server.NewFakeRegistry())
+	for i := range warmup {
+		d.observe(snapshot(start.Add(time.Duration(i)*time.Second), float64(100+50*(i%2)), nil))
+	}
+	assert.Empty(t, d.Anomalies(time.Time{}), "jumps before the warmup are not flagged")
+}
+
+func TestDetectorEvents(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	bus := event.NewBus()
+	anomalies := bus.Subscribe("test", 1, event.ThresholdCrossed)
+	pm := monitor.NewFakeMonitor(snapshot(start, 100, map[string]float64{"api": 20}))
+	d := NewDetector(pm, server.NewFakeRegistry(), WithWorkloads(1), WithEventBus(bus))
+	require.NoError(t, d.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Run(ctx) }()
+	bus.Publish(event.Event{Kind: event.SnapshotReady})
+	assert.Eventually(t, func() bool { return len(d.Series()) == 3 }, time.Second, time.Millisecond,
+		"the power is sampled when a snapshot is ready")
+	cancel()
+	require.NoError(t, <-done)
+
+	d.publish(Anomaly{Timestamp: start, Series: Series{Kind: KindPod, ID: "api-uid", Name: "api", Namespace: "prod",
+		Zone: "package", Watts: 90, ExpectedWatts: 20, ZScore: 12.5, Anomalous: true}})
+	assert.Equal(t, event.Event{
+		Kind:    event.ThresholdCrossed,
+		Time:    start,
+		Subject: "pod/api-uid",
+		Message: "Abnormal power",
+		Attrs: map[string]string{
+			"name": "api", "namespace": "prod", "zone": "package",
+			"watts": "90.00", "expected": "20.00", "zScore": "12.50",
+		},
+	}, <-anomalies.C())
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package budget notifies external systems when energy budgets tracked by the
+// monitor are exceeded.
+package budget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"k8s.io/utils/clock"
+)
+
+// Event is the payload posted to the webhook when a budget is exceeded
+type Event struct {
+	Node           string    `json:"node"`
+	Scope          string    `json:"scope"`
+	Name           string    `json:"name,omitempty"`
+	BudgetJoules   float64   `json:"budgetJoules"`
+	ConsumedJoules float64   `json:"consumedJoules"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// Opts holds the options of the WebhookNotifier
+type Opts struct {
+	logger   *slog.Logger
+	clock    clock.PassiveClock
+	client   *http.Client
+	nodeName string
+}
+
+// DefaultOpts returns the default options of the WebhookNotifier
+func DefaultOpts() Opts {
+	return Opts{
+		logger: slog.Default(),
+		clock:  clock.RealClock{},
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// OptionFn is a function that sets one or more options in Opts
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to timestamp events
+func WithClock(c clock.PassiveClock) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to post events
+func WithHTTPClient(c *http.Client) OptionFn {
+	return func(o *Opts) {
+		o.client = c
+	}
+}
+
+// WithNodeName sets the name of the node reported in events
+func WithNodeName(name string) OptionFn {
+	return func(o *Opts) {
+		o.nodeName = name
+	}
+}
+
+// WebhookNotifier posts an Event as JSON to a URL when a budget is exceeded
+type WebhookNotifier struct {
+	logger   *slog.Logger
+	clock    clock.PassiveClock
+	client   *http.Client
+	url      string
+	nodeName string
+}
+
+var _ monitor.BudgetNotifier = (*WebhookNotifier)(nil)
+
+// NewWebhookNotifier returns a notifier that posts events to url
+func NewWebhookNotifier(url string, applyOpts ...OptionFn) *WebhookNotifier {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &WebhookNotifier{
+		logger:   opts.logger.With("service", "budget-webhook"),
+		clock:    opts.clock,
+		client:   opts.client,
+		url:      url,
+		nodeName: opts.nodeName,
+	}
+}
+
+// BudgetExceeded implements monitor.BudgetNotifier; the event is posted in the
+// background so that collection is not blocked by the webhook
+func (n *WebhookNotifier) BudgetExceeded(s monitor.BudgetStatus) {
+	event := Event{
+		Node:           n.nodeName,
+		Scope:          s.Scope,
+		Name:           s.Name,
+		BudgetJoules:   s.Budget.Joules(),
+		ConsumedJoules: s.Consumed.Joules(),
+		Timestamp:      n.clock.Now(),
+	}
+
+	go func() {
+		if err := n.post(context.Background(), event); err != nil {
+			n.logger.Error("Failed to notify budget exceeded", "scope", s.Scope, "name", s.Name, "error", err)
+		}
+	}()
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post event: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
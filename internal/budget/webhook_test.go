@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	events := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var e Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+		events <- e
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	n := NewWebhookNotifier(server.URL,
+		WithNodeName("node-1"),
+		WithClock(testingclock.NewFakePassiveClock(now)),
+	)
+
+	n.BudgetExceeded(monitor.BudgetStatus{
+		Scope:    monitor.BudgetScopeNamespace,
+		Name:     "prod",
+		Budget:   100 * monitor.Joule,
+		Consumed: 150 * monitor.Joule,
+	})
+
+	select {
+	case e := <-events:
+		assert.Equal(t, Event{
+			Node:           "node-1",
+			Scope:          monitor.BudgetScopeNamespace,
+			Name:           "prod",
+			BudgetJoules:   100,
+			ConsumedJoules: 150,
+			Timestamp:      now,
+		}, e)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+}
+
+func TestWebhookNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL)
+	err := n.post(context.Background(), Event{Scope: monitor.BudgetScopeNode})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+
+	n = NewWebhookNotifier("http://127.0.0.1:0")
+	assert.Error(t, n.post(context.Background(), Event{}))
+}
@@ -0,0 +1,300 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package capping caps the power of the node by setting the power limit of
+// RAPL or of the BMC, to a cap configured when Kepler starts or set over the
+// API, if allowed, e.g. by an external controller closing the loop on the
+// power Kepler reports. Every change of the cap is logged for audit.
+package capping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+// Endpoint is the endpoint the power cap is read and set at
+const Endpoint = "/-/powercap"
+
+// Sources of changes of the cap, logged for audit
+const (
+	sourceConfig   = "config"
+	sourceAPI      = "api"
+	sourceShutdown = "shutdown"
+)
+
+type (
+	Limiter = device.PowerLimiter
+	Power   = device.Power
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// Status is the power cap of the node
+type Status struct {
+	Limiter       string  `json:"limiter"`
+	CapWatts      float64 `json:"capWatts"`      // 0 if the node is not capped
+	LimitWatts    float64 `json:"limitWatts"`    // limit read from the limiter; 0 if there is none
+	OriginalWatts float64 `json:"originalWatts"` // limit before Kepler set one, restored on shutdown
+	DryRun        bool    `json:"dryRun"`
+}
+
+type Opts struct {
+	logger   *slog.Logger
+	cap      Power
+	dryRun   bool
+	writable bool
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger: slog.Default(),
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Controller
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithCap sets the cap applied when the controller is initialized; 0 leaves
+// the limit as is
+func WithCap(nodeCap Power) OptionFn {
+	return func(o *Opts) {
+		o.cap = nodeCap
+	}
+}
+
+// WithDryRun logs the limits the controller would set instead of setting them
+func WithDryRun(dryRun bool) OptionFn {
+	return func(o *Opts) {
+		o.dryRun = dryRun
+	}
+}
+
+// WithWritableAPI allows the cap to be set over the API; it is read-only
+// otherwise, since the API is not authenticated
+func WithWritableAPI(writable bool) OptionFn {
+	return func(o *Opts) {
+		o.writable = writable
+	}
+}
+
+// Controller sets the power limit of the limiter to the cap of the node. The
+// cap is read at /-/powercap and, if the API is writable, set with a PUT of the
+// cap in watts; a cap of 0 restores the limits saved when Kepler started, as
+// does shutting down.
+type Controller struct {
+	logger   *slog.Logger
+	limiter  Limiter
+	api      APIRegistry
+	dryRun   bool
+	writable bool
+
+	mu       sync.Mutex
+	cap      Power // 0 if the node is not capped
+	original Power // limit saved on Init
+}
+
+var (
+	_ service.Initializer = (*Controller)(nil)
+	_ service.Shutdowner  = (*Controller)(nil)
+	_ service.Dependent   = (*Controller)(nil)
+)
+
+// NewController creates a controller of the power limit of limiter whose cap
+// is served using api
+func NewController(limiter Limiter, api APIRegistry, applyOpts ...OptionFn) *Controller {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Controller{
+		logger:   opts.logger.With("service", "power-cap", "limiter", limiter.Name()),
+		limiter:  limiter,
+		api:      api,
+		dryRun:   opts.dryRun,
+		writable: opts.writable,
+		cap:      opts.cap,
+	}
+}
+
+func (c *Controller) Name() string {
+	return "power-cap"
+}
+
+// Dependencies returns the API server the cap is served by
+func (c *Controller) Dependencies() []service.Service {
+	if s, ok := c.api.(service.Service); ok {
+		return []service.Service{s}
+	}
+	return nil
+}
+
+// Init saves the current limit, to restore it on shutdown, and applies the
+// configured cap
+func (c *Controller) Init() error {
+	original, err := c.limiter.SavePowerLimit()
+	if err != nil {
+		return fmt.Errorf("failed to save power limit: %w", err)
+	}
+	c.original = original
+
+	if err := c.api.Register(Endpoint, "Power cap", "Get or set (PUT) the power cap of the node in watts", http.HandlerFunc(c.handleCap)); err != nil {
+		return err
+	}
+
+	c.logger.Info("Power capping enabled", "limit-watts", original.Watts(), "dry-run", c.dryRun, "writable-api", c.writable)
+	if c.cap == 0 {
+		return nil
+	}
+	nodeCap := c.cap
+	c.cap = 0
+	return c.SetCap(nodeCap, sourceConfig, "")
+}
+
+// Shutdown restores the limit saved on Init if the node is capped
+func (c *Controller) Shutdown() error {
+	c.mu.Lock()
+	capped := c.cap != 0
+	c.mu.Unlock()
+
+	if !capped {
+		return nil
+	}
+	return c.SetCap(0, sourceShutdown, "")
+}
+
+// SetCap caps the power of the node, or restores the limit saved on Init if
+// cap is 0. source and remote, the address of the client, are logged for audit.
+func (c *Controller) SetCap(nodeCap Power, source, remote string) error {
+	if nodeCap < 0 || math.IsNaN(float64(nodeCap)) || math.IsInf(float64(nodeCap), 0) {
+		return fmt.Errorf("invalid power cap %s; must be finite and not negative", nodeCap)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limit := nodeCap
+	if nodeCap == 0 {
+		limit = c.original
+	}
+	audit := c.logger.With(
+		"audit", true,
+		"source", source,
+		"remote", remote,
+		"previous-cap-watts", c.cap.Watts(),
+		"cap-watts", nodeCap.Watts(),
+		"limit-watts", limit.Watts(),
+		"dry-run", c.dryRun,
+	)
+
+	if !c.dryRun {
+		set := func() error { return c.limiter.SetPowerLimit(limit) }
+		if nodeCap == 0 {
+			set = c.limiter.RestorePowerLimit
+		}
+		if err := set(); err != nil {
+			audit.Error("Failed to set power limit", "error", err)
+			return fmt.Errorf("failed to set power limit: %w", err)
+		}
+	}
+	c.cap = nodeCap
+
+	if nodeCap == 0 {
+		audit.Info("Power cap removed")
+	} else {
+		audit.Info("Power cap set")
+	}
+	return nil
+}
+
+// Cap returns the power cap of the node; 0 if it is not capped
+func (c *Controller) Cap() Power {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cap
+}
+
+// Status returns the power cap of the node
+func (c *Controller) Status() (Status, error) {
+	limit, err := c.limiter.PowerLimit()
+	if err != nil {
+		return Status{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Status{
+		Limiter:       c.limiter.Name(),
+		CapWatts:      c.cap.Watts(),
+		LimitWatts:    limit.Watts(),
+		OriginalWatts: c.original.Watts(),
+		DryRun:        c.dryRun,
+	}, nil
+}
+
+// handleCap responds with the status of the cap, after setting it to the watts
+// in the body of PUT requests
+func (c *Controller) handleCap(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if !c.writable {
+			http.Error(w, "setting the power cap over the API is not allowed", http.StatusForbidden)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read power cap: %v", err), http.StatusBadRequest)
+			return
+		}
+		watts, err := strconv.ParseFloat(strings.TrimSpace(string(body)), 64)
+		if err != nil || watts < 0 || math.IsNaN(watts) || math.IsInf(watts, 0) {
+			http.Error(w, fmt.Sprintf("invalid power cap %q; must be watts, or 0 to remove the cap", strings.TrimSpace(string(body))), http.StatusBadRequest)
+			return
+		}
+		if err := c.SetCap(Power(watts)*device.Watt, sourceAPI, r.RemoteAddr); err != nil {
+			code := http.StatusInternalServerError
+			if errors.Is(err, device.ErrInvalidPowerLimit) {
+				code = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), code)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "only GET and PUT are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := c.Status()
+	if err != nil {
+		c.logger.Error("Failed to read power limit", "error", err)
+		http.Error(w, "failed to read power limit", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		c.logger.Error("Failed to write power cap", "error", err)
+	}
+}
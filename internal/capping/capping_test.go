@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package capping
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+)
+
+// fakeLimiter records the limits set
+type fakeLimiter struct {
+	limit  Power
+	min    Power // limits below min are invalid
+	saved  Power
+	limits []Power
+	err    error
+}
+
+func (l *fakeLimiter) Name() string { return "fake" }
+
+func (l *fakeLimiter) PowerLimit() (Power, error) { return l.limit, nil }
+
+func (l *fakeLimiter) SetPowerLimit(limit Power) error {
+	if l.err != nil {
+		return l.err
+	}
+	if limit < l.min {
+		return device.Errorf(device.ErrInvalidPowerLimit, "power limit %s is below its min power %s", limit, l.min)
+	}
+	l.limit = limit
+	l.limits = append(l.limits, limit)
+	return nil
+}
+
+func (l *fakeLimiter) SavePowerLimit() (Power, error) {
+	l.saved = l.limit
+	return l.saved, nil
+}
+
+func (l *fakeLimiter) RestorePowerLimit() error {
+	return l.SetPowerLimit(l.saved)
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func TestController(t *testing.T) {
+	limiter := &fakeLimiter{limit: 300 * device.Watt, min: 50 * device.Watt}
+	registry := server.NewFakeRegistry()
+	c := NewController(limiter, registry, WithLogger(testLogger()), WithCap(250*device.Watt), WithWritableAPI(true))
+	assert.Equal(t, "power-cap", c.Name())
+
+	require.NoError(t, c.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+	assert.Equal(t, []Power{250 * device.Watt}, limiter.limits, "the configured cap is set")
+
+	do := func(method, body string) (int, Status) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(method, Endpoint, strings.NewReader(body)))
+		var status Status
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+		}
+		return rec.Code, status
+	}
+
+	code, status := do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, Status{Limiter: "fake", CapWatts: 250, LimitWatts: 250, OriginalWatts: 300}, status)
+
+	code, status = do(http.MethodPut, "200\n")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 200.0, status.CapWatts)
+	assert.Equal(t, 200*device.Watt, limiter.limit)
+	assert.Equal(t, 200*device.Watt, c.Cap())
+
+	for _, body := range []string{"-10", "fast", "", "10", "NaN", "Inf", "-Inf"} {
+		code, _ = do(http.MethodPut, body)
+		assert.Equal(t, http.StatusBadRequest, code, "invalid cap %q", body)
+	}
+	assert.Error(t, c.SetCap(Power(math.NaN()), sourceAPI, ""))
+	assert.Error(t, c.SetCap(Power(math.Inf(1)), sourceAPI, ""))
+	assert.Equal(t, 200*device.Watt, limiter.limit)
+	code, _ = do(http.MethodPost, "100")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+
+	limiter.err = errors.New("permission denied")
+	code, _ = do(http.MethodPut, "150")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	limiter.err = nil
+
+	// removing the cap restores the original limit
+	code, status = do(http.MethodPut, "0")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Zero(t, status.CapWatts)
+	assert.Equal(t, 300*device.Watt, limiter.limit)
+
+	// so does shutting down while capped
+	require.NoError(t, c.SetCap(100*device.Watt, sourceAPI, ""))
+	require.NoError(t, c.Shutdown())
+	assert.Equal(t, 300*device.Watt, limiter.limit)
+	assert.Len(t, limiter.limits, 5)
+
+	require.NoError(t, c.Shutdown())
+	assert.Len(t, limiter.limits, 5, "the limit is not restored if not capped")
+}
+
+func TestControllerReadOnly(t *testing.T) {
+	limiter := &fakeLimiter{limit: 300 * device.Watt}
+	registry := server.NewFakeRegistry()
+	c := NewController(limiter, registry, WithLogger(testLogger()))
+	require.NoError(t, c.Init())
+
+	rec := httptest.NewRecorder()
+	registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodPut, Endpoint, strings.NewReader("100")))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, limiter.limits, "the cap is not set over a read-only API")
+
+	rec = httptest.NewRecorder()
+	registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Endpoint, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestControllerDryRun(t *testing.T) {
+	limiter := &fakeLimiter{limit: 300 * device.Watt}
+	c := NewController(limiter, server.NewFakeRegistry(), WithLogger(testLogger()), WithCap(250*device.Watt), WithDryRun(true))
+	require.NoError(t, c.Init())
+
+	status, err := c.Status()
+	require.NoError(t, err)
+	assert.Equal(t, Status{Limiter: "fake", CapWatts: 250, LimitWatts: 300, OriginalWatts: 300, DryRun: true}, status)
+
+	require.NoError(t, c.SetCap(200*device.Watt, sourceAPI, ""))
+	require.NoError(t, c.Shutdown())
+	assert.Empty(t, limiter.limits, "no limit is set in dry-run mode")
+}
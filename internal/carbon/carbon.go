@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package carbon provides the carbon intensity of the electricity grid, used to
+// convert the energy consumed by the node and workloads into emissions.
+package carbon
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+// Intensity is the carbon intensity of electricity in grams of CO2 equivalent
+// emitted per kWh (gCO2e/kWh)
+type Intensity float64
+
+// Emissions returns the grams of CO2 equivalent emitted for consuming joules of
+// energy at the intensity
+func (i Intensity) Emissions(joules float64) float64 {
+	const joulesPerKWh = 3.6e6
+	return float64(i) * joules / joulesPerKWh
+}
+
+// ErrUnavailable is returned when the carbon intensity is not known yet
+var ErrUnavailable = errors.New("carbon intensity is unavailable")
+
+// Provider provides the carbon intensity of the electricity consumed by the node
+type Provider interface {
+	service.Service
+
+	// Intensity returns the latest known carbon intensity
+	Intensity() (Intensity, error)
+}
+
+// staticProvider provides a fixed carbon intensity
+type staticProvider struct {
+	intensity Intensity
+}
+
+var _ Provider = (*staticProvider)(nil)
+
+// NewStaticProvider returns a provider that always reports intensity; useful
+// when the grid mix is known and does not change much
+func NewStaticProvider(intensity Intensity) *staticProvider {
+	return &staticProvider{intensity: intensity}
+}
+
+func (p *staticProvider) Name() string {
+	return "carbon-static"
+}
+
+func (p *staticProvider) Intensity() (Intensity, error) {
+	return p.intensity, nil
+}
+
+// Opts holds the options of providers that fetch the carbon intensity from an API
+type Opts struct {
+	logger          *slog.Logger
+	clock           clock.WithTicker
+	client          *http.Client
+	baseURL         string
+	refreshInterval time.Duration
+}
+
+// DefaultOpts returns the default options of API providers
+func DefaultOpts() Opts {
+	return Opts{
+		logger:          slog.Default(),
+		clock:           clock.RealClock{},
+		client:          &http.Client{Timeout: 30 * time.Second},
+		refreshInterval: 15 * time.Minute,
+	}
+}
+
+// OptionFn is a function that sets one or more options in Opts
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to schedule refreshes
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to call the API
+func WithHTTPClient(c *http.Client) OptionFn {
+	return func(o *Opts) {
+		o.client = c
+	}
+}
+
+// WithBaseURL overrides the base URL of the API
+func WithBaseURL(url string) OptionFn {
+	return func(o *Opts) {
+		o.baseURL = url
+	}
+}
+
+// WithRefreshInterval sets how often the carbon intensity is fetched
+func WithRefreshInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.refreshInterval = d
+	}
+}
+
+// fetchFn fetches the current carbon intensity
+type fetchFn func(ctx context.Context) (Intensity, error)
+
+// apiProvider periodically fetches the carbon intensity in the background so that
+// the latest intensity is available without calling the API on every refresh
+type apiProvider struct {
+	name            string
+	logger          *slog.Logger
+	clock           clock.WithTicker
+	refreshInterval time.Duration
+	fetch           fetchFn
+
+	mu        sync.RWMutex
+	intensity Intensity
+	known     bool
+}
+
+var (
+	_ Provider            = (*apiProvider)(nil)
+	_ service.Initializer = (*apiProvider)(nil)
+	_ service.Runner      = (*apiProvider)(nil)
+)
+
+func newAPIProvider(name string, opts Opts, fetch fetchFn) *apiProvider {
+	return &apiProvider{
+		name:            name,
+		logger:          opts.logger.With("service", name),
+		clock:           opts.clock,
+		refreshInterval: opts.refreshInterval,
+		fetch:           fetch,
+	}
+}
+
+func (p *apiProvider) Name() string {
+	return p.name
+}
+
+// Init fetches the carbon intensity for the first time; failures are not fatal
+// since the API may be temporarily unreachable
+func (p *apiProvider) Init() error {
+	p.refresh(context.Background())
+	return nil
+}
+
+// Run refreshes the carbon intensity periodically until ctx is cancelled
+func (p *apiProvider) Run(ctx context.Context) error {
+	ticker := p.clock.NewTicker(p.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			p.refresh(ctx)
+		}
+	}
+}
+
+func (p *apiProvider) Intensity() (Intensity, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.known {
+		return 0, ErrUnavailable
+	}
+	return p.intensity, nil
+}
+
+// refresh fetches the carbon intensity; the last known intensity is retained
+// if the fetch fails
+func (p *apiProvider) refresh(ctx context.Context) {
+	intensity, err := p.fetch(ctx)
+	if err != nil {
+		p.logger.Warn("Failed to fetch carbon intensity; using last known value", "error", err)
+		return
+	}
+
+	p.mu.Lock()
+	p.intensity = intensity
+	p.known = true
+	p.mu.Unlock()
+
+	p.logger.Debug("Carbon intensity updated", "gco2e-per-kwh", float64(intensity))
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package carbon
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestIntensityEmissions(t *testing.T) {
+	intensity := Intensity(400)
+	// 1 kWh at 400 gCO2e/kWh
+	assert.InDelta(t, 400.0, intensity.Emissions(3.6e6), 1e-9)
+	assert.InDelta(t, 0.2, intensity.Emissions(1800), 1e-9)
+	assert.Zero(t, intensity.Emissions(0))
+}
+
+func TestStaticProvider(t *testing.T) {
+	p := NewStaticProvider(250)
+	assert.Equal(t, "carbon-static", p.Name())
+
+	intensity, err := p.Intensity()
+	require.NoError(t, err)
+	assert.Equal(t, Intensity(250), intensity)
+}
+
+func TestAPIProvider(t *testing.T) {
+	t.Run("unavailable until fetched", func(t *testing.T) {
+		p := newAPIProvider("test", DefaultOpts(), func(context.Context) (Intensity, error) {
+			return 0, errors.New("unreachable")
+		})
+
+		require.NoError(t, p.Init(), "fetch failures are not fatal")
+		_, err := p.Intensity()
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+
+	t.Run("retains last known intensity on failure", func(t *testing.T) {
+		fail := false
+		p := newAPIProvider("test", DefaultOpts(), func(context.Context) (Intensity, error) {
+			if fail {
+				return 0, errors.New("unreachable")
+			}
+			return 300, nil
+		})
+
+		require.NoError(t, p.Init())
+		fail = true
+		p.refresh(context.Background())
+
+		intensity, err := p.Intensity()
+		require.NoError(t, err)
+		assert.Equal(t, Intensity(300), intensity)
+	})
+
+	t.Run("refreshes periodically", func(t *testing.T) {
+		fakeClock := testingclock.NewFakeClock(time.Now())
+		var fetches atomic.Int32
+		opts := DefaultOpts()
+		WithClock(fakeClock)(&opts)
+		WithRefreshInterval(time.Minute)(&opts)
+
+		p := newAPIProvider("test", opts, func(context.Context) (Intensity, error) {
+			return Intensity(100 * fetches.Add(1)), nil
+		})
+		require.NoError(t, p.Init())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.NoError(t, p.Run(ctx))
+		}()
+
+		assert.Eventually(t, fakeClock.HasWaiters, time.Second, time.Millisecond)
+		fakeClock.Step(time.Minute)
+		assert.Eventually(t, func() bool {
+			intensity, err := p.Intensity()
+			return err == nil && intensity == 200
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		<-done
+	})
+}
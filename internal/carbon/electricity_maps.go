@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const electricityMapsURL = "https://api.electricitymap.org"
+
+type electricityMapsResponse struct {
+	Zone            string   `json:"zone"`
+	CarbonIntensity *float64 `json:"carbonIntensity"`
+}
+
+// NewElectricityMapsProvider returns a provider that fetches the latest carbon
+// intensity of zone (e.g. "DE") from the Electricity Maps API using token
+func NewElectricityMapsProvider(zone, token string, applyOpts ...OptionFn) *apiProvider {
+	opts := DefaultOpts()
+	opts.baseURL = electricityMapsURL
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	fetch := func(ctx context.Context) (Intensity, error) {
+		endpoint := fmt.Sprintf("%s/v3/carbon-intensity/latest?zone=%s", opts.baseURL, url.QueryEscape(zone))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("auth-token", token)
+
+		resp, err := opts.client.Do(req)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch carbon intensity: %w", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("failed to fetch carbon intensity of zone %s: %s", zone, resp.Status)
+		}
+
+		var data electricityMapsResponse
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			return 0, fmt.Errorf("failed to decode carbon intensity: %w", err)
+		}
+		if data.CarbonIntensity == nil {
+			return 0, fmt.Errorf("no carbon intensity reported for zone %s", zone)
+		}
+		return Intensity(*data.CarbonIntensity), nil
+	}
+
+	return newAPIProvider("carbon-electricitymaps", opts, fetch)
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package carbon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElectricityMapsProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("auth-token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "/v3/carbon-intensity/latest", r.URL.Path)
+
+		switch r.URL.Query().Get("zone") {
+		case "DE":
+			_, _ = w.Write([]byte(`{"zone": "DE", "carbonIntensity": 302, "datetime": "2025-01-01T00:00:00.000Z"}`))
+		case "XX":
+			_, _ = w.Write([]byte(`{"zone": "XX", "carbonIntensity": null}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("latest intensity", func(t *testing.T) {
+		p := NewElectricityMapsProvider("DE", "secret", WithBaseURL(server.URL))
+		assert.Equal(t, "carbon-electricitymaps", p.Name())
+		require.NoError(t, p.Init())
+
+		intensity, err := p.Intensity()
+		require.NoError(t, err)
+		assert.Equal(t, Intensity(302), intensity)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tt := []struct {
+			name  string
+			zone  string
+			token string
+			err   string
+		}{
+			{"unauthorized", "DE", "wrong", "401"},
+			{"unknown zone", "YY", "secret", "404"},
+			{"no intensity", "XX", "secret", "no carbon intensity"},
+		}
+		for _, tc := range tt {
+			t.Run(tc.name, func(t *testing.T) {
+				p := NewElectricityMapsProvider(tc.zone, tc.token, WithBaseURL(server.URL))
+				_, err := p.fetch(context.Background())
+				assert.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+const wattTimeURL = "https://api.watttime.org"
+
+// gramsPerPound converts the lbs/MWh reported by WattTime to g/kWh
+const gramsPerPound = 453.59237
+
+var errUnauthorized = errors.New("unauthorized")
+
+type wattTimeLoginResponse struct {
+	Token string `json:"token"`
+}
+
+type wattTimeForecastResponse struct {
+	Data []struct {
+		Value float64 `json:"value"`
+	} `json:"data"`
+	Meta struct {
+		Units string `json:"units"`
+	} `json:"meta"`
+}
+
+// wattTimeClient fetches the marginal operating emissions rate (MOER) of a
+// region; the login token is renewed when it expires
+type wattTimeClient struct {
+	opts     Opts
+	region   string
+	username string
+	password string
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewWattTimeProvider returns a provider that fetches the current marginal
+// carbon intensity of region (e.g. "CAISO_NORTH") from the WattTime API
+func NewWattTimeProvider(region, username, password string, applyOpts ...OptionFn) *apiProvider {
+	opts := DefaultOpts()
+	opts.baseURL = wattTimeURL
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	c := &wattTimeClient{
+		opts:     opts,
+		region:   region,
+		username: username,
+		password: password,
+	}
+	return newAPIProvider("carbon-watttime", opts, c.fetch)
+}
+
+func (c *wattTimeClient) fetch(ctx context.Context) (Intensity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == "" {
+		if err := c.login(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	intensity, err := c.currentMOER(ctx)
+	if !errors.Is(err, errUnauthorized) {
+		return intensity, err
+	}
+
+	// token has expired
+	if err := c.login(ctx); err != nil {
+		return 0, err
+	}
+	return c.currentMOER(ctx)
+}
+
+func (c *wattTimeClient) login(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.opts.baseURL+"/login", nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.opts.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to login to watttime: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to login to watttime: %s", resp.Status)
+	}
+
+	var data wattTimeLoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("failed to decode watttime login: %w", err)
+	}
+	c.token = data.Token
+	return nil
+}
+
+// currentMOER returns the current marginal carbon intensity of the region
+func (c *wattTimeClient) currentMOER(ctx context.Context) (Intensity, error) {
+	endpoint := fmt.Sprintf("%s/v3/forecast?region=%s&signal_type=co2_moer&horizon_hours=0",
+		c.opts.baseURL, url.QueryEscape(c.region))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.opts.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch carbon intensity: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return 0, errUnauthorized
+	default:
+		return 0, fmt.Errorf("failed to fetch carbon intensity of region %s: %s", c.region, resp.Status)
+	}
+
+	var data wattTimeForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return 0, fmt.Errorf("failed to decode carbon intensity: %w", err)
+	}
+	if len(data.Data) == 0 {
+		return 0, fmt.Errorf("no carbon intensity reported for region %s", c.region)
+	}
+
+	value := data.Data[0].Value
+	switch data.Meta.Units {
+	case "lbs_co2_per_mwh":
+		return Intensity(value * gramsPerPound / 1000), nil
+	case "g_co2_per_kwh":
+		return Intensity(value), nil
+	default:
+		return 0, fmt.Errorf("unsupported carbon intensity units: %q", data.Meta.Units)
+	}
+}
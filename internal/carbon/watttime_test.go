@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package carbon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWattTimeProvider(t *testing.T) {
+	logins := 0
+	validToken := "token-1"
+	units := "lbs_co2_per_mwh"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			logins++
+			_, _ = w.Write([]byte(`{"token": "` + validToken + `"}`))
+
+		case "/v3/forecast":
+			if r.Header.Get("Authorization") != "Bearer "+validToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "CAISO_NORTH", r.URL.Query().Get("region"))
+			assert.Equal(t, "co2_moer", r.URL.Query().Get("signal_type"))
+			_, _ = w.Write([]byte(`{"data": [{"point_time": "2025-01-01T00:00:00+00:00", "value": 1000}],
+				"meta": {"units": "` + units + `"}}`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewWattTimeProvider("CAISO_NORTH", "user", "pass", WithBaseURL(server.URL))
+	assert.Equal(t, "carbon-watttime", p.Name())
+	require.NoError(t, p.Init())
+
+	// 1000 lbs/MWh
+	intensity, err := p.Intensity()
+	require.NoError(t, err)
+	assert.InDelta(t, 453.59237, float64(intensity), 1e-6)
+	assert.Equal(t, 1, logins)
+
+	t.Run("token is reused", func(t *testing.T) {
+		_, err := p.fetch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, logins)
+	})
+
+	t.Run("expired token is renewed", func(t *testing.T) {
+		validToken = "token-2"
+		_, err := p.fetch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, logins)
+	})
+
+	t.Run("unsupported units", func(t *testing.T) {
+		units = "percentile"
+		_, err := p.fetch(context.Background())
+		assert.ErrorContains(t, err, "unsupported carbon intensity units")
+		units = "lbs_co2_per_mwh"
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		p := NewWattTimeProvider("CAISO_NORTH", "user", "wrong", WithBaseURL(server.URL))
+		_, err := p.fetch(context.Background())
+		assert.ErrorContains(t, err, "failed to login")
+	})
+}
@@ -0,0 +1,496 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package chargeback reports the energy consumed by the pods of each namespace
+// in periods of fixed length, with their share of the energy of the node, so
+// that namespaces can be charged for their energy without a metrics pipeline.
+package chargeback
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+// Endpoint is the endpoint the report is served at
+const Endpoint = "/chargeback"
+
+// ToolName is the name of the MCP tool returning the report
+const ToolName = "get_namespace_energy"
+
+type (
+	Monitor      = monitor.Service
+	ToolRegistry = server.ToolRegistry
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// PodEnergy is the energy consumed by a pod in the period of a report
+type PodEnergy struct {
+	Pod      string  `json:"pod"`
+	Joules   float64 `json:"joules"`
+	AvgWatts float64 `json:"avgWatts"`
+}
+
+// NamespaceEnergy is the energy consumed by the pods of a namespace in the
+// period of a report
+type NamespaceEnergy struct {
+	Namespace string  `json:"namespace"`
+	Joules    float64 `json:"joules"`
+	AvgWatts  float64 `json:"avgWatts"`
+	NodeShare float64 `json:"nodeShare"` // fraction of the energy of the node
+	Pods      int     `json:"pods"`      // pods that ran in the period
+
+	TopPods []PodEnergy `json:"topPods"` // sorted by energy, highest first
+}
+
+// Report is the energy of the namespaces running on the node in a period
+type Report struct {
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Complete   bool              `json:"complete"` // false for the period in progress
+	Zone       string            `json:"zone"`     // zone the energy is read from
+	NodeJoules float64           `json:"nodeJoules"`
+	Namespaces []NamespaceEnergy `json:"namespaces"` // sorted by energy, highest first
+}
+
+type Opts struct {
+	logger         *slog.Logger
+	clock          clock.WithTicker
+	interval       time.Duration
+	sampleInterval time.Duration
+	topPods        int
+	tools          ToolRegistry
+	events         *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:         slog.Default(),
+		clock:          clock.RealClock{},
+		interval:       24 * time.Hour,
+		sampleInterval: 5 * time.Second,
+		topPods:        5,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Reporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to sample pods and end periods
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithInterval sets the length of the period of a report
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithSampleInterval sets the interval between samples of the energy of pods;
+// it should be the interval of the monitor
+func WithSampleInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.sampleInterval = d
+	}
+}
+
+// WithTopPods sets the number of pods consuming the most energy reported for
+// each namespace
+func WithTopPods(n int) OptionFn {
+	return func(o *Opts) {
+		o.topPods = n
+	}
+}
+
+// WithTools sets the registry the report is served by as an MCP tool
+func WithTools(t ToolRegistry) OptionFn {
+	return func(o *Opts) {
+		o.tools = t
+	}
+}
+
+// WithEventBus samples the energy of pods whenever the monitor publishes a new
+// snapshot on bus, rather than every sample interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// podJoules is the energy consumed by a pod in the current period
+type podJoules struct {
+	name, namespace string
+	joules          float64
+}
+
+// period accumulates the energy of the node and of pods since its start
+type period struct {
+	start      time.Time
+	zone       string
+	nodeJoules float64
+	pods       map[string]*podJoules // keyed by pod ID; pods that terminated are kept
+}
+
+// Reporter accumulates the energy consumed by pods in periods of fixed length
+// and logs a report of the energy of each namespace at the end of each period.
+// The report of the last period is also served at /chargeback and, if tools
+// are set, as an MCP tool
+type Reporter struct {
+	logger         *slog.Logger
+	monitor        Monitor
+	api            APIRegistry
+	tools          ToolRegistry
+	clock          clock.WithTicker
+	interval       time.Duration
+	sampleInterval time.Duration
+	events         *event.Bus
+	snapshots      *event.Subscription // nil if sampled every sample interval
+	topPods        int
+
+	mu      sync.Mutex
+	current period
+	last    *Report // report of the last complete period; nil until one ends
+
+	// cumulative energy of the node and pods at the last sample, to compute
+	// the energy consumed between samples
+	lastSnapshot time.Time
+	lastNode     monitor.Energy
+	lastPods     map[string]monitor.Energy
+}
+
+var (
+	_ service.Initializer = (*Reporter)(nil)
+	_ service.Runner      = (*Reporter)(nil)
+	_ service.Dependent   = (*Reporter)(nil)
+)
+
+// NewReporter creates a new Reporter of the pods of pm that serves the report
+// using api
+func NewReporter(pm Monitor, api APIRegistry, applyOpts ...OptionFn) *Reporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Reporter{
+		logger:         opts.logger.With("service", "chargeback"),
+		monitor:        pm,
+		api:            api,
+		tools:          opts.tools,
+		clock:          opts.clock,
+		interval:       opts.interval,
+		sampleInterval: opts.sampleInterval,
+		events:         opts.events,
+		topPods:        opts.topPods,
+		current:        period{start: opts.clock.Now(), pods: map[string]*podJoules{}},
+		lastPods:       map[string]monitor.Energy{},
+	}
+}
+
+func (r *Reporter) Name() string {
+	return "chargeback"
+}
+
+// Dependencies returns the monitor, and the API server and MCP tools the
+// report is served by
+func (r *Reporter) Dependencies() []service.Service {
+	deps := []service.Service{r.monitor}
+	if s, ok := r.api.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	if s, ok := r.tools.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (r *Reporter) Init() error {
+	if r.events != nil {
+		r.snapshots = r.events.Subscribe(r.Name(), 1, event.SnapshotReady)
+	}
+	if err := r.api.Register(Endpoint, "Chargeback", "Energy of the pods of each namespace in the last period (?namespace=prod)", http.HandlerFunc(r.handleReport)); err != nil {
+		return err
+	}
+	if r.tools == nil {
+		return nil
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"namespace": map[string]any{
+				"type":        "string",
+				"description": "Only report the energy of the namespace",
+			},
+		},
+	}
+	return r.tools.RegisterTool(ToolName,
+		"Energy in joules, average power in watts and share of the energy of the node consumed by the pods of each namespace "+
+			"in the last complete period, with the pods consuming the most energy",
+		schema, r.callTool)
+}
+
+// Run samples the energy of pods and logs the report at the end of each period
+// until ctx is cancelled
+func (r *Reporter) Run(ctx context.Context) error {
+	var ready <-chan event.Event
+	if r.snapshots != nil {
+		defer r.snapshots.Close()
+		ready = r.snapshots.C()
+	}
+	var tick <-chan time.Time
+	if ready == nil {
+		ticker := r.clock.NewTicker(r.sampleInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-ready:
+		}
+		r.sample()
+		if r.clock.Since(r.periodStart()) >= r.interval {
+			r.logReport(r.endPeriod())
+		}
+	}
+}
+
+func (r *Reporter) sample() {
+	snapshot, err := r.monitor.LatestSnapshot()
+	if err != nil {
+		r.logger.Warn("Failed to get snapshot", "error", err)
+		return
+	}
+	r.observe(snapshot)
+}
+
+// observe adds the energy the node and the running pods of snapshot consumed
+// since the previous snapshot to the current period. The energy of a pod first
+// seen is counted from its start, unless it was running when the reporter
+// started
+func (r *Reporter) observe(snapshot *monitor.Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !snapshot.Timestamp.After(r.lastSnapshot) || snapshot.Node == nil {
+		return // already observed
+	}
+	first := r.lastSnapshot.IsZero()
+	r.lastSnapshot = snapshot.Timestamp
+
+	if r.current.zone == "" {
+		r.current.zone = primaryZone(snapshot.Node)
+	}
+	var zone monitor.EnergyZone
+	for z, usage := range snapshot.Node.Zones {
+		if z.Name() != r.current.zone {
+			continue
+		}
+		zone = z
+		if !first && usage.EnergyTotal >= r.lastNode {
+			r.current.nodeJoules += (usage.EnergyTotal - r.lastNode).Joules()
+		}
+		r.lastNode = usage.EnergyTotal
+	}
+	if zone == nil {
+		return // the zone is no longer read
+	}
+
+	pods := make(map[string]monitor.Energy, len(snapshot.Pods))
+	for id, pod := range snapshot.Pods {
+		if id == monitor.OtherWorkload {
+			continue
+		}
+		total := pod.Zones[zone].EnergyTotal
+		pods[id] = total
+
+		var joules monitor.Energy
+		if last, ok := r.lastPods[id]; ok && total >= last {
+			joules = total - last
+		} else if !ok && !first {
+			joules = total
+		}
+
+		p, ok := r.current.pods[id]
+		if !ok {
+			p = &podJoules{}
+			r.current.pods[id] = p
+		}
+		p.name, p.namespace = pod.Name, pod.Namespace
+		p.joules += joules.Joules()
+	}
+	r.lastPods = pods
+}
+
+// primaryZone returns the name of the zone of the node that consumed the most
+// energy, e.g. psys or package, since zones overlap on some platforms
+func primaryZone(node *monitor.Node) string {
+	primary := ""
+	var energy monitor.Energy
+	for zone, usage := range node.Zones {
+		if primary == "" || usage.EnergyTotal > energy ||
+			(usage.EnergyTotal == energy && zone.Name() < primary) {
+			primary, energy = zone.Name(), usage.EnergyTotal
+		}
+	}
+	return primary
+}
+
+func (r *Reporter) periodStart() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current.start
+}
+
+// endPeriod ends the current period, keeps its report and starts a new period
+func (r *Reporter) endPeriod() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	report := r.report(&r.current, now, "")
+	report.Complete = true
+	r.last = &report
+	r.current = period{start: now, zone: r.current.zone, pods: map[string]*podJoules{}}
+	return report
+}
+
+// Report returns the report of the last complete period, or of the period in
+// progress if none has ended yet, of namespace or of all namespaces if
+// namespace is empty
+func (r *Reporter) Report(namespace string) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.last == nil {
+		return r.report(&r.current, r.clock.Now(), namespace)
+	}
+	report := *r.last
+	report.Namespaces = slices.DeleteFunc(slices.Clone(report.Namespaces), func(ns NamespaceEnergy) bool {
+		return namespace != "" && ns.Namespace != namespace
+	})
+	return report
+}
+
+// report returns the report of p ending at end
+func (r *Reporter) report(p *period, end time.Time, namespace string) Report {
+	seconds := end.Sub(p.start).Seconds()
+	avgWatts := func(joules float64) float64 {
+		if seconds <= 0 {
+			return 0
+		}
+		return joules / seconds
+	}
+
+	byNamespace := map[string]*NamespaceEnergy{}
+	for _, pod := range p.pods {
+		if namespace != "" && pod.namespace != namespace {
+			continue
+		}
+		ns, ok := byNamespace[pod.namespace]
+		if !ok {
+			ns = &NamespaceEnergy{Namespace: pod.namespace, TopPods: []PodEnergy{}}
+			byNamespace[pod.namespace] = ns
+		}
+		ns.Joules += pod.joules
+		ns.Pods++
+		ns.TopPods = append(ns.TopPods, PodEnergy{Pod: pod.name, Joules: pod.joules, AvgWatts: avgWatts(pod.joules)})
+	}
+
+	report := Report{
+		Start:      p.start,
+		End:        end,
+		Zone:       p.zone,
+		NodeJoules: p.nodeJoules,
+		Namespaces: make([]NamespaceEnergy, 0, len(byNamespace)),
+	}
+	for _, ns := range byNamespace {
+		ns.AvgWatts = avgWatts(ns.Joules)
+		if p.nodeJoules > 0 {
+			ns.NodeShare = ns.Joules / p.nodeJoules
+		}
+		slices.SortFunc(ns.TopPods, func(a, b PodEnergy) int {
+			return cmp.Or(cmp.Compare(b.Joules, a.Joules), cmp.Compare(a.Pod, b.Pod))
+		})
+		ns.TopPods = ns.TopPods[:min(len(ns.TopPods), r.topPods)]
+		report.Namespaces = append(report.Namespaces, *ns)
+	}
+	slices.SortFunc(report.Namespaces, func(a, b NamespaceEnergy) int {
+		return cmp.Or(cmp.Compare(b.Joules, a.Joules), cmp.Compare(a.Namespace, b.Namespace))
+	})
+	return report
+}
+
+func (r *Reporter) logReport(report Report) {
+	r.logger.Info("Energy of namespaces",
+		"start", report.Start,
+		"end", report.End,
+		"zone", report.Zone,
+		"node-joules", report.NodeJoules,
+		"namespaces", len(report.Namespaces))
+	for _, ns := range report.Namespaces {
+		r.logger.Info("Energy of namespace",
+			"namespace", ns.Namespace,
+			"joules", ns.Joules,
+			"avg-watts", ns.AvgWatts,
+			"node-share", ns.NodeShare,
+			"pods", ns.Pods)
+	}
+}
+
+// handleReport serves the report of all namespaces, or of the namespace query
+// parameter
+func (r *Reporter) handleReport(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(r.Report(req.URL.Query().Get("namespace"))); err != nil {
+		r.logger.Error("Failed to write report", "error", err)
+	}
+}
+
+func (r *Reporter) callTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		Namespace string `json:"namespace"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+	return r.Report(params.Namespace), nil
+}
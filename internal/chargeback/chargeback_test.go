@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package chargeback
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var (
+	pkg  = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:1", 1000*device.Joule)
+)
+
+// pod is the namespace, name and cumulative package energy of a pod
+type pod struct {
+	namespace, name string
+	joules          float64
+}
+
+// snapshot returns a snapshot at ts of the node and pods keyed by ID with their
+// cumulative energy in joules
+func snapshot(ts time.Time, nodeJoules float64, pods map[string]pod) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = ts
+	s.Node = &monitor.Node{Timestamp: ts, Zones: monitor.NodeZoneUsageMap{
+		pkg:  {EnergyTotal: monitor.Energy(nodeJoules) * device.Joule},
+		dram: {EnergyTotal: 10 * device.Joule},
+	}}
+	for id, p := range pods {
+		s.Pods[id] = &monitor.Pod{ID: id, Name: p.name, Namespace: p.namespace, Zones: monitor.ZoneUsageMap{
+			pkg:  {EnergyTotal: monitor.Energy(p.joules) * device.Joule},
+			dram: {EnergyTotal: device.Joule},
+		}}
+	}
+	return s
+}
+
+func TestReporter(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	registry := server.NewFakeRegistry()
+	pm := monitor.NewFakeMonitor(nil)
+	r := NewReporter(pm, registry, WithClock(fakeClock), WithInterval(time.Hour), WithTopPods(1), WithTools(registry))
+	assert.Equal(t, "chargeback", r.Name())
+	assert.Equal(t, "monitor", r.Dependencies()[0].Name())
+	require.NoError(t, r.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+	require.Contains(t, registry.Tools, ToolName)
+
+	// the energy pods consumed before the reporter started isn't counted
+	r.observe(snapshot(start, 1000, map[string]pod{
+		"api-1": {"prod", "api-1", 100},
+		"db-0":  {"prod", "db-0", 200},
+	}))
+	r.observe(snapshot(start.Add(30*time.Minute), 2800, map[string]pod{
+		"api-1": {"prod", "api-1", 400},
+		"db-0":  {"prod", "db-0", 300},
+		"job":   {"dev", "job", 180},
+	}))
+	// api-1 and job terminate; a pod started since is counted from its start
+	r.observe(snapshot(start.Add(time.Hour), 4600, map[string]pod{
+		"db-0":  {"prod", "db-0", 1200},
+		"api-2": {"prod", "api-2", 360},
+	}))
+
+	fakeClock.SetTime(start.Add(time.Hour))
+	partial := r.Report("")
+	assert.False(t, partial.Complete, "no period has ended yet")
+
+	report := r.endPeriod()
+	assert.Equal(t, Report{
+		Start:      start,
+		End:        start.Add(time.Hour),
+		Complete:   true,
+		Zone:       "package",
+		NodeJoules: 3600,
+		Namespaces: []NamespaceEnergy{
+			{Namespace: "prod", Joules: 1660, AvgWatts: 1660.0 / 3600, NodeShare: 1660.0 / 3600, Pods: 3,
+				TopPods: []PodEnergy{{Pod: "db-0", Joules: 1000, AvgWatts: 1000.0 / 3600}}},
+			{Namespace: "dev", Joules: 180, AvgWatts: 0.05, NodeShare: 0.05, Pods: 1,
+				TopPods: []PodEnergy{{Pod: "job", Joules: 180, AvgWatts: 0.05}}},
+		},
+	}, report)
+
+	// the next period starts empty
+	r.observe(snapshot(start.Add(90*time.Minute), 5000, map[string]pod{"db-0": {"prod", "db-0", 1300}}))
+	assert.Equal(t, report, r.Report(""), "the report of the last complete period is served")
+
+	t.Run("REST", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/chargeback?namespace=dev", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got Report
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		require.Len(t, got.Namespaces, 1)
+		assert.Equal(t, 180.0, got.Namespaces[0].Joules)
+		assert.Len(t, r.Report("").Namespaces, 2, "filtering doesn't change the kept report")
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/chargeback", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("MCP tool", func(t *testing.T) {
+		got, err := registry.Tools[ToolName](context.Background(), json.RawMessage(`{"namespace":"prod"}`))
+		require.NoError(t, err)
+		require.Len(t, got.(Report).Namespaces, 1)
+		assert.Equal(t, "prod", got.(Report).Namespaces[0].Namespace)
+	})
+
+	t.Run("next period", func(t *testing.T) {
+		fakeClock.SetTime(start.Add(2 * time.Hour))
+		report := r.endPeriod()
+		assert.Equal(t, start.Add(time.Hour), report.Start)
+		assert.Equal(t, 400.0, report.NodeJoules)
+		require.Len(t, report.Namespaces, 1)
+		assert.Equal(t, 100.0, report.Namespaces[0].Joules)
+	})
+}
+
+func TestReporterEvents(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	bus := event.NewBus()
+	pm := monitor.NewFakeMonitor(snapshot(start, 1000, map[string]pod{"db-0": {"prod", "db-0", 100}}))
+	r := NewReporter(pm, server.NewFakeRegistry(), WithEventBus(bus))
+	require.NoError(t, r.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+	bus.Publish(event.Event{Kind: event.SnapshotReady})
+	assert.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.lastSnapshot.Equal(start)
+	}, time.Second, time.Millisecond, "the pods are sampled when a snapshot is ready")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
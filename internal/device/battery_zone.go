@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/clock"
+)
+
+// powerSupply is a power supply in /sys/class/power_supply, as read by UPower
+type powerSupply struct {
+	name string
+	dir  string
+}
+
+// readPowerSupplyString reads an attribute of a power supply
+func readPowerSupplyString(dir, attr string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, attr))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readPowerSupplyInt reads a signed attribute of a power supply; some drivers
+// report the current and power of a discharging battery as negative
+func readPowerSupplyInt(dir, attr string) (int64, error) {
+	s, err := readPowerSupplyString(dir, attr)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s: %w", filepath.Join(dir, attr), err)
+	}
+	return v, nil
+}
+
+// powerSupplies returns the batteries and AC adapters of the system; batteries
+// of peripherals, e.g. of a wireless mouse, are not those of the system
+func powerSupplies(sysfsPath string) (batteries, adapters []powerSupply, err error) {
+	base := filepath.Join(sysfsPath, "class", "power_supply")
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		ps := powerSupply{name: entry.Name(), dir: filepath.Join(base, entry.Name())}
+		if scope, err := readPowerSupplyString(ps.dir, "scope"); err == nil && scope == "Device" {
+			continue
+		}
+		typ, err := readPowerSupplyString(ps.dir, "type")
+		if err != nil {
+			continue
+		}
+		switch typ {
+		case "Battery":
+			batteries = append(batteries, ps)
+		case "Mains", "USB":
+			if _, err := os.Stat(filepath.Join(ps.dir, "online")); err == nil {
+				adapters = append(adapters, ps)
+			}
+		}
+	}
+	return batteries, adapters, nil
+}
+
+// batteryPower returns the power a battery discharges at, from power_now or,
+// if the battery doesn't report it, from current_now and voltage_now; ok is
+// false if the battery is not discharging
+func batteryPower(battery powerSupply) (power Power, ok bool, err error) {
+	status, err := readPowerSupplyString(battery.dir, "status")
+	if err != nil {
+		return 0, false, err
+	}
+	if status != "Discharging" {
+		return 0, false, nil
+	}
+
+	if uw, err := readPowerSupplyInt(battery.dir, "power_now"); err == nil {
+		return Power(abs64(uw)) * MicroWatt, true, nil
+	}
+	ua, err := readPowerSupplyInt(battery.dir, "current_now")
+	if err != nil {
+		return 0, false, err
+	}
+	uv, err := readPowerSupplyInt(battery.dir, "voltage_now")
+	if err != nil {
+		return 0, false, err
+	}
+	// µA * µV = pW
+	return Power(float64(abs64(ua))*float64(abs64(uv))/1e6) * MicroWatt, true, nil
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// NewBatteryPowerZone creates a zone of the given name whose power is the rate
+// the batteries of the system discharge at, read from power_supply in sysfs as
+// UPower does, e.g. the full power of a laptop of which RAPL only measures the
+// SoC. The power can only be read while the system runs on battery; on AC
+// power the batteries don't supply it, and reads fail with ErrTransient.
+func NewBatteryPowerZone(name, sysfsPath string, c clock.PassiveClock) (*PowerZone, error) {
+	batteries, adapters, err := powerSupplies(sysfsPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(batteries) == 0 {
+		return nil, Errorf(ErrUnsupportedHardware, "no battery found in %s", filepath.Join(sysfsPath, "class", "power_supply"))
+	}
+
+	dirs := make([]string, len(batteries))
+	for i, battery := range batteries {
+		dirs[i] = battery.dir
+	}
+	read := func() (Power, error) {
+		for _, adapter := range adapters {
+			online, err := readPowerSupplyInt(adapter.dir, "online")
+			if err != nil {
+				return 0, err
+			}
+			if online != 0 {
+				return 0, Errorf(ErrTransient, "on AC power (%s); the batteries don't supply the system", adapter.name)
+			}
+		}
+
+		var total Power
+		discharging := false
+		for _, battery := range batteries {
+			power, ok, err := batteryPower(battery)
+			if err != nil {
+				return 0, fmt.Errorf("failed to read battery %s: %w", battery.name, err)
+			}
+			if ok {
+				total += power
+				discharging = true
+			}
+		}
+		if !discharging {
+			return 0, Errorf(ErrTransient, "no battery is discharging")
+		}
+		return total, nil
+	}
+	return NewPowerZone(name, 0, strings.Join(dirs, ","), read, c), nil
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// writePowerSupply writes the attributes of a power supply in sysfs
+func writePowerSupply(t *testing.T, sysfs, name string, attrs map[string]string) {
+	t.Helper()
+	dir := filepath.Join(sysfs, "class", "power_supply", name)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for attr, value := range attrs {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, attr), []byte(value+"\n"), 0o644))
+	}
+}
+
+func TestBatteryPowerZone(t *testing.T) {
+	sysfs := t.TempDir()
+	writePowerSupply(t, sysfs, "AC", map[string]string{"type": "Mains", "online": "0"})
+	writePowerSupply(t, sysfs, "BAT0", map[string]string{"type": "Battery", "status": "Discharging", "power_now": "12000000"})
+	// reports the current and voltage, negative while discharging
+	writePowerSupply(t, sysfs, "BAT1", map[string]string{
+		"type": "Battery", "status": "Discharging", "current_now": "-500000", "voltage_now": "12000000",
+	})
+	// the battery of a wireless mouse
+	writePowerSupply(t, sysfs, "hidpp_battery_0", map[string]string{
+		"type": "Battery", "scope": "Device", "status": "Discharging", "power_now": "1000000",
+	})
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	zone, err := NewBatteryPowerZone("platform", sysfs, fakeClock)
+	require.NoError(t, err)
+	base := filepath.Join(sysfs, "class", "power_supply")
+	assert.Equal(t, filepath.Join(base, "BAT0")+","+filepath.Join(base, "BAT1"), zone.Path())
+
+	_, err = zone.Energy()
+	require.NoError(t, err)
+	fakeClock.Step(10 * time.Second)
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 180.0, energy.Joules(), 0.001, "12 W + 0.5 A * 12 V for 10s")
+
+	t.Run("on AC power", func(t *testing.T) {
+		writePowerSupply(t, sysfs, "AC", map[string]string{"online": "1"})
+		_, err := zone.Energy()
+		assert.ErrorIs(t, err, ErrTransient)
+		assert.ErrorContains(t, err, "on AC power (AC)")
+		writePowerSupply(t, sysfs, "AC", map[string]string{"online": "0"})
+	})
+
+	t.Run("not discharging", func(t *testing.T) {
+		writePowerSupply(t, sysfs, "BAT0", map[string]string{"status": "Full"})
+		writePowerSupply(t, sysfs, "BAT1", map[string]string{"status": "Not charging"})
+		_, err := zone.Energy()
+		assert.ErrorIs(t, err, ErrTransient)
+		assert.ErrorContains(t, err, "no battery is discharging")
+	})
+
+	t.Run("no battery", func(t *testing.T) {
+		sysfs := t.TempDir()
+		writePowerSupply(t, sysfs, "AC", map[string]string{"type": "Mains", "online": "1"})
+		_, err := NewBatteryPowerZone("platform", sysfs, fakeClock)
+		assert.ErrorIs(t, err, ErrUnsupportedHardware)
+
+		_, err = NewBatteryPowerZone("platform", t.TempDir(), fakeClock)
+		assert.ErrorIs(t, err, ErrUnsupportedHardware)
+	})
+}
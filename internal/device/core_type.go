@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Core types of hybrid CPUs
+const (
+	CoreTypePerformance = "performance" // P-cores
+	CoreTypeEfficiency  = "efficiency"  // E-cores
+)
+
+// corePMUs are the PMUs the kernel registers for each core type of Intel hybrid
+// CPUs, e.g. Alder Lake, listing the CPUs of that type
+var corePMUs = map[string]string{
+	"cpu_core": CoreTypePerformance,
+	"cpu_atom": CoreTypeEfficiency,
+}
+
+// CPUCoreTypes reads the core type of each CPU of a hybrid CPU from sysfs and
+// returns an error if the CPU is not hybrid
+func CPUCoreTypes(sysfsPath string) (map[int]string, error) {
+	coreTypes := map[int]string{}
+	for pmu, coreType := range corePMUs {
+		path := filepath.Join(sysfsPath, "devices", pmu, "cpus")
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read CPUs of %s: %w", pmu, err)
+		}
+
+		cpus, err := parseCPUList(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPUs of %s: %w", pmu, err)
+		}
+		for _, cpu := range cpus {
+			coreTypes[cpu] = coreType
+		}
+	}
+	if len(coreTypes) == 0 {
+		return nil, fmt.Errorf("no hybrid CPU found in %s", sysfsPath)
+	}
+	return coreTypes, nil
+}
+
+// parseCPUList parses a list of CPUs in the format of sysfs, e.g. 0-7,16,18-19
+func parseCPUList(list string) ([]int, error) {
+	var cpus []int
+	if list == "" {
+		return cpus, nil
+	}
+	for _, r := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(r, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU %q", first)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil || end < start {
+				return nil, fmt.Errorf("invalid CPU range %q", r)
+			}
+		}
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
@@ -26,6 +26,36 @@ type EnergyZone interface {
 	MaxEnergy() Energy
 }
 
+// DefaultUncertainty is the relative uncertainty of the power estimated by
+// models whose error is unknown
+const DefaultUncertainty = 0.5
+
+// UncertainZone is implemented by zones whose power is estimated rather than
+// measured, e.g. with a model
+type UncertainZone interface {
+	// Uncertainty returns the relative uncertainty of the power of the zone,
+	// e.g. 0.2 if it is estimated within ±20% of the actual power
+	Uncertainty() float64
+}
+
+// Uncertainty returns the relative uncertainty of the power of zone; 0 if its
+// power is measured
+func Uncertainty(zone EnergyZone) float64 {
+	if z, ok := zone.(UncertainZone); ok {
+		return z.Uncertainty()
+	}
+	return 0
+}
+
+// EnergyRestorer is implemented by zones whose energy is integrated by Kepler
+// rather than read from a counter of the hardware, and so restarts from zero
+// with Kepler unless restored
+type EnergyRestorer interface {
+	// RestoreEnergy sets the energy of the zone, e.g. to the energy of a
+	// previous run of Kepler; it has no effect once the zone was read
+	RestoreEnergy(energy Energy)
+}
+
 // CPUPowerMeter implements powerMeter
 type CPUPowerMeter interface {
 	powerMeter
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CPUIdleState is an idle state (C-state) of a CPU and the time the CPU spent
+// in it since boot
+type CPUIdleState struct {
+	Name      string
+	Residency time.Duration
+}
+
+// CPUState holds the current frequency of a CPU and the residency of its idle
+// states
+type CPUState struct {
+	CPU       int
+	Frequency float64 // in Hz; 0 if cpufreq is unavailable
+
+	// IdleStates are ordered from the shallowest to the deepest; empty if
+	// cpuidle is unavailable
+	IdleStates []CPUIdleState
+}
+
+// DeepIdleResidency returns the time the CPU spent in idle states deeper than
+// the shallowest one (usually POLL or WFI), where its power drops the most
+func (s CPUState) DeepIdleResidency() time.Duration {
+	var total time.Duration
+	for i := 1; i < len(s.IdleStates); i++ {
+		total += s.IdleStates[i].Residency
+	}
+	return total
+}
+
+// ReadCPUStates reads the frequency and idle state residency of all CPUs from
+// cpufreq and cpuidle in sysfs, ordered by CPU; either may be unavailable, as
+// is common in VMs
+func ReadCPUStates(sysfsPath string) ([]CPUState, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysfsPath, "devices", "system", "cpu", "cpu[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no cpus found in %s", sysfsPath)
+	}
+
+	states := make([]CPUState, 0, len(dirs))
+	for _, dir := range dirs {
+		cpu, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "cpu"))
+		if err != nil {
+			continue
+		}
+
+		state := CPUState{CPU: cpu}
+		if khz, err := readUint(filepath.Join(dir, "cpufreq", "scaling_cur_freq")); err == nil {
+			state.Frequency = float64(khz) * 1e3
+		}
+		if state.IdleStates, err = readIdleStates(dir); err != nil {
+			return nil, fmt.Errorf("failed to read idle states of cpu%d: %w", cpu, err)
+		}
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].CPU < states[j].CPU })
+	return states, nil
+}
+
+// readIdleStates reads the idle states of the CPU at cpuDir; nil if cpuidle is
+// unavailable
+func readIdleStates(cpuDir string) ([]CPUIdleState, error) {
+	dirs, err := filepath.Glob(filepath.Join(cpuDir, "cpuidle", "state[0-9]*"))
+	if err != nil || len(dirs) == 0 {
+		return nil, err
+	}
+
+	type indexed struct {
+		index int
+		state CPUIdleState
+	}
+	states := make([]indexed, 0, len(dirs))
+	for _, dir := range dirs {
+		index, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "state"))
+		if err != nil {
+			continue
+		}
+		name, err := os.ReadFile(filepath.Join(dir, "name"))
+		if err != nil {
+			return nil, err
+		}
+		usec, err := readUint(filepath.Join(dir, "time"))
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, indexed{index: index, state: CPUIdleState{
+			Name:      strings.TrimSpace(string(name)),
+			Residency: time.Duration(usec) * time.Microsecond,
+		}})
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].index < states[j].index })
+	ret := make([]CPUIdleState, len(states))
+	for i, s := range states {
+		ret[i] = s.state
+	}
+	return ret, nil
+}
+
+// readUint reads an unsigned integer from a sysfs file
+func readUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s: %w", path, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCPUStates(t *testing.T) {
+	sysfs := t.TempDir()
+	writeCPUFreq(t, sysfs, 2_000_000, 3_500_000)
+	writeCPUIdle(t, sysfs, 500, 700)
+
+	// cpu10 sorts after cpu1 and has neither cpufreq nor cpuidle
+	require.NoError(t, os.MkdirAll(filepath.Join(sysfs, "devices", "system", "cpu", "cpu10"), 0o755))
+
+	states, err := ReadCPUStates(sysfs)
+	require.NoError(t, err)
+	require.Len(t, states, 3)
+
+	assert.Equal(t, 0, states[0].CPU)
+	assert.Equal(t, 2e9, states[0].Frequency)
+	assert.Equal(t, []CPUIdleState{{Name: "POLL"}, {Name: "C6", Residency: 500 * time.Microsecond}}, states[0].IdleStates)
+	assert.Equal(t, 500*time.Microsecond, states[0].DeepIdleResidency())
+
+	assert.Equal(t, 3.5e9, states[1].Frequency)
+	assert.Equal(t, 700*time.Microsecond, states[1].DeepIdleResidency())
+
+	assert.Equal(t, CPUState{CPU: 10}, states[2])
+
+	ghz, ok := averageFrequency(states)
+	assert.True(t, ok)
+	assert.InDelta(t, 2.75, ghz, 0.001)
+
+	_, err = ReadCPUStates(t.TempDir())
+	assert.ErrorContains(t, err, "no cpus found")
+}
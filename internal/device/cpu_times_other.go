@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package device
+
+import "github.com/prometheus/procfs"
+
+// newCPUTimesReader returns a reader of the CPU time of the node from the stat
+// file of the procfs mounted at procfsPath
+func newCPUTimesReader(procfsPath string) (cpuTimesReader, error) {
+	fs, err := procfs.NewFS(procfsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (cpuTimes, error) {
+		stat, err := fs.Stat()
+		if err != nil {
+			return cpuTimes{}, err
+		}
+
+		cpu := stat.CPUTotal
+		idle := cpu.Idle + cpu.Iowait
+		busy := cpu.User + cpu.Nice + cpu.System + cpu.IRQ + cpu.SoftIRQ + cpu.Steal
+		return cpuTimes{busy: busy, total: busy + idle}, nil
+	}, nil
+}
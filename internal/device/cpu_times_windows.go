@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package device
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetSystemTimes = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetSystemTimes")
+
+// newCPUTimesReader returns a reader of the CPU time of the node from
+// GetSystemTimes; there is no procfs on windows
+func newCPUTimesReader(string) (cpuTimesReader, error) {
+	if err := procGetSystemTimes.Find(); err != nil {
+		return nil, Errorf(ErrUnsupportedHardware, "failed to find GetSystemTimes: %v", err)
+	}
+	return readSystemTimes, nil
+}
+
+// readSystemTimes reads the CPU time of all CPUs; the kernel time includes the
+// idle time
+func readSystemTimes() (cpuTimes, error) {
+	var idle, kernel, user windows.Filetime
+	ok, _, err := procGetSystemTimes.Call(
+		uintptr(unsafe.Pointer(&idle)),
+		uintptr(unsafe.Pointer(&kernel)),
+		uintptr(unsafe.Pointer(&user)),
+	)
+	if ok == 0 {
+		return cpuTimes{}, err
+	}
+
+	total := filetimeSeconds(kernel) + filetimeSeconds(user)
+	return cpuTimes{busy: total - filetimeSeconds(idle), total: total}, nil
+}
+
+// filetimeSeconds returns a duration in 100 ns intervals as seconds
+func filetimeSeconds(ft windows.Filetime) float64 {
+	return float64(uint64(ft.HighDateTime)<<32|uint64(ft.LowDateTime)) / 1e7
+}
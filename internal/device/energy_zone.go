@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
 )
 
 type Zone = string
@@ -36,8 +39,10 @@ type AggregatedZone struct {
 	index         int
 	zones         []EnergyZone
 	lastReadings  map[zoneKey]Energy
-	currentEnergy Energy // Aggregated energy counter
-	maxEnergy     Energy // Cached sum of all zone MaxEnergy values
+	lastRead      time.Time // time of the last readings; zero if never read
+	currentEnergy Energy    // Aggregated energy counter
+	maxEnergy     Energy    // Cached sum of all zone MaxEnergy values
+	clock         clock.PassiveClock
 	mu            sync.RWMutex
 }
 
@@ -73,6 +78,7 @@ func NewAggregatedZone(zones []EnergyZone) *AggregatedZone {
 		lastReadings:  make(map[zoneKey]Energy),
 		currentEnergy: 0,
 		maxEnergy:     totalMax, // Cache the combined MaxEnergy
+		clock:         clock.RealClock{},
 	}
 }
 
@@ -93,53 +99,44 @@ func (az *AggregatedZone) Path() string {
 }
 
 // Energy returns the total energy consumption across all aggregated zones,
-// handling wrap-around for each individual zone
+// handling wrap-around for each individual zone. All zones are read before the
+// counter is updated, so that a zone that can't be read doesn't lose the
+// energy of the others.
 func (az *AggregatedZone) Energy() (Energy, error) {
 	az.mu.Lock()
 	defer az.mu.Unlock()
 
-	var totalDelta Energy
-
-	for _, zone := range az.zones {
-		currentReading, err := zone.Energy()
+	readings := make([]Energy, len(az.zones))
+	for i, zone := range az.zones {
+		reading, err := zone.Energy()
 		if err != nil {
 			return 0, fmt.Errorf("no valid energy readings from aggregated zones - %s: %w", zone.Name(), err)
 		}
+		readings[i] = reading
+	}
 
-		zoneID := zoneKey{zone.Name(), zone.Index()}
+	now := az.clock.Now()
+	maxDelta := MaxEnergyDelta(now.Sub(az.lastRead))
+	az.lastRead = now
 
+	var totalDelta Energy
+	for i, zone := range az.zones {
+		zoneID := zoneKey{zone.Name(), zone.Index()}
 		if lastReading, exists := az.lastReadings[zoneID]; exists {
-
-			// Calculate delta since last reading
-			var delta Energy
-			if currentReading >= lastReading {
-				// Normal case: no wrap
-				delta = currentReading - lastReading
-			} else {
-				// Wrap occurred: calculate delta across wrap boundary
-				// Only if zone has valid MaxEnergy (> 0)
-				if zone.MaxEnergy() > 0 {
-					delta = (zone.MaxEnergy() - lastReading) + currentReading
-				} else {
-					// Invalid MaxEnergy, treat as normal delta (might be negative)
-					delta = currentReading - lastReading
-				}
-			}
+			delta, _ := EnergyDelta(readings[i], lastReading, zone.MaxEnergy(), maxDelta)
 			totalDelta += delta
 		} else {
 			// First reading: use current reading as initial energy
-			totalDelta += currentReading
+			totalDelta += readings[i]
 		}
-
-		// Update last reading
-		az.lastReadings[zoneID] = currentReading
+		az.lastReadings[zoneID] = readings[i]
 	}
 
 	// Update aggregated energy counter
 	az.currentEnergy += totalDelta
 
 	// Wrap at maxEnergy boundary to match hardware counter behavior
-	// This is required for the power attribution algorithm's calculateEnergyDelta()
+	// This is required for the power attribution algorithm's EnergyDelta()
 	if az.maxEnergy > 0 {
 		az.currentEnergy %= az.maxEnergy
 	}
@@ -152,3 +149,56 @@ func (az *AggregatedZone) Energy() (Energy, error) {
 func (az *AggregatedZone) MaxEnergy() Energy {
 	return az.maxEnergy
 }
+
+// CounterChange is how the energy counter of a zone changed between readings
+type CounterChange int
+
+const (
+	// CounterIncreased counters increased
+	CounterIncreased CounterChange = iota
+	// CounterWrapped counters wrapped around to 0 after their max energy
+	CounterWrapped
+	// CounterJumped counters changed by more energy than a zone can consume
+	// between the readings, or decreased without a max energy, e.g. as they
+	// were reset or read inconsistently; the energy is unknown and is
+	// discarded
+	CounterJumped
+)
+
+// MaxPower is the most power a zone can draw, above that of the largest
+// servers; a counter changing faster is not read consistently
+const MaxPower = 20_000 * Watt
+
+// MaxEnergyDelta returns the most energy a zone can consume in elapsed, drawing
+// MaxPower; 0, i.e. unbounded, if elapsed is not positive
+func MaxEnergyDelta(elapsed time.Duration) Energy {
+	maxDelta := MaxPower.MicroWatts() * elapsed.Seconds()
+	if elapsed <= 0 || maxDelta >= math.MaxUint64 {
+		return 0
+	}
+	return Energy(maxDelta)
+}
+
+// EnergyDelta returns the energy consumed between two readings of a counter
+// that wraps around to 0 after maxEnergy, and how the counter changed. A change
+// by more than maxDelta, the most energy the zone can consume between the
+// readings (see MaxEnergyDelta), is impossible and reported as a jump; if
+// maxDelta is 0, any change within the range of the counter is possible.
+func EnergyDelta(current, previous, maxEnergy, maxDelta Energy) (Energy, CounterChange) {
+	if current >= previous {
+		delta := current - previous
+		if maxDelta > 0 && delta > maxDelta {
+			return 0, CounterJumped
+		}
+		return delta, CounterIncreased
+	}
+
+	if maxEnergy == 0 || previous > maxEnergy {
+		return 0, CounterJumped
+	}
+	delta := (maxEnergy - previous) + current
+	if maxDelta > 0 && delta > maxDelta {
+		return 0, CounterJumped
+	}
+	return delta, CounterWrapped
+}
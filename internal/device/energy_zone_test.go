@@ -8,9 +8,11 @@ import (
 	"math"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
 // mockEnergyZone implements EnergyZone for testing
@@ -156,12 +158,17 @@ func TestAggregatedZone_WrapHandling(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, Energy(100), energy2) // 1100 % 1000 = 100
 
-		// Second wrap: 100 -> 50 (delta: 950)
-		// Total: 100 + 950 = 1050, wraps to 50 (1050 % 1000 = 50)
-		zone.SetEnergy(50)
+		zone.SetEnergy(600)
 		energy3, err := az.Energy()
 		require.NoError(t, err)
-		assert.Equal(t, Energy(50), energy3) // 1050 % 1000 = 50
+		assert.Equal(t, Energy(600), energy3)
+
+		// Second wrap: 600 -> 50 (delta: 450)
+		// Total: 600 + 450 = 1050, wraps to 50 (1050 % 1000 = 50)
+		zone.SetEnergy(50)
+		energy4, err := az.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, Energy(50), energy4) // 1050 % 1000 = 50
 	})
 
 	t.Run("BackwardReading", func(t *testing.T) {
@@ -170,18 +177,35 @@ func TestAggregatedZone_WrapHandling(t *testing.T) {
 		zones := []EnergyZone{zone}
 
 		az := NewAggregatedZone(zones)
+		fakeClock := testingclock.NewFakePassiveClock(time.Now())
+		az.clock = fakeClock
 
 		energy1, err := az.Energy()
 		require.NoError(t, err)
 		assert.Equal(t, Energy(500), energy1)
 
-		// Simulate backward reading (treated as wrap)
+		// Simulate backward reading; a wrap would be (1000-500) + 400 = 900µJ,
+		// more than the 200µJ a zone can consume in 10ns, so it is a jump and
+		// no energy is counted
+		fakeClock.SetTime(fakeClock.Now().Add(10 * time.Nanosecond))
 		zone.SetEnergy(400)
 		energy2, err := az.Energy()
 		require.NoError(t, err)
-		// Treated as wrap: (1000-500) + 400 = 900, total = 500 + 900 = 1400
-		// Wraps: 1400 % 1000 = 400
-		assert.Equal(t, Energy(400), energy2)
+		assert.Equal(t, Energy(500), energy2)
+
+		// energy is counted from the reading after the jump
+		fakeClock.SetTime(fakeClock.Now().Add(10 * time.Nanosecond))
+		zone.SetEnergy(450)
+		energy3, err := az.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, Energy(550), energy3)
+
+		// a wrap of 900µJ is possible in 1s
+		fakeClock.SetTime(fakeClock.Now().Add(time.Second))
+		zone.SetEnergy(350)
+		energy4, err := az.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, Energy(450), energy4, "550 + 900 wraps to 450")
 	})
 
 	t.Run("ZeroMaxEnergyHandling", func(t *testing.T) {
@@ -217,6 +241,28 @@ func TestAggregatedZone_ErrorHandling(t *testing.T) {
 		assert.Zero(t, energy)
 	})
 
+	t.Run("NoEnergyLostOnError", func(t *testing.T) {
+		zone0 := &mockEnergyZone{name: "package", index: 0, energy: 100, maxEnergy: 1000}
+		zone1 := &mockEnergyZone{name: "package", index: 1, energy: 200, maxEnergy: 1000}
+		az := NewAggregatedZone([]EnergyZone{zone0, zone1})
+
+		energy, err := az.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, Energy(300), energy)
+
+		// the reading of zone0 is not used while zone1 can't be read
+		zone0.SetEnergy(150)
+		zone1.err = fmt.Errorf("read error")
+		_, err = az.Energy()
+		require.Error(t, err)
+
+		zone0.SetEnergy(160)
+		zone1.err = nil
+		energy, err = az.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, Energy(360), energy, "energy of zone0 since its last use is counted")
+	})
+
 	t.Run("AllZonesError", func(t *testing.T) {
 		zones := []EnergyZone{
 			&mockEnergyZone{name: "package", index: 0, energy: 100, maxEnergy: 1000, err: fmt.Errorf("error1")},
@@ -611,3 +657,45 @@ func TestAggregatedZone_EdgeCases(t *testing.T) {
 		assert.Equal(t, expected2, energy2)
 	})
 }
+
+func TestEnergyDelta(t *testing.T) {
+	tt := []struct {
+		name      string
+		current   Energy
+		previous  Energy
+		maxEnergy Energy
+		maxDelta  Energy
+		delta     Energy
+		change    CounterChange
+	}{
+		{name: "increase", current: 25 * Joule, previous: 20 * Joule, maxEnergy: 100 * Joule, delta: 5 * Joule},
+		{name: "wrap around", current: 10 * Joule, previous: 90 * Joule, maxEnergy: 100 * Joule, delta: 20 * Joule, change: CounterWrapped},
+		{name: "zero values", maxEnergy: 100 * Joule},
+		{name: "decrease without max", current: 10 * Joule, previous: 20 * Joule, change: CounterJumped},
+		{name: "increase without max", current: 20 * Joule, previous: 10 * Joule, delta: 10 * Joule},
+		{name: "small wrap", current: 2 * Joule, previous: 8 * Joule, maxEnergy: 10 * Joule, delta: 4 * Joule, change: CounterWrapped},
+		{name: "current equals max", current: 100 * Joule, previous: 90 * Joule, maxEnergy: 100 * Joule, delta: 10 * Joule},
+		{name: "previous equals max", current: 10 * Joule, previous: 100 * Joule, maxEnergy: 100 * Joule, delta: 10 * Joule, change: CounterWrapped},
+		{name: "exact wrap", previous: 100 * Joule, maxEnergy: 100 * Joule, change: CounterWrapped},
+		{name: "increase of more than half the range", current: 61 * Joule, previous: 10 * Joule, maxEnergy: 100 * Joule, delta: 51 * Joule},
+		{name: "increase within max delta", current: 61 * Joule, previous: 10 * Joule, maxEnergy: 100 * Joule, maxDelta: 51 * Joule, delta: 51 * Joule},
+		{name: "increase above max delta", current: 61 * Joule, previous: 10 * Joule, maxEnergy: 100 * Joule, maxDelta: 50 * Joule, change: CounterJumped},
+		{name: "wrap within max delta", current: 89 * Joule, previous: 90 * Joule, maxEnergy: 100 * Joule, maxDelta: 99 * Joule, delta: 99 * Joule, change: CounterWrapped},
+		{name: "slight decrease", current: 89 * Joule, previous: 90 * Joule, maxEnergy: 100 * Joule, maxDelta: 10 * Joule, change: CounterJumped},
+		{name: "previous above max", current: 10 * Joule, previous: 200 * Joule, maxEnergy: 100 * Joule, change: CounterJumped},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			delta, change := EnergyDelta(tc.current, tc.previous, tc.maxEnergy, tc.maxDelta)
+			assert.Equal(t, tc.delta, delta)
+			assert.Equal(t, tc.change, change)
+		})
+	}
+}
+
+func TestMaxEnergyDelta(t *testing.T) {
+	assert.Equal(t, 100_000*Joule, MaxEnergyDelta(5*time.Second), "20kW over 5s")
+	assert.Zero(t, MaxEnergyDelta(0))
+	assert.Zero(t, MaxEnergyDelta(-time.Second))
+	assert.Zero(t, MaxEnergyDelta(time.Duration(math.MaxInt64)), "unbounded on overflow")
+}
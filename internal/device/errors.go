@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"syscall"
+)
+
+// Kinds of errors reading devices; use errors.Is to check the kind of an error
+var (
+	// ErrPermission is returned when Kepler lacks the privileges to read a device
+	ErrPermission = errors.New("permission denied")
+
+	// ErrUnsupportedHardware is returned when a device or its counters are not
+	// present on the node
+	ErrUnsupportedHardware = errors.New("unsupported hardware")
+
+	// ErrTransient is returned for failures that may not recur on the next read
+	ErrTransient = errors.New("transient error")
+)
+
+// classifiedError is an error of a kind, with the message of the error
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// Errorf formats an error of kind, e.g. ErrUnsupportedHardware, without adding
+// the kind to the message
+func Errorf(kind error, format string, args ...any) error {
+	return &classifiedError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// Classify returns err as an error of one of the kinds above: missing devices
+// and files are unsupported hardware, denied access is a permission error and
+// all other errors are transient. Errors already of a kind are returned as is.
+func Classify(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrPermission), errors.Is(err, ErrUnsupportedHardware), errors.Is(err, ErrTransient):
+		return err
+	case errors.Is(err, fs.ErrPermission):
+		return &classifiedError{kind: ErrPermission, err: err}
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, exec.ErrNotFound),
+		errors.Is(err, syscall.ENODEV), errors.Is(err, syscall.EOPNOTSUPP):
+		return &classifiedError{kind: ErrUnsupportedHardware, err: err}
+	default:
+		return &classifiedError{kind: ErrTransient, err: err}
+	}
+}
+
+// ErrorKind returns the kind of err as a short string for logs and metrics:
+// permission, unsupported-hardware or transient; empty if err is nil
+func ErrorKind(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrPermission):
+		return "permission"
+	case errors.Is(err, ErrUnsupportedHardware):
+		return "unsupported-hardware"
+	default:
+		return "transient"
+	}
+}
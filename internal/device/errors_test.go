@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	_, notExist := os.ReadFile(filepath.Join(t.TempDir(), "energy_uj"))
+	permission := &os.PathError{Op: "open", Path: "energy_uj", Err: os.ErrPermission}
+	unsupported := Errorf(ErrUnsupportedHardware, "no RAPL zones found")
+
+	tt := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"missing file", notExist, ErrUnsupportedHardware},
+		{"permission denied", permission, ErrPermission},
+		{"wrapped permission denied", fmt.Errorf("failed to read: %w", permission), ErrPermission},
+		{"already classified", unsupported, ErrUnsupportedHardware},
+		{"other", errors.New("device busy"), ErrTransient},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Classify(tc.err)
+			assert.ErrorIs(t, err, tc.kind)
+			assert.ErrorIs(t, err, tc.err, "the error is kept")
+			assert.Equal(t, tc.err.Error(), err.Error(), "the message is kept")
+		})
+	}
+
+	assert.NoError(t, Classify(nil))
+}
+
+func TestErrorKind(t *testing.T) {
+	assert.Equal(t, "", ErrorKind(nil))
+	assert.Equal(t, "permission", ErrorKind(Classify(os.ErrPermission)))
+	assert.Equal(t, "unsupported-hardware", ErrorKind(Classify(os.ErrNotExist)))
+	assert.Equal(t, "transient", ErrorKind(Classify(errors.New("EIO"))))
+	assert.Equal(t, "transient", ErrorKind(errors.New("unclassified")))
+}
@@ -0,0 +1,285 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/utils/clock"
+)
+
+// EstimatedZoneName is the name of the zone reported by the estimated power meter
+const EstimatedZoneName = "estimated"
+
+// LinearModel estimates the power of the node (in watts) from the CPU
+// utilization (0 - 1), the average CPU frequency (in GHz) and the ratio of time
+// CPUs spent in deep idle states (0 - 1) as:
+//
+//	power = Intercept + Utilization × utilization + Frequency × frequency + DeepIdle × deep idle
+//
+// Uncertainty is the relative error of the model, e.g. its mean absolute
+// percentage error when it was fitted; DefaultUncertainty is assumed if it is 0.
+type LinearModel struct {
+	Intercept   float64 `yaml:"intercept"`
+	Utilization float64 `yaml:"utilization"`
+	Frequency   float64 `yaml:"frequency"`
+	DeepIdle    float64 `yaml:"deepIdle"`
+	Uncertainty float64 `yaml:"uncertainty"`
+}
+
+// Estimate returns the power estimated by the model; estimates are never negative
+func (m LinearModel) Estimate(utilization, frequencyGHz, deepIdle float64) Power {
+	watts := m.Intercept + m.Utilization*utilization + m.Frequency*frequencyGHz + m.DeepIdle*deepIdle
+	return Power(max(watts, 0)) * Watt
+}
+
+// LoadLinearModel reads the coefficients of a LinearModel from a YAML file
+func LoadLinearModel(path string) (LinearModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LinearModel{}, fmt.Errorf("failed to read model file: %w", err)
+	}
+
+	return parseLinearModel(data, path)
+}
+
+// parseLinearModel parses the coefficients of a LinearModel read from source
+func parseLinearModel(data []byte, source string) (LinearModel, error) {
+	var model LinearModel
+	if err := yaml.Unmarshal(data, &model); err != nil {
+		return LinearModel{}, fmt.Errorf("failed to parse model file %s: %w", source, err)
+	}
+	return model, nil
+}
+
+// cpuTimes holds the cumulative busy and total CPU time of the node
+type cpuTimes struct {
+	busy  float64
+	total float64
+}
+
+// cpuTimesReader reads the cumulative CPU time of the node from the OS, i.e.
+// /proc/stat on linux and GetSystemTimes on windows
+type cpuTimesReader func() (cpuTimes, error)
+
+// cpuIdle holds the time all CPUs spent in deep idle states
+type cpuIdle struct {
+	residency time.Duration
+	cpus      int       // CPUs with idle states
+	read      time.Time // when residency was read
+}
+
+// estimatedPowerMeter implements CPUPowerMeter for platforms without RAPL (e.g.
+// cloud VMs) by estimating power from the CPU utilization, frequency and idle
+// states
+type estimatedPowerMeter struct {
+	logger   *slog.Logger
+	cpuTimes cpuTimesReader
+	sysfs    string
+	model    LinearModel
+	clock    clock.PassiveClock
+
+	zone *estimatedZone
+
+	mu       sync.Mutex
+	prev     cpuTimes
+	prevIdle cpuIdle
+}
+
+var _ CPUPowerMeter = (*estimatedPowerMeter)(nil)
+
+// EstimatorOptFn is a functional option for configuring the estimated power meter
+type EstimatorOptFn func(*estimatedPowerMeter)
+
+// WithEstimatorLogger sets the logger for the estimated power meter
+func WithEstimatorLogger(logger *slog.Logger) EstimatorOptFn {
+	return func(m *estimatedPowerMeter) {
+		m.logger = logger.With("service", "estimator")
+	}
+}
+
+// WithEstimatorClock sets the clock used to integrate the estimated power
+func WithEstimatorClock(c clock.PassiveClock) EstimatorOptFn {
+	return func(m *estimatedPowerMeter) {
+		m.clock = c
+	}
+}
+
+// NewEstimatedCPUMeter creates a new CPU power meter that estimates power using
+// model; CPU utilization is read from procfs, or from the OS on windows, and
+// frequency and idle states from sysfs
+func NewEstimatedCPUMeter(procfsPath, sysfsPath string, model LinearModel, opts ...EstimatorOptFn) (*estimatedPowerMeter, error) {
+	readTimes, err := newCPUTimesReader(procfsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &estimatedPowerMeter{
+		logger:   slog.Default().With("service", "estimator"),
+		cpuTimes: readTimes,
+		sysfs:    sysfsPath,
+		model:    model,
+		clock:    clock.RealClock{},
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+
+	ret.zone = &estimatedZone{
+		PowerZone: NewPowerZone(EstimatedZoneName, 0, "model:linear", ret.estimate, ret.clock),
+		meter:     ret,
+	}
+	return ret, nil
+}
+
+func (m *estimatedPowerMeter) Name() string {
+	return "estimator"
+}
+
+func (m *estimatedPowerMeter) Init() error {
+	if _, err := m.readCPUTimes(); err != nil {
+		return err
+	}
+
+	if m.model.Frequency != 0 || m.model.DeepIdle != 0 {
+		states, err := ReadCPUStates(m.sysfs)
+		if err != nil {
+			m.logger.Warn("CPU frequency and idle states are unavailable; estimating power without them", "error", err)
+		}
+
+		// the first estimate is relative to the deep idle time read now
+		m.deepIdleRatio(states)
+
+		if _, ok := averageFrequency(states); err == nil && m.model.Frequency != 0 && !ok {
+			m.logger.Warn("CPU frequency is unavailable; estimating power without it")
+		}
+		if err == nil && m.model.DeepIdle != 0 && m.prevIdle.cpus == 0 {
+			m.logger.Warn("CPU idle states are unavailable; estimating power without them")
+		}
+	}
+
+	m.logger.Info("Estimating power using linear model",
+		"intercept", m.model.Intercept,
+		"utilization", m.model.Utilization,
+		"frequency", m.model.Frequency,
+		"deep-idle", m.model.DeepIdle)
+	return nil
+}
+
+func (m *estimatedPowerMeter) Zones() ([]EnergyZone, error) {
+	return []EnergyZone{m.zone}, nil
+}
+
+func (m *estimatedPowerMeter) PrimaryEnergyZone() (EnergyZone, error) {
+	return m.zone, nil
+}
+
+// estimate reads the CPU utilization and deep idle time since the previous
+// estimate and the current CPU frequency, and returns the power estimated by
+// the model
+func (m *estimatedPowerMeter) estimate() (Power, error) {
+	times, err := m.readCPUTimes()
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	model := m.model
+	utilization := 0.0
+	if total := times.total - m.prev.total; total > 0 {
+		utilization = min(max((times.busy-m.prev.busy)/total, 0), 1)
+	}
+	m.prev = times
+	m.mu.Unlock()
+
+	frequency, deepIdle := 0.0, 0.0
+	if model.Frequency != 0 || model.DeepIdle != 0 {
+		// frequency and idle states are often unavailable in VMs and are
+		// ignored in that case
+		states, _ := ReadCPUStates(m.sysfs)
+		frequency, _ = averageFrequency(states)
+		deepIdle = m.deepIdleRatio(states)
+	}
+
+	return model.Estimate(utilization, frequency, deepIdle), nil
+}
+
+// SetModel replaces the model power is estimated with from the next estimate
+func (m *estimatedPowerMeter) SetModel(model LinearModel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.model = model
+}
+
+// estimatedZone is the zone of the estimated power meter, whose uncertainty is
+// that of the model
+type estimatedZone struct {
+	*PowerZone
+	meter *estimatedPowerMeter
+}
+
+var _ UncertainZone = (*estimatedZone)(nil)
+
+func (z *estimatedZone) Uncertainty() float64 {
+	z.meter.mu.Lock()
+	defer z.meter.mu.Unlock()
+	if u := z.meter.model.Uncertainty; u > 0 {
+		return u
+	}
+	return DefaultUncertainty
+}
+
+// readCPUTimes reads the cumulative busy and total CPU time of the node
+func (m *estimatedPowerMeter) readCPUTimes() (cpuTimes, error) {
+	times, err := m.cpuTimes()
+	if err != nil {
+		return cpuTimes{}, fmt.Errorf("failed to read cpu stats: %w", err)
+	}
+	return times, nil
+}
+
+// deepIdleRatio returns the ratio of time CPUs spent in deep idle states since
+// the previous call; 0 if idle states are unavailable or CPUs went offline
+func (m *estimatedPowerMeter) deepIdleRatio(states []CPUState) float64 {
+	idle := cpuIdle{read: m.clock.Now()}
+	for _, s := range states {
+		if len(s.IdleStates) > 0 {
+			idle.residency += s.DeepIdleResidency()
+			idle.cpus++
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prev := m.prevIdle
+	m.prevIdle = idle
+
+	elapsed := idle.read.Sub(prev.read)
+	if idle.cpus == 0 || idle.cpus != prev.cpus || elapsed <= 0 {
+		return 0
+	}
+	ratio := float64(idle.residency-prev.residency) / float64(elapsed) / float64(idle.cpus)
+	return min(max(ratio, 0), 1)
+}
+
+// averageFrequency returns the average current frequency of the CPUs with
+// cpufreq in GHz and false if none has
+func averageFrequency(states []CPUState) (float64, bool) {
+	total, n := 0.0, 0
+	for _, s := range states {
+		if s.Frequency > 0 {
+			total += s.Frequency
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return total / float64(n) / 1e9, true
+}
@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// writeProcStat writes a /proc/stat with the given busy (user) and idle time in ticks
+func writeProcStat(t *testing.T, procfs string, busy, idle int) {
+	t.Helper()
+	stat := fmt.Sprintf("cpu  %d 0 0 %d 0 0 0 0 0 0\ncpu0 %d 0 0 %d 0 0 0 0 0 0\nbtime 1700000000\n", busy, idle, busy, idle)
+	require.NoError(t, os.WriteFile(filepath.Join(procfs, "stat"), []byte(stat), 0o644))
+}
+
+func writeCPUFreq(t *testing.T, sysfs string, khz ...int) {
+	t.Helper()
+	for cpu, f := range khz {
+		dir := filepath.Join(sysfs, "devices", "system", "cpu", fmt.Sprintf("cpu%d", cpu), "cpufreq")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "scaling_cur_freq"), []byte(fmt.Sprintf("%d\n", f)), 0o644))
+	}
+}
+
+// writeCPUIdle writes the deep idle (state1) residency of each CPU in µs
+func writeCPUIdle(t *testing.T, sysfs string, usec ...int) {
+	t.Helper()
+	for cpu, u := range usec {
+		for state, name := range []string{"POLL", "C6"} {
+			dir := filepath.Join(sysfs, "devices", "system", "cpu", fmt.Sprintf("cpu%d", cpu), "cpuidle", fmt.Sprintf("state%d", state))
+			require.NoError(t, os.MkdirAll(dir, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "name"), []byte(name+"\n"), 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "time"), []byte(fmt.Sprintf("%d\n", u*state)), 0o644))
+		}
+	}
+}
+
+func TestLinearModel(t *testing.T) {
+	model := LinearModel{Intercept: 10, Utilization: 80, Frequency: 5, DeepIdle: -8}
+	assert.Equal(t, 10*Watt, model.Estimate(0, 0, 0))
+	assert.Equal(t, 60*Watt, model.Estimate(0.5, 2, 0))
+	assert.Equal(t, 56*Watt, model.Estimate(0.5, 2, 0.5))
+
+	negative := LinearModel{Intercept: -10}
+	assert.Equal(t, Power(0), negative.Estimate(0, 0, 0), "estimates are never negative")
+}
+
+func TestLoadLinearModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("intercept: 12.5\nutilization: 100\nfrequency: 2\n"), 0o644))
+
+	model, err := LoadLinearModel(path)
+	require.NoError(t, err)
+	assert.Equal(t, LinearModel{Intercept: 12.5, Utilization: 100, Frequency: 2}, model)
+
+	_, err = LoadLinearModel(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("intercept: [1"), 0o644))
+	_, err = LoadLinearModel(path)
+	assert.ErrorContains(t, err, "failed to parse model file")
+}
+
+func TestEstimatedPowerMeter(t *testing.T) {
+	procfs := t.TempDir()
+	sysfs := t.TempDir()
+	writeProcStat(t, procfs, 100, 100)
+	writeCPUFreq(t, sysfs, 2_000_000, 3_000_000)
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	model := LinearModel{Intercept: 10, Utilization: 100, Frequency: 4}
+	meter, err := NewEstimatedCPUMeter(procfs, sysfs, model, WithEstimatorClock(fakeClock))
+	require.NoError(t, err)
+	assert.Equal(t, "estimator", meter.Name())
+	require.NoError(t, meter.Init())
+
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.Equal(t, EstimatedZoneName, zones[0].Name())
+
+	primary, err := meter.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, zones[0], primary)
+
+	// the uncertainty of the model is unknown
+	assert.Equal(t, DefaultUncertainty, Uncertainty(zones[0]))
+
+	// first read: utilization since boot is 50%; 10 + 50 + 4 * 2.5 = 70W
+	_, err = zones[0].Energy()
+	require.NoError(t, err)
+
+	// 75% utilization in the interval: 10 + 75 + 10 = 95W
+	writeProcStat(t, procfs, 175, 125)
+	fakeClock.Step(2 * time.Second)
+	energy, err := zones[0].Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, (70.0+95.0)/2*2, energy.Joules(), 0.001)
+}
+
+func TestEstimatedPowerMeter_NoFrequency(t *testing.T) {
+	procfs := t.TempDir()
+	writeProcStat(t, procfs, 0, 100)
+
+	model := LinearModel{Intercept: 10, Utilization: 100, Frequency: 4}
+	meter, err := NewEstimatedCPUMeter(procfs, t.TempDir(), model)
+	require.NoError(t, err)
+	require.NoError(t, meter.Init(), "missing cpufreq is not an error")
+
+	power, err := meter.estimate()
+	require.NoError(t, err)
+	assert.Equal(t, 10*Watt, power)
+}
+
+func TestEstimatedPowerMeter_DeepIdle(t *testing.T) {
+	procfs := t.TempDir()
+	sysfs := t.TempDir()
+	writeProcStat(t, procfs, 0, 100)
+	writeCPUIdle(t, sysfs, 1_000_000, 1_000_000)
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	model := LinearModel{Intercept: 20, DeepIdle: -10}
+	meter, err := NewEstimatedCPUMeter(procfs, sysfs, model, WithEstimatorClock(fakeClock))
+	require.NoError(t, err)
+	require.NoError(t, meter.Init())
+
+	// both CPUs in deep idle for 1.5s of 2s
+	writeCPUIdle(t, sysfs, 2_500_000, 2_500_000)
+	fakeClock.Step(2 * time.Second)
+	power, err := meter.estimate()
+	require.NoError(t, err)
+	assert.InDelta(t, 12.5, power.Watts(), 0.001)
+
+	// a CPU went offline
+	writeCPUIdle(t, sysfs, 3_000_000)
+	require.NoError(t, os.RemoveAll(filepath.Join(sysfs, "devices", "system", "cpu", "cpu1")))
+	fakeClock.Step(time.Second)
+	power, err = meter.estimate()
+	require.NoError(t, err)
+	assert.Equal(t, 20*Watt, power)
+}
+
+func TestEstimatedPowerMeter_InitFail(t *testing.T) {
+	meter, err := NewEstimatedCPUMeter(t.TempDir(), t.TempDir(), LinearModel{})
+	require.NoError(t, err)
+	assert.ErrorContains(t, meter.Init(), "failed to read cpu stats")
+}
+
+func TestEstimatedPowerMeter_Uncertainty(t *testing.T) {
+	meter, err := NewEstimatedCPUMeter(t.TempDir(), t.TempDir(), LinearModel{Intercept: 10, Uncertainty: 0.15})
+	require.NoError(t, err)
+	zone, err := meter.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, 0.15, Uncertainty(zone))
+
+	// the uncertainty of a new model applies immediately
+	meter.SetModel(LinearModel{Intercept: 10, Uncertainty: 0.05})
+	assert.Equal(t, 0.05, Uncertainty(zone))
+
+	// measured zones are certain
+	assert.Zero(t, Uncertainty(NewMockRaplZone("package", 0, "", 1000)))
+	assert.Equal(t, DefaultUncertainty, Uncertainty(NewIOZone("network", IOModel{IdleWatts: 1}, nil)))
+}
@@ -0,0 +1,249 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"k8s.io/utils/clock"
+)
+
+// amdCard holds the sysfs paths of an AMD GPU
+type amdCard struct {
+	index     int
+	pciAddr   string // e.g. 0000:03:00.0
+	powerPath string // hwmon power file reporting µW
+	busyPath  string // gpu_busy_percent
+}
+
+// clientKey identifies a DRM client of a process on a device
+type clientKey struct {
+	pid     int
+	pciAddr string
+	id      string // drm-client-id; shared by fds (dup) of the same client
+}
+
+// amdMeter implements PowerMeter for AMD GPUs using the amdgpu driver's sysfs
+// (hwmon) interface. Per-process utilization is derived from the gfx engine
+// busy time amdgpu reports in /proc/<pid>/fdinfo.
+type amdMeter struct {
+	logger *slog.Logger
+	sysfs  string
+	procfs string
+	clock  clock.PassiveClock
+
+	cards []amdCard
+	zones []device.EnergyZone
+
+	// gfx engine busy time of DRM clients at the previous Utilization call
+	prevBusy map[clientKey]time.Duration
+	prevRead time.Time
+}
+
+var _ PowerMeter = (*amdMeter)(nil)
+
+// NewAMDMeter creates a new PowerMeter for AMD GPUs
+func NewAMDMeter(applyOpts ...OptionFn) *amdMeter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &amdMeter{
+		logger: opts.logger.With("service", "amd-gpu"),
+		sysfs:  opts.sysfs,
+		procfs: opts.procfs,
+		clock:  opts.clock,
+	}
+}
+
+func (m *amdMeter) Name() string {
+	return "amd"
+}
+
+func (m *amdMeter) Init() error {
+	cards, err := m.findCards()
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		return device.Errorf(device.ErrUnsupportedHardware, "no AMD GPUs found")
+	}
+
+	m.cards = cards
+	m.zones = make([]device.EnergyZone, 0, len(cards))
+	for _, card := range cards {
+		read := func() (device.Power, error) {
+			return readMicroWatts(card.powerPath)
+		}
+		if _, err := read(); err != nil {
+			return fmt.Errorf("failed to read power of gpu %d (%s): %w", card.index, card.pciAddr, device.Classify(err))
+		}
+		zone := device.NewPowerZone(fmt.Sprintf("amd-gpu-%d", card.index), card.index, card.powerPath, read, m.clock)
+		m.zones = append(m.zones, zone)
+		m.logger.Info("Found AMD GPU", "index", card.index, "pci", card.pciAddr)
+	}
+
+	return nil
+}
+
+// findCards returns all DRM cards driven by amdgpu that report power
+func (m *amdMeter) findCards() ([]amdCard, error) {
+	devices, err := filepath.Glob(filepath.Join(m.sysfs, "class", "drm", "card[0-9]*", "device"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(devices)
+
+	var cards []amdCard
+	for _, dev := range devices {
+		driver, err := filepath.EvalSymlinks(filepath.Join(dev, "driver"))
+		if err != nil || filepath.Base(driver) != "amdgpu" {
+			continue
+		}
+
+		powerPath := ""
+		for _, name := range []string{"power1_average", "power1_input"} {
+			matches, _ := filepath.Glob(filepath.Join(dev, "hwmon", "hwmon*", name))
+			if len(matches) > 0 {
+				powerPath = matches[0]
+				break
+			}
+		}
+		if powerPath == "" {
+			m.logger.Warn("Skipping AMD GPU without power sensor", "device", dev)
+			continue
+		}
+
+		pciAddr := dev
+		if resolved, err := filepath.EvalSymlinks(dev); err == nil {
+			pciAddr = resolved
+		}
+
+		cards = append(cards, amdCard{
+			index:     len(cards),
+			pciAddr:   filepath.Base(pciAddr),
+			powerPath: powerPath,
+			busyPath:  filepath.Join(dev, "gpu_busy_percent"),
+		})
+	}
+	return cards, nil
+}
+
+func (m *amdMeter) Zones() ([]device.EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no AMD GPUs initialized")
+	}
+	return m.zones, nil
+}
+
+func (m *amdMeter) Utilization() (map[int]Utilization, error) {
+	now := m.clock.Now()
+	busy, err := m.readClientBusy()
+	if err != nil {
+		return nil, device.Classify(err)
+	}
+
+	elapsed := now.Sub(m.prevRead)
+	procUtil := map[string]map[int]float64{}
+	if !m.prevRead.IsZero() && elapsed > 0 {
+		for key, total := range busy {
+			prev, ok := m.prevBusy[key]
+			if !ok || total < prev {
+				continue
+			}
+			if _, ok := procUtil[key.pciAddr]; !ok {
+				procUtil[key.pciAddr] = map[int]float64{}
+			}
+			procUtil[key.pciAddr][key.pid] += float64(total-prev) / float64(elapsed)
+		}
+	}
+	m.prevBusy = busy
+	m.prevRead = now
+
+	ret := make(map[int]Utilization, len(m.cards))
+	for _, card := range m.cards {
+		util := Utilization{Processes: procUtil[card.pciAddr]}
+		if percent, err := readInt(card.busyPath); err == nil {
+			util.Device = float64(percent) / 100
+		}
+		ret[card.index] = util
+	}
+	return ret, nil
+}
+
+// readClientBusy reads the gfx engine busy time of all amdgpu DRM clients
+func (m *amdMeter) readClientBusy() (map[clientKey]time.Duration, error) {
+	fdinfos, err := filepath.Glob(filepath.Join(m.procfs, "[0-9]*", "fdinfo", "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	busy := map[clientKey]time.Duration{}
+	for _, path := range fdinfos {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(filepath.Dir(path))))
+		if err != nil {
+			continue
+		}
+		info, err := parseDRMFdinfo(path)
+		if err != nil || info["drm-driver"] != "amdgpu" {
+			continue
+		}
+
+		ns, err := strconv.ParseUint(strings.TrimSuffix(info["drm-engine-gfx"], " ns"), 10, 64)
+		if err != nil {
+			continue
+		}
+		key := clientKey{pid: pid, pciAddr: info["drm-pdev"], id: info["drm-client-id"]}
+		busy[key] = time.Duration(ns)
+	}
+	return busy, nil
+}
+
+// parseDRMFdinfo parses the drm-* keys of a fdinfo file; the file is
+// expected to be small so it is read line by line until EOF
+func parseDRMFdinfo(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || !strings.HasPrefix(key, "drm-") {
+			continue
+		}
+		info[key] = strings.TrimSpace(value)
+	}
+	return info, scanner.Err()
+}
+
+func readInt(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readMicroWatts reads a hwmon power file which reports power in µW
+func readMicroWatts(path string) (device.Power, error) {
+	uw, err := readInt(path)
+	if err != nil {
+		return 0, err
+	}
+	return device.Power(uw) * device.MicroWatt, nil
+}
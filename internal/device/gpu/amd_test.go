@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+// fakeAMDSysfs creates a sysfs tree with a single amdgpu card at 0000:03:00.0
+func fakeAMDSysfs(t *testing.T, driver string) string {
+	t.Helper()
+	sysfs := t.TempDir()
+
+	driverDir := filepath.Join(sysfs, "bus", "pci", "drivers", driver)
+	require.NoError(t, os.MkdirAll(driverDir, 0o755))
+
+	dev := filepath.Join(sysfs, "devices", "pci0000:00", "0000:03:00.0")
+	writeFile(t, filepath.Join(dev, "hwmon", "hwmon3", "power1_average"), "150000000\n")
+	writeFile(t, filepath.Join(dev, "gpu_busy_percent"), "40\n")
+	require.NoError(t, os.Symlink(driverDir, filepath.Join(dev, "driver")))
+
+	card := filepath.Join(sysfs, "class", "drm", "card0")
+	require.NoError(t, os.MkdirAll(card, 0o755))
+	require.NoError(t, os.Symlink(dev, filepath.Join(card, "device")))
+	return sysfs
+}
+
+func writeFdinfo(t *testing.T, procfs string, pid, fd int, clientID string, gfxNs int64) {
+	t.Helper()
+	writeFile(t, filepath.Join(procfs, fmt.Sprint(pid), "fdinfo", fmt.Sprint(fd)), fmt.Sprintf(
+		"pos:\t0\nflags:\t02100002\ndrm-driver:\tamdgpu\ndrm-pdev:\t0000:03:00.0\ndrm-client-id:\t%s\ndrm-engine-gfx:\t%d ns\n",
+		clientID, gfxNs))
+}
+
+func TestAMDMeter(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	procfs := t.TempDir()
+	writeFdinfo(t, procfs, 100, 5, "1", 0)
+	writeFdinfo(t, procfs, 100, 6, "1", 0) // dup of the same client
+	writeFdinfo(t, procfs, 200, 5, "2", 0)
+	writeFile(t, filepath.Join(procfs, "300", "fdinfo", "1"), "pos:\t0\nflags:\t02\n")
+
+	meter := NewAMDMeter(
+		WithSysFSPath(fakeAMDSysfs(t, "amdgpu")),
+		WithProcFSPath(procfs),
+		WithClock(fakeClock),
+	)
+	assert.Equal(t, "amd", meter.Name())
+	require.NoError(t, meter.Init())
+
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.Equal(t, "amd-gpu-0", zones[0].Name())
+	assert.Equal(t, 0, zones[0].Index())
+
+	_, err = zones[0].Energy()
+	require.NoError(t, err)
+	fakeClock.Step(2 * time.Second)
+	energy, err := zones[0].Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 300.0, energy.Joules(), 0.001, "150W over 2s")
+
+	// first read only records the busy time of clients
+	util, err := meter.Utilization()
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4, util[0].Device, 0.0001)
+	assert.Empty(t, util[0].Processes)
+
+	writeFdinfo(t, procfs, 100, 5, "1", int64(time.Second))
+	writeFdinfo(t, procfs, 100, 6, "1", int64(time.Second))
+	writeFdinfo(t, procfs, 200, 5, "2", int64(500*time.Millisecond))
+	fakeClock.Step(2 * time.Second)
+
+	util, err = meter.Utilization()
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, util[0].Processes[100], 0.0001)
+	assert.InDelta(t, 0.25, util[0].Processes[200], 0.0001)
+	assert.NotContains(t, util[0].Processes, 300)
+}
+
+func TestAMDMeter_NoGPUs(t *testing.T) {
+	meter := NewAMDMeter(WithSysFSPath(fakeAMDSysfs(t, "i915")))
+	assert.ErrorContains(t, meter.Init(), "no AMD GPUs found")
+
+	_, err := meter.Zones()
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// DefaultDevicePluginCheckpoint is the checkpoint file of the device manager of
+// the kubelet, which records the devices allocated to the containers of pods
+const DefaultDevicePluginCheckpoint = "/var/lib/kubelet/device-plugins/kubelet_internal_checkpoint"
+
+// nvidiaResourcePrefix is the prefix of the resources of the NVIDIA device
+// plugin, e.g. nvidia.com/gpu and nvidia.com/mig-1g.5gb
+const nvidiaResourcePrefix = "nvidia.com/"
+
+// kubeletCheckpoint is the part of the checkpoint of the device manager used
+type kubeletCheckpoint struct {
+	Data struct {
+		PodDeviceEntries []struct {
+			PodUID       string
+			ResourceName string
+
+			// DeviceIDs are the devices allocated keyed by NUMA node, or a
+			// list of devices before Kubernetes 1.20
+			DeviceIDs json.RawMessage
+		}
+	}
+}
+
+// readDeviceAllocations returns the UIDs of the pods NVIDIA devices are
+// allocated to, keyed by the UUID of the GPU or MIG instance. A device shared
+// by time-slicing or MPS is allocated as replicas, e.g. GPU-<uuid>::0, to as
+// many pods. A missing checkpoint, e.g. on nodes without the device plugin,
+// has no allocations.
+func readDeviceAllocations(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cp kubeletCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("invalid device plugin checkpoint %s: %w", path, err)
+	}
+
+	allocations := map[string][]string{}
+	for _, entry := range cp.Data.PodDeviceEntries {
+		if !strings.HasPrefix(entry.ResourceName, nvidiaResourcePrefix) {
+			continue
+		}
+		ids, err := deviceIDs(entry.DeviceIDs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid devices of pod %s in %s: %w", entry.PodUID, path, err)
+		}
+		for _, id := range ids {
+			uuid, _, _ := strings.Cut(id, "::")
+			if !slices.Contains(allocations[uuid], entry.PodUID) {
+				allocations[uuid] = append(allocations[uuid], entry.PodUID)
+			}
+		}
+	}
+	return allocations, nil
+}
+
+// deviceIDs returns the devices of an entry of the checkpoint
+func deviceIDs(raw json.RawMessage) ([]string, error) {
+	var byNUMA map[string][]string
+	if err := json.Unmarshal(raw, &byNUMA); err == nil {
+		var ids []string
+		for _, numaIDs := range byNUMA {
+			ids = append(ids, numaIDs...)
+		}
+		return ids, nil
+	}
+
+	var ids []string
+	if err := json.Unmarshal(raw, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
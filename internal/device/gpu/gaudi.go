@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"k8s.io/utils/clock"
+)
+
+// hlSMICacheDuration is how long a reading of hl-smi is used for the power of
+// all devices, so that hl-smi is run once per refresh rather than once per
+// device
+const hlSMICacheDuration = time.Second
+
+// gaudiDevice holds the static information of an Intel Gaudi accelerator
+type gaudiDevice struct {
+	index   int
+	uuid    string
+	name    string
+	pciAddr string // e.g. 0000:33:00.0
+}
+
+// gaudiReading is the power and utilization of a Gaudi read by hl-smi
+type gaudiReading struct {
+	power       device.Power
+	utilization float64
+}
+
+// gaudiMeter implements PowerMeter for Intel Gaudi (formerly Habana) AI
+// accelerators using hl-smi, which mirrors the query interface of nvidia-smi.
+// hl-smi doesn't report the utilization of processes, so that of a device is
+// split equally among the processes that have it open, found from the DRM
+// fdinfo the habanalabs driver reports for accel devices (Linux 6.2+).
+type gaudiMeter struct {
+	logger *slog.Logger
+	path   string
+	run    cmdRunner
+	procfs string
+	clock  clock.PassiveClock
+
+	devices []gaudiDevice
+	zones   []device.EnergyZone
+
+	mu       sync.Mutex
+	cached   map[int]gaudiReading // readings of the last hl-smi run by index
+	cachedAt time.Time
+}
+
+var _ PowerMeter = (*gaudiMeter)(nil)
+
+// NewGaudiMeter creates a new PowerMeter for Intel Gaudi accelerators
+func NewGaudiMeter(applyOpts ...OptionFn) *gaudiMeter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &gaudiMeter{
+		logger: opts.logger.With("service", "gaudi"),
+		path:   opts.hlSMIPath,
+		run:    timeoutRunner(opts.cmdTimeout),
+		procfs: opts.procfs,
+		clock:  opts.clock,
+	}
+}
+
+func (m *gaudiMeter) Name() string {
+	return "gaudi"
+}
+
+func (m *gaudiMeter) Init() error {
+	rows, err := m.query("index,uuid,name,bus_id")
+	if err != nil {
+		return fmt.Errorf("failed to list Gaudi accelerators: %w", device.Classify(err))
+	}
+
+	m.devices = make([]gaudiDevice, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 4 {
+			return fmt.Errorf("unexpected accelerator info: %v", row)
+		}
+		index, err := strconv.Atoi(row[0])
+		if err != nil {
+			return fmt.Errorf("invalid accelerator index %q: %w", row[0], err)
+		}
+		m.devices = append(m.devices, gaudiDevice{index: index, uuid: row[1], name: row[2], pciAddr: strings.ToLower(row[3])})
+	}
+	if len(m.devices) == 0 {
+		return device.Errorf(device.ErrUnsupportedHardware, "no Gaudi accelerators found")
+	}
+
+	m.zones = make([]device.EnergyZone, 0, len(m.devices))
+	for _, dev := range m.devices {
+		index := dev.index
+		read := func() (device.Power, error) {
+			readings, err := m.readings()
+			if err != nil {
+				return 0, err
+			}
+			r, ok := readings[index]
+			if !ok {
+				return 0, fmt.Errorf("gaudi %d not found in hl-smi output", index)
+			}
+			return r.power, nil
+		}
+		// ensure power can be read before the zone is used
+		if _, err := read(); err != nil {
+			return fmt.Errorf("failed to read power of gaudi %d (%s): %w", index, dev.name, device.Classify(err))
+		}
+		zone := device.NewPowerZone(fmt.Sprintf("habana-gaudi-%d", index), index, "hl-smi:"+dev.uuid, read, m.clock)
+		m.zones = append(m.zones, zone)
+		m.logger.Info("Found Gaudi accelerator", "index", index, "uuid", dev.uuid, "name", dev.name, "pci", dev.pciAddr)
+	}
+	return nil
+}
+
+func (m *gaudiMeter) Zones() ([]device.EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no Gaudi accelerators initialized")
+	}
+	return m.zones, nil
+}
+
+// Utilization returns the utilization of the devices, split equally among the
+// processes that have them open
+func (m *gaudiMeter) Utilization() (map[int]Utilization, error) {
+	readings, err := m.readings()
+	if err != nil {
+		return nil, device.Classify(err)
+	}
+	clients, err := m.readClients()
+	if err != nil {
+		return nil, device.Classify(err)
+	}
+
+	ret := make(map[int]Utilization, len(m.devices))
+	for _, dev := range m.devices {
+		r, ok := readings[dev.index]
+		if !ok {
+			continue
+		}
+		util := Utilization{Device: r.utilization}
+		if pids := clients[dev.pciAddr]; len(pids) > 0 {
+			util.Processes = make(map[int]float64, len(pids))
+			for pid := range pids {
+				util.Processes[pid] = r.utilization / float64(len(pids))
+			}
+		}
+		ret[dev.index] = util
+	}
+	return ret, nil
+}
+
+// readings returns the power and utilization of all devices read by hl-smi,
+// which is run at most once per hlSMICacheDuration
+func (m *gaudiMeter) readings() (map[int]gaudiReading, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cached != nil && m.clock.Since(m.cachedAt) < hlSMICacheDuration {
+		return m.cached, nil
+	}
+
+	rows, err := m.query("index,power.draw,utilization.aip")
+	if err != nil {
+		return nil, err
+	}
+	readings := make(map[int]gaudiReading, len(rows))
+	for _, row := range rows {
+		if len(row) < 3 {
+			continue
+		}
+		index, err := strconv.Atoi(row[0])
+		if err != nil {
+			continue
+		}
+		watts, err := strconv.ParseFloat(strings.TrimSuffix(row[1], " W"), 64)
+		if err != nil {
+			return nil, device.Errorf(device.ErrUnsupportedHardware, "power reading of gaudi %d not supported: %q", index, row[1])
+		}
+		r := gaudiReading{power: device.Power(watts) * device.Watt}
+		if percent, err := strconv.ParseFloat(strings.TrimSuffix(row[2], " %"), 64); err == nil {
+			r.utilization = min(percent/100, 1)
+		}
+		readings[index] = r
+	}
+	m.cached = readings
+	m.cachedAt = m.clock.Now()
+	return readings, nil
+}
+
+// query runs an hl-smi query of fields of all devices and returns its rows
+func (m *gaudiMeter) query(fields string) ([][]string, error) {
+	out, err := m.run(m.path, "--query-aip="+fields, "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", fields, err)
+	}
+
+	var rows [][]string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		cols := strings.Split(line, ",")
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+		rows = append(rows, cols)
+	}
+	return rows, scanner.Err()
+}
+
+// readClients returns the PIDs of the processes that have a device open keyed
+// by the PCI address of the device
+func (m *gaudiMeter) readClients() (map[string]map[int]struct{}, error) {
+	fdinfos, err := filepath.Glob(filepath.Join(m.procfs, "[0-9]*", "fdinfo", "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	clients := map[string]map[int]struct{}{}
+	for _, path := range fdinfos {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(filepath.Dir(path))))
+		if err != nil {
+			continue
+		}
+		info, err := parseDRMFdinfo(path)
+		if err != nil || info["drm-driver"] != "habanalabs" {
+			continue
+		}
+		pdev := strings.ToLower(info["drm-pdev"])
+		if _, ok := clients[pdev]; !ok {
+			clients[pdev] = map[int]struct{}{}
+		}
+		clients[pdev][pid] = struct{}{}
+	}
+	return clients, nil
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestGaudiMeter(t *testing.T) {
+	procfs := t.TempDir()
+	// two processes of a training job use accelerator 0, one of which has it
+	// open twice; the other process has a GPU open
+	for path, content := range map[string]string{
+		"100/fdinfo/5": "pos:\t0\ndrm-driver:\thabanalabs\ndrm-pdev:\t0000:33:00.0\ndrm-client-id:\t1\n",
+		"100/fdinfo/6": "pos:\t0\ndrm-driver:\thabanalabs\ndrm-pdev:\t0000:33:00.0\ndrm-client-id:\t1\n",
+		"200/fdinfo/3": "pos:\t0\ndrm-driver:\thabanalabs\ndrm-pdev:\t0000:33:00.0\ndrm-client-id:\t2\n",
+		"300/fdinfo/3": "pos:\t0\ndrm-driver:\tamdgpu\ndrm-pdev:\t0000:03:00.0\ndrm-client-id:\t3\n",
+		"400/fdinfo/1": "pos:\t0\nflags:\t02\n",
+	} {
+		writeFile(t, filepath.Join(procfs, path), content)
+	}
+
+	power := "250"
+	runs := 0
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter := NewGaudiMeter(WithClock(fakeClock), WithProcFSPath(procfs))
+	meter.run = func(name string, args ...string) ([]byte, error) {
+		assert.Equal(t, "hl-smi", name)
+		runs++
+		switch args[0] {
+		case "--query-aip=index,uuid,name,bus_id":
+			return []byte("0, 01P0-HL2080A0-15-TNPS34-20-07-06, HL-225, 0000:33:00.0\n" +
+				"1, 01P0-HL2080A0-15-TNPS34-20-07-07, HL-225, 0000:9A:00.0\n"), nil
+		case "--query-aip=index,power.draw,utilization.aip":
+			return []byte("0, " + power + ", 60\n1, 100, 0\n"), nil
+		}
+		t.Fatalf("unexpected command: %s", strings.Join(args, " "))
+		return nil, nil
+	}
+	assert.Equal(t, "gaudi", meter.Name())
+
+	require.NoError(t, meter.Init())
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 2)
+	assert.Equal(t, "habana-gaudi-0", zones[0].Name())
+	assert.Equal(t, "hl-smi:01P0-HL2080A0-15-TNPS34-20-07-06", zones[0].Path())
+	assert.Equal(t, 1, zones[1].Index())
+	assert.Equal(t, 2, runs, "hl-smi is run once for the power of all devices")
+
+	_, err = zones[0].Energy()
+	require.NoError(t, err)
+	fakeClock.Step(10 * time.Second)
+	power = "350"
+	energy, err := zones[0].Energy()
+	require.NoError(t, err)
+	// trapezoid of 250W and 350W over 10s
+	assert.InDelta(t, 3000.0, energy.Joules(), 0.001)
+
+	util, err := meter.Utilization()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]Utilization{
+		0: {Device: 0.6, Processes: map[int]float64{100: 0.3, 200: 0.3}},
+		1: {Device: 0},
+	}, util)
+}
+
+func TestGaudiMeter_InitErrors(t *testing.T) {
+	t.Run("no devices", func(t *testing.T) {
+		meter := NewGaudiMeter()
+		meter.run = func(string, ...string) ([]byte, error) { return nil, nil }
+		assert.ErrorContains(t, meter.Init(), "no Gaudi accelerators found")
+	})
+
+	t.Run("hl-smi missing", func(t *testing.T) {
+		meter := NewGaudiMeter(WithHabanaSMIPath("/non/existent/hl-smi"))
+		assert.ErrorContains(t, meter.Init(), "failed to list Gaudi accelerators")
+	})
+
+	t.Run("power not supported", func(t *testing.T) {
+		meter := NewGaudiMeter()
+		meter.run = func(_ string, args ...string) ([]byte, error) {
+			if args[0] == "--query-aip=index,uuid,name,bus_id" {
+				return []byte("0, uuid, HL-225, 0000:33:00.0\n"), nil
+			}
+			return []byte("0, N/A, N/A\n"), nil
+		}
+		assert.ErrorContains(t, meter.Init(), "failed to read power of gaudi 0")
+	})
+}
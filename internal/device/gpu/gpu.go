@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"github.com/sustainable-computing-io/kepler/internal/device"
+)
+
+// Utilization represents the utilization of a GPU device over its last sampling period
+type Utilization struct {
+	// Device is the ratio of time the device was busy (value between 0.0 and 1.0)
+	Device float64
+
+	// Processes maps PIDs of processes using the device to their utilization of
+	// the device (value between 0.0 and 1.0)
+	Processes map[int]float64
+
+	// Pods maps UIDs of pods to their utilization of the device, for devices
+	// shared among pods whose processes are not reported, e.g. the MIG
+	// instances of a GPU allocated to pods by the device plugin
+	Pods map[string]float64
+}
+
+// PowerMeter reads power of GPU devices and the utilization of those devices
+// by processes, which is used to attribute GPU power to workloads
+type PowerMeter interface {
+	// Name returns a string identifying the power meter
+	Name() string
+
+	// Init probes for devices and returns an error if none can be read
+	Init() error
+
+	// Zones returns one energy zone per GPU device. Index() of the zone is the
+	// index of the device
+	Zones() ([]device.EnergyZone, error)
+
+	// Utilization returns device and per-process utilization keyed by the
+	// device index
+	Utilization() (map[int]Utilization, error)
+}
@@ -0,0 +1,341 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"k8s.io/utils/clock"
+)
+
+// intelDrivers are the DRM drivers of Intel GPUs
+var intelDrivers = map[string]bool{"i915": true, "xe": true}
+
+// intelCard holds the sysfs paths of an Intel GPU
+type intelCard struct {
+	index      int
+	pciAddr    string // e.g. 0000:03:00.0
+	driver     string // i915 or xe
+	energyPath string // hwmon energy file reporting µJ
+}
+
+// intelEnergyZone implements device.EnergyZone for the hwmon energy counter of
+// an Intel GPU
+type intelEnergyZone struct {
+	name  string
+	index int
+	path  string
+}
+
+var _ device.EnergyZone = (*intelEnergyZone)(nil)
+
+func (z *intelEnergyZone) Name() string {
+	return z.name
+}
+
+func (z *intelEnergyZone) Index() int {
+	return z.index
+}
+
+func (z *intelEnergyZone) Path() string {
+	return z.path
+}
+
+// Energy returns the energy of the GPU; hwmon reports energy in µJ
+func (z *intelEnergyZone) Energy() (device.Energy, error) {
+	uj, err := readInt(z.path)
+	if err != nil {
+		return 0, device.Classify(err)
+	}
+	return device.Energy(uj), nil
+}
+
+// MaxEnergy returns the maximum energy of the counter; the drivers extend the
+// hardware counter to 64 bits so it never wraps around in practice
+func (z *intelEnergyZone) MaxEnergy() device.Energy {
+	return device.Energy(math.MaxUint64)
+}
+
+// engineBusy is the busy time of an engine class used by a DRM client. i915
+// reports busy time in ns, whereas xe reports busy GPU cycles along with the
+// total GPU cycles elapsed
+type engineBusy struct {
+	busy     uint64
+	total    uint64 // xe only; 0 if busy is in ns
+	capacity uint64 // number of engines of the class
+}
+
+// intelMeter implements PowerMeter for Intel GPUs using the energy reported by
+// the i915 and xe drivers' hwmon interface, which is only available on discrete
+// GPUs; the power of integrated GPUs is part of the RAPL uncore or package
+// zone. Per-process utilization is derived from the engine busy time the
+// drivers report in /proc/<pid>/fdinfo.
+type intelMeter struct {
+	logger *slog.Logger
+	sysfs  string
+	procfs string
+	clock  clock.PassiveClock
+
+	cards []intelCard
+	zones []device.EnergyZone
+
+	// engine busy time of DRM clients at the previous Utilization call
+	prevBusy map[clientKey]map[string]engineBusy
+	prevRead time.Time
+}
+
+var _ PowerMeter = (*intelMeter)(nil)
+
+// NewIntelMeter creates a new PowerMeter for Intel GPUs
+func NewIntelMeter(applyOpts ...OptionFn) *intelMeter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &intelMeter{
+		logger: opts.logger.With("service", "intel-gpu"),
+		sysfs:  opts.sysfs,
+		procfs: opts.procfs,
+		clock:  opts.clock,
+	}
+}
+
+func (m *intelMeter) Name() string {
+	return "intel"
+}
+
+func (m *intelMeter) Init() error {
+	cards, err := m.findCards()
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		return device.Errorf(device.ErrUnsupportedHardware, "no Intel GPUs found")
+	}
+
+	m.cards = cards
+	m.zones = make([]device.EnergyZone, 0, len(cards))
+	for _, card := range cards {
+		zone := &intelEnergyZone{
+			name:  fmt.Sprintf("intel-gpu-%d", card.index),
+			index: card.index,
+			path:  card.energyPath,
+		}
+		if _, err := zone.Energy(); err != nil {
+			return fmt.Errorf("failed to read energy of gpu %d (%s): %w", card.index, card.pciAddr, device.Classify(err))
+		}
+		m.zones = append(m.zones, zone)
+		m.logger.Info("Found Intel GPU", "index", card.index, "pci", card.pciAddr, "driver", card.driver)
+	}
+
+	return nil
+}
+
+// findCards returns all DRM cards driven by i915 or xe that report energy
+func (m *intelMeter) findCards() ([]intelCard, error) {
+	devices, err := filepath.Glob(filepath.Join(m.sysfs, "class", "drm", "card[0-9]*", "device"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(devices)
+
+	var cards []intelCard
+	for _, dev := range devices {
+		driver, err := filepath.EvalSymlinks(filepath.Join(dev, "driver"))
+		if err != nil || !intelDrivers[filepath.Base(driver)] {
+			continue
+		}
+
+		energyPath := findIntelEnergy(dev)
+		if energyPath == "" {
+			m.logger.Info("Skipping Intel GPU without energy sensor; integrated GPUs are part of the RAPL zones",
+				"device", dev)
+			continue
+		}
+
+		pciAddr := dev
+		if resolved, err := filepath.EvalSymlinks(dev); err == nil {
+			pciAddr = resolved
+		}
+
+		cards = append(cards, intelCard{
+			index:      len(cards),
+			pciAddr:    filepath.Base(pciAddr),
+			driver:     filepath.Base(driver),
+			energyPath: energyPath,
+		})
+	}
+	return cards, nil
+}
+
+// findIntelEnergy returns the hwmon energy file of the whole card of a device.
+// xe reports the energy of the card and of the GPU package, labelled "card"
+// and "pkg"; i915 only reports energy1_input
+func findIntelEnergy(dev string) string {
+	inputs, _ := filepath.Glob(filepath.Join(dev, "hwmon", "hwmon*", "energy[0-9]*_input"))
+	sort.Strings(inputs)
+	for _, input := range inputs {
+		label, err := os.ReadFile(strings.TrimSuffix(input, "_input") + "_label")
+		if err == nil && strings.TrimSpace(string(label)) == "card" {
+			return input
+		}
+	}
+	for _, input := range inputs {
+		if filepath.Base(input) == "energy1_input" {
+			return input
+		}
+	}
+	return ""
+}
+
+func (m *intelMeter) Zones() ([]device.EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no Intel GPUs initialized")
+	}
+	return m.zones, nil
+}
+
+// Utilization returns the utilization of processes as the sum of their
+// utilization of each engine class, and that of the device as the utilization
+// of its busiest engine class
+func (m *intelMeter) Utilization() (map[int]Utilization, error) {
+	now := m.clock.Now()
+	busy, err := m.readClientBusy()
+	if err != nil {
+		return nil, device.Classify(err)
+	}
+
+	elapsed := now.Sub(m.prevRead)
+	procUtil := map[string]map[int]float64{}
+	engineUtil := map[string]map[string]float64{}
+	for key, engines := range busy {
+		prevEngines, ok := m.prevBusy[key]
+		if !ok {
+			continue
+		}
+		for class, e := range engines {
+			prev, ok := prevEngines[class]
+			if !ok || e.busy < prev.busy {
+				continue
+			}
+
+			var ratio float64
+			switch {
+			case e.total > 0 && e.total > prev.total:
+				ratio = float64(e.busy-prev.busy) / float64(e.total-prev.total)
+			case e.total == 0 && elapsed > 0:
+				ratio = float64(e.busy-prev.busy) / float64(elapsed)
+			default:
+				continue
+			}
+			ratio /= float64(max(e.capacity, 1))
+
+			if _, ok := procUtil[key.pciAddr]; !ok {
+				procUtil[key.pciAddr] = map[int]float64{}
+				engineUtil[key.pciAddr] = map[string]float64{}
+			}
+			procUtil[key.pciAddr][key.pid] += ratio
+			engineUtil[key.pciAddr][class] += ratio
+		}
+	}
+	m.prevBusy = busy
+	m.prevRead = now
+
+	ret := make(map[int]Utilization, len(m.cards))
+	for _, card := range m.cards {
+		util := Utilization{Processes: procUtil[card.pciAddr]}
+		for _, u := range engineUtil[card.pciAddr] {
+			util.Device = min(max(util.Device, u), 1)
+		}
+		ret[card.index] = util
+	}
+	return ret, nil
+}
+
+// readClientBusy reads the busy time of the engines used by all i915 and xe
+// DRM clients
+func (m *intelMeter) readClientBusy() (map[clientKey]map[string]engineBusy, error) {
+	fdinfos, err := filepath.Glob(filepath.Join(m.procfs, "[0-9]*", "fdinfo", "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	busy := map[clientKey]map[string]engineBusy{}
+	for _, path := range fdinfos {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(filepath.Dir(path))))
+		if err != nil {
+			continue
+		}
+		info, err := parseDRMFdinfo(path)
+		if err != nil || !intelDrivers[info["drm-driver"]] {
+			continue
+		}
+
+		engines := parseIntelEngines(info)
+		if len(engines) == 0 {
+			continue
+		}
+		key := clientKey{pid: pid, pciAddr: info["drm-pdev"], id: info["drm-client-id"]}
+		busy[key] = engines
+	}
+	return busy, nil
+}
+
+// parseIntelEngines returns the busy time of each engine class in the drm-*
+// keys of a fdinfo file. i915 reports e.g.
+//
+//	drm-engine-render:	12345 ns
+//	drm-engine-capacity-video:	2
+//
+// and xe reports e.g.
+//
+//	drm-cycles-rcs:	12345
+//	drm-total-cycles-rcs:	67890
+func parseIntelEngines(info map[string]string) map[string]engineBusy {
+	engines := map[string]engineBusy{}
+	capacity := map[string]uint64{}
+	for key, value := range info {
+		n, err := strconv.ParseUint(strings.TrimSuffix(value, " ns"), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(key, "drm-engine-capacity-"):
+			capacity[strings.TrimPrefix(key, "drm-engine-capacity-")] = n
+		case strings.HasPrefix(key, "drm-engine-"):
+			class := strings.TrimPrefix(key, "drm-engine-")
+			e := engines[class]
+			e.busy = n
+			engines[class] = e
+		case strings.HasPrefix(key, "drm-total-cycles-"):
+			class := strings.TrimPrefix(key, "drm-total-cycles-")
+			e := engines[class]
+			e.total = n
+			engines[class] = e
+		case strings.HasPrefix(key, "drm-cycles-"):
+			class := strings.TrimPrefix(key, "drm-cycles-")
+			e := engines[class]
+			e.busy = n
+			engines[class] = e
+		}
+	}
+	for class, n := range capacity {
+		if e, ok := engines[class]; ok {
+			e.capacity = n
+			engines[class] = e
+		}
+	}
+	return engines
+}
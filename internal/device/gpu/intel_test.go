@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// fakeIntelSysfs creates a sysfs tree with an integrated GPU without hwmon at
+// card0 and a discrete GPU at card1 (0000:03:00.0) driven by driver
+func fakeIntelSysfs(t *testing.T, driver string) string {
+	t.Helper()
+	sysfs := t.TempDir()
+
+	driverDir := filepath.Join(sysfs, "bus", "pci", "drivers", driver)
+	require.NoError(t, os.MkdirAll(driverDir, 0o755))
+
+	igpu := filepath.Join(sysfs, "devices", "pci0000:00", "0000:00:02.0")
+	require.NoError(t, os.MkdirAll(igpu, 0o755))
+	require.NoError(t, os.Symlink(driverDir, filepath.Join(igpu, "driver")))
+
+	dgpu := filepath.Join(sysfs, "devices", "pci0000:00", "0000:03:00.0")
+	hwmon := filepath.Join(dgpu, "hwmon", "hwmon4")
+	if driver == "xe" {
+		writeFile(t, filepath.Join(hwmon, "energy1_input"), "1000000\n")
+		writeFile(t, filepath.Join(hwmon, "energy1_label"), "pkg\n")
+		writeFile(t, filepath.Join(hwmon, "energy2_input"), "5000000\n")
+		writeFile(t, filepath.Join(hwmon, "energy2_label"), "card\n")
+	} else {
+		writeFile(t, filepath.Join(hwmon, "energy1_input"), "5000000\n")
+	}
+	require.NoError(t, os.Symlink(driverDir, filepath.Join(dgpu, "driver")))
+
+	for i, dev := range []string{igpu, dgpu} {
+		card := filepath.Join(sysfs, "class", "drm", fmt.Sprintf("card%d", i))
+		require.NoError(t, os.MkdirAll(card, 0o755))
+		require.NoError(t, os.Symlink(dev, filepath.Join(card, "device")))
+	}
+	return sysfs
+}
+
+func writeIntelFdinfo(t *testing.T, procfs string, pid, fd int, driver, clientID, engines string) {
+	t.Helper()
+	writeFile(t, filepath.Join(procfs, fmt.Sprint(pid), "fdinfo", fmt.Sprint(fd)), fmt.Sprintf(
+		"pos:\t0\nflags:\t02100002\ndrm-driver:\t%s\ndrm-pdev:\t0000:03:00.0\ndrm-client-id:\t%s\n%s",
+		driver, clientID, engines))
+}
+
+func TestIntelMeter_i915(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	sysfs := fakeIntelSysfs(t, "i915")
+	procfs := t.TempDir()
+	i915Engines := func(render, video time.Duration) string {
+		return fmt.Sprintf("drm-engine-render:\t%d ns\ndrm-engine-video:\t%d ns\ndrm-engine-capacity-video:\t2\n",
+			render, video)
+	}
+	writeIntelFdinfo(t, procfs, 100, 5, "i915", "1", i915Engines(0, 0))
+	writeIntelFdinfo(t, procfs, 100, 6, "i915", "1", i915Engines(0, 0)) // dup of the same client
+	writeIntelFdinfo(t, procfs, 200, 5, "i915", "2", i915Engines(0, 0))
+	writeFdinfo(t, procfs, 300, 5, "3", 0) // amdgpu client
+
+	meter := NewIntelMeter(
+		WithSysFSPath(sysfs),
+		WithProcFSPath(procfs),
+		WithClock(fakeClock),
+	)
+	assert.Equal(t, "intel", meter.Name())
+	require.NoError(t, meter.Init())
+
+	// the integrated GPU has no energy sensor
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.Equal(t, "intel-gpu-0", zones[0].Name())
+	assert.Equal(t, 0, zones[0].Index())
+
+	energy, err := zones[0].Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 5.0, energy.Joules(), 0.001)
+
+	// first read only records the busy time of clients
+	util, err := meter.Utilization()
+	require.NoError(t, err)
+	assert.Zero(t, util[0].Device)
+	assert.Empty(t, util[0].Processes)
+
+	writeIntelFdinfo(t, procfs, 100, 5, "i915", "1", i915Engines(time.Second, 0))
+	writeIntelFdinfo(t, procfs, 100, 6, "i915", "1", i915Engines(time.Second, 0))
+	writeIntelFdinfo(t, procfs, 200, 5, "i915", "2", i915Engines(500*time.Millisecond, 2*time.Second))
+	writeFdinfo(t, procfs, 300, 5, "3", int64(time.Second))
+	fakeClock.Step(2 * time.Second)
+
+	util, err = meter.Utilization()
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, util[0].Processes[100], 0.0001)
+	// render 0.25 and video 1 of 2 engines
+	assert.InDelta(t, 0.25+0.5, util[0].Processes[200], 0.0001)
+	assert.NotContains(t, util[0].Processes, 300)
+	// render engine is the busiest
+	assert.InDelta(t, 0.75, util[0].Device, 0.0001)
+}
+
+func TestIntelMeter_xe(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	procfs := t.TempDir()
+	xeEngines := func(cycles, total int) string {
+		return fmt.Sprintf("drm-cycles-rcs:\t%d\ndrm-total-cycles-rcs:\t%d\ndrm-cycles-bcs:\t0\ndrm-total-cycles-bcs:\t%d\n",
+			cycles, total, total)
+	}
+	writeIntelFdinfo(t, procfs, 100, 5, "xe", "1", xeEngines(1000, 10000))
+
+	meter := NewIntelMeter(
+		WithSysFSPath(fakeIntelSysfs(t, "xe")),
+		WithProcFSPath(procfs),
+		WithClock(fakeClock),
+	)
+	require.NoError(t, meter.Init())
+
+	// the energy of the card rather than the GPU package
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.Contains(t, zones[0].Path(), "energy2_input")
+
+	_, err = meter.Utilization()
+	require.NoError(t, err)
+
+	writeIntelFdinfo(t, procfs, 100, 5, "xe", "1", xeEngines(4000, 20000))
+	fakeClock.Step(time.Second)
+
+	util, err := meter.Utilization()
+	require.NoError(t, err)
+	assert.InDelta(t, 0.3, util[0].Processes[100], 0.0001)
+	assert.InDelta(t, 0.3, util[0].Device, 0.0001)
+}
+
+func TestIntelMeter_NoGPUs(t *testing.T) {
+	meter := NewIntelMeter(WithSysFSPath(fakeAMDSysfs(t, "amdgpu")))
+	assert.ErrorContains(t, meter.Init(), "no Intel GPUs found")
+
+	_, err := meter.Zones()
+	assert.Error(t, err)
+}
@@ -0,0 +1,518 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"k8s.io/utils/clock"
+)
+
+// nvmlDevice holds the static information of an NVIDIA GPU
+type nvmlDevice struct {
+	Index int
+	UUID  string
+	Name  string
+}
+
+// migDevice is a MIG instance of an NVIDIA GPU
+type migDevice struct {
+	UUID    string
+	Profile string // e.g. 3g.20gb
+	Slices  int    // compute slices of the instance, e.g. 3 for 3g.20gb
+}
+
+// computeApp is a process running compute work on an NVIDIA GPU
+type computeApp struct {
+	GPUUUID    string
+	PID        int
+	Name       string
+	UsedMemory float64 // MiB
+}
+
+// nvml is the subset of NVML used by nvidiaMeter.
+//
+// NOTE: NVML is accessed through nvidia-smi (which is a thin NVML frontend)
+// rather than the NVML library bindings, since the bindings require cgo and
+// Kepler is built with CGO_ENABLED=0
+type nvml interface {
+	Devices() ([]nvmlDevice, error)
+	Power(index int) (device.Power, error)
+	DeviceUtilization() (map[int]float64, error)
+	ProcessUtilization() (map[int]map[int]float64, error)
+	MIGDevices() (map[int][]migDevice, error)
+	ComputeApps() ([]computeApp, error)
+}
+
+// cmdRunner runs a command and returns its standard output
+type cmdRunner func(name string, args ...string) ([]byte, error)
+
+// timeoutRunner returns a cmdRunner that kills the command if it runs for
+// longer than timeout, e.g. when the driver hangs, so that it doesn't stall
+// collections
+func timeoutRunner(timeout time.Duration) cmdRunner {
+	return func(name string, args ...string) ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		out, err := exec.CommandContext(ctx, name, args...).Output()
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s timed out after %s: %w", name, timeout, ctx.Err())
+		}
+		return out, err
+	}
+}
+
+// nvidiaSMI implements nvml using nvidia-smi
+type nvidiaSMI struct {
+	path string
+	run  cmdRunner
+}
+
+var _ nvml = (*nvidiaSMI)(nil)
+
+func (s *nvidiaSMI) query(fields string, args ...string) ([][]string, error) {
+	return s.csv("--query-gpu="+fields, args...)
+}
+
+// csv runs a query of nvidia-smi and returns its rows
+func (s *nvidiaSMI) csv(query string, args ...string) ([][]string, error) {
+	args = append([]string{query, "--format=csv,noheader,nounits"}, args...)
+	out, err := s.run(s.path, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", strings.SplitN(query, "=", 2)[1], err)
+	}
+
+	var rows [][]string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		cols := strings.Split(line, ",")
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+		rows = append(rows, cols)
+	}
+	return rows, scanner.Err()
+}
+
+// Devices returns all NVIDIA GPUs on the node
+func (s *nvidiaSMI) Devices() ([]nvmlDevice, error) {
+	rows, err := s.query("index,uuid,name")
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]nvmlDevice, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 3 {
+			return nil, fmt.Errorf("unexpected device info: %v", row)
+		}
+		index, err := strconv.Atoi(row[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid device index %q: %w", row[0], err)
+		}
+		devices = append(devices, nvmlDevice{Index: index, UUID: row[1], Name: row[2]})
+	}
+	return devices, nil
+}
+
+// Power returns the current power draw of the device at index
+func (s *nvidiaSMI) Power(index int) (device.Power, error) {
+	rows, err := s.query("power.draw", "--id="+strconv.Itoa(index))
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return 0, fmt.Errorf("unexpected power reading for gpu %d: %v", index, rows)
+	}
+
+	watts, err := strconv.ParseFloat(rows[0][0], 64)
+	if err != nil {
+		return 0, device.Errorf(device.ErrUnsupportedHardware, "power reading of gpu %d not supported: %q", index, rows[0][0])
+	}
+	return device.Power(watts) * device.Watt, nil
+}
+
+// DeviceUtilization returns the utilization ratio of all devices keyed by device index
+func (s *nvidiaSMI) DeviceUtilization() (map[int]float64, error) {
+	rows, err := s.query("index,utilization.gpu")
+	if err != nil {
+		return nil, err
+	}
+
+	utilization := make(map[int]float64, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		index, err := strconv.Atoi(row[0])
+		if err != nil {
+			continue
+		}
+		percent, err := strconv.ParseFloat(row[1], 64)
+		if err != nil {
+			continue // [N/A]
+		}
+		utilization[index] = percent / 100
+	}
+	return utilization, nil
+}
+
+// ProcessUtilization returns the SM utilization ratio of processes keyed by
+// device index and PID using `nvidia-smi pmon` whose output looks like
+//
+//	# gpu         pid   type     sm    mem    enc    dec    command
+//	# Idx           #    C/G      %      %      %      %    name
+//	    0       12345     C      45     10      -      -    python
+func (s *nvidiaSMI) ProcessUtilization() (map[int]map[int]float64, error) {
+	out, err := s.run(s.path, "pmon", "--count=1", "--select=u")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read process utilization: %w", err)
+	}
+
+	gpuCol, pidCol, smCol := -1, -1, -1
+	utilization := map[int]map[int]float64{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "#" {
+			// use the first header line to locate the columns
+			if gpuCol == -1 {
+				for i, f := range fields[1:] {
+					switch f {
+					case "gpu":
+						gpuCol = i
+					case "pid":
+						pidCol = i
+					case "sm":
+						smCol = i
+					}
+				}
+			}
+			continue
+		}
+
+		if gpuCol == -1 || pidCol == -1 || smCol == -1 {
+			return nil, fmt.Errorf("unexpected pmon output: missing header")
+		}
+		if len(fields) <= max(gpuCol, pidCol, smCol) {
+			continue
+		}
+
+		index, err := strconv.Atoi(fields[gpuCol])
+		if err != nil {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[pidCol])
+		if err != nil {
+			continue // no processes running on the device shows "-"
+		}
+		percent, err := strconv.ParseFloat(fields[smCol], 64)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := utilization[index]; !ok {
+			utilization[index] = map[int]float64{}
+		}
+		utilization[index][pid] += percent / 100
+	}
+
+	return utilization, scanner.Err()
+}
+
+var (
+	// smiGPU matches a GPU listed by `nvidia-smi -L`, e.g.
+	// "GPU 0: NVIDIA A100-SXM4-40GB (UUID: GPU-5d5ba0d6-...)"
+	smiGPU = regexp.MustCompile(`^GPU (\d+): .*\(UUID: (GPU-[^)]+)\)`)
+
+	// smiMIG matches a MIG instance listed by `nvidia-smi -L` under its GPU,
+	// e.g. "  MIG 3g.20gb     Device  0: (UUID: MIG-1e4f...)"
+	smiMIG = regexp.MustCompile(`^\s+MIG ((\d+)g\.\S+)\s+Device\s+\d+: \(UUID: (MIG-[^)]+)\)`)
+)
+
+// MIGDevices returns the MIG instances of the GPUs in MIG mode keyed by the
+// index of the GPU
+func (s *nvidiaSMI) MIGDevices() (map[int][]migDevice, error) {
+	out, err := s.run(s.path, "-L")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MIG devices: %w", err)
+	}
+
+	migs := map[int][]migDevice{}
+	index := -1
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := smiGPU.FindStringSubmatch(line); m != nil {
+			index, _ = strconv.Atoi(m[1])
+			continue
+		}
+		m := smiMIG.FindStringSubmatch(line)
+		if m == nil || index < 0 {
+			continue
+		}
+		slices, _ := strconv.Atoi(m[2])
+		migs[index] = append(migs[index], migDevice{UUID: m[3], Profile: m[1], Slices: slices})
+	}
+	return migs, scanner.Err()
+}
+
+// ComputeApps returns the processes running compute work on the GPUs, which
+// include the clients of MPS servers
+func (s *nvidiaSMI) ComputeApps() ([]computeApp, error) {
+	rows, err := s.csv("--query-compute-apps=gpu_uuid,pid,process_name,used_memory")
+	if err != nil {
+		return nil, err
+	}
+
+	apps := make([]computeApp, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(row[1])
+		if err != nil {
+			continue
+		}
+		// the name of the process may have commas
+		name := strings.Join(row[2:len(row)-1], ",")
+		memory, _ := strconv.ParseFloat(row[len(row)-1], 64) // [N/A] without permission
+		apps = append(apps, computeApp{GPUUUID: row[0], PID: pid, Name: name, UsedMemory: memory})
+	}
+	return apps, nil
+}
+
+// mpsServer is the name of the MPS control daemon's server process, which runs
+// the work of the MPS clients of a GPU
+const mpsServer = "nvidia-cuda-mps-server"
+
+// nvidiaMeter implements PowerMeter for NVIDIA GPUs
+type nvidiaMeter struct {
+	logger     *slog.Logger
+	nvml       nvml
+	clock      clock.PassiveClock
+	checkpoint string
+	zones      []device.EnergyZone
+
+	uuids map[int]string      // UUIDs of the GPUs keyed by index
+	migs  map[int][]migDevice // MIG instances of the GPUs in MIG mode
+}
+
+var _ PowerMeter = (*nvidiaMeter)(nil)
+
+// NewNVIDIAMeter creates a new PowerMeter for NVIDIA GPUs
+func NewNVIDIAMeter(applyOpts ...OptionFn) *nvidiaMeter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &nvidiaMeter{
+		logger:     opts.logger.With("service", "nvidia-gpu"),
+		nvml:       &nvidiaSMI{path: opts.smiPath, run: timeoutRunner(opts.cmdTimeout)},
+		clock:      opts.clock,
+		checkpoint: opts.checkpoint,
+	}
+}
+
+func (m *nvidiaMeter) Name() string {
+	return "nvidia"
+}
+
+func (m *nvidiaMeter) Init() error {
+	devices, err := m.nvml.Devices()
+	if err != nil {
+		return fmt.Errorf("failed to list NVIDIA GPUs: %w", device.Classify(err))
+	}
+	if len(devices) == 0 {
+		return device.Errorf(device.ErrUnsupportedHardware, "no NVIDIA GPUs found")
+	}
+
+	m.zones = make([]device.EnergyZone, 0, len(devices))
+	m.uuids = make(map[int]string, len(devices))
+	for _, dev := range devices {
+		index := dev.Index
+		read := func() (device.Power, error) {
+			return m.nvml.Power(index)
+		}
+		zone := device.NewPowerZone(fmt.Sprintf("nvidia-gpu-%d", index), index, "nvml:"+dev.UUID, read, m.clock)
+		// ensure power can be read before the zone is used
+		if _, err := m.nvml.Power(index); err != nil {
+			return fmt.Errorf("failed to read power of gpu %d (%s): %w", index, dev.Name, device.Classify(err))
+		}
+		m.zones = append(m.zones, zone)
+		m.uuids[index] = dev.UUID
+		m.logger.Info("Found NVIDIA GPU", "index", index, "uuid", dev.UUID, "name", dev.Name)
+	}
+
+	// MIG instances are created by the administrator, so they are listed once
+	if m.migs, err = m.nvml.MIGDevices(); err != nil {
+		m.logger.Warn("Failed to list MIG instances; GPUs in MIG mode are not attributed", "error", err)
+	}
+	for index, instances := range m.migs {
+		for _, mig := range instances {
+			m.logger.Info("Found MIG instance", "gpu", index, "uuid", mig.UUID, "profile", mig.Profile)
+		}
+	}
+
+	return nil
+}
+
+func (m *nvidiaMeter) Zones() ([]device.EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no NVIDIA GPUs initialized")
+	}
+	return m.zones, nil
+}
+
+// Utilization returns the utilization of the GPUs by processes, and that of
+// the GPUs in MIG mode by the pods their instances are allocated to. The
+// utilization of MPS servers is that of their clients.
+func (m *nvidiaMeter) Utilization() (map[int]Utilization, error) {
+	devices, err := m.nvml.DeviceUtilization()
+	if err != nil {
+		return nil, device.Classify(err)
+	}
+
+	procs, err := m.nvml.ProcessUtilization()
+	if err != nil {
+		return nil, device.Classify(err)
+	}
+
+	ret := make(map[int]Utilization, len(devices))
+	for index, util := range devices {
+		ret[index] = Utilization{
+			Device:    util,
+			Processes: procs[index],
+		}
+	}
+
+	if apps, err := m.nvml.ComputeApps(); err != nil {
+		m.logger.Debug("Failed to list compute apps; MPS clients are not attributed", "error", err)
+	} else {
+		m.splitMPS(ret, apps)
+	}
+
+	if len(m.migs) > 0 {
+		allocations, err := m.allocations()
+		if err != nil {
+			return nil, err
+		}
+		for index, instances := range m.migs {
+			ret[index] = migUtilization(instances, allocations)
+		}
+	}
+	return ret, nil
+}
+
+// allocations returns the pods devices are allocated to by the device plugin
+func (m *nvidiaMeter) allocations() (map[string][]string, error) {
+	if m.checkpoint == "" {
+		return nil, nil
+	}
+	return readDeviceAllocations(m.checkpoint)
+}
+
+// splitMPS splits the utilization of the MPS server of each GPU among its
+// clients in proportion to the memory they use, or equally if it isn't
+// reported. Clients whose utilization is reported themselves, as on recent
+// GPUs, keep theirs and get no share of the server's.
+func (m *nvidiaMeter) splitMPS(util map[int]Utilization, apps []computeApp) {
+	for index, u := range util {
+		var server int
+		var clients []computeApp
+		for _, app := range apps {
+			if app.GPUUUID != m.uuids[index] {
+				continue
+			}
+			if filepath.Base(app.Name) == mpsServer {
+				server = app.PID
+				continue
+			}
+			clients = append(clients, app)
+		}
+
+		share, ok := u.Processes[server]
+		if server == 0 || !ok {
+			continue
+		}
+		clients = slices.DeleteFunc(clients, func(c computeApp) bool {
+			_, reported := u.Processes[c.PID]
+			return reported
+		})
+		if len(clients) == 0 {
+			continue
+		}
+
+		memory := 0.0
+		for _, client := range clients {
+			memory += client.UsedMemory
+		}
+		processes := make(map[int]float64, len(clients))
+		for _, client := range clients {
+			if memory > 0 {
+				processes[client.PID] += share * client.UsedMemory / memory
+			} else {
+				processes[client.PID] += share / float64(len(clients))
+			}
+		}
+		for pid, v := range u.Processes {
+			if pid != server {
+				processes[pid] += v
+			}
+		}
+		u.Processes = processes
+		util[index] = u
+	}
+}
+
+// migUtilization returns the utilization of a GPU in MIG mode, whose processes
+// aren't reported per instance: each instance allocated to pods is considered
+// busy and shared equally among its pods, so that the active power of the GPU
+// is split among the instances in use in proportion to their compute slices
+func migUtilization(instances []migDevice, allocations map[string][]string) Utilization {
+	total := 0
+	for _, mig := range instances {
+		total += mig.Slices
+	}
+	u := Utilization{Pods: map[string]float64{}}
+	if total == 0 {
+		return u
+	}
+
+	for _, mig := range instances {
+		pods := allocations[mig.UUID]
+		if len(pods) == 0 {
+			continue
+		}
+		share := float64(mig.Slices) / float64(total)
+		u.Device += share
+		for _, pod := range pods {
+			u.Pods[pod] += share / float64(len(pods))
+		}
+	}
+	return u
+}
@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+const pmonOutput = `# gpu         pid   type     sm    mem    enc    dec    command
+# Idx           #    C/G      %      %      %      %    name
+    0       1234     C      45     10      -      -    python
+    0       5678     C      15      2      -      -    python
+    1          -     -       -      -      -      -    -
+`
+
+// fakeSMI returns canned nvidia-smi output based on the arguments
+func fakeSMI(outputs map[string]string) cmdRunner {
+	return func(name string, args ...string) ([]byte, error) {
+		key := strings.Join(args, " ")
+		for prefix, out := range outputs {
+			if strings.HasPrefix(key, prefix) {
+				return []byte(out), nil
+			}
+		}
+		return nil, errors.New("unexpected command: " + key)
+	}
+}
+
+func TestNvidiaSMI_Devices(t *testing.T) {
+	smi := &nvidiaSMI{path: "nvidia-smi", run: fakeSMI(map[string]string{
+		"--query-gpu=index,uuid,name": "0, GPU-aaaa, NVIDIA A100\n1, GPU-bbbb, NVIDIA A100\n",
+	})}
+
+	devices, err := smi.Devices()
+	require.NoError(t, err)
+	assert.Equal(t, []nvmlDevice{
+		{Index: 0, UUID: "GPU-aaaa", Name: "NVIDIA A100"},
+		{Index: 1, UUID: "GPU-bbbb", Name: "NVIDIA A100"},
+	}, devices)
+}
+
+func TestNvidiaSMI_Power(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		smi := &nvidiaSMI{run: fakeSMI(map[string]string{"--query-gpu=power.draw": "55.5\n"})}
+		power, err := smi.Power(0)
+		require.NoError(t, err)
+		assert.InDelta(t, 55.5, power.Watts(), 0.0001)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		smi := &nvidiaSMI{run: fakeSMI(map[string]string{"--query-gpu=power.draw": "[N/A]\n"})}
+		_, err := smi.Power(0)
+		assert.ErrorContains(t, err, "not supported")
+	})
+}
+
+func TestNvidiaSMI_ProcessUtilization(t *testing.T) {
+	smi := &nvidiaSMI{run: fakeSMI(map[string]string{"pmon": pmonOutput})}
+
+	util, err := smi.ProcessUtilization()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]map[int]float64{
+		0: {1234: 0.45, 5678: 0.15},
+	}, util)
+}
+
+func TestNvidiaMeter(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter := NewNVIDIAMeter(WithClock(fakeClock))
+	meter.nvml = &nvidiaSMI{run: fakeSMI(map[string]string{
+		"--query-gpu=index,uuid,name":       "0, GPU-aaaa, NVIDIA A100\n",
+		"--query-gpu=power.draw":            "100\n",
+		"--query-gpu=index,utilization.gpu": "0, 60\n",
+		"pmon":                              pmonOutput,
+	})}
+
+	assert.Equal(t, "nvidia", meter.Name())
+
+	_, err := meter.Zones()
+	assert.Error(t, err, "zones are unavailable before Init")
+
+	require.NoError(t, meter.Init())
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.Equal(t, "nvidia-gpu-0", zones[0].Name())
+	assert.Equal(t, "nvml:GPU-aaaa", zones[0].Path())
+
+	_, err = zones[0].Energy()
+	require.NoError(t, err)
+	fakeClock.Step(10 * time.Second)
+	energy, err := zones[0].Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 1000.0, energy.Joules(), 0.001)
+
+	util, err := meter.Utilization()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]Utilization{
+		0: {Device: 0.6, Processes: map[int]float64{1234: 0.45, 5678: 0.15}},
+	}, util)
+}
+
+func TestNvidiaMeter_InitErrors(t *testing.T) {
+	t.Run("no devices", func(t *testing.T) {
+		meter := NewNVIDIAMeter()
+		meter.nvml = &nvidiaSMI{run: fakeSMI(map[string]string{"--query-gpu=index,uuid,name": ""})}
+		assert.ErrorContains(t, meter.Init(), "no NVIDIA GPUs found")
+	})
+
+	t.Run("nvidia-smi missing", func(t *testing.T) {
+		meter := NewNVIDIAMeter(WithNVIDIASMIPath("/non/existent/nvidia-smi"))
+		assert.Error(t, meter.Init())
+	})
+
+	t.Run("power not supported", func(t *testing.T) {
+		meter := NewNVIDIAMeter()
+		meter.nvml = &nvidiaSMI{run: fakeSMI(map[string]string{
+			"--query-gpu=index,uuid,name": "0, GPU-aaaa, NVIDIA T4\n",
+			"--query-gpu=power.draw":      "[Not Supported]\n",
+		})}
+		assert.ErrorContains(t, meter.Init(), "failed to read power of gpu 0")
+	})
+}
+
+const smiList = `GPU 0: NVIDIA A100-SXM4-40GB (UUID: GPU-aaaa)
+  MIG 3g.20gb     Device  0: (UUID: MIG-3333)
+  MIG 2g.10gb     Device  1: (UUID: MIG-2222)
+  MIG 1g.5gb      Device  2: (UUID: MIG-1111)
+  MIG 1g.5gb      Device  3: (UUID: MIG-1112)
+GPU 1: NVIDIA A100-SXM4-40GB (UUID: GPU-bbbb)
+`
+
+func TestNvidiaSMI_MIGDevices(t *testing.T) {
+	smi := &nvidiaSMI{run: fakeSMI(map[string]string{"-L": smiList})}
+
+	migs, err := smi.MIGDevices()
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]migDevice{
+		0: {
+			{UUID: "MIG-3333", Profile: "3g.20gb", Slices: 3},
+			{UUID: "MIG-2222", Profile: "2g.10gb", Slices: 2},
+			{UUID: "MIG-1111", Profile: "1g.5gb", Slices: 1},
+			{UUID: "MIG-1112", Profile: "1g.5gb", Slices: 1},
+		},
+	}, migs)
+}
+
+func TestNvidiaSMI_ComputeApps(t *testing.T) {
+	smi := &nvidiaSMI{run: fakeSMI(map[string]string{
+		"--query-compute-apps=gpu_uuid,pid,process_name,used_memory": "GPU-aaaa, 100, nvidia-cuda-mps-server, 30\n" +
+			"GPU-aaaa, 1234, /usr/bin/python3, 1000\n" +
+			"GPU-aaaa, 5678, train,v2, [N/A]\n",
+	})}
+
+	apps, err := smi.ComputeApps()
+	require.NoError(t, err)
+	assert.Equal(t, []computeApp{
+		{GPUUUID: "GPU-aaaa", PID: 100, Name: "nvidia-cuda-mps-server", UsedMemory: 30},
+		{GPUUUID: "GPU-aaaa", PID: 1234, Name: "/usr/bin/python3", UsedMemory: 1000},
+		{GPUUUID: "GPU-aaaa", PID: 5678, Name: "train,v2"},
+	}, apps)
+}
+
+func TestNvidiaMeter_MPS(t *testing.T) {
+	meter := NewNVIDIAMeter(WithDevicePluginCheckpoint(""))
+	meter.nvml = &nvidiaSMI{run: fakeSMI(map[string]string{
+		"--query-gpu=index,uuid,name":       "0, GPU-aaaa, NVIDIA V100\n",
+		"--query-gpu=power.draw":            "100\n",
+		"--query-gpu=index,utilization.gpu": "0, 80\n",
+		"-L":                                "GPU 0: NVIDIA V100 (UUID: GPU-aaaa)\n",
+		"pmon": `# gpu         pid   type     sm    mem    enc    dec    command
+# Idx           #    C/G      %      %      %      %    name
+    0        100     M      60     10      -      -    nvidia-cuda-mps
+    0        999     C      20      2      -      -    python
+`,
+		"--query-compute-apps=gpu_uuid,pid,process_name,used_memory": "GPU-aaaa, 100, nvidia-cuda-mps-server, 30\n" +
+			"GPU-aaaa, 999, python, 500\n" +
+			"GPU-aaaa, 1234, python, 3000\n" +
+			"GPU-aaaa, 5678, python, 1000\n",
+	})}
+	require.NoError(t, meter.Init())
+
+	util, err := meter.Utilization()
+	require.NoError(t, err)
+	require.Contains(t, util, 0)
+	assert.Equal(t, 0.8, util[0].Device)
+	// the 60% of the server is split by their memory among its clients whose
+	// utilization isn't reported
+	assert.InDelta(t, 0.2, util[0].Processes[999], 0.0001)
+	assert.InDelta(t, 0.45, util[0].Processes[1234], 0.0001)
+	assert.InDelta(t, 0.15, util[0].Processes[5678], 0.0001)
+	assert.NotContains(t, util[0].Processes, 100)
+}
+
+func TestNvidiaMeter_MIG(t *testing.T) {
+	checkpoint := filepath.Join(t.TempDir(), "kubelet_internal_checkpoint")
+	require.NoError(t, os.WriteFile(checkpoint, []byte(`{"Data":{"PodDeviceEntries":[
+		{"PodUID":"pod-a","ContainerName":"train","ResourceName":"nvidia.com/mig-3g.20gb","DeviceIDs":{"0":["MIG-3333"]},"AllocResp":""},
+		{"PodUID":"pod-b","ContainerName":"infer","ResourceName":"nvidia.com/mig-1g.5gb","DeviceIDs":{"0":["MIG-1111::0"]},"AllocResp":""},
+		{"PodUID":"pod-c","ContainerName":"infer","ResourceName":"nvidia.com/mig-1g.5gb","DeviceIDs":{"0":["MIG-1111::1"]},"AllocResp":""},
+		{"PodUID":"pod-d","ContainerName":"nic","ResourceName":"example.com/nic","DeviceIDs":{"0":["MIG-1112"]},"AllocResp":""}
+	],"RegisteredDevices":{}},"Checksum":1}`), 0o644))
+
+	meter := NewNVIDIAMeter(WithDevicePluginCheckpoint(checkpoint))
+	meter.nvml = &nvidiaSMI{run: fakeSMI(map[string]string{
+		"--query-gpu=index,uuid,name":       "0, GPU-aaaa, NVIDIA A100\n1, GPU-bbbb, NVIDIA A100\n",
+		"--query-gpu=power.draw":            "100\n",
+		"--query-gpu=index,utilization.gpu": "0, [N/A]\n1, 50\n",
+		"-L":                                smiList,
+		"pmon":                              pmonOutput,
+	})}
+	require.NoError(t, meter.Init())
+
+	util, err := meter.Utilization()
+	require.NoError(t, err)
+	// the instances of 3 and 1 of the 7 slices of GPU 0 are in use
+	assert.InDelta(t, 4.0/7, util[0].Device, 0.0001)
+	assert.Empty(t, util[0].Processes)
+	assert.Len(t, util[0].Pods, 3)
+	assert.InDelta(t, 3.0/7, util[0].Pods["pod-a"], 0.0001)
+	assert.InDelta(t, 0.5/7, util[0].Pods["pod-b"], 0.0001)
+	assert.InDelta(t, 0.5/7, util[0].Pods["pod-c"], 0.0001)
+
+	// GPUs not in MIG mode are unchanged
+	assert.Equal(t, Utilization{Device: 0.5}, util[1])
+}
+
+func TestReadDeviceAllocations(t *testing.T) {
+	dir := t.TempDir()
+
+	allocations, err := readDeviceAllocations(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, allocations)
+
+	// device IDs are a list before Kubernetes 1.20
+	path := filepath.Join(dir, "checkpoint")
+	require.NoError(t, os.WriteFile(path, []byte(`{"Data":{"PodDeviceEntries":[
+		{"PodUID":"pod-a","ResourceName":"nvidia.com/gpu","DeviceIDs":["GPU-aaaa::0","GPU-aaaa::1"]},
+		{"PodUID":"pod-b","ResourceName":"nvidia.com/gpu","DeviceIDs":["GPU-aaaa::2"]}
+	]}}`), 0o644))
+	allocations, err = readDeviceAllocations(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"GPU-aaaa": {"pod-a", "pod-b"}}, allocations)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"Data":`), 0o644))
+	_, err = readDeviceAllocations(path)
+	assert.ErrorContains(t, err, "invalid device plugin checkpoint")
+}
+
+func TestTimeoutRunner(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not found")
+	}
+
+	out, err := timeoutRunner(time.Second)("echo", "ok")
+	require.NoError(t, err)
+	assert.Equal(t, "ok\n", string(out))
+
+	_, err = timeoutRunner(10*time.Millisecond)("sleep", "5")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "sleep timed out after 10ms")
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gpu
+
+import (
+	"log/slog"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// Opts holds the options for GPU power meters
+type Opts struct {
+	logger  *slog.Logger
+	clock   clock.PassiveClock
+	smiPath string
+	sysfs   string
+	procfs  string
+
+	checkpoint string
+	hlSMIPath  string
+	cmdTimeout time.Duration
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// DefaultOpts returns the default options
+func DefaultOpts() Opts {
+	return Opts{
+		logger:  slog.Default(),
+		clock:   clock.RealClock{},
+		smiPath: "nvidia-smi",
+		sysfs:   "/sys",
+		procfs:  "/proc",
+
+		checkpoint: DefaultDevicePluginCheckpoint,
+		hlSMIPath:  "hl-smi",
+		cmdTimeout: 5 * time.Second,
+	}
+}
+
+// WithLogger sets the logger for the GPU power meter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to integrate power readings
+func WithClock(c clock.PassiveClock) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithNVIDIASMIPath sets the path to the nvidia-smi binary
+func WithNVIDIASMIPath(path string) OptionFn {
+	return func(o *Opts) {
+		if path != "" {
+			o.smiPath = path
+		}
+	}
+}
+
+// WithHabanaSMIPath sets the path to the hl-smi binary of Intel Gaudi
+// accelerators
+func WithHabanaSMIPath(path string) OptionFn {
+	return func(o *Opts) {
+		if path != "" {
+			o.hlSMIPath = path
+		}
+	}
+}
+
+// WithCommandTimeout sets how long a run of nvidia-smi or hl-smi may take before it is
+// killed and the reading fails; non-positive values keep the default
+func WithCommandTimeout(timeout time.Duration) OptionFn {
+	return func(o *Opts) {
+		if timeout > 0 {
+			o.cmdTimeout = timeout
+		}
+	}
+}
+
+// WithSysFSPath sets the path to sysfs used to discover GPUs
+func WithSysFSPath(path string) OptionFn {
+	return func(o *Opts) {
+		o.sysfs = path
+	}
+}
+
+// WithProcFSPath sets the path to procfs used to read per-process GPU usage
+func WithProcFSPath(path string) OptionFn {
+	return func(o *Opts) {
+		o.procfs = path
+	}
+}
+
+// WithDevicePluginCheckpoint sets the checkpoint of the device manager of the
+// kubelet used to find the pods that MIG instances and shared GPUs are
+// allocated to; empty disables it
+func WithDevicePluginCheckpoint(path string) OptionFn {
+	return func(o *Opts) {
+		o.checkpoint = path
+	}
+}
@@ -0,0 +1,330 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/vsock"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/utils/clock"
+)
+
+// guestEnergyMetric is the metric of the energy of VMs exported by Kepler on
+// the host
+const guestEnergyMetric = "kepler_vm_cpu_joules_total"
+
+// guestCacheDuration is how long the energy fetched from the host is used for
+// all zones, so that it is fetched once per refresh rather than once per zone
+const guestCacheDuration = time.Second
+
+// guestFetchTimeout is the timeout of fetching the metrics of the host
+const guestFetchTimeout = 5 * time.Second
+
+// guestPowerMeter implements CPUPowerMeter for Kepler running in a VM, which
+// has no RAPL, by reading the energy attributed to the VM by Kepler on the
+// host. Each zone of the VM on the host is reported as a zone of the guest, so
+// that the power of the host is attributed end to end to the workloads of the
+// guest.
+type guestPowerMeter struct {
+	logger   *slog.Logger
+	sysfs    string
+	endpoint string // metrics endpoint of Kepler on the host
+	url      string // URL the metrics are fetched from
+	vms      bool   // whether endpoint serves the power of VMs rather than metrics
+	client   *http.Client
+	vmID     string
+	clock    clock.PassiveClock
+
+	zones []EnergyZone
+
+	mu        sync.Mutex
+	energy    map[string]Energy // energy of the zones of the VM in the last fetch
+	fetchedAt time.Time
+}
+
+var _ CPUPowerMeter = (*guestPowerMeter)(nil)
+
+// GuestOptFn is a functional option for configuring the guest power meter
+type GuestOptFn func(*guestPowerMeter)
+
+// WithGuestLogger sets the logger for the guest power meter
+func WithGuestLogger(logger *slog.Logger) GuestOptFn {
+	return func(m *guestPowerMeter) {
+		m.logger = logger.With("service", "guest")
+	}
+}
+
+// WithGuestClock sets the clock used to cache the energy fetched from the host
+func WithGuestClock(c clock.PassiveClock) GuestOptFn {
+	return func(m *guestPowerMeter) {
+		m.clock = c
+	}
+}
+
+// WithVMID sets the ID of the VM on the host; by default it is the SMBIOS
+// system UUID of the VM, which is the ID of VMs started with a UUID (e.g. by
+// libvirt)
+func WithVMID(id string) GuestOptFn {
+	return func(m *guestPowerMeter) {
+		m.vmID = id
+	}
+}
+
+// NewGuestPowerMeter creates a new CPU power meter that reads the energy of the
+// VM it runs in from Kepler on the host at endpoint. Endpoints are http(s)
+// URLs, or vsock://<cid>:<port>/<path> to fetch over vsock, e.g.
+// vsock://2:28282/vms from the host. Endpoints whose path is /vms serve the
+// power of each VM at /vms/<id> (see the VM exporter); any other endpoint
+// serves the metrics of the host.
+func NewGuestPowerMeter(sysfsPath, endpoint string, opts ...GuestOptFn) (*guestPowerMeter, error) {
+	client, u, err := guestClient(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid guest endpoint %q: %w", endpoint, err)
+	}
+
+	ret := &guestPowerMeter{
+		logger:   slog.Default().With("service", "guest"),
+		sysfs:    sysfsPath,
+		endpoint: endpoint,
+		url:      u,
+		vms:      strings.HasSuffix(strings.TrimSuffix(u, "/"), "/vms"),
+		client:   client,
+		clock:    clock.RealClock{},
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret, nil
+}
+
+// guestClient returns the HTTP client and the URL to fetch metrics from
+// endpoint
+func guestClient(endpoint string) (*http.Client, string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &http.Client{Timeout: guestFetchTimeout}
+	switch u.Scheme {
+	case "http", "https":
+		return client, endpoint, nil
+	case "vsock":
+		cid, err := strconv.ParseUint(u.Hostname(), 10, 32)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid vsock context ID %q", u.Hostname())
+		}
+		port, err := strconv.ParseUint(u.Port(), 10, 32)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid vsock port %q", u.Port())
+		}
+		client.Transport = &http.Transport{
+			DialContext: func(context.Context, string, string) (net.Conn, error) {
+				return vsock.Dial(uint32(cid), uint32(port), nil)
+			},
+		}
+		u.Scheme = "http"
+		return client, u.String(), nil
+	default:
+		return nil, "", fmt.Errorf("unsupported scheme %q; must be one of http, https, vsock", u.Scheme)
+	}
+}
+
+func (m *guestPowerMeter) Name() string {
+	return "guest"
+}
+
+// Init finds the zones of the VM in the metrics of the host and creates a zone
+// of each
+func (m *guestPowerMeter) Init() error {
+	if m.vmID == "" {
+		id, err := os.ReadFile(filepath.Join(m.sysfs, "class", "dmi", "id", "product_uuid"))
+		if err != nil {
+			return fmt.Errorf("failed to read the system UUID of the VM: %w", Classify(err))
+		}
+		m.vmID = strings.TrimSpace(string(id))
+	}
+	if m.vms {
+		m.url = strings.TrimSuffix(m.url, "/") + "/" + url.PathEscape(m.vmID)
+	}
+
+	energy, err := m.fetch()
+	if err != nil {
+		return err
+	}
+	if len(energy) == 0 {
+		return Errorf(ErrUnsupportedHardware, "no energy of VM %s found on the host at %s", m.vmID, m.endpoint)
+	}
+	m.energy, m.fetchedAt = energy, m.clock.Now()
+
+	names := slices.Sorted(maps.Keys(energy))
+	m.zones = make([]EnergyZone, 0, len(names))
+	for i, name := range names {
+		m.zones = append(m.zones, &guestZone{meter: m, name: name, index: i})
+		m.logger.Info("Found zone of the VM on the host", "zone", name, "vm", m.vmID, "endpoint", m.endpoint)
+	}
+	return nil
+}
+
+func (m *guestPowerMeter) Zones() ([]EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no guest zones initialized")
+	}
+	return m.zones, nil
+}
+
+// PrimaryEnergyZone returns the zone with the highest energy coverage/priority
+func (m *guestPowerMeter) PrimaryEnergyZone() (EnergyZone, error) {
+	zones, err := m.Zones()
+	if err != nil {
+		return nil, err
+	}
+	return preferredZone(zones), nil
+}
+
+// zoneEnergy returns the energy of a zone of the VM; the metrics of the host
+// are fetched at most once per guestCacheDuration
+func (m *guestPowerMeter) zoneEnergy(zone string) (Energy, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.energy == nil || m.clock.Since(m.fetchedAt) >= guestCacheDuration {
+		energy, err := m.fetch()
+		if err != nil {
+			return 0, err
+		}
+		m.energy, m.fetchedAt = energy, m.clock.Now()
+	}
+
+	energy, ok := m.energy[zone]
+	if !ok {
+		return 0, fmt.Errorf("zone %s of VM %s not found on the host", zone, m.vmID)
+	}
+	return energy, nil
+}
+
+// fetch returns the energy of the zones of the running VM from the host
+func (m *guestPowerMeter) fetch() (map[string]Energy, error) {
+	req, err := http.NewRequest(http.MethodGet, m.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !m.vms {
+		req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the power of the VM from the host: %w", Classify(err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case m.vms && resp.StatusCode == http.StatusNotFound:
+		return map[string]Energy{}, nil // the host doesn't know the VM
+	case resp.StatusCode != http.StatusOK:
+		return nil, Errorf(ErrTransient, "failed to fetch the power of the VM from the host at %s: %s", m.endpoint, resp.Status)
+	}
+
+	if m.vms {
+		return decodeGuestVM(resp.Body)
+	}
+	return m.parseMetrics(resp.Body)
+}
+
+// decodeGuestVM returns the energy of the zones of a VM served by the VM
+// exporter of the host
+func decodeGuestVM(r io.Reader) (map[string]Energy, error) {
+	var vm struct {
+		Zones map[string]struct {
+			Joules float64 `json:"joules"`
+		} `json:"zones"`
+	}
+	if err := json.NewDecoder(r).Decode(&vm); err != nil {
+		return nil, fmt.Errorf("failed to decode the power of the VM: %w", err)
+	}
+
+	energy := make(map[string]Energy, len(vm.Zones))
+	for zone, z := range vm.Zones {
+		energy[zone] = Energy(z.Joules * float64(Joule))
+	}
+	return energy, nil
+}
+
+// parseMetrics returns the energy of the zones of the running VM in the
+// metrics of the host
+func (m *guestPowerMeter) parseMetrics(r io.Reader) (map[string]Energy, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the metrics of the host: %w", err)
+	}
+
+	energy := map[string]Energy{}
+	for _, metric := range families[guestEnergyMetric].GetMetric() {
+		var id, zone, state string
+		for _, label := range metric.GetLabel() {
+			switch label.GetName() {
+			case "vm_id":
+				id = label.GetValue()
+			case "zone":
+				zone = label.GetValue()
+			case "state":
+				state = label.GetValue()
+			}
+		}
+		if !strings.EqualFold(id, m.vmID) || state != "running" {
+			continue
+		}
+		energy[zone] += Energy(metric.GetCounter().GetValue() * float64(Joule))
+	}
+	return energy, nil
+}
+
+// guestZone is a zone of the VM on the host
+type guestZone struct {
+	meter *guestPowerMeter
+	name  string
+	index int
+}
+
+var _ EnergyZone = (*guestZone)(nil)
+
+func (z *guestZone) Name() string {
+	return z.name
+}
+
+func (z *guestZone) Index() int {
+	return z.index
+}
+
+func (z *guestZone) Path() string {
+	return z.meter.endpoint
+}
+
+func (z *guestZone) Energy() (Energy, error) {
+	return z.meter.zoneEnergy(z.name)
+}
+
+// MaxEnergy returns 0 since the energy of the VM does not wrap around; it is
+// only reset when Kepler on the host restarts
+func (z *guestZone) MaxEnergy() Energy {
+	return 0
+}
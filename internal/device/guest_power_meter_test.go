@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+const vmID = "df12672f-fedb-4f6f-9d51-0166868835fb"
+
+// fakeHost serves metrics of Kepler on a host running the VM; the energy of
+// the VM increases by 10 J in package and 2 J in dram per request
+func fakeHost(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	host := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := float64(requests.Add(1))
+		_, _ = fmt.Fprintf(w, `# HELP kepler_vm_cpu_joules_total Energy consumption of cpu at vm level in joules
+# TYPE kepler_vm_cpu_joules_total counter
+kepler_vm_cpu_joules_total{hypervisor="kvm",node_name="host",state="running",vm_id=%[1]q,vm_name="guest",zone="package"} %[2]g
+kepler_vm_cpu_joules_total{hypervisor="kvm",node_name="host",state="running",vm_id=%[1]q,vm_name="guest",zone="dram"} %[3]g
+kepler_vm_cpu_joules_total{hypervisor="kvm",node_name="host",state="running",vm_id="other",vm_name="other",zone="package"} 1000
+kepler_vm_cpu_joules_total{hypervisor="kvm",node_name="host",state="terminated",vm_id=%[1]q,vm_name="guest",zone="package"} 1000
+# HELP kepler_node_cpu_joules_total Energy consumption of cpu at node level in joules
+# TYPE kepler_node_cpu_joules_total counter
+kepler_node_cpu_joules_total{node_name="host",path="/sys/class/powercap/intel-rapl:0",zone="package"} 5000
+`, vmID, 10*n, 2*n)
+	}))
+	t.Cleanup(host.Close)
+	return host, &requests
+}
+
+func TestGuestPowerMeter(t *testing.T) {
+	host, requests := fakeHost(t)
+	sysfs := t.TempDir()
+	writeSysfs(t, sysfs, map[string]string{
+		"class/dmi/id/product_uuid": vmID,
+	})
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter, err := NewGuestPowerMeter(sysfs, host.URL+"/metrics", WithGuestClock(fakeClock))
+	require.NoError(t, err)
+	assert.Equal(t, "guest", meter.Name())
+	require.NoError(t, meter.Init())
+
+	assert.Equal(t, []string{"dram", "package"}, zoneNames(t, meter))
+	primary, err := meter.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, "package", primary.Name())
+	assert.Equal(t, host.URL+"/metrics", primary.Path())
+
+	zones, _ := meter.Zones()
+	for _, z := range zones {
+		_, err := z.Energy()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), requests.Load(), "metrics fetched by Init are used by the first refresh")
+
+	fakeClock.Step(5 * time.Second)
+	energy, err := primary.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 20.0, energy.Joules(), 0.001)
+	energy, err = zones[0].Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 4.0, energy.Joules(), 0.001)
+	assert.Equal(t, int32(2), requests.Load(), "metrics are fetched once for all zones")
+}
+
+func TestGuestPowerMeter_VMs(t *testing.T) {
+	host := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vms/"+vmID {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"id":"`+vmID+`","zones":{"package":{"joules":150,"watts":15},"dram":{"joules":20,"watts":2}}}`)
+	}))
+	t.Cleanup(host.Close)
+
+	meter, err := NewGuestPowerMeter(t.TempDir(), host.URL+"/vms", WithVMID(vmID))
+	require.NoError(t, err)
+	require.NoError(t, meter.Init())
+	assert.Equal(t, []string{"dram", "package"}, zoneNames(t, meter))
+
+	primary, err := meter.PrimaryEnergyZone()
+	require.NoError(t, err)
+	energy, err := primary.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 150.0, energy.Joules(), 0.001)
+
+	unknown, err := NewGuestPowerMeter(t.TempDir(), host.URL+"/vms/", WithVMID("unknown"))
+	require.NoError(t, err)
+	assert.ErrorIs(t, unknown.Init(), ErrUnsupportedHardware)
+}
+
+func TestGuestPowerMeter_Errors(t *testing.T) {
+	host, _ := fakeHost(t)
+
+	t.Run("unknown VM", func(t *testing.T) {
+		meter, err := NewGuestPowerMeter(t.TempDir(), host.URL, WithVMID("unknown"))
+		require.NoError(t, err)
+		assert.ErrorContains(t, meter.Init(), "no energy of VM unknown found")
+	})
+
+	t.Run("no system UUID", func(t *testing.T) {
+		meter, err := NewGuestPowerMeter(t.TempDir(), host.URL)
+		require.NoError(t, err)
+		assert.ErrorContains(t, meter.Init(), "failed to read the system UUID of the VM")
+	})
+
+	t.Run("host unavailable", func(t *testing.T) {
+		down := httptest.NewServer(http.NotFoundHandler())
+		meter, err := NewGuestPowerMeter(t.TempDir(), down.URL, WithVMID(vmID))
+		require.NoError(t, err)
+		assert.ErrorContains(t, meter.Init(), "404 Not Found")
+		down.Close()
+		assert.ErrorIs(t, meter.Init(), ErrTransient)
+	})
+
+	for _, endpoint := range []string{"ftp://host/metrics", "vsock://host:28282/metrics", "vsock://2/metrics"} {
+		t.Run(endpoint, func(t *testing.T) {
+			_, err := NewGuestPowerMeter(t.TempDir(), endpoint)
+			assert.ErrorContains(t, err, "invalid guest endpoint")
+		})
+	}
+}
+
+func TestGuestClient(t *testing.T) {
+	client, url, err := guestClient("vsock://2:28282/metrics")
+	require.NoError(t, err)
+	assert.Equal(t, "http://2:28282/metrics", url)
+	assert.NotNil(t, client.Transport)
+}
@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/clock"
+)
+
+// HwmonRail maps a power rail measured by a hwmon sensor to a zone
+type HwmonRail struct {
+	Zone    string `yaml:"zone"`    // zone the rail is reported as; rails of the same zone are summed
+	Sensor  string `yaml:"sensor"`  // name of the hwmon sensor, e.g. ina3221
+	Label   string `yaml:"label"`   // label of the rail, e.g. VDD_CPU_GPU_CV
+	Channel int    `yaml:"channel"` // channel of the rail if Label is empty; defaults to 1
+}
+
+// hwmonPowerMeter implements CPUPowerMeter for platforms without RAPL (e.g. ARM
+// SBCs and edge devices) by reading the power of rails measured by sensors
+// like the INA226 or INA3221 in hwmon
+type hwmonPowerMeter struct {
+	logger *slog.Logger
+	sysfs  string
+	rails  []HwmonRail
+	clock  clock.PassiveClock
+
+	zones []EnergyZone
+}
+
+var _ CPUPowerMeter = (*hwmonPowerMeter)(nil)
+
+// HwmonOptFn is a functional option for configuring the hwmon power meter
+type HwmonOptFn func(*hwmonPowerMeter)
+
+// WithHwmonLogger sets the logger for the hwmon power meter
+func WithHwmonLogger(logger *slog.Logger) HwmonOptFn {
+	return func(m *hwmonPowerMeter) {
+		m.logger = logger.With("service", "hwmon")
+	}
+}
+
+// WithHwmonClock sets the clock used to integrate the power of rails
+func WithHwmonClock(c clock.PassiveClock) HwmonOptFn {
+	return func(m *hwmonPowerMeter) {
+		m.clock = c
+	}
+}
+
+// NewHwmonPowerMeter creates a new CPU power meter that reads the power of
+// rails from hwmon sensors in sysfs
+func NewHwmonPowerMeter(sysfsPath string, rails []HwmonRail, opts ...HwmonOptFn) *hwmonPowerMeter {
+	ret := &hwmonPowerMeter{
+		logger: slog.Default().With("service", "hwmon"),
+		sysfs:  sysfsPath,
+		rails:  rails,
+		clock:  clock.RealClock{},
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+func (m *hwmonPowerMeter) Name() string {
+	return "hwmon"
+}
+
+// Init finds the sensors of all rails and creates a zone of each distinct zone
+// name, in the order of the rails
+func (m *hwmonPowerMeter) Init() error {
+	if len(m.rails) == 0 {
+		return fmt.Errorf("no hwmon rails configured")
+	}
+
+	sensors, err := hwmonSensors(m.sysfs)
+	if err != nil {
+		return err
+	}
+
+	type zoneRails struct {
+		name    string
+		paths   []string
+		readers []PowerReaderFn
+	}
+	var zones []*zoneRails
+	byName := map[string]*zoneRails{}
+
+	for _, rail := range m.rails {
+		path, read, err := findRail(sensors, rail)
+		if err != nil {
+			return err
+		}
+		if _, err := read(); err != nil {
+			return fmt.Errorf("failed to read power of rail %s: %w", path, Classify(err))
+		}
+
+		z, ok := byName[rail.Zone]
+		if !ok {
+			z = &zoneRails{name: rail.Zone}
+			byName[rail.Zone] = z
+			zones = append(zones, z)
+		}
+		z.paths = append(z.paths, path)
+		z.readers = append(z.readers, read)
+		m.logger.Info("Found hwmon power rail", "zone", rail.Zone, "sensor", rail.Sensor, "path", path)
+	}
+
+	m.zones = make([]EnergyZone, 0, len(zones))
+	for i, z := range zones {
+		readers := z.readers
+		read := func() (Power, error) {
+			total := Power(0)
+			for _, read := range readers {
+				p, err := read()
+				if err != nil {
+					return 0, err
+				}
+				total += p
+			}
+			return total, nil
+		}
+		m.zones = append(m.zones, NewPowerZone(z.name, i, strings.Join(z.paths, ","), read, m.clock))
+	}
+	return nil
+}
+
+func (m *hwmonPowerMeter) Zones() ([]EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no hwmon zones initialized")
+	}
+	return m.zones, nil
+}
+
+// PrimaryEnergyZone returns the zone with the highest energy coverage/priority
+func (m *hwmonPowerMeter) PrimaryEnergyZone() (EnergyZone, error) {
+	zones, err := m.Zones()
+	if err != nil {
+		return nil, err
+	}
+	return preferredZone(zones), nil
+}
+
+// preferredZone returns the first of the psys, package, core, dram and uncore
+// zones, or else the first zone
+func preferredZone(zones []EnergyZone) EnergyZone {
+	for _, p := range []string{"psys", "package", "core", "dram", "uncore"} {
+		for _, zone := range zones {
+			if strings.EqualFold(zone.Name(), p) {
+				return zone
+			}
+		}
+	}
+	return zones[0]
+}
+
+// hwmonSensor is a hwmon device and the name of its sensor
+type hwmonSensor struct {
+	dir  string
+	name string
+}
+
+// hwmonSensors returns all hwmon devices in sysfs ordered by path
+func hwmonSensors(sysfsPath string) ([]hwmonSensor, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysfsPath, "class", "hwmon", "hwmon[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+
+	sensors := make([]hwmonSensor, 0, len(dirs))
+	for _, dir := range dirs {
+		name, err := os.ReadFile(filepath.Join(dir, "name"))
+		if err != nil {
+			continue
+		}
+		sensors = append(sensors, hwmonSensor{dir: dir, name: strings.TrimSpace(string(name))})
+	}
+	return sensors, nil
+}
+
+// findRail returns the path and the power reader of rail in the first sensor
+// that has it. Power is read from powerN_input (µW) if the sensor reports it,
+// as the INA226 does, or else computed from the bus voltage inN_input (mV) and
+// current currN_input (mA), as reported by the INA3221
+func findRail(sensors []hwmonSensor, rail HwmonRail) (string, PowerReaderFn, error) {
+	for _, sensor := range sensors {
+		if !strings.EqualFold(sensor.name, rail.Sensor) {
+			continue
+		}
+
+		channel, ok := railChannel(sensor.dir, rail)
+		if !ok {
+			continue
+		}
+
+		power := filepath.Join(sensor.dir, fmt.Sprintf("power%d_input", channel))
+		if _, err := os.Stat(power); err == nil {
+			return power, func() (Power, error) {
+				uw, err := readUint(power)
+				return Power(uw) * MicroWatt, err
+			}, nil
+		}
+
+		voltage := filepath.Join(sensor.dir, fmt.Sprintf("in%d_input", channel))
+		current := filepath.Join(sensor.dir, fmt.Sprintf("curr%d_input", channel))
+		if _, err := os.Stat(current); err == nil {
+			return current, func() (Power, error) {
+				mv, err := readUint(voltage)
+				if err != nil {
+					return 0, err
+				}
+				ma, err := readUint(current)
+				// mV × mA = µW
+				return Power(mv*ma) * MicroWatt, err
+			}, nil
+		}
+	}
+
+	if rail.Label != "" {
+		return "", nil, Errorf(ErrUnsupportedHardware, "rail %q of hwmon sensor %q not found", rail.Label, rail.Sensor)
+	}
+	return "", nil, Errorf(ErrUnsupportedHardware, "channel %d of hwmon sensor %q not found", max(rail.Channel, 1), rail.Sensor)
+}
+
+// railChannel returns the channel of rail in a hwmon device; rails are found
+// by the label of any of the power, voltage or current inputs of a channel
+func railChannel(dir string, rail HwmonRail) (int, bool) {
+	if rail.Label == "" {
+		return max(rail.Channel, 1), true
+	}
+
+	labels, _ := filepath.Glob(filepath.Join(dir, "*_label"))
+	sort.Strings(labels)
+	for _, path := range labels {
+		label, err := os.ReadFile(path)
+		if err != nil || strings.TrimSpace(string(label)) != rail.Label {
+			continue
+		}
+
+		// e.g. in1_label, power2_label, curr3_label
+		name := strings.TrimSuffix(filepath.Base(path), "_label")
+		name = strings.TrimLeft(name, "abcdefghijklmnopqrstuvwxyz")
+		if channel, err := strconv.Atoi(name); err == nil {
+			return channel, true
+		}
+	}
+	return 0, false
+}
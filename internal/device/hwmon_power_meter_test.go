@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// fakeHwmonSysfs creates a sysfs tree with an INA3221 (hwmon0) measuring three
+// labelled rails and an INA226 (hwmon1) measuring a single rail
+func fakeHwmonSysfs(t *testing.T) string {
+	t.Helper()
+	sysfs := t.TempDir()
+	write := func(path, content string) {
+		path = filepath.Join(sysfs, "class", "hwmon", path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content+"\n"), 0o644))
+	}
+
+	write("hwmon0/name", "ina3221")
+	write("hwmon0/in1_label", "VDD_IN")
+	write("hwmon0/in1_input", "5000")
+	write("hwmon0/curr1_input", "1200")
+	write("hwmon0/in2_label", "VDD_CPU_GPU_CV")
+	write("hwmon0/in2_input", "5000")
+	write("hwmon0/curr2_input", "400")
+	write("hwmon0/in3_label", "VDD_SOC")
+	write("hwmon0/in3_input", "5000")
+	write("hwmon0/curr3_input", "200")
+
+	write("hwmon1/name", "ina226")
+	write("hwmon1/power1_input", "3500000")
+	return sysfs
+}
+
+func TestHwmonPowerMeter(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter := NewHwmonPowerMeter(fakeHwmonSysfs(t), []HwmonRail{
+		{Zone: "psys", Sensor: "ina3221", Label: "VDD_IN"},
+		{Zone: "package", Sensor: "ina3221", Label: "VDD_CPU_GPU_CV"},
+		{Zone: "package", Sensor: "ina3221", Label: "VDD_SOC"},
+		{Zone: "dram", Sensor: "INA226"},
+	}, WithHwmonClock(fakeClock))
+	assert.Equal(t, "hwmon", meter.Name())
+
+	_, err := meter.Zones()
+	assert.Error(t, err, "zones are not available before Init")
+	require.NoError(t, meter.Init())
+
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 3)
+
+	primary, err := meter.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, "psys", primary.Name())
+
+	tt := []struct {
+		name  string
+		index int
+		watts float64
+	}{
+		{"psys", 0, 6},
+		{"package", 1, 2 + 1},
+		{"dram", 2, 3.5},
+	}
+	for _, tc := range tt {
+		_, err := zones[tc.index].Energy()
+		require.NoError(t, err)
+	}
+	fakeClock.Step(2 * time.Second)
+	for _, tc := range tt {
+		zone := zones[tc.index]
+		assert.Equal(t, tc.name, zone.Name())
+		assert.Equal(t, tc.index, zone.Index())
+		energy, err := zone.Energy()
+		require.NoError(t, err)
+		assert.InDelta(t, tc.watts*2, energy.Joules(), 0.001, tc.name)
+	}
+	assert.Contains(t, zones[1].Path(), "curr2_input")
+	assert.Contains(t, zones[1].Path(), "curr3_input")
+}
+
+func TestHwmonPowerMeter_Errors(t *testing.T) {
+	sysfs := fakeHwmonSysfs(t)
+	tt := []struct {
+		name  string
+		rails []HwmonRail
+		err   string
+	}{
+		{"no rails", nil, "no hwmon rails configured"},
+		{"unknown sensor", []HwmonRail{{Zone: "package", Sensor: "ina219"}}, `channel 1 of hwmon sensor "ina219" not found`},
+		{"unknown label", []HwmonRail{{Zone: "package", Sensor: "ina3221", Label: "VDD_GPU"}}, `rail "VDD_GPU" of hwmon sensor "ina3221" not found`},
+		{"unknown channel", []HwmonRail{{Zone: "package", Sensor: "ina226", Channel: 2}}, `channel 2 of hwmon sensor "ina226" not found`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			meter := NewHwmonPowerMeter(sysfs, tc.rails)
+			assert.ErrorContains(t, meter.Init(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// IOModel estimates the power (in watts) of network interfaces or block
+// devices from their throughput (in GB/s) as:
+//
+//	power = IdleWatts + WattsPerGBps × throughput
+type IOModel struct {
+	IdleWatts    float64 `yaml:"idleWatts"`
+	WattsPerGBps float64 `yaml:"wattsPerGBps"`
+}
+
+// IsZero returns true if the model estimates no power
+func (m IOModel) IsZero() bool {
+	return m.IdleWatts == 0 && m.WattsPerGBps == 0
+}
+
+// IOZone implements EnergyZone for the network interfaces or block devices of
+// the node, whose energy is estimated with an IOModel. As watts per GB/s are
+// joules per GB, energy is the idle power integrated over time plus the energy
+// of the bytes transferred.
+type IOZone struct {
+	name  string
+	model IOModel
+	clock clock.PassiveClock
+
+	mu      sync.Mutex
+	energy  Energy    // estimated energy so far
+	pending uint64    // bytes transferred since the previous read
+	active  float64   // ratio of the energy of the previous read due to bytes
+	read    time.Time // time of the previous read; zero if never read
+}
+
+var (
+	_ EnergyZone     = (*IOZone)(nil)
+	_ EnergyRestorer = (*IOZone)(nil)
+	_ UncertainZone  = (*IOZone)(nil)
+)
+
+// NewIOZone creates a new IOZone that estimates energy using model
+func NewIOZone(name string, model IOModel, c clock.PassiveClock) *IOZone {
+	if c == nil {
+		c = clock.RealClock{}
+	}
+	return &IOZone{name: name, model: model, clock: c}
+}
+
+// Name returns the zone name
+func (z *IOZone) Name() string {
+	return z.name
+}
+
+// Index returns the index of the zone
+func (z *IOZone) Index() int {
+	return 0
+}
+
+// Path returns the model the energy is estimated with
+func (z *IOZone) Path() string {
+	return "model:io"
+}
+
+// Transfer adds bytes transferred by the devices, whose energy is added on
+// the next read
+func (z *IOZone) Transfer(bytes uint64) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.pending += bytes
+}
+
+// Uncertainty returns DefaultUncertainty; I/O models are only a rough
+// approximation of the power of devices
+func (z *IOZone) Uncertainty() float64 {
+	return DefaultUncertainty
+}
+
+// Energy returns the energy estimated since the first read
+func (z *IOZone) Energy() (Energy, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	now := z.clock.Now()
+	if z.read.IsZero() {
+		// bytes transferred before the first read are not accounted
+		z.pending = 0
+		z.read = now
+		return z.energy, nil
+	}
+
+	idle := z.model.IdleWatts * now.Sub(z.read).Seconds()
+	active := z.model.WattsPerGBps * float64(z.pending) / 1e9
+	z.energy += Energy((idle + active) * float64(Joule))
+	z.active = 0
+	if idle+active > 0 {
+		z.active = active / (idle + active)
+	}
+	z.pending = 0
+	z.read = now
+
+	return z.energy, nil
+}
+
+// ActiveRatio returns the ratio of the energy added on the last read that is
+// due to the bytes transferred rather than idle power
+func (z *IOZone) ActiveRatio() float64 {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	return z.active
+}
+
+// RestoreEnergy sets the energy the estimated energy is added to, if the zone
+// was not read yet
+func (z *IOZone) RestoreEnergy(energy Energy) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	if z.read.IsZero() {
+		z.energy = energy
+	}
+}
+
+// MaxEnergy returns the maximum energy that can be accumulated; estimated
+// energy never wraps around in practice
+func (z *IOZone) MaxEnergy() Energy {
+	return Energy(math.MaxUint64)
+}
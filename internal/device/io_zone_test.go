@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestIOModel_IsZero(t *testing.T) {
+	assert.True(t, IOModel{}.IsZero())
+	assert.False(t, IOModel{IdleWatts: 5}.IsZero())
+	assert.False(t, IOModel{WattsPerGBps: 2}.IsZero())
+}
+
+func TestIOZone_Energy(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	zone := NewIOZone("network", IOModel{IdleWatts: 5, WattsPerGBps: 10}, fakeClock)
+
+	assert.Equal(t, "network", zone.Name())
+	assert.Equal(t, "model:io", zone.Path())
+	assert.Greater(t, zone.MaxEnergy(), Energy(0))
+
+	// bytes transferred before the first read are not accounted
+	zone.Transfer(1e9)
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.Equal(t, Energy(0), energy)
+
+	fakeClock.Step(2 * time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 10.0, energy.Joules(), 0.001, "5W idle over 2s")
+	assert.Zero(t, zone.ActiveRatio())
+
+	// 10 W per GB/s is 10 J per GB
+	zone.Transfer(1e9)
+	zone.Transfer(5e8)
+	fakeClock.Step(time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 10.0+5+15, energy.Joules(), 0.001)
+	assert.InDelta(t, 0.75, zone.ActiveRatio(), 0.001)
+}
@@ -0,0 +1,324 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// jetsonInputRails are the rails of the power input of the board on Jetson
+// modules, reported as the psys zone
+var jetsonInputRails = map[string]bool{
+	"VDD_IN":      true, // Orin, Xavier NX
+	"VIN_SYS_5V0": true, // Orin Nano
+	"POM_5V_IN":   true, // Nano, TX2
+}
+
+// tegrastatsRail matches the power of a rail in tegrastats output, e.g.
+// "VDD_CPU_GPU_CV 794mW/794mW" (JetPack 5+) or "POM_5V_CPU 287/287" (JetPack 4)
+var tegrastatsRail = regexp.MustCompile(`\b([A-Z][A-Z0-9_]*) (\d+)(?:mW)?/(\d+)(?:mW)?\b`)
+
+// tegrastatsCacheDuration is how long a reading of tegrastats is used for all
+// zones, so that tegrastats is run once per refresh rather than once per zone
+const tegrastatsCacheDuration = time.Second
+
+// tegrastatsTimeout is how long tegrastats may take to print its first line
+// before it is killed, so that a hung tegrastats doesn't stall collections
+const tegrastatsTimeout = 5 * time.Second
+
+// jetsonRail is a power rail of a Jetson module
+type jetsonRail struct {
+	name string // e.g. VDD_CPU_GPU_CV
+	path string
+	read PowerReaderFn
+}
+
+// lineReader reads a line of output of a command
+type lineReader func() (string, error)
+
+// jetsonPowerMeter implements CPUPowerMeter for NVIDIA Jetson modules, which
+// have neither RAPL nor a BMC, by reading the power rails measured by their
+// INA3221 sensors. Rails are read from hwmon (JetPack 5+) or IIO (JetPack 4)
+// in sysfs, or else parsed from the output of tegrastats.
+type jetsonPowerMeter struct {
+	logger     *slog.Logger
+	sysfs      string
+	clock      clock.PassiveClock
+	tegrastats lineReader // nil disables the tegrastats fallback
+
+	zones []EnergyZone
+
+	mu       sync.Mutex
+	cached   map[string]Power // power of rails in the last tegrastats reading
+	cachedAt time.Time
+}
+
+var _ CPUPowerMeter = (*jetsonPowerMeter)(nil)
+
+// JetsonOptFn is a functional option for configuring the Jetson power meter
+type JetsonOptFn func(*jetsonPowerMeter)
+
+// WithJetsonLogger sets the logger for the Jetson power meter
+func WithJetsonLogger(logger *slog.Logger) JetsonOptFn {
+	return func(m *jetsonPowerMeter) {
+		m.logger = logger.With("service", "jetson")
+	}
+}
+
+// WithJetsonClock sets the clock used to integrate the power of rails
+func WithJetsonClock(c clock.PassiveClock) JetsonOptFn {
+	return func(m *jetsonPowerMeter) {
+		m.clock = c
+	}
+}
+
+// WithTegrastatsPath sets the path of tegrastats used to read rails when they
+// are not found in sysfs; empty disables it
+func WithTegrastatsPath(path string) JetsonOptFn {
+	return func(m *jetsonPowerMeter) {
+		m.tegrastats = nil
+		if path != "" {
+			m.tegrastats = func() (string, error) {
+				return readFirstLine(tegrastatsTimeout, path, "--interval", "100")
+			}
+		}
+	}
+}
+
+// NewJetsonPowerMeter creates a new CPU power meter for NVIDIA Jetson modules
+func NewJetsonPowerMeter(sysfsPath string, opts ...JetsonOptFn) *jetsonPowerMeter {
+	ret := &jetsonPowerMeter{
+		logger: slog.Default().With("service", "jetson"),
+		sysfs:  sysfsPath,
+		clock:  clock.RealClock{},
+	}
+	WithTegrastatsPath("tegrastats")(ret)
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+func (m *jetsonPowerMeter) Name() string {
+	return "jetson"
+}
+
+// Init finds the power rails of the module and creates a zone of each
+func (m *jetsonPowerMeter) Init() error {
+	rails, err := m.sysfsRails()
+	if err != nil {
+		return err
+	}
+	if len(rails) == 0 && m.tegrastats != nil {
+		m.logger.Info("No INA3221 power rails found in sysfs; reading them from tegrastats")
+		if rails, err = m.tegrastatsRails(); err != nil {
+			return err
+		}
+	}
+	if len(rails) == 0 {
+		return Errorf(ErrUnsupportedHardware, "no Jetson power rails found")
+	}
+
+	// the input rail is the primary zone, so it comes first
+	sort.SliceStable(rails, func(i, j int) bool {
+		return jetsonInputRails[rails[i].name] && !jetsonInputRails[rails[j].name]
+	})
+
+	m.zones = make([]EnergyZone, 0, len(rails))
+	for i, rail := range rails {
+		zone := jetsonZoneName(rail.name)
+		m.zones = append(m.zones, NewPowerZone(zone, i, rail.path, rail.read, m.clock))
+		m.logger.Info("Found Jetson power rail", "rail", rail.name, "zone", zone, "path", rail.path)
+	}
+	return nil
+}
+
+func (m *jetsonPowerMeter) Zones() ([]EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no Jetson zones initialized")
+	}
+	return m.zones, nil
+}
+
+// PrimaryEnergyZone returns the zone of the input rail, which covers the whole
+// module, or else the first zone
+func (m *jetsonPowerMeter) PrimaryEnergyZone() (EnergyZone, error) {
+	zones, err := m.Zones()
+	if err != nil {
+		return nil, err
+	}
+	return zones[0], nil
+}
+
+// jetsonZoneName returns the zone of a rail: psys for the input rail and the
+// lower case name of the rail without the VDD_ or POM_5V_ prefix otherwise,
+// e.g. cpu_gpu_cv for VDD_CPU_GPU_CV
+func jetsonZoneName(rail string) string {
+	if jetsonInputRails[rail] {
+		return ZonePSys
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(rail, "VDD_"), "POM_5V_")
+	return strings.ToLower(name)
+}
+
+// sysfsRails returns the labelled rails of INA3221 sensors in hwmon and IIO
+func (m *jetsonPowerMeter) sysfsRails() ([]jetsonRail, error) {
+	sensors, err := hwmonSensors(m.sysfs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rails []jetsonRail
+	for _, sensor := range sensors {
+		if sensor.name != "ina3221" {
+			continue
+		}
+		labels, _ := filepath.Glob(filepath.Join(sensor.dir, "in[0-9]*_label"))
+		sort.Strings(labels)
+		for _, path := range labels {
+			label, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			name := strings.TrimSpace(string(label))
+			if name == "" || name == "NC" {
+				continue // not connected
+			}
+
+			railPath, read, err := findRail([]hwmonSensor{sensor}, HwmonRail{Sensor: sensor.name, Label: name})
+			if err != nil {
+				// e.g. the sum of shunt voltages channel, which has no current
+				continue
+			}
+			rails = append(rails, jetsonRail{name: name, path: railPath, read: read})
+		}
+	}
+	if len(rails) > 0 {
+		return rails, nil
+	}
+
+	// JetPack 4 exposes the INA3221 through IIO, reporting power in mW
+	names, err := filepath.Glob(filepath.Join(m.sysfs, "bus", "i2c", "drivers", "ina3221x", "*", "iio:device*", "rail_name_[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	for _, path := range names {
+		label, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSpace(string(label))
+		channel := strings.TrimPrefix(filepath.Base(path), "rail_name_")
+		power := filepath.Join(filepath.Dir(path), fmt.Sprintf("in_power%s_input", channel))
+		if _, err := os.Stat(power); err != nil {
+			continue
+		}
+		rails = append(rails, jetsonRail{name: name, path: power, read: func() (Power, error) {
+			mw, err := readUint(power)
+			return Power(mw) * MilliWatt, err
+		}})
+	}
+	return rails, nil
+}
+
+// tegrastatsRails returns the rails reported by tegrastats
+func (m *jetsonPowerMeter) tegrastatsRails() ([]jetsonRail, error) {
+	line, err := m.tegrastats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run tegrastats: %w", Classify(err))
+	}
+
+	var rails []jetsonRail
+	for _, match := range tegrastatsRail.FindAllStringSubmatch(line, -1) {
+		name := match[1]
+		rails = append(rails, jetsonRail{name: name, path: "tegrastats:" + name, read: func() (Power, error) {
+			return m.tegrastatsPower(name)
+		}})
+	}
+	return rails, nil
+}
+
+// tegrastatsPower returns the power of a rail read by tegrastats, which is run
+// at most once per tegrastatsCacheDuration
+func (m *jetsonPowerMeter) tegrastatsPower(rail string) (Power, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cached == nil || m.clock.Since(m.cachedAt) >= tegrastatsCacheDuration {
+		line, err := m.tegrastats()
+		if err != nil {
+			return 0, fmt.Errorf("failed to run tegrastats: %w", Classify(err))
+		}
+		m.cached = parseTegrastats(line)
+		m.cachedAt = m.clock.Now()
+	}
+
+	power, ok := m.cached[rail]
+	if !ok {
+		return 0, fmt.Errorf("rail %s not found in tegrastats output", rail)
+	}
+	return power, nil
+}
+
+// parseTegrastats returns the current power of the rails in a line of
+// tegrastats output
+func parseTegrastats(line string) map[string]Power {
+	rails := map[string]Power{}
+	for _, match := range tegrastatsRail.FindAllStringSubmatch(line, -1) {
+		mw, err := strconv.ParseUint(match[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		rails[match[1]] = Power(mw) * MilliWatt
+	}
+	return rails
+}
+
+// readFirstLine runs a command that prints lines periodically (like
+// tegrastats), and returns the first line it prints, killing it if it prints
+// none within timeout
+func readFirstLine(timeout time.Duration, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	if !scanner.Scan() {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("%s timed out after %s: %w", name, timeout, ctx.Err())
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%s exited without output", name)
+	}
+	return scanner.Text(), nil
+}
@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func writeSysfs(t *testing.T, sysfs string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		path = filepath.Join(sysfs, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content+"\n"), 0o644))
+	}
+}
+
+func zoneNames(t *testing.T, meter CPUPowerMeter) []string {
+	t.Helper()
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	names := make([]string, len(zones))
+	for i, z := range zones {
+		names[i] = z.Name()
+	}
+	return names
+}
+
+func TestJetsonPowerMeter_Hwmon(t *testing.T) {
+	// Jetson Orin with JetPack 5+
+	sysfs := t.TempDir()
+	writeSysfs(t, sysfs, map[string]string{
+		"class/hwmon/hwmon1/name":        "ina3221",
+		"class/hwmon/hwmon1/in1_label":   "VDD_GPU_SOC",
+		"class/hwmon/hwmon1/in1_input":   "5000",
+		"class/hwmon/hwmon1/curr1_input": "600",
+		"class/hwmon/hwmon1/in2_label":   "VDD_CPU_CV",
+		"class/hwmon/hwmon1/in2_input":   "5000",
+		"class/hwmon/hwmon1/curr2_input": "200",
+		"class/hwmon/hwmon1/in3_label":   "VIN_SYS_5V0",
+		"class/hwmon/hwmon1/in3_input":   "5000",
+		"class/hwmon/hwmon1/curr3_input": "800",
+		"class/hwmon/hwmon1/in7_label":   "sum of shunt voltages",
+		"class/hwmon/hwmon0/name":        "cpu_thermal",
+	})
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter := NewJetsonPowerMeter(sysfs, WithJetsonClock(fakeClock), WithTegrastatsPath(""))
+	assert.Equal(t, "jetson", meter.Name())
+	require.NoError(t, meter.Init())
+
+	assert.Equal(t, []string{"psys", "gpu_soc", "cpu_cv"}, zoneNames(t, meter))
+	primary, err := meter.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, "psys", primary.Name())
+
+	_, err = primary.Energy()
+	require.NoError(t, err)
+	fakeClock.Step(time.Second)
+	energy, err := primary.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 4.0, energy.Joules(), 0.001)
+}
+
+func TestJetsonPowerMeter_IIO(t *testing.T) {
+	// Jetson Nano with JetPack 4
+	sysfs := t.TempDir()
+	iio := "bus/i2c/drivers/ina3221x/6-0040/iio:device0/"
+	writeSysfs(t, sysfs, map[string]string{
+		iio + "rail_name_0":       "POM_5V_IN",
+		iio + "in_power0_input":   "2180",
+		iio + "rail_name_1":       "POM_5V_GPU",
+		iio + "in_power1_input":   "0",
+		iio + "rail_name_2":       "POM_5V_CPU",
+		iio + "in_power2_input":   "287",
+		"class/hwmon/hwmon0/name": "thermal-fan-est",
+	})
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter := NewJetsonPowerMeter(sysfs, WithJetsonClock(fakeClock), WithTegrastatsPath(""))
+	require.NoError(t, meter.Init())
+	assert.Equal(t, []string{"psys", "gpu", "cpu"}, zoneNames(t, meter))
+
+	zones, _ := meter.Zones()
+	_, err := zones[2].Energy()
+	require.NoError(t, err)
+	fakeClock.Step(2 * time.Second)
+	energy, err := zones[2].Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 0.574, energy.Joules(), 0.001)
+}
+
+func TestJetsonPowerMeter_Tegrastats(t *testing.T) {
+	// read by Init, the first and second refresh
+	lines := []string{
+		"RAM 2000/3964MB (lfb 4x4MB) CPU [2%@102] POM_5V_IN 2000/2000 POM_5V_GPU 0/0 POM_5V_CPU 500/500",
+		"RAM 2000/3964MB (lfb 4x4MB) SWAP 0/1982MB (cached 0MB) CPU [2%@102,1%@102,0%@102,0%@102] " +
+			"EMC_FREQ 0% GR3D_FREQ 0% PLL@25C CPU@27.5C POM_5V_IN 2000/2000 POM_5V_GPU 0/0 POM_5V_CPU 500/500",
+		"RAM 2000/3964MB (lfb 4x4MB) CPU [20%@1479] POM_5V_IN 3000/2500 POM_5V_GPU 0/0 POM_5V_CPU 1500/1000",
+	}
+	runs := 0
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter := NewJetsonPowerMeter(t.TempDir(), WithJetsonClock(fakeClock))
+	meter.tegrastats = func() (string, error) {
+		line := lines[min(runs, len(lines)-1)]
+		runs++
+		return line, nil
+	}
+	require.NoError(t, meter.Init())
+	assert.Equal(t, []string{"psys", "gpu", "cpu"}, zoneNames(t, meter))
+
+	zones, _ := meter.Zones()
+	for _, z := range zones {
+		_, err := z.Energy()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, runs, "tegrastats is run once for all zones")
+
+	fakeClock.Step(2 * time.Second)
+	energy, err := zones[2].Energy()
+	require.NoError(t, err)
+	// trapezoid of 0.5W and 1.5W over 2s
+	assert.InDelta(t, 2.0, energy.Joules(), 0.001)
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, "tegrastats:POM_5V_CPU", zones[2].Path())
+}
+
+func TestJetsonPowerMeter_NoRails(t *testing.T) {
+	meter := NewJetsonPowerMeter(t.TempDir(), WithTegrastatsPath(""))
+	assert.ErrorContains(t, meter.Init(), "no Jetson power rails found")
+
+	meter = NewJetsonPowerMeter(t.TempDir(), WithTegrastatsPath(filepath.Join(t.TempDir(), "tegrastats")))
+	assert.ErrorContains(t, meter.Init(), "failed to run tegrastats")
+}
+
+func TestReadFirstLine(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not found")
+	}
+	path := filepath.Join(t.TempDir(), "tegrastats")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho VDD_IN 5000mW/5000mW\nexec sleep 5\n"), 0o755))
+
+	line, err := readFirstLine(time.Second, path)
+	require.NoError(t, err)
+	assert.Equal(t, "VDD_IN 5000mW/5000mW", line)
+
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nexec sleep 5\n"), 0o755))
+	_, err = readFirstLine(10*time.Millisecond, path)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "timed out after 10ms")
+}
+
+func TestParseTegrastats(t *testing.T) {
+	rails := parseTegrastats("RAM 3017/30536MB (lfb 6387x4MB) SWAP 0/15268MB (cached 0MB) CPU [0%@729,off] " +
+		"GR3D_FREQ 0% cpu@46.5C VDD_GPU_SOC 3176mW/3176mW VDD_CPU_CV 795mW/795mW VIN_SYS_5V0 3920mW/3920mW")
+	assert.Equal(t, map[string]Power{
+		"VDD_GPU_SOC": 3176 * MilliWatt,
+		"VDD_CPU_CV":  795 * MilliWatt,
+		"VIN_SYS_5V0": 3920 * MilliWatt,
+	}, rails)
+}
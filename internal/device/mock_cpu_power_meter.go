@@ -5,18 +5,7 @@ package device
 
 // TODO: Move this mock to a separate testutil package
 
-import (
-	"slices"
-	"testing"
-
-	"github.com/prometheus/procfs/sysfs"
-	"github.com/stretchr/testify/require"
-)
-
-const (
-	validSysFSPath = "testdata/sys"
-	badSysFSPath   = "testdata/bad_sysfs"
-)
+import "slices"
 
 type (
 	MockRaplZone struct {
@@ -68,20 +57,6 @@ func (m *MockRaplZone) Inc(delta Energy) {
 	m.energy = (m.energy + delta) % m.maxMicroJoules
 }
 
-func validSysFSFixtures(t *testing.T) sysfs.FS {
-	t.Helper()
-	fs, err := sysfs.NewFS(validSysFSPath)
-	require.NoError(t, err, "Failed to create sysfs test FS")
-	return fs
-}
-
-func invalidSysFSFixtures(t *testing.T) sysfs.FS {
-	t.Helper()
-	fs, err := sysfs.NewFS(badSysFSPath)
-	require.NoError(t, err, "Failed to create sysfs test FS")
-	return fs
-}
-
 func sortedZoneNames(zones []EnergyZone) []string {
 	names := make([]string, len(zones))
 	for i, zone := range zones {
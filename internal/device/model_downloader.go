@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// maxModelSize bounds the size of downloaded models, which are only a few
+// coefficients
+const maxModelSize = 1 << 20
+
+// ModelUpdater is a power meter whose model can be replaced while it runs
+type ModelUpdater interface {
+	SetModel(model LinearModel)
+}
+
+// ModelDownloader downloads the model of an estimated power meter from a URL
+// when Kepler starts and, if a refresh interval is set, periodically after that,
+// replacing the model of the meter whenever it changes. The SHA-256 checksum of
+// the model is published at the URL of the model with a .sha256 suffix, in the
+// format of sha256sum, and models that don't match it are rejected.
+type ModelDownloader struct {
+	logger          *slog.Logger
+	client          *http.Client
+	clock           clock.WithTicker
+	url             string
+	refreshInterval time.Duration
+	meter           ModelUpdater
+
+	// only accessed by Init and then Run
+	digest string // of the current model
+}
+
+// ModelDownloaderOptFn is a functional option for configuring the model downloader
+type ModelDownloaderOptFn func(*ModelDownloader)
+
+// WithModelDownloaderLogger sets the logger for the model downloader
+func WithModelDownloaderLogger(logger *slog.Logger) ModelDownloaderOptFn {
+	return func(d *ModelDownloader) {
+		d.logger = logger.With("service", "model-downloader")
+	}
+}
+
+// WithModelDownloaderClock sets the clock used to schedule refreshes
+func WithModelDownloaderClock(c clock.WithTicker) ModelDownloaderOptFn {
+	return func(d *ModelDownloader) {
+		d.clock = c
+	}
+}
+
+// WithModelRefreshInterval sets how often the model is downloaded again; 0
+// downloads it only once
+func WithModelRefreshInterval(interval time.Duration) ModelDownloaderOptFn {
+	return func(d *ModelDownloader) {
+		d.refreshInterval = interval
+	}
+}
+
+// NewModelDownloader creates a downloader of the model at url for meter
+func NewModelDownloader(url string, meter ModelUpdater, opts ...ModelDownloaderOptFn) *ModelDownloader {
+	d := &ModelDownloader{
+		logger: slog.Default().With("service", "model-downloader"),
+		client: &http.Client{Timeout: 30 * time.Second},
+		clock:  clock.RealClock{},
+		url:    url,
+		meter:  meter,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+func (d *ModelDownloader) Name() string {
+	return "model-downloader"
+}
+
+// Init downloads the model; Kepler does not start if it can't, so that power is
+// not estimated with a model other than the one configured
+func (d *ModelDownloader) Init() error {
+	return d.refresh(context.Background())
+}
+
+// Run downloads the model periodically until ctx is cancelled; the current
+// model is kept if a download fails
+func (d *ModelDownloader) Run(ctx context.Context) error {
+	if d.refreshInterval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := d.clock.NewTicker(d.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			if err := d.refresh(ctx); err != nil {
+				d.logger.Warn("Failed to download power model; keeping the current model", "error", err)
+			}
+		}
+	}
+}
+
+// refresh downloads the model and its checksum and replaces the model of the
+// meter if it changed
+func (d *ModelDownloader) refresh(ctx context.Context) error {
+	data, err := d.get(ctx, d.url)
+	if err != nil {
+		return err
+	}
+	sum, err := d.get(ctx, d.url+".sha256")
+	if err != nil {
+		return err
+	}
+
+	// sha256sum writes the checksum followed by the file name
+	fields := strings.Fields(string(sum))
+	if len(fields) == 0 {
+		return fmt.Errorf("empty checksum of model %s", d.url)
+	}
+	sha := sha256.Sum256(data)
+	digest := strings.ToLower(fields[0])
+	if got := hex.EncodeToString(sha[:]); got != digest {
+		return fmt.Errorf("checksum mismatch of model %s: got sha256 %s; want %s", d.url, got, digest)
+	}
+	if digest == d.digest {
+		return nil
+	}
+
+	model, err := parseLinearModel(data, d.url)
+	if err != nil {
+		return err
+	}
+	d.meter.SetModel(model)
+	d.digest = digest
+	d.logger.Info("Using downloaded power model",
+		"url", d.url,
+		"sha256", d.digest,
+		"intercept", model.Intercept,
+		"utilization", model.Utilization,
+		"frequency", model.Frequency,
+		"deep-idle", model.DeepIdle)
+	return nil
+}
+
+// get returns the body of url
+func (d *ModelDownloader) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxModelSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if len(data) > maxModelSize {
+		return nil, fmt.Errorf("failed to download %s: larger than %d bytes", url, maxModelSize)
+	}
+	return data, nil
+}
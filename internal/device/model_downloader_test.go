@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type fakeModelUpdater struct {
+	mu     sync.Mutex
+	models []LinearModel
+}
+
+func (u *fakeModelUpdater) SetModel(model LinearModel) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.models = append(u.models, model)
+}
+
+func (u *fakeModelUpdater) Models() []LinearModel {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]LinearModel(nil), u.models...)
+}
+
+// modelServer serves a model at /model.yaml and its checksum at
+// /model.yaml.sha256
+type modelServer struct {
+	mu    sync.Mutex
+	model string
+	sum   string
+}
+
+func (s *modelServer) set(model string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	digest := sha256.Sum256([]byte(model))
+	s.model, s.sum = model, hex.EncodeToString(digest[:])+"  model.yaml\n"
+}
+
+func (s *modelServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.URL.Path {
+	case "/model.yaml":
+		_, _ = w.Write([]byte(s.model))
+	case "/model.yaml.sha256":
+		_, _ = w.Write([]byte(s.sum))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestModelDownloader(t *testing.T) {
+	models := &modelServer{}
+	models.set("intercept: 10\nutilization: 100\n")
+	srv := httptest.NewServer(models)
+	t.Cleanup(srv.Close)
+
+	meter := &fakeModelUpdater{}
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	d := NewModelDownloader(srv.URL+"/model.yaml", meter,
+		WithModelDownloaderClock(fakeClock),
+		WithModelRefreshInterval(time.Minute))
+	assert.Equal(t, "model-downloader", d.Name())
+
+	require.NoError(t, d.Init())
+	assert.Equal(t, []LinearModel{{Intercept: 10, Utilization: 100}}, meter.Models())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Run(ctx) }()
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, 10*time.Millisecond)
+
+	models.set("intercept: 20\nutilization: 100\n")
+
+	// the model is swapped when it changes
+	assert.Eventually(t, func() bool {
+		fakeClock.Step(time.Minute)
+		return len(meter.Models()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, LinearModel{Intercept: 20, Utilization: 100}, meter.Models()[1])
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestModelDownloaderErrors(t *testing.T) {
+	tt := []struct {
+		name  string
+		model string
+		sum   string
+		err   string
+	}{{
+		name:  "checksum mismatch",
+		model: "intercept: 10\n",
+		sum:   "0000000000000000000000000000000000000000000000000000000000000000  model.yaml\n",
+		err:   "checksum mismatch",
+	}, {
+		name:  "empty checksum",
+		model: "intercept: 10\n",
+		sum:   "\n",
+		err:   "empty checksum",
+	}, {
+		name:  "invalid model",
+		model: "intercept: [10\n",
+		err:   "failed to parse model file",
+	}}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			models := &modelServer{}
+			models.set(tc.model)
+			if tc.sum != "" {
+				models.sum = tc.sum
+			}
+			srv := httptest.NewServer(models)
+			t.Cleanup(srv.Close)
+
+			meter := &fakeModelUpdater{}
+			err := NewModelDownloader(srv.URL+"/model.yaml", meter).Init()
+			assert.ErrorContains(t, err, tc.err)
+			assert.Empty(t, meter.Models())
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		srv := httptest.NewServer(&modelServer{})
+		t.Cleanup(srv.Close)
+
+		err := NewModelDownloader(srv.URL+"/missing.yaml", &fakeModelUpdater{}).Init()
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+}
+
+func TestModelDownloaderKeepsModel(t *testing.T) {
+	models := &modelServer{}
+	models.set("intercept: 10\n")
+	srv := httptest.NewServer(models)
+	t.Cleanup(srv.Close)
+
+	meter := &fakeModelUpdater{}
+	d := NewModelDownloader(srv.URL+"/model.yaml", meter)
+	require.NoError(t, d.Init())
+
+	// an unchanged model is not set again
+	require.NoError(t, d.refresh(context.Background()))
+	assert.Len(t, meter.Models(), 1)
+
+	// a model that fails its checksum is not used
+	models.mu.Lock()
+	models.model = "intercept: 1000\n"
+	models.mu.Unlock()
+	assert.ErrorContains(t, d.refresh(context.Background()), "checksum mismatch")
+	assert.Equal(t, []LinearModel{{Intercept: 10}}, meter.Models())
+}
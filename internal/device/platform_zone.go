@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// acpiPowerMeterSensor is the name of the hwmon sensor of the ACPI power meter
+// (ACPI0000), which reports the power of the platform measured by the firmware
+const acpiPowerMeterSensor = "power_meter"
+
+// NewACPIPowerZone creates a zone of the given name whose power is read from
+// the ACPI power meter in hwmon, e.g. power1_average of the power_meter sensor
+func NewACPIPowerZone(name, sysfsPath string, c clock.PassiveClock) (*PowerZone, error) {
+	sensors, err := hwmonSensors(sysfsPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, sensor := range sensors {
+		if sensor.name != acpiPowerMeterSensor {
+			continue
+		}
+		// the meter averages the power over its averaging interval; some
+		// firmware only reports the instantaneous power
+		for _, file := range []string{"power1_average", "power1_input"} {
+			path := filepath.Join(sensor.dir, file)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			read := func() (Power, error) {
+				uw, err := readUint(path)
+				return Power(uw) * MicroWatt, err
+			}
+			return NewPowerZone(name, 0, path, read, c), nil
+		}
+	}
+	return nil, Errorf(ErrUnsupportedHardware, "no ACPI power meter found in %s", filepath.Join(sysfsPath, "class", "hwmon"))
+}
+
+// PlatformSource is a meter of the power of the whole platform, e.g. the BMC
+// read with Redfish or the ACPI power meter
+type PlatformSource struct {
+	Name string
+	Zone EnergyZone
+}
+
+// PlatformSourceStatus is the last reading of a platform source
+type PlatformSourceStatus struct {
+	Name    string
+	Power   Power         // in the last interval; 0 if not known
+	Latency time.Duration // of the last read
+	Err     error         // of the last read; nil if it was read
+	Primary bool          // the energy of the platform is that of this source
+
+	// Discrepancy is the relative difference of the power of the source from
+	// the power of the primary, e.g. 0.1 if it is 10% higher; NaN if either is
+	// not known
+	Discrepancy float64
+}
+
+// platformReading is the state of a platform source between reads
+type platformReading struct {
+	energy Energy
+	ok     bool // energy was read
+}
+
+// PlatformZone implements EnergyZone for the whole platform of the node
+// measured by several sources. All sources are read, and the energy of the
+// platform increases by the energy of the primary source: the first one in
+// order of precedence that could be read, unless it is slower to read than
+// the max latency and another one isn't. The power of the other sources is
+// compared to the power of the primary, to detect miscalibrated sensors.
+type PlatformZone struct {
+	name       string
+	sources    []PlatformSource // in order of precedence
+	maxLatency time.Duration    // 0 if not limited
+	clock      clock.PassiveClock
+
+	mu       sync.Mutex
+	energy   Energy
+	read     time.Time // time of the previous read; zero if never read
+	readings []platformReading
+	statuses []PlatformSourceStatus
+}
+
+var (
+	_ EnergyZone     = (*PlatformZone)(nil)
+	_ EnergyRestorer = (*PlatformZone)(nil)
+)
+
+// PlatformOptFn is a functional option for configuring the platform zone
+type PlatformOptFn func(*PlatformZone)
+
+// WithPlatformMaxLatency sets the latency over which a source is only used as
+// the primary if no faster source could be read; 0 doesn't limit it
+func WithPlatformMaxLatency(d time.Duration) PlatformOptFn {
+	return func(z *PlatformZone) {
+		z.maxLatency = d
+	}
+}
+
+// WithPlatformClock sets the clock the power of the sources is computed with
+func WithPlatformClock(c clock.PassiveClock) PlatformOptFn {
+	return func(z *PlatformZone) {
+		z.clock = c
+	}
+}
+
+// NewPlatformZone creates a zone of the given name measured by sources, in
+// order of precedence
+func NewPlatformZone(name string, sources []PlatformSource, opts ...PlatformOptFn) *PlatformZone {
+	z := &PlatformZone{
+		name:     name,
+		sources:  sources,
+		clock:    clock.RealClock{},
+		readings: make([]platformReading, len(sources)),
+		statuses: make([]PlatformSourceStatus, len(sources)),
+	}
+	for i, source := range sources {
+		z.statuses[i] = PlatformSourceStatus{Name: source.Name, Discrepancy: math.NaN()}
+	}
+	for _, opt := range opts {
+		opt(z)
+	}
+	return z
+}
+
+// Name returns the zone name
+func (z *PlatformZone) Name() string {
+	return z.name
+}
+
+// Index returns the index of the zone
+func (z *PlatformZone) Index() int {
+	return 0
+}
+
+// Path returns the paths of the sources
+func (z *PlatformZone) Path() string {
+	paths := make([]string, 0, len(z.sources))
+	for _, source := range z.sources {
+		paths = append(paths, source.Zone.Path())
+	}
+	return strings.Join(paths, ",")
+}
+
+// RestoreEnergy sets the energy of the platform, which the energy of the
+// primary source is added to, if the zone was not read yet
+func (z *PlatformZone) RestoreEnergy(energy Energy) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	if z.read.IsZero() {
+		z.energy = energy
+	}
+}
+
+// MaxEnergy returns the maximum energy that can be accumulated; integrated
+// energy never wraps around in practice
+func (z *PlatformZone) MaxEnergy() Energy {
+	return Energy(math.MaxUint64)
+}
+
+// Meter returns the name of the primary source; the first source if none was
+// read yet
+func (z *PlatformZone) Meter() string {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	for _, status := range z.statuses {
+		if status.Primary {
+			return status.Name
+		}
+	}
+	return z.sources[0].Name
+}
+
+// Sources returns the status of the sources, in order of precedence
+func (z *PlatformZone) Sources() []PlatformSourceStatus {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	statuses := make([]PlatformSourceStatus, len(z.statuses))
+	copy(statuses, z.statuses)
+	return statuses
+}
+
+// Energy reads all sources concurrently and returns the energy of the
+// platform, increased by the energy of the primary source since the previous
+// read. An error is returned only if no source could be read.
+func (z *PlatformZone) Energy() (Energy, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	type result struct {
+		energy  Energy
+		err     error
+		latency time.Duration
+	}
+	results := make([]result, len(z.sources))
+	var wg sync.WaitGroup
+	for i, source := range z.sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := z.clock.Now()
+			energy, err := source.Zone.Energy()
+			results[i] = result{energy: energy, err: err, latency: z.clock.Since(start)}
+		}()
+	}
+	wg.Wait()
+	now := z.clock.Now()
+	elapsed := now.Sub(z.read).Seconds()
+
+	// the energy and power of each source in this interval; a source has none
+	// unless it was read this time and the previous time
+	deltas := make([]Energy, len(z.sources))
+	valid := make([]bool, len(z.sources))
+	var errs []error
+	for i, source := range z.sources {
+		r, prev := results[i], z.readings[i]
+		status := &z.statuses[i]
+		status.Latency, status.Err, status.Power = r.latency, r.err, 0
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name, r.err))
+			z.readings[i] = platformReading{}
+			continue
+		}
+		if prev.ok && !z.read.IsZero() && elapsed > 0 {
+			delta, change := EnergyDelta(r.energy, prev.energy, source.Zone.MaxEnergy(), MaxEnergyDelta(now.Sub(z.read)))
+			if change != CounterJumped {
+				deltas[i], valid[i] = delta, true
+				status.Power = Power(float64(delta.MicroJoules())/elapsed) * MicroWatt
+			}
+		}
+		z.readings[i] = platformReading{energy: r.energy, ok: true}
+	}
+	z.read = now
+
+	if len(errs) == len(z.sources) {
+		for i := range z.statuses {
+			z.statuses[i].Primary = false
+		}
+		return 0, fmt.Errorf("no platform source could be read: %w", errors.Join(errs...))
+	}
+
+	primary := z.primary(valid)
+	for i := range z.statuses {
+		status := &z.statuses[i]
+		status.Primary = i == primary
+		status.Discrepancy = math.NaN()
+		if primary >= 0 && valid[i] && z.statuses[primary].Power > 0 {
+			status.Discrepancy = (status.Power.Watts() - z.statuses[primary].Power.Watts()) / z.statuses[primary].Power.Watts()
+		}
+	}
+	if primary >= 0 {
+		z.energy += deltas[primary]
+	}
+	return z.energy, nil
+}
+
+// primary returns the index of the first source, in order of precedence, whose
+// power is known in this interval, preferring sources read within the max
+// latency; -1 if none is
+func (z *PlatformZone) primary(valid []bool) int {
+	fallback := -1
+	for i := range z.sources {
+		if !valid[i] {
+			continue
+		}
+		if z.maxLatency > 0 && z.statuses[i].Latency > z.maxLatency {
+			if fallback < 0 {
+				fallback = i
+			}
+			continue
+		}
+		return i
+	}
+	return fallback
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestACPIPowerZone(t *testing.T) {
+	sysfs := t.TempDir()
+	dir := filepath.Join(sysfs, "class", "hwmon", "hwmon1")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "name"), []byte("power_meter\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "power1_average"), []byte("250000000\n"), 0o644))
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	zone, err := NewACPIPowerZone("platform", sysfs, fakeClock)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "power1_average"), zone.Path())
+
+	_, err = zone.Energy()
+	require.NoError(t, err)
+	fakeClock.Step(2 * time.Second)
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 500.0, energy.Joules(), 0.001)
+
+	_, err = NewACPIPowerZone("platform", t.TempDir(), fakeClock)
+	assert.ErrorIs(t, err, ErrUnsupportedHardware)
+}
+
+func TestPlatformZone(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	redfish := &mockEnergyZone{name: "platform", path: "https://bmc", maxEnergy: Energy(math.MaxUint64)}
+	acpi := &mockEnergyZone{name: "platform", path: "/sys/class/hwmon/hwmon1/power1_average", maxEnergy: Energy(math.MaxUint64)}
+	zone := NewPlatformZone("platform", []PlatformSource{
+		{Name: "redfish", Zone: redfish},
+		{Name: "acpi", Zone: acpi},
+	}, WithPlatformClock(fakeClock))
+
+	assert.Equal(t, "platform", zone.Name())
+	assert.Equal(t, "https://bmc,/sys/class/hwmon/hwmon1/power1_average", zone.Path())
+	assert.Equal(t, "redfish", zone.Meter(), "the first source before any read")
+
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.Zero(t, energy)
+
+	// the ACPI power meter reads 10% higher than the BMC
+	fakeClock.Step(10 * time.Second)
+	redfish.SetEnergy(1000 * Joule)
+	acpi.SetEnergy(1100 * Joule)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.Equal(t, 1000*Joule, energy, "the energy of the source of highest precedence")
+	assert.Equal(t, "redfish", zone.Meter())
+
+	sources := zone.Sources()
+	require.Len(t, sources, 2)
+	assert.True(t, sources[0].Primary)
+	assert.InDelta(t, 100.0, sources[0].Power.Watts(), 0.001)
+	assert.Zero(t, sources[0].Discrepancy)
+	assert.False(t, sources[1].Primary)
+	assert.InDelta(t, 110.0, sources[1].Power.Watts(), 0.001)
+	assert.InDelta(t, 0.1, sources[1].Discrepancy, 0.001)
+
+	t.Run("fallback", func(t *testing.T) {
+		redfish.err = Errorf(ErrTransient, "BMC not responding")
+		fakeClock.Step(10 * time.Second)
+		acpi.SetEnergy(2200 * Joule)
+		energy, err := zone.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, 2100*Joule, energy, "the energy of the next source")
+		assert.Equal(t, "acpi", zone.Meter())
+
+		sources := zone.Sources()
+		assert.ErrorIs(t, sources[0].Err, ErrTransient)
+		assert.True(t, math.IsNaN(sources[0].Discrepancy))
+
+		// the power of a source is known after two reads in a row
+		redfish.err = nil
+		fakeClock.Step(10 * time.Second)
+		redfish.SetEnergy(3000 * Joule)
+		acpi.SetEnergy(3300 * Joule)
+		energy, err = zone.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, 3200*Joule, energy)
+		assert.Equal(t, "acpi", zone.Meter())
+
+		fakeClock.Step(10 * time.Second)
+		redfish.SetEnergy(4000 * Joule)
+		acpi.SetEnergy(4400 * Joule)
+		energy, err = zone.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, 4200*Joule, energy)
+		assert.Equal(t, "redfish", zone.Meter())
+	})
+
+	t.Run("no source", func(t *testing.T) {
+		redfish.err = errors.New("BMC not responding")
+		acpi.err = Errorf(ErrUnsupportedHardware, "no power meter")
+		_, err := zone.Energy()
+		assert.ErrorContains(t, err, "no platform source could be read")
+		assert.ErrorIs(t, err, ErrUnsupportedHardware)
+	})
+}
+
+func TestPlatformZonePrimary(t *testing.T) {
+	zone := NewPlatformZone("platform", []PlatformSource{
+		{Name: "redfish", Zone: &mockEnergyZone{}},
+		{Name: "acpi", Zone: &mockEnergyZone{}},
+	}, WithPlatformMaxLatency(time.Second))
+
+	assert.Equal(t, 0, zone.primary([]bool{true, true}))
+	assert.Equal(t, 1, zone.primary([]bool{false, true}))
+	assert.Equal(t, -1, zone.primary([]bool{false, false}))
+
+	// a slow source is the primary only if no other source is read
+	zone.statuses[0].Latency = 3 * time.Second
+	assert.Equal(t, 1, zone.primary([]bool{true, true}))
+	assert.Equal(t, 0, zone.primary([]bool{true, false}))
+}
@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PowerLimiter limits the power drawn by the node; the limit is enforced by
+// the hardware, e.g. RAPL or the BMC
+type PowerLimiter interface {
+	// Name returns the name of the limiter, e.g. rapl or redfish
+	Name() string
+
+	// PowerLimit returns the current power limit; 0 if there is none
+	PowerLimit() (Power, error)
+
+	// SetPowerLimit limits the power to limit; 0 removes the limit, if the
+	// limiter supports it
+	SetPowerLimit(limit Power) error
+
+	// SavePowerLimit saves the current power limit, to be restored by
+	// RestorePowerLimit, and returns it
+	SavePowerLimit() (Power, error)
+
+	// RestorePowerLimit restores the power limit saved by SavePowerLimit
+	RestorePowerLimit() error
+}
+
+// ErrInvalidPowerLimit is returned when a power limit is outside of the range
+// the limiter supports
+var ErrInvalidPowerLimit = errors.New("invalid power limit")
+
+// raplPackage is the sysfs directory of the RAPL package zone of a socket
+type raplPackage struct {
+	name string
+	dir  string
+}
+
+// raplPowerLimiter implements PowerLimiter by setting the long term power
+// limit (constraint 0) of the RAPL package zones, split evenly across sockets
+type raplPowerLimiter struct {
+	packages []raplPackage
+	saved    []Power // limit of each package saved; nil until saved
+	write    func(path string, power Power) error
+}
+
+var _ PowerLimiter = (*raplPowerLimiter)(nil)
+
+// NewRaplPowerLimiter creates a limiter of the RAPL package zones found in
+// sysfsPath, e.g. /sys
+func NewRaplPowerLimiter(sysfsPath string) (*raplPowerLimiter, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysfsPath, "class", "powercap", "intel-rapl:*"))
+	if err != nil {
+		return nil, err
+	}
+
+	l := &raplPowerLimiter{write: writeMicroWatts}
+	for _, dir := range dirs {
+		// subzones such as intel-rapl:0:0 (core) are limited by their package
+		if strings.Count(filepath.Base(dir), ":") != 1 {
+			continue
+		}
+		name, err := os.ReadFile(filepath.Join(dir, "name"))
+		if err != nil {
+			continue
+		}
+		if n := strings.TrimSpace(string(name)); strings.HasPrefix(n, "package") {
+			l.packages = append(l.packages, raplPackage{name: n, dir: dir})
+		}
+	}
+	if len(l.packages) == 0 {
+		return nil, Errorf(ErrUnsupportedHardware, "no RAPL package zones found in %s", sysfsPath)
+	}
+	sort.Slice(l.packages, func(i, j int) bool { return l.packages[i].dir < l.packages[j].dir })
+	return l, nil
+}
+
+func (l *raplPowerLimiter) Name() string {
+	return "rapl"
+}
+
+// PowerLimit returns the sum of the power limits of the packages
+func (l *raplPowerLimiter) PowerLimit() (Power, error) {
+	limits, err := l.readLimits()
+	if err != nil {
+		return 0, err
+	}
+	total := Power(0)
+	for _, limit := range limits {
+		total += limit
+	}
+	return total, nil
+}
+
+// SetPowerLimit sets the power limit of each package to an equal share of
+// limit; RAPL limits can't be removed, so limit must be positive and within
+// the min and max power of the packages
+func (l *raplPowerLimiter) SetPowerLimit(limit Power) error {
+	if limit <= 0 {
+		return Errorf(ErrInvalidPowerLimit, "invalid RAPL power limit %s; must be positive", limit)
+	}
+
+	share := limit / Power(len(l.packages))
+	limits := make([]Power, len(l.packages))
+	for i, pkg := range l.packages {
+		// the min and max power are not exposed by all platforms
+		if minPower, err := readMicroWatts(filepath.Join(pkg.dir, "constraint_0_min_power_uw")); err == nil && share < minPower {
+			return Errorf(ErrInvalidPowerLimit, "power limit %s of %s is below its min power %s", share, pkg.name, minPower)
+		}
+		if maxPower, err := readMicroWatts(filepath.Join(pkg.dir, "constraint_0_max_power_uw")); err == nil && maxPower > 0 && share > maxPower {
+			return Errorf(ErrInvalidPowerLimit, "power limit %s of %s exceeds its max power %s", share, pkg.name, maxPower)
+		}
+		limits[i] = share
+	}
+	return l.writeLimits(limits)
+}
+
+// SavePowerLimit saves the power limit of each package, which may differ, and
+// returns their sum
+func (l *raplPowerLimiter) SavePowerLimit() (Power, error) {
+	limits, err := l.readLimits()
+	if err != nil {
+		return 0, err
+	}
+	l.saved = limits
+
+	total := Power(0)
+	for _, limit := range limits {
+		total += limit
+	}
+	return total, nil
+}
+
+// RestorePowerLimit restores the power limit saved of each package
+func (l *raplPowerLimiter) RestorePowerLimit() error {
+	if l.saved == nil {
+		return fmt.Errorf("no RAPL power limit saved")
+	}
+	return l.writeLimits(l.saved)
+}
+
+// readLimits reads the power limit of each package
+func (l *raplPowerLimiter) readLimits() ([]Power, error) {
+	limits := make([]Power, len(l.packages))
+	for i, pkg := range l.packages {
+		limit, err := readMicroWatts(filepath.Join(pkg.dir, "constraint_0_power_limit_uw"))
+		if err != nil {
+			return nil, err
+		}
+		limits[i] = limit
+	}
+	return limits, nil
+}
+
+// writeLimits sets the power limit of each package to the limit at its index;
+// if a package fails to be set, the packages already set are rolled back to
+// their previous limit, so that the limits are not left half applied
+func (l *raplPowerLimiter) writeLimits(limits []Power) error {
+	previous, err := l.readLimits()
+	if err != nil {
+		return err
+	}
+
+	for i, pkg := range l.packages {
+		if err := l.write(filepath.Join(pkg.dir, "constraint_0_power_limit_uw"), limits[i]); err != nil {
+			errs := []error{fmt.Errorf("failed to set power limit of %s: %w", pkg.name, Classify(err))}
+			for j, set := range l.packages[:i] {
+				if err := l.write(filepath.Join(set.dir, "constraint_0_power_limit_uw"), previous[j]); err != nil {
+					errs = append(errs, fmt.Errorf("failed to roll back power limit of %s: %w", set.name, Classify(err)))
+				}
+			}
+			return errors.Join(errs...)
+		}
+	}
+	return nil
+}
+
+// writeMicroWatts writes power in microwatts to a sysfs file
+func writeMicroWatts(path string, power Power) error {
+	return os.WriteFile(path, []byte(strconv.FormatUint(uint64(power.MicroWatts()), 10)), 0o644)
+}
+
+// readMicroWatts reads power in microwatts from a sysfs file
+func readMicroWatts(path string) (Power, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, Classify(err)
+	}
+	uw, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid power in %s: %w", path, err)
+	}
+	return Power(uw) * MicroWatt, nil
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRaplZone writes a RAPL zone with a long term power limit to sysfs
+func writeRaplZone(t *testing.T, sysfs, dir, name string, limitUW, maxUW uint64) string {
+	t.Helper()
+	path := filepath.Join(sysfs, "class", "powercap", dir)
+	require.NoError(t, os.MkdirAll(path, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "name"), []byte(name+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "constraint_0_power_limit_uw"), []byte(fmt.Sprintf("%d\n", limitUW)), 0o644))
+	if maxUW > 0 {
+		require.NoError(t, os.WriteFile(filepath.Join(path, "constraint_0_max_power_uw"), []byte(fmt.Sprintf("%d\n", maxUW)), 0o644))
+	}
+	return path
+}
+
+func TestRaplPowerLimiter(t *testing.T) {
+	sysfs := t.TempDir()
+	pkg0 := writeRaplZone(t, sysfs, "intel-rapl:0", "package-0", 150_000_000, 200_000_000)
+	pkg1 := writeRaplZone(t, sysfs, "intel-rapl:1", "package-1", 150_000_000, 0)
+	writeRaplZone(t, sysfs, "intel-rapl:0:0", "core", 0, 0)
+	writeRaplZone(t, sysfs, "intel-rapl:2", "psys", 500_000_000, 0)
+
+	l, err := NewRaplPowerLimiter(sysfs)
+	require.NoError(t, err)
+	assert.Equal(t, "rapl", l.Name())
+	require.Len(t, l.packages, 2, "only package zones are limited")
+
+	limit, err := l.PowerLimit()
+	require.NoError(t, err)
+	assert.Equal(t, 300*Watt, limit)
+
+	require.NoError(t, l.SetPowerLimit(250*Watt))
+	for _, pkg := range []string{pkg0, pkg1} {
+		data, err := os.ReadFile(filepath.Join(pkg, "constraint_0_power_limit_uw"))
+		require.NoError(t, err)
+		assert.Equal(t, "125000000", string(data), "the limit is split across packages")
+	}
+
+	assert.ErrorContains(t, l.SetPowerLimit(500*Watt), "exceeds its max power")
+	assert.ErrorContains(t, l.SetPowerLimit(0), "must be positive")
+	limit, err = l.PowerLimit()
+	require.NoError(t, err)
+	assert.Equal(t, 250*Watt, limit, "invalid limits are not set")
+
+	_, err = NewRaplPowerLimiter(t.TempDir())
+	assert.ErrorIs(t, err, ErrUnsupportedHardware)
+}
+
+func TestRaplPowerLimiterMinPower(t *testing.T) {
+	sysfs := t.TempDir()
+	pkg := writeRaplZone(t, sysfs, "intel-rapl:0", "package-0", 150_000_000, 200_000_000)
+	require.NoError(t, os.WriteFile(filepath.Join(pkg, "constraint_0_min_power_uw"), []byte("40000000\n"), 0o644))
+
+	l, err := NewRaplPowerLimiter(sysfs)
+	require.NoError(t, err)
+
+	err = l.SetPowerLimit(30 * Watt)
+	assert.ErrorIs(t, err, ErrInvalidPowerLimit)
+	assert.ErrorContains(t, err, "below its min power")
+	assert.ErrorIs(t, l.SetPowerLimit(300*Watt), ErrInvalidPowerLimit)
+	require.NoError(t, l.SetPowerLimit(40*Watt))
+}
+
+func TestRaplPowerLimiterRestore(t *testing.T) {
+	sysfs := t.TempDir()
+	pkg0 := writeRaplZone(t, sysfs, "intel-rapl:0", "package-0", 180_000_000, 0)
+	pkg1 := writeRaplZone(t, sysfs, "intel-rapl:1", "package-1", 120_000_000, 0)
+
+	l, err := NewRaplPowerLimiter(sysfs)
+	require.NoError(t, err)
+	readLimits := func() []string {
+		var limits []string
+		for _, pkg := range []string{pkg0, pkg1} {
+			data, err := os.ReadFile(filepath.Join(pkg, "constraint_0_power_limit_uw"))
+			require.NoError(t, err)
+			limits = append(limits, strings.TrimSpace(string(data)))
+		}
+		return limits
+	}
+
+	assert.Error(t, l.RestorePowerLimit(), "nothing is restored before saving")
+
+	saved, err := l.SavePowerLimit()
+	require.NoError(t, err)
+	assert.Equal(t, 300*Watt, saved)
+
+	require.NoError(t, l.SetPowerLimit(200*Watt))
+	assert.Equal(t, []string{"100000000", "100000000"}, readLimits())
+
+	require.NoError(t, l.RestorePowerLimit())
+	assert.Equal(t, []string{"180000000", "120000000"}, readLimits(), "the limit of each package is restored")
+
+	// a failure to set a package rolls back the packages already set
+	l.write = func(path string, power Power) error {
+		if strings.HasPrefix(path, pkg1) {
+			return os.ErrPermission
+		}
+		return writeMicroWatts(path, power)
+	}
+	err = l.SetPowerLimit(200 * Watt)
+	assert.ErrorIs(t, err, ErrPermission)
+	assert.Equal(t, []string{"180000000", "120000000"}, readLimits(), "the limits are rolled back")
+}
+
+func TestRedfishPowerLimit(t *testing.T) {
+	var limit *float64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redfish/v1/Chassis/1/Power", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"PowerControl": []any{map[string]any{
+					"PowerConsumedWatts": 300,
+					"PowerCapacityWatts": 750,
+					"PowerLimit":         map[string]any{"LimitInWatts": limit},
+				}},
+			})
+		case http.MethodPatch:
+			var body struct {
+				PowerControl []struct {
+					PowerLimit struct {
+						LimitInWatts *float64
+					}
+				}
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.PowerControl) != 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			limit = body.PowerControl[0].PowerLimit.LimitInWatts
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	bmc := httptest.NewServer(mux)
+	t.Cleanup(bmc.Close)
+
+	zone, err := NewRedfishZone("platform", bmc.URL, WithRedfishChassis("1"))
+	require.NoError(t, err)
+
+	got, err := zone.PowerLimit()
+	require.NoError(t, err)
+	assert.Zero(t, got, "no limit is set")
+
+	capacity, err := zone.PowerCapacity()
+	require.NoError(t, err)
+	assert.Equal(t, 750*Watt, capacity)
+
+	require.NoError(t, zone.SetPowerLimit(400*Watt))
+	got, err = zone.PowerLimit()
+	require.NoError(t, err)
+	assert.Equal(t, 400*Watt, got)
+
+	require.NoError(t, zone.SetPowerLimit(0))
+	assert.Nil(t, limit, "the limit is removed")
+	assert.ErrorIs(t, zone.SetPowerLimit(-1*Watt), ErrInvalidPowerLimit)
+
+	// the limit saved, none, is restored
+	saved, err := zone.SavePowerLimit()
+	require.NoError(t, err)
+	assert.Zero(t, saved)
+	require.NoError(t, zone.SetPowerLimit(400*Watt))
+	require.NoError(t, zone.RestorePowerLimit())
+	assert.Nil(t, limit, "the limit is removed on restore")
+
+	zone, err = NewRedfishZone("platform", bmc.URL, WithRedfishChassis("Missing"))
+	require.NoError(t, err)
+	assert.ErrorIs(t, zone.SetPowerLimit(400*Watt), ErrUnsupportedHardware)
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// PowerReaderFn reads the instantaneous power of a zone
+type PowerReaderFn func() (Power, error)
+
+// PowerZone implements EnergyZone for devices (GPUs, power rails etc.) that only
+// report instantaneous power. Energy is derived by integrating power readings
+// over time using the trapezoidal rule.
+type PowerZone struct {
+	name  string
+	index int
+	path  string
+
+	read  PowerReaderFn
+	clock clock.PassiveClock
+
+	mu        sync.Mutex
+	energy    Energy    // integrated energy so far
+	lastPower Power     // power read in the previous call to Energy
+	lastRead  time.Time // time of the previous read; zero if never read
+}
+
+var (
+	_ EnergyZone     = (*PowerZone)(nil)
+	_ EnergyRestorer = (*PowerZone)(nil)
+)
+
+// NewPowerZone creates a new PowerZone that reads power using read
+func NewPowerZone(name string, index int, path string, read PowerReaderFn, c clock.PassiveClock) *PowerZone {
+	if c == nil {
+		c = clock.RealClock{}
+	}
+	return &PowerZone{
+		name:  name,
+		index: index,
+		path:  path,
+		read:  read,
+		clock: c,
+	}
+}
+
+// Name returns the zone name
+func (z *PowerZone) Name() string {
+	return z.name
+}
+
+// Index returns the index of the zone
+func (z *PowerZone) Index() int {
+	return z.index
+}
+
+// Path returns the path from which the power is read
+func (z *PowerZone) Path() string {
+	return z.path
+}
+
+// Energy returns the energy consumed by the zone since the first read
+func (z *PowerZone) Energy() (Energy, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	power, err := z.read()
+	if err != nil {
+		return z.energy, Classify(err)
+	}
+
+	now := z.clock.Now()
+	if !z.lastRead.IsZero() {
+		dt := now.Sub(z.lastRead).Seconds()
+		avg := (power + z.lastPower) / 2
+		// Power is in µW, so µW * s = µJ
+		z.energy += Energy(avg.MicroWatts() * dt)
+	}
+	z.lastPower = power
+	z.lastRead = now
+
+	return z.energy, nil
+}
+
+// RestoreEnergy sets the energy the zone integrates the power from, if it was
+// not read yet
+func (z *PowerZone) RestoreEnergy(energy Energy) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	if z.lastRead.IsZero() {
+		z.energy = energy
+	}
+}
+
+// MaxEnergy returns the maximum energy that can be accumulated; integrated
+// energy never wraps around in practice
+func (z *PowerZone) MaxEnergy() Energy {
+	return Energy(math.MaxUint64)
+}
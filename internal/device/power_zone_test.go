@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestPowerZone_Basics(t *testing.T) {
+	zone := NewPowerZone("gpu-0", 0, "nvidia-smi:GPU-0", func() (Power, error) { return 0, nil }, nil)
+
+	assert.Equal(t, "gpu-0", zone.Name())
+	assert.Equal(t, 0, zone.Index())
+	assert.Equal(t, "nvidia-smi:GPU-0", zone.Path())
+	assert.Greater(t, zone.MaxEnergy(), Energy(0))
+}
+
+func TestPowerZone_Energy(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	power := 100 * Watt
+	zone := NewPowerZone("gpu-0", 0, "", func() (Power, error) { return power, nil }, fakeClock)
+
+	// first read has no Δt to integrate over
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.Equal(t, Energy(0), energy)
+
+	fakeClock.Step(2 * time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 200.0, energy.Joules(), 0.001, "100W over 2s is 200J")
+
+	// trapezoidal integration: avg of 100W and 300W over 1s
+	power = 300 * Watt
+	fakeClock.Step(1 * time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 400.0, energy.Joules(), 0.001)
+}
+
+func TestPowerZone_ReadError(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	readErr := errors.New("device gone")
+	var err error
+	zone := NewPowerZone("gpu-0", 0, "", func() (Power, error) { return 50 * Watt, err }, fakeClock)
+
+	_, _ = zone.Energy()
+	fakeClock.Step(time.Second)
+	before, _ := zone.Energy()
+
+	err = readErr
+	fakeClock.Step(time.Second)
+	energy, readErrRet := zone.Energy()
+	assert.ErrorIs(t, readErrRet, readErr)
+	assert.Equal(t, before, energy, "energy must not change on read errors")
+}
+
+func TestPowerZone_RestoreEnergy(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	zone := NewPowerZone("gpu-0", 0, "", func() (Power, error) { return 100 * Watt, nil }, fakeClock)
+
+	zone.RestoreEnergy(1000 * Joule)
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.Equal(t, 1000*Joule, energy, "integrated from the restored energy")
+
+	zone.RestoreEnergy(0)
+	fakeClock.Step(time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 1100.0, energy.Joules(), 0.001, "not restored once read")
+}
@@ -0,0 +1,230 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// powermetricsLine matches the power of a component in the output of the
+// powermetrics samplers, e.g. "CPU Power: 1234 mW" or "Combined Power (CPU +
+// GPU + ANE): 1290 mW" on Apple Silicon, and "Intel energy model derived
+// package power (CPUs+GT+SA): 1.23W" on Intel Macs
+var powermetricsLine = regexp.MustCompile(`(?m)^\s*(.+?) [Pp]ower(?: \([^)]*\))?: ([0-9.]+) ?(mW|W)\s*$`)
+
+// powermetricsZones are the zones of the components reported by powermetrics,
+// in the order they are created; the package, which covers the whole SoC, is
+// the primary zone. The GPU of Apple Silicon is on the SoC, as is the GPU RAPL
+// reports as uncore.
+var powermetricsZones = []struct {
+	zone       Zone
+	components []string
+}{
+	{ZonePackage, []string{"Combined", "Package", "Intel energy model derived package"}},
+	{ZoneCore, []string{"CPU"}},
+	{ZoneUncore, []string{"GPU"}},
+	{ZoneDRAM, []string{"DRAM"}},
+	{"ane", []string{"ANE"}},
+}
+
+// powermetricsCacheDuration is how long a sample of powermetrics is used for
+// all zones, so that powermetrics is run once per refresh rather than once per
+// zone
+const powermetricsCacheDuration = time.Second
+
+// powermetricsTimeout is how long a sample of powermetrics may take before it
+// is killed, so that a hung powermetrics doesn't stall collections
+const powermetricsTimeout = 5 * time.Second
+
+// powermetricsPowerMeter implements CPUPowerMeter for macOS, which has no
+// RAPL in sysfs, by sampling the power of the CPU, GPU and Neural Engine with
+// powermetrics, which reads them from IOReport on Apple Silicon and from the
+// energy model of the CPU on Intel Macs. powermetrics must be run as root.
+type powermetricsPowerMeter struct {
+	logger *slog.Logger
+	clock  clock.PassiveClock
+	sample func() (string, error)
+
+	zones []EnergyZone
+
+	mu       sync.Mutex
+	cached   map[Zone]Power // power of the zones in the last sample
+	cachedAt time.Time
+}
+
+var _ CPUPowerMeter = (*powermetricsPowerMeter)(nil)
+
+// PowermetricsOptFn is a functional option for configuring the powermetrics
+// power meter
+type PowermetricsOptFn func(*powermetricsPowerMeter)
+
+// WithPowermetricsLogger sets the logger for the powermetrics power meter
+func WithPowermetricsLogger(logger *slog.Logger) PowermetricsOptFn {
+	return func(m *powermetricsPowerMeter) {
+		m.logger = logger.With("service", "powermetrics")
+	}
+}
+
+// WithPowermetricsClock sets the clock used to integrate the power of zones
+func WithPowermetricsClock(c clock.PassiveClock) PowermetricsOptFn {
+	return func(m *powermetricsPowerMeter) {
+		m.clock = c
+	}
+}
+
+// WithPowermetricsPath sets the path of powermetrics
+func WithPowermetricsPath(path string) PowermetricsOptFn {
+	return func(m *powermetricsPowerMeter) {
+		m.sample = func() (string, error) {
+			return runPowermetrics(path, powermetricsTimeout)
+		}
+	}
+}
+
+// NewPowermetricsPowerMeter creates a new CPU power meter for macOS
+func NewPowermetricsPowerMeter(opts ...PowermetricsOptFn) *powermetricsPowerMeter {
+	ret := &powermetricsPowerMeter{
+		logger: slog.Default().With("service", "powermetrics"),
+		clock:  clock.RealClock{},
+	}
+	WithPowermetricsPath("powermetrics")(ret)
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+func (m *powermetricsPowerMeter) Name() string {
+	return "powermetrics"
+}
+
+// Init samples powermetrics and creates a zone of each component it reports
+func (m *powermetricsPowerMeter) Init() error {
+	out, err := m.sample()
+	if err != nil {
+		return fmt.Errorf("failed to run powermetrics: %w", err)
+	}
+	powers := parsePowermetrics(out)
+
+	m.zones = nil
+	for _, z := range powermetricsZones {
+		if _, ok := powers[z.zone]; !ok {
+			continue
+		}
+		zone := z.zone
+		read := func() (Power, error) { return m.power(zone) }
+		m.zones = append(m.zones, NewPowerZone(zone, 0, "powermetrics:"+zone, read, m.clock))
+		m.logger.Info("Found powermetrics zone", "zone", zone, "power", powers[zone])
+	}
+	if len(m.zones) == 0 {
+		return Errorf(ErrUnsupportedHardware, "no power reported by powermetrics")
+	}
+	return nil
+}
+
+func (m *powermetricsPowerMeter) Zones() ([]EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no powermetrics zones initialized")
+	}
+	return m.zones, nil
+}
+
+// PrimaryEnergyZone returns the package zone, which covers the whole SoC, or
+// else the first zone
+func (m *powermetricsPowerMeter) PrimaryEnergyZone() (EnergyZone, error) {
+	zones, err := m.Zones()
+	if err != nil {
+		return nil, err
+	}
+	return zones[0], nil
+}
+
+// power returns the power of a zone sampled by powermetrics, which is run at
+// most once per powermetricsCacheDuration
+func (m *powermetricsPowerMeter) power(zone Zone) (Power, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cached == nil || m.clock.Since(m.cachedAt) >= powermetricsCacheDuration {
+		out, err := m.sample()
+		if err != nil {
+			return 0, fmt.Errorf("failed to run powermetrics: %w", err)
+		}
+		m.cached = parsePowermetrics(out)
+		m.cachedAt = m.clock.Now()
+	}
+
+	power, ok := m.cached[zone]
+	if !ok {
+		return 0, fmt.Errorf("zone %s not found in powermetrics output", zone)
+	}
+	return power, nil
+}
+
+// parsePowermetrics returns the power of the zones in the output of a sample
+// of powermetrics; components of the same zone, e.g. the combined and package
+// power reported by different versions of macOS, are not summed but the first
+// of them found is used
+func parsePowermetrics(out string) map[Zone]Power {
+	components := map[string]Power{}
+	for _, match := range powermetricsLine.FindAllStringSubmatch(out, -1) {
+		v, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			continue
+		}
+		unit := MilliWatt
+		if match[3] == "W" {
+			unit = Watt
+		}
+		components[strings.TrimSpace(match[1])] = Power(v) * unit
+	}
+
+	powers := map[Zone]Power{}
+	for _, z := range powermetricsZones {
+		for _, c := range z.components {
+			if power, ok := components[c]; ok {
+				powers[z.zone] = power
+				break
+			}
+		}
+	}
+	return powers
+}
+
+// runPowermetrics runs powermetrics for a single short sample of the power
+// of the CPU, GPU and Neural Engine, killing it after timeout
+func runPowermetrics(path string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, "--samplers", "cpu_power,gpu_power,ane_power", "--sample-rate", "200", "--sample-count", "1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("powermetrics timed out after %s: %w", timeout, ctx.Err())
+	}
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "superuser") {
+			return "", Errorf(ErrPermission, "%s", msg)
+		}
+		if msg != "" {
+			return "", fmt.Errorf("%w: %s", Classify(err), msg)
+		}
+		return "", Classify(err)
+	}
+	return string(out), nil
+}
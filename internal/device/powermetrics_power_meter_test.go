@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// appleSiliconSample returns a sample of powermetrics on an M2 at cpu and gpu mW
+func appleSiliconSample(cpu, gpu int) string {
+	return fmt.Sprintf(`Machine model: Mac14,2
+OS version: 23F79
+
+*** Sampled system activity (Mon Jun  2 12:00:00 2025 +0200) (204.31ms elapsed) ***
+
+**** Processor usage ****
+
+E-Cluster HW active frequency: 1083 MHz
+P-Cluster HW active frequency: 702 MHz
+
+CPU Power: %d mW
+GPU Power: %d mW
+ANE Power: 0 mW
+Combined Power (CPU + GPU + ANE): %d mW
+
+**** GPU usage ****
+
+GPU HW active frequency: 389 MHz
+GPU Power: %[2]d mW
+`, cpu, gpu, cpu+gpu)
+}
+
+func TestPowermetricsPowerMeter(t *testing.T) {
+	// read by Init, the first and second refresh
+	samples := []string{
+		appleSiliconSample(500, 100),
+		appleSiliconSample(500, 100),
+		appleSiliconSample(1500, 100),
+	}
+	runs := 0
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter := NewPowermetricsPowerMeter(WithPowermetricsClock(fakeClock))
+	meter.sample = func() (string, error) {
+		s := samples[min(runs, len(samples)-1)]
+		runs++
+		return s, nil
+	}
+	assert.Equal(t, "powermetrics", meter.Name())
+	require.NoError(t, meter.Init())
+	assert.Equal(t, []string{"package", "core", "uncore", "ane"}, zoneNames(t, meter))
+	primary, err := meter.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, "package", primary.Name())
+
+	zones, _ := meter.Zones()
+	for _, z := range zones {
+		_, err := z.Energy()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, runs, "powermetrics is run once for all zones")
+
+	fakeClock.Step(2 * time.Second)
+	energy, err := zones[1].Energy()
+	require.NoError(t, err)
+	// trapezoid of 0.5W and 1.5W over 2s
+	assert.InDelta(t, 2.0, energy.Joules(), 0.001)
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, "powermetrics:core", zones[1].Path())
+}
+
+func TestPowermetricsPowerMeter_Errors(t *testing.T) {
+	meter := NewPowermetricsPowerMeter()
+	meter.sample = func() (string, error) { return "", nil }
+	err := meter.Init()
+	assert.ErrorContains(t, err, "no power reported by powermetrics")
+	assert.ErrorIs(t, err, ErrUnsupportedHardware)
+
+	meter.sample = func() (string, error) {
+		return "", Errorf(ErrPermission, "powermetrics must be invoked as the superuser")
+	}
+	err = meter.Init()
+	assert.ErrorContains(t, err, "failed to run powermetrics")
+	assert.ErrorIs(t, err, ErrPermission)
+
+	meter = NewPowermetricsPowerMeter(WithPowermetricsPath(filepath.Join(t.TempDir(), "powermetrics")))
+	err = meter.Init()
+	assert.ErrorContains(t, err, "failed to run powermetrics")
+	assert.True(t, errors.Is(err, ErrUnsupportedHardware), "missing powermetrics is unsupported: %v", err)
+}
+
+func TestRunPowermetricsTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not found")
+	}
+	path := filepath.Join(t.TempDir(), "powermetrics")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nexec sleep 5\n"), 0o755))
+
+	_, err := runPowermetrics(path, 10*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "powermetrics timed out after 10ms")
+}
+
+func TestParsePowermetrics(t *testing.T) {
+	t.Run("apple silicon", func(t *testing.T) {
+		assert.Equal(t, map[Zone]Power{
+			ZonePackage: 1290 * MilliWatt,
+			ZoneCore:    1234 * MilliWatt,
+			ZoneUncore:  56 * MilliWatt,
+			"ane":       0,
+		}, parsePowermetrics(appleSiliconSample(1234, 56)))
+	})
+
+	t.Run("apple silicon on macOS 12", func(t *testing.T) {
+		out := `E-Cluster Power: 7 mW
+P-Cluster Power: 30 mW
+ANE Power: 0 mW
+DRAM Power: 47 mW
+CPU Power: 37 mW
+GPU Power: 13 mW
+Package Power: 177 mW
+`
+		assert.Equal(t, map[Zone]Power{
+			ZonePackage: 177 * MilliWatt,
+			ZoneCore:    37 * MilliWatt,
+			ZoneUncore:  13 * MilliWatt,
+			ZoneDRAM:    47 * MilliWatt,
+			"ane":       0,
+		}, parsePowermetrics(out))
+	})
+
+	t.Run("intel", func(t *testing.T) {
+		out := `**** Processor usage ****
+
+Intel energy model derived package power (CPUs+GT+SA): 4.71W
+
+LLC flushed residency: 79.3%
+`
+		assert.Equal(t, map[Zone]Power{ZonePackage: 4.71 * Watt}, parsePowermetrics(out))
+	})
+}
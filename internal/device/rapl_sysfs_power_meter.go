@@ -6,9 +6,8 @@ package device
 import (
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
-
-	"github.com/prometheus/procfs/sysfs"
 )
 
 // raplPowerMeter implements CPUPowerMeter using sysfs
@@ -17,7 +16,9 @@ type raplPowerMeter struct {
 	cachedZones []EnergyZone
 	logger      *slog.Logger
 	zoneFilter  []string
+	zoneExclude []string
 	topZone     EnergyZone
+	perSocket   bool
 }
 
 type OptionFn func(*raplPowerMeter)
@@ -49,15 +50,31 @@ func WithZoneFilter(zones []string) OptionFn {
 	}
 }
 
+// WithExcludedZones sets zone names to exclude from monitoring; exclusion
+// applies after the zones to include are selected
+func WithExcludedZones(zones []string) OptionFn {
+	return func(pm *raplPowerMeter) {
+		pm.zoneExclude = zones
+	}
+}
+
+// WithPerSocketZones reports zones of each socket separately (e.g. package-0,
+// package-1) instead of aggregating zones of the same type across sockets
+func WithPerSocketZones(enabled bool) OptionFn {
+	return func(pm *raplPowerMeter) {
+		pm.perSocket = enabled
+	}
+}
+
 // NewCPUPowerMeter creates a new CPU power meter
 func NewCPUPowerMeter(sysfsPath string, opts ...OptionFn) (*raplPowerMeter, error) {
-	fs, err := sysfs.NewFS(sysfsPath)
+	reader, err := newSysfsRaplReader(sysfsPath)
 	if err != nil {
 		return nil, err
 	}
 
 	ret := &raplPowerMeter{
-		reader:     sysfsRaplReader{fs: fs},
+		reader:     reader,
 		logger:     slog.Default().With("service", "rapl"),
 		zoneFilter: []string{},
 	}
@@ -79,7 +96,7 @@ func (r *raplPowerMeter) Init() error {
 	if err != nil {
 		return err
 	} else if len(zones) == 0 {
-		return fmt.Errorf("no RAPL zones found")
+		return Errorf(ErrUnsupportedHardware, "no RAPL zones found")
 	}
 
 	// try reading the first zone and return the error
@@ -88,11 +105,11 @@ func (r *raplPowerMeter) Init() error {
 }
 
 func (r *raplPowerMeter) needsFiltering() bool {
-	return len(r.zoneFilter) != 0
+	return len(r.zoneFilter) != 0 || len(r.zoneExclude) != 0
 }
 
-// filterZones applies the configured zone filter
-// If the filter is empty, all zones are returned
+// filterZones applies the configured zone filter and exclusions
+// If both are empty, all zones are returned
 func (r *raplPowerMeter) filterZones(zones []EnergyZone) []EnergyZone {
 	if !r.needsFiltering() {
 		return zones
@@ -102,10 +119,15 @@ func (r *raplPowerMeter) filterZones(zones []EnergyZone) []EnergyZone {
 	for _, name := range r.zoneFilter {
 		wanted[strings.ToLower(name)] = true
 	}
+	unwanted := make(map[string]bool, len(r.zoneExclude))
+	for _, name := range r.zoneExclude {
+		unwanted[strings.ToLower(name)] = true
+	}
 	var included, excluded []string
 	filtered := make([]EnergyZone, 0, len(zones))
 	for _, zone := range zones {
-		if wanted[strings.ToLower(zone.Name())] {
+		name := strings.ToLower(zone.Name())
+		if (len(wanted) == 0 || wanted[name]) && !unwanted[name] {
 			filtered = append(filtered, zone)
 			included = append(included, zone.Name())
 		} else {
@@ -126,7 +148,7 @@ func (r *raplPowerMeter) Zones() ([]EnergyZone, error) {
 	if err != nil {
 		return nil, err
 	} else if len(zones) == 0 {
-		return nil, fmt.Errorf("no RAPL zones found")
+		return nil, Errorf(ErrUnsupportedHardware, "no RAPL zones found")
 	}
 
 	zones = r.filterZones(zones)
@@ -134,6 +156,11 @@ func (r *raplPowerMeter) Zones() ([]EnergyZone, error) {
 		return nil, fmt.Errorf("no RAPL zones found after filtering")
 	}
 
+	zones = r.readableZones(zones)
+	if len(zones) == 0 {
+		return nil, Errorf(ErrUnsupportedHardware, "no readable RAPL zones found")
+	}
+
 	// filter out non-standard zones
 
 	stdZoneMap := map[zoneKey]EnergyZone{}
@@ -147,11 +174,48 @@ func (r *raplPowerMeter) Zones() ([]EnergyZone, error) {
 		stdZoneMap[key] = zone
 	}
 
+	if r.perSocket {
+		r.cachedZones = r.socketZones(stdZoneMap)
+		return r.cachedZones, nil
+	}
+
 	// Group zones by name for aggregation
 	r.cachedZones = r.groupZonesByName(stdZoneMap)
 	return r.cachedZones, nil
 }
 
+// readableZones returns the zones whose energy can be read; zones such as psys
+// are listed but can't be read on some platforms
+func (r *raplPowerMeter) readableZones(zones []EnergyZone) []EnergyZone {
+	readable := make([]EnergyZone, 0, len(zones))
+	for _, zone := range zones {
+		if _, err := zone.Energy(); err != nil {
+			r.logger.Warn("Skipping unreadable RAPL zone", "zone", zone.Name(), "path", zone.Path(), "error", err)
+			continue
+		}
+		readable = append(readable, zone)
+	}
+	return readable
+}
+
+// socketZones wraps each zone in a SocketZone so that zones of each socket are
+// reported separately; zones are sorted by name for a stable order. The psys
+// zone covers the whole platform rather than a socket and is not wrapped.
+func (r *raplPowerMeter) socketZones(stdZoneMap map[zoneKey]EnergyZone) []EnergyZone {
+	result := make([]EnergyZone, 0, len(stdZoneMap))
+	for _, zone := range stdZoneMap {
+		if strings.EqualFold(zone.Name(), string(ZonePSys)) {
+			result = append(result, zone)
+			continue
+		}
+		result = append(result, NewSocketZone(zone))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
 // groupZonesByName groups zones by their base name and creates AggregatedZone
 // instances when multiple zones share the same name (multi-socket systems)
 func (r *raplPowerMeter) groupZonesByName(stdZoneMap map[zoneKey]EnergyZone) []EnergyZone {
@@ -209,9 +273,13 @@ func (r *raplPowerMeter) PrimaryEnergyZone() (EnergyZone, error) {
 		return nil, fmt.Errorf("no energy zones available")
 	}
 
+	// NOTE: with per-socket zones, the zone of the first socket is used
 	zoneMap := map[string]EnergyZone{}
 	for _, zone := range zones {
-		zoneMap[strings.ToLower(zone.Name())] = zone
+		name := strings.ToLower(BaseZoneName(zone))
+		if _, exists := zoneMap[name]; !exists {
+			zoneMap[name] = zone
+		}
 	}
 
 	// Priority hierarchy for RAPL zones (highest to lowest priority)
@@ -234,54 +302,3 @@ func (r *raplPowerMeter) PrimaryEnergyZone() (EnergyZone, error) {
 func isStandardRaplPath(path string) bool {
 	return strings.Contains(path, "/intel-rapl:")
 }
-
-type sysfsRaplReader struct {
-	fs sysfs.FS
-}
-
-func (r sysfsRaplReader) Zones() ([]EnergyZone, error) {
-	raplZones, err := sysfs.GetRaplZones(r.fs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read rapl zones: %w", err)
-	}
-
-	// convert sysfs.RaplZones to EnergyZones
-	energyZones := make([]EnergyZone, 0, len(raplZones))
-	for _, zone := range raplZones {
-		energyZones = append(energyZones, sysfsRaplZone{zone})
-	}
-
-	return energyZones, nil
-}
-
-// sysfsRaplZone implements EnergyZone using sysfs.RaplZone.
-// It is an adapter for the EnergyZone interface
-type sysfsRaplZone struct {
-	zone sysfs.RaplZone
-}
-
-// Name returns the name of the zone
-func (s sysfsRaplZone) Name() string {
-	return s.zone.Name
-}
-
-// Index returns the index of the zone
-func (s sysfsRaplZone) Index() int {
-	return s.zone.Index
-}
-
-// Path returns the path of the zone
-func (s sysfsRaplZone) Path() string {
-	return s.zone.Path
-}
-
-// Energy returns the current energy value
-func (s sysfsRaplZone) Energy() (Energy, error) {
-	mj, err := s.zone.GetEnergyMicrojoules()
-	return Energy(mj), err
-}
-
-// MaxEnergy returns the maximum energy value before wraparound
-func (s sysfsRaplZone) MaxEnergy() Energy {
-	return Energy(s.zone.MaxMicrojoules)
-}
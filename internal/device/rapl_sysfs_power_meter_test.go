@@ -1,6 +1,8 @@
 // SPDX-FileCopyrightText: 2025 The Kepler Authors
 // SPDX-License-Identifier: Apache-2.0
 
+//go:build linux
+
 package device
 
 import (
@@ -15,6 +17,25 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+const (
+	validSysFSPath = "testdata/sys"
+	badSysFSPath   = "testdata/bad_sysfs"
+)
+
+func validSysFSFixtures(t *testing.T) sysfs.FS {
+	t.Helper()
+	fs, err := sysfs.NewFS(validSysFSPath)
+	require.NoError(t, err, "Failed to create sysfs test FS")
+	return fs
+}
+
+func invalidSysFSFixtures(t *testing.T) sysfs.FS {
+	t.Helper()
+	fs, err := sysfs.NewFS(badSysFSPath)
+	require.NoError(t, err, "Failed to create sysfs test FS")
+	return fs
+}
+
 // TestCPUPowerMeterInterface ensures that raplPowerMeter properly implements the CPUPowerMeter interface
 func TestCPUPowerMeterInterface(t *testing.T) {
 	var _ CPUPowerMeter = (*raplPowerMeter)(nil)
@@ -191,19 +212,93 @@ func TestAggregatedZoneIntegration(t *testing.T) {
 	assert.Equal(t, Energy(3000), packageEnergy) // 1000 + 2000 from both package zones
 }
 
-type mockZone struct {
-	name      string
-	index     int
-	path      string
-	energy    Energy
-	maxEnergy Energy
+func TestPerSocketZones(t *testing.T) {
+	mockReader := &mockSysFSReader{
+		response: []EnergyZone{
+			mockZone{name: "package", index: 0, path: "/intel-rapl:0", energy: 1000, maxEnergy: 100000},
+			mockZone{name: "package", index: 1, path: "/intel-rapl:1", energy: 2000, maxEnergy: 100000},
+			mockZone{name: "dram", index: 0, path: "/intel-rapl:0:0", energy: 500, maxEnergy: 50000},
+			mockZone{name: "dram", index: 1, path: "/intel-rapl:1:0", energy: 700, maxEnergy: 50000},
+		},
+	}
+
+	rapl := &raplPowerMeter{
+		reader:    mockReader,
+		logger:    slog.Default(),
+		perSocket: true,
+	}
+
+	zones, err := rapl.Zones()
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		_, isSocketZone := zone.(*SocketZone)
+		assert.True(t, isSocketZone, "%s should be a SocketZone", zone.Name())
+		names = append(names, zone.Name())
+	}
+	assert.Equal(t, []string{"dram-0", "dram-1", "package-0", "package-1"}, names)
+
+	energy, err := zones[3].Energy()
+	require.NoError(t, err)
+	assert.Equal(t, Energy(2000), energy)
+
+	primary, err := rapl.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, "package-0", primary.Name())
+}
+
+func TestPerSocketZones_PSys(t *testing.T) {
+	// psys is a top-level domain next to the package of single socket laptops
+	mockReader := &mockSysFSReader{
+		response: []EnergyZone{
+			mockZone{name: "package", index: 0, path: "/intel-rapl:0", energy: 1000, maxEnergy: 100000},
+			mockZone{name: "psys", index: 0, path: "/intel-rapl:1", energy: 3000, maxEnergy: 100000},
+		},
+	}
+
+	rapl := &raplPowerMeter{
+		reader:    mockReader,
+		logger:    slog.Default(),
+		perSocket: true,
+	}
+
+	zones, err := rapl.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 2)
+
+	assert.Equal(t, "package-0", zones[0].Name())
+	assert.Equal(t, "psys", zones[1].Name())
+	_, isSocketZone := zones[1].(*SocketZone)
+	assert.False(t, isSocketZone, "psys covers the platform rather than a socket")
+
+	primary, err := rapl.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, "psys", primary.Name())
 }
 
-func (m mockZone) Name() string            { return m.name }
-func (m mockZone) Index() int              { return m.index }
-func (m mockZone) Path() string            { return m.path }
-func (m mockZone) Energy() (Energy, error) { return m.energy, nil }
-func (m mockZone) MaxEnergy() Energy       { return m.maxEnergy }
+func TestUnreadableZones(t *testing.T) {
+	pkg := NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+	psys := NewMockRaplZone("psys", 0, "/sys/class/powercap/intel-rapl/intel-rapl:1", 1000)
+	psys.OnEnergy(0, errors.New("no data available"))
+
+	rapl := &raplPowerMeter{
+		reader: &mockSysFSReader{response: []EnergyZone{pkg, psys}},
+		logger: slog.Default(),
+	}
+	zones, err := rapl.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.Equal(t, "package", zones[0].Name())
+
+	pkg.OnEnergy(0, errors.New("permission denied"))
+	rapl = &raplPowerMeter{
+		reader: &mockSysFSReader{response: []EnergyZone{pkg, psys}},
+		logger: slog.Default(),
+	}
+	_, err = rapl.Zones()
+	assert.ErrorContains(t, err, "no readable RAPL zones")
+}
 
 type mockSysFSReader struct {
 	response []EnergyZone
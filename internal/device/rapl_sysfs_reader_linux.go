@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package device
+
+import (
+	"fmt"
+
+	"github.com/prometheus/procfs/sysfs"
+)
+
+// newSysfsRaplReader returns a reader of the RAPL zones of the sysfs mounted at
+// sysfsPath
+func newSysfsRaplReader(sysfsPath string) (sysfsReader, error) {
+	fs, err := sysfs.NewFS(sysfsPath)
+	if err != nil {
+		return nil, err
+	}
+	return sysfsRaplReader{fs: fs}, nil
+}
+
+type sysfsRaplReader struct {
+	fs sysfs.FS
+}
+
+func (r sysfsRaplReader) Zones() ([]EnergyZone, error) {
+	raplZones, err := sysfs.GetRaplZones(r.fs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rapl zones: %w", Classify(err))
+	}
+
+	// convert sysfs.RaplZones to EnergyZones
+	energyZones := make([]EnergyZone, 0, len(raplZones))
+	for _, zone := range raplZones {
+		energyZones = append(energyZones, sysfsRaplZone{zone})
+	}
+
+	return energyZones, nil
+}
+
+// sysfsRaplZone implements EnergyZone using sysfs.RaplZone.
+// It is an adapter for the EnergyZone interface
+type sysfsRaplZone struct {
+	zone sysfs.RaplZone
+}
+
+// Name returns the name of the zone
+func (s sysfsRaplZone) Name() string {
+	return s.zone.Name
+}
+
+// Index returns the index of the zone
+func (s sysfsRaplZone) Index() int {
+	return s.zone.Index
+}
+
+// Path returns the path of the zone
+func (s sysfsRaplZone) Path() string {
+	return s.zone.Path
+}
+
+// Energy returns the current energy value
+func (s sysfsRaplZone) Energy() (Energy, error) {
+	mj, err := s.zone.GetEnergyMicrojoules()
+	return Energy(mj), Classify(err)
+}
+
+// MaxEnergy returns the maximum energy value before wraparound
+func (s sysfsRaplZone) MaxEnergy() Energy {
+	return Energy(s.zone.MaxMicrojoules)
+}
@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package device
+
+// newSysfsRaplReader fails as RAPL zones are only read from sysfs on linux
+func newSysfsRaplReader(string) (sysfsReader, error) {
+	return nil, Errorf(ErrUnsupportedHardware, "RAPL is only supported on linux")
+}
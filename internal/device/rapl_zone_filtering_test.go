@@ -1,6 +1,8 @@
 // SPDX-FileCopyrightText: 2025 The Kepler Authors
 // SPDX-License-Identifier: Apache-2.0
 
+//go:build linux
+
 package device
 
 import (
@@ -101,6 +103,51 @@ func TestRaplZoneFiltering(t *testing.T) {
 	}
 }
 
+func TestRaplZoneExclusion(t *testing.T) {
+	allZones := []EnergyZone{
+		&MockRaplZone{name: "psys", path: "/sys/class/powercap/intel-rapl/intel-rapl:1", index: 0},
+		&MockRaplZone{name: "package", path: "/sys/class/powercap/intel-rapl/intel-rapl:0", index: 0},
+		&MockRaplZone{name: "core", path: "/sys/class/powercap/intel-rapl/intel-rapl:0:0", index: 0},
+		&MockRaplZone{name: "uncore", path: "/sys/class/powercap/intel-rapl/intel-rapl:0:1", index: 0},
+	}
+
+	tests := []struct {
+		name          string
+		include       []string
+		exclude       []string
+		expectedZones []string
+	}{{
+		name:          "exclude only",
+		exclude:       []string{"core", "Uncore"},
+		expectedZones: []string{"psys", "package"},
+	}, {
+		name:          "include and exclude",
+		include:       []string{"package", "core"},
+		exclude:       []string{"core"},
+		expectedZones: []string{"package"},
+	}, {
+		name:          "exclude everything",
+		exclude:       []string{"psys", "package", "core", "uncore"},
+		expectedZones: []string{},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			meter := &raplPowerMeter{
+				logger:      slog.Default(),
+				zoneFilter:  tc.include,
+				zoneExclude: tc.exclude,
+			}
+
+			names := []string{}
+			for _, zone := range meter.filterZones(allZones) {
+				names = append(names, zone.Name())
+			}
+			assert.Equal(t, tc.expectedZones, names)
+		})
+	}
+}
+
 // Test that zone filtering applies during Init
 func TestRaplZoneFiltering_Init(t *testing.T) {
 	packageZone := &MockRaplZone{
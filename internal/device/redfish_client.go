@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// redfishSessionsPath is the collection sessions of the BMC are created in
+const redfishSessionsPath = "/redfish/v1/SessionService/Sessions"
+
+// redfishClientKey identifies the clients that can share a session
+type redfishClientKey struct {
+	endpoint           string
+	username, password string
+	insecureSkipVerify bool
+}
+
+// redfishClients are the clients of the BMCs, shared by the zones reading or
+// limiting the power of the same BMC with the same credentials, since BMCs
+// allow few concurrent sessions and handle concurrent requests poorly
+var redfishClients = struct {
+	mu      sync.Mutex
+	clients map[redfishClientKey]*redfishClient
+}{clients: map[redfishClientKey]*redfishClient{}}
+
+// redfishLimits limit the rate of the requests sent to a BMC
+type redfishLimits struct {
+	minInterval time.Duration // between the requests to the BMC
+	jitter      time.Duration // max random delay added to minInterval and to the first request
+	clock       clock.Clock
+}
+
+// redfishClient sends the requests of all the zones of a BMC, one operation
+// at a time, authenticated with a single Redfish session. BMCs that don't support
+// sessions are sent the credentials with HTTP basic authentication.
+//
+// Operations are run at least minInterval apart, plus a random jitter, and the
+// first one after a random jitter, so that the instances of Kepler sharing a
+// management network don't send their requests at the same time.
+type redfishClient struct {
+	key    redfishClientKey
+	client *http.Client
+	limits redfishLimits
+
+	mu        sync.Mutex // serializes the operations on the BMC
+	token     string     // X-Auth-Token of the session; empty if none
+	session   string     // URL of the session, deleted to log out
+	basicAuth bool       // the BMC doesn't support sessions
+
+	limitMu   sync.Mutex
+	notBefore time.Time // the next operation is run after
+}
+
+// redfishClientFor returns the client of the BMC at endpoint shared by all
+// zones using the same credentials, creating it with limits if needed; the
+// limits of the first zone of the BMC apply
+func redfishClientFor(key redfishClientKey, limits redfishLimits) *redfishClient {
+	redfishClients.mu.Lock()
+	defer redfishClients.mu.Unlock()
+
+	if c, ok := redfishClients.clients[key]; ok {
+		return c
+	}
+	c := &redfishClient{
+		key:    key,
+		client: &http.Client{Timeout: redfishFetchTimeout},
+		limits: limits,
+	}
+	if key.insecureSkipVerify {
+		c.client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	c.notBefore = limits.clock.Now().Add(c.jitter())
+	redfishClients.clients[key] = c
+	return c
+}
+
+// jitter returns a random delay up to the jitter of the limits
+func (c *redfishClient) jitter() time.Duration {
+	if c.limits.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(c.limits.jitter)))
+}
+
+// ready returns true if an operation can be run on the BMC without waiting
+func (c *redfishClient) ready() bool {
+	c.limitMu.Lock()
+	defer c.limitMu.Unlock()
+	return !c.limits.clock.Now().Before(c.notBefore)
+}
+
+// wait waits until an operation can be run on the BMC
+func (c *redfishClient) wait() {
+	c.limitMu.Lock()
+	delay := c.notBefore.Sub(c.limits.clock.Now())
+	c.limitMu.Unlock()
+	if delay > 0 {
+		<-c.limits.clock.After(delay)
+	}
+}
+
+// ran delays the next operation by the min interval and a random jitter
+func (c *redfishClient) ran() {
+	c.limitMu.Lock()
+	defer c.limitMu.Unlock()
+	c.notBefore = c.limits.clock.Now().Add(c.limits.minInterval + c.jitter())
+}
+
+// CloseRedfishSessions logs out of the sessions of all the BMCs; they are
+// logged in again if they are read afterwards
+func CloseRedfishSessions() error {
+	redfishClients.mu.Lock()
+	clients := make([]*redfishClient, 0, len(redfishClients.clients))
+	for _, c := range redfishClients.clients {
+		clients = append(clients, c)
+	}
+	redfishClients.mu.Unlock()
+
+	var errs []error
+	for _, c := range clients {
+		errs = append(errs, c.logout())
+	}
+	return errors.Join(errs...)
+}
+
+// run runs fn, an operation sending its requests with do, once the rate
+// limits allow and no other operation is running
+func (c *redfishClient) run(fn func() error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.wait()
+	defer c.ran()
+	return fn()
+}
+
+// do sends a request with body, if not nil, encoded as JSON to path and
+// decodes the response into v, if not nil; the session is created again once
+// if it expired. It must be called by an operation passed to run.
+func (c *redfishClient) do(method, path string, body, v any) error {
+	if err := c.login(); err != nil {
+		return err
+	}
+	resp, err := c.send(method, path, body)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && c.token != "" {
+		_ = resp.Body.Close()
+		c.token, c.session = "", ""
+		if err := c.login(); err != nil {
+			return err
+		}
+		resp, err = c.send(method, path, body)
+	}
+
+	action := "read"
+	if method != http.MethodGet {
+		action = "update"
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s %s on the BMC: %w", action, path, Classify(err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := c.checkStatus(resp, action, path); err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s of the BMC: %w", path, err)
+	}
+	return nil
+}
+
+// send sends a request with the session token or the credentials
+func (c *redfishClient) send(method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.url(path), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("X-Auth-Token", c.token)
+	case c.basicAuth:
+		req.SetBasicAuth(c.key.username, c.key.password)
+	}
+	return c.client.Do(req)
+}
+
+// login creates a session if there are credentials and none was created; BMCs
+// without a session service fall back to basic authentication
+func (c *redfishClient) login() error {
+	if c.key.username == "" || c.token != "" || c.basicAuth {
+		return nil
+	}
+
+	credentials := map[string]string{"UserName": c.key.username, "Password": c.key.password}
+	resp, err := c.send(http.MethodPost, redfishSessionsPath, credentials)
+	if err != nil {
+		return fmt.Errorf("failed to log in to the BMC: %w", Classify(err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		c.basicAuth = true
+		return nil
+	}
+	if err := c.checkStatus(resp, "create", redfishSessionsPath); err != nil {
+		return err
+	}
+	token := resp.Header.Get("X-Auth-Token")
+	if token == "" {
+		// the session is unusable; the credentials are sent instead
+		c.basicAuth = true
+		return nil
+	}
+	c.token, c.session = token, resp.Header.Get("Location")
+	return nil
+}
+
+// logout deletes the session, if any
+func (c *redfishClient) logout() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == "" {
+		return nil
+	}
+	session := c.session
+	defer func() { c.token, c.session = "", "" }()
+	if session == "" {
+		return nil
+	}
+
+	resp, err := c.send(http.MethodDelete, session, nil)
+	if err != nil {
+		return fmt.Errorf("failed to log out of the BMC: %w", Classify(err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return c.checkStatus(resp, "delete", session)
+}
+
+// url returns the URL of path, which may already be a URL, e.g. the Location
+// of a session
+func (c *redfishClient) url(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return c.key.endpoint + path
+}
+
+// checkStatus returns an error classified by the status of resp if it is not
+// successful
+func (c *redfishClient) checkStatus(resp *http.Response, action, path string) error {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return Errorf(ErrPermission, "failed to %s %s on the BMC at %s: %s", action, path, c.key.endpoint, resp.Status)
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusMethodNotAllowed:
+		return Errorf(ErrUnsupportedHardware, "failed to %s %s on the BMC at %s: %s", action, path, c.key.endpoint, resp.Status)
+	default:
+		return Errorf(ErrTransient, "failed to %s %s on the BMC at %s: %s", action, path, c.key.endpoint, resp.Status)
+	}
+}
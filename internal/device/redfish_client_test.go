@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// sessionBMC is a BMC that only accepts requests authenticated with a session,
+// and fails requests sent while another is in flight
+type sessionBMC struct {
+	*httptest.Server
+	logins, logouts atomic.Int32
+	concurrent      atomic.Bool // a request was sent while another was in flight
+
+	mu       sync.Mutex
+	token    string
+	inFlight bool
+}
+
+func newSessionBMC(t *testing.T) *sessionBMC {
+	t.Helper()
+	bmc := &sessionBMC{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /redfish/v1/SessionService/Sessions", func(w http.ResponseWriter, r *http.Request) {
+		var credentials struct{ UserName, Password string }
+		if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil || credentials.UserName != "root" || credentials.Password != "calvin" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := bmc.logins.Add(1)
+		bmc.mu.Lock()
+		bmc.token = fmt.Sprintf("token-%d", n)
+		bmc.mu.Unlock()
+		w.Header().Set("X-Auth-Token", bmc.token)
+		w.Header().Set("Location", fmt.Sprintf("/redfish/v1/SessionService/Sessions/%d", n))
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("DELETE /redfish/v1/SessionService/Sessions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !bmc.authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		bmc.logouts.Add(1)
+	})
+	mux.HandleFunc("/redfish/v1/Chassis/1/Power", func(w http.ResponseWriter, r *http.Request) {
+		if !bmc.authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		bmc.mu.Lock()
+		if bmc.inFlight {
+			bmc.concurrent.Store(true)
+		}
+		bmc.inFlight = true
+		bmc.mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+		_, _ = fmt.Fprint(w, `{"PowerControl": [{"PowerConsumedWatts": 300, "PowerCapacityWatts": 750}]}`)
+
+		bmc.mu.Lock()
+		bmc.inFlight = false
+		bmc.mu.Unlock()
+	})
+	bmc.Server = httptest.NewServer(mux)
+	t.Cleanup(bmc.Close)
+	return bmc
+}
+
+func (b *sessionBMC) authorized(r *http.Request) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.token != "" && r.Header.Get("X-Auth-Token") == b.token
+}
+
+// expire expires the session of the BMC
+func (b *sessionBMC) expire() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.token = ""
+}
+
+func TestRedfishClientSharedSession(t *testing.T) {
+	bmc := newSessionBMC(t)
+	newZone := func(name string) *RedfishZone {
+		zone, err := NewRedfishZone(name, bmc.URL, WithRedfishChassis("1"), WithRedfishCredentials("root", "calvin"))
+		require.NoError(t, err)
+		return zone
+	}
+	platform, limiter := newZone("platform"), newZone("platform")
+	assert.Same(t, platform.client, limiter.client, "zones of the same BMC share a client")
+
+	other, err := NewRedfishZone("platform", bmc.URL, WithRedfishChassis("1"), WithRedfishCredentials("root", "other"))
+	require.NoError(t, err)
+	assert.NotSame(t, platform.client, other.client, "clients are not shared across credentials")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := platform.Energy()
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := limiter.PowerCapacity()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), bmc.logins.Load(), "a single session is created")
+	assert.False(t, bmc.concurrent.Load(), "requests are serialized")
+
+	t.Run("expired session", func(t *testing.T) {
+		bmc.expire()
+		capacity, err := limiter.PowerCapacity()
+		require.NoError(t, err)
+		assert.Equal(t, 750*Watt, capacity)
+		assert.Equal(t, int32(2), bmc.logins.Load(), "the session is created again")
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		require.NoError(t, CloseRedfishSessions())
+		assert.Equal(t, int32(1), bmc.logouts.Load())
+		assert.Empty(t, platform.client.token)
+
+		_, err := platform.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), bmc.logins.Load(), "the BMC is logged in again")
+		require.NoError(t, CloseRedfishSessions())
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		_, err := other.Energy()
+		assert.ErrorIs(t, err, ErrPermission)
+	})
+}
+
+func TestRedfishClientRateLimits(t *testing.T) {
+	bmc := fakeBMC(t)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	zone, err := NewRedfishZone("platform", bmc.URL,
+		WithRedfishClock(fakeClock),
+		WithRedfishCredentials("root", "calvin"),
+		WithRedfishMinInterval(10*time.Second),
+		WithRedfishJitter(5*time.Second))
+	require.NoError(t, err)
+
+	// the first request is delayed by up to the jitter
+	fakeClock.Step(5 * time.Second)
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.Zero(t, energy)
+
+	// the power read last, 100 W, is used until the min interval elapses
+	fakeClock.Step(2 * time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 200.0, energy.Joules(), 0.001)
+
+	// 200 W is read once the min interval and the jitter elapsed
+	fakeClock.Step(13 * time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 200.0+13*150, energy.Joules(), 0.001)
+
+	// other requests wait
+	done := make(chan error)
+	go func() {
+		_, err := zone.PowerCapacity()
+		done <- err
+	}()
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, 10*time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("the request was sent before the min interval elapsed")
+	default:
+	}
+	fakeClock.Step(15 * time.Second)
+	assert.NoError(t, <-done)
+}
+
+func TestRedfishZoneFirstReadDelayed(t *testing.T) {
+	bmc := fakeBMC(t)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	zone, err := NewRedfishZone("platform", bmc.URL,
+		WithRedfishClock(fakeClock),
+		WithRedfishCredentials("root", "calvin"),
+		WithRedfishJitter(time.Hour))
+	require.NoError(t, err)
+
+	if !zone.client.ready() {
+		_, err = zone.Energy()
+		assert.ErrorIs(t, err, ErrTransient, "the zone is unavailable until the first read")
+	}
+	fakeClock.Step(time.Hour)
+	_, err = zone.Energy()
+	assert.NoError(t, err)
+}
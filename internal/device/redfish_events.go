@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Kinds of power events logged by BMCs
+const (
+	PowerEventPSUFailure = "psu-failure" // a power supply failed or lost redundancy
+	PowerEventPowerCap   = "power-cap"   // the power cap of the BMC engaged
+	PowerEventBrownout   = "brownout"    // the input voltage dropped or was lost
+)
+
+// PowerEvent is an entry of a log service of the BMC related to power
+type PowerEvent struct {
+	ID        string // URI of the log entry
+	Kind      string
+	Created   time.Time // zero if the BMC reports no valid time
+	Severity  string    // OK, Warning or Critical
+	MessageID string
+	Message   string
+}
+
+// powerEventKeywords are the keywords in the messages of log entries of each
+// kind of power events, checked in order since a brownout is often logged as
+// the loss of the input of a power supply
+var powerEventKeywords = []struct {
+	kind     string
+	keywords []string
+}{
+	{PowerEventBrownout, []string{"brownout", "brown-out", "undervoltage", "under-voltage", "low input voltage",
+		"lost input", "input lost", "power input", "ac lost", "power outage"}},
+	{PowerEventPowerCap, []string{"power cap", "power limit", "power throttl"}},
+	{PowerEventPSUFailure, []string{"power suppl", "psu"}},
+}
+
+// powerEventRecoveries are the keywords of the messages of log entries ending
+// a power event
+var powerEventRecoveries = []string{"regained", "restored", "recovered", "returned to normal", "no longer"}
+
+// powerSupplySensorType is the sensor type of IPMI SEL entries of power
+// supplies
+const powerSupplySensorType = "Power Supply / Converter"
+
+// redfishLogEntry is the part of a Redfish LogEntry read
+type redfishLogEntry struct {
+	ID         string `json:"@odata.id"`
+	Created    string `json:"Created"` // RFC 3339, but empty or malformed on some BMCs
+	Severity   string `json:"Severity"`
+	Message    string `json:"Message"`
+	MessageID  string `json:"MessageId"`
+	SensorType string `json:"SensorType"`
+	EntryCode  string `json:"EntryCode"`
+}
+
+// powerEventKind returns the kind of power event of entry; empty if it is not
+// related to power or it is the end of an event, e.g. the redundancy of power
+// supplies regained
+func powerEventKind(entry redfishLogEntry) string {
+	if entry.EntryCode == "Deassert" {
+		return ""
+	}
+	message := strings.ToLower(entry.Message + " " + entry.MessageID)
+	for _, keyword := range powerEventRecoveries {
+		if strings.Contains(message, keyword) {
+			return ""
+		}
+	}
+	for _, k := range powerEventKeywords {
+		for _, keyword := range k.keywords {
+			if strings.Contains(message, keyword) {
+				if k.kind == PowerEventPSUFailure && !psuFailed(message) {
+					break
+				}
+				return k.kind
+			}
+		}
+	}
+	if entry.SensorType == powerSupplySensorType && psuFailed(message) {
+		return PowerEventPSUFailure
+	}
+	return ""
+}
+
+// psuFailed returns true if message reports the failure of a power supply
+// rather than, e.g., its insertion
+func psuFailed(message string) bool {
+	for _, keyword := range []string{"fail", "fault", "lost", "redundan", "removed", "not present", "critical"} {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRedfishLogServices sets the paths of the log services whose entries are
+// read for power events, e.g. /redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel;
+// by default those of the first manager and system of the BMC
+func WithRedfishLogServices(paths ...string) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.logServices = paths
+	}
+}
+
+// PowerEvents returns the entries of the log services of the BMC related to
+// power, in the order of the logs. The log services are discovered on the
+// first call if they are not set; only the first page of the entries of each
+// log service is read, which holds the most recent entries on most BMCs. The
+// zone isn't locked, so that its energy is read while the logs are, the
+// operations on the BMC being serialized by its client.
+func (z *RedfishZone) PowerEvents() ([]PowerEvent, error) {
+	var events []PowerEvent
+	err := z.client.run(func() error {
+		if err := z.findLogServices(); err != nil {
+			return err
+		}
+		for _, service := range z.logServices {
+			var entries struct {
+				Members []redfishLogEntry `json:"Members"`
+			}
+			if err := z.get(strings.TrimSuffix(service, "/")+"/Entries", &entries); err != nil {
+				return err
+			}
+			for _, entry := range entries.Members {
+				kind := powerEventKind(entry)
+				if kind == "" {
+					continue
+				}
+				created, _ := time.Parse(time.RFC3339, entry.Created)
+				events = append(events, PowerEvent{
+					ID:        entry.ID,
+					Kind:      kind,
+					Created:   created,
+					Severity:  entry.Severity,
+					MessageID: entry.MessageID,
+					Message:   entry.Message,
+				})
+			}
+		}
+		return nil
+	})
+	return events, err
+}
+
+// findLogServices finds the log services of the first manager and system of
+// the BMC if they are not known
+func (z *RedfishZone) findLogServices() error {
+	if len(z.logServices) > 0 {
+		return nil
+	}
+	type collection struct {
+		Members []struct {
+			ID string `json:"@odata.id"`
+		} `json:"Members"`
+	}
+
+	var services []string
+	for _, path := range []string{"/redfish/v1/Managers", "/redfish/v1/Systems"} {
+		var resources collection
+		if err := z.get(path, &resources); err != nil {
+			return err
+		}
+		if len(resources.Members) == 0 {
+			continue
+		}
+		var logs collection
+		if err := z.get(resources.Members[0].ID+"/LogServices", &logs); err != nil {
+			if errors.Is(err, ErrUnsupportedHardware) {
+				continue
+			}
+			return err
+		}
+		for _, m := range logs.Members {
+			services = append(services, m.ID)
+		}
+	}
+	if len(services) == 0 {
+		return Errorf(ErrUnsupportedHardware, "no log services found on the BMC at %s", z.endpoint)
+	}
+	z.logServices = services
+	return nil
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowerEventKind(t *testing.T) {
+	tt := []struct {
+		name  string
+		entry redfishLogEntry
+		kind  string
+	}{{
+		name:  "psu failure",
+		entry: redfishLogEntry{Message: "Power supply 2 failed.", MessageID: "PSU0003", Severity: "Critical"},
+		kind:  PowerEventPSUFailure,
+	}, {
+		name:  "psu redundancy lost",
+		entry: redfishLogEntry{Message: "The power supplies are not redundant. Insufficient resources to maintain normal operations."},
+		kind:  PowerEventPSUFailure,
+	}, {
+		name:  "psu redundancy regained",
+		entry: redfishLogEntry{Message: "The power supplies redundancy is regained.", SensorType: "Power Supply / Converter"},
+		kind:  "",
+	}, {
+		name:  "psu redundancy lost with sensor type",
+		entry: redfishLogEntry{Message: "PS Redundancy lost", SensorType: "Power Supply / Converter"},
+		kind:  PowerEventPSUFailure,
+	}, {
+		name:  "psu inserted",
+		entry: redfishLogEntry{Message: "Power supply 2 is present."},
+		kind:  "",
+	}, {
+		name:  "input lost",
+		entry: redfishLogEntry{Message: "The power input for power supply 1 is lost.", MessageID: "PSU0031"},
+		kind:  PowerEventBrownout,
+	}, {
+		name:  "undervoltage",
+		entry: redfishLogEntry{Message: "Input undervoltage detected on PSU 1", EntryCode: "Assert"},
+		kind:  PowerEventBrownout,
+	}, {
+		name:  "power cap",
+		entry: redfishLogEntry{Message: "The system performance degraded because power capacity has changed; power cap engaged."},
+		kind:  PowerEventPowerCap,
+	}, {
+		name:  "deasserted",
+		entry: redfishLogEntry{Message: "Power supply 2 failed.", EntryCode: "Deassert"},
+		kind:  "",
+	}, {
+		name:  "unrelated",
+		entry: redfishLogEntry{Message: "The chassis is closed while the power is off."},
+		kind:  "",
+	}}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.kind, powerEventKind(tc.entry))
+		})
+	}
+}
+
+// fakeEventsBMC serves the log services of the manager and the system of a
+// BMC, the system having no log service
+func fakeEventsBMC(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redfish/v1/Managers", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"Members": [{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1"}]}`)
+	})
+	mux.HandleFunc("/redfish/v1/Systems", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"Members": [{"@odata.id": "/redfish/v1/Systems/System.Embedded.1"}]}`)
+	})
+	mux.HandleFunc("/redfish/v1/Managers/iDRAC.Embedded.1/LogServices", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"Members": [{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel"}]}`)
+	})
+	mux.HandleFunc("/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"Members": [
+			{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/1", "Created": "2025-06-01T10:00:00-05:00",
+			 "Severity": "Critical", "Message": "Power supply 2 failed.", "MessageId": "PSU0003", "SensorType": "Power Supply / Converter"},
+			{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/2", "Created": "2025-06-01T10:05:00-05:00",
+			 "Severity": "OK", "Message": "The system inlet temperature is within range."},
+			{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/3", "Created": "",
+			 "Severity": "Warning", "Message": "Power cap engaged."}
+		]}`)
+	})
+	bmc := httptest.NewServer(mux)
+	t.Cleanup(bmc.Close)
+	return bmc
+}
+
+func TestRedfishZonePowerEvents(t *testing.T) {
+	bmc := fakeEventsBMC(t)
+	zone, err := NewRedfishZone("platform", bmc.URL)
+	require.NoError(t, err)
+
+	events, err := zone.PowerEvents()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel"}, zone.logServices,
+		"the system without log services is skipped")
+	require.Len(t, events, 2)
+	assert.Equal(t, PowerEvent{
+		ID:        "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/1",
+		Kind:      PowerEventPSUFailure,
+		Created:   time.Date(2025, 6, 1, 15, 0, 0, 0, time.UTC),
+		Severity:  "Critical",
+		MessageID: "PSU0003",
+		Message:   "Power supply 2 failed.",
+	}, PowerEvent{events[0].ID, events[0].Kind, events[0].Created.UTC(), events[0].Severity, events[0].MessageID, events[0].Message})
+	assert.Equal(t, PowerEventPowerCap, events[1].Kind)
+	assert.True(t, events[1].Created.IsZero(), "invalid times are ignored")
+
+	t.Run("log services set", func(t *testing.T) {
+		zone, err := NewRedfishZone("platform", bmc.URL,
+			WithRedfishLogServices("/redfish/v1/Systems/System.Embedded.1/LogServices/Missing"))
+		require.NoError(t, err)
+		_, err = zone.PowerEvents()
+		assert.ErrorIs(t, err, ErrUnsupportedHardware)
+	})
+
+	t.Run("no log services", func(t *testing.T) {
+		zone, err := NewRedfishZone("platform", fakeBMC(t).URL)
+		require.NoError(t, err)
+		_, err = zone.PowerEvents()
+		assert.ErrorIs(t, err, ErrUnsupportedHardware)
+	})
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redfishPowerHints describe how a BMC reports the power of its chassis, as
+// some don't follow the standard: e.g. they report the power in milliwatts in
+// an OEM field, or average it over a long interval
+type redfishPowerHints struct {
+	// field is the path of the power in the Power resource, e.g.
+	// [PowerControl 0 Oem Vendor PowerMilliwatts]; PowerConsumedWatts of the
+	// first power control if empty
+	field []string
+
+	scale    float64       // converts the reported power to watts
+	interval time.Duration // the reported power is averaged over; 0 if instantaneous
+}
+
+// WithRedfishPowerField sets the dot-separated path of the power in the Power
+// resource of the chassis, e.g. PowerControl.0.Oem.Vendor.PowerMilliwatts, for
+// BMCs reporting it in an OEM field; PowerControl.0.PowerConsumedWatts by default
+func WithRedfishPowerField(path string) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.hints.field = nil
+		if path != "" {
+			z.hints.field = strings.Split(path, ".")
+		}
+	}
+}
+
+// WithRedfishPowerScale sets the factor the reported power is multiplied by
+// to convert it to watts, e.g. 0.001 for milliwatts; 1 by default
+func WithRedfishPowerScale(scale float64) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.hints.scale = scale
+	}
+}
+
+// WithRedfishAveragingInterval sets the interval the BMC averages the reported
+// power over; 0, the default, if it reports the instantaneous power
+func WithRedfishAveragingInterval(d time.Duration) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.hints.interval = d
+	}
+}
+
+// lookupPower returns the number at the path of the power field in the Power
+// resource; ok is false if there is none
+func (h redfishPowerHints) lookupPower(resource json.RawMessage) (value float64, ok bool) {
+	var v any
+	if err := json.Unmarshal(resource, &v); err != nil {
+		return 0, false
+	}
+	for _, key := range h.field {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return 0, false
+			}
+			v = node[i]
+		default:
+			return 0, false
+		}
+	}
+	value, ok = v.(float64)
+	return value, ok
+}
+
+// normalize converts the reported power to watts and checks it is plausible:
+// not negative and, if the capacity of the chassis is known, not above it
+func (z *RedfishZone) normalize(reported float64, capacity *float64) (float64, error) {
+	watts := reported * z.hints.scale
+	switch {
+	case math.IsNaN(watts) || math.IsInf(watts, 0) || watts < 0:
+		return 0, Errorf(ErrTransient, "implausible power of %g W reported for chassis %s by the BMC at %s",
+			watts, z.chassis, z.endpoint)
+	case capacity != nil && *capacity > 0 && watts > *capacity:
+		return 0, Errorf(ErrTransient, "implausible power of %.1f W reported for chassis %s by the BMC at %s: above its capacity of %.1f W; check the scale of the power",
+			watts, z.chassis, z.endpoint, *capacity)
+	}
+	return watts, nil
+}
@@ -0,0 +1,389 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// redfishFetchTimeout is the timeout of requests to the BMC, which are often
+// slow to respond
+const redfishFetchTimeout = 5 * time.Second
+
+// RedfishZone implements EnergyZone for the whole platform of the node, i.e.
+// CPUs, memory, fans, disks and power supply losses, whose power is read from
+// the BMC of the node with Redfish. BMCs report power rather than energy, so
+// energy is the power integrated over time between reads. Zones of the same
+// BMC share its client, and so its session.
+type RedfishZone struct {
+	name               string
+	endpoint           string // URL of the BMC
+	chassis            string // ID of the chassis; discovered on the first read if empty
+	username           string
+	password           string
+	insecureSkipVerify bool
+	minInterval        time.Duration
+	jitter             time.Duration
+	hints              redfishPowerHints
+	logServices        []string // paths of the log services; discovered if empty
+	client             *redfishClient
+	clock              clock.Clock
+
+	mu     sync.Mutex
+	energy Energy    // integrated energy so far
+	power  float64   // watts of the previous read
+	read   time.Time // time of the previous read; zero if never read
+
+	saved      bool  // whether the power limit was saved
+	savedLimit Power // power limit saved; 0 if there was none
+}
+
+var (
+	_ EnergyZone     = (*RedfishZone)(nil)
+	_ EnergyRestorer = (*RedfishZone)(nil)
+	_ PowerLimiter   = (*RedfishZone)(nil)
+)
+
+// RedfishOptFn is a functional option for configuring the Redfish zone
+type RedfishOptFn func(*RedfishZone)
+
+// WithRedfishClock sets the clock the power of the BMC is integrated with
+func WithRedfishClock(c clock.Clock) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.clock = c
+	}
+}
+
+// WithRedfishChassis sets the ID of the chassis whose power is read, e.g.
+// System.Embedded.1; by default it is the first chassis of the BMC
+func WithRedfishChassis(id string) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.chassis = id
+	}
+}
+
+// WithRedfishCredentials sets the credentials used to log in to the BMC, or
+// sent with HTTP basic authentication if it doesn't support sessions
+func WithRedfishCredentials(username, password string) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.username, z.password = username, password
+	}
+}
+
+// WithRedfishInsecureSkipVerify skips the verification of the certificate of
+// the BMC, which is often self-signed
+func WithRedfishInsecureSkipVerify(skip bool) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.insecureSkipVerify = skip
+	}
+}
+
+// WithRedfishMinInterval sets the min interval between the requests to the
+// BMC; the power read last is used until it elapses
+func WithRedfishMinInterval(d time.Duration) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.minInterval = d
+	}
+}
+
+// WithRedfishJitter sets the max random delay of the first request to the BMC
+// and added to the min interval between requests
+func WithRedfishJitter(d time.Duration) RedfishOptFn {
+	return func(z *RedfishZone) {
+		z.jitter = d
+	}
+}
+
+// NewRedfishZone creates a zone of the given name whose power is read from the
+// BMC at endpoint, an http(s) URL such as https://10.0.0.2
+func NewRedfishZone(name, endpoint string, opts ...RedfishOptFn) (*RedfishZone, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid redfish endpoint %q; must be an http or https URL", endpoint)
+	}
+
+	z := &RedfishZone{
+		name:     name,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		hints:    redfishPowerHints{scale: 1},
+		clock:    clock.RealClock{},
+	}
+	for _, opt := range opts {
+		opt(z)
+	}
+	z.client = redfishClientFor(redfishClientKey{
+		endpoint:           z.endpoint,
+		username:           z.username,
+		password:           z.password,
+		insecureSkipVerify: z.insecureSkipVerify,
+	}, redfishLimits{
+		minInterval: z.minInterval,
+		jitter:      z.jitter,
+		clock:       z.clock,
+	})
+	return z, nil
+}
+
+// Name returns the zone name
+func (z *RedfishZone) Name() string {
+	return z.name
+}
+
+// Index returns the index of the zone
+func (z *RedfishZone) Index() int {
+	return 0
+}
+
+// Path returns the endpoint of the BMC
+func (z *RedfishZone) Path() string {
+	return z.endpoint
+}
+
+// Energy reads the power of the platform and returns the energy integrated
+// since the first read, using the average of the power of this read and of the
+// previous one, or the power of this read if the BMC already averages it. Until
+// the rate limits of the BMC allow another request, the power of the previous
+// read is used. Implausible readings are discarded, the energy of their
+// interval being integrated on the next read.
+func (z *RedfishZone) Energy() (Energy, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	power := z.power
+	switch {
+	case z.client.ready():
+		err := z.client.run(func() (err error) {
+			power, err = z.fetchPower()
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+	case z.read.IsZero():
+		return 0, Errorf(ErrTransient, "waiting to read the BMC at %s", z.endpoint)
+	}
+
+	now := z.clock.Now()
+	if !z.read.IsZero() {
+		avg := (z.power + power) / 2
+		if z.hints.interval > 0 {
+			// averaging it again with the previous read would delay it further
+			avg = power
+		}
+		z.energy += Energy(avg * now.Sub(z.read).Seconds() * float64(Joule))
+	}
+	z.power, z.read = power, now
+	return z.energy, nil
+}
+
+// RestoreEnergy sets the energy the zone integrates the power from, if it was
+// not read yet
+func (z *RedfishZone) RestoreEnergy(energy Energy) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	if z.read.IsZero() {
+		z.energy = energy
+	}
+}
+
+// MaxEnergy returns the maximum energy that can be accumulated; integrated
+// energy never wraps around in practice
+func (z *RedfishZone) MaxEnergy() Energy {
+	return Energy(math.MaxUint64)
+}
+
+// redfishPowerControl is the power control of a chassis, the first member of
+// its PowerControl array
+type redfishPowerControl struct {
+	PowerConsumedWatts *float64 `json:"PowerConsumedWatts,omitempty"`
+	PowerCapacityWatts *float64 `json:"PowerCapacityWatts,omitempty"`
+	PowerLimit         struct {
+		LimitInWatts *float64 `json:"LimitInWatts"`
+	} `json:"PowerLimit"`
+}
+
+// PowerLimit returns the power limit of the chassis set in the BMC; 0 if there
+// is none
+func (z *RedfishZone) PowerLimit() (Power, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	var control redfishPowerControl
+	err := z.client.run(func() (err error) {
+		control, err = z.fetchPowerControl()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if control.PowerLimit.LimitInWatts == nil {
+		return 0, nil
+	}
+	return Power(*control.PowerLimit.LimitInWatts) * Watt, nil
+}
+
+// PowerCapacity returns the power the chassis can draw, as provisioned for it
+// by the BMC, e.g. the rating of its power supplies; 0 if it is not reported
+func (z *RedfishZone) PowerCapacity() (Power, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	var control redfishPowerControl
+	err := z.client.run(func() (err error) {
+		control, err = z.fetchPowerControl()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if control.PowerCapacityWatts == nil {
+		return 0, nil
+	}
+	return Power(*control.PowerCapacityWatts) * Watt, nil
+}
+
+// SetPowerLimit sets the power limit of the chassis in the BMC; 0 removes it
+func (z *RedfishZone) SetPowerLimit(limit Power) error {
+	if limit < 0 {
+		return Errorf(ErrInvalidPowerLimit, "invalid Redfish power limit %s; must not be negative", limit)
+	}
+
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	control := redfishPowerControl{}
+	if limit > 0 {
+		watts := limit.Watts()
+		control.PowerLimit.LimitInWatts = &watts
+	}
+	body := map[string]any{"PowerControl": []redfishPowerControl{control}}
+	return z.client.run(func() error {
+		if err := z.findChassis(); err != nil {
+			return err
+		}
+		return z.do(http.MethodPatch, z.powerPath(), body, nil)
+	})
+}
+
+// SavePowerLimit saves the power limit of the chassis, or that there is none,
+// and returns it
+func (z *RedfishZone) SavePowerLimit() (Power, error) {
+	limit, err := z.PowerLimit()
+	if err != nil {
+		return 0, err
+	}
+
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.saved, z.savedLimit = true, limit
+	return limit, nil
+}
+
+// RestorePowerLimit restores the power limit of the chassis saved, removing
+// it if there was none
+func (z *RedfishZone) RestorePowerLimit() error {
+	z.mu.Lock()
+	saved, limit := z.saved, z.savedLimit
+	z.mu.Unlock()
+
+	if !saved {
+		return fmt.Errorf("no Redfish power limit saved")
+	}
+	return z.SetPowerLimit(limit)
+}
+
+// findChassis finds the chassis whose power is read if it is not known
+func (z *RedfishZone) findChassis() error {
+	if z.chassis != "" {
+		return nil
+	}
+	var chassis struct {
+		Members []struct {
+			ID string `json:"@odata.id"`
+		} `json:"Members"`
+	}
+	if err := z.get("/redfish/v1/Chassis", &chassis); err != nil {
+		return err
+	}
+	if len(chassis.Members) == 0 {
+		return Errorf(ErrUnsupportedHardware, "no chassis found on the BMC at %s", z.endpoint)
+	}
+	z.chassis = chassis.Members[0].ID[strings.LastIndex(chassis.Members[0].ID, "/")+1:]
+	return nil
+}
+
+// powerPath returns the path of the power resource of the chassis
+func (z *RedfishZone) powerPath() string {
+	return "/redfish/v1/Chassis/" + url.PathEscape(z.chassis) + "/Power"
+}
+
+// fetchPowerControl returns the power control of the whole chassis, finding
+// the chassis first if it is not known
+func (z *RedfishZone) fetchPowerControl() (redfishPowerControl, error) {
+	control, _, err := z.fetchPowerResource()
+	return control, err
+}
+
+// fetchPowerResource returns the power control of the whole chassis and the
+// Power resource it is part of, finding the chassis first if it is not known
+func (z *RedfishZone) fetchPowerResource() (redfishPowerControl, json.RawMessage, error) {
+	if err := z.findChassis(); err != nil {
+		return redfishPowerControl{}, nil, err
+	}
+
+	var resource json.RawMessage
+	if err := z.get(z.powerPath(), &resource); err != nil {
+		return redfishPowerControl{}, nil, err
+	}
+	var power struct {
+		PowerControl []redfishPowerControl `json:"PowerControl"`
+	}
+	if err := json.Unmarshal(resource, &power); err != nil {
+		return redfishPowerControl{}, nil, fmt.Errorf("failed to decode %s of the BMC: %w", z.powerPath(), err)
+	}
+	// the first power control is the power of the whole chassis
+	if len(power.PowerControl) == 0 {
+		return redfishPowerControl{}, nil, Errorf(ErrUnsupportedHardware, "no power reported for chassis %s by the BMC at %s", z.chassis, z.endpoint)
+	}
+	return power.PowerControl[0], resource, nil
+}
+
+// fetchPower returns the power consumed by the chassis in watts, read from the
+// power field and normalized
+func (z *RedfishZone) fetchPower() (float64, error) {
+	control, resource, err := z.fetchPowerResource()
+	if err != nil {
+		return 0, err
+	}
+	reported, ok := 0.0, control.PowerConsumedWatts != nil
+	if ok {
+		reported = *control.PowerConsumedWatts
+	}
+	if len(z.hints.field) > 0 {
+		reported, ok = z.hints.lookupPower(resource)
+	}
+	if !ok {
+		return 0, Errorf(ErrUnsupportedHardware, "no power reported for chassis %s by the BMC at %s", z.chassis, z.endpoint)
+	}
+	return z.normalize(reported, control.PowerCapacityWatts)
+}
+
+// get decodes the Redfish resource at path into v
+func (z *RedfishZone) get(path string, v any) error {
+	return z.do(http.MethodGet, path, nil, v)
+}
+
+// do sends a request to the BMC with the client shared by its zones
+func (z *RedfishZone) do(method, path string, body, v any) error {
+	return z.client.do(method, path, body, v)
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// fakeBMC serves the Redfish chassis of a BMC, whose power is 100 W on the
+// first read and increases by 100 W on every read
+func fakeBMC(t *testing.T) *httptest.Server {
+	t.Helper()
+	var reads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redfish/v1/Chassis", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"Members": [{"@odata.id": "/redfish/v1/Chassis/System.Embedded.1"}, {"@odata.id": "/redfish/v1/Chassis/Enclosure.Internal.0-1"}]}`)
+	})
+	mux.HandleFunc("/redfish/v1/Chassis/System.Embedded.1/Power", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "root" || password != "calvin" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"PowerControl": [{"PowerConsumedWatts": %d}, {"PowerConsumedWatts": 1}]}`, 100*reads.Add(1))
+	})
+	mux.HandleFunc("/redfish/v1/Chassis/Empty/Power", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"PowerControl": []}`)
+	})
+	bmc := httptest.NewServer(mux)
+	t.Cleanup(bmc.Close)
+	return bmc
+}
+
+func TestRedfishZone(t *testing.T) {
+	bmc := fakeBMC(t)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	zone, err := NewRedfishZone("platform", bmc.URL+"/", WithRedfishClock(fakeClock), WithRedfishCredentials("root", "calvin"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "platform", zone.Name())
+	assert.Equal(t, 0, zone.Index())
+	assert.Equal(t, bmc.URL, zone.Path())
+	assert.Greater(t, zone.MaxEnergy(), Energy(0))
+
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.Zero(t, energy)
+	assert.Equal(t, "System.Embedded.1", zone.chassis, "the first chassis")
+
+	// the average of 100 W and 200 W over 2s
+	fakeClock.Step(2 * time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 300.0, energy.Joules(), 0.001)
+
+	fakeClock.Step(time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 300.0+250, energy.Joules(), 0.001)
+}
+
+func TestRedfishZoneErrors(t *testing.T) {
+	bmc := fakeBMC(t)
+
+	_, err := NewRedfishZone("platform", "bmc:443")
+	assert.ErrorContains(t, err, "invalid redfish endpoint")
+
+	zone, err := NewRedfishZone("platform", bmc.URL)
+	require.NoError(t, err)
+	_, err = zone.Energy()
+	assert.ErrorIs(t, err, ErrPermission)
+
+	zone, err = NewRedfishZone("platform", bmc.URL, WithRedfishChassis("Missing"))
+	require.NoError(t, err)
+	_, err = zone.Energy()
+	assert.ErrorIs(t, err, ErrUnsupportedHardware)
+
+	zone, err = NewRedfishZone("platform", bmc.URL, WithRedfishChassis("Empty"))
+	require.NoError(t, err)
+	_, err = zone.Energy()
+	assert.ErrorContains(t, err, "no power reported for chassis Empty")
+
+	zone, err = NewRedfishZone("platform", "http://127.0.0.1:0")
+	require.NoError(t, err)
+	_, err = zone.Energy()
+	assert.ErrorIs(t, err, ErrTransient)
+}
+
+func TestRedfishZonePowerHints(t *testing.T) {
+	// an OEM field in milliwatts, averaged by the BMC
+	milliwatts := []int{100_000, 200_000, -5_000, 900_000, 300_000}
+	var reads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redfish/v1/Chassis/1/Power", func(w http.ResponseWriter, r *http.Request) {
+		mw := milliwatts[int(reads.Add(1)-1)%len(milliwatts)]
+		_, _ = fmt.Fprintf(w, `{"PowerControl": [{"PowerConsumedWatts": 1, "PowerCapacityWatts": 750, "Oem": {"Vendor": {"PowerMilliwatts": %d}}}]}`, mw)
+	})
+	bmc := httptest.NewServer(mux)
+	t.Cleanup(bmc.Close)
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	zone, err := NewRedfishZone("platform", bmc.URL,
+		WithRedfishClock(fakeClock),
+		WithRedfishChassis("1"),
+		WithRedfishPowerField("PowerControl.0.Oem.Vendor.PowerMilliwatts"),
+		WithRedfishPowerScale(0.001),
+		WithRedfishAveragingInterval(time.Minute))
+	require.NoError(t, err)
+
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.Zero(t, energy)
+
+	// 200 W over 2s, as the BMC already averages the power
+	fakeClock.Step(2 * time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 400.0, energy.Joules(), 0.001)
+
+	fakeClock.Step(time.Second)
+	_, err = zone.Energy()
+	assert.ErrorIs(t, err, ErrTransient, "negative power")
+	assert.ErrorContains(t, err, "implausible power of -5 W")
+
+	fakeClock.Step(time.Second)
+	_, err = zone.Energy()
+	assert.ErrorIs(t, err, ErrTransient, "power above the capacity")
+	assert.ErrorContains(t, err, "above its capacity of 750.0 W")
+
+	// the intervals of the discarded readings are integrated with the next one
+	fakeClock.Step(time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 400.0+3*300, energy.Joules(), 0.001)
+
+	t.Run("missing field", func(t *testing.T) {
+		zone, err := NewRedfishZone("platform", bmc.URL,
+			WithRedfishChassis("1"),
+			WithRedfishPowerField("PowerControl.1.PowerConsumedWatts"))
+		require.NoError(t, err)
+		_, err = zone.Energy()
+		assert.ErrorIs(t, err, ErrUnsupportedHardware)
+	})
+}
@@ -0,0 +1,316 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// NOTE: This replay meter is not intended to be used in production and is for
+// development only
+
+// replayMaxEnergy is the energy at which replayed zones wrap around; the
+// max_energy_range_uj of the package zone of most Intel CPUs
+const replayMaxEnergy = Energy(262143328850)
+
+// replayRecording is the cumulative energy of zones recorded over time
+type replayRecording struct {
+	offsets  []time.Duration // since the first sample; strictly increasing
+	energies [][]Energy      // per zone, cumulative since the first sample
+}
+
+// duration returns the time between the first and last sample
+func (r *replayRecording) duration() time.Duration {
+	return r.offsets[len(r.offsets)-1]
+}
+
+// energyAt returns the energy of zone at offset into the recording (which
+// must be within its duration), interpolated linearly between samples
+func (r *replayRecording) energyAt(zone int, offset time.Duration) Energy {
+	energies := r.energies[zone]
+	i := sort.Search(len(r.offsets), func(i int) bool { return r.offsets[i] >= offset })
+	if i == 0 {
+		return energies[0]
+	}
+	if i == len(r.offsets) {
+		return energies[len(energies)-1]
+	}
+
+	prev, next := r.offsets[i-1], r.offsets[i]
+	ratio := float64(offset-prev) / float64(next-prev)
+	return energies[i-1] + Energy(ratio*float64(energies[i]-energies[i-1]))
+}
+
+// replayEnergyZone implements the EnergyZone interface by replaying the
+// energy of a zone from a recording
+type replayEnergyZone struct {
+	name   string
+	index  int
+	path   string
+	column int
+
+	meter *replayRaplMeter
+}
+
+var _ EnergyZone = (*replayEnergyZone)(nil)
+
+// Name returns the zone name
+func (z *replayEnergyZone) Name() string {
+	return z.name
+}
+
+// Index returns the index of the zone
+func (z *replayEnergyZone) Index() int {
+	return z.index
+}
+
+// Path returns the recording the energy is replayed from
+func (z *replayEnergyZone) Path() string {
+	return z.path
+}
+
+// Energy returns the energy of the zone recorded at the time elapsed since the
+// first read of the meter. The recording is looped, continuing from the energy
+// reached at the end of the previous loop.
+func (z *replayEnergyZone) Energy() (Energy, error) {
+	return z.meter.energy(z.column), nil
+}
+
+// MaxEnergy returns the maximum value of energy usage that can be read.
+func (z *replayEnergyZone) MaxEnergy() Energy {
+	return replayMaxEnergy
+}
+
+// replayRaplMeter implements the CPUPowerMeter interface by replaying RAPL
+// readings recorded on another machine
+type replayRaplMeter struct {
+	logger    *slog.Logger
+	clock     clock.PassiveClock
+	path      string
+	recording *replayRecording
+	zones     []EnergyZone
+
+	startOnce sync.Once
+	start     time.Time
+}
+
+var _ CPUPowerMeter = (*replayRaplMeter)(nil)
+
+// ReplayOptFn is a functional option for configuring the replay meter
+type ReplayOptFn func(*replayRaplMeter)
+
+// WithReplayLogger sets the logger of the replay meter
+func WithReplayLogger(l *slog.Logger) ReplayOptFn {
+	return func(m *replayRaplMeter) {
+		m.logger = l.With("meter", m.Name())
+	}
+}
+
+// WithReplayClock sets the clock the recording is replayed by
+func WithReplayClock(c clock.PassiveClock) ReplayOptFn {
+	return func(m *replayRaplMeter) {
+		m.clock = c
+	}
+}
+
+// NewReplayCPUMeter creates a CPU power meter that replays the energy of zones
+// recorded in a file, so that the full stack can be run on machines without
+// (privileged access to) RAPL. The file is a CSV of samples of the energy
+// counters of zones:
+//
+//	# lines starting with # are ignored
+//	seconds,package,dram
+//	1718000000.0,123456789,23456789
+//	1718000001.0,123501234,23467890
+//
+// The first column is the time of the sample in seconds; the rest are the
+// energy_uj readings of a zone in microjoules. A reading lower than the
+// previous one is treated as the counter wrapping around. Only the given zones
+// are replayed; empty replays all zones of the recording.
+func NewReplayCPUMeter(path string, zones []string, opts ...ReplayOptFn) (CPUPowerMeter, error) {
+	names, recording, err := readReplayRecording(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay file %s: %w", path, err)
+	}
+
+	meter := &replayRaplMeter{
+		logger:    slog.Default().With("meter", "replay-cpu-meter"),
+		clock:     clock.RealClock{},
+		path:      path,
+		recording: recording,
+	}
+
+	columns := make([]int, 0, len(names))
+	if len(zones) == 0 {
+		for i := range names {
+			columns = append(columns, i)
+		}
+	}
+	for _, zone := range zones {
+		i := slices.IndexFunc(names, func(name string) bool { return strings.EqualFold(name, zone) })
+		if i < 0 {
+			return nil, fmt.Errorf("zone %q not found in replay file %s; recorded zones: %v", zone, path, names)
+		}
+		columns = append(columns, i)
+	}
+
+	meter.zones = make([]EnergyZone, 0, len(columns))
+	for index, column := range columns {
+		meter.zones = append(meter.zones, &replayEnergyZone{
+			name:   names[column],
+			index:  index,
+			path:   path,
+			column: column,
+			meter:  meter,
+		})
+	}
+
+	for _, opt := range opts {
+		opt(meter)
+	}
+
+	meter.logger.Info("Replaying recorded RAPL readings",
+		"file", path, "zones", len(meter.zones),
+		"samples", len(recording.offsets), "duration", recording.duration())
+
+	return meter, nil
+}
+
+func (m *replayRaplMeter) Name() string {
+	return "replay-cpu-meter"
+}
+
+func (m *replayRaplMeter) Zones() ([]EnergyZone, error) {
+	return m.zones, nil
+}
+
+// PrimaryEnergyZone returns the zone with the highest energy coverage/priority
+func (m *replayRaplMeter) PrimaryEnergyZone() (EnergyZone, error) {
+	if len(m.zones) == 0 {
+		return nil, fmt.Errorf("no zones available in replay meter")
+	}
+
+	for _, p := range []string{"psys", "package", "core", "dram", "uncore"} {
+		for _, zone := range m.zones {
+			if strings.EqualFold(zone.Name(), p) {
+				return zone, nil
+			}
+		}
+	}
+	return m.zones[0], nil
+}
+
+// energy returns the energy of the zone recorded in column at the time elapsed
+// since the first read of any zone
+func (m *replayRaplMeter) energy(column int) Energy {
+	m.startOnce.Do(func() { m.start = m.clock.Now() })
+
+	elapsed := m.clock.Since(m.start)
+	duration := m.recording.duration()
+	loops := elapsed / duration
+	offset := elapsed - loops*duration
+
+	energies := m.recording.energies[column]
+	total := energies[len(energies)-1]
+	energy := Energy(uint64(loops))*total + m.recording.energyAt(column, offset)
+	return energy % replayMaxEnergy
+}
+
+// readReplayRecording reads the names of the zones and their cumulative
+// energy from a replay file
+func readReplayRecording(path string) ([]string, *replayRecording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var names []string
+	var times []float64
+	var readings [][]uint64
+
+	scanner := bufio.NewScanner(f)
+	for line := 0; scanner.Scan(); {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		if names == nil {
+			if len(fields) < 2 {
+				return nil, nil, fmt.Errorf("line %d: header must have a time and at least one zone column", line)
+			}
+			names = fields[1:]
+			readings = make([][]uint64, len(names))
+			continue
+		}
+
+		if len(fields) != len(names)+1 {
+			return nil, nil, fmt.Errorf("line %d: expected %d columns, got %d", line, len(names)+1, len(fields))
+		}
+		t, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil || math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, nil, fmt.Errorf("line %d: invalid time %q", line, fields[0])
+		}
+		if n := len(times); n > 0 && t <= times[n-1] {
+			return nil, nil, fmt.Errorf("line %d: time %v must be after the previous sample %v", line, t, times[n-1])
+		}
+		times = append(times, t)
+
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: invalid energy %q of zone %s", line, field, names[i])
+			}
+			readings[i] = append(readings[i], v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(times) < 2 {
+		return nil, nil, fmt.Errorf("at least 2 samples are required, got %d", len(times))
+	}
+
+	recording := &replayRecording{
+		offsets:  make([]time.Duration, len(times)),
+		energies: make([][]Energy, len(names)),
+	}
+	for i, t := range times {
+		recording.offsets[i] = time.Duration((t - times[0]) * float64(time.Second))
+	}
+	if recording.duration() <= 0 {
+		return nil, nil, fmt.Errorf("samples must span at least a nanosecond")
+	}
+	for zone, values := range readings {
+		energies := make([]Energy, len(values))
+		for i := 1; i < len(values); i++ {
+			delta := values[i] - values[i-1]
+			if values[i] < values[i-1] {
+				// wrapped around; the energy up to the max range is unknown
+				delta = values[i]
+			}
+			energies[i] = energies[i-1] + Energy(delta)
+		}
+		recording.energies[zone] = energies
+	}
+	return names, recording, nil
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func writeReplayFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rapl.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestReplayCPUMeter(t *testing.T) {
+	path := writeReplayFile(t, `
+# recorded on a laptop
+seconds,package,core,psys
+100.0, 1000000, 500000, 3000000
+101.0, 3000000, 1500000, 8000000
+103.0, 5000000, 2500000, 13000000
+`)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter, err := NewReplayCPUMeter(path, nil, WithReplayClock(fakeClock))
+	require.NoError(t, err)
+	assert.Equal(t, "replay-cpu-meter", meter.Name())
+
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 3)
+	assert.Equal(t, "package", zones[0].Name())
+	assert.Equal(t, path, zones[0].Path())
+	assert.Equal(t, 2, zones[2].Index())
+
+	primary, err := meter.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, "psys", primary.Name())
+
+	pkg := zones[0]
+	tt := []struct {
+		step   time.Duration
+		energy Energy
+	}{
+		{0, 0},
+		{500 * time.Millisecond, 1000000},
+		{500 * time.Millisecond, 2000000},
+		{time.Second, 3000000},
+		{time.Second, 4000000},
+		// loops, continuing from the end of the recording
+		{time.Second, 4000000 + 2000000},
+		{3 * time.Second, 4000000*2 + 2000000},
+	}
+	for _, tc := range tt {
+		fakeClock.Step(tc.step)
+		energy, err := pkg.Energy()
+		require.NoError(t, err)
+		assert.Equal(t, tc.energy, energy)
+	}
+}
+
+func TestReplayCPUMeter_Zones(t *testing.T) {
+	path := writeReplayFile(t, "seconds,package,dram\n0,10,5\n1,20,10\n")
+
+	meter, err := NewReplayCPUMeter(path, []string{"DRAM"})
+	require.NoError(t, err)
+	zones, err := meter.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.Equal(t, "dram", zones[0].Name())
+	assert.Equal(t, 0, zones[0].Index())
+
+	_, err = NewReplayCPUMeter(path, []string{"uncore"})
+	assert.ErrorContains(t, err, `zone "uncore" not found`)
+}
+
+func TestReplayCPUMeter_WrapAround(t *testing.T) {
+	// the counter wrapped between the last two samples
+	path := writeReplayFile(t, "seconds,package\n0,900\n1,1000\n2,50\n")
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	meter, err := NewReplayCPUMeter(path, nil, WithReplayClock(fakeClock))
+	require.NoError(t, err)
+	zones, _ := meter.Zones()
+
+	_, _ = zones[0].Energy()
+	fakeClock.Step(2 * time.Second)
+	energy, err := zones[0].Energy()
+	require.NoError(t, err)
+	assert.Equal(t, Energy(150), energy)
+}
+
+func TestReplayCPUMeter_InvalidFiles(t *testing.T) {
+	tt := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"no zones", "seconds\n0\n1\n", "at least one zone"},
+		{"one sample", "seconds,package\n0,10\n", "at least 2 samples"},
+		{"columns", "seconds,package\n0,10\n1,20,30\n", "expected 2 columns"},
+		{"time", "seconds,package\n0,10\nnow,20\n", `invalid time "now"`},
+		{"time order", "seconds,package\n1,10\n1,20\n", "must be after the previous sample"},
+		{"energy", "seconds,package\n0,10\n1,-20\n", `invalid energy "-20" of zone package`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewReplayCPUMeter(writeReplayFile(t, tc.content), nil)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	_, err := NewReplayCPUMeter(filepath.Join(t.TempDir(), "missing.csv"), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// Types of smart plugs the wall power of a node can be read from
+const (
+	SmartPlugTasmota    = "tasmota"     // Tasmota firmware, HTTP API
+	SmartPlugShelly     = "shelly"      // Shelly Gen1, HTTP API
+	SmartPlugShellyGen2 = "shelly-gen2" // Shelly Gen2 and later (Plus, Pro), RPC over HTTP
+	SmartPlugKasa       = "kasa"        // TP-Link Kasa, local protocol over TCP
+)
+
+const (
+	smartPlugTimeout = 5 * time.Second
+	kasaDefaultPort  = "9999"
+	kasaMaxResponse  = 64 << 10 // bytes
+)
+
+// smartPlugReaders read the power of a plug at an address
+var smartPlugReaders = map[string]func(p *smartPlug) (Power, error){
+	SmartPlugTasmota:    (*smartPlug).readTasmota,
+	SmartPlugShelly:     (*smartPlug).readShelly,
+	SmartPlugShellyGen2: (*smartPlug).readShellyGen2,
+	SmartPlugKasa:       (*smartPlug).readKasa,
+}
+
+// smartPlug reads the power drawn through a smart plug with a power meter
+type smartPlug struct {
+	address string // base URL of HTTP plugs; host[:port] of Kasa plugs
+	client  *http.Client
+
+	username, password string // empty if authentication is disabled
+}
+
+// SmartPlugOptFn sets an option of a smart plug
+type SmartPlugOptFn func(*smartPlug)
+
+// WithSmartPlugCredentials sets the credentials of Tasmota and Shelly Gen1
+// plugs with authentication enabled
+func WithSmartPlugCredentials(username, password string) SmartPlugOptFn {
+	return func(p *smartPlug) {
+		p.username = username
+		p.password = password
+	}
+}
+
+// NewSmartPlugZone creates a zone of the given name whose power is the wall
+// power read from a smart plug of plugType at address: the base URL of the
+// HTTP API of the plug, e.g. http://192.168.1.20, or the host of Kasa plugs,
+// e.g. 192.168.1.21
+func NewSmartPlugZone(name, plugType, address string, c clock.PassiveClock, opts ...SmartPlugOptFn) (*PowerZone, error) {
+	read, ok := smartPlugReaders[plugType]
+	if !ok {
+		return nil, fmt.Errorf("unknown smart plug type %q", plugType)
+	}
+	p := &smartPlug{
+		address: strings.TrimSuffix(address, "/"),
+		client:  &http.Client{Timeout: smartPlugTimeout},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	// the credentials in the URL of the plug are not reported as its path
+	path := address
+	if u, err := url.Parse(address); err == nil && u.User != nil {
+		path = u.Redacted()
+	}
+	return NewPowerZone(name, 0, path, func() (Power, error) { return read(p) }, c), nil
+}
+
+// getJSON decodes the JSON response to a GET of path into v, authenticated
+// with HTTP basic authentication if credentials are set
+func (p *smartPlug) getJSON(path string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, p.address+path, nil)
+	if err != nil {
+		return err
+	}
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// the URL of the request holds the password of Tasmota plugs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = p.address
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("smart plug returned %s for %s", resp.Status, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// readTasmota reads the power of a Tasmota plug from its sensor status; the
+// power of plugs with several channels is reported per channel and summed
+func (p *smartPlug) readTasmota() (Power, error) {
+	var status struct {
+		StatusSNS struct {
+			Energy *struct {
+				Power json.RawMessage `json:"Power"`
+			} `json:"ENERGY"`
+		} `json:"StatusSNS"`
+	}
+	// Tasmota takes the credentials as parameters of the command
+	path := "/cm?cmnd=Status%208"
+	if p.username != "" {
+		path += "&user=" + url.QueryEscape(p.username) + "&password=" + url.QueryEscape(p.password)
+	}
+	if err := p.getJSON(path, &status); err != nil {
+		return 0, err
+	}
+	if status.StatusSNS.Energy == nil {
+		return 0, Errorf(ErrUnsupportedHardware, "tasmota plug %s has no energy monitoring", p.address)
+	}
+	var watts float64
+	if err := json.Unmarshal(status.StatusSNS.Energy.Power, &watts); err != nil {
+		var channels []float64
+		if err := json.Unmarshal(status.StatusSNS.Energy.Power, &channels); err != nil {
+			return 0, fmt.Errorf("invalid power of tasmota plug: %s", status.StatusSNS.Energy.Power)
+		}
+		watts = 0
+		for _, w := range channels {
+			watts += w
+		}
+	}
+	return Power(watts) * Watt, nil
+}
+
+// readShelly reads the power of a Shelly Gen1 plug, the sum of its meters
+func (p *smartPlug) readShelly() (Power, error) {
+	var status struct {
+		Meters []struct {
+			Power float64 `json:"power"`
+		} `json:"meters"`
+	}
+	if err := p.getJSON("/status", &status); err != nil {
+		return 0, err
+	}
+	if len(status.Meters) == 0 {
+		return 0, Errorf(ErrUnsupportedHardware, "shelly plug %s has no power meter", p.address)
+	}
+	var watts float64
+	for _, m := range status.Meters {
+		watts += m.Power
+	}
+	return Power(watts) * Watt, nil
+}
+
+// readShellyGen2 reads the active power of the first switch of a Shelly Gen2
+// plug
+func (p *smartPlug) readShellyGen2() (Power, error) {
+	var status struct {
+		APower *float64 `json:"apower"`
+	}
+	if err := p.getJSON("/rpc/Switch.GetStatus?id=0", &status); err != nil {
+		return 0, err
+	}
+	if status.APower == nil {
+		return 0, Errorf(ErrUnsupportedHardware, "shelly plug %s has no power meter", p.address)
+	}
+	return Power(*status.APower) * Watt, nil
+}
+
+// readKasa reads the realtime power of a TP-Link Kasa plug, e.g. HS110 or
+// KP115, with its local protocol
+func (p *smartPlug) readKasa() (Power, error) {
+	address := p.address
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, kasaDefaultPort)
+	}
+	conn, err := net.DialTimeout("tcp", address, smartPlugTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.SetDeadline(time.Now().Add(smartPlugTimeout)); err != nil {
+		return 0, err
+	}
+
+	if _, err := conn.Write(kasaEncrypt([]byte(`{"emeter":{"get_realtime":{}}}`))); err != nil {
+		return 0, err
+	}
+	var size [4]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return 0, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > kasaMaxResponse {
+		return 0, fmt.Errorf("response of kasa plug %s is too large: %d bytes", p.address, n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return 0, err
+	}
+
+	var resp struct {
+		Emeter *struct {
+			ErrCode  int `json:"err_code"` // set if the plug has no energy meter
+			Realtime struct {
+				ErrCode int      `json:"err_code"`
+				PowerMW *float64 `json:"power_mw"` // newer firmware
+				Power   *float64 `json:"power"`    // older firmware, in watts
+			} `json:"get_realtime"`
+		} `json:"emeter"`
+	}
+	if err := json.Unmarshal(kasaDecrypt(payload), &resp); err != nil {
+		return 0, fmt.Errorf("invalid response of kasa plug: %w", err)
+	}
+	switch rt := resp.Emeter; {
+	case rt == nil || rt.ErrCode != 0 || rt.Realtime.ErrCode != 0:
+		return 0, Errorf(ErrUnsupportedHardware, "kasa plug %s has no energy meter", p.address)
+	case rt.Realtime.PowerMW != nil:
+		return Power(*rt.Realtime.PowerMW) * MilliWatt, nil
+	case rt.Realtime.Power != nil:
+		return Power(*rt.Realtime.Power) * Watt, nil
+	default:
+		return 0, fmt.Errorf("kasa plug %s reported no power", p.address)
+	}
+}
+
+// kasaEncrypt encrypts a request with the autokey cipher of the Kasa protocol,
+// prefixed with its length
+func kasaEncrypt(plain []byte) []byte {
+	out := make([]byte, 4+len(plain))
+	binary.BigEndian.PutUint32(out, uint32(len(plain)))
+	key := byte(171)
+	for i, b := range plain {
+		key ^= b
+		out[4+i] = key
+	}
+	return out
+}
+
+// kasaDecrypt decrypts a response of the Kasa protocol, without its length
+func kasaDecrypt(cipher []byte) []byte {
+	out := make([]byte, len(cipher))
+	key := byte(171)
+	for i, b := range cipher {
+		out[i] = key ^ b
+		key = b
+	}
+	return out
+}
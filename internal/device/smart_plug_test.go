@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// plugServer serves body at path like an HTTP smart plug
+func plugServer(t *testing.T, path, body string) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RequestURI() != path {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// kasaServer answers one request with the encrypted response like a Kasa plug
+func kasaServer(t *testing.T, response string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		request := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, request); err != nil {
+			return
+		}
+		if string(kasaDecrypt(request)) == `{"emeter":{"get_realtime":{}}}` {
+			_, _ = conn.Write(kasaEncrypt([]byte(response)))
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestSmartPlugZone(t *testing.T) {
+	tt := []struct {
+		name     string
+		plugType string
+		address  string
+		watts    float64
+		err      string
+	}{{
+		name:     "tasmota",
+		plugType: SmartPlugTasmota,
+		address:  plugServer(t, "/cm?cmnd=Status%208", `{"StatusSNS":{"Time":"2025-06-01T12:00:00","ENERGY":{"Total":1.2,"Power":42,"Voltage":230}}}`),
+		watts:    42,
+	}, {
+		name:     "tasmota channels",
+		plugType: SmartPlugTasmota,
+		address:  plugServer(t, "/cm?cmnd=Status%208", `{"StatusSNS":{"ENERGY":{"Power":[40,2.5]}}}`),
+		watts:    42.5,
+	}, {
+		name:     "tasmota without energy monitoring",
+		plugType: SmartPlugTasmota,
+		address:  plugServer(t, "/cm?cmnd=Status%208", `{"StatusSNS":{"Time":"2025-06-01T12:00:00"}}`),
+		err:      "no energy monitoring",
+	}, {
+		name:     "shelly",
+		plugType: SmartPlugShelly,
+		address:  plugServer(t, "/status", `{"relays":[{"ison":true}],"meters":[{"power":61.3,"is_valid":true}]}`),
+		watts:    61.3,
+	}, {
+		name:     "shelly gen2",
+		plugType: SmartPlugShellyGen2,
+		address:  plugServer(t, "/rpc/Switch.GetStatus?id=0", `{"id":0,"output":true,"apower":75.2,"voltage":231.4}`) + "/",
+		watts:    75.2,
+	}, {
+		name:     "kasa",
+		plugType: SmartPlugKasa,
+		address:  kasaServer(t, `{"emeter":{"get_realtime":{"voltage_mv":230000,"power_mw":38500,"err_code":0}}}`),
+		watts:    38.5,
+	}, {
+		name:     "kasa older firmware",
+		plugType: SmartPlugKasa,
+		address:  kasaServer(t, `{"emeter":{"get_realtime":{"voltage":230.1,"power":12.5,"err_code":0}}}`),
+		watts:    12.5,
+	}, {
+		name:     "kasa without energy meter",
+		plugType: SmartPlugKasa,
+		address:  kasaServer(t, `{"emeter":{"err_code":-1,"err_msg":"module not support"}}`),
+		err:      "no energy meter",
+	}, {
+		name:     "not found",
+		plugType: SmartPlugShelly,
+		address:  plugServer(t, "/meter/0", `{}`),
+		err:      "404 Not Found",
+	}}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			zone, err := NewSmartPlugZone("platform", tc.plugType, tc.address, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.address, zone.Path())
+
+			power, err := zone.read()
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tc.watts, power.Watts(), 1e-9)
+		})
+	}
+
+	t.Run("credentials", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			q := r.URL.Query()
+			switch {
+			case r.URL.Path == "/status" && ok && user == "admin" && password == "secret":
+				_, _ = io.WriteString(w, `{"meters":[{"power":10}]}`)
+			case r.URL.Path == "/cm" && q.Get("user") == "admin" && q.Get("password") == "secret":
+				_, _ = io.WriteString(w, `{"StatusSNS":{"ENERGY":{"Power":20}}}`)
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+		t.Cleanup(srv.Close)
+
+		for plugType, watts := range map[string]float64{SmartPlugShelly: 10, SmartPlugTasmota: 20} {
+			zone, err := NewSmartPlugZone("platform", plugType, srv.URL, nil, WithSmartPlugCredentials("admin", "secret"))
+			require.NoError(t, err)
+			power, err := zone.read()
+			require.NoError(t, err, plugType)
+			assert.InDelta(t, watts, power.Watts(), 1e-9)
+		}
+
+		zone, err := NewSmartPlugZone("platform", SmartPlugTasmota, "http://127.0.0.1:1", nil, WithSmartPlugCredentials("admin", "secret"))
+		require.NoError(t, err)
+		_, err = zone.read()
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "secret", "the password is not logged")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := NewSmartPlugZone("platform", "zigbee", "http://plug", nil)
+		assert.ErrorContains(t, err, `unknown smart plug type "zigbee"`)
+	})
+}
+
+func TestSmartPlugZoneEnergy(t *testing.T) {
+	address := plugServer(t, "/rpc/Switch.GetStatus?id=0", `{"apower":100}`)
+	clock := testingclock.NewFakeClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	zone, err := NewSmartPlugZone("platform", SmartPlugShellyGen2, address, clock)
+	require.NoError(t, err)
+
+	energy, err := zone.Energy()
+	require.NoError(t, err)
+	assert.Equal(t, Energy(0), energy)
+
+	clock.Step(5 * time.Second)
+	energy, err = zone.Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, 500, energy.Joules(), 1e-9, "the wall power is integrated over time")
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SocketZone wraps the EnergyZone of a single CPU socket (package) so that
+// zones of different sockets are reported separately, e.g. package-0, dram-1,
+// instead of being aggregated.
+type SocketZone struct {
+	EnergyZone
+	socket int
+}
+
+var _ EnergyZone = (*SocketZone)(nil)
+
+// raplSocketPattern matches the socket id in RAPL paths, e.g. intel-rapl:1:0
+var raplSocketPattern = regexp.MustCompile(`-rapl:(\d+)`)
+
+// NewSocketZone creates a new SocketZone. The socket is derived from the RAPL
+// path of the zone and falls back to the zone index if the path has no socket id
+func NewSocketZone(zone EnergyZone) *SocketZone {
+	socket := zone.Index()
+	if m := raplSocketPattern.FindStringSubmatch(filepath.Base(zone.Path())); m != nil {
+		if id, err := strconv.Atoi(m[1]); err == nil {
+			socket = id
+		}
+	}
+	return &SocketZone{EnergyZone: zone, socket: socket}
+}
+
+// Name returns the zone name suffixed with the socket id
+func (z *SocketZone) Name() string {
+	return fmt.Sprintf("%s-%d", z.EnergyZone.Name(), z.socket)
+}
+
+// BaseName returns the name of the zone without the socket id
+func (z *SocketZone) BaseName() string {
+	return z.EnergyZone.Name()
+}
+
+// Socket returns the id of the socket the zone belongs to
+func (z *SocketZone) Socket() int {
+	return z.socket
+}
+
+// BaseZoneName returns the name of a zone without the socket id; i.e. zones
+// of the same type across sockets have the same base name
+func BaseZoneName(zone EnergyZone) string {
+	if sz, ok := zone.(*SocketZone); ok {
+		return sz.BaseName()
+	}
+	return zone.Name()
+}
+
+// CPUSockets reads the CPU topology from sysfs and returns the socket
+// (physical package) id of each CPU
+func CPUSockets(sysfsPath string) (map[int]int, error) {
+	pattern := filepath.Join(sysfsPath, "devices", "system", "cpu", "cpu[0-9]*", "topology", "physical_package_id")
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no cpu topology found in %s", sysfsPath)
+	}
+
+	sockets := make(map[int]int, len(files))
+	for _, f := range files {
+		cpuDir := filepath.Base(filepath.Dir(filepath.Dir(f)))
+		cpu, err := strconv.Atoi(strings.TrimPrefix(cpuDir, "cpu"))
+		if err != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read socket of %s: %w", cpuDir, err)
+		}
+		socket, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid socket id of %s: %w", cpuDir, err)
+		}
+		sockets[cpu] = socket
+	}
+	return sockets, nil
+}
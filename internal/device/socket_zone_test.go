@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocketZone(t *testing.T) {
+	tt := []struct {
+		zone     mockZone
+		name     string
+		baseName string
+		socket   int
+	}{
+		{mockZone{name: "package", index: 0, path: "/sys/class/powercap/intel-rapl:1"}, "package-1", "package", 1},
+		{mockZone{name: "dram", index: 0, path: "/sys/class/powercap/intel-rapl:1:0"}, "dram-1", "dram", 1},
+		{mockZone{name: "package", index: 0, path: "/sys/class/powercap/amd-rapl:0"}, "package-0", "package", 0},
+		{mockZone{name: "psys", index: 2, path: "/sys/class/powercap/other"}, "psys-2", "psys", 2},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			zone := NewSocketZone(tc.zone)
+			assert.Equal(t, tc.name, zone.Name())
+			assert.Equal(t, tc.baseName, zone.BaseName())
+			assert.Equal(t, tc.baseName, BaseZoneName(zone))
+			assert.Equal(t, tc.socket, zone.Socket())
+			assert.Equal(t, tc.zone.Path(), zone.Path())
+		})
+	}
+
+	assert.Equal(t, "core", BaseZoneName(mockZone{name: "core"}))
+}
+
+func TestCPUSockets(t *testing.T) {
+	sysfs := t.TempDir()
+	for cpu, socket := range []int{0, 0, 1, 1} {
+		dir := filepath.Join(sysfs, "devices", "system", "cpu", fmt.Sprintf("cpu%d", cpu), "topology")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "physical_package_id"), []byte(fmt.Sprintf("%d\n", socket)), 0o644))
+	}
+
+	sockets, err := CPUSockets(sysfs)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{0: 0, 1: 0, 2: 1, 3: 1}, sockets)
+
+	_, err = CPUSockets(t.TempDir())
+	assert.ErrorContains(t, err, "no cpu topology found")
+}
+
+type mockZone struct {
+	name      string
+	index     int
+	path      string
+	energy    Energy
+	maxEnergy Energy
+}
+
+func (m mockZone) Name() string            { return m.name }
+func (m mockZone) Index() int              { return m.index }
+func (m mockZone) Path() string            { return m.path }
+func (m mockZone) Energy() (Energy, error) { return m.energy, nil }
+func (m mockZone) MaxEnergy() Energy       { return m.maxEnergy }
+
+func TestCPUCoreTypes(t *testing.T) {
+	sysfs := t.TempDir()
+	for pmu, cpus := range map[string]string{"cpu_core": "0-3,8\n", "cpu_atom": "4-7\n"} {
+		dir := filepath.Join(sysfs, "devices", pmu)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cpus"), []byte(cpus), 0o644))
+	}
+
+	coreTypes, err := CPUCoreTypes(sysfs)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{
+		0: CoreTypePerformance, 1: CoreTypePerformance, 2: CoreTypePerformance, 3: CoreTypePerformance, 8: CoreTypePerformance,
+		4: CoreTypeEfficiency, 5: CoreTypeEfficiency, 6: CoreTypeEfficiency, 7: CoreTypeEfficiency,
+	}, coreTypes)
+
+	_, err = CPUCoreTypes(t.TempDir())
+	assert.ErrorContains(t, err, "no hybrid CPU found")
+
+	require.NoError(t, os.WriteFile(filepath.Join(sysfs, "devices", "cpu_atom", "cpus"), []byte("7-4\n"), 0o644))
+	_, err = CPUCoreTypes(sysfs)
+	assert.ErrorContains(t, err, `invalid CPU range "7-4"`)
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package event is an in-process bus of the events of Kepler, e.g. a snapshot
+// of the monitor being ready or a workload terminating. Services publish events
+// as they happen and other services, e.g. exporters and the MCP server,
+// subscribe to them rather than polling.
+package event
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// Kind is the kind of an event
+type Kind string
+
+// Kinds of events
+const (
+	// SnapshotReady is published when the monitor computed a new snapshot
+	SnapshotReady Kind = "snapshot-ready"
+
+	// ResourceTerminated is published when a container, VM or pod terminates;
+	// processes are not published since they come and go too often
+	ResourceTerminated Kind = "resource-terminated"
+
+	// ThresholdCrossed is published when a value crosses a threshold, e.g. an
+	// energy budget is exceeded or the power jumps abnormally
+	ThresholdCrossed Kind = "threshold-crossed"
+
+	// SourceDegraded is published when a source of the monitor, e.g. an energy
+	// zone, becomes unavailable
+	SourceDegraded Kind = "source-degraded"
+)
+
+// Event is something that happened in Kepler
+type Event struct {
+	Kind Kind      `json:"kind"`
+	Time time.Time `json:"time"`
+
+	// Subject is what the event is about, e.g. container/<id>, zone/package-0
+	// or budget/namespace/<name>; empty for snapshots
+	Subject string `json:"subject,omitempty"`
+
+	Message string            `json:"message,omitempty"`
+	Attrs   map[string]string `json:"attrs,omitempty"` // details of the event, e.g. the name of a container
+}
+
+type Opts struct {
+	logger *slog.Logger
+	clock  clock.PassiveClock
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger: slog.Default(),
+		clock:  clock.RealClock{},
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Bus
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock the time of events is set with
+func WithClock(c clock.PassiveClock) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// Bus delivers the events published to the subscriptions of their kind.
+// Publishing never blocks: an event is dropped for a subscription whose buffer
+// is full, so a slow subscriber doesn't hold up the monitor.
+type Bus struct {
+	logger *slog.Logger
+	clock  clock.PassiveClock
+
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// NewBus creates a new Bus without subscriptions
+func NewBus(applyOpts ...OptionFn) *Bus {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Bus{
+		logger: opts.logger.With("service", "event"),
+		clock:  opts.clock,
+		subs:   map[*Subscription]struct{}{},
+	}
+}
+
+// Publish delivers e to the subscriptions of its kind; the time of e is set to
+// now if it is zero
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = b.clock.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for s := range b.subs {
+		if !s.wants(e.Kind) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			// only the first drop is logged, as a slow subscriber drops many
+			if s.dropped.Add(1) == 1 {
+				b.logger.Warn("Subscriber is too slow; dropping events",
+					"subscriber", s.name, "kind", e.Kind)
+			}
+		}
+	}
+}
+
+// Subscribe returns a subscription named name to the events of kinds, or of
+// all kinds if none is given, buffering up to size events
+func (b *Bus) Subscribe(name string, size int, kinds ...Kind) *Subscription {
+	s := &Subscription{
+		bus:   b,
+		name:  name,
+		kinds: slices.Clone(kinds),
+		ch:    make(chan Event, max(size, 1)),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[s] = struct{}{}
+	b.logger.Debug("Subscribed to events", "subscriber", name, "kinds", kinds)
+	return s
+}
+
+// Subscription receives the events of some kinds published on a Bus
+type Subscription struct {
+	bus     *Bus
+	name    string
+	kinds   []Kind // all if empty
+	ch      chan Event
+	dropped atomic.Uint64
+
+	closeOnce sync.Once
+}
+
+func (s *Subscription) wants(kind Kind) bool {
+	return len(s.kinds) == 0 || slices.Contains(s.kinds, kind)
+}
+
+// C returns the channel the events are received on; it is closed by Close
+func (s *Subscription) C() <-chan Event {
+	return s.ch
+}
+
+// Dropped returns the number of events dropped since the buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops the delivery of events and closes the channel of s
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		b := s.bus
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, s)
+		close(s.ch)
+	})
+}
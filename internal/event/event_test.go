@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestBus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	bus := NewBus(WithClock(testingclock.NewFakeClock(now)))
+
+	all := bus.Subscribe("all", 10)
+	snapshots := bus.Subscribe("snapshots", 1, SnapshotReady)
+
+	bus.Publish(Event{Kind: SnapshotReady})
+	bus.Publish(Event{Kind: ResourceTerminated, Subject: "container/abc", Time: now.Add(-time.Second)})
+	bus.Publish(Event{Kind: SnapshotReady})
+
+	assert.Len(t, all.C(), 3)
+	assert.Equal(t, Event{Kind: SnapshotReady, Time: now}, <-all.C(), "the time is set if zero")
+	assert.Equal(t, now.Add(-time.Second), (<-all.C()).Time)
+	assert.Zero(t, all.Dropped())
+
+	assert.Len(t, snapshots.C(), 1, "only the events of the kinds subscribed to are received")
+	assert.Equal(t, uint64(1), snapshots.Dropped(), "events are dropped once the buffer is full")
+
+	snapshots.Close()
+	snapshots.Close()
+	_, ok := <-snapshots.C()
+	assert.True(t, ok, "buffered events are still received")
+	_, ok = <-snapshots.C()
+	assert.False(t, ok, "the channel is closed")
+	bus.Publish(Event{Kind: SnapshotReady})
+	assert.Len(t, all.C(), 2)
+	all.Close()
+}
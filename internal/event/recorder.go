@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+// ToolName is the name of the MCP tool returning the recent events
+const ToolName = "get_events"
+
+const (
+	// maxEvents is the number of most recent events kept by a Recorder
+	maxEvents = 100
+
+	// bufferSize is the number of events buffered by the subscription of a
+	// Recorder
+	bufferSize = 64
+)
+
+type ToolRegistry = server.ToolRegistry
+
+// recordedKinds are the kinds of events recorded; snapshots are ready too often
+// to be worth recording
+var recordedKinds = []Kind{ResourceTerminated, ThresholdCrossed, SourceDegraded}
+
+// Events is the result of the events tool
+type Events struct {
+	Events []Event `json:"events"` // oldest first
+}
+
+// Recorder subscribes to the events of a Bus, other than snapshots being ready,
+// and serves the most recent ones as an MCP tool
+type Recorder struct {
+	logger *slog.Logger
+	clock  clock.PassiveClock
+	bus    *Bus
+	tools  ToolRegistry
+	sub    *Subscription
+
+	mu     sync.Mutex
+	events []Event // most recent, oldest first
+}
+
+var (
+	_ service.Initializer = (*Recorder)(nil)
+	_ service.Runner      = (*Recorder)(nil)
+	_ service.Dependent   = (*Recorder)(nil)
+)
+
+// NewRecorder creates a new Recorder of the events of bus that serves them
+// using tools
+func NewRecorder(bus *Bus, tools ToolRegistry, applyOpts ...OptionFn) *Recorder {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Recorder{
+		logger: opts.logger.With("service", "event-recorder"),
+		clock:  opts.clock,
+		bus:    bus,
+		tools:  tools,
+	}
+}
+
+func (r *Recorder) Name() string {
+	return "event-recorder"
+}
+
+// Dependencies returns the MCP server the events are served by
+func (r *Recorder) Dependencies() []service.Service {
+	if s, ok := r.tools.(service.Service); ok {
+		return []service.Service{s}
+	}
+	return nil
+}
+
+// Init subscribes to the bus, so that no event is missed before Run, and
+// registers the tool
+func (r *Recorder) Init() error {
+	r.sub = r.bus.Subscribe(r.Name(), bufferSize, recordedKinds...)
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"since": map[string]any{
+				"type":        "string",
+				"description": "Only return the events of the last duration, e.g. 30m or 2h",
+			},
+			"kind": map[string]any{
+				"type":        "string",
+				"enum":        recordedKinds,
+				"description": "Only return the events of the kind",
+			},
+		},
+	}
+	return r.tools.RegisterTool(ToolName,
+		"Recent events of Kepler: containers, VMs and pods terminated, energy budgets exceeded and abnormal power jumps, "+
+			"and energy zones or other sources that became unavailable",
+		schema, r.callTool)
+}
+
+// Run records the events until ctx is cancelled
+func (r *Recorder) Run(ctx context.Context) error {
+	defer r.sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-r.sub.C():
+			r.record(e)
+		}
+	}
+}
+
+// record keeps e among the most recent events
+func (r *Recorder) record(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, e)
+	if n := len(r.events); n > maxEvents {
+		r.events = slices.Clone(r.events[n-maxEvents:])
+	}
+}
+
+// Events returns the recent events of kind at or after since, oldest first;
+// all kinds if kind is empty and all recent events if since is zero
+func (r *Recorder) Events(since time.Time, kind Kind) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ret := []Event{}
+	for _, e := range r.events {
+		if (since.IsZero() || !e.Time.Before(since)) && (kind == "" || e.Kind == kind) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+func (r *Recorder) callTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		Since string `json:"since"`
+		Kind  Kind   `json:"kind"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	var since time.Time
+	if params.Since != "" {
+		d, err := time.ParseDuration(params.Since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since: %q; must be a duration, e.g. 30m", params.Since)
+		}
+		since = r.clock.Now().Add(-d)
+	}
+	if params.Kind != "" && !slices.Contains(recordedKinds, params.Kind) {
+		return nil, fmt.Errorf("invalid kind: %q", params.Kind)
+	}
+	return Events{Events: r.Events(since, params.Kind)}, nil
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestRecorder(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(now)
+	bus := NewBus(WithClock(fakeClock))
+	tools := server.NewFakeRegistry()
+
+	r := NewRecorder(bus, tools, WithClock(fakeClock))
+	assert.Equal(t, "event-recorder", r.Name())
+	require.NoError(t, r.Init())
+	require.Contains(t, tools.Tools, ToolName)
+
+	bus.Publish(Event{Kind: SnapshotReady})
+	bus.Publish(Event{Kind: SourceDegraded, Subject: "zone/package-0", Time: now.Add(-time.Hour)})
+	bus.Publish(Event{Kind: ResourceTerminated, Subject: "container/abc"})
+	bus.Publish(Event{Kind: ThresholdCrossed, Subject: "budget/node"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+	require.Eventually(t, func() bool { return len(r.Events(time.Time{}, "")) == 3 }, time.Second, time.Millisecond,
+		"snapshots are not recorded")
+	cancel()
+	require.NoError(t, <-done)
+
+	result, err := tools.Tools[ToolName](context.Background(), json.RawMessage(`{"kind": "threshold-crossed"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []Event{{Kind: ThresholdCrossed, Time: now, Subject: "budget/node"}}, result.(Events).Events)
+
+	for i := range maxEvents {
+		r.record(Event{Kind: ResourceTerminated, Time: now.Add(time.Duration(i) * time.Second)})
+	}
+	assert.Len(t, r.Events(time.Time{}, ""), maxEvents, "only the most recent events are kept")
+
+	result, err = tools.Tools[ToolName](context.Background(), json.RawMessage(`{"since": "30m", "kind": "resource-terminated"}`))
+	require.NoError(t, err)
+	assert.Len(t, result.(Events).Events, maxEvents)
+
+	_, err = tools.Tools[ToolName](context.Background(), json.RawMessage(`{"since": "yesterday"}`))
+	assert.ErrorContains(t, err, `invalid since: "yesterday"`)
+	_, err = tools.Tools[ToolName](context.Background(), json.RawMessage(`{"kind": "snapshot-ready"}`))
+	assert.ErrorContains(t, err, `invalid kind: "snapshot-ready"`)
+}
@@ -0,0 +1,416 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package live streams the power of each collection over a WebSocket as
+// Grafana data frames, for real-time dashboards that refresh faster than
+// Prometheus scrapes.
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/query"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"golang.org/x/net/websocket"
+	"k8s.io/utils/clock"
+)
+
+type (
+	Initializer = service.Initializer
+	Runner      = service.Runner
+	Shutdowner  = service.Shutdowner
+	Dependent   = service.Dependent
+	Monitor     = monitor.Service
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+const (
+	// Endpoint is the WebSocket endpoint frames are streamed at
+	Endpoint = "/api/v1/stream"
+
+	// DefaultExpr is the expression streamed if none is given
+	DefaultExpr = "kepler_node_cpu_watts"
+
+	// writeTimeout is the time a frame must be written in, after which the
+	// client is disconnected
+	writeTimeout = 10 * time.Second
+)
+
+type Opts struct {
+	logger   *slog.Logger
+	clock    clock.WithTicker
+	interval time.Duration
+	events   *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:   slog.Default(),
+		clock:    clock.RealClock{},
+		interval: 5 * time.Second,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Exporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock snapshots are polled with
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithInterval sets the interval snapshots are polled at; it should be the
+// interval of the monitor so that each collection is streamed
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithEventBus streams each snapshot the monitor publishes on bus, rather than
+// polling the snapshot every interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// Frame is a Grafana data frame in its JSON encoding, as streamed by Grafana
+// Live. The schema is only sent in the first frame and when the series change.
+type Frame struct {
+	Schema *Schema `json:"schema,omitempty"`
+	Data   Data    `json:"data"`
+}
+
+// Schema is the name and fields of a frame
+type Schema struct {
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields"`
+}
+
+// Field is a column of a frame; the first is the time and the others are the
+// series of the expression, identified by their labels
+type Field struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	TypeInfo TypeInfo          `json:"typeInfo"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// TypeInfo is the Go type of the values of a field
+type TypeInfo struct {
+	Frame string `json:"frame"`
+}
+
+// Data is the values of each field of a frame, in the order of the schema:
+// the time in milliseconds since the Unix epoch and the value of each series
+type Data struct {
+	Values [][]float64 `json:"values"`
+}
+
+// subscriber is a client streaming the series of an expression
+type subscriber struct {
+	snapshots chan *monitor.Snapshot
+}
+
+// Exporter streams the series of a query expression, by default the power of
+// the node in each zone, over a WebSocket as a frame per collection
+type Exporter struct {
+	logger    *slog.Logger
+	monitor   Monitor
+	server    APIRegistry
+	clock     clock.WithTicker
+	interval  time.Duration
+	events    *event.Bus
+	snapshots *event.Subscription // nil if the snapshot is polled
+
+	// guards the subscribers and the clients added while shutting down
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	last        time.Time // time of the last snapshot streamed
+
+	done      chan struct{} // closed on shutdown to disconnect clients
+	closeOnce sync.Once
+	wg        sync.WaitGroup // of the clients
+}
+
+var (
+	_ Initializer = (*Exporter)(nil)
+	_ Runner      = (*Exporter)(nil)
+	_ Shutdowner  = (*Exporter)(nil)
+	_ Dependent   = (*Exporter)(nil)
+)
+
+// NewExporter creates a new exporter that streams the power of pm using s
+func NewExporter(pm Monitor, s APIRegistry, applyOpts ...OptionFn) *Exporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Exporter{
+		logger:      opts.logger.With("service", "live"),
+		monitor:     pm,
+		server:      s,
+		clock:       opts.clock,
+		interval:    opts.interval,
+		events:      opts.events,
+		subscribers: map[*subscriber]struct{}{},
+		done:        make(chan struct{}),
+	}
+}
+
+func (e *Exporter) Name() string {
+	return "live"
+}
+
+// Dependencies returns the monitor and the API server frames are streamed by
+func (e *Exporter) Dependencies() []service.Service {
+	deps := []service.Service{e.monitor}
+	if s, ok := e.server.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (e *Exporter) Init() error {
+	if e.events != nil {
+		e.snapshots = e.events.Subscribe(e.Name(), 1, event.SnapshotReady)
+	}
+	e.logger.Info("Initializing live exporter", "endpoint", Endpoint)
+	return e.server.Register(Endpoint, "Live",
+		"WebSocket streaming Grafana data frames of a query at each collection (?expr=sum by (pod_namespace) (kepler_pod_cpu_watts))",
+		http.HandlerFunc(e.handleStream))
+}
+
+// Run polls the snapshot while clients are connected and streams each new one
+// until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context) error {
+	var ready <-chan event.Event
+	if e.snapshots != nil {
+		defer e.snapshots.Close()
+		ready = e.snapshots.C()
+	}
+	var tick <-chan time.Time
+	if ready == nil {
+		ticker := e.clock.NewTicker(e.interval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-ready:
+		}
+		e.poll()
+	}
+}
+
+// Shutdown disconnects the clients, whose connections aren't closed by the
+// web server
+func (e *Exporter) Shutdown() error {
+	e.mu.Lock()
+	e.closeOnce.Do(func() { close(e.done) })
+	e.mu.Unlock()
+	e.wg.Wait()
+	return nil
+}
+
+// poll sends the latest snapshot to the subscribers if it is newer than the
+// last one sent
+func (e *Exporter) poll() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.subscribers) == 0 {
+		return
+	}
+
+	snapshot, err := e.monitor.LatestSnapshot()
+	if err != nil {
+		e.logger.Warn("Failed to get snapshot", "error", err)
+		return
+	}
+	if !snapshot.Timestamp.After(e.last) {
+		return
+	}
+	e.last = snapshot.Timestamp
+
+	for s := range e.subscribers {
+		select {
+		case s.snapshots <- snapshot:
+		default: // the client is still writing the previous frame and misses this one
+		}
+	}
+}
+
+func (e *Exporter) subscribe() *subscriber {
+	s := &subscriber{snapshots: make(chan *monitor.Snapshot, 1)}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.subscribers[s] = struct{}{}
+	return s
+}
+
+func (e *Exporter) unsubscribe(s *subscriber) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.subscribers, s)
+}
+
+// handleStream validates the expr query parameter against the current
+// snapshot before upgrading to a WebSocket streaming its frames
+func (e *Exporter) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	input := r.URL.Query().Get("expr")
+	if input == "" {
+		input = DefaultExpr
+	}
+	expr, err := query.Parse(input)
+	if err != nil {
+		http.Error(w, "invalid expression: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	snapshot, err := e.monitor.LatestSnapshot()
+	if err != nil {
+		e.logger.Error("Failed to get snapshot", "error", err)
+		http.Error(w, "failed to get power data", http.StatusServiceUnavailable)
+		return
+	}
+	v, err := query.NewEvaluator(snapshot, nil).Eval(expr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	e.mu.Lock()
+	select {
+	case <-e.done:
+		e.mu.Unlock()
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	default:
+	}
+	e.wg.Add(1)
+	e.mu.Unlock()
+	defer e.wg.Done()
+
+	websocket.Server{
+		// the power is served to any origin, as by the other endpoints; the
+		// handshake otherwise rejects clients without an Origin header, e.g.
+		// the Grafana server
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			e.stream(ws, input, expr, snapshot.Timestamp, v)
+		},
+	}.ServeHTTP(w, r)
+}
+
+// stream sends the frame of the first vector v at ts, then a frame of expr at
+// each new snapshot until the client disconnects or the exporter shuts down
+func (e *Exporter) stream(ws *websocket.Conn, input string, expr query.Expr, ts time.Time, v query.Vector) {
+	defer ws.Close()
+	logger := e.logger.With("remote", ws.Request().RemoteAddr, "expr", input)
+	logger.Debug("Client connected")
+	defer logger.Debug("Client disconnected")
+
+	s := e.subscribe()
+	defer e.unsubscribe(s)
+
+	// clients send nothing; reading detects that they disconnected
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var msg []byte
+		for websocket.Message.Receive(ws, &msg) == nil {
+		}
+	}()
+
+	var schemaKey string
+	for {
+		frame, key := newFrame(input, ts, v)
+		if key == schemaKey {
+			frame.Schema = nil
+		}
+		schemaKey = key
+
+		if err := ws.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+			return
+		}
+		if err := websocket.JSON.Send(ws, frame); err != nil {
+			logger.Debug("Failed to send frame", "error", err)
+			return
+		}
+
+		select {
+		case <-e.done:
+			return
+		case <-closed:
+			return
+		case snapshot := <-s.snapshots:
+			var err error
+			if v, err = query.NewEvaluator(snapshot, nil).Eval(expr); err != nil {
+				logger.Warn("Failed to evaluate expression", "error", err)
+				return
+			}
+			ts = snapshot.Timestamp
+		}
+	}
+}
+
+// newFrame returns the frame of v at ts, with its schema, and a key of the
+// schema that changes when the series do
+func newFrame(name string, ts time.Time, v query.Vector) (*Frame, string) {
+	schema := &Schema{
+		Name:   name,
+		Fields: []Field{{Name: "time", Type: "time", TypeInfo: TypeInfo{Frame: "time.Time"}}},
+	}
+	values := [][]float64{{float64(ts.UnixMilli())}}
+	var key strings.Builder
+	for _, s := range v {
+		field := Field{Name: "value", Type: "number", TypeInfo: TypeInfo{Frame: "float64"}}
+		if metric, ok := s.Labels[query.NameLabel]; ok {
+			field.Name = metric
+		}
+		field.Labels = maps.Clone(s.Labels)
+		delete(field.Labels, query.NameLabel)
+		schema.Fields = append(schema.Fields, field)
+		values = append(values, []float64{s.Value})
+
+		for _, k := range slices.Sorted(maps.Keys(s.Labels)) {
+			fmt.Fprintf(&key, "%s=%q,", k, s.Labels[k])
+		}
+		key.WriteByte(';')
+	}
+	return &Frame{Schema: schema, Data: Data{Values: values}}, key.String()
+}
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package live
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"golang.org/x/net/websocket"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var (
+	pkg  = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:0", 1000*device.Joule)
+)
+
+var start = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// snapshot returns a snapshot at ts of a node and pods of namespace prod
+// consuming watts each in the package zone
+func snapshot(ts time.Time, node float64, pods ...float64) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = ts
+	s.Node = &monitor.Node{Zones: monitor.NodeZoneUsageMap{
+		pkg:  {Power: monitor.Power(node) * device.Watt},
+		dram: {Power: monitor.Power(node/10) * device.Watt},
+	}}
+	for i, watts := range pods {
+		id := string(rune('a' + i))
+		s.Pods[id] = &monitor.Pod{ID: id, Name: "web-" + id, Namespace: "prod",
+			Zones: monitor.ZoneUsageMap{pkg: {Power: monitor.Power(watts) * device.Watt}}}
+	}
+	return s
+}
+
+func TestExporter(t *testing.T) {
+	pm := monitor.NewFakeMonitor(snapshot(start, 100))
+	registry := server.NewFakeRegistry()
+	fc := testingclock.NewFakeClock(start)
+	e := NewExporter(pm, registry, WithClock(fc), WithInterval(time.Second))
+	assert.Equal(t, "live", e.Name())
+	require.NoError(t, e.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+
+	server := httptest.NewServer(registry.Handlers[Endpoint])
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = e.Run(ctx) }()
+	require.Eventually(t, fc.HasWaiters, time.Second, time.Millisecond)
+
+	dial := func(t *testing.T, expr string) *websocket.Conn {
+		t.Helper()
+		u := "ws" + strings.TrimPrefix(server.URL, "http") + Endpoint
+		if expr != "" {
+			u += "?expr=" + url.QueryEscape(expr)
+		}
+		cfg, err := websocket.NewConfig(u, server.URL)
+		require.NoError(t, err)
+		cfg.Header.Del("Origin") // as sent by servers, e.g. Grafana
+		ws, err := websocket.DialConfig(cfg)
+		require.NoError(t, err)
+		return ws
+	}
+	receive := func(t *testing.T, ws *websocket.Conn) Frame {
+		t.Helper()
+		require.NoError(t, ws.SetReadDeadline(time.Now().Add(time.Second)))
+		var f Frame
+		require.NoError(t, websocket.JSON.Receive(ws, &f))
+		return f
+	}
+
+	t.Run("node", func(t *testing.T) {
+		ws := dial(t, "")
+		defer ws.Close()
+
+		f := receive(t, ws)
+		require.NotNil(t, f.Schema, "the first frame has the schema")
+		assert.Equal(t, DefaultExpr, f.Schema.Name)
+		assert.Equal(t, []Field{
+			{Name: "time", Type: "time", TypeInfo: TypeInfo{Frame: "time.Time"}},
+			{Name: "kepler_node_cpu_watts", Type: "number", TypeInfo: TypeInfo{Frame: "float64"}, Labels: map[string]string{"zone": "dram"}},
+			{Name: "kepler_node_cpu_watts", Type: "number", TypeInfo: TypeInfo{Frame: "float64"}, Labels: map[string]string{"zone": "package"}},
+		}, f.Schema.Fields)
+		assert.Equal(t, [][]float64{{float64(start.UnixMilli())}, {10}, {100}}, f.Data.Values)
+
+		// a new collection is streamed without the schema, which is unchanged
+		next := start.Add(time.Second)
+		pm.Set(snapshot(next, 50))
+		fc.Step(time.Second)
+		f = receive(t, ws)
+		assert.Nil(t, f.Schema)
+		assert.Equal(t, [][]float64{{float64(next.UnixMilli())}, {5}, {50}}, f.Data.Values)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		now := fc.Now()
+		pm.Set(snapshot(now, 100, 20, 10))
+		ws := dial(t, `sum by (pod_namespace) (kepler_pod_cpu_watts)`)
+		defer ws.Close()
+
+		f := receive(t, ws)
+		require.NotNil(t, f.Schema)
+		assert.Equal(t, []Field{
+			{Name: "time", Type: "time", TypeInfo: TypeInfo{Frame: "time.Time"}},
+			{Name: "value", Type: "number", TypeInfo: TypeInfo{Frame: "float64"}, Labels: map[string]string{"pod_namespace": "prod"}},
+		}, f.Schema.Fields)
+		assert.Equal(t, [][]float64{{float64(now.UnixMilli())}, {30}}, f.Data.Values)
+	})
+
+	t.Run("schema is sent when the series change", func(t *testing.T) {
+		now := fc.Now()
+		pm.Set(snapshot(now, 100, 20))
+		ws := dial(t, `kepler_pod_cpu_watts`)
+		defer ws.Close()
+		f := receive(t, ws)
+		require.NotNil(t, f.Schema)
+		assert.Len(t, f.Schema.Fields, 2)
+
+		pm.Set(snapshot(now.Add(time.Second), 100, 20, 10))
+		fc.Step(time.Second)
+		f = receive(t, ws)
+		require.NotNil(t, f.Schema)
+		assert.Len(t, f.Schema.Fields, 3)
+		assert.Equal(t, [][]float64{{float64(now.Add(time.Second).UnixMilli())}, {20}, {10}}, f.Data.Values)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for expr, code := range map[string]int{
+			`sum(`:                  http.StatusBadRequest,
+			`kepler_node_gpu_watts`: http.StatusUnprocessableEntity,
+		} {
+			resp, err := http.Get(server.URL + Endpoint + "?expr=" + url.QueryEscape(expr))
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, code, resp.StatusCode, expr)
+		}
+	})
+
+	t.Run("shutdown disconnects clients", func(t *testing.T) {
+		ws := dial(t, "")
+		defer ws.Close()
+		receive(t, ws)
+
+		require.NoError(t, e.Shutdown())
+		require.NoError(t, ws.SetReadDeadline(time.Now().Add(time.Second)))
+		var f Frame
+		assert.Error(t, websocket.JSON.Receive(ws, &f))
+	})
+}
@@ -0,0 +1,579 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mqtt publishes the power of the node and its workloads to an MQTT
+// broker, for edge and IoT fleets that collect telemetry over MQTT rather than
+// by scraping Prometheus.
+package mqtt
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+	"k8s.io/utils/clock"
+)
+
+type (
+	Initializer = service.Initializer
+	Runner      = service.Runner
+	Shutdowner  = service.Shutdowner
+	Dependent   = service.Dependent
+	Monitor     = monitor.Service
+)
+
+// NodePlaceholder is replaced by the name of the node in topics
+const NodePlaceholder = "{node}"
+
+// Topics the power is published to, under the topic of the exporter
+const (
+	TopicNode       = "node"
+	TopicProcesses  = "processes"
+	TopicContainers = "containers"
+	TopicVMs        = "vms"
+	TopicPods       = "pods"
+
+	// topics of the delta mode, which replace the ones above
+	TopicSnapshot = "snapshot"
+	TopicDelta    = "delta"
+)
+
+// Default ports of brokers
+const (
+	defaultPort    = "1883"
+	defaultTLSPort = "8883"
+)
+
+// NodePower is the message published to the node topic
+type NodePower struct {
+	Node       string                  `json:"node"`
+	Timestamp  time.Time               `json:"timestamp"`
+	UsageRatio float64                 `json:"usageRatio"` // ratio of the CPU time used
+	Zones      map[string]api.NodeZone `json:"zones"`      // keyed by zone name
+}
+
+// WorkloadsPower is the message published to the topic of each kind of
+// workload, with the workloads of the kind running on the node
+type WorkloadsPower struct {
+	Node      string         `json:"node"`
+	Timestamp time.Time      `json:"timestamp"`
+	Workloads []api.Workload `json:"workloads"`
+}
+
+type Opts struct {
+	logger       *slog.Logger
+	clock        clock.WithTicker
+	nodeName     string
+	topic        string
+	qos          byte
+	retain       bool
+	clientID     string
+	username     string
+	password     string
+	tlsConfig    *tls.Config
+	metricsLevel config.Level
+	interval     time.Duration
+	timeout      time.Duration
+	fullEvery    int
+	events       *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:       slog.Default(),
+		clock:        clock.RealClock{},
+		topic:        "kepler/" + NodePlaceholder,
+		metricsLevel: config.MetricsLevelAll,
+		interval:     15 * time.Second,
+		timeout:      10 * time.Second,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Exporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to publish periodically
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithNodeName sets the name of the node; the hostname is used if empty
+func WithNodeName(name string) OptionFn {
+	return func(o *Opts) {
+		o.nodeName = name
+	}
+}
+
+// WithTopic sets the topic the messages are published under; NodePlaceholder
+// is replaced by the name of the node
+func WithTopic(topic string) OptionFn {
+	return func(o *Opts) {
+		o.topic = topic
+	}
+}
+
+// WithQoS sets the quality of service of the messages: 0, 1 or 2
+func WithQoS(qos byte) OptionFn {
+	return func(o *Opts) {
+		o.qos = qos
+	}
+}
+
+// WithRetain sets whether the broker retains the last message of each topic
+// for new subscribers
+func WithRetain(retain bool) OptionFn {
+	return func(o *Opts) {
+		o.retain = retain
+	}
+}
+
+// WithClientID sets the client identifier; kepler-<node> is used if empty
+func WithClientID(id string) OptionFn {
+	return func(o *Opts) {
+		o.clientID = id
+	}
+}
+
+// WithCredentials sets the credentials to connect to the broker with
+func WithCredentials(username, password string) OptionFn {
+	return func(o *Opts) {
+		o.username = username
+		o.password = password
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of brokers with an ssl, tls or
+// mqtts URL
+func WithTLSConfig(c *tls.Config) OptionFn {
+	return func(o *Opts) {
+		o.tlsConfig = c
+	}
+}
+
+// WithMetricsLevel sets the levels of workloads published
+func WithMetricsLevel(level config.Level) OptionFn {
+	return func(o *Opts) {
+		o.metricsLevel = level
+	}
+}
+
+// WithInterval sets the interval between publishes
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithDeltas publishes a full snapshot every fullEvery messages and the deltas
+// from the previous snapshot in between, instead of the power of the node and
+// of each kind of workload; deltas are disabled if fullEvery is 0
+func WithDeltas(fullEvery int) OptionFn {
+	return func(o *Opts) {
+		o.fullEvery = fullEvery
+	}
+}
+
+// WithTimeout sets the timeout of connecting to and of each exchange with the
+// broker
+func WithTimeout(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.timeout = d
+	}
+}
+
+// WithEventBus publishes the power of the first snapshot the monitor publishes
+// on bus after each interval, rather than getting a snapshot every interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// Exporter publishes the power of the node and of its workloads from the
+// snapshot of the monitor to an MQTT broker periodically
+type Exporter struct {
+	logger       *slog.Logger
+	monitor      Monitor
+	clock        clock.WithTicker
+	broker       string
+	nodeName     string
+	topic        string
+	qos          byte
+	retain       bool
+	metricsLevel config.Level
+	interval     time.Duration
+	fullEvery    int
+	events       *event.Bus
+	snapshots    *event.Subscription // nil if published every interval
+
+	address            string      // host:port of the broker
+	tlsConfig          *tls.Config // nil if TLS is disabled
+	timeout            time.Duration
+	keepAlive          time.Duration
+	clientID           string
+	username, password string // empty if authentication is disabled
+
+	clientOpts *paho.ClientOptions // set by Init
+
+	// guards the client and the state of deltas, used by Run and by Shutdown
+	mu        sync.Mutex
+	client    paho.Client   // nil until connected and after a failure
+	last      *api.Snapshot // last snapshot published in delta mode; nil to publish a full one
+	sinceFull int           // deltas published since the last full snapshot
+}
+
+var (
+	_ Initializer = (*Exporter)(nil)
+	_ Runner      = (*Exporter)(nil)
+	_ Shutdowner  = (*Exporter)(nil)
+	_ Dependent   = (*Exporter)(nil)
+)
+
+// NewExporter creates a new exporter that publishes the power of pm to the
+// broker at the URL broker, e.g. tcp://broker:1883 or ssl://broker:8883
+func NewExporter(pm Monitor, broker string, applyOpts ...OptionFn) *Exporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Exporter{
+		logger:       opts.logger.With("service", "mqtt"),
+		monitor:      pm,
+		clock:        opts.clock,
+		broker:       broker,
+		nodeName:     opts.nodeName,
+		topic:        opts.topic,
+		qos:          opts.qos,
+		retain:       opts.retain,
+		metricsLevel: opts.metricsLevel,
+		interval:     opts.interval,
+		fullEvery:    opts.fullEvery,
+		events:       opts.events,
+		tlsConfig:    opts.tlsConfig,
+		timeout:      opts.timeout,
+		// messages are published every interval, which keeps the connection
+		// alive
+		keepAlive: max(2*opts.interval, time.Minute),
+		clientID:  opts.clientID,
+		username:  opts.username,
+		password:  opts.password,
+	}
+}
+
+func (e *Exporter) Name() string {
+	return "mqtt"
+}
+
+// Dependencies returns the monitor the power is read from
+func (e *Exporter) Dependencies() []service.Service {
+	return []service.Service{e.monitor}
+}
+
+func (e *Exporter) Init() error {
+	if e.qos > 2 {
+		return fmt.Errorf("invalid QoS %d; must be 0, 1 or 2", e.qos)
+	}
+	if e.interval <= 0 {
+		return fmt.Errorf("invalid interval %s; must be positive", e.interval)
+	}
+	if e.fullEvery < 0 {
+		return fmt.Errorf("invalid full snapshot interval %d; must not be negative", e.fullEvery)
+	}
+
+	u, err := url.Parse(e.broker)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("invalid broker URL %q", e.broker)
+	}
+	port := u.Port()
+	scheme := "tcp"
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		e.tlsConfig = nil
+		port = cmp.Or(port, defaultPort)
+	case "ssl", "tls", "mqtts":
+		if e.tlsConfig == nil {
+			e.tlsConfig = &tls.Config{}
+		}
+		e.tlsConfig = e.tlsConfig.Clone()
+		if e.tlsConfig.ServerName == "" {
+			e.tlsConfig.ServerName = u.Hostname()
+		}
+		scheme = "ssl"
+		port = cmp.Or(port, defaultTLSPort)
+	default:
+		return fmt.Errorf("invalid broker URL %q; scheme must be one of tcp, mqtt, ssl, tls, mqtts", e.broker)
+	}
+	e.address = net.JoinHostPort(u.Hostname(), port)
+
+	if e.nodeName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to get hostname: %w", err)
+		}
+		e.nodeName = hostname
+	}
+	e.topic = strings.TrimSuffix(strings.ReplaceAll(e.topic, NodePlaceholder, e.nodeName), "/")
+	if e.topic == "" || strings.ContainsAny(e.topic, "+#") {
+		return fmt.Errorf("invalid topic %q; must be non-empty without wildcards", e.topic)
+	}
+	if e.clientID == "" {
+		e.clientID = "kepler-" + e.nodeName
+	}
+
+	// the exporter connects with a new client on the next publish rather
+	// than reconnecting in the background, to publish a full snapshot after
+	// a reconnect
+	e.clientOpts = paho.NewClientOptions().
+		AddBroker(scheme + "://" + e.address).
+		SetProtocolVersion(4). // MQTT 3.1.1, without falling back to 3.1
+		SetClientID(e.clientID).
+		SetUsername(e.username).
+		SetPassword(e.password).
+		SetTLSConfig(e.tlsConfig).
+		SetCleanSession(true).
+		SetKeepAlive(e.keepAlive).
+		SetConnectTimeout(e.timeout).
+		SetWriteTimeout(e.timeout).
+		SetAutoReconnect(false)
+
+	if e.events != nil {
+		e.snapshots = e.events.Subscribe(e.Name(), 1, event.SnapshotReady)
+	}
+	e.logger.Info("Initializing MQTT exporter",
+		"broker", e.address, "tls", e.tlsConfig != nil, "topic", e.topic, "qos", e.qos, "interval", e.interval, "fullSnapshotEvery", e.fullEvery)
+	return nil
+}
+
+// Run publishes the power periodically until ctx is cancelled; the exporter
+// reconnects to the broker on the next publish if it fails
+func (e *Exporter) Run(ctx context.Context) error {
+	ticker := e.clock.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	var ready <-chan event.Event
+	if e.snapshots != nil {
+		defer e.snapshots.Close()
+		ready = e.snapshots.C()
+	}
+
+	// due is set at each interval until the next snapshot is ready
+	due := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			if ready != nil {
+				due = true
+				continue
+			}
+		case <-ready:
+			if !due {
+				continue
+			}
+			due = false
+		}
+		if err := e.publish(); err != nil {
+			e.logger.Error("Failed to publish power", "broker", e.address, "error", err)
+		}
+	}
+}
+
+// Shutdown publishes the power of the last snapshot, which the monitor
+// computes on shutdown, and disconnects from the broker
+func (e *Exporter) Shutdown() error {
+	if err := e.publish(); err != nil {
+		e.logger.Error("Failed to publish the last power", "broker", e.address, "error", err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.disconnect()
+	return nil
+}
+
+// publish publishes the power of the node and of each kind of workload of the
+// current snapshot, or its delta in delta mode, connecting to the broker if
+// needed
+func (e *Exporter) publish() error {
+	snapshot, err := e.monitor.Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	s := e.running(snapshot.API())
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.client == nil || !e.client.IsConnectionOpen() {
+		e.disconnect()
+		client := paho.NewClient(e.clientOpts)
+		connect := client.Connect()
+		connect.Wait() // bounded by the connect timeout
+		if err := connect.Error(); err != nil {
+			return fmt.Errorf("failed to connect to broker: %w", err)
+		}
+		e.client = client
+		e.logger.Info("Connected to MQTT broker", "broker", e.address)
+		// subscribers may have missed messages while disconnected
+		e.last = nil
+	}
+
+	var messages []message
+	if e.fullEvery > 0 {
+		m, err := e.deltaMessage(s)
+		if err != nil {
+			return err
+		}
+		messages = []message{m}
+	} else if messages, err = e.messages(s); err != nil {
+		return err
+	}
+	for _, m := range messages {
+		if err := e.wait(e.client.Publish(m.topic, e.qos, e.retain, m.payload)); err != nil {
+			e.disconnect()
+			e.last = nil
+			return fmt.Errorf("failed to publish to %s: %w", m.topic, err)
+		}
+	}
+
+	if e.fullEvery > 0 {
+		if e.last == nil {
+			e.sinceFull = 0
+		} else {
+			e.sinceFull++
+		}
+		e.last = s
+	}
+	return nil
+}
+
+// disconnect disconnects the client from the broker, if any, waiting up to
+// the timeout for the DISCONNECT to be sent
+func (e *Exporter) disconnect() {
+	if e.client == nil {
+		return
+	}
+	if e.client.IsConnectionOpen() {
+		e.client.Disconnect(uint(e.timeout.Milliseconds()))
+	}
+	e.client = nil
+}
+
+// wait waits for the broker to acknowledge the exchange of t, as required by
+// the QoS
+func (e *Exporter) wait(t paho.Token) error {
+	if !t.WaitTimeout(e.timeout) {
+		return errors.New("timed out waiting for the broker")
+	}
+	return t.Error()
+}
+
+type message struct {
+	topic   string
+	payload []byte
+}
+
+// kinds returns the topics of the kinds of workloads enabled by the metrics
+// level, keyed by kind
+func (e *Exporter) kinds() map[string]string {
+	kinds := map[string]string{}
+	if e.metricsLevel.IsProcessEnabled() {
+		kinds[api.KindProcess] = TopicProcesses
+	}
+	if e.metricsLevel.IsContainerEnabled() {
+		kinds[api.KindContainer] = TopicContainers
+	}
+	if e.metricsLevel.IsVMEnabled() {
+		kinds[api.KindVM] = TopicVMs
+	}
+	if e.metricsLevel.IsPodEnabled() {
+		kinds[api.KindPod] = TopicPods
+	}
+	return kinds
+}
+
+// running returns s with only the running workloads of the kinds enabled by
+// the metrics level
+func (e *Exporter) running(s *api.Snapshot) *api.Snapshot {
+	kinds := e.kinds()
+	ret := &api.Snapshot{Version: s.Version, Timestamp: s.Timestamp, Node: s.Node, Workloads: []api.Workload{}}
+	for _, w := range s.Workloads {
+		if _, ok := kinds[w.Kind]; ok && !w.Terminated {
+			ret.Workloads = append(ret.Workloads, w)
+		}
+	}
+	return ret
+}
+
+// deltaMessage returns the message of s in delta mode: the full snapshot
+// after a reconnect or a failure and every fullEvery messages, and the delta
+// of s from the last snapshot published otherwise
+func (e *Exporter) deltaMessage(s *api.Snapshot) (message, error) {
+	if e.last == nil || e.sinceFull+1 >= e.fullEvery {
+		e.last = nil
+		payload, err := json.Marshal(s)
+		return message{topic: e.topic + "/" + TopicSnapshot, payload: payload}, err
+	}
+	payload, err := json.Marshal(api.Diff(e.last, s))
+	return message{topic: e.topic + "/" + TopicDelta, payload: payload}, err
+}
+
+// messages returns the messages of the node and of each kind of workload
+// enabled by the metrics level in s, which has only running workloads
+func (e *Exporter) messages(s *api.Snapshot) ([]message, error) {
+	kinds := e.kinds()
+	workloads := map[string][]api.Workload{}
+	for kind := range kinds {
+		workloads[kind] = []api.Workload{}
+	}
+	for _, w := range s.Workloads {
+		workloads[w.Kind] = append(workloads[w.Kind], w)
+	}
+
+	node, err := json.Marshal(NodePower{
+		Node: e.nodeName, Timestamp: s.Timestamp, UsageRatio: s.Node.UsageRatio, Zones: s.Node.Zones,
+	})
+	if err != nil {
+		return nil, err
+	}
+	messages := []message{{topic: e.topic + "/" + TopicNode, payload: node}}
+	// kinds are published in a stable order
+	for _, kind := range []string{api.KindProcess, api.KindContainer, api.KindVM, api.KindPod} {
+		topic, ok := kinds[kind]
+		if !ok {
+			continue
+		}
+		payload, err := json.Marshal(WorkloadsPower{Node: e.nodeName, Timestamp: s.Timestamp, Workloads: workloads[kind]})
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message{topic: e.topic + "/" + topic, payload: payload})
+	}
+	return messages, nil
+}
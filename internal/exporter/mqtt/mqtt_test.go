@@ -0,0 +1,413 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// published is a PUBLISH packet received by the broker
+type published struct {
+	topic   string
+	qos     byte
+	retain  bool
+	payload []byte
+}
+
+// broker is a fake MQTT broker that accepts connections with its credentials,
+// if any, acknowledges messages and records them
+type broker struct {
+	t                  *testing.T
+	username, password string
+	connects           chan *packets.ConnectPacket
+	messages           chan published
+	disconnects        chan struct{}
+}
+
+func newBroker(t *testing.T, l net.Listener, username, password string) *broker {
+	b := &broker{
+		t:           t,
+		username:    username,
+		password:    password,
+		connects:    make(chan *packets.ConnectPacket, 10),
+		messages:    make(chan published, 100),
+		disconnects: make(chan struct{}, 10),
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *broker) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		var reply packets.ControlPacket
+		switch p := packet.(type) {
+		case *packets.ConnectPacket:
+			b.connects <- p
+			ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			if b.username != "" && (p.Username != b.username || string(p.Password) != b.password) {
+				ack.ReturnCode = packets.ErrRefusedBadUsernameOrPassword
+			}
+			reply = ack
+		case *packets.PublishPacket:
+			b.messages <- published{topic: p.TopicName, qos: p.Qos, retain: p.Retain, payload: p.Payload}
+			switch p.Qos {
+			case 1:
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				reply = ack
+			case 2:
+				rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				rec.MessageID = p.MessageID
+				reply = rec
+			}
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			reply = comp
+		case *packets.PingreqPacket:
+			reply = packets.NewControlPacket(packets.Pingresp)
+		case *packets.DisconnectPacket:
+			b.disconnects <- struct{}{}
+			return
+		}
+		if reply != nil {
+			if err := reply.Write(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (b *broker) next() published {
+	select {
+	case m := <-b.messages:
+		return m
+	case <-time.After(5 * time.Second):
+		b.t.Fatal("no message published")
+		return published{}
+	}
+}
+
+func testSnapshot() *monitor.Snapshot {
+	pkg := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	s := monitor.NewSnapshot()
+	s.Timestamp = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.Node = &monitor.Node{Timestamp: s.Timestamp, UsageRatio: 0.5, Zones: monitor.NodeZoneUsageMap{
+		pkg: {EnergyTotal: 500 * device.Joule, Power: 40 * device.Watt},
+	}}
+	s.Pods["p-1"] = &monitor.Pod{ID: "p-1", Name: "api", Namespace: "prod", Zones: monitor.ZoneUsageMap{
+		pkg: {EnergyTotal: 100 * device.Joule, Power: 10 * device.Watt},
+	}}
+	s.TerminatedPods["p-0"] = &monitor.Pod{ID: "p-0", Name: "job", Namespace: "dev", Zones: monitor.ZoneUsageMap{
+		pkg: {EnergyTotal: 50 * device.Joule},
+	}}
+	s.Containers["c-1"] = &monitor.Container{ID: "c-1", Name: "server", PodID: "p-1", Zones: monitor.ZoneUsageMap{
+		pkg: {EnergyTotal: 100 * device.Joule, Power: 10 * device.Watt},
+	}}
+	return s
+}
+
+func TestExporter(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := newBroker(t, l, "", "")
+
+	pm := monitor.NewFakeMonitor(testSnapshot())
+	e := NewExporter(pm, "tcp://"+l.Addr().String(),
+		WithNodeName("edge-1"),
+		WithTopic("site/{node}/power/"),
+		WithQoS(1),
+		WithRetain(true),
+		WithMetricsLevel(config.MetricsLevelNode|config.MetricsLevelPod),
+	)
+	assert.Equal(t, "mqtt", e.Name())
+	assert.Equal(t, "monitor", e.Dependencies()[0].Name())
+	require.NoError(t, e.Init())
+	require.NoError(t, e.publish())
+
+	connect := <-b.connects
+	assert.Equal(t, "kepler-edge-1", connect.ClientIdentifier, "the client id defaults to the node name")
+	assert.True(t, connect.CleanSession)
+
+	node := b.next()
+	assert.Equal(t, "site/edge-1/power/node", node.topic)
+	assert.Equal(t, byte(1), node.qos)
+	assert.True(t, node.retain)
+	var nodePower NodePower
+	require.NoError(t, json.Unmarshal(node.payload, &nodePower))
+	assert.Equal(t, "edge-1", nodePower.Node)
+	assert.Equal(t, 0.5, nodePower.UsageRatio)
+	assert.Equal(t, 40.0, nodePower.Zones["package"].Watts)
+	assert.Equal(t, 500.0, nodePower.Zones["package"].Joules)
+
+	pods := b.next()
+	assert.Equal(t, "site/edge-1/power/pods", pods.topic, "only the kinds of the metrics level are published")
+	var podsPower WorkloadsPower
+	require.NoError(t, json.Unmarshal(pods.payload, &podsPower))
+	require.Len(t, podsPower.Workloads, 1, "terminated workloads are not published")
+	assert.Equal(t, "api", podsPower.Workloads[0].Name)
+	assert.Equal(t, 10.0, podsPower.Workloads[0].Zones["package"].Watts)
+
+	// the connection is reused
+	require.NoError(t, e.publish())
+	assert.Equal(t, "site/edge-1/power/node", b.next().topic)
+	assert.Equal(t, "site/edge-1/power/pods", b.next().topic)
+	assert.Empty(t, b.connects)
+
+	require.NoError(t, e.Shutdown())
+	assert.Equal(t, "site/edge-1/power/node", b.next().topic, "the last snapshot is published on shutdown")
+	assert.Equal(t, "site/edge-1/power/pods", b.next().topic)
+	select {
+	case <-b.disconnects:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no DISCONNECT on shutdown")
+	}
+}
+
+func TestExporterQoS(t *testing.T) {
+	for _, qos := range []byte{0, 2} {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		b := newBroker(t, l, "", "")
+
+		e := NewExporter(monitor.NewFakeMonitor(testSnapshot()), "mqtt://"+l.Addr().String(),
+			WithNodeName("edge-1"), WithQoS(qos), WithMetricsLevel(config.MetricsLevelNode))
+		require.NoError(t, e.Init())
+		require.NoError(t, e.publish(), "qos %d", qos)
+		m := b.next()
+		assert.Equal(t, "kepler/edge-1/node", m.topic)
+		assert.Equal(t, qos, m.qos)
+		assert.False(t, m.retain)
+		require.NoError(t, e.Shutdown())
+	}
+}
+
+func TestExporterCredentials(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := newBroker(t, l, "kepler", "secret")
+
+	e := NewExporter(monitor.NewFakeMonitor(testSnapshot()), "tcp://"+l.Addr().String(),
+		WithNodeName("edge-1"), WithQoS(1), WithCredentials("kepler", "wrong"))
+	require.NoError(t, e.Init())
+	err = e.publish()
+	assert.ErrorContains(t, err, "bad user name or password")
+	assert.NotContains(t, err.Error(), "wrong", "the password is not logged")
+
+	e = NewExporter(monitor.NewFakeMonitor(testSnapshot()), "tcp://"+l.Addr().String(),
+		WithNodeName("edge-1"), WithQoS(1), WithClientID("meter-7"), WithCredentials("kepler", "secret"))
+	require.NoError(t, e.Init())
+	require.NoError(t, e.publish())
+	<-b.connects
+	assert.Equal(t, "meter-7", (<-b.connects).ClientIdentifier)
+	assert.Equal(t, "kepler/edge-1/node", b.next().topic)
+}
+
+func TestExporterTLS(t *testing.T) {
+	// the certificate of an httptest TLS server is valid for 127.0.0.1
+	srv := httptest.NewTLSServer(nil)
+	serverTLS := srv.TLS.Clone()
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	srv.Close()
+
+	l, err := tls.Listen("tcp", "127.0.0.1:0", serverTLS)
+	require.NoError(t, err)
+	b := newBroker(t, l, "", "")
+
+	e := NewExporter(monitor.NewFakeMonitor(testSnapshot()), "ssl://"+l.Addr().String(),
+		WithNodeName("edge-1"), WithTLSConfig(&tls.Config{RootCAs: pool}), WithMetricsLevel(config.MetricsLevelNode))
+	require.NoError(t, e.Init())
+	require.NoError(t, e.publish())
+	assert.Equal(t, "kepler/edge-1/node", b.next().topic)
+	require.NoError(t, e.Shutdown())
+
+	t.Run("untrusted", func(t *testing.T) {
+		e := NewExporter(monitor.NewFakeMonitor(testSnapshot()), "tls://"+l.Addr().String(), WithNodeName("edge-1"))
+		require.NoError(t, e.Init())
+		assert.ErrorContains(t, e.publish(), "certificate")
+	})
+}
+
+func TestExporterReconnects(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	e := NewExporter(monitor.NewFakeMonitor(testSnapshot()), "tcp://"+address,
+		WithNodeName("edge-1"), WithMetricsLevel(config.MetricsLevelNode), WithTimeout(time.Second))
+	require.NoError(t, e.Init())
+	assert.ErrorContains(t, e.publish(), "failed to connect to broker", "the broker is down")
+
+	l, err = net.Listen("tcp", address)
+	require.NoError(t, err)
+	b := newBroker(t, l, "", "")
+	require.NoError(t, e.publish(), "the exporter connects once the broker is up")
+	assert.Equal(t, "kepler/edge-1/node", b.next().topic)
+}
+
+func TestExporterEvents(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := newBroker(t, l, "", "")
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	bus := event.NewBus()
+	e := NewExporter(monitor.NewFakeMonitor(testSnapshot()), "tcp://"+l.Addr().String(),
+		WithNodeName("edge-1"),
+		WithMetricsLevel(config.MetricsLevelNode),
+		WithClock(fakeClock),
+		WithInterval(time.Second),
+		WithEventBus(bus),
+	)
+	require.NoError(t, e.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, time.Millisecond)
+	fakeClock.Step(time.Second)
+	assert.Never(t, func() bool { return len(b.messages) > 0 }, 100*time.Millisecond, 10*time.Millisecond,
+		"the power is published once the next snapshot is ready")
+
+	bus.Publish(event.Event{Kind: event.SnapshotReady})
+	assert.Equal(t, "kepler/edge-1/node", b.next().topic)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestExporterDeltas(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := newBroker(t, l, "", "")
+
+	pm := monitor.NewFakeMonitor(testSnapshot())
+	e := NewExporter(pm, "tcp://"+l.Addr().String(),
+		WithNodeName("edge-1"), WithQoS(1), WithDeltas(3),
+		WithMetricsLevel(config.MetricsLevelNode|config.MetricsLevelPod|config.MetricsLevelContainer))
+	require.NoError(t, e.Init())
+
+	require.NoError(t, e.publish())
+	m := b.next()
+	assert.Equal(t, "kepler/edge-1/snapshot", m.topic, "a full snapshot is published first")
+	var full api.Snapshot
+	require.NoError(t, json.Unmarshal(m.payload, &full))
+	assert.Len(t, full.Workloads, 2, "terminated workloads are not published")
+	assert.Empty(t, b.messages, "only the full snapshot is published")
+
+	// the container terminates and the pod consumes more
+	next := testSnapshot()
+	next.Timestamp = next.Timestamp.Add(5 * time.Second)
+	pod := *next.Pods["p-1"]
+	pod.Zones = monitor.ZoneUsageMap{}
+	for zone, usage := range next.Pods["p-1"].Zones {
+		usage.EnergyTotal += 10 * device.Joule
+		pod.Zones[zone] = usage
+	}
+	next.Pods["p-1"] = &pod
+	next.TerminatedContainers["c-1"] = next.Containers["c-1"]
+	delete(next.Containers, "c-1")
+	pm.Set(next)
+
+	require.NoError(t, e.publish())
+	m = b.next()
+	assert.Equal(t, "kepler/edge-1/delta", m.topic)
+	var delta api.Delta
+	require.NoError(t, json.Unmarshal(m.payload, &delta))
+	assert.Nil(t, delta.Node, "the node is unchanged")
+	assert.Empty(t, delta.Added)
+	require.Len(t, delta.Changed, 1)
+	assert.Equal(t, 110.0, delta.Changed[0].Zones["package"].Joules)
+	assert.Equal(t, []api.WorkloadRef{{Kind: api.KindContainer, ID: "c-1"}}, delta.Removed)
+
+	got, err := api.Apply(&full, &delta)
+	require.NoError(t, err)
+	assert.Equal(t, next.Timestamp, got.Timestamp)
+	assert.Len(t, got.Workloads, 1)
+
+	require.NoError(t, e.publish())
+	assert.Equal(t, "kepler/edge-1/delta", b.next().topic)
+	require.NoError(t, e.publish())
+	assert.Equal(t, "kepler/edge-1/snapshot", b.next().topic, "a full snapshot is published every 3 messages")
+	require.NoError(t, e.publish())
+	assert.Equal(t, "kepler/edge-1/delta", b.next().topic)
+
+	// a full snapshot is published after a reconnect
+	e.mu.Lock()
+	e.disconnect()
+	e.mu.Unlock()
+	require.NoError(t, e.publish())
+	assert.Equal(t, "kepler/edge-1/snapshot", b.next().topic)
+	require.NoError(t, e.Shutdown())
+}
+
+func TestExporterInit(t *testing.T) {
+	tt := []struct {
+		name   string
+		broker string
+		opts   []OptionFn
+		err    string
+	}{
+		{"no scheme", "broker:1883", nil, "invalid broker URL"},
+		{"http", "http://broker", nil, "scheme must be one of"},
+		{"no host", "tcp://", nil, "invalid broker URL"},
+		{"qos", "tcp://broker", []OptionFn{WithQoS(3)}, "invalid QoS 3"},
+		{"wildcard", "tcp://broker", []OptionFn{WithTopic("kepler/+")}, "without wildcards"},
+		{"interval", "tcp://broker", []OptionFn{WithInterval(0)}, "invalid interval"},
+		{"deltas", "tcp://broker", []OptionFn{WithDeltas(-1)}, "invalid full snapshot interval"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewExporter(monitor.NewFakeMonitor(nil), tc.broker, append(tc.opts, WithNodeName("edge-1"))...)
+			assert.ErrorContains(t, e.Init(), tc.err)
+		})
+	}
+
+	t.Run("default ports", func(t *testing.T) {
+		e := NewExporter(monitor.NewFakeMonitor(nil), "mqtt://broker", WithNodeName("edge-1"))
+		require.NoError(t, e.Init())
+		assert.Equal(t, "broker:1883", e.address)
+		assert.Nil(t, e.tlsConfig)
+
+		e = NewExporter(monitor.NewFakeMonitor(nil), "mqtts://broker", WithNodeName("edge-1"))
+		require.NoError(t, e.Init())
+		assert.Equal(t, "broker:8883", e.address)
+		assert.Equal(t, "broker", e.tlsConfig.ServerName)
+	})
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/sustainable-computing-io/kepler/internal/anomaly"
+)
+
+// anomalyCollector collects whether the power of the node and its top
+// workloads jumped abnormally in the last interval
+type anomalyCollector struct {
+	series func() []anomaly.Series
+
+	anomalyDesc *prom.Desc
+	zScoreDesc  *prom.Desc
+}
+
+// NewAnomalyCollector creates a collector of the anomalies of the series
+// returned by series
+func NewAnomalyCollector(series func() []anomaly.Series, nodeName string) *anomalyCollector {
+	labels := prom.Labels{nodeNameLabel: nodeName}
+	variableLabels := []string{"kind", "id", "name", "namespace", "zone"}
+	return &anomalyCollector{
+		series: series,
+		anomalyDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "", "power_anomaly"),
+			"1 if the power of the node or workload in the zone jumped abnormally in the last interval, 0 otherwise",
+			variableLabels, labels,
+		),
+		zScoreDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "", "power_anomaly_zscore"),
+			"Deviation of the power of the node or workload in the zone from its moving average in standard deviations",
+			variableLabels, labels,
+		),
+	}
+}
+
+func (c *anomalyCollector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.anomalyDesc
+	ch <- c.zScoreDesc
+}
+
+func (c *anomalyCollector) Collect(ch chan<- prom.Metric) {
+	for _, s := range c.series() {
+		anomalous := 0.0
+		if s.Anomalous {
+			anomalous = 1
+		}
+		ch <- prom.MustNewConstMetric(c.anomalyDesc, prom.GaugeValue, anomalous, s.Kind, s.ID, s.Name, s.Namespace, s.Zone)
+		ch <- prom.MustNewConstMetric(c.zScoreDesc, prom.GaugeValue, s.ZScore, s.Kind, s.ID, s.Name, s.Namespace, s.Zone)
+	}
+}
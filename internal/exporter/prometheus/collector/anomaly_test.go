@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/anomaly"
+)
+
+func TestAnomalyCollector(t *testing.T) {
+	series := []anomaly.Series{
+		{Kind: anomaly.KindNode, Zone: "package", Watts: 180, ExpectedWatts: 100, ZScore: 40, Anomalous: true},
+		{Kind: anomaly.KindPod, ID: "uid-1", Name: "api", Namespace: "prod", Zone: "package", Watts: 20, ExpectedWatts: 20, ZScore: 0.5},
+	}
+	collector := NewAnomalyCollector(func() []anomaly.Series { return series }, "test-node")
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_power_anomaly",
+		map[string]string{"kind": "node", "zone": "package", "node_name": "test-node"}, 1)
+	assertMetricLabelValues(t, registry, "kepler_power_anomaly",
+		map[string]string{"kind": "pod", "name": "api", "namespace": "prod"}, 0)
+	assertMetricLabelValues(t, registry, "kepler_power_anomaly_zscore",
+		map[string]string{"kind": "pod", "id": "uid-1"}, 0.5)
+	assert.Equal(t, 4, testutil.CollectAndCount(collector))
+}
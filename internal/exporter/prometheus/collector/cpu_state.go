@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strconv"
+	"sync"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+)
+
+// cpuStateCollector collects the frequency and idle state residency of CPUs
+// from sysfs
+type cpuStateCollector struct {
+	sync.Mutex
+
+	read          func() ([]device.CPUState, error)
+	frequencyDesc *prom.Desc
+	residencyDesc *prom.Desc
+}
+
+// NewCPUStateCollector creates a collector of the frequency and idle state
+// (C-state) residency of CPUs read from a sysfs mount path
+func NewCPUStateCollector(sysfsPath, nodeName string) *cpuStateCollector {
+	return newCPUStateCollectorWithReader(func() ([]device.CPUState, error) {
+		return device.ReadCPUStates(sysfsPath)
+	}, nodeName)
+}
+
+// newCPUStateCollectorWithReader injects the reader of CPU states
+func newCPUStateCollectorWithReader(read func() ([]device.CPUState, error), nodeName string) *cpuStateCollector {
+	return &cpuStateCollector{
+		read: read,
+		frequencyDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "node", "cpu_frequency_hertz"),
+			"Current frequency of a CPU in hertz",
+			[]string{"cpu"},
+			prom.Labels{nodeNameLabel: nodeName},
+		),
+		residencyDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "node", "cpu_idle_state_seconds_total"),
+			"Total time a CPU spent in an idle state (C-state) in seconds",
+			[]string{"cpu", "state"},
+			prom.Labels{nodeNameLabel: nodeName},
+		),
+	}
+}
+
+func (c *cpuStateCollector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.frequencyDesc
+	ch <- c.residencyDesc
+}
+
+func (c *cpuStateCollector) Collect(ch chan<- prom.Metric) {
+	c.Lock()
+	defer c.Unlock()
+
+	states, err := c.read()
+	if err != nil {
+		return
+	}
+	for _, s := range states {
+		cpu := strconv.Itoa(s.CPU)
+		// cpufreq and cpuidle are often unavailable in VMs
+		if s.Frequency > 0 {
+			ch <- prom.MustNewConstMetric(
+				c.frequencyDesc,
+				prom.GaugeValue,
+				s.Frequency,
+				cpu,
+			)
+		}
+		for _, state := range s.IdleStates {
+			ch <- prom.MustNewConstMetric(
+				c.residencyDesc,
+				prom.CounterValue,
+				state.Residency.Seconds(),
+				cpu, state.Name,
+			)
+		}
+	}
+}
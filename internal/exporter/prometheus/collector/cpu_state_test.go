@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+)
+
+func TestCPUStateCollector(t *testing.T) {
+	states := []device.CPUState{{
+		CPU:       0,
+		Frequency: 2.4e9,
+		IdleStates: []device.CPUIdleState{
+			{Name: "POLL", Residency: 10 * time.Millisecond},
+			{Name: "C6", Residency: 90 * time.Second},
+		},
+	}, {
+		// cpufreq and cpuidle unavailable
+		CPU: 1,
+	}}
+	collector := newCPUStateCollectorWithReader(func() ([]device.CPUState, error) {
+		return states, nil
+	}, "test-node")
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_node_cpu_frequency_hertz",
+		map[string]string{"cpu": "0", "node_name": "test-node"}, 2.4e9)
+	assertMetricLabelValues(t, registry, "kepler_node_cpu_idle_state_seconds_total",
+		map[string]string{"cpu": "0", "state": "POLL"}, 0.01)
+	assertMetricLabelValues(t, registry, "kepler_node_cpu_idle_state_seconds_total",
+		map[string]string{"cpu": "0", "state": "C6"}, 90)
+
+	assert.Equal(t, 3, testutil.CollectAndCount(collector), "cpus without cpufreq or cpuidle are not reported")
+
+	t.Run("read error", func(t *testing.T) {
+		collector := newCPUStateCollectorWithReader(func() ([]device.CPUState, error) {
+			return nil, errors.New("no cpus found")
+		}, "test-node")
+		assert.Zero(t, testutil.CollectAndCount(collector))
+	})
+}
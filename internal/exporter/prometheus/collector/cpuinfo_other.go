@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package collector
+
+import prom "github.com/prometheus/client_golang/prometheus"
+
+// cpuInfoCollector collects no metrics; CPU info is only read from procfs on
+// linux
+type cpuInfoCollector struct{}
+
+// NewCPUInfoCollector creates a CPUInfoCollector that collects no metrics
+func NewCPUInfoCollector(string) (*cpuInfoCollector, error) {
+	return &cpuInfoCollector{}, nil
+}
+
+func (c *cpuInfoCollector) Describe(chan<- *prom.Desc) {}
+
+func (c *cpuInfoCollector) Collect(chan<- prom.Metric) {}
@@ -1,6 +1,8 @@
 // SPDX-FileCopyrightText: 2025 The Kepler Authors
 // SPDX-License-Identifier: Apache-2.0
 
+//go:build linux
+
 package collector
 
 import (
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"math"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+)
+
+// platformSourceCollector collects the power of the platform read by each of
+// its sources and the discrepancy from the primary source, to detect
+// miscalibrated sensors
+type platformSourceCollector struct {
+	sources func() []device.PlatformSourceStatus
+
+	upDesc          *prom.Desc
+	primaryDesc     *prom.Desc
+	wattsDesc       *prom.Desc
+	discrepancyDesc *prom.Desc
+	latencyDesc     *prom.Desc
+}
+
+// NewPlatformSourceCollector creates a collector of the status of the sources
+// of the power of the platform returned by sources
+func NewPlatformSourceCollector(sources func() []device.PlatformSourceStatus, nodeName string) *platformSourceCollector {
+	labels := prom.Labels{nodeNameLabel: nodeName}
+	return &platformSourceCollector{
+		sources: sources,
+		upDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "node", "platform_source_up"),
+			"1 if the source of the power of the platform could be read in the last collection, 0 otherwise",
+			[]string{"source"}, labels,
+		),
+		primaryDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "node", "platform_source_primary"),
+			"1 if the energy of the platform is read from the source, 0 otherwise",
+			[]string{"source"}, labels,
+		),
+		wattsDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "node", "platform_source_watts"),
+			"Power of the platform read from the source in the last collection in watts",
+			[]string{"source"}, labels,
+		),
+		discrepancyDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "node", "platform_source_discrepancy_ratio"),
+			"Relative difference of the power read from the source from the power read from the primary source",
+			[]string{"source"}, labels,
+		),
+		latencyDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "node", "platform_source_read_latency_seconds"),
+			"Time taken to read the source in the last collection in seconds",
+			[]string{"source"}, labels,
+		),
+	}
+}
+
+func (c *platformSourceCollector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.upDesc
+	ch <- c.primaryDesc
+	ch <- c.wattsDesc
+	ch <- c.discrepancyDesc
+	ch <- c.latencyDesc
+}
+
+func (c *platformSourceCollector) Collect(ch chan<- prom.Metric) {
+	for _, s := range c.sources() {
+		up, primary := 0.0, 0.0
+		if s.Err == nil {
+			up = 1
+		}
+		if s.Primary {
+			primary = 1
+		}
+		ch <- prom.MustNewConstMetric(c.upDesc, prom.GaugeValue, up, s.Name)
+		ch <- prom.MustNewConstMetric(c.primaryDesc, prom.GaugeValue, primary, s.Name)
+		ch <- prom.MustNewConstMetric(c.latencyDesc, prom.GaugeValue, s.Latency.Seconds(), s.Name)
+		if s.Err != nil || s.Power == 0 {
+			continue
+		}
+		ch <- prom.MustNewConstMetric(c.wattsDesc, prom.GaugeValue, s.Power.Watts(), s.Name)
+		if !s.Primary && !math.IsNaN(s.Discrepancy) {
+			ch <- prom.MustNewConstMetric(c.discrepancyDesc, prom.GaugeValue, s.Discrepancy, s.Name)
+		}
+	}
+}
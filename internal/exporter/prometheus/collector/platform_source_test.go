@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+)
+
+func TestPlatformSourceCollector(t *testing.T) {
+	sources := []device.PlatformSourceStatus{{
+		Name:    "redfish",
+		Power:   100 * device.Watt,
+		Latency: 800 * time.Millisecond,
+		Primary: true,
+	}, {
+		Name:        "acpi",
+		Power:       110 * device.Watt,
+		Latency:     time.Millisecond,
+		Discrepancy: 0.1,
+	}}
+	collector := NewPlatformSourceCollector(func() []device.PlatformSourceStatus { return sources }, "test-node")
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_node_platform_source_primary",
+		map[string]string{"source": "redfish", "node_name": "test-node"}, 1)
+	assertMetricLabelValues(t, registry, "kepler_node_platform_source_primary",
+		map[string]string{"source": "acpi"}, 0)
+	assertMetricLabelValues(t, registry, "kepler_node_platform_source_watts",
+		map[string]string{"source": "acpi"}, 110)
+	assertMetricLabelValues(t, registry, "kepler_node_platform_source_discrepancy_ratio",
+		map[string]string{"source": "acpi"}, 0.1)
+	assertMetricLabelValues(t, registry, "kepler_node_platform_source_read_latency_seconds",
+		map[string]string{"source": "redfish"}, 0.8)
+	assert.Equal(t, 9, testutil.CollectAndCount(collector), "no discrepancy of the primary")
+
+	t.Run("unavailable source", func(t *testing.T) {
+		sources = []device.PlatformSourceStatus{
+			{Name: "redfish", Err: errors.New("BMC not responding"), Discrepancy: math.NaN()},
+			{Name: "acpi", Power: 110 * device.Watt, Primary: true},
+		}
+		assertMetricLabelValues(t, registry, "kepler_node_platform_source_up",
+			map[string]string{"source": "redfish"}, 0)
+		assertMetricLabelValues(t, registry, "kepler_node_platform_source_up",
+			map[string]string{"source": "acpi"}, 1)
+		assert.Equal(t, 7, testutil.CollectAndCount(collector))
+	})
+}
@@ -6,6 +6,8 @@ package collector
 import (
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,36 +30,331 @@ type PowerCollector struct {
 	// Lock to ensure thread safety during collection
 	mutex sync.RWMutex
 
-	// Node power metrics
-	ready                   bool
-	nodeCPUJoulesDescriptor *prometheus.Desc
-	nodeCPUWattsDescriptor  *prometheus.Desc
+	// Node power metrics of the zones of the CPU, and of those of the GPUs,
+	// which are only exported when isGPU is set
+	ready   bool
+	nodeCPU nodeZoneDescs
+	nodeGPU nodeZoneDescs
+	isGPU   func(monitor.EnergyZone) bool
 
-	// Node power attribution as active and idle
-	nodeCPUActiveWattsDesc  *prometheus.Desc
-	nodeCPUActiveJoulesDesc *prometheus.Desc
+	nodeCPUUsageRatioDescriptor *prometheus.Desc
+	// only exported on hybrid CPUs
+	nodeCoreTypeUsageRatioDesc *prometheus.Desc
 
-	nodeCPUIdleWattsDesc  *prometheus.Desc
-	nodeCPUIdleJoulesDesc *prometheus.Desc
+	nodeWraparoundsDesc *prometheus.Desc
+	nodeJumpsDesc       *prometheus.Desc
 
-	nodeCPUUsageRatioDescriptor *prometheus.Desc
+	// Min and max node power within the interval; only exported when the
+	// power of zones is sampled
+	powerRange bool
+
+	// Power of the node zones before calibration; only exported when zones are
+	// calibrated
+	calibration bool
 
 	// Process power metrics
 	processCPUJoulesDescriptor *prometheus.Desc
 	processCPUWattsDescriptor  *prometheus.Desc
 	processCPUTimeDescriptor   *prometheus.Desc
+	processCPUActiveWattsDesc  *prometheus.Desc
+	processCPUIdleWattsDesc    *prometheus.Desc
 
 	// Container power metrics
 	containerCPUJoulesDescriptor *prometheus.Desc
 	containerCPUWattsDescriptor  *prometheus.Desc
+	containerCPUActiveWattsDesc  *prometheus.Desc
+	containerCPUIdleWattsDesc    *prometheus.Desc
 
 	// Virtual Machine power metrics
 	vmCPUJoulesDescriptor *prometheus.Desc
 	vmCPUWattsDescriptor  *prometheus.Desc
+	vmCPUActiveWattsDesc  *prometheus.Desc
+	vmCPUIdleWattsDesc    *prometheus.Desc
 
 	// Pod power metrics
 	podCPUJoulesDescriptor *prometheus.Desc
 	podCPUWattsDescriptor  *prometheus.Desc
+	podCPUActiveWattsDesc  *prometheus.Desc
+	podCPUIdleWattsDesc    *prometheus.Desc
+
+	// Power of the pods of owner workloads, e.g. Deployments
+	workloadCPUJoulesDesc      *prometheus.Desc
+	workloadCPUWattsDesc       *prometheus.Desc
+	workloadCPUActiveWattsDesc *prometheus.Desc
+	workloadCPUIdleWattsDesc   *prometheus.Desc
+	workloadPodsDesc           *prometheus.Desc
+
+	// Process hardware events; only exported when perf events are counted
+	hwCounters                 bool
+	processCPUInstructionsDesc *prometheus.Desc
+	processCPUCyclesDesc       *prometheus.Desc
+	processCPUCacheMissesDesc  *prometheus.Desc
+
+	// Process disk and network bytes; only exported when I/O is tracked
+	io                        bool
+	processDiskReadBytesDesc  *prometheus.Desc
+	processDiskWriteBytesDesc *prometheus.Desc
+	processNetRxBytesDesc     *prometheus.Desc
+	processNetTxBytesDesc     *prometheus.Desc
+
+	// Process command line and user; only exported when process metadata is tracked
+	processInfo     bool
+	processInfoDesc *prometheus.Desc
+
+	// Container image and compose project; only exported when containers are
+	// looked up in container runtimes
+	containerInfo     bool
+	containerInfoDesc *prometheus.Desc
+
+	// Pod owner workload; only exported when pod metadata is tracked
+	podInfo     bool
+	podInfoDesc *prometheus.Desc
+
+	// systemd unit power metrics; only exported when systemd units are tracked
+	systemdUnits                  bool
+	systemdUnitCPUJoulesDesc      *prometheus.Desc
+	systemdUnitCPUWattsDesc       *prometheus.Desc
+	systemdUnitCPUActiveWattsDesc *prometheus.Desc
+	systemdUnitCPUIdleWattsDesc   *prometheus.Desc
+
+	// group power metrics; only exported when groups are defined
+	groups                  bool
+	groupCPUJoulesDesc      *prometheus.Desc
+	groupCPUWattsDesc       *prometheus.Desc
+	groupCPUActiveWattsDesc *prometheus.Desc
+	groupCPUIdleWattsDesc   *prometheus.Desc
+
+	// kernel and system aggregate power metrics; only exported when aggregates
+	// are tracked
+	aggregates                  bool
+	aggregateCPUJoulesDesc      *prometheus.Desc
+	aggregateCPUWattsDesc       *prometheus.Desc
+	aggregateCPUActiveWattsDesc *prometheus.Desc
+	aggregateCPUIdleWattsDesc   *prometheus.Desc
+
+	// Carbon emission metrics; only exported when a carbon intensity
+	// provider is configured
+	carbon                  bool
+	nodeCarbonIntensityDesc *prometheus.Desc
+	processCPUCO2eDesc      *prometheus.Desc
+	containerCPUCO2eDesc    *prometheus.Desc
+	vmCPUCO2eDesc           *prometheus.Desc
+	podCPUCO2eDesc          *prometheus.Desc
+	workloadCPUCO2eDesc     *prometheus.Desc
+	systemdUnitCPUCO2eDesc  *prometheus.Desc
+	groupCPUCO2eDesc        *prometheus.Desc
+	aggregateCPUCO2eDesc    *prometheus.Desc
+
+	// Energy cost metrics; only exported when a tariff is configured
+	cost                   bool
+	nodePriceDesc          *prometheus.Desc
+	processCPUCostDesc     *prometheus.Desc
+	containerCPUCostDesc   *prometheus.Desc
+	vmCPUCostDesc          *prometheus.Desc
+	podCPUCostDesc         *prometheus.Desc
+	workloadCPUCostDesc    *prometheus.Desc
+	systemdUnitCPUCostDesc *prometheus.Desc
+	groupCPUCostDesc       *prometheus.Desc
+	aggregateCPUCostDesc   *prometheus.Desc
+
+	// Energy budget metrics; only exported when budgets are configured
+	budgets             bool
+	budgetRemainingDesc *prometheus.Desc
+	budgetConsumedDesc  *prometheus.Desc
+
+	// Availability of the zones and other sources read by the monitor
+	sourceAvailableDesc *prometheus.Desc
+
+	// Power of the node zones by the source they are measured by; only
+	// exported when sourceOf is set
+	sourceOf      func(monitor.EnergyZone) string
+	nodeWattsDesc *prometheus.Desc
+
+	// processBudget limits the running processes exported; nil if all are
+	// exported
+	processBudget *processBudget
+}
+
+// PowerCollectorOption configures optional metrics of the PowerCollector
+type PowerCollectorOption func(*PowerCollector)
+
+// WithCarbonMetrics enables the export of grid carbon intensity and the
+// cumulative carbon emissions of the node and workloads
+func WithCarbonMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.carbon = enabled
+	}
+}
+
+// WithCostMetrics enables the export of the price of electricity and the
+// cumulative cost of the energy consumed by the node and workloads
+func WithCostMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.cost = enabled
+	}
+}
+
+// WithPowerRangeMetrics enables the export of the min and max power of the
+// node zones within the monitor interval
+func WithPowerRangeMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.powerRange = enabled
+	}
+}
+
+// WithCalibrationMetrics enables the export of the power of the node zones as
+// read, before calibration
+func WithCalibrationMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.calibration = enabled
+	}
+}
+
+// WithHWCounterMetrics enables the export of the instructions, CPU cycles and
+// last level cache misses counted for processes
+func WithHWCounterMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.hwCounters = enabled
+	}
+}
+
+// WithIOMetrics enables the export of the bytes processes read from and wrote
+// to disk, and received and sent over the network
+func WithIOMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.io = enabled
+	}
+}
+
+// WithProcessInfoMetrics enables the export of the command line, user and
+// parent of processes
+func WithProcessInfoMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.processInfo = enabled
+	}
+}
+
+// WithContainerInfoMetrics enables the export of the image and compose project
+// of containers
+func WithContainerInfoMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.containerInfo = enabled
+	}
+}
+
+// WithPodInfoMetrics enables the export of the owner workload of pods
+func WithPodInfoMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.podInfo = enabled
+	}
+}
+
+// WithSystemdUnitMetrics enables the export of the power of systemd units
+func WithSystemdUnitMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.systemdUnits = enabled
+	}
+}
+
+// WithGroupMetrics enables the export of the power of groups of processes
+func WithGroupMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.groups = enabled
+	}
+}
+
+// WithAggregateMetrics enables the export of the power of the kernel and
+// system aggregates
+func WithAggregateMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.aggregates = enabled
+	}
+}
+
+// WithMaxProcesses limits the running processes exported to the n using the
+// most power; the others are exported as a single series with pid
+// monitor.OtherWorkload.
+// 0 exports all processes.
+func WithMaxProcesses(n int) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		if n > 0 {
+			c.processBudget = newProcessBudget(n)
+		}
+	}
+}
+
+// WithBudgetMetrics enables the export of the energy consumed against daily
+// energy budgets
+func WithBudgetMetrics(enabled bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.budgets = enabled
+	}
+}
+
+// WithZoneSources enables the export of the power of the node zones by the
+// source returned by sourceOf, e.g. rapl, platform, gpu or estimated; nil
+// disables it
+func WithZoneSources(sourceOf func(monitor.EnergyZone) string) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.sourceOf = sourceOf
+	}
+}
+
+// WithGPUZones enables the export of the power of the node zones for which
+// isGPU is true, e.g. nvidia-gpu-0, as kepler_node_gpu_* rather than
+// kepler_node_cpu_*; nil exports all zones as CPU zones
+func WithGPUZones(isGPU func(monitor.EnergyZone) bool) PowerCollectorOption {
+	return func(c *PowerCollector) {
+		c.isGPU = isGPU
+	}
+}
+
+// nodeZoneDescs are the descriptors of the power of the zones of a device at
+// node level
+type nodeZoneDescs struct {
+	joules       *prometheus.Desc
+	watts        *prometheus.Desc
+	activeJoules *prometheus.Desc
+	activeWatts  *prometheus.Desc
+	idleJoules   *prometheus.Desc
+	idleWatts    *prometheus.Desc
+
+	// Relative uncertainty of the node power; workloads share the uncertainty
+	// of the zones their power is attributed from, so it is only exported for
+	// the node
+	uncertainty *prometheus.Desc
+
+	minWatts *prometheus.Desc
+	maxWatts *prometheus.Desc
+	rawWatts *prometheus.Desc
+	co2e     *prometheus.Desc
+	cost     *prometheus.Desc
+}
+
+func newNodeZoneDescs(device, nodeName string) nodeZoneDescs {
+	labels := []string{"zone", "path"}
+	return nodeZoneDescs{
+		joules:       joulesDesc("node", device, nodeName, labels),
+		watts:        wattsDesc("node", device, nodeName, labels),
+		activeJoules: deviceStateJoulesDesc("node", device, "active", nodeName, labels),
+		activeWatts:  deviceStateWattsDesc("node", device, "active", nodeName, labels),
+		idleJoules:   deviceStateJoulesDesc("node", device, "idle", nodeName, labels),
+		idleWatts:    deviceStateWattsDesc("node", device, "idle", nodeName, labels),
+
+		uncertainty: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "node", device+"_uncertainty_ratio"),
+			"Relative uncertainty of the power of a zone at node level, e.g. 0.2 if it is estimated within ±20%; 0 if it is measured",
+			labels, prometheus.Labels{nodeNameLabel: nodeName}),
+
+		minWatts: powerRangeDesc("node", device, "min", nodeName, labels),
+		maxWatts: powerRangeDesc("node", device, "max", nodeName, labels),
+		rawWatts: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "node", device+"_raw_watts"),
+			fmt.Sprintf("Power of a zone at node level in watts as read, before calibration; kepler_node_%s_watts is the corrected power", device),
+			labels, prometheus.Labels{nodeNameLabel: nodeName}),
+		co2e: co2eDesc("node", device, nodeName, labels),
+		cost: costDesc("node", device, nodeName, labels),
+	}
 }
 
 func joulesDesc(level, device, nodeName string, labels []string) *prometheus.Desc {
@@ -88,6 +385,27 @@ func deviceStateWattsDesc(level, device, state, nodeName string, labels []string
 		labels, prometheus.Labels{nodeNameLabel: nodeName})
 }
 
+func powerRangeDesc(level, device, stat, nodeName string, labels []string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(keplerNS, level, fmt.Sprintf("%s_%s_watts", device, stat)),
+		fmt.Sprintf("%s power consumption of %s at %s level in watts within the monitor interval", strings.ToUpper(stat[:1])+stat[1:], device, level),
+		labels, prometheus.Labels{nodeNameLabel: nodeName})
+}
+
+func co2eDesc(level, device, nodeName string, labels []string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(keplerNS, level, device+"_co2e_grams_total"),
+		fmt.Sprintf("Carbon emissions attributed to %s at %s level in grams of CO2e", device, level),
+		labels, prometheus.Labels{nodeNameLabel: nodeName})
+}
+
+func costDesc(level, device, nodeName string, labels []string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(keplerNS, level, device+"_cost_total"),
+		fmt.Sprintf("Cost of the energy consumed by %s at %s level in the currency of the tariff", device, level),
+		labels, prometheus.Labels{nodeNameLabel: nodeName})
+}
+
 func timeDesc(level, device, nodeName string, labels []string) *prometheus.Desc {
 	return prometheus.NewDesc(
 		prometheus.BuildFQName(keplerNS, level, device+"_seconds_total"),
@@ -95,9 +413,23 @@ func timeDesc(level, device, nodeName string, labels []string) *prometheus.Desc
 		labels, prometheus.Labels{nodeNameLabel: nodeName})
 }
 
+func eventsDesc(level, event, description, nodeName string, labels []string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(keplerNS, level, "cpu_"+event+"_total"),
+		fmt.Sprintf("Total %s of %s at %s level counted by CPU performance counters", description, level, level),
+		labels, prometheus.Labels{nodeNameLabel: nodeName})
+}
+
+func bytesDesc(level, device, direction, description, nodeName string, labels []string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(keplerNS, level, fmt.Sprintf("%s_%s_bytes_total", device, direction)),
+		fmt.Sprintf("Total bytes %s by %s at %s level", description, level, level),
+		labels, prometheus.Labels{nodeNameLabel: nodeName})
+}
+
 // NewPowerCollector creates a collector that provides consistent metrics
 // by fetching all data in a single snapshot during collection
-func NewPowerCollector(monitor PowerDataProvider, nodeName string, logger *slog.Logger, metricsLevel config.Level) *PowerCollector {
+func NewPowerCollector(monitor PowerDataProvider, nodeName string, logger *slog.Logger, metricsLevel config.Level, opts ...PowerCollectorOption) *PowerCollector {
 	const (
 		// these labels should remain the same across all descriptors to ease querying
 		zone   = "zone"
@@ -111,32 +443,160 @@ func NewPowerCollector(monitor PowerDataProvider, nodeName string, logger *slog.
 		logger:       logger.With("collector", "power"),
 		metricsLevel: metricsLevel,
 
-		nodeCPUJoulesDescriptor: joulesDesc("node", "cpu", nodeName, []string{zone, "path"}),
-		nodeCPUWattsDescriptor:  wattsDesc("node", "cpu", nodeName, []string{zone, "path"}),
-
-		nodeCPUActiveJoulesDesc: deviceStateJoulesDesc("node", "cpu", "active", nodeName, []string{zone, "path"}),
-		nodeCPUIdleJoulesDesc:   deviceStateJoulesDesc("node", "cpu", "idle", nodeName, []string{zone, "path"}),
-
-		nodeCPUActiveWattsDesc: deviceStateWattsDesc("node", "cpu", "active", nodeName, []string{zone, "path"}),
-		nodeCPUIdleWattsDesc:   deviceStateWattsDesc("node", "cpu", "idle", nodeName, []string{zone, "path"}),
+		nodeCPU: newNodeZoneDescs("cpu", nodeName),
+		nodeGPU: newNodeZoneDescs("gpu", nodeName),
 
 		nodeCPUUsageRatioDescriptor: prometheus.NewDesc(
 			prometheus.BuildFQName(keplerNS, "node", "cpu_usage_ratio"),
 			"CPU usage ratio of a node (value between 0.0 and 1.0)",
 			nil, prometheus.Labels{nodeNameLabel: nodeName}),
 
+		nodeCoreTypeUsageRatioDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "node", "cpu_core_type_usage_ratio"),
+			"CPU usage ratio of the performance or efficiency cores of a node with a hybrid CPU (value between 0.0 and 1.0)",
+			[]string{"core_type"}, prometheus.Labels{nodeNameLabel: nodeName}),
+
+		nodeWraparoundsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "node", "energy_wraparounds_total"),
+			"Number of times the energy counter of a zone wrapped around to 0 at its max energy, which Kepler corrects for",
+			[]string{zone, "path"}, prometheus.Labels{nodeNameLabel: nodeName}),
+		nodeJumpsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "node", "energy_jumps_total"),
+			"Number of times the energy counter of a zone changed by more energy than it can consume in the interval, e.g. as it was reset, whose energy Kepler discarded",
+			[]string{zone, "path"}, prometheus.Labels{nodeNameLabel: nodeName}),
+
 		processCPUJoulesDescriptor: joulesDesc("process", "cpu", nodeName, []string{"pid", "comm", "exe", "type", "state", cntrID, vmID, zone}),
 		processCPUWattsDescriptor:  wattsDesc("process", "cpu", nodeName, []string{"pid", "comm", "exe", "type", "state", cntrID, vmID, zone}),
 		processCPUTimeDescriptor:   timeDesc("process", "cpu", nodeName, []string{"pid", "comm", "exe", "type", cntrID, vmID}),
 
+		processCPUInstructionsDesc: eventsDesc("process", "instructions", "instructions retired", nodeName, []string{"pid", "comm", "exe", "type", cntrID, vmID}),
+		processCPUCyclesDesc:       eventsDesc("process", "cycles", "CPU cycles", nodeName, []string{"pid", "comm", "exe", "type", cntrID, vmID}),
+		processCPUCacheMissesDesc:  eventsDesc("process", "cache_misses", "last level cache misses", nodeName, []string{"pid", "comm", "exe", "type", cntrID, vmID}),
+
+		processDiskReadBytesDesc:  bytesDesc("process", "disk", "read", "read from disk", nodeName, []string{"pid", "comm", "exe", "type", cntrID, vmID}),
+		processDiskWriteBytesDesc: bytesDesc("process", "disk", "written", "written to disk", nodeName, []string{"pid", "comm", "exe", "type", cntrID, vmID}),
+		processNetRxBytesDesc:     bytesDesc("process", "network", "receive", "received over the network", nodeName, []string{"pid", "comm", "exe", "type", cntrID, vmID}),
+		processNetTxBytesDesc:     bytesDesc("process", "network", "transmit", "sent over the network", nodeName, []string{"pid", "comm", "exe", "type", cntrID, vmID}),
+
+		processCPUActiveWattsDesc: deviceStateWattsDesc("process", "cpu", "active", nodeName, []string{"pid", "comm", "exe", "type", "state", cntrID, vmID, zone}),
+		processCPUIdleWattsDesc:   deviceStateWattsDesc("process", "cpu", "idle", nodeName, []string{"pid", "comm", "exe", "type", "state", cntrID, vmID, zone}),
+
 		containerCPUJoulesDescriptor: joulesDesc("container", "cpu", nodeName, []string{cntrID, "container_name", "runtime", "state", zone, podID}),
 		containerCPUWattsDescriptor:  wattsDesc("container", "cpu", nodeName, []string{cntrID, "container_name", "runtime", "state", zone, podID}),
 
+		containerCPUActiveWattsDesc: deviceStateWattsDesc("container", "cpu", "active", nodeName, []string{cntrID, "container_name", "runtime", "state", zone, podID}),
+		containerCPUIdleWattsDesc:   deviceStateWattsDesc("container", "cpu", "idle", nodeName, []string{cntrID, "container_name", "runtime", "state", zone, podID}),
+
 		vmCPUJoulesDescriptor: joulesDesc("vm", "cpu", nodeName, []string{vmID, "vm_name", "hypervisor", "state", zone}),
 		vmCPUWattsDescriptor:  wattsDesc("vm", "cpu", nodeName, []string{vmID, "vm_name", "hypervisor", "state", zone}),
 
+		vmCPUActiveWattsDesc: deviceStateWattsDesc("vm", "cpu", "active", nodeName, []string{vmID, "vm_name", "hypervisor", "state", zone}),
+		vmCPUIdleWattsDesc:   deviceStateWattsDesc("vm", "cpu", "idle", nodeName, []string{vmID, "vm_name", "hypervisor", "state", zone}),
+
 		podCPUJoulesDescriptor: joulesDesc("pod", "cpu", nodeName, []string{podID, "pod_name", "pod_namespace", "state", zone}),
 		podCPUWattsDescriptor:  wattsDesc("pod", "cpu", nodeName, []string{podID, "pod_name", "pod_namespace", "state", zone}),
+
+		podCPUActiveWattsDesc: deviceStateWattsDesc("pod", "cpu", "active", nodeName, []string{podID, "pod_name", "pod_namespace", "state", zone}),
+		podCPUIdleWattsDesc:   deviceStateWattsDesc("pod", "cpu", "idle", nodeName, []string{podID, "pod_name", "pod_namespace", "state", zone}),
+
+		processInfoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "process", "info"),
+			"Command line, user and parent of running processes; always 1",
+			[]string{"pid", "comm", "cmdline", "uid", "user", "ppid"},
+			prometheus.Labels{nodeNameLabel: nodeName}),
+
+		containerInfoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "container", "info"),
+			"Image and compose project of running containers; always 1",
+			[]string{cntrID, "container_name", "runtime", "image", "compose_project", podID},
+			prometheus.Labels{nodeNameLabel: nodeName}),
+
+		workloadCPUJoulesDesc: joulesDesc("workload", "cpu", nodeName, []string{"workload_kind", "workload_name", "workload_namespace", zone}),
+		workloadCPUWattsDesc:  wattsDesc("workload", "cpu", nodeName, []string{"workload_kind", "workload_name", "workload_namespace", zone}),
+
+		workloadCPUActiveWattsDesc: deviceStateWattsDesc("workload", "cpu", "active", nodeName, []string{"workload_kind", "workload_name", "workload_namespace", zone}),
+		workloadCPUIdleWattsDesc:   deviceStateWattsDesc("workload", "cpu", "idle", nodeName, []string{"workload_kind", "workload_name", "workload_namespace", zone}),
+
+		workloadPodsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "workload", "pods"),
+			"Number of running pods of the workload, e.g. a Deployment, on the node",
+			[]string{"workload_kind", "workload_name", "workload_namespace"},
+			prometheus.Labels{nodeNameLabel: nodeName}),
+
+		podInfoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "pod", "info"),
+			"Owner workload of running pods; always 1",
+			[]string{podID, "pod_name", "pod_namespace", "owner_kind", "owner_name"},
+			prometheus.Labels{nodeNameLabel: nodeName}),
+
+		systemdUnitCPUJoulesDesc: joulesDesc("systemd_unit", "cpu", nodeName, []string{"unit_name", "slice", "state", zone}),
+		systemdUnitCPUWattsDesc:  wattsDesc("systemd_unit", "cpu", nodeName, []string{"unit_name", "slice", "state", zone}),
+
+		systemdUnitCPUActiveWattsDesc: deviceStateWattsDesc("systemd_unit", "cpu", "active", nodeName, []string{"unit_name", "slice", "state", zone}),
+		systemdUnitCPUIdleWattsDesc:   deviceStateWattsDesc("systemd_unit", "cpu", "idle", nodeName, []string{"unit_name", "slice", "state", zone}),
+
+		groupCPUJoulesDesc: joulesDesc("group", "cpu", nodeName, []string{"group_name", "pid", "cgroup", "state", zone}),
+		groupCPUWattsDesc:  wattsDesc("group", "cpu", nodeName, []string{"group_name", "pid", "cgroup", "state", zone}),
+
+		groupCPUActiveWattsDesc: deviceStateWattsDesc("group", "cpu", "active", nodeName, []string{"group_name", "pid", "cgroup", "state", zone}),
+		groupCPUIdleWattsDesc:   deviceStateWattsDesc("group", "cpu", "idle", nodeName, []string{"group_name", "pid", "cgroup", "state", zone}),
+
+		aggregateCPUJoulesDesc: joulesDesc("aggregate", "cpu", nodeName, []string{"aggregate", zone}),
+		aggregateCPUWattsDesc:  wattsDesc("aggregate", "cpu", nodeName, []string{"aggregate", zone}),
+
+		aggregateCPUActiveWattsDesc: deviceStateWattsDesc("aggregate", "cpu", "active", nodeName, []string{"aggregate", zone}),
+		aggregateCPUIdleWattsDesc:   deviceStateWattsDesc("aggregate", "cpu", "idle", nodeName, []string{"aggregate", zone}),
+
+		nodeCarbonIntensityDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "node", "carbon_intensity_grams_per_kwh"),
+			"Carbon intensity of the electricity grid powering the node in grams of CO2e per kWh",
+			nil, prometheus.Labels{nodeNameLabel: nodeName}),
+		processCPUCO2eDesc:   co2eDesc("process", "cpu", nodeName, []string{"pid", "comm", "exe", "type", "state", cntrID, vmID, zone}),
+		containerCPUCO2eDesc: co2eDesc("container", "cpu", nodeName, []string{cntrID, "container_name", "runtime", "state", zone, podID}),
+		vmCPUCO2eDesc:        co2eDesc("vm", "cpu", nodeName, []string{vmID, "vm_name", "hypervisor", "state", zone}),
+		podCPUCO2eDesc:       co2eDesc("pod", "cpu", nodeName, []string{podID, "pod_name", "pod_namespace", "state", zone}),
+		workloadCPUCO2eDesc:  co2eDesc("workload", "cpu", nodeName, []string{"workload_kind", "workload_name", "workload_namespace", zone}),
+
+		systemdUnitCPUCO2eDesc: co2eDesc("systemd_unit", "cpu", nodeName, []string{"unit_name", "slice", "state", zone}),
+		groupCPUCO2eDesc:       co2eDesc("group", "cpu", nodeName, []string{"group_name", "pid", "cgroup", "state", zone}),
+		aggregateCPUCO2eDesc:   co2eDesc("aggregate", "cpu", nodeName, []string{"aggregate", zone}),
+
+		nodePriceDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "node", "electricity_price_per_kwh"),
+			"Price of the electricity consumed by the node per kWh in the currency of the tariff",
+			nil, prometheus.Labels{nodeNameLabel: nodeName}),
+		processCPUCostDesc:   costDesc("process", "cpu", nodeName, []string{"pid", "comm", "exe", "type", "state", cntrID, vmID, zone}),
+		containerCPUCostDesc: costDesc("container", "cpu", nodeName, []string{cntrID, "container_name", "runtime", "state", zone, podID}),
+		vmCPUCostDesc:        costDesc("vm", "cpu", nodeName, []string{vmID, "vm_name", "hypervisor", "state", zone}),
+		podCPUCostDesc:       costDesc("pod", "cpu", nodeName, []string{podID, "pod_name", "pod_namespace", "state", zone}),
+		workloadCPUCostDesc:  costDesc("workload", "cpu", nodeName, []string{"workload_kind", "workload_name", "workload_namespace", zone}),
+
+		systemdUnitCPUCostDesc: costDesc("systemd_unit", "cpu", nodeName, []string{"unit_name", "slice", "state", zone}),
+		groupCPUCostDesc:       costDesc("group", "cpu", nodeName, []string{"group_name", "pid", "cgroup", "state", zone}),
+		aggregateCPUCostDesc:   costDesc("aggregate", "cpu", nodeName, []string{"aggregate", zone}),
+
+		budgetRemainingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "budget", "remaining_joules"),
+			"Energy remaining in the daily energy budget in joules",
+			[]string{"scope", "name"}, prometheus.Labels{nodeNameLabel: nodeName}),
+		budgetConsumedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "budget", "consumed_joules"),
+			"Energy consumed against the daily energy budget in joules",
+			[]string{"scope", "name"}, prometheus.Labels{nodeNameLabel: nodeName}),
+
+		sourceAvailableDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "source", "available"),
+			"Whether a source of data, e.g. an energy zone, could be read in the last collection (1) or not (0)",
+			[]string{"kind", "source", "path"}, prometheus.Labels{nodeNameLabel: nodeName}),
+
+		nodeWattsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(keplerNS, "node", "watts"),
+			"Power of a zone of the node in watts by the source it is measured by: the meter of the CPU, e.g. rapl or hwmon, platform, gpu, io or estimated",
+			[]string{"source", zone, "path"}, prometheus.Labels{nodeNameLabel: nodeName}),
+	}
+
+	for _, apply := range opts {
+		apply(c)
 	}
 
 	go c.waitForData()
@@ -155,15 +615,18 @@ func (c *PowerCollector) waitForData() {
 func (c *PowerCollector) Describe(ch chan<- *prometheus.Desc) {
 	// node
 	if c.metricsLevel.IsNodeEnabled() {
-		ch <- c.nodeCPUJoulesDescriptor
-		ch <- c.nodeCPUWattsDescriptor
 		ch <- c.nodeCPUUsageRatioDescriptor
-		// node cpu active
-		ch <- c.nodeCPUActiveJoulesDesc
-		ch <- c.nodeCPUActiveWattsDesc
-		// node cpu idle
-		ch <- c.nodeCPUIdleJoulesDesc
-		ch <- c.nodeCPUIdleWattsDesc
+		ch <- c.nodeCoreTypeUsageRatioDesc
+		c.describeNodeZones(ch, &c.nodeCPU)
+		if c.isGPU != nil {
+			c.describeNodeZones(ch, &c.nodeGPU)
+		}
+		ch <- c.nodeWraparoundsDesc
+		ch <- c.nodeJumpsDesc
+
+		if c.sourceOf != nil {
+			ch <- c.nodeWattsDesc
+		}
 	}
 
 	// process
@@ -171,25 +634,188 @@ func (c *PowerCollector) Describe(ch chan<- *prometheus.Desc) {
 		ch <- c.processCPUJoulesDescriptor
 		ch <- c.processCPUWattsDescriptor
 		ch <- c.processCPUTimeDescriptor
+		ch <- c.processCPUActiveWattsDesc
+		ch <- c.processCPUIdleWattsDesc
+
+		if c.hwCounters {
+			ch <- c.processCPUInstructionsDesc
+			ch <- c.processCPUCyclesDesc
+			ch <- c.processCPUCacheMissesDesc
+		}
+
+		if c.io {
+			ch <- c.processDiskReadBytesDesc
+			ch <- c.processDiskWriteBytesDesc
+			ch <- c.processNetRxBytesDesc
+			ch <- c.processNetTxBytesDesc
+		}
+
+		if c.processInfo {
+			ch <- c.processInfoDesc
+		}
 	}
 
 	// container
 	if c.metricsLevel.IsContainerEnabled() {
 		ch <- c.containerCPUJoulesDescriptor
 		ch <- c.containerCPUWattsDescriptor
+		ch <- c.containerCPUActiveWattsDesc
+		ch <- c.containerCPUIdleWattsDesc
 		// ch <- c.containerCPUTimeDescriptor // TODO: add conntainerCPUTimeDescriptor
+
+		if c.containerInfo {
+			ch <- c.containerInfoDesc
+		}
 	}
 
 	// vm
 	if c.metricsLevel.IsVMEnabled() {
 		ch <- c.vmCPUJoulesDescriptor
 		ch <- c.vmCPUWattsDescriptor
+		ch <- c.vmCPUActiveWattsDesc
+		ch <- c.vmCPUIdleWattsDesc
 	}
 
 	// pod
 	if c.metricsLevel.IsPodEnabled() {
 		ch <- c.podCPUJoulesDescriptor
 		ch <- c.podCPUWattsDescriptor
+		ch <- c.podCPUActiveWattsDesc
+		ch <- c.podCPUIdleWattsDesc
+
+		ch <- c.workloadCPUJoulesDesc
+		ch <- c.workloadCPUWattsDesc
+		ch <- c.workloadCPUActiveWattsDesc
+		ch <- c.workloadCPUIdleWattsDesc
+		ch <- c.workloadPodsDesc
+
+		if c.podInfo {
+			ch <- c.podInfoDesc
+		}
+	}
+
+	// systemd unit
+	if c.systemdUnits {
+		ch <- c.systemdUnitCPUJoulesDesc
+		ch <- c.systemdUnitCPUWattsDesc
+		ch <- c.systemdUnitCPUActiveWattsDesc
+		ch <- c.systemdUnitCPUIdleWattsDesc
+	}
+
+	// groups of processes
+	if c.groups {
+		ch <- c.groupCPUJoulesDesc
+		ch <- c.groupCPUWattsDesc
+		ch <- c.groupCPUActiveWattsDesc
+		ch <- c.groupCPUIdleWattsDesc
+	}
+
+	// kernel and system aggregates
+	if c.aggregates {
+		ch <- c.aggregateCPUJoulesDesc
+		ch <- c.aggregateCPUWattsDesc
+		ch <- c.aggregateCPUActiveWattsDesc
+		ch <- c.aggregateCPUIdleWattsDesc
+	}
+
+	if c.carbon {
+		c.describeCarbon(ch)
+	}
+
+	if c.cost {
+		c.describeCost(ch)
+	}
+
+	if c.budgets {
+		ch <- c.budgetRemainingDesc
+		ch <- c.budgetConsumedDesc
+	}
+
+	ch <- c.sourceAvailableDesc
+}
+
+// describeNodeZones describes the power metrics of the zones of a device at
+// node level, other than their emissions and cost
+func (c *PowerCollector) describeNodeZones(ch chan<- *prometheus.Desc, d *nodeZoneDescs) {
+	ch <- d.joules
+	ch <- d.watts
+	ch <- d.activeJoules
+	ch <- d.activeWatts
+	ch <- d.idleJoules
+	ch <- d.idleWatts
+	ch <- d.uncertainty
+	if c.powerRange {
+		ch <- d.minWatts
+		ch <- d.maxWatts
+	}
+	if c.calibration {
+		ch <- d.rawWatts
+	}
+}
+
+// describeCarbon describes the carbon emission metrics of all enabled levels
+func (c *PowerCollector) describeCarbon(ch chan<- *prometheus.Desc) {
+	if c.metricsLevel.IsNodeEnabled() {
+		ch <- c.nodeCarbonIntensityDesc
+		ch <- c.nodeCPU.co2e
+		if c.isGPU != nil {
+			ch <- c.nodeGPU.co2e
+		}
+	}
+	if c.metricsLevel.IsProcessEnabled() {
+		ch <- c.processCPUCO2eDesc
+	}
+	if c.metricsLevel.IsContainerEnabled() {
+		ch <- c.containerCPUCO2eDesc
+	}
+	if c.metricsLevel.IsVMEnabled() {
+		ch <- c.vmCPUCO2eDesc
+	}
+	if c.metricsLevel.IsPodEnabled() {
+		ch <- c.podCPUCO2eDesc
+		ch <- c.workloadCPUCO2eDesc
+	}
+	if c.systemdUnits {
+		ch <- c.systemdUnitCPUCO2eDesc
+	}
+	if c.groups {
+		ch <- c.groupCPUCO2eDesc
+	}
+	if c.aggregates {
+		ch <- c.aggregateCPUCO2eDesc
+	}
+}
+
+// describeCost describes the energy cost metrics of all enabled levels
+func (c *PowerCollector) describeCost(ch chan<- *prometheus.Desc) {
+	if c.metricsLevel.IsNodeEnabled() {
+		ch <- c.nodePriceDesc
+		ch <- c.nodeCPU.cost
+		if c.isGPU != nil {
+			ch <- c.nodeGPU.cost
+		}
+	}
+	if c.metricsLevel.IsProcessEnabled() {
+		ch <- c.processCPUCostDesc
+	}
+	if c.metricsLevel.IsContainerEnabled() {
+		ch <- c.containerCPUCostDesc
+	}
+	if c.metricsLevel.IsVMEnabled() {
+		ch <- c.vmCPUCostDesc
+	}
+	if c.metricsLevel.IsPodEnabled() {
+		ch <- c.podCPUCostDesc
+		ch <- c.workloadCPUCostDesc
+	}
+	if c.systemdUnits {
+		ch <- c.systemdUnitCPUCostDesc
+	}
+	if c.groups {
+		ch <- c.groupCPUCostDesc
+	}
+	if c.aggregates {
+		ch <- c.aggregateCPUCostDesc
 	}
 }
 
@@ -223,13 +849,29 @@ func (c *PowerCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	if c.metricsLevel.IsProcessEnabled() {
-		c.collectProcessMetrics(ch, "running", snapshot.Processes)
+		running := snapshot.Processes
+		if c.processBudget != nil {
+			var other *otherUsage
+			running, other = c.processBudget.split(running)
+			if other != nil {
+				c.collectOtherProcesses(ch, other)
+			}
+		}
+		c.collectProcessMetrics(ch, "running", running)
 		c.collectProcessMetrics(ch, "terminated", snapshot.TerminatedProcesses)
+
+		if c.processInfo {
+			c.collectProcessInfo(ch, running)
+		}
 	}
 
 	if c.metricsLevel.IsContainerEnabled() {
 		c.collectContainerMetrics(ch, "running", snapshot.Containers)
 		c.collectContainerMetrics(ch, "terminated", snapshot.TerminatedContainers)
+
+		if c.containerInfo {
+			c.collectContainerInfo(ch, snapshot.Containers)
+		}
 	}
 
 	if c.metricsLevel.IsVMEnabled() {
@@ -240,6 +882,162 @@ func (c *PowerCollector) Collect(ch chan<- prometheus.Metric) {
 	if c.metricsLevel.IsPodEnabled() {
 		c.collectPodMetrics(ch, "running", snapshot.Pods)
 		c.collectPodMetrics(ch, "terminated", snapshot.TerminatedPods)
+		c.collectWorkloadMetrics(ch, snapshot.OwnerWorkloads)
+
+		if c.podInfo {
+			c.collectPodInfo(ch, snapshot.Pods)
+		}
+	}
+
+	if c.systemdUnits {
+		c.collectSystemdUnitMetrics(ch, "running", snapshot.SystemdUnits)
+		c.collectSystemdUnitMetrics(ch, "terminated", snapshot.TerminatedSystemdUnits)
+	}
+
+	if c.groups {
+		c.collectGroupMetrics(ch, "running", snapshot.Groups)
+		c.collectGroupMetrics(ch, "terminated", snapshot.TerminatedGroups)
+	}
+
+	if c.aggregates {
+		c.collectAggregateMetrics(ch, snapshot.Aggregates)
+	}
+
+	if c.budgets {
+		c.collectBudgetMetrics(ch, snapshot.Budgets)
+	}
+
+	c.collectSourceMetrics(ch, snapshot.Sources)
+}
+
+// collectHWCounters collects the hardware events counted for a process
+func (c *PowerCollector) collectHWCounters(ch chan<- prometheus.Metric, pid string, proc *monitor.Process) {
+	ch <- prometheus.MustNewConstMetric(
+		c.processCPUInstructionsDesc,
+		prometheus.CounterValue,
+		float64(proc.HWCounters.Instructions),
+		pid, proc.Comm, proc.Exe, string(proc.Type),
+		proc.ContainerID, proc.VirtualMachineID,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.processCPUCyclesDesc,
+		prometheus.CounterValue,
+		float64(proc.HWCounters.Cycles),
+		pid, proc.Comm, proc.Exe, string(proc.Type),
+		proc.ContainerID, proc.VirtualMachineID,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.processCPUCacheMissesDesc,
+		prometheus.CounterValue,
+		float64(proc.HWCounters.CacheMisses),
+		pid, proc.Comm, proc.Exe, string(proc.Type),
+		proc.ContainerID, proc.VirtualMachineID,
+	)
+}
+
+// collectIO collects the bytes a process transferred to and from disk and over
+// the network
+func (c *PowerCollector) collectIO(ch chan<- prometheus.Metric, pid string, proc *monitor.Process) {
+	for _, m := range []struct {
+		desc  *prometheus.Desc
+		bytes uint64
+	}{
+		{c.processDiskReadBytesDesc, proc.IO.DiskReadBytes},
+		{c.processDiskWriteBytesDesc, proc.IO.DiskWriteBytes},
+		{c.processNetRxBytesDesc, proc.IO.NetRxBytes},
+		{c.processNetTxBytesDesc, proc.IO.NetTxBytes},
+	} {
+		ch <- prometheus.MustNewConstMetric(
+			m.desc,
+			prometheus.CounterValue,
+			float64(m.bytes),
+			pid, proc.Comm, proc.Exe, string(proc.Type),
+			proc.ContainerID, proc.VirtualMachineID,
+		)
+	}
+}
+
+// collectProcessInfo collects the command line, user and parent of running processes
+func (c *PowerCollector) collectProcessInfo(ch chan<- prometheus.Metric, processes monitor.Processes) {
+	for pid, proc := range processes {
+		if pid == monitor.OtherWorkload {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.processInfoDesc,
+			prometheus.GaugeValue,
+			1,
+			pid, proc.Comm, proc.CmdLine, strconv.Itoa(proc.UID), proc.User, strconv.Itoa(proc.ParentPID),
+		)
+	}
+}
+
+// collectContainerInfo collects the image and compose project of running containers
+func (c *PowerCollector) collectContainerInfo(ch chan<- prometheus.Metric, containers monitor.Containers) {
+	for id, cntr := range containers {
+		if id == monitor.OtherWorkload {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.containerInfoDesc,
+			prometheus.GaugeValue,
+			1,
+			id, cntr.Name, string(cntr.Runtime), cntr.Image, cntr.ComposeProject, cntr.PodID,
+		)
+	}
+}
+
+// collectPodInfo collects the owner workload of running pods
+func (c *PowerCollector) collectPodInfo(ch chan<- prometheus.Metric, pods monitor.Pods) {
+	for id, pod := range pods {
+		if id == monitor.OtherWorkload {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.podInfoDesc,
+			prometheus.GaugeValue,
+			1,
+			id, pod.Name, pod.Namespace, pod.OwnerKind, pod.OwnerName,
+		)
+	}
+}
+
+// collectBudgetMetrics collects the energy consumed against budgets
+func (c *PowerCollector) collectBudgetMetrics(ch chan<- prometheus.Metric, budgets []monitor.BudgetStatus) {
+	for _, b := range budgets {
+		ch <- prometheus.MustNewConstMetric(
+			c.budgetRemainingDesc,
+			prometheus.GaugeValue,
+			b.Remaining().Joules(),
+			b.Scope, b.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.budgetConsumedDesc,
+			prometheus.GaugeValue,
+			b.Consumed.Joules(),
+			b.Scope, b.Name,
+		)
+	}
+}
+
+// collectSourceMetrics collects the availability of the sources of the monitor
+func (c *PowerCollector) collectSourceMetrics(ch chan<- prometheus.Metric, sources []monitor.Source) {
+	for _, s := range sources {
+		name, path := s.Name, ""
+		if s.Zone != nil {
+			name, path = s.Zone.Name(), s.Zone.Path()
+		}
+
+		available := 0.0
+		if s.Available {
+			available = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.sourceAvailableDesc,
+			prometheus.GaugeValue,
+			available,
+			s.Kind, name, path,
+		)
 	}
 }
 
@@ -253,27 +1051,53 @@ func (c *PowerCollector) collectNodeMetrics(ch chan<- prometheus.Metric, node *m
 		prometheus.GaugeValue,
 		node.UsageRatio,
 	)
+	for coreType, ratio := range node.CoreTypeUsageRatios {
+		ch <- prometheus.MustNewConstMetric(
+			c.nodeCoreTypeUsageRatioDesc,
+			prometheus.GaugeValue,
+			ratio,
+			coreType,
+		)
+	}
+	if c.carbon {
+		ch <- prometheus.MustNewConstMetric(
+			c.nodeCarbonIntensityDesc,
+			prometheus.GaugeValue,
+			node.CarbonIntensity,
+		)
+	}
+	if c.cost {
+		ch <- prometheus.MustNewConstMetric(
+			c.nodePriceDesc,
+			prometheus.GaugeValue,
+			node.Price,
+		)
+	}
 	for zone, energy := range node.Zones {
 		path := zone.Path()
 		zoneName := zone.Name()
+		d := &c.nodeCPU
+		if c.isGPU != nil && c.isGPU(zone) {
+			d = &c.nodeGPU
+		}
 
 		// joules
 		ch <- prometheus.MustNewConstMetric(
-			c.nodeCPUJoulesDescriptor,
+			d.joules,
 			prometheus.CounterValue,
 			energy.EnergyTotal.Joules(),
 			zoneName, path,
 		)
 
 		ch <- prometheus.MustNewConstMetric(
-			c.nodeCPUActiveJoulesDesc,
+			d.activeJoules,
 			prometheus.CounterValue,
 			energy.ActiveEnergyTotal.Joules(),
 			zoneName, path,
 		)
 
 		ch <- prometheus.MustNewConstMetric(
-			c.nodeCPUIdleJoulesDesc,
+			d.idleJoules,
 			prometheus.CounterValue,
 			energy.IdleEnergyTotal.Joules(),
 			zoneName, path,
@@ -281,24 +1105,90 @@ func (c *PowerCollector) collectNodeMetrics(ch chan<- prometheus.Metric, node *m
 
 		// watts
 		ch <- prometheus.MustNewConstMetric(
-			c.nodeCPUWattsDescriptor,
+			d.watts,
 			prometheus.GaugeValue,
 			energy.Power.Watts(),
 			zoneName, path,
 		)
 		ch <- prometheus.MustNewConstMetric(
-			c.nodeCPUActiveWattsDesc,
+			d.activeWatts,
 			prometheus.GaugeValue,
 			energy.ActivePower.Watts(),
 			zoneName, path,
 		)
 		ch <- prometheus.MustNewConstMetric(
-			c.nodeCPUIdleWattsDesc,
+			d.idleWatts,
 			prometheus.GaugeValue,
 			energy.IdlePower.Watts(),
 			zoneName, path,
 		)
+		if c.calibration {
+			ch <- prometheus.MustNewConstMetric(
+				d.rawWatts,
+				prometheus.GaugeValue,
+				energy.RawPower.Watts(),
+				zoneName, path,
+			)
+		}
+		if c.sourceOf != nil {
+			ch <- prometheus.MustNewConstMetric(
+				c.nodeWattsDesc,
+				prometheus.GaugeValue,
+				energy.Power.Watts(),
+				c.sourceOf(zone), zoneName, path,
+			)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			d.uncertainty,
+			prometheus.GaugeValue,
+			energy.Uncertainty,
+			zoneName, path,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.nodeWraparoundsDesc,
+			prometheus.CounterValue,
+			float64(energy.Wraparounds),
+			zoneName, path,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.nodeJumpsDesc,
+			prometheus.CounterValue,
+			float64(energy.Jumps),
+			zoneName, path,
+		)
 
+		if c.powerRange {
+			ch <- prometheus.MustNewConstMetric(
+				d.minWatts,
+				prometheus.GaugeValue,
+				energy.MinPower.Watts(),
+				zoneName, path,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				d.maxWatts,
+				prometheus.GaugeValue,
+				energy.MaxPower.Watts(),
+				zoneName, path,
+			)
+		}
+
+		if c.carbon {
+			ch <- prometheus.MustNewConstMetric(
+				d.co2e,
+				prometheus.CounterValue,
+				energy.EmissionsTotal,
+				zoneName, path,
+			)
+		}
+
+		if c.cost {
+			ch <- prometheus.MustNewConstMetric(
+				d.cost,
+				prometheus.CounterValue,
+				energy.CostTotal,
+				zoneName, path,
+			)
+		}
 	}
 }
 
@@ -320,6 +1210,14 @@ func (c *PowerCollector) collectProcessMetrics(ch chan<- prometheus.Metric, stat
 			proc.ContainerID, proc.VirtualMachineID,
 		)
 
+		if c.hwCounters {
+			c.collectHWCounters(ch, pid, proc)
+		}
+
+		if c.io {
+			c.collectIO(ch, pid, proc)
+		}
+
 		for zone, usage := range proc.Zones {
 			zoneName := zone.Name()
 			ch <- prometheus.MustNewConstMetric(
@@ -339,6 +1237,46 @@ func (c *PowerCollector) collectProcessMetrics(ch chan<- prometheus.Metric, stat
 				proc.ContainerID, proc.VirtualMachineID,
 				zoneName,
 			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.processCPUActiveWattsDesc,
+				prometheus.GaugeValue,
+				usage.ActivePower.Watts(),
+				pid, proc.Comm, proc.Exe, string(proc.Type), state,
+				proc.ContainerID, proc.VirtualMachineID,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.processCPUIdleWattsDesc,
+				prometheus.GaugeValue,
+				usage.IdlePower.Watts(),
+				pid, proc.Comm, proc.Exe, string(proc.Type), state,
+				proc.ContainerID, proc.VirtualMachineID,
+				zoneName,
+			)
+
+			if c.carbon {
+				ch <- prometheus.MustNewConstMetric(
+					c.processCPUCO2eDesc,
+					prometheus.CounterValue,
+					usage.EmissionsTotal,
+					pid, proc.Comm, proc.Exe, string(proc.Type), state,
+					proc.ContainerID, proc.VirtualMachineID,
+					zoneName,
+				)
+			}
+
+			if c.cost {
+				ch <- prometheus.MustNewConstMetric(
+					c.processCPUCostDesc,
+					prometheus.CounterValue,
+					usage.CostTotal,
+					pid, proc.Comm, proc.Exe, string(proc.Type), state,
+					proc.ContainerID, proc.VirtualMachineID,
+					zoneName,
+				)
+			}
 		}
 	}
 }
@@ -372,6 +1310,46 @@ func (c *PowerCollector) collectContainerMetrics(ch chan<- prometheus.Metric, st
 				zoneName,
 				container.PodID,
 			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.containerCPUActiveWattsDesc,
+				prometheus.GaugeValue,
+				usage.ActivePower.Watts(),
+				id, container.Name, string(container.Runtime), state,
+				zoneName,
+				container.PodID,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.containerCPUIdleWattsDesc,
+				prometheus.GaugeValue,
+				usage.IdlePower.Watts(),
+				id, container.Name, string(container.Runtime), state,
+				zoneName,
+				container.PodID,
+			)
+
+			if c.carbon {
+				ch <- prometheus.MustNewConstMetric(
+					c.containerCPUCO2eDesc,
+					prometheus.CounterValue,
+					usage.EmissionsTotal,
+					id, container.Name, string(container.Runtime), state,
+					zoneName,
+					container.PodID,
+				)
+			}
+
+			if c.cost {
+				ch <- prometheus.MustNewConstMetric(
+					c.containerCPUCostDesc,
+					prometheus.CounterValue,
+					usage.CostTotal,
+					id, container.Name, string(container.Runtime), state,
+					zoneName,
+					container.PodID,
+				)
+			}
 		}
 	}
 }
@@ -402,6 +1380,230 @@ func (c *PowerCollector) collectVMMetrics(ch chan<- prometheus.Metric, state str
 				id, vm.Name, string(vm.Hypervisor), state,
 				zoneName,
 			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.vmCPUActiveWattsDesc,
+				prometheus.GaugeValue,
+				usage.ActivePower.Watts(),
+				id, vm.Name, string(vm.Hypervisor), state,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.vmCPUIdleWattsDesc,
+				prometheus.GaugeValue,
+				usage.IdlePower.Watts(),
+				id, vm.Name, string(vm.Hypervisor), state,
+				zoneName,
+			)
+
+			if c.carbon {
+				ch <- prometheus.MustNewConstMetric(
+					c.vmCPUCO2eDesc,
+					prometheus.CounterValue,
+					usage.EmissionsTotal,
+					id, vm.Name, string(vm.Hypervisor), state,
+					zoneName,
+				)
+			}
+
+			if c.cost {
+				ch <- prometheus.MustNewConstMetric(
+					c.vmCPUCostDesc,
+					prometheus.CounterValue,
+					usage.CostTotal,
+					id, vm.Name, string(vm.Hypervisor), state,
+					zoneName,
+				)
+			}
+		}
+	}
+}
+
+// collectSystemdUnitMetrics collects systemd unit power metrics
+func (c *PowerCollector) collectSystemdUnitMetrics(ch chan<- prometheus.Metric, state string, units monitor.SystemdUnits) {
+	if len(units) == 0 {
+		c.logger.Debug("No systemd units to export metrics for", "state", state)
+		return
+	}
+
+	for name, unit := range units {
+		for zone, usage := range unit.Zones {
+			zoneName := zone.Name()
+			ch <- prometheus.MustNewConstMetric(
+				c.systemdUnitCPUJoulesDesc,
+				prometheus.CounterValue,
+				usage.EnergyTotal.Joules(),
+				name, unit.Slice, state,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.systemdUnitCPUWattsDesc,
+				prometheus.GaugeValue,
+				usage.Power.Watts(),
+				name, unit.Slice, state,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.systemdUnitCPUActiveWattsDesc,
+				prometheus.GaugeValue,
+				usage.ActivePower.Watts(),
+				name, unit.Slice, state,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.systemdUnitCPUIdleWattsDesc,
+				prometheus.GaugeValue,
+				usage.IdlePower.Watts(),
+				name, unit.Slice, state,
+				zoneName,
+			)
+
+			if c.carbon {
+				ch <- prometheus.MustNewConstMetric(
+					c.systemdUnitCPUCO2eDesc,
+					prometheus.CounterValue,
+					usage.EmissionsTotal,
+					name, unit.Slice, state,
+					zoneName,
+				)
+			}
+
+			if c.cost {
+				ch <- prometheus.MustNewConstMetric(
+					c.systemdUnitCPUCostDesc,
+					prometheus.CounterValue,
+					usage.CostTotal,
+					name, unit.Slice, state,
+					zoneName,
+				)
+			}
+		}
+	}
+}
+
+// collectGroupMetrics collects the power metrics of groups of processes
+func (c *PowerCollector) collectGroupMetrics(ch chan<- prometheus.Metric, state string, groups monitor.Groups) {
+	if len(groups) == 0 {
+		c.logger.Debug("No groups to export metrics for", "state", state)
+		return
+	}
+
+	for name, group := range groups {
+		pid := ""
+		if group.PID != 0 {
+			pid = strconv.Itoa(group.PID)
+		}
+		for zone, usage := range group.Zones {
+			zoneName := zone.Name()
+			ch <- prometheus.MustNewConstMetric(
+				c.groupCPUJoulesDesc,
+				prometheus.CounterValue,
+				usage.EnergyTotal.Joules(),
+				name, pid, group.Cgroup, state,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.groupCPUWattsDesc,
+				prometheus.GaugeValue,
+				usage.Power.Watts(),
+				name, pid, group.Cgroup, state,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.groupCPUActiveWattsDesc,
+				prometheus.GaugeValue,
+				usage.ActivePower.Watts(),
+				name, pid, group.Cgroup, state,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.groupCPUIdleWattsDesc,
+				prometheus.GaugeValue,
+				usage.IdlePower.Watts(),
+				name, pid, group.Cgroup, state,
+				zoneName,
+			)
+
+			if c.carbon {
+				ch <- prometheus.MustNewConstMetric(
+					c.groupCPUCO2eDesc,
+					prometheus.CounterValue,
+					usage.EmissionsTotal,
+					name, pid, group.Cgroup, state,
+					zoneName,
+				)
+			}
+
+			if c.cost {
+				ch <- prometheus.MustNewConstMetric(
+					c.groupCPUCostDesc,
+					prometheus.CounterValue,
+					usage.CostTotal,
+					name, pid, group.Cgroup, state,
+					zoneName,
+				)
+			}
+		}
+	}
+}
+
+// collectAggregateMetrics collects kernel and system aggregate power metrics
+func (c *PowerCollector) collectAggregateMetrics(ch chan<- prometheus.Metric, aggregates monitor.Aggregates) {
+	for name, agg := range aggregates {
+		for zone, usage := range agg.Zones {
+			zoneName := zone.Name()
+			ch <- prometheus.MustNewConstMetric(
+				c.aggregateCPUJoulesDesc,
+				prometheus.CounterValue,
+				usage.EnergyTotal.Joules(),
+				name, zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.aggregateCPUWattsDesc,
+				prometheus.GaugeValue,
+				usage.Power.Watts(),
+				name, zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.aggregateCPUActiveWattsDesc,
+				prometheus.GaugeValue,
+				usage.ActivePower.Watts(),
+				name, zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.aggregateCPUIdleWattsDesc,
+				prometheus.GaugeValue,
+				usage.IdlePower.Watts(),
+				name, zoneName,
+			)
+
+			if c.carbon {
+				ch <- prometheus.MustNewConstMetric(
+					c.aggregateCPUCO2eDesc,
+					prometheus.CounterValue,
+					usage.EmissionsTotal,
+					name, zoneName,
+				)
+			}
+
+			if c.cost {
+				ch <- prometheus.MustNewConstMetric(
+					c.aggregateCPUCostDesc,
+					prometheus.CounterValue,
+					usage.CostTotal,
+					name, zoneName,
+				)
+			}
 		}
 	}
 }
@@ -431,6 +1633,103 @@ func (c *PowerCollector) collectPodMetrics(ch chan<- prometheus.Metric, state st
 				id, pod.Name, pod.Namespace, state,
 				zoneName,
 			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.podCPUActiveWattsDesc,
+				prometheus.GaugeValue,
+				usage.ActivePower.Watts(),
+				id, pod.Name, pod.Namespace, state,
+				zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.podCPUIdleWattsDesc,
+				prometheus.GaugeValue,
+				usage.IdlePower.Watts(),
+				id, pod.Name, pod.Namespace, state,
+				zoneName,
+			)
+
+			if c.carbon {
+				ch <- prometheus.MustNewConstMetric(
+					c.podCPUCO2eDesc,
+					prometheus.CounterValue,
+					usage.EmissionsTotal,
+					id, pod.Name, pod.Namespace, state,
+					zoneName,
+				)
+			}
+
+			if c.cost {
+				ch <- prometheus.MustNewConstMetric(
+					c.podCPUCostDesc,
+					prometheus.CounterValue,
+					usage.CostTotal,
+					id, pod.Name, pod.Namespace, state,
+					zoneName,
+				)
+			}
+		}
+	}
+}
+
+// collectWorkloadMetrics collects the power of the pods of owner workloads
+func (c *PowerCollector) collectWorkloadMetrics(ch chan<- prometheus.Metric, workloads monitor.OwnerWorkloads) {
+	for _, w := range workloads {
+		ch <- prometheus.MustNewConstMetric(
+			c.workloadPodsDesc,
+			prometheus.GaugeValue,
+			float64(w.Pods),
+			w.Kind, w.Name, w.Namespace,
+		)
+
+		for zone, usage := range w.Zones {
+			zoneName := zone.Name()
+			ch <- prometheus.MustNewConstMetric(
+				c.workloadCPUJoulesDesc,
+				prometheus.CounterValue,
+				usage.EnergyTotal.Joules(),
+				w.Kind, w.Name, w.Namespace, zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.workloadCPUWattsDesc,
+				prometheus.GaugeValue,
+				usage.Power.Watts(),
+				w.Kind, w.Name, w.Namespace, zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.workloadCPUActiveWattsDesc,
+				prometheus.GaugeValue,
+				usage.ActivePower.Watts(),
+				w.Kind, w.Name, w.Namespace, zoneName,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.workloadCPUIdleWattsDesc,
+				prometheus.GaugeValue,
+				usage.IdlePower.Watts(),
+				w.Kind, w.Name, w.Namespace, zoneName,
+			)
+
+			if c.carbon {
+				ch <- prometheus.MustNewConstMetric(
+					c.workloadCPUCO2eDesc,
+					prometheus.CounterValue,
+					usage.EmissionsTotal,
+					w.Kind, w.Name, w.Namespace, zoneName,
+				)
+			}
+
+			if c.cost {
+				ch <- prometheus.MustNewConstMetric(
+					c.workloadCPUCostDesc,
+					prometheus.CounterValue,
+					usage.CostTotal,
+					w.Kind, w.Name, w.Namespace, zoneName,
+				)
+			}
 		}
 	}
 }
@@ -45,12 +45,18 @@ func TestPowerCollectorConcurrency(t *testing.T) {
 
 	assert.NoError(t, fakeMonitor.Init())
 
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	runDone := make(chan struct{})
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
+		defer close(runDone)
 		err := fakeMonitor.Run(ctx)
 		assert.NoError(t, err)
 	}()
+	// stop the monitor before the mock expectations are cleared
+	t.Cleanup(func() {
+		cancel()
+		<-runDone
+	})
 
 	t.Run("Concurrent Describe", func(t *testing.T) {
 		numGoroutines := runtime.NumCPU() * 3
@@ -176,7 +182,7 @@ func TestPowerCollectorWithRegistry(t *testing.T) {
 				defer wg.Done()
 				metrics, err := registry.Gather()
 				assert.NoError(t, err, "Gather should not return an error")
-				assert.Len(t, metrics, 7, "Expected 7 node metric families") // Updated from 5 to 7 (added separate active/idle metrics)
+				assert.Len(t, metrics, 10, "Expected 10 node metric families")
 
 				for _, mf := range metrics {
 					switch mf.GetName() {
@@ -335,12 +341,18 @@ func TestConcurrentRegistration(t *testing.T) {
 	collector := NewPowerCollector(fakeMonitor, "test-node", newLogger(), config.MetricsLevelAll)
 	assert.NoError(t, fakeMonitor.Init())
 
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	runDone := make(chan struct{})
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
+		defer close(runDone)
 		err := fakeMonitor.Run(ctx)
 		assert.NoError(t, err)
 	}()
+	// stop the monitor before the mock expectations are cleared
+	t.Cleanup(func() {
+		cancel()
+		<-runDone
+	})
 
 	// Create registries
 	registries := make([]*prometheus.Registry, numRegistries)
@@ -392,22 +404,30 @@ func TestFastCollectAndDescribe(t *testing.T) {
 
 	assert.NoError(t, fakeMonitor.Init())
 
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	runDone := make(chan struct{})
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
+		defer close(runDone)
 		err := fakeMonitor.Run(ctx)
 		assert.NoError(t, err)
 	}()
+	// stop the monitor before the mock expectations are cleared
+	t.Cleanup(func() {
+		cancel()
+		<-runDone
+	})
 
 	// Test rapid Collect calls
 	const iterations = 100
 	t.Run("Collect", func(t *testing.T) {
 		for range iterations {
 			ch := make(chan prometheus.Metric, 100)
-			collector.Collect(ch)
-			close(ch)
+			go func() {
+				collector.Collect(ch)
+				close(ch)
+			}()
 			for range ch {
-				// drain channel
+				// drain channel; there may be more metrics than it can hold
 			}
 		}
 	})
@@ -437,8 +457,10 @@ func TestFastCollectAndDescribe(t *testing.T) {
 
 			// Collect
 			collectCh := make(chan prometheus.Metric, 100)
-			collector.Collect(collectCh)
-			close(collectCh)
+			go func() {
+				collector.Collect(collectCh)
+				close(collectCh)
+			}()
 			for range collectCh {
 				// drain channel
 			}
@@ -481,22 +503,32 @@ func assertMainMetricValue(t *testing.T, mf *dto.MetricFamily, zoneName string,
 
 func callDescribe(c prometheus.Collector, wg *sync.WaitGroup) {
 	defer wg.Done()
-	ch := make(chan *prometheus.Desc, 100)
+	ch := make(chan *prometheus.Desc)
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for range ch {
+			// drain the channel
+		}
+	}()
 	c.Describe(ch)
 	close(ch)
-	for range ch {
-		// drain the channel
-	}
+	<-drained
 }
 
 func callCollect(c prometheus.Collector, wg *sync.WaitGroup) {
 	defer wg.Done()
-	ch := make(chan prometheus.Metric, 100)
+	ch := make(chan prometheus.Metric)
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for range ch {
+			// drain the channel
+		}
+	}()
 	c.Collect(ch)
 	close(ch)
-	for range ch {
-		// drain the channel
-	}
+	<-drained
 }
 
 func newLogger() *slog.Logger {
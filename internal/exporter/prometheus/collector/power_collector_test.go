@@ -16,10 +16,13 @@ import (
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/sustainable-computing-io/kepler/config"
 	"github.com/sustainable-computing-io/kepler/internal/device"
 	"github.com/sustainable-computing-io/kepler/internal/monitor"
 	"github.com/sustainable-computing-io/kepler/internal/resource"
+	"github.com/sustainable-computing-io/kepler/pkg/synthetic"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
 // MockPowerMonitor mocks the PowerMonitor for testing
@@ -51,6 +54,10 @@ func (m *MockPowerMonitor) Snapshot() (*monitor.Snapshot, error) {
 	return args.Get(0).(*monitor.Snapshot), args.Error(1)
 }
 
+func (m *MockPowerMonitor) LatestSnapshot() (*monitor.Snapshot, error) {
+	return m.Snapshot()
+}
+
 func (m *MockPowerMonitor) DataChannel() <-chan struct{} {
 	return m.dataCh
 }
@@ -194,6 +201,10 @@ func TestPowerCollector(t *testing.T) {
 	testNodeData := monitor.Node{
 		Timestamp:  time.Now(),
 		UsageRatio: 0.5,
+		CoreTypeUsageRatios: map[string]float64{
+			device.CoreTypePerformance: 0.75,
+			device.CoreTypeEfficiency:  0.25,
+		},
 		Zones: monitor.NodeZoneUsageMap{
 			packageZone: monitor.NodeUsage{
 				EnergyTotal:       nodePkgAbs,
@@ -210,6 +221,9 @@ func TestPowerCollector(t *testing.T) {
 				Power:             nodeDramPower,
 				ActivePower:       nodeDramPower / 2, // 50% of power is used
 				IdlePower:         nodeDramPower / 2, // 50% of power is idle
+				Uncertainty:       0.25,
+				Wraparounds:       3,
+				Jumps:             1,
 			},
 		},
 	}
@@ -225,6 +239,8 @@ func TestPowerCollector(t *testing.T) {
 				packageZone: {
 					EnergyTotal: 100 * device.Joule,
 					Power:       5 * device.Watt,
+					ActivePower: 4 * device.Watt,
+					IdlePower:   1 * device.Watt,
 				},
 			},
 		},
@@ -239,6 +255,8 @@ func TestPowerCollector(t *testing.T) {
 				packageZone: {
 					EnergyTotal: 100 * device.Joule,
 					Power:       5 * device.Watt,
+					ActivePower: 4 * device.Watt,
+					IdlePower:   1 * device.Watt,
 				},
 			},
 		},
@@ -253,6 +271,8 @@ func TestPowerCollector(t *testing.T) {
 				packageZone: {
 					EnergyTotal: 100 * device.Joule,
 					Power:       5 * device.Watt,
+					ActivePower: 4 * device.Watt,
+					IdlePower:   1 * device.Watt,
 				},
 			},
 		},
@@ -266,6 +286,8 @@ func TestPowerCollector(t *testing.T) {
 				packageZone: {
 					EnergyTotal: 100 * device.Joule,
 					Power:       5 * device.Watt,
+					ActivePower: 4 * device.Watt,
+					IdlePower:   1 * device.Watt,
 				},
 			},
 		},
@@ -308,23 +330,35 @@ func TestPowerCollector(t *testing.T) {
 			"kepler_node_cpu_joules_total",
 			"kepler_node_cpu_watts",
 			"kepler_node_cpu_usage_ratio",
+			"kepler_node_cpu_core_type_usage_ratio",
 			"kepler_node_cpu_active_joules_total",
 			"kepler_node_cpu_idle_joules_total",
 			"kepler_node_cpu_active_watts",
 			"kepler_node_cpu_idle_watts",
+			"kepler_node_cpu_uncertainty_ratio",
+			"kepler_node_energy_wraparounds_total",
+			"kepler_node_energy_jumps_total",
 
 			"kepler_process_cpu_joules_total",
 			"kepler_process_cpu_watts",
+			"kepler_process_cpu_active_watts",
+			"kepler_process_cpu_idle_watts",
 			"kepler_process_cpu_seconds_total",
 
 			"kepler_container_cpu_joules_total",
 			"kepler_container_cpu_watts",
+			"kepler_container_cpu_active_watts",
+			"kepler_container_cpu_idle_watts",
 
 			"kepler_vm_cpu_joules_total",
 			"kepler_vm_cpu_watts",
+			"kepler_vm_cpu_active_watts",
+			"kepler_vm_cpu_idle_watts",
 
 			"kepler_pod_cpu_joules_total",
 			"kepler_pod_cpu_watts",
+			"kepler_pod_cpu_active_watts",
+			"kepler_pod_cpu_idle_watts",
 		}
 
 		assert.ElementsMatch(t, expectedMetricNames, metricNames(metrics))
@@ -383,6 +417,44 @@ func TestPowerCollector(t *testing.T) {
 			}
 		}
 
+		// only the power of the dram zone is estimated
+		for _, metric := range metrics {
+			if metric.GetName() == "kepler_node_cpu_uncertainty_ratio" {
+				for _, m := range metric.GetMetric() {
+					expected := 0.0
+					if valueOfLabel(m, "path") == dramZone.Path() {
+						expected = 0.25
+					}
+					assert.Equal(t, expected, m.GetGauge().GetValue(), "Unexpected uncertainty")
+				}
+			}
+			if metric.GetName() == "kepler_node_energy_wraparounds_total" {
+				for _, m := range metric.GetMetric() {
+					expected := 0.0
+					if valueOfLabel(m, "path") == dramZone.Path() {
+						expected = 3
+					}
+					assert.Equal(t, expected, m.GetCounter().GetValue(), "Unexpected wraparounds")
+				}
+			}
+			if metric.GetName() == "kepler_node_energy_jumps_total" {
+				for _, m := range metric.GetMetric() {
+					expected := 0.0
+					if valueOfLabel(m, "path") == dramZone.Path() {
+						expected = 1
+					}
+					assert.Equal(t, expected, m.GetCounter().GetValue(), "Unexpected jumps")
+				}
+			}
+			if metric.GetName() == "kepler_node_cpu_core_type_usage_ratio" {
+				got := map[string]float64{}
+				for _, m := range metric.GetMetric() {
+					got[valueOfLabel(m, "core_type")] = m.GetGauge().GetValue()
+				}
+				assert.Equal(t, testNodeData.CoreTypeUsageRatios, got, "Unexpected core type usage")
+			}
+		}
+
 		// Check active/idle attribution metrics (separate metrics, no mode label)
 		for _, metric := range metrics {
 			if metric.GetName() == "kepler_node_cpu_active_watts" {
@@ -445,6 +517,8 @@ func TestPowerCollector(t *testing.T) {
 		}
 		assertMetricLabelValues(t, registry, "kepler_process_cpu_joules_total", expectedLabels, 100.0)
 		assertMetricLabelValues(t, registry, "kepler_process_cpu_watts", expectedLabels, 5.0)
+		assertMetricLabelValues(t, registry, "kepler_process_cpu_active_watts", expectedLabels, 4.0)
+		assertMetricLabelValues(t, registry, "kepler_process_cpu_idle_watts", expectedLabels, 1.0)
 	})
 
 	t.Run("Container Metrics Labels", func(t *testing.T) {
@@ -457,6 +531,8 @@ func TestPowerCollector(t *testing.T) {
 		}
 		assertMetricLabelValues(t, registry, "kepler_container_cpu_joules_total", expectedLabels, 100.0)
 		assertMetricLabelValues(t, registry, "kepler_container_cpu_watts", expectedLabels, 5.0)
+		assertMetricLabelValues(t, registry, "kepler_container_cpu_active_watts", expectedLabels, 4.0)
+		assertMetricLabelValues(t, registry, "kepler_container_cpu_idle_watts", expectedLabels, 1.0)
 	})
 
 	t.Run("VM Metrics Labels", func(t *testing.T) {
@@ -469,6 +545,8 @@ func TestPowerCollector(t *testing.T) {
 		}
 		assertMetricLabelValues(t, registry, "kepler_vm_cpu_joules_total", expectedLabels, 100.0)
 		assertMetricLabelValues(t, registry, "kepler_vm_cpu_watts", expectedLabels, 5.0)
+		assertMetricLabelValues(t, registry, "kepler_vm_cpu_active_watts", expectedLabels, 4.0)
+		assertMetricLabelValues(t, registry, "kepler_vm_cpu_idle_watts", expectedLabels, 1.0)
 	})
 
 	t.Run("Pod Metrics Labels", func(t *testing.T) {
@@ -481,6 +559,8 @@ func TestPowerCollector(t *testing.T) {
 		}
 		assertMetricLabelValues(t, registry, "kepler_pod_cpu_joules_total", expectedLabels, 100.0)
 		assertMetricLabelValues(t, registry, "kepler_pod_cpu_watts", expectedLabels, 5.0)
+		assertMetricLabelValues(t, registry, "kepler_pod_cpu_active_watts", expectedLabels, 4.0)
+		assertMetricLabelValues(t, registry, "kepler_pod_cpu_idle_watts", expectedLabels, 1.0)
 	})
 
 	// Verify mock expectations
@@ -697,17 +777,20 @@ func TestPowerCollector_MetricsLevelFiltering(t *testing.T) {
 			name:         "Only Node metrics",
 			metricsLevel: config.MetricsLevelNode,
 			expectedMetrics: map[string]bool{
-				"kepler_node_cpu_joules_total":        true,
-				"kepler_node_cpu_watts":               true,
-				"kepler_node_cpu_usage_ratio":         true,
-				"kepler_node_cpu_active_joules_total": true,
-				"kepler_node_cpu_active_watts":        true,
-				"kepler_node_cpu_idle_joules_total":   true,
-				"kepler_node_cpu_idle_watts":          true,
-				"kepler_process_cpu_joules_total":     false,
-				"kepler_container_cpu_joules_total":   false,
-				"kepler_vm_cpu_joules_total":          false,
-				"kepler_pod_cpu_joules_total":         false,
+				"kepler_node_cpu_joules_total":         true,
+				"kepler_node_cpu_watts":                true,
+				"kepler_node_cpu_usage_ratio":          true,
+				"kepler_node_cpu_active_joules_total":  true,
+				"kepler_node_cpu_active_watts":         true,
+				"kepler_node_cpu_idle_joules_total":    true,
+				"kepler_node_cpu_idle_watts":           true,
+				"kepler_node_cpu_uncertainty_ratio":    true,
+				"kepler_node_energy_wraparounds_total": true,
+				"kepler_node_energy_jumps_total":       true,
+				"kepler_process_cpu_joules_total":      false,
+				"kepler_container_cpu_joules_total":    false,
+				"kepler_vm_cpu_joules_total":           false,
+				"kepler_pod_cpu_joules_total":          false,
 			},
 		},
 		{
@@ -1055,3 +1138,848 @@ func TestTerminatedVMExport(t *testing.T) {
 
 	mockMonitor.AssertExpectations(t)
 }
+
+func TestPowerCollector_CarbonMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	packageZone := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+
+	usage := monitor.ZoneUsageMap{
+		packageZone: {
+			EnergyTotal:    100 * device.Joule,
+			Power:          5 * device.Watt,
+			EmissionsTotal: 0.5,
+		},
+	}
+	snapshot := &monitor.Snapshot{
+		Timestamp: time.Now(),
+		Node: &monitor.Node{
+			Timestamp:       time.Now(),
+			CarbonIntensity: 400,
+			Zones: monitor.NodeZoneUsageMap{
+				packageZone: monitor.NodeUsage{
+					EnergyTotal:    1000 * device.Joule,
+					EmissionsTotal: 2.5,
+				},
+			},
+		},
+		Processes: monitor.Processes{
+			"123": {PID: 123, Comm: "test-process", Exe: "/usr/bin/123", Type: resource.RegularProcess, Zones: usage},
+		},
+		Containers: monitor.Containers{
+			"abcd-efgh": {ID: "abcd-efgh", Name: "test-container", Runtime: resource.PodmanRuntime, Zones: usage},
+		},
+		VirtualMachines: monitor.VirtualMachines{
+			"vm-1": {ID: "vm-1", Name: "test-vm", Hypervisor: resource.KVMHypervisor, Zones: usage},
+		},
+		Pods: monitor.Pods{
+			"pod-1": {ID: "pod-1", Name: "test-pod", Namespace: "default", Zones: usage},
+		},
+	}
+
+	carbonMetrics := []string{
+		"kepler_node_carbon_intensity_grams_per_kwh",
+		"kepler_node_cpu_co2e_grams_total",
+		"kepler_process_cpu_co2e_grams_total",
+		"kepler_container_cpu_co2e_grams_total",
+		"kepler_vm_cpu_co2e_grams_total",
+		"kepler_pod_cpu_co2e_grams_total",
+	}
+
+	newRegistry := func(t *testing.T, opts ...PowerCollectorOption) *prometheus.Registry {
+		t.Helper()
+		mockMonitor := NewMockPowerMonitor()
+		mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelAll, opts...)
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+		return registry
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		registry := newRegistry(t)
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+
+		for _, mf := range metrics {
+			assert.NotContains(t, carbonMetrics, mf.GetName())
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		registry := newRegistry(t, WithCarbonMetrics(true))
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+
+		names := make([]string, 0, len(metrics))
+		for _, mf := range metrics {
+			names = append(names, mf.GetName())
+		}
+		for _, name := range carbonMetrics {
+			assert.Contains(t, names, name)
+		}
+
+		assertMetricLabelValues(t, registry, "kepler_node_carbon_intensity_grams_per_kwh", map[string]string{}, 400)
+		assertMetricLabelValues(t, registry, "kepler_node_cpu_co2e_grams_total",
+			map[string]string{"zone": "package"}, 2.5)
+		assertMetricLabelValues(t, registry, "kepler_process_cpu_co2e_grams_total",
+			map[string]string{"pid": "123", "state": "running", "zone": "package"}, 0.5)
+		assertMetricLabelValues(t, registry, "kepler_container_cpu_co2e_grams_total",
+			map[string]string{"container_id": "abcd-efgh", "zone": "package"}, 0.5)
+		assertMetricLabelValues(t, registry, "kepler_vm_cpu_co2e_grams_total",
+			map[string]string{"vm_id": "vm-1", "zone": "package"}, 0.5)
+		assertMetricLabelValues(t, registry, "kepler_pod_cpu_co2e_grams_total",
+			map[string]string{"pod_id": "pod-1", "zone": "package"}, 0.5)
+	})
+}
+
+func TestPowerCollector_CostMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	packageZone := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+
+	usage := monitor.ZoneUsageMap{
+		packageZone: {EnergyTotal: 100 * device.Joule, CostTotal: 0.01},
+	}
+	snapshot := &monitor.Snapshot{
+		Timestamp: time.Now(),
+		Node: &monitor.Node{
+			Timestamp: time.Now(),
+			Price:     0.25,
+			Zones: monitor.NodeZoneUsageMap{
+				packageZone: monitor.NodeUsage{EnergyTotal: 1000 * device.Joule, CostTotal: 0.05},
+			},
+		},
+		Processes: monitor.Processes{
+			"123": {PID: 123, Comm: "test-process", Exe: "/usr/bin/123", Type: resource.RegularProcess, Zones: usage},
+		},
+		Containers: monitor.Containers{
+			"abcd-efgh": {ID: "abcd-efgh", Name: "test-container", Runtime: resource.PodmanRuntime, Zones: usage},
+		},
+		VirtualMachines: monitor.VirtualMachines{
+			"vm-1": {ID: "vm-1", Name: "test-vm", Hypervisor: resource.KVMHypervisor, Zones: usage},
+		},
+		Pods: monitor.Pods{
+			"pod-1": {ID: "pod-1", Name: "test-pod", Namespace: "default", Zones: usage},
+		},
+	}
+
+	costMetrics := []string{
+		"kepler_node_electricity_price_per_kwh",
+		"kepler_node_cpu_cost_total",
+		"kepler_process_cpu_cost_total",
+		"kepler_container_cpu_cost_total",
+		"kepler_vm_cpu_cost_total",
+		"kepler_pod_cpu_cost_total",
+	}
+
+	newRegistry := func(t *testing.T, opts ...PowerCollectorOption) *prometheus.Registry {
+		t.Helper()
+		mockMonitor := NewMockPowerMonitor()
+		mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelAll, opts...)
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+		return registry
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		metrics, err := newRegistry(t).Gather()
+		assert.NoError(t, err)
+
+		for _, mf := range metrics {
+			assert.NotContains(t, costMetrics, mf.GetName())
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		registry := newRegistry(t, WithCostMetrics(true))
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+
+		names := make([]string, 0, len(metrics))
+		for _, mf := range metrics {
+			names = append(names, mf.GetName())
+		}
+		for _, name := range costMetrics {
+			assert.Contains(t, names, name)
+		}
+
+		assertMetricLabelValues(t, registry, "kepler_node_electricity_price_per_kwh", map[string]string{}, 0.25)
+		assertMetricLabelValues(t, registry, "kepler_node_cpu_cost_total",
+			map[string]string{"zone": "package"}, 0.05)
+		assertMetricLabelValues(t, registry, "kepler_process_cpu_cost_total",
+			map[string]string{"pid": "123", "state": "running", "zone": "package"}, 0.01)
+		assertMetricLabelValues(t, registry, "kepler_container_cpu_cost_total",
+			map[string]string{"container_id": "abcd-efgh", "zone": "package"}, 0.01)
+		assertMetricLabelValues(t, registry, "kepler_vm_cpu_cost_total",
+			map[string]string{"vm_id": "vm-1", "zone": "package"}, 0.01)
+		assertMetricLabelValues(t, registry, "kepler_pod_cpu_cost_total",
+			map[string]string{"pod_id": "pod-1", "zone": "package"}, 0.01)
+	})
+}
+
+func TestPowerCollector_PowerRangeMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	packageZone := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+
+	snapshot := monitor.NewSnapshot()
+	snapshot.Node.Zones[packageZone] = monitor.NodeUsage{
+		EnergyTotal: 1000 * device.Joule,
+		Power:       20 * device.Watt,
+		MinPower:    5 * device.Watt,
+		MaxPower:    80 * device.Watt,
+	}
+
+	newRegistry := func(t *testing.T, opts ...PowerCollectorOption) *prometheus.Registry {
+		t.Helper()
+		mockMonitor := NewMockPowerMonitor()
+		mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode, opts...)
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+		return registry
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		metrics, err := newRegistry(t).Gather()
+		assert.NoError(t, err)
+
+		for _, mf := range metrics {
+			assert.NotContains(t, []string{"kepler_node_cpu_min_watts", "kepler_node_cpu_max_watts"}, mf.GetName())
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		registry := newRegistry(t, WithPowerRangeMetrics(true))
+
+		assertMetricLabelValues(t, registry, "kepler_node_cpu_watts",
+			map[string]string{"zone": "package"}, 20)
+		assertMetricLabelValues(t, registry, "kepler_node_cpu_min_watts",
+			map[string]string{"zone": "package"}, 5)
+		assertMetricLabelValues(t, registry, "kepler_node_cpu_max_watts",
+			map[string]string{"zone": "package"}, 80)
+	})
+}
+
+func TestPowerCollector_ContainerInfoMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Containers = monitor.Containers{
+		"c1": {ID: "c1", Name: "nginx", Runtime: resource.ContainerDRuntime, Image: "nginx:1.27", PodID: "pod-1"},
+		"c2": {ID: "c2", Name: "shop-web-1", Runtime: resource.DockerRuntime, Image: "nginx:1.27", ComposeProject: "shop"},
+	}
+	snapshot.TerminatedContainers = monitor.Containers{
+		"c3": {ID: "c3", Name: "job", Runtime: resource.CrioRuntime, Image: "busybox"},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelContainer, WithContainerInfoMetrics(true))
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_container_info",
+		map[string]string{"container_id": "c1", "container_name": "nginx", "runtime": "containerd", "image": "nginx:1.27", "pod_id": "pod-1"}, 1)
+	assertMetricLabelValues(t, registry, "kepler_container_info",
+		map[string]string{"container_id": "c2", "container_name": "shop-web-1", "compose_project": "shop", "pod_id": ""}, 1)
+
+	metrics, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, mf := range metrics {
+		if mf.GetName() == "kepler_container_info" {
+			assert.Len(t, mf.GetMetric(), 2, "only running containers are exported")
+		}
+	}
+}
+
+func TestPowerCollector_PodInfoMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Pods = monitor.Pods{
+		"pod-1": {ID: "pod-1", Name: "web-5d8f7c9b4-x2x9z", Namespace: "default", OwnerKind: "Deployment", OwnerName: "web"},
+		"pod-2": {ID: "pod-2", Name: "bare", Namespace: "default"},
+	}
+	snapshot.TerminatedPods = monitor.Pods{
+		"pod-3": {ID: "pod-3", Name: "db-0", Namespace: "default", OwnerKind: "StatefulSet", OwnerName: "db"},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelPod, WithPodInfoMetrics(true))
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_pod_info",
+		map[string]string{"pod_id": "pod-1", "owner_kind": "Deployment", "owner_name": "web"}, 1)
+	assertMetricLabelValues(t, registry, "kepler_pod_info",
+		map[string]string{"pod_id": "pod-2", "owner_kind": "", "owner_name": ""}, 1)
+
+	metrics, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, mf := range metrics {
+		if mf.GetName() == "kepler_pod_info" {
+			assert.Len(t, mf.GetMetric(), 2, "only running pods are exported")
+		}
+	}
+}
+
+func TestPowerCollector_WorkloadMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	pkg := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.OwnerWorkloads = monitor.OwnerWorkloads{
+		"prod/Deployment/web": {Kind: "Deployment", Name: "web", Namespace: "prod", Pods: 3, Zones: monitor.ZoneUsageMap{
+			pkg: {EnergyTotal: 120 * device.Joule, Power: 12 * device.Watt, ActivePower: 9 * device.Watt, IdlePower: 3 * device.Watt},
+		}},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelPod)
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	labels := map[string]string{"workload_kind": "Deployment", "workload_name": "web", "workload_namespace": "prod", "zone": "package"}
+	assertMetricLabelValues(t, registry, "kepler_workload_cpu_joules_total", labels, 120)
+	assertMetricLabelValues(t, registry, "kepler_workload_cpu_watts", labels, 12)
+	assertMetricLabelValues(t, registry, "kepler_workload_cpu_active_watts", labels, 9)
+	assertMetricLabelValues(t, registry, "kepler_workload_cpu_idle_watts", labels, 3)
+	assertMetricLabelValues(t, registry, "kepler_workload_pods",
+		map[string]string{"workload_kind": "Deployment", "workload_name": "web", "workload_namespace": "prod"}, 3)
+}
+
+func TestPowerCollector_ZoneSourceMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	pkg := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	gpu := device.NewMockRaplZone("gpu", 0, "", 1000*device.Joule)
+	platform := device.NewMockRaplZone("platform", 0, "", 1000*device.Joule)
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Node = &monitor.Node{Zones: monitor.NodeZoneUsageMap{
+		pkg:      {Power: 40 * device.Watt},
+		gpu:      {Power: 150 * device.Watt},
+		platform: {Power: 250 * device.Watt},
+	}}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	sources := map[monitor.EnergyZone]string{pkg: "rapl", gpu: monitor.ZoneSourceGPU, platform: monitor.ZoneSourcePlatform}
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode,
+		WithZoneSources(func(zone monitor.EnergyZone) string { return sources[zone] }))
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_node_watts", map[string]string{"source": "rapl", "zone": "package"}, 40)
+	assertMetricLabelValues(t, registry, "kepler_node_watts", map[string]string{"source": "gpu", "zone": "gpu"}, 150)
+	assertMetricLabelValues(t, registry, "kepler_node_watts", map[string]string{"source": "platform", "zone": "platform"}, 250)
+}
+
+func TestPowerCollector_GPUZoneMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	pkg := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	gpu := device.NewMockRaplZone("nvidia-gpu-0", 0, "nvml:GPU-1", 1000*device.Joule)
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Node = &monitor.Node{Zones: monitor.NodeZoneUsageMap{
+		pkg: {EnergyTotal: 400 * device.Joule, Power: 40 * device.Watt, ActivePower: 30 * device.Watt, IdlePower: 10 * device.Watt},
+		gpu: {EnergyTotal: 1500 * device.Joule, Power: 150 * device.Watt, ActivePower: 100 * device.Watt, IdlePower: 50 * device.Watt},
+	}}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode,
+		WithGPUZones(func(zone monitor.EnergyZone) bool { return zone == gpu }))
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	cpuLabels := map[string]string{"zone": "package"}
+	assertMetricLabelValues(t, registry, "kepler_node_cpu_joules_total", cpuLabels, 400)
+	assertMetricLabelValues(t, registry, "kepler_node_cpu_watts", cpuLabels, 40)
+
+	gpuLabels := map[string]string{"zone": "nvidia-gpu-0", "path": "nvml:GPU-1"}
+	assertMetricLabelValues(t, registry, "kepler_node_gpu_joules_total", gpuLabels, 1500)
+	assertMetricLabelValues(t, registry, "kepler_node_gpu_watts", gpuLabels, 150)
+	assertMetricLabelValues(t, registry, "kepler_node_gpu_active_watts", gpuLabels, 100)
+	assertMetricLabelValues(t, registry, "kepler_node_gpu_idle_watts", gpuLabels, 50)
+
+	metrics, err := registry.Gather()
+	require.NoError(t, err)
+	for _, mf := range metrics {
+		if !strings.HasPrefix(mf.GetName(), "kepler_node_cpu_") {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			assert.NotEqual(t, "nvidia-gpu-0", valueOfLabel(m, "zone"), "GPU zone exported as %s", mf.GetName())
+		}
+	}
+}
+
+func TestPowerCollector_CalibrationMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	pkg := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Node = &monitor.Node{Zones: monitor.NodeZoneUsageMap{
+		pkg: {Power: 24 * device.Watt, RawPower: 20 * device.Watt},
+	}}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode, WithCalibrationMetrics(true))
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	labels := map[string]string{"zone": "package"}
+	assertMetricLabelValues(t, registry, "kepler_node_cpu_watts", labels, 24)
+	assertMetricLabelValues(t, registry, "kepler_node_cpu_raw_watts", labels, 20)
+}
+
+func TestPowerCollector_ProcessInfoMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Processes = monitor.Processes{
+		"42": {PID: 42, Comm: "nginx", CmdLine: "nginx -g daemon off;", UID: 33, User: "www-data", ParentPID: 1},
+		"43": {PID: 43, Comm: "worker", UID: 2000, ParentPID: 42},
+	}
+	snapshot.TerminatedProcesses = monitor.Processes{
+		"44": {PID: 44, Comm: "sh", CmdLine: "sh -c true", ParentPID: 1},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelProcess, WithProcessInfoMetrics(true))
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_process_info",
+		map[string]string{"pid": "42", "cmdline": "nginx -g daemon off;", "uid": "33", "user": "www-data", "ppid": "1"}, 1)
+	assertMetricLabelValues(t, registry, "kepler_process_info",
+		map[string]string{"pid": "43", "comm": "worker", "uid": "2000", "user": "", "ppid": "42"}, 1)
+
+	metrics, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, mf := range metrics {
+		if mf.GetName() == "kepler_process_info" {
+			assert.Len(t, mf.GetMetric(), 2, "only running processes are exported")
+		}
+	}
+}
+
+func TestPowerCollector_HWCounterMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Processes = monitor.Processes{
+		"42": {PID: 42, Comm: "nginx", HWCounters: monitor.HWCounters{Instructions: 3000, Cycles: 2000, CacheMisses: 10}},
+	}
+	snapshot.TerminatedProcesses = monitor.Processes{
+		"44": {PID: 44, Comm: "sh", HWCounters: monitor.HWCounters{Instructions: 500, Cycles: 400, CacheMisses: 1}},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	t.Run("enabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelProcess, WithHWCounterMetrics(true))
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		assertMetricLabelValues(t, registry, "kepler_process_cpu_instructions_total",
+			map[string]string{"pid": "42", "comm": "nginx"}, 3000)
+		assertMetricLabelValues(t, registry, "kepler_process_cpu_cycles_total",
+			map[string]string{"pid": "42", "comm": "nginx"}, 2000)
+		assertMetricLabelValues(t, registry, "kepler_process_cpu_cache_misses_total",
+			map[string]string{"pid": "42", "comm": "nginx"}, 10)
+		assertMetricLabelValues(t, registry, "kepler_process_cpu_instructions_total",
+			map[string]string{"pid": "44", "comm": "sh"}, 500)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelProcess)
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		for _, mf := range metrics {
+			assert.NotEqual(t, "kepler_process_cpu_instructions_total", mf.GetName())
+		}
+	})
+}
+
+func TestPowerCollector_IOMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Processes = monitor.Processes{
+		"42": {PID: 42, Comm: "nginx", IO: monitor.IOCounters{DiskReadBytes: 4096, DiskWriteBytes: 8192, NetRxBytes: 1000, NetTxBytes: 5000}},
+	}
+	snapshot.TerminatedProcesses = monitor.Processes{
+		"44": {PID: 44, Comm: "curl", IO: monitor.IOCounters{NetRxBytes: 300}},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	t.Run("enabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelProcess, WithIOMetrics(true))
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		assertMetricLabelValues(t, registry, "kepler_process_disk_read_bytes_total",
+			map[string]string{"pid": "42", "comm": "nginx"}, 4096)
+		assertMetricLabelValues(t, registry, "kepler_process_disk_written_bytes_total",
+			map[string]string{"pid": "42", "comm": "nginx"}, 8192)
+		assertMetricLabelValues(t, registry, "kepler_process_network_receive_bytes_total",
+			map[string]string{"pid": "42", "comm": "nginx"}, 1000)
+		assertMetricLabelValues(t, registry, "kepler_process_network_transmit_bytes_total",
+			map[string]string{"pid": "42", "comm": "nginx"}, 5000)
+		assertMetricLabelValues(t, registry, "kepler_process_network_receive_bytes_total",
+			map[string]string{"pid": "44", "comm": "curl"}, 300)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelProcess)
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		for _, mf := range metrics {
+			assert.NotEqual(t, "kepler_process_network_receive_bytes_total", mf.GetName())
+		}
+	})
+}
+
+func TestPowerCollector_SystemdUnitMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	packageZone := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.SystemdUnits = monitor.SystemdUnits{
+		"sshd.service": {
+			Name:  "sshd.service",
+			Slice: "system.slice",
+			Zones: monitor.ZoneUsageMap{
+				packageZone: {EnergyTotal: 100 * device.Joule, Power: 5 * device.Watt, ActivePower: 4 * device.Watt, IdlePower: 1 * device.Watt},
+			},
+		},
+	}
+	snapshot.TerminatedSystemdUnits = monitor.SystemdUnits{
+		"cron.service": {
+			Name:  "cron.service",
+			Slice: "system.slice",
+			Zones: monitor.ZoneUsageMap{
+				packageZone: {EnergyTotal: 50 * device.Joule},
+			},
+		},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	t.Run("enabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode, WithSystemdUnitMetrics(true))
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		assertMetricLabelValues(t, registry, "kepler_systemd_unit_cpu_joules_total",
+			map[string]string{"unit_name": "sshd.service", "slice": "system.slice", "state": "running", "zone": "package"}, 100)
+		assertMetricLabelValues(t, registry, "kepler_systemd_unit_cpu_watts",
+			map[string]string{"unit_name": "sshd.service", "state": "running", "zone": "package"}, 5)
+		assertMetricLabelValues(t, registry, "kepler_systemd_unit_cpu_active_watts",
+			map[string]string{"unit_name": "sshd.service", "zone": "package"}, 4)
+		assertMetricLabelValues(t, registry, "kepler_systemd_unit_cpu_idle_watts",
+			map[string]string{"unit_name": "sshd.service", "zone": "package"}, 1)
+		assertMetricLabelValues(t, registry, "kepler_systemd_unit_cpu_joules_total",
+			map[string]string{"unit_name": "cron.service", "state": "terminated", "zone": "package"}, 50)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode)
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		for _, mf := range metrics {
+			assert.NotContains(t, mf.GetName(), "kepler_systemd_unit_")
+		}
+	})
+}
+
+func TestPowerCollector_GroupMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	packageZone := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Groups = monitor.Groups{
+		"session": {
+			Name:   "session",
+			Cgroup: "/user.slice/user-1000.slice/session-3.scope",
+			Zones: monitor.ZoneUsageMap{
+				packageZone: {EnergyTotal: 100 * device.Joule, Power: 5 * device.Watt, ActivePower: 4 * device.Watt, IdlePower: 1 * device.Watt},
+			},
+		},
+	}
+	snapshot.TerminatedGroups = monitor.Groups{
+		"build": {
+			Name: "build",
+			PID:  42,
+			Zones: monitor.ZoneUsageMap{
+				packageZone: {EnergyTotal: 50 * device.Joule},
+			},
+		},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	t.Run("enabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode, WithGroupMetrics(true))
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		assertMetricLabelValues(t, registry, "kepler_group_cpu_joules_total",
+			map[string]string{"group_name": "session", "pid": "", "cgroup": "/user.slice/user-1000.slice/session-3.scope", "state": "running", "zone": "package"}, 100)
+		assertMetricLabelValues(t, registry, "kepler_group_cpu_watts",
+			map[string]string{"group_name": "session", "state": "running", "zone": "package"}, 5)
+		assertMetricLabelValues(t, registry, "kepler_group_cpu_active_watts",
+			map[string]string{"group_name": "session", "zone": "package"}, 4)
+		assertMetricLabelValues(t, registry, "kepler_group_cpu_idle_watts",
+			map[string]string{"group_name": "session", "zone": "package"}, 1)
+		assertMetricLabelValues(t, registry, "kepler_group_cpu_joules_total",
+			map[string]string{"group_name": "build", "pid": "42", "cgroup": "", "state": "terminated", "zone": "package"}, 50)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode)
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		for _, mf := range metrics {
+			assert.NotContains(t, mf.GetName(), "kepler_group_")
+		}
+	})
+}
+
+func TestPowerCollector_AggregateMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	packageZone := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Aggregates = monitor.Aggregates{
+		"kernel": {
+			Name: "kernel",
+			Zones: monitor.ZoneUsageMap{
+				packageZone: {EnergyTotal: 30 * device.Joule, Power: 3 * device.Watt, ActivePower: 3 * device.Watt},
+			},
+		},
+		"system": {
+			Name: "system",
+			Zones: monitor.ZoneUsageMap{
+				packageZone: {EnergyTotal: 20 * device.Joule, Power: 2 * device.Watt, ActivePower: 2 * device.Watt},
+			},
+		},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	t.Run("enabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode, WithAggregateMetrics(true))
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		assertMetricLabelValues(t, registry, "kepler_aggregate_cpu_joules_total",
+			map[string]string{"aggregate": "kernel", "zone": "package"}, 30)
+		assertMetricLabelValues(t, registry, "kepler_aggregate_cpu_watts",
+			map[string]string{"aggregate": "system", "zone": "package"}, 2)
+		assertMetricLabelValues(t, registry, "kepler_aggregate_cpu_active_watts",
+			map[string]string{"aggregate": "kernel", "zone": "package"}, 3)
+		assertMetricLabelValues(t, registry, "kepler_aggregate_cpu_idle_watts",
+			map[string]string{"aggregate": "system", "zone": "package"}, 0)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode)
+		mockMonitor.TriggerUpdate()
+		assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		for _, mf := range metrics {
+			assert.NotContains(t, mf.GetName(), "kepler_aggregate_")
+		}
+	})
+}
+
+func TestPowerCollector_BudgetMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	snapshot.Budgets = []monitor.BudgetStatus{
+		{Scope: monitor.BudgetScopeNode, Budget: 1000 * device.Joule, Consumed: 400 * device.Joule},
+		{Scope: monitor.BudgetScopeNamespace, Name: "prod", Budget: 100 * device.Joule, Consumed: 150 * device.Joule},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode, WithBudgetMetrics(true))
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_budget_remaining_joules",
+		map[string]string{"scope": "node", "name": ""}, 600)
+	assertMetricLabelValues(t, registry, "kepler_budget_consumed_joules",
+		map[string]string{"scope": "node", "name": ""}, 400)
+	assertMetricLabelValues(t, registry, "kepler_budget_remaining_joules",
+		map[string]string{"scope": "namespace", "name": "prod"}, 0)
+	assertMetricLabelValues(t, registry, "kepler_budget_consumed_joules",
+		map[string]string{"scope": "namespace", "name": "prod"}, 150)
+}
+
+func TestPowerCollector_SourceMetrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	mockMonitor := NewMockPowerMonitor()
+	snapshot := monitor.NewSnapshot()
+	psys := device.NewMockRaplZone("psys", 0, "/sys/class/powercap/intel-rapl/intel-rapl:1", 1000)
+	snapshot.Sources = []monitor.Source{
+		{Kind: monitor.SourceZone, Zone: psys, Err: device.ErrPermission},
+		{Kind: monitor.SourceGPU, Name: "nvidia", Available: true},
+	}
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelNode)
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_source_available",
+		map[string]string{"kind": "zone", "source": "psys", "path": "/sys/class/powercap/intel-rapl/intel-rapl:1"}, 0)
+	assertMetricLabelValues(t, registry, "kepler_source_available",
+		map[string]string{"kind": "gpu", "source": "nvidia", "path": ""}, 1)
+}
+
+// BenchmarkPowerCollector measures collecting the metrics of a snapshot of
+// nodes running many synthetic workloads
+func BenchmarkPowerCollector(b *testing.B) {
+	for _, tc := range []struct {
+		processes, containers int
+	}{
+		{1000, 100},
+		{10000, 1000},
+	} {
+		b.Run(fmt.Sprintf("processes=%d/containers=%d", tc.processes, tc.containers), func(b *testing.B) {
+			fakeClock := testingclock.NewFakeClock(time.Now())
+			opts := []synthetic.OptionFn{
+				synthetic.WithClock(fakeClock),
+				synthetic.WithProcesses(tc.processes),
+				synthetic.WithContainers(tc.containers),
+			}
+			pm := monitor.NewPowerMonitor(synthetic.NewCPUMeter(opts...),
+				monitor.WithLogger(newLogger()),
+				monitor.WithClock(fakeClock),
+				monitor.WithResourceInformer(synthetic.NewInformer(opts...)),
+			)
+			require.NoError(b, pm.Init())
+			collector := NewPowerCollector(pm, "test-node", newLogger(), config.MetricsLevelAll)
+
+			// the first snapshot is computed on demand; the second one has power
+			_, err := pm.Snapshot()
+			require.NoError(b, err)
+			fakeClock.Step(5 * time.Second)
+			_, err = pm.Snapshot()
+			require.NoError(b, err)
+			require.Eventually(b, collector.isReady, 5*time.Second, time.Millisecond)
+
+			ch := make(chan prometheus.Metric, 1024)
+			collected := make(chan int)
+			go func() {
+				n := 0
+				for range ch {
+					n++
+				}
+				collected <- n
+			}()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				collector.Collect(ch)
+			}
+			b.StopTimer()
+			close(ch)
+			b.ReportMetric(float64(<-collected)/float64(b.N), "metrics/op")
+		})
+	}
+}
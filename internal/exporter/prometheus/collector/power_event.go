@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/sustainable-computing-io/kepler/internal/powerevent"
+)
+
+// powerEventCollector collects the number of power events logged by the BMC
+// of the node, e.g. failed power supplies or brownouts
+type powerEventCollector struct {
+	counts func() []powerevent.Count
+
+	eventsDesc *prom.Desc
+}
+
+// NewPowerEventCollector creates a collector of the number of power events
+// returned by counts
+func NewPowerEventCollector(counts func() []powerevent.Count, nodeName string) *powerEventCollector {
+	return &powerEventCollector{
+		counts: counts,
+		eventsDesc: prom.NewDesc(
+			prom.BuildFQName(keplerNS, "platform", "power_events_total"),
+			"Number of power events of the kind and severity logged by the BMC since Kepler started",
+			[]string{"kind", "severity"}, prom.Labels{nodeNameLabel: nodeName},
+		),
+	}
+}
+
+func (c *powerEventCollector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.eventsDesc
+}
+
+func (c *powerEventCollector) Collect(ch chan<- prom.Metric) {
+	for _, count := range c.counts() {
+		ch <- prom.MustNewConstMetric(c.eventsDesc, prom.CounterValue, float64(count.Total), count.Kind, count.Severity)
+	}
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/powerevent"
+)
+
+func TestPowerEventCollector(t *testing.T) {
+	counts := []powerevent.Count{
+		{Kind: powerevent.KindPSUFailure, Severity: "Critical", Total: 1},
+		{Kind: powerevent.KindBrownout, Severity: "Warning", Total: 3},
+	}
+	collector := NewPowerEventCollector(func() []powerevent.Count { return counts }, "test-node")
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	assertMetricLabelValues(t, registry, "kepler_platform_power_events_total",
+		map[string]string{"kind": "psu-failure", "severity": "Critical", "node_name": "test-node"}, 1)
+	assertMetricLabelValues(t, registry, "kepler_platform_power_events_total",
+		map[string]string{"kind": "brownout", "severity": "Warning"}, 3)
+	assert.Equal(t, 2, testutil.CollectAndCount(collector))
+}
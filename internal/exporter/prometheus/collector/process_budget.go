@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+// processBudget limits the running processes exported to the max processes
+// using the most power, bounding the number of process series. The other
+// processes are exported as a single series whose energy and CPU time are the
+// sum of what they used while they were not exported, so that its counters
+// never decrease as processes move in and out of the top processes. The other
+// processes include the monitor.OtherWorkload process of filtered out
+// processes, if any.
+type processBudget struct {
+	max int
+
+	mu sync.Mutex
+	// last holds the energy and CPU time of the running processes at the
+	// last collection, by PID
+	last map[string]processTotals
+	// other holds the cumulative energy and CPU time of the other processes;
+	// nil until a process was not exported
+	other *processTotals
+}
+
+// processTotals is the energy, by zone name, and CPU time used by processes
+type processTotals struct {
+	energy  map[string]monitor.Energy
+	cpuTime float64
+}
+
+// otherUsage is the usage of the processes that are not exported
+type otherUsage struct {
+	processTotals
+	power       map[string]monitor.Power // by zone name
+	activePower map[string]monitor.Power
+	idlePower   map[string]monitor.Power
+}
+
+func newProcessBudget(maxProcesses int) *processBudget {
+	return &processBudget{max: maxProcesses, last: map[string]processTotals{}}
+}
+
+// split returns the running processes to export and the usage of the others;
+// the usage is nil if every process has been exported so far and no process is
+// filtered out
+func (b *processBudget) split(processes monitor.Processes) (monitor.Processes, *otherUsage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pids := make([]string, 0, len(processes))
+	for pid := range processes {
+		if pid == monitor.OtherWorkload {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+	power := func(pid string) float64 {
+		total := 0.0
+		for _, usage := range processes[pid].Zones {
+			total += usage.Power.Watts()
+		}
+		return total
+	}
+	slices.SortFunc(pids, func(x, y string) int {
+		return cmp.Or(cmp.Compare(power(y), power(x)), cmp.Compare(x, y))
+	})
+
+	top := make(monitor.Processes, min(len(pids), b.max))
+	last := make(map[string]processTotals, len(processes))
+	var other *otherUsage
+	for i, pid := range pids {
+		proc := processes[pid]
+		totals := processTotals{energy: make(map[string]monitor.Energy, len(proc.Zones)), cpuTime: proc.CPUTotalTime}
+		for zone, usage := range proc.Zones {
+			totals.energy[zone.Name()] = usage.EnergyTotal
+		}
+		prev := b.last[pid]
+		last[pid] = totals
+
+		if i < b.max {
+			top[pid] = proc
+			continue
+		}
+
+		if b.other == nil {
+			b.other = &processTotals{energy: map[string]monitor.Energy{}}
+		}
+		if other == nil {
+			other = newOtherUsage()
+		}
+		// the PID is reused if the totals decreased
+		b.other.cpuTime += delta(totals.cpuTime, prev.cpuTime)
+		for zone, usage := range proc.Zones {
+			name := zone.Name()
+			b.other.energy[name] += monitor.Energy(delta(float64(totals.energy[name]), float64(prev.energy[name])))
+			other.power[name] += usage.Power
+			other.activePower[name] += usage.ActivePower
+			other.idlePower[name] += usage.IdlePower
+		}
+	}
+	b.last = last
+
+	filtered := processes[monitor.OtherWorkload]
+	if b.other == nil && filtered == nil {
+		return top, nil
+	}
+	// the series of the other processes is exported from then on so that its
+	// counters are continuous
+	if other == nil {
+		other = newOtherUsage()
+	}
+	other.energy = map[string]monitor.Energy{}
+	if b.other != nil {
+		other.cpuTime = b.other.cpuTime
+		for name, energy := range b.other.energy {
+			other.energy[name] = energy
+		}
+	}
+	// the counters of the filtered out processes never decrease either
+	if filtered != nil {
+		other.cpuTime += filtered.CPUTotalTime
+		for zone, usage := range filtered.Zones {
+			name := zone.Name()
+			other.energy[name] += usage.EnergyTotal
+			other.power[name] += usage.Power
+			other.activePower[name] += usage.ActivePower
+			other.idlePower[name] += usage.IdlePower
+		}
+	}
+	return top, other
+}
+
+func newOtherUsage() *otherUsage {
+	return &otherUsage{
+		power:       map[string]monitor.Power{},
+		activePower: map[string]monitor.Power{},
+		idlePower:   map[string]monitor.Power{},
+	}
+}
+
+// delta returns the increase of a counter; the current value if it decreased
+func delta(current, previous float64) float64 {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
+
+// collectOtherProcesses collects the metrics of the running processes that are
+// not exported as a single series with pid and comm monitor.OtherWorkload
+func (c *PowerCollector) collectOtherProcesses(ch chan<- prometheus.Metric, other *otherUsage) {
+	ch <- prometheus.MustNewConstMetric(
+		c.processCPUTimeDescriptor,
+		prometheus.CounterValue,
+		other.cpuTime,
+		monitor.OtherWorkload, monitor.OtherWorkload, "", "", "", "",
+	)
+
+	for zoneName, energy := range other.energy {
+		ch <- prometheus.MustNewConstMetric(
+			c.processCPUJoulesDescriptor,
+			prometheus.CounterValue,
+			energy.Joules(),
+			monitor.OtherWorkload, monitor.OtherWorkload, "", "", "running", "", "",
+			zoneName,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.processCPUWattsDescriptor,
+			prometheus.GaugeValue,
+			other.power[zoneName].Watts(),
+			monitor.OtherWorkload, monitor.OtherWorkload, "", "", "running", "", "",
+			zoneName,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.processCPUActiveWattsDesc,
+			prometheus.GaugeValue,
+			other.activePower[zoneName].Watts(),
+			monitor.OtherWorkload, monitor.OtherWorkload, "", "", "running", "", "",
+			zoneName,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.processCPUIdleWattsDesc,
+			prometheus.GaugeValue,
+			other.idlePower[zoneName].Watts(),
+			monitor.OtherWorkload, monitor.OtherWorkload, "", "", "running", "", "",
+			zoneName,
+		)
+	}
+}
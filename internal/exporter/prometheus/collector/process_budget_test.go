@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+func TestProcessBudget(t *testing.T) {
+	pkg := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+	process := func(pid int, watts, joules, cpuTime float64) *monitor.Process {
+		return &monitor.Process{
+			PID:          pid,
+			CPUTotalTime: cpuTime,
+			Zones: monitor.ZoneUsageMap{
+				pkg: {Power: monitor.Power(watts) * device.Watt, EnergyTotal: monitor.Energy(joules) * device.Joule},
+			},
+		}
+	}
+
+	b := newProcessBudget(2)
+
+	t.Run("within budget", func(t *testing.T) {
+		top, other := b.split(monitor.Processes{
+			"1": process(1, 10, 100, 10),
+			"2": process(2, 5, 50, 5),
+		})
+		assert.Len(t, top, 2)
+		assert.Nil(t, other, "all processes are exported")
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		top, other := b.split(monitor.Processes{
+			"1": process(1, 10, 120, 12),
+			"2": process(2, 1, 55, 6),
+			"3": process(3, 20, 20, 1),
+		})
+		assert.ElementsMatch(t, []string{"1", "3"}, keys(top), "processes using the most power are exported")
+		require.NotNil(t, other)
+		assert.Equal(t, 1*device.Watt, other.power["package"])
+		// only the energy used since process 2 was last exported
+		assert.Equal(t, 5*device.Joule, other.energy["package"])
+		assert.Equal(t, 1.0, other.cpuTime)
+	})
+
+	t.Run("counters of other processes don't decrease", func(t *testing.T) {
+		// process 2 is exported again and process 1 exits
+		top, other := b.split(monitor.Processes{
+			"2": process(2, 30, 60, 7),
+			"3": process(3, 20, 30, 2),
+		})
+		assert.ElementsMatch(t, []string{"2", "3"}, keys(top))
+		require.NotNil(t, other, "other processes are exported once they were")
+		assert.Equal(t, 5*device.Joule, other.energy["package"])
+		assert.Equal(t, monitor.Power(0), other.power["package"])
+
+		top, other = b.split(monitor.Processes{
+			"2": process(2, 30, 70, 8),
+			"3": process(3, 20, 40, 3),
+			"4": process(4, 2, 4, 0.5),
+		})
+		assert.ElementsMatch(t, []string{"2", "3"}, keys(top))
+		assert.Equal(t, 9*device.Joule, other.energy["package"])
+		assert.Equal(t, 1.5, other.cpuTime)
+	})
+
+	t.Run("filtered out processes", func(t *testing.T) {
+		b := newProcessBudget(2)
+		top, other := b.split(monitor.Processes{
+			"1":                   process(1, 10, 100, 10),
+			monitor.OtherWorkload: process(0, 50, 500, 50),
+		})
+		assert.ElementsMatch(t, []string{"1"}, keys(top), "filtered out processes are not ranked")
+		require.NotNil(t, other)
+		assert.Equal(t, 50*device.Watt, other.power["package"])
+		assert.Equal(t, 500*device.Joule, other.energy["package"])
+
+		top, other = b.split(monitor.Processes{
+			"1":                   process(1, 10, 110, 11),
+			"2":                   process(2, 5, 50, 5),
+			"3":                   process(3, 1, 10, 1),
+			monitor.OtherWorkload: process(0, 50, 550, 55),
+		})
+		assert.ElementsMatch(t, []string{"1", "2"}, keys(top))
+		assert.Equal(t, 51*device.Watt, other.power["package"])
+		assert.Equal(t, 560*device.Joule, other.energy["package"], "the energy of the filtered out processes adds to that of the others")
+		assert.Equal(t, 56.0, other.cpuTime)
+	})
+}
+
+func keys(processes monitor.Processes) []string {
+	ret := make([]string, 0, len(processes))
+	for pid := range processes {
+		ret = append(ret, pid)
+	}
+	return ret
+}
+
+func TestPowerCollector_MaxProcesses(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	pkg := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000)
+
+	processes := monitor.Processes{}
+	for pid, watts := range map[string]monitor.Power{"1": 3, "2": 2, "3": 1} {
+		processes[pid] = &monitor.Process{
+			Comm: "proc-" + pid,
+			Zones: monitor.ZoneUsageMap{
+				pkg: {Power: watts * device.Watt, EnergyTotal: 10 * device.Joule},
+			},
+		}
+	}
+	snapshot := &monitor.Snapshot{
+		Timestamp: time.Now(),
+		Node:      &monitor.Node{Timestamp: time.Now(), Zones: monitor.NodeZoneUsageMap{}},
+		Processes: processes,
+	}
+
+	mockMonitor := NewMockPowerMonitor()
+	mockMonitor.On("Snapshot").Return(snapshot, nil)
+	collector := NewPowerCollector(mockMonitor, "test-node", logger, config.MetricsLevelProcess, WithMaxProcesses(2))
+	mockMonitor.TriggerUpdate()
+	assert.Eventually(t, collector.isReady, time.Second, 5*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	metrics, err := registry.Gather()
+	require.NoError(t, err)
+	pids := []string{}
+	for _, mf := range metrics {
+		if mf.GetName() != "kepler_process_cpu_watts" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			pids = append(pids, valueOfLabel(m, "pid"))
+		}
+	}
+	assert.ElementsMatch(t, []string{"1", "2", monitor.OtherWorkload}, pids)
+
+	assertMetricLabelValues(t, registry, "kepler_process_cpu_watts",
+		map[string]string{"pid": monitor.OtherWorkload, "comm": monitor.OtherWorkload, "state": "running", "zone": "package"}, 1)
+	assertMetricLabelValues(t, registry, "kepler_process_cpu_joules_total",
+		map[string]string{"pid": monitor.OtherWorkload, "zone": "package"}, 10)
+}
@@ -4,22 +4,29 @@
 package prometheus
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 
 	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/anomaly"
+	"github.com/sustainable-computing-io/kepler/internal/device"
 	collector "github.com/sustainable-computing-io/kepler/internal/exporter/prometheus/collector"
 	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/powerevent"
 	"github.com/sustainable-computing-io/kepler/internal/service"
 )
 
 type (
-	Initializer = service.Initializer
-	Monitor     = monitor.Service
+	Initializer   = service.Initializer
+	Dependent     = service.Dependent
+	HealthChecker = service.HealthChecker
+	Monitor       = monitor.Service
 )
 
 type APIRegistry interface {
@@ -31,8 +38,28 @@ type Opts struct {
 	debugCollectors map[string]bool
 	collectors      map[string]prom.Collector
 	procfs          string
+	sysfs           string
 	nodeName        string
 	metricsLevel    config.Level
+	carbon          bool
+	cost            bool
+	powerRange      bool
+	calibration     bool
+	hwCounters      bool
+	io              bool
+	processInfo     bool
+	containerInfo   bool
+	podInfo         bool
+	systemdUnits    bool
+	groups          bool
+	aggregates      bool
+	budgets         bool
+	maxProcesses    int
+	platformSources func() []device.PlatformSourceStatus
+	anomalies       func() []anomaly.Series
+	powerEvents     func() []powerevent.Count
+	zoneSources     func(monitor.EnergyZone) string
+	gpuZones        func(monitor.EnergyZone) bool
 }
 
 // DefaultOpts() returns a new Opts with defaults set
@@ -76,6 +103,12 @@ func WithProcFSPath(procfs string) OptionFn {
 	}
 }
 
+func WithSysFSPath(sysfs string) OptionFn {
+	return func(o *Opts) {
+		o.sysfs = sysfs
+	}
+}
+
 func WithCollectors(c map[string]prom.Collector) OptionFn {
 	return func(o *Opts) {
 		o.collectors = c
@@ -94,6 +127,148 @@ func WithMetricsLevel(level config.Level) OptionFn {
 	}
 }
 
+// WithCarbon enables the export of carbon emission metrics
+func WithCarbon(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.carbon = enabled
+	}
+}
+
+// WithCost enables the export of energy cost metrics
+func WithCost(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.cost = enabled
+	}
+}
+
+// WithPowerRange enables the export of the min and max node power within the
+// monitor interval
+func WithPowerRange(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.powerRange = enabled
+	}
+}
+
+// WithCalibration enables the export of the power of the node zones before
+// calibration
+func WithCalibration(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.calibration = enabled
+	}
+}
+
+// WithHWCounters enables the export of the hardware events counted for processes
+func WithHWCounters(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.hwCounters = enabled
+	}
+}
+
+// WithIO enables the export of the disk and network bytes of processes
+func WithIO(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.io = enabled
+	}
+}
+
+// WithProcessInfo enables the export of the command line, user and parent of processes
+func WithProcessInfo(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.processInfo = enabled
+	}
+}
+
+// WithContainerInfo enables the export of the image and compose project of containers
+func WithContainerInfo(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.containerInfo = enabled
+	}
+}
+
+// WithPodInfo enables the export of the owner workload of pods
+func WithPodInfo(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.podInfo = enabled
+	}
+}
+
+// WithSystemdUnits enables the export of the power of systemd units
+func WithSystemdUnits(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.systemdUnits = enabled
+	}
+}
+
+// WithGroups enables the export of the power of groups of processes
+func WithGroups(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.groups = enabled
+	}
+}
+
+// WithAggregates enables the export of the power of the kernel and system
+// aggregates
+func WithAggregates(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.aggregates = enabled
+	}
+}
+
+// WithBudgets enables the export of energy budget metrics
+func WithBudgets(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.budgets = enabled
+	}
+}
+
+// WithMaxProcesses limits the running processes exported to the n using the
+// most power; 0 exports all processes
+func WithMaxProcesses(n int) OptionFn {
+	return func(o *Opts) {
+		o.maxProcesses = n
+	}
+}
+
+// WithPlatformSources enables the export of the status of the sources of the
+// power of the platform returned by sources; nil disables it
+func WithPlatformSources(sources func() []device.PlatformSourceStatus) OptionFn {
+	return func(o *Opts) {
+		o.platformSources = sources
+	}
+}
+
+// WithAnomalies enables the export of whether the power of the series returned
+// by series jumped abnormally; nil disables it
+func WithAnomalies(series func() []anomaly.Series) OptionFn {
+	return func(o *Opts) {
+		o.anomalies = series
+	}
+}
+
+// WithPowerEvents enables the export of the number of power events logged by
+// the BMC returned by counts; nil disables it
+func WithPowerEvents(counts func() []powerevent.Count) OptionFn {
+	return func(o *Opts) {
+		o.powerEvents = counts
+	}
+}
+
+// WithZoneSources enables the export of the power of the node zones by the
+// source returned by sourceOf; nil disables it
+func WithZoneSources(sourceOf func(monitor.EnergyZone) string) OptionFn {
+	return func(o *Opts) {
+		o.zoneSources = sourceOf
+	}
+}
+
+// WithGPUZones enables the export of the power of the node zones for which
+// isGPU is true as kepler_node_gpu_*; nil exports them as kepler_node_cpu_*
+func WithGPUZones(isGPU func(monitor.EnergyZone) bool) OptionFn {
+	return func(o *Opts) {
+		o.gpuZones = isGPU
+	}
+}
+
 // Exporter exports power data to Prometheus
 type Exporter struct {
 	logger          *slog.Logger
@@ -102,9 +277,19 @@ type Exporter struct {
 	server          APIRegistry
 	debugCollectors map[string]bool
 	collectors      map[string]prom.Collector
+
+	// registered is true once metrics are served and scrapeErr is the error
+	// of the last scrape; both are reported by Health
+	healthMu   sync.Mutex
+	registered bool
+	scrapeErr  error
 }
 
-var _ Initializer = (*Exporter)(nil)
+var (
+	_ Initializer   = (*Exporter)(nil)
+	_ Dependent     = (*Exporter)(nil)
+	_ HealthChecker = (*Exporter)(nil)
+)
 
 // NewExporter creates a new PrometheusExporter instance
 func NewExporter(pm Monitor, s APIRegistry, applyOpts ...OptionFn) *Exporter {
@@ -140,20 +325,48 @@ func CreateCollectors(pm Monitor, applyOpts ...OptionFn) (map[string]prom.Collec
 	opts := Opts{
 		logger:       slog.Default(),
 		procfs:       "/proc",
+		sysfs:        "/sys",
 		metricsLevel: config.MetricsLevelAll,
 	}
 	for _, apply := range applyOpts {
 		apply(&opts)
 	}
+	powerCollector := collector.NewPowerCollector(pm, opts.nodeName, opts.logger, opts.metricsLevel,
+		collector.WithCarbonMetrics(opts.carbon),
+		collector.WithCostMetrics(opts.cost),
+		collector.WithPowerRangeMetrics(opts.powerRange),
+		collector.WithCalibrationMetrics(opts.calibration),
+		collector.WithHWCounterMetrics(opts.hwCounters),
+		collector.WithIOMetrics(opts.io),
+		collector.WithProcessInfoMetrics(opts.processInfo),
+		collector.WithContainerInfoMetrics(opts.containerInfo),
+		collector.WithPodInfoMetrics(opts.podInfo),
+		collector.WithSystemdUnitMetrics(opts.systemdUnits),
+		collector.WithGroupMetrics(opts.groups),
+		collector.WithAggregateMetrics(opts.aggregates),
+		collector.WithBudgetMetrics(opts.budgets),
+		collector.WithMaxProcesses(opts.maxProcesses),
+		collector.WithZoneSources(opts.zoneSources),
+		collector.WithGPUZones(opts.gpuZones))
 	collectors := map[string]prom.Collector{
 		"build_info": collector.NewKeplerBuildInfoCollector(),
-		"power":      collector.NewPowerCollector(pm, opts.nodeName, opts.logger, opts.metricsLevel),
+		"power":      powerCollector,
 	}
 	cpuInfoCollector, err := collector.NewCPUInfoCollector(opts.procfs)
 	if err != nil {
 		return nil, err
 	}
 	collectors["cpu_info"] = cpuInfoCollector
+	collectors["cpu_state"] = collector.NewCPUStateCollector(opts.sysfs, opts.nodeName)
+	if opts.platformSources != nil {
+		collectors["platform_source"] = collector.NewPlatformSourceCollector(opts.platformSources, opts.nodeName)
+	}
+	if opts.anomalies != nil {
+		collectors["anomaly"] = collector.NewAnomalyCollector(opts.anomalies, opts.nodeName)
+	}
+	if opts.powerEvents != nil {
+		collectors["power_event"] = collector.NewPowerEventCollector(opts.powerEvents, opts.nodeName)
+	}
 	return collectors, nil
 }
 
@@ -174,18 +387,71 @@ func (e *Exporter) Init() error {
 		e.registry.MustRegister(collector)
 	}
 
+	handler := promhttp.HandlerFor(
+		e.registry,
+		promhttp.HandlerOpts{
+			EnableOpenMetrics: true,
+			Registry:          e.registry,
+			ErrorLog:          scrapeErrorLog{e},
+		},
+	)
 	err := e.server.Register("/metrics", "Metrics", "Prometheus metrics",
-		promhttp.HandlerFor(
-			e.registry,
-			promhttp.HandlerOpts{
-				EnableOpenMetrics: true,
-				Registry:          e.registry,
-			},
-		))
-	return err
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// errors of this scrape are set by scrapeErrorLog
+			e.setScrapeErr(nil)
+			handler.ServeHTTP(w, r)
+		}))
+	if err != nil {
+		return err
+	}
+
+	e.healthMu.Lock()
+	e.registered = true
+	e.healthMu.Unlock()
+	return nil
+}
+
+// Health returns an error if metrics are not served or the last scrape failed
+func (e *Exporter) Health() error {
+	e.healthMu.Lock()
+	defer e.healthMu.Unlock()
+	if !e.registered {
+		return fmt.Errorf("metrics endpoint is not registered")
+	}
+	if e.scrapeErr != nil {
+		return fmt.Errorf("last scrape failed: %w", e.scrapeErr)
+	}
+	return nil
+}
+
+func (e *Exporter) setScrapeErr(err error) {
+	e.healthMu.Lock()
+	defer e.healthMu.Unlock()
+	e.scrapeErr = err
+}
+
+// scrapeErrorLog records the errors promhttp reports while serving metrics
+type scrapeErrorLog struct {
+	e *Exporter
+}
+
+func (l scrapeErrorLog) Println(v ...any) {
+	msg := fmt.Sprint(v...)
+	l.e.logger.Error("Failed to serve metrics", "error", msg)
+	l.e.setScrapeErr(errors.New(msg))
 }
 
 // Name implements service.Name
 func (e *Exporter) Name() string {
 	return "prometheus"
 }
+
+// Dependencies returns the monitor and the API server the metrics are served
+// by
+func (e *Exporter) Dependencies() []service.Service {
+	deps := []service.Service{e.monitor}
+	if s, ok := e.server.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -50,6 +51,10 @@ func (m *MockMonitor) Snapshot() (*monitor.Snapshot, error) {
 	return nil, args.Error(1)
 }
 
+func (m *MockMonitor) LatestSnapshot() (*monitor.Snapshot, error) {
+	return m.Snapshot()
+}
+
 func (m *MockMonitor) DataChannel() <-chan struct{} {
 	args := m.Called()
 	return args.Get(0).(<-chan struct{})
@@ -204,6 +209,54 @@ func TestExporter_Init(t *testing.T) {
 	})
 }
 
+// failingCollector collects an invalid metric while fail is set
+type failingCollector struct {
+	desc *prom.Desc
+	fail bool
+}
+
+func (c *failingCollector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.desc
+}
+
+func (c *failingCollector) Collect(ch chan<- prom.Metric) {
+	if c.fail {
+		ch <- prom.NewInvalidMetric(c.desc, errors.New("collect error"))
+		return
+	}
+	ch <- prom.MustNewConstMetric(c.desc, prom.GaugeValue, 1)
+}
+
+func TestExporter_Health(t *testing.T) {
+	mockMonitor := &MockMonitor{}
+	mockRegistry := &MockAPIRegistry{}
+	var handler http.Handler
+	mockRegistry.On("Register", "/metrics", "Metrics", "Prometheus metrics", mock.Anything).
+		Run(func(args mock.Arguments) { handler = args.Get(3).(http.Handler) }).
+		Return(nil)
+
+	c := &failingCollector{desc: prom.NewDesc("kepler_test", "test", nil, nil), fail: true}
+	exporter := NewExporter(mockMonitor, mockRegistry,
+		WithDebugCollectors(nil),
+		WithCollectors(map[string]prom.Collector{"test": c}))
+	assert.ErrorContains(t, exporter.Health(), "metrics endpoint is not registered")
+
+	assert.NoError(t, exporter.Init())
+	assert.NoError(t, exporter.Health())
+
+	scrape := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusInternalServerError, scrape())
+	assert.ErrorContains(t, exporter.Health(), "last scrape failed")
+
+	c.fail = false
+	assert.Equal(t, http.StatusOK, scrape())
+	assert.NoError(t, exporter.Health())
+}
+
 func TestCollectorForName(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -317,6 +370,7 @@ func TestExporter_CreateCollectors(t *testing.T) {
 		mockMonitor,
 		WithLogger(slog.Default()),
 		WithProcFSPath("/proc"),
+		WithSysFSPath("/sys"),
 	)
 	time.Sleep(50 * time.Millisecond)
 
@@ -324,5 +378,6 @@ func TestExporter_CreateCollectors(t *testing.T) {
 	mockMonitor.AssertExpectations(t)
 
 	assert.NoError(t, err)
-	assert.Len(t, coll, 3)
+	assert.Len(t, coll, 4)
+	assert.Contains(t, coll, "cpu_state")
 }
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package push
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	nodeJoulesDesc = prometheus.NewDesc("kepler_node_cpu_joules_total",
+		"Energy consumption of cpu at node level in joules",
+		[]string{"zone"}, nil)
+	processJoulesDesc = prometheus.NewDesc("kepler_process_cpu_joules_total",
+		"Energy consumption of cpu at process level in joules",
+		[]string{"pid", "comm", "state", "zone"}, nil)
+	containerJoulesDesc = prometheus.NewDesc("kepler_container_cpu_joules_total",
+		"Energy consumption of cpu at container level in joules",
+		[]string{"container_id", "container_name", "state", "zone"}, nil)
+	vmJoulesDesc = prometheus.NewDesc("kepler_vm_cpu_joules_total",
+		"Energy consumption of cpu at vm level in joules",
+		[]string{"vm_id", "vm_name", "state", "zone"}, nil)
+	podJoulesDesc = prometheus.NewDesc("kepler_pod_cpu_joules_total",
+		"Energy consumption of cpu at pod level in joules",
+		[]string{"pod_id", "pod_name", "pod_namespace", "state", "zone"}, nil)
+)
+
+// summaryCollector collects the energy of a Summary with the names of the
+// metrics of the Prometheus exporter; the node is the instance the metrics are
+// grouped by in the Pushgateway
+type summaryCollector struct {
+	summary Summary
+}
+
+var _ prometheus.Collector = (*summaryCollector)(nil)
+
+func (c *summaryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nodeJoulesDesc
+	ch <- processJoulesDesc
+	ch <- containerJoulesDesc
+	ch <- vmJoulesDesc
+	ch <- podJoulesDesc
+}
+
+func (c *summaryCollector) Collect(ch chan<- prometheus.Metric) {
+	for zone, joules := range c.summary.Zones {
+		ch <- prometheus.MustNewConstMetric(nodeJoulesDesc, prometheus.CounterValue, joules, zone)
+	}
+
+	collect := func(desc *prometheus.Desc, workloads []Workload, labels func(Workload) []string) {
+		for _, w := range workloads {
+			for zone, joules := range w.Zones {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, joules,
+					append(labels(w), w.State, zone)...)
+			}
+		}
+	}
+	idName := func(w Workload) []string { return []string{w.ID, w.Name} }
+	collect(processJoulesDesc, c.summary.Processes, idName)
+	collect(containerJoulesDesc, c.summary.Containers, idName)
+	collect(vmJoulesDesc, c.summary.VirtualMachines, idName)
+	collect(podJoulesDesc, c.summary.Pods, func(w Workload) []string {
+		return []string{w.ID, w.Name, w.Namespace}
+	})
+}
@@ -0,0 +1,543 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package push pushes a summary of the energy consumed by the node and its
+// workloads to a Prometheus Pushgateway or an HTTP endpoint, so that short
+// lived nodes, e.g. batch or CI machines, report their total consumption
+// before they disappear.
+package push
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+type (
+	Initializer = service.Initializer
+	Runner      = service.Runner
+	Shutdowner  = service.Shutdowner
+	Dependent   = service.Dependent
+	Monitor     = monitor.Service
+)
+
+// Formats of the summary
+const (
+	FormatPushgateway = "pushgateway"
+	FormatJSON        = "json"
+)
+
+// Workload states
+const (
+	StateRunning    = "running"
+	StateTerminated = "terminated"
+)
+
+// Summary is the energy consumed by the node and its workloads since Kepler
+// started
+type Summary struct {
+	Node      string             `json:"node"`
+	Timestamp time.Time          `json:"timestamp"`
+	Zones     map[string]float64 `json:"zones"` // joules of the node keyed by zone name
+
+	Processes       []Workload `json:"processes,omitempty"`
+	Containers      []Workload `json:"containers,omitempty"`
+	VirtualMachines []Workload `json:"virtualMachines,omitempty"`
+	Pods            []Workload `json:"pods,omitempty"`
+}
+
+// Workload is the energy consumed by a workload while it was monitored
+type Workload struct {
+	ID        string             `json:"id"` // PID of processes
+	Name      string             `json:"name"`
+	Namespace string             `json:"namespace,omitempty"` // of pods
+	State     string             `json:"state"`
+	Zones     map[string]float64 `json:"zones"` // joules keyed by zone name
+}
+
+// joules returns the energy of the zone that consumed the most, since zones
+// overlap on some platforms
+func (w Workload) joules() float64 {
+	var joules float64
+	for _, j := range w.Zones {
+		joules = max(joules, j)
+	}
+	return joules
+}
+
+type Opts struct {
+	logger         *slog.Logger
+	clock          clock.WithTicker
+	client         *http.Client
+	format         string
+	job            string
+	nodeName       string
+	metricsLevel   config.Level
+	interval       time.Duration
+	sampleInterval time.Duration
+	maxTerminated  int
+	events         *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:         slog.Default(),
+		clock:          clock.RealClock{},
+		client:         &http.Client{Timeout: 10 * time.Second},
+		format:         FormatPushgateway,
+		job:            "kepler",
+		metricsLevel:   config.MetricsLevelAll,
+		sampleInterval: 5 * time.Second,
+		maxTerminated:  500,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Exporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to sample workloads and schedule pushes
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to push the summary
+func WithHTTPClient(c *http.Client) OptionFn {
+	return func(o *Opts) {
+		o.client = c
+	}
+}
+
+// WithFormat sets the format of the summary: pushgateway or json
+func WithFormat(format string) OptionFn {
+	return func(o *Opts) {
+		o.format = format
+	}
+}
+
+// WithJob sets the job the metrics are grouped by in the Pushgateway
+func WithJob(job string) OptionFn {
+	return func(o *Opts) {
+		o.job = job
+	}
+}
+
+// WithNodeName sets the name of the node; the hostname is used if empty
+func WithNodeName(name string) OptionFn {
+	return func(o *Opts) {
+		o.nodeName = name
+	}
+}
+
+// WithMetricsLevel sets the levels of workloads in the summary
+func WithMetricsLevel(level config.Level) OptionFn {
+	return func(o *Opts) {
+		o.metricsLevel = level
+	}
+}
+
+// WithInterval sets the interval between pushes; 0 pushes only on shutdown
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithSampleInterval sets the interval between samples of the workloads; it
+// should be the interval of the monitor so that no terminated workload is missed
+func WithSampleInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.sampleInterval = d
+	}
+}
+
+// WithMaxTerminated sets the maximum number of terminated workloads of each
+// level in the summary, keeping those that consumed the most; 0 drops all and
+// -1 keeps all
+func WithMaxTerminated(n int) OptionFn {
+	return func(o *Opts) {
+		o.maxTerminated = n
+	}
+}
+
+// WithEventBus samples the workloads whenever the monitor publishes a new
+// snapshot on bus, rather than every sample interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// Exporter samples the workloads of the monitor, keeping the last known energy
+// of terminated workloads, and pushes a Summary on shutdown and, optionally,
+// periodically
+type Exporter struct {
+	logger         *slog.Logger
+	monitor        Monitor
+	clock          clock.WithTicker
+	client         *http.Client
+	url            string
+	format         string
+	job            string
+	nodeName       string
+	metricsLevel   config.Level
+	interval       time.Duration
+	sampleInterval time.Duration
+	events         *event.Bus
+	snapshots      *event.Subscription // nil if sampled every sample interval
+	maxTerminated  int
+
+	mu         sync.Mutex
+	node       map[string]float64
+	running    map[string]map[string]Workload // keyed by level, then ID
+	terminated map[string]map[string]Workload
+	pushed     bool
+}
+
+var (
+	_ Initializer = (*Exporter)(nil)
+	_ Runner      = (*Exporter)(nil)
+	_ Shutdowner  = (*Exporter)(nil)
+	_ Dependent   = (*Exporter)(nil)
+)
+
+// levelWorkloads are the running and terminated workloads of a level in a
+// snapshot keyed by ID
+type levelWorkloads struct {
+	running, terminated map[string]Workload
+}
+
+// levels of workloads in the summary
+const (
+	levelProcess   = "process"
+	levelContainer = "container"
+	levelVM        = "vm"
+	levelPod       = "pod"
+)
+
+// NewExporter creates a new exporter that pushes the summary of pm to url
+func NewExporter(pm Monitor, url string, applyOpts ...OptionFn) *Exporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Exporter{
+		logger:         opts.logger.With("service", "push"),
+		monitor:        pm,
+		clock:          opts.clock,
+		client:         opts.client,
+		url:            url,
+		format:         opts.format,
+		job:            opts.job,
+		nodeName:       opts.nodeName,
+		metricsLevel:   opts.metricsLevel,
+		interval:       opts.interval,
+		sampleInterval: opts.sampleInterval,
+		events:         opts.events,
+		maxTerminated:  opts.maxTerminated,
+		node:           map[string]float64{},
+		running:        map[string]map[string]Workload{},
+		terminated:     map[string]map[string]Workload{},
+	}
+}
+
+func (e *Exporter) Name() string {
+	return "push"
+}
+
+// Dependencies returns the monitor the summary is read from
+func (e *Exporter) Dependencies() []service.Service {
+	return []service.Service{e.monitor}
+}
+
+func (e *Exporter) Init() error {
+	if e.format != FormatPushgateway && e.format != FormatJSON {
+		return fmt.Errorf("invalid format %q; must be one of %s, %s", e.format, FormatPushgateway, FormatJSON)
+	}
+	if e.nodeName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to get hostname: %w", err)
+		}
+		e.nodeName = hostname
+	}
+	if e.events != nil {
+		e.snapshots = e.events.Subscribe(e.Name(), 1, event.SnapshotReady)
+	}
+	e.logger.Info("Initializing push exporter", "url", e.url, "format", e.format, "interval", e.interval)
+	return nil
+}
+
+// Run samples the workloads and pushes the summary periodically, if an
+// interval is set, until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context) error {
+	var ready <-chan event.Event
+	if e.snapshots != nil {
+		defer e.snapshots.Close()
+		ready = e.snapshots.C()
+	}
+	var tick <-chan time.Time
+	if ready == nil {
+		ticker := e.clock.NewTicker(e.sampleInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	lastPush := e.clock.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-ready:
+		}
+		e.sample()
+		if e.interval > 0 && e.clock.Since(lastPush) >= e.interval {
+			if err := e.push(ctx, e.Summary()); err != nil {
+				e.logger.Error("Failed to push summary", "error", err)
+			}
+			lastPush = e.clock.Now()
+		}
+	}
+}
+
+// Shutdown pushes the final summary, from the last snapshot the monitor
+// computes on shutdown
+func (e *Exporter) Shutdown() error {
+	e.mu.Lock()
+	pushed := e.pushed
+	e.pushed = true
+	e.mu.Unlock()
+	if pushed {
+		return nil
+	}
+
+	e.sample()
+	summary := e.Summary()
+	if err := e.push(context.Background(), summary); err != nil {
+		return fmt.Errorf("failed to push summary: %w", err)
+	}
+	e.logger.Info("Pushed energy summary", "url", e.url, "zones", summary.Zones)
+	return nil
+}
+
+func (e *Exporter) sample() {
+	snapshot, err := e.monitor.Snapshot()
+	if err != nil {
+		e.logger.Warn("Failed to get snapshot; the summary may be stale", "error", err)
+		return
+	}
+	e.observe(snapshot)
+}
+
+// observe updates the energy of the node and workloads from snapshot. Running
+// workloads that are neither running nor terminated in snapshot are kept as
+// terminated with their last known energy, in case the snapshot they were
+// terminated in was missed
+func (e *Exporter) observe(snapshot *monitor.Snapshot) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if snapshot.Node != nil {
+		for zone, usage := range snapshot.Node.Zones {
+			e.node[zone.Name()] = usage.EnergyTotal.Joules()
+		}
+	}
+
+	levels := map[string]levelWorkloads{}
+	if e.metricsLevel.IsProcessEnabled() {
+		levels[levelProcess] = levelWorkloads{
+			processes(snapshot.Processes, StateRunning), processes(snapshot.TerminatedProcesses, StateTerminated),
+		}
+	}
+	if e.metricsLevel.IsContainerEnabled() {
+		levels[levelContainer] = levelWorkloads{
+			containers(snapshot.Containers, StateRunning), containers(snapshot.TerminatedContainers, StateTerminated),
+		}
+	}
+	if e.metricsLevel.IsVMEnabled() {
+		levels[levelVM] = levelWorkloads{
+			vms(snapshot.VirtualMachines, StateRunning), vms(snapshot.TerminatedVirtualMachines, StateTerminated),
+		}
+	}
+	if e.metricsLevel.IsPodEnabled() {
+		levels[levelPod] = levelWorkloads{
+			pods(snapshot.Pods, StateRunning), pods(snapshot.TerminatedPods, StateTerminated),
+		}
+	}
+
+	for level, current := range levels {
+		for id, w := range e.running[level] {
+			if _, ok := current.running[id]; ok {
+				continue
+			}
+			if _, ok := current.terminated[id]; !ok {
+				w.State = StateTerminated
+				e.terminate(level, w)
+			}
+		}
+		for _, w := range current.terminated {
+			e.terminate(level, w)
+		}
+		e.running[level] = current.running
+	}
+}
+
+// terminate keeps w as a terminated workload of level, evicting the terminated
+// workload that consumed the least if there are too many
+func (e *Exporter) terminate(level string, w Workload) {
+	if e.maxTerminated == 0 {
+		return
+	}
+	terminated := e.terminated[level]
+	if terminated == nil {
+		terminated = map[string]Workload{}
+		e.terminated[level] = terminated
+	}
+	terminated[w.ID] = w
+	if e.maxTerminated < 0 || len(terminated) <= e.maxTerminated {
+		return
+	}
+
+	var evict string
+	for id, t := range terminated {
+		if evict == "" || t.joules() < terminated[evict].joules() {
+			evict = id
+		}
+	}
+	delete(terminated, evict)
+}
+
+// Summary returns the summary of the energy consumed by the node and its
+// running and terminated workloads
+func (e *Exporter) Summary() Summary {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	list := func(level string) []Workload {
+		workloads := slices.Collect(maps.Values(e.running[level]))
+		workloads = slices.AppendSeq(workloads, maps.Values(e.terminated[level]))
+		slices.SortFunc(workloads, func(a, b Workload) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+		return workloads
+	}
+
+	return Summary{
+		Node:            e.nodeName,
+		Timestamp:       e.clock.Now(),
+		Zones:           maps.Clone(e.node),
+		Processes:       list(levelProcess),
+		Containers:      list(levelContainer),
+		VirtualMachines: list(levelVM),
+		Pods:            list(levelPod),
+	}
+}
+
+func (e *Exporter) push(ctx context.Context, summary Summary) error {
+	if e.format == FormatJSON {
+		return e.postJSON(ctx, summary)
+	}
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(&summaryCollector{summary: summary}); err != nil {
+		return err
+	}
+	return push.New(e.url, e.job).
+		Grouping("instance", summary.Node).
+		Client(e.client).
+		Gatherer(registry).
+		PushContext(ctx)
+}
+
+func (e *Exporter) postJSON(ctx context.Context, summary Summary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post summary: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return nil
+}
+
+func zones(usage monitor.ZoneUsageMap) map[string]float64 {
+	joules := make(map[string]float64, len(usage))
+	for zone, u := range usage {
+		joules[zone.Name()] = u.EnergyTotal.Joules()
+	}
+	return joules
+}
+
+func processes(procs monitor.Processes, state string) map[string]Workload {
+	workloads := make(map[string]Workload, len(procs))
+	for id, p := range procs {
+		workloads[id] = Workload{ID: strconv.Itoa(p.PID), Name: p.Comm, State: state, Zones: zones(p.Zones)}
+	}
+	return workloads
+}
+
+func containers(cntrs monitor.Containers, state string) map[string]Workload {
+	workloads := make(map[string]Workload, len(cntrs))
+	for id, c := range cntrs {
+		workloads[id] = Workload{ID: c.ID, Name: c.Name, State: state, Zones: zones(c.Zones)}
+	}
+	return workloads
+}
+
+func vms(machines monitor.VirtualMachines, state string) map[string]Workload {
+	workloads := make(map[string]Workload, len(machines))
+	for id, vm := range machines {
+		workloads[id] = Workload{ID: vm.ID, Name: vm.Name, State: state, Zones: zones(vm.Zones)}
+	}
+	return workloads
+}
+
+func pods(ps monitor.Pods, state string) map[string]Workload {
+	workloads := make(map[string]Workload, len(ps))
+	for id, p := range ps {
+		workloads[id] = Workload{ID: p.ID, Name: p.Name, Namespace: p.Namespace, State: state, Zones: zones(p.Zones)}
+	}
+	return workloads
+}
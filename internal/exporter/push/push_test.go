@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var pkg = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+
+func usage(joules float64) monitor.ZoneUsageMap {
+	return monitor.ZoneUsageMap{pkg: {EnergyTotal: monitor.Energy(joules) * device.Joule}}
+}
+
+// snapshot returns a snapshot of the node and the running and terminated
+// processes keyed by PID with their energy in joules
+func snapshot(nodeJoules float64, running, terminated map[int]float64) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Node = &monitor.Node{Zones: monitor.NodeZoneUsageMap{
+		pkg: {EnergyTotal: monitor.Energy(nodeJoules) * device.Joule},
+	}}
+	for pid, joules := range running {
+		p := &monitor.Process{PID: pid, Comm: "job", Zones: usage(joules)}
+		s.Processes[p.StringID()] = p
+	}
+	for pid, joules := range terminated {
+		p := &monitor.Process{PID: pid, Comm: "job", Zones: usage(joules)}
+		s.TerminatedProcesses[p.StringID()] = p
+	}
+	return s
+}
+
+func TestExporterSummary(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	e := NewExporter(monitor.NewFakeMonitor(nil), "http://pushgateway:9091",
+		WithClock(testingclock.NewFakeClock(now)),
+		WithNodeName("ci-1"),
+		WithMetricsLevel(config.MetricsLevelNode|config.MetricsLevelProcess),
+		WithMaxTerminated(2),
+	)
+
+	e.observe(snapshot(100, map[int]float64{1: 10, 2: 20, 3: 30, 4: 5}, nil))
+	// 2 terminated and was seen by the monitor; 3 and 4 were missed
+	e.observe(snapshot(200, map[int]float64{1: 15}, map[int]float64{2: 25}))
+
+	summary := e.Summary()
+	assert.Equal(t, "ci-1", summary.Node)
+	assert.Equal(t, now, summary.Timestamp)
+	assert.Equal(t, map[string]float64{"package": 200}, summary.Zones)
+	assert.Equal(t, []Workload{
+		{ID: "1", Name: "job", State: StateRunning, Zones: map[string]float64{"package": 15}},
+		{ID: "2", Name: "job", State: StateTerminated, Zones: map[string]float64{"package": 25}},
+		{ID: "3", Name: "job", State: StateTerminated, Zones: map[string]float64{"package": 30}},
+	}, summary.Processes, "4 consumed the least and is evicted")
+	assert.Empty(t, summary.Containers, "containers are not enabled")
+}
+
+func TestExporterPushgateway(t *testing.T) {
+	type request struct {
+		method, path, body string
+	}
+	requests := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{r.Method, r.URL.Path, string(body)}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pm := monitor.NewFakeMonitor(snapshot(100, map[int]float64{1: 10}, nil))
+	e := NewExporter(pm, server.URL, WithNodeName("ci-1"), WithJob("batch"))
+	require.NoError(t, e.Init())
+	require.NoError(t, e.Shutdown())
+
+	req := <-requests
+	assert.Equal(t, http.MethodPut, req.method)
+	assert.Equal(t, "/metrics/job/batch/instance/ci-1", req.path)
+	assert.NotEmpty(t, req.body)
+
+	// the summary is pushed only once on shutdown
+	require.NoError(t, e.Shutdown())
+	assert.Empty(t, requests)
+}
+
+func TestSummaryCollector(t *testing.T) {
+	c := &summaryCollector{summary: Summary{
+		Node:  "ci-1",
+		Zones: map[string]float64{"package": 100, "dram": 10},
+		Processes: []Workload{
+			{ID: "1", Name: "job", State: StateTerminated, Zones: map[string]float64{"package": 10}},
+		},
+		Pods: []Workload{
+			{ID: "pod-1", Name: "build", Namespace: "ci", State: StateRunning, Zones: map[string]float64{"package": 20}},
+		},
+	}}
+
+	assert.Equal(t, 4, testutil.CollectAndCount(c))
+	expected := `
+# HELP kepler_pod_cpu_joules_total Energy consumption of cpu at pod level in joules
+# TYPE kepler_pod_cpu_joules_total counter
+kepler_pod_cpu_joules_total{pod_id="pod-1",pod_name="build",pod_namespace="ci",state="running",zone="package"} 20
+# HELP kepler_process_cpu_joules_total Energy consumption of cpu at process level in joules
+# TYPE kepler_process_cpu_joules_total counter
+kepler_process_cpu_joules_total{comm="job",pid="1",state="terminated",zone="package"} 10
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"kepler_pod_cpu_joules_total", "kepler_process_cpu_joules_total"))
+}
+
+func TestExporterJSON(t *testing.T) {
+	summaries := make(chan Summary, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var s Summary
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&s))
+		summaries <- s
+	}))
+	defer server.Close()
+
+	pm := monitor.NewFakeMonitor(snapshot(100, map[int]float64{1: 10}, nil))
+	e := NewExporter(pm, server.URL, WithNodeName("ci-1"), WithFormat(FormatJSON))
+	require.NoError(t, e.Init())
+	require.NoError(t, e.Shutdown())
+
+	s := <-summaries
+	assert.Equal(t, "ci-1", s.Node)
+	assert.Equal(t, map[string]float64{"package": 100}, s.Zones)
+	require.Len(t, s.Processes, 1)
+	assert.Equal(t, 10.0, s.Processes[0].Zones["package"])
+}
+
+func TestExporterErrors(t *testing.T) {
+	e := NewExporter(monitor.NewFakeMonitor(nil), "http://localhost", WithFormat("xml"))
+	assert.ErrorContains(t, e.Init(), `invalid format "xml"`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	for _, format := range []string{FormatPushgateway, FormatJSON} {
+		pm := monitor.NewFakeMonitor(snapshot(100, nil, nil))
+		e := NewExporter(pm, server.URL, WithNodeName("ci-1"), WithFormat(format))
+		require.NoError(t, e.Init())
+		assert.Error(t, e.Shutdown(), format)
+	}
+}
+
+func TestExporterRun(t *testing.T) {
+	pushes := make(chan Summary, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var s Summary
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&s))
+		pushes <- s
+	}))
+	defer server.Close()
+
+	fakeClock := testingclock.NewFakeClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	pm := monitor.NewFakeMonitor(snapshot(100, map[int]float64{1: 10}, nil))
+	e := NewExporter(pm, server.URL,
+		WithNodeName("ci-1"),
+		WithFormat(FormatJSON),
+		WithClock(fakeClock),
+		WithSampleInterval(time.Second),
+		WithInterval(2*time.Second),
+	)
+	require.NoError(t, e.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, time.Millisecond)
+	fakeClock.Step(time.Second)
+	require.Eventually(t, func() bool { return len(e.Summary().Processes) == 1 }, time.Second, time.Millisecond)
+	assert.Empty(t, pushes, "not pushed before the interval")
+
+	// the process terminates and is missed by the monitor
+	pm.Set(snapshot(200, nil, nil))
+	fakeClock.Step(time.Second)
+
+	select {
+	case s := <-pushes:
+		assert.Equal(t, 200.0, s.Zones["package"])
+		require.Len(t, s.Processes, 1)
+		assert.Equal(t, StateTerminated, s.Processes[0].State)
+	case <-time.After(5 * time.Second):
+		t.Fatal("summary was not pushed")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestExporterEvents(t *testing.T) {
+	bus := event.NewBus()
+	pm := monitor.NewFakeMonitor(snapshot(100, map[int]float64{1: 10}, nil))
+	e := NewExporter(pm, "http://localhost", WithNodeName("ci-1"), WithEventBus(bus))
+	require.NoError(t, e.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+	bus.Publish(event.Event{Kind: event.SnapshotReady})
+	assert.Eventually(t, func() bool { return len(e.Summary().Processes) == 1 }, time.Second, time.Millisecond,
+		"the workloads are sampled when a snapshot is ready")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
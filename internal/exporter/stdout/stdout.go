@@ -5,10 +5,12 @@ package stdout
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
@@ -18,10 +20,12 @@ import (
 )
 
 type (
-	Initializer = service.Initializer
-	Runner      = service.Runner
-	Shutdowner  = service.Shutdowner
-	Monitor     = monitor.Service
+	Initializer   = service.Initializer
+	Runner        = service.Runner
+	Shutdowner    = service.Shutdowner
+	Dependent     = service.Dependent
+	HealthChecker = service.HealthChecker
+	Monitor       = monitor.Service
 )
 
 // Exporter exports power data to stdout
@@ -31,12 +35,18 @@ type Exporter struct {
 	out      io.WriteCloser
 	ticker   time.Ticker
 	interval time.Duration
+
+	// exportErr is the error of the last export, reported by Health
+	mu        sync.Mutex
+	exportErr error
 }
 
 var (
-	_ Initializer = (*Exporter)(nil)
-	_ Runner      = (*Exporter)(nil)
-	_ Shutdowner  = (*Exporter)(nil)
+	_ Initializer   = (*Exporter)(nil)
+	_ Runner        = (*Exporter)(nil)
+	_ Shutdowner    = (*Exporter)(nil)
+	_ Dependent     = (*Exporter)(nil)
+	_ HealthChecker = (*Exporter)(nil)
 )
 
 type Opts struct {
@@ -101,12 +111,10 @@ func (e *Exporter) Run(ctx context.Context) error {
 	for {
 		select {
 		case now := <-e.ticker.C:
-			snapshot, err := e.monitor.Snapshot()
-			if err != nil {
+			if err := e.export(now); err != nil {
 				e.logger.Error("Failed to collect power data", "error", err)
 				return nil
 			}
-			write(e.out, now, snapshot)
 		case <-ctx.Done():
 			e.logger.Info("Exiting ticker")
 			return nil
@@ -114,6 +122,29 @@ func (e *Exporter) Run(ctx context.Context) error {
 	}
 }
 
+// export writes the snapshot of the monitor
+func (e *Exporter) export(now time.Time) error {
+	snapshot, err := e.monitor.Snapshot()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.exportErr = err
+	if err != nil {
+		return err
+	}
+	write(e.out, now, snapshot)
+	return nil
+}
+
+// Health returns the error of the last export
+func (e *Exporter) Health() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.exportErr != nil {
+		return fmt.Errorf("last export failed: %w", e.exportErr)
+	}
+	return nil
+}
+
 func write(out io.Writer, now time.Time, snapshot *monitor.Snapshot) {
 	writeNode(out, snapshot.Node)
 }
@@ -131,11 +162,16 @@ func writeNode(out io.Writer, node *monitor.Node) {
 	sort.Slice(rows, func(i, j int) bool {
 		return rows[i][0] < rows[j][0]
 	})
+	WriteTable(out, []string{"Zone", "Power(W)", "Absolute(J)"}, rows)
+}
+
+// WriteTable writes rows under header as a table with right aligned cells
+func WriteTable(out io.Writer, header []string, rows [][]string) {
 	table := tablewriter.NewWriter(out)
 	table.Configure(func(cfg *tablewriter.Config) {
 		cfg.Row.Formatting.Alignment = tw.AlignRight
 	})
-	table.Header([]string{"Zone", "Power(W)", "Absolute(J)"})
+	table.Header(header)
 	_ = table.Bulk(rows)
 	// removed because testcase gets a trailing whitespace which fails CI
 	// table.Caption(tw.Caption{
@@ -145,7 +181,12 @@ func writeNode(out io.Writer, node *monitor.Node) {
 	_ = table.Render()
 }
 
+// Shutdown writes the last snapshot, which the monitor computes on shutdown,
+// and closes the output
 func (e *Exporter) Shutdown() error {
+	if err := e.export(time.Now()); err != nil {
+		e.logger.Error("Failed to collect the last power data", "error", err)
+	}
 	return e.out.Close()
 }
 
@@ -153,3 +194,8 @@ func (e *Exporter) Shutdown() error {
 func (e *Exporter) Name() string {
 	return "stdout"
 }
+
+// Dependencies returns the monitor whose snapshots are written
+func (e *Exporter) Dependencies() []service.Service {
+	return []service.Service{e.monitor}
+}
@@ -52,6 +52,10 @@ func (m *MockMonitor) Snapshot() (*monitor.Snapshot, error) {
 	return nil, args.Error(1)
 }
 
+func (m *MockMonitor) LatestSnapshot() (*monitor.Snapshot, error) {
+	return m.Snapshot()
+}
+
 func (m *MockMonitor) DataChannel() <-chan struct{} {
 	args := m.Called()
 	return args.Get(0).(<-chan struct{})
@@ -127,6 +131,28 @@ func TestExporter_InitRunShotdown(t *testing.T) {
 	})
 }
 
+func TestExporter_Shutdown(t *testing.T) {
+	mockMonitor := &MockMonitor{}
+	mockMonitor.On("Snapshot").Return(&monitor.Snapshot{Node: getTestNodeData()}, nil).Once()
+	buf := &bytes.Buffer{}
+	exporter := NewExporter(mockMonitor, WithOutput(&dummyWriteCloser{buf}))
+
+	assert.NoError(t, exporter.Shutdown())
+	assert.Contains(t, buf.String(), "package", "the last snapshot is written on shutdown")
+	mockMonitor.AssertExpectations(t)
+}
+
+func TestExporter_Health(t *testing.T) {
+	mockMonitor := &MockMonitor{}
+	mockMonitor.On("Snapshot").Return(nil, assert.AnError)
+	exporter := NewExporter(mockMonitor, WithOutput(&dummyWriteCloser{&bytes.Buffer{}}), WithInterval(time.Millisecond))
+	assert.NoError(t, exporter.Health())
+
+	assert.NoError(t, exporter.Init())
+	assert.NoError(t, exporter.Run(context.Background()), "run returns once the export fails")
+	assert.ErrorIs(t, exporter.Health(), assert.AnError)
+}
+
 func Test_print(t *testing.T) {
 	buf := bytes.Buffer{}
 	now, err := time.Parse(time.RFC3339, "2025-05-15T01:01:01Z")
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package textfile writes the metrics of the Prometheus exporter to a .prom
+// file read by the textfile collector of node_exporter, where Kepler can't be
+// scraped itself.
+package textfile
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+type (
+	Initializer   = service.Initializer
+	Runner        = service.Runner
+	Shutdowner    = service.Shutdowner
+	Dependent     = service.Dependent
+	HealthChecker = service.HealthChecker
+	Monitor       = monitor.Service
+)
+
+type Opts struct {
+	logger     *slog.Logger
+	clock      clock.WithTicker
+	interval   time.Duration
+	collectors map[string]prom.Collector
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:   slog.Default(),
+		clock:    clock.RealClock{},
+		interval: 15 * time.Second,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Exporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock the file is written with
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithInterval sets the interval between writes
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithCollectors sets the collectors whose metrics are written; they are those
+// of the Prometheus exporter, which they may be shared with
+func WithCollectors(c map[string]prom.Collector) OptionFn {
+	return func(o *Opts) {
+		o.collectors = c
+	}
+}
+
+// Exporter writes the metrics of its collectors to a file every interval. The
+// file is replaced atomically, so that node_exporter never reads a partial
+// file; the temporary file it is written to doesn't end with .prom and so is
+// ignored by node_exporter.
+type Exporter struct {
+	logger     *slog.Logger
+	monitor    Monitor
+	clock      clock.WithTicker
+	path       string
+	interval   time.Duration
+	collectors map[string]prom.Collector
+	registry   *prom.Registry
+
+	// writeErr is the error of the last write, reported by Health
+	mu       sync.Mutex
+	writeErr error
+}
+
+var (
+	_ Initializer   = (*Exporter)(nil)
+	_ Runner        = (*Exporter)(nil)
+	_ Shutdowner    = (*Exporter)(nil)
+	_ Dependent     = (*Exporter)(nil)
+	_ HealthChecker = (*Exporter)(nil)
+)
+
+// NewExporter creates a new exporter that writes the metrics of the snapshots
+// of pm to path
+func NewExporter(pm Monitor, path string, applyOpts ...OptionFn) *Exporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Exporter{
+		logger:     opts.logger.With("service", "textfile"),
+		monitor:    pm,
+		clock:      opts.clock,
+		path:       path,
+		interval:   opts.interval,
+		collectors: opts.collectors,
+		registry:   prom.NewRegistry(),
+	}
+}
+
+func (e *Exporter) Name() string {
+	return "textfile"
+}
+
+// Dependencies returns the monitor whose snapshots the metrics are collected
+// from
+func (e *Exporter) Dependencies() []service.Service {
+	return []service.Service{e.monitor}
+}
+
+func (e *Exporter) Init() error {
+	dir := filepath.Dir(e.path)
+	if info, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("failed to access textfile directory: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("textfile directory %s is not a directory", dir)
+	}
+
+	for name, collector := range e.collectors {
+		if err := e.registry.Register(collector); err != nil {
+			return fmt.Errorf("failed to register collector %s: %w", name, err)
+		}
+	}
+	e.logger.Info("Initializing textfile exporter", "path", e.path, "interval", e.interval)
+	return nil
+}
+
+// Run writes the metrics every interval until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context) error {
+	ticker := e.clock.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			if err := e.write(); err != nil {
+				e.logger.Error("Failed to write metrics", "path", e.path, "error", err)
+			}
+		}
+	}
+}
+
+// Shutdown writes the metrics of the last snapshot, which the monitor computes
+// on shutdown; the file is kept, so node_exporter serves them until it is
+// removed
+func (e *Exporter) Shutdown() error {
+	if err := e.write(); err != nil {
+		e.logger.Error("Failed to write the last metrics", "path", e.path, "error", err)
+	}
+	return nil
+}
+
+// write replaces the file with the metrics currently collected
+func (e *Exporter) write() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.writeErr = prom.WriteToTextfile(e.path, e.registry)
+	return e.writeErr
+}
+
+// Health returns the error of the last write
+func (e *Exporter) Health() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.writeErr != nil {
+		return fmt.Errorf("last write failed: %w", e.writeErr)
+	}
+	return nil
+}
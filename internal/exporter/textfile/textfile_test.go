@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package textfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestExporter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kepler.prom")
+
+	watts := prom.NewGaugeVec(prom.GaugeOpts{Name: "kepler_node_cpu_watts", Help: "Power of the node"}, []string{"zone"})
+	watts.WithLabelValues("package").Set(42)
+
+	fc := testingclock.NewFakeClock(time.Now())
+	e := NewExporter(monitor.NewFakeMonitor(monitor.NewSnapshot()), path,
+		WithClock(fc),
+		WithInterval(time.Second),
+		WithCollectors(map[string]prom.Collector{"power": watts}),
+	)
+	assert.Equal(t, "textfile", e.Name())
+	require.NoError(t, e.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = e.Run(ctx) }()
+	require.Eventually(t, fc.HasWaiters, time.Second, time.Millisecond)
+
+	read := func() string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+
+	fc.Step(time.Second)
+	require.Eventually(t, func() bool { return read() != "" }, time.Second, time.Millisecond)
+	assert.Equal(t, "# HELP kepler_node_cpu_watts Power of the node\n"+
+		"# TYPE kepler_node_cpu_watts gauge\n"+
+		`kepler_node_cpu_watts{zone="package"} 42`+"\n", read())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm(), "readable by node_exporter")
+	assert.NoError(t, e.Health())
+
+	// the metrics of the last snapshot are written on shutdown
+	watts.WithLabelValues("package").Set(21)
+	require.NoError(t, e.Shutdown())
+	assert.Contains(t, read(), `kepler_node_cpu_watts{zone="package"} 21`)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary files are removed")
+
+	// failed writes are reported by Health
+	require.NoError(t, os.RemoveAll(dir))
+	require.NoError(t, e.Shutdown())
+	assert.ErrorContains(t, e.Health(), "last write failed")
+}
+
+func TestExporterInit(t *testing.T) {
+	dir := t.TempDir()
+
+	e := NewExporter(monitor.NewFakeMonitor(monitor.NewSnapshot()), filepath.Join(dir, "missing", "kepler.prom"))
+	assert.ErrorContains(t, e.Init(), "failed to access textfile directory")
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+	e = NewExporter(monitor.NewFakeMonitor(monitor.NewSnapshot()), filepath.Join(file, "kepler.prom"))
+	assert.ErrorContains(t, e.Init(), "is not a directory")
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package vm
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+type (
+	Initializer = service.Initializer
+	Dependent   = service.Dependent
+	Monitor     = monitor.Service
+)
+
+// APIRegistry registers the endpoints served to VMs, including over vsock
+type APIRegistry interface {
+	RegisterGuest(endpoint, summary, description string, handler http.Handler) error
+}
+
+// Endpoint is the endpoint the power of a VM is served at, followed by its ID
+const Endpoint = "/vms/"
+
+// VM is the power of a VM served to Kepler running in the VM
+type VM struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Hypervisor string          `json:"hypervisor"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Zones      map[string]Zone `json:"zones"` // keyed by zone name
+}
+
+// Zone is the energy and power of a VM in a zone
+type Zone struct {
+	Joules float64 `json:"joules"` // cumulative energy
+	Watts  float64 `json:"watts"`
+}
+
+type Opts struct {
+	logger *slog.Logger
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger: slog.Default(),
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Exporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// Exporter serves the power of each running VM at /vms/<id>, so that Kepler
+// running in a VM can read the power of its VM (see the guest power meter)
+// without access to the metrics of the host, which include all VMs. Over
+// vsock, a VM is only served its own power, the VM of the context ID of the
+// peer.
+type Exporter struct {
+	logger  *slog.Logger
+	monitor Monitor
+	server  APIRegistry
+}
+
+var (
+	_ Initializer = (*Exporter)(nil)
+	_ Dependent   = (*Exporter)(nil)
+)
+
+// NewExporter creates a new VM exporter that serves the power of VMs using s
+func NewExporter(pm Monitor, s APIRegistry, applyOpts ...OptionFn) *Exporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Exporter{
+		logger:  opts.logger.With("service", "vm"),
+		monitor: pm,
+		server:  s,
+	}
+}
+
+func (e *Exporter) Name() string {
+	return "vm"
+}
+
+// Dependencies returns the monitor and the API server the power of VMs is
+// served by
+func (e *Exporter) Dependencies() []service.Service {
+	deps := []service.Service{e.monitor}
+	if s, ok := e.server.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (e *Exporter) Init() error {
+	e.logger.Info("Initializing VM exporter", "endpoint", Endpoint)
+	return e.server.RegisterGuest(Endpoint, "VMs", "Power of a VM by its ID (/vms/<id>)", http.HandlerFunc(e.handleVM))
+}
+
+// handleVM serves the power of the running VM whose ID follows /vms/
+func (e *Exporter) handleVM(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, Endpoint)
+	if id == "" || strings.Contains(id, "/") {
+		http.Error(w, "usage: /vms/<id>", http.StatusNotFound)
+		return
+	}
+
+	snapshot, err := e.monitor.LatestSnapshot()
+	if err != nil {
+		e.logger.Error("Failed to get snapshot", "error", err)
+		http.Error(w, "failed to get power data", http.StatusServiceUnavailable)
+		return
+	}
+
+	vm := findVM(snapshot.VirtualMachines, id)
+	if vm == nil {
+		http.Error(w, "VM not found", http.StatusNotFound)
+		return
+	}
+	if cid, ok := server.VsockPeer(r.Context()); ok && (vm.CID == 0 || vm.CID != cid) {
+		e.logger.Warn("VM power requested by another VM", "vm", id, "cid", cid)
+		http.Error(w, "VMs are only served their own power", http.StatusForbidden)
+		return
+	}
+
+	resp := VM{
+		ID:         vm.ID,
+		Name:       vm.Name,
+		Hypervisor: string(vm.Hypervisor),
+		Timestamp:  snapshot.Timestamp,
+		Zones:      make(map[string]Zone, len(vm.Zones)),
+	}
+	// zones of the same name, e.g. package zones of each socket, are summed
+	for zone, usage := range vm.Zones {
+		z := resp.Zones[zone.Name()]
+		z.Joules += usage.EnergyTotal.Joules()
+		z.Watts += usage.Power.Watts()
+		resp.Zones[zone.Name()] = z
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		e.logger.Error("Failed to write VM power", "vm", id, "error", err)
+	}
+}
+
+// findVM returns the VM of id; IDs are UUIDs, which are compared ignoring case
+func findVM(vms monitor.VirtualMachines, id string) *monitor.VirtualMachine {
+	if vm, ok := vms[id]; ok {
+		return vm
+	}
+	for vmID, vm := range vms {
+		if strings.EqualFold(vmID, id) {
+			return vm
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+)
+
+func TestExporter(t *testing.T) {
+	pkg0 := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	pkg1 := device.NewMockRaplZone("package", 1, "/sys/class/powercap/intel-rapl/intel-rapl:1", 1000*device.Joule)
+	dram := device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:1", 1000*device.Joule)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := monitor.NewSnapshot()
+	snapshot.Timestamp = now
+	snapshot.VirtualMachines["df12672f-fedb-4f6f-9d51-0166868835fb"] = &monitor.VirtualMachine{
+		ID:         "df12672f-fedb-4f6f-9d51-0166868835fb",
+		Name:       "guest",
+		Hypervisor: "kvm",
+		CID:        3,
+		Zones: monitor.ZoneUsageMap{
+			pkg0: {EnergyTotal: 100 * device.Joule, Power: 10 * device.Watt},
+			pkg1: {EnergyTotal: 50 * device.Joule, Power: 5 * device.Watt},
+			dram: {EnergyTotal: 20 * device.Joule, Power: 2 * device.Watt},
+		},
+	}
+
+	pm := monitor.NewFakeMonitor(snapshot)
+	registry := server.NewFakeRegistry()
+	exporter := NewExporter(pm, registry)
+	assert.Equal(t, "vm", exporter.Name())
+	require.NoError(t, exporter.Init())
+	handler := registry.Handlers[Endpoint]
+	require.NotNil(t, handler)
+
+	t.Run("VM", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/vms/DF12672F-FEDB-4F6F-9D51-0166868835FB", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var vm VM
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&vm))
+		assert.Equal(t, VM{
+			ID:         "df12672f-fedb-4f6f-9d51-0166868835fb",
+			Name:       "guest",
+			Hypervisor: "kvm",
+			Timestamp:  now,
+			Zones: map[string]Zone{
+				"package": {Joules: 150, Watts: 15},
+				"dram":    {Joules: 20, Watts: 2},
+			},
+		}, vm)
+	})
+
+	tt := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+	}{
+		{"unknown VM", http.MethodGet, "/vms/unknown", http.StatusNotFound},
+		{"no ID", http.MethodGet, "/vms/", http.StatusNotFound},
+		{"nested path", http.MethodGet, "/vms/df12672f-fedb-4f6f-9d51-0166868835fb/zones", http.StatusNotFound},
+		{"POST", http.MethodPost, "/vms/df12672f-fedb-4f6f-9d51-0166868835fb", http.StatusMethodNotAllowed},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, tc.code, rec.Code)
+		})
+	}
+
+	t.Run("over vsock", func(t *testing.T) {
+		for cid, code := range map[uint32]int{3: http.StatusOK, 4: http.StatusForbidden} {
+			req := httptest.NewRequest(http.MethodGet, "/vms/df12672f-fedb-4f6f-9d51-0166868835fb", nil)
+			req = req.WithContext(server.ContextWithVsockPeer(req.Context(), cid))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, code, rec.Code, "cid %d", cid)
+		}
+	})
+
+	t.Run("snapshot error", func(t *testing.T) {
+		pm.SetError(errors.New("no data"))
+		defer pm.SetError(nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/vms/df12672f-fedb-4f6f-9d51-0166868835fb", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
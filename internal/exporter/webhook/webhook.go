@@ -0,0 +1,370 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhook posts an energy report of each container to a webhook when
+// the container exits, so that CI systems can annotate their jobs with the
+// energy they consumed.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+type (
+	Initializer = service.Initializer
+	Runner      = service.Runner
+	Shutdowner  = service.Shutdowner
+	Dependent   = service.Dependent
+	Monitor     = monitor.Service
+)
+
+// Report is the energy consumed by a container while it was monitored
+type Report struct {
+	Node      string `json:"node"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Image     string `json:"image,omitempty"`
+	Runtime   string `json:"runtime"`
+	Pod       string `json:"pod,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+
+	// StartTime is when the container was first seen, which is when Kepler
+	// started for containers that were already running
+	StartTime       time.Time `json:"startTime"`
+	EndTime         time.Time `json:"endTime"`
+	DurationSeconds float64   `json:"durationSeconds"`
+
+	Joules    float64            `json:"joules"`    // of the zone that consumed the most
+	PeakWatts float64            `json:"peakWatts"` // of the zone that consumed the most
+	Zones     map[string]float64 `json:"zones"`     // joules keyed by zone name
+}
+
+type Opts struct {
+	logger         *slog.Logger
+	clock          clock.WithTicker
+	client         *http.Client
+	nodeName       string
+	sampleInterval time.Duration
+	events         *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:         slog.Default(),
+		clock:          clock.RealClock{},
+		client:         &http.Client{Timeout: 10 * time.Second},
+		sampleInterval: 5 * time.Second,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Exporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to sample containers
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to post reports
+func WithHTTPClient(c *http.Client) OptionFn {
+	return func(o *Opts) {
+		o.client = c
+	}
+}
+
+// WithNodeName sets the name of the node; the hostname is used if empty
+func WithNodeName(name string) OptionFn {
+	return func(o *Opts) {
+		o.nodeName = name
+	}
+}
+
+// WithSampleInterval sets the interval between samples of the containers; it
+// should be the interval of the monitor so that the peak power is not missed
+func WithSampleInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.sampleInterval = d
+	}
+}
+
+// WithEventBus samples the containers whenever the monitor publishes a new
+// snapshot on bus, rather than every sample interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// Exporter samples the containers of the monitor and posts a Report of each
+// container that exits to a webhook
+type Exporter struct {
+	logger         *slog.Logger
+	monitor        Monitor
+	clock          clock.WithTicker
+	client         *http.Client
+	url            string
+	nodeName       string
+	sampleInterval time.Duration
+	events         *event.Bus
+	snapshots      *event.Subscription // nil if sampled every sample interval
+
+	// guards the reports, which are updated by Run and by Shutdown
+	mu           sync.Mutex
+	running      map[string]*Report  // keyed by container ID
+	reported     map[string]struct{} // IDs of containers reported that may still be in snapshots
+	lastSnapshot time.Time
+}
+
+var (
+	_ Initializer = (*Exporter)(nil)
+	_ Runner      = (*Exporter)(nil)
+	_ Shutdowner  = (*Exporter)(nil)
+	_ Dependent   = (*Exporter)(nil)
+)
+
+// NewExporter creates a new exporter that posts the reports of the containers
+// of pm to url
+func NewExporter(pm Monitor, url string, applyOpts ...OptionFn) *Exporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Exporter{
+		logger:         opts.logger.With("service", "webhook"),
+		monitor:        pm,
+		clock:          opts.clock,
+		client:         opts.client,
+		url:            url,
+		nodeName:       opts.nodeName,
+		sampleInterval: opts.sampleInterval,
+		events:         opts.events,
+		running:        map[string]*Report{},
+		reported:       map[string]struct{}{},
+	}
+}
+
+func (e *Exporter) Name() string {
+	return "webhook"
+}
+
+// Dependencies returns the monitor the containers are read from
+func (e *Exporter) Dependencies() []service.Service {
+	return []service.Service{e.monitor}
+}
+
+func (e *Exporter) Init() error {
+	if e.nodeName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to get hostname: %w", err)
+		}
+		e.nodeName = hostname
+	}
+	if e.events != nil {
+		e.snapshots = e.events.Subscribe(e.Name(), 1, event.SnapshotReady)
+	}
+	e.logger.Info("Initializing webhook exporter", "url", e.url)
+	return nil
+}
+
+// Run samples the containers and posts the reports of those that exited until
+// ctx is cancelled
+func (e *Exporter) Run(ctx context.Context) error {
+	var ready <-chan event.Event
+	if e.snapshots != nil {
+		defer e.snapshots.Close()
+		ready = e.snapshots.C()
+	}
+	var tick <-chan time.Time
+	if ready == nil {
+		ticker := e.clock.NewTicker(e.sampleInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-ready:
+		}
+		e.report(ctx)
+	}
+}
+
+// Shutdown posts the reports of the containers that exited in the last
+// snapshot, which the monitor computes on shutdown
+func (e *Exporter) Shutdown() error {
+	e.report(context.Background())
+	return nil
+}
+
+// report samples the containers and posts the reports of those that exited
+func (e *Exporter) report(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	snapshot, err := e.monitor.Snapshot()
+	if err != nil {
+		e.logger.Warn("Failed to get snapshot", "error", err)
+		return
+	}
+	for _, report := range e.observe(snapshot) {
+		if err := e.post(ctx, report); err != nil {
+			e.logger.Error("Failed to post energy report", "container", report.ID, "error", err)
+		}
+	}
+}
+
+// observe updates the reports of the running containers from snapshot and
+// returns the reports of the containers that are no longer running. The energy
+// of a container terminated in snapshot is its final energy; that of one
+// missing from snapshot is its last known energy. Containers that started and
+// exited between two snapshots are reported as started at the earlier one
+func (e *Exporter) observe(snapshot *monitor.Snapshot) []Report {
+	if !snapshot.Timestamp.After(e.lastSnapshot) {
+		return nil // already observed
+	}
+	previous := e.lastSnapshot
+	if previous.IsZero() {
+		previous = snapshot.Timestamp
+	}
+	e.lastSnapshot = snapshot.Timestamp
+
+	for id, c := range snapshot.Containers {
+		report, ok := e.running[id]
+		if !ok {
+			report = e.newReport(snapshot, c, snapshot.Timestamp)
+			e.running[id] = report
+		}
+		update(report, c.Zones, true)
+	}
+
+	// terminated containers stay in snapshots until they are exported, so
+	// those reported are remembered until they are dropped
+	reported := map[string]struct{}{}
+	var exited []Report
+	for id, report := range e.running {
+		if _, ok := snapshot.Containers[id]; ok {
+			continue
+		}
+		if c, ok := snapshot.TerminatedContainers[id]; ok {
+			update(report, c.Zones, false)
+		}
+		exited = append(exited, e.exit(report, snapshot.Timestamp))
+		reported[id] = struct{}{}
+		delete(e.running, id)
+	}
+
+	for id, c := range snapshot.TerminatedContainers {
+		if _, ok := reported[id]; ok {
+			continue
+		}
+		reported[id] = struct{}{}
+		if _, ok := e.reported[id]; ok {
+			continue
+		}
+		report := e.newReport(snapshot, c, previous)
+		update(report, c.Zones, true)
+		exited = append(exited, e.exit(report, snapshot.Timestamp))
+	}
+	e.reported = reported
+
+	return exited
+}
+
+func (e *Exporter) newReport(snapshot *monitor.Snapshot, c *monitor.Container, start time.Time) *Report {
+	report := &Report{
+		Node:      e.nodeName,
+		ID:        c.ID,
+		Name:      c.Name,
+		Image:     c.Image,
+		Runtime:   string(c.Runtime),
+		StartTime: start,
+	}
+	pod, ok := snapshot.Pods[c.PodID]
+	if !ok {
+		pod, ok = snapshot.TerminatedPods[c.PodID]
+	}
+	if ok {
+		report.Pod = pod.Name
+		report.Namespace = pod.Namespace
+	}
+	return report
+}
+
+func (e *Exporter) exit(report *Report, end time.Time) Report {
+	report.EndTime = end
+	report.DurationSeconds = end.Sub(report.StartTime).Seconds()
+	return *report
+}
+
+// update sets the energy of report to that of zones and, if running, raises
+// its peak power to the power of zones
+func update(report *Report, zones monitor.ZoneUsageMap, running bool) {
+	report.Zones = make(map[string]float64, len(zones))
+	report.Joules = 0
+	var watts float64
+	for zone, usage := range zones {
+		joules := usage.EnergyTotal.Joules()
+		report.Zones[zone.Name()] = joules
+		// zones overlap on some platforms, so the zone that consumed the most
+		// is reported
+		if joules >= report.Joules {
+			report.Joules = joules
+			watts = usage.Power.Watts()
+		}
+	}
+	if running {
+		report.PeakWatts = max(report.PeakWatts, watts)
+	}
+}
+
+func (e *Exporter) post(ctx context.Context, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post report: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
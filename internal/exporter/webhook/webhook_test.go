@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var (
+	pkg  = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:0", 1000*device.Joule)
+)
+
+// container returns a container in a pod with its energy in joules and power
+// in watts in the package zone, and a tenth of them in the dram zone
+func container(id string, joules, watts float64) *monitor.Container {
+	return &monitor.Container{
+		ID:      id,
+		Name:    "build",
+		Image:   "golang:1.23",
+		Runtime: "containerd",
+		PodID:   "pod-1",
+		Zones: monitor.ZoneUsageMap{
+			pkg:  {EnergyTotal: monitor.Energy(joules) * device.Joule, Power: monitor.Power(watts) * device.Watt},
+			dram: {EnergyTotal: monitor.Energy(joules/10) * device.Joule, Power: monitor.Power(watts/10) * device.Watt},
+		},
+	}
+}
+
+// snapshot returns a snapshot at ts of the running and terminated containers
+func snapshot(ts time.Time, running, terminated []*monitor.Container) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = ts
+	s.Pods["pod-1"] = &monitor.Pod{ID: "pod-1", Name: "ci-job-42", Namespace: "ci"}
+	for _, c := range running {
+		s.Containers[c.ID] = c
+	}
+	for _, c := range terminated {
+		s.TerminatedContainers[c.ID] = c
+	}
+	return s
+}
+
+func TestExporterObserve(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	e := NewExporter(monitor.NewFakeMonitor(nil), "http://ci:8080/energy", WithNodeName("ci-1"))
+
+	assert.Empty(t, e.observe(snapshot(at(0), []*monitor.Container{container("c-1", 10, 2)}, nil)))
+	assert.Empty(t, e.observe(snapshot(at(0), nil, nil)), "a snapshot is observed once")
+	assert.Empty(t, e.observe(snapshot(at(5), []*monitor.Container{container("c-1", 60, 12), container("c-2", 5, 1)}, nil)))
+	assert.Empty(t, e.observe(snapshot(at(10), []*monitor.Container{container("c-1", 100, 8), container("c-2", 10, 1)}, nil)))
+
+	reports := e.observe(snapshot(at(15), []*monitor.Container{container("c-2", 15, 1)},
+		[]*monitor.Container{container("c-1", 120, 4), container("c-3", 30, 6)}))
+	require.Len(t, reports, 2)
+	if reports[0].ID != "c-1" {
+		reports[0], reports[1] = reports[1], reports[0]
+	}
+	assert.Equal(t, Report{
+		Node:            "ci-1",
+		ID:              "c-1",
+		Name:            "build",
+		Image:           "golang:1.23",
+		Runtime:         "containerd",
+		Pod:             "ci-job-42",
+		Namespace:       "ci",
+		StartTime:       at(0),
+		EndTime:         at(15),
+		DurationSeconds: 15,
+		Joules:          120,
+		PeakWatts:       12,
+		Zones:           map[string]float64{"package": 120, "dram": 12},
+	}, reports[0], "final energy of the terminated container")
+	assert.Equal(t, "c-3", reports[1].ID, "started and exited between snapshots")
+	assert.Equal(t, at(10), reports[1].StartTime)
+	assert.Equal(t, 5.0, reports[1].DurationSeconds)
+	assert.Equal(t, 6.0, reports[1].PeakWatts)
+
+	assert.Empty(t, e.observe(snapshot(at(20), []*monitor.Container{container("c-2", 20, 1)},
+		[]*monitor.Container{container("c-1", 120, 4), container("c-3", 30, 6)})),
+		"terminated containers are reported once")
+
+	reports = e.observe(snapshot(at(25), nil, nil))
+	require.Len(t, reports, 1)
+	assert.Equal(t, "c-2", reports[0].ID)
+	assert.Equal(t, 20.0, reports[0].Joules, "last known energy of a missing container")
+	assert.Equal(t, 20.0, reports[0].DurationSeconds)
+}
+
+func TestExporterRun(t *testing.T) {
+	reports := make(chan Report, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var report Report
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		reports <- report
+	}))
+	defer server.Close()
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	pm := monitor.NewFakeMonitor(snapshot(start, []*monitor.Container{container("c-1", 10, 2)}, nil))
+	e := NewExporter(pm, server.URL, WithClock(fakeClock), WithSampleInterval(time.Second))
+	require.NoError(t, e.Init())
+	assert.NotEmpty(t, e.nodeName, "the hostname if no node name is set")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, time.Millisecond)
+	fakeClock.Step(time.Second)
+	require.Eventually(t, func() bool { return pm.SnapshotCalls() == 1 }, time.Second, time.Millisecond)
+
+	pm.Set(snapshot(start.Add(5*time.Second), nil, []*monitor.Container{container("c-1", 50, 3)}))
+	fakeClock.Step(time.Second)
+
+	select {
+	case report := <-reports:
+		assert.Equal(t, "c-1", report.ID)
+		assert.Equal(t, 50.0, report.Joules)
+		assert.Equal(t, 5.0, report.DurationSeconds)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	// the containers that exited in the last snapshot are reported on shutdown
+	pm.Set(snapshot(start.Add(7*time.Second), nil, []*monitor.Container{container("c-2", 8, 4)}))
+	require.NoError(t, e.Shutdown())
+	select {
+	case report := <-reports:
+		assert.Equal(t, "c-2", report.ID)
+		assert.Equal(t, 8.0, report.Joules)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called on shutdown")
+	}
+}
+
+func TestExporterPostError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	e := NewExporter(monitor.NewFakeMonitor(nil), server.URL)
+	err := e.post(context.Background(), Report{ID: "c-1"})
+	assert.ErrorContains(t, err, "503")
+
+	e = NewExporter(monitor.NewFakeMonitor(nil), "http://127.0.0.1:0")
+	assert.Error(t, e.post(context.Background(), Report{}))
+}
@@ -0,0 +1,492 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package federation aggregates the snapshots of other Kepler agents into the
+// power of the cluster, for small clusters that don't run Prometheus.
+package federation
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+	"github.com/sustainable-computing-io/kepler/pkg/client"
+	"k8s.io/utils/clock"
+)
+
+// Endpoint is the endpoint the power of the cluster is served at
+const Endpoint = "/cluster"
+
+// Names of the MCP tools
+const (
+	PowerToolName        = "get_cluster_power"
+	TopConsumersToolName = "get_cluster_top_consumers"
+)
+
+// defaultTopConsumers is the number of workloads returned by the top
+// consumers tool when no number is requested
+const defaultTopConsumers = 10
+
+type ToolRegistry = server.ToolRegistry
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// Agent is a Kepler agent whose snapshots are aggregated
+type Agent struct {
+	Name string // node of the agent; the host of URL if empty
+	URL  string // base URL of the web server of the agent, e.g. http://worker-1:28282
+}
+
+// NodePower is the power of a node in the last snapshot scraped from its agent
+type NodePower struct {
+	Node      string    `json:"node"`
+	Up        bool      `json:"up"`              // the last scrape succeeded
+	Error     string    `json:"error,omitempty"` // of the last scrape
+	Timestamp time.Time `json:"timestamp"`       // of the snapshot; zero if none was scraped
+	Zone      string    `json:"zone,omitempty"`  // zone of the node that consumed the most energy
+	Watts     float64   `json:"watts"`
+	Pods      int       `json:"pods"` // running pods
+}
+
+// NamespacePower is the power of the running pods of a namespace on all nodes
+type NamespacePower struct {
+	Namespace string  `json:"namespace"`
+	Watts     float64 `json:"watts"`
+	Pods      int     `json:"pods"`
+	Nodes     int     `json:"nodes"` // nodes running pods of the namespace
+}
+
+// Cluster is the power of the nodes of the cluster and of its namespaces.
+// Nodes whose agent couldn't be scraped are listed with their last power, but
+// are not counted in the power of the cluster and of namespaces
+type Cluster struct {
+	Timestamp  time.Time        `json:"timestamp"`
+	Watts      float64          `json:"watts"`      // of the nodes up
+	Nodes      []NodePower      `json:"nodes"`      // sorted by name
+	Namespaces []NamespacePower `json:"namespaces"` // sorted by power, highest first
+}
+
+// Consumer is a running workload of a node of the cluster
+type Consumer struct {
+	Node      string  `json:"node"`
+	Kind      string  `json:"kind"`
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Namespace string  `json:"namespace,omitempty"`
+	Watts     float64 `json:"watts"` // in the zone of the node that consumed the most energy
+}
+
+type Opts struct {
+	logger     *slog.Logger
+	clock      clock.WithTicker
+	interval   time.Duration
+	timeout    time.Duration
+	httpClient *http.Client
+	tools      ToolRegistry
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:   slog.Default(),
+		clock:    clock.RealClock{},
+		interval: 15 * time.Second,
+		timeout:  5 * time.Second,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Aggregator
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to schedule scrapes
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithInterval sets the interval between scrapes of the agents
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithTimeout sets the timeout of a scrape of an agent
+func WithTimeout(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.timeout = d
+	}
+}
+
+// WithHTTPClient sets the HTTP client the agents are scraped with
+func WithHTTPClient(c *http.Client) OptionFn {
+	return func(o *Opts) {
+		o.httpClient = c
+	}
+}
+
+// WithTools sets the registry the power of the cluster is served by as MCP
+// tools
+func WithTools(t ToolRegistry) OptionFn {
+	return func(o *Opts) {
+		o.tools = t
+	}
+}
+
+// agentState is the last snapshot scraped from an agent
+type agentState struct {
+	name     string
+	client   *client.Client
+	snapshot *api.Snapshot
+	err      error // of the last scrape
+}
+
+// Aggregator scrapes the snapshots of Kepler agents every interval and serves
+// the power of the cluster at /cluster and, if tools are set, as MCP tools
+type Aggregator struct {
+	logger   *slog.Logger
+	api      APIRegistry
+	tools    ToolRegistry
+	clock    clock.WithTicker
+	interval time.Duration
+	timeout  time.Duration
+
+	mu     sync.RWMutex
+	agents []*agentState // sorted by name
+}
+
+var (
+	_ service.Initializer = (*Aggregator)(nil)
+	_ service.Runner      = (*Aggregator)(nil)
+	_ service.Dependent   = (*Aggregator)(nil)
+)
+
+// NewAggregator creates a new Aggregator of the snapshots of agents that
+// serves the power of the cluster using api
+func NewAggregator(agents []Agent, api APIRegistry, applyOpts ...OptionFn) *Aggregator {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	var clientOpts []client.OptionFn
+	if opts.httpClient != nil {
+		clientOpts = append(clientOpts, client.WithHTTPClient(opts.httpClient))
+	}
+	states := make([]*agentState, 0, len(agents))
+	for _, a := range agents {
+		states = append(states, &agentState{
+			name:   AgentName(a),
+			client: client.New(a.URL, clientOpts...),
+		})
+	}
+	slices.SortFunc(states, func(a, b *agentState) int { return cmp.Compare(a.name, b.name) })
+
+	return &Aggregator{
+		logger:   opts.logger.With("service", "federation"),
+		api:      api,
+		tools:    opts.tools,
+		clock:    opts.clock,
+		interval: opts.interval,
+		timeout:  opts.timeout,
+		agents:   states,
+	}
+}
+
+// AgentName returns the name of the node of a, or the host of its URL if it
+// has no name
+func AgentName(a Agent) string {
+	if a.Name != "" {
+		return a.Name
+	}
+	if u, err := url.Parse(a.URL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return a.URL
+}
+
+func (a *Aggregator) Name() string {
+	return "federation"
+}
+
+// Dependencies returns the API server and MCP tools the power of the cluster
+// is served by
+func (a *Aggregator) Dependencies() []service.Service {
+	var deps []service.Service
+	if s, ok := a.api.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	if s, ok := a.tools.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (a *Aggregator) Init() error {
+	if err := a.api.Register(Endpoint, "Cluster", "Power of the nodes of the cluster and of its namespaces", http.HandlerFunc(a.handleCluster)); err != nil {
+		return err
+	}
+	if a.tools == nil {
+		return nil
+	}
+
+	str := func(description string) map[string]any {
+		return map[string]any{"type": "string", "description": description}
+	}
+	if err := a.tools.RegisterTool(PowerToolName,
+		"Power in watts of the cluster, of each node and of the pods of each namespace on all nodes, "+
+			"and whether the Kepler agent of each node could be reached",
+		map[string]any{"type": "object", "properties": map[string]any{
+			"namespace": str("Only return the power of the namespace"),
+		}},
+		a.callPowerTool); err != nil {
+		return err
+	}
+
+	return a.tools.RegisterTool(TopConsumersToolName,
+		"Running workloads drawing the most power on all nodes of the cluster, highest first",
+		map[string]any{"type": "object", "properties": map[string]any{
+			"kind":      str("Kind of the workloads: pod (default), container, vm or process"),
+			"namespace": str("Only return the workloads of the namespace"),
+			"n": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of workloads returned (default %d)", defaultTopConsumers),
+			},
+		}},
+		a.callTopConsumersTool)
+}
+
+// Run scrapes the agents every interval until ctx is cancelled
+func (a *Aggregator) Run(ctx context.Context) error {
+	ticker := a.clock.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	a.scrape(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			a.scrape(ctx)
+		}
+	}
+}
+
+// scrape reads the snapshots of all agents concurrently; the last snapshot of
+// an agent that can't be read is kept
+func (a *Aggregator) scrape(ctx context.Context) {
+	type result struct {
+		snapshot *api.Snapshot
+		err      error
+	}
+	results := make([]result, len(a.agents))
+
+	var wg sync.WaitGroup
+	for i, agent := range a.agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, a.timeout)
+			defer cancel()
+			results[i].snapshot, results[i].err = agent.client.Snapshot(ctx)
+		}()
+	}
+	wg.Wait()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, agent := range a.agents {
+		r := results[i]
+		if r.err != nil && agent.err == nil {
+			a.logger.Warn("Failed to scrape agent", "node", agent.name, "error", r.err)
+		} else if r.err == nil && agent.err != nil {
+			a.logger.Info("Scraped agent again", "node", agent.name)
+		}
+		agent.err = r.err
+		if r.err == nil {
+			agent.snapshot = r.snapshot
+		}
+	}
+}
+
+// primaryZone returns the zone of the node of s that consumed the most energy,
+// e.g. psys or package, since zones overlap on some platforms
+func primaryZone(s *api.Snapshot) string {
+	primary := ""
+	var joules float64
+	for name, z := range s.Node.Zones {
+		if primary == "" || z.Joules > joules || (z.Joules == joules && name < primary) {
+			primary, joules = name, z.Joules
+		}
+	}
+	return primary
+}
+
+// Cluster returns the power of the nodes of the cluster and of the pods of
+// namespace, or of all namespaces if namespace is empty
+func (a *Aggregator) Cluster(namespace string) Cluster {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	cluster := Cluster{
+		Timestamp:  a.clock.Now(),
+		Nodes:      make([]NodePower, 0, len(a.agents)),
+		Namespaces: []NamespacePower{},
+	}
+	namespaces := map[string]*NamespacePower{}
+	for _, agent := range a.agents {
+		node := NodePower{Node: agent.name, Up: agent.err == nil && agent.snapshot != nil}
+		if agent.err != nil {
+			node.Error = agent.err.Error()
+		}
+		if agent.snapshot == nil {
+			cluster.Nodes = append(cluster.Nodes, node)
+			continue
+		}
+
+		s := agent.snapshot
+		node.Timestamp = s.Timestamp
+		node.Zone = primaryZone(s)
+		node.Watts = s.Node.Zones[node.Zone].Watts
+
+		nodeNamespaces := map[string]bool{}
+		for _, w := range s.Workloads {
+			if w.Kind != api.KindPod || w.Terminated {
+				continue
+			}
+			node.Pods++
+			if !node.Up || (namespace != "" && w.Namespace != namespace) {
+				continue
+			}
+			ns, ok := namespaces[w.Namespace]
+			if !ok {
+				ns = &NamespacePower{Namespace: w.Namespace}
+				namespaces[w.Namespace] = ns
+			}
+			ns.Watts += w.Zones[node.Zone].Watts
+			ns.Pods++
+			if !nodeNamespaces[w.Namespace] {
+				nodeNamespaces[w.Namespace] = true
+				ns.Nodes++
+			}
+		}
+		if node.Up {
+			cluster.Watts += node.Watts
+		}
+		cluster.Nodes = append(cluster.Nodes, node)
+	}
+
+	for _, ns := range namespaces {
+		cluster.Namespaces = append(cluster.Namespaces, *ns)
+	}
+	slices.SortFunc(cluster.Namespaces, func(a, b NamespacePower) int {
+		return cmp.Or(cmp.Compare(b.Watts, a.Watts), cmp.Compare(a.Namespace, b.Namespace))
+	})
+	return cluster
+}
+
+// TopConsumers returns the n running workloads of kind, optionally of
+// namespace, drawing the most power on the nodes up, highest first. All are
+// returned if n is not positive
+func (a *Aggregator) TopConsumers(kind, namespace string, n int) []Consumer {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	ret := []Consumer{}
+	for _, agent := range a.agents {
+		if agent.err != nil || agent.snapshot == nil {
+			continue
+		}
+		zone := primaryZone(agent.snapshot)
+		for _, w := range agent.snapshot.Workloads {
+			if w.Kind != kind || w.Terminated || (namespace != "" && w.Namespace != namespace) {
+				continue
+			}
+			ret = append(ret, Consumer{
+				Node:      agent.name,
+				Kind:      w.Kind,
+				ID:        w.ID,
+				Name:      w.Name,
+				Namespace: w.Namespace,
+				Watts:     w.Zones[zone].Watts,
+			})
+		}
+	}
+	slices.SortFunc(ret, func(a, b Consumer) int {
+		return cmp.Or(cmp.Compare(b.Watts, a.Watts), cmp.Compare(a.Node, b.Node), cmp.Compare(a.ID, b.ID))
+	})
+	if n > 0 && len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret
+}
+
+// handleCluster serves the power of the cluster, optionally of the pods of the
+// namespace query parameter
+func (a *Aggregator) handleCluster(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(a.Cluster(req.URL.Query().Get("namespace"))); err != nil {
+		a.logger.Error("Failed to write cluster power", "error", err)
+	}
+}
+
+func (a *Aggregator) callPowerTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		Namespace string `json:"namespace"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+	return a.Cluster(params.Namespace), nil
+}
+
+func (a *Aggregator) callTopConsumersTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		Kind      string `json:"kind"`
+		Namespace string `json:"namespace"`
+		N         int    `json:"n"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+	kind := cmp.Or(params.Kind, api.KindPod)
+	switch kind {
+	case api.KindPod, api.KindContainer, api.KindVM, api.KindProcess:
+	default:
+		return nil, fmt.Errorf("invalid kind: %q; must be one of pod, container, vm or process", kind)
+	}
+	if params.N < 0 {
+		return nil, fmt.Errorf("invalid n: %d; must be positive", params.N)
+	}
+	return a.TopConsumers(kind, params.Namespace, cmp.Or(params.N, defaultTopConsumers)), nil
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+	"github.com/sustainable-computing-io/kepler/pkg/client"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// agent starts a Kepler agent serving s, or failing if s is nil
+func agent(t *testing.T, s *api.Snapshot) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s == nil || r.URL.Path != client.SnapshotEndpoint {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		b, err := api.MarshalProto(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", api.ContentTypeProtobuf)
+		_, _ = w.Write(b)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// node returns a snapshot of a node drawing watts in the package zone, its
+// primary zone, and of its pods
+func node(watts float64, pods ...api.Workload) *api.Snapshot {
+	return &api.Snapshot{
+		Version:   api.Version,
+		Timestamp: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Node: api.Node{Zones: map[string]api.NodeZone{
+			"package": {Joules: 1000, Watts: watts},
+			"dram":    {Joules: 100, Watts: 5},
+		}},
+		Workloads: pods,
+	}
+}
+
+func pod(id, namespace string, watts float64) api.Workload {
+	return api.Workload{Kind: api.KindPod, ID: id, Name: id, Namespace: namespace, Zones: map[string]api.Zone{
+		"package": {Watts: watts},
+		"dram":    {Watts: 1},
+	}}
+}
+
+func TestAggregator(t *testing.T) {
+	worker1 := agent(t, node(100, pod("api-1", "prod", 30), pod("job", "dev", 10)))
+	worker2 := agent(t, node(80,
+		pod("api-2", "prod", 25),
+		api.Workload{Kind: api.KindPod, ID: "done", Namespace: "prod", Terminated: true, Zones: map[string]api.Zone{"package": {Watts: 50}}},
+		api.Workload{Kind: api.KindContainer, ID: "c-1", Name: "server", Zones: map[string]api.Zone{"package": {Watts: 20}}},
+	))
+	down := agent(t, nil)
+
+	now := time.Date(2025, 6, 1, 12, 0, 5, 0, time.UTC)
+	registry := server.NewFakeRegistry()
+	a := NewAggregator([]Agent{
+		{Name: "worker-2", URL: worker2.URL},
+		{Name: "worker-1", URL: worker1.URL + "/"},
+		{URL: down.URL},
+	}, registry, WithClock(testingclock.NewFakeClock(now)), WithTools(registry))
+	assert.Equal(t, "federation", a.Name())
+	require.NoError(t, a.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+	require.Contains(t, registry.Tools, PowerToolName)
+	require.Contains(t, registry.Tools, TopConsumersToolName)
+
+	a.scrape(context.Background())
+	cluster := a.Cluster("")
+	assert.Equal(t, now, cluster.Timestamp)
+	assert.Equal(t, 180.0, cluster.Watts)
+	require.Len(t, cluster.Nodes, 3)
+	assert.Equal(t, "127.0.0.1", cluster.Nodes[0].Node, "agents without a name are named after their host")
+	assert.False(t, cluster.Nodes[0].Up)
+	assert.Contains(t, cluster.Nodes[0].Error, "503 Service Unavailable")
+	assert.Equal(t, NodePower{
+		Node: "worker-1", Up: true, Timestamp: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Zone: "package", Watts: 100, Pods: 2,
+	}, cluster.Nodes[1])
+	assert.Equal(t, 1, cluster.Nodes[2].Pods, "terminated pods are not counted")
+	assert.Equal(t, []NamespacePower{
+		{Namespace: "prod", Watts: 55, Pods: 2, Nodes: 2},
+		{Namespace: "dev", Watts: 10, Pods: 1, Nodes: 1},
+	}, cluster.Namespaces)
+
+	top := a.TopConsumers(api.KindPod, "", 2)
+	require.Len(t, top, 2)
+	assert.Equal(t, Consumer{Node: "worker-1", Kind: api.KindPod, ID: "api-1", Name: "api-1", Namespace: "prod", Watts: 30}, top[0])
+	assert.Equal(t, "api-2", top[1].ID)
+
+	t.Run("REST", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cluster?namespace=dev", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got Cluster
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		require.Len(t, got.Namespaces, 1)
+		assert.Equal(t, 10.0, got.Namespaces[0].Watts)
+		assert.Equal(t, 180.0, got.Watts, "the power of nodes isn't filtered by namespace")
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/cluster", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("MCP tools", func(t *testing.T) {
+		got, err := registry.Tools[PowerToolName](context.Background(), json.RawMessage(`{"namespace":"prod"}`))
+		require.NoError(t, err)
+		assert.Len(t, got.(Cluster).Namespaces, 1)
+
+		got, err = registry.Tools[TopConsumersToolName](context.Background(), json.RawMessage(`{"kind":"container"}`))
+		require.NoError(t, err)
+		require.Len(t, got.([]Consumer), 1)
+		assert.Equal(t, "worker-2", got.([]Consumer)[0].Node)
+
+		got, err = registry.Tools[TopConsumersToolName](context.Background(), json.RawMessage(`{"namespace":"prod"}`))
+		require.NoError(t, err)
+		assert.Len(t, got.([]Consumer), 2)
+
+		_, err = registry.Tools[TopConsumersToolName](context.Background(), json.RawMessage(`{"kind":"node"}`))
+		assert.ErrorContains(t, err, "invalid kind")
+	})
+
+	t.Run("agent down", func(t *testing.T) {
+		worker1.Close()
+		a.scrape(context.Background())
+		cluster := a.Cluster("")
+		assert.Equal(t, 80.0, cluster.Watts, "nodes down are not counted")
+		assert.False(t, cluster.Nodes[1].Up)
+		assert.Equal(t, 100.0, cluster.Nodes[1].Watts, "the last power of nodes down is kept")
+		assert.Len(t, a.TopConsumers(api.KindPod, "", 0), 1)
+	})
+}
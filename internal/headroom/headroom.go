@@ -0,0 +1,518 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package headroom serves a compact summary of the power of the node, its cap
+// and its trend, to be polled by energy-aware scheduler plugins and
+// deschedulers. The summary is computed as the node is sampled, so requests
+// are served without reading the monitor. It is also served as an MCP tool
+// telling whether the node can take more load within its power budget.
+package headroom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+// Endpoint is the endpoint the summary is served at
+const Endpoint = "/headroom"
+
+// ToolName is the name of the MCP tool telling whether the node can take more
+// load within its power budget
+const ToolName = "get_power_headroom"
+
+type (
+	Monitor      = monitor.Service
+	Power        = device.Power
+	ToolRegistry = server.ToolRegistry
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// CapFn returns the power cap of the node; 0 if it is not capped
+type CapFn func() Power
+
+// CapacityFn returns the power the node can draw, e.g. PowerCapacityWatts
+// reported by its BMC; 0 if it is not known
+type CapacityFn func() (Power, error)
+
+// Summary is the power of the node and its headroom below the cap
+type Summary struct {
+	Node      string    `json:"node,omitempty"`
+	Timestamp time.Time `json:"timestamp"` // of the last sample
+	Zone      string    `json:"zone"`      // the power is of, e.g. platform or package
+
+	Watts         float64 `json:"watts"`    // in the last interval
+	AvgWatts      float64 `json:"avgWatts"` // over the window
+	MaxWatts      float64 `json:"maxWatts"` // over the window
+	CPUUsageRatio float64 `json:"cpuUsageRatio"`
+
+	// TrendWattsPerMinute is the slope of the power over the window; positive
+	// if the power is increasing
+	TrendWattsPerMinute float64 `json:"trendWattsPerMinute"`
+
+	CapWatts      float64 `json:"capWatts,omitempty"`      // omitted if the node is not capped
+	CapacityWatts float64 `json:"capacityWatts,omitempty"` // omitted if not known
+
+	// HeadroomWatts is the budget, the lower of the cap and the capacity,
+	// minus watts; omitted if the node has neither
+	HeadroomWatts *float64 `json:"headroomWatts,omitempty"`
+
+	WindowSeconds float64 `json:"windowSeconds"` // covered by the samples
+}
+
+type Opts struct {
+	logger         *slog.Logger
+	clock          clock.WithTicker
+	window         time.Duration
+	sampleInterval time.Duration
+	nodeName       string
+	capFn          CapFn
+	capacityFn     CapacityFn
+	tools          ToolRegistry
+	events         *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:         slog.Default(),
+		clock:          clock.RealClock{},
+		window:         5 * time.Minute,
+		sampleInterval: 5 * time.Second,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Reporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to sample the node
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithWindow sets the window the average, max and trend of the power are
+// computed over
+func WithWindow(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.window = d
+	}
+}
+
+// WithSampleInterval sets the interval between samples of the node; it should
+// be the interval of the monitor
+func WithSampleInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.sampleInterval = d
+	}
+}
+
+// WithNodeName sets the name of the node reported
+func WithNodeName(name string) OptionFn {
+	return func(o *Opts) {
+		o.nodeName = name
+	}
+}
+
+// WithCap sets the function returning the power cap of the node
+func WithCap(fn CapFn) OptionFn {
+	return func(o *Opts) {
+		o.capFn = fn
+	}
+}
+
+// WithCapacity sets the function returning the power the node can draw; it is
+// called until the capacity is known
+func WithCapacity(fn CapacityFn) OptionFn {
+	return func(o *Opts) {
+		o.capacityFn = fn
+	}
+}
+
+// WithTools sets the registry the headroom is served by as an MCP tool
+func WithTools(t ToolRegistry) OptionFn {
+	return func(o *Opts) {
+		o.tools = t
+	}
+}
+
+// WithEventBus samples the node whenever the monitor publishes a new snapshot
+// on bus, rather than every sample interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// sample is the power of the node at a point in time
+type sample struct {
+	timestamp time.Time
+	watts     float64
+}
+
+// Reporter samples the power of the node and serves its summary at /headroom
+type Reporter struct {
+	logger         *slog.Logger
+	monitor        Monitor
+	api            APIRegistry
+	clock          clock.WithTicker
+	window         time.Duration
+	sampleInterval time.Duration
+	events         *event.Bus
+	snapshots      *event.Subscription // nil if sampled every sample interval
+	nodeName       string
+	capFn          CapFn
+	capacityFn     CapacityFn
+	tools          ToolRegistry
+
+	mu       sync.Mutex
+	samples  []sample // within the window, oldest first
+	summary  *Summary // nil until the node is sampled
+	capacity Power    // 0 until known
+}
+
+var (
+	_ service.Initializer = (*Reporter)(nil)
+	_ service.Runner      = (*Reporter)(nil)
+	_ service.Dependent   = (*Reporter)(nil)
+)
+
+// NewReporter creates a new Reporter of the power of pm that serves the summary
+// using api
+func NewReporter(pm Monitor, api APIRegistry, applyOpts ...OptionFn) *Reporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Reporter{
+		logger:         opts.logger.With("service", "headroom"),
+		monitor:        pm,
+		api:            api,
+		clock:          opts.clock,
+		window:         opts.window,
+		sampleInterval: opts.sampleInterval,
+		events:         opts.events,
+		nodeName:       opts.nodeName,
+		capFn:          opts.capFn,
+		capacityFn:     opts.capacityFn,
+		tools:          opts.tools,
+	}
+}
+
+func (r *Reporter) Name() string {
+	return "headroom"
+}
+
+// Dependencies returns the monitor, and the API server and MCP tools the
+// summary is served by
+func (r *Reporter) Dependencies() []service.Service {
+	deps := []service.Service{r.monitor}
+	if s, ok := r.api.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	if s, ok := r.tools.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (r *Reporter) Init() error {
+	if r.events != nil {
+		r.snapshots = r.events.Subscribe(r.Name(), 1, event.SnapshotReady)
+	}
+	if err := r.api.Register(Endpoint, "Headroom", "Power, cap and trend of the node for schedulers", http.HandlerFunc(r.handleSummary)); err != nil {
+		return err
+	}
+	if r.tools == nil {
+		return nil
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"additionalWatts": map[string]any{
+				"type":        "number",
+				"description": "Power the additional load is expected to draw, in watts",
+			},
+		},
+	}
+	return r.tools.RegisterTool(ToolName,
+		"Whether the node can take more load without exceeding its power budget, the lower of its power cap "+
+			"and its capacity reported by the BMC, with its current, average and max power and its trend",
+		schema, r.callTool)
+}
+
+// Run samples the node until ctx is cancelled
+func (r *Reporter) Run(ctx context.Context) error {
+	var ready <-chan event.Event
+	if r.snapshots != nil {
+		defer r.snapshots.Close()
+		ready = r.snapshots.C()
+	}
+	var tick <-chan time.Time
+	if ready == nil {
+		ticker := r.clock.NewTicker(r.sampleInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-ready:
+		}
+		r.readCapacity()
+		snapshot, err := r.monitor.LatestSnapshot()
+		if err != nil {
+			r.logger.Warn("Failed to get snapshot", "error", err)
+			continue
+		}
+		r.observe(snapshot)
+	}
+}
+
+// readCapacity reads the capacity of the node if it is not known yet
+func (r *Reporter) readCapacity() {
+	if r.capacityFn == nil {
+		return
+	}
+	r.mu.Lock()
+	known := r.capacity > 0
+	r.mu.Unlock()
+	if known {
+		return
+	}
+
+	capacity, err := r.capacityFn()
+	if err != nil {
+		r.logger.Debug("Failed to read power capacity", "error", err)
+		return
+	}
+	r.mu.Lock()
+	r.capacity = capacity
+	r.mu.Unlock()
+}
+
+// observe adds the power of the node in snapshot to the samples and updates
+// the summary; snapshots already observed are ignored
+func (r *Reporter) observe(snapshot *monitor.Snapshot) {
+	zone := primaryZone(snapshot.Node)
+	if zone == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n := len(r.samples); n > 0 && !snapshot.Timestamp.After(r.samples[n-1].timestamp) {
+		return
+	}
+	r.samples = append(r.samples, sample{timestamp: snapshot.Timestamp, watts: snapshot.Node.Zones[zone].Power.Watts()})
+
+	// drop the samples that fell out of the window
+	first := 0
+	for first < len(r.samples)-1 && snapshot.Timestamp.Sub(r.samples[first].timestamp) > r.window {
+		first++
+	}
+	r.samples = r.samples[first:]
+
+	summary := &Summary{
+		Node:          r.nodeName,
+		Timestamp:     snapshot.Timestamp,
+		Zone:          zone.Name(),
+		Watts:         r.samples[len(r.samples)-1].watts,
+		CPUUsageRatio: snapshot.Node.UsageRatio,
+		WindowSeconds: snapshot.Timestamp.Sub(r.samples[0].timestamp).Seconds(),
+	}
+	total := 0.0
+	for _, s := range r.samples {
+		total += s.watts
+		summary.MaxWatts = max(summary.MaxWatts, s.watts)
+	}
+	summary.AvgWatts = total / float64(len(r.samples))
+	summary.TrendWattsPerMinute = trend(r.samples) * 60
+	r.summary = summary
+}
+
+// trend returns the least squares slope of the power of samples in watts per
+// second; 0 if there are fewer than two samples
+func trend(samples []sample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.timestamp.Sub(samples[0].timestamp).Seconds()
+		sumX += x
+		sumY += s.watts
+		sumXY += x * s.watts
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
+// primaryZone returns the zone of the node that consumed the most energy,
+// e.g. platform, psys or package, since zones overlap on some platforms
+func primaryZone(node *monitor.Node) monitor.EnergyZone {
+	if node == nil {
+		return nil
+	}
+	var primary monitor.EnergyZone
+	var energy monitor.Energy
+	for zone, usage := range node.Zones {
+		if primary == nil || usage.EnergyTotal > energy ||
+			(usage.EnergyTotal == energy && zone.Name() < primary.Name()) {
+			primary, energy = zone, usage.EnergyTotal
+		}
+	}
+	return primary
+}
+
+// Summary returns the summary of the last sample with the current cap; false
+// if the node wasn't sampled yet
+func (r *Reporter) Summary() (Summary, bool) {
+	r.mu.Lock()
+	if r.summary == nil {
+		r.mu.Unlock()
+		return Summary{}, false
+	}
+	summary := *r.summary
+	summary.CapacityWatts = r.capacity.Watts()
+	r.mu.Unlock()
+
+	if r.capFn != nil {
+		summary.CapWatts = r.capFn().Watts()
+	}
+	if budget := budgetWatts(summary); budget > 0 {
+		headroom := budget - summary.Watts
+		summary.HeadroomWatts = &headroom
+	}
+	return summary, true
+}
+
+// budgetWatts returns the lower of the cap and the capacity of summary that
+// are set; 0 if neither is
+func budgetWatts(summary Summary) float64 {
+	switch {
+	case summary.CapWatts > 0 && summary.CapacityWatts > 0:
+		return min(summary.CapWatts, summary.CapacityWatts)
+	case summary.CapWatts > 0:
+		return summary.CapWatts
+	default:
+		return summary.CapacityWatts
+	}
+}
+
+// Assessment tells whether the node can take more load within its budget
+type Assessment struct {
+	Summary
+
+	BudgetWatts     float64 `json:"budgetWatts,omitempty"` // the lower of the cap and the capacity
+	AdditionalWatts float64 `json:"additionalWatts"`       // expected to be drawn by the load
+
+	// ProjectedWatts is the power at the end of the next window if it keeps
+	// increasing at its trend, plus the additional watts
+	ProjectedWatts float64 `json:"projectedWatts"`
+
+	// CanTakeMoreLoad is true if the projected power is within the budget;
+	// omitted if the node has no budget
+	CanTakeMoreLoad *bool  `json:"canTakeMoreLoad,omitempty"`
+	Reason          string `json:"reason"`
+}
+
+// Assess tells whether the node can take load drawing additional watts without
+// exceeding its budget; false if the node wasn't sampled yet
+func (r *Reporter) Assess(additional float64) (Assessment, bool) {
+	summary, ok := r.Summary()
+	if !ok {
+		return Assessment{}, false
+	}
+
+	a := Assessment{
+		Summary:         summary,
+		BudgetWatts:     budgetWatts(summary),
+		AdditionalWatts: additional,
+		ProjectedWatts:  summary.Watts + max(summary.TrendWattsPerMinute, 0)*r.window.Minutes() + additional,
+	}
+	if a.BudgetWatts == 0 {
+		a.Reason = "the node has no power cap and its capacity is not known"
+		return a, true
+	}
+	fits := a.ProjectedWatts <= a.BudgetWatts
+	a.CanTakeMoreLoad = &fits
+	if fits {
+		a.Reason = fmt.Sprintf("the projected power of %.1f W is within the budget of %.1f W", a.ProjectedWatts, a.BudgetWatts)
+	} else {
+		a.Reason = fmt.Sprintf("the projected power of %.1f W exceeds the budget of %.1f W", a.ProjectedWatts, a.BudgetWatts)
+	}
+	return a, true
+}
+
+func (r *Reporter) callTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		AdditionalWatts float64 `json:"additionalWatts"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+	if params.AdditionalWatts < 0 {
+		return nil, fmt.Errorf("invalid additionalWatts %v; must not be negative", params.AdditionalWatts)
+	}
+	a, ok := r.Assess(params.AdditionalWatts)
+	if !ok {
+		return nil, fmt.Errorf("node not sampled yet")
+	}
+	return a, nil
+}
+
+// handleSummary serves the summary of the node
+func (r *Reporter) handleSummary(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	summary, ok := r.Summary()
+	if !ok {
+		http.Error(w, "node not sampled yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		r.logger.Error("Failed to write headroom", "error", err)
+	}
+}
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package headroom
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var (
+	pkg  = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:1", 1000*device.Joule)
+)
+
+// snapshot returns a snapshot of the node using watts in the package zone
+func snapshot(ts time.Time, watts float64) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = ts
+	s.Node = &monitor.Node{
+		Timestamp:  ts,
+		UsageRatio: 0.4,
+		Zones: monitor.NodeZoneUsageMap{
+			pkg:  {EnergyTotal: 1000 * device.Joule, Power: device.Power(watts) * device.Watt},
+			dram: {EnergyTotal: 100 * device.Joule, Power: 5 * device.Watt},
+		},
+	}
+	return s
+}
+
+func TestReporter(t *testing.T) {
+	start := time.Now()
+	nodeCap := 300 * device.Watt
+	registry := server.NewFakeRegistry()
+	r := NewReporter(monitor.NewFakeMonitor(nil), registry,
+		WithWindow(time.Minute),
+		WithNodeName("node-1"),
+		WithCap(func() Power { return nodeCap }))
+	assert.Equal(t, "headroom", r.Name())
+	require.NoError(t, r.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+
+	get := func() (int, Summary) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Endpoint, nil))
+		var summary Summary
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&summary))
+		}
+		return rec.Code, summary
+	}
+
+	code, _ := get()
+	assert.Equal(t, http.StatusServiceUnavailable, code, "the node is not sampled yet")
+
+	// the power increases by 10 W every 30s
+	r.observe(snapshot(start, 100))
+	r.observe(snapshot(start.Add(30*time.Second), 110))
+	r.observe(snapshot(start.Add(30*time.Second), 500)) // already observed
+	r.observe(snapshot(start.Add(60*time.Second), 120))
+
+	code, summary := get()
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "node-1", summary.Node)
+	assert.Equal(t, "package", summary.Zone, "the zone that consumed the most energy")
+	assert.Equal(t, 120.0, summary.Watts)
+	assert.Equal(t, 110.0, summary.AvgWatts)
+	assert.Equal(t, 120.0, summary.MaxWatts)
+	assert.Equal(t, 0.4, summary.CPUUsageRatio)
+	assert.InDelta(t, 20.0, summary.TrendWattsPerMinute, 1e-9)
+	assert.Equal(t, 300.0, summary.CapWatts)
+	require.NotNil(t, summary.HeadroomWatts)
+	assert.Equal(t, 180.0, *summary.HeadroomWatts)
+	assert.Equal(t, 60.0, summary.WindowSeconds)
+
+	// samples older than the window are dropped
+	r.observe(snapshot(start.Add(90*time.Second), 60))
+	_, summary = get()
+	assert.Equal(t, 60.0, summary.WindowSeconds)
+	assert.InDelta(t, (110.0+120+60)/3, summary.AvgWatts, 1e-9)
+	assert.Negative(t, summary.TrendWattsPerMinute)
+
+	// the cap is read on every request
+	nodeCap = 0
+	_, summary = get()
+	assert.Zero(t, summary.CapWatts)
+	assert.Nil(t, summary.HeadroomWatts, "no headroom without a cap")
+
+	rec := httptest.NewRecorder()
+	registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Endpoint, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestReporterAssess(t *testing.T) {
+	start := time.Now()
+	nodeCap := 0 * device.Watt
+	registry := server.NewFakeRegistry()
+	r := NewReporter(monitor.NewFakeMonitor(nil), registry,
+		WithWindow(time.Minute),
+		WithCap(func() Power { return nodeCap }),
+		WithTools(registry))
+	require.NoError(t, r.Init())
+	require.Contains(t, registry.Tools, ToolName)
+
+	call := func(args string) (Assessment, error) {
+		got, err := registry.Tools[ToolName](context.Background(), json.RawMessage(args))
+		if err != nil {
+			return Assessment{}, err
+		}
+		return got.(Assessment), nil
+	}
+
+	_, err := call(`{}`)
+	assert.ErrorContains(t, err, "not sampled yet")
+
+	// the power increases by 10 W every 30s
+	r.observe(snapshot(start, 100))
+	r.observe(snapshot(start.Add(30*time.Second), 110))
+	r.observe(snapshot(start.Add(60*time.Second), 120))
+
+	a, err := call(`{}`)
+	require.NoError(t, err)
+	assert.Nil(t, a.CanTakeMoreLoad, "no budget without a cap or capacity")
+	assert.Zero(t, a.BudgetWatts)
+
+	// the capacity is the budget unless the cap is lower
+	r.capacity = 200 * device.Watt
+	a, err = call(`{"additionalWatts": 30}`)
+	require.NoError(t, err)
+	assert.Equal(t, 200.0, a.BudgetWatts)
+	assert.Equal(t, 200.0, a.CapacityWatts)
+	assert.InDelta(t, 120+20+30, a.ProjectedWatts, 1e-9, "the trend over the next window is projected")
+	require.NotNil(t, a.CanTakeMoreLoad)
+	assert.True(t, *a.CanTakeMoreLoad)
+
+	nodeCap = 150 * device.Watt
+	a, err = call(`{"additionalWatts": 30}`)
+	require.NoError(t, err)
+	assert.Equal(t, 150.0, a.BudgetWatts)
+	assert.Equal(t, 30.0, *a.HeadroomWatts)
+	assert.False(t, *a.CanTakeMoreLoad)
+	assert.Contains(t, a.Reason, "exceeds the budget of 150.0 W")
+
+	_, err = call(`{"additionalWatts": -1}`)
+	assert.ErrorContains(t, err, "must not be negative")
+}
+
+func TestReporterRun(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	pm := monitor.NewFakeMonitor(snapshot(fakeClock.Now(), 100))
+	calls := 0
+	r := NewReporter(pm, server.NewFakeRegistry(), WithClock(fakeClock), WithSampleInterval(time.Second),
+		WithCapacity(func() (Power, error) {
+			calls++
+			return 500 * device.Watt, nil
+		}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		fakeClock.Step(time.Second)
+		_, ok := r.Summary()
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+	summary, _ := r.Summary()
+	assert.Equal(t, 500.0, summary.CapacityWatts)
+	assert.Equal(t, 1, calls, "the capacity is read until known")
+}
+
+func TestReporterEvents(t *testing.T) {
+	bus := event.NewBus()
+	pm := monitor.NewFakeMonitor(snapshot(time.Now(), 100))
+	r := NewReporter(pm, server.NewFakeRegistry(), WithEventBus(bus))
+	require.NoError(t, r.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+	bus.Publish(event.Event{Kind: event.SnapshotReady})
+	assert.Eventually(t, func() bool {
+		_, ok := r.Summary()
+		return ok
+	}, time.Second, time.Millisecond, "the node is sampled when a snapshot is ready")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
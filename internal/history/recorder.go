@@ -0,0 +1,528 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/config"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+	"k8s.io/utils/clock"
+)
+
+// Endpoint is the endpoint the history is served at
+const Endpoint = "/history"
+
+// ToolName is the name of the MCP tool returning the history
+const ToolName = "get_power_history"
+
+// defaultLimit is the number of rows returned when no limit is requested
+const defaultLimit = 1000
+
+type (
+	Monitor      = monitor.Service
+	ToolRegistry = server.ToolRegistry
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+type Opts struct {
+	logger          *slog.Logger
+	clock           clock.WithTicker
+	interval        time.Duration
+	retention       time.Duration
+	hourlyRetention time.Duration
+	dailyRetention  time.Duration
+	metricsLevel    config.Level
+	tools           ToolRegistry
+	events          *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:          slog.Default(),
+		clock:           clock.RealClock{},
+		interval:        5 * time.Second,
+		retention:       24 * time.Hour,
+		hourlyRetention: 7 * 24 * time.Hour,
+		dailyRetention:  90 * 24 * time.Hour,
+		metricsLevel:    config.MetricsLevelNode | config.MetricsLevelContainer | config.MetricsLevelVM | config.MetricsLevelPod,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Recorder
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to sample the monitor and prune old rows
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithInterval sets the interval between samples; it should be the interval of
+// the monitor, which is used instead if the monitor reports it
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithRetention sets how long rows are kept
+func WithRetention(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.retention = d
+	}
+}
+
+// WithRollupRetention sets how long hourly and daily rollups are kept
+func WithRollupRetention(hourly, daily time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.hourlyRetention = hourly
+		o.dailyRetention = daily
+	}
+}
+
+// WithMetricsLevel sets the levels recorded
+func WithMetricsLevel(level config.Level) OptionFn {
+	return func(o *Opts) {
+		o.metricsLevel = level
+	}
+}
+
+// WithTools sets the registry the history is served by as an MCP tool
+func WithTools(t ToolRegistry) OptionFn {
+	return func(o *Opts) {
+		o.tools = t
+	}
+}
+
+// WithEventBus samples the monitor whenever it publishes a new snapshot on
+// bus, rather than every interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// Recorder samples the power of the node and its workloads every interval into
+// a Store and rolls the rows up by hour and day. It serves the history at
+// /history and the rollups at /history/rollups and, if tools are set, as MCP
+// tools
+type Recorder struct {
+	logger          *slog.Logger
+	monitor         Monitor
+	store           Store
+	api             APIRegistry
+	tools           ToolRegistry
+	clock           clock.WithTicker
+	interval        time.Duration
+	retention       time.Duration
+	hourlyRetention time.Duration
+	dailyRetention  time.Duration
+	metricsLevel    config.Level
+	events          *event.Bus
+	snapshots       *event.Subscription // nil if sampled every interval
+
+	// only accessed by Init and Run
+	lastSnapshot time.Time
+	lastEnergy   map[rowKey]monitor.Energy
+	lastPrune    time.Time
+	nextRollup   map[Resolution]time.Time
+}
+
+// rowKey identifies the rows of a zone of the node or a workload
+type rowKey struct {
+	kind, id, zone string
+}
+
+var (
+	_ service.Initializer = (*Recorder)(nil)
+	_ service.Runner      = (*Recorder)(nil)
+	_ service.Shutdowner  = (*Recorder)(nil)
+	_ service.Dependent   = (*Recorder)(nil)
+)
+
+// NewRecorder creates a new Recorder of the power of pm into store that serves
+// the history using api
+func NewRecorder(pm Monitor, store Store, api APIRegistry, applyOpts ...OptionFn) *Recorder {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Recorder{
+		logger:          opts.logger.With("service", "history"),
+		monitor:         pm,
+		store:           store,
+		api:             api,
+		tools:           opts.tools,
+		clock:           opts.clock,
+		interval:        opts.interval,
+		retention:       opts.retention,
+		hourlyRetention: opts.hourlyRetention,
+		dailyRetention:  opts.dailyRetention,
+		metricsLevel:    opts.metricsLevel,
+		events:          opts.events,
+		lastEnergy:      map[rowKey]monitor.Energy{},
+		nextRollup:      map[Resolution]time.Time{},
+	}
+}
+
+func (r *Recorder) Name() string {
+	return "history"
+}
+
+// sampleInterval returns the interval between samples: that of the monitor if
+// it reports it, since it changes when the configuration is reloaded
+func (r *Recorder) sampleInterval() time.Duration {
+	if m, ok := r.monitor.(interface{ Interval() time.Duration }); ok && m.Interval() > 0 {
+		return m.Interval()
+	}
+	return r.interval
+}
+
+// Dependencies returns the monitor, and the API server and MCP tools the
+// history is served by
+func (r *Recorder) Dependencies() []service.Service {
+	deps := []service.Service{r.monitor}
+	if s, ok := r.api.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	if s, ok := r.tools.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (r *Recorder) Init() error {
+	if r.events != nil {
+		r.snapshots = r.events.Subscribe(r.Name(), 1, event.SnapshotReady)
+	}
+	r.prune()
+	if err := r.initRollups(); err != nil {
+		return fmt.Errorf("failed to read rollups: %w", err)
+	}
+
+	if err := r.api.Register(Endpoint, "History", "Power of the node and workloads in past intervals", http.HandlerFunc(r.handleHistory)); err != nil {
+		return err
+	}
+	if err := r.api.Register(RollupsEndpoint, "Rollups", "Energy of the node, namespaces, workloads and containers in past hours and days", http.HandlerFunc(r.handleRollups)); err != nil {
+		return err
+	}
+	if r.tools == nil {
+		return nil
+	}
+
+	str := func(description string) map[string]any {
+		return map[string]any{"type": "string", "description": description}
+	}
+	properties := func(what, kinds string) map[string]any {
+		return map[string]any{
+			"kind":      str(fmt.Sprintf("Only return %s of %s", what, kinds)),
+			"id":        str(fmt.Sprintf("Only return %s of the workload with the ID", what)),
+			"namespace": str(fmt.Sprintf("Only return %s of the namespace", what)),
+			"zone":      str(fmt.Sprintf("Only return %s of the zone, e.g. package", what)),
+			"since":     str(fmt.Sprintf("Only return %s of the last duration, e.g. 30m or 2h", what)),
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("Maximum number of most recent %s returned (default %d)", what, defaultLimit),
+			},
+		}
+	}
+	if err := r.tools.RegisterTool(ToolName,
+		"Average power in watts and energy in joules of the node and its workloads in each past monitor interval",
+		map[string]any{"type": "object", "properties": properties("rows", "node, process, container, vm, pod or workload, e.g. a Deployment")},
+		r.callTool); err != nil {
+		return err
+	}
+
+	if err := r.tools.RegisterTool(TrendsToolName,
+		"Average power in watts of each zone of the node in its most recent monitor intervals, oldest first, "+
+			"as compact arrays to describe how the power of zones changes over time",
+		map[string]any{"type": "object", "properties": map[string]any{
+			"zone": str("Only return the power of the zone, e.g. package"),
+			"samples": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of most recent intervals returned of each zone (default %d)", defaultTrendSamples),
+			},
+		}},
+		r.callTrendsTool); err != nil {
+		return err
+	}
+
+	rollupProperties := properties("rollups", "node, namespace, workload, e.g. a Deployment, or container")
+	rollupProperties["resolution"] = map[string]any{
+		"type":        "string",
+		"enum":        []string{string(Hourly), string(Daily)},
+		"description": "Period of the rollups; days start at midnight UTC",
+	}
+	return r.tools.RegisterTool(RollupsToolName,
+		"Energy in joules and average power in watts of the node, namespaces, workloads and containers in each past hour or day",
+		map[string]any{"type": "object", "properties": rollupProperties, "required": []string{"resolution"}},
+		r.callRollupsTool)
+}
+
+// Run samples the monitor until ctx is cancelled
+func (r *Recorder) Run(ctx context.Context) error {
+	var ready <-chan event.Event
+	if r.snapshots != nil {
+		defer r.snapshots.Close()
+		ready = r.snapshots.C()
+	}
+	var tick <-chan time.Time
+	if ready == nil {
+		ticker := r.clock.NewTicker(r.interval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-ready:
+		}
+		r.sample()
+		if r.clock.Since(r.lastPrune) >= time.Minute {
+			r.rollup()
+			r.prune()
+		}
+	}
+}
+
+// Shutdown closes the store
+func (r *Recorder) Shutdown() error {
+	return r.store.Close()
+}
+
+func (r *Recorder) sample() {
+	snapshot, err := r.monitor.LatestSnapshot()
+	if err != nil {
+		r.logger.Warn("Failed to get snapshot", "error", err)
+		return
+	}
+	if !snapshot.Timestamp.After(r.lastSnapshot) {
+		return // already recorded
+	}
+	r.lastSnapshot = snapshot.Timestamp
+
+	if err := r.store.Append(r.rows(snapshot)); err != nil {
+		r.logger.Error("Failed to record history", "error", err)
+	}
+}
+
+func (r *Recorder) prune() {
+	r.lastPrune = r.clock.Now()
+	if err := r.store.Prune(r.lastPrune.Add(-r.retention)); err != nil {
+		r.logger.Error("Failed to prune history", "error", err)
+	}
+	for res, retention := range map[Resolution]time.Duration{Hourly: r.hourlyRetention, Daily: r.dailyRetention} {
+		if err := r.store.PruneRollups(res, r.lastPrune.Add(-retention)); err != nil {
+			r.logger.Error("Failed to prune rollups", "resolution", res, "error", err)
+		}
+	}
+}
+
+// rows returns the rows of the node and the running workloads of snapshot. The
+// energy of a row is the energy consumed since the previous snapshot, or since
+// the workload was first seen
+func (r *Recorder) rows(snapshot *monitor.Snapshot) []Row {
+	var rows []Row
+	energy := make(map[rowKey]monitor.Energy, len(r.lastEnergy))
+
+	add := func(kind, id, name, namespace string, zone monitor.EnergyZone, total monitor.Energy, power monitor.Power, uncertainty float64) {
+		key := rowKey{kind, id, zone.Name()}
+		energy[key] = total
+		joules := total
+		if last, ok := r.lastEnergy[key]; ok && total >= last {
+			joules = total - last
+		}
+		rows = append(rows, Row{
+			Timestamp: snapshot.Timestamp,
+			Kind:      kind,
+			ID:        id,
+			Name:      name,
+			Namespace: namespace,
+			Zone:      zone.Name(),
+			Watts:     power.Watts(),
+			Joules:    joules.Joules(),
+
+			Uncertainty: uncertainty,
+		})
+	}
+	addZones := func(kind, id, name, namespace string, zones monitor.ZoneUsageMap) {
+		for zone, usage := range zones {
+			add(kind, id, name, namespace, zone, usage.EnergyTotal, usage.Power, usage.Uncertainty)
+		}
+	}
+
+	if r.metricsLevel.IsNodeEnabled() && snapshot.Node != nil {
+		for zone, usage := range snapshot.Node.Zones {
+			add(KindNode, "", "", "", zone, usage.EnergyTotal, usage.Power, usage.Uncertainty)
+		}
+	}
+	if r.metricsLevel.IsProcessEnabled() {
+		for _, p := range snapshot.Processes {
+			addZones(KindProcess, strconv.Itoa(p.PID), p.Comm, "", p.Zones)
+		}
+	}
+	if r.metricsLevel.IsContainerEnabled() {
+		for _, c := range snapshot.Containers {
+			var namespace string
+			if pod, ok := snapshot.Pods[c.PodID]; ok {
+				namespace = pod.Namespace
+			}
+			addZones(KindContainer, c.ID, c.Name, namespace, c.Zones)
+		}
+	}
+	if r.metricsLevel.IsVMEnabled() {
+		for _, vm := range snapshot.VirtualMachines {
+			addZones(KindVM, vm.ID, vm.Name, "", vm.Zones)
+		}
+	}
+	if r.metricsLevel.IsPodEnabled() {
+		for _, p := range snapshot.Pods {
+			addZones(KindPod, p.ID, p.Name, p.Namespace, p.Zones)
+		}
+		for _, w := range snapshot.OwnerWorkloads {
+			addZones(KindWorkload, w.Kind+"/"+w.Name, w.Name, w.Namespace, w.Zones)
+		}
+	}
+
+	r.lastEnergy = energy
+	return rows
+}
+
+// History is the response of the history endpoint and tool
+type History = api.History
+
+// handleHistory serves the rows selected by the kind, id, namespace, zone,
+// start, end (RFC 3339) and limit query parameters
+func (r *Recorder) handleHistory(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q, err := parseQuery(req.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := r.store.Query(q)
+	if err != nil {
+		r.logger.Error("Failed to query history", "error", err)
+		http.Error(w, "failed to query history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(History{Rows: rows}); err != nil {
+		r.logger.Error("Failed to write history", "error", err)
+	}
+}
+
+// parseQuery returns the query selected by the kind, id, namespace, zone,
+// start, end (RFC 3339) and limit query parameters
+func parseQuery(params url.Values) (Query, error) {
+	q := Query{
+		Kind:      params.Get("kind"),
+		ID:        params.Get("id"),
+		Namespace: params.Get("namespace"),
+		Zone:      params.Get("zone"),
+		Limit:     defaultLimit,
+	}
+	for name, t := range map[string]*time.Time{"start": &q.Start, "end": &q.End} {
+		if v := params.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return Query{}, fmt.Errorf("invalid %s: %q; must be an RFC 3339 time", name, v)
+			}
+			*t = parsed
+		}
+	}
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return Query{}, fmt.Errorf("invalid limit: %q; must be a positive integer", v)
+		}
+		q.Limit = limit
+	}
+	return q, nil
+}
+
+// toolArgs are the arguments of the MCP tools
+type toolArgs struct {
+	Kind      string `json:"kind"`
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	Zone      string `json:"zone"`
+	Since     string `json:"since"`
+	Limit     int    `json:"limit"`
+}
+
+// query returns the query selected by the arguments at now
+func (a toolArgs) query(now time.Time) (Query, error) {
+	q := Query{
+		Kind:      a.Kind,
+		ID:        a.ID,
+		Namespace: a.Namespace,
+		Zone:      a.Zone,
+		Limit:     cmp.Or(max(a.Limit, 0), defaultLimit),
+	}
+	if a.Since != "" {
+		since, err := time.ParseDuration(a.Since)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid since: %q; must be a duration, e.g. 30m", a.Since)
+		}
+		q.Start = now.Add(-since)
+	}
+	return q, nil
+}
+
+func (r *Recorder) callTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params toolArgs
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	q, err := params.query(r.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.store.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	return History{Rows: rows}, nil
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var pkg = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+
+// snapshot returns a snapshot at ts of the node and a pod with their
+// cumulative energy in joules and power in watts
+func snapshot(ts time.Time, nodeJoules, podJoules, podWatts float64) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = ts
+	s.Node = &monitor.Node{Timestamp: ts, Zones: monitor.NodeZoneUsageMap{
+		pkg: {EnergyTotal: monitor.Energy(nodeJoules) * device.Joule, Power: 100 * device.Watt},
+	}}
+	s.Pods["pod-1"] = &monitor.Pod{
+		ID:        "pod-1",
+		Name:      "api",
+		Namespace: "prod",
+		Zones: monitor.ZoneUsageMap{
+			pkg: {EnergyTotal: monitor.Energy(podJoules) * device.Joule, Power: monitor.Power(podWatts) * device.Watt},
+		},
+	}
+	s.Containers["c-1"] = &monitor.Container{
+		ID:    "c-1",
+		Name:  "server",
+		PodID: "pod-1",
+		Zones: s.Pods["pod-1"].Zones,
+	}
+	return s
+}
+
+func TestRecorder(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	pm := monitor.NewFakeMonitor(snapshot(start, 500, 50, 10))
+	registry := server.NewFakeRegistry()
+	store := NewMemoryStore()
+
+	r := NewRecorder(pm, store, registry, WithClock(fakeClock), WithTools(registry), WithRetention(time.Hour))
+	assert.Equal(t, "history", r.Name())
+	assert.Equal(t, "monitor", r.Dependencies()[0].Name())
+	require.NoError(t, r.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+	require.Contains(t, registry.Tools, ToolName)
+
+	r.sample()
+	r.sample() // the same snapshot is recorded once
+	pm.Set(snapshot(start.Add(5*time.Second), 1000, 150, 20))
+	r.sample()
+
+	rows, err := store.Query(Query{})
+	require.NoError(t, err)
+	assert.Len(t, rows, 6)
+
+	rows, err = store.Query(Query{Kind: KindPod})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, Row{
+		Timestamp: start, Kind: KindPod, ID: "pod-1", Name: "api", Namespace: "prod",
+		Zone: "package", Watts: 10, Joules: 50,
+	}, rows[0], "energy since the pod was first seen")
+	assert.Equal(t, 20.0, rows[1].Watts)
+	assert.Equal(t, 100.0, rows[1].Joules, "energy since the previous snapshot")
+
+	rows, err = store.Query(Query{Kind: KindContainer})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "prod", rows[0].Namespace, "namespace of the pod of the container")
+
+	t.Run("REST", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/history?kind=node&start=2025-06-01T12:00:01Z&limit=10", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got History
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		require.Len(t, got.Rows, 1)
+		assert.Equal(t, 500.0, got.Rows[0].Joules)
+
+		for _, query := range []string{"start=yesterday", "limit=0", "limit=ten"} {
+			rec = httptest.NewRecorder()
+			registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/history?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/history", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("MCP tool", func(t *testing.T) {
+		fakeClock.SetTime(start.Add(time.Minute))
+		got, err := registry.Tools[ToolName](context.Background(), json.RawMessage(`{"namespace":"prod","kind":"pod","since":"1m"}`))
+		require.NoError(t, err)
+		assert.Len(t, got.(History).Rows, 2)
+
+		got, err = registry.Tools[ToolName](context.Background(), json.RawMessage(`{"since":"58s"}`))
+		require.NoError(t, err)
+		assert.Len(t, got.(History).Rows, 3)
+
+		_, err = registry.Tools[ToolName](context.Background(), json.RawMessage(`{"since":"yesterday"}`))
+		assert.ErrorContains(t, err, "invalid since")
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		fakeClock.SetTime(start.Add(time.Hour + time.Second))
+		r.prune()
+		rows, err := store.Query(Query{})
+		require.NoError(t, err)
+		assert.Len(t, rows, 3)
+	})
+}
+
+func TestRecorderWorkloads(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := snapshot(start, 500, 50, 10)
+	s.OwnerWorkloads["prod/Deployment/api"] = &monitor.OwnerWorkload{
+		Kind: "Deployment", Name: "api", Namespace: "prod", Pods: 2,
+		Zones: monitor.ZoneUsageMap{pkg: {EnergyTotal: 80 * device.Joule, Power: 16 * device.Watt}},
+	}
+	store := NewMemoryStore()
+	r := NewRecorder(monitor.NewFakeMonitor(s), store, server.NewFakeRegistry(),
+		WithClock(testingclock.NewFakeClock(start)))
+	r.sample()
+
+	rows, err := store.Query(Query{Kind: KindWorkload})
+	require.NoError(t, err)
+	assert.Equal(t, []Row{{
+		Timestamp: start, Kind: KindWorkload, ID: "Deployment/api", Name: "api", Namespace: "prod",
+		Zone: "package", Watts: 16, Joules: 80,
+	}}, rows)
+}
+
+func TestRecorderRun(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	pm := monitor.NewFakeMonitor(snapshot(start, 500, 50, 10))
+	store := NewMemoryStore()
+	r := NewRecorder(pm, store, server.NewFakeRegistry(),
+		WithClock(fakeClock), WithInterval(time.Second))
+	require.NoError(t, r.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, time.Millisecond)
+	fakeClock.Step(time.Second)
+	require.Eventually(t, func() bool {
+		rows, _ := store.Query(Query{})
+		return len(rows) == 3
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.NoError(t, r.Shutdown())
+}
+
+func TestRecorderEvents(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	bus := event.NewBus()
+	pm := monitor.NewFakeMonitor(snapshot(start, 500, 50, 10))
+	store := NewMemoryStore()
+	r := NewRecorder(pm, store, server.NewFakeRegistry(), WithEventBus(bus))
+	require.NoError(t, r.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+	bus.Publish(event.Event{Kind: event.SnapshotReady})
+	assert.Eventually(t, func() bool {
+		rows, _ := store.Query(Query{})
+		return len(rows) == 3
+	}, time.Second, time.Millisecond, "the monitor is sampled when a snapshot is ready")
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.NoError(t, r.Shutdown())
+}
@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RollupsEndpoint is the endpoint the rollups are served at
+const RollupsEndpoint = "/history/rollups"
+
+// RollupsToolName is the name of the MCP tool returning the rollups
+const RollupsToolName = "get_energy_rollups"
+
+// KindNamespace is the kind of rollups of the pods of a namespace
+const KindNamespace = "namespace"
+
+// Resolution is the period of a rollup
+type Resolution string
+
+// Resolutions of rollups; periods start at whole UTC hours and days
+const (
+	Hourly Resolution = "hour"
+	Daily  Resolution = "day"
+)
+
+// Duration returns the length of the period
+func (r Resolution) Duration() time.Duration {
+	if r == Daily {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+func parseResolution(s string) (Resolution, error) {
+	switch res := Resolution(s); res {
+	case Hourly, Daily:
+		return res, nil
+	}
+	return "", fmt.Errorf("invalid resolution: %q; must be one of %s, %s", s, Hourly, Daily)
+}
+
+// Rollup is the energy consumed by the node, the pods of a namespace or of a
+// workload, or a container in a zone in an hour or a day
+type Rollup struct {
+	Start      time.Time  `json:"start"`
+	Resolution Resolution `json:"resolution"`
+	Kind       string     `json:"kind"`         // node, namespace, workload or container
+	ID         string     `json:"id,omitempty"` // of workloads and containers
+	Name       string     `json:"name,omitempty"`
+	Namespace  string     `json:"namespace,omitempty"`
+	Zone       string     `json:"zone"`
+	Watts      float64    `json:"watts"` // average power in the period
+	Joules     float64    `json:"joules"`
+}
+
+func (r Rollup) start() time.Time {
+	return r.Start
+}
+
+// row returns the fields of r a Query selects by
+func (r Rollup) row() Row {
+	return Row{Timestamp: r.Start, Kind: r.Kind, ID: r.ID, Namespace: r.Namespace, Zone: r.Zone}
+}
+
+// RollupQuery selects rollups of a resolution; Start and End select by the
+// start of the period
+type RollupQuery struct {
+	Query
+	Resolution Resolution
+}
+
+// rollupKey identifies the rollups of a zone of the node, a namespace, a
+// workload or a container
+type rollupKey struct {
+	kind, id, namespace, zone string
+}
+
+// initRollups sets the start of the next rollup of each resolution to the end
+// of the most recent one stored or, if there is none, to the first period
+// whose data is retained
+func (r *Recorder) initRollups() error {
+	now := r.clock.Now()
+	for res, retention := range map[Resolution]time.Duration{Hourly: r.retention, Daily: r.hourlyRetention} {
+		latest, err := r.store.QueryRollups(RollupQuery{Resolution: res, Query: Query{Limit: 1}})
+		if err != nil {
+			return err
+		}
+		if len(latest) > 0 {
+			r.nextRollup[res] = latest[0].Start.Add(res.Duration())
+			continue
+		}
+		r.nextRollup[res] = now.Add(-retention).Truncate(res.Duration()).Add(res.Duration())
+	}
+	return nil
+}
+
+// rollup stores the rollups of the periods that ended since the last rollup;
+// hourly rollups are computed from rows and daily rollups from hourly rollups
+func (r *Recorder) rollup() {
+	now := r.clock.Now()
+	for _, res := range []Resolution{Hourly, Daily} {
+		for start := r.nextRollup[res]; !start.Add(res.Duration()).After(now); start = start.Add(res.Duration()) {
+			rollups, err := r.computeRollups(res, start)
+			if err == nil {
+				err = r.store.AppendRollups(rollups)
+			}
+			if err != nil {
+				r.logger.Error("Failed to roll up history", "resolution", res, "start", start, "error", err)
+				break
+			}
+			r.nextRollup[res] = start.Add(res.Duration())
+		}
+	}
+}
+
+func (r *Recorder) computeRollups(res Resolution, start time.Time) ([]Rollup, error) {
+	end := start.Add(res.Duration())
+	joules := map[rollupKey]float64{}
+	names := map[rollupKey]string{}
+
+	if res == Hourly {
+		rows, err := r.store.Query(Query{Start: start, End: end})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			var key rollupKey
+			switch row.Kind {
+			case KindNode:
+				key = rollupKey{KindNode, "", "", row.Zone}
+			case KindPod:
+				key = rollupKey{KindNamespace, "", row.Namespace, row.Zone}
+			case KindWorkload, KindContainer:
+				key = rollupKey{row.Kind, row.ID, row.Namespace, row.Zone}
+				names[key] = row.Name
+			default:
+				continue
+			}
+			joules[key] += row.Joules
+		}
+	} else {
+		hourly, err := r.store.QueryRollups(RollupQuery{Resolution: Hourly, Query: Query{Start: start, End: end}})
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range hourly {
+			key := rollupKey{h.Kind, h.ID, h.Namespace, h.Zone}
+			joules[key] += h.Joules
+			if h.Name != "" {
+				names[key] = h.Name
+			}
+		}
+	}
+
+	rollups := make([]Rollup, 0, len(joules))
+	for key, j := range joules {
+		rollups = append(rollups, Rollup{
+			Start:      start,
+			Resolution: res,
+			Kind:       key.kind,
+			ID:         key.id,
+			Name:       names[key],
+			Namespace:  key.namespace,
+			Zone:       key.zone,
+			Watts:      j / res.Duration().Seconds(),
+			Joules:     j,
+		})
+	}
+	slices.SortFunc(rollups, func(a, b Rollup) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.ID, b.ID),
+			cmp.Compare(a.Zone, b.Zone),
+		)
+	})
+	return rollups, nil
+}
+
+// Rollups is the response of the rollups endpoint and tool
+type Rollups struct {
+	Rollups []Rollup `json:"rollups"`
+}
+
+// handleRollups serves the rollups selected by the resolution query parameter
+// and the parameters of the history endpoint
+func (r *Recorder) handleRollups(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := req.URL.Query()
+	res, err := parseResolution(params.Get("resolution"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q, err := parseQuery(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rollups, err := r.store.QueryRollups(RollupQuery{Query: q, Resolution: res})
+	if err != nil {
+		r.logger.Error("Failed to query rollups", "error", err)
+		http.Error(w, "failed to query rollups", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Rollups{Rollups: rollups}); err != nil {
+		r.logger.Error("Failed to write rollups", "error", err)
+	}
+}
+
+func (r *Recorder) callRollupsTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		toolArgs
+		Resolution string `json:"resolution"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := parseResolution(params.Resolution)
+	if err != nil {
+		return nil, err
+	}
+	q, err := params.query(r.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	rollups, err := r.store.QueryRollups(RollupQuery{Query: q, Resolution: res})
+	if err != nil {
+		return nil, err
+	}
+	return Rollups{Rollups: rollups}, nil
+}
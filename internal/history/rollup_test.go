@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestRecorderRollups(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(day)
+	pm := monitor.NewFakeMonitor(snapshot(day, 0, 0, 0))
+	store := NewMemoryStore()
+	newRecorder := func(registry *server.FakeRegistry) *Recorder {
+		return NewRecorder(pm, store, registry, WithClock(fakeClock), WithTools(registry),
+			WithRetention(48*time.Hour), WithRollupRetention(48*time.Hour, 30*24*time.Hour))
+	}
+
+	registry := server.NewFakeRegistry()
+	r := newRecorder(registry)
+	require.NoError(t, r.Init())
+	require.Contains(t, registry.Handlers, RollupsEndpoint)
+	require.Contains(t, registry.Tools, RollupsToolName)
+
+	require.NoError(t, store.Append([]Row{
+		{Timestamp: day.Add(10 * time.Minute), Kind: KindNode, Zone: "package", Joules: 100},
+		{Timestamp: day.Add(10 * time.Minute), Kind: KindPod, ID: "pod-1", Namespace: "prod", Zone: "package", Joules: 50},
+		{Timestamp: day.Add(10 * time.Minute), Kind: KindContainer, ID: "c-1", Name: "server", Namespace: "prod", Zone: "package", Joules: 30},
+		{Timestamp: day.Add(10 * time.Minute), Kind: KindWorkload, ID: "Deployment/api", Name: "api", Namespace: "prod", Zone: "package", Joules: 50},
+		{Timestamp: day.Add(20 * time.Minute), Kind: KindNode, Zone: "package", Joules: 260},
+		{Timestamp: day.Add(20 * time.Minute), Kind: KindPod, ID: "pod-2", Namespace: "prod", Zone: "package", Joules: 60},
+		{Timestamp: day.Add(20 * time.Minute), Kind: KindProcess, ID: "42", Zone: "package", Joules: 20},
+		{Timestamp: day.Add(65 * time.Minute), Kind: KindNode, Zone: "package", Joules: 360},
+		{Timestamp: day.Add(65 * time.Minute), Kind: KindPod, ID: "pod-3", Namespace: "dev", Zone: "package", Joules: 10},
+	}))
+
+	fakeClock.SetTime(day.Add(90 * time.Minute))
+	r.rollup()
+	rollups, err := store.QueryRollups(RollupQuery{Resolution: Hourly})
+	require.NoError(t, err)
+	require.Len(t, rollups, 4, "only the hour that ended is rolled up")
+	assert.Equal(t, []Rollup{
+		{Start: day, Resolution: Hourly, Kind: KindContainer, ID: "c-1", Name: "server", Namespace: "prod", Zone: "package", Watts: 30.0 / 3600, Joules: 30},
+		{Start: day, Resolution: Hourly, Kind: KindNamespace, Namespace: "prod", Zone: "package", Watts: 110.0 / 3600, Joules: 110},
+		{Start: day, Resolution: Hourly, Kind: KindNode, Zone: "package", Watts: 0.1, Joules: 360},
+		{Start: day, Resolution: Hourly, Kind: KindWorkload, ID: "Deployment/api", Name: "api", Namespace: "prod", Zone: "package", Watts: 50.0 / 3600, Joules: 50},
+	}, rollups)
+
+	fakeClock.SetTime(day.Add(24*time.Hour + time.Minute))
+	r.rollup()
+	rollups, err = store.QueryRollups(RollupQuery{Resolution: Daily})
+	require.NoError(t, err)
+	assert.Equal(t, []Rollup{
+		{Start: day, Resolution: Daily, Kind: KindContainer, ID: "c-1", Name: "server", Namespace: "prod", Zone: "package", Watts: 30.0 / 86400, Joules: 30},
+		{Start: day, Resolution: Daily, Kind: KindNamespace, Namespace: "dev", Zone: "package", Watts: 10.0 / 86400, Joules: 10},
+		{Start: day, Resolution: Daily, Kind: KindNamespace, Namespace: "prod", Zone: "package", Watts: 110.0 / 86400, Joules: 110},
+		{Start: day, Resolution: Daily, Kind: KindNode, Zone: "package", Watts: 720.0 / 86400, Joules: 720},
+		{Start: day, Resolution: Daily, Kind: KindWorkload, ID: "Deployment/api", Name: "api", Namespace: "prod", Zone: "package", Watts: 50.0 / 86400, Joules: 50},
+	}, rollups)
+
+	t.Run("restart", func(t *testing.T) {
+		r := newRecorder(server.NewFakeRegistry())
+		require.NoError(t, r.Init())
+		r.rollup()
+		rollups, err := store.QueryRollups(RollupQuery{Resolution: Daily})
+		require.NoError(t, err)
+		assert.Len(t, rollups, 5, "rolled up periods are not rolled up again")
+	})
+
+	t.Run("REST", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[RollupsEndpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/history/rollups?resolution=day&kind=namespace&namespace=prod&start=2025-06-01T00:00:00Z", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got Rollups
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		require.Len(t, got.Rollups, 1)
+		assert.Equal(t, 110.0, got.Rollups[0].Joules)
+
+		for _, query := range []string{"", "resolution=week", "resolution=day&limit=0"} {
+			rec = httptest.NewRecorder()
+			registry.Handlers[RollupsEndpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/history/rollups?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("MCP tool", func(t *testing.T) {
+		got, err := registry.Tools[RollupsToolName](context.Background(),
+			json.RawMessage(`{"resolution":"hour","kind":"node","since":"24h"}`))
+		require.NoError(t, err)
+		require.Len(t, got.(Rollups).Rollups, 1, "the hours of the last day with rows")
+		assert.Equal(t, day.Add(time.Hour), got.(Rollups).Rollups[0].Start)
+
+		_, err = registry.Tools[RollupsToolName](context.Background(), json.RawMessage(`{}`))
+		assert.ErrorContains(t, err, "invalid resolution")
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		fakeClock.SetTime(day.Add(50 * time.Hour))
+		r.prune()
+		rollups, err := store.QueryRollups(RollupQuery{Resolution: Hourly})
+		require.NoError(t, err)
+		assert.Empty(t, rollups)
+		rollups, err = store.QueryRollups(RollupQuery{Resolution: Daily})
+		require.NoError(t, err)
+		assert.Len(t, rollups, 5)
+	})
+}
@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	// pure Go SQLite driver, so Kepler builds without cgo
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS power (
+	timestamp   INTEGER NOT NULL, -- unix nanoseconds
+	kind        TEXT    NOT NULL,
+	id          TEXT    NOT NULL,
+	name        TEXT    NOT NULL,
+	namespace   TEXT    NOT NULL,
+	zone        TEXT    NOT NULL,
+	watts       REAL    NOT NULL,
+	joules      REAL    NOT NULL,
+	uncertainty REAL    NOT NULL DEFAULT 0 -- relative
+);
+CREATE INDEX IF NOT EXISTS power_timestamp ON power (timestamp);
+CREATE INDEX IF NOT EXISTS power_kind_id ON power (kind, id, timestamp);
+CREATE TABLE IF NOT EXISTS rollup (
+	start      INTEGER NOT NULL, -- unix nanoseconds
+	resolution TEXT    NOT NULL,
+	kind       TEXT    NOT NULL,
+	id         TEXT    NOT NULL,
+	name       TEXT    NOT NULL,
+	namespace  TEXT    NOT NULL,
+	zone       TEXT    NOT NULL,
+	watts      REAL    NOT NULL,
+	joules     REAL    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS rollup_resolution_start ON rollup (resolution, start);
+`
+
+// SQLiteStore stores rows in a SQLite database file, so they survive restarts
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*SQLiteStore)(nil)
+
+// NewSQLiteStore opens the SQLite database at path, creating it if needed
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	// WAL lets queries run while rows are appended
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database %q: %w", path, err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create history database %q: %w", path, err)
+	}
+	// databases created before power had an uncertainty lack its column
+	if err := addColumn(db, "power", "uncertainty", "REAL NOT NULL DEFAULT 0"); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to migrate history database %q: %w", path, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// addColumn adds a column to table unless it has it already
+func addColumn(db *sql.DB, table, column, definition string) error {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	return err
+}
+
+func (s *SQLiteStore) Append(rows []Row) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`INSERT INTO power (timestamp, kind, id, name, namespace, zone, watts, joules, uncertainty)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, r := range rows {
+		if _, err := stmt.Exec(r.Timestamp.UnixNano(), r.Kind, r.ID, r.Name, r.Namespace, r.Zone, r.Watts, r.Joules, r.Uncertainty); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// selectSQL returns the statement selecting the columns of the rows of table
+// matched by q, the most recent first up to the limit, ordered by the time
+// column, and its arguments
+func selectSQL(table, timeColumn, columns string, q Query, where []string, args []any) (string, []any) {
+	for _, f := range []struct {
+		column, value string
+	}{
+		{"kind", q.Kind},
+		{"id", q.ID},
+		{"namespace", q.Namespace},
+		{"zone", q.Zone},
+	} {
+		if f.value != "" {
+			where = append(where, f.column+" = ?")
+			args = append(args, f.value)
+		}
+	}
+	if !q.Start.IsZero() {
+		where = append(where, timeColumn+" >= ?")
+		args = append(args, q.Start.UnixNano())
+	}
+	if !q.End.IsZero() {
+		where = append(where, timeColumn+" < ?")
+		args = append(args, q.End.UnixNano())
+	}
+
+	query := "SELECT rowid, " + columns + " FROM " + table
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	// the most recent rows are selected, then ordered by time
+	query += " ORDER BY " + timeColumn + " DESC, rowid DESC"
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+	return "SELECT " + columns + " FROM (" + query + ") ORDER BY " + timeColumn + ", rowid", args
+}
+
+func (s *SQLiteStore) Query(q Query) ([]Row, error) {
+	query, args := selectSQL("power", "timestamp", "timestamp, kind, id, name, namespace, zone, watts, joules, uncertainty", q, nil, nil)
+	result, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = result.Close() }()
+
+	rows := []Row{}
+	for result.Next() {
+		var r Row
+		var ns int64
+		if err := result.Scan(&ns, &r.Kind, &r.ID, &r.Name, &r.Namespace, &r.Zone, &r.Watts, &r.Joules, &r.Uncertainty); err != nil {
+			return nil, err
+		}
+		r.Timestamp = time.Unix(0, ns).UTC()
+		rows = append(rows, r)
+	}
+	return rows, result.Err()
+}
+
+func (s *SQLiteStore) Prune(before time.Time) error {
+	_, err := s.db.Exec("DELETE FROM power WHERE timestamp < ?", before.UnixNano())
+	return err
+}
+
+func (s *SQLiteStore) AppendRollups(rollups []Rollup) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`INSERT INTO rollup (start, resolution, kind, id, name, namespace, zone, watts, joules)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, r := range rollups {
+		if _, err := stmt.Exec(r.Start.UnixNano(), string(r.Resolution), r.Kind, r.ID, r.Name, r.Namespace, r.Zone, r.Watts, r.Joules); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) QueryRollups(q RollupQuery) ([]Rollup, error) {
+	query, args := selectSQL("rollup", "start", "start, kind, id, name, namespace, zone, watts, joules", q.Query,
+		[]string{"resolution = ?"}, []any{string(q.Resolution)})
+	result, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = result.Close() }()
+
+	rollups := []Rollup{}
+	for result.Next() {
+		r := Rollup{Resolution: q.Resolution}
+		var ns int64
+		if err := result.Scan(&ns, &r.Kind, &r.ID, &r.Name, &r.Namespace, &r.Zone, &r.Watts, &r.Joules); err != nil {
+			return nil, err
+		}
+		r.Start = time.Unix(0, ns).UTC()
+		rollups = append(rollups, r)
+	}
+	return rollups, result.Err()
+}
+
+func (s *SQLiteStore) PruneRollups(resolution Resolution, before time.Time) error {
+	_, err := s.db.Exec("DELETE FROM rollup WHERE resolution = ? AND start < ?", string(resolution), before.UnixNano())
+	return err
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package history records the power of the node and its workloads in every
+// interval, so that past power can be queried over REST and MCP. Rows are kept
+// in memory or, to survive restarts, in a SQLite database, and are dropped
+// once they are older than the retention. Rows are downsampled into hourly and
+// daily rollups, which are kept for longer.
+package history
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+)
+
+// Kinds of rows
+const (
+	KindNode      = "node"
+	KindProcess   = "process"
+	KindContainer = "container"
+	KindVM        = "vm"
+	KindPod       = "pod"
+
+	// KindWorkload is the kind of rows of the pods of a workload controlling
+	// them, e.g. a Deployment; their ID is the kind and name of the
+	// workload, e.g. Deployment/api
+	KindWorkload = "workload"
+)
+
+// Row is the power of the node or of a workload in a zone in an interval
+type Row = api.HistoryRow
+
+// Query selects rows; empty fields match all rows
+type Query struct {
+	Kind      string
+	ID        string
+	Namespace string
+	Zone      string
+	Start     time.Time // inclusive
+	End       time.Time // exclusive
+	Limit     int       // most recent rows returned; 0 for all
+}
+
+// matches returns true if r is selected by q, ignoring the limit
+func (q Query) matches(r Row) bool {
+	return (q.Kind == "" || r.Kind == q.Kind) &&
+		(q.ID == "" || r.ID == q.ID) &&
+		(q.Namespace == "" || r.Namespace == q.Namespace) &&
+		(q.Zone == "" || r.Zone == q.Zone) &&
+		(q.Start.IsZero() || !r.Timestamp.Before(q.Start)) &&
+		(q.End.IsZero() || r.Timestamp.Before(q.End))
+}
+
+// Store stores rows
+type Store interface {
+	// Append stores rows
+	Append(rows []Row) error
+
+	// Query returns the rows selected by q ordered by time
+	Query(q Query) ([]Row, error)
+
+	// Prune deletes the rows older than before
+	Prune(before time.Time) error
+
+	// AppendRollups stores rollups
+	AppendRollups(rollups []Rollup) error
+
+	// QueryRollups returns the rollups selected by q ordered by start
+	QueryRollups(q RollupQuery) ([]Rollup, error)
+
+	// PruneRollups deletes the rollups of the resolution starting before before
+	PruneRollups(resolution Resolution, before time.Time) error
+
+	// Close releases the resources of the store
+	Close() error
+}
+
+// MemoryStore stores rows in memory, so they are lost on restart
+type MemoryStore struct {
+	mu      sync.RWMutex
+	rows    []Row                   // ordered by time
+	rollups map[Resolution][]Rollup // ordered by start
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{rollups: map[Resolution][]Rollup{}}
+}
+
+func (s *MemoryStore) Append(rows []Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rows = appendSorted(s.rows, rows, func(r Row) time.Time { return r.Timestamp })
+	return nil
+}
+
+func (s *MemoryStore) Query(q Query) ([]Row, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := []Row{}
+	for _, r := range s.rows {
+		if q.matches(r) {
+			rows = append(rows, r)
+		}
+	}
+	if q.Limit > 0 && len(rows) > q.Limit {
+		rows = rows[len(rows)-q.Limit:]
+	}
+	return rows, nil
+}
+
+func (s *MemoryStore) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rows = pruneSorted(s.rows, before, func(r Row) time.Time { return r.Timestamp })
+	return nil
+}
+
+func (s *MemoryStore) AppendRollups(rollups []Rollup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byResolution := map[Resolution][]Rollup{}
+	for _, r := range rollups {
+		byResolution[r.Resolution] = append(byResolution[r.Resolution], r)
+	}
+	for res, added := range byResolution {
+		s.rollups[res] = appendSorted(s.rollups[res], added, Rollup.start)
+	}
+	return nil
+}
+
+func (s *MemoryStore) QueryRollups(q RollupQuery) ([]Rollup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rollups := []Rollup{}
+	for _, r := range s.rollups[q.Resolution] {
+		if q.matches(r.row()) {
+			rollups = append(rollups, r)
+		}
+	}
+	if q.Limit > 0 && len(rollups) > q.Limit {
+		rollups = rollups[len(rollups)-q.Limit:]
+	}
+	return rollups, nil
+}
+
+func (s *MemoryStore) PruneRollups(resolution Resolution, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rollups[resolution] = pruneSorted(s.rollups[resolution], before, Rollup.start)
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// appendSorted appends added to s, which is ordered by ts, keeping the order
+func appendSorted[T any](s, added []T, ts func(T) time.Time) []T {
+	byTime := func(a, b T) int {
+		return ts(a).Compare(ts(b))
+	}
+	n := len(s)
+	s = append(s, added...)
+	// values are usually appended in order, so only the new values are sorted
+	slices.SortStableFunc(s[n:], byTime)
+	if n > 0 && n < len(s) && ts(s[n]).Before(ts(s[n-1])) {
+		slices.SortStableFunc(s, byTime)
+	}
+	return s
+}
+
+// pruneSorted deletes the values of s, which is ordered by ts, before before
+func pruneSorted[T any](s []T, before time.Time, ts func(T) time.Time) []T {
+	i, _ := slices.BinarySearchFunc(s, before, func(v T, t time.Time) int {
+		return ts(v).Compare(t)
+	})
+	return slices.Delete(s, 0, i)
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteStore returns a store in the database at path
+func newSQLiteStore(t *testing.T, path string) *SQLiteStore {
+	t.Helper()
+	s, err := NewSQLiteStore(path)
+	require.NoError(t, err)
+	return s
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemoryStore() },
+		"sqlite": func(t *testing.T) Store {
+			return newSQLiteStore(t, filepath.Join(t.TempDir(), "history.db"))
+		},
+	}
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * 5 * time.Second) }
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			defer func() { assert.NoError(t, s.Close()) }()
+
+			for i := range 3 {
+				require.NoError(t, s.Append([]Row{
+					{Timestamp: at(i), Kind: KindNode, Zone: "package", Watts: 100, Joules: 500},
+					{Timestamp: at(i), Kind: KindPod, ID: "pod-1", Name: "api", Namespace: "prod", Zone: "package", Watts: 10, Joules: 50, Uncertainty: 0.5},
+					{Timestamp: at(i), Kind: KindPod, ID: "pod-2", Name: "batch", Namespace: "dev", Zone: "package", Watts: 20, Joules: 100},
+				}))
+			}
+
+			rows, err := s.Query(Query{})
+			require.NoError(t, err)
+			assert.Len(t, rows, 9)
+
+			rows, err = s.Query(Query{Kind: KindPod, Namespace: "prod"})
+			require.NoError(t, err)
+			require.Len(t, rows, 3)
+			assert.Equal(t, Row{Timestamp: at(0), Kind: KindPod, ID: "pod-1", Name: "api", Namespace: "prod", Zone: "package", Watts: 10, Joules: 50, Uncertainty: 0.5}, rows[0])
+
+			rows, err = s.Query(Query{ID: "pod-2", Start: at(1), End: at(2)})
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			assert.Equal(t, at(1), rows[0].Timestamp)
+
+			rows, err = s.Query(Query{Kind: KindNode, Limit: 2})
+			require.NoError(t, err)
+			require.Len(t, rows, 2, "the most recent rows")
+			assert.Equal(t, at(1), rows[0].Timestamp)
+			assert.Equal(t, at(2), rows[1].Timestamp)
+
+			rows, err = s.Query(Query{Zone: "dram"})
+			require.NoError(t, err)
+			assert.Empty(t, rows)
+
+			require.NoError(t, s.Prune(at(2)))
+			rows, err = s.Query(Query{})
+			require.NoError(t, err)
+			require.Len(t, rows, 3)
+			assert.Equal(t, at(2), rows[0].Timestamp)
+		})
+	}
+}
+
+func TestStoreRollups(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemoryStore() },
+		"sqlite": func(t *testing.T) Store {
+			return newSQLiteStore(t, filepath.Join(t.TempDir(), "history.db"))
+		},
+	}
+
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	hour := func(i int) time.Time { return day.Add(time.Duration(i) * time.Hour) }
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			defer func() { assert.NoError(t, s.Close()) }()
+
+			for i := range 3 {
+				require.NoError(t, s.AppendRollups([]Rollup{
+					{Start: hour(i), Resolution: Hourly, Kind: KindNamespace, Namespace: "prod", Zone: "package", Watts: 1, Joules: 3600},
+					{Start: hour(i), Resolution: Hourly, Kind: KindContainer, ID: "c-1", Name: "server", Namespace: "dev", Zone: "package", Watts: 2, Joules: 7200},
+				}))
+			}
+			require.NoError(t, s.AppendRollups([]Rollup{
+				{Start: day, Resolution: Daily, Kind: KindNamespace, Namespace: "prod", Zone: "package", Watts: 1, Joules: 86400},
+			}))
+
+			rollups, err := s.QueryRollups(RollupQuery{Resolution: Hourly})
+			require.NoError(t, err)
+			assert.Len(t, rollups, 6)
+
+			rollups, err = s.QueryRollups(RollupQuery{Resolution: Hourly, Query: Query{Kind: KindContainer, Start: hour(1)}})
+			require.NoError(t, err)
+			require.Len(t, rollups, 2)
+			assert.Equal(t, Rollup{
+				Start: hour(1), Resolution: Hourly, Kind: KindContainer, ID: "c-1", Name: "server", Namespace: "dev",
+				Zone: "package", Watts: 2, Joules: 7200,
+			}, rollups[0])
+
+			rollups, err = s.QueryRollups(RollupQuery{Resolution: Hourly, Query: Query{Namespace: "prod", Limit: 1}})
+			require.NoError(t, err)
+			require.Len(t, rollups, 1)
+			assert.Equal(t, hour(2), rollups[0].Start, "the most recent rollup")
+
+			rollups, err = s.QueryRollups(RollupQuery{Resolution: Daily})
+			require.NoError(t, err)
+			require.Len(t, rollups, 1)
+			assert.Equal(t, 86400.0, rollups[0].Joules)
+
+			require.NoError(t, s.PruneRollups(Hourly, hour(2)))
+			rollups, err = s.QueryRollups(RollupQuery{Resolution: Hourly})
+			require.NoError(t, err)
+			assert.Len(t, rollups, 2)
+			rollups, err = s.QueryRollups(RollupQuery{Resolution: Daily})
+			require.NoError(t, err)
+			assert.Len(t, rollups, 1, "only rollups of the resolution are pruned")
+		})
+	}
+}
+
+func TestMemoryStoreOutOfOrder(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	require.NoError(t, s.Append([]Row{{Timestamp: start.Add(time.Minute), Kind: KindNode}}))
+	require.NoError(t, s.Append([]Row{{Timestamp: start, Kind: KindNode}}))
+
+	rows, err := s.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, start, rows[0].Timestamp)
+}
+
+func TestSQLiteStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	s := newSQLiteStore(t, path)
+	require.NoError(t, s.Append([]Row{{Timestamp: now, Kind: KindNode, Zone: "package", Watts: 100}}))
+	require.NoError(t, s.Close())
+
+	s = newSQLiteStore(t, path)
+	defer func() { assert.NoError(t, s.Close()) }()
+	rows, err := s.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, 100.0, rows[0].Watts)
+
+	_, err = NewSQLiteStore(filepath.Join(t.TempDir(), "missing", "history.db"))
+	assert.Error(t, err)
+}
+
+func TestSQLiteStoreMigrates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// a database created before power had an uncertainty
+	s := newSQLiteStore(t, path)
+	_, err := s.db.Exec("ALTER TABLE power DROP COLUMN uncertainty")
+	require.NoError(t, err)
+	_, err = s.db.Exec("INSERT INTO power VALUES (?, 'node', '', '', '', 'package', 100, 500)", now.UnixNano())
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s = newSQLiteStore(t, path)
+	defer func() { assert.NoError(t, s.Close()) }()
+	require.NoError(t, s.Append([]Row{{Timestamp: now.Add(time.Second), Kind: KindNode, Zone: "package", Watts: 50, Uncertainty: 0.5}}))
+
+	rows, err := s.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Zero(t, rows[0].Uncertainty)
+	assert.Equal(t, 0.5, rows[1].Uncertainty)
+}
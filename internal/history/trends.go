@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// TrendsToolName is the name of the MCP tool returning the recent power of
+// each zone of the node
+const TrendsToolName = "get_zone_trends"
+
+// defaultTrendSamples is the number of samples of each zone returned when no
+// number is requested
+const defaultTrendSamples = 60
+
+// ZoneTrend is the power of a zone of the node in its most recent intervals
+type ZoneTrend struct {
+	Zone  string    `json:"zone"`
+	Start time.Time `json:"start"` // end of the first interval
+	End   time.Time `json:"end"`   // end of the last interval
+
+	// Watts is the average power in each interval, oldest first, rounded to
+	// hundredths of a watt
+	Watts []float64 `json:"watts"`
+
+	MinWatts float64 `json:"minWatts"`
+	MaxWatts float64 `json:"maxWatts"`
+}
+
+// Trends is the response of the trends tool
+type Trends struct {
+	IntervalSeconds float64     `json:"intervalSeconds"` // between samples
+	Zones           []ZoneTrend `json:"zones"`
+}
+
+// trends returns the last samples rows of each zone of the node, or only of
+// zone if set, recorded in the last 2*samples intervals so that a zone no
+// longer read doesn't return stale samples
+func (r *Recorder) trends(zone string, samples int) (Trends, error) {
+	interval := r.sampleInterval()
+	window := time.Duration(2*samples) * interval
+	rows, err := r.store.Query(Query{Kind: KindNode, Zone: zone, Start: r.clock.Now().Add(-window)})
+	if err != nil {
+		return Trends{}, err
+	}
+
+	byZone := map[string][]Row{}
+	for _, row := range rows {
+		byZone[row.Zone] = append(byZone[row.Zone], row)
+	}
+
+	ret := Trends{IntervalSeconds: interval.Seconds(), Zones: []ZoneTrend{}}
+	for _, name := range slices.Sorted(maps.Keys(byZone)) {
+		zoneRows := byZone[name]
+		zoneRows = zoneRows[max(len(zoneRows)-samples, 0):]
+
+		t := ZoneTrend{
+			Zone:     name,
+			Start:    zoneRows[0].Timestamp,
+			End:      zoneRows[len(zoneRows)-1].Timestamp,
+			Watts:    make([]float64, len(zoneRows)),
+			MinWatts: math.Inf(1),
+		}
+		for i, row := range zoneRows {
+			w := math.Round(row.Watts*100) / 100
+			t.Watts[i] = w
+			t.MinWatts = min(t.MinWatts, w)
+			t.MaxWatts = max(t.MaxWatts, w)
+		}
+		ret.Zones = append(ret.Zones, t)
+	}
+	return ret, nil
+}
+
+func (r *Recorder) callTrendsTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		Zone    string `json:"zone"`
+		Samples int    `json:"samples"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+	if params.Samples < 0 || params.Samples > defaultLimit {
+		return nil, fmt.Errorf("invalid samples: %d; must be between 1 and %d", params.Samples, defaultLimit)
+	}
+	return r.trends(params.Zone, cmp.Or(params.Samples, defaultTrendSamples))
+}
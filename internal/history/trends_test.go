@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestRecorderTrends(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	registry := server.NewFakeRegistry()
+	store := NewMemoryStore()
+	r := NewRecorder(monitor.NewFakeMonitor(nil), store, registry, WithClock(fakeClock), WithTools(registry))
+	require.NoError(t, r.Init())
+	require.Contains(t, registry.Tools, TrendsToolName)
+
+	// the package power climbs over 5 intervals of 5s; dram stays flat, and an
+	// old sample of a zone no longer read is out of the window
+	require.NoError(t, store.Append([]Row{{Timestamp: start.Add(-time.Hour), Kind: KindNode, Zone: "uncore", Watts: 3}}))
+	for i := range 5 {
+		ts := start.Add(time.Duration(i) * 5 * time.Second)
+		require.NoError(t, store.Append([]Row{
+			{Timestamp: ts, Kind: KindNode, Zone: "package", Watts: 50 + 10*float64(i) + 0.004},
+			{Timestamp: ts, Kind: KindNode, Zone: "dram", Watts: 8},
+			{Timestamp: ts, Kind: KindPod, ID: "pod-1", Zone: "package", Watts: 5},
+		}))
+	}
+	fakeClock.SetTime(start.Add(20 * time.Second))
+
+	got, err := registry.Tools[TrendsToolName](context.Background(), json.RawMessage(`{"samples":3}`))
+	require.NoError(t, err)
+	assert.Equal(t, Trends{IntervalSeconds: 5, Zones: []ZoneTrend{
+		{Zone: "dram", Start: start.Add(10 * time.Second), End: start.Add(20 * time.Second), Watts: []float64{8, 8, 8}, MinWatts: 8, MaxWatts: 8},
+		{Zone: "package", Start: start.Add(10 * time.Second), End: start.Add(20 * time.Second), Watts: []float64{70, 80, 90}, MinWatts: 70, MaxWatts: 90},
+	}}, got)
+
+	got, err = registry.Tools[TrendsToolName](context.Background(), json.RawMessage(`{"zone":"package"}`))
+	require.NoError(t, err)
+	require.Len(t, got.(Trends).Zones, 1)
+	assert.Equal(t, []float64{50, 60, 70, 80, 90}, got.(Trends).Zones[0].Watts)
+
+	_, err = registry.Tools[TrendsToolName](context.Background(), json.RawMessage(`{"samples":-1}`))
+	assert.ErrorContains(t, err, "invalid samples")
+}
+
+// intervalMonitor is a monitor reporting its interval
+type intervalMonitor struct {
+	*monitor.FakeMonitor
+	interval time.Duration
+}
+
+func (m *intervalMonitor) Interval() time.Duration { return m.interval }
+
+func TestRecorderTrendsInterval(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	registry := server.NewFakeRegistry()
+	pm := &intervalMonitor{FakeMonitor: monitor.NewFakeMonitor(nil), interval: 5 * time.Second}
+	r := NewRecorder(pm, NewMemoryStore(), registry, WithClock(fakeClock), WithTools(registry), WithInterval(5*time.Second))
+	require.NoError(t, r.Init())
+
+	// the interval of the monitor changed on reload
+	pm.interval = 10 * time.Second
+	got, err := registry.Tools[TrendsToolName](context.Background(), json.RawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, got.(Trends).IntervalSeconds)
+}
@@ -0,0 +1,374 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package inspect serves the current power of the node and its workloads as
+// JSON, and renders it for the top and show commands, which inspect a running
+// Kepler.
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+)
+
+type (
+	Initializer  = service.Initializer
+	Dependent    = service.Dependent
+	Monitor      = monitor.Service
+	ToolRegistry = server.ToolRegistry
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// Endpoint is the endpoint the power is served at
+const Endpoint = "/power"
+
+// SnapshotEndpoint is the endpoint the snapshot is served at in the versioned
+// format of pkg/api
+const SnapshotEndpoint = "/api/v1/snapshot"
+
+// KindNode selects only the power of the node
+const KindNode = "node"
+
+// Kinds of workloads
+const (
+	KindProcess   = "process"
+	KindContainer = "container"
+	KindVM        = "vm"
+	KindPod       = "pod"
+)
+
+// Kinds are the kinds of workloads
+var Kinds = []string{KindProcess, KindContainer, KindVM, KindPod}
+
+// Power is the current power of the node and its running workloads
+type Power struct {
+	Timestamp time.Time           `json:"timestamp"`
+	Node      map[string]NodeZone `json:"node"` // keyed by zone name
+	Workloads []Workload          `json:"workloads"`
+}
+
+// NodeZone is the energy and power of the node in a zone
+type NodeZone struct {
+	Joules      float64 `json:"joules"` // cumulative energy
+	Watts       float64 `json:"watts"`
+	ActiveWatts float64 `json:"activeWatts"`
+	IdleWatts   float64 `json:"idleWatts"`
+}
+
+// Workload is the energy and power of a running workload
+type Workload struct {
+	Kind       string          `json:"kind"`
+	ID         string          `json:"id"` // PID of processes
+	Name       string          `json:"name"`
+	Namespace  string          `json:"namespace,omitempty"` // of pods and their containers
+	Pod        string          `json:"pod,omitempty"`       // name of the pod of containers
+	Container  string          `json:"container,omitempty"` // ID of the container of processes
+	VM         string          `json:"vm,omitempty"`        // ID of the VM of processes
+	CPUSeconds float64         `json:"cpuSeconds"`
+	Zones      map[string]Zone `json:"zones"` // keyed by zone name
+}
+
+// Zone is the energy and power of a workload in a zone
+type Zone struct {
+	Joules float64 `json:"joules"` // cumulative energy
+	Watts  float64 `json:"watts"`
+}
+
+type Opts struct {
+	logger    *slog.Logger
+	tools     ToolRegistry
+	services  []service.Service
+	intervals map[string]time.Duration
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger: slog.Default(),
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the API
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithTools sets the registry the zones, the status and the power of
+// workloads are served by as MCP tools
+func WithTools(t ToolRegistry) OptionFn {
+	return func(o *Opts) {
+		o.tools = t
+	}
+}
+
+// WithServices sets the enabled services whose readiness and health are
+// reported in the status
+func WithServices(services ...service.Service) OptionFn {
+	return func(o *Opts) {
+		o.services = services
+	}
+}
+
+// WithIntervals sets the configured intervals reported in the status, keyed by
+// configuration setting, e.g. monitor.interval
+func WithIntervals(intervals map[string]time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.intervals = intervals
+	}
+}
+
+// API serves the current power of the node and its workloads, the energy
+// zones of the node and the status of Kepler
+type API struct {
+	logger    *slog.Logger
+	monitor   Monitor
+	server    APIRegistry
+	tools     ToolRegistry
+	services  []service.Service
+	intervals map[string]time.Duration
+}
+
+var (
+	_ Initializer = (*API)(nil)
+	_ Dependent   = (*API)(nil)
+)
+
+// NewAPI creates a new API serving the power of pm using s
+func NewAPI(pm Monitor, s APIRegistry, applyOpts ...OptionFn) *API {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &API{
+		logger:    opts.logger.With("service", "inspect"),
+		monitor:   pm,
+		server:    s,
+		tools:     opts.tools,
+		services:  opts.services,
+		intervals: opts.intervals,
+	}
+}
+
+func (a *API) Name() string {
+	return "inspect"
+}
+
+// Dependencies returns the monitor, and the API server and MCP tools the power
+// and zones are served by
+func (a *API) Dependencies() []service.Service {
+	deps := []service.Service{a.monitor}
+	if s, ok := a.server.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	if s, ok := a.tools.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (a *API) Init() error {
+	if err := a.server.Register(Endpoint, "Power", "Current power of the node and workloads (?kind=pod or node)", http.HandlerFunc(a.handlePower)); err != nil {
+		return err
+	}
+	if err := a.server.Register(SnapshotEndpoint, "Snapshot",
+		"Power of the node and all workloads in the versioned format of pkg/api, as JSON or as protobuf if accepted (Accept: application/x-protobuf)",
+		http.HandlerFunc(a.handleSnapshot)); err != nil {
+		return err
+	}
+	if err := a.server.Register(ZonesEndpoint, "Zones", "Energy zones of the node, where they are read from and whether they can be read", http.HandlerFunc(a.handleZones)); err != nil {
+		return err
+	}
+	if a.tools == nil {
+		return nil
+	}
+	if err := a.tools.RegisterTool(ZonesToolName,
+		"Energy zones of the node with the meter each is read from (e.g. rapl, hwmon, estimator or redfish), "+
+			"its path, the energy its counter wraps around at, and whether it could be read in the last collection",
+		map[string]any{"type": "object", "properties": map[string]any{}},
+		a.callZonesTool); err != nil {
+		return err
+	}
+	if err := a.tools.RegisterTool(StatusToolName,
+		"Status of the Kepler agent to debug why power data is missing: its version, the enabled services "+
+			"and whether they are ready and healthy, the meters the available zones are read from, the other "+
+			"sources (e.g. GPU utilization or carbon intensity) and whether they could be read, the collection "+
+			"and other intervals, the energy zones, and the recent warnings and errors logged",
+		map[string]any{"type": "object", "properties": map[string]any{}},
+		a.callStatusTool); err != nil {
+		return err
+	}
+	return a.registerWorkloadTools()
+}
+
+// handlePower serves the power of the node and the running workloads, only of
+// the kind query parameter if set; none if it is node
+func (a *API) handlePower(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	if kind != "" && kind != KindNode && !slices.Contains(Kinds, kind) {
+		http.Error(w, fmt.Sprintf("invalid kind: %q; must be one of node, %s", kind, strings.Join(Kinds, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	snapshot, err := a.monitor.LatestSnapshot()
+	if err != nil {
+		a.logger.Error("Failed to get snapshot", "error", err)
+		http.Error(w, "failed to get power data", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newPower(snapshot, kind)); err != nil {
+		a.logger.Error("Failed to write power", "error", err)
+	}
+}
+
+// handleSnapshot serves the snapshot as protobuf if the client accepts it, or
+// as JSON
+func (a *API) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	snapshot, err := a.monitor.LatestSnapshot()
+	if err != nil {
+		a.logger.Error("Failed to get snapshot", "error", err)
+		http.Error(w, "failed to get power data", http.StatusServiceUnavailable)
+		return
+	}
+
+	s := snapshot.API()
+	if strings.Contains(r.Header.Get("Accept"), api.ContentTypeProtobuf) {
+		b, err := api.MarshalProto(s)
+		if err != nil {
+			a.logger.Error("Failed to encode snapshot", "error", err)
+			http.Error(w, "failed to encode snapshot", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", api.ContentTypeProtobuf)
+		if _, err := w.Write(b); err != nil {
+			a.logger.Error("Failed to write snapshot", "error", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", api.ContentTypeJSON)
+	if err := api.EncodeJSON(w, s); err != nil {
+		a.logger.Error("Failed to write snapshot", "error", err)
+	}
+}
+
+// newPower returns the power of snapshot with the workloads of kind, of all
+// kinds if empty or of none if node
+func newPower(snapshot *monitor.Snapshot, kind string) Power {
+	p := Power{
+		Timestamp: snapshot.Timestamp,
+		Node:      map[string]NodeZone{},
+		Workloads: []Workload{},
+	}
+	if snapshot.Node != nil {
+		// zones of the same name, e.g. package zones of each socket, are summed
+		for zone, usage := range snapshot.Node.Zones {
+			z := p.Node[zone.Name()]
+			z.Joules += usage.EnergyTotal.Joules()
+			z.Watts += usage.Power.Watts()
+			z.ActiveWatts += usage.ActivePower.Watts()
+			z.IdleWatts += usage.IdlePower.Watts()
+			p.Node[zone.Name()] = z
+		}
+	}
+
+	include := func(k string) bool { return kind == "" || kind == k }
+	if include(KindProcess) {
+		for _, proc := range snapshot.Processes {
+			p.Workloads = append(p.Workloads, Workload{
+				Kind:       KindProcess,
+				ID:         strconv.Itoa(proc.PID),
+				Name:       proc.Comm,
+				Container:  proc.ContainerID,
+				VM:         proc.VirtualMachineID,
+				CPUSeconds: proc.CPUTotalTime,
+				Zones:      zones(proc.Zones),
+			})
+		}
+	}
+	if include(KindContainer) {
+		for _, c := range snapshot.Containers {
+			w := Workload{
+				Kind:       KindContainer,
+				ID:         c.ID,
+				Name:       c.Name,
+				CPUSeconds: c.CPUTotalTime,
+				Zones:      zones(c.Zones),
+			}
+			if pod, ok := snapshot.Pods[c.PodID]; ok {
+				w.Pod = pod.Name
+				w.Namespace = pod.Namespace
+			}
+			p.Workloads = append(p.Workloads, w)
+		}
+	}
+	if include(KindVM) {
+		for _, vm := range snapshot.VirtualMachines {
+			p.Workloads = append(p.Workloads, Workload{
+				Kind:       KindVM,
+				ID:         vm.ID,
+				Name:       vm.Name,
+				CPUSeconds: vm.CPUTotalTime,
+				Zones:      zones(vm.Zones),
+			})
+		}
+	}
+	if include(KindPod) {
+		for _, pod := range snapshot.Pods {
+			p.Workloads = append(p.Workloads, Workload{
+				Kind:       KindPod,
+				ID:         pod.ID,
+				Name:       pod.Name,
+				Namespace:  pod.Namespace,
+				CPUSeconds: pod.CPUTotalTime,
+				Zones:      zones(pod.Zones),
+			})
+		}
+	}
+	return p
+}
+
+// zones returns the energy and power of usage keyed by zone name, summing
+// zones of the same name
+func zones(usage monitor.ZoneUsageMap) map[string]Zone {
+	ret := make(map[string]Zone, len(usage))
+	for zone, u := range usage {
+		z := ret[zone.Name()]
+		z.Joules += u.EnergyTotal.Joules()
+		z.Watts += u.Power.Watts()
+		ret[zone.Name()] = z
+	}
+	return ret
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+)
+
+var (
+	pkg0 = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	pkg1 = device.NewMockRaplZone("package", 1, "/sys/class/powercap/intel-rapl/intel-rapl:1", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:0", 1000*device.Joule)
+)
+
+func usage(joules, watts float64) monitor.Usage {
+	return monitor.Usage{EnergyTotal: monitor.Energy(joules) * device.Joule, Power: monitor.Power(watts) * device.Watt}
+}
+
+func testSnapshot() *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.Node = &monitor.Node{Zones: monitor.NodeZoneUsageMap{
+		pkg0: {EnergyTotal: 600 * device.Joule, Power: 60 * device.Watt, ActivePower: 40 * device.Watt, IdlePower: 20 * device.Watt},
+		pkg1: {EnergyTotal: 400 * device.Joule, Power: 40 * device.Watt, ActivePower: 30 * device.Watt, IdlePower: 10 * device.Watt},
+		dram: {EnergyTotal: 100 * device.Joule, Power: 10 * device.Watt},
+	}}
+	s.Processes["42"] = &monitor.Process{PID: 42, Comm: "nginx", ContainerID: "c-1", CPUTotalTime: 3,
+		Zones: monitor.ZoneUsageMap{pkg0: usage(30, 3)}}
+	s.Containers["c-1"] = &monitor.Container{ID: "c-1", Name: "web", PodID: "pod-1",
+		Zones: monitor.ZoneUsageMap{pkg0: usage(30, 3), pkg1: usage(20, 2)}}
+	s.VirtualMachines["vm-1"] = &monitor.VirtualMachine{ID: "vm-1", Name: "guest",
+		Zones: monitor.ZoneUsageMap{pkg0: usage(10, 1)}}
+	s.Pods["pod-1"] = &monitor.Pod{ID: "pod-1", Name: "web-0", Namespace: "prod",
+		Zones: monitor.ZoneUsageMap{pkg0: usage(30, 3), pkg1: usage(20, 2)}}
+	return s
+}
+
+func TestAPI(t *testing.T) {
+	registry := server.NewFakeRegistry()
+	api := NewAPI(monitor.NewFakeMonitor(testSnapshot()), registry)
+	assert.Equal(t, "inspect", api.Name())
+	require.NoError(t, api.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+
+	server := httptest.NewServer(registry.Handlers[Endpoint])
+	defer server.Close()
+	ctx := context.Background()
+
+	p, err := Fetch(ctx, server.Client(), server.URL+"/", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), p.Timestamp)
+	assert.Equal(t, map[string]NodeZone{
+		"package": {Joules: 1000, Watts: 100, ActiveWatts: 70, IdleWatts: 30},
+		"dram":    {Joules: 100, Watts: 10},
+	}, p.Node, "zones of the same name are summed")
+	assert.Len(t, p.Workloads, 4)
+
+	p, err = Fetch(ctx, server.Client(), server.URL, KindContainer)
+	require.NoError(t, err)
+	require.Len(t, p.Workloads, 1)
+	assert.Equal(t, Workload{
+		Kind: KindContainer, ID: "c-1", Name: "web", Namespace: "prod", Pod: "web-0",
+		Zones: map[string]Zone{"package": {Joules: 50, Watts: 5}},
+	}, p.Workloads[0])
+
+	p, err = Fetch(ctx, server.Client(), server.URL, KindProcess)
+	require.NoError(t, err)
+	require.Len(t, p.Workloads, 1)
+	assert.Equal(t, "42", p.Workloads[0].ID)
+	assert.Equal(t, "c-1", p.Workloads[0].Container)
+	assert.Equal(t, 3.0, p.Workloads[0].CPUSeconds)
+
+	p, err = Fetch(ctx, server.Client(), server.URL, KindNode)
+	require.NoError(t, err)
+	assert.Empty(t, p.Workloads)
+	assert.Len(t, p.Node, 2)
+
+	_, err = Fetch(ctx, server.Client(), server.URL, "namespace")
+	assert.ErrorContains(t, err, `invalid kind: "namespace"`)
+
+	resp, err := server.Client().Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	_, err = Fetch(ctx, server.Client(), "http://127.0.0.1:0", "")
+	assert.ErrorContains(t, err, "failed to connect to Kepler")
+}
+
+func TestSnapshotEndpoint(t *testing.T) {
+	registry := server.NewFakeRegistry()
+	require.NoError(t, NewAPI(monitor.NewFakeMonitor(testSnapshot()), registry).Init())
+	require.Contains(t, registry.Handlers, SnapshotEndpoint)
+
+	server := httptest.NewServer(registry.Handlers[SnapshotEndpoint])
+	defer server.Close()
+
+	get := func(accept string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp
+	}
+
+	resp := get("")
+	assert.Equal(t, api.ContentTypeJSON, resp.Header.Get("Content-Type"))
+	fromJSON, err := api.DecodeJSON(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), fromJSON.Timestamp)
+	assert.Equal(t, 1000.0, fromJSON.Node.Zones["package"].Joules)
+	assert.Len(t, fromJSON.Workloads, 4)
+
+	resp = get(api.ContentTypeProtobuf)
+	assert.Equal(t, api.ContentTypeProtobuf, resp.Header.Get("Content-Type"))
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+	fromProto, err := api.UnmarshalProto(b)
+	require.NoError(t, err)
+	assert.Equal(t, fromJSON, fromProto, "both encodings carry the same snapshot")
+}
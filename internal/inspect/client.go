@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Fetch returns the power served by the Kepler at baseURL, with the workloads
+// of kind or, if empty, of all kinds
+func Fetch(ctx context.Context, client *http.Client, baseURL, kind string) (*Power, error) {
+	u := strings.TrimSuffix(baseURL, "/") + Endpoint
+	if kind != "" {
+		u += "?" + url.Values{"kind": {kind}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Kepler: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("kepler responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var p Power
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to decode power: %w", err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sustainable-computing-io/kepler/internal/exporter/stdout"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+// shortIDLen is the length container IDs are shortened to in tables, as by
+// container runtimes
+const shortIDLen = 12
+
+// zone returns the energy and power of w in the zone of the name or, if name
+// is empty, in the zone that consumes the most, since zones overlap on some
+// platforms
+func (w Workload) zone(name string) Zone {
+	if name != "" {
+		return w.Zones[name]
+	}
+	var top Zone
+	for _, z := range w.Zones {
+		if z.Watts > top.Watts || (z.Watts == top.Watts && z.Joules > top.Joules) {
+			top = z
+		}
+	}
+	return top
+}
+
+// Top returns the n workloads consuming the most power in the zone of the name
+// or, if empty, in the zone each consumes the most in; all if n is not positive
+func Top(workloads []Workload, zone string, n int) []Workload {
+	top := slices.Clone(workloads)
+	slices.SortStableFunc(top, func(a, b Workload) int {
+		return cmp.Or(
+			cmp.Compare(b.zone(zone).Watts, a.zone(zone).Watts),
+			cmp.Compare(b.zone(zone).Joules, a.zone(zone).Joules),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// Find returns the workload of kind whose ID or name is id, or whose ID
+// starts with id
+func Find(workloads []Workload, kind, id string) (Workload, error) {
+	var byName, byPrefix []Workload
+	for _, w := range workloads {
+		if w.Kind != kind {
+			continue
+		}
+		switch {
+		case w.ID == id:
+			return w, nil
+		case w.Name == id:
+			byName = append(byName, w)
+		case strings.HasPrefix(w.ID, id):
+			byPrefix = append(byPrefix, w)
+		}
+	}
+
+	for _, matches := range [][]Workload{byName, byPrefix} {
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			return Workload{}, fmt.Errorf("%d %ss match %q; use the ID", len(matches), kind, id)
+		}
+	}
+	return Workload{}, fmt.Errorf("no running %s matches %q", kind, id)
+}
+
+// WriteNode writes the power of the node in each zone
+func WriteNode(out io.Writer, p *Power) {
+	rows := [][]string{}
+	for _, name := range slices.Sorted(maps.Keys(p.Node)) {
+		z := p.Node[name]
+		rows = append(rows, []string{name, watts(z.Watts), watts(z.ActiveWatts), watts(z.IdleWatts), joules(z.Joules)})
+	}
+	stdout.WriteTable(out, []string{"Zone", "Power(W)", "Active(W)", "Idle(W)", "Absolute(J)"}, rows)
+}
+
+// WriteWorkloads writes the power of workloads in the zone of the name or, if
+// empty, in the zone each consumes the most in
+func WriteWorkloads(out io.Writer, workloads []Workload, zone string) {
+	rows := [][]string{}
+	for _, w := range workloads {
+		id := w.ID
+		if w.Kind == KindContainer && len(id) > shortIDLen {
+			id = id[:shortIDLen]
+		}
+		z := w.zone(zone)
+		rows = append(rows, []string{id, w.Name, w.Namespace, watts(z.Watts), joules(z.Joules)})
+	}
+	stdout.WriteTable(out, []string{"ID", "Name", "Namespace", "Power(W)", "Absolute(J)"}, rows)
+}
+
+// WriteWorkload writes the details of w and its power in each zone
+func WriteWorkload(out io.Writer, w Workload) {
+	fields := [][2]string{
+		{"Kind", w.Kind},
+		{"ID", w.ID},
+		{"Name", w.Name},
+		{"Namespace", w.Namespace},
+		{"Pod", w.Pod},
+		{"Container", w.Container},
+		{"VM", w.VM},
+		{"CPU time", fmt.Sprintf("%.2fs", w.CPUSeconds)},
+	}
+	for _, f := range fields {
+		if f[1] != "" {
+			_, _ = fmt.Fprintf(out, "%-10s %s\n", f[0]+":", f[1])
+		}
+	}
+
+	rows := [][]string{}
+	for _, name := range slices.Sorted(maps.Keys(w.Zones)) {
+		z := w.Zones[name]
+		rows = append(rows, []string{name, watts(z.Watts), joules(z.Joules)})
+	}
+	stdout.WriteTable(out, []string{"Zone", "Power(W)", "Absolute(J)"}, rows)
+}
+
+// watts and joules format power and energy as the stdout exporter does
+func watts(w float64) string {
+	return (monitor.Power(w) * monitor.Watt).String()
+}
+
+func joules(j float64) string {
+	return (monitor.Energy(j) * monitor.Joule).String()
+}
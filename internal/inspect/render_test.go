@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTop(t *testing.T) {
+	workloads := []Workload{
+		{Kind: KindPod, ID: "a", Zones: map[string]Zone{"package": {Watts: 5}, "dram": {Watts: 1}}},
+		{Kind: KindPod, ID: "b", Zones: map[string]Zone{"package": {Watts: 2}, "dram": {Watts: 9}}},
+		{Kind: KindPod, ID: "c", Zones: map[string]Zone{"package": {Watts: 7}}},
+	}
+
+	ids := func(workloads []Workload) []string {
+		var ids []string
+		for _, w := range workloads {
+			ids = append(ids, w.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"b", "c", "a"}, ids(Top(workloads, "", 0)), "ranked by the zone each consumes the most in")
+	assert.Equal(t, []string{"c", "a"}, ids(Top(workloads, "package", 2)))
+	assert.Equal(t, []string{"b", "a", "c"}, ids(Top(workloads, "dram", 5)))
+	assert.Equal(t, "a", workloads[0].ID, "workloads are not reordered")
+}
+
+func TestFind(t *testing.T) {
+	workloads := []Workload{
+		{Kind: KindContainer, ID: "3f2a9c1b7e4d", Name: "web"},
+		{Kind: KindContainer, ID: "3f2a77aa0000", Name: "sidecar"},
+		{Kind: KindContainer, ID: "9b1c00000000", Name: "sidecar"},
+		{Kind: KindPod, ID: "web", Name: "web-0"},
+	}
+
+	w, err := Find(workloads, KindContainer, "3f2a9c1b7e4d")
+	require.NoError(t, err)
+	assert.Equal(t, "web", w.Name)
+
+	w, err = Find(workloads, KindContainer, "web")
+	require.NoError(t, err)
+	assert.Equal(t, "3f2a9c1b7e4d", w.ID, "by name")
+
+	w, err = Find(workloads, KindContainer, "9b1c")
+	require.NoError(t, err)
+	assert.Equal(t, "9b1c00000000", w.ID, "by ID prefix")
+
+	_, err = Find(workloads, KindContainer, "3f2a")
+	assert.ErrorContains(t, err, `2 containers match "3f2a"`)
+	_, err = Find(workloads, KindContainer, "sidecar")
+	assert.ErrorContains(t, err, `2 containers match "sidecar"`)
+	_, err = Find(workloads, KindVM, "web")
+	assert.ErrorContains(t, err, `no running vm matches "web"`)
+}
+
+func TestWrite(t *testing.T) {
+	p := newPower(testSnapshot(), "")
+
+	var out bytes.Buffer
+	WriteNode(&out, &p)
+	assert.Contains(t, out.String(), "100.00W")
+	assert.Contains(t, out.String(), "1000.00J")
+
+	out.Reset()
+	WriteWorkloads(&out, []Workload{
+		{Kind: KindContainer, ID: "3f2a9c1b7e4d5a6b", Name: "web", Namespace: "prod", Zones: map[string]Zone{"package": {Joules: 50, Watts: 5}}},
+	}, "")
+	assert.Contains(t, out.String(), "3f2a9c1b7e4d ", "container IDs are shortened")
+	assert.NotContains(t, out.String(), "3f2a9c1b7e4d5a6b")
+	assert.Contains(t, out.String(), "5.00W")
+
+	out.Reset()
+	w, err := Find(p.Workloads, KindPod, "web-0")
+	require.NoError(t, err)
+	WriteWorkload(&out, w)
+	assert.Contains(t, out.String(), "Namespace: prod")
+	assert.NotContains(t, out.String(), "Container:")
+	assert.Contains(t, out.String(), "50.00J", "zones of the same name are summed")
+}
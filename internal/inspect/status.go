@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/logger"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"github.com/sustainable-computing-io/kepler/internal/version"
+)
+
+// StatusToolName is the name of the MCP tool returning the status of Kepler
+const StatusToolName = "get_agent_status"
+
+// MonitorIntervalKey is the key of the collection interval in the intervals
+// of the status
+const MonitorIntervalKey = "monitor.interval"
+
+// IntervalReporter reports the current collection interval, which may differ
+// from the configured one once the configuration is reloaded; it is only
+// reported by monitors implementing it
+type IntervalReporter interface {
+	Interval() time.Duration
+}
+
+// Status is the status of the running Kepler, to find out why power data is
+// missing without access to the node or its logs
+type Status struct {
+	Version   string            `json:"version"`
+	Timestamp time.Time         `json:"timestamp"`       // of the last collection
+	Error     string            `json:"error,omitempty"` // why the last collection can't be read
+	Services  []ServiceStatus   `json:"services"`
+	Meters    []string          `json:"meters"`    // the available zones are read from, e.g. rapl or redfish
+	Sources   []SourceStatus    `json:"sources"`   // other than zones, e.g. carbon intensity providers
+	Intervals map[string]string `json:"intervals"` // keyed by configuration setting
+	Zones     []ZoneInfo        `json:"zones"`
+	Errors    []logger.Record   `json:"errors"` // recent warnings and errors logged, oldest first
+}
+
+// ServiceStatus is the status of an enabled service
+type ServiceStatus struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"` // why it is unhealthy
+}
+
+// SourceStatus is whether a source other than a zone, e.g. the utilization of
+// GPUs or a carbon intensity provider, could be read in the last collection
+type SourceStatus struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Error     string `json:"error,omitempty"` // why it is unavailable
+}
+
+func (a *API) callStatusTool(_ context.Context, _ json.RawMessage) (any, error) {
+	return a.status(), nil
+}
+
+// status returns the status of Kepler; the collection related parts are empty
+// if there is no snapshot
+func (a *API) status() Status {
+	s := Status{
+		Version:   version.Info().Version,
+		Services:  []ServiceStatus{},
+		Meters:    []string{},
+		Sources:   []SourceStatus{},
+		Intervals: map[string]string{},
+		Zones:     []ZoneInfo{},
+		Errors:    append([]logger.Record{}, logger.Recent()...),
+	}
+
+	for _, svc := range a.services {
+		s.Services = append(s.Services, serviceStatus(svc))
+	}
+
+	intervals := map[string]time.Duration{}
+	maps.Copy(intervals, a.intervals)
+	if r, ok := a.monitor.(IntervalReporter); ok {
+		intervals[MonitorIntervalKey] = r.Interval()
+	}
+	for key, d := range intervals {
+		s.Intervals[key] = d.String()
+	}
+
+	snapshot, err := a.monitor.LatestSnapshot()
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Timestamp = snapshot.Timestamp
+	s.Zones = a.zones(snapshot)
+	for _, z := range s.Zones {
+		if z.Available && z.Source != "" && !slices.Contains(s.Meters, z.Source) {
+			s.Meters = append(s.Meters, z.Source)
+		}
+	}
+	for _, src := range snapshot.Sources {
+		if src.Kind == monitor.SourceZone {
+			continue
+		}
+		ss := SourceStatus{Kind: src.Kind, Name: src.Name, Available: src.Available}
+		if src.Err != nil {
+			ss.Error = src.Err.Error()
+		}
+		s.Sources = append(s.Sources, ss)
+	}
+	return s
+}
+
+// serviceStatus returns whether svc is ready, if it reports it, and healthy,
+// if it checks it
+func serviceStatus(svc service.Service) ServiceStatus {
+	s := ServiceStatus{Name: svc.Name(), Ready: true, Healthy: true}
+	if r, ok := svc.(service.Readier); ok {
+		select {
+		case <-r.Ready():
+		default:
+			s.Ready = false
+		}
+	}
+	if hc, ok := svc.(service.HealthChecker); ok {
+		if err := hc.Health(); err != nil {
+			s.Healthy = false
+			s.Error = err.Error()
+		}
+	}
+	return s
+}
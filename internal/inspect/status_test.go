@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+)
+
+var _ IntervalReporter = (*monitor.PowerMonitor)(nil)
+
+// fakeStatusMonitor reports the meter of zones and its collection interval
+type fakeStatusMonitor struct {
+	fakeMeterMonitor
+	interval time.Duration
+}
+
+func (m *fakeStatusMonitor) Interval() time.Duration { return m.interval }
+
+// fakeService is a service that is ready once ready is closed, and healthy
+// unless err is set
+type fakeService struct {
+	name  string
+	ready chan struct{}
+	err   error
+}
+
+func (s *fakeService) Name() string           { return s.name }
+func (s *fakeService) Ready() <-chan struct{} { return s.ready }
+func (s *fakeService) Health() error          { return s.err }
+
+func TestStatus(t *testing.T) {
+	s := testSnapshot()
+	s.Sources = []monitor.Source{
+		{Kind: monitor.SourceZone, Zone: pkg0, Available: true},
+		{Kind: monitor.SourceZone, Zone: dram, Err: device.ErrPermission},
+		{Kind: monitor.SourceCarbon, Name: "electricitymaps", Err: errors.New("401 Unauthorized")},
+	}
+	pm := &fakeStatusMonitor{
+		fakeMeterMonitor: fakeMeterMonitor{
+			FakeMonitor: monitor.NewFakeMonitor(s),
+			meters:      map[monitor.EnergyZone]string{pkg0: "rapl", dram: "rapl"},
+		},
+		interval: 10 * time.Second,
+	}
+	ready := make(chan struct{})
+	close(ready)
+	services := []*fakeService{
+		{name: "monitor", ready: ready},
+		{name: "carbon", ready: make(chan struct{}), err: errors.New("no intensity fetched")},
+	}
+
+	registry := server.NewFakeRegistry()
+	api := NewAPI(pm, registry,
+		WithTools(registry),
+		WithServices(services[0], services[1]),
+		WithIntervals(map[string]time.Duration{
+			MonitorIntervalKey:       5 * time.Second,
+			"carbon.refreshInterval": 15 * time.Minute,
+		}),
+	)
+	require.NoError(t, api.Init())
+	require.Contains(t, registry.Tools, StatusToolName)
+
+	result, err := registry.Tools[StatusToolName](context.Background(), nil)
+	require.NoError(t, err)
+	// the status is returned to MCP clients as JSON
+	out, err := json.Marshal(result)
+	require.NoError(t, err)
+	var status Status
+	require.NoError(t, json.Unmarshal(out, &status))
+
+	assert.Equal(t, s.Timestamp, status.Timestamp)
+	assert.Empty(t, status.Error)
+	assert.Equal(t, []ServiceStatus{
+		{Name: "monitor", Ready: true, Healthy: true},
+		{Name: "carbon", Error: "no intensity fetched"},
+	}, status.Services)
+	assert.Equal(t, []string{"rapl"}, status.Meters, "only the meters of available zones")
+	assert.Equal(t, []SourceStatus{
+		{Kind: monitor.SourceCarbon, Name: "electricitymaps", Error: "401 Unauthorized"},
+	}, status.Sources, "zones are not listed as sources")
+	assert.Equal(t, map[string]string{
+		MonitorIntervalKey:       "10s",
+		"carbon.refreshInterval": "15m0s",
+	}, status.Intervals, "the current collection interval")
+	require.Len(t, status.Zones, 2)
+	assert.Equal(t, device.ErrPermission.Error(), status.Zones[0].Error)
+	assert.NotNil(t, status.Errors)
+
+	t.Run("no snapshot", func(t *testing.T) {
+		pm.SetError(errors.New("failed to get snapshot"))
+		defer pm.SetError(nil)
+
+		status := api.status()
+		assert.Equal(t, "failed to get snapshot", status.Error)
+		assert.Empty(t, status.Zones)
+		assert.Len(t, status.Services, 2, "services are reported without a snapshot")
+		assert.Equal(t, "10s", status.Intervals[MonitorIntervalKey])
+	})
+}
@@ -0,0 +1,358 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+// Names of the MCP tools returning the power of workloads
+const (
+	WorkloadsToolName       = "get_workload_power"
+	EmissionsToolName       = "get_emissions"
+	SystemdUnitsToolName    = "get_systemd_unit_power"
+	SearchProcessesToolName = "search_processes"
+)
+
+// KindUnit selects systemd units in the MCP tools
+const KindUnit = "unit"
+
+// toolKinds are the kinds of workloads of the MCP tools
+var toolKinds = append(slices.Clone(Kinds), KindUnit)
+
+// defaultTopN is the number of workloads returned by the MCP tools by default
+const defaultTopN = 10
+
+// WorkloadPower is the power of a running workload split between its share of
+// the active and idle power of the node, with its emissions
+type WorkloadPower struct {
+	Kind      string `json:"kind"`
+	ID        string `json:"id"` // PID of processes, name of systemd units
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"` // of pods and their containers
+	Slice     string `json:"slice,omitempty"`     // of systemd units
+
+	// Command line, user and parent of processes; only set if Kepler tracks
+	// process metadata
+	CmdLine   string `json:"cmdline,omitempty"`
+	User      string `json:"user,omitempty"`
+	UID       *int   `json:"uid,omitempty"`
+	ParentPID int    `json:"parentPid,omitempty"`
+
+	// Watts and GramsCO2e are those of the ranking zone, the zone of the node
+	// that consumed the most energy
+	Watts     float64 `json:"watts"`
+	GramsCO2e float64 `json:"gramsCO2e"`
+
+	Zones map[string]ZonePower `json:"zones"` // keyed by zone name
+}
+
+// ZonePower is the energy, power and emissions of a workload in a zone
+type ZonePower struct {
+	Joules      float64 `json:"joules"` // cumulative energy
+	Watts       float64 `json:"watts"`
+	ActiveWatts float64 `json:"activeWatts"`
+	IdleWatts   float64 `json:"idleWatts"`
+	GramsCO2e   float64 `json:"gramsCO2e"` // cumulative emissions
+}
+
+// Workloads is the result of the MCP tools returning the power of workloads
+type Workloads struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Zone      string          `json:"zone"` // workloads are ranked by
+	Workloads []WorkloadPower `json:"workloads"`
+}
+
+// Emissions is the result of the emissions MCP tool
+type Emissions struct {
+	Timestamp time.Time `json:"timestamp"`
+	Zone      string    `json:"zone"` // workloads are ranked by
+
+	// CarbonIntensity of the electricity in the last interval in gCO2e/kWh;
+	// 0 if unknown, in which case no emissions are counted
+	CarbonIntensity float64            `json:"carbonIntensity"`
+	Node            map[string]float64 `json:"node"` // grams of CO2e keyed by zone name
+	Workloads       []WorkloadPower    `json:"workloads"`
+}
+
+// workloadArgs are the arguments of the MCP tools returning workloads
+type workloadArgs struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Slice     string `json:"slice"`
+	User      string `json:"user"`
+	CmdLine   string `json:"cmdline"`
+	Name      string `json:"name"`
+	N         int    `json:"n"`
+}
+
+// registerWorkloadTools registers the MCP tools returning the power of
+// workloads
+func (a *API) registerWorkloadTools() error {
+	str := func(description string) map[string]any {
+		return map[string]any{"type": "string", "description": description}
+	}
+	kind := map[string]any{
+		"type":        "string",
+		"enum":        toolKinds,
+		"description": "Kind of the workloads; unit for systemd units (default pod)",
+	}
+	n := map[string]any{
+		"type":        "integer",
+		"description": fmt.Sprintf("Maximum number of workloads returned (default %d)", defaultTopN),
+	}
+
+	if err := a.tools.RegisterTool(WorkloadsToolName,
+		"Running workloads drawing the most power, with the power of each zone split between their share of "+
+			"the active power of the node and of its idle power, and their emissions",
+		map[string]any{"type": "object", "properties": map[string]any{
+			"kind":      kind,
+			"namespace": str("Only return the pods and containers of the namespace"),
+			"n":         n,
+		}},
+		a.callWorkloadsTool); err != nil {
+		return err
+	}
+	if err := a.tools.RegisterTool(EmissionsToolName,
+		"Grams of CO2e emitted by the node in each zone since Kepler started, the current carbon intensity of "+
+			"the grid, and the running workloads that emitted the most",
+		map[string]any{"type": "object", "properties": map[string]any{
+			"kind":      kind,
+			"namespace": str("Only return the pods and containers of the namespace"),
+			"n":         n,
+		}},
+		a.callEmissionsTool); err != nil {
+		return err
+	}
+	if err := a.tools.RegisterTool(SystemdUnitsToolName,
+		"Systemd services and scopes drawing the most power, with their slice, on hosts where processes are "+
+			"grouped by systemd unit",
+		map[string]any{"type": "object", "properties": map[string]any{
+			"slice": str("Only return the units of the slice, e.g. system.slice"),
+			"n":     n,
+		}},
+		a.callSystemdUnitsTool); err != nil {
+		return err
+	}
+	return a.tools.RegisterTool(SearchProcessesToolName,
+		"Running processes drawing the most power whose user, command line or name match, with their command "+
+			"line, user and parent; users and command lines are only known if Kepler tracks process metadata",
+		map[string]any{"type": "object", "properties": map[string]any{
+			"user":    str("Only return the processes of the user name or UID"),
+			"cmdline": str("Only return the processes whose command line contains the text"),
+			"name":    str("Only return the processes whose name contains the text"),
+			"n":       n,
+		}},
+		a.callSearchProcessesTool)
+}
+
+// parseWorkloadArgs returns the arguments of an MCP tool call, with the kind
+// defaulting to pods
+func parseWorkloadArgs(args json.RawMessage) (workloadArgs, error) {
+	var params workloadArgs
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return workloadArgs{}, err
+		}
+	}
+	params.Kind = cmp.Or(params.Kind, KindPod)
+	if !slices.Contains(toolKinds, params.Kind) {
+		return workloadArgs{}, fmt.Errorf("invalid kind: %q; must be one of %s", params.Kind, strings.Join(toolKinds, ", "))
+	}
+	params.N = cmp.Or(max(params.N, 0), defaultTopN)
+	return params, nil
+}
+
+func (a *API) callWorkloadsTool(_ context.Context, args json.RawMessage) (any, error) {
+	params, err := parseWorkloadArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return a.workloads(params)
+}
+
+// workloads returns the running workloads selected by params drawing the
+// most power
+func (a *API) workloads(params workloadArgs) (Workloads, error) {
+	snapshot, err := a.monitor.LatestSnapshot()
+	if err != nil {
+		return Workloads{}, err
+	}
+
+	zone := rankingZone(snapshot.Node)
+	workloads := filterWorkloads(workloadPowers(snapshot, params.Kind, zone), params)
+	return Workloads{
+		Timestamp: snapshot.Timestamp,
+		Zone:      zone,
+		Workloads: top(workloads, params.N, func(w WorkloadPower) float64 { return w.Watts }),
+	}, nil
+}
+
+func (a *API) callEmissionsTool(_ context.Context, args json.RawMessage) (any, error) {
+	params, err := parseWorkloadArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := a.monitor.LatestSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	zone := rankingZone(snapshot.Node)
+	e := Emissions{
+		Timestamp: snapshot.Timestamp,
+		Zone:      zone,
+		Node:      map[string]float64{},
+	}
+	if snapshot.Node != nil {
+		e.CarbonIntensity = snapshot.Node.CarbonIntensity
+		for z, usage := range snapshot.Node.Zones {
+			e.Node[z.Name()] += usage.EmissionsTotal
+		}
+	}
+	workloads := filterWorkloads(workloadPowers(snapshot, params.Kind, zone), params)
+	e.Workloads = top(workloads, params.N, func(w WorkloadPower) float64 { return w.GramsCO2e })
+	return e, nil
+}
+
+func (a *API) callSystemdUnitsTool(_ context.Context, args json.RawMessage) (any, error) {
+	params, err := parseWorkloadArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	params.Kind = KindUnit
+	return a.workloads(params)
+}
+
+func (a *API) callSearchProcessesTool(_ context.Context, args json.RawMessage) (any, error) {
+	params, err := parseWorkloadArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	params.Kind = KindProcess
+	return a.workloads(params)
+}
+
+// rankingZone returns the name of the zone of the node that consumed the most
+// energy, e.g. psys or package, since zones overlap on some platforms
+func rankingZone(node *monitor.Node) string {
+	if node == nil {
+		return ""
+	}
+	energy := map[string]monitor.Energy{}
+	for zone, usage := range node.Zones {
+		energy[zone.Name()] += usage.EnergyTotal
+	}
+	ranking := ""
+	for name, e := range energy {
+		if ranking == "" || e > energy[ranking] || (e == energy[ranking] && name < ranking) {
+			ranking = name
+		}
+	}
+	return ranking
+}
+
+// workloadPowers returns the power of the running workloads of kind in snapshot,
+// with the watts and emissions of zone
+func workloadPowers(snapshot *monitor.Snapshot, kind, zone string) []WorkloadPower {
+	ret := []WorkloadPower{}
+	add := func(w WorkloadPower, usage monitor.ZoneUsageMap) {
+		w.Kind = kind
+		w.Zones = zonePowers(usage)
+		w.Watts = w.Zones[zone].Watts
+		w.GramsCO2e = w.Zones[zone].GramsCO2e
+		ret = append(ret, w)
+	}
+
+	switch kind {
+	case KindProcess:
+		for _, p := range snapshot.Processes {
+			w := WorkloadPower{
+				ID:        strconv.Itoa(p.PID),
+				Name:      p.Comm,
+				CmdLine:   p.CmdLine,
+				User:      p.User,
+				ParentPID: p.ParentPID,
+			}
+			if p.User != "" || p.CmdLine != "" {
+				uid := p.UID
+				w.UID = &uid
+			}
+			add(w, p.Zones)
+		}
+	case KindContainer:
+		for _, c := range snapshot.Containers {
+			w := WorkloadPower{ID: c.ID, Name: c.Name}
+			if pod, ok := snapshot.Pods[c.PodID]; ok {
+				w.Namespace = pod.Namespace
+			}
+			add(w, c.Zones)
+		}
+	case KindVM:
+		for _, vm := range snapshot.VirtualMachines {
+			add(WorkloadPower{ID: vm.ID, Name: vm.Name}, vm.Zones)
+		}
+	case KindPod:
+		for _, pod := range snapshot.Pods {
+			add(WorkloadPower{ID: pod.ID, Name: pod.Name, Namespace: pod.Namespace}, pod.Zones)
+		}
+	case KindUnit:
+		for _, u := range snapshot.SystemdUnits {
+			add(WorkloadPower{ID: u.Name, Name: u.Name, Slice: u.Slice}, u.Zones)
+		}
+	}
+	return ret
+}
+
+// zonePowers returns the energy, power and emissions of usage keyed by zone
+// name, summing zones of the same name
+func zonePowers(usage monitor.ZoneUsageMap) map[string]ZonePower {
+	ret := make(map[string]ZonePower, len(usage))
+	for zone, u := range usage {
+		z := ret[zone.Name()]
+		z.Joules += u.EnergyTotal.Joules()
+		z.Watts += u.Power.Watts()
+		z.ActiveWatts += u.ActivePower.Watts()
+		z.IdleWatts += u.IdlePower.Watts()
+		z.GramsCO2e += u.EmissionsTotal
+		ret[zone.Name()] = z
+	}
+	return ret
+}
+
+// filterWorkloads returns the workloads matching the filters of params
+func filterWorkloads(workloads []WorkloadPower, params workloadArgs) []WorkloadPower {
+	return slices.DeleteFunc(workloads, func(w WorkloadPower) bool {
+		switch {
+		case params.Namespace != "" && w.Namespace != params.Namespace:
+			return true
+		case params.Slice != "" && w.Slice != params.Slice:
+			return true
+		case params.User != "" && w.User != params.User && (w.UID == nil || strconv.Itoa(*w.UID) != params.User):
+			return true
+		case params.CmdLine != "" && !strings.Contains(w.CmdLine, params.CmdLine):
+			return true
+		case params.Name != "" && !strings.Contains(w.Name, params.Name):
+			return true
+		}
+		return false
+	})
+}
+
+// top returns the n workloads of the highest value, sorted by it
+func top(workloads []WorkloadPower, n int, value func(WorkloadPower) float64) []WorkloadPower {
+	slices.SortFunc(workloads, func(a, b WorkloadPower) int {
+		return cmp.Or(cmp.Compare(value(b), value(a)), cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.ID, b.ID))
+	})
+	return workloads[:min(n, len(workloads))]
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+)
+
+func TestWorkloadTools(t *testing.T) {
+	s := testSnapshot()
+	s.Node.CarbonIntensity = 400
+	s.Node.Zones[pkg0] = monitor.NodeUsage{EnergyTotal: 600 * device.Joule, Power: 60 * device.Watt, EmissionsTotal: 0.06}
+	s.Pods["pod-1"].Zones[pkg0] = monitor.Usage{
+		EnergyTotal: 30 * device.Joule, Power: 3 * device.Watt,
+		ActivePower: 2 * device.Watt, IdlePower: 1 * device.Watt, EmissionsTotal: 0.003,
+	}
+	s.Pods["pod-2"] = &monitor.Pod{ID: "pod-2", Name: "db-0", Namespace: "staging",
+		Zones: monitor.ZoneUsageMap{pkg0: {EnergyTotal: 90 * device.Joule, Power: 1 * device.Watt, EmissionsTotal: 0.009}}}
+	s.Processes["7"] = &monitor.Process{PID: 7, Comm: "backup", CmdLine: "/usr/bin/backup --full /srv", UID: 1000, User: "alice",
+		Zones: monitor.ZoneUsageMap{pkg0: usage(5, 5)}}
+	s.SystemdUnits["sshd.service"] = &monitor.SystemdUnit{Name: "sshd.service", Slice: "system.slice",
+		Zones: monitor.ZoneUsageMap{pkg0: usage(4, 0.5)}}
+	s.SystemdUnits["session-2.scope"] = &monitor.SystemdUnit{Name: "session-2.scope", Slice: "user-1000.slice",
+		Zones: monitor.ZoneUsageMap{pkg0: usage(8, 2)}}
+
+	registry := server.NewFakeRegistry()
+	require.NoError(t, NewAPI(monitor.NewFakeMonitor(s), registry, WithTools(registry)).Init())
+	call := func(name, args string) any {
+		t.Helper()
+		require.Contains(t, registry.Tools, name)
+		result, err := registry.Tools[name](context.Background(), json.RawMessage(args))
+		require.NoError(t, err)
+		return result
+	}
+	ids := func(workloads []WorkloadPower) []string {
+		ret := []string{}
+		for _, w := range workloads {
+			ret = append(ret, w.ID)
+		}
+		return ret
+	}
+
+	t.Run("workload power", func(t *testing.T) {
+		got := call(WorkloadsToolName, "").(Workloads)
+		assert.Equal(t, s.Timestamp, got.Timestamp)
+		assert.Equal(t, "package", got.Zone)
+		require.Equal(t, []string{"pod-1", "pod-2"}, ids(got.Workloads))
+		assert.Equal(t, WorkloadPower{
+			Kind: KindPod, ID: "pod-1", Name: "web-0", Namespace: "prod",
+			Watts: 5, GramsCO2e: 0.003,
+			Zones: map[string]ZonePower{"package": {Joules: 50, Watts: 5, ActiveWatts: 2, IdleWatts: 1, GramsCO2e: 0.003}},
+		}, got.Workloads[0])
+
+		got = call(WorkloadsToolName, `{"namespace": "staging"}`).(Workloads)
+		assert.Equal(t, []string{"pod-2"}, ids(got.Workloads))
+
+		got = call(WorkloadsToolName, `{"kind": "vm", "n": 1}`).(Workloads)
+		assert.Equal(t, []string{"vm-1"}, ids(got.Workloads))
+
+		_, err := registry.Tools[WorkloadsToolName](context.Background(), json.RawMessage(`{"kind": "node"}`))
+		assert.ErrorContains(t, err, `invalid kind: "node"`)
+	})
+
+	t.Run("emissions", func(t *testing.T) {
+		got := call(EmissionsToolName, "").(Emissions)
+		assert.Equal(t, 400.0, got.CarbonIntensity)
+		assert.InDelta(t, 0.06, got.Node["package"], 1e-9)
+		assert.Equal(t, []string{"pod-2", "pod-1"}, ids(got.Workloads), "sorted by emissions")
+	})
+
+	t.Run("systemd units", func(t *testing.T) {
+		got := call(SystemdUnitsToolName, "").(Workloads)
+		assert.Equal(t, []string{"session-2.scope", "sshd.service"}, ids(got.Workloads))
+		assert.Equal(t, "user-1000.slice", got.Workloads[0].Slice)
+
+		got = call(SystemdUnitsToolName, `{"slice": "system.slice"}`).(Workloads)
+		assert.Equal(t, []string{"sshd.service"}, ids(got.Workloads))
+	})
+
+	t.Run("search processes", func(t *testing.T) {
+		got := call(SearchProcessesToolName, "").(Workloads)
+		assert.Equal(t, []string{"7", "42"}, ids(got.Workloads))
+
+		for _, args := range []string{`{"user": "alice"}`, `{"user": "1000"}`, `{"cmdline": "--full"}`, `{"name": "back"}`} {
+			got = call(SearchProcessesToolName, args).(Workloads)
+			require.Equal(t, []string{"7"}, ids(got.Workloads), args)
+		}
+		p := got.Workloads[0]
+		assert.Equal(t, "/usr/bin/backup --full /srv", p.CmdLine)
+		assert.Equal(t, "alice", p.User)
+		require.NotNil(t, p.UID)
+		assert.Equal(t, 1000, *p.UID)
+
+		got = call(SearchProcessesToolName, `{"user": "bob"}`).(Workloads)
+		assert.Empty(t, got.Workloads)
+	})
+}
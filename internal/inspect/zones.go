@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"slices"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+// ZonesEndpoint is the endpoint the energy zones are served at
+const ZonesEndpoint = "/zones"
+
+// ZonesToolName is the name of the MCP tool returning the energy zones
+const ZonesToolName = "list_energy_zones"
+
+// How the energy counters of zones wrap around
+const (
+	// WrapAtMax counters wrap around to 0 after MaxJoules, which is accounted for
+	WrapAtMax = "max"
+	// WrapNever counters are accumulated by Kepler and don't wrap around
+	WrapNever = "never"
+	// WrapUnknown counters have no known maximum; the energy of an interval in
+	// which they decrease is counted as 0
+	WrapUnknown = "unknown"
+)
+
+// MeterResolver resolves the meter the energy of a zone is read from; the
+// source of zones is only reported by monitors implementing it
+type MeterResolver interface {
+	MeterOf(zone monitor.EnergyZone) string
+}
+
+// ZoneInfo describes an energy zone of the node, to find out why a zone is
+// missing or not reported as expected
+type ZoneInfo struct {
+	Name        string  `json:"name"`
+	Index       int     `json:"index"`
+	Source      string  `json:"source,omitempty"` // meter, e.g. rapl, hwmon, estimator, nvidia, io or redfish
+	Path        string  `json:"path"`             // the energy is read from, e.g. in sysfs
+	Wraparound  string  `json:"wraparound"`
+	MaxJoules   float64 `json:"maxJoules,omitempty"`   // the counter wraps around at
+	Uncertainty float64 `json:"uncertainty,omitempty"` // relative; of estimated zones
+	Available   bool    `json:"available"`             // whether it was read in the last collection
+	Error       string  `json:"error,omitempty"`       // why it is unavailable
+}
+
+// handleZones serves the energy zones of the node
+func (a *API) handleZones(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	snapshot, err := a.monitor.LatestSnapshot()
+	if err != nil {
+		a.logger.Error("Failed to get snapshot", "error", err)
+		http.Error(w, "failed to get zones", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(a.zones(snapshot)); err != nil {
+		a.logger.Error("Failed to write zones", "error", err)
+	}
+}
+
+func (a *API) callZonesTool(_ context.Context, _ json.RawMessage) (any, error) {
+	snapshot, err := a.monitor.LatestSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return a.zones(snapshot), nil
+}
+
+// zones returns the energy zones read in the collection of snapshot, sorted by
+// source, name and index. Zones filtered out by the configuration are not read
+// and so not listed.
+func (a *API) zones(snapshot *monitor.Snapshot) []ZoneInfo {
+	resolver, _ := a.monitor.(MeterResolver)
+
+	ret := []ZoneInfo{}
+	for _, s := range snapshot.Sources {
+		if s.Kind != monitor.SourceZone || s.Zone == nil {
+			continue
+		}
+		zone := s.Zone
+		info := ZoneInfo{
+			Name:        zone.Name(),
+			Index:       zone.Index(),
+			Path:        zone.Path(),
+			Uncertainty: device.Uncertainty(zone),
+			Available:   s.Available,
+		}
+		if resolver != nil {
+			info.Source = resolver.MeterOf(zone)
+		}
+		switch maxEnergy := zone.MaxEnergy(); {
+		case maxEnergy == 0:
+			info.Wraparound = WrapUnknown
+		case maxEnergy == math.MaxUint64:
+			info.Wraparound = WrapNever
+		default:
+			info.Wraparound = WrapAtMax
+			info.MaxJoules = maxEnergy.Joules()
+		}
+		if s.Err != nil {
+			info.Error = s.Err.Error()
+		}
+		ret = append(ret, info)
+	}
+
+	slices.SortFunc(ret, func(x, y ZoneInfo) int {
+		return cmp.Or(cmp.Compare(x.Source, y.Source), cmp.Compare(x.Name, y.Name), cmp.Compare(x.Index, y.Index))
+	})
+	return ret
+}
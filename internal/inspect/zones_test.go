@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+)
+
+// fakeMeterMonitor reports the meter of zones
+type fakeMeterMonitor struct {
+	*monitor.FakeMonitor
+	meters map[monitor.EnergyZone]string
+}
+
+func (m *fakeMeterMonitor) MeterOf(zone monitor.EnergyZone) string { return m.meters[zone] }
+
+func TestZones(t *testing.T) {
+	noMax := device.NewMockRaplZone("psys", 0, "/sys/class/powercap/intel-rapl/intel-rapl:1", 0)
+	platform := device.NewPowerZone("platform", 0, "https://bmc/redfish/v1/Chassis/1/Power", nil, nil)
+
+	s := testSnapshot()
+	s.Sources = []monitor.Source{
+		{Kind: monitor.SourceCarbon, Name: "electricitymaps", Available: true},
+		{Kind: monitor.SourceZone, Zone: pkg0, Available: true},
+		{Kind: monitor.SourceZone, Zone: noMax, Err: device.ErrPermission},
+		{Kind: monitor.SourceZone, Zone: platform, Available: true},
+	}
+	pm := &fakeMeterMonitor{
+		FakeMonitor: monitor.NewFakeMonitor(s),
+		meters:      map[monitor.EnergyZone]string{pkg0: "rapl", noMax: "rapl", platform: "redfish"},
+	}
+
+	registry := server.NewFakeRegistry()
+	api := NewAPI(pm, registry, WithTools(registry))
+	require.NoError(t, api.Init())
+	require.Contains(t, registry.Handlers, ZonesEndpoint)
+	require.Contains(t, registry.Tools, ZonesToolName)
+
+	want := []ZoneInfo{{
+		Name: "package", Index: 0, Source: "rapl", Path: "/sys/class/powercap/intel-rapl/intel-rapl:0",
+		Wraparound: WrapAtMax, MaxJoules: 1000, Available: true,
+	}, {
+		Name: "psys", Index: 0, Source: "rapl", Path: "/sys/class/powercap/intel-rapl/intel-rapl:1",
+		Wraparound: WrapUnknown, Error: device.ErrPermission.Error(),
+	}, {
+		Name: "platform", Index: 0, Source: "redfish", Path: "https://bmc/redfish/v1/Chassis/1/Power",
+		Wraparound: WrapNever, Available: true,
+	}}
+
+	t.Run("rest", func(t *testing.T) {
+		server := httptest.NewServer(registry.Handlers[ZonesEndpoint])
+		defer server.Close()
+
+		resp, err := server.Client().Get(server.URL)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var got []ZoneInfo
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+		assert.Equal(t, want, got, "only zones are listed, sorted by source and name")
+	})
+
+	t.Run("tool", func(t *testing.T) {
+		got, err := registry.Tools[ZonesToolName](context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("without meters", func(t *testing.T) {
+		api := NewAPI(monitor.NewFakeMonitor(s), server.NewFakeRegistry())
+		zones := api.zones(s)
+		require.Len(t, zones, 3)
+		for _, z := range zones {
+			assert.Empty(t, z.Source)
+		}
+	})
+}
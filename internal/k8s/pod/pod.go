@@ -12,7 +12,9 @@ import (
 	"github.com/sustainable-computing-io/kepler/internal/logger"
 	"github.com/sustainable-computing-io/kepler/internal/service"
 	"go.uber.org/zap/zapcore"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -40,6 +42,18 @@ type (
 		PodName       string
 		Namespace     string
 		ContainerName string
+
+		// CPU requests and limits of the pod in cores; 0 if not set
+		PodCPURequest float64
+		PodCPULimit   float64
+
+		// PodLabels are the labels of the pod
+		PodLabels map[string]string
+
+		// OwnerKind and OwnerName identify the workload controlling the pod,
+		// e.g. a Deployment or StatefulSet; empty for pods without a controller
+		OwnerKind string
+		OwnerName string
 	}
 
 	podInformer struct {
@@ -53,6 +67,9 @@ type (
 
 		createRestConfigFunc func(kubeConfigPath string) (*rest.Config, error)
 		newManagerFunc       func(config *rest.Config, options ctrl.Options) (ctrl.Manager, error)
+
+		// ready is closed once the cache of pods is synced
+		ready chan struct{}
 	}
 
 	Option struct {
@@ -100,6 +117,7 @@ func NewInformer(opts ...OptFn) *podInformer {
 		nodeName:             opt.nodeName,
 		createRestConfigFunc: getConfig,
 		newManagerFunc:       ctrl.NewManager,
+		ready:                make(chan struct{}),
 	}
 }
 
@@ -202,9 +220,21 @@ func extractContainerID(str string) string {
 
 func (pi *podInformer) Run(ctx context.Context) error {
 	pi.logger.Info("Starting pod informer")
+	go func() {
+		if pi.manager.GetCache().WaitForCacheSync(ctx) {
+			pi.logger.Info("pod informer cache synced")
+			close(pi.ready)
+		}
+	}()
 	return pi.manager.Start(ctx)
 }
 
+// Ready returns a channel that is closed once the cache of pods is synced, so
+// that the pods of containers can be looked up
+func (pi *podInformer) Ready() <-chan struct{} {
+	return pi.ready
+}
+
 // LookupByContainerID retrieves pod details and container name given a containerID
 func (pi *podInformer) LookupByContainerID(containerID string) (*ContainerInfo, bool, error) {
 	var pods corev1.PodList
@@ -229,11 +259,18 @@ func (pi *podInformer) LookupByContainerID(containerID string) (*ContainerInfo,
 		containerName := pi.findContainerName(&pod, containerID)
 		pi.logger.Debug("pod found for container", "container", containerID, "pod", pod.Name, "containerName", containerName)
 
+		request, limit := podCPUResources(&pod)
+		ownerKind, ownerName := podOwner(&pod)
 		return &ContainerInfo{
 			PodID:         string(pod.UID),
 			PodName:       pod.Name,
 			Namespace:     pod.Namespace,
 			ContainerName: containerName,
+			PodCPURequest: request,
+			PodCPULimit:   limit,
+			PodLabels:     pod.Labels,
+			OwnerKind:     ownerKind,
+			OwnerName:     ownerName,
 		}, true, nil
 	}
 }
@@ -292,3 +329,36 @@ func (pi *podInformer) findContainerName(pod *corev1.Pod, containerID string) st
 	}
 	return ""
 }
+
+// podCPUResources returns the sum of the CPU requests and limits (in cores) of
+// the regular containers of the pod
+func podCPUResources(pod *corev1.Pod) (request, limit float64) {
+	for _, c := range pod.Spec.Containers {
+		if cpu, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+			request += cpu.AsApproximateFloat64()
+		}
+		if cpu, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+			limit += cpu.AsApproximateFloat64()
+		}
+	}
+	return request, limit
+}
+
+// podOwner returns the kind and name of the workload controlling the pod.
+// Pods of a Deployment are controlled by a ReplicaSet named after the
+// Deployment and the pod template hash, so the Deployment is derived from the
+// ReplicaSet name without looking up the ReplicaSet.
+func podOwner(pod *corev1.Pod) (kind, name string) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "", ""
+	}
+
+	if ref.Kind == "ReplicaSet" {
+		hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		if deployment, ok := strings.CutSuffix(ref.Name, "-"+hash); ok && hash != "" {
+			return "Deployment", deployment
+		}
+	}
+	return ref.Kind, ref.Name
+}
@@ -14,9 +14,11 @@ import (
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap/zapcore"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -170,6 +172,10 @@ func TestPodInfo(t *testing.T) {
 				Name:      "pod-name",
 				UID:       "pod-uuid",
 				Namespace: "pod-namespace",
+				Labels:    map[string]string{"app": "db"},
+				OwnerReferences: []v1.OwnerReference{
+					{Kind: "StatefulSet", Name: "db", Controller: ptr.To(true)},
+				},
 			},
 		}
 		mockCache.On(
@@ -189,6 +195,9 @@ func TestPodInfo(t *testing.T) {
 		assert.Equal(t, pod1.Name, containerInfo.PodName, "unexpected pod name")
 		assert.Equal(t, pod1.Namespace, containerInfo.Namespace, "unexpected pod namespace")
 		assert.Equal(t, "", containerInfo.ContainerName, "expected empty container name")
+		assert.Equal(t, map[string]string{"app": "db"}, containerInfo.PodLabels)
+		assert.Equal(t, "StatefulSet", containerInfo.OwnerKind)
+		assert.Equal(t, "db", containerInfo.OwnerName)
 	})
 	t.Run("more than one pod found", func(t *testing.T) {
 		pi := NewInformer()
@@ -413,6 +422,82 @@ func TestFindContainerName(t *testing.T) {
 	})
 }
 
+func TestPodCPUResources(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				},
+			}, {
+				Name: "sidecar",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+			}, {
+				Name: "best-effort",
+			}},
+		},
+	}
+
+	request, limit := podCPUResources(pod)
+	assert.InDelta(t, 0.6, request, 0.0001)
+	assert.InDelta(t, 2.0, limit, 0.0001)
+
+	request, limit = podCPUResources(&corev1.Pod{})
+	assert.Zero(t, request)
+	assert.Zero(t, limit)
+}
+
+func TestPodOwner(t *testing.T) {
+	controller := func(kind, name string) []v1.OwnerReference {
+		return []v1.OwnerReference{{Kind: kind, Name: name, Controller: ptr.To(true)}}
+	}
+
+	tt := []struct {
+		name         string
+		pod          v1.ObjectMeta
+		kind, expect string
+	}{{
+		name: "bare pod",
+		pod:  v1.ObjectMeta{Name: "pod"},
+	}, {
+		name: "deployment",
+		pod: v1.ObjectMeta{
+			Labels:          map[string]string{"pod-template-hash": "5d8f7c9b4"},
+			OwnerReferences: controller("ReplicaSet", "web-5d8f7c9b4"),
+		},
+		kind: "Deployment", expect: "web",
+	}, {
+		name: "replicaset without template hash",
+		pod: v1.ObjectMeta{
+			OwnerReferences: controller("ReplicaSet", "web-5d8f7c9b4"),
+		},
+		kind: "ReplicaSet", expect: "web-5d8f7c9b4",
+	}, {
+		name: "statefulset",
+		pod: v1.ObjectMeta{
+			OwnerReferences: controller("StatefulSet", "db"),
+		},
+		kind: "StatefulSet", expect: "db",
+	}, {
+		name: "non-controller owner",
+		pod: v1.ObjectMeta{
+			OwnerReferences: []v1.OwnerReference{{Kind: "ConfigMap", Name: "cfg"}},
+		},
+	}}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			kind, name := podOwner(&corev1.Pod{ObjectMeta: tc.pod})
+			assert.Equal(t, tc.kind, kind)
+			assert.Equal(t, tc.expect, name)
+		})
+	}
+}
+
 func TestSlogLevelToZapLevel(t *testing.T) {
 	tests := []struct {
 		input    slog.Level
@@ -11,18 +11,41 @@ import (
 	"strings"
 )
 
-var logLevel slog.Level
+// logLevel is the level of all loggers, which can be changed at runtime
+var logLevel slog.LevelVar
 
+// New creates a logger of format writing to w at level; the warnings and
+// errors it logs are kept, see Recent
 func New(level, format string, w io.Writer) *slog.Logger {
-	logLevel = parseLogLevel(level)
-	return slog.New(handlerForFormat(format, logLevel, w))
+	logLevel.Set(parseLogLevel(level))
+	return slog.New(&recentHandler{Handler: handlerForFormat(format, &logLevel, w)})
 }
 
 func LogLevel() slog.Level {
-	return logLevel
+	return logLevel.Level()
 }
 
-func handlerForFormat(format string, logLevel slog.Level, w io.Writer) slog.Handler {
+// SetLogLevel sets the level of all loggers to one of debug, info, warn or
+// error
+func SetLogLevel(level string) error {
+	if err := ValidateLogLevel(level); err != nil {
+		return err
+	}
+	logLevel.Set(parseLogLevel(level))
+	return nil
+}
+
+// ValidateLogLevel returns an error if level can't be set by SetLogLevel
+func ValidateLogLevel(level string) error {
+	switch level {
+	case "debug", "info", "warn", "error":
+		return nil
+	default:
+		return fmt.Errorf("invalid log level: %q; must be one of debug, info, warn, error", level)
+	}
+}
+
+func handlerForFormat(format string, logLevel slog.Leveler, w io.Writer) slog.Handler {
 	switch format {
 	case "json":
 		return slog.NewJSONHandler(w, &slog.HandlerOptions{
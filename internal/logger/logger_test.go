@@ -167,3 +167,22 @@ func TestParseLogLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLogLevel(t *testing.T) {
+	var out bytes.Buffer
+	logger := New("info", "text", &out)
+	logger.Debug("before")
+
+	assert.NoError(t, SetLogLevel("debug"))
+	assert.Equal(t, slog.LevelDebug, LogLevel())
+	logger.Debug("after")
+	assert.NotContains(t, out.String(), "before")
+	assert.Contains(t, out.String(), "after", "loggers created before follow the level")
+
+	assert.ErrorContains(t, SetLogLevel("verbose"), `invalid log level: "verbose"`)
+	assert.Equal(t, slog.LevelDebug, LogLevel())
+
+	assert.NoError(t, ValidateLogLevel("warn"))
+	assert.Error(t, ValidateLogLevel("verbose"))
+	assert.Equal(t, slog.LevelDebug, LogLevel(), "validating doesn't set the level")
+}
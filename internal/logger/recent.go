@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxRecent is the number of warnings and errors kept
+const maxRecent = 20
+
+// Record is a warning or error logged
+type Record struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
+// recent keeps the last warnings and errors logged by all loggers, to find out
+// why a running Kepler misbehaves without access to its logs
+var recent struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// Recent returns the last warnings and errors logged, oldest first
+func Recent() []Record {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	return slices.Clone(recent.records)
+}
+
+func addRecent(r Record) {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	if len(recent.records) == maxRecent {
+		recent.records = slices.Delete(recent.records, 0, 1)
+	}
+	recent.records = append(recent.records, r)
+}
+
+// recentHandler keeps the warnings and errors handled by its handler in recent
+type recentHandler struct {
+	slog.Handler
+	attrs  []slog.Attr // of the logger, with their keys prefixed by their group
+	prefix string      // of the keys of attributes in the current group
+}
+
+func (h *recentHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		attrs := make(map[string]string, len(h.attrs)+r.NumAttrs())
+		for _, a := range h.attrs {
+			attrs[a.Key] = a.Value.String()
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[h.prefix+a.Key] = a.Value.Resolve().String()
+			return true
+		})
+		addRecent(Record{Time: r.Time, Level: r.Level.String(), Message: r.Message, Attrs: attrs})
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *recentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefixed := slices.Clone(h.attrs)
+	for _, a := range attrs {
+		prefixed = append(prefixed, slog.Attr{Key: h.prefix + a.Key, Value: a.Value.Resolve()})
+	}
+	return &recentHandler{Handler: h.Handler.WithAttrs(attrs), attrs: prefixed, prefix: h.prefix}
+}
+
+func (h *recentHandler) WithGroup(name string) slog.Handler {
+	return &recentHandler{Handler: h.Handler.WithGroup(name), attrs: h.attrs, prefix: h.prefix + name + "."}
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecent(t *testing.T) {
+	recent.records = nil
+	var out bytes.Buffer
+	logger := New("info", "json", &out).With("service", "monitor")
+
+	logger.Info("collected")
+	logger.Warn("zone unavailable", "zone", "dram")
+	logger.WithGroup("bmc").Error("request failed", "status", 503)
+
+	records := Recent()
+	require.Len(t, records, 2, "only warnings and errors are kept")
+	assert.Equal(t, "WARN", records[0].Level)
+	assert.Equal(t, "zone unavailable", records[0].Message)
+	assert.Equal(t, map[string]string{"service": "monitor", "zone": "dram"}, records[0].Attrs)
+	assert.Equal(t, "ERROR", records[1].Level)
+	assert.Equal(t, map[string]string{"service": "monitor", "bmc.status": "503"}, records[1].Attrs)
+	assert.Contains(t, out.String(), "request failed", "records are still logged")
+
+	for i := range maxRecent {
+		logger.Warn(fmt.Sprintf("warning %d", i))
+	}
+	records = Recent()
+	require.Len(t, records, maxRecent)
+	assert.Equal(t, "warning 0", records[0].Message, "the oldest records are dropped")
+	assert.Equal(t, fmt.Sprintf("warning %d", maxRecent-1), records[maxRecent-1].Message)
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package measure runs a command and measures the energy consumed by it and its
+// descendants until it exits, for the measure command.
+package measure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+type (
+	Runner    = service.Runner
+	Dependent = service.Dependent
+	Monitor   = monitor.Service
+)
+
+// GroupAdder groups processes so that their power is attributed as a whole
+type GroupAdder interface {
+	AddGroup(spec resource.GroupSpec) error
+}
+
+// GroupName is the name of the group of the processes of the command
+const GroupName = "measure"
+
+// Summary is the energy consumed by a command and its descendants
+type Summary struct {
+	Command  string
+	PID      int
+	ExitCode int
+
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+
+	Processes  int     // number of processes of the tree that were measured
+	CPUSeconds float64 // CPU time of the processes
+
+	Joules float64            // of the zone that consumed the most
+	Watts  float64            // average power of the zone that consumed the most
+	Zones  map[string]float64 // joules keyed by zone name
+}
+
+type Opts struct {
+	logger         *slog.Logger
+	clock          clock.WithTicker
+	sampleInterval time.Duration
+	maxSamples     int
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:         slog.Default(),
+		clock:          clock.RealClock{},
+		sampleInterval: time.Second,
+		maxSamples:     5,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Measurer
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to sample the processes
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithSampleInterval sets the interval between samples of the processes; it
+// should be the interval of the monitor so that no terminated process is missed
+func WithSampleInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.sampleInterval = d
+	}
+}
+
+// Measurer runs a command and measures the energy consumed by its process tree
+// until it exits. The tree is tracked as a group of processes, which holds the
+// processes whose parent is in it when they are first seen, so processes
+// reparented before then, e.g. daemons, are not measured.
+type Measurer struct {
+	logger         *slog.Logger
+	monitor        Monitor
+	groups         GroupAdder
+	clock          clock.WithTicker
+	sampleInterval time.Duration
+	maxSamples     int
+	cmd            *exec.Cmd
+
+	// only accessed by Run until it returns
+	group   *monitor.Group // last seen
+	summary Summary
+}
+
+var (
+	_ Runner    = (*Measurer)(nil)
+	_ Dependent = (*Measurer)(nil)
+)
+
+// NewMeasurer creates a new Measurer that runs cmd, groups its processes with
+// groups and measures their power using pm
+func NewMeasurer(pm Monitor, groups GroupAdder, cmd *exec.Cmd, applyOpts ...OptionFn) *Measurer {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Measurer{
+		logger:         opts.logger.With("service", "measure"),
+		monitor:        pm,
+		groups:         groups,
+		clock:          opts.clock,
+		sampleInterval: opts.sampleInterval,
+		maxSamples:     opts.maxSamples,
+		cmd:            cmd,
+	}
+}
+
+func (m *Measurer) Name() string {
+	return "measure"
+}
+
+// Dependencies returns the monitor the processes are read from
+func (m *Measurer) Dependencies() []service.Service {
+	return []service.Service{m.monitor}
+}
+
+// Run starts the command once the monitor is ready and samples its processes
+// until it exits, then returns so that Kepler shuts down. The command is killed
+// if ctx is cancelled first.
+func (m *Measurer) Run(ctx context.Context) error {
+	m.summary = Summary{Command: strings.Join(m.cmd.Args, " "), Start: m.clock.Now()}
+	if err := m.cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", m.cmd.Path, err)
+	}
+	m.summary.PID = m.cmd.Process.Pid
+	if err := m.groups.AddGroup(resource.GroupSpec{Name: GroupName, PID: m.summary.PID}); err != nil {
+		_ = m.cmd.Process.Kill()
+		_ = m.cmd.Wait()
+		return fmt.Errorf("failed to group the processes of %s: %w", m.cmd.Path, err)
+	}
+	m.logger.Info("Measuring command", "command", m.summary.Command, "pid", m.summary.PID)
+
+	exited := make(chan error, 1)
+	go func() { exited <- m.cmd.Wait() }()
+
+	ticker := m.clock.NewTicker(m.sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = m.cmd.Process.Kill()
+			<-exited
+			return nil
+
+		case <-ticker.C():
+			m.sample()
+
+		case err := <-exited:
+			var exitErr *exec.ExitError
+			if err != nil && !errors.As(err, &exitErr) {
+				return fmt.Errorf("failed to wait for %s: %w", m.cmd.Path, err)
+			}
+			m.summary.End = m.clock.Now()
+			m.summary.ExitCode = m.cmd.ProcessState.ExitCode()
+			m.sampleFinal(ctx, ticker)
+			return nil
+		}
+	}
+}
+
+// sampleFinal samples the group until it terminates, which it does once all
+// its processes exit, with the final energy of its processes
+func (m *Measurer) sampleFinal(ctx context.Context, ticker clock.Ticker) {
+	for range m.maxSamples {
+		if m.sample() {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+	m.logger.Warn("The processes of the command were not measured since it exited; the summary may be incomplete")
+}
+
+// sample records the group of the command in the current snapshot and returns
+// true if it terminated
+func (m *Measurer) sample() bool {
+	snapshot, err := m.monitor.Snapshot()
+	if err != nil {
+		m.logger.Warn("Failed to get snapshot", "error", err)
+		return false
+	}
+	if g, ok := snapshot.TerminatedGroups[GroupName]; ok {
+		m.group = g
+		return true
+	}
+	if g, ok := snapshot.Groups[GroupName]; ok {
+		m.group = g
+	}
+	return false
+}
+
+// Summary returns the energy consumed by the command; it is complete once Run
+// returns
+func (m *Measurer) Summary() Summary {
+	s := m.summary
+	s.Duration = s.End.Sub(s.Start)
+	summarize(&s, m.group)
+	return s
+}
+
+// summarize sets the energy and CPU time of s to those of g, if seen; zones of
+// the same name are summed
+func summarize(s *Summary, g *monitor.Group) {
+	s.Zones = map[string]float64{}
+	if g == nil {
+		return
+	}
+	s.Processes = g.Processes
+	s.CPUSeconds = g.CPUTotalTime
+	for zone, usage := range g.Zones {
+		s.Zones[zone.Name()] += usage.EnergyTotal.Joules()
+	}
+
+	// zones overlap on some platforms, so the zone that consumed the most is
+	// summarized
+	for _, joules := range s.Zones {
+		s.Joules = max(s.Joules, joules)
+	}
+	if secs := s.Duration.Seconds(); secs > 0 {
+		s.Watts = s.Joules / secs
+	}
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package measure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// fakeMonitor reports the group added last as running until its process exits
+// and is reaped, and as terminated from then on
+type fakeMonitor struct {
+	mu    sync.Mutex
+	spec  resource.GroupSpec
+	calls int
+	err   error // returned by AddGroup
+}
+
+func (m *fakeMonitor) Name() string                 { return "monitor" }
+func (m *fakeMonitor) DataChannel() <-chan struct{} { return nil }
+func (m *fakeMonitor) ZoneNames() []string          { return nil }
+
+func (m *fakeMonitor) AddGroup(spec resource.GroupSpec) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spec = spec
+	return m.err
+}
+
+func (m *fakeMonitor) Snapshot() (*monitor.Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	s := monitor.NewSnapshot()
+	s.Timestamp = time.Now()
+	if m.spec.Name == "" {
+		return s, nil
+	}
+
+	g := group(m.calls, float64(m.calls))
+	if alive(m.spec.PID) {
+		s.Groups[m.spec.Name] = g
+	} else {
+		s.TerminatedGroups[m.spec.Name] = g
+	}
+	return s, nil
+}
+
+// alive returns true if the process of pid is running
+func alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	return err == nil && p.Signal(syscall.Signal(0)) == nil
+}
+
+func (m *fakeMonitor) LatestSnapshot() (*monitor.Snapshot, error) {
+	return m.Snapshot()
+}
+
+var (
+	pkg  = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	pkg1 = device.NewMockRaplZone("package", 1, "/sys/class/powercap/intel-rapl/intel-rapl:1", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:0", 1000*device.Joule)
+)
+
+// group returns a group of processes with its energy in joules in the package
+// zone, and a tenth of it in the dram zone
+func group(processes int, joules float64) *monitor.Group {
+	return &monitor.Group{
+		Name:         GroupName,
+		Processes:    processes,
+		CPUTotalTime: joules / 100,
+		Zones: monitor.ZoneUsageMap{
+			pkg:  {EnergyTotal: monitor.Energy(joules) * device.Joule},
+			dram: {EnergyTotal: monitor.Energy(joules) * device.Joule / 10},
+		},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summary{Duration: 2 * time.Second}
+	summarize(&s, group(4, 62))
+	assert.Equal(t, 4, s.Processes)
+	assert.InDelta(t, 62.0, s.Joules, 1e-9)
+	assert.InDelta(t, 31.0, s.Watts, 1e-9)
+	assert.InDelta(t, 0.62, s.CPUSeconds, 1e-9)
+	assert.InDeltaMapValues(t, map[string]float64{"package": 62, "dram": 6.2}, s.Zones, 1e-9)
+
+	t.Run("zones of the same name", func(t *testing.T) {
+		var s Summary
+		summarize(&s, &monitor.Group{Zones: monitor.ZoneUsageMap{
+			pkg:  {EnergyTotal: 30 * device.Joule},
+			pkg1: {EnergyTotal: 20 * device.Joule},
+		}})
+		assert.Equal(t, map[string]float64{"package": 50}, s.Zones)
+		assert.Equal(t, 50.0, s.Joules)
+		assert.Zero(t, s.Watts, "no average power without a duration")
+	})
+
+	t.Run("not seen", func(t *testing.T) {
+		s := Summary{Duration: time.Second}
+		summarize(&s, nil)
+		assert.Empty(t, s.Zones)
+		assert.Zero(t, s.Processes)
+		assert.Zero(t, s.Joules)
+	})
+}
+
+func TestMeasurerRun(t *testing.T) {
+	pm := &fakeMonitor{}
+	m := NewMeasurer(pm, pm, exec.Command("sh", "-c", "sleep 0.1; exit 3"), WithSampleInterval(10*time.Millisecond))
+	assert.Equal(t, "measure", m.Name())
+	require.Len(t, m.Dependencies(), 1)
+	assert.Equal(t, pm, m.Dependencies()[0])
+
+	require.NoError(t, m.Run(context.Background()))
+	s := m.Summary()
+	assert.Equal(t, "sh -c sleep 0.1; exit 3", s.Command)
+	assert.NotZero(t, s.PID)
+	assert.Equal(t, 3, s.ExitCode)
+	assert.GreaterOrEqual(t, s.Duration, 100*time.Millisecond)
+	assert.Equal(t, resource.GroupSpec{Name: GroupName, PID: s.PID}, pm.spec)
+	assert.Greater(t, pm.calls, 1, "sampled while running and once the command exited")
+	assert.Equal(t, pm.calls, s.Processes, "final energy of the terminated group")
+	assert.InDelta(t, float64(pm.calls), s.Zones["package"], 1e-9)
+
+	var out bytes.Buffer
+	WriteSummary(&out, s)
+	assert.Contains(t, out.String(), "Exit code: 3")
+	assert.Contains(t, out.String(), "ZONE")
+}
+
+func TestMeasurerRunErrors(t *testing.T) {
+	pm := &fakeMonitor{}
+	m := NewMeasurer(pm, pm, exec.Command("/nonexistent"))
+	assert.ErrorContains(t, m.Run(context.Background()), "failed to start /nonexistent")
+
+	pm = &fakeMonitor{err: errors.New("invalid group")}
+	m = NewMeasurer(pm, pm, exec.Command("sleep", "10"))
+	assert.ErrorContains(t, m.Run(context.Background()), "failed to group the processes of")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pm = &fakeMonitor{}
+	m = NewMeasurer(pm, pm, exec.Command("sleep", "10"), WithSampleInterval(time.Hour))
+	start := time.Now()
+	require.NoError(t, m.Run(ctx))
+	assert.Less(t, time.Since(start), 5*time.Second, "the command is killed")
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package measure
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/exporter/stdout"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+// WriteSummary writes the details of s and the energy and average power of the
+// command in each zone
+func WriteSummary(out io.Writer, s Summary) {
+	fields := [][2]string{
+		{"Command", s.Command},
+		{"PID", fmt.Sprint(s.PID)},
+		{"Exit code", fmt.Sprint(s.ExitCode)},
+		{"Duration", s.Duration.Round(time.Millisecond).String()},
+		{"Processes", fmt.Sprint(s.Processes)},
+		{"CPU time", fmt.Sprintf("%.2fs", s.CPUSeconds)},
+		{"Energy", joules(s.Joules)},
+		{"Power", watts(s.Watts) + " (average)"},
+	}
+	for _, f := range fields {
+		_, _ = fmt.Fprintf(out, "%-10s %s\n", f[0]+":", f[1])
+	}
+
+	secs := s.Duration.Seconds()
+	rows := [][]string{}
+	for _, name := range slices.Sorted(maps.Keys(s.Zones)) {
+		j := s.Zones[name]
+		var w float64
+		if secs > 0 {
+			w = j / secs
+		}
+		rows = append(rows, []string{name, watts(w), joules(j)})
+	}
+	stdout.WriteTable(out, []string{"Zone", "Power(W)", "Absolute(J)"}, rows)
+}
+
+// watts and joules format power and energy as the stdout exporter does
+func watts(w float64) string {
+	return (monitor.Power(w) * monitor.Watt).String()
+}
+
+func joules(j float64) string {
+	return (monitor.Energy(j) * monitor.Joule).String()
+}
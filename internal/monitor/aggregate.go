@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"maps"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// firstAggregateRead initializes aggregate power data for the first time
+func (pm *PowerMonitor) firstAggregateRead(snapshot *Snapshot) error {
+	if !pm.aggregates {
+		return nil
+	}
+
+	node := pm.resources.Node()
+	aggregates := make(Aggregates, len(node.Aggregates))
+
+	zones := snapshot.Node.Zones
+	for name, a := range node.Aggregates {
+		agg := newAggregate(a, zones)
+
+		// Calculate initial energy as the share of node active energy
+		pm.attributeZones(agg.Zones, zones, Workload{Kind: AggregateWorkload, ID: name, CPUTimeDelta: a.CPUTimeDelta}, node.ProcessTotalCPUTimeDelta, nil)
+
+		aggregates[name] = agg
+	}
+	snapshot.Aggregates = aggregates
+
+	pm.logger.Debug("Initialized aggregate power tracking",
+		"aggregates", len(aggregates))
+	return nil
+}
+
+// calculateAggregatePower calculates power for the kernel and system
+// aggregates; aggregates never terminate
+func (pm *PowerMonitor) calculateAggregatePower(prev, newSnapshot *Snapshot) error {
+	if !pm.aggregates {
+		return nil
+	}
+
+	node := pm.resources.Node()
+	pm.logger.Debug("Calculating aggregate power",
+		"node.cpu.time", node.ProcessTotalCPUTimeDelta,
+		"aggregates", len(node.Aggregates),
+	)
+
+	aggregates := make(Aggregates, len(node.Aggregates))
+
+	zones := newSnapshot.Node.Zones
+	for name, a := range node.Aggregates {
+		var prevZones ZoneUsageMap
+		prevAgg, exists := prev.Aggregates[name]
+		if exists {
+			prevZones = prevAgg.Zones
+		}
+		usage := pm.usageBuffer(zones)
+		pm.attributeZones(usage, zones, Workload{Kind: AggregateWorkload, ID: name, CPUTimeDelta: a.CPUTimeDelta}, node.ProcessTotalCPUTimeDelta, prevZones)
+
+		// share unchanged aggregates with the previous snapshot (copy-on-write)
+		if exists && prevAgg.CPUTotalTime == a.CPUTotalTime && sameUsage(prevAgg.Zones, usage) {
+			aggregates[name] = prevAgg
+			continue
+		}
+
+		agg := newAggregate(a, zones)
+		maps.Copy(agg.Zones, usage)
+		aggregates[name] = agg
+	}
+
+	newSnapshot.Aggregates = aggregates
+	return nil
+}
+
+// newAggregate creates a new Aggregate with zones initialized from resource.Aggregate
+func newAggregate(a *resource.Aggregate, zones NodeZoneUsageMap) *Aggregate {
+	agg := &Aggregate{
+		Name:         a.Name,
+		CPUTotalTime: a.CPUTotalTime,
+		Zones:        make(ZoneUsageMap, len(zones)),
+	}
+
+	for zone := range zones {
+		agg.Zones[zone] = Usage{
+			EnergyTotal: Energy(0),
+			Power:       Power(0),
+		}
+	}
+
+	return agg
+}
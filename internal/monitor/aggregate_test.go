@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestAggregatePowerCalculation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	zones := CreateTestZones()
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(zones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(zones[0], nil)
+
+	resInformer := &MockResourceInformer{}
+	monitor := &PowerMonitor{
+		logger:        logger,
+		cpu:           mockMeter,
+		clock:         fakeClock,
+		resources:     resInformer,
+		maxTerminated: 500,
+		aggregates:    true,
+	}
+	require.NoError(t, monitor.Init())
+
+	node := &resource.Node{
+		CPUUsageRatio:            0.5,
+		ProcessTotalCPUTimeDelta: 100,
+		Aggregates: map[string]*resource.Aggregate{
+			resource.KernelAggregate: {Name: resource.KernelAggregate, CPUTotalTime: 10, CPUTimeDelta: 10},
+			resource.SystemAggregate: {Name: resource.SystemAggregate, CPUTotalTime: 20, CPUTimeDelta: 20},
+		},
+	}
+	resInformer.On("Node").Return(node)
+
+	t.Run("firstAggregateRead", func(t *testing.T) {
+		snapshot := NewSnapshot()
+		snapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		require.NoError(t, monitor.firstAggregateRead(snapshot))
+
+		require.Len(t, snapshot.Aggregates, 2)
+		system := snapshot.Aggregates[resource.SystemAggregate]
+		assert.Equal(t, 20.0, system.CPUTotalTime)
+		for _, zone := range zones {
+			expected := Energy(0.2 * float64(snapshot.Node.Zones[zone].activeEnergy))
+			assert.Equal(t, expected, system.Zones[zone].EnergyTotal)
+		}
+	})
+
+	t.Run("calculateAggregatePower", func(t *testing.T) {
+		prevSnapshot := NewSnapshot()
+		prevSnapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		prevSnapshot.Aggregates[resource.KernelAggregate] = &Aggregate{
+			Name:  resource.KernelAggregate,
+			Zones: make(ZoneUsageMap, len(zones)),
+		}
+		for _, zone := range zones {
+			prevSnapshot.Aggregates[resource.KernelAggregate].Zones[zone] = Usage{EnergyTotal: 25 * Joule}
+		}
+
+		fakeClock.Step(2 * time.Second)
+		newSnapshot := NewSnapshot()
+		newSnapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		require.NoError(t, monitor.calculateAggregatePower(prevSnapshot, newSnapshot))
+
+		require.Len(t, newSnapshot.Aggregates, 2)
+		kernel := newSnapshot.Aggregates[resource.KernelAggregate]
+		for _, zone := range zones {
+			nodeZone := newSnapshot.Node.Zones[zone]
+			assert.Equal(t, 25*Joule+Energy(0.1*float64(nodeZone.activeEnergy)), kernel.Zones[zone].EnergyTotal)
+			assert.Equal(t, Power(0.1*nodeZone.ActivePower.MicroWatts()), kernel.Zones[zone].Power)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := &PowerMonitor{logger: logger, resources: &MockResourceInformer{}}
+
+		prevSnapshot := NewSnapshot()
+		newSnapshot := NewSnapshot()
+		require.NoError(t, disabled.firstAggregateRead(newSnapshot))
+		require.NoError(t, disabled.calculateAggregatePower(prevSnapshot, newSnapshot))
+		assert.Empty(t, newSnapshot.Aggregates)
+	})
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+)
+
+// API returns the snapshot in the versioned wire format of pkg/api. Zones of
+// the same name, e.g. package zones of each socket, are summed; workloads are
+// ordered by kind, running before terminated, and by ID.
+func (s *Snapshot) API() *api.Snapshot {
+	ret := &api.Snapshot{
+		Version:   api.Version,
+		Timestamp: s.Timestamp,
+		Node:      api.Node{Zones: map[string]api.NodeZone{}},
+		Workloads: []api.Workload{},
+	}
+	if s.Node != nil {
+		ret.Node.UsageRatio = s.Node.UsageRatio
+		ret.Node.CarbonIntensity = s.Node.CarbonIntensity
+		ret.Node.Price = s.Node.Price
+		for zone, u := range s.Node.Zones {
+			z := ret.Node.Zones[zone.Name()]
+			z.Joules += u.EnergyTotal.Joules()
+			z.Watts += u.Power.Watts()
+			z.ActiveJoules += u.ActiveEnergyTotal.Joules()
+			z.ActiveWatts += u.ActivePower.Watts()
+			z.IdleJoules += u.IdleEnergyTotal.Joules()
+			z.IdleWatts += u.IdlePower.Watts()
+			z.MinWatts += u.MinPower.Watts()
+			z.MaxWatts += u.MaxPower.Watts()
+			ret.Node.Zones[zone.Name()] = z
+		}
+	}
+
+	add := func(ws []api.Workload) {
+		slices.SortFunc(ws, func(a, b api.Workload) int {
+			return cmp.Or(cmp.Compare(btoi(a.Terminated), btoi(b.Terminated)), cmp.Compare(a.ID, b.ID))
+		})
+		ret.Workloads = append(ret.Workloads, ws...)
+	}
+
+	var ws []api.Workload
+	for running, procs := range map[bool]Processes{true: s.Processes, false: s.TerminatedProcesses} {
+		for _, p := range procs {
+			ws = append(ws, api.Workload{
+				Kind: api.KindProcess, ID: strconv.Itoa(p.PID), Name: p.Comm, Terminated: !running,
+				Container: p.ContainerID, VM: p.VirtualMachineID, CPUSeconds: p.CPUTotalTime, Zones: apiZones(p.Zones),
+			})
+		}
+	}
+	add(ws)
+
+	ws = nil
+	for running, containers := range map[bool]Containers{true: s.Containers, false: s.TerminatedContainers} {
+		for _, c := range containers {
+			ws = append(ws, api.Workload{
+				Kind: api.KindContainer, ID: c.ID, Name: c.Name, Terminated: !running,
+				Pod: c.PodID, CPUSeconds: c.CPUTotalTime, Zones: apiZones(c.Zones),
+			})
+		}
+	}
+	add(ws)
+
+	ws = nil
+	for running, vms := range map[bool]VirtualMachines{true: s.VirtualMachines, false: s.TerminatedVirtualMachines} {
+		for _, vm := range vms {
+			ws = append(ws, api.Workload{
+				Kind: api.KindVM, ID: vm.ID, Name: vm.Name, Terminated: !running,
+				CPUSeconds: vm.CPUTotalTime, Zones: apiZones(vm.Zones),
+			})
+		}
+	}
+	add(ws)
+
+	ws = nil
+	for running, pods := range map[bool]Pods{true: s.Pods, false: s.TerminatedPods} {
+		for _, p := range pods {
+			ws = append(ws, api.Workload{
+				Kind: api.KindPod, ID: p.ID, Name: p.Name, Namespace: p.Namespace, Terminated: !running,
+				CPUSeconds: p.CPUTotalTime, Zones: apiZones(p.Zones),
+			})
+		}
+	}
+	add(ws)
+
+	ws = nil
+	for running, units := range map[bool]SystemdUnits{true: s.SystemdUnits, false: s.TerminatedSystemdUnits} {
+		for _, u := range units {
+			ws = append(ws, api.Workload{
+				Kind: api.KindSystemdUnit, ID: u.Name, Name: u.Name, Terminated: !running,
+				CPUSeconds: u.CPUTotalTime, Zones: apiZones(u.Zones),
+			})
+		}
+	}
+	add(ws)
+
+	ws = nil
+	for running, groups := range map[bool]Groups{true: s.Groups, false: s.TerminatedGroups} {
+		for _, g := range groups {
+			ws = append(ws, api.Workload{
+				Kind: api.KindGroup, ID: g.Name, Name: g.Name, Terminated: !running,
+				CPUSeconds: g.CPUTotalTime, Zones: apiZones(g.Zones),
+			})
+		}
+	}
+	add(ws)
+
+	ws = nil
+	for _, a := range s.Aggregates {
+		ws = append(ws, api.Workload{
+			Kind: api.KindAggregate, ID: a.Name, Name: a.Name,
+			CPUSeconds: a.CPUTotalTime, Zones: apiZones(a.Zones),
+		})
+	}
+	add(ws)
+
+	return ret
+}
+
+// apiZones returns the energy and power of usage keyed by zone name, summing
+// zones of the same name
+func apiZones(usage ZoneUsageMap) map[string]api.Zone {
+	ret := make(map[string]api.Zone, len(usage))
+	for zone, u := range usage {
+		z := ret[zone.Name()]
+		z.Joules += u.EnergyTotal.Joules()
+		z.Watts += u.Power.Watts()
+		z.ActiveWatts += u.ActivePower.Watts()
+		z.IdleWatts += u.IdlePower.Watts()
+		ret[zone.Name()] = z
+	}
+	return ret
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+)
+
+func TestSnapshotAPI(t *testing.T) {
+	pkg0 := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+	pkg1 := device.NewMockRaplZone("package", 1, "", 1000*Joule)
+
+	s := NewSnapshot()
+	s.Timestamp = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.Node = &Node{UsageRatio: 0.5, Price: 0.3, Zones: NodeZoneUsageMap{
+		pkg0: {EnergyTotal: 600 * Joule, Power: 60 * Watt, ActivePower: 40 * Watt, IdlePower: 20 * Watt, MaxPower: 70 * Watt},
+		pkg1: {EnergyTotal: 400 * Joule, Power: 40 * Watt, ActiveEnergyTotal: 100 * Joule, MaxPower: 50 * Watt},
+	}}
+	s.Processes["42"] = &Process{PID: 42, Comm: "nginx", ContainerID: "c-1", CPUTotalTime: 3,
+		Zones: ZoneUsageMap{pkg0: {EnergyTotal: 30 * Joule, Power: 3 * Watt, ActivePower: 2 * Watt, IdlePower: Watt}}}
+	s.TerminatedProcesses["7"] = &Process{PID: 7, Comm: "cron", Zones: ZoneUsageMap{}}
+	s.Processes["100"] = &Process{PID: 100, Comm: "sshd", Zones: ZoneUsageMap{}}
+	s.Containers["c-1"] = &Container{ID: "c-1", Name: "web", PodID: "pod-1",
+		Zones: ZoneUsageMap{pkg0: {EnergyTotal: 30 * Joule}, pkg1: {EnergyTotal: 20 * Joule}}}
+	s.TerminatedPods["pod-2"] = &Pod{ID: "pod-2", Name: "job", Namespace: "batch", Zones: ZoneUsageMap{}}
+	s.Aggregates["kernel"] = &Aggregate{Name: "kernel", CPUTotalTime: 1, Zones: ZoneUsageMap{}}
+
+	got := s.API()
+	assert.Equal(t, api.Version, got.Version)
+	assert.Equal(t, s.Timestamp, got.Timestamp)
+	assert.Equal(t, api.Node{UsageRatio: 0.5, Price: 0.3, Zones: map[string]api.NodeZone{
+		"package": {Joules: 1000, Watts: 100, ActiveJoules: 100, ActiveWatts: 40, IdleWatts: 20, MaxWatts: 120},
+	}}, got.Node)
+
+	require.Len(t, got.Workloads, 6)
+	var ids []string
+	for _, w := range got.Workloads {
+		ids = append(ids, w.Kind+"/"+w.ID)
+	}
+	assert.Equal(t, []string{"process/100", "process/42", "process/7", "container/c-1", "pod/pod-2", "aggregate/kernel"}, ids)
+
+	assert.Equal(t, api.Workload{
+		Kind: api.KindProcess, ID: "42", Name: "nginx", Container: "c-1", CPUSeconds: 3,
+		Zones: map[string]api.Zone{"package": {Joules: 30, Watts: 3, ActiveWatts: 2, IdleWatts: 1}},
+	}, got.Workloads[1])
+	assert.True(t, got.Workloads[2].Terminated)
+	assert.Equal(t, "pod-1", got.Workloads[3].Pod)
+	assert.Equal(t, map[string]api.Zone{"package": {Joules: 50}}, got.Workloads[3].Zones)
+	assert.Equal(t, "batch", got.Workloads[4].Namespace)
+	assert.True(t, got.Workloads[4].Terminated)
+
+	// the snapshot survives encoding
+	b, err := api.MarshalProto(got)
+	require.NoError(t, err)
+	decoded, err := api.UnmarshalProto(b)
+	require.NoError(t, err)
+	assert.Equal(t, got, decoded)
+}
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"maps"
+	"strconv"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// WorkloadKind identifies the type of workload power is attributed to
+type WorkloadKind int
+
+const (
+	ProcessWorkload WorkloadKind = iota
+	ContainerWorkload
+	VMWorkload
+	PodWorkload
+	SystemdUnitWorkload
+	AggregateWorkload
+	GroupWorkload
+)
+
+// Workload identifies a workload whose share of a zone's energy is attributed
+type Workload struct {
+	Kind         WorkloadKind
+	ID           string
+	CPUTimeDelta float64 // CPU time used by the workload since the last refresh
+}
+
+// Attribution decides the share of a zone's active energy attributable to a workload
+type Attribution interface {
+	// Name returns the name of the attribution strategy
+	Name() string
+
+	// Update is called once on every refresh, before any ratio is requested, so that
+	// shares that depend on all running processes can be computed
+	Update(procs *resource.Processes)
+
+	// Ratio returns the ratio of the zone's active energy attributable to the
+	// workload and false if nothing can be attributed
+	Ratio(zone EnergyZone, w Workload, nodeCPUTimeDelta float64) (float64, bool)
+}
+
+// workloadShares holds the share of a zone's active energy attributed to each
+// workload, keyed by workload kind and then by workload ID
+type workloadShares map[WorkloadKind]map[string]float64
+
+func newWorkloadShares() workloadShares {
+	return workloadShares{
+		ProcessWorkload:     {},
+		ContainerWorkload:   {},
+		VMWorkload:          {},
+		PodWorkload:         {},
+		SystemdUnitWorkload: {},
+		AggregateWorkload:   {},
+		GroupWorkload:       {},
+	}
+}
+
+// add adds share to the process and to the container, pod, VM, systemd unit and
+// group it belongs to; shares of kernel threads are added to the kernel aggregate
+func (ws workloadShares) add(proc *resource.Process, share float64) {
+	ws[ProcessWorkload][strconv.Itoa(proc.PID)] += share
+	if proc.Container != nil {
+		ws[ContainerWorkload][proc.Container.ID] += share
+		if proc.Container.Pod != nil {
+			ws[PodWorkload][proc.Container.Pod.ID] += share
+		}
+	}
+	if proc.VirtualMachine != nil {
+		ws[VMWorkload][proc.VirtualMachine.ID] += share
+	}
+	if proc.SystemdUnit != nil {
+		ws[SystemdUnitWorkload][proc.SystemdUnit.Name] += share
+	}
+	if proc.Group != nil {
+		ws[GroupWorkload][proc.Group.Name] += share
+	}
+	if proc.KernelThread {
+		ws[AggregateWorkload][resource.KernelAggregate] += share
+	}
+}
+
+// runningProcesses returns the running processes including those that aren't
+// reported due to the resource filter, as they count towards the shares of the
+// containers, pods and VMs they belong to
+func runningProcesses(procs *resource.Processes) map[int]*resource.Process {
+	if len(procs.Filtered) == 0 {
+		return procs.Running
+	}
+
+	running := make(map[int]*resource.Process, len(procs.Running)+len(procs.Filtered))
+	maps.Copy(running, procs.Running)
+	maps.Copy(running, procs.Filtered)
+	return running
+}
+
+// ratio returns the share of the workload and false if it has none
+func (ws workloadShares) ratio(w Workload) (float64, bool) {
+	share := ws[w.Kind][w.ID]
+	return share, share > 0
+}
+
+// cpuTimeAttribution attributes energy in proportion to the CPU time of workloads
+type cpuTimeAttribution struct{}
+
+// NewCPUTimeAttribution returns an Attribution that attributes the energy of all
+// zones in proportion to the CPU time of workloads
+func NewCPUTimeAttribution() Attribution {
+	return cpuTimeAttribution{}
+}
+
+func (cpuTimeAttribution) Name() string {
+	return "cpu-time"
+}
+
+func (cpuTimeAttribution) Update(*resource.Processes) {}
+
+func (cpuTimeAttribution) Ratio(_ EnergyZone, w Workload, nodeCPUTimeDelta float64) (float64, bool) {
+	if nodeCPUTimeDelta == 0 {
+		return 0, false
+	}
+	return w.CPUTimeDelta / nodeCPUTimeDelta, true
+}
+
+// updateAttribution updates the attribution strategy with the running processes
+func (pm *PowerMonitor) updateAttribution() {
+	if pm.attribution == nil {
+		return
+	}
+	pm.attribution.Update(pm.resources.Processes())
+}
+
+// attributionRatio returns the ratio of a zone's active energy attributable to a
+// workload and false if nothing can be attributed. GPU zones are attributed by GPU
+// utilization, I/O zones by bytes transferred, the platform zone to VMs by CPU
+// time and per-socket zones by CPU time on that socket (if the CPU topology is
+// known); all other zones are attributed by the configured strategy.
+func (pm *PowerMonitor) attributionRatio(zone EnergyZone, w Workload, nodeCPUTimeDelta float64) (float64, bool) {
+	if _, isGPU := pm.gpuZones[zone]; isGPU {
+		return pm.gpuShares[zone].ratio(w)
+	}
+
+	if _, isIO := pm.ioZones[zone]; isIO {
+		return pm.ioShares[zone].ratio(w)
+	}
+
+	if pm.isPlatform(zone) {
+		return pm.platformShares.ratio(w)
+	}
+
+	if socket, ok := pm.socketOf(zone); ok {
+		return pm.socketShares[socket].ratio(w)
+	}
+
+	if pm.attribution == nil {
+		return cpuTimeAttribution{}.Ratio(zone, w, nodeCPUTimeDelta)
+	}
+	return pm.attribution.Ratio(zone, w, nodeCPUTimeDelta)
+}
+
+// attributeZones sets the usage of a workload in each zone to its share of the
+// zone's active power and energy, and of the idle power and energy as per the idle
+// policy. Energy accumulates over prev, the zone usage of the workload in the
+// previous snapshot (nil for new workloads).
+func (pm *PowerMonitor) attributeZones(usage ZoneUsageMap, zones NodeZoneUsageMap, w Workload, nodeCPUTimeDelta float64, prev ZoneUsageMap) {
+	idleRatio := pm.idleRatio(w)
+
+	for zone, nodeZoneUsage := range zones {
+		// Skip zones with zero power to avoid division by zero
+		hasActive := nodeZoneUsage.ActivePower != 0 && nodeZoneUsage.activeEnergy != 0
+		hasIdle := idleRatio > 0 && nodeZoneUsage.IdlePower != 0 && nodeZoneUsage.idleEnergy != 0
+
+		ratio, ok := 0.0, false
+		if hasActive {
+			ratio, ok = pm.attributionRatio(zone, w, nodeCPUTimeDelta)
+		}
+		if !ok && !hasIdle {
+			// the totals are kept when no energy is attributed
+			if prevUsage, hasZone := prev[zone]; hasZone {
+				usage[zone] = Usage{
+					Uncertainty:    nodeZoneUsage.Uncertainty,
+					EnergyTotal:    prevUsage.EnergyTotal,
+					EmissionsTotal: prevUsage.EmissionsTotal,
+					CostTotal:      prevUsage.CostTotal,
+				}
+			}
+			continue
+		}
+
+		// Calculate energy for this interval and add it to the previous total
+		energy := Energy(ratio * float64(nodeZoneUsage.activeEnergy))
+		activePower := Power(ratio * nodeZoneUsage.ActivePower.MicroWatts())
+		idlePower := Power(0)
+		if hasIdle {
+			energy += Energy(idleRatio * float64(nodeZoneUsage.idleEnergy))
+			idlePower = Power(idleRatio * nodeZoneUsage.IdlePower.MicroWatts())
+		}
+		emissions := pm.carbonIntensity.Emissions(energy.Joules())
+		cost := pm.price.Cost(energy.Joules())
+		if prevUsage, hasZone := prev[zone]; hasZone {
+			energy += prevUsage.EnergyTotal
+			emissions += prevUsage.EmissionsTotal
+			cost += prevUsage.CostTotal
+		}
+
+		usage[zone] = Usage{
+			Power:          activePower + idlePower,
+			ActivePower:    activePower,
+			IdlePower:      idlePower,
+			Uncertainty:    nodeZoneUsage.Uncertainty,
+			EnergyTotal:    energy,
+			EmissionsTotal: emissions,
+			CostTotal:      cost,
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+func TestCPUTimeAttribution(t *testing.T) {
+	a := NewCPUTimeAttribution()
+	assert.Equal(t, "cpu-time", a.Name())
+	a.Update(&resource.Processes{})
+
+	zone := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+
+	ratio, ok := a.Ratio(zone, Workload{Kind: ContainerWorkload, ID: "c1", CPUTimeDelta: 1}, 4)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.25, ratio, 0.0001)
+
+	_, ok = a.Ratio(zone, Workload{Kind: ContainerWorkload, ID: "c1", CPUTimeDelta: 1}, 0)
+	assert.False(t, ok, "nothing to attribute without node cpu time")
+}
+
+func TestAttributeZones(t *testing.T) {
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+	core := device.NewMockRaplZone("core", 0, "", 1000*Joule)
+	idle := device.NewMockRaplZone("dram", 0, "", 1000*Joule)
+
+	zones := NodeZoneUsageMap{
+		pkg:  {ActivePower: 40 * Watt, activeEnergy: 400 * Joule},
+		core: {ActivePower: 20 * Watt, activeEnergy: 200 * Joule},
+		idle: {},
+	}
+	prev := ZoneUsageMap{pkg: {EnergyTotal: 100 * Joule}}
+
+	pm := &PowerMonitor{attribution: NewCPUTimeAttribution()}
+	usage := ZoneUsageMap{}
+	pm.attributeZones(usage, zones, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 1}, 4, prev)
+
+	assert.Equal(t, 10*Watt, usage[pkg].Power)
+	assert.Equal(t, 200*Joule, usage[pkg].EnergyTotal, "energy accumulates over previous")
+	assert.Equal(t, 5*Watt, usage[core].Power)
+	assert.Equal(t, 50*Joule, usage[core].EnergyTotal)
+	assert.NotContains(t, usage, EnergyZone(idle), "zones without active power are skipped")
+}
+
+func TestRunningProcesses(t *testing.T) {
+	reported := &resource.Process{PID: 1}
+	filtered := &resource.Process{PID: 2}
+
+	procs := &resource.Processes{Running: map[int]*resource.Process{1: reported}}
+	assert.Equal(t, procs.Running, runningProcesses(procs))
+
+	procs.Filtered = map[int]*resource.Process{2: filtered}
+	running := runningProcesses(procs)
+	assert.Len(t, running, 2)
+	assert.Same(t, filtered, running[2])
+	assert.Len(t, procs.Running, 1, "reported processes are not modified")
+}
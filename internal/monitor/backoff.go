@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import "time"
+
+// nextInterval returns the interval after which the next collection is scheduled.
+//
+// With backoff enabled (maxInterval > interval), the interval doubles after each
+// collection in which the node is idle, i.e. the power of the primary zone is
+// below idleThreshold, up to maxInterval. It drops back to interval as soon as
+// the node is busy again.
+func (pm *PowerMonitor) nextInterval() time.Duration {
+	interval := pm.Interval()
+	if pm.maxInterval <= interval {
+		return interval
+	}
+
+	current := time.Duration(pm.currentInterval.Load())
+	if !pm.isNodeIdle() {
+		if current > interval {
+			pm.logger.Debug("Node is busy; restoring collection interval", "interval", interval)
+		}
+		pm.currentInterval.Store(int64(interval))
+		return interval
+	}
+
+	next := min(max(2*current, interval), pm.maxInterval)
+	if next != current {
+		pm.logger.Debug("Node is idle; backing off collection interval", "interval", next)
+	}
+	pm.currentInterval.Store(int64(next))
+	return next
+}
+
+// isNodeIdle returns true if the power of the primary zone in the latest
+// snapshot is below the idle threshold
+func (pm *PowerMonitor) isNodeIdle() bool {
+	snapshot := pm.snapshot.Load()
+	if snapshot == nil || snapshot.Node == nil {
+		return false
+	}
+
+	zone, err := pm.cpu.PrimaryEnergyZone()
+	if err != nil {
+		return false
+	}
+
+	usage, ok := snapshot.Node.Zones[zone]
+	if !ok {
+		return false
+	}
+	return usage.Power < pm.idleThreshold
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestNextInterval(t *testing.T) {
+	zones := CreateTestZones()
+	pkg := zones[0]
+
+	newMonitor := func(maxInterval time.Duration) *PowerMonitor {
+		meter := &MockCPUPowerMeter{}
+		meter.On("PrimaryEnergyZone").Return(pkg, nil).Maybe()
+		return &PowerMonitor{
+			logger:        slog.Default(),
+			cpu:           meter,
+			interval:      5 * time.Second,
+			maxInterval:   maxInterval,
+			idleThreshold: 20 * Watt,
+		}
+	}
+
+	setPower := func(pm *PowerMonitor, power Power) {
+		snapshot := NewSnapshot()
+		snapshot.Node.Zones[pkg] = NodeUsage{Power: power}
+		pm.snapshot.Store(snapshot)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		pm := newMonitor(0)
+		setPower(pm, 5*Watt)
+		assert.Equal(t, 5*time.Second, pm.nextInterval())
+		assert.Equal(t, 5*time.Second, pm.nextInterval())
+	})
+
+	t.Run("no snapshot", func(t *testing.T) {
+		pm := newMonitor(time.Minute)
+		assert.Equal(t, 5*time.Second, pm.nextInterval())
+	})
+
+	t.Run("backs off while idle and restores when busy", func(t *testing.T) {
+		pm := newMonitor(time.Minute)
+		setPower(pm, 5*Watt)
+
+		var intervals []time.Duration
+		for range 6 {
+			intervals = append(intervals, pm.nextInterval())
+		}
+		assert.Equal(t, []time.Duration{
+			5 * time.Second, 10 * time.Second, 20 * time.Second,
+			40 * time.Second, time.Minute, time.Minute,
+		}, intervals)
+
+		setPower(pm, 30*Watt)
+		assert.Equal(t, 5*time.Second, pm.nextInterval())
+
+		setPower(pm, 5*Watt)
+		assert.Equal(t, 10*time.Second, pm.nextInterval())
+	})
+}
+
+func TestSetInterval(t *testing.T) {
+	pm := NewPowerMonitor(&MockCPUPowerMeter{}, WithInterval(5*time.Second))
+	assert.Equal(t, 5*time.Second, pm.nextInterval())
+
+	assert.NoError(t, pm.SetInterval(10*time.Second))
+	assert.Equal(t, 10*time.Second, pm.nextInterval())
+
+	assert.ErrorContains(t, pm.SetInterval(0), "can't be changed from 10s to 0s at runtime")
+	assert.Equal(t, 10*time.Second, pm.nextInterval())
+
+	assert.NoError(t, pm.ValidateInterval(time.Second))
+	assert.Error(t, pm.ValidateInterval(0))
+	assert.Equal(t, 10*time.Second, pm.Interval(), "validating doesn't change the interval")
+
+	onDemand := NewPowerMonitor(&MockCPUPowerMeter{}, WithInterval(0))
+	assert.Error(t, onDemand.SetInterval(time.Second))
+}
+
+func TestPollingDuringBackoff(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	pkg := &MockEnergyZone{}
+	pkg.On("Name").Return("package")
+	pkg.On("Index").Return(0)
+	pkg.On("Path").Return("")
+	pkg.On("Energy").Return(Energy(100_000), nil)
+	pkg.On("MaxEnergy").Return(Energy(1_000_000))
+	meter := &MockCPUPowerMeter{}
+	meter.On("Zones").Return([]device.EnergyZone{pkg}, nil)
+	meter.On("PrimaryEnergyZone").Return(pkg, nil)
+
+	resourceInformer := &MockResourceInformer{}
+	resourceInformer.SetExpectations(t, CreateTestResources())
+	resourceInformer.On("Refresh").Return(nil)
+
+	// the energy doesn't change, so the node is idle
+	pm := NewPowerMonitor(meter,
+		WithResourceInformer(resourceInformer),
+		WithClock(fakeClock),
+		WithInterval(5*time.Second),
+		WithMaxStaleness(500*time.Millisecond),
+		WithIntervalBackoff(time.Minute, 20*Watt),
+	)
+	require.NoError(t, pm.Init())
+	require.NoError(t, pm.refreshSnapshot())
+	assert.Equal(t, 500*time.Millisecond, pm.staleness())
+
+	// the collection loop backs off to 40s
+	for range 4 {
+		pm.nextInterval()
+	}
+	assert.Equal(t, 40*time.Second, pm.staleness())
+	collected := pm.snapshot.Load().Timestamp
+
+	// a consumer polling every 5s doesn't collect while backing off
+	for range 7 {
+		fakeClock.Step(5 * time.Second)
+		snapshot, err := pm.Snapshot()
+		require.NoError(t, err)
+		assert.Equal(t, collected, snapshot.Timestamp)
+	}
+
+	// but does once the data is older than the current interval
+	fakeClock.Step(10 * time.Second)
+	snapshot, err := pm.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, fakeClock.Now(), snapshot.Timestamp)
+
+	// the staleness is restored with the interval when the node is busy
+	busy := NewSnapshot()
+	busy.Node.Zones[pkg] = NodeUsage{Power: 50 * Watt}
+	pm.snapshot.Store(busy)
+	assert.Equal(t, 5*time.Second, pm.nextInterval())
+	assert.Equal(t, 500*time.Millisecond, pm.staleness())
+}
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/event"
+)
+
+// Budget scopes
+const (
+	BudgetScopeNode      = "node"
+	BudgetScopeNamespace = "namespace"
+)
+
+// Budgets limits the energy consumed per day (UTC) by the node and by the pods
+// of namespaces; zero budgets are not tracked
+type Budgets struct {
+	Node       Energy
+	Namespaces map[string]Energy
+}
+
+func (b Budgets) isEmpty() bool {
+	if b.Node > 0 {
+		return false
+	}
+	for _, budget := range b.Namespaces {
+		if budget > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BudgetStatus is the energy consumed against a budget in the current day
+type BudgetStatus struct {
+	Scope    string // BudgetScopeNode or BudgetScopeNamespace
+	Name     string // namespace; empty for the node
+	Budget   Energy
+	Consumed Energy
+}
+
+// Remaining returns the energy left in the budget for the current day
+func (s BudgetStatus) Remaining() Energy {
+	if s.Consumed >= s.Budget {
+		return 0
+	}
+	return s.Budget - s.Consumed
+}
+
+// Exceeded returns true if more energy than the budget has been consumed
+func (s BudgetStatus) Exceeded() bool {
+	return s.Consumed > s.Budget
+}
+
+// BudgetNotifier is notified when a budget is exceeded; it is notified at most
+// once per budget and day, and is called during collection so it must not block
+type BudgetNotifier interface {
+	BudgetExceeded(status BudgetStatus)
+}
+
+// budgetTracker accumulates the energy consumed per day against budgets. It is
+// only accessed during collection, which is serialized
+type budgetTracker struct {
+	budgets Budgets
+
+	day        time.Time         // start of the current day
+	node       Energy            // energy consumed by the node in the current day
+	namespaces map[string]Energy // energy consumed by namespaces in the current day
+	exceeded   map[BudgetStatus]bool
+
+	// cumulative energy seen in the previous collection, used to compute the
+	// energy consumed in each interval
+	prevNode    Energy
+	hasPrevNode bool
+	prevPods    map[string]Energy
+}
+
+func newBudgetTracker(budgets Budgets) *budgetTracker {
+	return &budgetTracker{
+		budgets:    budgets,
+		namespaces: make(map[string]Energy),
+		exceeded:   make(map[BudgetStatus]bool),
+		prevPods:   make(map[string]Energy),
+	}
+}
+
+// resetIfNewDay starts accumulating consumption afresh at the start of each day
+func (t *budgetTracker) resetIfNewDay(now time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if day.Equal(t.day) {
+		return
+	}
+	t.day = day
+	t.node = 0
+	clear(t.namespaces)
+	clear(t.exceeded)
+}
+
+// addNode accumulates the energy consumed by the node since the last collection
+// given the cumulative (active and idle) energy of the node
+func (t *budgetTracker) addNode(total Energy) {
+	if t.hasPrevNode && total >= t.prevNode {
+		t.node += total - t.prevNode
+	}
+	t.prevNode = total
+	t.hasPrevNode = true
+}
+
+// addPods accumulates the energy consumed by pods since the last collection to
+// their namespaces; pods are tracked until they are neither running nor terminated
+func (t *budgetTracker) addPods(zone EnergyZone, pods ...Pods) {
+	seen := make(map[string]bool, len(t.prevPods))
+	for _, p := range pods {
+		for id, pod := range p {
+			total := pod.Zones[zone].EnergyTotal
+			if prev := t.prevPods[id]; total > prev {
+				t.namespaces[pod.Namespace] += total - prev
+			}
+			t.prevPods[id] = total
+			seen[id] = true
+		}
+	}
+
+	for id := range t.prevPods {
+		if !seen[id] {
+			delete(t.prevPods, id)
+		}
+	}
+}
+
+// statuses returns the status of all budgets sorted by scope and name
+func (t *budgetTracker) statuses() []BudgetStatus {
+	ret := make([]BudgetStatus, 0, len(t.budgets.Namespaces)+1)
+	if t.budgets.Node > 0 {
+		ret = append(ret, BudgetStatus{
+			Scope:    BudgetScopeNode,
+			Budget:   t.budgets.Node,
+			Consumed: t.node,
+		})
+	}
+
+	for _, ns := range slices.Sorted(maps.Keys(t.budgets.Namespaces)) {
+		budget := t.budgets.Namespaces[ns]
+		if budget == 0 {
+			continue
+		}
+		ret = append(ret, BudgetStatus{
+			Scope:    BudgetScopeNamespace,
+			Name:     ns,
+			Budget:   budget,
+			Consumed: t.namespaces[ns],
+		})
+	}
+	return ret
+}
+
+// markExceeded returns true if the budget is exceeded for the first time in
+// the current day
+func (t *budgetTracker) markExceeded(s BudgetStatus) bool {
+	if !s.Exceeded() {
+		return false
+	}
+	key := BudgetStatus{Scope: s.Scope, Name: s.Name}
+	if t.exceeded[key] {
+		return false
+	}
+	t.exceeded[key] = true
+	return true
+}
+
+// updateBudgets accumulates the energy consumed in the snapshot against the
+// budgets and notifies when a budget is exceeded
+func (pm *PowerMonitor) updateBudgets(snapshot *Snapshot, now time.Time) {
+	if pm.budgets == nil {
+		return
+	}
+
+	zone, err := pm.cpu.PrimaryEnergyZone()
+	if err != nil {
+		pm.logger.Warn("Failed to get primary energy zone; budgets are not updated", "error", err)
+		return
+	}
+
+	t := pm.budgets
+	t.resetIfNewDay(now)
+
+	node := snapshot.Node.Zones[zone]
+	t.addNode(node.ActiveEnergyTotal + node.IdleEnergyTotal)
+	t.addPods(zone, snapshot.Pods, snapshot.TerminatedPods)
+
+	snapshot.Budgets = t.statuses()
+	for _, s := range snapshot.Budgets {
+		if !t.markExceeded(s) {
+			continue
+		}
+
+		pm.logger.Warn("Energy budget exceeded",
+			"scope", s.Scope, "name", s.Name,
+			"budget", s.Budget, "consumed", s.Consumed)
+		for _, n := range pm.budgetNotifiers {
+			n.BudgetExceeded(s)
+		}
+		pm.queueEvent(event.Event{
+			Kind:    event.ThresholdCrossed,
+			Subject: strings.TrimSuffix("budget/"+s.Scope+"/"+s.Name, "/"),
+			Message: "Energy budget exceeded",
+			Attrs: map[string]string{
+				"budget":   fmt.Sprintf("%.0f", s.Budget.Joules()),
+				"consumed": fmt.Sprintf("%.0f", s.Consumed.Joules()),
+			},
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	exceeded []BudgetStatus
+}
+
+func (n *recordingNotifier) BudgetExceeded(s BudgetStatus) {
+	n.exceeded = append(n.exceeded, s)
+}
+
+func TestBudgetStatus(t *testing.T) {
+	s := BudgetStatus{Budget: 100 * Joule, Consumed: 40 * Joule}
+	assert.Equal(t, 60*Joule, s.Remaining())
+	assert.False(t, s.Exceeded())
+
+	s.Consumed = 100 * Joule
+	assert.Equal(t, Energy(0), s.Remaining())
+	assert.False(t, s.Exceeded())
+
+	s.Consumed = 120 * Joule
+	assert.Equal(t, Energy(0), s.Remaining())
+	assert.True(t, s.Exceeded())
+}
+
+func TestBudgetsIsEmpty(t *testing.T) {
+	assert.True(t, Budgets{}.isEmpty())
+	assert.True(t, Budgets{Namespaces: map[string]Energy{"ns": 0}}.isEmpty())
+	assert.False(t, Budgets{Node: Joule}.isEmpty())
+	assert.False(t, Budgets{Namespaces: map[string]Energy{"ns": Joule}}.isEmpty())
+}
+
+func TestUpdateBudgets(t *testing.T) {
+	zones := CreateTestZones()
+	pkg := zones[0]
+
+	meter := &MockCPUPowerMeter{}
+	meter.On("PrimaryEnergyZone").Return(pkg, nil)
+
+	notifier := &recordingNotifier{}
+	pm := &PowerMonitor{
+		logger: slog.Default(),
+		cpu:    meter,
+		budgets: newBudgetTracker(Budgets{
+			Node: 100 * Joule,
+			Namespaces: map[string]Energy{
+				"prod": 50 * Joule,
+				"dev":  20 * Joule,
+			},
+		}),
+		budgetNotifiers: []BudgetNotifier{notifier},
+	}
+
+	newSnapshot := func(node Energy, pods Pods, terminated Pods) *Snapshot {
+		s := NewSnapshot()
+		s.Node.Zones[pkg] = NodeUsage{ActiveEnergyTotal: node / 2, IdleEnergyTotal: node / 2}
+		if pods != nil {
+			s.Pods = pods
+		}
+		if terminated != nil {
+			s.TerminatedPods = terminated
+		}
+		return s
+	}
+	pod := func(ns string, energy Energy) *Pod {
+		return &Pod{Namespace: ns, Zones: ZoneUsageMap{pkg: {EnergyTotal: energy}}}
+	}
+
+	day := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	// first collection sets the baseline of the node and counts pods from 0
+	s := newSnapshot(1000*Joule, Pods{"a": pod("prod", 10*Joule)}, nil)
+	pm.updateBudgets(s, day)
+	require.Len(t, s.Budgets, 3)
+	assert.Equal(t, BudgetStatus{Scope: BudgetScopeNode, Budget: 100 * Joule}, s.Budgets[0])
+	assert.Equal(t, BudgetStatus{Scope: BudgetScopeNamespace, Name: "dev", Budget: 20 * Joule}, s.Budgets[1])
+	assert.Equal(t, BudgetStatus{Scope: BudgetScopeNamespace, Name: "prod", Budget: 50 * Joule, Consumed: 10 * Joule}, s.Budgets[2])
+	assert.Empty(t, notifier.exceeded)
+
+	// node consumes 60 J, pod "a" 30 J and new pod "b" 25 J in dev which exceeds its budget
+	s = newSnapshot(1060*Joule, Pods{"a": pod("prod", 40*Joule), "b": pod("dev", 25*Joule)}, nil)
+	pm.updateBudgets(s, day.Add(time.Minute))
+	assert.Equal(t, 60*Joule, s.Budgets[0].Consumed)
+	assert.Equal(t, 25*Joule, s.Budgets[1].Consumed)
+	assert.Equal(t, 40*Joule, s.Budgets[2].Consumed)
+	assert.Equal(t, 10*Joule, s.Budgets[2].Remaining())
+	require.Len(t, notifier.exceeded, 1)
+	assert.Equal(t, "dev", notifier.exceeded[0].Name)
+
+	// terminated pods are not counted twice; exceeded budgets notify only once
+	s = newSnapshot(1120*Joule, Pods{"a": pod("prod", 45*Joule)}, Pods{"b": pod("dev", 25*Joule)})
+	pm.updateBudgets(s, day.Add(2*time.Minute))
+	assert.Equal(t, 120*Joule, s.Budgets[0].Consumed)
+	assert.Equal(t, 25*Joule, s.Budgets[1].Consumed)
+	assert.Equal(t, 45*Joule, s.Budgets[2].Consumed)
+	require.Len(t, notifier.exceeded, 2)
+	assert.Equal(t, BudgetScopeNode, notifier.exceeded[1].Scope)
+
+	// consumption is reset at the start of the next day
+	s = newSnapshot(1130*Joule, Pods{"a": pod("prod", 50*Joule)}, nil)
+	pm.updateBudgets(s, day.Add(14*time.Hour))
+	assert.Equal(t, 10*Joule, s.Budgets[0].Consumed)
+	assert.Equal(t, Energy(0), s.Budgets[1].Consumed)
+	assert.Equal(t, 5*Joule, s.Budgets[2].Consumed)
+	assert.NotContains(t, pm.budgets.prevPods, "b", "pods that are gone are no longer tracked")
+	assert.Len(t, notifier.exceeded, 2)
+}
+
+func TestUpdateBudgetsDisabled(t *testing.T) {
+	pm := &PowerMonitor{logger: slog.Default()}
+	s := NewSnapshot()
+	pm.updateBudgets(s, time.Now())
+	assert.Nil(t, s.Budgets)
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+// Calibration corrects the power read from a zone, e.g. with factors derived
+// from comparing RAPL or the BMC against an external power meter:
+// corrected = Scale × raw + Offset
+type Calibration struct {
+	Scale  float64 // 1 leaves the power read unchanged
+	Offset Power
+}
+
+// Calibrations maps the names of zones, e.g. package or platform, to their
+// calibration; zones not listed are not corrected
+type Calibrations map[string]Calibration
+
+// apply returns the energy corrected over an interval of seconds
+func (c Calibration) apply(energy Energy, seconds float64) Energy {
+	corrected := c.Scale*float64(energy) + float64(c.Offset)*seconds
+	return Energy(max(corrected, 0))
+}
+
+// correct returns the power corrected
+func (c Calibration) correct(power Power) Power {
+	return Power(max(c.Scale*float64(power)+float64(c.Offset), 0))
+}
+
+// calibrate returns the energy of zone in an interval of seconds corrected by
+// its calibration, and whether zone is calibrated
+func (pm *PowerMonitor) calibrate(zone EnergyZone, energy Energy, seconds float64) (Energy, bool) {
+	c, ok := pm.calibrations[zone.Name()]
+	if !ok {
+		return energy, false
+	}
+	return c.apply(energy, seconds), true
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestNodePowerCalibration(t *testing.T) {
+	pkg := device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*Joule)
+	dram := device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:0", 1000*Joule)
+
+	meter := &MockCPUPowerMeter{}
+	meter.On("Zones").Return([]EnergyZone{pkg, dram}, nil)
+	informer := &MockResourceInformer{}
+	informer.On("Node").Return(&resource.Node{CPUUsageRatio: 0.5})
+
+	clock := testingclock.NewFakeClock(time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC))
+	pm := NewPowerMonitor(meter,
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithClock(clock),
+		WithResourceInformer(informer),
+		WithCalibrations(Calibrations{"package": {Scale: 1.1, Offset: 2 * Watt}}),
+	)
+
+	prev := NewSnapshot()
+	pkg.OnEnergy(100*Joule, nil)
+	dram.OnEnergy(10*Joule, nil)
+	require.NoError(t, pm.firstNodeRead(prev.Node))
+
+	clock.Step(5 * time.Second)
+	pkg.OnEnergy(200*Joule, nil)
+	dram.OnEnergy(35*Joule, nil)
+	current := NewSnapshot()
+	require.NoError(t, pm.calculateNodePower(prev.Node, current.Node))
+
+	usage := current.Node.Zones[pkg]
+	assert.InDelta(t, 20, usage.RawPower.Watts(), 1e-6)
+	assert.InDelta(t, 24, usage.Power.Watts(), 1e-6, "1.1 × 20 W + 2 W")
+	assert.InDelta(t, 12, usage.ActivePower.Watts(), 1e-6, "the corrected power is split")
+	assert.InDelta(t, 120, (usage.ActiveEnergyTotal + usage.IdleEnergyTotal - prev.Node.Zones[pkg].ActiveEnergyTotal -
+		prev.Node.Zones[pkg].IdleEnergyTotal).Joules(), 1e-6, "the corrected energy is accumulated")
+	assert.Equal(t, 200*Joule, usage.EnergyTotal, "the counter of the zone is kept as read")
+
+	usage = current.Node.Zones[dram]
+	assert.InDelta(t, 5, usage.Power.Watts(), 1e-6)
+	assert.Equal(t, usage.Power, usage.RawPower, "zones not calibrated are not corrected")
+
+	t.Run("corrected power is not negative", func(t *testing.T) {
+		c := Calibration{Scale: 1, Offset: -10 * Watt}
+		assert.Equal(t, Energy(0), c.apply(20*Joule, 5))
+		assert.Equal(t, Power(0), c.correct(5*Watt))
+		assert.Equal(t, 5*Watt, c.correct(15*Watt))
+	})
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+// refreshCarbonIntensity updates the carbon intensity used to compute the
+// emissions of the energy consumed in this interval; emissions are not
+// accumulated while the intensity is unknown
+func (pm *PowerMonitor) refreshCarbonIntensity(node *Node) {
+	pm.carbonIntensity = 0
+	if pm.carbon == nil {
+		return
+	}
+
+	intensity, err := pm.carbon.Intensity()
+	pm.updateSource(SourceCarbon, pm.carbon.Name(), err)
+	if err != nil {
+		pm.logger.Debug("Carbon intensity is unknown; emissions are not accumulated", "error", err)
+		return
+	}
+
+	pm.carbonIntensity = intensity
+	node.CarbonIntensity = float64(intensity)
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/carbon"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type unavailableCarbonProvider struct{}
+
+func (unavailableCarbonProvider) Name() string { return "unavailable" }
+
+func (unavailableCarbonProvider) Intensity() (carbon.Intensity, error) {
+	return 0, carbon.ErrUnavailable
+}
+
+func TestRefreshCarbonIntensity(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		pm := &PowerMonitor{logger: slog.Default()}
+		node := &Node{}
+		pm.refreshCarbonIntensity(node)
+		assert.Zero(t, pm.carbonIntensity)
+		assert.Zero(t, node.CarbonIntensity)
+	})
+
+	t.Run("known", func(t *testing.T) {
+		pm := &PowerMonitor{logger: slog.Default(), carbon: carbon.NewStaticProvider(400)}
+		node := &Node{}
+		pm.refreshCarbonIntensity(node)
+		assert.Equal(t, carbon.Intensity(400), pm.carbonIntensity)
+		assert.Equal(t, 400.0, node.CarbonIntensity)
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		pm := &PowerMonitor{logger: slog.Default(), carbon: unavailableCarbonProvider{}, carbonIntensity: 400}
+		node := &Node{}
+		pm.refreshCarbonIntensity(node)
+		assert.Zero(t, pm.carbonIntensity, "stale intensity must not be used")
+		assert.Zero(t, node.CarbonIntensity)
+	})
+}
+
+func TestAttributeZonesEmissions(t *testing.T) {
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+	zones := NodeZoneUsageMap{
+		pkg: {ActivePower: 40 * Watt, activeEnergy: 3600 * Joule},
+	}
+
+	pm := &PowerMonitor{carbonIntensity: 500}
+	prev := ZoneUsageMap{
+		pkg: {EnergyTotal: 100 * Joule, EmissionsTotal: 2},
+	}
+
+	usage := ZoneUsageMap{}
+	pm.attributeZones(usage, zones, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 1}, 4, prev)
+
+	// 1/4 of 3600 J = 0.00025 kWh at 500 gCO2e/kWh = 0.125 g
+	assert.Equal(t, 1000*Joule, usage[pkg].EnergyTotal)
+	assert.InDelta(t, 2.125, usage[pkg].EmissionsTotal, 1e-9)
+}
+
+func TestNodeEmissions(t *testing.T) {
+	pkg := device.NewMockRaplZone("package-0", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1_000_000_000*Joule)
+
+	meter := &MockCPUPowerMeter{}
+	meter.On("Zones").Return([]EnergyZone{pkg}, nil)
+	meter.On("PrimaryEnergyZone").Return(pkg, nil)
+
+	resources := &MockResourceInformer{}
+	resources.On("Node").Return(&resource.Node{CPUUsageRatio: 0.5}, nil)
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	pm := &PowerMonitor{
+		logger:    slog.Default(),
+		cpu:       meter,
+		clock:     fakeClock,
+		resources: resources,
+		carbon:    carbon.NewStaticProvider(200),
+	}
+	require.NoError(t, pm.Init())
+
+	pkg.Inc(7200 * Joule)
+	prev := NewSnapshot()
+	require.NoError(t, pm.firstNodeRead(prev.Node))
+	assert.Equal(t, 200.0, prev.Node.CarbonIntensity)
+	assert.Zero(t, prev.Node.Zones[pkg].EmissionsTotal, "emissions start when monitoring starts")
+
+	// 2 x 1 kWh at 200 gCO2e/kWh
+	for range 2 {
+		pkg.Inc(3600_000 * Joule)
+		fakeClock.Step(time.Hour)
+
+		current := NewSnapshot()
+		require.NoError(t, pm.calculateNodePower(prev.Node, current.Node))
+		prev = current
+	}
+	assert.InDelta(t, 400.0, prev.Node.Zones[pkg].EmissionsTotal, 1e-6)
+}
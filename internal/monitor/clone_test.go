@@ -242,6 +242,9 @@ func TestPodClone(t *testing.T) {
 			ID:           "pod-789",
 			Name:         "test-pod",
 			Namespace:    "default",
+			Labels:       map[string]string{"app": "web"},
+			OwnerKind:    "Deployment",
+			OwnerName:    "web",
 			CPUTotalTime: 150.0,
 			Zones: ZoneUsageMap{
 				zone: Usage{
@@ -258,11 +261,16 @@ func TestPodClone(t *testing.T) {
 		assert.Equal(t, original.ID, clone.ID, "ID should be copied")
 		assert.Equal(t, original.Name, clone.Name, "Name should be copied")
 		assert.Equal(t, original.Namespace, clone.Namespace, "Namespace should be copied")
+		assert.Equal(t, original.Labels, clone.Labels, "Labels should be copied")
+		assert.Equal(t, original.OwnerKind, clone.OwnerKind, "OwnerKind should be copied")
+		assert.Equal(t, original.OwnerName, clone.OwnerName, "OwnerName should be copied")
 		assert.Equal(t, original.CPUTotalTime, clone.CPUTotalTime, "CPUTotalTime should be copied")
 		assert.Equal(t, original.Zones[zone], clone.Zones[zone], "Zone values should be copied")
 
 		// Verify deep copy behavior
 		clone.Name = "modified-pod"
+		clone.Labels["app"] = "modified"
+		assert.Equal(t, "web", original.Labels["app"], "Original Labels should be unchanged")
 		clone.Zones[zone] = Usage{EnergyTotal: 500 * Joule, Power: 25 * Watt}
 
 		assert.NotEqual(t, original.Name, clone.Name, "Original Name should be unchanged")
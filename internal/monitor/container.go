@@ -4,6 +4,8 @@
 package monitor
 
 import (
+	"maps"
+
 	"github.com/sustainable-computing-io/kepler/internal/resource"
 )
 
@@ -18,20 +20,8 @@ func (pm *PowerMonitor) firstContainerRead(snapshot *Snapshot) error {
 	for id, cntr := range running {
 		container := newContainer(cntr, zones)
 
-		// Calculate initial energy based on CPU ratio * nodeActiveEnergy
-		for zone, nodeZoneUsage := range zones {
-			if nodeZoneUsage.ActivePower == 0 || nodeZoneUsage.activeEnergy == 0 || nodeCPUTimeDelta == 0 {
-				continue
-			}
-
-			cpuTimeRatio := cntr.CPUTimeDelta / nodeCPUTimeDelta
-			activeEnergy := Energy(cpuTimeRatio * float64(nodeZoneUsage.activeEnergy))
-
-			container.Zones[zone] = Usage{
-				Power:       Power(0), // No power in first read - no delta time to calculate rate
-				EnergyTotal: activeEnergy,
-			}
-		}
+		// Calculate initial energy as the share of node active energy
+		pm.attributeZones(container.Zones, zones, Workload{Kind: ContainerWorkload, ID: id, CPUTimeDelta: cntr.CPUTimeDelta}, nodeCPUTimeDelta, pm.restoredZones(ContainerWorkload, id, zones))
 
 		containers[id] = container
 	}
@@ -44,11 +34,13 @@ func (pm *PowerMonitor) firstContainerRead(snapshot *Snapshot) error {
 
 func newContainer(cntr *resource.Container, zones NodeZoneUsageMap) *Container {
 	container := &Container{
-		ID:           cntr.ID,
-		Name:         cntr.Name,
-		Runtime:      cntr.Runtime,
-		CPUTotalTime: cntr.CPUTotalTime,
-		Zones:        make(ZoneUsageMap, len(zones)),
+		ID:             cntr.ID,
+		Name:           cntr.Name,
+		Runtime:        cntr.Runtime,
+		Image:          cntr.Image,
+		ComposeProject: cntr.ComposeProject,
+		CPUTotalTime:   cntr.CPUTotalTime,
+		Zones:          make(ZoneUsageMap, len(zones)),
 	}
 
 	// Initialize each zone with zero values
@@ -67,13 +59,26 @@ func newContainer(cntr *resource.Container, zones NodeZoneUsageMap) *Container {
 	return container
 }
 
+// matches returns true if the container has the same attributes as cntr
+func (c *Container) matches(cntr *resource.Container) bool {
+	podID := ""
+	if cntr.Pod != nil {
+		podID = cntr.Pod.ID
+	}
+
+	return c.ID == cntr.ID &&
+		c.Name == cntr.Name &&
+		c.Runtime == cntr.Runtime &&
+		c.Image == cntr.Image &&
+		c.ComposeProject == cntr.ComposeProject &&
+		c.CPUTotalTime == cntr.CPUTotalTime &&
+		c.PodID == podID
+}
+
 // calculateContainerPower calculates container power for each running container
 func (pm *PowerMonitor) calculateContainerPower(prev, newSnapshot *Snapshot) error {
-	// Clear terminated workloads if snapshot has been exported
-	if pm.exported.Load() {
-		pm.logger.Debug("Clearing terminated containers after export")
-		pm.terminatedContainersTracker.Clear()
-	}
+	// Release terminated workloads that are past retention (or exported)
+	pm.terminatedContainersTracker.Prune(pm.exported.Load())
 
 	// Get the current cntrs
 	cntrs := pm.resources.Containers()
@@ -88,6 +93,9 @@ func (pm *PowerMonitor) calculateContainerPower(prev, newSnapshot *Snapshot) err
 		// Add to internal tracker (which will handle priority-based retention)
 		// NOTE: Each terminated container is only added once since a container cannot be terminated twice
 		pm.terminatedContainersTracker.Add(prevContainer.Clone())
+		pm.queueTerminated("container", id, map[string]string{
+			"name": prevContainer.Name, "runtime": string(prevContainer.Runtime), "pod": prevContainer.PodID,
+		})
 	}
 
 	// process running containers
@@ -104,38 +112,26 @@ func (pm *PowerMonitor) calculateContainerPower(prev, newSnapshot *Snapshot) err
 
 	// For each container, calculate power for each zone separately
 	for id, c := range cntrs.Running {
-		container := newContainer(c, zones)
+		// Calculate share of each zone's active power and energy; energy
+		// accumulates over the previous snapshot
+		var prevZones ZoneUsageMap
+		prevContainer, exists := prev.Containers[id]
+		if exists {
+			prevZones = prevContainer.Zones
+		} else {
+			prevZones = pm.restoredZones(ContainerWorkload, id, zones)
+		}
+		usage := pm.usageBuffer(zones)
+		pm.attributeZones(usage, zones, Workload{Kind: ContainerWorkload, ID: id, CPUTimeDelta: c.CPUTimeDelta}, nodeCPUTimeDelta, prevZones)
 
-		// Calculate CPU time ratio for this container
-
-		// For each zone in the node, calculate container's share
-		for zone, nodeZoneUsage := range zones {
-			// Skip zones with zero power to avoid division by zero
-			if nodeZoneUsage.ActivePower == 0 || nodeZoneUsage.activeEnergy == 0 || nodeCPUTimeDelta == 0 {
-				continue
-			}
-
-			cpuTimeRatio := c.CPUTimeDelta / nodeCPUTimeDelta
-
-			// Calculate energy delta for this interval
-			activeEnergy := Energy(cpuTimeRatio * float64(nodeZoneUsage.activeEnergy))
-
-			// Calculate absolute energy based on previous data
-			// New container, starts with delta
-			absoluteEnergy := activeEnergy
-			if prev, exists := prev.Containers[id]; exists {
-				if prevUsage, hasZone := prev.Zones[zone]; hasZone {
-					absoluteEnergy += prevUsage.EnergyTotal
-				}
-			}
-
-			// Calculate container's share of this zone's power and energy
-			container.Zones[zone] = Usage{
-				Power:       Power(cpuTimeRatio * nodeZoneUsage.ActivePower.MicroWatts()),
-				EnergyTotal: absoluteEnergy,
-			}
+		// share unchanged containers with the previous snapshot (copy-on-write)
+		if exists && prevContainer.matches(c) && sameUsage(prevContainer.Zones, usage) {
+			containerMap[id] = prevContainer
+			continue
 		}
 
+		container := newContainer(c, zones)
+		maps.Copy(container.Zones, usage)
 		containerMap[id] = container
 	}
 
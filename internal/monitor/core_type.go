@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// coreTypeAttribution attributes energy in proportion to the CPU time of
+// workloads weighted by the core type of the CPU each process last ran on, as a
+// CPU second on a P-core of a hybrid CPU consumes more energy than one on an
+// E-core. CPU time on CPUs of unknown core type has a weight of 1.
+type coreTypeAttribution struct {
+	coreTypes map[int]string
+	weights   map[string]float64
+
+	shares workloadShares // nil if no CPU time was used
+}
+
+// NewCoreTypeAttribution returns an Attribution that attributes the energy of
+// all zones in proportion to the CPU time of workloads weighted by the core
+// type of the CPUs they ran on. coreTypes holds the core type of each CPU and
+// weights the weight of each core type. The resource informer must track the
+// last CPU of processes for the weights to have any effect.
+func NewCoreTypeAttribution(coreTypes map[int]string, weights map[string]float64) Attribution {
+	return &coreTypeAttribution{coreTypes: coreTypes, weights: weights}
+}
+
+func (a *coreTypeAttribution) Name() string {
+	return "core-type"
+}
+
+// weight returns the weight of the CPU time of proc
+func (a *coreTypeAttribution) weight(proc *resource.Process) float64 {
+	if w, ok := a.weights[a.coreTypes[proc.LastCPU]]; ok {
+		return w
+	}
+	return 1
+}
+
+func (a *coreTypeAttribution) Update(procs *resource.Processes) {
+	a.shares = nil
+
+	running := runningProcesses(procs)
+	total := 0.0
+	for _, proc := range running {
+		total += a.weight(proc) * proc.CPUTimeDelta
+	}
+	if total == 0 {
+		return
+	}
+
+	a.shares = newWorkloadShares()
+	for _, proc := range running {
+		if weighted := a.weight(proc) * proc.CPUTimeDelta; weighted > 0 {
+			a.shares.add(proc, weighted/total)
+		}
+	}
+}
+
+func (a *coreTypeAttribution) Ratio(zone EnergyZone, w Workload, nodeCPUTimeDelta float64) (float64, bool) {
+	if a.shares == nil {
+		return cpuTimeAttribution{}.Ratio(zone, w, nodeCPUTimeDelta)
+	}
+	return a.shares.ratio(w)
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+func TestCoreTypeAttribution(t *testing.T) {
+	coreTypes := map[int]string{0: device.CoreTypePerformance, 1: device.CoreTypeEfficiency}
+	weights := map[string]float64{device.CoreTypePerformance: 1, device.CoreTypeEfficiency: 0.25}
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+
+	a := NewCoreTypeAttribution(coreTypes, weights)
+	assert.Equal(t, "core-type", a.Name())
+
+	cntr := &resource.Container{ID: "container-1"}
+	a.Update(&resource.Processes{
+		Running: map[int]*resource.Process{
+			1: {PID: 1, LastCPU: 0, CPUTimeDelta: 1, Container: cntr}, // 1 on a P-core
+			2: {PID: 2, LastCPU: 1, CPUTimeDelta: 2, Container: cntr}, // 0.5 on an E-core
+			3: {PID: 3, LastCPU: 7, CPUTimeDelta: 0.5},                // 0.5 on an unknown core
+		},
+	})
+
+	ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 1}, 3.5)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, ratio, 1e-9)
+
+	ratio, ok = a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "2", CPUTimeDelta: 2}, 3.5)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.25, ratio, 1e-9, "CPU time on E-cores is weighted")
+
+	ratio, ok = a.Ratio(pkg, Workload{Kind: ContainerWorkload, ID: "container-1", CPUTimeDelta: 3}, 3.5)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.75, ratio, 1e-9)
+
+	// without CPU time, energy is attributed by CPU time as reported
+	a.Update(&resource.Processes{})
+	ratio, ok = a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 1}, 2)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, ratio, 1e-9)
+}
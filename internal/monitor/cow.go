@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"maps"
+)
+
+// Snapshots are immutable once stored, which allows workloads to be built with
+// copy-on-write semantics: a workload whose attributes and usage did not change
+// since the previous refresh (e.g. an idle process) is shared with the previous
+// snapshot instead of being copied. Usage is first computed into a buffer that
+// is reused across workloads and refreshes, and a workload is only allocated
+// when it differs from its previous version.
+//
+// NOTE: workloads of a snapshot must never be modified in place; Clone them
+// first.
+
+// usageBuffer returns a buffer with zero usage for all zones, to compute the
+// usage of a workload into. The buffer is overwritten by the next call and
+// must be copied to be retained. Refreshes are serialized so it is safe to
+// reuse the buffer across refreshes.
+func (pm *PowerMonitor) usageBuffer(zones NodeZoneUsageMap) ZoneUsageMap {
+	if pm.usageBuf == nil {
+		pm.usageBuf = make(ZoneUsageMap, len(zones))
+	}
+	clear(pm.usageBuf)
+	for zone := range zones {
+		pm.usageBuf[zone] = Usage{}
+	}
+	return pm.usageBuf
+}
+
+// sameUsage returns true if the usage of all zones is equal
+func sameUsage(a, b ZoneUsageMap) bool {
+	return maps.Equal(a, b)
+}
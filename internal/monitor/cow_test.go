@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// newCOWTestMonitor returns an initialized monitor whose informer reports n
+// running processes of which the first busy have used CPU time since the last refresh
+func newCOWTestMonitor(tb testing.TB, n, busy int) (*PowerMonitor, []EnergyZone) {
+	tb.Helper()
+
+	zones := CreateTestZones()
+	meter := &MockCPUPowerMeter{}
+	meter.On("Zones").Return(zones, nil)
+	meter.On("PrimaryEnergyZone").Return(zones[0], nil)
+
+	running := make(map[int]*resource.Process, n)
+	for pid := 1; pid <= n; pid++ {
+		delta := 0.0
+		if pid <= busy {
+			delta = 1
+		}
+		running[pid] = &resource.Process{
+			PID:          pid,
+			Comm:         fmt.Sprintf("proc-%d", pid),
+			Exe:          "/usr/bin/proc",
+			Type:         resource.RegularProcess,
+			CPUTotalTime: 100,
+			CPUTimeDelta: delta,
+		}
+	}
+
+	informer := &MockResourceInformer{}
+	informer.On("Node").Return(&resource.Node{ProcessTotalCPUTimeDelta: float64(max(busy, 1))})
+	informer.On("Processes").Return(&resource.Processes{Running: running, Terminated: map[int]*resource.Process{}})
+
+	pm := &PowerMonitor{
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cpu:           meter,
+		clock:         testingclock.NewFakeClock(time.Now()),
+		resources:     informer,
+		maxTerminated: 500,
+	}
+	require.NoError(tb, pm.Init())
+	return pm, zones
+}
+
+func TestCopyOnWriteProcesses(t *testing.T) {
+	pm, zones := newCOWTestMonitor(t, 3, 1)
+
+	prev := NewSnapshot()
+	prev.Node = createNodeSnapshot(zones, time.Now(), 0.5)
+	require.NoError(t, pm.firstProcessRead(prev))
+
+	busy := prev.Processes["1"].Clone()
+
+	current := NewSnapshot()
+	current.Node = createNodeSnapshot(zones, time.Now(), 0.5)
+	require.NoError(t, pm.calculateProcessPower(prev, current))
+	require.Len(t, current.Processes, 3)
+
+	// idle processes are shared with the previous snapshot
+	assert.Same(t, prev.Processes["2"], current.Processes["2"])
+	assert.Same(t, prev.Processes["3"], current.Processes["3"])
+
+	// busy processes are copied and the previous snapshot is left unchanged
+	assert.NotSame(t, prev.Processes["1"], current.Processes["1"])
+	assert.Equal(t, busy, prev.Processes["1"])
+	for _, zone := range zones {
+		assert.Greater(t, current.Processes["1"].Zones[zone].EnergyTotal, busy.Zones[zone].EnergyTotal)
+	}
+
+	t.Run("changed attributes are copied", func(t *testing.T) {
+		procs := pm.resources.Processes()
+		procs.Running[2].Comm = "renamed"
+
+		next := NewSnapshot()
+		next.Node = createNodeSnapshot(zones, time.Now(), 0.5)
+		require.NoError(t, pm.calculateProcessPower(current, next))
+
+		assert.NotSame(t, current.Processes["2"], next.Processes["2"])
+		assert.Equal(t, "renamed", next.Processes["2"].Comm)
+		assert.Equal(t, current.Processes["2"].Zones, next.Processes["2"].Zones)
+		assert.Same(t, current.Processes["3"], next.Processes["3"])
+	})
+}
+
+func TestUsageBuffer(t *testing.T) {
+	zones := CreateTestZones()
+	node := createNodeSnapshot(zones, time.Now(), 0.5)
+	pm := &PowerMonitor{}
+
+	buf := pm.usageBuffer(node.Zones)
+	require.Len(t, buf, len(zones))
+	buf[zones[0]] = Usage{EnergyTotal: 10 * Joule}
+
+	buf = pm.usageBuffer(node.Zones)
+	assert.Equal(t, Usage{}, buf[zones[0]], "buffer is reset on every call")
+}
+
+func TestMatches(t *testing.T) {
+	cntr := &resource.Container{ID: "c1", Name: "app", Runtime: resource.PodmanRuntime, CPUTotalTime: 10,
+		Pod: &resource.Pod{ID: "p1"}}
+	c := newContainer(cntr, nil)
+	assert.True(t, c.matches(cntr))
+	cntr.CPUTotalTime = 11
+	assert.False(t, c.matches(cntr))
+
+	vm := &resource.VirtualMachine{ID: "vm1", Name: "vm", Hypervisor: resource.KVMHypervisor, CPUTotalTime: 10}
+	v := newVM(vm, nil)
+	assert.True(t, v.matches(vm))
+	vm.Name = "renamed"
+	assert.False(t, v.matches(vm))
+
+	pod := &resource.Pod{ID: "p1", Name: "pod", Namespace: "default", CPUTotalTime: 10,
+		Labels: map[string]string{"app": "web"}, OwnerKind: "Deployment", OwnerName: "web"}
+	p := newPod(pod, nil)
+	assert.True(t, p.matches(pod))
+	pod.Labels = map[string]string{"app": "web", "tier": "frontend"}
+	assert.False(t, p.matches(pod))
+	pod.Labels = p.Labels
+	pod.Namespace = "other"
+	assert.False(t, p.matches(pod))
+
+	proc := &resource.Process{PID: 1, Comm: "proc", Exe: "/bin/proc", CPUTotalTime: 10,
+		Container: &resource.Container{ID: "c1"}}
+	pr := newProcess(proc, nil)
+	assert.True(t, pr.matches(proc))
+	proc.Container = nil
+	assert.False(t, pr.matches(proc))
+
+	proc = &resource.Process{PID: 2, Comm: "sh", CmdLine: "sh -c true", UID: 1000, User: "alice", ParentPID: 1}
+	pr = newProcess(proc, nil)
+	assert.True(t, pr.matches(proc))
+	proc.CmdLine = "sleep 1"
+	assert.False(t, pr.matches(proc))
+}
+
+// BenchmarkCalculateProcessPower measures the cost of refreshing processes on a
+// node where most processes are idle between refreshes
+func BenchmarkCalculateProcessPower(b *testing.B) {
+	for _, tc := range []struct {
+		processes, busy int
+	}{
+		{1000, 100},
+		{5000, 500},
+		{5000, 5000},
+	} {
+		b.Run(fmt.Sprintf("processes=%d/busy=%d", tc.processes, tc.busy), func(b *testing.B) {
+			pm, zones := newCOWTestMonitor(b, tc.processes, tc.busy)
+
+			prev := NewSnapshot()
+			prev.Node = createNodeSnapshot(zones, time.Now(), 0.5)
+			require.NoError(b, pm.firstProcessRead(prev))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				current := NewSnapshot()
+				current.Node = prev.Node
+				if err := pm.calculateProcessPower(prev, current); err != nil {
+					b.Fatal(err)
+				}
+				prev = current
+			}
+		})
+	}
+}
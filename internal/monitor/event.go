@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"maps"
+	"strconv"
+
+	"github.com/sustainable-computing-io/kepler/internal/event"
+)
+
+// queueEvent queues e to be published once the snapshot being refreshed is
+// stored, so that subscribers reading the snapshot on an event see its effect
+func (pm *PowerMonitor) queueEvent(e event.Event) {
+	if pm.events == nil {
+		return
+	}
+	pm.pendingEvents = append(pm.pendingEvents, e)
+}
+
+// queueTerminated queues the event of a workload of kind, e.g. container,
+// having terminated
+func (pm *PowerMonitor) queueTerminated(kind, id string, attrs map[string]string) {
+	maps.DeleteFunc(attrs, func(_, v string) bool { return v == "" })
+	attrs["kind"] = kind
+	attrs["id"] = id
+	pm.queueEvent(event.Event{
+		Kind:    event.ResourceTerminated,
+		Subject: kind + "/" + id,
+		Attrs:   attrs,
+	})
+}
+
+// publishEvents publishes the events queued while refreshing snapshot,
+// followed by snapshot being ready
+func (pm *PowerMonitor) publishEvents(snapshot *Snapshot) {
+	if pm.events == nil {
+		return
+	}
+	for _, e := range pm.pendingEvents {
+		e.Time = snapshot.Timestamp
+		pm.events.Publish(e)
+	}
+	pm.pendingEvents = pm.pendingEvents[:0]
+
+	pm.events.Publish(event.Event{
+		Kind: event.SnapshotReady,
+		Time: snapshot.Timestamp,
+		Attrs: map[string]string{
+			"processes":  strconv.Itoa(len(snapshot.Processes)),
+			"containers": strconv.Itoa(len(snapshot.Containers)),
+			"vms":        strconv.Itoa(len(snapshot.VirtualMachines)),
+			"pods":       strconv.Itoa(len(snapshot.Pods)),
+		},
+	})
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+)
+
+func TestPublishEvents(t *testing.T) {
+	bus := event.NewBus()
+	sub := bus.Subscribe("test", 10)
+	defer sub.Close()
+	pm := &PowerMonitor{logger: slog.Default(), events: bus}
+
+	denied := &os.PathError{Op: "open", Path: "energy_uj", Err: os.ErrPermission}
+	pm.updateSource(SourceGPU, "nvidia", denied)
+	pm.updateSource(SourceGPU, "nvidia", denied)
+	pm.queueTerminated("pod", "abc", map[string]string{"name": "api", "namespace": ""})
+	assert.Empty(t, sub.C(), "events are queued until the snapshot is stored")
+
+	snapshot := NewSnapshot()
+	snapshot.Timestamp = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshot.Pods = Pods{"def": &Pod{ID: "def"}}
+	pm.publishEvents(snapshot)
+
+	require.Len(t, sub.C(), 3, "only changes of sources are published")
+	degraded := <-sub.C()
+	assert.Equal(t, event.SourceDegraded, degraded.Kind)
+	assert.Equal(t, "gpu/nvidia", degraded.Subject)
+	assert.Equal(t, "permission", degraded.Attrs["reason"])
+	assert.Equal(t, snapshot.Timestamp, degraded.Time)
+
+	assert.Equal(t, event.Event{
+		Kind:    event.ResourceTerminated,
+		Time:    snapshot.Timestamp,
+		Subject: "pod/abc",
+		Attrs:   map[string]string{"kind": "pod", "id": "abc", "name": "api"},
+	}, <-sub.C())
+
+	ready := <-sub.C()
+	assert.Equal(t, event.SnapshotReady, ready.Kind)
+	assert.Equal(t, "1", ready.Attrs["pods"])
+	assert.Empty(t, pm.pendingEvents)
+
+	t.Run("disabled", func(t *testing.T) {
+		pm := &PowerMonitor{logger: slog.Default()}
+		pm.queueTerminated("vm", "xyz", map[string]string{})
+		assert.Empty(t, pm.pendingEvents)
+		pm.publishEvents(snapshot)
+	})
+}
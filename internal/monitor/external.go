@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// EstimatePath is the path external estimators serve estimates at
+const EstimatePath = "/v1/estimate"
+
+// EstimateRequest is posted as JSON to external estimators on every refresh with
+// the features of the processes running since the previous refresh
+type EstimateRequest struct {
+	Processes []ProcessFeatures `json:"processes"`
+}
+
+// ProcessFeatures are the features of a process the power is estimated from
+type ProcessFeatures struct {
+	PID            int     `json:"pid"`
+	Comm           string  `json:"comm"`
+	ContainerID    string  `json:"containerId,omitempty"`
+	PodID          string  `json:"podId,omitempty"`
+	VMID           string  `json:"vmId,omitempty"`
+	CPUTimeDelta   float64 `json:"cpuTimeDelta"`   // seconds since the previous refresh
+	ResidentMemory uint64  `json:"residentMemory"` // bytes
+	Instructions   uint64  `json:"instructions"`   // since the previous refresh; 0 without perf events
+	Cycles         uint64  `json:"cycles"`
+	CacheMisses    uint64  `json:"cacheMisses"`
+}
+
+// EstimateResponse is the power an external estimator estimates for the
+// processes of an EstimateRequest; processes it omits get no power
+type EstimateResponse struct {
+	Processes []ProcessEstimate `json:"processes"`
+}
+
+// ProcessEstimate is the estimated power of a process
+type ProcessEstimate struct {
+	PID   int     `json:"pid"`
+	Watts float64 `json:"watts"`
+}
+
+// externalAttribution sends the features of the running processes to an external
+// estimator, e.g. a sidecar serving an ML model, on every refresh and attributes
+// the energy of all zones in proportion to the power it estimates for them.
+// While the estimator fails or times out, energy is attributed by CPU time.
+type externalAttribution struct {
+	logger   *slog.Logger
+	client   *http.Client
+	url      string
+	fallback Attribution
+
+	shares  workloadShares // of the last estimate; nil if it failed
+	failing bool
+}
+
+// ExternalOptFn is a functional option for configuring the external attribution
+type ExternalOptFn func(*externalAttribution)
+
+// WithExternalLogger sets the logger for the external attribution
+func WithExternalLogger(logger *slog.Logger) ExternalOptFn {
+	return func(a *externalAttribution) {
+		a.logger = logger.With("attribution", "external")
+	}
+}
+
+// WithExternalTimeout sets the timeout of requests to the estimator, which
+// delay every refresh until they return
+func WithExternalTimeout(d time.Duration) ExternalOptFn {
+	return func(a *externalAttribution) {
+		a.client.Timeout = d
+	}
+}
+
+// NewExternalAttribution returns an Attribution that attributes the energy of
+// all zones in proportion to the power estimated by the estimator at endpoint,
+// an http(s) URL or unix:///path/to/socket for an estimator on a unix socket.
+// Estimates are posted to EstimatePath of the endpoint.
+func NewExternalAttribution(endpoint string, opts ...ExternalOptFn) (Attribution, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid estimator endpoint %q: %w", endpoint, err)
+	}
+
+	a := &externalAttribution{
+		logger:   slog.Default().With("attribution", "external"),
+		client:   &http.Client{Timeout: time.Second},
+		fallback: NewCPUTimeAttribution(),
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid estimator endpoint %q: no host", endpoint)
+		}
+		a.url = strings.TrimSuffix(endpoint, "/") + EstimatePath
+	case "unix":
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid estimator endpoint %q: no socket path", endpoint)
+		}
+		var d net.Dialer
+		a.client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return d.DialContext(ctx, "unix", u.Path)
+			},
+		}
+		a.url = "http://estimator" + EstimatePath
+	default:
+		return nil, fmt.Errorf("invalid estimator endpoint %q: unsupported scheme %q; must be one of http, https, unix", endpoint, u.Scheme)
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a, nil
+}
+
+func (a *externalAttribution) Name() string {
+	return "external"
+}
+
+func (a *externalAttribution) Update(procs *resource.Processes) {
+	running := runningProcesses(procs)
+	shares, err := a.estimate(running)
+	if err != nil {
+		// warn only once until the estimator recovers, as it is asked on every refresh
+		if !a.failing {
+			a.logger.Warn("Failed to estimate power; attributing by CPU time until it recovers", "error", err)
+		}
+		a.failing, a.shares = true, nil
+		a.fallback.Update(procs)
+		return
+	}
+
+	if a.failing {
+		a.logger.Info("Estimating power again")
+	}
+	a.failing, a.shares = false, shares
+}
+
+func (a *externalAttribution) Ratio(zone EnergyZone, w Workload, nodeCPUTimeDelta float64) (float64, bool) {
+	if a.shares == nil {
+		return a.fallback.Ratio(zone, w, nodeCPUTimeDelta)
+	}
+	return a.shares.ratio(w)
+}
+
+// estimate asks the estimator for the power of the running processes and returns
+// their shares of the total
+func (a *externalAttribution) estimate(running map[int]*resource.Process) (workloadShares, error) {
+	req := EstimateRequest{Processes: make([]ProcessFeatures, 0, len(running))}
+	for _, proc := range running {
+		f := ProcessFeatures{
+			PID:            proc.PID,
+			Comm:           proc.Comm,
+			CPUTimeDelta:   proc.CPUTimeDelta,
+			ResidentMemory: proc.ResidentMemory,
+			Instructions:   proc.HWCountersDelta.Instructions,
+			Cycles:         proc.HWCountersDelta.Cycles,
+			CacheMisses:    proc.HWCountersDelta.CacheMisses,
+		}
+		if proc.Container != nil {
+			f.ContainerID = proc.Container.ID
+			if proc.Container.Pod != nil {
+				f.PodID = proc.Container.Pod.ID
+			}
+		}
+		if proc.VirtualMachine != nil {
+			f.VMID = proc.VirtualMachine.ID
+		}
+		req.Processes = append(req.Processes, f)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Post(a.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from estimator: %s", resp.Status)
+	}
+
+	var estimates EstimateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&estimates); err != nil {
+		return nil, fmt.Errorf("failed to decode estimate: %w", err)
+	}
+
+	// estimates of unknown processes and negative estimates are ignored
+	total := 0.0
+	for _, e := range estimates.Processes {
+		if _, ok := running[e.PID]; ok && e.Watts > 0 {
+			total += e.Watts
+		}
+	}
+
+	shares := newWorkloadShares()
+	if total == 0 {
+		return shares, nil
+	}
+	for _, e := range estimates.Processes {
+		if proc, ok := running[e.PID]; ok && e.Watts > 0 {
+			shares.add(proc, e.Watts/total)
+		}
+	}
+	return shares, nil
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// fakeEstimator estimates the power of a process as 10 W per CPU second plus
+// 1 W per 100 bytes of resident memory, and fails while failing is set
+func fakeEstimator(t *testing.T, failing *atomic.Bool) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != EstimatePath {
+			http.NotFound(w, r)
+			return
+		}
+		if failing.Load() {
+			http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+			return
+		}
+
+		var req EstimateRequest
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			return
+		}
+		var resp EstimateResponse
+		for _, p := range req.Processes {
+			resp.Processes = append(resp.Processes, ProcessEstimate{
+				PID:   p.PID,
+				Watts: 10*p.CPUTimeDelta + float64(p.ResidentMemory)/100,
+			})
+		}
+		// estimates of unknown processes are ignored
+		resp.Processes = append(resp.Processes, ProcessEstimate{PID: 42, Watts: 1000})
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+}
+
+func TestExternalAttribution(t *testing.T) {
+	cntr := &resource.Container{ID: "container-1"}
+	procs := &resource.Processes{
+		Running: map[int]*resource.Process{
+			1: {PID: 1, Container: cntr, ResidentMemory: 100, CPUTimeDelta: 1},
+			2: {PID: 2, Container: cntr, ResidentMemory: 300, CPUTimeDelta: 0},
+		},
+		Filtered: map[int]*resource.Process{
+			3: {PID: 3, ResidentMemory: 0, CPUTimeDelta: 2},
+		},
+	}
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+
+	var failing atomic.Bool
+	estimator := httptest.NewServer(fakeEstimator(t, &failing))
+	t.Cleanup(estimator.Close)
+
+	a, err := NewExternalAttribution(estimator.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "external", a.Name())
+
+	t.Run("estimates", func(t *testing.T) {
+		a.Update(procs)
+
+		// 11 W of 11 + 3 + 20 W
+		ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "1"}, 3)
+		assert.True(t, ok)
+		assert.InDelta(t, 11.0/34, ratio, 0.0001)
+
+		ratio, ok = a.Ratio(pkg, Workload{Kind: ContainerWorkload, ID: "container-1"}, 3)
+		assert.True(t, ok)
+		assert.InDelta(t, 14.0/34, ratio, 0.0001)
+
+		ratio, ok = a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "3"}, 3)
+		assert.True(t, ok)
+		assert.InDelta(t, 20.0/34, ratio, 0.0001)
+	})
+
+	t.Run("falls back to cpu time", func(t *testing.T) {
+		failing.Store(true)
+		a.Update(procs)
+
+		ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 1}, 3)
+		assert.True(t, ok)
+		assert.InDelta(t, 1.0/3, ratio, 0.0001)
+
+		// estimates are used again once the estimator recovers
+		failing.Store(false)
+		a.Update(procs)
+		ratio, ok = a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 1}, 3)
+		assert.True(t, ok)
+		assert.InDelta(t, 11.0/34, ratio, 0.0001)
+	})
+
+	t.Run("falls back on timeout", func(t *testing.T) {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		}))
+		t.Cleanup(slow.Close)
+
+		a, err := NewExternalAttribution(slow.URL, WithExternalTimeout(10*time.Millisecond))
+		require.NoError(t, err)
+		a.Update(procs)
+
+		ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "3", CPUTimeDelta: 2}, 3)
+		assert.True(t, ok)
+		assert.InDelta(t, 2.0/3, ratio, 0.0001)
+	})
+}
+
+func TestExternalAttributionUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "estimator.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	var failing atomic.Bool
+	estimator := httptest.NewUnstartedServer(fakeEstimator(t, &failing))
+	estimator.Listener = l
+	estimator.Start()
+	t.Cleanup(estimator.Close)
+
+	a, err := NewExternalAttribution("unix://" + socket)
+	require.NoError(t, err)
+	a.Update(&resource.Processes{
+		Running: map[int]*resource.Process{
+			1: {PID: 1, CPUTimeDelta: 1},
+			2: {PID: 2, CPUTimeDelta: 3},
+		},
+	})
+
+	ratio, ok := a.Ratio(device.NewMockRaplZone("package", 0, "", 1000*Joule), Workload{Kind: ProcessWorkload, ID: "2"}, 4)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.75, ratio, 0.0001)
+}
+
+func TestExternalAttributionInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"localhost:8100", "http://", "unix://", "grpc://localhost:8100"} {
+		_, err := NewExternalAttribution(endpoint)
+		assert.ErrorContains(t, err, "invalid estimator endpoint", endpoint)
+	}
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"github.com/sustainable-computing-io/kepler/internal/device/gpu"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// gpuZone associates a GPU energy zone with the meter it belongs to
+type gpuZone struct {
+	meter gpu.PowerMeter
+	index int // device index as reported by the meter
+}
+
+// initGPUZones collects the zones of all GPU meters; meters whose zones cannot
+// be read are skipped
+func (pm *PowerMonitor) initGPUZones() {
+	pm.gpuZones = map[EnergyZone]gpuZone{}
+	for _, meter := range pm.gpus {
+		zones, err := meter.Zones()
+		if err != nil {
+			pm.logger.Warn("Skipping GPU meter; failed to get zones", "meter", meter.Name(), "error", err)
+			continue
+		}
+		for _, zone := range zones {
+			pm.gpuZones[zone] = gpuZone{meter: meter, index: zone.Index()}
+			pm.gpuZoneList = append(pm.gpuZoneList, zone)
+		}
+	}
+}
+
+// zones returns all zones of the node; i.e. CPU zones followed by GPU zones, I/O
+// zones and the platform zone
+func (pm *PowerMonitor) zones() ([]EnergyZone, error) {
+	zones, err := pm.cpu.Zones()
+	if err != nil {
+		return nil, err
+	}
+	if len(pm.gpuZoneList) == 0 && len(pm.ioZoneList) == 0 && pm.platform == nil {
+		return zones, nil
+	}
+
+	all := make([]EnergyZone, 0, len(zones)+len(pm.gpuZoneList)+len(pm.ioZoneList)+1)
+	all = append(all, zones...)
+	all = append(all, pm.gpuZoneList...)
+	all = append(all, pm.ioZoneList...)
+	if pm.platform != nil {
+		all = append(all, pm.platform)
+	}
+	return all, nil
+}
+
+// refreshGPUUtilization reads the device and per-process utilization of all GPUs
+func (pm *PowerMonitor) refreshGPUUtilization() {
+	if len(pm.gpus) == 0 {
+		return
+	}
+
+	pm.gpuUtilization = make(map[EnergyZone]gpu.Utilization, len(pm.gpuZones))
+	utilByMeter := make(map[gpu.PowerMeter]map[int]gpu.Utilization, len(pm.gpus))
+	for _, meter := range pm.gpus {
+		util, err := meter.Utilization()
+		pm.updateSource(SourceGPU, meter.Name(), err)
+		if err != nil {
+			continue
+		}
+		utilByMeter[meter] = util
+	}
+
+	for zone, gz := range pm.gpuZones {
+		if util, ok := utilByMeter[gz.meter][gz.index]; ok {
+			pm.gpuUtilization[zone] = util
+		}
+	}
+}
+
+// activeRatio returns the ratio of a zone's energy that is considered active;
+// GPU zones use the device utilization, I/O zones the share of their estimated
+// energy due to bytes transferred, the platform zone is all active as it is
+// attributed to VMs as a whole, and all others use node CPU usage
+func (pm *PowerMonitor) activeRatio(zone EnergyZone, nodeCPUUsageRatio float64) float64 {
+	if pm.isPlatform(zone) {
+		return 1
+	}
+	if z, isIO := pm.ioZones[zone]; isIO {
+		return z.zone.ActiveRatio()
+	}
+	if _, isGPU := pm.gpuZones[zone]; !isGPU {
+		return nodeCPUUsageRatio
+	}
+	return min(pm.gpuUtilization[zone].Device, 1)
+}
+
+// computeGPUShares computes the share of each GPU zone's active energy attributable
+// to running workloads based on the per-process GPU utilization. Containers, VMs and
+// pods get the sum of the shares of their processes. Pods also get the share of
+// the device they use, e.g. of the MIG instances allocated to them, which isn't
+// that of their processes.
+func (pm *PowerMonitor) computeGPUShares() {
+	if len(pm.gpuZones) == 0 {
+		return
+	}
+
+	running := runningProcesses(pm.resources.Processes())
+	pm.gpuShares = make(map[EnergyZone]workloadShares, len(pm.gpuUtilization))
+
+	var pods *resource.Pods
+	for _, util := range pm.gpuUtilization {
+		if len(util.Pods) > 0 {
+			pods = pm.resources.Pods()
+			break
+		}
+	}
+
+	for zone, util := range pm.gpuUtilization {
+		total := 0.0
+		for _, u := range util.Processes {
+			total += u
+		}
+		for _, u := range util.Pods {
+			total += u
+		}
+
+		shares := newWorkloadShares()
+		pm.gpuShares[zone] = shares
+		if total == 0 {
+			continue
+		}
+
+		for pid, u := range util.Processes {
+			proc, ok := running[pid]
+			if !ok {
+				// process may have terminated or belongs to another PID namespace
+				continue
+			}
+			shares.add(proc, u/total)
+		}
+		for id, u := range util.Pods {
+			if _, ok := pods.Running[id]; !ok {
+				if _, ok := pods.Filtered[id]; !ok {
+					// pod may have terminated while its devices are still allocated
+					continue
+				}
+			}
+			shares[PodWorkload][id] += u / total
+		}
+	}
+}
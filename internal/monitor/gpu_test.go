@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/device/gpu"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// fakeGPUMeter is a gpu.PowerMeter with a fixed set of zones and utilization
+type fakeGPUMeter struct {
+	zones []EnergyZone
+	util  map[int]gpu.Utilization
+}
+
+func (m *fakeGPUMeter) Name() string                                  { return "fake-gpu" }
+func (m *fakeGPUMeter) Init() error                                   { return nil }
+func (m *fakeGPUMeter) Zones() ([]EnergyZone, error)                  { return m.zones, nil }
+func (m *fakeGPUMeter) Utilization() (map[int]gpu.Utilization, error) { return m.util, nil }
+
+func TestGPUPowerAttribution(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	cpuZones := CreateTestZones()
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(cpuZones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(cpuZones[0], nil)
+
+	// GPU drawing a constant 100W
+	gpuZone := device.NewPowerZone("nvidia-gpu-0", 0, "nvml:GPU-0",
+		func() (Power, error) { return 100 * Watt, nil }, fakeClock)
+	gpuMeter := &fakeGPUMeter{
+		zones: []EnergyZone{gpuZone},
+		util: map[int]gpu.Utilization{
+			0: {Device: 0.8, Processes: map[int]float64{123: 0.6, 456: 0.2}},
+		},
+	}
+
+	pod := &resource.Pod{ID: "pod-1", Name: "pod", Namespace: "default"}
+	cntr := &resource.Container{ID: "container-1", Name: "container", Pod: pod}
+	procs := &resource.Processes{
+		Running: map[int]*resource.Process{
+			123: {PID: 123, Comm: "train", Container: cntr, CPUTimeDelta: 1},
+			456: {PID: 456, Comm: "infer", CPUTimeDelta: 1},
+			789: {PID: 789, Comm: "idle", CPUTimeDelta: 2},
+		},
+	}
+
+	resInformer := &MockResourceInformer{}
+	resInformer.On("Node").Return(&resource.Node{CPUUsageRatio: 0.5, ProcessTotalCPUTimeDelta: 4}, nil)
+	resInformer.On("Processes").Return(procs)
+
+	monitor := &PowerMonitor{
+		logger:                       logger,
+		cpu:                          mockMeter,
+		gpus:                         []gpu.PowerMeter{gpuMeter},
+		clock:                        fakeClock,
+		resources:                    resInformer,
+		maxTerminated:                500,
+		minTerminatedEnergyThreshold: 1 * Joule,
+	}
+	require.NoError(t, monitor.Init())
+
+	assert.Equal(t, []string{"package-0", "core-0", "nvidia-gpu-0"}, monitor.ZoneNames())
+
+	prev := NewSnapshot()
+	require.NoError(t, monitor.firstNodeRead(prev.Node))
+	assert.Contains(t, prev.Node.Zones, EnergyZone(gpuZone))
+
+	fakeClock.Step(10 * time.Second)
+	newSnapshot := NewSnapshot()
+	require.NoError(t, monitor.calculateNodePower(prev.Node, newSnapshot.Node))
+
+	// active/idle split of GPU zones uses the device utilization
+	gpuUsage := newSnapshot.Node.Zones[gpuZone]
+	assert.InDelta(t, 100.0, gpuUsage.Power.Watts(), 0.001)
+	assert.InDelta(t, 80.0, gpuUsage.ActivePower.Watts(), 0.001)
+	assert.InDelta(t, 20.0, gpuUsage.IdlePower.Watts(), 0.001)
+
+	monitor.computeGPUShares()
+	require.NoError(t, monitor.calculateProcessPower(prev, newSnapshot))
+
+	// GPU power is attributed by GPU utilization; i.e. 0.6 : 0.2 of active power
+	assert.InDelta(t, 60.0, newSnapshot.Processes["123"].Zones[gpuZone].Power.Watts(), 0.001)
+	assert.InDelta(t, 20.0, newSnapshot.Processes["456"].Zones[gpuZone].Power.Watts(), 0.001)
+	assert.Equal(t, Power(0), newSnapshot.Processes["789"].Zones[gpuZone].Power)
+
+	// CPU zones continue to be attributed by CPU time
+	for _, zone := range cpuZones {
+		active := newSnapshot.Node.Zones[zone].ActivePower
+		assert.InDelta(t, active.Watts()/2, newSnapshot.Processes["789"].Zones[zone].Power.Watts(), 0.001)
+	}
+
+	t.Run("workload shares", func(t *testing.T) {
+		ratio, ok := monitor.attributionRatio(gpuZone, Workload{Kind: ContainerWorkload, ID: "container-1"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.75, ratio, 0.001)
+
+		ratio, ok = monitor.attributionRatio(gpuZone, Workload{Kind: PodWorkload, ID: "pod-1"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.75, ratio, 0.001)
+
+		_, ok = monitor.attributionRatio(gpuZone, Workload{Kind: ContainerWorkload, ID: "unknown"}, 4)
+		assert.False(t, ok)
+
+		_, ok = monitor.attributionRatio(cpuZones[0], Workload{Kind: ProcessWorkload, ID: "123", CPUTimeDelta: 1}, 0)
+		assert.False(t, ok, "cpu zones can't be attributed without cpu time")
+	})
+}
+
+func TestGPUPodShares(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	gpuZone := device.NewPowerZone("nvidia-gpu-0", 0, "nvml:GPU-0",
+		func() (Power, error) { return 100 * Watt, nil }, fakeClock)
+	// a GPU in MIG mode whose instances are allocated to pods
+	gpuMeter := &fakeGPUMeter{
+		zones: []EnergyZone{gpuZone},
+		util: map[int]gpu.Utilization{
+			0: {Device: 4.0 / 7, Pods: map[string]float64{"pod-1": 3.0 / 7, "pod-2": 1.0 / 7}},
+		},
+	}
+
+	resInformer := &MockResourceInformer{}
+	resInformer.On("Processes").Return(&resource.Processes{})
+	resInformer.On("Pods").Return(&resource.Pods{
+		Running:  map[string]*resource.Pod{"pod-1": {ID: "pod-1"}},
+		Filtered: map[string]*resource.Pod{"pod-2": {ID: "pod-2"}},
+	})
+
+	monitor := &PowerMonitor{
+		logger:    slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		gpus:      []gpu.PowerMeter{gpuMeter},
+		resources: resInformer,
+	}
+	monitor.initGPUZones()
+	monitor.refreshGPUUtilization()
+	monitor.computeGPUShares()
+
+	ratio, ok := monitor.attributionRatio(gpuZone, Workload{Kind: PodWorkload, ID: "pod-1"}, 0)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.75, ratio, 0.001)
+	ratio, ok = monitor.attributionRatio(gpuZone, Workload{Kind: PodWorkload, ID: "pod-2"}, 0)
+	assert.True(t, ok, "filtered pods count towards the shares")
+	assert.InDelta(t, 0.25, ratio, 0.001)
+
+	// pods whose devices are still allocated after they terminated get nothing
+	gpuMeter.util[0] = gpu.Utilization{Device: 1, Pods: map[string]float64{"pod-1": 0.5, "gone": 0.5}}
+	monitor.refreshGPUUtilization()
+	monitor.computeGPUShares()
+	ratio, _ = monitor.attributionRatio(gpuZone, Workload{Kind: PodWorkload, ID: "pod-1"}, 0)
+	assert.InDelta(t, 0.5, ratio, 0.001)
+	_, ok = monitor.attributionRatio(gpuZone, Workload{Kind: PodWorkload, ID: "gone"}, 0)
+	assert.False(t, ok)
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"maps"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// firstGroupRead initializes group power data for the first time
+func (pm *PowerMonitor) firstGroupRead(snapshot *Snapshot) error {
+	if !pm.groups {
+		return nil
+	}
+
+	running := pm.resources.Groups().Running
+	groups := make(Groups, len(running))
+
+	zones := snapshot.Node.Zones
+	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
+
+	for name, g := range running {
+		group := newGroup(g, zones)
+
+		// Calculate initial energy as the share of node active energy
+		pm.attributeZones(group.Zones, zones, Workload{Kind: GroupWorkload, ID: name, CPUTimeDelta: g.CPUTimeDelta}, nodeCPUTimeDelta, nil)
+
+		groups[name] = group
+	}
+	snapshot.Groups = groups
+
+	pm.logger.Debug("Initialized group power tracking",
+		"groups", len(groups))
+	return nil
+}
+
+// calculateGroupPower calculates power for each running group and handles
+// terminated groups
+func (pm *PowerMonitor) calculateGroupPower(prev, newSnapshot *Snapshot) error {
+	if !pm.groups {
+		return nil
+	}
+
+	// Release terminated workloads that are past retention (or exported)
+	pm.terminatedGroupsTracker.Prune(pm.exported.Load())
+
+	groups := pm.resources.Groups()
+
+	// Handle terminated groups; groups run until the refresh after their last
+	// process exits, so the previous snapshot holds their final energy
+	pm.logger.Debug("Processing terminated groups", "terminated", len(groups.Terminated))
+	for name := range groups.Terminated {
+		prevGroup, exists := prev.Groups[name]
+		if !exists {
+			continue
+		}
+
+		// Add to internal tracker (which will handle priority-based retention)
+		pm.terminatedGroupsTracker.Add(prevGroup.Clone())
+	}
+
+	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
+	pm.logger.Debug("Calculating group power",
+		"node.cpu.time", nodeCPUTimeDelta,
+		"running", len(groups.Running),
+	)
+
+	groupMap := make(Groups, len(groups.Running))
+
+	// For each group, calculate power for each zone separately
+	zones := newSnapshot.Node.Zones
+	for name, g := range groups.Running {
+		// Calculate share of each zone's active power and energy; energy
+		// accumulates over the previous snapshot
+		var prevZones ZoneUsageMap
+		prevGroup, exists := prev.Groups[name]
+		if exists {
+			prevZones = prevGroup.Zones
+		}
+		usage := pm.usageBuffer(zones)
+		pm.attributeZones(usage, zones, Workload{Kind: GroupWorkload, ID: name, CPUTimeDelta: g.CPUTimeDelta}, nodeCPUTimeDelta, prevZones)
+
+		// share unchanged groups with the previous snapshot (copy-on-write)
+		if exists && prevGroup.matches(g) && sameUsage(prevGroup.Zones, usage) {
+			groupMap[name] = prevGroup
+			continue
+		}
+
+		group := newGroup(g, zones)
+		maps.Copy(group.Zones, usage)
+		groupMap[name] = group
+	}
+
+	newSnapshot.Groups = groupMap
+
+	// Populate terminated groups from tracker
+	newSnapshot.TerminatedGroups = pm.terminatedGroupsTracker.Items()
+	pm.logger.Debug("snapshot updated for groups",
+		"running", len(newSnapshot.Groups),
+		"terminated", len(newSnapshot.TerminatedGroups),
+	)
+
+	return nil
+}
+
+// matches returns true if the group has the same attributes as g
+func (pg *Group) matches(g *resource.Group) bool {
+	return pg.Name == g.Name &&
+		pg.PID == g.PID &&
+		pg.Cgroup == g.Cgroup &&
+		pg.Processes == g.Processes &&
+		pg.CPUTotalTime == g.CPUTotalTime
+}
+
+// newGroup creates a new Group with zones initialized from resource.Group
+func newGroup(g *resource.Group, zones NodeZoneUsageMap) *Group {
+	group := &Group{
+		Name:         g.Name,
+		PID:          g.PID,
+		Cgroup:       g.Cgroup,
+		Processes:    g.Processes,
+		CPUTotalTime: g.CPUTotalTime,
+		Zones:        make(ZoneUsageMap, len(zones)),
+	}
+
+	// Initialize each zone with zero values
+	for zone := range zones {
+		group.Zones[zone] = Usage{
+			EnergyTotal: Energy(0),
+			Power:       Power(0),
+		}
+	}
+
+	return group
+}
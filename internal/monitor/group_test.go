@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestGroupPowerCalculation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	zones := CreateTestZones()
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(zones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(zones[0], nil)
+
+	resInformer := &MockResourceInformer{}
+	monitor := &PowerMonitor{
+		logger:        logger,
+		cpu:           mockMeter,
+		clock:         fakeClock,
+		resources:     resInformer,
+		maxTerminated: 500,
+		groups:        true,
+	}
+	require.NoError(t, monitor.Init())
+
+	tr := CreateTestResources(createOnly(testNode))
+	nodeCPUTimeDelta := tr.Node.ProcessTotalCPUTimeDelta
+	resInformer.On("Node").Return(tr.Node, nil)
+
+	const session = "/user.slice/user-1000.slice/session-3.scope"
+
+	t.Run("firstGroupRead", func(t *testing.T) {
+		groups := &resource.Groups{
+			Running: map[string]*resource.Group{
+				"build":   {Name: "build", PID: 42, Processes: 3, CPUTotalTime: 10, CPUTimeDelta: 0.4 * nodeCPUTimeDelta},
+				"session": {Name: "session", Cgroup: session, Processes: 1, CPUTotalTime: 20, CPUTimeDelta: 0.1 * nodeCPUTimeDelta},
+			},
+			Terminated: map[string]*resource.Group{},
+		}
+		resInformer.On("Groups").Return(groups).Once()
+
+		snapshot := NewSnapshot()
+		snapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		require.NoError(t, monitor.firstGroupRead(snapshot))
+
+		require.Len(t, snapshot.Groups, 2)
+		build := snapshot.Groups["build"]
+		assert.Equal(t, 42, build.PID)
+		assert.Equal(t, 3, build.Processes)
+		assert.Equal(t, 10.0, build.CPUTotalTime)
+		assert.Equal(t, session, snapshot.Groups["session"].Cgroup)
+		for _, zone := range zones {
+			expected := Energy(0.4 * float64(snapshot.Node.Zones[zone].activeEnergy))
+			assert.Equal(t, expected, build.Zones[zone].EnergyTotal)
+		}
+	})
+
+	t.Run("calculateGroupPower", func(t *testing.T) {
+		prevSnapshot := NewSnapshot()
+		prevSnapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		for _, name := range []string{"build", "test"} {
+			prevSnapshot.Groups[name] = &Group{
+				Name:  name,
+				PID:   42,
+				Zones: make(ZoneUsageMap, len(zones)),
+			}
+			for _, zone := range zones {
+				prevSnapshot.Groups[name].Zones[zone] = Usage{EnergyTotal: 25 * Joule}
+			}
+		}
+
+		// the processes of test exited before the previous refresh
+		groups := &resource.Groups{
+			Running: map[string]*resource.Group{
+				"build": {Name: "build", PID: 42, Processes: 4, CPUTotalTime: 50, CPUTimeDelta: 0.5 * nodeCPUTimeDelta},
+			},
+			Terminated: map[string]*resource.Group{
+				"test": {Name: "test", PID: 43},
+			},
+		}
+		resInformer.On("Groups").Return(groups).Once()
+
+		fakeClock.Step(2 * time.Second)
+		newSnapshot := NewSnapshot()
+		newSnapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		require.NoError(t, monitor.calculateGroupPower(prevSnapshot, newSnapshot))
+
+		require.Len(t, newSnapshot.Groups, 1)
+		build := newSnapshot.Groups["build"]
+		assert.Equal(t, 4, build.Processes)
+		for _, zone := range zones {
+			nodeZone := newSnapshot.Node.Zones[zone]
+			assert.Equal(t, 25*Joule+Energy(0.5*float64(nodeZone.activeEnergy)), build.Zones[zone].EnergyTotal)
+			assert.Equal(t, Power(0.5*nodeZone.ActivePower.MicroWatts()), build.Zones[zone].Power)
+		}
+
+		require.Len(t, newSnapshot.TerminatedGroups, 1)
+		assert.Equal(t, 25*Joule, newSnapshot.TerminatedGroups["test"].Zones[zones[0]].EnergyTotal)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := &PowerMonitor{logger: logger, resources: resInformer}
+
+		prevSnapshot := NewSnapshot()
+		newSnapshot := NewSnapshot()
+		require.NoError(t, disabled.firstGroupRead(newSnapshot))
+		require.NoError(t, disabled.calculateGroupPower(prevSnapshot, newSnapshot))
+		assert.Empty(t, newSnapshot.Groups)
+		resInformer.AssertNumberOfCalls(t, "Groups", 2)
+	})
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"strconv"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// IdlePolicy controls how the idle power of the node is attributed to workloads
+type IdlePolicy int
+
+const (
+	// IdleExcluded attributes no idle power to workloads
+	IdleExcluded IdlePolicy = iota
+
+	// IdleEven spreads idle power evenly across running workloads of each kind
+	IdleEven
+
+	// IdleByRequests distributes idle power across pods in proportion to their
+	// CPU requests (or limits when pods have no requests); other workloads get
+	// an even share
+	IdleByRequests
+)
+
+func (p IdlePolicy) String() string {
+	switch p {
+	case IdleExcluded:
+		return "exclude"
+	case IdleEven:
+		return "even"
+	case IdleByRequests:
+		return "requests"
+	default:
+		return "unknown"
+	}
+}
+
+// idleRatio returns the share of the node's idle energy attributable to a workload
+func (pm *PowerMonitor) idleRatio(w Workload) float64 {
+	if pm.idlePolicy == IdleExcluded {
+		return 0
+	}
+	return pm.idleShares[w.Kind][w.ID]
+}
+
+// computeIdleShares computes the share of the node's idle energy attributable to
+// each running workload as per the idle policy
+func (pm *PowerMonitor) computeIdleShares() {
+	if pm.idlePolicy == IdleExcluded {
+		return
+	}
+
+	pm.idleShares = workloadShares{
+		ProcessWorkload:   evenShares(pm.resources.Processes().Running, strconv.Itoa),
+		ContainerWorkload: evenShares(pm.resources.Containers().Running, idString),
+		VMWorkload:        evenShares(pm.resources.VirtualMachines().Running, idString),
+		PodWorkload:       pm.podIdleShares(pm.resources.Pods().Running),
+	}
+	if pm.systemdUnits {
+		pm.idleShares[SystemdUnitWorkload] = evenShares(pm.resources.SystemdUnits().Running, idString)
+	}
+	if pm.groups {
+		pm.idleShares[GroupWorkload] = evenShares(pm.resources.Groups().Running, idString)
+	}
+}
+
+// podIdleShares returns the idle shares of pods; with IdleByRequests, pods without
+// requests or limits get no idle power unless no pod has any
+func (pm *PowerMonitor) podIdleShares(pods map[string]*resource.Pod) map[string]float64 {
+	if pm.idlePolicy != IdleByRequests {
+		return evenShares(pods, idString)
+	}
+
+	weights := make(map[string]float64, len(pods))
+	total := 0.0
+	for id, pod := range pods {
+		weight := pod.CPURequest
+		if weight == 0 {
+			weight = pod.CPULimit
+		}
+		weights[id] = weight
+		total += weight
+	}
+	if total == 0 {
+		return evenShares(pods, idString)
+	}
+
+	for id := range weights {
+		weights[id] /= total
+	}
+	return weights
+}
+
+// evenShares returns an equal share for each running workload
+func evenShares[K comparable, V any](running map[K]V, id func(K) string) map[string]float64 {
+	shares := make(map[string]float64, len(running))
+	for k := range running {
+		shares[id(k)] = 1 / float64(len(running))
+	}
+	return shares
+}
+
+func idString(id string) string {
+	return id
+}
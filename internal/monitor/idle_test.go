@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+func idleTestInformer() *MockResourceInformer {
+	resInformer := &MockResourceInformer{}
+	resInformer.On("Processes").Return(&resource.Processes{
+		Running: map[int]*resource.Process{1: {PID: 1}, 2: {PID: 2}, 3: {PID: 3}, 4: {PID: 4}},
+	})
+	resInformer.On("Containers").Return(&resource.Containers{
+		Running: map[string]*resource.Container{"c1": {ID: "c1"}, "c2": {ID: "c2"}},
+	})
+	resInformer.On("VirtualMachines").Return(&resource.VirtualMachines{
+		Running: map[string]*resource.VirtualMachine{},
+	})
+	resInformer.On("Pods").Return(&resource.Pods{
+		Running: map[string]*resource.Pod{
+			"p1": {ID: "p1", CPURequest: 1.5},
+			"p2": {ID: "p2", CPURequest: 0, CPULimit: 0.5},
+			"p3": {ID: "p3"},
+		},
+	})
+	return resInformer
+}
+
+func TestIdleShares(t *testing.T) {
+	t.Run("exclude", func(t *testing.T) {
+		pm := &PowerMonitor{resources: &MockResourceInformer{}}
+		pm.computeIdleShares()
+		assert.Zero(t, pm.idleRatio(Workload{Kind: ProcessWorkload, ID: "1"}))
+	})
+
+	t.Run("even", func(t *testing.T) {
+		pm := &PowerMonitor{resources: idleTestInformer(), idlePolicy: IdleEven}
+		pm.computeIdleShares()
+
+		assert.InDelta(t, 0.25, pm.idleRatio(Workload{Kind: ProcessWorkload, ID: "1"}), 0.0001)
+		assert.InDelta(t, 0.5, pm.idleRatio(Workload{Kind: ContainerWorkload, ID: "c2"}), 0.0001)
+		assert.InDelta(t, 1.0/3, pm.idleRatio(Workload{Kind: PodWorkload, ID: "p1"}), 0.0001)
+		assert.Zero(t, pm.idleRatio(Workload{Kind: VMWorkload, ID: "vm"}))
+	})
+
+	t.Run("requests", func(t *testing.T) {
+		pm := &PowerMonitor{resources: idleTestInformer(), idlePolicy: IdleByRequests}
+		pm.computeIdleShares()
+
+		assert.InDelta(t, 0.75, pm.idleRatio(Workload{Kind: PodWorkload, ID: "p1"}), 0.0001)
+		assert.InDelta(t, 0.25, pm.idleRatio(Workload{Kind: PodWorkload, ID: "p2"}), 0.0001, "limits are used without requests")
+		assert.Zero(t, pm.idleRatio(Workload{Kind: PodWorkload, ID: "p3"}))
+
+		// other workloads are spread evenly
+		assert.InDelta(t, 0.25, pm.idleRatio(Workload{Kind: ProcessWorkload, ID: "4"}), 0.0001)
+	})
+}
+
+func TestAttributeZonesWithIdle(t *testing.T) {
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+	zones := NodeZoneUsageMap{
+		pkg: {
+			ActivePower: 40 * Watt, activeEnergy: 400 * Joule,
+			IdlePower: 20 * Watt, idleEnergy: 200 * Joule,
+		},
+	}
+
+	pm := &PowerMonitor{resources: idleTestInformer(), idlePolicy: IdleEven}
+	pm.computeIdleShares()
+
+	usage := ZoneUsageMap{}
+	pm.attributeZones(usage, zones, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 1}, 4, nil)
+	// 1/4 of active and 1/4 of idle
+	assert.Equal(t, 15*Watt, usage[pkg].Power)
+	assert.Equal(t, 10*Watt, usage[pkg].ActivePower)
+	assert.Equal(t, 5*Watt, usage[pkg].IdlePower)
+	assert.Equal(t, 150*Joule, usage[pkg].EnergyTotal)
+
+	// workloads without cpu time still get their share of idle power
+	usage = ZoneUsageMap{}
+	pm.attributeZones(usage, zones, Workload{Kind: ProcessWorkload, ID: "2"}, 0, nil)
+	assert.Equal(t, 5*Watt, usage[pkg].Power)
+	assert.Zero(t, usage[pkg].ActivePower)
+	assert.Equal(t, 5*Watt, usage[pkg].IdlePower)
+	assert.Equal(t, 50*Joule, usage[pkg].EnergyTotal)
+}
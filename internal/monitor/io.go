@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	"k8s.io/utils/clock"
+)
+
+// names of the zones of the network interfaces and block devices of the node
+const (
+	NetworkZone = "network"
+	StorageZone = "storage"
+)
+
+// ioZone is the zone of the network interfaces or block devices of the node,
+// whose energy is estimated from and attributed by the bytes processes
+// transfer over the network or to and from disk
+type ioZone struct {
+	zone    *device.IOZone
+	network bool
+}
+
+// bytes returns the bytes transferred by the devices of the zone in c
+func (z ioZone) bytes(c resource.IOCounters) uint64 {
+	if z.network {
+		return c.Network()
+	}
+	return c.Disk()
+}
+
+// newIOZones returns the I/O zones of the models that are set
+func newIOZones(network, storage device.IOModel, c clock.PassiveClock) (map[EnergyZone]ioZone, []EnergyZone) {
+	zones := map[EnergyZone]ioZone{}
+	var list []EnergyZone
+	if !network.IsZero() {
+		z := device.NewIOZone(NetworkZone, network, c)
+		zones[z] = ioZone{zone: z, network: true}
+		list = append(list, z)
+	}
+	if !storage.IsZero() {
+		z := device.NewIOZone(StorageZone, storage, c)
+		zones[z] = ioZone{zone: z}
+		list = append(list, z)
+	}
+	return zones, list
+}
+
+// refreshIOZones adds the bytes transferred by all processes since the last
+// refresh to the I/O zones; their energy is estimated on the next read
+func (pm *PowerMonitor) refreshIOZones() {
+	if len(pm.ioZones) == 0 {
+		return
+	}
+
+	io := pm.resources.Node().IODelta
+	for _, z := range pm.ioZones {
+		z.zone.Transfer(z.bytes(io))
+	}
+}
+
+// computeIOShares computes the share of each I/O zone's active energy
+// attributable to workloads based on the bytes their processes transferred,
+// including processes that exited since the last refresh. Containers, VMs and
+// pods get the sum of the shares of their processes.
+func (pm *PowerMonitor) computeIOShares() {
+	if len(pm.ioZones) == 0 {
+		return
+	}
+
+	procs := pm.resources.Processes()
+	running := runningProcesses(procs)
+	pm.ioShares = make(map[EnergyZone]workloadShares, len(pm.ioZones))
+
+	for zone, z := range pm.ioZones {
+		shares := newWorkloadShares()
+		pm.ioShares[zone] = shares
+
+		total := uint64(0)
+		for _, proc := range running {
+			total += z.bytes(proc.IODelta)
+		}
+		for _, proc := range procs.Terminated {
+			total += z.bytes(proc.IODelta)
+		}
+		if total == 0 {
+			continue
+		}
+
+		for _, set := range []map[int]*resource.Process{running, procs.Terminated} {
+			for _, proc := range set {
+				if bytes := z.bytes(proc.IODelta); bytes > 0 {
+					shares.add(proc, float64(bytes)/float64(total))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestNewIOZones(t *testing.T) {
+	zones, list := newIOZones(device.IOModel{}, device.IOModel{}, nil)
+	assert.Empty(t, zones)
+	assert.Empty(t, list)
+
+	zones, list = newIOZones(device.IOModel{}, device.IOModel{IdleWatts: 2}, nil)
+	require.Len(t, list, 1)
+	assert.Equal(t, StorageZone, list[0].Name())
+	assert.False(t, zones[list[0]].network)
+
+	_, list = newIOZones(device.IOModel{WattsPerGBps: 1}, device.IOModel{IdleWatts: 2}, nil)
+	require.Len(t, list, 2)
+	assert.Equal(t, NetworkZone, list[0].Name())
+	assert.Equal(t, StorageZone, list[1].Name())
+}
+
+func TestIOPowerAttribution(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	cpuZones := CreateTestZones()
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(cpuZones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(cpuZones[0], nil)
+
+	pod := &resource.Pod{ID: "pod-1", Name: "pod", Namespace: "default"}
+	cntr := &resource.Container{ID: "container-1", Name: "container", Pod: pod}
+	procs := &resource.Processes{
+		Running: map[int]*resource.Process{
+			123: {PID: 123, Comm: "server", Container: cntr, CPUTimeDelta: 1,
+				IODelta: resource.IOCounters{NetRxBytes: 3e9, NetTxBytes: 3e9}},
+			456: {PID: 456, Comm: "backup", CPUTimeDelta: 1,
+				IODelta: resource.IOCounters{DiskWriteBytes: 2e9}},
+			789: {PID: 789, Comm: "idle", CPUTimeDelta: 2},
+		},
+		Terminated: map[int]*resource.Process{
+			321: {PID: 321, Comm: "curl", IODelta: resource.IOCounters{NetRxBytes: 2e9}},
+		},
+	}
+	node := &resource.Node{CPUUsageRatio: 0.5, ProcessTotalCPUTimeDelta: 4,
+		IODelta: resource.IOCounters{DiskWriteBytes: 2e9, NetRxBytes: 5e9, NetTxBytes: 3e9}}
+
+	resInformer := &MockResourceInformer{}
+	resInformer.On("Node").Return(node, nil)
+	resInformer.On("Processes").Return(procs)
+
+	monitor := &PowerMonitor{
+		logger:        logger,
+		cpu:           mockMeter,
+		clock:         fakeClock,
+		resources:     resInformer,
+		maxTerminated: 500,
+	}
+	monitor.ioZones, monitor.ioZoneList = newIOZones(
+		device.IOModel{IdleWatts: 4, WattsPerGBps: 10},
+		device.IOModel{IdleWatts: 2, WattsPerGBps: 5}, fakeClock)
+	require.NoError(t, monitor.Init())
+
+	assert.Equal(t, []string{"package-0", "core-0", NetworkZone, StorageZone}, monitor.ZoneNames())
+	network, storage := monitor.ioZoneList[0], monitor.ioZoneList[1]
+
+	prev := NewSnapshot()
+	require.NoError(t, monitor.firstNodeRead(prev.Node))
+	prev.Processes["321"] = &Process{PID: 321, Comm: "curl", Zones: ZoneUsageMap{}}
+
+	monitor.refreshIOZones()
+	monitor.computeIOShares()
+
+	fakeClock.Step(10 * time.Second)
+	newSnapshot := NewSnapshot()
+	require.NoError(t, monitor.calculateNodePower(prev.Node, newSnapshot.Node))
+
+	// 4W idle and 8 GB at 10 J/GB over 10s
+	networkUsage := newSnapshot.Node.Zones[network]
+	assert.InDelta(t, 12.0, networkUsage.Power.Watts(), 0.001)
+	assert.InDelta(t, 8.0, networkUsage.ActivePower.Watts(), 0.001)
+	assert.InDelta(t, 4.0, networkUsage.IdlePower.Watts(), 0.001)
+
+	// 2W idle and 2 GB at 5 J/GB over 10s
+	storageUsage := newSnapshot.Node.Zones[storage]
+	assert.InDelta(t, 3.0, storageUsage.Power.Watts(), 0.001)
+	assert.InDelta(t, 1.0, storageUsage.ActivePower.Watts(), 0.001)
+
+	// the power of I/O zones is estimated, while that of CPU zones is measured
+	assert.Equal(t, device.DefaultUncertainty, networkUsage.Uncertainty)
+	assert.Zero(t, newSnapshot.Node.Zones[cpuZones[0]].Uncertainty)
+
+	require.NoError(t, monitor.calculateProcessPower(prev, newSnapshot))
+
+	// I/O zones are attributed by the bytes transferred over the network or disk
+	assert.InDelta(t, 6.0, newSnapshot.Processes["123"].Zones[network].Power.Watts(), 0.001)
+	assert.Equal(t, device.DefaultUncertainty, newSnapshot.Processes["123"].Zones[network].Uncertainty)
+	assert.Zero(t, newSnapshot.Processes["123"].Zones[storage].Power)
+	assert.InDelta(t, 1.0, newSnapshot.Processes["456"].Zones[storage].Power.Watts(), 0.001)
+	assert.Zero(t, newSnapshot.Processes["789"].Zones[network].Power)
+
+	// including the bytes of processes that exited since the last refresh
+	require.Len(t, newSnapshot.TerminatedProcesses, 1)
+	terminated := newSnapshot.TerminatedProcesses["321"]
+	require.NotNil(t, terminated)
+	assert.Equal(t, uint64(2e9), terminated.IO.NetRxBytes)
+	assert.InDelta(t, 20.0, terminated.Zones[network].EnergyTotal.Joules(), 0.001)
+
+	// CPU zones continue to be attributed by CPU time
+	for _, zone := range cpuZones {
+		active := newSnapshot.Node.Zones[zone].ActivePower
+		assert.InDelta(t, active.Watts()/2, newSnapshot.Processes["789"].Zones[zone].Power.Watts(), 0.001)
+	}
+
+	ratio, ok := monitor.attributionRatio(network, Workload{Kind: PodWorkload, ID: "pod-1"}, 4)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.75, ratio, 0.001)
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"strings"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// isMemoryZone returns true for zones whose power is driven by memory activity
+// rather than CPU activity; i.e. dram and uncore (LLC, memory controller)
+func isMemoryZone(zone EnergyZone) bool {
+	name := strings.ToLower(zone.Name())
+	return strings.HasPrefix(name, "dram") || strings.HasPrefix(name, "uncore")
+}
+
+// memoryShares returns the share of each workload in the resident memory of all
+// running processes. Containers, VMs and pods get the sum of the shares of their
+// processes.
+func memoryShares(procs *resource.Processes) workloadShares {
+	shares := newWorkloadShares()
+	running := runningProcesses(procs)
+
+	total := uint64(0)
+	for _, proc := range running {
+		total += proc.ResidentMemory
+	}
+	if total == 0 {
+		return shares
+	}
+
+	for _, proc := range running {
+		if proc.ResidentMemory == 0 {
+			continue
+		}
+		shares.add(proc, float64(proc.ResidentMemory)/float64(total))
+	}
+	return shares
+}
+
+// cpuMemoryAttribution attributes memory zones (dram, uncore) by the resident
+// memory of workloads and all other zones by their CPU time
+type cpuMemoryAttribution struct {
+	memory workloadShares
+}
+
+// NewCPUMemoryAttribution returns an Attribution that attributes the energy of
+// memory zones (dram, uncore) in proportion to the resident memory of workloads
+// and all other zones in proportion to their CPU time. The resource informer must
+// track memory for memory zones to be attributed.
+func NewCPUMemoryAttribution() Attribution {
+	return &cpuMemoryAttribution{}
+}
+
+func (a *cpuMemoryAttribution) Name() string {
+	return "cpu-memory"
+}
+
+func (a *cpuMemoryAttribution) Update(procs *resource.Processes) {
+	a.memory = memoryShares(procs)
+}
+
+func (a *cpuMemoryAttribution) Ratio(zone EnergyZone, w Workload, nodeCPUTimeDelta float64) (float64, bool) {
+	if isMemoryZone(zone) {
+		return a.memory.ratio(w)
+	}
+	return cpuTimeAttribution{}.Ratio(zone, w, nodeCPUTimeDelta)
+}
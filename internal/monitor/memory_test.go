@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+func TestIsMemoryZone(t *testing.T) {
+	tt := []struct {
+		name     string
+		expected bool
+	}{
+		{"dram", true},
+		{"dram-0", true},
+		{"uncore", true},
+		{"package-0", false},
+		{"core", false},
+		{"psys", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			zone := device.NewMockRaplZone(tc.name, 0, "", 1000*Joule)
+			assert.Equal(t, tc.expected, isMemoryZone(zone))
+		})
+	}
+}
+
+func TestCPUMemoryAttribution(t *testing.T) {
+	pod := &resource.Pod{ID: "pod-1"}
+	cntr := &resource.Container{ID: "container-1", Pod: pod}
+	vm := &resource.VirtualMachine{ID: "vm-1"}
+	procs := &resource.Processes{
+		Running: map[int]*resource.Process{
+			1: {PID: 1, Container: cntr, ResidentMemory: 300, CPUTimeDelta: 1},
+			2: {PID: 2, Container: cntr, ResidentMemory: 100, CPUTimeDelta: 1},
+			3: {PID: 3, VirtualMachine: vm, ResidentMemory: 600, CPUTimeDelta: 2},
+		},
+	}
+	resInformer := &MockResourceInformer{}
+	resInformer.On("Processes").Return(procs)
+
+	dram := device.NewMockRaplZone("dram", 0, "", 1000*Joule)
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+
+	pm := &PowerMonitor{resources: resInformer, attribution: NewCPUMemoryAttribution()}
+	assert.Equal(t, "cpu-memory", pm.attribution.Name())
+	pm.updateAttribution()
+
+	tt := []struct {
+		name     string
+		zone     EnergyZone
+		workload Workload
+		expected float64
+	}{
+		{"process", dram, Workload{Kind: ProcessWorkload, ID: "3", CPUTimeDelta: 2}, 0.6},
+		{"container", dram, Workload{Kind: ContainerWorkload, ID: "container-1", CPUTimeDelta: 2}, 0.4},
+		{"pod", dram, Workload{Kind: PodWorkload, ID: "pod-1", CPUTimeDelta: 2}, 0.4},
+		{"vm", dram, Workload{Kind: VMWorkload, ID: "vm-1", CPUTimeDelta: 2}, 0.6},
+		// non memory zones continue to use cpu time
+		{"cpu zone", pkg, Workload{Kind: ProcessWorkload, ID: "3", CPUTimeDelta: 2}, 0.5},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ratio, ok := pm.attributionRatio(tc.zone, tc.workload, 4)
+			assert.True(t, ok)
+			assert.InDelta(t, tc.expected, ratio, 0.0001)
+		})
+	}
+
+	t.Run("without memory", func(t *testing.T) {
+		pm.attribution.Update(&resource.Processes{})
+		_, ok := pm.attributionRatio(dram, Workload{Kind: ProcessWorkload, ID: "3", CPUTimeDelta: 2}, 4)
+		assert.False(t, ok)
+	})
+}
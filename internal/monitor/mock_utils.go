@@ -5,6 +5,7 @@ package monitor
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -72,7 +73,7 @@ type MockResourceInformer struct {
 	mock.Mock
 }
 
-func (m *MockResourceInformer) SetExpectations(t *testing.T, tr *TestResource) {
+func (m *MockResourceInformer) SetExpectations(t testing.TB, tr *TestResource) {
 	t.Helper()
 	if tr.Node != nil {
 		m.On("Node").Return(tr.Node, nil)
@@ -134,6 +135,16 @@ func (m *MockResourceInformer) Pods() *resource.Pods {
 	return args.Get(0).(*resource.Pods)
 }
 
+func (m *MockResourceInformer) SystemdUnits() *resource.SystemdUnits {
+	args := m.Called()
+	return args.Get(0).(*resource.SystemdUnits)
+}
+
+func (m *MockResourceInformer) Groups() *resource.Groups {
+	args := m.Called()
+	return args.Get(0).(*resource.Groups)
+}
+
 var _ resource.Informer = (*MockResourceInformer)(nil)
 
 // Helper functions for creating test data
@@ -389,3 +400,54 @@ func CreateTestResources(opts ...resOptFn) *TestResource {
 
 	return &TestResource{node, processes, containers, vms, pods}
 }
+
+// FakeMonitor is a PowerDataProvider returning the snapshot set by tests
+type FakeMonitor struct {
+	mu       sync.Mutex
+	snapshot *Snapshot
+	err      error
+	calls    int
+}
+
+var _ PowerDataProvider = (*FakeMonitor)(nil)
+
+// NewFakeMonitor creates a new FakeMonitor returning snapshot
+func NewFakeMonitor(snapshot *Snapshot) *FakeMonitor {
+	return &FakeMonitor{snapshot: snapshot}
+}
+
+func (m *FakeMonitor) Name() string                 { return "monitor" }
+func (m *FakeMonitor) DataChannel() <-chan struct{} { return nil }
+func (m *FakeMonitor) ZoneNames() []string          { return nil }
+
+func (m *FakeMonitor) Snapshot() (*Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	return m.snapshot, m.err
+}
+
+func (m *FakeMonitor) LatestSnapshot() (*Snapshot, error) {
+	return m.Snapshot()
+}
+
+// Set sets the snapshot returned from now on
+func (m *FakeMonitor) Set(snapshot *Snapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot = snapshot
+}
+
+// SetError sets the error returned with the snapshot from now on
+func (m *FakeMonitor) SetError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// SnapshotCalls returns the number of snapshots taken
+func (m *FakeMonitor) SnapshotCalls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
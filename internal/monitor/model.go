@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"strconv"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// modelAttribution estimates the power of each process with a linear model over
+// its share of the node's CPU time, resident memory and instructions, and
+// attributes the energy of all zones in proportion to the estimates
+type modelAttribution struct {
+	cpuWeight          float64
+	memoryWeight       float64
+	instructionsWeight float64
+
+	shares workloadShares
+}
+
+// NewModelAttribution returns an Attribution that estimates the power of each
+// process as cpuWeight * (CPU time share) + memoryWeight * (resident memory share)
+// + instructionsWeight * (instructions share) and attributes the energy of all
+// zones in proportion to the estimates. Instructions are only counted if the
+// resource informer counts perf events. Weights must not be negative and at
+// least one must be positive.
+func NewModelAttribution(cpuWeight, memoryWeight, instructionsWeight float64) Attribution {
+	return &modelAttribution{
+		cpuWeight:          cpuWeight,
+		memoryWeight:       memoryWeight,
+		instructionsWeight: instructionsWeight,
+	}
+}
+
+func (a *modelAttribution) Name() string {
+	return "model"
+}
+
+func (a *modelAttribution) Update(procs *resource.Processes) {
+	a.shares = newWorkloadShares()
+
+	running := runningProcesses(procs)
+	totalCPU, totalInstructions := 0.0, 0.0
+	for _, proc := range running {
+		totalCPU += proc.CPUTimeDelta
+		totalInstructions += float64(proc.HWCountersDelta.Instructions)
+	}
+	memory := memoryShares(procs)
+
+	// estimates are normalized so that the shares of all processes add up to 1
+	// even if there is no CPU, memory or instructions to attribute by
+	cpuWeight, memoryWeight, instructionsWeight := a.cpuWeight, a.memoryWeight, a.instructionsWeight
+	if totalCPU == 0 {
+		cpuWeight = 0
+	}
+	if len(memory[ProcessWorkload]) == 0 {
+		memoryWeight = 0
+	}
+	if totalInstructions == 0 {
+		instructionsWeight = 0
+	}
+	total := cpuWeight + memoryWeight + instructionsWeight
+	if total == 0 {
+		return
+	}
+
+	for _, proc := range running {
+		estimate := memoryWeight * memory[ProcessWorkload][strconv.Itoa(proc.PID)]
+		if cpuWeight > 0 {
+			estimate += cpuWeight * proc.CPUTimeDelta / totalCPU
+		}
+		if instructionsWeight > 0 {
+			estimate += instructionsWeight * float64(proc.HWCountersDelta.Instructions) / totalInstructions
+		}
+		if estimate > 0 {
+			a.shares.add(proc, estimate/total)
+		}
+	}
+}
+
+func (a *modelAttribution) Ratio(_ EnergyZone, w Workload, _ float64) (float64, bool) {
+	return a.shares.ratio(w)
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+func TestModelAttribution(t *testing.T) {
+	cntr := &resource.Container{ID: "container-1"}
+	procs := &resource.Processes{
+		Running: map[int]*resource.Process{
+			1: {PID: 1, Container: cntr, ResidentMemory: 100, CPUTimeDelta: 3},
+			2: {PID: 2, Container: cntr, ResidentMemory: 300, CPUTimeDelta: 0},
+			3: {PID: 3, ResidentMemory: 0, CPUTimeDelta: 1},
+		},
+	}
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+	dram := device.NewMockRaplZone("dram", 0, "", 1000*Joule)
+
+	t.Run("cpu and memory", func(t *testing.T) {
+		a := NewModelAttribution(0.5, 0.5, 0)
+		assert.Equal(t, "model", a.Name())
+		a.Update(procs)
+
+		// 0.5 * 3/4 (cpu) + 0.5 * 1/4 (memory)
+		ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "1"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.5, ratio, 0.0001)
+
+		// all zones use the same model
+		ratio, ok = a.Ratio(dram, Workload{Kind: ProcessWorkload, ID: "2"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.375, ratio, 0.0001)
+
+		ratio, ok = a.Ratio(pkg, Workload{Kind: ContainerWorkload, ID: "container-1"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.875, ratio, 0.0001)
+
+		ratio, ok = a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "3"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.125, ratio, 0.0001)
+	})
+
+	t.Run("cpu only", func(t *testing.T) {
+		a := NewModelAttribution(1, 0, 0)
+		a.Update(procs)
+
+		_, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "2"}, 4)
+		assert.False(t, ok)
+
+		ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "1"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.75, ratio, 0.0001)
+	})
+
+	t.Run("no memory tracked", func(t *testing.T) {
+		a := NewModelAttribution(0.5, 0.5, 0)
+		a.Update(&resource.Processes{
+			Running: map[int]*resource.Process{
+				1: {PID: 1, CPUTimeDelta: 1},
+				2: {PID: 2, CPUTimeDelta: 3},
+			},
+		})
+
+		// weights are renormalized when memory isn't available
+		ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "2"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.75, ratio, 0.0001)
+	})
+
+	t.Run("cpu and instructions", func(t *testing.T) {
+		a := NewModelAttribution(0.5, 0, 0.5)
+		a.Update(&resource.Processes{
+			Running: map[int]*resource.Process{
+				1: {PID: 1, CPUTimeDelta: 1, HWCountersDelta: resource.HWCounters{Instructions: 3000}},
+				2: {PID: 2, CPUTimeDelta: 1, HWCountersDelta: resource.HWCounters{Instructions: 1000}},
+			},
+		})
+
+		// 0.5 * 1/2 (cpu) + 0.5 * 3/4 (instructions)
+		ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "1"}, 2)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.625, ratio, 0.0001)
+	})
+
+	t.Run("no instructions counted", func(t *testing.T) {
+		a := NewModelAttribution(0.5, 0, 0.5)
+		a.Update(&resource.Processes{
+			Running: map[int]*resource.Process{
+				1: {PID: 1, CPUTimeDelta: 1},
+				2: {PID: 2, CPUTimeDelta: 3},
+			},
+		})
+
+		ratio, ok := a.Ratio(pkg, Workload{Kind: ProcessWorkload, ID: "2"}, 4)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.75, ratio, 0.0001)
+	})
+}
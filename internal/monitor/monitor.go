@@ -7,10 +7,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/sustainable-computing-io/kepler/internal/carbon"
 	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/device/gpu"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/pricing"
 	"github.com/sustainable-computing-io/kepler/internal/resource"
 	"github.com/sustainable-computing-io/kepler/internal/service"
 	"golang.org/x/sync/singleflight"
@@ -18,9 +23,16 @@ import (
 )
 
 type PowerDataProvider interface {
-	// Snapshot returns the current power data
+	// Snapshot returns the current power data and marks it exported, so that
+	// the workloads terminated are cleared in the next collection; it is meant
+	// for exporters
 	Snapshot() (*Snapshot, error)
 
+	// LatestSnapshot returns the power data last collected, without refreshing
+	// it or marking it exported; it is meant for consumers that observe the
+	// power, e.g. the history, and must not be modified
+	LatestSnapshot() (*Snapshot, error)
+
 	// DataChannel returns a channel that signals when new data is available
 	DataChannel() <-chan struct{}
 
@@ -39,9 +51,25 @@ type PowerMonitor struct {
 	// passed externally
 	logger *slog.Logger
 	cpu    device.CPUPowerMeter
-
-	interval time.Duration
-	clock    clock.WithTicker
+	gpus   []gpu.PowerMeter
+
+	// interval can be changed at runtime by SetInterval, so it is read
+	// through collectionInterval
+	intervalMu sync.RWMutex
+	interval   time.Duration
+	clock      clock.WithTicker
+
+	// collection backs off up to maxInterval while the node power is below
+	// idleThreshold; currentInterval, a time.Duration, is only set by the
+	// collection loop and is read by isFresh
+	maxInterval     time.Duration
+	idleThreshold   Power
+	currentInterval atomic.Int64
+
+	// sampler tracks the min and max power of zones within the interval by
+	// sampling them every sampleInterval; nil if sampling is disabled
+	sampleInterval time.Duration
+	sampler        *powerSampler
 
 	// related to snapshots
 	maxStaleness time.Duration
@@ -49,6 +77,7 @@ type PowerMonitor struct {
 	// related to terminated resource tracking
 	maxTerminated                int
 	minTerminatedEnergyThreshold Energy
+	maxTerminatedAge             time.Duration
 
 	resources resource.Informer
 
@@ -69,18 +98,121 @@ type PowerMonitor struct {
 
 	zonesNames []string // cache of all zones
 
+	// GPU zones and their utilization; updated on every refresh
+	gpuZones       map[EnergyZone]gpuZone
+	gpuZoneList    []EnergyZone // GPU zones in the order reported by meters
+	gpuUtilization map[EnergyZone]gpu.Utilization
+	gpuShares      map[EnergyZone]workloadShares
+
+	// I/O zones estimated from the bytes transferred by processes, and their
+	// shares; updated on every refresh
+	ioZones    map[EnergyZone]ioZone
+	ioZoneList []EnergyZone // network zone followed by storage zone
+	ioShares   map[EnergyZone]workloadShares
+
+	// zone of the whole platform read from the BMC, attributed to VMs; nil if
+	// the platform is not monitored
+	platform       EnergyZone
+	platformShares workloadShares
+
+	// calibrations correct the energy read from zones before it is split and
+	// attributed; zones not listed are not corrected
+	calibrations Calibrations
+
+	// attribution decides the share of zones' active energy attributed to
+	// workloads; nil attributes by CPU time
+	attribution Attribution
+
+	// idlePolicy controls how node idle power is attributed to workloads
+	idlePolicy IdlePolicy
+	idleShares workloadShares
+
+	// cpuSockets maps CPUs to their socket; used to attribute per-socket zones
+	cpuSockets   map[int]int
+	socketShares map[int]workloadShares
+
+	// carbon provides the carbon intensity used to compute emissions; nil
+	// disables emissions. carbonIntensity is updated on every refresh
+	carbon          carbon.Provider
+	carbonIntensity carbon.Intensity
+
+	// tariff provides the price of electricity used to compute cost; nil
+	// disables cost. price is updated on every refresh
+	tariff *pricing.Tariff
+	price  pricing.Price
+
+	// budgets tracks the daily energy consumption against budgets; nil if no
+	// budgets are set
+	budgets         *budgetTracker
+	budgetNotifiers []BudgetNotifier
+
+	// events is the bus events are published on; nil if disabled. The events
+	// of a refresh are queued in pendingEvents and published once its
+	// snapshot is stored
+	events        *event.Bus
+	pendingEvents []event.Event
+
+	// usageBuf is reused to compute the usage of workloads; see usageBuffer
+	usageBuf ZoneUsageMap
+
+	// sources tracks the availability of zones, GPU utilization and the carbon
+	// intensity
+	sources sourceTracker
+
 	// Internal terminated workload trackers (not exposed)
 	terminatedProcessesTracker  *TerminatedResourceTracker[*Process]
 	terminatedContainersTracker *TerminatedResourceTracker[*Container]
 	terminatedVMsTracker        *TerminatedResourceTracker[*VirtualMachine]
 	terminatedPodsTracker       *TerminatedResourceTracker[*Pod]
 
+	// systemdUnits enables power attribution to systemd units
+	systemdUnits                  bool
+	terminatedSystemdUnitsTracker *TerminatedResourceTracker[*SystemdUnit]
+
+	// groups enables power attribution to groups of processes
+	groups                  bool
+	terminatedGroupsTracker *TerminatedResourceTracker[*Group]
+
+	// aggregates enables power attribution to the kernel and system aggregates
+	aggregates bool
+
+	// otherWorkloads enables reporting the power of filtered out workloads as
+	// the OtherWorkload process, container and pod
+	otherWorkloads bool
+
+	// restored are the totals of a previous run the counters continue from;
+	// the totals of workloads are removed once restored. nil if none
+	restored *Totals
+
 	// For managing the collection loop
 	collectionCtx    context.Context
 	collectionCancel context.CancelFunc
+
+	// ready is closed once the first collection completes
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	// refreshErr is the error of the last collection, reported by Health
+	refreshMu  sync.Mutex
+	refreshErr error
+
+	// drained is set once the last snapshot is computed on shutdown; Snapshot
+	// returns it from then on
+	drained   atomic.Bool
+	drainOnce sync.Once
 }
 
-var _ Service = (*PowerMonitor)(nil)
+var (
+	_ Service               = (*PowerMonitor)(nil)
+	_ service.Dependent     = (*PowerMonitor)(nil)
+	_ service.Readier       = (*PowerMonitor)(nil)
+	_ service.HealthChecker = (*PowerMonitor)(nil)
+	_ service.Drainer       = (*PowerMonitor)(nil)
+)
+
+// staleHealthIntervals is the number of collection intervals after which the
+// monitor is unhealthy if no collection completed
+const staleHealthIntervals = 3
 
 // NewPowerMonitor creates a new PowerMonitor instance
 func NewPowerMonitor(meter device.CPUPowerMeter, applyOpts ...OptionFn) *PowerMonitor {
@@ -94,6 +226,7 @@ func NewPowerMonitor(meter device.CPUPowerMeter, applyOpts ...OptionFn) *PowerMo
 	monitor := &PowerMonitor{
 		logger:    opts.logger.With("service", "monitor"),
 		cpu:       meter,
+		gpus:      opts.gpus,
 		clock:     opts.clock,
 		interval:  opts.interval,
 		resources: opts.resources,
@@ -101,11 +234,48 @@ func NewPowerMonitor(meter device.CPUPowerMeter, applyOpts ...OptionFn) *PowerMo
 
 		maxStaleness: opts.maxStaleness,
 
+		maxInterval:   opts.maxInterval,
+		idleThreshold: opts.idleThreshold,
+
+		sampleInterval: opts.sampleInterval,
+
 		maxTerminated:                opts.maxTerminated,
 		minTerminatedEnergyThreshold: opts.minTerminatedEnergyThreshold,
+		maxTerminatedAge:             opts.maxTerminatedAge,
+
+		calibrations: opts.calibrations,
+
+		attribution: opts.attribution,
+		idlePolicy:  opts.idlePolicy,
+		cpuSockets:  opts.cpuSockets,
+		carbon:      opts.carbon,
+		tariff:      opts.tariff,
+
+		systemdUnits: opts.systemdUnits,
+		groups:       opts.groups,
+		aggregates:   opts.aggregates,
+
+		otherWorkloads: opts.otherWorkloads,
+		restored:       opts.totals,
+
+		budgetNotifiers: opts.budgetNotifiers,
+		events:          opts.events,
 
 		collectionCtx:    ctx,
 		collectionCancel: cancel,
+
+		ready: make(chan struct{}),
+	}
+
+	monitor.ioZones, monitor.ioZoneList = newIOZones(opts.networkModel, opts.storageModel, opts.clock)
+	monitor.platform = opts.platform
+
+	if opts.sampleInterval > 0 && opts.sampleInterval < opts.interval {
+		monitor.sampler = newPowerSampler()
+	}
+
+	if !opts.budgets.isEmpty() {
+		monitor.budgets = newBudgetTracker(opts.budgets)
 	}
 
 	return monitor
@@ -129,19 +299,31 @@ func (pm *PowerMonitor) Init() error {
 	pm.logger.Info("Using primary energy zone for terminated workload tracking",
 		"zone", primaryEnergyZone.Name())
 
+	// terminated workloads are retained until exported unless a max age is set
+	var trackerOpts []TrackerOptionFn
+	if pm.maxTerminatedAge > 0 {
+		trackerOpts = append(trackerOpts, WithMaxAge(pm.maxTerminatedAge, pm.clock))
+	}
+
 	// Initialize terminated workload trackers with the primary energy zone and minimum energy threshold
 	pm.terminatedProcessesTracker = NewTerminatedResourceTracker[*Process](
 		primaryEnergyZone, pm.maxTerminated,
-		pm.minTerminatedEnergyThreshold, pm.logger)
+		pm.minTerminatedEnergyThreshold, pm.logger, trackerOpts...)
 	pm.terminatedContainersTracker = NewTerminatedResourceTracker[*Container](
 		primaryEnergyZone, pm.maxTerminated,
-		pm.minTerminatedEnergyThreshold, pm.logger)
+		pm.minTerminatedEnergyThreshold, pm.logger, trackerOpts...)
 	pm.terminatedVMsTracker = NewTerminatedResourceTracker[*VirtualMachine](
 		primaryEnergyZone, pm.maxTerminated,
-		pm.minTerminatedEnergyThreshold, pm.logger)
+		pm.minTerminatedEnergyThreshold, pm.logger, trackerOpts...)
 	pm.terminatedPodsTracker = NewTerminatedResourceTracker[*Pod](
 		primaryEnergyZone, pm.maxTerminated,
-		pm.minTerminatedEnergyThreshold, pm.logger)
+		pm.minTerminatedEnergyThreshold, pm.logger, trackerOpts...)
+	pm.terminatedSystemdUnitsTracker = NewTerminatedResourceTracker[*SystemdUnit](
+		primaryEnergyZone, pm.maxTerminated,
+		pm.minTerminatedEnergyThreshold, pm.logger, trackerOpts...)
+	pm.terminatedGroupsTracker = NewTerminatedResourceTracker[*Group](
+		primaryEnergyZone, pm.maxTerminated,
+		pm.minTerminatedEnergyThreshold, pm.logger, trackerOpts...)
 
 	// signal now so that exporters can construct descriptors
 	pm.signalNewData()
@@ -161,6 +343,14 @@ func (pm *PowerMonitor) signalNewData() {
 func (pm *PowerMonitor) Run(ctx context.Context) error {
 	pm.logger.Info("Monitor is running...")
 	pm.collectionLoop()
+	pm.readyOnce.Do(func() {
+		if pm.ready != nil {
+			close(pm.ready)
+		}
+	})
+	if pm.sampler != nil {
+		go pm.samplingLoop()
+	}
 	<-ctx.Done()
 	pm.collectionCancel()
 	pm.logger.Info("Monitor has terminated.")
@@ -173,6 +363,117 @@ func (pm *PowerMonitor) Shutdown() error {
 	return nil
 }
 
+// Drain stops the collection and computes the last snapshot, which Snapshot
+// returns from then on, so that the energy consumed since the previous
+// collection and the workloads terminated since are exported on shutdown
+func (pm *PowerMonitor) Drain() error {
+	var err error
+	pm.drainOnce.Do(func() {
+		pm.collectionCancel()
+		if pm.snapshot.Load() != nil {
+			// the last snapshot is computed even if fresh, unless a collection
+			// is in progress
+			_, err, _ = pm.computeGroup.Do("compute", func() (any, error) {
+				return nil, pm.refreshSnapshot()
+			})
+		}
+		pm.drained.Store(true)
+		pm.logger.Info("Monitor is drained")
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compute the last snapshot: %w", err)
+	}
+	return nil
+}
+
+// Dependencies returns the CPU power meter, resource informer and carbon
+// intensity provider the monitor reads on every collection
+func (pm *PowerMonitor) Dependencies() []service.Service {
+	deps := []service.Service{pm.cpu}
+	if pm.resources != nil {
+		deps = append(deps, pm.resources)
+	}
+	if pm.carbon != nil {
+		deps = append(deps, pm.carbon)
+	}
+	return deps
+}
+
+// Ready returns a channel that is closed once the first collection completes
+// after the monitor is run
+func (pm *PowerMonitor) Ready() <-chan struct{} {
+	return pm.ready
+}
+
+// Health returns the error of the last collection, or an error if no
+// collection completed for staleHealthIntervals intervals once running
+func (pm *PowerMonitor) Health() error {
+	pm.refreshMu.Lock()
+	err := pm.refreshErr
+	pm.refreshMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("last collection failed: %w", err)
+	}
+
+	select {
+	case <-pm.ready:
+	default:
+		return nil // not running yet
+	}
+	interval := pm.Interval()
+	if interval <= 0 {
+		return nil // collected on demand
+	}
+
+	snapshot := pm.snapshot.Load()
+	if snapshot == nil || snapshot.Timestamp.IsZero() {
+		return fmt.Errorf("no power data collected")
+	}
+	if age, limit := pm.clock.Since(snapshot.Timestamp), staleHealthIntervals*max(interval, pm.maxInterval); age > limit {
+		return fmt.Errorf("no power data collected for %s", age.Round(time.Second))
+	}
+	return nil
+}
+
+// SetInterval changes the interval of periodic collections from the next
+// collection. Periodic collection can't be enabled or disabled at runtime.
+func (pm *PowerMonitor) SetInterval(interval time.Duration) error {
+	pm.intervalMu.Lock()
+	defer pm.intervalMu.Unlock()
+	if err := pm.validateInterval(interval); err != nil {
+		return err
+	}
+	if interval != pm.interval {
+		pm.logger.Info("Changing collection interval", "from", pm.interval, "to", interval)
+	}
+	pm.interval = interval
+	return nil
+}
+
+// ValidateInterval returns an error if the interval of periodic collections
+// can't be changed to interval by SetInterval
+func (pm *PowerMonitor) ValidateInterval(interval time.Duration) error {
+	pm.intervalMu.RLock()
+	defer pm.intervalMu.RUnlock()
+	return pm.validateInterval(interval)
+}
+
+// validateInterval must be called with intervalMu held
+func (pm *PowerMonitor) validateInterval(interval time.Duration) error {
+	if (interval > 0) != (pm.interval > 0) {
+		return fmt.Errorf("monitor interval can't be changed from %s to %s at runtime", pm.interval, interval)
+	}
+	return nil
+}
+
+// Interval returns the interval of periodic collections; 0 if data
+// is only collected on demand
+func (pm *PowerMonitor) Interval() time.Duration {
+	pm.intervalMu.RLock()
+	defer pm.intervalMu.RUnlock()
+	return pm.interval
+}
+
 func (pm *PowerMonitor) DataChannel() <-chan struct{} {
 	return pm.dataCh
 }
@@ -183,8 +484,11 @@ func (pm *PowerMonitor) ZoneNames() []string {
 }
 
 func (pm *PowerMonitor) Snapshot() (*Snapshot, error) {
-	if err := pm.ensureFreshData(); err != nil {
-		return nil, err
+	// the last snapshot is kept once drained
+	if !pm.drained.Load() {
+		if err := pm.ensureFreshData(); err != nil {
+			return nil, err
+		}
 	}
 
 	snapshot := pm.snapshot.Load()
@@ -199,6 +503,15 @@ func (pm *PowerMonitor) Snapshot() (*Snapshot, error) {
 	return snapshot.Clone(), nil
 }
 
+func (pm *PowerMonitor) LatestSnapshot() (*Snapshot, error) {
+	snapshot := pm.snapshot.Load()
+	if snapshot == nil {
+		return nil, fmt.Errorf("failed to get snapshot")
+	}
+	// snapshots are immutable once stored, so it need not be cloned
+	return snapshot, nil
+}
+
 func (pm *PowerMonitor) initZones() error {
 	// zone names need to be collected only once and can be cached
 	zones, err := pm.cpu.Zones()
@@ -206,9 +519,20 @@ func (pm *PowerMonitor) initZones() error {
 		return err
 	}
 
-	pm.zonesNames = make([]string, len(zones))
-	for i, zone := range zones {
-		pm.zonesNames[i] = zone.Name()
+	pm.initGPUZones()
+
+	pm.zonesNames = make([]string, 0, len(zones)+len(pm.gpuZoneList)+len(pm.ioZoneList)+1)
+	for _, zone := range zones {
+		pm.zonesNames = append(pm.zonesNames, zone.Name())
+	}
+	for _, zone := range pm.gpuZoneList {
+		pm.zonesNames = append(pm.zonesNames, zone.Name())
+	}
+	for _, zone := range pm.ioZoneList {
+		pm.zonesNames = append(pm.zonesNames, zone.Name())
+	}
+	if pm.platform != nil {
+		pm.zonesNames = append(pm.zonesNames, pm.platform.Name())
 	}
 
 	return nil
@@ -220,14 +544,14 @@ func (pm *PowerMonitor) collectionLoop() {
 		pm.logger.Error("Failed to collect initial power data", "error", err)
 	}
 
-	if pm.interval > 0 {
+	if pm.Interval() > 0 {
 		pm.scheduleNextCollection()
 	}
 }
 
 // scheduleNextCollection schedules the next data collection
 func (pm *PowerMonitor) scheduleNextCollection() {
-	timer := pm.clock.After(pm.interval)
+	timer := pm.clock.After(pm.nextInterval())
 	go func() {
 		select {
 		case <-timer:
@@ -298,6 +622,9 @@ func (pm *PowerMonitor) synchronizedPowerRefresh() error {
 		return nil, pm.refreshSnapshot()
 	})
 
+	pm.refreshMu.Lock()
+	pm.refreshErr = err
+	pm.refreshMu.Unlock()
 	return err
 }
 
@@ -308,7 +635,19 @@ func (pm *PowerMonitor) isFresh() bool {
 	}
 
 	age := pm.clock.Now().Sub(snapshot.Timestamp)
-	return age <= pm.maxStaleness
+	return age <= pm.staleness()
+}
+
+// staleness returns the age after which the data is collected again when read.
+// While collection backs off, it is the current interval, so that consumers
+// reading the data, e.g. Prometheus scraping, don't collect it at the interval
+// backing off is meant to avoid.
+func (pm *PowerMonitor) staleness() time.Duration {
+	current := time.Duration(pm.currentInterval.Load())
+	if interval := pm.Interval(); interval > 0 && current > interval {
+		return max(pm.maxStaleness, current)
+	}
+	return pm.maxStaleness
 }
 
 // refreshSnapshot creates a new snapshot of the power consumption
@@ -322,6 +661,7 @@ func (pm *PowerMonitor) refreshSnapshot() error {
 
 	newSnapshot := NewSnapshot()
 	prevSnapshot := pm.snapshot.Load()
+	pm.pendingEvents = pm.pendingEvents[:0]
 
 	if prevSnapshot == nil {
 		// Handle initial collection explicitly
@@ -339,8 +679,11 @@ func (pm *PowerMonitor) refreshSnapshot() error {
 
 	// Update snapshot with current timestamp
 	newSnapshot.Timestamp = pm.clock.Now()
+	newSnapshot.Sources = pm.sourceList()
+	pm.updateBudgets(newSnapshot, newSnapshot.Timestamp)
 	pm.snapshot.Store(newSnapshot)
 	pm.signalNewData()
+	pm.publishEvents(newSnapshot)
 	pm.logger.Debug("refreshSnapshot",
 		"processes", len(newSnapshot.Processes),
 		"containers", len(newSnapshot.Containers),
@@ -350,6 +693,11 @@ func (pm *PowerMonitor) refreshSnapshot() error {
 		"terminated_containers", len(newSnapshot.TerminatedContainers),
 		"terminated_vms", len(newSnapshot.TerminatedVirtualMachines),
 		"terminated_pods", len(newSnapshot.TerminatedPods),
+		"systemd_units", len(newSnapshot.SystemdUnits),
+		"terminated_systemd_units", len(newSnapshot.TerminatedSystemdUnits),
+		"groups", len(newSnapshot.Groups),
+		"terminated_groups", len(newSnapshot.TerminatedGroups),
+		"aggregates", len(newSnapshot.Aggregates),
 	)
 
 	return nil
@@ -361,18 +709,49 @@ const (
 	containerPowerError = "failed to calculate container power: %w"
 	vmPowerError        = "failed to calculate vm power: %w"
 	podPowerError       = "failed to calculate pod power: %w"
+	unitPowerError      = "failed to calculate systemd unit power: %w"
+	groupPowerError     = "failed to calculate group power: %w"
+	aggregatePowerError = "failed to calculate aggregate power: %w"
 )
 
-func (pm *PowerMonitor) firstReading(newSnapshot *Snapshot) error {
-	// First read for node
-	if err := pm.firstNodeRead(newSnapshot.Node); err != nil {
-		return fmt.Errorf(nodePowerError, err)
+// refreshResourcesWhile refreshes resources in parallel with readNode, which
+// reads the node's zones, since they read independent sources (procfs, cgroups
+// and BPF vs. energy meters); a refresh then takes as long as the slower of the
+// two rather than both. readNode must not use the resources being refreshed,
+// so the usage of the node is read before.
+func (pm *PowerMonitor) refreshResourcesWhile(readNode func() error) error {
+	var refreshErr error
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		refreshErr = pm.resources.Refresh()
+	}()
+	nodeErr := readNode()
+	wg.Wait()
+
+	if nodeErr != nil {
+		return fmt.Errorf(nodePowerError, nodeErr)
 	}
+	if refreshErr != nil {
+		pm.logger.Error("snapshot rebuild failed to refresh resources", "error", refreshErr)
+		return refreshErr
+	}
+	return nil
+}
 
-	if err := pm.resources.Refresh(); err != nil {
-		pm.logger.Error("snapshot rebuild failed to refresh resources", "error", err)
+func (pm *PowerMonitor) firstReading(newSnapshot *Snapshot) error {
+	// First read for node
+	usage := pm.nodeUsage()
+	if err := pm.refreshResourcesWhile(func() error {
+		return pm.firstNodePower(newSnapshot.Node, usage)
+	}); err != nil {
 		return err
 	}
+	pm.computeGPUShares()
+	pm.updateAttribution()
+	pm.computeIdleShares()
+	pm.computeSocketShares()
 
 	// First read for processes
 	if err := pm.firstProcessRead(newSnapshot); err != nil {
@@ -393,19 +772,42 @@ func (pm *PowerMonitor) firstReading(newSnapshot *Snapshot) error {
 		return fmt.Errorf(podPowerError, err)
 	}
 
+	if err := pm.firstSystemdUnitRead(newSnapshot); err != nil {
+		return fmt.Errorf(unitPowerError, err)
+	}
+
+	if err := pm.firstGroupRead(newSnapshot); err != nil {
+		return fmt.Errorf(groupPowerError, err)
+	}
+
+	if err := pm.firstAggregateRead(newSnapshot); err != nil {
+		return fmt.Errorf(aggregatePowerError, err)
+	}
+
+	// First read for the other workloads
+	pm.calculateOtherPower(nil, newSnapshot)
+
+	// First read for the owner workloads of pods
+	pm.calculateOwnerPower(nil, newSnapshot)
+
 	return nil
 }
 
 func (pm *PowerMonitor) calculatePower(prev, newSnapshot *Snapshot) error {
 	// Calculate node power
-	if err := pm.calculateNodePower(prev.Node, newSnapshot.Node); err != nil {
-		return fmt.Errorf(nodePowerError, err)
-	}
-
-	if err := pm.resources.Refresh(); err != nil {
-		pm.logger.Error("snapshot rebuild failed to refresh resources", "error", err)
+	usage := pm.nodeUsage()
+	if err := pm.refreshResourcesWhile(func() error {
+		return pm.nodePower(prev.Node, newSnapshot.Node, usage)
+	}); err != nil {
 		return err
 	}
+	pm.refreshIOZones()
+	pm.computeGPUShares()
+	pm.computeIOShares()
+	pm.computePlatformShares()
+	pm.updateAttribution()
+	pm.computeIdleShares()
+	pm.computeSocketShares()
 
 	// Calculate process power
 	if err := pm.calculateProcessPower(prev, newSnapshot); err != nil {
@@ -427,5 +829,26 @@ func (pm *PowerMonitor) calculatePower(prev, newSnapshot *Snapshot) error {
 		return fmt.Errorf(podPowerError, err)
 	}
 
+	// calculate systemd unit power
+	if err := pm.calculateSystemdUnitPower(prev, newSnapshot); err != nil {
+		return fmt.Errorf(unitPowerError, err)
+	}
+
+	// calculate group power
+	if err := pm.calculateGroupPower(prev, newSnapshot); err != nil {
+		return fmt.Errorf(groupPowerError, err)
+	}
+
+	// calculate kernel and system aggregate power
+	if err := pm.calculateAggregatePower(prev, newSnapshot); err != nil {
+		return fmt.Errorf(aggregatePowerError, err)
+	}
+
+	// attribute the power of filtered out workloads to the other workloads
+	pm.calculateOtherPower(prev, newSnapshot)
+
+	// sum the power of pods by the workloads controlling them
+	pm.calculateOwnerPower(prev, newSnapshot)
+
 	return nil
 }
@@ -490,12 +490,20 @@ func TestPowerMonitor_ConcurrentCollection(t *testing.T) {
 		err := monitor.Init()
 		require.NoError(t, err)
 
-		// run in background
+		// run in background; stop the collection before the mock expectations are
+		// cleared so that no refresh outlives the test
 		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		runDone := make(chan struct{})
 		go func() {
+			defer close(runDone)
 			_ = monitor.Run(ctx)
 		}()
+		t.Cleanup(func() {
+			cancel()
+			<-runDone
+			// joins a refresh that may still be in flight
+			_ = monitor.synchronizedPowerRefresh()
+		})
 
 		// wait for monitor to startt
 		time.Sleep(10 * time.Millisecond)
@@ -543,7 +551,9 @@ func TestPowerMonitor_ConcurrentCollection(t *testing.T) {
 		}
 
 		// keep clock ticking while snapshots are requested
+		tickDone := make(chan struct{})
 		go func() {
+			defer close(tickDone)
 			for range numIterations {
 				fakeClock.Step(50 * time.Millisecond)
 				time.Sleep(10 * time.Millisecond)
@@ -551,6 +561,7 @@ func TestPowerMonitor_ConcurrentCollection(t *testing.T) {
 		}()
 
 		wg.Wait()
+		<-tickDone
 
 		// verify no errors were encountered
 		assert.False(t, encounteredErr.Load(), "Some goroutines encountered errors")
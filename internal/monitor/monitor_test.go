@@ -6,6 +6,8 @@ package monitor
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"os"
@@ -17,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/sustainable-computing-io/kepler/internal/device"
 	"github.com/sustainable-computing-io/kepler/internal/resource"
+	"github.com/sustainable-computing-io/kepler/pkg/synthetic"
 	testingclock "k8s.io/utils/clock/testing"
 )
 
@@ -169,6 +172,45 @@ func TestPowerMonitor_Snapshot(t *testing.T) {
 	assert.Equal(t, monitor.snapshot.Load(), snapshot)
 }
 
+func TestPowerMonitor_LatestSnapshot(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	mockPowerMeter := &MockCPUPowerMeter{}
+	pkg := &MockEnergyZone{}
+	pkg.On("Name").Return("package")
+	pkg.On("Index").Return(0)
+	pkg.On("Path").Return("")
+	pkg.On("Energy").Return(Energy(100_000), nil)
+	pkg.On("MaxEnergy").Return(Energy(1_000_000))
+	mockPowerMeter.On("Zones").Return([]device.EnergyZone{pkg}, nil)
+	mockPowerMeter.On("PrimaryEnergyZone").Return(pkg, nil)
+
+	tr := CreateTestResources()
+	resourceInformer := &MockResourceInformer{}
+	resourceInformer.SetExpectations(t, tr)
+	resourceInformer.On("Refresh").Return(nil)
+
+	monitor := NewPowerMonitor(mockPowerMeter,
+		WithResourceInformer(resourceInformer),
+		WithClock(fakeClock),
+		WithMaxStaleness(time.Second),
+	)
+
+	_, err := monitor.LatestSnapshot()
+	assert.Error(t, err, "no snapshot collected yet")
+
+	require.NoError(t, monitor.Init())
+	require.NoError(t, monitor.refreshSnapshot())
+	stored := monitor.snapshot.Load()
+
+	// a stale snapshot is neither refreshed nor marked exported
+	fakeClock.Step(time.Minute)
+	snapshot, err := monitor.LatestSnapshot()
+	require.NoError(t, err)
+	assert.Same(t, stored, snapshot)
+	assert.Same(t, stored, monitor.snapshot.Load())
+	assert.False(t, monitor.exported.Load())
+}
+
 func TestPowerMonitor_InitZones(t *testing.T) {
 	fakePowerMeter, err := device.NewFakeCPUMeter(nil)
 	require.NoError(t, err, "failed to create fake power meter")
@@ -664,6 +706,48 @@ func TestRefreshSnapshotError(t *testing.T) {
 	})
 }
 
+func TestPowerMonitor_Health(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	mockClock := testingclock.NewFakeClock(time.Date(2023, 4, 15, 9, 0, 0, 0, time.UTC))
+
+	tr := CreateTestResources()
+	resourceInformer := &MockResourceInformer{}
+	resourceInformer.SetExpectations(t, tr)
+	resourceInformer.On("Refresh").Return(nil)
+
+	mockCPUPowerMeter := &MockCPUPowerMeter{}
+	pm := NewPowerMonitor(
+		mockCPUPowerMeter,
+		WithLogger(logger),
+		WithClock(mockClock),
+		WithInterval(5*time.Second),
+		WithResourceInformer(resourceInformer),
+	)
+	assert.NoError(t, pm.Health(), "monitor is healthy until it collects")
+
+	mockCPUPowerMeter.On("Zones").Return([]EnergyZone(nil), assert.AnError).Once()
+	require.Error(t, pm.synchronizedPowerRefresh())
+	assert.ErrorIs(t, pm.Health(), assert.AnError, "failed collections are unhealthy")
+
+	pkg := device.NewMockRaplZone("package-0", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 200*Joule)
+	mockCPUPowerMeter.On("Zones").Return([]EnergyZone{pkg}, nil)
+	mockCPUPowerMeter.On("PrimaryEnergyZone").Return(pkg, nil)
+	require.NoError(t, pm.Init())
+	require.NoError(t, pm.synchronizedPowerRefresh())
+	assert.NoError(t, pm.Health())
+
+	// collections are not expected until the monitor is running
+	mockClock.Step(time.Minute)
+	assert.NoError(t, pm.Health())
+
+	close(pm.ready)
+	assert.ErrorContains(t, pm.Health(), "no power data collected for 1m0s")
+
+	require.NoError(t, pm.synchronizedPowerRefresh())
+	mockClock.Step(15 * time.Second)
+	assert.NoError(t, pm.Health(), "collections may be late by up to 3 intervals")
+}
+
 // TestTerminatedWorkloadsClearedAfterSnapshot validates that terminated workloads
 // (processes, containers, VMs, pods) are cleared in the first calculation after
 // the Snapshot function is called.
@@ -1048,3 +1132,180 @@ func TestSnapshotFreshnessAndCloning(t *testing.T) {
 	resourceInformer.AssertExpectations(t)
 	mockMeter.AssertExpectations(t)
 }
+
+func TestPowerMonitor_Drain(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	zones := CreateTestZones()
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(zones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(zones[0], nil)
+
+	tr := CreateTestResources()
+	resourceInformer := &MockResourceInformer{}
+	resourceInformer.SetExpectations(t, tr)
+	resourceInformer.On("Refresh").Return(nil)
+
+	maxStaleness := 1 * time.Second
+	monitor := NewPowerMonitor(
+		mockMeter,
+		WithClock(fakeClock),
+		WithMaxStaleness(maxStaleness),
+		WithResourceInformer(resourceInformer),
+	)
+	require.NoError(t, monitor.Init())
+	require.NoError(t, monitor.refreshSnapshot())
+	initial := monitor.snapshot.Load()
+
+	// the last snapshot is computed even though the previous one is fresh
+	fakeClock.Step(100 * time.Millisecond)
+	require.NoError(t, monitor.Drain())
+	last := monitor.snapshot.Load()
+	assert.True(t, last.Timestamp.After(initial.Timestamp), "a last snapshot should be computed")
+	assert.Equal(t, fakeClock.Now(), last.Timestamp)
+	assert.Error(t, monitor.collectionCtx.Err(), "collection should be stopped")
+
+	// the last snapshot is returned from then on, even once stale
+	resourceInformer.ExpectedCalls = nil
+	fakeClock.Step(maxStaleness + 100*time.Millisecond)
+	snapshot, err := monitor.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, last.Timestamp, snapshot.Timestamp, "no snapshot should be computed once drained")
+	snapshot, err = monitor.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, last.Timestamp, snapshot.Timestamp)
+
+	// draining again is a no-op
+	require.NoError(t, monitor.Drain())
+	assert.Same(t, last, monitor.snapshot.Load())
+
+	resourceInformer.AssertExpectations(t)
+}
+
+// slowZone is a zone whose energy takes latency to read, or until ready is
+// closed if it is set
+type slowZone struct {
+	EnergyZone
+	latency time.Duration
+	ready   <-chan struct{}
+}
+
+func (z *slowZone) Energy() (Energy, error) {
+	if z.ready != nil {
+		select {
+		case <-z.ready:
+		case <-time.After(time.Second):
+			return 0, errors.New("resources were not refreshed while reading the zone")
+		}
+	}
+	time.Sleep(z.latency)
+	return z.EnergyZone.Energy()
+}
+
+func TestRefreshSnapshotReadsZonesWhileRefreshingResources(t *testing.T) {
+	refreshing := make(chan struct{})
+	pkg := &slowZone{
+		EnergyZone: device.NewMockRaplZone("package-0", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*Joule),
+		ready:      refreshing,
+	}
+	meter := &MockCPUPowerMeter{}
+	meter.On("Zones").Return([]EnergyZone{pkg}, nil)
+
+	resourceInformer := &MockResourceInformer{}
+	resourceInformer.SetExpectations(t, CreateTestResources())
+	resourceInformer.On("Refresh").Run(func(mock.Arguments) {
+		close(refreshing)
+	}).Return(nil).Once()
+
+	pm := NewPowerMonitor(meter,
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithClock(testingclock.NewFakeClock(time.Now())),
+		WithResourceInformer(resourceInformer),
+	)
+	require.NoError(t, pm.refreshSnapshot())
+	assert.Contains(t, pm.snapshot.Load().Node.Zones, EnergyZone(pkg))
+	resourceInformer.AssertExpectations(t)
+}
+
+// BenchmarkRefreshSnapshot measures the latency of a refresh when reading the
+// energy of zones and refreshing resources are slow, as on large nodes
+func BenchmarkRefreshSnapshot(b *testing.B) {
+	for _, tc := range []struct {
+		zone, refresh time.Duration
+	}{
+		{time.Millisecond, 5 * time.Millisecond},
+		{5 * time.Millisecond, 5 * time.Millisecond},
+		{5 * time.Millisecond, time.Millisecond},
+	} {
+		b.Run(fmt.Sprintf("zone=%s/refresh=%s", tc.zone, tc.refresh), func(b *testing.B) {
+			var zones []EnergyZone
+			for _, z := range CreateTestZones() {
+				zones = append(zones, &slowZone{EnergyZone: z, latency: tc.zone})
+			}
+			meter := &MockCPUPowerMeter{}
+			meter.On("Zones").Return(zones, nil)
+			meter.On("PrimaryEnergyZone").Return(zones[0], nil)
+
+			resourceInformer := &MockResourceInformer{}
+			resourceInformer.SetExpectations(b, CreateTestResources())
+			resourceInformer.On("Refresh").After(tc.refresh).Return(nil)
+
+			fakeClock := testingclock.NewFakeClock(time.Now())
+			pm := NewPowerMonitor(meter,
+				WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+				WithClock(fakeClock),
+				WithResourceInformer(resourceInformer),
+			)
+			require.NoError(b, pm.Init())
+			require.NoError(b, pm.refreshSnapshot())
+
+			b.ResetTimer()
+			for range b.N {
+				fakeClock.Step(time.Second)
+				if err := pm.refreshSnapshot(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkRefreshWorkloads measures refreshing the snapshot of nodes running
+// many synthetic workloads, with and without churn
+func BenchmarkRefreshWorkloads(b *testing.B) {
+	for _, tc := range []struct {
+		processes, containers int
+		churn                 float64
+	}{
+		{1000, 100, 0},
+		{10000, 1000, 0},
+		{10000, 1000, 0.05},
+	} {
+		b.Run(fmt.Sprintf("processes=%d/containers=%d/churn=%g", tc.processes, tc.containers, tc.churn), func(b *testing.B) {
+			fakeClock := testingclock.NewFakeClock(time.Now())
+			opts := []synthetic.OptionFn{
+				synthetic.WithClock(fakeClock),
+				synthetic.WithProcesses(tc.processes),
+				synthetic.WithContainers(tc.containers),
+				synthetic.WithProcessChurn(tc.churn),
+				synthetic.WithContainerChurn(tc.churn),
+			}
+			pm := NewPowerMonitor(synthetic.NewCPUMeter(opts...),
+				WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+				WithClock(fakeClock),
+				WithResourceInformer(synthetic.NewInformer(opts...)),
+			)
+			require.NoError(b, pm.Init())
+			require.NoError(b, pm.refreshSnapshot())
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				fakeClock.Step(5 * time.Second)
+				if err := pm.refreshSnapshot(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
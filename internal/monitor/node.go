@@ -5,9 +5,69 @@ package monitor
 
 import (
 	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
 )
 
+// nodeUsage is the CPU usage of the node, used to split the energy of zones
+// into active and idle energy
+type nodeUsage struct {
+	cpuTimeDelta        float64
+	usageRatio          float64
+	coreTypeUsageRatios map[string]float64
+}
+
+// nodeUsage returns the CPU usage of the node read by the last refresh of
+// resources
+func (pm *PowerMonitor) nodeUsage() nodeUsage {
+	node := pm.resources.Node()
+	return nodeUsage{
+		cpuTimeDelta:        node.ProcessTotalCPUTimeDelta,
+		usageRatio:          node.CPUUsageRatio,
+		coreTypeUsageRatios: node.CoreTypeUsageRatios,
+	}
+}
+
+// zoneReading is the energy read from a zone, or the error reading it
+type zoneReading struct {
+	energy Energy
+	err    error
+}
+
+// readZones reads the energy of zones and the utilization of GPUs. GPU zones
+// are read along with the utilization of their meters, which are not safe for
+// concurrent use, in parallel with the other zones.
+func (pm *PowerMonitor) readZones(zones []EnergyZone) []zoneReading {
+	readings := make([]zoneReading, len(zones))
+	read := func(gpus bool) {
+		for i, zone := range zones {
+			if _, isGPU := pm.gpuZones[zone]; isGPU == gpus {
+				readings[i].energy, readings[i].err = zone.Energy()
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pm.refreshGPUUtilization()
+		read(true)
+	}()
+	read(false)
+	wg.Wait()
+	return readings
+}
+
 func (pm *PowerMonitor) calculateNodePower(prevNode, newNode *Node) error {
+	return pm.nodePower(prevNode, newNode, pm.nodeUsage())
+}
+
+// nodePower computes the power of the node since prevNode, splitting it using
+// usage
+func (pm *PowerMonitor) nodePower(prevNode, newNode *Node, usage nodeUsage) error {
 	// Get previous measurements for calculating watts
 	prevReadTime := prevNode.Timestamp
 	prevZones := prevNode.Zones
@@ -16,14 +76,18 @@ func (pm *PowerMonitor) calculateNodePower(prevNode, newNode *Node) error {
 	newNode.Timestamp = now
 
 	// get zones first, before locking for read
-	zones, err := pm.cpu.Zones()
+	zones, err := pm.zones()
 	if err != nil {
 		return err
 	}
+	readings := pm.readZones(zones)
+	pm.refreshCarbonIntensity(newNode)
+	pm.refreshPrice(newNode)
 
-	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
-	nodeCPUUsageRatio := pm.resources.Node().CPUUsageRatio
+	nodeCPUTimeDelta := usage.cpuTimeDelta
+	nodeCPUUsageRatio := usage.usageRatio
 	newNode.UsageRatio = nodeCPUUsageRatio
+	newNode.CoreTypeUsageRatios = usage.coreTypeUsageRatios
 
 	pm.logger.Debug("Calculating Node power",
 		"node.process-cpu.time", nodeCPUTimeDelta,
@@ -34,18 +98,20 @@ func (pm *PowerMonitor) calculateNodePower(prevNode, newNode *Node) error {
 	timeDiff := now.Sub(prevReadTime).Seconds()
 	// Get the current energy
 
-	var retErr error
-	for _, zone := range zones {
-		absEnergy, err := zone.Energy()
+	var errs []error
+	for i, zone := range zones {
+		absEnergy, err := readings[i].energy, readings[i].err
+		pm.updateZoneSource(zone, err)
 		if err != nil {
-			retErr = errors.Join(err)
-			pm.logger.Warn("Could not read energy for zone", "zone", zone.Name(), "index", zone.Index(), "error", err)
+			errs = append(errs, err)
 			continue
 		}
 
 		// Calculate watts and joules diff if we have previous data for the zone
-		var activeEnergy, activeEnergyTotal, idleEnergyTotal Energy
-		var power, activePower, idlePower Power
+		var activeEnergy, idleEnergy, activeEnergyTotal, idleEnergyTotal Energy
+		var power, rawPower, activePower, idlePower Power
+		var emissionsTotal, costTotal float64
+		var wraparounds, jumps uint64
 
 		if prevZone, ok := prevZones[zone]; ok {
 			// Absolute is a running total, so to find the current energy usage, calculate the delta
@@ -53,69 +119,118 @@ func (pm *PowerMonitor) calculateNodePower(prevNode, newNode *Node) error {
 			// active = delta * cpuUsage
 			// idle = delta - active
 
-			deltaEnergy := calculateEnergyDelta(absEnergy, prevZone.EnergyTotal, zone.MaxEnergy())
-
-			activeEnergy = Energy(float64(deltaEnergy) * nodeCPUUsageRatio)
-			idleEnergy := deltaEnergy - activeEnergy
+			deltaEnergy, change := device.EnergyDelta(absEnergy, prevZone.EnergyTotal, zone.MaxEnergy(),
+				device.MaxEnergyDelta(now.Sub(prevReadTime)))
+			wraparounds, jumps = prevZone.Wraparounds, prevZone.Jumps
+			switch change {
+			case device.CounterWrapped:
+				wraparounds++
+			case device.CounterJumped:
+				jumps++
+				pm.logger.Warn("Energy counter of zone jumped; discarding the energy of the interval",
+					"zone", zone.Name(), "path", zone.Path(),
+					"previous", prevZone.EnergyTotal, "current", absEnergy, "max", zone.MaxEnergy())
+			}
+			rawPower = Power(float64(deltaEnergy) / timeDiff)
+			calibrated := false
+			if change != device.CounterJumped {
+				deltaEnergy, calibrated = pm.calibrate(zone, deltaEnergy, timeDiff)
+			}
+			activeRatio := pm.activeRatio(zone, nodeCPUUsageRatio)
+
+			activeEnergy = Energy(float64(deltaEnergy) * activeRatio)
+			idleEnergy = deltaEnergy - activeEnergy
 
 			activeEnergyTotal = prevZone.ActiveEnergyTotal + activeEnergy
 			idleEnergyTotal = prevZone.IdleEnergyTotal + idleEnergy
 
 			powerF64 := float64(deltaEnergy) / float64(timeDiff)
 			power = Power(powerF64)
-			activePower = Power(powerF64 * nodeCPUUsageRatio)
+			activePower = Power(powerF64 * activeRatio)
 			idlePower = power - activePower
+			if !calibrated {
+				rawPower = power
+			}
+
+			emissionsTotal = prevZone.EmissionsTotal + pm.carbonIntensity.Emissions(deltaEnergy.Joules())
+			costTotal = prevZone.CostTotal + pm.price.Cost(deltaEnergy.Joules())
 		}
 
+		minPower, maxPower := pm.powerRange(zone, absEnergy, now, power)
+
 		newNode.Zones[zone] = NodeUsage{
 			EnergyTotal: absEnergy,
 
 			activeEnergy:      activeEnergy,
+			idleEnergy:        idleEnergy,
 			ActiveEnergyTotal: activeEnergyTotal,
 			IdleEnergyTotal:   idleEnergyTotal,
 
 			Power:       power,
+			RawPower:    rawPower,
 			ActivePower: activePower,
 			IdlePower:   idlePower,
+			MinPower:    minPower,
+			MaxPower:    maxPower,
+			Uncertainty: device.Uncertainty(zone),
+			Wraparounds: wraparounds,
+			Jumps:       jumps,
+
+			EmissionsTotal: emissionsTotal,
+			CostTotal:      costTotal,
 		}
 	}
 
-	return retErr
+	return unavailableZonesError(zones, errs)
 }
 
-// Calculate joules difference handling wraparound
-func calculateEnergyDelta(current, previous, maxJoules Energy) Energy {
-	if current >= previous {
-		return current - previous
+// unavailableZonesError returns an error if none of the zones could be read;
+// otherwise power is computed for the zones that could be read
+func unavailableZonesError(zones []EnergyZone, errs []error) error {
+	if len(zones) > 0 && len(errs) == len(zones) {
+		return fmt.Errorf("no zone could be read: %w", errors.Join(errs...))
 	}
-
-	// counter wraparound
-	if maxJoules > 0 {
-		return (maxJoules - previous) + current
-	}
-
-	return 0 // Unable to calculate delta
+	return nil
 }
 
 // firstNodeRead reads the energy for the first time
 func (pm *PowerMonitor) firstNodeRead(node *Node) error {
+	return pm.firstNodePower(node, pm.nodeUsage())
+}
+
+// firstNodePower reads the energy for the first time, splitting it using usage
+func (pm *PowerMonitor) firstNodePower(node *Node, usage nodeUsage) error {
 	node.Timestamp = pm.clock.Now()
 
-	zones, err := pm.cpu.Zones()
+	zones, err := pm.zones()
 	if err != nil {
 		return err
 	}
-
-	nodeCPUUsageRatio := pm.resources.Node().CPUUsageRatio
-	var retErr error
-	for _, zone := range zones {
-		energy, err := zone.Energy()
+	pm.restoreZones(zones)
+	readings := pm.readZones(zones)
+	pm.refreshCarbonIntensity(node)
+	pm.refreshPrice(node)
+
+	nodeCPUUsageRatio := usage.usageRatio
+	node.CoreTypeUsageRatios = usage.coreTypeUsageRatios
+	var errs []error
+	for i, zone := range zones {
+		energy, err := readings[i].energy, readings[i].err
+		pm.updateZoneSource(zone, err)
 		if err != nil {
-			retErr = errors.Join(err)
-			pm.logger.Warn("Could not read energy for zone", "zone", zone.Name(), "index", zone.Index(), "error", err)
+			errs = append(errs, err)
 			continue
 		}
-		activeEnergy := Energy(float64(energy) * nodeCPUUsageRatio)
+		if pm.sampler != nil {
+			pm.sampler.record(zone, energy, node.Timestamp)
+		}
+		if usage, ok := pm.restoredNodeUsage(zone, energy); ok {
+			// the energy consumed while Kepler was not running is unknown
+			node.Zones[zone] = usage
+			continue
+		}
+
+		activeEnergy := Energy(float64(energy) * pm.activeRatio(zone, nodeCPUUsageRatio))
 		idleEnergy := energy - activeEnergy
 
 		node.Zones[zone] = NodeUsage{
@@ -123,9 +238,11 @@ func (pm *PowerMonitor) firstNodeRead(node *Node) error {
 			ActiveEnergyTotal: activeEnergy,
 			IdleEnergyTotal:   idleEnergy,
 			activeEnergy:      activeEnergy,
+			idleEnergy:        idleEnergy,
+			Uncertainty:       device.Uncertainty(zone),
 			// Power can't be calculated in the first read since we need Δt
 		}
 	}
 
-	return retErr
+	return unavailableZonesError(zones, errs)
 }
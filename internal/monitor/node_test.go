@@ -263,85 +263,37 @@ func TestNodeErrorHandling(t *testing.T) {
 
 		current := NewSnapshot()
 		err := pm.firstNodeRead(current.Node)
-		assert.Error(t, err, "pkg read error must be propagated")
+		assert.NoError(t, err, "zones that can be read are used in degraded mode")
 
 		prev := NewSnapshot()
 		err = pm.calculateNodePower(prev.Node, current.Node)
-		assert.Error(t, err, "pkg read error must be propagated")
+		assert.NoError(t, err, "zones that can be read are used in degraded mode")
 		mockCPUPowerMeter.AssertExpectations(t)
 
-		// Should have zone info for both
+		// Should only have zone info of the zone that could be read
 		assert.NotContains(t, current.Node.Zones, pkg)
 		assert.Contains(t, current.Node.Zones, core)
+
+		sources := pm.sourceList()
+		require.Len(t, sources, 2)
+		assert.Equal(t, Source{Kind: SourceZone, Zone: pkg, Err: device.Classify(assert.AnError)}, sources[0])
+		assert.ErrorIs(t, sources[0].Err, device.ErrTransient)
+		assert.Equal(t, Source{Kind: SourceZone, Zone: core, Available: true}, sources[1])
 	})
 
-	mockResourceInformer.AssertExpectations(t)
-}
+	t.Run("All Zones Energy Read Error", func(t *testing.T) {
+		core.OnEnergy(0, assert.AnError)
 
-// TestCalculateEnergyDelta tests the CalculateEnergyDelta function directly
-func TestCalculateEnergyDelta(t *testing.T) {
-	testCases := []struct {
-		name      string
-		current   Energy
-		previous  Energy
-		maxJoules Energy
-		expected  Energy
-	}{{
-		name:      "Normal",
-		current:   25 * Joule,
-		previous:  20 * Joule,
-		maxJoules: 100 * Joule,
-		expected:  5 * Joule,
-	}, {
-		name:      "Wrap around",
-		current:   10 * Joule,
-		previous:  90 * Joule,
-		maxJoules: 100 * Joule,
-		expected:  20 * Joule, // 100-90 + 10J
-	}, {
-		name:      "Zero values",
-		current:   0 * Joule,
-		previous:  0 * Joule,
-		maxJoules: 100 * Joule,
-		expected:  0 * Joule,
-	}, {
-		name:      "Max value is zero",
-		current:   10 * Joule,
-		previous:  20 * Joule,
-		maxJoules: 0 * Joule,
-		expected:  0 * Joule, // returns 0 if there is no max and there is a wrap
-	}, {
-		name:      "Negative diff but max is negative",
-		current:   2 * Joule,
-		previous:  8 * Joule,
-		maxJoules: 10 * Joule,
-		expected:  4 * Joule, // No wrap correction with negative max
-	}, {
-		name:      "Current equals max",
-		current:   100 * Joule,
-		previous:  90 * Joule,
-		maxJoules: 100 * Joule,
-		expected:  10 * Joule,
-	}, {
-		name:      "Previous equals max",
-		current:   10 * Joule,
-		previous:  100 * Joule,
-		maxJoules: 100 * Joule,
-		expected:  10 * Joule,
-	}, {
-		name:      "Exact wrap",
-		current:   0 * Joule,
-		previous:  100 * Joule,
-		maxJoules: 100 * Joule,
-		expected:  0 * Joule,
-	}}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := calculateEnergyDelta(tc.current, tc.previous, tc.maxJoules)
-			assert.Equal(t, tc.expected, result, "Diff should match expected value")
-		})
-	}
+		current := NewSnapshot()
+		err := pm.firstNodeRead(current.Node)
+		assert.ErrorContains(t, err, "no zone could be read")
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, current.Node.Zones)
+
+		assert.False(t, pm.sourceList()[1].Available)
+	})
+
+	mockResourceInformer.AssertExpectations(t)
 }
 
 // TestNodeActiveEnergyCounterBehavior verifies that ActiveEnergy and IdleEnergy represent interval-based energy attribution,
@@ -663,3 +615,51 @@ func TestNodeActiveEnergyTotalAccumulation(t *testing.T) {
 	mockCPUPowerMeter.AssertExpectations(t)
 	mockResourceInformer.AssertExpectations(t)
 }
+
+func TestNodeEnergyWraparounds(t *testing.T) {
+	pkg := device.NewMockRaplZone("package-0", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 100_000*Joule)
+	mockCPUPowerMeter := &MockCPUPowerMeter{}
+	mockCPUPowerMeter.On("Zones").Return([]EnergyZone{pkg}, nil)
+	mockResourceInformer := &MockResourceInformer{}
+	mockResourceInformer.On("Node").Return(&resource.Node{CPUUsageRatio: 0.5})
+
+	mockClock := test_clock.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	pm := NewPowerMonitor(mockCPUPowerMeter,
+		WithLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))),
+		WithClock(mockClock),
+		WithResourceInformer(mockResourceInformer))
+
+	pkg.OnEnergy(99_900*Joule, nil)
+	prev := NewSnapshot()
+	require.NoError(t, pm.firstNodeRead(prev.Node))
+
+	next := func(elapsed time.Duration, energy Energy) NodeUsage {
+		mockClock.Step(elapsed)
+		pkg.OnEnergy(energy, nil)
+		current := NewSnapshot()
+		require.NoError(t, pm.calculateNodePower(prev.Node, current.Node))
+		prev = current
+		return current.Node.Zones[pkg]
+	}
+
+	usage := next(time.Second, 100*Joule)
+	assert.Equal(t, uint64(1), usage.Wraparounds)
+	assert.Equal(t, 200*Watt, usage.Power, "energy across the wraparound is counted")
+
+	usage = next(time.Second, 400*Joule)
+	assert.Equal(t, uint64(1), usage.Wraparounds, "wraparounds are cumulative")
+	assert.Equal(t, 300*Watt, usage.Power)
+
+	// a wraparound would be 99,999J in 1s, more than a zone can draw
+	usage = next(time.Second, 399*Joule)
+	assert.Equal(t, uint64(1), usage.Wraparounds, "a slight decrease is a jump")
+	assert.Equal(t, uint64(1), usage.Jumps)
+	assert.Equal(t, Power(0), usage.Power, "the energy of a jump is discarded")
+	assert.Equal(t, 100_400*Joule, usage.ActiveEnergyTotal+usage.IdleEnergyTotal)
+
+	// more than half the range of the counter is possible over a long interval
+	usage = next(time.Minute, 60_399*Joule)
+	assert.Equal(t, uint64(1), usage.Jumps, "jumps are cumulative")
+	assert.Equal(t, 1000*Watt, usage.Power)
+	assert.Equal(t, 160_400*Joule, usage.ActiveEnergyTotal+usage.IdleEnergyTotal)
+}
@@ -7,6 +7,11 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/sustainable-computing-io/kepler/internal/carbon"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/device/gpu"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/pricing"
 	"github.com/sustainable-computing-io/kepler/internal/resource"
 	"k8s.io/utils/clock"
 )
@@ -19,6 +24,28 @@ type Opts struct {
 	maxStaleness                 time.Duration
 	maxTerminated                int
 	minTerminatedEnergyThreshold Energy
+	maxTerminatedAge             time.Duration
+	gpus                         []gpu.PowerMeter
+	networkModel                 device.IOModel
+	storageModel                 device.IOModel
+	platform                     device.EnergyZone
+	attribution                  Attribution
+	idlePolicy                   IdlePolicy
+	cpuSockets                   map[int]int
+	maxInterval                  time.Duration
+	idleThreshold                Power
+	sampleInterval               time.Duration
+	carbon                       carbon.Provider
+	tariff                       *pricing.Tariff
+	budgets                      Budgets
+	calibrations                 Calibrations
+	budgetNotifiers              []BudgetNotifier
+	events                       *event.Bus
+	systemdUnits                 bool
+	groups                       bool
+	aggregates                   bool
+	otherWorkloads               bool
+	totals                       *Totals
 }
 
 // NewConfig returns a new Config with defaults set
@@ -31,6 +58,26 @@ func DefaultOpts() Opts {
 		resources:                    nil,
 		maxTerminated:                500,
 		minTerminatedEnergyThreshold: 10 * Joule,
+		maxTerminatedAge:             0,
+		gpus:                         nil,
+		platform:                     nil,
+		attribution:                  NewCPUTimeAttribution(),
+		idlePolicy:                   IdleExcluded,
+		cpuSockets:                   nil,
+		maxInterval:                  0,
+		idleThreshold:                0,
+		sampleInterval:               0,
+		carbon:                       nil,
+		tariff:                       nil,
+		budgets:                      Budgets{},
+		calibrations:                 nil,
+		budgetNotifiers:              nil,
+		events:                       nil,
+		systemdUnits:                 false,
+		groups:                       false,
+		aggregates:                   false,
+		otherWorkloads:               false,
+		totals:                       nil,
 	}
 }
 
@@ -85,3 +132,167 @@ func WithMinTerminatedEnergyThreshold(threshold Energy) OptionFn {
 		o.minTerminatedEnergyThreshold = threshold
 	}
 }
+
+// WithMaxTerminatedAge sets the duration for which terminated workloads are retained;
+// 0 retains terminated workloads only until they are exported
+func WithMaxTerminatedAge(age time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.maxTerminatedAge = age
+	}
+}
+
+// WithGPUMeters sets the (initialized) GPU power meters whose zones are monitored
+// along with the CPU zones
+func WithGPUMeters(meters ...gpu.PowerMeter) OptionFn {
+	return func(o *Opts) {
+		o.gpus = meters
+	}
+}
+
+// WithIOModels sets the models that estimate the power of the network interfaces
+// and block devices of the node from the bytes transferred by processes; a zone
+// is monitored for each model that is set. The resource informer must track I/O.
+func WithIOModels(network, storage device.IOModel) OptionFn {
+	return func(o *Opts) {
+		o.networkModel = network
+		o.storageModel = storage
+	}
+}
+
+// WithPlatformZone sets the zone of the whole platform of the node, e.g. read
+// from its BMC, whose power is attributed to VMs in proportion to their CPU
+// time; nil disables it
+func WithPlatformZone(zone device.EnergyZone) OptionFn {
+	return func(o *Opts) {
+		o.platform = zone
+	}
+}
+
+// WithAttribution sets the strategy used to attribute the power of zones to workloads
+func WithAttribution(a Attribution) OptionFn {
+	return func(o *Opts) {
+		o.attribution = a
+	}
+}
+
+// WithIdlePolicy sets how the idle power of the node is attributed to workloads
+func WithIdlePolicy(p IdlePolicy) OptionFn {
+	return func(o *Opts) {
+		o.idlePolicy = p
+	}
+}
+
+// WithCPUSockets sets the socket of each CPU which is used to attribute the power
+// of per-socket zones to the workloads running on that socket. The resource
+// informer must track the last CPU of processes for this to have any effect.
+func WithCPUSockets(sockets map[int]int) OptionFn {
+	return func(o *Opts) {
+		o.cpuSockets = sockets
+	}
+}
+
+// WithSampleInterval sets the interval at which the power of CPU zones is
+// sampled to track their min and max power within the collection interval.
+// Sampling is disabled if d is 0 or not less than the collection interval.
+func WithSampleInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.sampleInterval = d
+	}
+}
+
+// WithIntervalBackoff lowers the collection frequency while the node is idle,
+// i.e. its power is below idleThreshold, by doubling the collection interval
+// up to maxInterval; the interval is restored as soon as the node is busy.
+// Backoff is disabled if maxInterval is not greater than the interval.
+func WithIntervalBackoff(maxInterval time.Duration, idleThreshold Power) OptionFn {
+	return func(o *Opts) {
+		o.maxInterval = maxInterval
+		o.idleThreshold = idleThreshold
+	}
+}
+
+// WithCarbonProvider sets the provider of the carbon intensity used to compute
+// the emissions of the node and workloads
+func WithCarbonProvider(p carbon.Provider) OptionFn {
+	return func(o *Opts) {
+		o.carbon = p
+	}
+}
+
+// WithTariff sets the tariff used to compute the cost of the energy consumed
+// by the node and workloads
+func WithTariff(t *pricing.Tariff) OptionFn {
+	return func(o *Opts) {
+		o.tariff = t
+	}
+}
+
+// WithBudgets sets the daily energy budgets of the node and of namespaces
+func WithBudgets(b Budgets) OptionFn {
+	return func(o *Opts) {
+		o.budgets = b
+	}
+}
+
+// WithCalibrations sets the calibration of the power of zones, applied to the
+// energy of each interval before it is attributed to workloads
+func WithCalibrations(c Calibrations) OptionFn {
+	return func(o *Opts) {
+		o.calibrations = c
+	}
+}
+
+// WithBudgetNotifiers sets the notifiers called when a budget is exceeded
+func WithBudgetNotifiers(n ...BudgetNotifier) OptionFn {
+	return func(o *Opts) {
+		o.budgetNotifiers = n
+	}
+}
+
+// WithEventBus sets the bus the events of the monitor, e.g. a snapshot being
+// ready or a workload terminating, are published on; nil disables them
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// WithSystemdUnits enables attributing power to the systemd units processes run
+// in; the resource informer must track systemd units
+func WithSystemdUnits(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.systemdUnits = enabled
+	}
+}
+
+// WithGroups enables attributing power to the groups of processes defined in
+// the resource informer
+func WithGroups(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.groups = enabled
+	}
+}
+
+// WithAggregates enables attributing power to the kernel and system aggregates;
+// the resource informer must track aggregates
+func WithAggregates(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.aggregates = enabled
+	}
+}
+
+// WithOtherWorkloads enables reporting the power attributed to filtered out
+// processes, containers and pods as the OtherWorkload of their level
+func WithOtherWorkloads(enabled bool) OptionFn {
+	return func(o *Opts) {
+		o.otherWorkloads = enabled
+	}
+}
+
+// WithTotals sets the cumulative counters of a previous run of Kepler, which the
+// counters of the node and of the containers, VMs and pods continue from
+func WithTotals(t *Totals) OptionFn {
+	return func(o *Opts) {
+		o.totals = t
+	}
+}
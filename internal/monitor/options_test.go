@@ -5,6 +5,7 @@ package monitor
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,3 +30,45 @@ func TestWithMaxTerminated(t *testing.T) {
 		})
 	}
 }
+
+// TestWithMaxTerminatedAge tests the WithMaxTerminatedAge option function
+func TestWithMaxTerminatedAge(t *testing.T) {
+	opts := DefaultOpts()
+	assert.Equal(t, time.Duration(0), opts.maxTerminatedAge, "terminated workloads are retained until exported by default")
+
+	WithMaxTerminatedAge(2 * time.Minute)(&opts)
+	assert.Equal(t, 2*time.Minute, opts.maxTerminatedAge)
+}
+
+// TestWithIntervalBackoff tests the WithIntervalBackoff option function
+func TestWithIntervalBackoff(t *testing.T) {
+	opts := DefaultOpts()
+	assert.Zero(t, opts.maxInterval, "backoff is disabled by default")
+
+	WithIntervalBackoff(time.Minute, 20*Watt)(&opts)
+	assert.Equal(t, time.Minute, opts.maxInterval)
+	assert.Equal(t, 20*Watt, opts.idleThreshold)
+}
+
+func TestWithSampleInterval(t *testing.T) {
+	opts := DefaultOpts()
+	assert.Zero(t, opts.sampleInterval, "sampling is disabled by default")
+
+	WithSampleInterval(time.Second)(&opts)
+	assert.Equal(t, time.Second, opts.sampleInterval)
+}
+
+func TestWithBudgets(t *testing.T) {
+	opts := DefaultOpts()
+	assert.True(t, opts.budgets.isEmpty(), "budgets are disabled by default")
+
+	notifier := &recordingNotifier{}
+	WithBudgets(Budgets{Node: 100 * Joule})(&opts)
+	WithBudgetNotifiers(notifier)(&opts)
+	assert.Equal(t, 100*Joule, opts.budgets.Node)
+	assert.Equal(t, []BudgetNotifier{notifier}, opts.budgetNotifiers)
+
+	pm := NewPowerMonitor(&MockCPUPowerMeter{}, WithBudgets(opts.budgets))
+	assert.NotNil(t, pm.budgets)
+	assert.Nil(t, NewPowerMonitor(&MockCPUPowerMeter{}).budgets)
+}
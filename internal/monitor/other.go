@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"strconv"
+)
+
+// calculateOtherPower adds the OtherWorkload process, container and pod to
+// newSnapshot, holding the power attributed to the workloads of their level
+// that are filtered out, so that the power of the workloads of a level sums up
+// to the power attributed to them by the node. prev is nil on the first read.
+// Only levels with filtered workloads, now or before, get an other workload.
+func (pm *PowerMonitor) calculateOtherPower(prev, newSnapshot *Snapshot) {
+	if !pm.otherWorkloads {
+		return
+	}
+
+	var prevProcess *Process
+	var prevContainer *Container
+	var prevPod *Pod
+	if prev != nil {
+		prevProcess = prev.Processes[OtherWorkload]
+		prevContainer = prev.Containers[OtherWorkload]
+		prevPod = prev.Pods[OtherWorkload]
+	}
+
+	procs := pm.resources.Processes().Filtered
+	if len(procs) > 0 || prevProcess != nil {
+		workloads := make([]Workload, 0, len(procs))
+		for pid, proc := range procs {
+			workloads = append(workloads, Workload{Kind: ProcessWorkload, ID: strconv.Itoa(pid), CPUTimeDelta: proc.CPUTimeDelta})
+		}
+		process := &Process{Comm: OtherWorkload}
+		process.CPUTotalTime, process.Zones = pm.otherUsage(newSnapshot.Node.Zones, workloads, prevProcess.otherTotals())
+		if newSnapshot.Processes == nil {
+			newSnapshot.Processes = make(Processes)
+		}
+		newSnapshot.Processes[OtherWorkload] = process
+	}
+
+	cntrs := pm.resources.Containers().Filtered
+	if len(cntrs) > 0 || prevContainer != nil {
+		workloads := make([]Workload, 0, len(cntrs))
+		for id, c := range cntrs {
+			workloads = append(workloads, Workload{Kind: ContainerWorkload, ID: id, CPUTimeDelta: c.CPUTimeDelta})
+		}
+		container := &Container{ID: OtherWorkload, Name: OtherWorkload}
+		container.CPUTotalTime, container.Zones = pm.otherUsage(newSnapshot.Node.Zones, workloads, prevContainer.otherTotals())
+		if newSnapshot.Containers == nil {
+			newSnapshot.Containers = make(Containers)
+		}
+		newSnapshot.Containers[OtherWorkload] = container
+	}
+
+	pods := pm.resources.Pods().Filtered
+	if len(pods) > 0 || prevPod != nil {
+		workloads := make([]Workload, 0, len(pods))
+		for id, p := range pods {
+			workloads = append(workloads, Workload{Kind: PodWorkload, ID: id, CPUTimeDelta: p.CPUTimeDelta})
+		}
+		pod := &Pod{ID: OtherWorkload, Name: OtherWorkload}
+		pod.CPUTotalTime, pod.Zones = pm.otherUsage(newSnapshot.Node.Zones, workloads, prevPod.otherTotals())
+		if newSnapshot.Pods == nil {
+			newSnapshot.Pods = make(Pods)
+		}
+		newSnapshot.Pods[OtherWorkload] = pod
+	}
+}
+
+// otherTotals holds the cumulative CPU time and usage of an other workload
+type otherTotals struct {
+	cpuTime float64
+	zones   ZoneUsageMap
+}
+
+func (p *Process) otherTotals() otherTotals {
+	if p == nil {
+		return otherTotals{}
+	}
+	return otherTotals{cpuTime: p.CPUTotalTime, zones: p.Zones}
+}
+
+func (c *Container) otherTotals() otherTotals {
+	if c == nil {
+		return otherTotals{}
+	}
+	return otherTotals{cpuTime: c.CPUTotalTime, zones: c.Zones}
+}
+
+func (p *Pod) otherTotals() otherTotals {
+	if p == nil {
+		return otherTotals{}
+	}
+	return otherTotals{cpuTime: p.CPUTotalTime, zones: p.Zones}
+}
+
+// otherUsage returns the CPU time and usage of the other workload of
+// workloads: the sum of the power of workloads in the last interval, with the
+// energy, emissions and cost of the interval added to the totals of prev
+func (pm *PowerMonitor) otherUsage(zones NodeZoneUsageMap, workloads []Workload, prev otherTotals) (float64, ZoneUsageMap) {
+	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
+
+	cpuTime := prev.cpuTime
+	usage := make(ZoneUsageMap, len(zones))
+	for zone, nodeZoneUsage := range zones {
+		u := prev.zones[zone]
+		usage[zone] = Usage{
+			EnergyTotal:    u.EnergyTotal,
+			Uncertainty:    nodeZoneUsage.Uncertainty,
+			EmissionsTotal: u.EmissionsTotal,
+			CostTotal:      u.CostTotal,
+		}
+	}
+
+	interval := make(ZoneUsageMap, len(zones))
+	for _, w := range workloads {
+		cpuTime += w.CPUTimeDelta
+
+		clear(interval)
+		pm.attributeZones(interval, zones, w, nodeCPUTimeDelta, nil)
+		for zone, u := range interval {
+			total := usage[zone]
+			total.EnergyTotal += u.EnergyTotal
+			total.Power += u.Power
+			total.ActivePower += u.ActivePower
+			total.IdlePower += u.IdlePower
+			total.EmissionsTotal += u.EmissionsTotal
+			total.CostTotal += u.CostTotal
+			usage[zone] = total
+		}
+	}
+	return cpuTime, usage
+}
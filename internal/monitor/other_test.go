@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestOtherPowerCalculation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	zones := CreateTestZones()
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(zones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(zones[0], nil)
+
+	resInformer := &MockResourceInformer{}
+	monitor := &PowerMonitor{
+		logger:         logger,
+		cpu:            mockMeter,
+		clock:          fakeClock,
+		resources:      resInformer,
+		maxTerminated:  500,
+		otherWorkloads: true,
+	}
+	require.NoError(t, monitor.Init())
+
+	resInformer.On("Node").Return(&resource.Node{CPUUsageRatio: 0.5, ProcessTotalCPUTimeDelta: 100})
+	resInformer.On("Processes").Return(&resource.Processes{
+		Filtered: map[int]*resource.Process{
+			10: {PID: 10, CPUTimeDelta: 10},
+			20: {PID: 20, CPUTimeDelta: 20},
+		},
+	})
+	resInformer.On("Containers").Return(&resource.Containers{
+		Filtered: map[string]*resource.Container{"c1": {ID: "c1", CPUTimeDelta: 25}},
+	})
+	resInformer.On("Pods").Return(&resource.Pods{})
+
+	t.Run("first read", func(t *testing.T) {
+		snapshot := NewSnapshot()
+		snapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		monitor.calculateOtherPower(nil, snapshot)
+
+		process := snapshot.Processes[OtherWorkload]
+		require.NotNil(t, process, "the power of filtered out processes is reported")
+		assert.Equal(t, OtherWorkload, process.Comm)
+		assert.Equal(t, 30.0, process.CPUTotalTime)
+		for _, zone := range zones {
+			nodeZone := snapshot.Node.Zones[zone]
+			assert.Equal(t, Energy(0.3*float64(nodeZone.activeEnergy)), process.Zones[zone].EnergyTotal)
+			assert.InDelta(t, 0.3*nodeZone.ActivePower.MicroWatts(), process.Zones[zone].Power.MicroWatts(), 1)
+		}
+
+		container := snapshot.Containers[OtherWorkload]
+		require.NotNil(t, container)
+		assert.Equal(t, 25.0, container.CPUTotalTime)
+
+		assert.NotContains(t, snapshot.Pods, OtherWorkload, "no pod is filtered out")
+	})
+
+	t.Run("energy accumulates", func(t *testing.T) {
+		prevSnapshot := NewSnapshot()
+		prevSnapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		prevSnapshot.Processes[OtherWorkload] = &Process{Comm: OtherWorkload, CPUTotalTime: 50, Zones: make(ZoneUsageMap, len(zones))}
+		prevSnapshot.Pods[OtherWorkload] = &Pod{ID: OtherWorkload, CPUTotalTime: 5, Zones: make(ZoneUsageMap, len(zones))}
+		for _, zone := range zones {
+			prevSnapshot.Processes[OtherWorkload].Zones[zone] = Usage{EnergyTotal: 25 * Joule}
+			prevSnapshot.Pods[OtherWorkload].Zones[zone] = Usage{EnergyTotal: 5 * Joule}
+		}
+
+		fakeClock.Step(2 * time.Second)
+		newSnapshot := NewSnapshot()
+		newSnapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		monitor.calculateOtherPower(prevSnapshot, newSnapshot)
+
+		process := newSnapshot.Processes[OtherWorkload]
+		require.NotNil(t, process)
+		assert.Equal(t, 80.0, process.CPUTotalTime)
+		for _, zone := range zones {
+			nodeZone := newSnapshot.Node.Zones[zone]
+			assert.Equal(t, 25*Joule+Energy(0.3*float64(nodeZone.activeEnergy)), process.Zones[zone].EnergyTotal)
+		}
+
+		pod := newSnapshot.Pods[OtherWorkload]
+		require.NotNil(t, pod, "the other pod is reported once it was")
+		assert.Equal(t, 5.0, pod.CPUTotalTime)
+		for _, zone := range zones {
+			assert.Equal(t, 5*Joule, pod.Zones[zone].EnergyTotal)
+			assert.Equal(t, Power(0), pod.Zones[zone].Power)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := &PowerMonitor{logger: logger, resources: &MockResourceInformer{}}
+
+		newSnapshot := NewSnapshot()
+		disabled.calculateOtherPower(nil, newSnapshot)
+		assert.Empty(t, newSnapshot.Processes)
+		assert.Empty(t, newSnapshot.Containers)
+		assert.Empty(t, newSnapshot.Pods)
+	})
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+// calculateOwnerPower sums the power of the running pods of newSnapshot by the
+// workload controlling them, e.g. a Deployment. prev is nil on the first read.
+//
+// The energy of an owner workload accumulates the energy its pods consumed
+// since the previous snapshot, so that its counter doesn't decrease when its
+// pods terminate or are replaced. Pods without a controller are skipped.
+func (pm *PowerMonitor) calculateOwnerPower(prev, newSnapshot *Snapshot) {
+	owners := make(OwnerWorkloads)
+
+	for id, pod := range newSnapshot.Pods {
+		if pod.OwnerKind == "" {
+			continue
+		}
+
+		key := OwnerKey(pod.Namespace, pod.OwnerKind, pod.OwnerName)
+		owner, exists := owners[key]
+		if !exists {
+			owner = &OwnerWorkload{
+				Kind:      pod.OwnerKind,
+				Name:      pod.OwnerName,
+				Namespace: pod.Namespace,
+				Zones:     make(ZoneUsageMap, len(pod.Zones)),
+			}
+			// counters continue from the previous snapshot
+			if prev != nil {
+				if prevOwner, ok := prev.OwnerWorkloads[key]; ok {
+					for zone, usage := range prevOwner.Zones {
+						owner.Zones[zone] = Usage{
+							EnergyTotal:    usage.EnergyTotal,
+							EmissionsTotal: usage.EmissionsTotal,
+							CostTotal:      usage.CostTotal,
+						}
+					}
+				}
+			}
+			owners[key] = owner
+		}
+		owner.Pods++
+
+		var prevZones ZoneUsageMap
+		if prev != nil {
+			if prevPod, ok := prev.Pods[id]; ok {
+				prevZones = prevPod.Zones
+			}
+		}
+
+		for zone, usage := range pod.Zones {
+			prevUsage := prevZones[zone]
+			total := owner.Zones[zone]
+			if usage.EnergyTotal >= prevUsage.EnergyTotal {
+				total.EnergyTotal += usage.EnergyTotal - prevUsage.EnergyTotal
+			}
+			total.EmissionsTotal += max(usage.EmissionsTotal-prevUsage.EmissionsTotal, 0)
+			total.CostTotal += max(usage.CostTotal-prevUsage.CostTotal, 0)
+
+			total.Power += usage.Power
+			total.ActivePower += usage.ActivePower
+			total.IdlePower += usage.IdlePower
+
+			// the relative uncertainty of a sum is at most that of its largest term
+			total.Uncertainty = max(total.Uncertainty, usage.Uncertainty)
+			owner.Zones[zone] = total
+		}
+	}
+
+	newSnapshot.OwnerWorkloads = owners
+	pm.logger.Debug("snapshot updated for owner workloads", "owners", len(owners))
+}
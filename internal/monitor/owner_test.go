@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnerPowerCalculation(t *testing.T) {
+	pm := &PowerMonitor{logger: slog.Default()}
+	pkg := &fakeZone{name: "package", index: 0}
+
+	pod := func(id, ownerKind, ownerName string, joules Energy, watts Power) *Pod {
+		return &Pod{
+			ID: id, Name: id, Namespace: "prod", OwnerKind: ownerKind, OwnerName: ownerName,
+			Zones: ZoneUsageMap{pkg: {EnergyTotal: joules * Joule, Power: watts * Watt, ActivePower: watts * Watt, Uncertainty: 0.1}},
+		}
+	}
+
+	first := NewSnapshot()
+	first.Pods = Pods{
+		"api-1": pod("api-1", "Deployment", "api", 10, 2),
+		"api-2": pod("api-2", "Deployment", "api", 20, 3),
+		"db-0":  pod("db-0", "StatefulSet", "db", 50, 5),
+		"bare":  pod("bare", "", "", 100, 10),
+	}
+	pm.calculateOwnerPower(nil, first)
+
+	require.Len(t, first.OwnerWorkloads, 2, "pods without a controller are skipped")
+	api := first.OwnerWorkloads["prod/Deployment/api"]
+	require.NotNil(t, api)
+	assert.Equal(t, "Deployment", api.Kind)
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, "prod", api.Namespace)
+	assert.Equal(t, 2, api.Pods)
+	assert.Equal(t, Usage{EnergyTotal: 30 * Joule, Power: 5 * Watt, ActivePower: 5 * Watt, Uncertainty: 0.1}, api.Zones[pkg])
+	assert.Equal(t, "prod/Deployment/api", api.StringID())
+
+	// api-1 is replaced by api-3 and api-2 consumes 5 J more
+	second := NewSnapshot()
+	second.Pods = Pods{
+		"api-2": pod("api-2", "Deployment", "api", 25, 1),
+		"api-3": pod("api-3", "Deployment", "api", 4, 4),
+	}
+	pm.calculateOwnerPower(first, second)
+
+	require.Len(t, second.OwnerWorkloads, 1, "workloads without running pods are dropped")
+	api = second.OwnerWorkloads["prod/Deployment/api"]
+	assert.Equal(t, 2, api.Pods)
+	assert.Equal(t, 39*Joule, api.Zones[pkg].EnergyTotal, "energy doesn't decrease when pods terminate")
+	assert.Equal(t, 5*Watt, api.Zones[pkg].Power)
+
+	clone := second.Clone()
+	assert.Equal(t, second.OwnerWorkloads, clone.OwnerWorkloads)
+	assert.NotSame(t, api, clone.OwnerWorkloads["prod/Deployment/api"])
+}
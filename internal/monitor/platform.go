@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// PlatformZone is the name of the zone of the whole platform of the node, read
+// from its BMC
+const PlatformZone = "platform"
+
+// isPlatform returns true if zone is the platform zone
+func (pm *PowerMonitor) isPlatform(zone EnergyZone) bool {
+	return pm.platform != nil && zone == pm.platform
+}
+
+// computePlatformShares computes the share of the platform zone's energy
+// attributable to VMs in proportion to the CPU time of their processes,
+// including processes that exited since the last refresh. The platform is
+// shared by VMs only, so VMs are charged for all of it, including the power
+// of the host; other workloads get no share.
+func (pm *PowerMonitor) computePlatformShares() {
+	if pm.platform == nil {
+		return
+	}
+
+	procs := pm.resources.Processes()
+	running := runningProcesses(procs)
+	pm.platformShares = newWorkloadShares()
+
+	total := 0.0
+	for _, set := range []map[int]*resource.Process{running, procs.Terminated} {
+		for _, proc := range set {
+			if proc.VirtualMachine != nil {
+				total += proc.CPUTimeDelta
+			}
+		}
+	}
+	if total == 0 {
+		return
+	}
+
+	for _, set := range []map[int]*resource.Process{running, procs.Terminated} {
+		for _, proc := range set {
+			if proc.VirtualMachine != nil && proc.CPUTimeDelta > 0 {
+				pm.platformShares.add(proc, proc.CPUTimeDelta/total)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestPlatformPowerAttribution(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	cpuZones := CreateTestZones()
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(cpuZones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(cpuZones[0], nil)
+
+	platform := device.NewMockRaplZone(PlatformZone, 0, "https://bmc", 1_000_000*device.Joule)
+
+	vm1 := &resource.VirtualMachine{ID: "vm-1", Name: "tenant-a"}
+	vm2 := &resource.VirtualMachine{ID: "vm-2", Name: "tenant-b"}
+	procs := &resource.Processes{
+		Running: map[int]*resource.Process{
+			100: {PID: 100, Comm: "qemu-kvm", VirtualMachine: vm1, CPUTimeDelta: 3},
+			200: {PID: 200, Comm: "qemu-kvm", VirtualMachine: vm2, CPUTimeDelta: 1},
+			300: {PID: 300, Comm: "libvirtd", CPUTimeDelta: 4},
+		},
+		Terminated: map[int]*resource.Process{
+			201: {PID: 201, Comm: "qemu-kvm", VirtualMachine: vm2, CPUTimeDelta: 2},
+		},
+	}
+	node := &resource.Node{CPUUsageRatio: 0.5, ProcessTotalCPUTimeDelta: 10}
+
+	resInformer := &MockResourceInformer{}
+	resInformer.On("Node").Return(node, nil)
+	resInformer.On("Processes").Return(procs)
+
+	monitor := &PowerMonitor{
+		logger:        logger,
+		cpu:           mockMeter,
+		clock:         fakeClock,
+		resources:     resInformer,
+		maxTerminated: 500,
+		platform:      platform,
+	}
+	require.NoError(t, monitor.Init())
+	assert.Equal(t, []string{"package-0", "core-0", PlatformZone}, monitor.ZoneNames())
+
+	prev := NewSnapshot()
+	require.NoError(t, monitor.firstNodeRead(prev.Node))
+	monitor.computePlatformShares()
+
+	// 300 W over 10s
+	fakeClock.Step(10 * time.Second)
+	platform.Inc(3000 * device.Joule)
+	newSnapshot := NewSnapshot()
+	require.NoError(t, monitor.calculateNodePower(prev.Node, newSnapshot.Node))
+
+	usage := newSnapshot.Node.Zones[platform]
+	assert.InDelta(t, 300.0, usage.Power.Watts(), 0.001)
+	assert.InDelta(t, 300.0, usage.ActivePower.Watts(), 0.001, "the platform is attributed as a whole")
+	assert.Zero(t, usage.IdlePower)
+
+	// VMs share the platform by the CPU time of their processes, including
+	// processes that exited since the last refresh
+	for _, tc := range []struct {
+		w     Workload
+		ratio float64
+	}{
+		{Workload{Kind: VMWorkload, ID: "vm-1"}, 0.5},
+		{Workload{Kind: VMWorkload, ID: "vm-2"}, 0.5},
+		{Workload{Kind: ProcessWorkload, ID: "100"}, 0.5},
+	} {
+		ratio, ok := monitor.attributionRatio(platform, tc.w, 10)
+		assert.True(t, ok)
+		assert.InDelta(t, tc.ratio, ratio, 0.001)
+	}
+	_, ok := monitor.attributionRatio(platform, Workload{Kind: ProcessWorkload, ID: "300", CPUTimeDelta: 4}, 10)
+	assert.False(t, ok, "processes outside of VMs get no share of the platform")
+
+	// CPU zones continue to be attributed by CPU time
+	ratio, ok := monitor.attributionRatio(cpuZones[0], Workload{Kind: ProcessWorkload, ID: "300", CPUTimeDelta: 4}, 10)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.4, ratio, 0.001)
+
+	t.Run("no VMs", func(t *testing.T) {
+		resInformer := &MockResourceInformer{}
+		resInformer.On("Processes").Return(&resource.Processes{Running: map[int]*resource.Process{
+			300: {PID: 300, Comm: "libvirtd", CPUTimeDelta: 4},
+		}})
+		monitor := &PowerMonitor{resources: resInformer, platform: platform}
+		monitor.computePlatformShares()
+		_, ok := monitor.attributionRatio(platform, Workload{Kind: ProcessWorkload, ID: "300"}, 4)
+		assert.False(t, ok)
+	})
+}
@@ -4,6 +4,8 @@
 package monitor
 
 import (
+	"maps"
+
 	"github.com/sustainable-computing-io/kepler/internal/resource"
 )
 
@@ -18,20 +20,8 @@ func (pm *PowerMonitor) firstPodRead(snapshot *Snapshot) error {
 	for id, p := range running {
 		pod := newPod(p, zones)
 
-		// Calculate initial energy based on CPU ratio * nodeActiveEnergy
-		for zone, nodeZoneUsage := range zones {
-			if nodeZoneUsage.ActivePower == 0 || nodeZoneUsage.activeEnergy == 0 || nodeCPUTimeDelta == 0 {
-				continue
-			}
-
-			cpuTimeRatio := p.CPUTimeDelta / nodeCPUTimeDelta
-			activeEnergy := Energy(cpuTimeRatio * float64(nodeZoneUsage.activeEnergy))
-
-			pod.Zones[zone] = Usage{
-				Power:       Power(0), // No power in first read - no delta time to calculate rate
-				EnergyTotal: activeEnergy,
-			}
-		}
+		// Calculate initial energy as the share of node active energy
+		pm.attributeZones(pod.Zones, zones, Workload{Kind: PodWorkload, ID: id, CPUTimeDelta: p.CPUTimeDelta}, nodeCPUTimeDelta, pm.restoredZones(PodWorkload, id, zones))
 
 		pods[id] = pod
 	}
@@ -44,11 +34,8 @@ func (pm *PowerMonitor) firstPodRead(snapshot *Snapshot) error {
 
 // calculatePodPower calculates pod power for each running pod and handles terminated pods
 func (pm *PowerMonitor) calculatePodPower(prev, newSnapshot *Snapshot) error {
-	// Clear terminated workloads if snapshot has been exported
-	if pm.exported.Load() {
-		pm.logger.Debug("Clearing terminated pods after export")
-		pm.terminatedPodsTracker.Clear()
-	}
+	// Release terminated workloads that are past retention (or exported)
+	pm.terminatedPodsTracker.Prune(pm.exported.Load())
 
 	// Get the current pods
 	pods := pm.resources.Pods()
@@ -64,6 +51,9 @@ func (pm *PowerMonitor) calculatePodPower(prev, newSnapshot *Snapshot) error {
 		// Add to internal tracker (which will handle priority-based retention)
 		// NOTE: Each terminated pod is only added once since a pod cannot be terminated twice
 		pm.terminatedPodsTracker.Add(prevPod.Clone())
+		pm.queueTerminated("pod", id, map[string]string{
+			"name": prevPod.Name, "namespace": prevPod.Namespace,
+		})
 	}
 
 	// Skip if no running pods
@@ -84,36 +74,29 @@ func (pm *PowerMonitor) calculatePodPower(prev, newSnapshot *Snapshot) error {
 	podMap := make(map[string]*Pod, len(pods.Running))
 
 	// For each pod, calculate power for each zone separately
+	zones := newSnapshot.Node.Zones
 	for id, p := range pods.Running {
-		// Create pod power entry with node zones
-		pod := newPod(p, newSnapshot.Node.Zones)
-
-		// Calculate CPU time ratio for this pod
-
-		// For each zone in the node, calculate pod's share
-		for zone, nodeZoneUsage := range newSnapshot.Node.Zones {
-			// Skip zones with zero power to avoid division by zero
-			if nodeZoneUsage.Power == 0 || nodeZoneUsage.activeEnergy == 0 || nodeCPUTimeDelta == 0 {
-				continue
-			}
-
-			cpuTimeRatio := p.CPUTimeDelta / nodeCPUTimeDelta
-			// Calculate pod's share of this zone's power and energy
-			activeEnergy := Energy(float64(nodeZoneUsage.activeEnergy) * cpuTimeRatio)
-			absoluteEnergy := activeEnergy
-
-			// If we have previous data for this pod and zone, add to absolute energy
-			if prev, exists := prev.Pods[id]; exists {
-				if prevUsage, hasZone := prev.Zones[zone]; hasZone {
-					absoluteEnergy += prevUsage.EnergyTotal
-				}
-			}
-			pod.Zones[zone] = Usage{
-				EnergyTotal: absoluteEnergy,
-				Power:       Power(cpuTimeRatio * float64(nodeZoneUsage.ActivePower)),
-			}
+		// Calculate share of each zone's active power and energy; energy
+		// accumulates over the previous snapshot
+		var prevZones ZoneUsageMap
+		prevPod, exists := prev.Pods[id]
+		if exists {
+			prevZones = prevPod.Zones
+		} else {
+			prevZones = pm.restoredZones(PodWorkload, id, zones)
 		}
+		usage := pm.usageBuffer(zones)
+		pm.attributeZones(usage, zones, Workload{Kind: PodWorkload, ID: id, CPUTimeDelta: p.CPUTimeDelta}, nodeCPUTimeDelta, prevZones)
 
+		// share unchanged pods with the previous snapshot (copy-on-write)
+		if exists && prevPod.matches(p) && sameUsage(prevPod.Zones, usage) {
+			podMap[id] = prevPod
+			continue
+		}
+
+		// Create pod power entry with node zones
+		pod := newPod(p, zones)
+		maps.Copy(pod.Zones, usage)
 		podMap[id] = pod
 	}
 
@@ -130,12 +113,28 @@ func (pm *PowerMonitor) calculatePodPower(prev, newSnapshot *Snapshot) error {
 	return nil
 }
 
+// matches returns true if the pod has the same attributes as pod
+func (p *Pod) matches(pod *resource.Pod) bool {
+	return p.ID == pod.ID &&
+		p.Name == pod.Name &&
+		p.Namespace == pod.Namespace &&
+		p.OwnerKind == pod.OwnerKind &&
+		p.OwnerName == pod.OwnerName &&
+		p.CPURequest == pod.CPURequest &&
+		maps.Equal(p.Labels, pod.Labels) &&
+		p.CPUTotalTime == pod.CPUTotalTime
+}
+
 // newPod creates a new Pod struct with initialized zones from resource.Pod
 func newPod(pod *resource.Pod, zones NodeZoneUsageMap) *Pod {
 	p := &Pod{
 		ID:           pod.ID,
 		Name:         pod.Name,
 		Namespace:    pod.Namespace,
+		Labels:       pod.Labels,
+		OwnerKind:    pod.OwnerKind,
+		OwnerName:    pod.OwnerName,
+		CPURequest:   pod.CPURequest,
 		CPUTotalTime: pod.CPUTotalTime,
 		Zones:        make(ZoneUsageMap, len(zones)),
 	}
@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+// refreshPrice updates the price of electricity used to compute the cost of
+// the energy consumed in this interval, as per the tariff at the time of the
+// reading
+func (pm *PowerMonitor) refreshPrice(node *Node) {
+	pm.price = 0
+	if pm.tariff == nil {
+		return
+	}
+
+	pm.price = pm.tariff.PriceAt(node.Timestamp)
+	node.Price = float64(pm.price)
+}
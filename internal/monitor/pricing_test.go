@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/pricing"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestRefreshPrice(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		pm := &PowerMonitor{price: 0.2}
+		node := &Node{}
+		pm.refreshPrice(node)
+		assert.Zero(t, pm.price)
+		assert.Zero(t, node.Price)
+	})
+
+	t.Run("time of day", func(t *testing.T) {
+		pm := &PowerMonitor{tariff: &pricing.Tariff{
+			Price:   0.10,
+			Periods: []pricing.Period{{Start: 17 * time.Hour, End: 21 * time.Hour, Price: 0.30}},
+		}}
+		node := &Node{Timestamp: time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)}
+		pm.refreshPrice(node)
+		assert.Equal(t, pricing.Price(0.30), pm.price)
+		assert.Equal(t, 0.30, node.Price)
+
+		node = &Node{Timestamp: time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)}
+		pm.refreshPrice(node)
+		assert.Equal(t, 0.10, node.Price)
+	})
+}
+
+func TestAttributeZonesCost(t *testing.T) {
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+	zones := NodeZoneUsageMap{
+		pkg: {ActivePower: 40 * Watt, activeEnergy: 3600 * Joule},
+	}
+
+	pm := &PowerMonitor{price: 0.20}
+	prev := ZoneUsageMap{
+		pkg: {EnergyTotal: 100 * Joule, CostTotal: 1},
+	}
+
+	usage := ZoneUsageMap{}
+	pm.attributeZones(usage, zones, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 1}, 4, prev)
+
+	// 1/4 of 3600 J = 0.00025 kWh at 0.20/kWh = 0.00005
+	assert.InDelta(t, 1.00005, usage[pkg].CostTotal, 1e-9)
+}
+
+func TestNodeCost(t *testing.T) {
+	pkg := device.NewMockRaplZone("package-0", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1_000_000_000*Joule)
+
+	meter := &MockCPUPowerMeter{}
+	meter.On("Zones").Return([]EnergyZone{pkg}, nil)
+	meter.On("PrimaryEnergyZone").Return(pkg, nil)
+
+	resources := &MockResourceInformer{}
+	resources.On("Node").Return(&resource.Node{CPUUsageRatio: 0.5}, nil)
+
+	// peak price from 17:00 to 21:00
+	fakeClock := testingclock.NewFakeClock(time.Date(2025, 6, 2, 16, 0, 0, 0, time.UTC))
+	pm := &PowerMonitor{
+		logger:    slog.Default(),
+		cpu:       meter,
+		clock:     fakeClock,
+		resources: resources,
+		tariff: &pricing.Tariff{
+			Price:   0.10,
+			Periods: []pricing.Period{{Start: 17 * time.Hour, End: 21 * time.Hour, Price: 0.30}},
+		},
+	}
+	require.NoError(t, pm.Init())
+
+	prev := NewSnapshot()
+	require.NoError(t, pm.firstNodeRead(prev.Node))
+	assert.Equal(t, 0.10, prev.Node.Price)
+	assert.Zero(t, prev.Node.Zones[pkg].CostTotal, "cost starts when monitoring starts")
+
+	// 1 kWh at the peak price, then 1 kWh at the regular price
+	for _, step := range []time.Duration{time.Hour, 4 * time.Hour} {
+		pkg.Inc(3600_000 * Joule)
+		fakeClock.Step(step)
+
+		current := NewSnapshot()
+		require.NoError(t, pm.calculateNodePower(prev.Node, current.Node))
+		prev = current
+	}
+	assert.Equal(t, 0.10, prev.Node.Price)
+	assert.InDelta(t, 0.40, prev.Node.Zones[pkg].CostTotal, 1e-9)
+}
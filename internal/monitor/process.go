@@ -5,6 +5,8 @@ package monitor
 
 import (
 	"fmt"
+	"maps"
+	"strconv"
 
 	"github.com/sustainable-computing-io/kepler/internal/resource"
 )
@@ -20,20 +22,8 @@ func (pm *PowerMonitor) firstProcessRead(snapshot *Snapshot) error {
 	for _, proc := range running {
 		process := newProcess(proc, zones)
 
-		// Calculate initial energy based on CPU ratio * nodeActiveEnergy
-		for zone, nodeZoneUsage := range zones {
-			if nodeZoneUsage.ActivePower == 0 || nodeZoneUsage.activeEnergy == 0 || nodeCPUTimeDelta == 0 {
-				continue
-			}
-
-			cpuTimeRatio := proc.CPUTimeDelta / nodeCPUTimeDelta
-			activeEnergy := Energy(cpuTimeRatio * float64(nodeZoneUsage.activeEnergy))
-
-			process.Zones[zone] = Usage{
-				Power:       Power(0), // No power in first read - no delta time to calculate rate
-				EnergyTotal: activeEnergy,
-			}
-		}
+		// Calculate initial energy as the share of node active energy
+		pm.attributeZones(process.Zones, zones, Workload{Kind: ProcessWorkload, ID: process.StringID(), CPUTimeDelta: proc.CPUTimeDelta}, nodeCPUTimeDelta, nil)
 
 		processes[process.StringID()] = process
 	}
@@ -52,7 +42,14 @@ func newProcess(proc *resource.Process, zones NodeZoneUsageMap) *Process {
 		Exe:          proc.Exe,
 		Type:         proc.Type,
 		CPUTotalTime: proc.CPUTotalTime,
+		HWCounters:   proc.HWCounters,
+		IO:           proc.IO,
 		Zones:        make(ZoneUsageMap, len(zones)),
+
+		CmdLine:   proc.CmdLine,
+		UID:       proc.UID,
+		User:      proc.User,
+		ParentPID: proc.ParentPID,
 	}
 
 	// Initialize each zone with zero values
@@ -75,33 +72,66 @@ func newProcess(proc *resource.Process, zones NodeZoneUsageMap) *Process {
 	return process
 }
 
+// matches returns true if the process has the same attributes as proc
+func (p *Process) matches(proc *resource.Process) bool {
+	containerID, vmID := "", ""
+	if proc.Container != nil {
+		containerID = proc.Container.ID
+	}
+	if proc.VirtualMachine != nil {
+		vmID = proc.VirtualMachine.ID
+	}
+
+	return p.PID == proc.PID &&
+		p.Comm == proc.Comm &&
+		p.Exe == proc.Exe &&
+		p.Type == proc.Type &&
+		p.CPUTotalTime == proc.CPUTotalTime &&
+		p.HWCounters == proc.HWCounters &&
+		p.IO == proc.IO &&
+		p.ContainerID == containerID &&
+		p.VirtualMachineID == vmID &&
+		p.CmdLine == proc.CmdLine &&
+		p.UID == proc.UID &&
+		p.User == proc.User &&
+		p.ParentPID == proc.ParentPID
+}
+
 // calculateProcessPower calculates process power for each running process
 func (pm *PowerMonitor) calculateProcessPower(prev, newSnapshot *Snapshot) error {
-	// Clear terminated workloads if snapshot has been exported
-	if pm.exported.Load() {
-		pm.logger.Debug("Clearing terminated processes after export")
-		pm.terminatedProcessesTracker.Clear()
-	}
+	// Release terminated workloads that are past retention (or exported)
+	pm.terminatedProcessesTracker.Prune(pm.exported.Load())
 
 	procs := pm.resources.Processes()
 
+	zones := newSnapshot.Node.Zones
+	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
+
 	pm.logger.Debug("Processing terminated processes", "terminated", len(procs.Terminated))
-	for pid := range procs.Terminated {
+	for pid, proc := range procs.Terminated {
 		pidStr := fmt.Sprintf("%d", pid)
 		prevProcess, exists := prev.Processes[pidStr]
 		if !exists {
 			continue
 		}
 
+		terminated := prevProcess.Clone()
+		// attribute the CPU time used and bytes transferred between the previous
+		// refresh and exit
+		if proc.CPUTimeDelta > 0 || proc.IODelta != (IOCounters{}) {
+			terminated.CPUTotalTime += proc.CPUTimeDelta
+			pm.attributeZones(terminated.Zones, zones, Workload{Kind: ProcessWorkload, ID: pidStr, CPUTimeDelta: proc.CPUTimeDelta}, nodeCPUTimeDelta, prevProcess.Zones)
+		}
+		terminated.HWCounters = terminated.HWCounters.Add(proc.HWCountersDelta)
+		terminated.IO = terminated.IO.Add(proc.IODelta)
+
 		// Add to internal tracker (which will handle priority-based retention)
 		// NOTE: Each terminated process is only added once since a process cannot be terminated twice
-		pm.terminatedProcessesTracker.Add(prevProcess.Clone())
+		pm.terminatedProcessesTracker.Add(terminated)
 	}
 
 	running := procs.Running
 
-	zones := newSnapshot.Node.Zones
-	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
 	pm.logger.Debug("Calculating Process power",
 		"node.cpu.time", nodeCPUTimeDelta,
 		"running", len(running),
@@ -115,36 +145,28 @@ func (pm *PowerMonitor) calculateProcessPower(prev, newSnapshot *Snapshot) error
 		pm.logger.Warn("No running processes found, skipping running process power calculation")
 	}
 
-	for _, proc := range running {
-		process := newProcess(proc, zones)
-		pid := process.StringID() // to string
-
-		// For each zone in the node, calculate process's share
-		for zone, nodeZoneUsage := range zones {
-			if nodeZoneUsage.ActivePower == 0 || nodeZoneUsage.activeEnergy == 0 || nodeCPUTimeDelta == 0 {
-				continue
-			}
-
-			cpuTimeRatio := proc.CPUTimeDelta / nodeCPUTimeDelta
-			// Calculate energy  for this interval
-			activeEnergy := Energy(cpuTimeRatio * float64(nodeZoneUsage.activeEnergy))
-
-			// Calculate absolute energy based on previous data
-			absoluteEnergy := activeEnergy
-			if prev, exists := prev.Processes[pid]; exists {
-				if prevUsage, hasZone := prev.Zones[zone]; hasZone {
-					absoluteEnergy += prevUsage.EnergyTotal
-				}
-			}
-
-			// Calculate process's share of this zone's power and energy
-			process.Zones[zone] = Usage{
-				Power:       Power(cpuTimeRatio * nodeZoneUsage.ActivePower.MicroWatts()),
-				EnergyTotal: absoluteEnergy,
-			}
+	for pidInt, proc := range running {
+		pid := strconv.Itoa(pidInt)
+
+		// Calculate share of each zone's active power and energy; energy
+		// accumulates over the previous snapshot
+		var prevZones ZoneUsageMap
+		prevProcess, exists := prev.Processes[pid]
+		if exists {
+			prevZones = prevProcess.Zones
+		}
+		usage := pm.usageBuffer(zones)
+		pm.attributeZones(usage, zones, Workload{Kind: ProcessWorkload, ID: pid, CPUTimeDelta: proc.CPUTimeDelta}, nodeCPUTimeDelta, prevZones)
+
+		// share unchanged processes with the previous snapshot (copy-on-write)
+		if exists && prevProcess.matches(proc) && sameUsage(prevProcess.Zones, usage) {
+			processMap[pid] = prevProcess
+			continue
 		}
 
-		processMap[process.StringID()] = process
+		process := newProcess(proc, zones)
+		maps.Copy(process.Zones, usage)
+		processMap[pid] = process
 	}
 
 	// Update the snapshot of running processes
@@ -479,7 +479,7 @@ func TestTerminatedProcessTracking(t *testing.T) {
 				456: {PID: 456, Comm: "process2", Exe: "/usr/bin/process2", CPUTotalTime: 170.0, CPUTimeDelta: 20.0},
 			},
 			Terminated: map[int]*resource.Process{
-				123: {PID: 123, Comm: "process1", Exe: "/usr/bin/process1", CPUTotalTime: 130.0, CPUTimeDelta: 30.0},
+				123: {PID: 123, Comm: "process1", Exe: "/usr/bin/process1", CPUTotalTime: 130.0, CPUTimeDelta: 0},
 			},
 		}
 
@@ -534,6 +534,69 @@ func TestTerminatedProcessTracking(t *testing.T) {
 		resInformer.AssertExpectations(t)
 	})
 
+	t.Run("terminated process CPU time since last refresh", func(t *testing.T) {
+		resInformer := &MockResourceInformer{}
+		monitor := &PowerMonitor{
+			logger:        logger,
+			cpu:           &MockCPUPowerMeter{},
+			clock:         fakeClock,
+			resources:     resInformer,
+			maxTerminated: 500,
+		}
+		monitor.terminatedProcessesTracker = NewTerminatedResourceTracker[*Process](zones[0], 500, 0, logger)
+
+		prev := NewSnapshot()
+		prev.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		prev.Processes["123"] = &Process{
+			PID: 123, Comm: "process1", CPUTotalTime: 100.0,
+			HWCounters: HWCounters{Instructions: 1000, Cycles: 500},
+			Zones:      make(ZoneUsageMap, len(zones)),
+		}
+		for _, zone := range zones {
+			prev.Processes["123"].Zones[zone] = Usage{EnergyTotal: 25 * Joule, Power: 5 * Watt}
+		}
+
+		snapshot := NewSnapshot()
+		snapshot.Node = createNodeSnapshot(zones, fakeClock.Now().Add(time.Second), 0.5)
+
+		// process 123 used 10s of CPU time before exiting
+		procs := &resource.Processes{
+			Running: map[int]*resource.Process{
+				456: {PID: 456, Comm: "process2", CPUTotalTime: 170.0, CPUTimeDelta: 30.0},
+			},
+			Terminated: map[int]*resource.Process{
+				123: {
+					PID: 123, Comm: "process1", CPUTotalTime: 100.0, CPUTimeDelta: 10.0,
+					HWCountersDelta: resource.HWCounters{Instructions: 200, Cycles: 100},
+				},
+			},
+		}
+		resInformer.On("Node").Return(&resource.Node{ProcessTotalCPUTimeDelta: 40.0}, nil)
+		resInformer.On("Processes").Return(procs).Once()
+
+		err := monitor.calculateProcessPower(prev, snapshot)
+		require.NoError(t, err)
+
+		terminated := monitor.terminatedProcessesTracker.Items()
+		require.Contains(t, terminated, "123")
+		proc := terminated["123"]
+		assert.Equal(t, 110.0, proc.CPUTotalTime)
+		assert.Equal(t, HWCounters{Instructions: 1200, Cycles: 600}, proc.HWCounters, "events counted until exit")
+		for _, zone := range zones {
+			node := snapshot.Node.Zones[zone]
+			usage := proc.Zones[zone]
+			assert.InDelta(t, (25*Joule + node.activeEnergy/4).Joules(), usage.EnergyTotal.Joules(), 1e-6)
+			assert.InDelta(t, (node.ActivePower / 4).Watts(), usage.Power.Watts(), 1e-6)
+		}
+
+		// the share of the terminated process is not given to running processes
+		for _, zone := range zones {
+			node := snapshot.Node.Zones[zone]
+			assert.InDelta(t, (node.ActivePower * 3 / 4).Watts(), snapshot.Processes["456"].Zones[zone].Power.Watts(), 1e-6)
+		}
+		resInformer.AssertExpectations(t)
+	})
+
 	t.Run("terminated process cleanup after export", func(t *testing.T) {
 		mockMeter := &MockCPUPowerMeter{}
 		mockMeter.On("Zones").Return(zones, nil)
@@ -689,8 +752,8 @@ func TestTerminatedProcessTracking(t *testing.T) {
 				300: {PID: 300, Comm: "proc3", CPUTimeDelta: 35.0}, // Still running
 			},
 			Terminated: map[int]*resource.Process{
-				100: {PID: 100, Comm: "proc1", CPUTimeDelta: 10.0},
-				200: {PID: 200, Comm: "proc2", CPUTimeDelta: 20.0},
+				100: {PID: 100, Comm: "proc1", CPUTimeDelta: 0},
+				200: {PID: 200, Comm: "proc2", CPUTimeDelta: 0},
 			},
 		}
 
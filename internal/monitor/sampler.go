@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+)
+
+// powerSampler tracks the min and max power of zones within a collection
+// interval by reading their energy more frequently than the interval. Power
+// computed over the whole interval is an average that hides short spikes.
+//
+// NOTE: samples are recorded by the sampling loop and taken by the refresh,
+// which run in different goroutines
+type powerSampler struct {
+	mu    sync.Mutex
+	zones map[EnergyZone]*zoneSamples
+}
+
+// zoneSamples holds the last energy reading of a zone and the min and max
+// power sampled since the stats were last taken
+type zoneSamples struct {
+	energy   Energy
+	readAt   time.Time
+	min, max Power
+	count    int
+}
+
+func newPowerSampler() *powerSampler {
+	return &powerSampler{zones: make(map[EnergyZone]*zoneSamples)}
+}
+
+// record computes the power of the zone since its previous reading and
+// updates the min and max power of the interval
+func (s *powerSampler) record(zone EnergyZone, energy Energy, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zs, ok := s.zones[zone]
+	if !ok {
+		s.zones[zone] = &zoneSamples{energy: energy, readAt: now}
+		return
+	}
+
+	dt := now.Sub(zs.readAt).Seconds()
+	if dt <= 0 {
+		return
+	}
+
+	delta, change := device.EnergyDelta(energy, zs.energy, zone.MaxEnergy(), device.MaxEnergyDelta(now.Sub(zs.readAt)))
+	if change == device.CounterJumped {
+		// the power of the sample is unknown
+		zs.energy, zs.readAt = energy, now
+		return
+	}
+	power := Power(float64(delta) / dt)
+	if zs.count == 0 {
+		zs.min, zs.max = power, power
+	} else {
+		zs.min, zs.max = min(zs.min, power), max(zs.max, power)
+	}
+	zs.count++
+	zs.energy, zs.readAt = energy, now
+}
+
+// take records the reading of the zone at the end of the interval and returns
+// the min and max power sampled within the interval. ok is false if no power
+// was sampled, e.g. on the first reading of the zone.
+func (s *powerSampler) take(zone EnergyZone, energy Energy, now time.Time) (minPower, maxPower Power, ok bool) {
+	s.record(zone, energy, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zs := s.zones[zone]
+	if zs.count == 0 {
+		return 0, 0, false
+	}
+	minPower, maxPower = zs.min, zs.max
+	zs.min, zs.max, zs.count = 0, 0, 0
+	return minPower, maxPower, true
+}
+
+// powerRange returns the min and max power of the zone within the interval
+// ending now; without sampling the interval average power is both min and max
+func (pm *PowerMonitor) powerRange(zone EnergyZone, energy Energy, now time.Time, avg Power) (Power, Power) {
+	if pm.sampler == nil {
+		return avg, avg
+	}
+	minPower, maxPower, ok := pm.sampler.take(zone, energy, now)
+	if !ok {
+		return avg, avg
+	}
+	if c, calibrated := pm.calibrations[zone.Name()]; calibrated {
+		// the power is sampled from the zone before calibration
+		return c.correct(minPower), c.correct(maxPower)
+	}
+	return minPower, maxPower
+}
+
+// samplingLoop samples the power of CPU zones every sampleInterval until the
+// collection is cancelled
+func (pm *PowerMonitor) samplingLoop() {
+	ticker := pm.clock.NewTicker(pm.sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			pm.samplePower()
+		case <-pm.collectionCtx.Done():
+			return
+		}
+	}
+}
+
+// samplePower records the energy of all CPU zones
+func (pm *PowerMonitor) samplePower() {
+	zones, err := pm.cpu.Zones()
+	if err != nil {
+		pm.logger.Debug("Could not sample zones", "error", err)
+		return
+	}
+
+	for _, zone := range zones {
+		energy, err := zone.Energy()
+		if err != nil {
+			pm.logger.Debug("Could not sample energy for zone", "zone", zone.Name(), "error", err)
+			continue
+		}
+		pm.sampler.record(zone, energy, pm.clock.Now())
+	}
+}
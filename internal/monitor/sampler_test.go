@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestPowerSampler(t *testing.T) {
+	zone := device.NewMockRaplZone("package-0", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*Joule)
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	s := newPowerSampler()
+
+	t.Run("first reading", func(t *testing.T) {
+		_, _, ok := s.take(zone, 100*Joule, start)
+		assert.False(t, ok, "power can't be sampled without a previous reading")
+	})
+
+	t.Run("min and max within interval", func(t *testing.T) {
+		s.record(zone, 110*Joule, start.Add(time.Second))   // 10 W
+		s.record(zone, 190*Joule, start.Add(2*time.Second)) // 80 W
+		s.record(zone, 195*Joule, start.Add(3*time.Second)) // 5 W
+
+		minPower, maxPower, ok := s.take(zone, 215*Joule, start.Add(4*time.Second)) // 20 W
+		require.True(t, ok)
+		assert.InDelta(t, 5, minPower.Watts(), 1e-9)
+		assert.InDelta(t, 80, maxPower.Watts(), 1e-9)
+	})
+
+	t.Run("stats are reset after take", func(t *testing.T) {
+		minPower, maxPower, ok := s.take(zone, 245*Joule, start.Add(5*time.Second)) // 30 W
+		require.True(t, ok)
+		assert.InDelta(t, 30, minPower.Watts(), 1e-9)
+		assert.InDelta(t, 30, maxPower.Watts(), 1e-9)
+	})
+
+	t.Run("counter wraparound", func(t *testing.T) {
+		s.record(zone, 745*Joule, start.Add(6*time.Second)) // 500 W
+		s.record(zone, 5*Joule, start.Add(7*time.Second))   // 255 J to wrap + 5 J
+		minPower, maxPower, ok := s.take(zone, 5*Joule, start.Add(7*time.Second))
+		require.True(t, ok)
+		assert.InDelta(t, 260, minPower.Watts(), 1e-9)
+		assert.InDelta(t, 500, maxPower.Watts(), 1e-9)
+	})
+
+	t.Run("counter jump", func(t *testing.T) {
+		// a wraparound would be 999 J in 1ms, more than a zone can draw
+		s.record(zone, 4*Joule, start.Add(7*time.Second+time.Millisecond)) // decreased; not sampled
+		minPower, maxPower, ok := s.take(zone, 14*Joule, start.Add(8*time.Second+time.Millisecond))
+		require.True(t, ok)
+		assert.InDelta(t, 10, minPower.Watts(), 1e-9)
+		assert.InDelta(t, 10, maxPower.Watts(), 1e-9)
+	})
+}
+
+func TestNodePowerRange(t *testing.T) {
+	pkg := device.NewMockRaplZone("package-0", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*Joule)
+	zones := []EnergyZone{pkg}
+
+	newMonitor := func(sampleInterval time.Duration) (*PowerMonitor, *testingclock.FakeClock) {
+		meter := &MockCPUPowerMeter{}
+		meter.On("Zones").Return(zones, nil)
+
+		informer := &MockResourceInformer{}
+		informer.On("Node").Return(&resource.Node{CPUUsageRatio: 0.5})
+
+		clock := testingclock.NewFakeClock(time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC))
+		pm := NewPowerMonitor(meter,
+			WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			WithClock(clock),
+			WithResourceInformer(informer),
+			WithInterval(5*time.Second),
+			WithSampleInterval(sampleInterval),
+		)
+		return pm, clock
+	}
+
+	t.Run("sampled", func(t *testing.T) {
+		pm, clock := newMonitor(time.Second)
+		require.NotNil(t, pm.sampler)
+
+		prev := NewSnapshot()
+		pkg.OnEnergy(100*Joule, nil)
+		require.NoError(t, pm.firstNodeRead(prev.Node))
+
+		// 10 W, 90 W and 0 W for a second each, then 10 W for 2 seconds
+		for _, energy := range []Energy{110 * Joule, 200 * Joule, 200 * Joule} {
+			clock.Step(time.Second)
+			pkg.OnEnergy(energy, nil)
+			pm.samplePower()
+		}
+		clock.Step(2 * time.Second)
+		pkg.OnEnergy(220*Joule, nil)
+
+		current := NewSnapshot()
+		require.NoError(t, pm.calculateNodePower(prev.Node, current.Node))
+
+		usage := current.Node.Zones[pkg]
+		assert.InDelta(t, 24, usage.Power.Watts(), 1e-9, "power is the average over the interval")
+		assert.InDelta(t, 0, usage.MinPower.Watts(), 1e-9)
+		assert.InDelta(t, 90, usage.MaxPower.Watts(), 1e-9)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		pm, clock := newMonitor(0)
+		assert.Nil(t, pm.sampler)
+
+		prev := NewSnapshot()
+		pkg.OnEnergy(100*Joule, nil)
+		require.NoError(t, pm.firstNodeRead(prev.Node))
+
+		clock.Step(5 * time.Second)
+		pkg.OnEnergy(200*Joule, nil)
+
+		current := NewSnapshot()
+		require.NoError(t, pm.calculateNodePower(prev.Node, current.Node))
+
+		usage := current.Node.Zones[pkg]
+		assert.InDelta(t, 20, usage.Power.Watts(), 1e-9)
+		assert.Equal(t, usage.Power, usage.MinPower)
+		assert.Equal(t, usage.Power, usage.MaxPower)
+	})
+
+	t.Run("sample interval not less than interval", func(t *testing.T) {
+		pm, _ := newMonitor(5 * time.Second)
+		assert.Nil(t, pm.sampler)
+	})
+}
+
+func TestSamplingLoop(t *testing.T) {
+	pkg := device.NewMockRaplZone("package-0", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*Joule)
+	meter := &MockCPUPowerMeter{}
+	meter.On("Zones").Return([]EnergyZone{pkg}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := testingclock.NewFakeClock(time.Now())
+	pm := &PowerMonitor{
+		logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cpu:              meter,
+		clock:            clock,
+		sampleInterval:   time.Second,
+		sampler:          newPowerSampler(),
+		collectionCtx:    ctx,
+		collectionCancel: cancel,
+	}
+
+	done := make(chan struct{})
+	go func() {
+		pm.samplingLoop()
+		close(done)
+	}()
+
+	require.Eventually(t, clock.HasWaiters, time.Second, 10*time.Millisecond)
+	clock.Step(time.Second)
+
+	assert.Eventually(t, func() bool {
+		pm.sampler.mu.Lock()
+		defer pm.sampler.mu.Unlock()
+		return pm.sampler.zones[pkg] != nil
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sampling loop did not stop")
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// socketOf returns the socket of a zone and false if the zone isn't specific
+// to a socket or the CPU topology is unknown
+func (pm *PowerMonitor) socketOf(zone EnergyZone) (int, bool) {
+	if len(pm.cpuSockets) == 0 {
+		return 0, false
+	}
+	sz, ok := zone.(*device.SocketZone)
+	if !ok {
+		return 0, false
+	}
+	return sz.Socket(), true
+}
+
+// computeSocketShares computes the share of each socket's active energy
+// attributable to running workloads. Processes are assigned to the socket of the
+// CPU they last ran on, or to the socket their container is pinned to, and share
+// the socket's energy in proportion to their CPU time. Containers, VMs and pods
+// get the sum of the shares of their processes.
+func (pm *PowerMonitor) computeSocketShares() {
+	if len(pm.cpuSockets) == 0 {
+		return
+	}
+
+	running := runningProcesses(pm.resources.Processes())
+	containers := pm.resources.Containers().Running
+	sockets := make(map[int]int, len(running)) // by PID
+	pinned := map[string]pinnedSocket{}        // by container ID
+	totals := map[int]float64{}
+	for _, proc := range running {
+		socket, ok := pm.socketOfProcess(proc, containers, pinned)
+		if !ok {
+			continue
+		}
+		sockets[proc.PID] = socket
+		totals[socket] += proc.CPUTimeDelta
+	}
+
+	pm.socketShares = make(map[int]workloadShares, len(totals))
+	for socket := range totals {
+		pm.socketShares[socket] = newWorkloadShares()
+	}
+
+	for _, proc := range running {
+		socket, ok := sockets[proc.PID]
+		if !ok || totals[socket] == 0 || proc.CPUTimeDelta == 0 {
+			continue
+		}
+		pm.socketShares[socket].add(proc, proc.CPUTimeDelta/totals[socket])
+	}
+}
+
+// pinnedSocket is the socket a container is pinned to, if any
+type pinnedSocket struct {
+	socket int
+	ok     bool
+}
+
+// socketOfProcess returns the socket a process ran on since the last refresh.
+// Processes of containers whose cpuset only holds CPUs of one socket ran on that
+// socket, wherever they last ran; the CPU a process last ran on is only sampled
+// at the refresh and misses the sockets it ran on before. Other processes are
+// assigned to the socket of the CPU they last ran on. pinned caches the socket
+// of containers.
+func (pm *PowerMonitor) socketOfProcess(proc *resource.Process, containers map[string]*resource.Container,
+	pinned map[string]pinnedSocket,
+) (int, bool) {
+	if proc.Container != nil {
+		p, seen := pinned[proc.Container.ID]
+		if !seen {
+			if c, ok := containers[proc.Container.ID]; ok && len(c.CPUs) > 0 {
+				p.socket, p.ok = pm.cpuSetSocket(c.CPUs)
+			}
+			pinned[proc.Container.ID] = p
+		}
+		if p.ok {
+			return p.socket, true
+		}
+	}
+	socket, ok := pm.cpuSockets[proc.LastCPU]
+	return socket, ok
+}
+
+// cpuSetSocket returns the socket of the CPUs of a cpuset and false if they
+// span sockets or the socket of a CPU is unknown
+func (pm *PowerMonitor) cpuSetSocket(cpus []int) (int, bool) {
+	socket := -1
+	for _, cpu := range cpus {
+		s, ok := pm.cpuSockets[cpu]
+		if !ok || (socket != -1 && s != socket) {
+			return 0, false
+		}
+		socket = s
+	}
+	return socket, socket != -1
+}
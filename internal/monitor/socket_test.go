@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+func TestSocketAttribution(t *testing.T) {
+	cntr := &resource.Container{ID: "container-1"}
+	procs := &resource.Processes{
+		Running: map[int]*resource.Process{
+			1: {PID: 1, Container: cntr, LastCPU: 0, CPUTimeDelta: 3},
+			2: {PID: 2, LastCPU: 1, CPUTimeDelta: 1},
+			3: {PID: 3, Container: cntr, LastCPU: 2, CPUTimeDelta: 2},
+			4: {PID: 4, LastCPU: 3, CPUTimeDelta: 2},
+		},
+	}
+	resInformer := &MockResourceInformer{}
+	resInformer.On("Processes").Return(procs)
+	resInformer.On("Containers").Return(&resource.Containers{
+		Running: map[string]*resource.Container{"container-1": {ID: "container-1"}},
+	})
+
+	// cpus 0, 1 on socket 0 and cpus 2, 3 on socket 1
+	sockets := map[int]int{0: 0, 1: 0, 2: 1, 3: 1}
+	pkg0 := device.NewSocketZone(device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl:0", 1000*Joule))
+	pkg1 := device.NewSocketZone(device.NewMockRaplZone("package", 1, "/sys/class/powercap/intel-rapl:1", 1000*Joule))
+
+	t.Run("with topology", func(t *testing.T) {
+		pm := &PowerMonitor{resources: resInformer, cpuSockets: sockets}
+		pm.computeSocketShares()
+
+		ratio, ok := pm.attributionRatio(pkg0, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 3}, 8)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.75, ratio, 0.0001)
+
+		_, ok = pm.attributionRatio(pkg1, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 3}, 8)
+		assert.False(t, ok, "process did not run on socket 1")
+
+		ratio, ok = pm.attributionRatio(pkg1, Workload{Kind: ProcessWorkload, ID: "4", CPUTimeDelta: 2}, 8)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.5, ratio, 0.0001)
+
+		// containers get the share of their processes on each socket
+		ratio, ok = pm.attributionRatio(pkg0, Workload{Kind: ContainerWorkload, ID: "container-1", CPUTimeDelta: 5}, 8)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.75, ratio, 0.0001)
+
+		ratio, ok = pm.attributionRatio(pkg1, Workload{Kind: ContainerWorkload, ID: "container-1", CPUTimeDelta: 5}, 8)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.5, ratio, 0.0001)
+	})
+
+	t.Run("pinned container", func(t *testing.T) {
+		// container-1 is pinned to the CPUs of socket 1 and last ran on socket
+		// 0 with process 1
+		resInformer := &MockResourceInformer{}
+		resInformer.On("Processes").Return(procs)
+		resInformer.On("Containers").Return(&resource.Containers{
+			Running: map[string]*resource.Container{"container-1": {ID: "container-1", CPUs: []int{2, 3}}},
+		})
+
+		pm := &PowerMonitor{resources: resInformer, cpuSockets: sockets}
+		pm.computeSocketShares()
+
+		_, ok := pm.attributionRatio(pkg0, Workload{Kind: ContainerWorkload, ID: "container-1", CPUTimeDelta: 5}, 8)
+		assert.False(t, ok, "container is pinned to socket 1")
+
+		ratio, ok := pm.attributionRatio(pkg1, Workload{Kind: ContainerWorkload, ID: "container-1", CPUTimeDelta: 5}, 8)
+		assert.True(t, ok)
+		assert.InDelta(t, 5.0/7, ratio, 0.0001)
+
+		ratio, ok = pm.attributionRatio(pkg0, Workload{Kind: ProcessWorkload, ID: "2", CPUTimeDelta: 1}, 8)
+		assert.True(t, ok)
+		assert.InDelta(t, 1.0, ratio, 0.0001, "process 2 is the only one on socket 0")
+	})
+
+	t.Run("container spanning sockets", func(t *testing.T) {
+		resInformer := &MockResourceInformer{}
+		resInformer.On("Processes").Return(procs)
+		resInformer.On("Containers").Return(&resource.Containers{
+			Running: map[string]*resource.Container{"container-1": {ID: "container-1", CPUs: []int{1, 2}}},
+		})
+
+		pm := &PowerMonitor{resources: resInformer, cpuSockets: sockets}
+		pm.computeSocketShares()
+
+		// processes are assigned to the socket of the CPU they last ran on
+		ratio, ok := pm.attributionRatio(pkg0, Workload{Kind: ContainerWorkload, ID: "container-1", CPUTimeDelta: 5}, 8)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.75, ratio, 0.0001)
+	})
+
+	t.Run("without topology", func(t *testing.T) {
+		pm := &PowerMonitor{resources: resInformer}
+		pm.computeSocketShares()
+
+		ratio, ok := pm.attributionRatio(pkg1, Workload{Kind: ProcessWorkload, ID: "1", CPUTimeDelta: 3}, 8)
+		assert.True(t, ok)
+		assert.InDelta(t, 0.375, ratio, 0.0001)
+	})
+}
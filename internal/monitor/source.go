@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+)
+
+// Kinds of sources
+const (
+	SourceZone   = "zone"   // energy zone of a CPU, GPU or I/O meter
+	SourceGPU    = "gpu"    // utilization of the GPUs of a meter
+	SourceCarbon = "carbon" // carbon intensity provider
+)
+
+// Source is a source of data read by the monitor, and whether it could be read
+// in the last collection. The monitor continues in degraded mode when some
+// sources are unavailable, e.g. without the zones it can't read.
+type Source struct {
+	Kind      string
+	Name      string     // name of the meter or provider; empty for zones
+	Zone      EnergyZone // zone of zone sources; nil for other sources
+	Available bool
+	Err       error // why the source is unavailable; nil if it is available
+}
+
+// Sources of the power of node zones, other than the meter of the CPU, e.g.
+// rapl or hwmon, whose zones have the name of the meter
+const (
+	ZoneSourcePlatform  = "platform"  // platform zone read from the BMC, e.g. Redfish or IPMI, or ACPI
+	ZoneSourceGPU       = "gpu"       // zones of GPU meters
+	ZoneSourceIO        = "io"        // zones of I/O devices
+	ZoneSourceEstimated = "estimated" // CPU zones estimated by a model
+)
+
+type sourceKey struct {
+	kind, name string
+}
+
+// sourceTracker tracks the availability of sources in the order they are first
+// read. It is only updated while refreshing the snapshot.
+type sourceTracker struct {
+	sources []Source
+	index   map[any]int // index of sources keyed by zone or sourceKey
+}
+
+// updateSource records whether a source other than a zone could be read
+func (pm *PowerMonitor) updateSource(kind, name string, err error) {
+	pm.trackSource(sourceKey{kind: kind, name: name}, Source{Kind: kind, Name: name}, err,
+		"kind", kind, "source", name)
+}
+
+// updateZoneSource records whether the energy of zone could be read
+func (pm *PowerMonitor) updateZoneSource(zone EnergyZone, err error) {
+	// NOTE: the name of the zone is only needed to log changes
+	pm.trackSource(zone, Source{Kind: SourceZone, Zone: zone}, err)
+}
+
+// trackSource records the availability of a source, logging when it becomes
+// unavailable or available again
+func (pm *PowerMonitor) trackSource(key any, source Source, err error, attrs ...any) {
+	t := &pm.sources
+	if t.index == nil {
+		t.index = map[any]int{}
+	}
+
+	source.Err = device.Classify(err)
+	source.Available = err == nil
+
+	// sources are expected to be available, so only changes are logged
+	wasAvailable := true
+	i, seen := t.index[key]
+	if seen {
+		wasAvailable = t.sources[i].Available
+		t.sources[i] = source
+	} else {
+		t.index[key] = len(t.sources)
+		t.sources = append(t.sources, source)
+	}
+	if wasAvailable == source.Available {
+		return
+	}
+	if source.Zone != nil {
+		attrs = append(attrs, "kind", SourceZone, "zone", source.Zone.Name(), "index", source.Zone.Index())
+	}
+	if source.Available {
+		pm.logger.Info("Source is available again", attrs...)
+		return
+	}
+	pm.logger.Warn("Source is unavailable; continuing without it",
+		append(attrs, "reason", device.ErrorKind(source.Err), "error", source.Err)...)
+
+	subject := source.Kind + "/" + source.Name
+	if source.Zone != nil {
+		subject = fmt.Sprintf("%s/%s-%d", SourceZone, source.Zone.Name(), source.Zone.Index())
+	}
+	pm.queueEvent(event.Event{
+		Kind:    event.SourceDegraded,
+		Subject: subject,
+		Message: source.Err.Error(),
+		Attrs:   map[string]string{"reason": device.ErrorKind(source.Err)},
+	})
+}
+
+// sourceList returns the availability of all sources
+func (pm *PowerMonitor) sourceList() []Source {
+	return slices.Clone(pm.sources.sources)
+}
+
+// MeterOf returns the name of the meter the energy of zone is read from, e.g.
+// rapl, hwmon or estimator for CPU zones, nvidia for GPU zones, io for I/O zones
+// and redfish or acpi for the platform zone
+func (pm *PowerMonitor) MeterOf(zone EnergyZone) string {
+	if gz, ok := pm.gpuZones[zone]; ok {
+		return gz.meter.Name()
+	}
+	if slices.Contains(pm.ioZoneList, zone) {
+		return "io"
+	}
+	if pm.platform != nil && zone == pm.platform {
+		// the meter of a platform measured by several sources is its primary
+		if m, ok := zone.(interface{ Meter() string }); ok {
+			return m.Meter()
+		}
+		return "redfish"
+	}
+	return pm.cpu.Name()
+}
+
+// IsGPUZone returns whether zone is a zone of a GPU meter, e.g. nvidia-gpu-0
+func (pm *PowerMonitor) IsGPUZone(zone EnergyZone) bool {
+	_, ok := pm.gpuZones[zone]
+	return ok
+}
+
+// SourceOf returns the source the power of zone is measured by: the name of the
+// meter of the CPU, e.g. rapl or hwmon, for CPU zones, ZoneSourceEstimated for
+// CPU zones estimated by a model, or ZoneSourceGPU, ZoneSourceIO or
+// ZoneSourcePlatform
+func (pm *PowerMonitor) SourceOf(zone EnergyZone) string {
+	if _, ok := pm.gpuZones[zone]; ok {
+		return ZoneSourceGPU
+	}
+	if slices.Contains(pm.ioZoneList, zone) {
+		return ZoneSourceIO
+	}
+	if pm.platform != nil && zone == pm.platform {
+		return ZoneSourcePlatform
+	}
+	if meter := pm.cpu.Name(); meter != "estimator" {
+		return meter
+	}
+	return ZoneSourceEstimated
+}
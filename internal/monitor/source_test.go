@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+)
+
+func TestSourceAvailability(t *testing.T) {
+	var logs bytes.Buffer
+	pm := &PowerMonitor{logger: slog.New(slog.NewTextHandler(&logs, nil))}
+	denied := &os.PathError{Op: "open", Path: "energy_uj", Err: os.ErrPermission}
+
+	pm.updateSource(SourceCarbon, "electricitymaps", nil)
+	pm.updateSource(SourceGPU, "nvidia", denied)
+	pm.updateSource(SourceGPU, "nvidia", denied)
+	assert.Equal(t, 1, strings.Count(logs.String(), "Source is unavailable"), "only changes are logged")
+	assert.Contains(t, logs.String(), "reason=permission")
+
+	sources := pm.sourceList()
+	assert.Len(t, sources, 2)
+	assert.Equal(t, Source{Kind: SourceCarbon, Name: "electricitymaps", Available: true}, sources[0])
+	assert.Equal(t, SourceGPU, sources[1].Kind)
+	assert.False(t, sources[1].Available)
+	assert.ErrorIs(t, sources[1].Err, device.ErrPermission)
+
+	pm.updateSource(SourceGPU, "nvidia", nil)
+	assert.Contains(t, logs.String(), "Source is available again")
+	assert.True(t, pm.sourceList()[1].Available)
+	assert.False(t, sources[1].Available, "listed sources are not modified")
+}
+
+func TestMeterOf(t *testing.T) {
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+	gpu0 := device.NewMockRaplZone("gpu", 0, "", 1000*Joule)
+	network := device.NewMockRaplZone("network", 0, "", 1000*Joule)
+	platform := device.NewMockRaplZone("platform", 0, "", 1000*Joule)
+
+	cpu := &MockCPUPowerMeter{}
+	cpu.On("Name").Return("rapl")
+	pm := &PowerMonitor{
+		cpu:        cpu,
+		gpuZones:   map[EnergyZone]gpuZone{gpu0: {meter: &fakeGPUMeter{}}},
+		ioZoneList: []EnergyZone{network},
+		platform:   platform,
+	}
+
+	assert.Equal(t, "rapl", pm.MeterOf(pkg))
+	assert.Equal(t, "fake-gpu", pm.MeterOf(gpu0))
+	assert.Equal(t, "io", pm.MeterOf(network))
+	assert.Equal(t, "redfish", pm.MeterOf(platform))
+
+	pm.platform = device.NewPlatformZone(PlatformZone, []device.PlatformSource{{Name: "acpi", Zone: platform}})
+	assert.Equal(t, "acpi", pm.MeterOf(pm.platform), "the primary source of the platform")
+}
+
+func TestSourceOf(t *testing.T) {
+	pkg := device.NewMockRaplZone("package", 0, "", 1000*Joule)
+	gpu0 := device.NewMockRaplZone("gpu", 0, "", 1000*Joule)
+	network := device.NewMockRaplZone("network", 0, "", 1000*Joule)
+	platform := device.NewPlatformZone(PlatformZone, []device.PlatformSource{
+		{Name: "acpi", Zone: device.NewMockRaplZone("platform", 0, "", 1000*Joule)},
+	})
+
+	cpu := &MockCPUPowerMeter{}
+	cpu.On("Name").Return("rapl")
+	pm := &PowerMonitor{
+		cpu:        cpu,
+		gpuZones:   map[EnergyZone]gpuZone{gpu0: {meter: &fakeGPUMeter{}}},
+		ioZoneList: []EnergyZone{network},
+		platform:   platform,
+	}
+
+	assert.Equal(t, "rapl", pm.SourceOf(pkg))
+	assert.Equal(t, ZoneSourceGPU, pm.SourceOf(gpu0))
+	assert.Equal(t, ZoneSourceIO, pm.SourceOf(network))
+	assert.Equal(t, ZoneSourcePlatform, pm.SourceOf(platform))
+
+	estimator := &MockCPUPowerMeter{}
+	estimator.On("Name").Return("estimator")
+	pm.cpu = estimator
+	assert.Equal(t, ZoneSourceEstimated, pm.SourceOf(pkg))
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"maps"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// firstSystemdUnitRead initializes systemd unit power data for the first time
+func (pm *PowerMonitor) firstSystemdUnitRead(snapshot *Snapshot) error {
+	if !pm.systemdUnits {
+		return nil
+	}
+
+	running := pm.resources.SystemdUnits().Running
+	units := make(SystemdUnits, len(running))
+
+	zones := snapshot.Node.Zones
+	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
+
+	for name, u := range running {
+		unit := newSystemdUnit(u, zones)
+
+		// Calculate initial energy as the share of node active energy
+		pm.attributeZones(unit.Zones, zones, Workload{Kind: SystemdUnitWorkload, ID: name, CPUTimeDelta: u.CPUTimeDelta}, nodeCPUTimeDelta, nil)
+
+		units[name] = unit
+	}
+	snapshot.SystemdUnits = units
+
+	pm.logger.Debug("Initialized systemd unit power tracking",
+		"units", len(units))
+	return nil
+}
+
+// calculateSystemdUnitPower calculates power for each running systemd unit and
+// handles terminated units
+func (pm *PowerMonitor) calculateSystemdUnitPower(prev, newSnapshot *Snapshot) error {
+	if !pm.systemdUnits {
+		return nil
+	}
+
+	// Release terminated workloads that are past retention (or exported)
+	pm.terminatedSystemdUnitsTracker.Prune(pm.exported.Load())
+
+	units := pm.resources.SystemdUnits()
+
+	// Handle terminated units
+	pm.logger.Debug("Processing terminated systemd units", "terminated", len(units.Terminated))
+	for name := range units.Terminated {
+		prevUnit, exists := prev.SystemdUnits[name]
+		if !exists {
+			continue
+		}
+
+		// Add to internal tracker (which will handle priority-based retention)
+		pm.terminatedSystemdUnitsTracker.Add(prevUnit.Clone())
+	}
+
+	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
+	pm.logger.Debug("Calculating systemd unit power",
+		"node.cpu.time", nodeCPUTimeDelta,
+		"running", len(units.Running),
+	)
+
+	unitMap := make(SystemdUnits, len(units.Running))
+
+	// For each unit, calculate power for each zone separately
+	zones := newSnapshot.Node.Zones
+	for name, u := range units.Running {
+		// Calculate share of each zone's active power and energy; energy
+		// accumulates over the previous snapshot
+		var prevZones ZoneUsageMap
+		prevUnit, exists := prev.SystemdUnits[name]
+		if exists {
+			prevZones = prevUnit.Zones
+		}
+		usage := pm.usageBuffer(zones)
+		pm.attributeZones(usage, zones, Workload{Kind: SystemdUnitWorkload, ID: name, CPUTimeDelta: u.CPUTimeDelta}, nodeCPUTimeDelta, prevZones)
+
+		// share unchanged units with the previous snapshot (copy-on-write)
+		if exists && prevUnit.matches(u) && sameUsage(prevUnit.Zones, usage) {
+			unitMap[name] = prevUnit
+			continue
+		}
+
+		unit := newSystemdUnit(u, zones)
+		maps.Copy(unit.Zones, usage)
+		unitMap[name] = unit
+	}
+
+	newSnapshot.SystemdUnits = unitMap
+
+	// Populate terminated units from tracker
+	newSnapshot.TerminatedSystemdUnits = pm.terminatedSystemdUnitsTracker.Items()
+	pm.logger.Debug("snapshot updated for systemd units",
+		"running", len(newSnapshot.SystemdUnits),
+		"terminated", len(newSnapshot.TerminatedSystemdUnits),
+	)
+
+	return nil
+}
+
+// matches returns true if the unit has the same attributes as u
+func (su *SystemdUnit) matches(u *resource.SystemdUnit) bool {
+	return su.Name == u.Name &&
+		su.Slice == u.Slice &&
+		su.CPUTotalTime == u.CPUTotalTime
+}
+
+// newSystemdUnit creates a new SystemdUnit with zones initialized from resource.SystemdUnit
+func newSystemdUnit(u *resource.SystemdUnit, zones NodeZoneUsageMap) *SystemdUnit {
+	unit := &SystemdUnit{
+		Name:         u.Name,
+		Slice:        u.Slice,
+		CPUTotalTime: u.CPUTotalTime,
+		Zones:        make(ZoneUsageMap, len(zones)),
+	}
+
+	// Initialize each zone with zero values
+	for zone := range zones {
+		unit.Zones[zone] = Usage{
+			EnergyTotal: Energy(0),
+			Power:       Power(0),
+		}
+	}
+
+	return unit
+}
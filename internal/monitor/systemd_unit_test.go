@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestSystemdUnitPowerCalculation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	zones := CreateTestZones()
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(zones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(zones[0], nil)
+
+	resInformer := &MockResourceInformer{}
+	monitor := &PowerMonitor{
+		logger:        logger,
+		cpu:           mockMeter,
+		clock:         fakeClock,
+		resources:     resInformer,
+		maxTerminated: 500,
+		systemdUnits:  true,
+	}
+	require.NoError(t, monitor.Init())
+
+	tr := CreateTestResources(createOnly(testNode))
+	nodeCPUTimeDelta := tr.Node.ProcessTotalCPUTimeDelta
+	resInformer.On("Node").Return(tr.Node, nil)
+
+	t.Run("firstSystemdUnitRead", func(t *testing.T) {
+		units := &resource.SystemdUnits{
+			Running: map[string]*resource.SystemdUnit{
+				"sshd.service":  {Name: "sshd.service", Slice: "system.slice", CPUTotalTime: 10, CPUTimeDelta: 0.4 * nodeCPUTimeDelta},
+				"nginx.service": {Name: "nginx.service", Slice: "system.slice", CPUTotalTime: 20, CPUTimeDelta: 0.1 * nodeCPUTimeDelta},
+			},
+			Terminated: map[string]*resource.SystemdUnit{},
+		}
+		resInformer.On("SystemdUnits").Return(units).Once()
+
+		snapshot := NewSnapshot()
+		snapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		require.NoError(t, monitor.firstSystemdUnitRead(snapshot))
+
+		require.Len(t, snapshot.SystemdUnits, 2)
+		sshd := snapshot.SystemdUnits["sshd.service"]
+		assert.Equal(t, "system.slice", sshd.Slice)
+		assert.Equal(t, 10.0, sshd.CPUTotalTime)
+		for _, zone := range zones {
+			expected := Energy(0.4 * float64(snapshot.Node.Zones[zone].activeEnergy))
+			assert.Equal(t, expected, sshd.Zones[zone].EnergyTotal)
+		}
+	})
+
+	t.Run("calculateSystemdUnitPower", func(t *testing.T) {
+		prevSnapshot := NewSnapshot()
+		prevSnapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		for _, name := range []string{"sshd.service", "cron.service"} {
+			prevSnapshot.SystemdUnits[name] = &SystemdUnit{
+				Name:  name,
+				Slice: "system.slice",
+				Zones: make(ZoneUsageMap, len(zones)),
+			}
+			for _, zone := range zones {
+				prevSnapshot.SystemdUnits[name].Zones[zone] = Usage{EnergyTotal: 25 * Joule}
+			}
+		}
+
+		// cron.service stopped since the previous refresh
+		units := &resource.SystemdUnits{
+			Running: map[string]*resource.SystemdUnit{
+				"sshd.service": {Name: "sshd.service", Slice: "system.slice", CPUTotalTime: 50, CPUTimeDelta: 0.5 * nodeCPUTimeDelta},
+			},
+			Terminated: map[string]*resource.SystemdUnit{
+				"cron.service": {Name: "cron.service", Slice: "system.slice"},
+			},
+		}
+		resInformer.On("SystemdUnits").Return(units).Once()
+
+		fakeClock.Step(2 * time.Second)
+		newSnapshot := NewSnapshot()
+		newSnapshot.Node = createNodeSnapshot(zones, fakeClock.Now(), 0.5)
+		require.NoError(t, monitor.calculateSystemdUnitPower(prevSnapshot, newSnapshot))
+
+		require.Len(t, newSnapshot.SystemdUnits, 1)
+		sshd := newSnapshot.SystemdUnits["sshd.service"]
+		for _, zone := range zones {
+			nodeZone := newSnapshot.Node.Zones[zone]
+			assert.Equal(t, 25*Joule+Energy(0.5*float64(nodeZone.activeEnergy)), sshd.Zones[zone].EnergyTotal)
+			assert.Equal(t, Power(0.5*nodeZone.ActivePower.MicroWatts()), sshd.Zones[zone].Power)
+		}
+
+		require.Len(t, newSnapshot.TerminatedSystemdUnits, 1)
+		assert.Contains(t, newSnapshot.TerminatedSystemdUnits, "cron.service")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := &PowerMonitor{logger: logger, resources: resInformer}
+
+		prevSnapshot := NewSnapshot()
+		newSnapshot := NewSnapshot()
+		require.NoError(t, disabled.firstSystemdUnitRead(newSnapshot))
+		require.NoError(t, disabled.calculateSystemdUnitPower(prevSnapshot, newSnapshot))
+		assert.Empty(t, newSnapshot.SystemdUnits)
+		resInformer.AssertNumberOfCalls(t, "SystemdUnits", 2)
+	})
+}
@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"time"
 
 	"github.com/sustainable-computing-io/kepler/internal/device"
+	"k8s.io/utils/clock"
 )
 
 // Resource represents any resource type that can be tracked by energy consumption
@@ -35,6 +37,29 @@ type TerminatedResourceTracker[T Resource] struct {
 	targetZone         device.EnergyZone // zone to use for energy comparison
 	maxSize            int               // maximum number of resources to track
 	minEnergyThreshold Energy            // minimum energy threshold to track a resource
+
+	// retention related
+	maxAge  time.Duration        // 0 releases resources once exported; > 0 retains them for maxAge
+	clock   clock.PassiveClock   // clock used to timestamp terminations
+	addedAt map[string]time.Time // ID -> time the resource was added to the tracker
+}
+
+// TrackerOpts holds the optional settings of a TerminatedResourceTracker
+type TrackerOpts struct {
+	maxAge time.Duration
+	clock  clock.PassiveClock
+}
+
+// TrackerOptionFn is a function that sets one or more options in TrackerOpts
+type TrackerOptionFn func(*TrackerOpts)
+
+// WithMaxAge retains terminated resources for the given duration (measured using c)
+// instead of releasing them as soon as they have been exported
+func WithMaxAge(age time.Duration, c clock.PassiveClock) TrackerOptionFn {
+	return func(o *TrackerOpts) {
+		o.maxAge = age
+		o.clock = c
+	}
 }
 
 // Heap implements a min-heap of resources sorted by energy consumption
@@ -48,7 +73,12 @@ type HeapItem[T Resource] struct {
 }
 
 // NewTerminatedResourceTracker creates a new tracker with the specified energy zone, capacity, and minimum energy threshold
-func NewTerminatedResourceTracker[T Resource](zone device.EnergyZone, maxSize int, minEnergyThreshold Energy, logger *slog.Logger) *TerminatedResourceTracker[T] {
+func NewTerminatedResourceTracker[T Resource](zone device.EnergyZone, maxSize int, minEnergyThreshold Energy, logger *slog.Logger, applyOpts ...TrackerOptionFn) *TerminatedResourceTracker[T] {
+	opts := TrackerOpts{clock: clock.RealClock{}}
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
 	h := Heap[T]{}
 	heap.Init(&h)
 
@@ -64,6 +94,9 @@ func NewTerminatedResourceTracker[T Resource](zone device.EnergyZone, maxSize in
 		targetZone:         zone,
 		maxSize:            maxSize,
 		minEnergyThreshold: minEnergyThreshold,
+		maxAge:             opts.maxAge,
+		clock:              opts.clock,
+		addedAt:            make(map[string]time.Time),
 	}
 }
 
@@ -92,11 +125,7 @@ func (trt *TerminatedResourceTracker[T]) Add(resource T) {
 		return // Ignore duplicate - terminated resource already tracked
 	}
 
-	// Get the energy from the target zone for this resource
-	energyTotal := Energy(0)
-	if zoneUsage, exists := resource.ZoneUsage()[trt.targetZone]; exists {
-		energyTotal = zoneUsage.EnergyTotal
-	}
+	energyTotal := trt.energyOf(resource)
 
 	// Filter out resources that don't meet the minimum energy threshold
 	if energyTotal < trt.minEnergyThreshold {
@@ -117,6 +146,7 @@ func (trt *TerminatedResourceTracker[T]) Add(resource T) {
 		// Room available, just add
 		heap.Push(&trt.heap, newItem)
 		trt.resources[id] = resource
+		trt.addedAt[id] = trt.clock.Now()
 		return
 	}
 
@@ -125,10 +155,12 @@ func (trt *TerminatedResourceTracker[T]) Add(resource T) {
 		// Evict lowest energy resource
 		minItem := heap.Pop(&trt.heap).(HeapItem[T])
 		delete(trt.resources, minItem.ID)
+		delete(trt.addedAt, minItem.ID)
 
 		// Add new higher-energy resource
 		heap.Push(&trt.heap, newItem)
 		trt.resources[id] = resource
+		trt.addedAt[id] = trt.clock.Now()
 	}
 }
 
@@ -152,9 +184,44 @@ func (trt *TerminatedResourceTracker[T]) MaxSize() int {
 	return trt.maxSize
 }
 
+// MaxAge returns the duration terminated resources are retained for; 0 indicates
+// that resources are retained only until they are exported
+func (trt *TerminatedResourceTracker[T]) MaxAge() time.Duration {
+	return trt.maxAge
+}
+
+// Prune releases resources that need not be retained any longer.
+// Without a max age, all resources are released once they have been exported.
+// With a max age, resources are retained (regardless of exports) until they
+// are older than max age so that consumers with slow scrape intervals do not
+// miss terminated resources.
+func (trt *TerminatedResourceTracker[T]) Prune(exported bool) {
+	if trt.maxAge <= 0 {
+		if exported {
+			trt.Clear()
+		}
+		return
+	}
+
+	now := trt.clock.Now()
+	kept := trt.heap[:0]
+	for _, item := range trt.heap {
+		if now.Sub(trt.addedAt[item.ID]) > trt.maxAge {
+			trt.logger.Debug("Releasing expired terminated resource", "id", item.ID)
+			delete(trt.resources, item.ID)
+			delete(trt.addedAt, item.ID)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	trt.heap = kept
+	heap.Init(&trt.heap)
+}
+
 // Clear removes all tracked resources
 func (trt *TerminatedResourceTracker[T]) Clear() {
 	trt.resources = make(map[string]T)
+	trt.addedAt = make(map[string]time.Time)
 	trt.heap = trt.heap[:0] // Clear the slice but keep the underlying array
 	heap.Init(&trt.heap)    // Re-initialize the heap
 }
@@ -199,3 +266,22 @@ func (h *Heap[T]) Pop() any {
 	*h = old[0 : n-1]
 	return item
 }
+
+// energyOf returns the energy of the resource in the target zone. With per-socket
+// zones, the energy of the target zone type across all sockets is used so that
+// resources running on any socket are compared fairly.
+func (trt *TerminatedResourceTracker[T]) energyOf(resource T) Energy {
+	usage := resource.ZoneUsage()
+	if _, isSocketZone := trt.targetZone.(*device.SocketZone); !isSocketZone {
+		return usage[trt.targetZone].EnergyTotal
+	}
+
+	target := device.BaseZoneName(trt.targetZone)
+	energyTotal := Energy(0)
+	for zone, zoneUsage := range usage {
+		if device.BaseZoneName(zone) == target {
+			energyTotal += zoneUsage.EnergyTotal
+		}
+	}
+	return energyTotal
+}
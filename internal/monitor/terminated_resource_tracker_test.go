@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/sustainable-computing-io/kepler/internal/device"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
 // MockResource implements the Resource interface for testing
@@ -266,6 +268,70 @@ func TestTerminatedResourceTracker_Clear(t *testing.T) {
 	assert.Equal(t, 0, len(tracker.Items()))
 }
 
+func TestTerminatedResourceTracker_Prune(t *testing.T) {
+	zones := CreateTestZones()
+	zone := zones[0]
+
+	t.Run("without max age resources are released only after export", func(t *testing.T) {
+		tracker := NewTerminatedResourceTracker[*MockResource](zone, 5, 0, slog.Default())
+		tracker.Add(createMockResource("resource-1", zone, 1000*Joule))
+
+		tracker.Prune(false)
+		assert.Equal(t, 1, tracker.Size(), "unexported resources must be retained")
+
+		tracker.Prune(true)
+		assert.Equal(t, 0, tracker.Size(), "exported resources must be released")
+	})
+
+	t.Run("with max age resources are retained until expired", func(t *testing.T) {
+		fakeClock := testingclock.NewFakeClock(time.Now())
+		tracker := NewTerminatedResourceTracker[*MockResource](zone, 5, 0, slog.Default(),
+			WithMaxAge(30*time.Second, fakeClock))
+		assert.Equal(t, 30*time.Second, tracker.MaxAge())
+
+		tracker.Add(createMockResource("old", zone, 1000*Joule))
+		fakeClock.Step(20 * time.Second)
+		tracker.Add(createMockResource("new", zone, 500*Joule))
+
+		// exports do not release resources that are within max age
+		tracker.Prune(true)
+		assert.Equal(t, 2, tracker.Size())
+
+		fakeClock.Step(15 * time.Second)
+		tracker.Prune(true)
+		items := tracker.Items()
+		require.Len(t, items, 1)
+		assert.Contains(t, items, "new")
+
+		fakeClock.Step(20 * time.Second)
+		tracker.Prune(false)
+		assert.Equal(t, 0, tracker.Size())
+	})
+
+	t.Run("heap stays consistent after pruning", func(t *testing.T) {
+		fakeClock := testingclock.NewFakeClock(time.Now())
+		tracker := NewTerminatedResourceTracker[*MockResource](zone, 2, 0, slog.Default(),
+			WithMaxAge(10*time.Second, fakeClock))
+
+		tracker.Add(createMockResource("expired", zone, 100*Joule))
+		fakeClock.Step(11 * time.Second)
+		tracker.Add(createMockResource("low", zone, 200*Joule))
+		tracker.Prune(false)
+		require.Equal(t, 1, tracker.Size())
+
+		// capacity is available again; adding a higher energy resource when
+		// full must evict the lowest energy resource that is still retained
+		tracker.Add(createMockResource("high", zone, 300*Joule))
+		tracker.Add(createMockResource("highest", zone, 400*Joule))
+
+		items := tracker.Items()
+		assert.Len(t, items, 2)
+		assert.Contains(t, items, "high")
+		assert.Contains(t, items, "highest")
+		assert.NotContains(t, items, "low")
+	})
+}
+
 func TestTerminatedResourceTracker_MultiZoneResource(t *testing.T) {
 	zones := CreateTestZones()
 	trackedZone := zones[0]
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"github.com/sustainable-computing-io/kepler/internal/device"
+)
+
+// Totals are the cumulative counters of the node and of the workloads whose IDs
+// don't change when Kepler restarts, i.e. containers, VMs and pods, keyed by
+// zone name. They are saved so that the counters continue from their values
+// when Kepler is restarted, rather than from zero.
+type Totals struct {
+	Node            map[string]NodeTotals     `json:"node"`
+	Containers      map[string]WorkloadTotals `json:"containers,omitempty"`
+	VirtualMachines map[string]WorkloadTotals `json:"virtualMachines,omitempty"`
+	Pods            map[string]WorkloadTotals `json:"pods,omitempty"`
+}
+
+// NodeTotals are the cumulative counters of a zone of the node
+type NodeTotals struct {
+	EnergyTotal       Energy  `json:"energyTotal"`
+	ActiveEnergyTotal Energy  `json:"activeEnergyTotal"`
+	IdleEnergyTotal   Energy  `json:"idleEnergyTotal"`
+	EmissionsTotal    float64 `json:"emissionsTotal,omitempty"`
+	CostTotal         float64 `json:"costTotal,omitempty"`
+}
+
+// UsageTotals are the cumulative counters of a zone of a workload
+type UsageTotals struct {
+	EnergyTotal    Energy  `json:"energyTotal"`
+	EmissionsTotal float64 `json:"emissionsTotal,omitempty"`
+	CostTotal      float64 `json:"costTotal,omitempty"`
+}
+
+// WorkloadTotals are the cumulative counters of the zones of a workload
+type WorkloadTotals map[string]UsageTotals
+
+// TotalsOf returns the cumulative counters of the node and of the running
+// containers, VMs and pods of snapshot
+func TotalsOf(snapshot *Snapshot) *Totals {
+	totals := &Totals{Node: map[string]NodeTotals{}}
+	if snapshot.Node != nil {
+		for zone, usage := range snapshot.Node.Zones {
+			totals.Node[zone.Name()] = NodeTotals{
+				EnergyTotal:       usage.EnergyTotal,
+				ActiveEnergyTotal: usage.ActiveEnergyTotal,
+				IdleEnergyTotal:   usage.IdleEnergyTotal,
+				EmissionsTotal:    usage.EmissionsTotal,
+				CostTotal:         usage.CostTotal,
+			}
+		}
+	}
+
+	workloadTotals := func(zones ZoneUsageMap) WorkloadTotals {
+		t := make(WorkloadTotals, len(zones))
+		for zone, usage := range zones {
+			t[zone.Name()] = UsageTotals{
+				EnergyTotal:    usage.EnergyTotal,
+				EmissionsTotal: usage.EmissionsTotal,
+				CostTotal:      usage.CostTotal,
+			}
+		}
+		return t
+	}
+	totals.Containers = make(map[string]WorkloadTotals, len(snapshot.Containers))
+	for id, c := range snapshot.Containers {
+		if id != OtherWorkload {
+			totals.Containers[id] = workloadTotals(c.Zones)
+		}
+	}
+	totals.VirtualMachines = make(map[string]WorkloadTotals, len(snapshot.VirtualMachines))
+	for id, vm := range snapshot.VirtualMachines {
+		totals.VirtualMachines[id] = workloadTotals(vm.Zones)
+	}
+	totals.Pods = make(map[string]WorkloadTotals, len(snapshot.Pods))
+	for id, p := range snapshot.Pods {
+		if id != OtherWorkload {
+			totals.Pods[id] = workloadTotals(p.Zones)
+		}
+	}
+	return totals
+}
+
+// restoreZones sets the energy of the zones integrated by Kepler to their
+// restored totals before they are first read; zones read from counters of the
+// hardware keep counting from their own values
+func (pm *PowerMonitor) restoreZones(zones []EnergyZone) {
+	if pm.restored == nil {
+		return
+	}
+	for _, zone := range zones {
+		total, ok := pm.restored.Node[zone.Name()]
+		if !ok {
+			continue
+		}
+		if restorer, ok := zone.(device.EnergyRestorer); ok {
+			restorer.RestoreEnergy(total.EnergyTotal)
+		}
+	}
+}
+
+// restoredNodeUsage returns the usage of zone on the first read with its
+// active and idle totals restored, and whether there are totals to restore
+func (pm *PowerMonitor) restoredNodeUsage(zone EnergyZone, energy Energy) (NodeUsage, bool) {
+	if pm.restored == nil {
+		return NodeUsage{}, false
+	}
+	total, ok := pm.restored.Node[zone.Name()]
+	if !ok {
+		return NodeUsage{}, false
+	}
+	return NodeUsage{
+		EnergyTotal:       energy,
+		ActiveEnergyTotal: total.ActiveEnergyTotal,
+		IdleEnergyTotal:   total.IdleEnergyTotal,
+		EmissionsTotal:    total.EmissionsTotal,
+		CostTotal:         total.CostTotal,
+		Uncertainty:       device.Uncertainty(zone),
+	}, true
+}
+
+// restoredZones returns the usage of the zones of a workload not seen before
+// with its restored totals, or nil if it has none. The totals of a workload are
+// restored once, when it is first seen after Kepler restarts.
+func (pm *PowerMonitor) restoredZones(kind WorkloadKind, id string, zones NodeZoneUsageMap) ZoneUsageMap {
+	if pm.restored == nil {
+		return nil
+	}
+	var workloads map[string]WorkloadTotals
+	switch kind {
+	case ContainerWorkload:
+		workloads = pm.restored.Containers
+	case VMWorkload:
+		workloads = pm.restored.VirtualMachines
+	case PodWorkload:
+		workloads = pm.restored.Pods
+	}
+	totals, ok := workloads[id]
+	if !ok {
+		return nil
+	}
+	delete(workloads, id)
+
+	usage := make(ZoneUsageMap, len(totals))
+	for zone := range zones {
+		if total, ok := totals[zone.Name()]; ok {
+			usage[zone] = Usage{
+				EnergyTotal:    total.EnergyTotal,
+				EmissionsTotal: total.EmissionsTotal,
+				CostTotal:      total.CostTotal,
+			}
+		}
+	}
+	return usage
+}
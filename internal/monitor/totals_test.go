@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestTotalsOf(t *testing.T) {
+	zones := CreateTestZones()
+	snapshot := NewSnapshot()
+	snapshot.Node = createNodeSnapshot(zones, time.Now(), 0.5)
+	snapshot.Containers["container-1"] = &Container{ID: "container-1", Zones: ZoneUsageMap{
+		zones[0]: {EnergyTotal: 25 * Joule, CostTotal: 0.5},
+	}}
+	snapshot.Containers[OtherWorkload] = &Container{ID: OtherWorkload, Zones: ZoneUsageMap{
+		zones[0]: {EnergyTotal: 5 * Joule},
+	}}
+	snapshot.Pods["pod-1"] = &Pod{ID: "pod-1", Zones: ZoneUsageMap{zones[0]: {EnergyTotal: 25 * Joule}}}
+	snapshot.Processes["123"] = &Process{PID: 123, Zones: ZoneUsageMap{zones[0]: {EnergyTotal: 25 * Joule}}}
+
+	totals := TotalsOf(snapshot)
+	assert.Equal(t, NodeTotals{
+		EnergyTotal:       200 * Joule,
+		ActiveEnergyTotal: 50 * Joule,
+		IdleEnergyTotal:   50 * Joule,
+	}, totals.Node["package-0"])
+	assert.Equal(t, WorkloadTotals{"package-0": {EnergyTotal: 25 * Joule, CostTotal: 0.5}}, totals.Containers["container-1"])
+	assert.NotContains(t, totals.Containers, OtherWorkload)
+	assert.Contains(t, totals.Pods, "pod-1")
+	assert.Empty(t, totals.VirtualMachines)
+}
+
+func TestRestoredTotals(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	// a zone integrated by Kepler along with the RAPL zones
+	zones := CreateTestZones()
+	gpu := device.NewPowerZone("gpu-0", 0, "", func() (Power, error) { return 100 * Watt, nil }, fakeClock)
+	zones = append(zones, gpu)
+	mockMeter := &MockCPUPowerMeter{}
+	mockMeter.On("Zones").Return(zones, nil)
+	mockMeter.On("PrimaryEnergyZone").Return(zones[0], nil)
+	resInformer := &MockResourceInformer{}
+
+	totals := &Totals{
+		Node: map[string]NodeTotals{
+			"package-0": {EnergyTotal: 5000 * Joule, ActiveEnergyTotal: 3000 * Joule, IdleEnergyTotal: 2000 * Joule, EmissionsTotal: 2},
+			"gpu-0":     {EnergyTotal: 7000 * Joule, ActiveEnergyTotal: 7000 * Joule},
+		},
+		Containers: map[string]WorkloadTotals{
+			"container-1": {"package-0": {EnergyTotal: 1000 * Joule, CostTotal: 0.5}},
+		},
+	}
+	pm := &PowerMonitor{
+		logger:    logger,
+		cpu:       mockMeter,
+		clock:     fakeClock,
+		resources: resInformer,
+		restored:  totals,
+	}
+	require.NoError(t, pm.Init())
+
+	tr := CreateTestResources(createOnly(testContainers, testNode))
+	resInformer.SetExpectations(t, tr)
+
+	snapshot := NewSnapshot()
+	require.NoError(t, pm.firstNodeRead(snapshot.Node))
+
+	pkg := snapshot.Node.Zones[zones[0]]
+	assert.Equal(t, 3000*Joule, pkg.ActiveEnergyTotal)
+	assert.Equal(t, 2000*Joule, pkg.IdleEnergyTotal)
+	assert.Equal(t, 2.0, pkg.EmissionsTotal)
+	assert.NotEqual(t, 5000*Joule, pkg.EnergyTotal, "counters of the hardware are not restored")
+	assert.Equal(t, 7000*Joule, snapshot.Node.Zones[gpu].EnergyTotal, "energy integrated by Kepler is restored")
+
+	require.NoError(t, pm.firstContainerRead(snapshot))
+	cntr := snapshot.Containers["container-1"]
+	assert.Equal(t, 1000*Joule, cntr.Zones[zones[0]].EnergyTotal)
+	assert.Equal(t, 0.5, cntr.Zones[zones[0]].CostTotal)
+	assert.Zero(t, snapshot.Containers["container-2"].Zones[zones[0]].EnergyTotal)
+	assert.Empty(t, totals.Containers, "the totals of a workload are restored once")
+}
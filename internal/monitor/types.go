@@ -5,6 +5,7 @@ package monitor
 
 import (
 	"maps"
+	"slices"
 	"strconv"
 	"time"
 
@@ -23,10 +24,36 @@ const (
 	Watt  = device.Watt
 )
 
+// OtherWorkload is the ID of the synthetic process, container and pod holding
+// the power attributed to the workloads of their level that are filtered out
+const OtherWorkload = "__other__"
+
 // NodeUsage contains energy consumption data of a node. This is different to Usage in that it has idle/active split
 type NodeUsage struct {
 	EnergyTotal Energy // Cumulative joules counter
-	Power       Power  // Current power in watts
+	Power       Power  // Current power in watts; the average over the interval
+
+	// RawPower is the power read from the zone before calibration; equal to
+	// Power if the zone isn't calibrated
+	RawPower Power
+
+	// Min and max power within the interval, sampled at the sample interval;
+	// both are equal to Power if sampling is disabled
+	MinPower Power
+	MaxPower Power
+
+	// Uncertainty is the relative uncertainty of Power, e.g. 0.2 if it is
+	// estimated within ±20%; 0 if it is measured
+	Uncertainty float64
+
+	// Wraparounds is the number of times the energy counter of the zone wrapped
+	// around since it was first read
+	Wraparounds uint64
+
+	// Jumps is the number of times the energy counter of the zone changed by
+	// more energy than it can consume in the interval, e.g. as it was reset,
+	// since it was first read; the energy of these intervals is discarded
+	Jumps uint64
 
 	// Split of Delta Energy between Active and Idle
 	ActiveEnergyTotal Energy // Cumulative energy counter for active workloads
@@ -35,8 +62,12 @@ type NodeUsage struct {
 	IdleEnergyTotal Energy // Cumulative energy counter for idle workloads
 	IdlePower       Power  // portion of the total power that allocated to node idling
 
-	// NOTE: activeEnergy is an internal variable that is used to calculate Resource's energy
+	EmissionsTotal float64 // Cumulative grams of CO2e emitted since monitoring started
+	CostTotal      float64 // Cumulative cost of the energy consumed since monitoring started
+
+	// NOTE: activeEnergy and idleEnergy are internal variables that are used to calculate Resource's energy
 	activeEnergy Energy // Energy used by the Resource running
+	idleEnergy   Energy // Energy used by the node idling
 }
 
 // Usage contains energy consumption data of workloads (Process, Container, VM)
@@ -44,6 +75,16 @@ type NodeUsage struct {
 type Usage struct {
 	EnergyTotal Energy // Cumulative joules counter
 	Power       Power  // Current power in watts
+
+	ActivePower Power // Share of the node's active power
+	IdlePower   Power // Share of the node's idle power, as per the idle policy
+
+	// Uncertainty is the relative uncertainty of Power, that of the power of
+	// the zone it is a share of; 0 if the power of the zone is measured
+	Uncertainty float64
+
+	EmissionsTotal float64 // Cumulative grams of CO2e emitted
+	CostTotal      float64 // Cumulative cost of the energy consumed
 }
 
 // ZoneUsageMap maps energy zones to basic usage data (absolute energy and power).
@@ -59,6 +100,19 @@ type Node struct {
 	Timestamp  time.Time        // Timestamp of the last measurement
 	UsageRatio float64          // ratio of usage
 	Zones      NodeZoneUsageMap // Map of zones to usage
+
+	// CoreTypeUsageRatios holds the CPU usage ratio of each core type of a
+	// hybrid CPU, keyed by core type; nil unless the core types are known.
+	// It is not modified once set.
+	CoreTypeUsageRatios map[string]float64
+
+	// CarbonIntensity of the electricity consumed in the last interval in
+	// gCO2e/kWh; 0 if unknown
+	CarbonIntensity float64
+
+	// Price of the electricity consumed in the last interval per kWh; 0 if
+	// no tariff is set
+	Price float64
 }
 
 func (n *Node) Clone() *Node {
@@ -81,10 +135,25 @@ type Process struct {
 
 	CPUTotalTime float64 // CPU time in seconds
 
+	// HWCounters holds the hardware events counted for the process; zero
+	// unless the resource informer counts perf events
+	HWCounters HWCounters
+
+	// IO holds the bytes transferred by the process; zero unless the resource
+	// informer tracks I/O
+	IO IOCounters
+
 	Zones ZoneUsageMap
 
 	ContainerID      string // empty if not a container
 	VirtualMachineID string // empty if not a virtual machine
+
+	// Command line, user and parent of the process; only set if the resource
+	// informer tracks process metadata
+	CmdLine   string
+	UID       int
+	User      string
+	ParentPID int
 }
 
 func (p *Process) Clone() *Process {
@@ -108,6 +177,10 @@ func (p *Process) StringID() string {
 	return strconv.Itoa(p.PID)
 }
 
+type HWCounters = resource.HWCounters
+
+type IOCounters = resource.IOCounters
+
 type ContainerRuntime = resource.ContainerRuntime
 
 // Container represents the power consumption of a container
@@ -117,6 +190,11 @@ type Container struct {
 
 	Runtime ContainerRuntime // Container runtime
 
+	// Image and compose project of the container; only set if the container
+	// is known to the container runtime (CRI) or engine (Docker, Podman)
+	Image          string
+	ComposeProject string
+
 	CPUTotalTime float64 // CPU time in seconds
 
 	Zones ZoneUsageMap
@@ -154,6 +232,7 @@ type VirtualMachine struct {
 	Name string // VM name
 
 	Hypervisor Hypervisor
+	CID        uint32 // vsock context ID; 0 if unknown
 
 	CPUTotalTime float64 // CPU time in seconds
 
@@ -186,6 +265,13 @@ type Pod struct {
 	Name      string // Pod Name
 	Namespace string // Pod Namespace
 
+	// Labels and owner workload of the pod; only set if the resource informer
+	// tracks pod metadata
+	Labels    map[string]string
+	OwnerKind string // e.g. Deployment, StatefulSet
+	OwnerName string
+
+	CPURequest   float64 // CPU requests in cores; 0 if not set
 	CPUTotalTime float64 // CPU time in seconds
 
 	// Replace single Usage with ZoneUsageMap
@@ -198,6 +284,7 @@ func (p *Pod) Clone() *Pod {
 	}
 
 	ret := *p
+	ret.Labels = maps.Clone(p.Labels)
 	ret.Zones = make(ZoneUsageMap, len(p.Zones))
 	maps.Copy(ret.Zones, p.Zones)
 	return &ret
@@ -213,11 +300,150 @@ func (p *Pod) StringID() string {
 	return p.ID
 }
 
+// SystemdUnit represents the power consumption of a systemd service or scope
+type SystemdUnit struct {
+	Name  string // unit name, e.g. sshd.service
+	Slice string // slice the unit belongs to, e.g. system.slice
+
+	CPUTotalTime float64 // CPU time in seconds
+
+	Zones ZoneUsageMap
+}
+
+func (u *SystemdUnit) Clone() *SystemdUnit {
+	if u == nil {
+		return nil
+	}
+
+	ret := *u
+	ret.Zones = make(ZoneUsageMap, len(u.Zones))
+	maps.Copy(ret.Zones, u.Zones)
+	return &ret
+}
+
+// ZoneUsage implements the Resource interface
+func (u *SystemdUnit) ZoneUsage() ZoneUsageMap {
+	return u.Zones
+}
+
+// StringID implements the Resource interface
+func (u *SystemdUnit) StringID() string {
+	return u.Name
+}
+
+// Group represents the power consumption of a group of processes: a process
+// and its descendants, or the processes in a cgroup
+type Group struct {
+	Name   string
+	PID    int    // root process of a process tree; 0 if the group is a cgroup
+	Cgroup string // cgroup path of the group; empty if the group is a process tree
+
+	Processes    int     // number of processes in the group so far
+	CPUTotalTime float64 // CPU time in seconds
+
+	Zones ZoneUsageMap
+}
+
+func (g *Group) Clone() *Group {
+	if g == nil {
+		return nil
+	}
+
+	ret := *g
+	ret.Zones = make(ZoneUsageMap, len(g.Zones))
+	maps.Copy(ret.Zones, g.Zones)
+	return &ret
+}
+
+// ZoneUsage implements the Resource interface
+func (g *Group) ZoneUsage() ZoneUsageMap {
+	return g.Zones
+}
+
+// StringID implements the Resource interface
+func (g *Group) StringID() string {
+	return g.Name
+}
+
+// Aggregate represents the power consumption of CPU time that isn't used by
+// user workloads, i.e. kernel threads and CPU time of no process seen
+type Aggregate struct {
+	Name string // aggregate name, e.g. kernel
+
+	CPUTotalTime float64 // CPU time in seconds
+
+	Zones ZoneUsageMap
+}
+
+func (a *Aggregate) Clone() *Aggregate {
+	if a == nil {
+		return nil
+	}
+
+	ret := *a
+	ret.Zones = make(ZoneUsageMap, len(a.Zones))
+	maps.Copy(ret.Zones, a.Zones)
+	return &ret
+}
+
+// ZoneUsage implements the Resource interface
+func (a *Aggregate) ZoneUsage() ZoneUsageMap {
+	return a.Zones
+}
+
+// StringID implements the Resource interface
+func (a *Aggregate) StringID() string {
+	return a.Name
+}
+
+// OwnerWorkload represents the power consumption of the running pods
+// controlled by a workload, e.g. a Deployment, StatefulSet, Job or DaemonSet
+type OwnerWorkload struct {
+	Kind      string // e.g. Deployment, StatefulSet
+	Name      string
+	Namespace string
+
+	Pods int // number of running pods
+
+	Zones ZoneUsageMap
+}
+
+func (w *OwnerWorkload) Clone() *OwnerWorkload {
+	if w == nil {
+		return nil
+	}
+
+	ret := *w
+	ret.Zones = make(ZoneUsageMap, len(w.Zones))
+	maps.Copy(ret.Zones, w.Zones)
+	return &ret
+}
+
+// ZoneUsage implements the Resource interface
+func (w *OwnerWorkload) ZoneUsage() ZoneUsageMap {
+	return w.Zones
+}
+
+// StringID implements the Resource interface
+func (w *OwnerWorkload) StringID() string {
+	return OwnerKey(w.Namespace, w.Kind, w.Name)
+}
+
+// OwnerKey returns the key of the owner workload of a pod in OwnerWorkloads,
+// e.g. prod/Deployment/api
+func OwnerKey(namespace, kind, name string) string {
+	return namespace + "/" + kind + "/" + name
+}
+
 type (
 	Processes       = map[string]*Process
 	Containers      = map[string]*Container
 	VirtualMachines = map[string]*VirtualMachine
 	Pods            = map[string]*Pod
+	SystemdUnits    = map[string]*SystemdUnit
+	Groups          = map[string]*Group
+	Aggregates      = map[string]*Aggregate
+	OwnerWorkloads  = map[string]*OwnerWorkload
 )
 
 // Snapshot encapsulates power monitoring data
@@ -235,6 +461,22 @@ type Snapshot struct {
 	TerminatedVirtualMachines VirtualMachines // Terminated VMs with highest energy consumption
 	Pods                      Pods            // Pod power data, keyed by pod ID
 	TerminatedPods            Pods            // Terminated pods with highest energy consumption
+
+	SystemdUnits           SystemdUnits // systemd unit power data, keyed by unit name
+	TerminatedSystemdUnits SystemdUnits // Terminated units with highest energy consumption
+
+	Groups           Groups // process group power data, keyed by group name
+	TerminatedGroups Groups // Terminated groups with highest energy consumption
+
+	Aggregates Aggregates // kernel and system aggregate power data, keyed by name
+
+	// OwnerWorkloads is the power data of running pods summed by the workload
+	// controlling them, keyed by OwnerKey
+	OwnerWorkloads OwnerWorkloads
+
+	Budgets []BudgetStatus // Energy consumed against budgets in the current day
+
+	Sources []Source // Availability of the sources of data in the last collection
 }
 
 // NewSnapshot creates a new Snapshot instance
@@ -252,6 +494,12 @@ func NewSnapshot() *Snapshot {
 		TerminatedVirtualMachines: make(VirtualMachines),
 		Pods:                      make(Pods),
 		TerminatedPods:            make(Pods),
+		SystemdUnits:              make(SystemdUnits),
+		TerminatedSystemdUnits:    make(SystemdUnits),
+		Groups:                    make(Groups),
+		TerminatedGroups:          make(Groups),
+		Aggregates:                make(Aggregates),
+		OwnerWorkloads:            make(OwnerWorkloads),
 	}
 }
 
@@ -267,6 +515,14 @@ func (s *Snapshot) Clone() *Snapshot {
 		TerminatedVirtualMachines: make(VirtualMachines, len(s.TerminatedVirtualMachines)),
 		Pods:                      make(Pods, len(s.Pods)),
 		TerminatedPods:            make(Pods, len(s.TerminatedPods)),
+		SystemdUnits:              make(SystemdUnits, len(s.SystemdUnits)),
+		TerminatedSystemdUnits:    make(SystemdUnits, len(s.TerminatedSystemdUnits)),
+		Groups:                    make(Groups, len(s.Groups)),
+		TerminatedGroups:          make(Groups, len(s.TerminatedGroups)),
+		Aggregates:                make(Aggregates, len(s.Aggregates)),
+		OwnerWorkloads:            make(OwnerWorkloads, len(s.OwnerWorkloads)),
+		Budgets:                   slices.Clone(s.Budgets),
+		Sources:                   slices.Clone(s.Sources),
 	}
 
 	// Deep copy the processes map
@@ -306,5 +562,31 @@ func (s *Snapshot) Clone() *Snapshot {
 		clone.TerminatedPods[id] = src.Clone()
 	}
 
+	for name, src := range s.SystemdUnits {
+		clone.SystemdUnits[name] = src.Clone()
+	}
+
+	// Deep copy terminated systemd units map
+	for name, src := range s.TerminatedSystemdUnits {
+		clone.TerminatedSystemdUnits[name] = src.Clone()
+	}
+
+	for name, src := range s.Groups {
+		clone.Groups[name] = src.Clone()
+	}
+
+	// Deep copy terminated groups map
+	for name, src := range s.TerminatedGroups {
+		clone.TerminatedGroups[name] = src.Clone()
+	}
+
+	for name, src := range s.Aggregates {
+		clone.Aggregates[name] = src.Clone()
+	}
+
+	for key, src := range s.OwnerWorkloads {
+		clone.OwnerWorkloads[key] = src.Clone()
+	}
+
 	return clone
 }
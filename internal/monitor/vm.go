@@ -4,6 +4,8 @@
 package monitor
 
 import (
+	"maps"
+
 	"github.com/sustainable-computing-io/kepler/internal/resource"
 )
 
@@ -18,20 +20,8 @@ func (pm *PowerMonitor) firstVMRead(snapshot *Snapshot) error {
 	for id, vm := range running {
 		vmInstance := newVM(vm, zones)
 
-		// Calculate initial energy based on CPU ratio * nodeActiveEnergy
-		for zone, nodeZoneUsage := range zones {
-			if nodeZoneUsage.ActivePower == 0 || nodeZoneUsage.activeEnergy == 0 || nodeCPUTimeDelta == 0 {
-				continue
-			}
-
-			cpuTimeRatio := vm.CPUTimeDelta / nodeCPUTimeDelta
-			activeEnergy := Energy(cpuTimeRatio * float64(nodeZoneUsage.activeEnergy))
-
-			vmInstance.Zones[zone] = Usage{
-				Power:       Power(0), // No power in first read - no delta time to calculate rate
-				EnergyTotal: activeEnergy,
-			}
-		}
+		// Calculate initial energy as the share of node active energy
+		pm.attributeZones(vmInstance.Zones, zones, Workload{Kind: VMWorkload, ID: id, CPUTimeDelta: vm.CPUTimeDelta}, nodeCPUTimeDelta, pm.restoredZones(VMWorkload, id, zones))
 
 		vms[id] = vmInstance
 	}
@@ -44,11 +34,8 @@ func (pm *PowerMonitor) firstVMRead(snapshot *Snapshot) error {
 
 // calculateVMPower calculates power for each running VM and handles terminated VMs
 func (pm *PowerMonitor) calculateVMPower(prev, newSnapshot *Snapshot) error {
-	// Clear terminated workloads if snapshot has been exported
-	if pm.exported.Load() {
-		pm.logger.Debug("Clearing terminated VMs after export")
-		pm.terminatedVMsTracker.Clear()
-	}
+	// Release terminated workloads that are past retention (or exported)
+	pm.terminatedVMsTracker.Prune(pm.exported.Load())
 
 	vms := pm.resources.VirtualMachines()
 
@@ -63,6 +50,9 @@ func (pm *PowerMonitor) calculateVMPower(prev, newSnapshot *Snapshot) error {
 		// Add to internal tracker (which will handle priority-based retention)
 		// NOTE: Each terminated VM is only added once since a VM cannot be terminated twice
 		pm.terminatedVMsTracker.Add(prevVM.Clone())
+		pm.queueTerminated("vm", id, map[string]string{
+			"name": prevVM.Name, "hypervisor": string(prevVM.Hypervisor),
+		})
 	}
 
 	nodeCPUTimeDelta := pm.resources.Node().ProcessTotalCPUTimeDelta
@@ -75,36 +65,28 @@ func (pm *PowerMonitor) calculateVMPower(prev, newSnapshot *Snapshot) error {
 	vmMap := make(VirtualMachines, len(vms.Running))
 
 	// For each VM, calculate power for each zone separately
+	zones := newSnapshot.Node.Zones
 	for id, vm := range vms.Running {
-		newVMInstance := newVM(vm, newSnapshot.Node.Zones)
-
-		// For each zone in the node, calculate VM's share
-		for zone, nodeZoneUsage := range newSnapshot.Node.Zones {
-			// Skip zones with zero power to avoid division by zero
-			if nodeZoneUsage.ActivePower == 0 || nodeZoneUsage.activeEnergy == 0 || nodeCPUTimeDelta == 0 {
-				continue
-			}
-
-			// Calculate VM's share of this zone's power and energy
-			cpuTimeRatio := vm.CPUTimeDelta / nodeCPUTimeDelta
-
-			// Calculate energy delta for this interval
-			activeEnergy := Energy(cpuTimeRatio * float64(nodeZoneUsage.activeEnergy))
-
-			// Calculate absolute energy based on previous data
-			absoluteEnergy := activeEnergy
-			if prev, exists := prev.VirtualMachines[id]; exists {
-				if prevUsage, hasZone := prev.Zones[zone]; hasZone {
-					absoluteEnergy += prevUsage.EnergyTotal
-				}
-			}
-
-			newVMInstance.Zones[zone] = Usage{
-				Power:       Power(cpuTimeRatio * nodeZoneUsage.ActivePower.MicroWatts()),
-				EnergyTotal: absoluteEnergy,
-			}
+		// Calculate share of each zone's active power and energy; energy
+		// accumulates over the previous snapshot
+		var prevZones ZoneUsageMap
+		prevVM, exists := prev.VirtualMachines[id]
+		if exists {
+			prevZones = prevVM.Zones
+		} else {
+			prevZones = pm.restoredZones(VMWorkload, id, zones)
 		}
+		usage := pm.usageBuffer(zones)
+		pm.attributeZones(usage, zones, Workload{Kind: VMWorkload, ID: id, CPUTimeDelta: vm.CPUTimeDelta}, nodeCPUTimeDelta, prevZones)
 
+		// share unchanged VMs with the previous snapshot (copy-on-write)
+		if exists && prevVM.matches(vm) && sameUsage(prevVM.Zones, usage) {
+			vmMap[id] = prevVM
+			continue
+		}
+
+		newVMInstance := newVM(vm, zones)
+		maps.Copy(newVMInstance.Zones, usage)
 		vmMap[id] = newVMInstance
 	}
 
@@ -120,12 +102,21 @@ func (pm *PowerMonitor) calculateVMPower(prev, newSnapshot *Snapshot) error {
 	return nil
 }
 
+// matches returns true if the VM has the same attributes as vm
+func (v *VirtualMachine) matches(vm *resource.VirtualMachine) bool {
+	return v.ID == vm.ID &&
+		v.Name == vm.Name &&
+		v.Hypervisor == vm.Hypervisor &&
+		v.CPUTotalTime == vm.CPUTotalTime
+}
+
 // newVM creates a new VirtualMachine struct with initialized zones from resource.VirtualMachine
 func newVM(vm *resource.VirtualMachine, zones NodeZoneUsageMap) *VirtualMachine {
 	newVMInstance := &VirtualMachine{
 		ID:           vm.ID,
 		Name:         vm.Name,
 		Hypervisor:   vm.Hypervisor,
+		CID:          vm.CID,
 		CPUTotalTime: vm.CPUTotalTime,
 		Zones:        make(ZoneUsageMap, len(zones)),
 	}
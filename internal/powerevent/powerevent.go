@@ -0,0 +1,312 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package powerevent watches the logs of the BMC of the node for events
+// related to power, e.g. failed power supplies, an engaged power cap or a
+// brownout, and reports them in the logs, over REST and as metrics.
+package powerevent
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+// Endpoint is the endpoint the recent power events are served at
+const Endpoint = "/events"
+
+// maxEvents is the number of most recent events kept
+const maxEvents = 100
+
+// Kinds of power events
+const (
+	KindPSUFailure = device.PowerEventPSUFailure
+	KindPowerCap   = device.PowerEventPowerCap
+	KindBrownout   = device.PowerEventBrownout
+)
+
+// Source returns the power events logged by the BMC, e.g. a Redfish zone
+type Source interface {
+	PowerEvents() ([]device.PowerEvent, error)
+}
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// Event is an event related to power logged by the BMC
+type Event struct {
+	ID string `json:"id"` // of the log entry in the BMC
+
+	// Time is when the BMC logged the event, or when it was first read if
+	// the BMC reports no valid time
+	Time time.Time `json:"time"`
+
+	Kind      string `json:"kind"`
+	Severity  string `json:"severity"` // OK, Warning or Critical
+	MessageID string `json:"messageId,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Events is the response of the events endpoint
+type Events struct {
+	Events []Event `json:"events"` // oldest first
+}
+
+// Count is the number of events of a kind and severity logged since Kepler
+// started
+type Count struct {
+	Kind     string
+	Severity string
+	Total    int
+}
+
+type Opts struct {
+	logger   *slog.Logger
+	clock    clock.WithTicker
+	interval time.Duration
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:   slog.Default(),
+		clock:    clock.RealClock{},
+		interval: time.Minute,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Watcher
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to read the logs periodically
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithInterval sets the interval between reads of the logs of the BMC
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// countKey identifies a count of events
+type countKey struct {
+	kind, severity string
+}
+
+// Watcher reads the power events of the BMC periodically, logging and counting
+// the events logged since it started
+type Watcher struct {
+	logger   *slog.Logger
+	source   Source
+	api      APIRegistry
+	clock    clock.WithTicker
+	interval time.Duration
+
+	mu     sync.Mutex
+	read   bool                // the logs were read at least once
+	seen   map[string]struct{} // keys of the events of the last read
+	events []Event             // most recent, oldest first
+	counts map[countKey]int
+}
+
+var (
+	_ service.Initializer = (*Watcher)(nil)
+	_ service.Runner      = (*Watcher)(nil)
+	_ service.Dependent   = (*Watcher)(nil)
+)
+
+// NewWatcher creates a new Watcher of the power events of source that serves
+// them using api
+func NewWatcher(source Source, api APIRegistry, applyOpts ...OptionFn) *Watcher {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Watcher{
+		logger:   opts.logger.With("service", "powerevent"),
+		source:   source,
+		api:      api,
+		clock:    opts.clock,
+		interval: opts.interval,
+		seen:     map[string]struct{}{},
+		counts:   map[countKey]int{},
+	}
+}
+
+func (w *Watcher) Name() string {
+	return "powerevent"
+}
+
+// Dependencies returns the API server the events are served by
+func (w *Watcher) Dependencies() []service.Service {
+	if s, ok := w.api.(service.Service); ok {
+		return []service.Service{s}
+	}
+	return nil
+}
+
+func (w *Watcher) Init() error {
+	return w.api.Register(Endpoint, "Power events",
+		"Recent power events logged by the BMC: failed power supplies, power cap engaged, brownouts (?since=1h&kind=psu-failure)",
+		http.HandlerFunc(w.handleEvents))
+}
+
+// Run reads the logs of the BMC periodically until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := w.clock.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			w.poll()
+		}
+	}
+}
+
+// poll reads the power events of the BMC and observes them
+func (w *Watcher) poll() {
+	events, err := w.source.PowerEvents()
+	if err != nil {
+		w.logger.Warn("Failed to read power events of the BMC", "error", err)
+		return
+	}
+	w.observe(events)
+}
+
+// observe records the events not seen in the previous read. The events already
+// logged when the logs are first read are kept but not counted nor logged as
+// new, since they happened before Kepler started.
+func (w *Watcher) observe(events []device.PowerEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.clock.Now()
+	seen := make(map[string]struct{}, len(events))
+	var added []Event
+	for _, e := range events {
+		// the IDs of entries are reused by some BMCs once their logs are cleared
+		key := e.ID + "@" + e.Created.String()
+		seen[key] = struct{}{}
+		if _, ok := w.seen[key]; ok {
+			continue
+		}
+		event := Event{ID: e.ID, Time: e.Created, Kind: e.Kind, Severity: e.Severity, MessageID: e.MessageID, Message: e.Message}
+		if event.Time.IsZero() {
+			event.Time = now
+		}
+		added = append(added, event)
+	}
+	w.seen = seen
+	slices.SortStableFunc(added, func(a, b Event) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	if !w.read {
+		w.read = true
+		if len(added) > 0 {
+			w.logger.Info("Read past power events of the BMC", "events", len(added))
+		}
+	} else {
+		for _, e := range added {
+			w.logger.Warn("Power event logged by the BMC",
+				"kind", e.Kind, "severity", e.Severity, "message", e.Message, "messageId", e.MessageID, "time", e.Time)
+			w.counts[countKey{e.Kind, e.Severity}]++
+		}
+	}
+
+	w.events = append(w.events, added...)
+	if n := len(w.events); n > maxEvents {
+		w.events = slices.Clone(w.events[n-maxEvents:])
+	}
+}
+
+// Events returns the recent events of kind at or after since, oldest first;
+// all kinds if kind is empty and all recent events if since is zero
+func (w *Watcher) Events(since time.Time, kind string) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ret := []Event{}
+	for _, e := range w.events {
+		if (since.IsZero() || !e.Time.Before(since)) && (kind == "" || e.Kind == kind) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// Counts returns the number of events of each kind and severity logged since
+// Kepler started
+func (w *Watcher) Counts() []Count {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ret := make([]Count, 0, len(w.counts))
+	for key, total := range w.counts {
+		ret = append(ret, Count{Kind: key.kind, Severity: key.severity, Total: total})
+	}
+	slices.SortFunc(ret, func(a, b Count) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Severity, b.Severity))
+	})
+	return ret
+}
+
+// handleEvents serves the recent events of the duration of the since query
+// parameter and of the kind of the kind parameter, or all recent ones
+func (w *Watcher) handleEvents(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rw.Header().Set("Allow", http.MethodGet)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var since time.Time
+	if s := req.URL.Query().Get("since"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid since: %q; must be a duration, e.g. 1h", s), http.StatusBadRequest)
+			return
+		}
+		since = w.clock.Now().Add(-d)
+	}
+	kind := req.URL.Query().Get("kind")
+	switch kind {
+	case "", KindPSUFailure, KindPowerCap, KindBrownout:
+	default:
+		http.Error(rw, fmt.Sprintf("invalid kind: %q; must be one of %s, %s, %s", kind, KindPSUFailure, KindPowerCap, KindBrownout),
+			http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(Events{Events: w.Events(since, kind)}); err != nil {
+		w.logger.Error("Failed to write power events", "error", err)
+	}
+}
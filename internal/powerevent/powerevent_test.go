@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package powerevent
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// fakeSource returns the events set last, or err
+type fakeSource struct {
+	events []device.PowerEvent
+	err    error
+}
+
+func (s *fakeSource) PowerEvents() ([]device.PowerEvent, error) {
+	return s.events, s.err
+}
+
+func TestWatcher(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(now)
+	registry := server.NewFakeRegistry()
+	past := device.PowerEvent{ID: "/Entries/1", Kind: KindPSUFailure, Created: now.Add(-24 * time.Hour), Severity: "Critical", Message: "Power supply 2 failed."}
+	source := &fakeSource{events: []device.PowerEvent{past}}
+
+	w := NewWatcher(source, registry, WithClock(fakeClock))
+	assert.Equal(t, "powerevent", w.Name())
+	require.NoError(t, w.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+
+	w.poll()
+	assert.Len(t, w.Events(time.Time{}, ""), 1, "past events are kept")
+	assert.Empty(t, w.Counts(), "past events are not counted")
+
+	fakeClock.Step(time.Minute)
+	source.events = []device.PowerEvent{
+		past,
+		{ID: "/Entries/2", Kind: KindBrownout, Created: now.Add(30 * time.Second), Severity: "Warning", Message: "The power input for power supply 1 is lost."},
+		{ID: "/Entries/3", Kind: KindPowerCap, Severity: "Warning", Message: "Power cap engaged."},
+	}
+	w.poll()
+	source.err = errors.New("BMC not responding")
+	w.poll()
+	source.err = nil
+	// the log is cleared and the IDs of its entries reused
+	fakeClock.Step(time.Minute)
+	source.events = []device.PowerEvent{
+		{ID: "/Entries/1", Kind: KindBrownout, Created: now.Add(90 * time.Second), Severity: "Warning", Message: "Input undervoltage"},
+	}
+	w.poll()
+
+	events := w.Events(time.Time{}, "")
+	require.Len(t, events, 4)
+	assert.Equal(t, Event{ID: "/Entries/2", Time: now.Add(30 * time.Second), Kind: KindBrownout, Severity: "Warning",
+		Message: "The power input for power supply 1 is lost."}, events[1])
+	assert.Equal(t, now.Add(time.Minute), events[2].Time, "events without a time are at the time they were read")
+	assert.Equal(t, []Count{
+		{Kind: KindBrownout, Severity: "Warning", Total: 2},
+		{Kind: KindPowerCap, Severity: "Warning", Total: 1},
+	}, w.Counts())
+	assert.Len(t, w.Events(now, ""), 3)
+	assert.Len(t, w.Events(time.Time{}, KindBrownout), 2)
+
+	t.Run("REST", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?since=1h&kind=power-cap", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got Events
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		require.Len(t, got.Events, 1)
+		assert.Equal(t, "Power cap engaged.", got.Events[0].Message)
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?kind=fan", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?since=yesterday", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pricing provides the price of electricity, used to convert the energy
+// consumed by the node and workloads into cost.
+package pricing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// time zones of schedules are resolved without the time zone database of
+	// the host, which is missing from minimal images
+	_ "time/tzdata"
+)
+
+// joulesPerKWh is the number of joules in a kWh
+const joulesPerKWh = 3.6e6
+
+// Price is the price of electricity per kWh in the currency of the tariff
+type Price float64
+
+// Cost returns the cost of consuming joules of energy at the price
+func (p Price) Cost(joules float64) float64 {
+	return float64(p) * joules / joulesPerKWh
+}
+
+// Period is a time of day period with its own price, e.g. peak hours
+type Period struct {
+	Start time.Duration  // since midnight
+	End   time.Duration  // since midnight; periods ending before they start end the next day
+	Days  []time.Weekday // days the period starts on; all days if empty
+	Price Price
+}
+
+// contains returns true if t, in the location of the tariff, is in the period
+func (p Period) contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	day := t.Weekday()
+
+	if p.End <= p.Start { // wraps past midnight
+		if offset >= p.Start {
+			return p.onDay(day)
+		}
+		return offset < p.End && p.onDay((day+6)%7) // started the previous day
+	}
+	return offset >= p.Start && offset < p.End && p.onDay(day)
+}
+
+func (p Period) onDay(day time.Weekday) bool {
+	if len(p.Days) == 0 {
+		return true
+	}
+	for _, d := range p.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Tariff is a flat price of electricity with optional time of day periods
+type Tariff struct {
+	Price    Price    // price outside of periods
+	Periods  []Period // the first period containing a time sets its price
+	Location *time.Location
+}
+
+// PriceAt returns the price of electricity at the time at
+func (t *Tariff) PriceAt(at time.Time) Price {
+	loc := t.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	at = at.In(loc)
+	for _, p := range t.Periods {
+		if p.contains(at) {
+			return p.Price
+		}
+	}
+	return t.Price
+}
+
+// ParseTimeOfDay parses a time of day in the 24-hour HH:MM format, e.g. 17:30,
+// into the duration since midnight; 24:00 is the end of the day
+func ParseTimeOfDay(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q; must be HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ParseWeekday parses the name of a day of the week, e.g. mon or Monday,
+// ignoring case
+func ParseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q; must be one of mon, tue, wed, thu, fri, sat, sun", s)
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCost(t *testing.T) {
+	assert.InDelta(t, 0.15, Price(0.15).Cost(3.6e6), 1e-9)
+	assert.InDelta(t, 0.0, Price(0).Cost(3.6e6), 1e-9)
+}
+
+func TestTariff(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tariff := &Tariff{
+		Price: 0.10,
+		Periods: []Period{
+			// weekday evening peak
+			{Start: 17 * time.Hour, End: 21 * time.Hour, Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, Price: 0.30},
+			// overnight off-peak
+			{Start: 23 * time.Hour, End: 6 * time.Hour, Price: 0.05},
+		},
+		Location: ny,
+	}
+
+	tt := []struct {
+		name string
+		at   time.Time
+		want Price
+	}{
+		{"weekday afternoon", time.Date(2025, 6, 2, 12, 0, 0, 0, ny), 0.10},
+		{"weekday peak start", time.Date(2025, 6, 2, 17, 0, 0, 0, ny), 0.30},
+		{"weekday peak end", time.Date(2025, 6, 2, 21, 0, 0, 0, ny), 0.10},
+		{"weekend evening", time.Date(2025, 6, 1, 18, 0, 0, 0, ny), 0.10},
+		{"before midnight", time.Date(2025, 6, 2, 23, 30, 0, 0, ny), 0.05},
+		{"after midnight", time.Date(2025, 6, 3, 5, 59, 0, 0, ny), 0.05},
+		{"morning", time.Date(2025, 6, 3, 6, 0, 0, 0, ny), 0.10},
+		{"in another time zone", time.Date(2025, 6, 2, 22, 0, 0, 0, time.UTC), 0.30},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tariff.PriceAt(tc.at))
+		})
+	}
+
+	t.Run("wrapping period on days", func(t *testing.T) {
+		// Friday night to Saturday morning only
+		tariff := &Tariff{Price: 0.10, Periods: []Period{
+			{Start: 22 * time.Hour, End: 2 * time.Hour, Days: []time.Weekday{time.Friday}, Price: 0.01},
+		}}
+		assert.Equal(t, Price(0.01), tariff.PriceAt(time.Date(2025, 6, 6, 23, 0, 0, 0, time.UTC)))
+		assert.Equal(t, Price(0.01), tariff.PriceAt(time.Date(2025, 6, 7, 1, 0, 0, 0, time.UTC)))
+		assert.Equal(t, Price(0.10), tariff.PriceAt(time.Date(2025, 6, 8, 1, 0, 0, 0, time.UTC)))
+	})
+}
+
+func TestParse(t *testing.T) {
+	d, err := ParseTimeOfDay("17:30")
+	require.NoError(t, err)
+	assert.Equal(t, 17*time.Hour+30*time.Minute, d)
+
+	d, err = ParseTimeOfDay("24:00")
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, d)
+
+	for _, s := range []string{"", "5pm", "25:00", "12:60"} {
+		_, err := ParseTimeOfDay(s)
+		assert.Error(t, err, s)
+	}
+
+	for s, want := range map[string]time.Weekday{"mon": time.Monday, "Sunday": time.Sunday, "SAT": time.Saturday} {
+		got, err := ParseWeekday(s)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err = ParseWeekday("mo")
+	assert.Error(t, err)
+}
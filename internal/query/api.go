@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package query evaluates Prometheus-style instant queries, e.g.
+// topk(5, sum by (pod_namespace) (kepler_pod_cpu_watts)), against the current
+// snapshot and the history of the power, for clients that don't run
+// Prometheus.
+package query
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/sustainable-computing-io/kepler/internal/history"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+type (
+	Initializer = service.Initializer
+	Dependent   = service.Dependent
+	Monitor     = monitor.Service
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// Endpoint is the endpoint queries are served at
+const Endpoint = "/api/v1/query"
+
+type Opts struct {
+	logger *slog.Logger
+	store  history.Store
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger: slog.Default(),
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the API
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithHistory sets the store of the history functions over time are evaluated
+// against; they fail without it
+func WithHistory(store history.Store) OptionFn {
+	return func(o *Opts) {
+		o.store = store
+	}
+}
+
+// API serves instant queries over the snapshot of the monitor
+type API struct {
+	logger  *slog.Logger
+	monitor Monitor
+	server  APIRegistry
+	store   history.Store
+}
+
+var (
+	_ Initializer = (*API)(nil)
+	_ Dependent   = (*API)(nil)
+)
+
+// NewAPI creates a new API serving queries over the snapshot of pm using s
+func NewAPI(pm Monitor, s APIRegistry, applyOpts ...OptionFn) *API {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &API{
+		logger:  opts.logger.With("service", "query"),
+		monitor: pm,
+		server:  s,
+		store:   opts.store,
+	}
+}
+
+func (a *API) Name() string {
+	return "query"
+}
+
+// Dependencies returns the monitor queries are evaluated against and the API
+// server they are served by
+func (a *API) Dependencies() []service.Service {
+	deps := []service.Service{a.monitor}
+	if s, ok := a.server.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (a *API) Init() error {
+	return a.server.Register(Endpoint, "Query",
+		"Prometheus-style instant query over the current power (?expr=topk(5, kepler_pod_cpu_watts))",
+		http.HandlerFunc(a.handleQuery))
+}
+
+// Response is the response of the query endpoint, in the format of the
+// Prometheus HTTP API so that its clients can read it
+type Response struct {
+	Status    string `json:"status"`
+	Data      *Data  `json:"data,omitempty"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Data is the result of a query
+type Data struct {
+	ResultType string   `json:"resultType"`
+	Result     []Result `json:"result"`
+}
+
+// Result is a sample of the result of a query; Value is the Unix time in
+// seconds and the value as a string
+type Result struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]any            `json:"value"`
+}
+
+// Error types of responses
+const (
+	ErrorBadData     = "bad_data"
+	ErrorExecution   = "execution"
+	ErrorUnavailable = "unavailable"
+)
+
+// handleQuery evaluates the expr query parameter, or query as in Prometheus,
+// against the current snapshot
+func (a *API) handleQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	input := r.FormValue("expr")
+	if input == "" {
+		input = r.FormValue("query")
+	}
+	if input == "" {
+		a.respond(w, http.StatusBadRequest, Response{ErrorType: ErrorBadData, Error: "missing expr parameter"})
+		return
+	}
+	e, err := Parse(input)
+	if err != nil {
+		a.respond(w, http.StatusBadRequest, Response{ErrorType: ErrorBadData, Error: "invalid expression: " + err.Error()})
+		return
+	}
+
+	snapshot, err := a.monitor.LatestSnapshot()
+	if err != nil {
+		a.logger.Error("Failed to get snapshot", "error", err)
+		a.respond(w, http.StatusServiceUnavailable, Response{ErrorType: ErrorUnavailable, Error: "failed to get power data"})
+		return
+	}
+	v, err := NewEvaluator(snapshot, a.store).Eval(e)
+	if err != nil {
+		a.respond(w, http.StatusUnprocessableEntity, Response{ErrorType: ErrorExecution, Error: err.Error()})
+		return
+	}
+
+	ts := float64(snapshot.Timestamp.UnixMilli()) / 1000
+	data := &Data{ResultType: "vector", Result: make([]Result, 0, len(v))}
+	for _, s := range v {
+		data.Result = append(data.Result, Result{
+			Metric: s.Labels,
+			Value:  [2]any{ts, strconv.FormatFloat(s.Value, 'f', -1, 64)},
+		})
+	}
+	a.respond(w, http.StatusOK, Response{Data: data})
+}
+
+func (a *API) respond(w http.ResponseWriter, status int, resp Response) {
+	resp.Status = "success"
+	if resp.Error != "" {
+		resp.Status = "error"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		a.logger.Error("Failed to write query response", "error", err)
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+)
+
+func TestAPI(t *testing.T) {
+	pm := monitor.NewFakeMonitor(testSnapshot())
+	registry := server.NewFakeRegistry()
+	api := NewAPI(pm, registry)
+	assert.Equal(t, "query", api.Name())
+	require.NoError(t, api.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+
+	get := func(t *testing.T, params url.Values) (int, Response) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Endpoint+"?"+params.Encode(), nil))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var resp Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return rec.Code, resp
+	}
+
+	t.Run("query", func(t *testing.T) {
+		for _, param := range []string{"expr", "query"} {
+			code, resp := get(t, url.Values{param: {`sum by (pod_namespace) (kepler_pod_cpu_watts{zone="package"})`}})
+			require.Equal(t, http.StatusOK, code)
+			assert.Equal(t, "success", resp.Status)
+			require.NotNil(t, resp.Data)
+			assert.Equal(t, "vector", resp.Data.ResultType)
+			assert.Equal(t, []Result{
+				{Metric: map[string]string{"pod_namespace": "dev"}, Value: [2]any{1748779200.0, "5"}},
+				{Metric: map[string]string{"pod_namespace": "prod"}, Value: [2]any{1748779200.0, "30"}},
+			}, resp.Data.Result)
+		}
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		code, resp := get(t, url.Values{"expr": {`kepler_vm_cpu_watts`}})
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, resp.Data.Result)
+		assert.NotNil(t, resp.Data.Result)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		code, resp := get(t, url.Values{})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, Response{Status: "error", ErrorType: ErrorBadData, Error: "missing expr parameter"}, resp)
+
+		code, resp = get(t, url.Values{"expr": {`sum(`}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, ErrorBadData, resp.ErrorType)
+		assert.Contains(t, resp.Error, "invalid expression")
+
+		code, resp = get(t, url.Values{"expr": {`avg_over_time(kepler_node_cpu_watts[5m])`}})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, ErrorExecution, resp.ErrorType)
+		assert.Contains(t, resp.Error, "enable history")
+
+		pm.SetError(errors.New("no data yet"))
+		defer pm.SetError(nil)
+		code, resp = get(t, url.Values{"expr": {`kepler_node_cpu_watts`}})
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, ErrorUnavailable, resp.ErrorType)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, Endpoint, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -0,0 +1,380 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/history"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+// NameLabel is the label of the metric name of a sample
+const NameLabel = "__name__"
+
+// Sample is the value of a series
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Vector is the result of an expression: a sample per series
+type Vector []Sample
+
+// metricPattern matches the metrics that can be queried; the levels match the
+// kinds of history rows
+var metricPattern = regexp.MustCompile(`^kepler_(node|process|container|vm|pod)_cpu_(watts|joules_total)$`)
+
+// Evaluator evaluates expressions against a snapshot and, for functions over
+// time, the history of the power
+type Evaluator struct {
+	snapshot *monitor.Snapshot
+	store    history.Store // nil if history isn't recorded
+}
+
+// NewEvaluator returns an evaluator against snapshot and the history in store,
+// which may be nil
+func NewEvaluator(snapshot *monitor.Snapshot, store history.Store) *Evaluator {
+	return &Evaluator{snapshot: snapshot, store: store}
+}
+
+// Eval evaluates e at the time of the snapshot
+func (ev *Evaluator) Eval(e Expr) (Vector, error) {
+	switch e := e.(type) {
+	case *Selector:
+		if e.Range > 0 {
+			return nil, fmt.Errorf("range selector %s[%s] must be the argument of a function over time, e.g. avg_over_time", e.Metric, e.Range)
+		}
+		return ev.instant(e)
+	case *Call:
+		return ev.overTime(e)
+	case *Aggregate:
+		v, err := ev.Eval(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return aggregate(e, v), nil
+	case *Compare:
+		v, err := ev.Eval(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return slices.DeleteFunc(v, func(s Sample) bool { return !compare(e.Op, s.Value, e.Value) }), nil
+	default:
+		return nil, fmt.Errorf("unsupported expression %T", e)
+	}
+}
+
+// parseMetric returns the level and unit of metric
+func parseMetric(metric string) (level, unit string, err error) {
+	m := metricPattern.FindStringSubmatch(metric)
+	if m == nil {
+		return "", "", fmt.Errorf("unknown metric %q; must be kepler_<level>_cpu_watts or kepler_<level>_cpu_joules_total, "+
+			"where level is one of node, process, container, vm, pod", metric)
+	}
+	return m[1], m[2], nil
+}
+
+// instant returns the samples of the series selected by s in the snapshot
+func (ev *Evaluator) instant(s *Selector) (Vector, error) {
+	level, unit, err := parseMetric(s.Metric)
+	if err != nil {
+		return nil, err
+	}
+	value := func(u monitor.Usage) float64 {
+		if unit == "watts" {
+			return u.Power.Watts()
+		}
+		return u.EnergyTotal.Joules()
+	}
+
+	var v Vector
+	// zones of the same name, e.g. package zones of each socket, are summed
+	add := func(labels map[string]string, zones monitor.ZoneUsageMap) {
+		byName := map[string]float64{}
+		for zone, usage := range zones {
+			byName[zone.Name()] += value(usage)
+		}
+		for zone, value := range byName {
+			l := maps.Clone(labels)
+			l[NameLabel] = s.Metric
+			l["zone"] = zone
+			if matches(s.Matchers, l) {
+				v = append(v, Sample{Labels: l, Value: value})
+			}
+		}
+	}
+
+	snapshot := ev.snapshot
+	switch level {
+	case history.KindNode:
+		if snapshot.Node != nil {
+			zones := make(monitor.ZoneUsageMap, len(snapshot.Node.Zones))
+			for zone, usage := range snapshot.Node.Zones {
+				zones[zone] = monitor.Usage{EnergyTotal: usage.EnergyTotal, Power: usage.Power}
+			}
+			add(map[string]string{}, zones)
+		}
+	case history.KindProcess:
+		for _, p := range snapshot.Processes {
+			add(map[string]string{
+				"pid":          strconv.Itoa(p.PID),
+				"comm":         p.Comm,
+				"container_id": p.ContainerID,
+				"vm_id":        p.VirtualMachineID,
+			}, p.Zones)
+		}
+	case history.KindContainer:
+		for _, c := range snapshot.Containers {
+			labels := map[string]string{
+				"container_id":   c.ID,
+				"container_name": c.Name,
+				"runtime":        string(c.Runtime),
+				"pod_id":         c.PodID,
+			}
+			if pod, ok := snapshot.Pods[c.PodID]; ok {
+				labels["pod_name"] = pod.Name
+				labels["pod_namespace"] = pod.Namespace
+			}
+			add(labels, c.Zones)
+		}
+	case history.KindVM:
+		for _, vm := range snapshot.VirtualMachines {
+			add(map[string]string{
+				"vm_id":      vm.ID,
+				"vm_name":    vm.Name,
+				"hypervisor": string(vm.Hypervisor),
+			}, vm.Zones)
+		}
+	case history.KindPod:
+		for _, p := range snapshot.Pods {
+			add(map[string]string{
+				"pod_id":        p.ID,
+				"pod_name":      p.Name,
+				"pod_namespace": p.Namespace,
+			}, p.Zones)
+		}
+	}
+	sortVector(v)
+	return v, nil
+}
+
+// rowLabels returns the labels of the series of a history row, named as those
+// of the snapshot
+func rowLabels(r history.Row) map[string]string {
+	labels := map[string]string{"zone": r.Zone}
+	switch r.Kind {
+	case history.KindProcess:
+		labels["pid"] = r.ID
+		labels["comm"] = r.Name
+	case history.KindContainer:
+		labels["container_id"] = r.ID
+		labels["container_name"] = r.Name
+		labels["pod_namespace"] = r.Namespace
+	case history.KindVM:
+		labels["vm_id"] = r.ID
+		labels["vm_name"] = r.Name
+	case history.KindPod:
+		labels["pod_id"] = r.ID
+		labels["pod_name"] = r.Name
+		labels["pod_namespace"] = r.Namespace
+	}
+	return labels
+}
+
+// overTime applies the function of c to the power of each series selected in
+// the history in the range before the snapshot
+func (ev *Evaluator) overTime(c *Call) (Vector, error) {
+	if ev.store == nil {
+		return nil, fmt.Errorf("%s needs the history of the power, which is not recorded; enable history", c.Func)
+	}
+	level, unit, err := parseMetric(c.Selector.Metric)
+	if err != nil {
+		return nil, err
+	}
+	if unit != "watts" {
+		return nil, fmt.Errorf("%s is only supported for the power in watts; the history has no %s", c.Func, c.Selector.Metric)
+	}
+
+	end := ev.snapshot.Timestamp
+	rows, err := ev.store.Query(history.Query{
+		Kind:  level,
+		Start: end.Add(-c.Selector.Range),
+		End:   end.Add(time.Nanosecond), // the snapshot is included
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+
+	// rows of zones of the same name at the same time are summed, as in the
+	// snapshot, before the function is applied over time
+	type point struct {
+		labels map[string]string
+		values map[time.Time]float64
+	}
+	series := map[string]*point{}
+	for _, r := range rows {
+		labels := rowLabels(r)
+		if !matches(c.Selector.Matchers, labels) {
+			continue
+		}
+		key := labelsKey(labels)
+		p, ok := series[key]
+		if !ok {
+			p = &point{labels: labels, values: map[time.Time]float64{}}
+			series[key] = p
+		}
+		p.values[r.Timestamp] += r.Watts
+	}
+
+	v := make(Vector, 0, len(series))
+	for _, p := range series {
+		values := slices.Collect(maps.Values(p.values))
+		v = append(v, Sample{Labels: p.labels, Value: overTime(c.Func, values)})
+	}
+	sortVector(v)
+	return v, nil
+}
+
+// overTime applies fn to values, of which there is at least one
+func overTime(fn string, values []float64) float64 {
+	switch fn {
+	case "min_over_time":
+		return slices.Min(values)
+	case "max_over_time":
+		return slices.Max(values)
+	case "count_over_time":
+		return float64(len(values))
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	if fn == "avg_over_time" {
+		return sum / float64(len(values))
+	}
+	return sum
+}
+
+// matches returns true if labels match all matchers
+func matches(matchers []Matcher, labels map[string]string) bool {
+	for _, m := range matchers {
+		if !m.Matches(labels[m.Label]) {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregate aggregates the samples of v grouped by the labels of a
+func aggregate(a *Aggregate, v Vector) Vector {
+	type group struct {
+		labels  map[string]string
+		samples []Sample
+	}
+	groups := map[string]*group{}
+	var keys []string
+	for _, s := range v {
+		labels := make(map[string]string, len(a.By))
+		for _, l := range a.By {
+			if value, ok := s.Labels[l]; ok && value != "" {
+				labels[l] = value
+			}
+		}
+		key := labelsKey(labels)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{labels: labels}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.samples = append(g.samples, s)
+	}
+	slices.Sort(keys)
+
+	ret := Vector{}
+	for _, key := range keys {
+		g := groups[key]
+		switch a.Op {
+		case "topk", "bottomk":
+			samples := slices.Clone(g.samples)
+			slices.SortStableFunc(samples, func(x, y Sample) int {
+				if a.Op == "topk" {
+					return cmp.Compare(y.Value, x.Value)
+				}
+				return cmp.Compare(x.Value, y.Value)
+			})
+			ret = append(ret, samples[:min(a.Param, len(samples))]...)
+		default:
+			values := make([]float64, len(g.samples))
+			for i, s := range g.samples {
+				values[i] = s.Value
+			}
+			ret = append(ret, Sample{Labels: g.labels, Value: aggregateValues(a.Op, values)})
+		}
+	}
+	return ret
+}
+
+func aggregateValues(op string, values []float64) float64 {
+	switch op {
+	case "min":
+		return slices.Min(values)
+	case "max":
+		return slices.Max(values)
+	case "count":
+		return float64(len(values))
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	if op == "avg" {
+		return sum / float64(len(values))
+	}
+	return sum
+}
+
+func compare(op string, a, b float64) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case "<":
+		return a < b
+	case ">=":
+		return a >= b
+	default:
+		return a <= b
+	}
+}
+
+// labelsKey returns a key identifying labels
+func labelsKey(labels map[string]string) string {
+	var b strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(labels[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// sortVector sorts v by labels, so that results are stable
+func sortVector(v Vector) {
+	slices.SortFunc(v, func(x, y Sample) int {
+		return strings.Compare(labelsKey(x.Labels), labelsKey(y.Labels))
+	})
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/history"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+var (
+	pkg0 = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	pkg1 = device.NewMockRaplZone("package", 1, "/sys/class/powercap/intel-rapl/intel-rapl:1", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:0", 1000*device.Joule)
+)
+
+var now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func usage(joules, watts float64) monitor.Usage {
+	return monitor.Usage{EnergyTotal: monitor.Energy(joules) * device.Joule, Power: monitor.Power(watts) * device.Watt}
+}
+
+func pod(id, name, namespace string, watts float64) *monitor.Pod {
+	return &monitor.Pod{ID: id, Name: name, Namespace: namespace,
+		Zones: monitor.ZoneUsageMap{pkg0: usage(10*watts, watts), dram: usage(watts, watts/10)}}
+}
+
+func testSnapshot() *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = now
+	s.Node = &monitor.Node{Zones: monitor.NodeZoneUsageMap{
+		pkg0: {EnergyTotal: 600 * device.Joule, Power: 60 * device.Watt},
+		pkg1: {EnergyTotal: 400 * device.Joule, Power: 40 * device.Watt},
+		dram: {EnergyTotal: 100 * device.Joule, Power: 10 * device.Watt},
+	}}
+	s.Pods["p-1"] = pod("p-1", "web-0", "prod", 20)
+	s.Pods["p-2"] = pod("p-2", "web-1", "prod", 10)
+	s.Pods["p-3"] = pod("p-3", "batch-0", "dev", 5)
+	s.Containers["c-1"] = &monitor.Container{ID: "c-1", Name: "web", Runtime: "containerd", PodID: "p-1",
+		Zones: monitor.ZoneUsageMap{pkg0: usage(100, 20)}}
+	return s
+}
+
+func eval(t *testing.T, store history.Store, input string) Vector {
+	t.Helper()
+	e, err := Parse(input)
+	require.NoError(t, err, input)
+	v, err := NewEvaluator(testSnapshot(), store).Eval(e)
+	require.NoError(t, err, input)
+	return v
+}
+
+func TestEvalInstant(t *testing.T) {
+	t.Run("zones of the same name are summed", func(t *testing.T) {
+		assert.Equal(t, Vector{
+			{Labels: map[string]string{NameLabel: "kepler_node_cpu_joules_total", "zone": "dram"}, Value: 100},
+			{Labels: map[string]string{NameLabel: "kepler_node_cpu_joules_total", "zone": "package"}, Value: 1000},
+		}, eval(t, nil, `kepler_node_cpu_joules_total`))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		assert.Equal(t, Vector{{
+			Labels: map[string]string{
+				NameLabel: "kepler_pod_cpu_watts", "pod_id": "p-1", "pod_name": "web-0", "pod_namespace": "prod", "zone": "package",
+			},
+			Value: 20,
+		}}, eval(t, nil, `kepler_pod_cpu_watts{pod_namespace="prod", zone="package"} > 10`))
+		assert.Len(t, eval(t, nil, `kepler_pod_cpu_watts{pod_name!~"web-.*"}`), 2)
+	})
+
+	t.Run("containers have the labels of their pod", func(t *testing.T) {
+		v := eval(t, nil, `kepler_container_cpu_watts`)
+		require.Len(t, v, 1)
+		assert.Equal(t, map[string]string{
+			NameLabel: "kepler_container_cpu_watts", "container_id": "c-1", "container_name": "web", "runtime": "containerd",
+			"pod_id": "p-1", "pod_name": "web-0", "pod_namespace": "prod", "zone": "package",
+		}, v[0].Labels)
+	})
+
+	t.Run("sum by namespace", func(t *testing.T) {
+		assert.Equal(t, Vector{
+			{Labels: map[string]string{"pod_namespace": "dev"}, Value: 5},
+			{Labels: map[string]string{"pod_namespace": "prod"}, Value: 30},
+		}, eval(t, nil, `sum by (pod_namespace) (kepler_pod_cpu_watts{zone="package"})`))
+		assert.Equal(t, Vector{{Labels: map[string]string{}, Value: 6}},
+			eval(t, nil, `count(kepler_pod_cpu_watts)`))
+		assert.Equal(t, Vector{{Labels: map[string]string{}, Value: 0.5}},
+			eval(t, nil, `min(kepler_pod_cpu_watts)`))
+	})
+
+	t.Run("topk", func(t *testing.T) {
+		v := eval(t, nil, `topk(2, kepler_pod_cpu_watts{zone="package"})`)
+		require.Len(t, v, 2)
+		assert.Equal(t, "web-0", v[0].Labels["pod_name"])
+		assert.Equal(t, "web-1", v[1].Labels["pod_name"])
+
+		v = eval(t, nil, `bottomk by (pod_namespace) (1, kepler_pod_cpu_watts{zone="package"})`)
+		require.Len(t, v, 2, "one per namespace")
+		assert.Equal(t, "batch-0", v[0].Labels["pod_name"])
+		assert.Equal(t, "web-1", v[1].Labels["pod_name"])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for input, msg := range map[string]string{
+			`kepler_node_gpu_watts`:                         `unknown metric "kepler_node_gpu_watts"`,
+			`kepler_node_cpu_watts[5m]`:                     "must be the argument of a function over time",
+			`avg_over_time(kepler_node_cpu_watts[5m])`:      "needs the history of the power",
+			`sum(max_over_time(kepler_node_cpu_watts[5m]))`: "needs the history of the power",
+		} {
+			e, err := Parse(input)
+			require.NoError(t, err)
+			_, err = NewEvaluator(testSnapshot(), nil).Eval(e)
+			assert.ErrorContains(t, err, msg, input)
+		}
+	})
+}
+
+func TestEvalOverTime(t *testing.T) {
+	store := history.NewMemoryStore()
+	row := func(ago time.Duration, kind, id, name, namespace, zone string, watts float64) history.Row {
+		return history.Row{Timestamp: now.Add(-ago), Kind: kind, ID: id, Name: name, Namespace: namespace, Zone: zone, Watts: watts}
+	}
+	require.NoError(t, store.Append([]history.Row{
+		row(2*time.Hour, history.KindNode, "", "", "", "package", 500),
+		row(20*time.Minute, history.KindNode, "", "", "", "package", 60),
+		row(20*time.Minute, history.KindNode, "", "", "", "package", 40), // of another socket
+		row(10*time.Minute, history.KindNode, "", "", "", "package", 80),
+		row(10*time.Minute, history.KindNode, "", "", "", "dram", 10),
+		row(10*time.Minute, history.KindPod, "p-1", "web-0", "prod", "package", 20),
+		row(0, history.KindPod, "p-1", "web-0", "prod", "package", 30),
+		row(0, history.KindPod, "p-3", "batch-0", "dev", "package", 5),
+	}))
+
+	assert.Equal(t, Vector{
+		{Labels: map[string]string{"zone": "package"}, Value: 90},
+	}, eval(t, store, `avg_over_time(kepler_node_cpu_watts{zone="package"}[1h])`),
+		"sockets are summed and rows older than the range are ignored")
+	assert.Equal(t, Vector{
+		{Labels: map[string]string{"zone": "package"}, Value: 100},
+	}, eval(t, store, `max_over_time(kepler_node_cpu_watts{zone="package"}[1h])`))
+	assert.Equal(t, Vector{
+		{Labels: map[string]string{"pod_namespace": "dev"}, Value: 5},
+		{Labels: map[string]string{"pod_namespace": "prod"}, Value: 25},
+	}, eval(t, store, `sum by (pod_namespace) (avg_over_time(kepler_pod_cpu_watts[30m]))`))
+	assert.Equal(t, Vector{
+		{Labels: map[string]string{"pod_id": "p-1", "pod_name": "web-0", "pod_namespace": "prod", "zone": "package"}, Value: 2},
+	}, eval(t, store, `count_over_time(kepler_pod_cpu_watts{pod_namespace="prod"}[30m])`))
+
+	e, err := Parse(`avg_over_time(kepler_node_cpu_joules_total[1h])`)
+	require.NoError(t, err)
+	_, err = NewEvaluator(testSnapshot(), store).Eval(e)
+	assert.ErrorContains(t, err, "only supported for the power in watts")
+}
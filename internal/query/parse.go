@@ -0,0 +1,457 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Expr is a parsed expression
+type Expr interface {
+	expr()
+}
+
+// Selector selects the series of a metric whose labels match all matchers;
+// Range is the duration of history selected, 0 for the current snapshot
+type Selector struct {
+	Metric   string
+	Matchers []Matcher
+	Range    time.Duration
+}
+
+// Match operators
+const (
+	MatchEqual     = "="
+	MatchNotEqual  = "!="
+	MatchRegexp    = "=~"
+	MatchNotRegexp = "!~"
+)
+
+// Matcher matches the value of a label; a missing label has an empty value
+type Matcher struct {
+	Label string
+	Op    string
+	Value string
+
+	re *regexp.Regexp // of regexp operators, anchored as in Prometheus
+}
+
+// Matches returns true if value is matched
+func (m Matcher) Matches(value string) bool {
+	switch m.Op {
+	case MatchEqual:
+		return value == m.Value
+	case MatchNotEqual:
+		return value != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(value)
+	default:
+		return !m.re.MatchString(value)
+	}
+}
+
+// Aggregate aggregates the samples of Expr grouped by the By labels; Param is
+// the number of samples kept by topk and bottomk
+type Aggregate struct {
+	Op    string
+	By    []string
+	Param int
+	Expr  Expr
+}
+
+// Call applies a function over time to the history selected by Selector
+type Call struct {
+	Func     string
+	Selector *Selector
+}
+
+// Compare keeps the samples of Expr whose value compares to Value with Op
+type Compare struct {
+	Op    string
+	Expr  Expr
+	Value float64
+}
+
+func (*Selector) expr()  {}
+func (*Aggregate) expr() {}
+func (*Call) expr()      {}
+func (*Compare) expr()   {}
+
+// Aggregation operators
+var aggregations = []string{"sum", "avg", "min", "max", "count", "topk", "bottomk"}
+
+// Functions over time
+var functions = []string{"avg_over_time", "min_over_time", "max_over_time", "sum_over_time", "count_over_time"}
+
+// Comparison operators
+var comparisons = []string{"==", "!=", ">", "<", ">=", "<="}
+
+// Parse parses an expression:
+//
+//	expr      = operand [ comparison number ]
+//	operand   = aggregate | call | selector | "(" expr ")"
+//	aggregate = op [ "by" labels ] "(" [ number "," ] expr ")" [ "by" labels ]
+//	call      = function "(" selector ")"
+//	selector  = metric [ "{" [ label match string { "," label match string } ] "}" ] [ "[" duration "]" ]
+//	labels    = "(" [ label { "," label } ] ")"
+func Parse(input string) (Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenDuration
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string // unquoted for strings
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// punctuation is ordered so that longer operators are lexed first
+var punctuation = []string{"!=", "=~", "!~", ">=", "<=", "==", "(", ")", "{", "}", "[", "]", ",", "=", ">", "<"}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		// identifiers, numbers and punctuation are ASCII; other characters are
+		// only valid in strings
+		c, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+
+		case c == '_' || isAlpha(c):
+			start := i
+			for i < len(input) && (input[i] == '_' || input[i] == ':' || isAlnum(rune(input[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: input[start:i], pos: start})
+
+		case isDigit(c) || c == '.' || c == '-':
+			start := i
+			i++
+			for i < len(input) && (isAlnum(rune(input[i])) || input[i] == '.') {
+				i++
+			}
+			text := input[start:i]
+			// durations are only valid in ranges, e.g. [5m]
+			if len(tokens) > 0 && tokens[len(tokens)-1].text == "[" {
+				tokens = append(tokens, token{kind: tokenDuration, text: text, pos: start})
+				continue
+			}
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", text, start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, pos: start})
+
+		case c == '"' || c == '\'' || c == '`':
+			start := i
+			i++
+			for i < len(input) && rune(input[i]) != c {
+				if input[i] == '\\' && c != '`' {
+					i++
+				}
+				i++
+			}
+			if i >= len(input) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			text := input[start:i]
+			if c == '\'' {
+				text = `"` + strings.ReplaceAll(strings.ReplaceAll(text[1:len(text)-1], `\'`, `'`), `"`, `\"`) + `"`
+			}
+			value, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s at position %d", input[start:i], start)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, pos: start})
+
+		default:
+			j := slices.IndexFunc(punctuation, func(p string) bool { return strings.HasPrefix(input[i:], p) })
+			if j < 0 {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: punctuation[j], pos: i})
+			i += len(punctuation[j])
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+func isAlpha(c rune) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isAlnum(c rune) bool {
+	return isAlpha(c) || isDigit(c)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), t.pos)
+}
+
+// expect consumes the punctuation text
+func (p *parser) expect(text string) error {
+	if t := p.next(); t.kind != tokenPunct || t.text != text {
+		return p.errorf(t, "expected %q, found %s", text, t)
+	}
+	return nil
+}
+
+func (p *parser) accept(text string) bool {
+	if t := p.peek(); t.kind == tokenPunct && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseExpr() (Expr, error) {
+	e, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokenPunct || !slices.Contains(comparisons, t.text) {
+		return e, nil
+	}
+	p.next()
+	n := p.next()
+	if n.kind != tokenNumber {
+		return nil, p.errorf(n, "expected a number after %q, found %s", t.text, n)
+	}
+	value, _ := strconv.ParseFloat(n.text, 64)
+	return &Compare{Op: t.text, Expr: e, Value: value}, nil
+}
+
+func (p *parser) parseOperand() (Expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenPunct && t.text == "(":
+		p.next()
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(")")
+	case t.kind != tokenIdent:
+		return nil, p.errorf(t, "expected a metric, aggregation or function, found %s", t)
+	case slices.Contains(aggregations, t.text):
+		return p.parseAggregate()
+	case slices.Contains(functions, t.text):
+		return p.parseCall()
+	default:
+		return p.parseSelector()
+	}
+}
+
+func (p *parser) parseAggregate() (Expr, error) {
+	op := p.next().text
+	a := &Aggregate{Op: op}
+	if t := p.peek(); t.kind == tokenIdent && t.text == "by" {
+		p.next()
+		by, err := p.parseLabels()
+		if err != nil {
+			return nil, err
+		}
+		a.By = by
+	}
+
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if op == "topk" || op == "bottomk" {
+		t := p.next()
+		k, err := strconv.Atoi(t.text)
+		if t.kind != tokenNumber || err != nil || k <= 0 {
+			return nil, p.errorf(t, "expected a positive integer as the parameter of %s, found %s", op, t)
+		}
+		a.Param = k
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+	e, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	a.Expr = e
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind == tokenIdent && t.text == "by" {
+		if a.By != nil {
+			return nil, p.errorf(t, "duplicate by clause")
+		}
+		p.next()
+		by, err := p.parseLabels()
+		if err != nil {
+			return nil, err
+		}
+		a.By = by
+	}
+	return a, nil
+}
+
+func (p *parser) parseLabels() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	labels := []string{}
+	for !p.accept(")") {
+		if len(labels) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		t := p.next()
+		if t.kind != tokenIdent {
+			return nil, p.errorf(t, "expected a label, found %s", t)
+		}
+		labels = append(labels, t.text)
+	}
+	return labels, nil
+}
+
+func (p *parser) parseCall() (Expr, error) {
+	fn := p.next().text
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return nil, p.errorf(t, "expected a metric as the argument of %s, found %s", fn, t)
+	}
+	e, err := p.parseSelector()
+	if err != nil {
+		return nil, err
+	}
+	s := e.(*Selector)
+	if s.Range == 0 {
+		return nil, p.errorf(t, "expected a range, e.g. %s[5m], as the argument of %s", s.Metric, fn)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &Call{Func: fn, Selector: s}, nil
+}
+
+func (p *parser) parseSelector() (Expr, error) {
+	s := &Selector{Metric: p.next().text}
+	if p.accept("{") {
+		for !p.accept("}") {
+			if len(s.Matchers) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+				if p.accept("}") {
+					break // trailing comma
+				}
+			}
+			m, err := p.parseMatcher()
+			if err != nil {
+				return nil, err
+			}
+			s.Matchers = append(s.Matchers, m)
+		}
+	}
+	if p.accept("[") {
+		t := p.next()
+		d, err := time.ParseDuration(t.text)
+		if t.kind != tokenDuration || err != nil || d <= 0 {
+			return nil, p.errorf(t, "expected a positive duration, e.g. 5m, found %s", t)
+		}
+		s.Range = d
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (p *parser) parseMatcher() (Matcher, error) {
+	label := p.next()
+	if label.kind != tokenIdent {
+		return Matcher{}, p.errorf(label, "expected a label, found %s", label)
+	}
+	op := p.next()
+	if op.kind != tokenPunct || !slices.Contains([]string{MatchEqual, MatchNotEqual, MatchRegexp, MatchNotRegexp}, op.text) {
+		return Matcher{}, p.errorf(op, "expected one of =, !=, =~, !~, found %s", op)
+	}
+	value := p.next()
+	if value.kind != tokenString {
+		return Matcher{}, p.errorf(value, "expected a quoted string, found %s", value)
+	}
+
+	m := Matcher{Label: label.text, Op: op.text, Value: value.text}
+	if m.Op == MatchRegexp || m.Op == MatchNotRegexp {
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return Matcher{}, p.errorf(value, "invalid regexp %s: %v", value, err)
+		}
+		m.re = re
+	}
+	return m, nil
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("selector", func(t *testing.T) {
+		e, err := Parse(`kepler_pod_cpu_watts{pod_namespace="prod", zone!='dram', pod_name=~"web-.*",}`)
+		require.NoError(t, err)
+		s, ok := e.(*Selector)
+		require.True(t, ok)
+		assert.Equal(t, "kepler_pod_cpu_watts", s.Metric)
+		require.Len(t, s.Matchers, 3)
+		assert.Equal(t, "pod_namespace", s.Matchers[0].Label)
+		assert.Equal(t, MatchEqual, s.Matchers[0].Op)
+		assert.Equal(t, "prod", s.Matchers[0].Value)
+		assert.Equal(t, MatchNotEqual, s.Matchers[1].Op)
+		assert.Equal(t, "dram", s.Matchers[1].Value)
+		assert.True(t, s.Matchers[2].Matches("web-0"))
+		assert.False(t, s.Matchers[2].Matches("api-web-0"), "regexps are anchored")
+	})
+
+	t.Run("aggregation", func(t *testing.T) {
+		for _, input := range []string{
+			`sum by (pod_namespace, zone) (kepler_pod_cpu_watts)`,
+			`sum(kepler_pod_cpu_watts) by (pod_namespace, zone)`,
+		} {
+			e, err := Parse(input)
+			require.NoError(t, err, input)
+			a, ok := e.(*Aggregate)
+			require.True(t, ok)
+			assert.Equal(t, "sum", a.Op)
+			assert.Equal(t, []string{"pod_namespace", "zone"}, a.By)
+			assert.IsType(t, &Selector{}, a.Expr)
+		}
+	})
+
+	t.Run("topk of a comparison", func(t *testing.T) {
+		e, err := Parse(`topk(3, (sum by (pod_name) (kepler_pod_cpu_watts)) > 1.5)`)
+		require.NoError(t, err)
+		a := e.(*Aggregate)
+		assert.Equal(t, "topk", a.Op)
+		assert.Equal(t, 3, a.Param)
+		c, ok := a.Expr.(*Compare)
+		require.True(t, ok)
+		assert.Equal(t, ">", c.Op)
+		assert.Equal(t, 1.5, c.Value)
+		assert.IsType(t, &Aggregate{}, c.Expr)
+	})
+
+	t.Run("function over time", func(t *testing.T) {
+		e, err := Parse(`avg_over_time(kepler_node_cpu_watts{zone="package"}[1h30m])`)
+		require.NoError(t, err)
+		c, ok := e.(*Call)
+		require.True(t, ok)
+		assert.Equal(t, "avg_over_time", c.Func)
+		assert.Equal(t, 90*time.Minute, c.Selector.Range)
+		assert.Len(t, c.Selector.Matchers, 1)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for input, msg := range map[string]string{
+			``:                                                "expected a metric, aggregation or function, found end of expression",
+			`sum(kepler_pod_cpu_watts`:                        `expected ")", found end of expression at position 24`,
+			`kepler_pod_cpu_watts{zone="a"`:                   `expected ",", found end of expression`,
+			`kepler_pod_cpu_watts{zone=a}`:                    `expected a quoted string, found "a"`,
+			`kepler_pod_cpu_watts{zone=~"("}`:                 "invalid regexp",
+			`kepler_pod_cpu_watts{zone="a}`:                   "unterminated string at position 26",
+			`topk(kepler_pod_cpu_watts)`:                      "expected a positive integer as the parameter of topk",
+			`topk(0, kepler_pod_cpu_watts)`:                   "expected a positive integer as the parameter of topk",
+			`avg_over_time(kepler_node_cpu_watts)`:            "expected a range, e.g. kepler_node_cpu_watts[5m]",
+			`kepler_node_cpu_watts[-5m]`:                      "expected a positive duration",
+			`kepler_node_cpu_watts[5x]`:                       "expected a positive duration",
+			`kepler_node_cpu_watts > x`:                       `expected a number after ">", found "x"`,
+			`kepler_node_cpu_watts kepler_pod_cpu_watts`:      `unexpected "kepler_pod_cpu_watts" at position 22`,
+			`kepler_node_cpu_watts + 1`:                       "unexpected character '+' at position 22",
+			`sum by (zone) (kepler_node_cpu_watts) by (zone)`: "duplicate by clause",
+			`é`:                              "unexpected character 'é' at position 0",
+			`kepler_pod_cpu_watts{zoné="a"}`: "unexpected character 'é' at position 24",
+		} {
+			_, err := Parse(input)
+			assert.ErrorContains(t, err, msg, input)
+		}
+	})
+}
+
+// FuzzParse checks that any input is parsed or rejected, without hanging or
+// panicking, since expressions come from unauthenticated requests
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		`kepler_pod_cpu_watts{pod_namespace="prod", zone!='dram', pod_name=~"web-.*",}`,
+		`topk(3, (sum by (pod_name) (kepler_pod_cpu_watts)) > 1.5)`,
+		`avg_over_time(kepler_node_cpu_watts{zone="package"}[1h30m])`,
+		`kepler_pod_cpu_watts{pod_name="café"}`,
+		"é",
+		"\xff",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		e, err := Parse(input)
+		if err == nil && e == nil {
+			t.Fatalf("Parse(%q) returned neither an expression nor an error", input)
+		}
+	})
+}
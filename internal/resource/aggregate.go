@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+const (
+	// KernelAggregate accounts for the CPU time of kernel threads
+	KernelAggregate = "kernel"
+
+	// SystemAggregate accounts for the CPU time not used by any process seen,
+	// e.g. interrupts and processes that started and exited between refreshes
+	SystemAggregate = "system"
+)
+
+// Aggregate is a synthetic resource that accounts for CPU time that isn't used
+// by user workloads so that attribution to workloads and aggregates sums up to
+// the node
+type Aggregate struct {
+	Name string
+
+	// Resource usage tracking
+	CPUTotalTime float64 // total cpu time accounted to the aggregate so far
+	CPUTimeDelta float64 // cpu time accounted to the aggregate since last refresh
+}
+
+// refreshAggregates updates the kernel and system aggregates. The system
+// aggregate gets the active CPU time of the node less the CPU time of all
+// processes; the active CPU time is read from /proc/stat, or is
+// nodeCPUTimeDelta if it is read from the root cgroup.
+func (ri *resourceInformer) refreshAggregates(nodeCPUTimeDelta float64) error {
+	procs, kernel := 0.0, 0.0
+	for _, m := range []map[int]*Process{ri.processes.Running, ri.processes.Terminated} {
+		for _, proc := range m {
+			procs += proc.CPUTimeDelta
+			if proc.KernelThread {
+				kernel += proc.CPUTimeDelta
+			}
+		}
+	}
+
+	active := nodeCPUTimeDelta
+	if ri.cgroupRoot == "" {
+		cpuTime, err := ri.fs.ActiveCPUTime()
+		if err != nil {
+			return err
+		}
+		// the first refresh has nothing to compare against
+		active = 0
+		if ri.activeCPUTime > 0 {
+			active = max(cpuTime-ri.activeCPUTime, 0)
+		}
+		ri.activeCPUTime = cpuTime
+	}
+
+	ri.updateAggregate(KernelAggregate, kernel)
+	ri.updateAggregate(SystemAggregate, max(active-procs, 0))
+	return nil
+}
+
+func (ri *resourceInformer) updateAggregate(name string, delta float64) {
+	agg, exists := ri.node.Aggregates[name]
+	if !exists {
+		agg = &Aggregate{Name: name}
+		ri.node.Aggregates[name] = agg
+	}
+	agg.CPUTimeDelta = delta
+	agg.CPUTotalTime += delta
+}
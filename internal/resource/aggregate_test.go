@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresh_Aggregates(t *testing.T) {
+	newMockProc := func(pid int, comm string, kthread bool, cpuTime float64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return(comm, nil)
+		mockProc.On("Executable").Return("", nil).Maybe()
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/"}}, nil).Maybe()
+		mockProc.On("CmdLine").Return([]string{}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("KernelThread").Return(kthread, nil)
+		mockProc.On("CPUTime").Return(cpuTime, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("ActiveCPUTime").Return(100.0, nil).Once()
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(2, "kthreadd", true, 1.0),
+		newMockProc(100, "bash", false, 2.0),
+	}, nil).Once()
+
+	informer, err := NewInformer(WithProcReader(mockReader), WithAggregates(true))
+	require.NoError(t, err)
+
+	// nothing to compare the active CPU time against on the first refresh
+	require.NoError(t, informer.Refresh())
+	node := informer.Node()
+	require.Len(t, node.Aggregates, 2)
+	assert.Equal(t, 1.0, node.Aggregates[KernelAggregate].CPUTimeDelta)
+	assert.Equal(t, 0.0, node.Aggregates[SystemAggregate].CPUTimeDelta)
+	assert.Equal(t, 3.0, node.ProcessTotalCPUTimeDelta)
+
+	// 10s of active CPU time: 1s kernel thread, 3s user process, 6s unaccounted
+	mockReader.On("ActiveCPUTime").Return(110.0, nil).Once()
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(2, "kthreadd", true, 2.0),
+		newMockProc(100, "bash", false, 5.0),
+	}, nil).Once()
+
+	require.NoError(t, informer.Refresh())
+	node = informer.Node()
+	assert.Equal(t, 1.0, node.Aggregates[KernelAggregate].CPUTimeDelta)
+	assert.Equal(t, 2.0, node.Aggregates[KernelAggregate].CPUTotalTime)
+	assert.Equal(t, 6.0, node.Aggregates[SystemAggregate].CPUTimeDelta)
+	assert.Equal(t, 6.0, node.Aggregates[SystemAggregate].CPUTotalTime)
+	assert.Equal(t, 10.0, node.ProcessTotalCPUTimeDelta)
+	assert.True(t, informer.Processes().Running[2].KernelThread)
+	assert.False(t, informer.Processes().Running[100].KernelThread)
+}
+
+func TestRefresh_AggregatesDisabled(t *testing.T) {
+	mockProc := &MockProcInfo{}
+	mockProc.On("PID").Return(2)
+	mockProc.On("Comm").Return("kthreadd", nil)
+	mockProc.On("Executable").Return("", nil)
+	mockProc.On("Cgroups").Return([]cGroup{{Path: "/"}}, nil)
+	mockProc.On("CmdLine").Return([]string{}, nil).Maybe()
+	mockProc.On("Environ").Return([]string{}, nil).Maybe()
+	mockProc.On("CPUTime").Return(1.0, nil)
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+
+	informer, err := NewInformer(WithProcReader(mockReader))
+	require.NoError(t, err)
+
+	require.NoError(t, informer.Refresh())
+	assert.Empty(t, informer.Node().Aggregates)
+	mockReader.AssertNotCalled(t, "ActiveCPUTime")
+	mockProc.AssertNotCalled(t, "KernelThread")
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: GPL-2.0-only OR BSD-2-Clause
+
+//go:build ignore
+
+#include "common.h"
+
+char __license[] SEC("license") = "Dual BSD/GPL";
+
+// time of the last context switch on each CPU
+struct {
+	__uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+	__type(key, u32);
+	__type(value, u64);
+	__uint(max_entries, 1);
+} last_switch SEC(".maps");
+
+// CPU time of processes in nanoseconds by thread group ID
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__type(key, u32);
+	__type(value, u64);
+	__uint(max_entries, MAX_PROCESSES);
+	__uint(map_flags, BPF_F_NO_PREALLOC);
+} times SEC(".maps");
+
+// sched_switch accounts the time since the last context switch on the CPU to
+// the process switched out, by thread group ID. The sched_switch tracepoint
+// runs in the context of the previous task.
+SEC("raw_tracepoint/sched_switch")
+int sched_switch(struct bpf_raw_tracepoint_args *ctx)
+{
+	u32 zero = 0;
+	u64 now = bpf_ktime_get_ns();
+
+	u64 *last = bpf_map_lookup_elem(&last_switch, &zero);
+	if (!last)
+		return 0;
+	u64 prev = *last;
+	*last = now;
+	// first switch seen on this CPU
+	if (!prev)
+		return 0;
+	u64 delta = now - prev;
+
+	// the idle task isn't accounted
+	u32 tgid = bpf_get_current_pid_tgid() >> 32;
+	if (!tgid)
+		return 0;
+
+	u64 *time = bpf_map_lookup_elem(&times, &tgid);
+	if (time) {
+		__sync_fetch_and_add(time, delta);
+		return 0;
+	}
+	bpf_map_update_elem(&times, &tgid, &delta, BPF_NOEXIST);
+	return 0;
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: GPL-2.0-only OR BSD-2-Clause
+
+// common.h declares the subset of the kernel types and constants used by the
+// eBPF programs, in place of vmlinux.h
+
+#pragma once
+
+typedef unsigned char __u8;
+typedef short unsigned int __u16;
+typedef int __s32;
+typedef unsigned int __u32;
+typedef long long int __s64;
+typedef long long unsigned int __u64;
+typedef __u8 u8;
+typedef __u16 u16;
+typedef __s32 s32;
+typedef __u32 u32;
+typedef __s64 s64;
+typedef __u64 u64;
+typedef __u16 __be16;
+typedef __u32 __be32;
+typedef __u32 __wsum;
+
+#include "bpf_helpers.h"
+
+enum bpf_map_type {
+	BPF_MAP_TYPE_HASH = 1,
+	BPF_MAP_TYPE_PERCPU_ARRAY = 6,
+};
+
+// flags of bpf_map_update_elem
+enum {
+	BPF_ANY = 0,
+	BPF_NOEXIST = 1,
+	BPF_EXIST = 2,
+};
+
+// flags of maps
+enum {
+	BPF_F_NO_PREALLOC = 1,
+};
+
+// arguments of raw tracepoints, i.e. those of the tracepoint's TP_PROTO
+struct bpf_raw_tracepoint_args {
+	__u64 args[0];
+};
+
+// address families of sockets and flags of messages, from linux/socket.h
+#define AF_INET 2
+#define AF_INET6 10
+#define MSG_PEEK 2
+
+// struct sock and struct sock_common, reduced to the fields read by the
+// programs; their offsets are relocated to those of the running kernel (CO-RE)
+struct sock_common {
+	unsigned short skc_family;
+} __attribute__((preserve_access_index));
+
+struct sock {
+	struct sock_common __sk_common;
+} __attribute__((preserve_access_index));
+
+// max number of processes accounted at once; programs stop accounting new
+// processes once it is reached
+#define MAX_PROCESSES (1 << 18)
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: GPL-2.0-only OR BSD-2-Clause
+
+//go:build ignore
+
+#include "common.h"
+#include "bpf_core_read.h"
+
+char __license[] SEC("license") = "Dual BSD/GPL";
+
+// bytes received and sent by a process
+struct net_bytes {
+	u64 rx;
+	u64 tx;
+};
+
+// bytes received and sent by processes by thread group ID
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__type(key, u32);
+	__type(value, struct net_bytes);
+	__uint(max_entries, MAX_PROCESSES);
+	__uint(map_flags, BPF_F_NO_PREALLOC);
+} bytes SEC(".maps");
+
+// count adds the bytes sent or received over sk, if an IPv4 or IPv6 socket,
+// to those of the current process
+static __always_inline void count(struct sock *sk, u64 len, int tx)
+{
+	u16 family = BPF_CORE_READ(sk, __sk_common.skc_family);
+	if (family != AF_INET && family != AF_INET6)
+		return;
+
+	u32 tgid = bpf_get_current_pid_tgid() >> 32;
+	struct net_bytes *b = bpf_map_lookup_elem(&bytes, &tgid);
+	if (b) {
+		__sync_fetch_and_add(tx ? &b->tx : &b->rx, len);
+		return;
+	}
+
+	struct net_bytes init = {};
+	if (tx)
+		init.tx = len;
+	else
+		init.rx = len;
+	bpf_map_update_elem(&bytes, &tgid, &init, BPF_NOEXIST);
+}
+
+// the arguments of sock_recv_length and sock_send_length are the socket, the
+// bytes received or sent (or a negative error) and the message flags
+
+SEC("raw_tracepoint/sock_recv_length")
+int sock_recv_length(struct bpf_raw_tracepoint_args *ctx)
+{
+	int ret = ctx->args[1];
+	int flags = ctx->args[2];
+	// data peeked at is received again
+	if (ret <= 0 || flags & MSG_PEEK)
+		return 0;
+	count((struct sock *)ctx->args[0], ret, 0);
+	return 0;
+}
+
+SEC("raw_tracepoint/sock_send_length")
+int sock_send_length(struct bpf_raw_tracepoint_args *ctx)
+{
+	int ret = ctx->args[1];
+	if (ret <= 0)
+		return 0;
+	count((struct sock *)ctx->args[0], ret, 1);
+	return 0;
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupUsage is the CPU time used by a container cgroup
+type cgroupUsage struct {
+	Runtime ContainerRuntime
+	CPUTime float64 // in seconds
+	Path    string  // of the container cgroup, relative to the root
+}
+
+// isCgroupV2 returns true if root is the mount point of a cgroup v2 hierarchy
+func isCgroupV2(root string) bool {
+	_, err := os.Stat(filepath.Join(root, "cgroup.controllers"))
+	return err == nil
+}
+
+// readContainerCgroups walks the cgroup v2 hierarchy under root and returns the
+// CPU time used by the cgroup of each container, keyed by the container ID.
+// Cgroups nested under a container cgroup are accounted to the container.
+func readContainerCgroups(root string) (map[string]cgroupUsage, error) {
+	containers := make(map[string]cgroupUsage)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			// cgroups can be removed while walking the hierarchy
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel := strings.TrimPrefix(path, root)
+		runtime, id := containerInfoFromCgroupPaths([]string{rel})
+		if id == "" {
+			return nil
+		}
+
+		cpuTime, err := readCgroupCPUTime(path)
+		if err != nil {
+			// the container may have terminated
+			return fs.SkipDir
+		}
+		usage := containers[id]
+		usage.Runtime = runtime
+		usage.CPUTime += cpuTime
+		usage.Path = rel
+		containers[id] = usage
+		return fs.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk cgroups: %w", err)
+	}
+
+	return containers, nil
+}
+
+// readCgroupCPUTime returns the CPU time used by the tasks of a cgroup and its
+// descendants in seconds, as reported by usage_usec in cpu.stat
+func readCgroupCPUTime(dir string) (float64, error) {
+	f, err := os.Open(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || key != "usage_usec" {
+			continue
+		}
+		usec, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid usage_usec in %s: %w", f.Name(), err)
+		}
+		return float64(usec) / 1e6, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, errors.New("usage_usec not found in " + f.Name())
+}
@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/k8s/pod"
+)
+
+const (
+	cgroupTestID1 = "1111111111111111111111111111111111111111111111111111111111111111"
+	cgroupTestID2 = "2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// writeCgroup creates a cgroup directory under root with usage_usec in cpu.stat
+func writeCgroup(t *testing.T, root, path string, usec uint64) {
+	t.Helper()
+	dir := filepath.Join(root, path)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	stat := fmt.Sprintf("usage_usec %d\nuser_usec %d\nsystem_usec 0\n", usec, usec)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.stat"), []byte(stat), 0o644))
+}
+
+// newCgroupRoot returns a cgroup v2 hierarchy with a container of each runtime
+func newCgroupRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu memory"), 0o644))
+	writeCgroup(t, root, "", 100_000_000)
+	writeCgroup(t, root, "system.slice", 20_000_000)
+	writeCgroup(t, root, "system.slice/docker-"+cgroupTestID1+".scope", 2_500_000)
+	writeCgroup(t, root, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1234.slice", 6_000_000)
+	writeCgroup(t, root, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1234.slice/cri-containerd-"+cgroupTestID2+".scope", 5_000_000)
+	// cgroups nested in a container are accounted to the container
+	writeCgroup(t, root, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1234.slice/cri-containerd-"+cgroupTestID2+".scope/nested", 1_000_000)
+	return root
+}
+
+func TestIsCgroupV2(t *testing.T) {
+	assert.True(t, isCgroupV2(newCgroupRoot(t)))
+	assert.False(t, isCgroupV2(t.TempDir()))
+}
+
+func TestReadCgroupCPUTime(t *testing.T) {
+	root := newCgroupRoot(t)
+
+	cpuTime, err := readCgroupCPUTime(root)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, cpuTime)
+
+	_, err = readCgroupCPUTime(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "cpu.stat"), []byte("user_usec 10\n"), 0o644))
+	_, err = readCgroupCPUTime(root)
+	assert.ErrorContains(t, err, "usage_usec not found")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "cpu.stat"), []byte("usage_usec abc\n"), 0o644))
+	_, err = readCgroupCPUTime(root)
+	assert.ErrorContains(t, err, "invalid usage_usec")
+}
+
+func TestReadContainerCgroups(t *testing.T) {
+	root := newCgroupRoot(t)
+
+	containers, err := readContainerCgroups(root)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cgroupUsage{
+		cgroupTestID1: {Runtime: DockerRuntime, CPUTime: 2.5, Path: "/system.slice/docker-" + cgroupTestID1 + ".scope"},
+		cgroupTestID2: {
+			Runtime: ContainerDRuntime, CPUTime: 5,
+			Path: "/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1234.slice/cri-containerd-" + cgroupTestID2 + ".scope",
+		},
+	}, containers)
+
+	_, err = readContainerCgroups(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+}
+
+func TestRefresh_CgroupCPUAccounting(t *testing.T) {
+	root := newCgroupRoot(t)
+
+	mockProcFS := &MockProcReader{}
+	mockProcFS.On("AllProcs").Return([]procInfo{}, nil).Once()
+	mockProcFS.On("CPUUsageRatio").Return(0.5, nil)
+
+	mockPodInformer := new(mockPodInformer)
+	mockPodInformer.On("LookupByContainerID", cgroupTestID1).Return(nil, false, nil)
+	mockPodInformer.On("LookupByContainerID", cgroupTestID2).Return(
+		&pod.ContainerInfo{PodID: "pod-1234", PodName: "web", Namespace: "default", ContainerName: "app"}, true, nil)
+
+	informer, err := NewInformer(
+		WithProcReader(mockProcFS),
+		WithPodInformer(mockPodInformer),
+		WithCgroupCPUAccounting(root),
+		WithProcessTracking(false),
+	)
+	require.NoError(t, err)
+	require.NoError(t, informer.Init())
+
+	require.NoError(t, informer.Refresh())
+	assert.Empty(t, informer.Processes().Running, "processes are not read")
+	assert.Equal(t, 100.0, informer.Node().ProcessTotalCPUTimeDelta)
+
+	containers := informer.Containers().Running
+	require.Len(t, containers, 2)
+	assert.Equal(t, 2.5, containers[cgroupTestID1].CPUTimeDelta)
+	assert.Equal(t, 5.0, containers[cgroupTestID2].CPUTimeDelta)
+	assert.Equal(t, "app", containers[cgroupTestID2].Name)
+	assert.Equal(t, 5.0, informer.Pods().Running["pod-1234"].CPUTimeDelta)
+
+	// docker container terminates and the others use more CPU time
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "system.slice/docker-"+cgroupTestID1+".scope")))
+	writeCgroup(t, root, "", 103_000_000)
+	writeCgroup(t, root, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1234.slice/cri-containerd-"+cgroupTestID2+".scope", 6_500_000)
+
+	require.NoError(t, informer.Refresh())
+	assert.InDelta(t, 3.0, informer.Node().ProcessTotalCPUTimeDelta, 1e-9)
+
+	containers = informer.Containers().Running
+	require.Len(t, containers, 1)
+	assert.InDelta(t, 1.5, containers[cgroupTestID2].CPUTimeDelta, 1e-9)
+	assert.Equal(t, 6.5, containers[cgroupTestID2].CPUTotalTime)
+	assert.Contains(t, informer.Containers().Terminated, cgroupTestID1)
+
+	mockProcFS.AssertExpectations(t)
+}
+
+func TestReadCgroupCPUSet(t *testing.T) {
+	dir := t.TempDir()
+	tt := []struct {
+		cpuset string
+		cpus   []int
+		err    string
+	}{
+		{cpuset: "0-3,8,10-11\n", cpus: []int{0, 1, 2, 3, 8, 10, 11}},
+		{cpuset: "5", cpus: []int{5}},
+		{cpuset: "\n", cpus: nil},
+		{cpuset: "3-1", err: "invalid CPU range"},
+		{cpuset: "a", err: "invalid CPU"},
+	}
+	for _, tc := range tt {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cpuset.cpus.effective"), []byte(tc.cpuset), 0o644))
+		cpus, err := readCgroupCPUSet(dir)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, "cpuset %q", tc.cpuset)
+			continue
+		}
+		require.NoError(t, err, "cpuset %q", tc.cpuset)
+		assert.Equal(t, tc.cpus, cpus, "cpuset %q", tc.cpuset)
+	}
+
+	_, err := readCgroupCPUSet(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestRefresh_ContainerCPUSets(t *testing.T) {
+	root := newCgroupRoot(t)
+	pinned := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1234.slice/cri-containerd-" + cgroupTestID2 + ".scope"
+	require.NoError(t, os.WriteFile(filepath.Join(root, pinned, "cpuset.cpus.effective"), []byte("2-3\n"), 0o644))
+
+	mockProcFS := &MockProcReader{}
+	mockProcFS.On("AllProcs").Return([]procInfo{}, nil)
+	mockProcFS.On("CPUUsageRatio").Return(0.5, nil)
+
+	informer, err := NewInformer(
+		WithProcReader(mockProcFS),
+		WithCgroupCPUAccounting(root),
+		WithContainerCPUSets(root),
+		WithProcessTracking(false),
+	)
+	require.NoError(t, err)
+	require.NoError(t, informer.Init())
+
+	require.NoError(t, informer.Refresh())
+	containers := informer.Containers().Running
+	require.Len(t, containers, 2)
+	assert.Equal(t, []int{2, 3}, containers[cgroupTestID2].CPUs)
+	assert.Nil(t, containers[cgroupTestID1].CPUs, "the cpuset of the container can't be read")
+
+	// the cpuset is updated while the container runs
+	require.NoError(t, os.WriteFile(filepath.Join(root, pinned, "cpuset.cpus.effective"), []byte("6\n"), 0o644))
+	require.NoError(t, informer.Refresh())
+	assert.Equal(t, []int{6}, informer.Containers().Running[cgroupTestID2].CPUs)
+}
+
+func TestInit_CgroupV2NotMounted(t *testing.T) {
+	mockProcFS := &MockProcReader{}
+	mockProcFS.On("AllProcs").Return([]procInfo{}, nil)
+
+	informer, err := NewInformer(
+		WithProcReader(mockProcFS),
+		WithCgroupCPUAccounting(t.TempDir()),
+		WithProcessTracking(false),
+	)
+	require.NoError(t, err)
+	require.NoError(t, informer.Init())
+
+	assert.Empty(t, informer.cgroupRoot, "falls back to reading processes")
+	assert.True(t, informer.trackProcesses)
+
+	informer, err = NewInformer(
+		WithProcReader(mockProcFS),
+		WithContainerCPUSets(t.TempDir()),
+	)
+	require.NoError(t, err)
+	require.NoError(t, informer.Init())
+	assert.Empty(t, informer.cpusetRoot, "cpusets are not read")
+}
+
+func TestNewInformer_ProcessTracking(t *testing.T) {
+	informer, err := NewInformer(WithProcReader(&MockProcReader{}), WithProcessTracking(false))
+	require.NoError(t, err)
+	assert.True(t, informer.trackProcesses, "processes can only be skipped with cgroup CPU accounting")
+
+	informer, err = NewInformer(WithProcReader(&MockProcReader{}), WithCgroupCPUAccounting("/sys/fs/cgroup"))
+	require.NoError(t, err)
+	assert.True(t, informer.trackProcesses, "processes are tracked by default")
+	assert.True(t, strings.HasSuffix(informer.cgroupRoot, "cgroup"))
+}
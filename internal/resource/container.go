@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 )
 
 var (
@@ -22,6 +23,10 @@ var (
 	libpodPayloadPattern = regexp.MustCompile(`/libpod-payload-([0-9a-f]+)`)
 
 	kubepodsPattern = regexp.MustCompile(`/kubepods/[^/]+/pod[0-9a-f\-]+/([0-9a-f]{64})`)
+
+	// on windows, processes are in the job object of their container, named
+	// after it by containerd
+	jobPattern = regexp.MustCompile(`^/job/([0-9a-f]{64})$`)
 )
 
 // containerPatterns maps pre-compiled patterns to runtime types
@@ -36,6 +41,55 @@ var containerPatterns = map[*regexp.Regexp]ContainerRuntime{
 	libpodPayloadPattern: PodmanRuntime,
 
 	kubepodsPattern: KubePodsRuntime,
+
+	jobPattern: ContainerDRuntime,
+}
+
+// runtimeRequestTimeout bounds each call to a container runtime so that a hung
+// runtime can't stall a refresh
+const runtimeRequestTimeout = 2 * time.Second
+
+// runtimeContainer is a container as known to the container runtime or engine
+type runtimeContainer struct {
+	ID           string
+	PodSandboxID string
+	Name         string
+	Image        string
+	Runtime      ContainerRuntime
+	Labels       map[string]string
+}
+
+// pod returns the pod of the container from the labels set by kubelet; nil
+// if the container isn't run by kubelet
+func (c *runtimeContainer) pod() *Pod {
+	uid := c.Labels[criPodUIDLabel]
+	if uid == "" {
+		return nil
+	}
+	return &Pod{
+		ID:        uid,
+		Name:      c.Labels[criPodNameLabel],
+		Namespace: c.Labels[criPodNamespaceLabel],
+	}
+}
+
+// composeProject returns the compose project of the container from the labels
+// set by docker compose and podman-compose; empty if not run by compose
+func (c *runtimeContainer) composeProject() string {
+	for _, label := range []string{"com.docker.compose.project", "io.podman.compose.project"} {
+		if project := c.Labels[label]; project != "" {
+			return project
+		}
+	}
+	return ""
+}
+
+// containerRuntime looks up containers in a container runtime or engine
+type containerRuntime interface {
+	// Container returns the container with the given ID; nil if the runtime
+	// doesn't know the container
+	Container(id string) (*runtimeContainer, error)
+	Close() error
 }
 
 // containerInfoFromProc detects if a process is running in a container and extracts container info
@@ -64,6 +118,12 @@ func containerInfoFromProc(proc procInfo) (*Container, error) {
 		ID:      ctnrID,
 		Runtime: runtime,
 	}
+	for _, path := range paths {
+		if _, id := containerInfoFromCgroupPaths([]string{path}); id == ctnrID {
+			c.cgroupPath = path
+			break
+		}
+	}
 
 	if env, err := proc.Environ(); err == nil {
 		c.Name = containerNameFromEnv(env)
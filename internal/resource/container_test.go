@@ -133,6 +133,21 @@ func TestContainerIDFromPathWithCgroup(t *testing.T) {
 		path: "0::/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-3f05ee050f82c0145f1d88c94269c39dff0f07dbf8bba20aafd54b3a75dcaecc.scope/container",
 
 		expected: expect{id: "3f05ee050f82c0145f1d88c94269c39dff0f07dbf8bba20aafd54b3a75dcaecc", runtime: PodmanRuntime},
+	}, {
+		name: "docker rootless container",
+		path: "0::/user.slice/user-1000.slice/user@1000.service/user.slice/docker-2fa3e04b676df750842faf017052dd37ea0cc5bc7259a3487a1718c7fe100c94.scope",
+
+		expected: expect{id: "2fa3e04b676df750842faf017052dd37ea0cc5bc7259a3487a1718c7fe100c94", runtime: DockerRuntime},
+	}, {
+		name: "docker rootless container with cgroupfs driver",
+		path: "0::/user.slice/user-1000.slice/user@1000.service/docker.service/docker/2fa3e04b676df750842faf017052dd37ea0cc5bc7259a3487a1718c7fe100c94",
+
+		expected: expect{id: "2fa3e04b676df750842faf017052dd37ea0cc5bc7259a3487a1718c7fe100c94", runtime: DockerRuntime},
+	}, {
+		name: "podman conmon",
+		path: "0::/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-conmon-3f05ee050f82c0145f1d88c94269c39dff0f07dbf8bba20aafd54b3a75dcaecc.scope",
+
+		expected: expect{id: "", runtime: UnknownRuntime},
 	}, {
 		name: "podman rootful container",
 		path: "0::/machine.slice/libpod-06dc5f321aad8726aa26559f16ec203bc099245bc44894b14a89fc02b022d1d5.scope/container",
@@ -152,6 +167,16 @@ func TestContainerIDFromPathWithCgroup(t *testing.T) {
 		path: "0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-burstable.slice/kubelet-kubepods-burstable-pod3cae2e45_052c_4b11_80d3_4d7b2d2d3464.slice/cri-containerd-2b180104511194aab36fd295d3e217439f3ddb5bc88277f37b4952abee85c40e.scope",
 
 		expected: expect{id: "2b180104511194aab36fd295d3e217439f3ddb5bc88277f37b4952abee85c40e", runtime: ContainerDRuntime},
+	}, {
+		name: "windows job object",
+		path: "/job/7d9c1a5e0f3b4c2a8e6d1f0b9a7c5e3d2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e",
+
+		expected: expect{id: "7d9c1a5e0f3b4c2a8e6d1f0b9a7c5e3d2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e", runtime: ContainerDRuntime},
+	}, {
+		name: "windows job object not of a container",
+		path: "/job/PcaJobObject",
+
+		expected: expect{id: "", runtime: UnknownRuntime},
 	}}
 
 	for _, test := range tests {
@@ -332,6 +357,7 @@ func TestContainerInfoFromProc(t *testing.T) {
 			require.NotNil(t, container, "Expected container to be detected")
 			assert.Equal(t, tc.expectedID, container.ID)
 			assert.Equal(t, tc.expectedRuntime, container.Runtime)
+			assert.Equal(t, tc.cgroupsPath, container.cgroupPath)
 			if tc.expectedName != "" {
 				assert.Equal(t, tc.expectedName, container.Name)
 			}
@@ -345,6 +371,8 @@ func TestContainerClone(t *testing.T) {
 			ID:           "1234567890ab",
 			Name:         "test-container",
 			Runtime:      DockerRuntime,
+			Image:        "nginx:1.27",
+			Labels:       map[string]string{"app": "web"},
 			CPUTimeDelta: 123.45,
 		}
 
@@ -354,6 +382,8 @@ func TestContainerClone(t *testing.T) {
 		assert.Equal(t, original.ID, clone.ID)
 		assert.Equal(t, original.Name, clone.Name)
 		assert.Equal(t, original.Runtime, clone.Runtime)
+		assert.Equal(t, original.Image, clone.Image)
+		assert.Equal(t, original.Labels, clone.Labels)
 		assert.Equal(t, float64(0), clone.CPUTimeDelta) // CPUTime shouldn't be cloned
 	})
 
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+// coreTypeUsageRatios returns the CPU usage ratio of each core type, the mean
+// of the usage ratios of its CPUs; nil if the usage of CPUs can't be read
+func (ri *resourceInformer) coreTypeUsageRatios() (map[string]float64, error) {
+	reader, ok := ri.fs.(perCPUUsageReader)
+	if !ok {
+		return nil, nil
+	}
+	cpuRatios, err := reader.CPUUsageRatios()
+	if err != nil || cpuRatios == nil {
+		return nil, err
+	}
+
+	sums, counts := map[string]float64{}, map[string]int{}
+	for cpu, ratio := range cpuRatios {
+		if coreType, ok := ri.coreTypes[cpu]; ok {
+			sums[coreType] += ratio
+			counts[coreType]++
+		}
+	}
+
+	ratios := make(map[string]float64, len(sums))
+	for coreType, sum := range sums {
+		ratios[coreType] = sum / float64(counts[coreType])
+	}
+	return ratios, nil
+}
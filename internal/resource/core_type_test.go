@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// perCPUMockReader is a MockProcReader that reads the usage of each CPU
+type perCPUMockReader struct {
+	*MockProcReader
+	ratios map[int]float64
+}
+
+func (r *perCPUMockReader) CPUUsageRatios() (map[int]float64, error) {
+	return r.ratios, nil
+}
+
+func TestCoreTypeUsage(t *testing.T) {
+	newReader := func(ratios map[int]float64) allProcReader {
+		mockReader := &MockProcReader{}
+		mockReader.On("AllProcs").Return([]procInfo{}, nil)
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+		return &perCPUMockReader{MockProcReader: mockReader, ratios: ratios}
+	}
+	coreTypes := map[int]string{0: "performance", 1: "performance", 2: "efficiency", 3: "efficiency"}
+
+	t.Run("mean of cpus", func(t *testing.T) {
+		reader := newReader(map[int]float64{0: 0.9, 1: 0.5, 2: 0.2, 3: 0.1, 4: 1})
+		informer, err := NewInformer(WithProcReader(reader), WithCPUCoreTypes(coreTypes))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+
+		ratios := informer.Node().CoreTypeUsageRatios
+		assert.Len(t, ratios, 2, "cpus of unknown core types are ignored")
+		assert.InDelta(t, 0.7, ratios["performance"], 1e-9)
+		assert.InDelta(t, 0.15, ratios["efficiency"], 1e-9)
+	})
+
+	t.Run("without core types", func(t *testing.T) {
+		informer, err := NewInformer(WithProcReader(newReader(map[int]float64{0: 0.9})))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+		assert.Nil(t, informer.Node().CoreTypeUsageRatios)
+	})
+}
+
+func TestProcFSReaderCPUUsageRatios(t *testing.T) {
+	reader, err := NewProcFSReader("./testdata/procfs")
+	require.NoError(t, err)
+
+	ratios, err := reader.CPUUsageRatios()
+	require.NoError(t, err)
+	assert.Nil(t, ratios, "first call has no usage")
+	require.Len(t, reader.prevCPUStats, 2)
+
+	read := reader.prevCPUStats[1]
+	reader.prevCPUStats[1] = procfs.CPUStat{
+		User:   read.User - 300,
+		System: read.System - 100,
+		Idle:   read.Idle - 600,
+		Nice:   read.Nice, Iowait: read.Iowait, IRQ: read.IRQ, SoftIRQ: read.SoftIRQ, Steal: read.Steal,
+	}
+	ratios, err = reader.CPUUsageRatios()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]float64{0: 0, 1: 0.4}, ratios)
+}
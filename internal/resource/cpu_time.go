@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"errors"
+	"sync"
+)
+
+// bpfCPUTimes reads the CPU time of processes in nanoseconds, accounted by an
+// eBPF program on every context switch, keyed by PID (thread group ID)
+type bpfCPUTimes interface {
+	// Load loads and attaches the eBPF program
+	Load() error
+
+	// Lookup returns the CPU time accounted to the process since the program
+	// was attached; 0 if the process didn't run since
+	Lookup(pid int) (uint64, error)
+
+	// PIDs returns the processes with accounted CPU time
+	PIDs() ([]int, error)
+
+	// Delete removes the CPU time of a process
+	Delete(pid int) error
+
+	// Close detaches the eBPF program
+	Close() error
+}
+
+// bpfCPUTime provides the CPU time of processes from eBPF. The eBPF program
+// only sees the CPU time used after it is attached, so the CPU time of a
+// process is its CPU time in procfs when first seen plus the CPU time
+// accounted by eBPF since. CPU time deltas are then as precise as the
+// scheduler clock rather than a multiple of the scheduler tick.
+type bpfCPUTime struct {
+	times bpfCPUTimes
+
+	// processes are read in parallel
+	mu sync.Mutex
+	// offsets holds the procfs CPU time less the eBPF CPU time in seconds of
+	// processes when first seen, keyed by PID
+	offsets map[int]float64
+}
+
+func newBPFCPUTime(times bpfCPUTimes) *bpfCPUTime {
+	return &bpfCPUTime{times: times, offsets: make(map[int]float64)}
+}
+
+// cpuTime returns the CPU time of proc in seconds
+func (b *bpfCPUTime) cpuTime(proc procInfo) (float64, error) {
+	pid := proc.PID()
+	ns, err := b.times.Lookup(pid)
+	if err != nil {
+		return 0, err
+	}
+	bpfTime := float64(ns) / 1e9
+
+	b.mu.Lock()
+	offset, seen := b.offsets[pid]
+	b.mu.Unlock()
+	if seen {
+		return offset + bpfTime, nil
+	}
+
+	procTime, err := proc.CPUTime()
+	if err != nil {
+		return 0, err
+	}
+
+	b.mu.Lock()
+	b.offsets[pid] = procTime - bpfTime
+	b.mu.Unlock()
+	return procTime, nil
+}
+
+// retain releases the CPU time of the processes that aren't running so that
+// their PID can be reused. Processes that start and exit between two refreshes
+// are never seen, so the processes accounted by eBPF are listed rather than
+// only the terminated ones. Processes that started since running was read are
+// released too, which is harmless as they are yet to be seen.
+func (b *bpfCPUTime) retain(running map[int]*Process) error {
+	b.mu.Lock()
+	for pid := range b.offsets {
+		if _, ok := running[pid]; !ok {
+			delete(b.offsets, pid)
+		}
+	}
+	b.mu.Unlock()
+
+	return retainPIDs(b.times, running)
+}
+
+// bpfPIDMap is an eBPF map keyed by PID
+type bpfPIDMap interface {
+	PIDs() ([]int, error)
+	Delete(pid int) error
+}
+
+// retainPIDs removes the processes that aren't running from the map
+func retainPIDs(m bpfPIDMap, running map[int]*Process) error {
+	pids, err := m.PIDs()
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, pid := range pids {
+		if _, ok := running[pid]; !ok {
+			errs = errors.Join(errs, m.Delete(pid))
+		}
+	}
+	return errs
+}
+
+// bpfProcInfo reads the CPU time of a process from eBPF and everything else
+// from procfs
+type bpfProcInfo struct {
+	procInfo
+	bpf *bpfCPUTime
+}
+
+func (p *bpfProcInfo) CPUTime() (float64, error) {
+	return p.bpf.cpuTime(p.procInfo)
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && (amd64 || arm64)
+
+package resource
+
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -tags linux -target bpfel -output-stem cpu_time cpuTime bpf/cpu_time.c -- -I./bpf/include
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/rlimit"
+)
+
+// bpfSchedSwitch accounts the CPU time of processes with the eBPF program of
+// bpf/cpu_time.c attached to the sched_switch tracepoint, which requires
+// CAP_BPF and CAP_PERFMON (or CAP_SYS_ADMIN)
+type bpfSchedSwitch struct {
+	objs cpuTimeObjects
+	link link.Link
+}
+
+var _ bpfCPUTimes = (*bpfSchedSwitch)(nil)
+
+func newBPFCPUTimes() bpfCPUTimes {
+	return &bpfSchedSwitch{}
+}
+
+func (b *bpfSchedSwitch) Load() error {
+	// maps and programs are charged to RLIMIT_MEMLOCK on kernels before 5.11;
+	// this is a no-op on later kernels
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return fmt.Errorf("failed to remove the memlock limit of eBPF maps: %w", err)
+	}
+
+	if err := loadCpuTimeObjects(&b.objs, nil); err != nil {
+		return fmt.Errorf("failed to load eBPF program: %w", err)
+	}
+
+	var err error
+	b.link, err = link.AttachRawTracepoint(link.RawTracepointOptions{
+		Name:    "sched_switch",
+		Program: b.objs.SchedSwitch,
+	})
+	if err != nil {
+		_ = b.Close()
+		return fmt.Errorf("failed to attach eBPF program to sched_switch: %w", err)
+	}
+	return nil
+}
+
+func (b *bpfSchedSwitch) Lookup(pid int) (uint64, error) {
+	var value uint64
+	err := b.objs.Times.Lookup(uint32(pid), &value)
+	if errors.Is(err, ebpf.ErrKeyNotExist) {
+		return 0, nil
+	}
+	return value, err
+}
+
+func (b *bpfSchedSwitch) PIDs() ([]int, error) {
+	return bpfMapPIDs(b.objs.Times)
+}
+
+func (b *bpfSchedSwitch) Delete(pid int) error {
+	return bpfMapDelete(b.objs.Times, pid)
+}
+
+func (b *bpfSchedSwitch) Close() error {
+	var errs error
+	// closing the link detaches the program
+	if b.link != nil {
+		errs = b.link.Close()
+		b.link = nil
+	}
+	errs = errors.Join(errs, b.objs.Close())
+	b.objs = cpuTimeObjects{}
+	return errs
+}
+
+// bpfMapPIDs returns the keys of a map keyed by PID
+func bpfMapPIDs(m *ebpf.Map) ([]int, error) {
+	var pids []int
+	var key any // nil for the first key
+	var next uint32
+	for {
+		err := m.NextKey(key, &next)
+		if errors.Is(err, ebpf.ErrKeyNotExist) {
+			return pids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		pids = append(pids, int(next))
+		key = next
+	}
+}
+
+// bpfMapDelete removes a process from a map keyed by PID; processes not in the
+// map are ignored
+func bpfMapDelete(m *ebpf.Map, pid int) error {
+	err := m.Delete(uint32(pid))
+	if errors.Is(err, ebpf.ErrKeyNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && (amd64 || arm64)
+
+package resource
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBPFSchedSwitch(t *testing.T) {
+	times := newBPFCPUTimes()
+	if err := times.Load(); err != nil {
+		t.Skipf("eBPF program can't be loaded: %v", err)
+	}
+	t.Cleanup(func() { _ = times.Close() })
+
+	// use about 50ms of CPU time, switching out to let the program account it
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		runtime.Gosched()
+		time.Sleep(time.Microsecond)
+	}
+
+	pid := os.Getpid()
+	ns, err := times.Lookup(pid)
+	require.NoError(t, err)
+	assert.Greater(t, ns, uint64(0), "CPU time of the test process")
+
+	pids, err := times.PIDs()
+	require.NoError(t, err)
+	assert.Contains(t, pids, pid)
+
+	require.NoError(t, times.Delete(pid))
+	require.NoError(t, times.Delete(pid), "deleting an unknown process")
+
+	ns, err = times.Lookup(0)
+	require.NoError(t, err)
+	assert.Zero(t, ns, "the idle task isn't accounted")
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux || !(amd64 || arm64)
+
+package resource
+
+import "errors"
+
+// bpfSchedSwitch is only supported on linux on amd64 and arm64
+type bpfSchedSwitch struct{}
+
+var _ bpfCPUTimes = (*bpfSchedSwitch)(nil)
+
+func newBPFCPUTimes() bpfCPUTimes {
+	return &bpfSchedSwitch{}
+}
+
+func (b *bpfSchedSwitch) Load() error {
+	return errors.New("eBPF CPU time accounting is only supported on linux on amd64 and arm64")
+}
+
+func (b *bpfSchedSwitch) Lookup(int) (uint64, error) {
+	return 0, nil
+}
+
+func (b *bpfSchedSwitch) PIDs() ([]int, error) {
+	return nil, nil
+}
+
+func (b *bpfSchedSwitch) Delete(int) error {
+	return nil
+}
+
+func (b *bpfSchedSwitch) Close() error {
+	return nil
+}
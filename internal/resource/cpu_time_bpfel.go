@@ -0,0 +1,122 @@
+// Code generated by bpf2go; DO NOT EDIT.
+//go:build (386 || amd64 || arm || arm64 || loong64 || mips64le || mipsle || ppc64le || riscv64) && linux
+
+package resource
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/cilium/ebpf"
+)
+
+// loadCpuTime returns the embedded CollectionSpec for cpuTime.
+func loadCpuTime() (*ebpf.CollectionSpec, error) {
+	reader := bytes.NewReader(_CpuTimeBytes)
+	spec, err := ebpf.LoadCollectionSpecFromReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("can't load cpuTime: %w", err)
+	}
+
+	return spec, err
+}
+
+// loadCpuTimeObjects loads cpuTime and converts it into a struct.
+//
+// The following types are suitable as obj argument:
+//
+//	*cpuTimeObjects
+//	*cpuTimePrograms
+//	*cpuTimeMaps
+//
+// See ebpf.CollectionSpec.LoadAndAssign documentation for details.
+func loadCpuTimeObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
+	spec, err := loadCpuTime()
+	if err != nil {
+		return err
+	}
+
+	return spec.LoadAndAssign(obj, opts)
+}
+
+// cpuTimeSpecs contains maps and programs before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type cpuTimeSpecs struct {
+	cpuTimeProgramSpecs
+	cpuTimeMapSpecs
+}
+
+// cpuTimeSpecs contains programs before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type cpuTimeProgramSpecs struct {
+	SchedSwitch *ebpf.ProgramSpec `ebpf:"sched_switch"`
+}
+
+// cpuTimeMapSpecs contains maps before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type cpuTimeMapSpecs struct {
+	LastSwitch *ebpf.MapSpec `ebpf:"last_switch"`
+	Times      *ebpf.MapSpec `ebpf:"times"`
+}
+
+// cpuTimeObjects contains all objects after they have been loaded into the kernel.
+//
+// It can be passed to loadCpuTimeObjects or ebpf.CollectionSpec.LoadAndAssign.
+type cpuTimeObjects struct {
+	cpuTimePrograms
+	cpuTimeMaps
+}
+
+func (o *cpuTimeObjects) Close() error {
+	return _CpuTimeClose(
+		&o.cpuTimePrograms,
+		&o.cpuTimeMaps,
+	)
+}
+
+// cpuTimeMaps contains all maps after they have been loaded into the kernel.
+//
+// It can be passed to loadCpuTimeObjects or ebpf.CollectionSpec.LoadAndAssign.
+type cpuTimeMaps struct {
+	LastSwitch *ebpf.Map `ebpf:"last_switch"`
+	Times      *ebpf.Map `ebpf:"times"`
+}
+
+func (m *cpuTimeMaps) Close() error {
+	return _CpuTimeClose(
+		m.LastSwitch,
+		m.Times,
+	)
+}
+
+// cpuTimePrograms contains all programs after they have been loaded into the kernel.
+//
+// It can be passed to loadCpuTimeObjects or ebpf.CollectionSpec.LoadAndAssign.
+type cpuTimePrograms struct {
+	SchedSwitch *ebpf.Program `ebpf:"sched_switch"`
+}
+
+func (p *cpuTimePrograms) Close() error {
+	return _CpuTimeClose(
+		p.SchedSwitch,
+	)
+}
+
+func _CpuTimeClose(closers ...io.Closer) error {
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Do not access this directly.
+//
+//go:embed cpu_time_bpfel.o
+var _CpuTimeBytes []byte
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBPFCPUTimes holds the CPU time of processes in nanoseconds
+type fakeBPFCPUTimes struct {
+	times map[int]uint64
+}
+
+func (f *fakeBPFCPUTimes) Load() error { return nil }
+
+func (f *fakeBPFCPUTimes) Lookup(pid int) (uint64, error) {
+	return f.times[pid], nil
+}
+
+func (f *fakeBPFCPUTimes) PIDs() ([]int, error) {
+	pids := make([]int, 0, len(f.times))
+	for pid := range f.times {
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+func (f *fakeBPFCPUTimes) Delete(pid int) error {
+	delete(f.times, pid)
+	return nil
+}
+
+func (f *fakeBPFCPUTimes) Close() error { return nil }
+
+func TestBPFCPUTime(t *testing.T) {
+	times := &fakeBPFCPUTimes{times: map[int]uint64{1: 500_000_000}}
+	bpf := newBPFCPUTime(times)
+
+	proc := &MockProcInfo{}
+	proc.On("PID").Return(1)
+	proc.On("CPUTime").Return(10.0, nil).Once()
+
+	cpuTime, err := bpf.cpuTime(proc)
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, cpuTime, "CPU time in procfs when first seen")
+
+	times.times[1] = 750_000_000
+	cpuTime, err = bpf.cpuTime(proc)
+	require.NoError(t, err)
+	assert.InDelta(t, 10.25, cpuTime, 1e-9, "CPU time accounted by eBPF since")
+	proc.AssertNumberOfCalls(t, "CPUTime", 1)
+
+	t.Run("retain", func(t *testing.T) {
+		times.times[2] = 1 // started and exited between two refreshes
+
+		require.NoError(t, bpf.retain(map[int]*Process{1: {PID: 1}}))
+		assert.Equal(t, map[int]uint64{1: 750_000_000}, times.times)
+
+		require.NoError(t, bpf.retain(map[int]*Process{}))
+		assert.Empty(t, times.times)
+		assert.Empty(t, bpf.offsets, "PID can be reused")
+	})
+}
+
+func TestRefresh_BPFCPUTime(t *testing.T) {
+	newMockProc := func(pid int) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return("worker", nil)
+		mockProc.On("Executable").Return("/bin/worker", nil)
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/system.slice/worker.service"}}, nil)
+		mockProc.On("CmdLine").Return([]string{"/bin/worker"}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(2.0, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1)}, nil).Once()
+
+	informer, err := NewInformer(WithProcReader(mockReader), WithEBPFCPUTime(true))
+	require.NoError(t, err)
+	times := &fakeBPFCPUTimes{times: map[int]uint64{1: 1_000_000_000}}
+	informer.bpf = newBPFCPUTime(times)
+
+	require.NoError(t, informer.Refresh())
+	proc := informer.Processes().Running[1]
+	require.NotNil(t, proc)
+	assert.Equal(t, 2.0, proc.CPUTotalTime)
+
+	// procfs reports no progress within a scheduler tick
+	times.times[1] = 1_004_000_000
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1)}, nil).Once()
+	require.NoError(t, informer.Refresh())
+	proc = informer.Processes().Running[1]
+	require.NotNil(t, proc)
+	assert.InDelta(t, 0.004, proc.CPUTimeDelta, 1e-9)
+
+	// the CPU time of exited processes is released
+	mockReader.On("AllProcs").Return([]procInfo{}, nil).Once()
+	require.NoError(t, informer.Refresh())
+	assert.Empty(t, times.times)
+}
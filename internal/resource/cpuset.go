@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// refreshContainerCPUSets reads the CPUs each running container may run on from
+// its cpuset. Cpusets can change while containers run, e.g. when the CPU manager
+// of the kubelet takes CPUs out of the shared pool, so they are read on every
+// refresh. The CPUs of containers whose cpuset can't be read are unknown.
+func (ri *resourceInformer) refreshContainerCPUSets() {
+	for _, c := range ri.containers.Running {
+		if c.cgroupPath == "" {
+			continue
+		}
+		cpus, err := readCgroupCPUSet(filepath.Join(ri.cpusetRoot, c.cgroupPath))
+		if err != nil {
+			ri.logger.Debug("Failed to read cpuset of container", "container", c.ID, "error", err)
+			c.CPUs = nil
+			continue
+		}
+		c.CPUs = cpus
+	}
+}
+
+// readCgroupCPUSet returns the CPUs the tasks of a cgroup may run on, as
+// reported by cpuset.cpus.effective
+func readCgroupCPUSet(dir string) ([]int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "cpuset.cpus.effective"))
+	if err != nil {
+		return nil, err
+	}
+	cpus, err := parseCPUSet(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cpuset in %s: %w", dir, err)
+	}
+	return cpus, nil
+}
+
+// parseCPUSet parses a list of CPUs in the cpuset format, e.g. 0-7,16,18-19
+func parseCPUSet(list string) ([]int, error) {
+	var cpus []int
+	if list == "" {
+		return cpus, nil
+	}
+	for _, r := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(r, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU %q", first)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil || end < start {
+				return nil, fmt.Errorf("invalid CPU range %q", r)
+			}
+		}
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
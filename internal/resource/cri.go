@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// labels kubelet sets on the containers it creates
+const (
+	criPodNameLabel      = "io.kubernetes.pod.name"
+	criPodNamespaceLabel = "io.kubernetes.pod.namespace"
+	criPodUIDLabel       = "io.kubernetes.pod.uid"
+)
+
+// criClient is a client of the CRI runtime service (v1) served by containerd
+// and CRI-O on a unix socket
+type criClient struct {
+	endpoint string
+	conn     *grpc.ClientConn
+	client   runtimeapi.RuntimeServiceClient
+	runtime  ContainerRuntime
+}
+
+var _ containerRuntime = (*criClient)(nil)
+
+// newCRIClient returns a client of the CRI socket at endpoint, which can be a
+// path or a unix:// URL; it connects on Start
+func newCRIClient(endpoint string) *criClient {
+	return &criClient{
+		endpoint: endpoint,
+		runtime:  UnknownRuntime,
+	}
+}
+
+// Start connects to the runtime and reads its name
+func (c *criClient) Start() error {
+	target := c.endpoint
+	if !strings.HasPrefix(target, "unix://") {
+		target = "unix://" + target
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to container runtime at %s: %w", c.endpoint, err)
+	}
+	c.conn = conn
+	c.client = runtimeapi.NewRuntimeServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeRequestTimeout)
+	defer cancel()
+	resp, err := c.client.Version(ctx, &runtimeapi.VersionRequest{Version: "v1"})
+	if err != nil {
+		return fmt.Errorf("failed to get version of container runtime at %s: %w", c.endpoint, err)
+	}
+
+	c.runtime = criRuntime(resp.GetRuntimeName())
+	return nil
+}
+
+// criRuntime returns the runtime of the given CRI runtime name
+func criRuntime(name string) ContainerRuntime {
+	switch name {
+	case "containerd":
+		return ContainerDRuntime
+	case "cri-o":
+		return CrioRuntime
+	default:
+		return UnknownRuntime
+	}
+}
+
+func (c *criClient) Container(id string) (*runtimeContainer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeRequestTimeout)
+	defer cancel()
+	resp, err := c.client.ListContainers(ctx, &runtimeapi.ListContainersRequest{
+		Filter: &runtimeapi.ContainerFilter{Id: id},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list container %s: %w", id, err)
+	}
+
+	// runtimes match the filter as an ID prefix
+	for _, ctr := range resp.GetContainers() {
+		if ctr.GetId() == id {
+			ret := newRuntimeContainer(ctr)
+			ret.Runtime = c.runtime
+			return ret, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *criClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// newRuntimeContainer returns the fields of a CRI container used by kepler
+func newRuntimeContainer(ctr *runtimeapi.Container) *runtimeContainer {
+	ret := &runtimeContainer{
+		ID:           ctr.GetId(),
+		PodSandboxID: ctr.GetPodSandboxId(),
+		Name:         ctr.GetMetadata().GetName(),
+		Labels:       make(map[string]string, len(ctr.GetLabels())),
+	}
+	maps.Copy(ret.Labels, ctr.GetLabels())
+
+	// kubelet passes the resolved image ID to the runtime and the image as
+	// written in the pod spec separately
+	ret.Image = cmp.Or(ctr.GetImage().GetUserSpecifiedImage(), ctr.GetImage().GetImage())
+	return ret
+}
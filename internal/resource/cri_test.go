@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	criTestID1 = "1111111111111111111111111111111111111111111111111111111111111111"
+	criTestID2 = "2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// fakeCRIServer serves the Version and ListContainers calls of the CRI
+// runtime service
+type fakeCRIServer struct {
+	runtimeapi.UnimplementedRuntimeServiceServer
+	containers []*runtimeapi.Container
+	err        error // of ListContainers
+}
+
+func (f *fakeCRIServer) Version(context.Context, *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	return &runtimeapi.VersionResponse{Version: "0.1.0", RuntimeName: "containerd", RuntimeApiVersion: "v1"}, nil
+}
+
+func (f *fakeCRIServer) ListContainers(_ context.Context, req *runtimeapi.ListContainersRequest) (*runtimeapi.ListContainersResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &runtimeapi.ListContainersResponse{}
+	for _, ctr := range f.containers {
+		if strings.HasPrefix(ctr.Id, req.GetFilter().GetId()) {
+			resp.Containers = append(resp.Containers, ctr)
+		}
+	}
+	return resp, nil
+}
+
+// newFakeCRIServer serves containers over the CRI runtime service on a unix
+// socket and returns its endpoint
+func newFakeCRIServer(t *testing.T, fake *fakeCRIServer) string {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "cri.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	runtimeapi.RegisterRuntimeServiceServer(srv, fake)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(srv.Stop)
+
+	return "unix://" + socket
+}
+
+func TestCRIClient(t *testing.T) {
+	fake := &fakeCRIServer{containers: []*runtimeapi.Container{{
+		Id:           criTestID1,
+		PodSandboxId: "sandbox-1",
+		Metadata:     &runtimeapi.ContainerMetadata{Name: "nginx"},
+		Image:        &runtimeapi.ImageSpec{Image: "sha256:abc", UserSpecifiedImage: "nginx:1.27"},
+		State:        runtimeapi.ContainerState_CONTAINER_RUNNING,
+		CreatedAt:    1700000000,
+		Labels: map[string]string{
+			criPodNameLabel:      "web-0",
+			criPodNamespaceLabel: "default",
+			criPodUIDLabel:       "pod-uid-1",
+		},
+	}}}
+	client := newCRIClient(newFakeCRIServer(t, fake))
+	t.Cleanup(func() { _ = client.Close() })
+	require.NoError(t, client.Start())
+	assert.Equal(t, ContainerDRuntime, client.runtime)
+
+	ctr, err := client.Container(criTestID1)
+	require.NoError(t, err)
+	require.NotNil(t, ctr)
+	assert.Equal(t, "sandbox-1", ctr.PodSandboxID)
+	assert.Equal(t, "nginx", ctr.Name)
+	assert.Equal(t, "nginx:1.27", ctr.Image, "image as written in the pod spec")
+	assert.Equal(t, ContainerDRuntime, ctr.Runtime)
+	assert.Equal(t, &Pod{ID: "pod-uid-1", Name: "web-0", Namespace: "default"}, ctr.pod())
+
+	t.Run("unknown container", func(t *testing.T) {
+		ctr, err := client.Container(criTestID2)
+		require.NoError(t, err)
+		assert.Nil(t, ctr)
+	})
+
+	t.Run("ID prefix", func(t *testing.T) {
+		ctr, err := client.Container(criTestID1[:12])
+		require.NoError(t, err)
+		assert.Nil(t, ctr, "only the full ID matches")
+	})
+
+	t.Run("runtime error", func(t *testing.T) {
+		fake.err = status.Error(codes.Unavailable, "runtime is shutting down")
+		defer func() { fake.err = nil }()
+		_, err := client.Container(criTestID1)
+		assert.ErrorContains(t, err, "runtime is shutting down")
+		assert.Equal(t, codes.Unavailable, status.Code(errors.Unwrap(err)))
+	})
+
+	t.Run("no runtime", func(t *testing.T) {
+		client := newCRIClient(filepath.Join(t.TempDir(), "missing.sock"))
+		assert.Error(t, client.Start())
+	})
+}
+
+func TestCRIContainerPod(t *testing.T) {
+	assert.Nil(t, (&runtimeContainer{Labels: map[string]string{}}).pod(), "not run by kubelet")
+}
+
+// fakeContainerRuntime returns the containers it holds
+type fakeContainerRuntime struct {
+	containers map[string]*runtimeContainer
+	lookups    int
+}
+
+func (f *fakeContainerRuntime) Container(id string) (*runtimeContainer, error) {
+	f.lookups++
+	return f.containers[id], nil
+}
+
+func (f *fakeContainerRuntime) Close() error { return nil }
+
+func TestRefresh_ContainerRuntime(t *testing.T) {
+	newMockProc := func(pid int, ctrID string, cpuTime float64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return("worker", nil)
+		mockProc.On("Executable").Return("/bin/worker", nil)
+		mockProc.On("Cgroups").Return([]cGroup{{
+			Path: "/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1.slice/cri-containerd-" + ctrID + ".scope",
+		}}, nil)
+		mockProc.On("CmdLine").Return([]string{"/bin/worker"}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{"HOSTNAME=web-0"}, nil).Maybe()
+		mockProc.On("CPUTime").Return(cpuTime, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(1, criTestID1, 1.0),
+		newMockProc(2, criTestID1, 2.0),
+		newMockProc(3, criTestID2, 4.0),
+	}, nil)
+
+	informer, err := NewInformer(WithProcReader(mockReader))
+	require.NoError(t, err)
+	cri := &fakeContainerRuntime{containers: map[string]*runtimeContainer{
+		criTestID1: {
+			ID:      criTestID1,
+			Name:    "nginx",
+			Image:   "nginx:1.27",
+			Runtime: ContainerDRuntime,
+			Labels: map[string]string{
+				criPodNameLabel:      "web-0",
+				criPodNamespaceLabel: "default",
+				criPodUIDLabel:       "pod-uid-1",
+			},
+		},
+	}}
+	informer.runtimes = []containerRuntime{cri}
+
+	require.NoError(t, informer.Refresh())
+	containers := informer.Containers().Running
+	require.Len(t, containers, 2)
+
+	nginx := containers[criTestID1]
+	assert.Equal(t, "nginx", nginx.Name, "name known to the runtime")
+	assert.Equal(t, "nginx:1.27", nginx.Image)
+	assert.Equal(t, "web-0", nginx.Labels[criPodNameLabel])
+
+	other := containers[criTestID2]
+	assert.Equal(t, "web-0", other.Name, "name from the environment of unknown containers")
+	assert.Empty(t, other.Image)
+
+	// pods are known from the labels of containers without a pod informer
+	pods := informer.Pods()
+	require.Len(t, pods.Running, 1)
+	pod := pods.Running["pod-uid-1"]
+	require.NotNil(t, pod)
+	assert.Equal(t, "web-0", pod.Name)
+	assert.Equal(t, "default", pod.Namespace)
+	assert.Equal(t, 3.0, pod.CPUTimeDelta)
+	assert.Equal(t, []string{criTestID2}, pods.ContainersNoPod)
+
+	// containers are looked up once
+	require.NoError(t, informer.Refresh())
+	assert.Equal(t, 2, cri.lookups)
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// dockerClient looks up containers through the Docker Engine API, which both
+// Docker and Podman serve on a unix socket. Endpoints can be glob patterns so
+// that the sockets of rootless Podman (one per user) are found as users start
+// them; endpoints without a socket are skipped.
+type dockerClient struct {
+	endpoints []string
+	clients   map[string]*http.Client // by socket path
+}
+
+var _ containerRuntime = (*dockerClient)(nil)
+
+// newDockerClient returns a client of the Docker Engine API sockets matching
+// endpoints, which can be paths, glob patterns or unix:// URLs
+func newDockerClient(endpoints []string) *dockerClient {
+	paths := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		paths = append(paths, strings.TrimPrefix(endpoint, "unix://"))
+	}
+	return &dockerClient{
+		endpoints: paths,
+		clients:   make(map[string]*http.Client),
+	}
+}
+
+// dockerInspect holds the fields of a container inspected through the Docker
+// Engine API used by kepler
+type dockerInspect struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Config struct {
+		Image  string            `json:"Image"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+}
+
+func (d *dockerClient) Container(id string) (*runtimeContainer, error) {
+	var errs error
+	for _, socket := range d.sockets() {
+		ctr, err := d.inspect(socket, id)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if ctr != nil {
+			return ctr, nil
+		}
+	}
+	return nil, errs
+}
+
+// sockets returns the sockets matching the endpoints
+func (d *dockerClient) sockets() []string {
+	var sockets []string
+	for _, pattern := range d.endpoints {
+		// patterns are validated with the configuration
+		matches, _ := filepath.Glob(pattern)
+		sockets = append(sockets, matches...)
+	}
+	return sockets
+}
+
+// inspect returns the container with the given ID from the engine at socket;
+// nil if the engine doesn't know the container
+func (d *dockerClient) inspect(socket, id string) (*runtimeContainer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"http://localhost/containers/"+url.PathEscape(id)+"/json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.client(socket).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s at %s: %w", id, socket, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to inspect container %s at %s: unexpected HTTP status %s", id, socket, resp.Status)
+	}
+
+	var inspect dockerInspect
+	if err := json.NewDecoder(resp.Body).Decode(&inspect); err != nil {
+		return nil, fmt.Errorf("failed to parse container %s at %s: %w", id, socket, err)
+	}
+
+	return &runtimeContainer{
+		ID:      inspect.ID,
+		Name:    strings.TrimPrefix(inspect.Name, "/"),
+		Image:   inspect.Config.Image,
+		Runtime: UnknownRuntime, // Docker and Podman are told apart by the cgroup path
+		Labels:  inspect.Config.Labels,
+	}, nil
+}
+
+// client returns the HTTP client of the socket
+func (d *dockerClient) client(socket string) *http.Client {
+	if c, ok := d.clients[socket]; ok {
+		return c
+	}
+
+	c := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}}
+	d.clients[socket] = c
+	return c
+}
+
+func (d *dockerClient) Close() error {
+	for _, c := range d.clients {
+		c.CloseIdleConnections()
+	}
+	return nil
+}
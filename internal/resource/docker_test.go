@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeDockerServer serves the container inspect call of the Docker Engine
+// API on the socket at path
+func newFakeDockerServer(t *testing.T, path string, containers ...*dockerInspect) {
+	t.Helper()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/containers/"), "/json")
+		for _, ctr := range containers {
+			if ctr.ID == id {
+				_ = json.NewEncoder(w).Encode(ctr)
+				return
+			}
+		}
+		http.Error(w, `{"message":"no such container"}`, http.StatusNotFound)
+	})
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	srv := &http.Server{Handler: handler}
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { _ = srv.Close() })
+}
+
+func TestDockerClient(t *testing.T) {
+	dir := t.TempDir()
+
+	web := &dockerInspect{ID: criTestID1, Name: "/shop-web-1"}
+	web.Config.Image = "nginx:1.27"
+	web.Config.Labels = map[string]string{"com.docker.compose.project": "shop"}
+	newFakeDockerServer(t, filepath.Join(dir, "docker.sock"), web)
+
+	db := &dockerInspect{ID: criTestID2, Name: "/db"}
+	db.Config.Image = "docker.io/library/postgres:16"
+	db.Config.Labels = map[string]string{"io.podman.compose.project": "inventory"}
+	newFakeDockerServer(t, filepath.Join(dir, "user", "1000", "podman.sock"), db)
+
+	client := newDockerClient([]string{
+		"unix://" + filepath.Join(dir, "docker.sock"),
+		filepath.Join(dir, "missing.sock"),
+		filepath.Join(dir, "user", "*", "podman.sock"),
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctr, err := client.Container(criTestID1)
+	require.NoError(t, err)
+	require.NotNil(t, ctr)
+	assert.Equal(t, "shop-web-1", ctr.Name)
+	assert.Equal(t, "nginx:1.27", ctr.Image)
+	assert.Equal(t, UnknownRuntime, ctr.Runtime, "runtime is taken from the cgroup path")
+	assert.Equal(t, "shop", ctr.composeProject())
+	assert.Nil(t, ctr.pod())
+
+	t.Run("rootless podman", func(t *testing.T) {
+		ctr, err := client.Container(criTestID2)
+		require.NoError(t, err)
+		require.NotNil(t, ctr)
+		assert.Equal(t, "db", ctr.Name)
+		assert.Equal(t, "inventory", ctr.composeProject())
+	})
+
+	t.Run("unknown container", func(t *testing.T) {
+		ctr, err := client.Container(strings.Repeat("3", 64))
+		require.NoError(t, err)
+		assert.Nil(t, ctr)
+	})
+
+	t.Run("no engine", func(t *testing.T) {
+		client := newDockerClient([]string{filepath.Join(dir, "missing.sock")})
+		ctr, err := client.Container(criTestID1)
+		require.NoError(t, err)
+		assert.Nil(t, ctr)
+	})
+}
+
+func TestRefresh_DockerContainers(t *testing.T) {
+	mockProc := &MockProcInfo{}
+	mockProc.On("PID").Return(1)
+	mockProc.On("Comm").Return("nginx", nil)
+	mockProc.On("Executable").Return("/usr/sbin/nginx", nil)
+	mockProc.On("Cgroups").Return([]cGroup{{Path: "/system.slice/docker-" + criTestID1 + ".scope"}}, nil)
+	mockProc.On("Environ").Return([]string{"HOSTNAME=" + criTestID1[:12]}, nil).Maybe()
+	mockProc.On("CmdLine").Return([]string{"nginx"}, nil).Maybe()
+	mockProc.On("CPUTime").Return(1.0, nil)
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	web := &dockerInspect{ID: criTestID1, Name: "/shop-web-1"}
+	web.Config.Image = "nginx:1.27"
+	web.Config.Labels = map[string]string{"com.docker.compose.project": "shop"}
+	newFakeDockerServer(t, socket, web)
+
+	informer, err := NewInformer(WithProcReader(mockReader), WithDockerEndpoints([]string{socket}))
+	require.NoError(t, err)
+	require.NoError(t, informer.Init())
+	t.Cleanup(func() { _ = informer.Shutdown() })
+
+	require.NoError(t, informer.Refresh())
+	ctr := informer.Containers().Running[criTestID1]
+	require.NotNil(t, ctr)
+	assert.Equal(t, "shop-web-1", ctr.Name, "name known to docker instead of the hostname")
+	assert.Equal(t, DockerRuntime, ctr.Runtime)
+	assert.Equal(t, "nginx:1.27", ctr.Image)
+	assert.Equal(t, "shop", ctr.ComposeProject)
+	assert.Nil(t, ctr.Pod)
+	assert.Empty(t, informer.Pods().Running)
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Filter selects the workloads reported by the informer. A workload is reported
+// if it matches every include rule that is set and no exclude rule. Workloads
+// that are filtered out are still read so that the CPU time of the node is
+// complete.
+type Filter struct {
+	// IncludeComm and ExcludeComm select processes by command name; nil
+	// rules are not applied
+	IncludeComm *regexp.Regexp
+	ExcludeComm *regexp.Regexp
+
+	// IncludeCgroups and ExcludeCgroups select processes by the prefix of any
+	// of their cgroup paths
+	IncludeCgroups []string
+	ExcludeCgroups []string
+
+	// IncludeNamespaces and ExcludeNamespaces select pods, and the containers
+	// and processes in them, by namespace; workloads outside of pods are not
+	// selected by namespace
+	IncludeNamespaces []string
+	ExcludeNamespaces []string
+
+	// MinCPUTimeDelta is the CPU time in seconds a process must use within a
+	// refresh before it is reported; it is reported from then on until it exits
+	MinCPUTimeDelta float64
+}
+
+// matchesCgroups returns true if processes are selected by cgroup path
+func (f *Filter) matchesCgroups() bool {
+	return len(f.IncludeCgroups) > 0 || len(f.ExcludeCgroups) > 0
+}
+
+// admits returns true once the process used enough CPU time to be reported
+func (f *Filter) admits(p *Process) bool {
+	if !p.reported && p.CPUTimeDelta >= f.MinCPUTimeDelta {
+		p.reported = true
+	}
+	return p.reported
+}
+
+// selectsProcess returns true if the process matches the comm and cgroup rules
+func (f *Filter) selectsProcess(p *Process) bool {
+	if f.IncludeComm != nil && !f.IncludeComm.MatchString(p.Comm) {
+		return false
+	}
+	if f.ExcludeComm != nil && f.ExcludeComm.MatchString(p.Comm) {
+		return false
+	}
+	if len(f.IncludeCgroups) > 0 && !hasAnyPrefix(p.cgroupPaths, f.IncludeCgroups) {
+		return false
+	}
+	return !hasAnyPrefix(p.cgroupPaths, f.ExcludeCgroups)
+}
+
+// selectsPod returns true if the pod is reported; nil pods are always reported
+func (f *Filter) selectsPod(pod *Pod) bool {
+	if pod == nil {
+		return true
+	}
+	if len(f.IncludeNamespaces) > 0 && !slices.Contains(f.IncludeNamespaces, pod.Namespace) {
+		return false
+	}
+	return !slices.Contains(f.ExcludeNamespaces, pod.Namespace)
+}
+
+// selectsContainer returns true if the pod of the container, if any, is reported
+func (f *Filter) selectsContainer(c *Container) bool {
+	return c == nil || f.selectsPod(c.Pod)
+}
+
+func hasAnyPrefix(paths, prefixes []string) bool {
+	for _, path := range paths {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cgroupPaths returns the cgroup paths of the process
+func cgroupPaths(proc procInfo) ([]string, error) {
+	cgroups, err := proc.Cgroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process cgroups: %w", err)
+	}
+
+	paths := make([]string, 0, len(cgroups))
+	for _, cg := range cgroups {
+		paths = append(paths, cg.Path)
+	}
+	return paths, nil
+}
+
+// applyFilter removes the workloads that aren't reported. It is applied once
+// all workloads and the node are refreshed, so that containers, pods, VMs,
+// systemd units and the node account for the CPU time of all processes.
+// Running processes, containers and pods that are filtered out are kept in
+// Processes.Filtered, Containers.Filtered and Pods.Filtered.
+func (ri *resourceInformer) applyFilter() {
+	f := ri.filter
+
+	filteredPods := make(map[string]*Pod)
+	for id, pod := range ri.pods.Running {
+		if !f.selectsPod(pod) {
+			filteredPods[id] = pod
+			delete(ri.pods.Running, id)
+		}
+	}
+	for id, pod := range ri.pods.Terminated {
+		if !f.selectsPod(pod) {
+			delete(ri.pods.Terminated, id)
+		}
+	}
+	ri.pods.Filtered = filteredPods
+
+	filteredContainers := make(map[string]*Container)
+	for id, c := range ri.containers.Running {
+		if !f.selectsContainer(c) {
+			filteredContainers[id] = c
+			delete(ri.containers.Running, id)
+		}
+	}
+	for id, c := range ri.containers.Terminated {
+		if !f.selectsContainer(c) {
+			delete(ri.containers.Terminated, id)
+		}
+	}
+	ri.containers.Filtered = filteredContainers
+
+	filtered := make(map[int]*Process)
+	for pid, proc := range ri.processes.Running {
+		if !f.admits(proc) || !f.selectsProcess(proc) || !f.selectsContainer(proc.Container) {
+			filtered[pid] = proc
+			delete(ri.processes.Running, pid)
+		}
+	}
+	ri.processes.Filtered = filtered
+
+	// processes that exit before they are admitted are never reported
+	for pid, proc := range ri.processes.Terminated {
+		if !proc.reported || !f.selectsProcess(proc) || !f.selectsContainer(proc.Container) {
+			delete(ri.processes.Terminated, pid)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/k8s/pod"
+)
+
+func TestFilter_SelectsProcess(t *testing.T) {
+	nginx := &Process{Comm: "nginx", cgroupPaths: []string{"/system.slice/nginx.service"}}
+	kworker := &Process{Comm: "kworker/0:1", cgroupPaths: []string{"/"}}
+
+	tt := []struct {
+		name     string
+		filter   Filter
+		selected []*Process
+	}{
+		{"no rules", Filter{}, []*Process{nginx, kworker}},
+		{"include comm", Filter{IncludeComm: regexp.MustCompile("^nginx$")}, []*Process{nginx}},
+		{"exclude comm", Filter{ExcludeComm: regexp.MustCompile("^kworker/")}, []*Process{nginx}},
+		{"include cgroup", Filter{IncludeCgroups: []string{"/system.slice/"}}, []*Process{nginx}},
+		{"exclude cgroup", Filter{ExcludeCgroups: []string{"/system.slice/"}}, []*Process{kworker}},
+		{
+			"include and exclude",
+			Filter{IncludeCgroups: []string{"/"}, ExcludeComm: regexp.MustCompile("nginx")},
+			[]*Process{kworker},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, p := range []*Process{nginx, kworker} {
+				assert.Equal(t, slices.Contains(tc.selected, p), tc.filter.selectsProcess(p), p.Comm)
+			}
+		})
+	}
+}
+
+func TestFilter_SelectsPod(t *testing.T) {
+	f := Filter{IncludeNamespaces: []string{"prod", "kube-system"}, ExcludeNamespaces: []string{"kube-system"}}
+
+	assert.True(t, f.selectsPod(&Pod{Namespace: "prod"}))
+	assert.False(t, f.selectsPod(&Pod{Namespace: "kube-system"}))
+	assert.False(t, f.selectsPod(&Pod{Namespace: "dev"}))
+	assert.True(t, f.selectsPod(nil), "workloads outside of pods are not selected by namespace")
+	assert.True(t, f.selectsContainer(&Container{ID: "standalone"}))
+	assert.False(t, f.selectsContainer(&Container{Pod: &Pod{Namespace: "dev"}}))
+}
+
+func TestRefresh_Filter(t *testing.T) {
+	newMockProc := func(pid int, comm string, cpuTime float64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return(comm, nil)
+		mockProc.On("Executable").Return("/usr/bin/"+comm, nil)
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/system.slice/" + comm + ".service"}}, nil)
+		mockProc.On("CmdLine").Return([]string{comm}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(cpuTime, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(1, "nginx", 2.0),
+		newMockProc(2, "sshd", 1.0),
+		newMockProc(3, "cron", 0.001),
+	}, nil).Once()
+
+	filter := &Filter{ExcludeCgroups: []string{"/system.slice/sshd"}, MinCPUTimeDelta: 0.01}
+	informer, err := NewInformer(WithProcReader(mockReader), WithFilter(filter))
+	require.NoError(t, err)
+
+	require.NoError(t, informer.Refresh())
+	procs := informer.Processes()
+	assert.Len(t, procs.Running, 1)
+	assert.Contains(t, procs.Running, 1)
+	assert.Len(t, procs.Filtered, 2, "sshd is excluded and cron used too little CPU time")
+	assert.Equal(t, 3.001, informer.Node().ProcessTotalCPUTimeDelta, "filtered processes count towards the node")
+
+	// cron is reported once it uses enough CPU time and sshd exits
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(1, "nginx", 2.5),
+		newMockProc(3, "cron", 0.5),
+	}, nil).Once()
+
+	require.NoError(t, informer.Refresh())
+	procs = informer.Processes()
+	assert.Len(t, procs.Running, 2)
+	assert.Contains(t, procs.Running, 3)
+	assert.Empty(t, procs.Filtered)
+	assert.Empty(t, procs.Terminated, "filtered processes are not reported when they exit")
+}
+
+func TestRefresh_SetFilter(t *testing.T) {
+	newMockProc := func(pid int, comm string) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return(comm, nil)
+		mockProc.On("Executable").Return("/usr/bin/"+comm, nil)
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/system.slice/" + comm + ".service"}}, nil)
+		mockProc.On("CmdLine").Return([]string{comm}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(1.0, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1, "nginx"), newMockProc(2, "sshd")}, nil)
+
+	informer, err := NewInformer(WithProcReader(mockReader))
+	require.NoError(t, err)
+	require.NoError(t, informer.Refresh())
+	assert.Len(t, informer.Processes().Running, 2)
+
+	// cgroups of processes seen before the filter are read once it has cgroup rules
+	informer.SetFilter(&Filter{ExcludeCgroups: []string{"/system.slice/sshd"}})
+	assert.Len(t, informer.Processes().Running, 2, "filter applies from the next refresh")
+	require.NoError(t, informer.Refresh())
+	assert.Len(t, informer.Processes().Running, 1)
+	assert.Contains(t, informer.Processes().Running, 1)
+
+	informer.SetFilter(nil)
+	require.NoError(t, informer.Refresh())
+	assert.Len(t, informer.Processes().Running, 2)
+}
+
+func TestRefresh_FilterNamespaces(t *testing.T) {
+	root := newCgroupRoot(t)
+
+	mockProcFS := &MockProcReader{}
+	mockProcFS.On("AllProcs").Return([]procInfo{}, nil)
+	mockProcFS.On("CPUUsageRatio").Return(0.5, nil)
+
+	mockPodInformer := new(mockPodInformer)
+	mockPodInformer.On("LookupByContainerID", cgroupTestID1).Return(nil, false, nil)
+	mockPodInformer.On("LookupByContainerID", cgroupTestID2).Return(
+		&pod.ContainerInfo{PodID: "pod-1234", PodName: "web", Namespace: "default", ContainerName: "app"}, true, nil)
+
+	informer, err := NewInformer(
+		WithProcReader(mockProcFS),
+		WithPodInformer(mockPodInformer),
+		WithCgroupCPUAccounting(root),
+		WithProcessTracking(false),
+		WithFilter(&Filter{ExcludeNamespaces: []string{"default"}}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, informer.Init())
+
+	require.NoError(t, informer.Refresh())
+	assert.Empty(t, informer.Pods().Running)
+	assert.Contains(t, informer.Pods().Filtered, "pod-1234")
+	containers := informer.Containers().Running
+	require.Len(t, containers, 1)
+	assert.Contains(t, containers, cgroupTestID1, "containers outside of pods are reported")
+	assert.Contains(t, informer.Containers().Filtered, cgroupTestID2)
+	assert.Equal(t, 100.0, informer.Node().ProcessTotalCPUTimeDelta)
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupSpec defines a group of processes tracked as a workload: either the
+// process of PID and its descendants, or the processes in the cgroup at Cgroup,
+// e.g. a transient systemd scope, and the cgroups nested in it
+type GroupSpec struct {
+	Name   string
+	PID    int
+	Cgroup string
+}
+
+// Validate returns an error if the spec has no name, or doesn't set exactly
+// one of PID and Cgroup
+func (s GroupSpec) Validate() error {
+	switch {
+	case s.Name == "":
+		return fmt.Errorf("group name is required")
+	case s.PID < 0:
+		return fmt.Errorf("invalid pid of group %q: %d", s.Name, s.PID)
+	case (s.PID == 0) == (s.Cgroup == ""):
+		return fmt.Errorf("group %q must set either a pid or a cgroup", s.Name)
+	case s.Cgroup != "" && !strings.HasPrefix(s.Cgroup, "/"):
+		return fmt.Errorf("invalid cgroup of group %q: %q; must be an absolute cgroup path", s.Name, s.Cgroup)
+	}
+	return nil
+}
+
+// AddGroup starts grouping processes by spec from the next refresh, replacing
+// the group of the same name if any. Process trees stop being tracked once all
+// their processes exit. It is safe to call concurrently with Refresh.
+func (ri *resourceInformer) AddGroup(spec GroupSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	ri.groupsMu.Lock()
+	defer ri.groupsMu.Unlock()
+	ri.pendingGroups = append(ri.pendingGroups, spec)
+	return nil
+}
+
+// applyPendingGroups adds the groups added since the last refresh
+func (ri *resourceInformer) applyPendingGroups() {
+	ri.groupsMu.Lock()
+	pending := ri.pendingGroups
+	ri.pendingGroups = nil
+	ri.groupsMu.Unlock()
+
+	for _, spec := range pending {
+		ri.groupSpecs[spec.Name] = spec
+		if spec.Cgroup != "" {
+			ri.cgroupGroups = true
+		}
+	}
+}
+
+// readsCgroupPaths returns true if processes are selected by cgroup path, by
+// the filter or a group
+func (ri *resourceInformer) readsCgroupPaths() bool {
+	return (ri.filter != nil && ri.filter.matchesCgroups()) || ri.cgroupGroups
+}
+
+// refreshGroups assigns processes to groups and sums the CPU time of each
+// group. A group runs while any of its processes ran since the last refresh,
+// so that the CPU time used by its processes before they exited is included.
+func (ri *resourceInformer) refreshGroups() error {
+	groupsRunning := make(map[string]*Group)
+	if len(ri.groupSpecs) > 0 {
+		ri.assignGroups()
+	}
+
+	for _, procs := range []map[int]*Process{ri.processes.Running, ri.processes.Terminated} {
+		for _, proc := range procs {
+			g := proc.Group
+			if g == nil {
+				continue
+			}
+			// reset CPU time of the group if it is getting added to the running list for the first time
+			if _, seen := groupsRunning[g.Name]; !seen {
+				g.CPUTimeDelta = 0
+			}
+			g.CPUTimeDelta += proc.CPUTimeDelta
+			g.CPUTotalTime += proc.CPUTimeDelta
+			groupsRunning[g.Name] = g
+		}
+	}
+
+	// Find terminated groups; process trees end with their processes
+	groupsTerminated := make(map[string]*Group)
+	for name, g := range ri.groupCache {
+		if _, isRunning := groupsRunning[name]; !isRunning {
+			groupsTerminated[name] = g
+			delete(ri.groupCache, name)
+			if g.PID != 0 {
+				delete(ri.groupSpecs, name)
+			}
+		}
+	}
+
+	ri.groups.Running = groupsRunning
+	ri.groups.Terminated = groupsTerminated
+
+	return nil
+}
+
+// assignGroups assigns the running processes that aren't in a group to the
+// group they belong to, if any: the group whose root process or cgroup they
+// are, or the group of their parent. Processes stay in the group they are
+// first assigned to. Parents are assigned before their children, which may be
+// seen first.
+func (ri *resourceInformer) assignGroups() {
+	for assigned := true; assigned; {
+		assigned = false
+		for _, proc := range ri.processes.Running {
+			if proc.Group != nil {
+				continue
+			}
+			name, ok := ri.groupOf(proc)
+			if !ok {
+				continue
+			}
+
+			g, exists := ri.groupCache[name]
+			if !exists {
+				spec := ri.groupSpecs[name]
+				g = &Group{Name: spec.Name, PID: spec.PID, Cgroup: spec.Cgroup}
+				ri.groupCache[name] = g
+			}
+			g.Processes++
+			proc.Group = g
+			assigned = true
+		}
+	}
+}
+
+// groupOf returns the name of the group of a process that isn't in a group
+// yet and false if it belongs to none
+func (ri *resourceInformer) groupOf(proc *Process) (string, bool) {
+	for name, spec := range ri.groupSpecs {
+		if spec.PID != 0 && spec.PID == proc.PID {
+			return name, true
+		}
+		if spec.Cgroup != "" && hasAnyPrefix(proc.cgroupPaths, []string{spec.Cgroup}) {
+			return name, true
+		}
+	}
+	if parent, ok := ri.processes.Running[proc.ParentPID]; ok && parent.Group != nil {
+		return parent.Group.Name, true
+	}
+	return "", false
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupSpecValidate(t *testing.T) {
+	tt := []struct {
+		name string
+		spec GroupSpec
+		err  string
+	}{
+		{"process tree", GroupSpec{Name: "build", PID: 42}, ""},
+		{"cgroup", GroupSpec{Name: "session", Cgroup: "/user.slice/user-1000.slice/session-3.scope"}, ""},
+		{"no name", GroupSpec{PID: 42}, "group name is required"},
+		{"negative pid", GroupSpec{Name: "build", PID: -1}, "invalid pid"},
+		{"neither", GroupSpec{Name: "build"}, "must set either a pid or a cgroup"},
+		{"both", GroupSpec{Name: "build", PID: 42, Cgroup: "/build"}, "must set either a pid or a cgroup"},
+		{"relative cgroup", GroupSpec{Name: "session", Cgroup: "session-3.scope"}, "must be an absolute cgroup path"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	_, err := NewInformer(WithProcReader(&MockProcReader{}), WithGroups(GroupSpec{Name: "build"}))
+	assert.Error(t, err)
+}
+
+func TestRefresh_Groups(t *testing.T) {
+	newMockProc := func(pid, ppid int, cgroup string, cpuTime float64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return("worker", nil).Maybe()
+		mockProc.On("Executable").Return("/bin/worker", nil).Maybe()
+		mockProc.On("Cgroups").Return([]cGroup{{Path: cgroup}}, nil).Maybe()
+		mockProc.On("CmdLine").Return([]string{"/bin/worker"}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("ParentPID").Return(ppid, nil)
+		mockProc.On("CPUTime").Return(cpuTime, nil)
+		return mockProc
+	}
+
+	const session = "/user.slice/user-1000.slice/session-3.scope"
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	// children are listed before their parents
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(12, 11, "/user.slice/build", 1.0),
+		newMockProc(11, 10, "/user.slice/build", 2.0),
+		newMockProc(10, 1, "/user.slice/build", 3.0),
+		newMockProc(20, 1, session, 4.0),
+		newMockProc(21, 1, session+"/app", 1.0),
+		newMockProc(30, 1, "/system.slice/nginx.service", 5.0),
+	}, nil).Once()
+
+	informer, err := NewInformer(WithProcReader(mockReader), WithGroups(
+		GroupSpec{Name: "build", PID: 10},
+		GroupSpec{Name: "session", Cgroup: session},
+	))
+	require.NoError(t, err)
+
+	require.NoError(t, informer.Refresh())
+	groups := informer.Groups()
+	require.Len(t, groups.Running, 2)
+	assert.Empty(t, groups.Terminated)
+
+	build := groups.Running["build"]
+	require.NotNil(t, build)
+	assert.Equal(t, 10, build.PID)
+	assert.Equal(t, 3, build.Processes)
+	assert.Equal(t, 6.0, build.CPUTimeDelta)
+	assert.Equal(t, 6.0, build.CPUTotalTime)
+
+	assert.Equal(t, session, groups.Running["session"].Cgroup)
+	assert.Equal(t, 2, groups.Running["session"].Processes, "nested cgroups are in the group")
+	assert.Equal(t, 5.0, groups.Running["session"].CPUTimeDelta)
+	assert.Nil(t, informer.Processes().Running[30].Group)
+
+	// the root exits; its children keep running and a grandchild starts
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(13, 11, "/user.slice/build", 0.5),
+		newMockProc(11, 1, "/user.slice/build", 3.0),
+		newMockProc(12, 11, "/user.slice/build", 1.5),
+		newMockProc(20, 1, session, 4.0),
+		newMockProc(21, 1, session+"/app", 1.0),
+		newMockProc(30, 1, "/system.slice/nginx.service", 6.0),
+	}, nil).Once()
+
+	require.NoError(t, informer.Refresh())
+	groups = informer.Groups()
+	build = groups.Running["build"]
+	require.NotNil(t, build)
+	assert.Equal(t, 4, build.Processes)
+	assert.Equal(t, 2.0, build.CPUTimeDelta)
+	assert.Equal(t, 8.0, build.CPUTotalTime)
+	assert.Equal(t, 0.0, groups.Running["session"].CPUTimeDelta)
+
+	// the tree exits; a group added at runtime starts
+	require.NoError(t, informer.AddGroup(GroupSpec{Name: "nginx", PID: 30}))
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(20, 1, session, 4.5),
+		newMockProc(30, 1, "/system.slice/nginx.service", 7.0),
+	}, nil).Once()
+
+	require.NoError(t, informer.Refresh())
+	groups = informer.Groups()
+	assert.Contains(t, groups.Running, "build", "runs while its processes ran since the last refresh")
+	assert.Equal(t, 1.0, groups.Running["nginx"].CPUTimeDelta)
+	assert.Equal(t, 1.0, groups.Running["nginx"].CPUTotalTime, "CPU time from when the group was added")
+
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(20, 1, session, 5.0),
+		newMockProc(30, 1, "/system.slice/nginx.service", 8.0),
+	}, nil).Once()
+
+	require.NoError(t, informer.Refresh())
+	groups = informer.Groups()
+	assert.Len(t, groups.Running, 2)
+	assert.Contains(t, groups.Terminated, "build")
+	assert.NotContains(t, informer.groupSpecs, "build", "process trees end with their processes")
+	assert.Contains(t, informer.groupSpecs, "session")
+}
+
+func TestRefresh_NoGroups(t *testing.T) {
+	mockProc := &MockProcInfo{}
+	mockProc.On("PID").Return(1)
+	mockProc.On("Comm").Return("init", nil)
+	mockProc.On("Executable").Return("/sbin/init", nil)
+	mockProc.On("Cgroups").Return([]cGroup{{Path: "/init.scope"}}, nil).Maybe()
+	mockProc.On("CmdLine").Return([]string{"/sbin/init"}, nil).Maybe()
+	mockProc.On("Environ").Return([]string{}, nil).Maybe()
+	mockProc.On("CPUTime").Return(1.0, nil)
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+
+	informer, err := NewInformer(WithProcReader(mockReader))
+	require.NoError(t, err)
+
+	require.NoError(t, informer.Refresh())
+	assert.Empty(t, informer.Groups().Running)
+	assert.Nil(t, informer.Processes().Running[1].Group)
+	mockProc.AssertNotCalled(t, "ParentPID")
+}
@@ -4,6 +4,7 @@
 package resource
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -19,18 +20,40 @@ import (
 type Node struct {
 	ProcessTotalCPUTimeDelta float64 // sum of all process CPU time deltas
 	CPUUsageRatio            float64
+
+	// CoreTypeUsageRatios holds the CPU usage ratio of each core type of a
+	// hybrid CPU, keyed by core type; nil unless the core types of CPUs are set
+	CoreTypeUsageRatios map[string]float64
+
+	// IODelta is the sum of the bytes transferred by all processes since the
+	// last refresh; zero unless I/O tracking is enabled
+	IODelta IOCounters
+
+	// Aggregates holds the kernel and system aggregates, keyed by name; empty
+	// unless aggregate tracking is enabled, in which case ProcessTotalCPUTimeDelta
+	// includes the CPU time of the system aggregate
+	Aggregates map[string]*Aggregate
 }
 
 // Processes represents sets of running and terminated processes
 type Processes struct {
 	Running    map[int]*Process
 	Terminated map[int]*Process
+
+	// Filtered holds the running processes that aren't reported due to the
+	// filter; they still count towards the containers, pods and VMs they
+	// belong to
+	Filtered map[int]*Process
 }
 
 // Containers represents sets of running and terminated containers
 type Containers struct {
 	Running    map[string]*Container
 	Terminated map[string]*Container
+
+	// Filtered holds the running containers that aren't reported due to the
+	// filter
+	Filtered map[string]*Container
 }
 
 // VirtualMachines represents sets of running and terminated VMs
@@ -39,10 +62,25 @@ type VirtualMachines struct {
 	Terminated map[string]*VirtualMachine
 }
 
+// SystemdUnits represents sets of running and terminated systemd units
+type SystemdUnits struct {
+	Running    map[string]*SystemdUnit
+	Terminated map[string]*SystemdUnit
+}
+
+// Groups represents sets of running and terminated groups of processes
+type Groups struct {
+	Running    map[string]*Group
+	Terminated map[string]*Group
+}
+
 type Pods struct {
 	Running         map[string]*Pod
 	Terminated      map[string]*Pod
 	ContainersNoPod []string
+
+	// Filtered holds the running pods that aren't reported due to the filter
+	Filtered map[string]*Pod
 }
 
 // Informer provides the interface for accessing process, container, virtual machine, and pod information
@@ -63,6 +101,14 @@ type Informer interface {
 
 	// Pods returns the current running and terminated pods
 	Pods() *Pods
+
+	// SystemdUnits returns the current running and terminated systemd units;
+	// both are empty unless systemd unit tracking is enabled
+	SystemdUnits() *SystemdUnits
+
+	// Groups returns the current running and terminated groups of processes;
+	// both are empty unless groups are defined
+	Groups() *Groups
 }
 
 // resourceInformer is the default implementation of the resource tracking service
@@ -71,6 +117,80 @@ type resourceInformer struct {
 	fs     allProcReader
 	clock  clock.Clock
 
+	// trackMemory enables reading resident memory of processes
+	trackMemory bool
+	// trackLastCPU enables reading the CPU processes last ran on
+	trackLastCPU bool
+	// coreTypes holds the core type of each CPU of a hybrid CPU
+	coreTypes map[int]string
+	// trackExited enables estimating the CPU time used by processes before exiting
+	trackExited bool
+	// podMetadata enables decorating pods with their labels and owner
+	podMetadata bool
+	// trackSystemdUnits enables grouping processes by systemd unit
+	trackSystemdUnits bool
+	// processMetadata enables reading the command line, user and parent of processes
+	processMetadata bool
+	users           *userNames
+	// trackAggregates enables the kernel and system aggregates
+	trackAggregates bool
+	// activeCPUTime is the active CPU time of the node at the last refresh
+	activeCPUTime float64
+	// filter selects the workloads reported; nil if all workloads are reported
+	filter *Filter
+	// nextFilter replaces filter at the next refresh if filterChanged; see
+	// SetFilter
+	filterMu      sync.Mutex
+	nextFilter    *Filter
+	filterChanged bool
+	// scanWorkers is the max number of processes read in parallel
+	scanWorkers int
+
+	// cgroupRoot is the cgroup v2 mount point from which the CPU time of
+	// containers and the node is read; empty if CPU time is read from processes
+	cgroupRoot string
+	// cpusetRoot is the cgroup v2 mount point from which the cpusets of
+	// containers are read; empty if they aren't read
+	cpusetRoot string
+	// trackProcesses enables reading processes; always true without cgroupRoot
+	trackProcesses bool
+	// cgroupCPUTime is the CPU time of the root cgroup at the last refresh
+	cgroupCPUTime float64
+
+	// processEvents enables tracking processes through process events
+	processEvents bool
+	// procEvents is the source of process events; nil if procfs is scanned
+	procEvents procEventSource
+	// ebpfCPUTime enables reading the CPU time of processes from eBPF
+	ebpfCPUTime bool
+	// bpf provides the CPU time of processes; nil if it is read from procfs
+	bpf *bpfCPUTime
+	// perfEvents enables counting the hardware events of processes
+	perfEvents bool
+	// procFSPath is the procfs mount point the threads of processes are read from
+	procFSPath string
+	// hwCounters counts the hardware events of processes; nil if they aren't counted
+	hwCounters hwCounterReader
+	// trackIO enables reading the disk and network bytes of processes
+	trackIO bool
+	// netBytes counts the network bytes of processes; nil if they aren't counted
+	netBytes bpfNetBytes
+	// vmDetector detects VM processes
+	vmDetector *vmDetector
+
+	// criEndpoint is the CRI socket of the container runtime; empty if
+	// containers aren't looked up through CRI
+	criEndpoint string
+	// dockerEndpoints are the Docker Engine API sockets of Docker and Podman
+	dockerEndpoints []string
+	// runtimes look up containers, in order; empty if containers are only
+	// known from the cgroups and environment of their processes
+	runtimes []containerRuntime
+
+	// procEventsSynced is true if the process cache reflects the last procfs
+	// scan and the process events received since
+	procEventsSynced bool
+
 	node *Node
 
 	// Process tracking
@@ -90,10 +210,35 @@ type resourceInformer struct {
 	podCache    map[string]*Pod
 	pods        *Pods
 
+	// systemd unit tracking
+	unitCache map[string]*SystemdUnit
+	units     *SystemdUnits
+
+	// group tracking; groupsMu guards pendingGroups, the groups added since
+	// the last refresh (see AddGroup)
+	groupsMu      sync.Mutex
+	pendingGroups []GroupSpec
+	groupSpecs    map[string]GroupSpec
+	// cgroupGroups is true once a group of a cgroup is defined, from when the
+	// cgroups of processes are read
+	cgroupGroups bool
+	groupCache   map[string]*Group
+	groups       *Groups
+
 	lastScanTime time.Time // Time of the last full scan
+
+	// nodeErr is the error reading the node in the last refresh, reported by
+	// Health; errors of workloads (e.g. processes exiting while read) are not
+	healthMu sync.Mutex
+	nodeErr  error
 }
 
-var _ Informer = (*resourceInformer)(nil)
+var (
+	_ Informer              = (*resourceInformer)(nil)
+	_ service.Shutdowner    = (*resourceInformer)(nil)
+	_ service.Dependent     = (*resourceInformer)(nil)
+	_ service.HealthChecker = (*resourceInformer)(nil)
+)
 
 // NewInformer creates a new ResourceInformer
 func NewInformer(opts ...OptionFn) (*resourceInformer, error) {
@@ -103,7 +248,7 @@ func NewInformer(opts ...OptionFn) (*resourceInformer, error) {
 	}
 
 	if opt.procReader == nil && opt.procFSPath != "" {
-		if pi, err := NewProcFSReader(opt.procFSPath); err != nil {
+		if pi, err := newProcReader(opt.procFSPath); err != nil {
 			return nil, fmt.Errorf("failed to create procfs reader: %w", err)
 		} else {
 			opt.procReader = pi
@@ -114,12 +259,43 @@ func NewInformer(opts ...OptionFn) (*resourceInformer, error) {
 		return nil, errors.New("no procfs reader specified")
 	}
 
+	for _, spec := range opt.groups {
+		if err := spec.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &resourceInformer{
 		logger: opt.logger.With("service", "resource-informer"),
 		fs:     opt.procReader,
 		clock:  opt.clock,
 
-		node: &Node{},
+		trackMemory:  opt.trackMemory,
+		trackLastCPU: opt.trackLastCPU,
+		coreTypes:    opt.coreTypes,
+		trackExited:  opt.trackExited,
+		podMetadata:  opt.podMetadata,
+
+		trackSystemdUnits: opt.systemdUnits,
+		processMetadata:   opt.processMetadata,
+		trackAggregates:   opt.aggregates,
+		filter:            opt.filter,
+		scanWorkers:       opt.scanWorkers,
+		users:             newUserNames(),
+
+		cgroupRoot:      opt.cgroupRoot,
+		cpusetRoot:      opt.cpusetRoot,
+		trackProcesses:  opt.trackProcesses || opt.cgroupRoot == "",
+		processEvents:   opt.processEvents,
+		ebpfCPUTime:     opt.ebpfCPUTime,
+		perfEvents:      opt.perfEvents,
+		trackIO:         opt.trackIO,
+		procFSPath:      cmp.Or(opt.procFSPath, "/proc"),
+		vmDetector:      &vmDetector{libvirtDir: opt.libvirtDir},
+		criEndpoint:     opt.criEndpoint,
+		dockerEndpoints: opt.dockerEndpoints,
+
+		node: &Node{Aggregates: make(map[string]*Aggregate)},
 
 		procCache: make(map[int]*Process),
 		processes: &Processes{
@@ -145,6 +321,20 @@ func NewInformer(opts ...OptionFn) (*resourceInformer, error) {
 			Running:    make(map[string]*Pod),
 			Terminated: make(map[string]*Pod),
 		},
+
+		unitCache: make(map[string]*SystemdUnit),
+		units: &SystemdUnits{
+			Running:    make(map[string]*SystemdUnit),
+			Terminated: make(map[string]*SystemdUnit),
+		},
+
+		pendingGroups: opt.groups,
+		groupSpecs:    make(map[string]GroupSpec),
+		groupCache:    make(map[string]*Group),
+		groups: &Groups{
+			Running:    make(map[string]*Group),
+			Terminated: make(map[string]*Group),
+		},
 	}, nil
 }
 
@@ -152,6 +342,14 @@ func (ri *resourceInformer) Name() string {
 	return "resource-informer"
 }
 
+// Dependencies returns the pod informer used to look up the pods of containers
+func (ri *resourceInformer) Dependencies() []service.Service {
+	if ri.podInformer == nil {
+		return nil
+	}
+	return []service.Service{ri.podInformer}
+}
+
 func (ri *resourceInformer) Init() error {
 	// ensure we can access procfs
 	_, err := ri.fs.AllProcs()
@@ -159,16 +357,182 @@ func (ri *resourceInformer) Init() error {
 		return fmt.Errorf("failed to access procfs: %w", err)
 	}
 
+	if ri.cgroupRoot != "" && !isCgroupV2(ri.cgroupRoot) {
+		ri.logger.Warn("cgroup v2 is not mounted; reading CPU time from processes", "path", ri.cgroupRoot)
+		ri.cgroupRoot = ""
+		ri.trackProcesses = true
+	}
+	if ri.cpusetRoot != "" && !isCgroupV2(ri.cpusetRoot) {
+		ri.logger.Warn("cgroup v2 is not mounted; cpusets of containers are not read", "path", ri.cpusetRoot)
+		ri.cpusetRoot = ""
+	}
+
+	if ri.processEvents && ri.trackProcesses && ri.procEvents == nil {
+		pc := newProcConnector()
+		if err := pc.Start(); err != nil {
+			ri.logger.Warn("Failed to subscribe to process events; scanning procfs", "error", err)
+		} else {
+			ri.procEvents = pc
+		}
+	}
+
+	if ri.ebpfCPUTime && ri.trackProcesses && ri.bpf == nil {
+		times := newBPFCPUTimes()
+		if err := times.Load(); err != nil {
+			ri.logger.Warn("Failed to load the eBPF program; reading CPU time from procfs", "error", err)
+		} else {
+			ri.logger.Info("Reading CPU time of processes from eBPF")
+			ri.bpf = newBPFCPUTime(times)
+		}
+	}
+
+	if ri.perfEvents && ri.trackProcesses && ri.hwCounters == nil {
+		counters := newHWCounterReader(ri.procFSPath)
+		if err := counters.Start(); err != nil {
+			ri.logger.Warn("Failed to count hardware events; processes are reported without them", "error", err)
+		} else {
+			ri.logger.Info("Counting hardware events of processes")
+			ri.hwCounters = counters
+		}
+	}
+
+	if ri.trackIO && ri.trackProcesses && ri.netBytes == nil {
+		netBytes := newBPFNetBytes()
+		if err := netBytes.Load(); err != nil {
+			ri.logger.Warn("Failed to load the eBPF programs; processes are reported without network bytes", "error", err)
+		} else {
+			ri.logger.Info("Counting network bytes of processes with eBPF")
+			ri.netBytes = netBytes
+		}
+	}
+
+	if ri.criEndpoint != "" {
+		cri := newCRIClient(ri.criEndpoint)
+		if err := cri.Start(); err != nil {
+			ri.logger.Warn("Failed to connect to the container runtime; containers are not looked up through CRI", "error", err)
+		} else {
+			ri.logger.Info("Looking up containers in the container runtime", "endpoint", ri.criEndpoint, "runtime", cri.runtime)
+			ri.runtimes = append(ri.runtimes, cri)
+		}
+	}
+
+	// Docker and rootless Podman sockets may appear after startup
+	if len(ri.dockerEndpoints) > 0 {
+		ri.logger.Info("Looking up containers in Docker and Podman", "endpoints", ri.dockerEndpoints)
+		ri.runtimes = append(ri.runtimes, newDockerClient(ri.dockerEndpoints))
+	}
+
 	ri.logger.Info("Resource informer initialized successfully")
 	return nil
 }
 
+// Shutdown unsubscribes from process events, detaches the eBPF programs, stops
+// counting hardware events and disconnects from container runtimes
+func (ri *resourceInformer) Shutdown() error {
+	var errs error
+	if ri.procEvents != nil {
+		errs = errors.Join(errs, ri.procEvents.Close())
+	}
+	if ri.bpf != nil {
+		errs = errors.Join(errs, ri.bpf.times.Close())
+	}
+	if ri.hwCounters != nil {
+		errs = errors.Join(errs, ri.hwCounters.Close())
+	}
+	if ri.netBytes != nil {
+		errs = errors.Join(errs, ri.netBytes.Close())
+	}
+	for _, rt := range ri.runtimes {
+		errs = errors.Join(errs, rt.Close())
+	}
+	return errs
+}
+
+// resolveContainer sets the name, image, labels and pod of a new container to
+// those known to the first container runtime that knows it; the container is
+// left as is if no runtime knows it
+func (ri *resourceInformer) resolveContainer(c *Container) {
+	var ctr *runtimeContainer
+	for _, rt := range ri.runtimes {
+		found, err := rt.Container(c.ID)
+		if err != nil {
+			ri.logger.Debug("Failed to look up container in the container runtime", "container", c.ID, "error", err)
+			continue
+		}
+		if found != nil {
+			ctr = found
+			break
+		}
+	}
+	if ctr == nil {
+		return
+	}
+
+	c.Name = ctr.Name
+	c.Image = ctr.Image
+	c.Labels = ctr.Labels
+	c.ComposeProject = ctr.composeProject()
+	c.Pod = ctr.pod()
+	// cgroup paths of pods don't always name the runtime
+	if ctr.Runtime != UnknownRuntime {
+		c.Runtime = ctr.Runtime
+	}
+}
+
+// listProcs returns the processes to refresh. With process events, only the
+// cached processes and the processes started since the last refresh are read,
+// less those that exited; procfs is scanned until the cache is in sync.
+func (ri *resourceInformer) listProcs() ([]procInfo, error) {
+	if ri.procEvents == nil {
+		return ri.fs.AllProcs()
+	}
+
+	events, err := ri.procEvents.Drain()
+	if err != nil || !ri.procEventsSynced {
+		if err != nil {
+			ri.logger.Warn("Failed to read process events; scanning procfs", "error", err)
+		}
+		procs, err := ri.fs.AllProcs()
+		ri.procEventsSynced = err == nil
+		return procs, err
+	}
+
+	pids := make(map[int]struct{}, len(ri.procCache)+len(events))
+	for pid := range ri.procCache {
+		pids[pid] = struct{}{}
+	}
+	for _, ev := range events {
+		if ev.Kind == procEventExit {
+			delete(pids, ev.PID)
+		} else {
+			pids[ev.PID] = struct{}{}
+		}
+	}
+
+	procs := make([]procInfo, 0, len(pids))
+	for pid := range pids {
+		proc, err := ri.fs.Proc(pid)
+		if err != nil {
+			// the process exited and its exit event is yet to be read
+			ri.logger.Debug("Process not found", "pid", pid, "error", err)
+			continue
+		}
+		procs = append(procs, proc)
+	}
+	return procs, nil
+}
+
 // refreshProcesses refreshes the process cache and returns the procs for containers and VMs
 func (ri *resourceInformer) refreshProcesses() ([]*Process, []*Process, error) {
-	procs, err := ri.fs.AllProcs()
+	procs, err := ri.listProcs()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get processes: %w", err)
 	}
+	if ri.bpf != nil {
+		for i, proc := range procs {
+			procs[i] = &bpfProcInfo{procInfo: proc, bpf: ri.bpf}
+		}
+	}
 
 	// construct current running processes
 	procsRunning := make(map[int]*Process, len(procs))
@@ -179,10 +543,11 @@ func (ri *resourceInformer) refreshProcesses() ([]*Process, []*Process, error) {
 
 	// Refresh process cache and update running processes
 	var refreshErrs error
-	for _, p := range procs {
-		pid := p.PID()
-		// start by updating the process
-		proc, err := ri.updateProcessCache(p)
+	for _, r := range ri.scanProcs(procs) {
+		pid, proc, err := r.pid, r.proc, r.err
+		if r.isNew {
+			ri.procCache[pid] = proc
+		}
 		if err != nil {
 			if os.IsNotExist(err) {
 				ri.logger.Debug("Process not found", "pid", pid)
@@ -213,6 +578,29 @@ func (ri *resourceInformer) refreshProcesses() ([]*Process, []*Process, error) {
 		}
 	}
 
+	if ri.hwCounters != nil {
+		ri.releaseHWCounters(procsTerminated)
+	}
+
+	if ri.trackIO {
+		ri.releaseIO(procsRunning, procsTerminated)
+	}
+
+	if ri.bpf != nil {
+		if err := ri.bpf.retain(procsRunning); err != nil {
+			ri.logger.Debug("Failed to release the eBPF CPU time of exited processes", "error", err)
+		}
+	}
+
+	if ri.trackExited {
+		estimateExitedCPUTime(procsRunning, procsTerminated)
+	} else {
+		// CPU time used by terminated processes since the last refresh is unknown
+		for _, proc := range procsTerminated {
+			proc.CPUTimeDelta = 0
+		}
+	}
+
 	// Update tracking structures
 	ri.processes.Running = procsRunning
 	ri.processes.Terminated = procsTerminated
@@ -248,6 +636,56 @@ func (ri *resourceInformer) refreshContainers(containerProcs []*Process) error {
 	return nil
 }
 
+// refreshContainerCgroups refreshes the containers and their CPU time from the
+// cgroup v2 hierarchy. Names of containers are taken from their processes if
+// processes are tracked.
+func (ri *resourceInformer) refreshContainerCgroups(containerProcs []*Process) error {
+	cgroups, err := readContainerCgroups(ri.cgroupRoot)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]string)
+	for _, proc := range containerProcs {
+		if proc.Container.Name != "" {
+			names[proc.Container.ID] = proc.Container.Name
+		}
+	}
+
+	containersRunning := make(map[string]*Container, len(cgroups))
+	for id, cg := range cgroups {
+		cached, exists := ri.containerCache[id]
+		if !exists {
+			cached = &Container{ID: id, Runtime: cg.Runtime, cgroupPath: cg.Path}
+			ri.resolveContainer(cached)
+			ri.containerCache[id] = cached
+		}
+		// containers known to the container runtime, which have an image, keep
+		// the name the runtime knows them by
+		if name, ok := names[id]; ok && cached.Image == "" {
+			cached.Name = name
+		}
+
+		// CPU time is reset when a cgroup is recreated with the same ID
+		cached.CPUTimeDelta = max(cg.CPUTime-cached.CPUTotalTime, 0)
+		cached.CPUTotalTime = cg.CPUTime
+		containersRunning[id] = cached
+	}
+
+	containersTerminated := make(map[string]*Container)
+	for id, container := range ri.containerCache {
+		if _, isRunning := containersRunning[id]; !isRunning {
+			containersTerminated[id] = container
+			delete(ri.containerCache, id)
+		}
+	}
+
+	ri.containers.Running = containersRunning
+	ri.containers.Terminated = containersTerminated
+
+	return nil
+}
+
 func (ri *resourceInformer) refreshVMs(vmProcs []*Process) error {
 	vmsRunning := make(map[string]*VirtualMachine)
 
@@ -272,8 +710,50 @@ func (ri *resourceInformer) refreshVMs(vmProcs []*Process) error {
 	return nil
 }
 
+// refreshSystemdUnits groups the running processes by the systemd unit they run in
+func (ri *resourceInformer) refreshSystemdUnits() error {
+	if !ri.trackSystemdUnits {
+		return nil
+	}
+
+	unitsRunning := make(map[string]*SystemdUnit)
+	for _, proc := range ri.processes.Running {
+		u := proc.SystemdUnit
+		if u == nil {
+			continue
+		}
+
+		cached, exists := ri.unitCache[u.Name]
+		if !exists {
+			cached = u.Clone()
+			ri.unitCache[u.Name] = cached
+		}
+		// reset CPU Time of the unit if it is getting added to the running list for the first time
+		if _, seen := unitsRunning[u.Name]; !seen {
+			cached.CPUTimeDelta = 0
+		}
+		cached.CPUTimeDelta += proc.CPUTimeDelta
+		cached.CPUTotalTime += proc.CPUTimeDelta
+		unitsRunning[u.Name] = cached
+	}
+
+	// Find terminated units
+	unitsTerminated := make(map[string]*SystemdUnit)
+	for name, unit := range ri.unitCache {
+		if _, isRunning := unitsRunning[name]; !isRunning {
+			unitsTerminated[name] = unit
+			delete(ri.unitCache, name)
+		}
+	}
+
+	ri.units.Running = unitsRunning
+	ri.units.Terminated = unitsTerminated
+
+	return nil
+}
+
 func (ri *resourceInformer) refreshPods() error {
-	if ri.podInformer == nil {
+	if ri.podInformer == nil && len(ri.runtimes) == 0 {
 		return nil
 	}
 
@@ -282,6 +762,18 @@ func (ri *resourceInformer) refreshPods() error {
 	var refreshErrs error
 
 	for _, container := range ri.containers.Running {
+		// without a pod informer, pods are known from the labels kubelet sets
+		// on the containers it creates
+		if ri.podInformer == nil {
+			if container.Pod == nil {
+				containersNoPod = append(containersNoPod, container.ID)
+				continue
+			}
+			_, seen := podsRunning[container.Pod.ID]
+			podsRunning[container.Pod.ID] = ri.updatePodCache(container, !seen)
+			continue
+		}
+
 		cntrInfo, found, err := ri.podInformer.LookupByContainerID(container.ID)
 		if err != nil {
 			ri.logger.Debug("Failed to get pod for container", "container", container.ID, "error", err)
@@ -295,9 +787,16 @@ func (ri *resourceInformer) refreshPods() error {
 		}
 
 		pod := &Pod{
-			ID:        cntrInfo.PodID,
-			Name:      cntrInfo.PodName,
-			Namespace: cntrInfo.Namespace,
+			ID:         cntrInfo.PodID,
+			Name:       cntrInfo.PodName,
+			Namespace:  cntrInfo.Namespace,
+			CPURequest: cntrInfo.PodCPURequest,
+			CPULimit:   cntrInfo.PodCPULimit,
+		}
+		if ri.podMetadata {
+			pod.Labels = cntrInfo.PodLabels
+			pod.OwnerKind = cntrInfo.OwnerKind
+			pod.OwnerName = cntrInfo.OwnerName
 		}
 		container.Pod = pod
 		container.Name = cntrInfo.ContainerName
@@ -326,10 +825,43 @@ func (ri *resourceInformer) refreshPods() error {
 }
 
 func (ri *resourceInformer) refreshNode() error {
-	// Calculate total CPU delta from all running processes
+	// Calculate total CPU delta from all running processes and the processes
+	// that terminated since the last refresh, or from the root cgroup
 	procCPUDeltaTotal := float64(0)
-	for _, proc := range ri.processes.Running {
-		procCPUDeltaTotal += proc.CPUTimeDelta
+	if ri.cgroupRoot != "" {
+		cpuTime, err := readCgroupCPUTime(ri.cgroupRoot)
+		if err != nil {
+			return fmt.Errorf("failed to get root cgroup cpu time: %w", err)
+		}
+		procCPUDeltaTotal = max(cpuTime-ri.cgroupCPUTime, 0)
+		ri.cgroupCPUTime = cpuTime
+	} else {
+		for _, proc := range ri.processes.Running {
+			procCPUDeltaTotal += proc.CPUTimeDelta
+		}
+		for _, proc := range ri.processes.Terminated {
+			procCPUDeltaTotal += proc.CPUTimeDelta
+		}
+	}
+
+	if ri.trackAggregates {
+		if err := ri.refreshAggregates(procCPUDeltaTotal); err != nil {
+			return fmt.Errorf("failed to refresh aggregates: %w", err)
+		}
+		// the root cgroup already accounts for the CPU time of no process
+		if ri.cgroupRoot == "" {
+			procCPUDeltaTotal += ri.node.Aggregates[SystemAggregate].CPUTimeDelta
+		}
+	}
+
+	var io IOCounters
+	if ri.trackIO {
+		for _, proc := range ri.processes.Running {
+			io = io.Add(proc.IODelta)
+		}
+		for _, proc := range ri.processes.Terminated {
+			io = io.Add(proc.IODelta)
+		}
 	}
 
 	// Get current CPU usage ratio
@@ -338,8 +870,17 @@ func (ri *resourceInformer) refreshNode() error {
 		return fmt.Errorf("failed to get procfs usage: %w", err)
 	}
 
+	var coreTypeUsage map[string]float64
+	if len(ri.coreTypes) > 0 {
+		if coreTypeUsage, err = ri.coreTypeUsageRatios(); err != nil {
+			return fmt.Errorf("failed to get core type usage: %w", err)
+		}
+	}
+
 	ri.node.ProcessTotalCPUTimeDelta = procCPUDeltaTotal
 	ri.node.CPUUsageRatio = usage
+	ri.node.CoreTypeUsageRatios = coreTypeUsage
+	ri.node.IODelta = io
 
 	return nil
 }
@@ -349,29 +890,50 @@ func (ri *resourceInformer) refreshNode() error {
 func (ri *resourceInformer) Refresh() error {
 	started := ri.clock.Now()
 
+	ri.filterMu.Lock()
+	if ri.filterChanged {
+		ri.filter, ri.filterChanged = ri.nextFilter, false
+	}
+	ri.filterMu.Unlock()
+	ri.applyPendingGroups()
+
 	// Refresh workloads in dependency order:
 	// processes -> {
 	//   -> containers -> pod
 	//   -> VMs
+	//   -> systemd units
+	//   -> groups
 	//   -> node
 	// }
 	var refreshErrs error
 
-	containerProcs, vmProcs, err := ri.refreshProcesses()
-	if err != nil {
-		refreshErrs = errors.Join(refreshErrs, err)
+	// with cgroup CPU accounting, processes are only read if requested
+	var containerProcs, vmProcs []*Process
+	if ri.trackProcesses {
+		var err error
+		containerProcs, vmProcs, err = ri.refreshProcesses()
+		if err != nil {
+			refreshErrs = errors.Join(refreshErrs, err)
+		}
 	}
 
 	// refresh containers and VMs in parallel
 	// Note: No locking needed on ri fields since refreshContainers() and refreshVMs()
 	// operate on completely disjoint data structures (containers vs VMs)
 	wg := sync.WaitGroup{}
-	wg.Add(3)
+	wg.Add(5)
 
-	var cntrErrs, podErrs, vmErrs, nodeErrs error
+	var cntrErrs, podErrs, vmErrs, unitErrs, groupErrs, nodeErrs error
 	go func() {
 		defer wg.Done()
-		cntrErrs = ri.refreshContainers(containerProcs)
+		if ri.cgroupRoot != "" {
+			cntrErrs = ri.refreshContainerCgroups(containerProcs)
+		} else {
+			cntrErrs = ri.refreshContainers(containerProcs)
+		}
+		if ri.cpusetRoot != "" {
+			ri.refreshContainerCPUSets()
+		}
 		podErrs = ri.refreshPods()
 	}()
 
@@ -380,6 +942,16 @@ func (ri *resourceInformer) Refresh() error {
 		vmErrs = ri.refreshVMs(vmProcs)
 	}()
 
+	go func() {
+		defer wg.Done()
+		unitErrs = ri.refreshSystemdUnits()
+	}()
+
+	go func() {
+		defer wg.Done()
+		groupErrs = ri.refreshGroups()
+	}()
+
 	go func() {
 		defer wg.Done()
 		nodeErrs = ri.refreshNode()
@@ -387,7 +959,17 @@ func (ri *resourceInformer) Refresh() error {
 
 	wg.Wait()
 
-	refreshErrs = errors.Join(refreshErrs, cntrErrs, podErrs, vmErrs, nodeErrs)
+	refreshErrs = errors.Join(refreshErrs, cntrErrs, podErrs, vmErrs, unitErrs, groupErrs, nodeErrs)
+
+	ri.healthMu.Lock()
+	ri.nodeErr = nodeErrs
+	ri.healthMu.Unlock()
+
+	if ri.filter != nil {
+		ri.applyFilter()
+	} else {
+		ri.processes.Filtered, ri.containers.Filtered, ri.pods.Filtered = nil, nil, nil
+	}
 
 	// Update timing
 	now := ri.clock.Now()
@@ -397,6 +979,7 @@ func (ri *resourceInformer) Refresh() error {
 	ri.logger.Debug("Resource information collected",
 		"process.running", len(ri.processes.Running),
 		"process.terminated", len(ri.processes.Terminated),
+		"process.filtered", len(ri.processes.Filtered),
 		"container.running", len(ri.containers.Running),
 		"container.terminated", len(ri.containers.Terminated),
 		"vm.running", len(ri.vms.Running),
@@ -404,11 +987,35 @@ func (ri *resourceInformer) Refresh() error {
 		"pod.running", len(ri.pods.Running),
 		"pod.terminated", len(ri.pods.Terminated),
 		"container.no-pod", len(ri.pods.ContainersNoPod),
+		"systemd-unit.running", len(ri.units.Running),
+		"systemd-unit.terminated", len(ri.units.Terminated),
+		"group.running", len(ri.groups.Running),
+		"group.terminated", len(ri.groups.Terminated),
 		"duration", duration)
 
 	return refreshErrs
 }
 
+// SetFilter changes the filter of the workloads reported from the next
+// refresh; nil reports all workloads. It is safe to call concurrently with
+// Refresh.
+func (ri *resourceInformer) SetFilter(f *Filter) {
+	ri.filterMu.Lock()
+	defer ri.filterMu.Unlock()
+	ri.nextFilter, ri.filterChanged = f, true
+}
+
+// Health returns the error reading the node in the last refresh, without
+// which power cannot be attributed to workloads
+func (ri *resourceInformer) Health() error {
+	ri.healthMu.Lock()
+	defer ri.healthMu.Unlock()
+	if ri.nodeErr != nil {
+		return fmt.Errorf("failed to read node usage: %w", ri.nodeErr)
+	}
+	return nil
+}
+
 func (ri *resourceInformer) Node() *Node {
 	return ri.node
 }
@@ -429,6 +1036,14 @@ func (ri *resourceInformer) Pods() *Pods {
 	return ri.pods
 }
 
+func (ri *resourceInformer) SystemdUnits() *SystemdUnits {
+	return ri.units
+}
+
+func (ri *resourceInformer) Groups() *Groups {
+	return ri.groups
+}
+
 // Add VM cache update method
 func (ri *resourceInformer) updateVMCache(proc *Process) *VirtualMachine {
 	vm := proc.VirtualMachine
@@ -448,22 +1063,184 @@ func (ri *resourceInformer) updateVMCache(proc *Process) *VirtualMachine {
 	return cached
 }
 
-// updateProcessCache updates the process cache with the latest information and returns the updated process
-func (ri *resourceInformer) updateProcessCache(proc procInfo) (*Process, error) {
+// updateProcess reads the latest information of a process and returns the
+// cached process updated with it, or a new process if it isn't cached. It
+// doesn't modify the process cache so that processes can be read in parallel.
+func (ri *resourceInformer) updateProcess(proc procInfo) (*Process, bool, error) {
 	pid := proc.PID()
 
 	if cached, exists := ri.procCache[pid]; exists {
-		err := populateProcessFields(cached, proc)
-		return cached, err
+		comm := cached.Comm
+		if err := populateProcessFields(cached, proc, ri.vmDetector); err != nil {
+			return cached, false, err
+		}
+		// the command line changes when the process executes a new program
+		if ri.processMetadata && cached.Comm != comm {
+			if err := ri.populateMetadata(cached, proc); err != nil {
+				return cached, false, err
+			}
+		}
+		if err := ri.populateOptionalFields(cached, proc); err != nil {
+			return cached, false, err
+		}
+		if ri.hwCounters != nil {
+			ri.readHWCounters(cached)
+		}
+		// processes seen before the filter had cgroup rules or a group of a
+		// cgroup was defined
+		if ri.readsCgroupPaths() && cached.cgroupPaths == nil {
+			paths, err := cgroupPaths(proc)
+			if err != nil {
+				return cached, false, err
+			}
+			cached.cgroupPaths = paths
+		}
+		return cached, false, nil
 	}
 
-	newProc, err := newProcess(proc)
+	newProc, err := newProcess(proc, ri.vmDetector)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	if err := ri.populateOptionalFields(newProc, proc); err != nil {
+		return nil, false, err
+	}
+	if ri.processMetadata {
+		if err := ri.populateMetadata(newProc, proc); err != nil {
+			return nil, false, err
+		}
 	}
+	// children that exited before the process was first seen are not of interest
+	newProc.ChildrenCPUTimeDelta = 0
 
-	ri.procCache[pid] = newProc
-	return newProc, nil
+	if ri.trackAggregates {
+		kthread, err := proc.KernelThread()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get process flags: %w", err)
+		}
+		newProc.KernelThread = kthread
+	}
+
+	// processes are moved into their cgroup before they run, so the cgroups
+	// are read only once
+	if ri.readsCgroupPaths() {
+		paths, err := cgroupPaths(proc)
+		if err != nil {
+			return nil, false, err
+		}
+		newProc.cgroupPaths = paths
+	}
+
+	// systemd moves processes into the cgroup of their unit before they run,
+	// so the unit is read only once
+	if ri.trackSystemdUnits {
+		unit, err := systemdUnitFromProc(proc)
+		if err != nil {
+			return nil, false, err
+		}
+		newProc.SystemdUnit = unit
+	}
+
+	// counters are opened last so that they are only opened for processes
+	// that are cached, and released once they exit
+	if ri.hwCounters != nil {
+		ri.readHWCounters(newProc)
+	}
+
+	return newProc, true, nil
+}
+
+// populateOptionalFields updates the fields of the process that are read only if
+// their tracking is enabled
+func (ri *resourceInformer) populateOptionalFields(p *Process, proc procInfo) error {
+	if ri.trackMemory {
+		rss, err := proc.ResidentMemory()
+		if err != nil {
+			return fmt.Errorf("failed to get process resident memory: %w", err)
+		}
+		p.ResidentMemory = rss
+	}
+
+	if ri.trackLastCPU {
+		cpu, err := proc.LastCPU()
+		if err != nil {
+			return fmt.Errorf("failed to get process last cpu: %w", err)
+		}
+		p.LastCPU = cpu
+	}
+
+	if ri.trackExited || ri.processMetadata || len(ri.groupSpecs) > 0 {
+		ppid, err := proc.ParentPID()
+		if err != nil {
+			return fmt.Errorf("failed to get process parent pid: %w", err)
+		}
+		p.ParentPID = ppid
+	}
+
+	if ri.trackIO {
+		if err := ri.readIO(p, proc); err != nil {
+			return err
+		}
+	}
+
+	if ri.trackExited {
+		children, err := proc.ChildrenCPUTime()
+		if err != nil {
+			return fmt.Errorf("failed to get process children cpu time: %w", err)
+		}
+		p.ChildrenCPUTimeDelta = children - p.ChildrenCPUTime
+		p.ChildrenCPUTime = children
+	}
+
+	return nil
+}
+
+// estimateExitedCPUTime sets the CPU time delta of terminated processes to the
+// CPU time they used between the last refresh and their exit.
+//
+// The kernel adds the CPU time of a process to the children CPU time of its
+// parent when the process is waited for. The increase in children CPU time of a
+// running parent, less the CPU time its terminated children were last seen with,
+// is the CPU time used by those children since the last refresh and by children
+// that started and exited between two refreshes. It is shared among the
+// terminated children in proportion to their last CPU time delta, and is
+// charged to the parent when none of its terminated children were seen.
+func estimateExitedCPUTime(running, terminated map[int]*Process) {
+	exited := make(map[int][]*Process)
+	lastDelta := make(map[*Process]float64, len(terminated))
+	for _, proc := range terminated {
+		lastDelta[proc] = proc.CPUTimeDelta
+		proc.CPUTimeDelta = 0
+		if _, ok := running[proc.ParentPID]; ok {
+			exited[proc.ParentPID] = append(exited[proc.ParentPID], proc)
+		}
+	}
+
+	for ppid, parent := range running {
+		unaccounted := parent.ChildrenCPUTimeDelta
+		children := exited[ppid]
+		weights := 0.0
+		for _, child := range children {
+			unaccounted -= child.CPUTotalTime
+			weights += lastDelta[child]
+		}
+		if unaccounted <= 1e-12 {
+			continue
+		}
+
+		if len(children) == 0 {
+			parent.CPUTimeDelta += unaccounted
+			continue
+		}
+
+		for _, child := range children {
+			share := 1 / float64(len(children))
+			if weights > 0 {
+				share = lastDelta[child] / weights
+			}
+			child.CPUTimeDelta = unaccounted * share
+		}
+	}
 }
 
 func (ri *resourceInformer) updateContainerCache(proc *Process, resetCPUTime bool) *Container {
@@ -475,6 +1252,7 @@ func (ri *resourceInformer) updateContainerCache(proc *Process, resetCPUTime boo
 	cached, exists := ri.containerCache[c.ID]
 	if !exists {
 		cached = c.Clone()
+		ri.resolveContainer(cached)
 		ri.containerCache[c.ID] = cached
 	}
 
@@ -502,6 +1280,11 @@ func (ri *resourceInformer) updatePodCache(container *Container, resetCPUTime bo
 	if resetCPUTime {
 		cached.CPUTimeDelta = 0
 	}
+	// requests and limits can be resized in-place
+	cached.CPURequest = p.CPURequest
+	cached.CPULimit = p.CPULimit
+	// labels can be updated
+	cached.Labels = p.Labels
 
 	cached.CPUTimeDelta += container.CPUTimeDelta
 	cached.CPUTotalTime += container.CPUTotalTime
@@ -509,7 +1292,7 @@ func (ri *resourceInformer) updatePodCache(container *Container, resetCPUTime bo
 	return cached
 }
 
-func populateProcessFields(p *Process, proc procInfo) error {
+func populateProcessFields(p *Process, proc procInfo, vms *vmDetector) error {
 	cpuTotalTime, err := proc.CPUTime()
 	if err != nil {
 		return err
@@ -538,7 +1321,7 @@ func populateProcessFields(p *Process, proc procInfo) error {
 
 	// Determine process type and associated container/VM only if not already set
 	if p.Type == UnknownProcess || commChanged {
-		info, err := computeTypeInfoFromProc(proc)
+		info, err := computeTypeInfoFromProc(proc, vms)
 		if err != nil {
 			return fmt.Errorf("failed to detect process type: %w", err)
 		}
@@ -557,7 +1340,7 @@ type ProcessTypeInfo struct {
 	VM        *VirtualMachine
 }
 
-func computeTypeInfoFromProc(proc procInfo) (*ProcessTypeInfo, error) {
+func computeTypeInfoFromProc(proc procInfo, vms *vmDetector) (*ProcessTypeInfo, error) {
 	// detect process type in parallel
 	type result struct {
 		container *Container
@@ -577,7 +1360,7 @@ func computeTypeInfoFromProc(proc procInfo) (*ProcessTypeInfo, error) {
 
 	go func() {
 		defer close(vmCh)
-		vm, err := vmInfoFromProc(proc)
+		vm, err := vms.detect(proc)
 		vmCh <- result{vm: vm, err: err}
 	}()
 
@@ -601,12 +1384,12 @@ func computeTypeInfoFromProc(proc procInfo) (*ProcessTypeInfo, error) {
 }
 
 // newProcess creates a new Process with static information filled in
-func newProcess(proc procInfo) (*Process, error) {
+func newProcess(proc procInfo, vms *vmDetector) (*Process, error) {
 	p := &Process{
 		PID: proc.PID(),
 	}
 
-	if err := populateProcessFields(p, proc); err != nil {
+	if err := populateProcessFields(p, proc, vms); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import "fmt"
+
+// bpfNetBytes reads the bytes processes received and sent over IPv4 and IPv6
+// sockets, counted by eBPF programs, keyed by PID (thread group ID)
+type bpfNetBytes interface {
+	// Load loads and attaches the eBPF programs
+	Load() error
+
+	// Lookup returns the bytes received and sent by the process since the
+	// programs were attached; 0 if the process didn't use the network since
+	Lookup(pid int) (rx, tx uint64, err error)
+
+	// PIDs returns the processes with counted bytes
+	PIDs() ([]int, error)
+
+	// Delete removes the bytes of a process
+	Delete(pid int) error
+
+	// Close detaches the eBPF programs
+	Close() error
+}
+
+// readIO updates the bytes transferred by the process; disk bytes are read
+// from procfs and network bytes from eBPF, if the programs are loaded
+func (ri *resourceInformer) readIO(p *Process, proc procInfo) error {
+	var io IOCounters
+	var err error
+	io.DiskReadBytes, io.DiskWriteBytes, err = proc.DiskIO()
+	if err != nil {
+		return fmt.Errorf("failed to get process disk io: %w", err)
+	}
+
+	if ri.netBytes != nil {
+		io.NetRxBytes, io.NetTxBytes, err = ri.netBytes.Lookup(p.PID)
+		if err != nil {
+			return fmt.Errorf("failed to get process network bytes: %w", err)
+		}
+	}
+
+	p.IODelta = io.Sub(p.IO)
+	p.IO = io
+	return nil
+}
+
+// releaseIO sets the bytes transferred by terminated processes since the last
+// refresh to the network bytes counted until they exited, and releases the
+// network bytes of the processes that aren't running. The disk bytes of exited
+// processes can't be read.
+func (ri *resourceInformer) releaseIO(running, terminated map[int]*Process) {
+	for pid, p := range terminated {
+		p.IODelta = IOCounters{}
+		if ri.netBytes == nil {
+			continue
+		}
+
+		rx, tx, err := ri.netBytes.Lookup(pid)
+		if err != nil {
+			ri.logger.Debug("Failed to get network bytes of exited process", "pid", pid, "error", err)
+			continue
+		}
+		io := p.IO
+		io.NetRxBytes, io.NetTxBytes = rx, tx
+		p.IODelta = io.Sub(p.IO)
+		p.IO = p.IO.Add(p.IODelta)
+	}
+
+	if ri.netBytes != nil {
+		if err := retainPIDs(ri.netBytes, running); err != nil {
+			ri.logger.Debug("Failed to release the network bytes of exited processes", "error", err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNetBytes returns the bytes it holds
+type fakeNetBytes struct {
+	bytes map[int][2]uint64 // rx, tx by PID
+}
+
+func (f *fakeNetBytes) Load() error { return nil }
+
+func (f *fakeNetBytes) Lookup(pid int) (uint64, uint64, error) {
+	b := f.bytes[pid]
+	return b[0], b[1], nil
+}
+
+func (f *fakeNetBytes) PIDs() ([]int, error) {
+	pids := make([]int, 0, len(f.bytes))
+	for pid := range f.bytes {
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+func (f *fakeNetBytes) Delete(pid int) error {
+	delete(f.bytes, pid)
+	return nil
+}
+
+func (f *fakeNetBytes) Close() error { return nil }
+
+func TestIOCounters(t *testing.T) {
+	c := IOCounters{DiskReadBytes: 100, DiskWriteBytes: 50, NetRxBytes: 20, NetTxBytes: 10}
+	assert.Equal(t, uint64(150), c.Disk())
+	assert.Equal(t, uint64(30), c.Network())
+	assert.Equal(t, IOCounters{DiskReadBytes: 60, DiskWriteBytes: 50, NetRxBytes: 20, NetTxBytes: 0},
+		c.Sub(IOCounters{DiskReadBytes: 40, NetTxBytes: 15}), "network bytes of a reused PID")
+	assert.Equal(t, IOCounters{DiskReadBytes: 140, DiskWriteBytes: 50, NetRxBytes: 20, NetTxBytes: 15},
+		c.Add(IOCounters{DiskReadBytes: 40, NetTxBytes: 5}))
+}
+
+func TestRefresh_IO(t *testing.T) {
+	newMockProc := func(pid int, read, write uint64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return("worker", nil)
+		mockProc.On("Executable").Return("/bin/worker", nil)
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/system.slice/worker.service"}}, nil)
+		mockProc.On("CmdLine").Return([]string{"/bin/worker"}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(1.0, nil)
+		mockProc.On("DiskIO").Return(read, write, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1, 4096, 8192), newMockProc(2, 0, 0)}, nil).Once()
+
+	informer, err := NewInformer(WithProcReader(mockReader), WithIOTracking(true))
+	require.NoError(t, err)
+	netBytes := &fakeNetBytes{bytes: map[int][2]uint64{
+		1: {1000, 500},
+		3: {10, 10}, // exited before it was seen
+	}}
+	informer.netBytes = netBytes
+
+	require.NoError(t, informer.Refresh())
+	running := informer.Processes().Running
+	require.Len(t, running, 2)
+	assert.Equal(t, IOCounters{DiskReadBytes: 4096, DiskWriteBytes: 8192, NetRxBytes: 1000, NetTxBytes: 500}, running[1].IODelta)
+	assert.Zero(t, running[2].IO)
+	assert.Equal(t, uint64(12288), informer.Node().IODelta.Disk())
+	assert.NotContains(t, netBytes.bytes, 3, "bytes of processes that aren't running are released")
+
+	netBytes.bytes[1] = [2]uint64{1500, 700}
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1, 4096, 12288), newMockProc(2, 0, 0)}, nil).Once()
+	require.NoError(t, informer.Refresh())
+	proc := informer.Processes().Running[1]
+	assert.Equal(t, IOCounters{DiskWriteBytes: 4096, NetRxBytes: 500, NetTxBytes: 200}, proc.IODelta)
+	assert.Equal(t, IOCounters{DiskReadBytes: 4096, DiskWriteBytes: 12288, NetRxBytes: 1500, NetTxBytes: 700}, proc.IO)
+	assert.Equal(t, IOCounters{DiskWriteBytes: 4096, NetRxBytes: 500, NetTxBytes: 200}, informer.Node().IODelta)
+
+	// network bytes counted until exit are added to terminated processes
+	netBytes.bytes[1] = [2]uint64{1600, 700}
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(2, 0, 0)}, nil).Once()
+	require.NoError(t, informer.Refresh())
+	terminated := informer.Processes().Terminated[1]
+	require.NotNil(t, terminated)
+	assert.Equal(t, IOCounters{NetRxBytes: 100}, terminated.IODelta)
+	assert.Equal(t, uint64(1600), terminated.IO.NetRxBytes)
+	assert.NotContains(t, netBytes.bytes, 1)
+}
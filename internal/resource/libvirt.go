@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// libvirtDomain holds the identity of a running libvirt domain
+type libvirtDomain struct {
+	Name  string `xml:"name"`
+	UUID  string `xml:"uuid"`
+	Title string `xml:"title"`
+
+	// Metadata holds the OpenStack (nova) display name of instances
+	Metadata struct {
+		Instance struct {
+			Name string `xml:"name"`
+		} `xml:"instance"`
+	} `xml:"metadata"`
+}
+
+// displayName returns the name users know the domain by: the OpenStack instance
+// name, the title or the name of the domain, in that order
+func (d *libvirtDomain) displayName() string {
+	for _, name := range []string{d.Metadata.Instance.Name, d.Title, d.Name} {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// libvirtDomainStatus is the status file libvirt keeps for each running domain
+type libvirtDomainStatus struct {
+	PID    int           `xml:"pid,attr"`
+	Domain libvirtDomain `xml:"domain"`
+}
+
+// lookupLibvirtDomain returns the domain named name from the status files in
+// dir, or nil if the domain isn't run by the process with the given pid
+func lookupLibvirtDomain(dir, name string, pid int) (*libvirtDomain, error) {
+	if name == "" || strings.ContainsRune(name, filepath.Separator) {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name+".xml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var status libvirtDomainStatus
+	if err := xml.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse libvirt domain status of %s: %w", name, err)
+	}
+
+	// the status file may be stale or the name reused by another process
+	if status.PID != pid {
+		return nil, nil
+	}
+	return &status.Domain, nil
+}
@@ -52,6 +52,41 @@ func (m *MockProcInfo) CPUTime() (float64, error) {
 	return args.Get(0).(float64), args.Error(1)
 }
 
+func (m *MockProcInfo) ResidentMemory() (uint64, error) {
+	args := m.Called()
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockProcInfo) LastCPU() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProcInfo) ParentPID() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProcInfo) KernelThread() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProcInfo) UID() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProcInfo) ChildrenCPUTime() (float64, error) {
+	args := m.Called()
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockProcInfo) DiskIO() (uint64, uint64, error) {
+	args := m.Called()
+	return args.Get(0).(uint64), args.Get(1).(uint64), args.Error(2)
+}
+
 // MockProcReader is a mock implementation of procInformer for testing
 type MockProcReader struct {
 	mock.Mock
@@ -62,11 +97,24 @@ func (m *MockProcReader) AllProcs() ([]procInfo, error) {
 	return args.Get(0).([]procInfo), args.Error(1)
 }
 
+func (m *MockProcReader) Proc(pid int) (procInfo, error) {
+	args := m.Called(pid)
+	if proc, ok := args.Get(0).(procInfo); ok {
+		return proc, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockProcReader) CPUUsageRatio() (float64, error) {
 	args := m.Called()
 	return args.Get(0).(float64), args.Error(1)
 }
 
+func (m *MockProcReader) ActiveCPUTime() (float64, error) {
+	args := m.Called()
+	return args.Get(0).(float64), args.Error(1)
+}
+
 func mockContainerIDAndPath(rt ContainerRuntime) (string, string) {
 	containerPaths := map[ContainerRuntime]string{
 		DockerRuntime:     "/docker/<id>",
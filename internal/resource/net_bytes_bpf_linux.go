@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && (amd64 || arm64)
+
+package resource
+
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -tags linux -target bpfel -output-stem net_bytes netBytes bpf/net_bytes.c -- -I./bpf/include
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/rlimit"
+)
+
+// bpfSockLength counts the bytes processes send and receive over sockets
+// with the eBPF programs of bpf/net_bytes.c attached to the sock_send_length
+// and sock_recv_length tracepoints (Linux 6.3), which requires CAP_BPF and
+// CAP_PERFMON (or CAP_SYS_ADMIN)
+type bpfSockLength struct {
+	objs  netBytesObjects
+	links []link.Link
+}
+
+var _ bpfNetBytes = (*bpfSockLength)(nil)
+
+func newBPFNetBytes() bpfNetBytes {
+	return &bpfSockLength{}
+}
+
+func (b *bpfSockLength) Load() error {
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return fmt.Errorf("failed to remove the memlock limit of eBPF maps: %w", err)
+	}
+
+	// the offset of the family of sockets is relocated with the BTF of the
+	// running kernel
+	if err := loadNetBytesObjects(&b.objs, nil); err != nil {
+		return fmt.Errorf("failed to load eBPF programs: %w", err)
+	}
+
+	tracepoints := []link.RawTracepointOptions{
+		{Name: "sock_recv_length", Program: b.objs.SockRecvLength},
+		{Name: "sock_send_length", Program: b.objs.SockSendLength},
+	}
+	for _, tp := range tracepoints {
+		l, err := link.AttachRawTracepoint(tp)
+		if err != nil {
+			_ = b.Close()
+			return fmt.Errorf("failed to attach eBPF program to %s: %w", tp.Name, err)
+		}
+		b.links = append(b.links, l)
+	}
+	return nil
+}
+
+func (b *bpfSockLength) Lookup(pid int) (uint64, uint64, error) {
+	var value netBytesNetBytes
+	err := b.objs.Bytes.Lookup(uint32(pid), &value)
+	if errors.Is(err, ebpf.ErrKeyNotExist) {
+		return 0, 0, nil
+	}
+	return value.Rx, value.Tx, err
+}
+
+func (b *bpfSockLength) PIDs() ([]int, error) {
+	return bpfMapPIDs(b.objs.Bytes)
+}
+
+func (b *bpfSockLength) Delete(pid int) error {
+	return bpfMapDelete(b.objs.Bytes, pid)
+}
+
+func (b *bpfSockLength) Close() error {
+	var errs error
+	// closing the links detaches the programs
+	for _, l := range b.links {
+		errs = errors.Join(errs, l.Close())
+	}
+	b.links = nil
+	errs = errors.Join(errs, b.objs.Close())
+	b.objs = netBytesObjects{}
+	return errs
+}
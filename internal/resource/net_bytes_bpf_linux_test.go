@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && (amd64 || arm64)
+
+package resource
+
+import (
+	"io"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestBPFSockLength(t *testing.T) {
+	netBytes := newBPFNetBytes()
+	if err := netBytes.Load(); err != nil {
+		t.Skipf("eBPF programs can't be loaded: %v", err)
+	}
+	t.Cleanup(func() { _ = netBytes.Close() })
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	received := make(chan int64)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			received <- 0
+			return
+		}
+		defer conn.Close()
+		n, _ := io.Copy(io.Discard, conn)
+		received <- n
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write(make([]byte, 4096))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	require.Equal(t, int64(4096), <-received)
+
+	pid := os.Getpid()
+	rx, tx, err := netBytes.Lookup(pid)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4096), tx)
+	assert.Equal(t, uint64(4096), rx, "both ends of the connection are in the test process")
+
+	// unix sockets don't go through the network
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM, 0)
+	require.NoError(t, err)
+	defer unix.Close(fds[0])
+	defer unix.Close(fds[1])
+	require.NoError(t, unix.Sendmsg(fds[0], make([]byte, 1024), nil, nil, 0))
+	_, tx, err = netBytes.Lookup(pid)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4096), tx)
+
+	pids, err := netBytes.PIDs()
+	require.NoError(t, err)
+	assert.Contains(t, pids, pid)
+
+	require.NoError(t, netBytes.Delete(pid))
+	rx, tx, err = netBytes.Lookup(pid)
+	require.NoError(t, err)
+	assert.Zero(t, rx+tx)
+}
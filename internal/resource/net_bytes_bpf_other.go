@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux || !(amd64 || arm64)
+
+package resource
+
+import "errors"
+
+// bpfSockLength is only supported on linux on amd64 and arm64
+type bpfSockLength struct{}
+
+var _ bpfNetBytes = (*bpfSockLength)(nil)
+
+func newBPFNetBytes() bpfNetBytes {
+	return &bpfSockLength{}
+}
+
+func (b *bpfSockLength) Load() error {
+	return errors.New("eBPF network byte counting is only supported on linux on amd64 and arm64")
+}
+
+func (b *bpfSockLength) Lookup(int) (uint64, uint64, error) {
+	return 0, 0, nil
+}
+
+func (b *bpfSockLength) PIDs() ([]int, error) {
+	return nil, nil
+}
+
+func (b *bpfSockLength) Delete(int) error {
+	return nil
+}
+
+func (b *bpfSockLength) Close() error {
+	return nil
+}
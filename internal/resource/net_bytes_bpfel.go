@@ -0,0 +1,127 @@
+// Code generated by bpf2go; DO NOT EDIT.
+//go:build (386 || amd64 || arm || arm64 || loong64 || mips64le || mipsle || ppc64le || riscv64) && linux
+
+package resource
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/cilium/ebpf"
+)
+
+type netBytesNetBytes struct {
+	Rx uint64
+	Tx uint64
+}
+
+// loadNetBytes returns the embedded CollectionSpec for netBytes.
+func loadNetBytes() (*ebpf.CollectionSpec, error) {
+	reader := bytes.NewReader(_NetBytesBytes)
+	spec, err := ebpf.LoadCollectionSpecFromReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("can't load netBytes: %w", err)
+	}
+
+	return spec, err
+}
+
+// loadNetBytesObjects loads netBytes and converts it into a struct.
+//
+// The following types are suitable as obj argument:
+//
+//	*netBytesObjects
+//	*netBytesPrograms
+//	*netBytesMaps
+//
+// See ebpf.CollectionSpec.LoadAndAssign documentation for details.
+func loadNetBytesObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
+	spec, err := loadNetBytes()
+	if err != nil {
+		return err
+	}
+
+	return spec.LoadAndAssign(obj, opts)
+}
+
+// netBytesSpecs contains maps and programs before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type netBytesSpecs struct {
+	netBytesProgramSpecs
+	netBytesMapSpecs
+}
+
+// netBytesSpecs contains programs before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type netBytesProgramSpecs struct {
+	SockRecvLength *ebpf.ProgramSpec `ebpf:"sock_recv_length"`
+	SockSendLength *ebpf.ProgramSpec `ebpf:"sock_send_length"`
+}
+
+// netBytesMapSpecs contains maps before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type netBytesMapSpecs struct {
+	Bytes *ebpf.MapSpec `ebpf:"bytes"`
+}
+
+// netBytesObjects contains all objects after they have been loaded into the kernel.
+//
+// It can be passed to loadNetBytesObjects or ebpf.CollectionSpec.LoadAndAssign.
+type netBytesObjects struct {
+	netBytesPrograms
+	netBytesMaps
+}
+
+func (o *netBytesObjects) Close() error {
+	return _NetBytesClose(
+		&o.netBytesPrograms,
+		&o.netBytesMaps,
+	)
+}
+
+// netBytesMaps contains all maps after they have been loaded into the kernel.
+//
+// It can be passed to loadNetBytesObjects or ebpf.CollectionSpec.LoadAndAssign.
+type netBytesMaps struct {
+	Bytes *ebpf.Map `ebpf:"bytes"`
+}
+
+func (m *netBytesMaps) Close() error {
+	return _NetBytesClose(
+		m.Bytes,
+	)
+}
+
+// netBytesPrograms contains all programs after they have been loaded into the kernel.
+//
+// It can be passed to loadNetBytesObjects or ebpf.CollectionSpec.LoadAndAssign.
+type netBytesPrograms struct {
+	SockRecvLength *ebpf.Program `ebpf:"sock_recv_length"`
+	SockSendLength *ebpf.Program `ebpf:"sock_send_length"`
+}
+
+func (p *netBytesPrograms) Close() error {
+	return _NetBytesClose(
+		p.SockRecvLength,
+		p.SockSendLength,
+	)
+}
+
+func _NetBytesClose(closers ...io.Closer) error {
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Do not access this directly.
+//
+//go:embed net_bytes_bpfel.o
+var _NetBytesBytes []byte
@@ -6,6 +6,7 @@ package resource
 import (
 	"log/slog"
 	"os"
+	"runtime"
 
 	"github.com/sustainable-computing-io/kepler/internal/k8s/pod"
 	"k8s.io/utils/clock"
@@ -13,11 +14,33 @@ import (
 
 // Options contains all the configuration for the ResourceTracker
 type Options struct {
-	logger      *slog.Logger
-	clock       clock.Clock
-	procFSPath  string
-	procReader  allProcReader
-	podInformer pod.Informer
+	logger       *slog.Logger
+	clock        clock.Clock
+	procFSPath   string
+	procReader   allProcReader
+	podInformer  pod.Informer
+	trackMemory  bool
+	trackLastCPU bool
+	trackExited  bool
+	podMetadata  bool
+	coreTypes    map[int]string
+
+	cgroupRoot      string
+	cpusetRoot      string
+	trackProcesses  bool
+	processEvents   bool
+	ebpfCPUTime     bool
+	perfEvents      bool
+	trackIO         bool
+	libvirtDir      string
+	criEndpoint     string
+	dockerEndpoints []string
+	systemdUnits    bool
+	groups          []GroupSpec
+	processMetadata bool
+	aggregates      bool
+	filter          *Filter
+	scanWorkers     int
 }
 
 // OptionFn is a function that configures the Options
@@ -44,6 +67,166 @@ func WithPodInformer(pi pod.Informer) OptionFn {
 	}
 }
 
+// WithMemoryTracking enables reading the resident memory of processes
+func WithMemoryTracking(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.trackMemory = enabled
+	}
+}
+
+// WithLastCPUTracking enables reading the CPU each process last ran on
+func WithLastCPUTracking(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.trackLastCPU = enabled
+	}
+}
+
+// WithCPUCoreTypes sets the core type of each CPU of a hybrid CPU, which
+// enables reading the CPU usage ratio of each core type
+func WithCPUCoreTypes(coreTypes map[int]string) OptionFn {
+	return func(o *Options) {
+		o.coreTypes = coreTypes
+	}
+}
+
+// WithExitedProcessTracking enables estimating the CPU time used by processes
+// between the last refresh and their exit, including processes that start and
+// exit between two refreshes
+func WithExitedProcessTracking(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.trackExited = enabled
+	}
+}
+
+// WithPodMetadata enables decorating pods with their labels and owner workload
+func WithPodMetadata(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.podMetadata = enabled
+	}
+}
+
+// WithCgroupCPUAccounting reads the CPU time of containers and the node from the
+// cgroup v2 hierarchy mounted at root instead of summing the CPU time of their
+// processes; an empty root disables cgroup CPU accounting
+func WithCgroupCPUAccounting(root string) OptionFn {
+	return func(o *Options) {
+		o.cgroupRoot = root
+	}
+}
+
+// WithContainerCPUSets reads the CPUs containers may run on from their cpuset in
+// the cgroup v2 hierarchy mounted at root; an empty root disables it
+func WithContainerCPUSets(root string) OptionFn {
+	return func(o *Options) {
+		o.cpusetRoot = root
+	}
+}
+
+// WithProcessTracking enables reading processes from procfs. Processes can only
+// be skipped with cgroup CPU accounting, in which case processes and VMs are
+// not reported.
+func WithProcessTracking(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.trackProcesses = enabled
+	}
+}
+
+// WithProcessEvents enables tracking processes through the kernel process events
+// (netlink proc connector) instead of scanning procfs on every refresh. procfs
+// is still scanned on the first refresh and whenever events are lost.
+func WithProcessEvents(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.processEvents = enabled
+	}
+}
+
+// WithEBPFCPUTime enables reading the CPU time of processes from an eBPF
+// program that accounts it on every context switch, which is more precise than
+// the CPU time in procfs; procfs is read if the program can't be loaded
+func WithEBPFCPUTime(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.ebpfCPUTime = enabled
+	}
+}
+
+// WithPerfEvents enables counting the instructions, CPU cycles and last level
+// cache misses of processes with perf events; processes are reported without
+// counts if the CPU has no performance monitoring unit or counting isn't allowed
+func WithPerfEvents(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.perfEvents = enabled
+	}
+}
+
+// WithIOTracking enables reading the bytes processes read from and write to
+// block devices from procfs, and counting the bytes they receive and send over
+// the network with eBPF; processes are reported without network bytes if the
+// eBPF programs can't be loaded
+func WithIOTracking(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.trackIO = enabled
+	}
+}
+
+// WithLibvirtPath sets the directory where libvirt keeps the status of running
+// QEMU domains, used to look up the UUID and name of VMs; empty disables lookups
+func WithLibvirtPath(dir string) OptionFn {
+	return func(o *Options) {
+		o.libvirtDir = dir
+	}
+}
+
+// WithCRIEndpoint sets the CRI socket of the container runtime (containerd or
+// CRI-O), used to look up the name, image, labels and pod of containers; empty
+// disables lookups
+func WithCRIEndpoint(endpoint string) OptionFn {
+	return func(o *Options) {
+		o.criEndpoint = endpoint
+	}
+}
+
+// WithDockerEndpoints sets the Docker Engine API sockets of Docker and Podman,
+// used to look up the name, image and labels of containers; endpoints can be
+// glob patterns to match the sockets of rootless Podman
+func WithDockerEndpoints(endpoints []string) OptionFn {
+	return func(o *Options) {
+		o.dockerEndpoints = endpoints
+	}
+}
+
+// WithSystemdUnits enables grouping processes by the systemd unit and slice
+// they run in, as read from their cgroup
+func WithSystemdUnits(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.systemdUnits = enabled
+	}
+}
+
+// WithGroups sets the groups of processes tracked as workloads; more can be
+// added at runtime with AddGroup
+func WithGroups(specs ...GroupSpec) OptionFn {
+	return func(o *Options) {
+		o.groups = specs
+	}
+}
+
+// WithProcessMetadata enables reading the command line, user and parent PID of
+// processes
+func WithProcessMetadata(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.processMetadata = enabled
+	}
+}
+
+// WithAggregates enables the synthetic kernel and system aggregates that
+// account for the CPU time of kernel threads and the CPU time not used by any
+// process seen, such as interrupts
+func WithAggregates(enabled bool) OptionFn {
+	return func(o *Options) {
+		o.aggregates = enabled
+	}
+}
+
 // WithLogger sets the logger
 func WithLogger(logger *slog.Logger) OptionFn {
 	return func(o *Options) {
@@ -58,11 +241,29 @@ func WithClock(c clock.Clock) OptionFn {
 	}
 }
 
+// WithFilter sets the filter that selects the workloads reported; all
+// workloads are reported if f is nil
+func WithFilter(f *Filter) OptionFn {
+	return func(o *Options) {
+		o.filter = f
+	}
+}
+
+// WithScanWorkers sets the max number of processes read in parallel; processes
+// are read sequentially if n is 1
+func WithScanWorkers(n int) OptionFn {
+	return func(o *Options) {
+		o.scanWorkers = n
+	}
+}
+
 // defaultOptions returns the default options
 func defaultOptions() *Options {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	return &Options{
-		logger: logger,
-		clock:  &clock.RealClock{},
+		logger:         logger,
+		clock:          &clock.RealClock{},
+		trackProcesses: true,
+		scanWorkers:    runtime.GOMAXPROCS(0),
 	}
 }
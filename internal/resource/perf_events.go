@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+// hwCounterReader counts the hardware events of processes with perf events
+type hwCounterReader interface {
+	// Start checks that hardware events can be counted
+	Start() error
+
+	// Read returns the events counted for the process since it was first
+	// read; counting starts on the first read
+	Read(pid int) (HWCounters, error)
+
+	// Release stops counting the events of an exited process and returns the
+	// events counted until it exited
+	Release(pid int) (HWCounters, error)
+
+	// Close stops counting the events of all processes
+	Close() error
+}
+
+// readHWCounters updates the hardware event counts of the process. Processes
+// whose events can't be counted are still reported, without counts.
+func (ri *resourceInformer) readHWCounters(p *Process) {
+	counts, err := ri.hwCounters.Read(p.PID)
+	if err != nil {
+		ri.logger.Debug("Failed to count process hardware events", "pid", p.PID, "error", err)
+		return
+	}
+	p.HWCountersDelta = counts.Sub(p.HWCounters)
+	p.HWCounters = p.HWCounters.Add(p.HWCountersDelta)
+}
+
+// releaseHWCounters stops counting the events of terminated processes and
+// adds the events counted until they exited
+func (ri *resourceInformer) releaseHWCounters(terminated map[int]*Process) {
+	for pid, p := range terminated {
+		counts, err := ri.hwCounters.Release(pid)
+		if err != nil {
+			ri.logger.Debug("Failed to release process hardware events", "pid", pid, "error", err)
+			p.HWCountersDelta = HWCounters{}
+			continue
+		}
+		p.HWCountersDelta = counts.Sub(p.HWCounters)
+		p.HWCounters = p.HWCounters.Add(p.HWCountersDelta)
+	}
+}
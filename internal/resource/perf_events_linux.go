@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package resource
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// perfBitInheritThread is the inherit_thread bit of perf_event_attr (Linux
+// 5.13): counters are inherited by new threads but not by child processes,
+// which are counted on their own
+const perfBitInheritThread = 1 << 35
+
+// perfMaxFDs is the max number of counters open at once; processes are not
+// counted once it is reached
+const perfMaxFDs = 1 << 15
+
+// perfEvent is the type and config of a perf event
+type perfEvent struct {
+	typ    uint32
+	config uint64
+}
+
+// hwEvents are the events counted, in the order of the fields of HWCounters
+var hwEvents = [3]perfEvent{
+	{unix.PERF_TYPE_HARDWARE, unix.PERF_COUNT_HW_INSTRUCTIONS},
+	{unix.PERF_TYPE_HARDWARE, unix.PERF_COUNT_HW_CPU_CYCLES},
+	{unix.PERF_TYPE_HARDWARE, unix.PERF_COUNT_HW_CACHE_MISSES},
+}
+
+// perfCounters counts the events of processes with a counter per event on
+// every thread of the process when first read. Counters are inherited by the
+// threads started after, whose counts are added to the counters of the thread
+// that started them. Counting requires CAP_PERFMON (or CAP_SYS_ADMIN).
+type perfCounters struct {
+	procFSPath string
+	events     [3]perfEvent
+
+	// processes are read in parallel
+	mu    sync.Mutex
+	procs map[int]*perfProcess
+	fds   int // number of counters open
+}
+
+var _ hwCounterReader = (*perfCounters)(nil)
+
+// perfProcess holds the counters of the threads of a process
+type perfProcess struct {
+	threads [][3]int // counters by thread and event
+	err     error    // set if the events of the process can't be counted
+}
+
+func newHWCounterReader(procFSPath string) hwCounterReader {
+	return newPerfCounters(procFSPath, hwEvents)
+}
+
+func newPerfCounters(procFSPath string, events [3]perfEvent) *perfCounters {
+	return &perfCounters{
+		procFSPath: procFSPath,
+		events:     events,
+		procs:      make(map[int]*perfProcess),
+	}
+}
+
+func (pc *perfCounters) Start() error {
+	// count the events of this thread to check they are supported
+	for _, ev := range pc.events {
+		fd, err := openPerfEvent(ev, 0)
+		if err != nil {
+			return fmt.Errorf("failed to count perf event %d/%d: %w", ev.typ, ev.config, err)
+		}
+		_ = unix.Close(fd)
+	}
+	return nil
+}
+
+func (pc *perfCounters) Read(pid int) (HWCounters, error) {
+	pc.mu.Lock()
+	p, ok := pc.procs[pid]
+	pc.mu.Unlock()
+
+	if !ok {
+		p = pc.open(pid)
+		pc.mu.Lock()
+		pc.procs[pid] = p
+		pc.mu.Unlock()
+	}
+	if p.err != nil {
+		return HWCounters{}, p.err
+	}
+	return p.read()
+}
+
+// open opens the counters of every thread of the process
+func (pc *perfCounters) open(pid int) *perfProcess {
+	tasks, err := os.ReadDir(filepath.Join(pc.procFSPath, strconv.Itoa(pid), "task"))
+	if err != nil {
+		return &perfProcess{err: err}
+	}
+
+	pc.mu.Lock()
+	full := pc.fds+len(tasks)*len(pc.events) > perfMaxFDs
+	if !full {
+		pc.fds += len(tasks) * len(pc.events)
+	}
+	pc.mu.Unlock()
+	if full {
+		return &perfProcess{err: errors.New("too many perf event counters open")}
+	}
+
+	p := &perfProcess{threads: make([][3]int, 0, len(tasks))}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		fds, err := pc.openThread(tid)
+		if errors.Is(err, unix.ESRCH) {
+			continue // the thread exited
+		}
+		if err != nil {
+			p.err = err
+			break
+		}
+		p.threads = append(p.threads, fds)
+	}
+
+	// counters are only reserved for the threads counted
+	pc.mu.Lock()
+	pc.fds -= (len(tasks) - len(p.threads)) * len(pc.events)
+	pc.mu.Unlock()
+	if p.err != nil {
+		pc.close(p)
+	}
+	return p
+}
+
+// openThread opens the counters of the events of a thread
+func (pc *perfCounters) openThread(tid int) ([3]int, error) {
+	var fds [3]int
+	for i, ev := range pc.events {
+		fd, err := openPerfEvent(ev, tid)
+		if err != nil {
+			for _, fd := range fds[:i] {
+				_ = unix.Close(fd)
+			}
+			return fds, err
+		}
+		fds[i] = fd
+	}
+	return fds, nil
+}
+
+// openPerfEvent opens a counter of the event for the thread on any CPU; 0
+// counts the calling thread
+func openPerfEvent(ev perfEvent, tid int) (int, error) {
+	attr := unix.PerfEventAttr{
+		Type:        ev.typ,
+		Config:      ev.config,
+		Size:        uint32(unsafe.Sizeof(unix.PerfEventAttr{})),
+		Read_format: unix.PERF_FORMAT_TOTAL_TIME_ENABLED | unix.PERF_FORMAT_TOTAL_TIME_RUNNING,
+		Bits:        unix.PerfBitInherit | perfBitInheritThread | unix.PerfBitExcludeHv,
+	}
+	return unix.PerfEventOpen(&attr, tid, -1, -1, unix.PERF_FLAG_FD_CLOEXEC)
+}
+
+// read returns the sum of the counters of the threads. Counters of threads
+// that exited keep their final count.
+func (p *perfProcess) read() (HWCounters, error) {
+	var counts [3]uint64
+	buf := make([]byte, 24)
+	for _, fds := range p.threads {
+		for i, fd := range fds {
+			if _, err := unix.Read(fd, buf); err != nil {
+				return HWCounters{}, fmt.Errorf("failed to read perf event counter: %w", err)
+			}
+			counts[i] += scaledCount(buf)
+		}
+	}
+	return HWCounters{Instructions: counts[0], Cycles: counts[1], CacheMisses: counts[2]}, nil
+}
+
+// scaledCount returns the count read from a counter, scaled up to the time
+// the counter was enabled when it shared the PMU with other counters
+func scaledCount(buf []byte) uint64 {
+	value := binary.NativeEndian.Uint64(buf[0:])
+	enabled := binary.NativeEndian.Uint64(buf[8:])
+	running := binary.NativeEndian.Uint64(buf[16:])
+	if running == 0 {
+		return 0
+	}
+	if running == enabled {
+		return value
+	}
+	return uint64(float64(value) * float64(enabled) / float64(running))
+}
+
+func (pc *perfCounters) Release(pid int) (HWCounters, error) {
+	pc.mu.Lock()
+	p, ok := pc.procs[pid]
+	delete(pc.procs, pid)
+	pc.mu.Unlock()
+	if !ok {
+		return HWCounters{}, nil
+	}
+	if p.err != nil {
+		return HWCounters{}, p.err
+	}
+
+	counts, err := p.read()
+	pc.close(p)
+	return counts, err
+}
+
+// close closes the counters of the process
+func (pc *perfCounters) close(p *perfProcess) {
+	for _, fds := range p.threads {
+		for _, fd := range fds {
+			_ = unix.Close(fd)
+		}
+	}
+	pc.mu.Lock()
+	pc.fds -= len(p.threads) * len(pc.events)
+	pc.mu.Unlock()
+	p.threads = nil
+}
+
+func (pc *perfCounters) Close() error {
+	pc.mu.Lock()
+	procs := pc.procs
+	pc.procs = make(map[int]*perfProcess)
+	pc.mu.Unlock()
+
+	for _, p := range procs {
+		pc.close(p)
+	}
+	return nil
+}
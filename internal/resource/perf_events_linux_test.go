@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package resource
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestScaledCount(t *testing.T) {
+	buf := func(value, enabled, running uint64) []byte {
+		b := make([]byte, 24)
+		binary.NativeEndian.PutUint64(b[0:], value)
+		binary.NativeEndian.PutUint64(b[8:], enabled)
+		binary.NativeEndian.PutUint64(b[16:], running)
+		return b
+	}
+
+	assert.Equal(t, uint64(100), scaledCount(buf(100, 10, 10)))
+	assert.Equal(t, uint64(400), scaledCount(buf(100, 20, 5)), "multiplexed counter")
+	assert.Equal(t, uint64(0), scaledCount(buf(0, 10, 0)), "counter never scheduled")
+}
+
+func TestPerfCounters(t *testing.T) {
+	// hardware events aren't available in most VMs; software events are
+	// counted the same way
+	pc := newPerfCounters("/proc", [3]perfEvent{
+		{unix.PERF_TYPE_SOFTWARE, unix.PERF_COUNT_SW_TASK_CLOCK},
+		{unix.PERF_TYPE_SOFTWARE, unix.PERF_COUNT_SW_CONTEXT_SWITCHES},
+		{unix.PERF_TYPE_SOFTWARE, unix.PERF_COUNT_SW_PAGE_FAULTS},
+	})
+	if err := pc.Start(); err != nil {
+		t.Skipf("perf events can't be counted: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+
+	pid := os.Getpid()
+	first, err := pc.Read(pid)
+	require.NoError(t, err)
+	assert.Positive(t, pc.fds)
+
+	// use CPU time on threads, some of which may be started after the first read
+	done := make(chan struct{})
+	for range 4 {
+		go func() {
+			deadline := time.Now().Add(20 * time.Millisecond)
+			for time.Now().Before(deadline) {
+			}
+			done <- struct{}{}
+		}()
+	}
+	for range 4 {
+		<-done
+	}
+	time.Sleep(time.Millisecond)
+
+	counts, err := pc.Read(pid)
+	require.NoError(t, err)
+	delta := counts.Sub(first)
+	assert.Greater(t, delta.Instructions, uint64(20*time.Millisecond), "task clock in ns")
+	assert.Positive(t, delta.Cycles, "context switches")
+
+	final, err := pc.Release(pid)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, final.Instructions, counts.Instructions)
+	assert.Zero(t, pc.fds, "counters are closed")
+	assert.Empty(t, pc.procs)
+
+	t.Run("unknown process", func(t *testing.T) {
+		_, err := pc.Read(1 << 30)
+		assert.Error(t, err)
+
+		counts, err := pc.Release(1 << 29)
+		require.NoError(t, err)
+		assert.Zero(t, counts)
+	})
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package resource
+
+import "errors"
+
+// perfCounters is only supported on linux
+type perfCounters struct{}
+
+var _ hwCounterReader = (*perfCounters)(nil)
+
+func newHWCounterReader(string) hwCounterReader {
+	return &perfCounters{}
+}
+
+func (pc *perfCounters) Start() error {
+	return errors.New("perf events are only supported on linux")
+}
+
+func (pc *perfCounters) Read(int) (HWCounters, error) {
+	return HWCounters{}, nil
+}
+
+func (pc *perfCounters) Release(int) (HWCounters, error) {
+	return HWCounters{}, nil
+}
+
+func (pc *perfCounters) Close() error {
+	return nil
+}
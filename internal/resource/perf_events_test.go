@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHWCounters returns the counts it holds
+type fakeHWCounters struct {
+	counts   map[int]HWCounters
+	released []int
+}
+
+func (f *fakeHWCounters) Start() error { return nil }
+
+func (f *fakeHWCounters) Read(pid int) (HWCounters, error) {
+	counts, ok := f.counts[pid]
+	if !ok {
+		return HWCounters{}, errors.New("not counted")
+	}
+	return counts, nil
+}
+
+func (f *fakeHWCounters) Release(pid int) (HWCounters, error) {
+	f.released = append(f.released, pid)
+	return f.counts[pid], nil
+}
+
+func (f *fakeHWCounters) Close() error { return nil }
+
+func TestHWCountersSub(t *testing.T) {
+	c := HWCounters{Instructions: 100, Cycles: 50, CacheMisses: 5}
+	assert.Equal(t, HWCounters{Instructions: 60, Cycles: 30, CacheMisses: 0},
+		c.Sub(HWCounters{Instructions: 40, Cycles: 20, CacheMisses: 6}), "scaled counts that went down")
+	assert.Equal(t, HWCounters{Instructions: 140, Cycles: 70, CacheMisses: 10}, c.Add(HWCounters{Instructions: 40, Cycles: 20, CacheMisses: 5}))
+}
+
+func TestRefresh_HWCounters(t *testing.T) {
+	newMockProc := func(pid int) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return("worker", nil)
+		mockProc.On("Executable").Return("/bin/worker", nil)
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/system.slice/worker.service"}}, nil)
+		mockProc.On("CmdLine").Return([]string{"/bin/worker"}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(1.0, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1), newMockProc(2)}, nil).Once()
+
+	informer, err := NewInformer(WithProcReader(mockReader), WithPerfEvents(true))
+	require.NoError(t, err)
+	counters := &fakeHWCounters{counts: map[int]HWCounters{
+		1: {Instructions: 1000, Cycles: 500, CacheMisses: 10},
+	}}
+	informer.hwCounters = counters
+
+	require.NoError(t, informer.Refresh())
+	running := informer.Processes().Running
+	require.Len(t, running, 2)
+	assert.Equal(t, HWCounters{Instructions: 1000, Cycles: 500, CacheMisses: 10}, running[1].HWCountersDelta)
+	assert.Zero(t, running[2].HWCounters, "processes that can't be counted are reported")
+
+	counters.counts[1] = HWCounters{Instructions: 1500, Cycles: 700, CacheMisses: 12}
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1), newMockProc(2)}, nil).Once()
+	require.NoError(t, informer.Refresh())
+	proc := informer.Processes().Running[1]
+	assert.Equal(t, HWCounters{Instructions: 500, Cycles: 200, CacheMisses: 2}, proc.HWCountersDelta)
+	assert.Equal(t, HWCounters{Instructions: 1500, Cycles: 700, CacheMisses: 12}, proc.HWCounters)
+
+	// events counted until exit are added to terminated processes
+	counters.counts[1] = HWCounters{Instructions: 1600, Cycles: 750, CacheMisses: 12}
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(2)}, nil).Once()
+	require.NoError(t, informer.Refresh())
+	terminated := informer.Processes().Terminated[1]
+	require.NotNil(t, terminated)
+	assert.Equal(t, HWCounters{Instructions: 100, Cycles: 50}, terminated.HWCountersDelta)
+	assert.Equal(t, uint64(1600), terminated.HWCounters.Instructions)
+	assert.Equal(t, []int{1}, counters.released)
+}
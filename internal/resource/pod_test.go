@@ -16,8 +16,13 @@ func TestPodClone(t *testing.T) {
 			ID:           "pod-123",
 			Name:         "test-pod",
 			Namespace:    "default",
+			CPURequest:   0.5,
+			CPULimit:     2,
 			CPUTotalTime: 42.5,
 			CPUTimeDelta: 10.2,
+			Labels:       map[string]string{"app": "web"},
+			OwnerKind:    "Deployment",
+			OwnerName:    "web",
 		}
 
 		clone := original.Clone()
@@ -25,6 +30,11 @@ func TestPodClone(t *testing.T) {
 		assert.Equal(t, original.ID, clone.ID)
 		assert.Equal(t, original.Name, clone.Name)
 		assert.Equal(t, original.Namespace, clone.Namespace)
+		assert.Equal(t, original.CPURequest, clone.CPURequest)
+		assert.Equal(t, original.CPULimit, clone.CPULimit)
+		assert.Equal(t, original.Labels, clone.Labels)
+		assert.Equal(t, original.OwnerKind, clone.OwnerKind)
+		assert.Equal(t, original.OwnerName, clone.OwnerName)
 		// CPU times should not be copied in Clone
 		assert.Equal(t, float64(0), clone.CPUTotalTime)
 		assert.Equal(t, float64(0), clone.CPUTimeDelta)
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package resource
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// procConnector receives process events from the kernel through the netlink
+// proc connector, which requires CAP_NET_ADMIN
+type procConnector struct {
+	fd  int
+	buf []byte
+}
+
+var _ procEventSource = (*procConnector)(nil)
+
+func newProcConnector() *procConnector {
+	return &procConnector{fd: -1, buf: make([]byte, os.Getpagesize())}
+}
+
+func (pc *procConnector) Start() error {
+	fd, err := syscall.Socket(syscall.AF_NETLINK,
+		syscall.SOCK_DGRAM|syscall.SOCK_NONBLOCK|syscall.SOCK_CLOEXEC, syscall.NETLINK_CONNECTOR)
+	if err != nil {
+		return fmt.Errorf("failed to create netlink socket: %w", err)
+	}
+
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		_ = syscall.Close(fd)
+		return fmt.Errorf("failed to bind netlink socket: %w", err)
+	}
+	pc.fd = fd
+
+	if err := pc.send(procCnMcastListen); err != nil {
+		_ = pc.Close()
+		return fmt.Errorf("failed to subscribe to process events: %w", err)
+	}
+	return nil
+}
+
+// send sends a proc connector multicast operation to the kernel
+func (pc *procConnector) send(op uint32) error {
+	payload := connectorMsg(op)
+	msg := make([]byte, syscall.NLMSG_HDRLEN+len(payload))
+
+	// struct nlmsghdr: len, type, flags, seq and pid of the sender
+	binary.NativeEndian.PutUint32(msg[0:], uint32(len(msg)))
+	binary.NativeEndian.PutUint16(msg[4:], syscall.NLMSG_DONE)
+	binary.NativeEndian.PutUint32(msg[12:], uint32(os.Getpid()))
+	copy(msg[syscall.NLMSG_HDRLEN:], payload)
+
+	return syscall.Sendto(pc.fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK})
+}
+
+func (pc *procConnector) Drain() ([]procEvent, error) {
+	var events []procEvent
+	var lost bool
+	for {
+		n, _, err := syscall.Recvfrom(pc.fd, pc.buf, 0)
+		switch {
+		case errors.Is(err, syscall.EAGAIN):
+			if lost {
+				return events, errProcEventsLost
+			}
+			return events, nil
+		case errors.Is(err, syscall.EINTR):
+			continue
+		case errors.Is(err, syscall.ENOBUFS):
+			// keep reading so that the events queued after the overflow are
+			// consumed before procfs is scanned
+			lost = true
+			continue
+		case err != nil:
+			return events, fmt.Errorf("failed to read process events: %w", err)
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(pc.buf[:n])
+		if err != nil {
+			return events, fmt.Errorf("failed to parse process events: %w", err)
+		}
+		for _, msg := range msgs {
+			if ev, ok := parseProcEvent(msg.Data); ok {
+				events = append(events, ev)
+			}
+		}
+	}
+}
+
+func (pc *procConnector) Close() error {
+	if pc.fd < 0 {
+		return nil
+	}
+	// the subscription ends with the socket; unsubscribing is best effort
+	_ = pc.send(procCnMcastIgnore)
+	err := syscall.Close(pc.fd)
+	pc.fd = -1
+	return err
+}
@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package resource
+
+import "errors"
+
+// procConnector is only supported on linux
+type procConnector struct{}
+
+var _ procEventSource = (*procConnector)(nil)
+
+func newProcConnector() *procConnector {
+	return &procConnector{}
+}
+
+func (pc *procConnector) Start() error {
+	return errors.New("process events are only supported on linux")
+}
+
+func (pc *procConnector) Drain() ([]procEvent, error) {
+	return nil, nil
+}
+
+func (pc *procConnector) Close() error {
+	return nil
+}
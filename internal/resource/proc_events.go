@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// procEventKind is the kind of a process lifecycle event
+type procEventKind int
+
+const (
+	procEventFork procEventKind = iota
+	procEventExec
+	procEventExit
+)
+
+// procEvent is a process lifecycle event reported by the kernel
+type procEvent struct {
+	Kind procEventKind
+	PID  int
+}
+
+// errProcEventsLost is returned when the kernel dropped process events, e.g.
+// because they were not read fast enough; procfs must be scanned to resync
+var errProcEventsLost = errors.New("process events lost")
+
+// procEventSource reports the processes that started, executed a new program
+// or exited
+type procEventSource interface {
+	// Start subscribes to process events
+	Start() error
+
+	// Drain returns the events received since the last call without blocking
+	Drain() ([]procEvent, error)
+
+	// Close unsubscribes from process events
+	Close() error
+}
+
+// proc connector constants from linux/cn_proc.h and linux/connector.h
+const (
+	cnIdxProc = 0x1
+	cnValProc = 0x1
+
+	procCnMcastListen = 1
+	procCnMcastIgnore = 2
+
+	procCnEventFork = 0x00000001
+	procCnEventExec = 0x00000002
+	procCnEventExit = 0x80000000
+
+	// cnMsgLen is the size of struct cn_msg without its payload
+	cnMsgLen = 20
+	// procCnEventHdrLen is the size of what, cpu and timestamp_ns of struct proc_event
+	procCnEventHdrLen = 16
+)
+
+// connectorMsg returns the payload of a netlink message (struct cn_msg) that
+// sets the proc connector multicast operation
+func connectorMsg(op uint32) []byte {
+	b := make([]byte, cnMsgLen+4)
+	binary.NativeEndian.PutUint32(b[0:], cnIdxProc)
+	binary.NativeEndian.PutUint32(b[4:], cnValProc)
+	binary.NativeEndian.PutUint16(b[16:], 4) // len of the payload
+	binary.NativeEndian.PutUint32(b[cnMsgLen:], op)
+	return b
+}
+
+// parseProcEvent parses the payload of a proc connector netlink message (struct
+// cn_msg followed by struct proc_event). Events of threads and of kinds other
+// than fork, exec and exit are ignored.
+func parseProcEvent(data []byte) (procEvent, bool) {
+	if len(data) < cnMsgLen+procCnEventHdrLen {
+		return procEvent{}, false
+	}
+	ev := data[cnMsgLen:]
+	what := binary.NativeEndian.Uint32(ev)
+	payload := ev[procCnEventHdrLen:]
+
+	// pid and tgid of the process the event is about, at offset
+	pidAt := func(offset int) (int, bool) {
+		if len(payload) < offset+8 {
+			return 0, false
+		}
+		pid := binary.NativeEndian.Uint32(payload[offset:])
+		tgid := binary.NativeEndian.Uint32(payload[offset+4:])
+		return int(pid), pid == tgid
+	}
+
+	var kind procEventKind
+	var pid int
+	var isProcess bool
+	switch what {
+	case procCnEventFork:
+		// parent pid and tgid are followed by the child pid and tgid
+		kind = procEventFork
+		pid, isProcess = pidAt(8)
+	case procCnEventExec:
+		kind = procEventExec
+		pid, isProcess = pidAt(0)
+	case procCnEventExit:
+		kind = procEventExit
+		pid, isProcess = pidAt(0)
+	default:
+		return procEvent{}, false
+	}
+	if !isProcess {
+		return procEvent{}, false
+	}
+	return procEvent{Kind: kind, PID: pid}, true
+}
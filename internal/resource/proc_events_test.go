@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// procCnEvent returns the payload of a proc connector message with the event
+// kind what and the given pid and tgid fields
+func procCnEvent(what uint32, ids ...uint32) []byte {
+	b := make([]byte, cnMsgLen+procCnEventHdrLen+4*len(ids))
+	binary.NativeEndian.PutUint32(b[cnMsgLen:], what)
+	for i, id := range ids {
+		binary.NativeEndian.PutUint32(b[cnMsgLen+procCnEventHdrLen+4*i:], id)
+	}
+	return b
+}
+
+func TestParseProcEvent(t *testing.T) {
+	tt := []struct {
+		name  string
+		data  []byte
+		event procEvent
+		ok    bool
+	}{
+		{"fork", procCnEvent(procCnEventFork, 1, 1, 42, 42), procEvent{Kind: procEventFork, PID: 42}, true},
+		{"thread fork", procCnEvent(procCnEventFork, 1, 1, 43, 42), procEvent{}, false},
+		{"exec", procCnEvent(procCnEventExec, 42, 42), procEvent{Kind: procEventExec, PID: 42}, true},
+		{"exit", procCnEvent(procCnEventExit, 42, 42, 0, 17), procEvent{Kind: procEventExit, PID: 42}, true},
+		{"thread exit", procCnEvent(procCnEventExit, 43, 42, 0, 17), procEvent{}, false},
+		{"uid change", procCnEvent(0x4, 42, 42, 0, 0), procEvent{}, false},
+		{"truncated", procCnEvent(procCnEventFork, 1, 1), procEvent{}, false},
+		{"empty", nil, procEvent{}, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			event, ok := parseProcEvent(tc.data)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.event, event)
+		})
+	}
+}
+
+func TestConnectorMsg(t *testing.T) {
+	msg := connectorMsg(procCnMcastListen)
+	require.Len(t, msg, cnMsgLen+4)
+	assert.Equal(t, uint32(cnIdxProc), binary.NativeEndian.Uint32(msg[0:]))
+	assert.Equal(t, uint32(cnValProc), binary.NativeEndian.Uint32(msg[4:]))
+	assert.Equal(t, uint16(4), binary.NativeEndian.Uint16(msg[16:]))
+	assert.Equal(t, uint32(procCnMcastListen), binary.NativeEndian.Uint32(msg[cnMsgLen:]))
+}
+
+// fakeProcEvents is a procEventSource that returns queued events
+type fakeProcEvents struct {
+	events []procEvent
+	err    error
+	closed bool
+}
+
+func (f *fakeProcEvents) Start() error { return nil }
+
+func (f *fakeProcEvents) Drain() ([]procEvent, error) {
+	events, err := f.events, f.err
+	f.events, f.err = nil, nil
+	return events, err
+}
+
+func (f *fakeProcEvents) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestRefresh_ProcessEvents(t *testing.T) {
+	newMockProc := func(pid int, cpuTime float64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return("worker", nil).Maybe()
+		mockProc.On("Executable").Return("/bin/worker", nil).Maybe()
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/user.slice"}}, nil).Maybe()
+		mockProc.On("CmdLine").Return([]string{"/bin/worker"}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(cpuTime, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1, 1.0), newMockProc(2, 1.0)}, nil).Once()
+
+	events := &fakeProcEvents{}
+	informer, err := NewInformer(WithProcReader(mockReader), WithProcessEvents(true))
+	require.NoError(t, err)
+	informer.procEvents = events
+
+	// procfs is scanned on the first refresh
+	require.NoError(t, informer.Refresh())
+	assert.Len(t, informer.Processes().Running, 2)
+
+	// 2 exits, 3 starts and 4 starts and exits between refreshes
+	events.events = []procEvent{
+		{Kind: procEventExit, PID: 2},
+		{Kind: procEventFork, PID: 3},
+		{Kind: procEventExec, PID: 3},
+		{Kind: procEventFork, PID: 4},
+		{Kind: procEventExit, PID: 4},
+	}
+	mockReader.On("Proc", 1).Return(newMockProc(1, 2.0), nil).Once()
+	mockReader.On("Proc", 3).Return(newMockProc(3, 0.5), nil).Once()
+
+	require.NoError(t, informer.Refresh())
+	procs := informer.Processes()
+	assert.Len(t, procs.Running, 2)
+	assert.Equal(t, 1.0, procs.Running[1].CPUTimeDelta)
+	assert.Equal(t, 0.5, procs.Running[3].CPUTimeDelta)
+	assert.Contains(t, procs.Terminated, 2)
+
+	// a process that exited before it is read is skipped
+	events.events = []procEvent{{Kind: procEventFork, PID: 5}}
+	mockReader.On("Proc", 1).Return(newMockProc(1, 2.0), nil).Once()
+	mockReader.On("Proc", 3).Return(newMockProc(3, 0.5), nil).Once()
+	mockReader.On("Proc", 5).Return(nil, os.ErrNotExist).Once()
+
+	require.NoError(t, informer.Refresh())
+	assert.Len(t, informer.Processes().Running, 2)
+
+	// procfs is scanned again when events are lost
+	events.err = errProcEventsLost
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc(1, 3.0), newMockProc(6, 1.0)}, nil).Once()
+
+	require.NoError(t, informer.Refresh())
+	procs = informer.Processes()
+	assert.Len(t, procs.Running, 2)
+	assert.Contains(t, procs.Running, 6)
+	assert.Contains(t, procs.Terminated, 3)
+
+	require.NoError(t, informer.Shutdown())
+	assert.True(t, events.closed)
+	mockReader.AssertExpectations(t)
+}
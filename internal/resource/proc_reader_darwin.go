@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build darwin
+
+package resource
+
+import (
+	"runtime"
+
+	"k8s.io/utils/clock"
+)
+
+// newProcReader returns the reader of the processes of the node listed by ps;
+// there is no procfs on macOS, so procfsPath is ignored
+func newProcReader(string) (allProcReader, error) {
+	return newPSProcReader("/bin/ps", runtime.NumCPU(), psTimeout, clock.RealClock{}), nil
+}
@@ -0,0 +1,12 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows && !darwin
+
+package resource
+
+// newProcReader returns the reader of the processes of the procfs mounted at
+// procfsPath
+func newProcReader(procfsPath string) (allProcReader, error) {
+	return NewProcFSReader(procfsPath)
+}
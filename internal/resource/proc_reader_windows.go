@@ -0,0 +1,437 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32 = windows.NewLazySystemDLL("kernel32.dll")
+	ntdll    = windows.NewLazySystemDLL("ntdll.dll")
+
+	procGetSystemTimes          = kernel32.NewProc("GetSystemTimes")
+	procGetProcessIoCounters    = kernel32.NewProc("GetProcessIoCounters")
+	procK32GetProcessMemoryInfo = kernel32.NewProc("K32GetProcessMemoryInfo")
+	procOpenJobObjectW          = kernel32.NewProc("OpenJobObjectW")
+
+	procNtOpenDirectoryObject  = ntdll.NewProc("NtOpenDirectoryObject")
+	procNtQueryDirectoryObject = ntdll.NewProc("NtQueryDirectoryObject")
+)
+
+const (
+	// systemPID is the PID of the System process, which runs the threads of
+	// the kernel
+	systemPID = 4
+
+	// jobDirectory is the object directory of the named job objects of
+	// session 0, where services such as containerd run
+	jobDirectory = `\BaseNamedObjects`
+
+	// maxJobProcesses is the maximum number of processes read per job object
+	maxJobProcesses = 4096
+
+	directoryQuery = 0x0001 // DIRECTORY_QUERY
+	jobObjectQuery = 0x0004 // JOB_OBJECT_QUERY
+)
+
+// winProcReader implements allProcReader on windows, where processes are
+// enumerated with the Tool Help API and read with the Win32 API. Processes of
+// containers are found from the job objects they are assigned to, which
+// containerd names after the container.
+type winProcReader struct {
+	prev systemTimes
+}
+
+var _ allProcReader = (*winProcReader)(nil)
+
+// newProcReader returns the reader of the processes of the node; there is no
+// procfs on windows, so procfsPath is ignored
+func newProcReader(string) (allProcReader, error) {
+	if err := procGetSystemTimes.Find(); err != nil {
+		return nil, fmt.Errorf("failed to find GetSystemTimes: %w", err)
+	}
+	return &winProcReader{}, nil
+}
+
+// AllProcs returns the processes that can be opened; those that cannot, e.g.
+// the Idle process and protected processes, are skipped
+func (r *winProcReader) AllProcs() ([]procInfo, error) {
+	entries, err := processEntries()
+	if err != nil {
+		return nil, err
+	}
+	jobs := jobsByPID()
+
+	procs := make([]procInfo, 0, len(entries))
+	for _, e := range entries {
+		p, err := newWinProc(e, jobs[int(e.ProcessID)])
+		if err != nil {
+			continue
+		}
+		procs = append(procs, p)
+	}
+	return procs, nil
+}
+
+// Proc returns the process with the given PID
+func (r *winProcReader) Proc(pid int) (procInfo, error) {
+	entries, err := processEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if int(e.ProcessID) == pid {
+			return newWinProc(e, jobsByPID()[pid])
+		}
+	}
+	return nil, fmt.Errorf("process %d: %w", pid, os.ErrNotExist)
+}
+
+// CPUUsageRatio returns the ratio of the CPU time spent outside of the Idle
+// process since the previous call; 0 on the first call
+func (r *winProcReader) CPUUsageRatio() (float64, error) {
+	curr, err := readSystemTimes()
+	if err != nil {
+		return 0, err
+	}
+
+	prev := r.prev
+	r.prev = curr
+	if prev == (systemTimes{}) {
+		return 0, nil
+	}
+
+	total := curr.total - prev.total
+	if total <= 0 {
+		return 0, nil
+	}
+	return (curr.busy - prev.busy) / total, nil
+}
+
+// ActiveCPUTime returns the CPU time in seconds spent by all CPUs outside of
+// the Idle process since boot
+func (r *winProcReader) ActiveCPUTime() (float64, error) {
+	t, err := readSystemTimes()
+	if err != nil {
+		return 0, err
+	}
+	return t.busy, nil
+}
+
+// systemTimes holds the cumulative busy and total CPU time of all CPUs in
+// seconds
+type systemTimes struct {
+	busy  float64
+	total float64
+}
+
+// readSystemTimes reads the CPU time of all CPUs; the kernel time includes the
+// idle time
+func readSystemTimes() (systemTimes, error) {
+	var idle, kernel, user windows.Filetime
+	ok, _, err := procGetSystemTimes.Call(
+		uintptr(unsafe.Pointer(&idle)),
+		uintptr(unsafe.Pointer(&kernel)),
+		uintptr(unsafe.Pointer(&user)),
+	)
+	if ok == 0 {
+		return systemTimes{}, fmt.Errorf("failed to read system times: %w", err)
+	}
+
+	total := filetimeSeconds(kernel) + filetimeSeconds(user)
+	return systemTimes{busy: total - filetimeSeconds(idle), total: total}, nil
+}
+
+// filetimeSeconds returns a duration in 100 ns intervals as seconds
+func filetimeSeconds(ft windows.Filetime) float64 {
+	return float64(uint64(ft.HighDateTime)<<32|uint64(ft.LowDateTime)) / 1e7
+}
+
+// processEntries returns a snapshot of the processes of the node
+func processEntries() ([]windows.ProcessEntry32, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot processes: %w", err)
+	}
+	defer func() { _ = windows.CloseHandle(snapshot) }()
+
+	var entries []windows.ProcessEntry32
+	e := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(snapshot, &e); err == nil; err = windows.Process32Next(snapshot, &e) {
+		entries = append(entries, e)
+	}
+	if !errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+		return nil, fmt.Errorf("failed to read process snapshot: %w", err)
+	}
+	return entries, nil
+}
+
+// winProc implements procInfo for a windows process, whose counters are read
+// when it is created since the process is not kept open
+type winProc struct {
+	pid  int
+	ppid int
+	comm string
+	jobs []cGroup
+
+	exe        string
+	cpuTime    float64
+	rss        uint64
+	readBytes  uint64
+	writeBytes uint64
+}
+
+var _ procInfo = (*winProc)(nil)
+
+// processMemoryCounters is PROCESS_MEMORY_COUNTERS
+type processMemoryCounters struct {
+	CB                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// newWinProc opens the process of e and reads its counters; jobs are the paths
+// of the job objects the process is assigned to
+func newWinProc(e windows.ProcessEntry32, jobs []cGroup) (*winProc, error) {
+	pid := int(e.ProcessID)
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, e.ProcessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open process %d: %w", pid, err)
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+
+	p := &winProc{
+		pid:  pid,
+		ppid: int(e.ParentProcessID),
+		comm: strings.TrimSuffix(windows.UTF16ToString(e.ExeFile[:]), ".exe"),
+		jobs: jobs,
+	}
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return nil, fmt.Errorf("failed to get cpu time of process %d: %w", pid, err)
+	}
+	p.cpuTime = filetimeSeconds(kernel) + filetimeSeconds(user)
+
+	// the executable of the System process has no path
+	buf := make([]uint16, windows.MAX_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err == nil {
+		p.exe = windows.UTF16ToString(buf[:size])
+	}
+
+	mem := processMemoryCounters{CB: uint32(unsafe.Sizeof(processMemoryCounters{}))}
+	if ok, _, err := procK32GetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&mem)), uintptr(mem.CB)); ok == 0 {
+		return nil, fmt.Errorf("failed to get memory of process %d: %w", pid, err)
+	}
+	p.rss = uint64(mem.WorkingSetSize)
+
+	var io windows.IO_COUNTERS
+	if ok, _, err := procGetProcessIoCounters.Call(uintptr(h), uintptr(unsafe.Pointer(&io))); ok == 0 {
+		return nil, fmt.Errorf("failed to get io of process %d: %w", pid, err)
+	}
+	p.readBytes, p.writeBytes = io.ReadTransferCount, io.WriteTransferCount
+
+	return p, nil
+}
+
+func (p *winProc) PID() int {
+	return p.pid
+}
+
+// Comm returns the name of the executable without its .exe extension
+func (p *winProc) Comm() (string, error) {
+	return p.comm, nil
+}
+
+func (p *winProc) Executable() (string, error) {
+	return p.exe, nil
+}
+
+// Cgroups returns the job objects of the process as /job/<name>, which are what
+// cgroups are to linux processes
+func (p *winProc) Cgroups() ([]cGroup, error) {
+	return p.jobs, nil
+}
+
+// Environ returns no environment; the environment of other processes is only
+// in their memory
+func (p *winProc) Environ() ([]string, error) {
+	return nil, nil
+}
+
+// CmdLine returns only the executable of the process; like its environment,
+// the arguments of other processes are only in their memory
+func (p *winProc) CmdLine() ([]string, error) {
+	if p.exe == "" {
+		return []string{p.comm}, nil
+	}
+	return []string{p.exe}, nil
+}
+
+func (p *winProc) CPUTime() (float64, error) {
+	return p.cpuTime, nil
+}
+
+// ResidentMemory returns the working set of the process
+func (p *winProc) ResidentMemory() (uint64, error) {
+	return p.rss, nil
+}
+
+// LastCPU returns 0; windows does not report the processor a process last ran
+// on
+func (p *winProc) LastCPU() (int, error) {
+	return 0, nil
+}
+
+func (p *winProc) ParentPID() (int, error) {
+	return p.ppid, nil
+}
+
+// ChildrenCPUTime returns 0; windows does not account the CPU time of exited
+// children to their parent
+func (p *winProc) ChildrenCPUTime() (float64, error) {
+	return 0, nil
+}
+
+// UID returns -1; windows processes are owned by SIDs rather than user IDs
+func (p *winProc) UID() (int, error) {
+	return -1, nil
+}
+
+// KernelThread returns true for the System process, which runs the threads of
+// the kernel
+func (p *winProc) KernelThread() (bool, error) {
+	return p.pid == systemPID, nil
+}
+
+// DiskIO returns the bytes the process read and wrote; windows counts all of
+// its I/O, not only that of block devices
+func (p *winProc) DiskIO() (uint64, uint64, error) {
+	return p.readBytes, p.writeBytes, nil
+}
+
+// objectDirectoryInformation is OBJECT_DIRECTORY_INFORMATION
+type objectDirectoryInformation struct {
+	Name     windows.NTUnicodeString
+	TypeName windows.NTUnicodeString
+}
+
+// jobsByPID returns the paths of the named job objects of the processes keyed
+// by PID. Job objects are best effort: none are returned if they cannot be
+// read, and processes are then not mapped to containers.
+func jobsByPID() map[int][]cGroup {
+	names, err := namedJobs()
+	if err != nil {
+		return nil
+	}
+
+	jobs := map[int][]cGroup{}
+	for _, name := range names {
+		pids, err := jobProcesses(name)
+		if err != nil {
+			continue
+		}
+		for _, pid := range pids {
+			jobs[pid] = append(jobs[pid], cGroup{Path: "/job/" + name})
+		}
+	}
+	return jobs
+}
+
+// namedJobs returns the names of the job objects of jobDirectory
+func namedJobs() ([]string, error) {
+	dirName, err := windows.NewNTUnicodeString(jobDirectory)
+	if err != nil {
+		return nil, err
+	}
+	attrs := windows.OBJECT_ATTRIBUTES{ObjectName: dirName}
+	attrs.Length = uint32(unsafe.Sizeof(attrs))
+
+	var dir windows.Handle
+	status, _, _ := procNtOpenDirectoryObject.Call(uintptr(unsafe.Pointer(&dir)), directoryQuery, uintptr(unsafe.Pointer(&attrs)))
+	if status != 0 {
+		return nil, fmt.Errorf("failed to open %s: %w", jobDirectory, windows.NTStatus(status))
+	}
+	defer func() { _ = windows.CloseHandle(dir) }()
+
+	var names []string
+	buf := make([]byte, 4096)
+	var index, length uint32
+	restart := uintptr(1)
+	for {
+		// entries are read one at a time, so that the buffer holds only one
+		status, _, _ := procNtQueryDirectoryObject.Call(
+			uintptr(dir),
+			uintptr(unsafe.Pointer(&buf[0])),
+			uintptr(len(buf)),
+			1, // ReturnSingleEntry
+			restart,
+			uintptr(unsafe.Pointer(&index)),
+			uintptr(unsafe.Pointer(&length)),
+		)
+		restart = 0
+		if windows.NTStatus(status) == windows.STATUS_NO_MORE_ENTRIES {
+			return names, nil
+		}
+		if status != 0 {
+			return nil, fmt.Errorf("failed to read %s: %w", jobDirectory, windows.NTStatus(status))
+		}
+
+		info := (*objectDirectoryInformation)(unsafe.Pointer(&buf[0]))
+		if info.TypeName.String() == "Job" {
+			names = append(names, info.Name.String())
+		}
+	}
+}
+
+// jobProcesses returns the PIDs of the processes assigned to the named job
+// object
+func jobProcesses(name string) ([]int, error) {
+	n, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	h, _, err := procOpenJobObjectW.Call(jobObjectQuery, 0, uintptr(unsafe.Pointer(n)))
+	if h == 0 {
+		return nil, fmt.Errorf("failed to open job object %s: %w", name, err)
+	}
+	job := windows.Handle(h)
+	defer func() { _ = windows.CloseHandle(job) }()
+
+	// JOBOBJECT_BASIC_PROCESS_ID_LIST
+	var list struct {
+		NumberOfAssignedProcesses uint32
+		NumberOfProcessIdsInList  uint32
+		ProcessIDList             [maxJobProcesses]uintptr
+	}
+	err = windows.QueryInformationJobObject(job, windows.JobObjectBasicProcessIdList,
+		uintptr(unsafe.Pointer(&list)), uint32(unsafe.Sizeof(list)), nil)
+	if err != nil && !errors.Is(err, windows.ERROR_MORE_DATA) {
+		return nil, fmt.Errorf("failed to list processes of job object %s: %w", name, err)
+	}
+
+	pids := make([]int, list.NumberOfProcessIdsInList)
+	for i := range pids {
+		pids[i] = int(list.ProcessIDList[i])
+	}
+	return pids, nil
+}
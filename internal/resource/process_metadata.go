@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxCmdLineLen is the max length in bytes of the command line kept for a process
+const maxCmdLineLen = 256
+
+// userNames resolves UIDs to user names and caches the result, including UIDs
+// unknown to the user database
+type userNames struct {
+	lookup func(uid string) (*user.User, error)
+
+	mu    sync.Mutex // processes are read in parallel
+	names map[int]string
+}
+
+func newUserNames() *userNames {
+	return &userNames{lookup: user.LookupId, names: make(map[int]string)}
+}
+
+// name returns the name of the user with the given UID or an empty string if
+// the UID is unknown
+func (u *userNames) name(uid int) string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if name, ok := u.names[uid]; ok {
+		return name
+	}
+
+	name := ""
+	if usr, err := u.lookup(strconv.Itoa(uid)); err == nil {
+		name = usr.Username
+	}
+	u.names[uid] = name
+	return name
+}
+
+// populateMetadata reads the command line and user of the process
+func (ri *resourceInformer) populateMetadata(p *Process, proc procInfo) error {
+	cmdline, err := proc.CmdLine()
+	if err != nil {
+		return fmt.Errorf("failed to get process cmdline: %w", err)
+	}
+	p.CmdLine = truncateCmdLine(cmdline)
+
+	uid, err := proc.UID()
+	if err != nil {
+		return fmt.Errorf("failed to get process uid: %w", err)
+	}
+	p.UID = uid
+	p.User = ri.users.name(uid)
+
+	return nil
+}
+
+// truncateCmdLine joins the arguments of a command line and truncates it to
+// maxCmdLineLen bytes without splitting a UTF-8 character
+func truncateCmdLine(args []string) string {
+	cmdline := strings.Join(args, " ")
+	if len(cmdline) <= maxCmdLineLen {
+		return cmdline
+	}
+
+	end := maxCmdLineLen
+	for end > 0 && !utf8.RuneStart(cmdline[end]) {
+		end--
+	}
+	return cmdline[:end]
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"errors"
+	"os/user"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateCmdLine(t *testing.T) {
+	assert.Equal(t, "", truncateCmdLine(nil))
+	assert.Equal(t, "nginx -g daemon off;", truncateCmdLine([]string{"nginx", "-g", "daemon off;"}))
+
+	long := truncateCmdLine([]string{"java", strings.Repeat("x", 300)})
+	assert.Len(t, long, maxCmdLineLen)
+	assert.True(t, strings.HasPrefix(long, "java xxx"))
+
+	// multi-byte characters are not split
+	multiByte := truncateCmdLine([]string{strings.Repeat("a", maxCmdLineLen-1) + "é"})
+	assert.Len(t, multiByte, maxCmdLineLen-1)
+}
+
+func TestUserNames(t *testing.T) {
+	lookups := 0
+	users := &userNames{
+		lookup: func(uid string) (*user.User, error) {
+			lookups++
+			if uid == "1000" {
+				return &user.User{Uid: uid, Username: "alice"}, nil
+			}
+			return nil, errors.New("unknown user")
+		},
+		names: make(map[int]string),
+	}
+
+	assert.Equal(t, "alice", users.name(1000))
+	assert.Equal(t, "alice", users.name(1000))
+	assert.Equal(t, "", users.name(2000))
+	assert.Equal(t, "", users.name(2000))
+	assert.Equal(t, 2, lookups, "lookups are cached")
+}
+
+func TestRefresh_ProcessMetadata(t *testing.T) {
+	newMockProc := func(comm string, cmdline []string, cpuTime float64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(42)
+		mockProc.On("Comm").Return(comm, nil)
+		mockProc.On("Executable").Return("/usr/bin/"+comm, nil)
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/user.slice"}}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CmdLine").Return(cmdline, nil)
+		mockProc.On("UID").Return(0, nil)
+		mockProc.On("ParentPID").Return(1, nil)
+		mockProc.On("CPUTime").Return(cpuTime, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc("bash", []string{"bash", "-l"}, 1.0)}, nil).Once()
+
+	informer, err := NewInformer(WithProcReader(mockReader), WithProcessMetadata(true))
+	require.NoError(t, err)
+	informer.users = &userNames{
+		lookup: func(uid string) (*user.User, error) { return &user.User{Uid: uid, Username: "root"}, nil },
+		names:  make(map[int]string),
+	}
+
+	require.NoError(t, informer.Refresh())
+	proc := informer.Processes().Running[42]
+	require.NotNil(t, proc)
+	assert.Equal(t, "bash -l", proc.CmdLine)
+	assert.Equal(t, 0, proc.UID)
+	assert.Equal(t, "root", proc.User)
+	assert.Equal(t, 1, proc.ParentPID)
+
+	// the command line is read again after the process executes a new program
+	mockReader.On("AllProcs").Return([]procInfo{newMockProc("sleep", []string{"sleep", "60"}, 2.0)}, nil).Once()
+	require.NoError(t, informer.Refresh())
+	assert.Equal(t, "sleep 60", informer.Processes().Running[42].CmdLine)
+}
@@ -23,12 +23,23 @@ type procInfo interface {
 	Environ() ([]string, error)
 	CmdLine() ([]string, error)
 	CPUTime() (float64, error)
+	ResidentMemory() (uint64, error)
+	LastCPU() (int, error)
+	ParentPID() (int, error)
+	ChildrenCPUTime() (float64, error)
+	UID() (int, error)
+	KernelThread() (bool, error)
+	DiskIO() (readBytes, writeBytes uint64, err error)
 }
 
 // procWrapper implements ProcInfo by wrapping procfs.Proc. This is needed because the procfs.Proc
 // does not implement PID() as a method
 type procWrapper struct {
 	proc procfs.Proc
+
+	// stat is read once and shared by the methods that need it since procs are
+	// wrapped afresh on every AllProcs call
+	stat *procfs.ProcStat
 }
 
 var _ procInfo = (*procWrapper)(nil)
@@ -72,8 +83,21 @@ func (p *procWrapper) CmdLine() ([]string, error) {
 // hardcoded just like in procfs
 const userHZ = 100
 
-func (p *procWrapper) CPUTime() (float64, error) {
+func (p *procWrapper) readStat() (*procfs.ProcStat, error) {
+	if p.stat != nil {
+		return p.stat, nil
+	}
+
 	st, err := p.proc.Stat()
+	if err != nil {
+		return nil, err
+	}
+	p.stat = &st
+	return p.stat, nil
+}
+
+func (p *procWrapper) CPUTime() (float64, error) {
+	st, err := p.readStat()
 	if err != nil {
 		return 0, err
 	}
@@ -81,6 +105,78 @@ func (p *procWrapper) CPUTime() (float64, error) {
 	return float64(st.STime+st.UTime) / userHZ, nil
 }
 
+func (p *procWrapper) ResidentMemory() (uint64, error) {
+	st, err := p.readStat()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(st.ResidentMemory()), nil
+}
+
+func (p *procWrapper) LastCPU() (int, error) {
+	st, err := p.readStat()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(st.Processor), nil
+}
+
+func (p *procWrapper) ParentPID() (int, error) {
+	st, err := p.readStat()
+	if err != nil {
+		return 0, err
+	}
+
+	return st.PPID, nil
+}
+
+// pfKThread is the PF_KTHREAD flag of the kernel set for kernel threads
+const pfKThread = 0x00200000
+
+// KernelThread returns true if the process is a kernel thread
+func (p *procWrapper) KernelThread() (bool, error) {
+	st, err := p.readStat()
+	if err != nil {
+		return false, err
+	}
+
+	return st.Flags&pfKThread != 0, nil
+}
+
+// UID returns the real user ID of the process
+func (p *procWrapper) UID() (int, error) {
+	status, err := p.proc.NewStatus()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(status.UIDs[0]), nil
+}
+
+// ChildrenCPUTime returns the CPU time of the children of the process that have
+// exited and been waited for
+func (p *procWrapper) ChildrenCPUTime() (float64, error) {
+	st, err := p.readStat()
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(st.CSTime+st.CUTime) / userHZ, nil
+}
+
+// DiskIO returns the bytes the process caused to be read from and written to
+// block devices, including the bytes of its threads that exited
+func (p *procWrapper) DiskIO() (uint64, uint64, error) {
+	io, err := p.proc.IO()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return io.ReadBytes, io.WriteBytes, nil
+}
+
 // WrapProc wraps a procfs.Proc in a ProcInfo interface
 func WrapProc(proc procfs.Proc) procInfo {
 	return &procWrapper{proc: proc}
@@ -91,16 +187,35 @@ type allProcReader interface {
 	// AllProcs returns a list of all running processes
 	AllProcs() ([]procInfo, error)
 
+	// Proc returns the process with the given PID
+	Proc(pid int) (procInfo, error)
+
 	// CPUUsageRatio returns the CPU usage ratio
 	CPUUsageRatio() (float64, error)
+
+	// ActiveCPUTime returns the CPU time in seconds spent by all CPUs outside
+	// of idle and iowait since boot
+	ActiveCPUTime() (float64, error)
 }
 
 // procFSReader is the default implementation of ProcReader using procfs
 type procFSReader struct {
 	fs       procfs.FS
 	prevStat procfs.CPUStat
+
+	// prevCPUStats holds the stat of each CPU read by CPUUsageRatios
+	prevCPUStats map[int64]procfs.CPUStat
+}
+
+// perCPUUsageReader reads the usage ratio of each CPU
+type perCPUUsageReader interface {
+	// CPUUsageRatios returns the usage ratio of each CPU since the last call,
+	// keyed by CPU; nil on the first call
+	CPUUsageRatios() (map[int]float64, error)
 }
 
+var _ perCPUUsageReader = (*procFSReader)(nil)
+
 // CPUUsageRatio returns the CPU usage ratio as
 // active over total, where active = total - (idle + iowait)
 // and total = user + nice + system + idle + iowait + irq + softirq + steal
@@ -117,9 +232,35 @@ func (r *procFSReader) CPUUsageRatio() (float64, error) {
 	if prev == (procfs.CPUStat{}) {
 		return 0, nil
 	}
+	return usageRatio(prev, current.CPUTotal), nil
+}
+
+// CPUUsageRatios returns the usage ratio of each CPU as CPUUsageRatio does for
+// all CPUs
+func (r *procFSReader) CPUUsageRatios() (map[int]float64, error) {
+	current, err := r.fs.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	prev := r.prevCPUStats
+	r.prevCPUStats = current.CPU
+	if prev == nil {
+		return nil, nil
+	}
 
-	curr := current.CPUTotal
+	ratios := make(map[int]float64, len(current.CPU))
+	for cpu, curr := range current.CPU {
+		if p, ok := prev[cpu]; ok {
+			ratios[int(cpu)] = usageRatio(p, curr)
+		}
+	}
+	return ratios, nil
+}
 
+// usageRatio returns the ratio of the time spent outside of idle and iowait
+// between the prev and curr stats
+func usageRatio(prev, curr procfs.CPUStat) float64 {
 	// find delta for all components
 	dUser := curr.User - prev.User
 	dNice := curr.Nice - prev.Nice
@@ -132,12 +273,21 @@ func (r *procFSReader) CPUUsageRatio() (float64, error) {
 
 	total := dUser + dNice + dSystem + dIdle + dIowait + dIRQ + dSoftIRQ + dSteal
 	if total == 0 {
-		return 0, nil
+		return 0
 	}
 
 	active := total - (dIdle + dIowait)
-	ratio := active / total
-	return ratio, nil
+	return active / total
+}
+
+func (r *procFSReader) ActiveCPUTime() (float64, error) {
+	stat, err := r.fs.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	t := stat.CPUTotal
+	return t.User + t.Nice + t.System + t.IRQ + t.SoftIRQ + t.Steal, nil
 }
 
 // AllProcs returns a list of all running processes
@@ -154,6 +304,15 @@ func (r *procFSReader) AllProcs() ([]procInfo, error) {
 	return ret, nil
 }
 
+// Proc returns the process with the given PID
+func (r *procFSReader) Proc(pid int) (procInfo, error) {
+	proc, err := r.fs.Proc(pid)
+	if err != nil {
+		return nil, err
+	}
+	return WrapProc(proc), nil
+}
+
 // NewProcFSReader creates a new ProcReader that reads from the specified procfs path
 func NewProcFSReader(procfsPath string) (*procFSReader, error) {
 	fs, err := procfs.NewFS(procfsPath)
@@ -28,7 +28,7 @@ func TestNewProcess(t *testing.T) {
 		mockProc.On("CmdLine").Return([]string{"/bin/bash"}, nil).Maybe()
 		mockProc.On("CPUTime").Return(float64(10.5), nil).Once()
 
-		process, err := newProcess(mockProc)
+		process, err := newProcess(mockProc, &vmDetector{})
 		require.NoError(t, err)
 		assert.NotNil(t, process)
 		assert.Equal(t, 12345, process.PID)
@@ -49,7 +49,7 @@ func TestNewProcess(t *testing.T) {
 		mockProc.On("Comm").Return("", assert.AnError)
 		mockProc.On("CPUTime").Return(float64(10.5), nil).Once()
 
-		process, err := newProcess(mockProc)
+		process, err := newProcess(mockProc, &vmDetector{})
 		assert.Error(t, err)
 		assert.Nil(t, process)
 		assert.ErrorContains(t, err, "failed to get process comm")
@@ -64,7 +64,7 @@ func TestNewProcess(t *testing.T) {
 		mockProc.On("Executable").Return("", errors.New("executable error"))
 		mockProc.On("CPUTime").Return(float64(10.5), nil).Once()
 
-		process, err := newProcess(mockProc)
+		process, err := newProcess(mockProc, &vmDetector{})
 		assert.Error(t, err)
 		assert.Nil(t, process)
 		assert.ErrorContains(t, err, "failed to get process executable")
@@ -81,7 +81,7 @@ func TestNewProcess(t *testing.T) {
 		mockProc.On("Cgroups").Return([]cGroup{}, errors.New("cgroups error"))
 		mockProc.On("CPUTime").Return(float64(10.5), nil).Once()
 
-		process, err := newProcess(mockProc)
+		process, err := newProcess(mockProc, &vmDetector{})
 		assert.Error(t, err)
 		assert.Nil(t, process)
 		assert.ErrorContains(t, err, "failed to get process cgroups")
@@ -101,7 +101,7 @@ func TestNewProcess(t *testing.T) {
 		mockProc.On("Cgroups").Return([]cGroup{{Path: fmt.Sprintf("/sys/fs/cgroup/system.slice/docker-%s.scope", ctrID)}}, nil)
 		mockProc.On("Environ").Return([]string{"CONTAINER_NAME=test-container"}, nil)
 
-		process, err := newProcess(mockProc)
+		process, err := newProcess(mockProc, &vmDetector{})
 		require.NoError(t, err)
 		require.NotNil(t, process)
 		assert.Equal(t, 12345, process.PID)
@@ -502,6 +502,54 @@ func TestRefresh_PodInformer(t *testing.T) {
 		mockProcFS.AssertExpectations(t)
 		mockProc.AssertExpectations(t)
 	})
+	t.Run("decorates pods with metadata", func(t *testing.T) {
+		for _, enabled := range []bool{false, true} {
+			mockProc := &MockProcInfo{}
+			mockProc.On("PID").Return(123)
+			mockProc.On("Comm").Return("test-process", nil)
+			mockProc.On("CmdLine").Return([]string{"/usr/bin/test"}, nil)
+			mockProc.On("Executable").Return("/usr/bin/test", nil)
+			containerID, cgPath := mockContainerIDAndPath(DockerRuntime)
+			mockProc.On("Cgroups").Return([]cGroup{{Path: cgPath}}, nil)
+			mockProc.On("CPUTime").Return(10.0, nil)
+			mockProc.On("Environ").Return([]string{}, nil)
+
+			mockProcFS := &MockProcReader{}
+			mockProcFS.On("AllProcs").Return([]procInfo{mockProc}, nil)
+			mockProcFS.On("CPUUsageRatio").Return(0.5, nil)
+
+			mockPodInformer := new(mockPodInformer)
+			mockPodInformer.On("LookupByContainerID", containerID).Return(
+				&pod.ContainerInfo{
+					PodID:     "pod123",
+					PodName:   "web-5d8f7c9b4-x2x9z",
+					Namespace: "default",
+					PodLabels: map[string]string{"app": "web"},
+					OwnerKind: "Deployment",
+					OwnerName: "web",
+				}, true, nil,
+			)
+
+			informer, err := NewInformer(WithProcReader(mockProcFS), WithPodInformer(mockPodInformer),
+				WithPodMetadata(enabled))
+			require.NoError(t, err)
+			require.NoError(t, informer.Refresh())
+
+			p := informer.Pods().Running["pod123"]
+			require.NotNil(t, p)
+			assert.Equal(t, "default", p.Namespace)
+			if !enabled {
+				assert.Nil(t, p.Labels)
+				assert.Empty(t, p.OwnerKind)
+				assert.Empty(t, p.OwnerName)
+				continue
+			}
+			assert.Equal(t, map[string]string{"app": "web"}, p.Labels)
+			assert.Equal(t, "Deployment", p.OwnerKind)
+			assert.Equal(t, "web", p.OwnerName)
+			assert.Equal(t, "web", informer.Containers().Running[containerID].Pod.OwnerName)
+		}
+	})
 	t.Run("podInformer returns ErrNoPod", func(t *testing.T) {
 		mockProc := &MockProcInfo{}
 		mockProc.On("PID").Return(456)
@@ -1264,3 +1312,191 @@ func TestRefreshConcurrency(t *testing.T) {
 	mockProc2.AssertExpectations(t)
 	mockProc3.AssertExpectations(t)
 }
+
+func TestMemoryTracking(t *testing.T) {
+	newMockProc := func() *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(3001)
+		mockProc.On("Comm").Return("memory-hog", nil)
+		mockProc.On("Executable").Return("/bin/memory-hog", nil)
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/user.slice"}}, nil)
+		mockProc.On("CmdLine").Return([]string{"/bin/memory-hog"}, nil)
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(float64(1.0), nil)
+		return mockProc
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		mockProc := newMockProc()
+		mockProc.On("ResidentMemory").Return(uint64(64<<20), nil)
+
+		mockReader := &MockProcReader{}
+		mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+
+		informer, err := NewInformer(WithProcReader(mockReader), WithMemoryTracking(true))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+
+		assert.Equal(t, uint64(64<<20), informer.Processes().Running[3001].ResidentMemory)
+		mockProc.AssertExpectations(t)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mockProc := newMockProc()
+
+		mockReader := &MockProcReader{}
+		mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+
+		informer, err := NewInformer(WithProcReader(mockReader))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+
+		assert.Zero(t, informer.Processes().Running[3001].ResidentMemory)
+		mockProc.AssertNotCalled(t, "ResidentMemory")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockProc := newMockProc()
+		mockProc.On("ResidentMemory").Return(uint64(0), errors.New("stat read error"))
+
+		mockReader := &MockProcReader{}
+		mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+
+		informer, err := NewInformer(WithProcReader(mockReader), WithMemoryTracking(true))
+		require.NoError(t, err)
+		assert.ErrorContains(t, informer.Refresh(), "resident memory")
+	})
+}
+
+func TestLastCPUTracking(t *testing.T) {
+	newMockProc := func() *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(3002)
+		mockProc.On("Comm").Return("worker", nil)
+		mockProc.On("Executable").Return("/bin/worker", nil)
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/user.slice"}}, nil)
+		mockProc.On("CmdLine").Return([]string{"/bin/worker"}, nil)
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(float64(1.0), nil)
+		return mockProc
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		mockProc := newMockProc()
+		mockProc.On("LastCPU").Return(5, nil)
+
+		mockReader := &MockProcReader{}
+		mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+
+		informer, err := NewInformer(WithProcReader(mockReader), WithLastCPUTracking(true))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+
+		assert.Equal(t, 5, informer.Processes().Running[3002].LastCPU)
+		mockProc.AssertExpectations(t)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mockProc := newMockProc()
+
+		mockReader := &MockProcReader{}
+		mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+
+		informer, err := NewInformer(WithProcReader(mockReader))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+
+		assert.Zero(t, informer.Processes().Running[3002].LastCPU)
+		mockProc.AssertNotCalled(t, "LastCPU")
+	})
+}
+
+func TestExitedProcessTracking(t *testing.T) {
+	newMockProc := func(pid, ppid int, cpuTime, childrenCPUTime float64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return(fmt.Sprintf("proc-%d", pid), nil).Maybe()
+		mockProc.On("Executable").Return("/bin/proc", nil).Maybe()
+		mockProc.On("Cgroups").Return([]cGroup{{Path: "/user.slice"}}, nil).Maybe()
+		mockProc.On("CmdLine").Return([]string{"/bin/proc"}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(cpuTime, nil)
+		mockProc.On("ParentPID").Return(ppid, nil)
+		mockProc.On("ChildrenCPUTime").Return(childrenCPUTime, nil)
+		return mockProc
+	}
+
+	t.Run("terminated children share unaccounted time", func(t *testing.T) {
+		mockReader := &MockProcReader{}
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+		mockReader.On("AllProcs").Return([]procInfo{
+			newMockProc(10, 1, 1.0, 1.0),
+			newMockProc(11, 10, 2.0, 0),
+			newMockProc(12, 10, 1.0, 0),
+		}, nil).Once()
+
+		informer, err := NewInformer(WithProcReader(mockReader), WithExitedProcessTracking(true))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+
+		parent := informer.Processes().Running[10]
+		assert.Equal(t, 10, informer.Processes().Running[11].ParentPID)
+		assert.Zero(t, parent.ChildrenCPUTimeDelta, "children exited before the first refresh are ignored")
+
+		// both children exit: 11 used 2.0s and 12 used 1.0s more CPU time, and
+		// children never seen used another 1.5s
+		mockReader.On("AllProcs").Return([]procInfo{
+			newMockProc(10, 1, 2.0, 1.0+4.0+2.0+1.5),
+		}, nil).Once()
+		require.NoError(t, informer.Refresh())
+
+		procs := informer.Processes()
+		assert.Equal(t, 1.0, procs.Running[10].CPUTimeDelta)
+		// 4.5s unaccounted shared in proportion to the last deltas of 2.0 and 1.0
+		assert.InDelta(t, 3.0, procs.Terminated[11].CPUTimeDelta, 1e-9)
+		assert.InDelta(t, 1.5, procs.Terminated[12].CPUTimeDelta, 1e-9)
+		assert.InDelta(t, 5.5, informer.Node().ProcessTotalCPUTimeDelta, 1e-9)
+	})
+
+	t.Run("parent is charged for unseen children", func(t *testing.T) {
+		mockReader := &MockProcReader{}
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+		mockReader.On("AllProcs").Return([]procInfo{newMockProc(10, 1, 1.0, 0)}, nil).Once()
+
+		informer, err := NewInformer(WithProcReader(mockReader), WithExitedProcessTracking(true))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+
+		mockReader.On("AllProcs").Return([]procInfo{newMockProc(10, 1, 1.5, 2.0)}, nil).Once()
+		require.NoError(t, informer.Refresh())
+
+		parent := informer.Processes().Running[10]
+		assert.Equal(t, 2.0, parent.ChildrenCPUTimeDelta)
+		assert.InDelta(t, 2.5, parent.CPUTimeDelta, 1e-9)
+		assert.InDelta(t, 2.5, informer.Node().ProcessTotalCPUTimeDelta, 1e-9)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mockProc := newMockProc(10, 1, 1.0, 0)
+
+		mockReader := &MockProcReader{}
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+		mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil).Once()
+
+		informer, err := NewInformer(WithProcReader(mockReader))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+
+		mockReader.On("AllProcs").Return([]procInfo{}, nil).Once()
+		require.NoError(t, informer.Refresh())
+
+		assert.Zero(t, informer.Processes().Terminated[10].CPUTimeDelta)
+		mockProc.AssertNotCalled(t, "ParentPID")
+		mockProc.AssertNotCalled(t, "ChildrenCPUTime")
+	})
+}
@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// psFields are the fields of the processes listed by ps; comm is last since
+// the path of the executable may have spaces
+const psFields = "pid=,ppid=,uid=,rss=,time=,comm="
+
+// psTimeout is how long ps may take to list the processes before it is killed
+const psTimeout = 5 * time.Second
+
+// psProcReader implements allProcReader where there is no procfs, e.g. on
+// macOS, by listing processes with ps. Attribution is coarse: ps reports the
+// CPU time of processes only to the hundredth of a second, and the node CPU
+// time is the sum of that of the processes listed, which misses the CPU time
+// of processes that exited since the previous listing.
+type psProcReader struct {
+	ps     func() (string, error)
+	clock  clock.PassiveClock
+	numCPU int
+
+	mu      sync.Mutex
+	prevCPU map[int]float64 // CPU time of processes at the previous listing
+	active  float64         // CPU time of the node since the first listing
+
+	// node CPU time and time at the previous CPUUsageRatio call
+	ratioActive float64
+	ratioAt     time.Time
+}
+
+var _ allProcReader = (*psProcReader)(nil)
+
+// newPSProcReader returns a reader of the processes listed by the ps at path,
+// which is killed if it runs for longer than timeout
+func newPSProcReader(path string, numCPU int, timeout time.Duration, c clock.PassiveClock) *psProcReader {
+	return &psProcReader{
+		ps: func() (string, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			out, err := exec.CommandContext(ctx, path, "-axww", "-o", psFields).Output()
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("ps timed out after %s: %w", timeout, ctx.Err())
+			}
+			return string(out), err
+		},
+		clock:  c,
+		numCPU: numCPU,
+	}
+}
+
+// AllProcs returns the processes listed by ps
+func (r *psProcReader) AllProcs() ([]procInfo, error) {
+	procs, err := r.list()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]procInfo, len(procs))
+	for i, p := range procs {
+		ret[i] = p
+	}
+	return ret, nil
+}
+
+// Proc returns the process with the given PID
+func (r *psProcReader) Proc(pid int) (procInfo, error) {
+	procs, err := r.list()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range procs {
+		if p.pid == pid {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("process %d: %w", pid, os.ErrNotExist)
+}
+
+// CPUUsageRatio returns the ratio of the CPU time of the processes to that of
+// all CPUs since the previous call; 0 on the first call
+func (r *psProcReader) CPUUsageRatio() (float64, error) {
+	if _, err := r.list(); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.clock.Now()
+	prevActive, prevAt := r.ratioActive, r.ratioAt
+	r.ratioActive, r.ratioAt = r.active, now
+	if prevAt.IsZero() {
+		return 0, nil
+	}
+
+	total := now.Sub(prevAt).Seconds() * float64(r.numCPU)
+	if total <= 0 {
+		return 0, nil
+	}
+	return min((r.active-prevActive)/total, 1), nil
+}
+
+// ActiveCPUTime returns the CPU time in seconds spent by the processes listed
+// since the first listing
+func (r *psProcReader) ActiveCPUTime() (float64, error) {
+	if _, err := r.list(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.active, nil
+}
+
+// list runs ps and adds the CPU time the processes spent since the previous
+// listing to the CPU time of the node
+func (r *psProcReader) list() ([]*psProc, error) {
+	out, err := r.ps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	procs, err := parsePS(out)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cpu := make(map[int]float64, len(procs))
+	for _, p := range procs {
+		cpu[p.pid] = p.cpuTime
+		if prev, ok := r.prevCPU[p.pid]; ok {
+			r.active += max(p.cpuTime-prev, 0)
+		} else if r.prevCPU != nil {
+			// started since the previous listing
+			r.active += p.cpuTime
+		}
+	}
+	r.prevCPU = cpu
+	return procs, nil
+}
+
+// parsePS parses the processes listed by ps with psFields
+func parsePS(out string) ([]*psProc, error) {
+	var procs []*psProc
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("invalid ps line: %q", line)
+		}
+
+		var ints [4]int
+		for i := range ints {
+			v, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid ps line: %q: %w", line, err)
+			}
+			ints[i] = v
+		}
+		cpuTime, err := parsePSTime(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("invalid ps line: %q: %w", line, err)
+		}
+
+		// the rest of the line is the executable, which may have spaces
+		exe := line
+		for range 5 {
+			exe = strings.TrimSpace(exe[strings.IndexAny(exe, " \t"):])
+		}
+		procs = append(procs, &psProc{
+			pid:     ints[0],
+			ppid:    ints[1],
+			uid:     ints[2],
+			rss:     uint64(ints[3]) * 1024,
+			cpuTime: cpuTime,
+			exe:     exe,
+		})
+	}
+	return procs, scanner.Err()
+}
+
+// parsePSTime parses a CPU time of ps in seconds, e.g. 1:02.03 on macOS, where
+// the minutes may exceed 59, or 1-02:03:04 with days and hours on linux
+func parsePSTime(s string) (float64, error) {
+	var days float64
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		v, err := strconv.ParseFloat(d, 64)
+		if err != nil {
+			return 0, err
+		}
+		days, s = v, rest
+	}
+
+	var secs float64
+	for _, part := range strings.Split(s, ":") {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, err
+		}
+		secs = secs*60 + v
+	}
+	return days*24*3600 + secs, nil
+}
+
+// psProc implements procInfo for a process listed by ps
+type psProc struct {
+	pid     int
+	ppid    int
+	uid     int
+	rss     uint64
+	cpuTime float64
+	exe     string
+}
+
+var _ procInfo = (*psProc)(nil)
+
+func (p *psProc) PID() int {
+	return p.pid
+}
+
+// Comm returns the name of the executable
+func (p *psProc) Comm() (string, error) {
+	return filepath.Base(p.exe), nil
+}
+
+func (p *psProc) Executable() (string, error) {
+	return p.exe, nil
+}
+
+// Cgroups returns no cgroups, so processes are not mapped to containers
+func (p *psProc) Cgroups() ([]cGroup, error) {
+	return nil, nil
+}
+
+// Environ returns no environment, which is not listed
+func (p *psProc) Environ() ([]string, error) {
+	return nil, nil
+}
+
+// CmdLine returns only the executable of the process; its arguments are not
+// listed, as they may be too long for a line
+func (p *psProc) CmdLine() ([]string, error) {
+	return []string{p.exe}, nil
+}
+
+func (p *psProc) CPUTime() (float64, error) {
+	return p.cpuTime, nil
+}
+
+func (p *psProc) ResidentMemory() (uint64, error) {
+	return p.rss, nil
+}
+
+// LastCPU returns 0; ps does not list the processor a process last ran on
+func (p *psProc) LastCPU() (int, error) {
+	return 0, nil
+}
+
+func (p *psProc) ParentPID() (int, error) {
+	return p.ppid, nil
+}
+
+// ChildrenCPUTime returns 0; ps does not list the CPU time of exited children
+// separately
+func (p *psProc) ChildrenCPUTime() (float64, error) {
+	return 0, nil
+}
+
+func (p *psProc) UID() (int, error) {
+	return p.uid, nil
+}
+
+// KernelThread returns true for the kernel_task of macOS, PID 0, which runs
+// the threads of the kernel
+func (p *psProc) KernelThread() (bool, error) {
+	return p.pid == 0, nil
+}
+
+// DiskIO returns no I/O; ps does not list it
+func (p *psProc) DiskIO() (uint64, uint64, error) {
+	return 0, 0, nil
+}
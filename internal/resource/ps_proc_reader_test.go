@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestParsePS(t *testing.T) {
+	// ps -axww -o pid=,ppid=,uid=,rss=,time=,comm= on macOS
+	out := `    0     0     0      0 125:01.52 kernel_task
+    1     0     0  13904   3:12.04 /sbin/launchd
+  501     1   501 184320   0:04.50 /Applications/Visual Studio Code.app/Contents/MacOS/Electron
+`
+	procs, err := parsePS(out)
+	require.NoError(t, err)
+	require.Len(t, procs, 3)
+
+	kernel := procs[0]
+	assert.Equal(t, 0, kernel.PID())
+	assert.InDelta(t, 7501.52, kernel.cpuTime, 0.001)
+	isKernel, _ := kernel.KernelThread()
+	assert.True(t, isKernel)
+
+	code := procs[2]
+	assert.Equal(t, 501, code.PID())
+	ppid, _ := code.ParentPID()
+	assert.Equal(t, 1, ppid)
+	uid, _ := code.UID()
+	assert.Equal(t, 501, uid)
+	rss, _ := code.ResidentMemory()
+	assert.Equal(t, uint64(184320*1024), rss)
+	exe, _ := code.Executable()
+	assert.Equal(t, "/Applications/Visual Studio Code.app/Contents/MacOS/Electron", exe)
+	comm, _ := code.Comm()
+	assert.Equal(t, "Electron", comm)
+	isKernel, _ = code.KernelThread()
+	assert.False(t, isKernel)
+
+	_, err = parsePS("1 0 0 abc 0:01.00 /sbin/launchd")
+	assert.ErrorContains(t, err, "invalid ps line")
+	_, err = parsePS("1 0 0")
+	assert.ErrorContains(t, err, "invalid ps line")
+}
+
+func TestParsePSTime(t *testing.T) {
+	for s, secs := range map[string]float64{
+		"0:00.07":    0.07,
+		"125:01.52":  7501.52,
+		"01:02:03":   3723,
+		"1-02:03:04": 93784,
+	} {
+		got, err := parsePSTime(s)
+		require.NoError(t, err, s)
+		assert.InDelta(t, secs, got, 0.001, s)
+	}
+	_, err := parsePSTime("1:xx")
+	assert.Error(t, err)
+}
+
+func TestPSProcReader(t *testing.T) {
+	listings := []string{
+		"1 0 0 100 0:10.00 /sbin/launchd\n2 1 0 100 0:05.00 /usr/libexec/logd\n",
+		// logd exited and mds started
+		"1 0 0 100 0:12.00 /sbin/launchd\n3 1 0 100 0:01.00 /usr/sbin/mds\n",
+		"1 0 0 100 0:16.00 /sbin/launchd\n3 1 0 100 0:01.00 /usr/sbin/mds\n",
+	}
+	fc := testingclock.NewFakeClock(time.Now())
+	r := newPSProcReader("ps", 2, psTimeout, fc)
+	runs := 0
+	r.ps = func() (string, error) {
+		out := listings[min(runs, len(listings)-1)]
+		runs++
+		return out, nil
+	}
+
+	ratio, err := r.CPUUsageRatio()
+	require.NoError(t, err)
+	assert.Zero(t, ratio, "the first call has nothing to compare against")
+
+	fc.Step(4 * time.Second)
+	active, err := r.ActiveCPUTime()
+	require.NoError(t, err)
+	assert.InDelta(t, 3.0, active, 0.001, "launchd ran 2s and mds 1s")
+
+	procs, err := r.AllProcs()
+	require.NoError(t, err)
+	assert.Len(t, procs, 2)
+
+	// 3s and 4s of the processes over 4s of 2 CPUs
+	ratio, err = r.CPUUsageRatio()
+	require.NoError(t, err)
+	assert.InDelta(t, 7.0/8, ratio, 0.001)
+
+	p, err := r.Proc(3)
+	require.NoError(t, err)
+	comm, _ := p.Comm()
+	assert.Equal(t, "mds", comm)
+	_, err = r.Proc(2)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestPSProcReaderTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not found")
+	}
+	path := filepath.Join(t.TempDir(), "ps")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nexec sleep 5\n"), 0o755))
+
+	r := newPSProcReader(path, 2, 10*time.Millisecond, testingclock.NewFakeClock(time.Now()))
+	_, err := r.AllProcs()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "ps timed out after 10ms")
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import "sync"
+
+// minProcsPerScanWorker is the min number of processes read by each worker;
+// fewer processes are read sequentially as starting workers costs more than
+// reading them
+const minProcsPerScanWorker = 256
+
+// scanResult is the outcome of reading a process
+type scanResult struct {
+	pid   int
+	proc  *Process
+	isNew bool // proc isn't in the process cache yet
+	err   error
+}
+
+// scanProcs reads procs and returns the results in the same order. On busy
+// nodes, procs are sharded across up to scanWorkers workers. Workers only read
+// the process cache; new processes are added to it by the caller.
+func (ri *resourceInformer) scanProcs(procs []procInfo) []scanResult {
+	results := make([]scanResult, len(procs))
+	scan := func(start, end int) {
+		for i := start; i < end; i++ {
+			proc, isNew, err := ri.updateProcess(procs[i])
+			results[i] = scanResult{pid: procs[i].PID(), proc: proc, isNew: isNew, err: err}
+		}
+	}
+
+	workers := min(ri.scanWorkers, len(procs)/minProcsPerScanWorker)
+	if workers <= 1 {
+		scan(0, len(procs))
+		return results
+	}
+
+	shard := (len(procs) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(procs); start += shard {
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			scan(start, end)
+		}(start, min(start+shard, len(procs)))
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresh_ParallelScan(t *testing.T) {
+	const numProcs = 4 * minProcsPerScanWorker
+
+	newMockProcs := func(cpuTime float64) []procInfo {
+		procs := make([]procInfo, 0, numProcs)
+		for pid := 1; pid <= numProcs; pid++ {
+			mockProc := &MockProcInfo{}
+			mockProc.On("PID").Return(pid)
+			mockProc.On("Comm").Return(fmt.Sprintf("proc-%d", pid), nil).Maybe()
+			mockProc.On("Executable").Return("/bin/proc", nil).Maybe()
+			mockProc.On("Cgroups").Return([]cGroup{{Path: "/"}}, nil).Maybe()
+			mockProc.On("CmdLine").Return([]string{"/bin/proc"}, nil).Maybe()
+			mockProc.On("Environ").Return([]string{}, nil).Maybe()
+			mockProc.On("CPUTime").Return(cpuTime*float64(pid), nil)
+			procs = append(procs, mockProc)
+		}
+		return procs
+	}
+
+	refresh := func(workers int) *Processes {
+		mockReader := &MockProcReader{}
+		mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+		mockReader.On("AllProcs").Return(newMockProcs(1.0), nil).Once()
+		mockReader.On("AllProcs").Return(newMockProcs(1.5), nil).Once()
+
+		informer, err := NewInformer(WithProcReader(mockReader), WithScanWorkers(workers))
+		require.NoError(t, err)
+		require.NoError(t, informer.Refresh())
+		require.NoError(t, informer.Refresh())
+		return informer.Processes()
+	}
+
+	sequential := refresh(1)
+	parallel := refresh(4)
+
+	require.Len(t, parallel.Running, numProcs)
+	for pid, proc := range sequential.Running {
+		require.Contains(t, parallel.Running, pid)
+		assert.Equal(t, proc.Comm, parallel.Running[pid].Comm)
+		assert.Equal(t, proc.CPUTimeDelta, parallel.Running[pid].CPUTimeDelta)
+		assert.Equal(t, 0.5*float64(pid), parallel.Running[pid].CPUTimeDelta)
+	}
+}
+
+func TestRefresh_Health(t *testing.T) {
+	mockReader := &MockProcReader{}
+	mockReader.On("AllProcs").Return([]procInfo{}, nil)
+	mockReader.On("CPUUsageRatio").Return(float64(0), assert.AnError).Once()
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+
+	informer, err := NewInformer(WithProcReader(mockReader))
+	require.NoError(t, err)
+	assert.NoError(t, informer.Health())
+
+	assert.Error(t, informer.Refresh())
+	assert.ErrorIs(t, informer.Health(), assert.AnError)
+
+	require.NoError(t, informer.Refresh())
+	assert.NoError(t, informer.Health())
+}
+
+// BenchmarkScanProcs measures the time to read the processes of a busy node by
+// reading the processes of the host repeatedly
+func BenchmarkScanProcs(b *testing.B) {
+	const numProcs = 10_000
+
+	reader, err := NewProcFSReader("/proc")
+	require.NoError(b, err)
+	procs, err := reader.AllProcs()
+	require.NoError(b, err)
+	require.NotEmpty(b, procs)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("processes=%d/workers=%d", numProcs, workers), func(b *testing.B) {
+			informer, err := NewInformer(WithProcReader(reader), WithScanWorkers(workers))
+			require.NoError(b, err)
+
+			scan := make([]procInfo, numProcs)
+			b.ResetTimer()
+			for range b.N {
+				// procs are wrapped afresh as stat is read once per wrapper
+				for i := range scan {
+					scan[i] = &procWrapper{proc: procs[i%len(procs)].(*procWrapper).proc}
+				}
+				informer.scanProcs(scan)
+			}
+		})
+	}
+}
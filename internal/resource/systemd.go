@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rootSlice is the name systemd gives to the root of the cgroup hierarchy
+const rootSlice = "-.slice"
+
+// systemdUnitFromProc returns the systemd unit the process runs in or nil if
+// the process isn't run by systemd (e.g. kernel threads)
+func systemdUnitFromProc(proc procInfo) (*SystemdUnit, error) {
+	cgroups, err := proc.Cgroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process cgroups: %w", err)
+	}
+
+	// with cgroup v1, the name=systemd hierarchy is the one managed by systemd,
+	// but controllers mounted by systemd mirror it
+	for _, cg := range cgroups {
+		if unit := systemdUnitFromCgroupPath(cg.Path); unit != nil {
+			return unit, nil
+		}
+	}
+	return nil, nil
+}
+
+// systemdUnitFromCgroupPath returns the systemd unit of a cgroup path, which
+// is the outermost service or scope, and the slice it is nested in. Nested
+// units such as the apps of a user session (user@1000.service/app.slice/...)
+// and cgroups delegated to a service are grouped under the outermost unit.
+func systemdUnitFromCgroupPath(path string) *SystemdUnit {
+	slice := rootSlice
+	for _, part := range strings.Split(path, "/") {
+		switch {
+		case strings.HasSuffix(part, ".slice"):
+			slice = part
+		case strings.HasSuffix(part, ".service"), strings.HasSuffix(part, ".scope"):
+			return &SystemdUnit{Name: part, Slice: slice}
+		}
+	}
+	return nil
+}
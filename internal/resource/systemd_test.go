@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdUnitFromCgroupPath(t *testing.T) {
+	tt := []struct {
+		name string
+		path string
+		unit *SystemdUnit
+	}{
+		{"service", "/system.slice/sshd.service", &SystemdUnit{Name: "sshd.service", Slice: "system.slice"}},
+		{"init", "/init.scope", &SystemdUnit{Name: "init.scope", Slice: "-.slice"}},
+		{"session", "/user.slice/user-1000.slice/session-2.scope", &SystemdUnit{Name: "session-2.scope", Slice: "user-1000.slice"}},
+		{
+			"user manager app",
+			"/user.slice/user-1000.slice/user@1000.service/app.slice/app-firefox-1234.scope",
+			&SystemdUnit{Name: "user@1000.service", Slice: "user-1000.slice"},
+		},
+		{"delegated", "/system.slice/docker.service/payload", &SystemdUnit{Name: "docker.service", Slice: "system.slice"}},
+		{
+			"container scope",
+			"/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1.slice/cri-containerd-abc.scope",
+			&SystemdUnit{Name: "cri-containerd-abc.scope", Slice: "kubepods-besteffort-pod1.slice"},
+		},
+		{"slice only", "/system.slice", nil},
+		{"root", "/", nil},
+		{"not systemd", "/kubepods/besteffort/pod1/abc", nil},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.unit, systemdUnitFromCgroupPath(tc.path))
+		})
+	}
+}
+
+func TestRefresh_SystemdUnits(t *testing.T) {
+	newMockProc := func(pid int, cgroup string, cpuTime float64) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid)
+		mockProc.On("Comm").Return("worker", nil).Maybe()
+		mockProc.On("Executable").Return("/bin/worker", nil).Maybe()
+		mockProc.On("Cgroups").Return([]cGroup{{Path: cgroup}}, nil).Maybe()
+		mockProc.On("CmdLine").Return([]string{"/bin/worker"}, nil).Maybe()
+		mockProc.On("Environ").Return([]string{}, nil).Maybe()
+		mockProc.On("CPUTime").Return(cpuTime, nil)
+		return mockProc
+	}
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(1, "/init.scope", 1.0),
+		newMockProc(2, "/system.slice/nginx.service", 2.0),
+		newMockProc(3, "/system.slice/nginx.service", 3.0),
+		newMockProc(4, "/", 1.0),
+	}, nil).Once()
+
+	informer, err := NewInformer(WithProcReader(mockReader), WithSystemdUnits(true))
+	require.NoError(t, err)
+
+	require.NoError(t, informer.Refresh())
+	units := informer.SystemdUnits()
+	require.Len(t, units.Running, 2)
+	assert.Empty(t, units.Terminated)
+
+	nginx := units.Running["nginx.service"]
+	require.NotNil(t, nginx)
+	assert.Equal(t, "system.slice", nginx.Slice)
+	assert.Equal(t, 5.0, nginx.CPUTimeDelta)
+	assert.Equal(t, 5.0, nginx.CPUTotalTime)
+	assert.Equal(t, 1.0, units.Running["init.scope"].CPUTimeDelta)
+	assert.Nil(t, informer.Processes().Running[4].SystemdUnit)
+
+	// nginx stops; init keeps running
+	mockReader.On("AllProcs").Return([]procInfo{
+		newMockProc(1, "/init.scope", 1.5),
+		newMockProc(4, "/", 2.0),
+	}, nil).Once()
+
+	require.NoError(t, informer.Refresh())
+	units = informer.SystemdUnits()
+	require.Len(t, units.Running, 1)
+	assert.Equal(t, 0.5, units.Running["init.scope"].CPUTimeDelta)
+	assert.Equal(t, 1.5, units.Running["init.scope"].CPUTotalTime)
+	assert.Contains(t, units.Terminated, "nginx.service")
+}
+
+func TestRefresh_SystemdUnitsDisabled(t *testing.T) {
+	mockProc := &MockProcInfo{}
+	mockProc.On("PID").Return(1)
+	mockProc.On("Comm").Return("init", nil)
+	mockProc.On("Executable").Return("/sbin/init", nil)
+	mockProc.On("Cgroups").Return([]cGroup{{Path: "/init.scope"}}, nil)
+	mockProc.On("CmdLine").Return([]string{"/sbin/init"}, nil).Maybe()
+	mockProc.On("Environ").Return([]string{}, nil).Maybe()
+	mockProc.On("CPUTime").Return(1.0, nil)
+
+	mockReader := &MockProcReader{}
+	mockReader.On("CPUUsageRatio").Return(float64(0.5), nil)
+	mockReader.On("AllProcs").Return([]procInfo{mockProc}, nil)
+
+	informer, err := NewInformer(WithProcReader(mockReader))
+	require.NoError(t, err)
+
+	require.NoError(t, informer.Refresh())
+	assert.Empty(t, informer.SystemdUnits().Running)
+	assert.Nil(t, informer.Processes().Running[1].SystemdUnit)
+}
@@ -3,6 +3,11 @@
 
 package resource
 
+import (
+	"maps"
+	"slices"
+)
+
 type ProcessType string
 
 const (
@@ -22,9 +27,133 @@ type Process struct {
 	Container      *Container
 	VirtualMachine *VirtualMachine
 
+	// SystemdUnit is the systemd unit the process runs in; only set if systemd
+	// unit tracking is enabled
+	SystemdUnit *SystemdUnit
+
+	// Group is the group of processes the process is in; nil unless a group
+	// is defined that it belongs to
+	Group *Group
+
+	// KernelThread is true for kernel threads; only set if aggregate tracking
+	// is enabled
+	KernelThread bool
+
 	// Dynamic
 	CPUTotalTime float64 // total cpu time used by the process
 	CPUTimeDelta float64 // cpu time used by the process since last refresh
+
+	ResidentMemory uint64 // resident set size in bytes; 0 unless memory tracking is enabled
+	LastCPU        int    // CPU the process last ran on; 0 unless last cpu tracking is enabled
+
+	// read only if process metadata tracking is enabled; CmdLine is truncated
+	// to maxCmdLineLen bytes
+	CmdLine string
+	UID     int
+	User    string // empty if the UID is not known to the user database
+
+	// read only if exited process tracking or process metadata tracking is
+	// enabled, or groups are defined
+	ParentPID int
+
+	// read only if exited process tracking is enabled
+	ChildrenCPUTime      float64 // total cpu time used by the exited children of the process
+	ChildrenCPUTimeDelta float64 // cpu time used by children that exited since last refresh
+
+	// read only if perf event tracking is enabled; counted since the process
+	// was first seen
+	HWCounters      HWCounters
+	HWCountersDelta HWCounters // counted since last refresh
+
+	// read only if I/O tracking is enabled; disk bytes are counted since the
+	// process started and network bytes since it was first seen
+	IO      IOCounters
+	IODelta IOCounters // transferred since last refresh
+
+	// cgroupPaths are read only if the filter or a group selects processes by
+	// cgroup
+	cgroupPaths []string
+	// reported is set once the process is admitted by the filter
+	reported bool
+}
+
+// HWCounters holds the hardware events counted by the performance monitoring
+// unit of the CPU for a process
+type HWCounters struct {
+	Instructions uint64 // instructions retired
+	Cycles       uint64 // CPU cycles
+	CacheMisses  uint64 // last level cache misses
+}
+
+// Add returns the sum of the events counted in c and d
+func (c HWCounters) Add(d HWCounters) HWCounters {
+	return HWCounters{
+		Instructions: c.Instructions + d.Instructions,
+		Cycles:       c.Cycles + d.Cycles,
+		CacheMisses:  c.CacheMisses + d.CacheMisses,
+	}
+}
+
+// Sub returns the events counted since prev. Counts are scaled when counters
+// are multiplexed and can go down slightly, in which case nothing was counted.
+func (c HWCounters) Sub(prev HWCounters) HWCounters {
+	sub := func(a, b uint64) uint64 {
+		if a < b {
+			return 0
+		}
+		return a - b
+	}
+	return HWCounters{
+		Instructions: sub(c.Instructions, prev.Instructions),
+		Cycles:       sub(c.Cycles, prev.Cycles),
+		CacheMisses:  sub(c.CacheMisses, prev.CacheMisses),
+	}
+}
+
+// IOCounters holds the bytes a process read from and wrote to block devices,
+// and received and sent over IPv4 and IPv6 sockets
+type IOCounters struct {
+	DiskReadBytes  uint64
+	DiskWriteBytes uint64
+	NetRxBytes     uint64
+	NetTxBytes     uint64
+}
+
+// Disk returns the bytes read from and written to block devices
+func (c IOCounters) Disk() uint64 {
+	return c.DiskReadBytes + c.DiskWriteBytes
+}
+
+// Network returns the bytes received and sent over the network
+func (c IOCounters) Network() uint64 {
+	return c.NetRxBytes + c.NetTxBytes
+}
+
+// Add returns the sum of the bytes in c and d
+func (c IOCounters) Add(d IOCounters) IOCounters {
+	return IOCounters{
+		DiskReadBytes:  c.DiskReadBytes + d.DiskReadBytes,
+		DiskWriteBytes: c.DiskWriteBytes + d.DiskWriteBytes,
+		NetRxBytes:     c.NetRxBytes + d.NetRxBytes,
+		NetTxBytes:     c.NetTxBytes + d.NetTxBytes,
+	}
+}
+
+// Sub returns the bytes transferred since prev. Network bytes start over when
+// the PID of an exited process is reused, in which case nothing was transferred.
+func (c IOCounters) Sub(prev IOCounters) IOCounters {
+	sub := func(a, b uint64) uint64 {
+		if a < b {
+			return 0
+		}
+		return a - b
+	}
+	return IOCounters{
+		DiskReadBytes:  sub(c.DiskReadBytes, prev.DiskReadBytes),
+		DiskWriteBytes: sub(c.DiskWriteBytes, prev.DiskWriteBytes),
+		NetRxBytes:     sub(c.NetRxBytes, prev.NetRxBytes),
+		NetTxBytes:     sub(c.NetTxBytes, prev.NetTxBytes),
+	}
 }
 
 // Container represents metadata about a container
@@ -33,11 +162,27 @@ type Container struct {
 	Name    string
 	Runtime ContainerRuntime
 
+	// Image and Labels of the container; only set if the container is known
+	// to the container runtime (CRI) or engine (Docker, Podman)
+	Image  string
+	Labels map[string]string
+	// ComposeProject is the docker compose or podman-compose project of the
+	// container; empty if not run by compose
+	ComposeProject string
+
 	Pod *Pod
 
+	// CPUs the container may run on, read from its cpuset; nil if unknown or
+	// cpusets aren't read
+	CPUs []int
+
 	// Resource usage tracking
 	CPUTotalTime float64 // total cpu time used by the container so far
 	CPUTimeDelta float64 // cpu time used by the container since last refresh
+
+	// cgroupPath is the path of the cgroup of the container relative to the
+	// cgroup v2 mount point; empty if unknown
+	cgroupPath string
 }
 
 type ContainerRuntime string
@@ -58,9 +203,14 @@ func (c *Container) Clone() *Container {
 	}
 
 	clone := &Container{
-		ID:      c.ID,
-		Name:    c.Name,
-		Runtime: c.Runtime,
+		ID:             c.ID,
+		Name:           c.Name,
+		Runtime:        c.Runtime,
+		Image:          c.Image,
+		Labels:         maps.Clone(c.Labels),
+		ComposeProject: c.ComposeProject,
+		CPUs:           slices.Clone(c.CPUs),
+		cgroupPath:     c.cgroupPath,
 	}
 
 	return clone
@@ -71,6 +221,7 @@ type VirtualMachine struct {
 	ID         string
 	Name       string
 	Hypervisor Hypervisor
+	CID        uint32 // vsock context ID; 0 if the VM has no vsock device or it isn't known
 
 	// Resource usage tracking
 	CPUTotalTime float64 // total cpu time used by the VM so far
@@ -82,7 +233,9 @@ type Hypervisor string
 const (
 	UnknownHypervisor Hypervisor = "unknown"
 
-	KVMHypervisor Hypervisor = "kvm"
+	KVMHypervisor         Hypervisor = "kvm"
+	CloudHypervisor       Hypervisor = "cloud-hypervisor"
+	FirecrackerHypervisor Hypervisor = "firecracker"
 
 	// TODO: add patterns for these hypervisors
 	VirtualBoxHypervisor Hypervisor = "virtualbox"
@@ -100,14 +253,59 @@ func (vm *VirtualMachine) Clone() *VirtualMachine {
 		ID:         vm.ID,
 		Name:       vm.Name,
 		Hypervisor: vm.Hypervisor,
+		CID:        vm.CID,
 	}
 }
 
+// SystemdUnit represents a systemd service or scope unit that processes run in
+type SystemdUnit struct {
+	Name  string // e.g. sshd.service, session-2.scope
+	Slice string // slice the unit belongs to, e.g. system.slice
+
+	// Resource usage tracking
+	CPUTotalTime float64 // total cpu time used by the unit so far
+	CPUTimeDelta float64 // cpu time used by the unit since last refresh
+}
+
+// Clone creates a deep copy of a SystemdUnit
+func (u *SystemdUnit) Clone() *SystemdUnit {
+	if u == nil {
+		return nil
+	}
+
+	return &SystemdUnit{
+		Name:  u.Name,
+		Slice: u.Slice,
+	}
+}
+
+// Group is a group of processes tracked as a workload; see GroupSpec
+type Group struct {
+	Name   string
+	PID    int    // root process of a process tree; 0 if the group is a cgroup
+	Cgroup string // cgroup path of the group; empty if the group is a process tree
+
+	Processes int // number of processes assigned to the group so far
+
+	// Resource usage tracking
+	CPUTotalTime float64 // total cpu time used by the group so far
+	CPUTimeDelta float64 // cpu time used by the group since last refresh
+}
+
 type Pod struct {
 	ID        string
 	Name      string
 	Namespace string
 
+	CPURequest float64 // CPU requests of the pod in cores; 0 if not set
+	CPULimit   float64 // CPU limits of the pod in cores; 0 if not set
+
+	// Labels and the owner workload (e.g. Deployment, StatefulSet) of the pod;
+	// only set if pod metadata tracking is enabled
+	Labels    map[string]string
+	OwnerKind string
+	OwnerName string
+
 	// Resource usage tracking
 	CPUTotalTime float64 // total cpu time used by the Pod so far
 	CPUTimeDelta float64 // cpu time used by the Pod since last refresh
@@ -118,8 +316,13 @@ func (p *Pod) Clone() *Pod {
 		return nil
 	}
 	return &Pod{
-		ID:        p.ID,
-		Name:      p.Name,
-		Namespace: p.Namespace,
+		ID:         p.ID,
+		Name:       p.Name,
+		Namespace:  p.Namespace,
+		CPURequest: p.CPURequest,
+		CPULimit:   p.CPULimit,
+		Labels:     maps.Clone(p.Labels),
+		OwnerKind:  p.OwnerKind,
+		OwnerName:  p.OwnerName,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -14,14 +15,59 @@ var (
 	// QEMU/KVM patterns - matches both qemu-system-* and qemu-kvm variants
 	qemuPattern = regexp.MustCompile(`(bin/qemu-system-\w+|libexec/qemu-kvm)`)
 
+	// cloud-hypervisor and firecracker are matched by the executable name
+	cloudHypervisorPattern = regexp.MustCompile(`^cloud-hypervisor$`)
+	firecrackerPattern     = regexp.MustCompile(`^firecracker$`)
+
+	// qemuGuestCIDPattern matches the context ID of vhost-vsock devices, given
+	// as guest-cid=3 or, by libvirt, as JSON: "guest-cid":3
+	qemuGuestCIDPattern = regexp.MustCompile(`guest-cid"?[=:]\s*"?(\d+)`)
+
 	// TODO: add patterns for virtual box,  VMware, Xen
 
 	// VM process name patterns
 	vmProcessPatterns = map[*regexp.Regexp]Hypervisor{
-		qemuPattern: KVMHypervisor,
+		qemuPattern:            KVMHypervisor,
+		cloudHypervisorPattern: CloudHypervisor,
+		firecrackerPattern:     FirecrackerHypervisor,
 	}
 )
 
+// vmDetector detects the processes of VMs along with their ID, name and
+// hypervisor
+type vmDetector struct {
+	// libvirtDir is the directory where libvirt keeps the status of running
+	// QEMU domains (e.g. /run/libvirt/qemu); empty disables libvirt lookups
+	libvirtDir string
+}
+
+// detect returns the VM run by the process or nil if it isn't a VM process.
+// The ID and name of QEMU VMs are taken from their libvirt domain if found.
+func (d *vmDetector) detect(proc procInfo) (*VirtualMachine, error) {
+	vm, err := vmInfoFromProc(proc)
+	if err != nil || vm == nil || vm.Hypervisor != KVMHypervisor || d.libvirtDir == "" {
+		return vm, err
+	}
+
+	cmdline, err := proc.CmdLine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process cmdline: %w", err)
+	}
+	dom, err := lookupLibvirtDomain(d.libvirtDir, qemuVMNameFromCmdLine(cmdline), proc.PID())
+	if err != nil || dom == nil {
+		// not managed by libvirt or the domain status isn't readable
+		return vm, nil
+	}
+
+	if dom.UUID != "" {
+		vm.ID = dom.UUID
+	}
+	if name := dom.displayName(); name != "" {
+		vm.Name = name
+	}
+	return vm, nil
+}
+
 // vmInfoFromProc detects if a process is a VM process and extracts VM info
 func vmInfoFromProc(proc procInfo) (*VirtualMachine, error) {
 	// Check command line for VM processes
@@ -42,13 +88,14 @@ func vmInfoFromProc(proc procInfo) (*VirtualMachine, error) {
 	vm := &VirtualMachine{
 		ID:         vmID,
 		Hypervisor: hypervisor,
+		CID:        vsockCIDFromCmdLine(cmdline, hypervisor),
 	}
 
 	// Try to get VM name from command line arguments
 	vm.Name = vmNameFromCmdLine(cmdline, hypervisor)
 
 	if vm.Name == "" {
-		vm.Name = fmt.Sprintf("%s-%s", hypervisor, vmID[:8])
+		vm.Name = fmt.Sprintf("%s-%s", hypervisor, vmID[:min(len(vmID), 8)])
 	}
 
 	return vm, nil
@@ -83,6 +130,10 @@ func extractVMID(cmdline []string, hypervisor Hypervisor) string {
 	switch hypervisor {
 	case KVMHypervisor:
 		return extractQemuMachineID(cmdline)
+	case CloudHypervisor:
+		return extractCloudHypervisorID(cmdline)
+	case FirecrackerHypervisor:
+		return firecrackerIDFromCmdLine(cmdline)
 	default:
 		return ""
 	}
@@ -113,6 +164,8 @@ func vmNameFromCmdLine(cmdline []string, hypervisor Hypervisor) string {
 	switch hypervisor {
 	case KVMHypervisor:
 		return qemuVMNameFromCmdLine(cmdline)
+	case FirecrackerHypervisor:
+		return firecrackerIDFromCmdLine(cmdline)
 	default:
 		return ""
 	}
@@ -142,3 +195,90 @@ func qemuVMNameFromCmdLine(cmdline []string) string {
 	}
 	return ""
 }
+
+// argValue returns the value of a command line flag given as "flag value" or
+// "flag=value"
+func argValue(cmdline []string, flag string) string {
+	for i, arg := range cmdline {
+		if arg == flag && i+1 < len(cmdline) {
+			return cmdline[i+1]
+		}
+		if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// extractCloudHypervisorID extracts the VM ID from the API socket path of a
+// cloud-hypervisor command line. Kata Containers places the socket in a
+// directory named after the sandbox (e.g. /run/vc/vm/<sandbox-id>/clh-api.sock),
+// so the name of the directory is used as the ID.
+func extractCloudHypervisorID(cmdline []string) string {
+	socket := argValue(cmdline, "--api-socket")
+	if socket == "" {
+		return ""
+	}
+
+	// the socket can be given as "path=<path>[,fd=<fd>]"
+	for _, opt := range strings.Split(socket, ",") {
+		if path, ok := strings.CutPrefix(opt, "path="); ok {
+			socket = path
+			break
+		}
+	}
+	if !strings.HasPrefix(socket, "/") {
+		return ""
+	}
+	dir := filepath.Base(filepath.Dir(socket))
+	if dir == "/" || dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// vsockCIDFromCmdLine extracts the vsock context ID of the VM from its command
+// line; 0 if it has no vsock device. Firecracker configures vsock through its
+// API, so its context ID is not known.
+func vsockCIDFromCmdLine(cmdline []string, hypervisor Hypervisor) uint32 {
+	var value string
+	switch hypervisor {
+	case KVMHypervisor:
+		for _, arg := range cmdline {
+			if !strings.Contains(arg, "vhost-vsock") {
+				continue
+			}
+			if m := qemuGuestCIDPattern.FindStringSubmatch(arg); m != nil {
+				value = m[1]
+				break
+			}
+		}
+	case CloudHypervisor:
+		// --vsock cid=<cid>,socket=<path>
+		for _, opt := range strings.Split(argValue(cmdline, "--vsock"), ",") {
+			if cid, ok := strings.CutPrefix(opt, "cid="); ok {
+				value = cid
+				break
+			}
+		}
+	}
+
+	cid, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(cid)
+}
+
+// firecrackerDefaultID is the ID of firecracker VMs started without --id
+const firecrackerDefaultID = "anonymous-instance"
+
+// firecrackerIDFromCmdLine extracts the VM ID from a firecracker command line,
+// which is also used as its name
+func firecrackerIDFromCmdLine(cmdline []string) string {
+	id := argValue(cmdline, "--id")
+	if id == firecrackerDefaultID {
+		return ""
+	}
+	return id
+}
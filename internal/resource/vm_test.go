@@ -4,6 +4,9 @@
 package resource
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -131,6 +134,58 @@ func TestVMInfoFromCmdLine(t *testing.T) {
 			hypervisor: KVMHypervisor,
 			vmID:       "2f7573722f62696e", // First 16 chars of hex hash
 		},
+	}, {
+		name: "cloud-hypervisor started by Kata Containers",
+		cmdline: []string{
+			"/opt/kata/bin/cloud-hypervisor",
+			"--api-socket", "/run/vc/vm/3f1b0a2c9e8d/clh-api.sock",
+		},
+		expected: expect{
+			hypervisor: CloudHypervisor,
+			vmID:       "3f1b0a2c9e8d",
+		},
+	}, {
+		name: "cloud-hypervisor with api socket options",
+		cmdline: []string{
+			"cloud-hypervisor",
+			"--api-socket=path=/var/lib/ch/vm-42/api.sock,fd=3",
+			"--kernel", "/boot/vmlinux",
+		},
+		expected: expect{
+			hypervisor: CloudHypervisor,
+			vmID:       "vm-42",
+		},
+	}, {
+		name: "firecracker with id",
+		cmdline: []string{
+			"/usr/bin/firecracker",
+			"--id", "fc-vm-1",
+			"--api-sock", "/run/firecracker.socket",
+		},
+		expected: expect{
+			hypervisor: FirecrackerHypervisor,
+			vmID:       "fc-vm-1",
+		},
+	}, {
+		name: "firecracker with default id (generates hash-based ID)",
+		cmdline: []string{
+			"/usr/bin/firecracker",
+			"--id=anonymous-instance",
+		},
+		expected: expect{
+			hypervisor: FirecrackerHypervisor,
+			vmID:       "2f7573722f62696e",
+		},
+	}, {
+		name: "Editing a file named after a hypervisor",
+		cmdline: []string{
+			"/usr/bin/vim",
+			"/tmp/firecracker",
+		},
+		expected: expect{
+			hypervisor: UnknownHypervisor,
+			vmID:       "",
+		},
 	}}
 
 	for _, tc := range tests {
@@ -317,6 +372,34 @@ func TestVMInfoFromProc(t *testing.T) {
 			},
 			error: false,
 		},
+	}, {
+		name: "firecracker VM",
+		cmdline: []string{
+			"/usr/bin/firecracker",
+			"--id", "fc1",
+		},
+		expected: expect{
+			vm: &VirtualMachine{
+				ID:         "fc1",
+				Name:       "fc1",
+				Hypervisor: FirecrackerHypervisor,
+			},
+			error: false,
+		},
+	}, {
+		name: "cloud-hypervisor VM with short ID (generates name)",
+		cmdline: []string{
+			"/usr/bin/cloud-hypervisor",
+			"--api-socket", "/run/ch/vm1/api.sock",
+		},
+		expected: expect{
+			vm: &VirtualMachine{
+				ID:         "vm1",
+				Name:       "cloud-hypervisor-vm1",
+				Hypervisor: CloudHypervisor,
+			},
+			error: false,
+		},
 	}, {
 		name: "Not a VM process",
 		cmdline: []string{
@@ -370,6 +453,7 @@ func TestVMClone(t *testing.T) {
 			ID:           "df12672f-fedb-4f6f-9d51-0166868835fb",
 			Name:         "test-vm",
 			Hypervisor:   KVMHypervisor,
+			CID:          3,
 			CPUTimeDelta: 123.45,
 		}
 
@@ -379,6 +463,7 @@ func TestVMClone(t *testing.T) {
 		assert.Equal(t, original.ID, clone.ID)
 		assert.Equal(t, original.Name, clone.Name)
 		assert.Equal(t, original.Hypervisor, clone.Hypervisor)
+		assert.Equal(t, original.CID, clone.CID)
 		assert.Equal(t, float64(0), clone.CPUTimeDelta) // CPUTime shouldn't be cloned
 	})
 
@@ -440,3 +525,145 @@ func TestVMNameFromCmdLine(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractCloudHypervisorID(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmdline  []string
+		expected string
+	}{
+		{"no api socket", []string{"cloud-hypervisor", "--kernel", "/boot/vmlinux"}, ""},
+		{"relative socket", []string{"cloud-hypervisor", "--api-socket", "api.sock"}, ""},
+		{"socket in root", []string{"cloud-hypervisor", "--api-socket", "/api.sock"}, ""},
+		{"socket path", []string{"cloud-hypervisor", "--api-socket", "/run/vc/vm/abc/clh-api.sock"}, "abc"},
+		{"socket path option", []string{"cloud-hypervisor", "--api-socket", "fd=3,path=/run/ch/vm-2/api.sock"}, "vm-2"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, extractCloudHypervisorID(tc.cmdline))
+		})
+	}
+}
+
+func TestVsockCIDFromCmdLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmdline    []string
+		hypervisor Hypervisor
+		expected   uint32
+	}{
+		{"qemu without vsock", []string{"/usr/bin/qemu-system-x86_64", "-name", "vm"}, KVMHypervisor, 0},
+		{"qemu vsock option", []string{"/usr/bin/qemu-system-x86_64", "-device", "vhost-vsock-pci,id=vsock0,guest-cid=3"}, KVMHypervisor, 3},
+		{
+			"libvirt json device",
+			[]string{"/usr/bin/qemu-system-x86_64", "-device", `{"driver":"vhost-vsock-pci","id":"vsock0","guest-cid":42,"vhostfd":"28"}`},
+			KVMHypervisor, 42,
+		},
+		{"qemu invalid cid", []string{"/usr/bin/qemu-system-x86_64", "-device", "vhost-vsock-pci,guest-cid=99999999999"}, KVMHypervisor, 0},
+		{"cloud-hypervisor vsock", []string{"cloud-hypervisor", "--vsock", "cid=5,socket=/run/ch/vsock.sock"}, CloudHypervisor, 5},
+		{"cloud-hypervisor without vsock", []string{"cloud-hypervisor", "--kernel", "/boot/vmlinux"}, CloudHypervisor, 0},
+		{"firecracker", []string{"firecracker", "--id", "vm-1"}, FirecrackerHypervisor, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, vsockCIDFromCmdLine(tc.cmdline, tc.hypervisor))
+		})
+	}
+}
+
+// writeLibvirtStatus writes the libvirt status file of a domain run by pid
+func writeLibvirtStatus(t *testing.T, dir, name string, pid int, domain string) {
+	t.Helper()
+	status := fmt.Sprintf(`<domstatus state='running' reason='booted' pid='%d'>
+  <domain type='kvm' id='25'>
+    <name>%s</name>
+    %s
+  </domain>
+</domstatus>`, pid, name, domain)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".xml"), []byte(status), 0o644))
+}
+
+func TestVMDetector(t *testing.T) {
+	dir := t.TempDir()
+	writeLibvirtStatus(t, dir, "instance-0000008b", 1234, `<uuid>df12672f-fedb-4f6f-9d51-0166868835fb</uuid>
+    <metadata>
+      <nova:instance xmlns:nova="http://openstack.org/xmlns/libvirt/nova/1.1">
+        <nova:name>web-server</nova:name>
+      </nova:instance>
+    </metadata>`)
+	writeLibvirtStatus(t, dir, "titled", 1235, `<uuid>12345678-1234-5678-9abc-123456789abc</uuid>
+    <title>Build Runner</title>`)
+
+	newQemuProc := func(pid int, cmdline ...string) *MockProcInfo {
+		mockProc := &MockProcInfo{}
+		mockProc.On("PID").Return(pid).Maybe()
+		mockProc.On("CmdLine").Return(append([]string{"/usr/libexec/qemu-kvm"}, cmdline...), nil)
+		return mockProc
+	}
+
+	tests := []struct {
+		name     string
+		detector *vmDetector
+		proc     *MockProcInfo
+		expected *VirtualMachine
+	}{{
+		name:     "OpenStack instance name",
+		detector: &vmDetector{libvirtDir: dir},
+		proc:     newQemuProc(1234, "-name", "guest=instance-0000008b,debug-threads=on"),
+		expected: &VirtualMachine{ID: "df12672f-fedb-4f6f-9d51-0166868835fb", Name: "web-server", Hypervisor: KVMHypervisor},
+	}, {
+		name:     "domain title",
+		detector: &vmDetector{libvirtDir: dir},
+		proc:     newQemuProc(1235, "-name", "guest=titled"),
+		expected: &VirtualMachine{ID: "12345678-1234-5678-9abc-123456789abc", Name: "Build Runner", Hypervisor: KVMHypervisor},
+	}, {
+		name:     "domain run by another process",
+		detector: &vmDetector{libvirtDir: dir},
+		proc:     newQemuProc(999, "-name", "guest=titled"),
+		expected: &VirtualMachine{ID: "titled", Name: "titled", Hypervisor: KVMHypervisor},
+	}, {
+		name:     "not managed by libvirt",
+		detector: &vmDetector{libvirtDir: dir},
+		proc:     newQemuProc(1236, "-name", "guest=standalone"),
+		expected: &VirtualMachine{ID: "standalone", Name: "standalone", Hypervisor: KVMHypervisor},
+	}, {
+		name:     "libvirt lookups disabled",
+		detector: &vmDetector{},
+		proc:     newQemuProc(1234, "-name", "guest=instance-0000008b"),
+		expected: &VirtualMachine{ID: "instance-0000008b", Name: "instance-0000008b", Hypervisor: KVMHypervisor},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vm, err := tc.detector.detect(tc.proc)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, vm)
+		})
+	}
+
+	t.Run("not a VM", func(t *testing.T) {
+		mockProc := &MockProcInfo{}
+		mockProc.On("CmdLine").Return([]string{"/usr/bin/bash"}, nil)
+
+		vm, err := (&vmDetector{libvirtDir: dir}).detect(mockProc)
+		require.NoError(t, err)
+		assert.Nil(t, vm)
+	})
+}
+
+func TestLookupLibvirtDomain(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.xml"), []byte("<domstatus"), 0o644))
+
+	_, err := lookupLibvirtDomain(dir, "broken", 1)
+	assert.ErrorContains(t, err, "failed to parse libvirt domain status")
+
+	_, err = lookupLibvirtDomain(dir, "missing", 1)
+	assert.Error(t, err)
+
+	dom, err := lookupLibvirtDomain(dir, "../broken", 1)
+	assert.NoError(t, err)
+	assert.Nil(t, dom, "names with path separators are ignored")
+}
@@ -0,0 +1,464 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rightsizing reports pods whose CPU requests are much higher than
+// their CPU usage. With the requests idle policy, the idle power of the node is
+// attributed in proportion to CPU requests, so over-provisioned pods are
+// attributed idle power they don't need.
+package rightsizing
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// headroom is the fraction of the peak CPU usage of a pod added to
+	// suggest its CPU request
+	headroom = 0.2
+
+	// overProvisionedRatio is the largest ratio of the suggested to the
+	// current CPU request for which a pod is reported
+	overProvisionedRatio = 0.5
+
+	// minCPURequest is the smallest CPU request suggested, in cores
+	minCPURequest = 0.01
+
+	// minObservation is how long a pod is observed before a suggestion is made
+	minObservation = 5 * time.Minute
+)
+
+// Endpoint is the endpoint the report is served at
+const Endpoint = "/rightsizing"
+
+// ToolName is the name of the MCP tool returning the report
+const ToolName = "rightsizing_report"
+
+type (
+	Monitor      = monitor.Service
+	ToolRegistry = server.ToolRegistry
+)
+
+type APIRegistry interface {
+	Register(endpoint, summary, description string, handler http.Handler) error
+}
+
+// Suggestion is a suggested CPU request of an over-provisioned pod
+type Suggestion struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	OwnerKind string `json:"ownerKind,omitempty"`
+	OwnerName string `json:"ownerName,omitempty"`
+
+	CPURequest          float64 `json:"cpuRequestCores"`
+	AvgCPU              float64 `json:"avgCPUCores"`
+	PeakCPU             float64 `json:"peakCPUCores"`
+	SuggestedCPURequest float64 `json:"suggestedCPURequestCores"`
+
+	Joules          float64 `json:"joules"`          // energy consumed while observed
+	IdleWatts       float64 `json:"idleWatts"`       // average idle power attributed
+	WastedIdleWatts float64 `json:"wastedIdleWatts"` // idle power attributed to the unneeded request
+
+	ObservedSeconds float64 `json:"observedSeconds"`
+}
+
+// Report is the right-sizing report of the pods running on the node
+type Report struct {
+	Timestamp       time.Time    `json:"timestamp"`
+	Pods            int          `json:"pods"` // pods observed
+	WastedIdleWatts float64      `json:"wastedIdleWatts"`
+	Suggestions     []Suggestion `json:"suggestions"` // sorted by wasted idle power, highest first
+}
+
+type Opts struct {
+	logger         *slog.Logger
+	clock          clock.WithTicker
+	interval       time.Duration
+	sampleInterval time.Duration
+	tools          ToolRegistry
+	events         *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:         slog.Default(),
+		clock:          clock.RealClock{},
+		interval:       time.Hour,
+		sampleInterval: 5 * time.Second,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Reporter
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used to sample pods and schedule reports
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithInterval sets the interval between reports in the logs
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithSampleInterval sets the interval between samples of the usage of pods;
+// it should be the interval of the monitor
+func WithSampleInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.sampleInterval = d
+	}
+}
+
+// WithTools sets the registry the report is served by as an MCP tool
+func WithTools(t ToolRegistry) OptionFn {
+	return func(o *Opts) {
+		o.tools = t
+	}
+}
+
+// WithEventBus samples the usage of pods whenever the monitor publishes a new
+// snapshot on bus, rather than every sample interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// podStats is the usage of a pod accumulated since it was first observed
+type podStats struct {
+	name, namespace      string
+	ownerKind, ownerName string
+	cpuRequest           float64
+
+	firstSeen, lastSeen time.Time
+	firstCPU, lastCPU   float64
+	peakCPU             float64 // highest CPU usage in cores between two samples
+
+	firstEnergy, lastEnergy monitor.Energy
+	idleJoules              float64
+}
+
+// Reporter accumulates the CPU usage and idle power of pods over time, and
+// periodically logs right-sizing suggestions for over-provisioned pods. The
+// report is also served at /rightsizing and, if tools are set, as an MCP tool
+type Reporter struct {
+	logger         *slog.Logger
+	monitor        Monitor
+	api            APIRegistry
+	tools          ToolRegistry
+	clock          clock.WithTicker
+	interval       time.Duration
+	sampleInterval time.Duration
+	events         *event.Bus
+	snapshots      *event.Subscription // nil if sampled every sample interval
+
+	mu   sync.Mutex
+	pods map[string]*podStats // keyed by pod ID
+}
+
+var (
+	_ service.Initializer = (*Reporter)(nil)
+	_ service.Runner      = (*Reporter)(nil)
+	_ service.Dependent   = (*Reporter)(nil)
+)
+
+// NewReporter creates a new Reporter of the pods of pm that serves the report
+// using api
+func NewReporter(pm Monitor, api APIRegistry, applyOpts ...OptionFn) *Reporter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Reporter{
+		logger:         opts.logger.With("service", "rightsizing"),
+		monitor:        pm,
+		api:            api,
+		tools:          opts.tools,
+		clock:          opts.clock,
+		interval:       opts.interval,
+		sampleInterval: opts.sampleInterval,
+		events:         opts.events,
+		pods:           map[string]*podStats{},
+	}
+}
+
+func (r *Reporter) Name() string {
+	return "rightsizing"
+}
+
+// Dependencies returns the monitor, and the API server and MCP tools the
+// report is served by
+func (r *Reporter) Dependencies() []service.Service {
+	deps := []service.Service{r.monitor}
+	if s, ok := r.api.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	if s, ok := r.tools.(service.Service); ok {
+		deps = append(deps, s)
+	}
+	return deps
+}
+
+func (r *Reporter) Init() error {
+	if r.events != nil {
+		r.snapshots = r.events.Subscribe(r.Name(), 1, event.SnapshotReady)
+	}
+	if err := r.api.Register(Endpoint, "Right-sizing", "Over-provisioned pods and suggested CPU requests", http.HandlerFunc(r.handleReport)); err != nil {
+		return err
+	}
+	if r.tools == nil {
+		return nil
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"namespace": map[string]any{
+				"type":        "string",
+				"description": "Only report the pods of the namespace",
+			},
+		},
+	}
+	return r.tools.RegisterTool(ToolName,
+		"Pods on the node whose CPU requests are much higher than their peak CPU usage, "+
+			"with suggested CPU requests and the idle power attributed to the unneeded requests",
+		schema, r.callTool)
+}
+
+// Run samples the usage of pods and logs the report periodically until ctx is
+// cancelled
+func (r *Reporter) Run(ctx context.Context) error {
+	var ready <-chan event.Event
+	if r.snapshots != nil {
+		defer r.snapshots.Close()
+		ready = r.snapshots.C()
+	}
+	var tick <-chan time.Time
+	if ready == nil {
+		ticker := r.clock.NewTicker(r.sampleInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	lastReport := r.clock.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-ready:
+		}
+		r.sample()
+		if r.clock.Since(lastReport) >= r.interval {
+			r.logReport(r.Report(""))
+			lastReport = r.clock.Now()
+		}
+	}
+}
+
+func (r *Reporter) sample() {
+	snapshot, err := r.monitor.LatestSnapshot()
+	if err != nil {
+		r.logger.Warn("Failed to get snapshot", "error", err)
+		return
+	}
+	r.observe(snapshot)
+}
+
+// observe accumulates the usage of the running pods of snapshot; pods that are
+// no longer running are forgotten
+func (r *Reporter) observe(snapshot *monitor.Snapshot) {
+	zone := primaryZone(snapshot.Node)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, pod := range snapshot.Pods {
+		usage := pod.Zones[zone]
+		stats, ok := r.pods[id]
+		if !ok {
+			r.pods[id] = &podStats{
+				firstSeen:   snapshot.Timestamp,
+				lastSeen:    snapshot.Timestamp,
+				firstCPU:    pod.CPUTotalTime,
+				lastCPU:     pod.CPUTotalTime,
+				firstEnergy: usage.EnergyTotal,
+				lastEnergy:  usage.EnergyTotal,
+			}
+			stats = r.pods[id]
+		} else if dt := snapshot.Timestamp.Sub(stats.lastSeen).Seconds(); dt > 0 {
+			stats.peakCPU = max(stats.peakCPU, (pod.CPUTotalTime-stats.lastCPU)/dt)
+			stats.idleJoules += usage.IdlePower.Watts() * dt
+			stats.lastSeen = snapshot.Timestamp
+			stats.lastCPU = pod.CPUTotalTime
+			stats.lastEnergy = usage.EnergyTotal
+		}
+		stats.name, stats.namespace = pod.Name, pod.Namespace
+		stats.ownerKind, stats.ownerName = pod.OwnerKind, pod.OwnerName
+		stats.cpuRequest = pod.CPURequest
+	}
+
+	for id := range r.pods {
+		if _, ok := snapshot.Pods[id]; !ok {
+			delete(r.pods, id)
+		}
+	}
+}
+
+// primaryZone returns the zone of the node that consumed the most energy,
+// e.g. psys or package, since zones overlap on some platforms
+func primaryZone(node *monitor.Node) monitor.EnergyZone {
+	if node == nil {
+		return nil
+	}
+	var primary monitor.EnergyZone
+	var energy monitor.Energy
+	for zone, usage := range node.Zones {
+		if primary == nil || usage.EnergyTotal > energy ||
+			(usage.EnergyTotal == energy && zone.Name() < primary.Name()) {
+			primary, energy = zone, usage.EnergyTotal
+		}
+	}
+	return primary
+}
+
+// Report returns the right-sizing report of the pods of namespace, or of all
+// pods if namespace is empty
+func (r *Reporter) Report(namespace string) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := Report{
+		Timestamp:   r.clock.Now(),
+		Suggestions: []Suggestion{},
+	}
+	for _, stats := range r.pods {
+		if namespace != "" && stats.namespace != namespace {
+			continue
+		}
+		report.Pods++
+		if s, ok := stats.suggest(); ok {
+			report.Suggestions = append(report.Suggestions, s)
+			report.WastedIdleWatts += s.WastedIdleWatts
+		}
+	}
+
+	slices.SortFunc(report.Suggestions, func(a, b Suggestion) int {
+		return cmp.Or(
+			cmp.Compare(b.WastedIdleWatts, a.WastedIdleWatts),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Pod, b.Pod),
+		)
+	})
+	return report
+}
+
+// suggest returns the suggested CPU request of the pod if it is
+// over-provisioned, i.e. its request is at least twice the request needed for
+// its peak CPU usage with headroom
+func (s *podStats) suggest() (Suggestion, bool) {
+	observed := s.lastSeen.Sub(s.firstSeen)
+	if s.cpuRequest <= 0 || observed < minObservation {
+		return Suggestion{}, false
+	}
+
+	// round up to 10 millicores
+	suggested := max(math.Ceil(s.peakCPU*(1+headroom)*100)/100, minCPURequest)
+	if suggested > s.cpuRequest*overProvisionedRatio {
+		return Suggestion{}, false
+	}
+
+	var joules float64
+	if s.lastEnergy > s.firstEnergy {
+		joules = (s.lastEnergy - s.firstEnergy).Joules()
+	}
+	idleWatts := s.idleJoules / observed.Seconds()
+	return Suggestion{
+		Pod:                 s.name,
+		Namespace:           s.namespace,
+		OwnerKind:           s.ownerKind,
+		OwnerName:           s.ownerName,
+		CPURequest:          s.cpuRequest,
+		AvgCPU:              (s.lastCPU - s.firstCPU) / observed.Seconds(),
+		PeakCPU:             s.peakCPU,
+		SuggestedCPURequest: suggested,
+		Joules:              joules,
+		IdleWatts:           idleWatts,
+		WastedIdleWatts:     idleWatts * (1 - suggested/s.cpuRequest),
+		ObservedSeconds:     observed.Seconds(),
+	}, true
+}
+
+func (r *Reporter) logReport(report Report) {
+	if len(report.Suggestions) == 0 {
+		r.logger.Debug("No over-provisioned pods", "pods", report.Pods)
+		return
+	}
+
+	r.logger.Info("Found over-provisioned pods",
+		"pods", report.Pods,
+		"over-provisioned", len(report.Suggestions),
+		"wasted-idle-watts", report.WastedIdleWatts)
+	for _, s := range report.Suggestions {
+		r.logger.Info("Pod is over-provisioned",
+			"pod", s.Pod,
+			"namespace", s.Namespace,
+			"cpu-request", s.CPURequest,
+			"peak-cpu", s.PeakCPU,
+			"suggested-cpu-request", s.SuggestedCPURequest,
+			"wasted-idle-watts", s.WastedIdleWatts)
+	}
+}
+
+// handleReport serves the report of all pods, or of the pods of the namespace
+// query parameter
+func (r *Reporter) handleReport(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(r.Report(req.URL.Query().Get("namespace"))); err != nil {
+		r.logger.Error("Failed to write report", "error", err)
+	}
+}
+
+func (r *Reporter) callTool(_ context.Context, args json.RawMessage) (any, error) {
+	var params struct {
+		Namespace string `json:"namespace"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, err
+		}
+	}
+	return r.Report(params.Namespace), nil
+}
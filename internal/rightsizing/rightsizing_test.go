@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package rightsizing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/server"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var (
+	pkg  = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+	dram = device.NewMockRaplZone("dram", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0:1", 1000*device.Joule)
+)
+
+// podUsage is the CPU request and usage, and the idle power of a pod
+type podUsage struct {
+	namespace, name string
+	request, cores  float64
+	idleWatts       float64
+}
+
+// snapshot returns the snapshot of pods elapsed after start
+func snapshot(start time.Time, elapsed time.Duration, pods map[string]podUsage) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = start.Add(elapsed)
+	s.Node = &monitor.Node{
+		Timestamp: s.Timestamp,
+		Zones: monitor.NodeZoneUsageMap{
+			pkg:  {EnergyTotal: 1000 * device.Joule},
+			dram: {EnergyTotal: 100 * device.Joule},
+		},
+	}
+	for id, p := range pods {
+		s.Pods[id] = &monitor.Pod{
+			ID:           id,
+			Name:         p.name,
+			Namespace:    p.namespace,
+			OwnerKind:    "Deployment",
+			OwnerName:    p.name,
+			CPURequest:   p.request,
+			CPUTotalTime: p.cores * elapsed.Seconds(),
+			Zones: monitor.ZoneUsageMap{
+				pkg: {
+					EnergyTotal: monitor.Energy(p.idleWatts*elapsed.Seconds()) * device.Joule,
+					IdlePower:   monitor.Power(p.idleWatts) * device.Watt,
+				},
+				dram: {IdlePower: 100 * device.Watt},
+			},
+		}
+	}
+	return s
+}
+
+func TestReporter(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	pm := monitor.NewFakeMonitor(nil)
+	registry := server.NewFakeRegistry()
+
+	r := NewReporter(pm, registry, WithClock(fakeClock), WithTools(registry))
+	assert.Equal(t, "rightsizing", r.Name())
+	assert.Equal(t, "monitor", r.Dependencies()[0].Name())
+	require.NoError(t, r.Init())
+	require.Contains(t, registry.Handlers, Endpoint)
+	require.Contains(t, registry.Tools, ToolName)
+
+	pods := map[string]podUsage{
+		"idle":   {namespace: "apps", name: "idle", request: 2, cores: 0.1, idleWatts: 4},
+		"busy":   {namespace: "apps", name: "busy", request: 1, cores: 0.9, idleWatts: 2},
+		"small":  {namespace: "tools", name: "small", request: 0.5, cores: 0.05, idleWatts: 1},
+		"no-req": {namespace: "tools", name: "no-req", cores: 0.01},
+	}
+	for _, elapsed := range []time.Duration{0, 5 * time.Minute, 10 * time.Minute} {
+		r.observe(snapshot(start, elapsed, pods))
+	}
+
+	report := r.Report("")
+	assert.Equal(t, 4, report.Pods)
+	require.Len(t, report.Suggestions, 2, "busy is not over-provisioned and no-req has no request")
+
+	idle := report.Suggestions[0]
+	assert.Equal(t, "idle", idle.Pod)
+	assert.Equal(t, "apps", idle.Namespace)
+	assert.Equal(t, "Deployment", idle.OwnerKind)
+	assert.InDelta(t, 0.1, idle.AvgCPU, 0.001)
+	assert.InDelta(t, 0.1, idle.PeakCPU, 0.001)
+	assert.InDelta(t, 0.12, idle.SuggestedCPURequest, 0.001)
+	assert.InDelta(t, 4.0, idle.IdleWatts, 0.001, "idle power of the primary zone")
+	assert.InDelta(t, 4*(1-0.12/2), idle.WastedIdleWatts, 0.001)
+	assert.InDelta(t, 2400.0, idle.Joules, 1)
+	assert.InDelta(t, 600.0, idle.ObservedSeconds, 0.001)
+
+	small := report.Suggestions[1]
+	assert.Equal(t, "small", small.Pod)
+	assert.InDelta(t, 0.06, small.SuggestedCPURequest, 0.001)
+	assert.InDelta(t, idle.WastedIdleWatts+small.WastedIdleWatts, report.WastedIdleWatts, 0.001)
+
+	t.Run("namespace", func(t *testing.T) {
+		report := r.Report("tools")
+		assert.Equal(t, 2, report.Pods)
+		require.Len(t, report.Suggestions, 1)
+		assert.Equal(t, "small", report.Suggestions[0].Pod)
+	})
+
+	t.Run("REST", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rightsizing?namespace=apps", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got Report
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Equal(t, 2, got.Pods)
+		require.Len(t, got.Suggestions, 1)
+		assert.Equal(t, "idle", got.Suggestions[0].Pod)
+
+		rec = httptest.NewRecorder()
+		registry.Handlers[Endpoint].ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rightsizing", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("MCP tool", func(t *testing.T) {
+		got, err := registry.Tools[ToolName](context.Background(), json.RawMessage(`{"namespace":"tools"}`))
+		require.NoError(t, err)
+		assert.Len(t, got.(Report).Suggestions, 1)
+
+		got, err = registry.Tools[ToolName](context.Background(), nil)
+		require.NoError(t, err)
+		assert.Len(t, got.(Report).Suggestions, 2)
+	})
+
+	t.Run("pods not observed long enough", func(t *testing.T) {
+		r := NewReporter(pm, registry, WithClock(fakeClock))
+		r.observe(snapshot(start, 0, pods))
+		r.observe(snapshot(start, time.Minute, pods))
+		assert.Empty(t, r.Report("").Suggestions)
+	})
+
+	t.Run("terminated pods are forgotten", func(t *testing.T) {
+		r.observe(snapshot(start, 15*time.Minute, map[string]podUsage{"busy": pods["busy"]}))
+		report := r.Report("")
+		assert.Equal(t, 1, report.Pods)
+		assert.Empty(t, report.Suggestions)
+	})
+}
+
+func TestReporterRun(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	pm := monitor.NewFakeMonitor(snapshot(start, 0, map[string]podUsage{
+		"idle": {namespace: "apps", name: "idle", request: 2, cores: 0.1, idleWatts: 4},
+	}))
+	r := NewReporter(pm, server.NewFakeRegistry(), WithClock(fakeClock), WithSampleInterval(time.Second), WithInterval(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, time.Millisecond)
+	fakeClock.Step(time.Second)
+	require.Eventually(t, func() bool { return r.Report("").Pods == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestReporterEvents(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	bus := event.NewBus()
+	pm := monitor.NewFakeMonitor(snapshot(start, 0, map[string]podUsage{
+		"idle": {namespace: "apps", name: "idle", request: 2, cores: 0.1, idleWatts: 4},
+	}))
+	r := NewReporter(pm, server.NewFakeRegistry(), WithEventBus(bus))
+	require.NoError(t, r.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+	bus.Publish(event.Event{Kind: event.SnapshotReady})
+	assert.Eventually(t, func() bool { return r.Report("").Pods == 1 }, time.Second, time.Millisecond,
+		"the pods are sampled when a snapshot is ready")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
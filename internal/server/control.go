@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/sustainable-computing-io/kepler/internal/logger"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+// lifecycleDisabled is the error of requests changing Kepler while lifecycle is
+// disabled
+const lifecycleDisabled = "lifecycle API is not enabled; start Kepler with --web.enable-lifecycle"
+
+// ReloadFn reloads the configuration and applies the settings that can be
+// changed at runtime
+type ReloadFn func() error
+
+// control reloads the configuration on SIGHUP or a request to /-/reload, and
+// reads and changes the log level at /-/loglevel. Since the endpoints are not
+// authenticated, requests changing Kepler are forbidden unless lifecycle is
+// enabled; SIGHUP always reloads the configuration.
+type control struct {
+	logger    *slog.Logger
+	api       APIService
+	reload    ReloadFn
+	lifecycle bool
+
+	// mu serializes reloads
+	mu sync.Mutex
+}
+
+var (
+	_ service.Initializer = (*control)(nil)
+	_ service.Runner      = (*control)(nil)
+	_ service.Dependent   = (*control)(nil)
+)
+
+// NewControl creates a service that reloads the configuration with reload;
+// lifecycle enables the requests to /-/reload and PUT requests to /-/loglevel
+func NewControl(api APIService, logger *slog.Logger, reload ReloadFn, lifecycle bool) *control {
+	return &control{
+		logger:    logger.With("service", "control"),
+		api:       api,
+		reload:    reload,
+		lifecycle: lifecycle,
+	}
+}
+
+func (c *control) Name() string {
+	return "control"
+}
+
+// Dependencies returns the API server the endpoints are served by
+func (c *control) Dependencies() []service.Service {
+	return []service.Service{c.api}
+}
+
+func (c *control) Init() error {
+	if err := c.api.Register("/-/reload", "Reload", "Reload the configuration (POST)", http.HandlerFunc(c.handleReload)); err != nil {
+		return err
+	}
+	return c.api.Register("/-/loglevel", "Log level", "Get or set (PUT) the log level", http.HandlerFunc(c.handleLogLevel))
+}
+
+// Run reloads the configuration on every SIGHUP
+func (c *control) Run(ctx context.Context) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-hup:
+			c.logger.Info("Received SIGHUP; reloading configuration")
+			_ = c.reloadConfig()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *control) reloadConfig() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.reload(); err != nil {
+		c.logger.Error("Failed to reload configuration", "error", err)
+		return err
+	}
+	c.logger.Info("Configuration reloaded")
+	return nil
+}
+
+func (c *control) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !c.lifecycle {
+		http.Error(w, lifecycleDisabled, http.StatusForbidden)
+		return
+	}
+	if err := c.reloadConfig(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to reload configuration: %v", err), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, "configuration reloaded")
+}
+
+// handleLogLevel responds with the log level, after setting it to the level
+// in the body of PUT requests
+func (c *control) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if !c.lifecycle {
+			http.Error(w, lifecycleDisabled, http.StatusForbidden)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read log level: %v", err), http.StatusBadRequest)
+			return
+		}
+		level := strings.TrimSpace(string(body))
+		if err := logger.SetLogLevel(level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.logger.Info("Log level changed", "level", level)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "only GET and PUT are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	fmt.Fprintln(w, strings.ToLower(logger.LogLevel().String()))
+}
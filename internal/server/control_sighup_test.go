@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package server
+
+import (
+	"context"
+	"log/slog"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlReloadOnSIGHUP(t *testing.T) {
+	reloaded := make(chan struct{}, 1)
+	c := NewControl(&MockAPIService{}, slog.Default(), func() error {
+		reloaded <- struct{}{}
+		return nil
+	}, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	// signal.Notify is called when Run starts
+	require.Eventually(t, func() bool {
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+		select {
+		case <-reloaded:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sustainable-computing-io/kepler/internal/logger"
+)
+
+func TestControlInit(t *testing.T) {
+	api := &MockAPIService{}
+	c := NewControl(api, slog.Default(), func() error { return nil }, true)
+	assert.Equal(t, "control", c.Name())
+	assert.Equal(t, api, c.Dependencies()[0])
+
+	api.On("Register", "/-/reload", "Reload", mock.Anything, mock.Anything).Return(nil)
+	api.On("Register", "/-/loglevel", "Log level", mock.Anything, mock.Anything).Return(nil)
+	require.NoError(t, c.Init())
+	api.AssertExpectations(t)
+}
+
+func TestControlReload(t *testing.T) {
+	reloads := 0
+	var reloadErr error
+	c := NewControl(&MockAPIService{}, slog.Default(), func() error {
+		reloads++
+		return reloadErr
+	}, true)
+
+	do := func(method string) (int, string) {
+		rec := httptest.NewRecorder()
+		c.handleReload(rec, httptest.NewRequest(method, "/-/reload", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	code, _ := do(http.MethodGet)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	assert.Equal(t, 0, reloads)
+
+	code, body := do(http.MethodPost)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "configuration reloaded\n", body)
+	assert.Equal(t, 1, reloads)
+
+	reloadErr = errors.New("invalid config")
+	code, body = do(http.MethodPost)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, body, "invalid config")
+	assert.Equal(t, 2, reloads)
+}
+
+func TestControlLogLevel(t *testing.T) {
+	require.NoError(t, logger.SetLogLevel("info"))
+	t.Cleanup(func() { _ = logger.SetLogLevel("info") })
+
+	c := NewControl(&MockAPIService{}, slog.Default(), nil, true)
+	do := func(method, body string) (int, string) {
+		rec := httptest.NewRecorder()
+		c.handleLogLevel(rec, httptest.NewRequest(method, "/-/loglevel", strings.NewReader(body)))
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "info\n", body)
+
+	code, body = do(http.MethodPut, "debug\n")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "debug\n", body)
+	assert.Equal(t, slog.LevelDebug, logger.LogLevel())
+
+	code, body = do(http.MethodPut, "verbose")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "invalid log level")
+	assert.Equal(t, slog.LevelDebug, logger.LogLevel())
+
+	code, _ = do(http.MethodDelete, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestControlLifecycleDisabled(t *testing.T) {
+	require.NoError(t, logger.SetLogLevel("info"))
+	t.Cleanup(func() { _ = logger.SetLogLevel("info") })
+
+	reloads := 0
+	c := NewControl(&MockAPIService{}, slog.Default(), func() error {
+		reloads++
+		return nil
+	}, false)
+
+	rec := httptest.NewRecorder()
+	c.handleReload(rec, httptest.NewRequest(http.MethodPost, "/-/reload", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "--web.enable-lifecycle")
+	assert.Equal(t, 0, reloads)
+
+	rec = httptest.NewRecorder()
+	c.handleLogLevel(rec, httptest.NewRequest(http.MethodPut, "/-/loglevel", strings.NewReader("debug")))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, slog.LevelInfo, logger.LogLevel())
+
+	// the log level can still be read
+	rec = httptest.NewRecorder()
+	c.handleLogLevel(rec, httptest.NewRequest(http.MethodGet, "/-/loglevel", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "info\n", rec.Body.String())
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sustainable-computing-io/kepler/internal/service"
+)
+
+// health serves the health of services for liveness and readiness probes. A
+// service is live if it is healthy, and ready once it is also ready
+type health struct {
+	api      APIService
+	services []service.Service
+}
+
+var (
+	_ service.Service     = (*health)(nil)
+	_ service.Initializer = (*health)(nil)
+	_ service.Dependent   = (*health)(nil)
+)
+
+// NewHealth creates a service that serves the health of services at /healthz
+// and their readiness at /readyz
+func NewHealth(api APIService, services ...service.Service) *health {
+	return &health{
+		api:      api,
+		services: services,
+	}
+}
+
+func (h *health) Name() string {
+	return "health"
+}
+
+// Dependencies returns the API server the health is served by
+func (h *health) Dependencies() []service.Service {
+	return []service.Service{h.api}
+}
+
+func (h *health) Init() error {
+	if err := h.api.Register("/healthz", "Health", "Health of services", http.HandlerFunc(h.healthz)); err != nil {
+		return err
+	}
+	return h.api.Register("/readyz", "Readiness", "Readiness of services", http.HandlerFunc(h.readyz))
+}
+
+// healthz reports the health of all services that implement HealthChecker
+func (h *health) healthz(w http.ResponseWriter, _ *http.Request) {
+	report(w, "healthz", h.checkHealth())
+}
+
+// readyz reports the readiness of all services that implement Readier, in
+// addition to their health
+func (h *health) readyz(w http.ResponseWriter, _ *http.Request) {
+	var checks []check
+	for _, s := range h.services {
+		r, ok := s.(service.Readier)
+		if !ok {
+			continue
+		}
+		var err error
+		select {
+		case <-r.Ready():
+		default:
+			err = fmt.Errorf("not ready")
+		}
+		checks = append(checks, check{name: s.Name() + "-ready", err: err})
+	}
+	report(w, "readyz", append(checks, h.checkHealth()...))
+}
+
+// check is the result of checking a service
+type check struct {
+	name string
+	err  error
+}
+
+func (h *health) checkHealth() []check {
+	var checks []check
+	for _, s := range h.services {
+		if hc, ok := s.(service.HealthChecker); ok {
+			checks = append(checks, check{name: s.Name(), err: hc.Health()})
+		}
+	}
+	return checks
+}
+
+// report writes the result of each check, responding with
+// 503 Service Unavailable if any check failed
+func report(w http.ResponseWriter, endpoint string, checks []check) {
+	var out strings.Builder
+	failed := false
+	for _, c := range checks {
+		if c.err != nil {
+			failed = true
+			fmt.Fprintf(&out, "[-]%s failed: %v\n", c.name, c.err)
+			continue
+		}
+		fmt.Fprintf(&out, "[+]%s ok\n", c.name)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if failed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(&out, "%s check failed\n", endpoint)
+	} else {
+		fmt.Fprintf(&out, "%s check passed\n", endpoint)
+	}
+	_, _ = w.Write([]byte(out.String()))
+}
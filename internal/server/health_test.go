@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService implements HealthChecker and Readier
+type fakeService struct {
+	name   string
+	health error
+	ready  chan struct{}
+}
+
+func (f *fakeService) Name() string           { return f.name }
+func (f *fakeService) Health() error          { return f.health }
+func (f *fakeService) Ready() <-chan struct{} { return f.ready }
+
+func TestHealthInit(t *testing.T) {
+	api := &MockAPIService{}
+	h := NewHealth(api)
+	assert.Equal(t, "health", h.Name())
+	assert.Equal(t, api, h.Dependencies()[0])
+
+	api.On("Register", "/healthz", "Health", "Health of services", mock.Anything).Return(nil)
+	api.On("Register", "/readyz", "Readiness", "Readiness of services", mock.Anything).Return(nil)
+	require.NoError(t, h.Init())
+	api.AssertExpectations(t)
+
+	api = &MockAPIService{}
+	api.On("Register", "/healthz", "Health", "Health of services", mock.Anything).Return(errors.New("register error"))
+	assert.EqualError(t, NewHealth(api).Init(), "register error")
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	monitor := &fakeService{name: "monitor", ready: make(chan struct{})}
+	informer := &fakeService{name: "resource-informer", ready: make(chan struct{})}
+	close(informer.ready)
+	h := NewHealth(&MockAPIService{}, monitor, informer)
+
+	get := func(handler http.HandlerFunc) (int, string) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get(h.healthz)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "[+]monitor ok\n[+]resource-informer ok\nhealthz check passed\n", body)
+
+	code, body = get(h.readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "[-]monitor-ready failed: not ready\n")
+	assert.Contains(t, body, "[+]resource-informer-ready ok\n")
+	assert.Contains(t, body, "readyz check failed\n")
+
+	close(monitor.ready)
+	code, _ = get(h.readyz)
+	assert.Equal(t, http.StatusOK, code)
+
+	informer.health = errors.New("failed to read node usage")
+	code, body = get(h.healthz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "[-]resource-informer failed: failed to read node usage\n")
+	code, _ = get(h.readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code, "unhealthy services are not ready")
+}
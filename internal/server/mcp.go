@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"github.com/sustainable-computing-io/kepler/internal/version"
+)
+
+// mcpProtocolVersion is the version of the Model Context Protocol served
+const mcpProtocolVersion = "2025-03-26"
+
+// JSON-RPC error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+)
+
+// ToolFn handles a call of an MCP tool with its arguments; the result is
+// returned to the client as JSON text
+type ToolFn func(ctx context.Context, args json.RawMessage) (any, error)
+
+// ToolRegistry registers tools that are served over MCP
+type ToolRegistry interface {
+	RegisterTool(name, description string, inputSchema map[string]any, fn ToolFn) error
+}
+
+// MCP serves tools over the Model Context Protocol (streamable HTTP transport,
+// without sessions or server sent events) at /mcp, so that AI assistants can
+// query Kepler. Tools are registered by other services
+type MCP struct {
+	api APIService
+
+	mu    sync.RWMutex
+	tools map[string]tool
+}
+
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	fn          ToolFn
+}
+
+var (
+	_ service.Service     = (*MCP)(nil)
+	_ service.Initializer = (*MCP)(nil)
+	_ service.Dependent   = (*MCP)(nil)
+	_ ToolRegistry        = (*MCP)(nil)
+)
+
+// NewMCP creates a service that serves the tools registered with it over MCP
+func NewMCP(api APIService) *MCP {
+	return &MCP{
+		api:   api,
+		tools: map[string]tool{},
+	}
+}
+
+func (m *MCP) Name() string {
+	return "mcp"
+}
+
+// Dependencies returns the API server MCP is served by
+func (m *MCP) Dependencies() []service.Service {
+	return []service.Service{m.api}
+}
+
+func (m *MCP) Init() error {
+	return m.api.Register("/mcp", "MCP", "Model Context Protocol tools", http.HandlerFunc(m.handle))
+}
+
+// RegisterTool registers a tool; inputSchema is the JSON schema of its
+// arguments, which defaults to an object without properties
+func (m *MCP) RegisterTool(name, description string, inputSchema map[string]any, fn ToolFn) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.tools[name]; exists {
+		return fmt.Errorf("tool %s already registered", name)
+	}
+	if inputSchema == nil {
+		inputSchema = map[string]any{"type": "object"}
+	}
+	m.tools[name] = tool{Name: name, Description: description, InputSchema: inputSchema, fn: fn}
+	return nil
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// handle serves a JSON-RPC request; notifications, which have no ID, are
+// accepted without a response
+func (m *MCP) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRPC(w, rpcResponse{Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		writeRPC(w, rpcResponse{ID: req.ID, Error: &rpcError{Code: rpcInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}})
+		return
+	}
+	if len(req.ID) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	result, rpcErr := m.call(r.Context(), req.Method, req.Params)
+	writeRPC(w, rpcResponse{ID: req.ID, Result: result, Error: rpcErr})
+}
+
+func (m *MCP) call(ctx context.Context, method string, params json.RawMessage) (any, *rpcError) {
+	switch method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "kepler", "version": version.Info().Version},
+		}, nil
+
+	case "ping":
+		return map[string]any{}, nil
+
+	case "tools/list":
+		m.mu.RLock()
+		tools := make([]tool, 0, len(m.tools))
+		for _, t := range m.tools {
+			tools = append(tools, t)
+		}
+		m.mu.RUnlock()
+		slices.SortFunc(tools, func(a, b tool) int { return strings.Compare(a.Name, b.Name) })
+		return map[string]any{"tools": tools}, nil
+
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		m.mu.RLock()
+		t, ok := m.tools[p.Name]
+		m.mu.RUnlock()
+		if !ok {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unknown tool %s", p.Name)}
+		}
+		return callTool(ctx, t, p.Arguments)
+
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method %s not found", method)}
+	}
+}
+
+// callTool calls a tool; errors of the tool are reported in the result so
+// that the client can see them, as per MCP
+func callTool(ctx context.Context, t tool, args json.RawMessage) (any, *rpcError) {
+	text := func(s string, isError bool) map[string]any {
+		return map[string]any{
+			"content": []map[string]any{{"type": "text", "text": s}},
+			"isError": isError,
+		}
+	}
+
+	result, err := t.fn(ctx, args)
+	if err != nil {
+		return text(err.Error(), true), nil
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, &rpcError{Code: rpcInternalError, Message: err.Error()}
+	}
+	return text(string(out), false), nil
+}
+
+func writeRPC(w http.ResponseWriter, resp rpcResponse) {
+	resp.JSONRPC = "2.0"
+	if resp.ID == nil {
+		resp.ID = json.RawMessage("null")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCP(t *testing.T) {
+	api := &MockAPIService{}
+	api.On("Register", "/mcp", "MCP", "Model Context Protocol tools", mock.Anything).Return(nil)
+
+	m := NewMCP(api)
+	assert.Equal(t, "mcp", m.Name())
+	require.NoError(t, m.Init())
+	api.AssertExpectations(t)
+
+	require.NoError(t, m.RegisterTool("echo", "Echoes its arguments", nil,
+		func(_ context.Context, args json.RawMessage) (any, error) {
+			var v map[string]any
+			return v, json.Unmarshal(args, &v)
+		}))
+	require.NoError(t, m.RegisterTool("fail", "Always fails", nil,
+		func(context.Context, json.RawMessage) (any, error) {
+			return nil, errors.New("no data")
+		}))
+	assert.ErrorContains(t, m.RegisterTool("echo", "", nil, nil), "already registered")
+
+	post := func(t *testing.T, body string) (int, map[string]any) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		m.handle(rec, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body)))
+		if rec.Body.Len() == 0 {
+			return rec.Code, nil
+		}
+		var resp map[string]any
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return rec.Code, resp
+	}
+
+	t.Run("initialize", func(t *testing.T) {
+		code, resp := post(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.EqualValues(t, 1, resp["id"])
+		result := resp["result"].(map[string]any)
+		assert.Equal(t, mcpProtocolVersion, result["protocolVersion"])
+		assert.Contains(t, result["capabilities"], "tools")
+	})
+
+	t.Run("notification", func(t *testing.T) {
+		code, resp := post(t, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		assert.Equal(t, http.StatusAccepted, code)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("tools/list", func(t *testing.T) {
+		_, resp := post(t, `{"jsonrpc":"2.0","id":"a","method":"tools/list"}`)
+		tools := resp["result"].(map[string]any)["tools"].([]any)
+		require.Len(t, tools, 2)
+		assert.Equal(t, "echo", tools[0].(map[string]any)["name"])
+		assert.Equal(t, map[string]any{"type": "object"}, tools[0].(map[string]any)["inputSchema"])
+		assert.Equal(t, "fail", tools[1].(map[string]any)["name"])
+	})
+
+	t.Run("tools/call", func(t *testing.T) {
+		_, resp := post(t, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"x":1}}}`)
+		result := resp["result"].(map[string]any)
+		assert.Equal(t, false, result["isError"])
+		content := result["content"].([]any)[0].(map[string]any)
+		assert.Equal(t, "text", content["type"])
+		assert.JSONEq(t, `{"x":1}`, content["text"].(string))
+
+		_, resp = post(t, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"fail"}}`)
+		result = resp["result"].(map[string]any)
+		assert.Equal(t, true, result["isError"])
+		assert.Equal(t, "no data", result["content"].([]any)[0].(map[string]any)["text"])
+	})
+
+	tt := []struct {
+		name string
+		body string
+		code float64
+	}{
+		{"unknown tool", `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"unknown"}}`, rpcInvalidParams},
+		{"unknown method", `{"jsonrpc":"2.0","id":5,"method":"resources/list"}`, rpcMethodNotFound},
+		{"invalid version", `{"jsonrpc":"1.0","id":6,"method":"ping"}`, rpcInvalidRequest},
+		{"invalid JSON", `{`, rpcParseError},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, resp := post(t, tc.body)
+			require.Contains(t, resp, "error")
+			assert.Equal(t, tc.code, resp["error"].(map[string]any)["code"])
+		})
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		m.handle(rec, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
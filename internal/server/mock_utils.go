@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"sync"
+)
+
+// FakeRegistry records the handlers and tools registered, like an APIServer
+// with its MCP server, for tests
+type FakeRegistry struct {
+	mu sync.Mutex
+
+	// Handlers are the handlers registered, keyed by endpoint
+	Handlers map[string]http.Handler
+	// Tools are the tools registered, keyed by name
+	Tools map[string]ToolFn
+}
+
+var _ ToolRegistry = (*FakeRegistry)(nil)
+
+// NewFakeRegistry creates a new FakeRegistry with nothing registered
+func NewFakeRegistry() *FakeRegistry {
+	return &FakeRegistry{
+		Handlers: map[string]http.Handler{},
+		Tools:    map[string]ToolFn{},
+	}
+}
+
+func (r *FakeRegistry) Register(endpoint, _, _ string, handler http.Handler) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Handlers[endpoint] = handler
+	return nil
+}
+
+// RegisterGuest registers handler like Register
+func (r *FakeRegistry) RegisterGuest(endpoint, summary, description string, handler http.Handler) error {
+	return r.Register(endpoint, summary, description, handler)
+}
+
+func (r *FakeRegistry) RegisterTool(name, _ string, _ map[string]any, fn ToolFn) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Tools[name] = fn
+	return nil
+}
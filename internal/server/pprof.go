@@ -17,6 +17,7 @@ type pp struct {
 var (
 	_ service.Service     = (*pp)(nil)
 	_ service.Initializer = (*pp)(nil)
+	_ service.Dependent   = (*pp)(nil)
 )
 
 func NewPprof(api APIService) *pp {
@@ -29,6 +30,11 @@ func (p *pp) Name() string {
 	return "pprof"
 }
 
+// Dependencies returns the API server the profiles are served by
+func (p *pp) Dependencies() []service.Service {
+	return []service.Service{p.api}
+}
+
 func (p *pp) Init() error {
 	return p.api.Register("/debug/pprof/", "pprof", "Profiling Data", handlers())
 }
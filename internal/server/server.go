@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/prometheus/exporter-toolkit/web"
@@ -31,6 +32,10 @@ type APIServer struct {
 	mux                 *http.ServeMux
 	endpointDescription string
 	webCfgPath          string
+
+	// guestEndpoints are the endpoints served to VMs over vsock
+	guestMu        sync.RWMutex
+	guestEndpoints []string
 }
 
 var _ APIService = (*APIServer)(nil)
@@ -81,16 +86,16 @@ func NewAPIServer(applyOpts ...OptionFn) *APIServer {
 	}
 
 	mux := http.NewServeMux()
-	server := &http.Server{
-		Handler: mux,
-	}
 	apiServer := &APIServer{
 		logger:      opts.logger.With("service", "api-server"),
 		listenAddrs: opts.listenAddrs,
 		mux:         mux,
-		server:      server,
 		webCfgPath:  opts.webCfgPath,
 	}
+	apiServer.server = &http.Server{
+		Handler:     apiServer.guestGuard(mux),
+		ConnContext: withVsockPeer,
+	}
 
 	return apiServer
 }
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/mdlayher/vsock"
+)
+
+// vsockPeerKey is the context key of the context ID of vsock peers
+type vsockPeerKey struct{}
+
+// withVsockPeer returns ctx with the context ID of the peer of c if c is a
+// vsock connection
+func withVsockPeer(ctx context.Context, c net.Conn) context.Context {
+	if addr, ok := c.RemoteAddr().(*vsock.Addr); ok {
+		return ContextWithVsockPeer(ctx, addr.ContextID)
+	}
+	return ctx
+}
+
+// ContextWithVsockPeer returns ctx of a request received over vsock from the
+// VM of context ID cid
+func ContextWithVsockPeer(ctx context.Context, cid uint32) context.Context {
+	return context.WithValue(ctx, vsockPeerKey{}, cid)
+}
+
+// VsockPeer returns the context ID of the VM a request was received from over
+// vsock; ok is false for requests received over the network
+func VsockPeer(ctx context.Context) (cid uint32, ok bool) {
+	cid, ok = ctx.Value(vsockPeerKey{}).(uint32)
+	return cid, ok
+}
+
+// guestGuard serves requests received over vsock only if they are for one of
+// the guest endpoints, so that VMs can't read the metrics of the host, which
+// include all VMs
+func (s *APIServer) guestGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := VsockPeer(r.Context()); ok && !s.isGuestEndpoint(r.URL.Path) {
+			http.Error(w, "endpoint not served over vsock", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isGuestEndpoint returns true if path is served by one of the guest
+// endpoints; endpoints ending with / serve the paths below them
+func (s *APIServer) isGuestEndpoint(path string) bool {
+	s.guestMu.RLock()
+	defer s.guestMu.RUnlock()
+
+	for _, endpoint := range s.guestEndpoints {
+		if path == endpoint || (strings.HasSuffix(endpoint, "/") && strings.HasPrefix(path, endpoint)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterGuest registers handler like Register and also serves it to VMs
+// over vsock, to which other endpoints are not served
+func (s *APIServer) RegisterGuest(endpoint, summary, description string, handler http.Handler) error {
+	if err := s.Register(endpoint, summary, description, handler); err != nil {
+		return err
+	}
+	s.guestMu.Lock()
+	defer s.guestMu.Unlock()
+	s.guestEndpoints = append(s.guestEndpoints, endpoint)
+	return nil
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mdlayher/vsock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteConn is a connection from addr
+type remoteConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c remoteConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+func TestVsockPeer(t *testing.T) {
+	ctx := withVsockPeer(context.Background(), remoteConn{addr: &vsock.Addr{ContextID: 3, Port: 1234}})
+	cid, ok := VsockPeer(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(3), cid)
+
+	ctx = withVsockPeer(context.Background(), remoteConn{addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}})
+	_, ok = VsockPeer(ctx)
+	assert.False(t, ok)
+}
+
+func TestGuestEndpoints(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	s := NewAPIServer()
+	require.NoError(t, s.Register("/metrics", "Metrics", "Prometheus metrics", ok))
+	require.NoError(t, s.RegisterGuest("/vms/", "VMs", "Power of a VM", ok))
+
+	vsockCtx := ContextWithVsockPeer(context.Background(), 3)
+	tt := []struct {
+		name   string
+		ctx    context.Context
+		path   string
+		status int
+	}{
+		{name: "metrics over the network", ctx: context.Background(), path: "/metrics", status: http.StatusOK},
+		{name: "guest endpoint over the network", ctx: context.Background(), path: "/vms/vm-1", status: http.StatusOK},
+		{name: "metrics over vsock", ctx: vsockCtx, path: "/metrics", status: http.StatusForbidden},
+		{name: "landing page over vsock", ctx: vsockCtx, path: "/", status: http.StatusForbidden},
+		{name: "guest endpoint over vsock", ctx: vsockCtx, path: "/vms/vm-1", status: http.StatusOK},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil).WithContext(tc.ctx)
+			rec := httptest.NewRecorder()
+			s.server.Handler.ServeHTTP(rec, req)
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
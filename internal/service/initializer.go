@@ -7,16 +7,23 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 )
 
-// Init initializes all services that implement the Initializer interface.
-// If any service fails to initialize, it will shut down all previously initialized services
-// that implement the Shutdowner interface.
+// Init initializes all services that implement the Initializer interface after
+// the services they depend on. If any service fails to initialize, it will shut
+// down all previously initialized services that implement the Shutdowner
+// interface in reverse order.
 func Init(logger *slog.Logger, services []Service) error {
 	if logger == nil {
 		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
 	}
 
+	services, err := Order(services)
+	if err != nil {
+		return err
+	}
+
 	var retErr error
 	initialized := make([]Service, 0, len(services))
 
@@ -41,7 +48,7 @@ func Init(logger *slog.Logger, services []Service) error {
 	}
 
 	logger.Info("Shutting down initialized services")
-	for _, s := range initialized {
+	for _, s := range slices.Backward(initialized) {
 		srv, ok := s.(Shutdowner)
 		if !ok {
 			logger.Debug("skipping service shutdown", "service", s.Name(),
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dependencies returns the dependencies of s that are in services
+func dependencies(s Service, services map[Service]bool) []Service {
+	dependent, ok := s.(Dependent)
+	if !ok {
+		return nil
+	}
+
+	var deps []Service
+	for _, dep := range dependent.Dependencies() {
+		if dep != nil && services[dep] && dep != s {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Order returns services ordered such that every service comes after the
+// services it depends on, and otherwise in the order they are given. It
+// returns an error if dependencies are cyclic.
+//
+// NOTE: services are compared by identity, so they must be comparable, e.g.
+// pointers
+func Order(services []Service) ([]Service, error) {
+	managed := make(map[Service]bool, len(services))
+	for _, s := range services {
+		managed[s] = true
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[Service]int, len(services))
+	ordered := make([]Service, 0, len(services))
+
+	var visit func(s Service, path []string) error
+	visit = func(s Service, path []string) error {
+		switch state[s] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("cyclic service dependencies: %s", strings.Join(append(path, s.Name()), " -> "))
+		}
+
+		state[s] = visiting
+		for _, dep := range dependencies(s, managed) {
+			if err := visit(dep, append(path, s.Name())); err != nil {
+				return err
+			}
+		}
+		state[s] = visited
+		ordered = append(ordered, s)
+		return nil
+	}
+
+	for _, s := range services {
+		if err := visit(s, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// readiness returns the ready channels of the Readier runners that s depends
+// on, directly or through other services in services
+func readiness(s Service, services map[Service]bool) []<-chan struct{} {
+	var ready []<-chan struct{}
+	seen := map[Service]bool{s: true}
+
+	pending := dependencies(s, services)
+	for len(pending) > 0 {
+		dep := pending[0]
+		pending = pending[1:]
+		if seen[dep] {
+			continue
+		}
+		seen[dep] = true
+
+		// only runners become ready
+		if _, ok := dep.(Runner); ok {
+			if r, ok := dep.(Readier); ok {
+				ready = append(ready, r.Ready())
+			}
+		}
+		pending = append(pending, dependencies(dep, services)...)
+	}
+	return ready
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// events records the calls made to services in the order they are made
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.list...)
+}
+
+// mockDependentService implements Initializer, Runner, Shutdowner, Dependent
+// and Readier, and records calls to events
+type mockDependentService struct {
+	mockService
+	deps   []Service
+	events *events
+	initFn func() error
+	runFn  func(ctx context.Context) error
+	ready  chan struct{}
+}
+
+func (m *mockDependentService) Dependencies() []Service {
+	return m.deps
+}
+
+func (m *mockDependentService) Init() error {
+	m.events.add("init " + m.name)
+	if m.initFn != nil {
+		return m.initFn()
+	}
+	return nil
+}
+
+func (m *mockDependentService) Run(ctx context.Context) error {
+	m.events.add("run " + m.name)
+	if m.runFn != nil {
+		return m.runFn(ctx)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (m *mockDependentService) Shutdown() error {
+	m.events.add("shutdown " + m.name)
+	return nil
+}
+
+func (m *mockDependentService) Ready() <-chan struct{} {
+	return m.ready
+}
+
+// mockDrainerService is a mockDependentService that implements Drainer
+type mockDrainerService struct {
+	mockDependentService
+}
+
+func (m *mockDrainerService) Drain() error {
+	m.events.add("drain " + m.name)
+	return nil
+}
+
+func names(services []Service) []string {
+	ret := make([]string, len(services))
+	for i, s := range services {
+		ret[i] = s.Name()
+	}
+	return ret
+}
+
+func TestOrder(t *testing.T) {
+	t.Run("dependencies come first", func(t *testing.T) {
+		a := &mockDependentService{mockService: mockService{name: "a"}}
+		b := &mockDependentService{mockService: mockService{name: "b"}, deps: []Service{a}}
+		c := &mockDependentService{mockService: mockService{name: "c"}, deps: []Service{b}}
+		d := &mockService{name: "d"}
+
+		ordered, err := Order([]Service{c, d, b, a})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c", "d"}, names(ordered))
+	})
+
+	t.Run("order is kept without dependencies", func(t *testing.T) {
+		a := &mockService{name: "a"}
+		b := &mockService{name: "b"}
+		c := &mockService{name: "c"}
+
+		ordered, err := Order([]Service{c, a, b})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c", "a", "b"}, names(ordered))
+	})
+
+	t.Run("unmanaged, nil and self dependencies are ignored", func(t *testing.T) {
+		unmanaged := &mockService{name: "unmanaged"}
+		a := &mockDependentService{mockService: mockService{name: "a"}}
+		a.deps = []Service{unmanaged, nil, a}
+
+		ordered, err := Order([]Service{a})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, names(ordered))
+	})
+
+	t.Run("cyclic dependencies", func(t *testing.T) {
+		a := &mockDependentService{mockService: mockService{name: "a"}}
+		b := &mockDependentService{mockService: mockService{name: "b"}, deps: []Service{a}}
+		c := &mockDependentService{mockService: mockService{name: "c"}, deps: []Service{b}}
+		a.deps = []Service{c}
+
+		_, err := Order([]Service{a, b, c})
+		assert.EqualError(t, err, "cyclic service dependencies: a -> c -> b -> a")
+	})
+}
+
+func TestInitOrder(t *testing.T) {
+	ev := &events{}
+	initErr := errors.New("init error")
+	a := &mockDependentService{mockService: mockService{name: "a"}, events: ev}
+	b := &mockDependentService{mockService: mockService{name: "b"}, events: ev, deps: []Service{a}}
+	c := &mockDependentService{mockService: mockService{name: "c"}, events: ev, deps: []Service{b},
+		initFn: func() error { return initErr }}
+
+	err := Init(nil, []Service{c, b, a})
+	assert.ErrorIs(t, err, initErr)
+	assert.Equal(t, []string{
+		"init a", "init b", "init c",
+		"shutdown b", "shutdown a",
+	}, ev.get())
+}
+
+func TestRunOrder(t *testing.T) {
+	t.Run("services wait for their dependencies to be ready", func(t *testing.T) {
+		ev := &events{}
+		ready := make(chan struct{})
+		a := &mockDependentService{mockService: mockService{name: "a"}, events: ev, ready: ready}
+		b := &mockDependentService{mockService: mockService{name: "b"}, events: ev, deps: []Service{a}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errCh := make(chan error)
+		go func() {
+			errCh <- Run(ctx, nil, []Service{b, a})
+		}()
+
+		assert.Eventually(t, func() bool { return len(ev.get()) == 1 }, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, []string{"run a"}, ev.get(), "b must not run before a is ready")
+
+		close(ready)
+		assert.Eventually(t, func() bool { return len(ev.get()) == 2 }, time.Second, time.Millisecond)
+
+		cancel()
+		require.NoError(t, <-errCh)
+		assert.Equal(t, []string{"run a", "run b", "shutdown b", "shutdown a"}, ev.get())
+	})
+
+	t.Run("waiting services stop when cancelled", func(t *testing.T) {
+		ev := &events{}
+		a := &mockDependentService{mockService: mockService{name: "a"}, events: ev, ready: make(chan struct{})}
+		b := &mockDependentService{mockService: mockService{name: "b"}, events: ev, deps: []Service{a}}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		require.NoError(t, Run(ctx, nil, []Service{a, b}))
+		assert.Equal(t, []string{"run a", "shutdown b", "shutdown a"}, ev.get())
+	})
+	t.Run("services are drained before any is shut down", func(t *testing.T) {
+		ev := &events{}
+		ready := make(chan struct{})
+		close(ready)
+		a := &mockDrainerService{mockDependentService{mockService: mockService{name: "a"}, events: ev, ready: ready}}
+		b := &mockDrainerService{mockDependentService{mockService: mockService{name: "b"}, events: ev, ready: ready, deps: []Service{a}}}
+		c := &mockDependentService{mockService: mockService{name: "c"}, events: ev, deps: []Service{b}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errCh := make(chan error)
+		go func() {
+			errCh <- Run(ctx, nil, []Service{c, b, a})
+		}()
+
+		assert.Eventually(t, func() bool { return len(ev.get()) == 3 }, time.Second, time.Millisecond)
+		cancel()
+		require.NoError(t, <-errCh)
+		assert.Equal(t, []string{
+			"drain a", "drain b",
+			"shutdown c", "shutdown b", "shutdown a",
+		}, ev.get()[3:])
+	})
+}
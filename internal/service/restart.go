@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// RestartPolicy controls whether a runner is run again when its Run fails.
+// Only Run is called again on restart; the service is neither shut down nor
+// initialized again, so only runners that can be run again should be restarted
+type RestartPolicy struct {
+	// Restart enables restarting the service; when false, a failing service
+	// stops all services
+	Restart bool
+	// MaxRetries is the number of consecutive restarts after which a failing
+	// service stops all services; 0 for unlimited
+	MaxRetries int
+	// Backoff is the delay before the first restart; it doubles after every
+	// restart up to MaxBackoff (unless 0)
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// stablePeriod returns how long a run must last for the service to be
+// considered recovered, so that its restarts and backoff are reset: the
+// longest delay between restarts
+func (p RestartPolicy) stablePeriod() time.Duration {
+	if p.MaxBackoff > 0 {
+		return p.MaxBackoff
+	}
+	return p.Backoff
+}
+
+// runOpts are the options of Run
+type runOpts struct {
+	restart  RestartPolicy
+	restarts map[string]RestartPolicy
+}
+
+// RunOptFn is a functional option for Run
+type RunOptFn func(*runOpts)
+
+// WithRestartPolicy sets the restart policy of all services without a policy
+// of their own; by default services are not restarted
+func WithRestartPolicy(p RestartPolicy) RunOptFn {
+	return func(o *runOpts) {
+		o.restart = p
+	}
+}
+
+// WithServiceRestartPolicy sets the restart policy of the service named name
+func WithServiceRestartPolicy(name string, p RestartPolicy) RunOptFn {
+	return func(o *runOpts) {
+		if o.restarts == nil {
+			o.restarts = map[string]RestartPolicy{}
+		}
+		o.restarts[name] = p
+	}
+}
+
+// policyOf returns the restart policy of s
+func (o *runOpts) policyOf(s Service) RestartPolicy {
+	if p, ok := o.restarts[s.Name()]; ok {
+		return p
+	}
+	return o.restart
+}
+
+// supervise runs r, restarting it according to p while it fails and ctx is
+// not done. A run lasting longer than the stable period of p resets the
+// restarts and backoff, so that failures far apart don't exhaust the retries.
+// It returns the error of the last run.
+func supervise(ctx context.Context, logger *slog.Logger, r Runner, p RestartPolicy) error {
+	backoff := p.Backoff
+	for restarts := 0; ; restarts++ {
+		started := time.Now()
+		err := r.Run(ctx)
+		if err == nil || ctx.Err() != nil || !p.Restart {
+			return err
+		}
+		if restarts > 0 && time.Since(started) > p.stablePeriod() {
+			restarts, backoff = 0, p.Backoff
+		}
+		if p.MaxRetries > 0 && restarts >= p.MaxRetries {
+			return fmt.Errorf("service %s failed after %d restarts: %w", r.Name(), restarts, err)
+		}
+
+		logger.Warn("service failed; restarting", "service", r.Name(), "error", err,
+			"restart", restarts+1, "backoff", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 {
+			backoff = min(backoff, p.MaxBackoff)
+		}
+	}
+}
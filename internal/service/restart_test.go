@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunRestart(t *testing.T) {
+	runErr := errors.New("run error")
+
+	t.Run("failing service is restarted until it succeeds", func(t *testing.T) {
+		svc := &mockRunShutdownService{mockService: mockService{name: "svc"}}
+		svc.runFn = func(ctx context.Context) error {
+			if svc.runCount < 3 {
+				return runErr
+			}
+			return nil
+		}
+
+		err := Run(context.Background(), nil, []Service{svc},
+			WithRestartPolicy(RestartPolicy{Restart: true, Backoff: time.Millisecond}))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, svc.runCount)
+		assert.Equal(t, 1, svc.shutdownCount)
+	})
+
+	t.Run("failing service stops after max retries", func(t *testing.T) {
+		svc := &mockRunShutdownService{
+			mockService: mockService{name: "svc"},
+			runFn:       func(ctx context.Context) error { return runErr },
+		}
+
+		err := Run(context.Background(), nil, []Service{svc},
+			WithRestartPolicy(RestartPolicy{Restart: true, MaxRetries: 2, Backoff: time.Millisecond}))
+		assert.ErrorIs(t, err, runErr)
+		assert.EqualError(t, err, "service svc failed after 2 restarts: run error")
+		assert.Equal(t, 3, svc.runCount)
+	})
+
+	t.Run("policies are set per service", func(t *testing.T) {
+		rerun := make(chan struct{})
+		restarted := &mockRunner{mockService: mockService{name: "restarted"}}
+		restarted.runFn = func(ctx context.Context) error {
+			if restarted.runCount < 2 {
+				return runErr
+			}
+			close(rerun)
+			<-ctx.Done()
+			return nil
+		}
+		failFast := &mockRunner{
+			mockService: mockService{name: "fail-fast"},
+			runFn: func(ctx context.Context) error {
+				<-rerun
+				return runErr
+			},
+		}
+
+		err := Run(context.Background(), nil, []Service{restarted, failFast},
+			WithRestartPolicy(RestartPolicy{Restart: true, Backoff: time.Millisecond}),
+			WithServiceRestartPolicy("fail-fast", RestartPolicy{}))
+		assert.ErrorIs(t, err, runErr)
+		assert.Equal(t, 1, failFast.runCount)
+	})
+
+	t.Run("restart is cancelled during backoff", func(t *testing.T) {
+		svc := &mockRunner{
+			mockService: mockService{name: "svc"},
+			runFn:       func(ctx context.Context) error { return runErr },
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := Run(ctx, nil, []Service{svc},
+			WithRestartPolicy(RestartPolicy{Restart: true, Backoff: time.Hour}))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, svc.runCount)
+	})
+}
+
+func TestSuperviseBackoff(t *testing.T) {
+	var runs []time.Time
+	svc := &mockRunner{mockService: mockService{name: "svc"}}
+	svc.runFn = func(ctx context.Context) error {
+		runs = append(runs, time.Now())
+		return errors.New("run error")
+	}
+
+	err := supervise(context.Background(), slog.Default(), svc,
+		RestartPolicy{Restart: true, MaxRetries: 3, Backoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond})
+	assert.Error(t, err)
+	assert.Len(t, runs, 4)
+
+	// backoff doubles up to max backoff: 10ms, 20ms, 20ms
+	for i, min := range []time.Duration{10, 20, 20} {
+		assert.GreaterOrEqual(t, runs[i+1].Sub(runs[i]), min*time.Millisecond)
+	}
+}
+
+func TestSuperviseReset(t *testing.T) {
+	runErr := errors.New("run error")
+	var runs []time.Time
+	svc := &mockRunner{mockService: mockService{name: "svc"}}
+	svc.runFn = func(ctx context.Context) error {
+		runs = append(runs, time.Now())
+		switch len(runs) {
+		case 3:
+			// runs longer than max backoff before failing again
+			time.Sleep(50 * time.Millisecond)
+			return runErr
+		case 5:
+			return nil
+		}
+		return runErr
+	}
+
+	err := supervise(context.Background(), slog.Default(), svc,
+		RestartPolicy{Restart: true, MaxRetries: 2, Backoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond})
+	// without the reset, the third failure would exceed the max retries
+	assert.NoError(t, err)
+	assert.Len(t, runs, 5)
+}
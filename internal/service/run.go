@@ -7,25 +7,60 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"slices"
+	"sync"
 
 	"github.com/oklog/run"
 )
 
-// Run runs all services that implement the Runner interface.
-// It returns an error if any service fails.
-func Run(outer context.Context, logger *slog.Logger, services []Service) error {
+// Run runs all services that implement the Runner interface. Services are run
+// once the services they depend on that implement Readier are ready, and shut
+// down before the services they depend on, once all services that implement
+// Drainer are drained. Failing services are restarted according to their
+// RestartPolicy.
+// It returns an error if any service fails and is not restarted.
+func Run(outer context.Context, logger *slog.Logger, services []Service, applyOpts ...RunOptFn) error {
 	if logger == nil {
 		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
 	}
+	opts := runOpts{}
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	services, err := Order(services)
+	if err != nil {
+		return err
+	}
+	managed := make(map[Service]bool, len(services))
+	for _, s := range services {
+		managed[s] = true
+	}
 
 	logger.Info("Running all services")
 	ctx, cancel := context.WithCancel(outer)
 	defer cancel()
+	// services are drained, once, when the first of them is interrupted
+	var drainOnce sync.Once
+	drain := func() {
+		for _, s := range services {
+			drainer, ok := s.(Drainer)
+			if !ok {
+				continue
+			}
+			logger.Info("draining", "service", s.Name())
+			if err := drainer.Drain(); err != nil {
+				logger.Warn("service drain failed with error", "service", s.Name(), "error", err)
+			}
+		}
+	}
+
 	// Create run group
 	var g run.Group
 
-	// Add services to run group
-	for _, s := range services {
+	// Add services to run group in reverse order, as the run group interrupts
+	// (and so shuts down) services in the order they are added
+	for _, s := range slices.Backward(services) {
 		runner, ok := s.(Runner)
 		if !ok {
 			logger.Warn("skipping service", "service", s.Name())
@@ -35,16 +70,26 @@ func Run(outer context.Context, logger *slog.Logger, services []Service) error {
 		// Create local copies of the variables for the closure
 		svc := s
 		r := runner
+		ready := readiness(svc, managed)
+		policy := opts.policyOf(svc)
 		g.Add(
 			func() error {
+				for _, ch := range ready {
+					select {
+					case <-ch:
+					case <-ctx.Done():
+						return nil
+					}
+				}
 				logger.Info("Running service", "service", svc.Name())
-				return r.Run(ctx)
+				return supervise(ctx, logger, r, policy)
 			},
 			func(err error) {
 				cancel()
 				if err != nil {
 					logger.Warn("service terminated", "service", svc.Name(), "reason", err)
 				}
+				drainOnce.Do(drain)
 
 				shutdowner, ok := svc.(Shutdowner)
 				if !ok {
@@ -30,3 +30,42 @@ type Shutdowner interface {
 	// Shutdown shuts down the service
 	Shutdown() error
 }
+
+// Dependent is the interface that services must implement that depend on other
+// services. Dependencies are initialized and run before and shut down after the
+// services that depend on them
+type Dependent interface {
+	Service
+	// Dependencies returns the services the service depends on; those that are
+	// not managed (e.g. nil or not passed to Init and Run) are ignored
+	Dependencies() []Service
+}
+
+// Readier is the interface that runners must implement that are not ready to
+// be used as soon as they are run; services that depend on them are not run
+// until they are ready
+type Readier interface {
+	Service
+	// Ready returns a channel that is closed once the service is ready
+	Ready() <-chan struct{}
+}
+
+// HealthChecker is the interface that services must implement that can check
+// their health, e.g. to report it to liveness and readiness probes
+type HealthChecker interface {
+	Service
+	// Health returns nil if the service is healthy, or else the reason it
+	// is not
+	Health() error
+}
+
+// Drainer is the interface that services must implement that produce data for
+// other services, e.g. to export it, and stop producing it on shutdown. All
+// services are drained, in the order they depend on each other, before any
+// service is shut down, so that the data drained is available to services
+// that depend on them when they are shut down
+type Drainer interface {
+	Service
+	// Drain stops producing data once the last of it is produced
+	Drain() error
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+// version is the version of the format of the state file
+const version = 1
+
+// file is the content of the state file. Checksum is the SHA-256 of Totals as
+// written, so that a file truncated or corrupted, e.g. by a crash while it was
+// written, is not restored.
+type file struct {
+	Version  int             `json:"version"`
+	SavedAt  time.Time       `json:"savedAt"`
+	Checksum string          `json:"checksum"`
+	Totals   json.RawMessage `json:"totals"`
+}
+
+// checksum returns the hex encoded SHA-256 of data
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Save writes totals to the state file at path, replacing it atomically
+func Save(path string, totals *monitor.Totals, savedAt time.Time) error {
+	data, err := json.Marshal(totals)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	content, err := json.Marshal(file{
+		Version:  version,
+		SavedAt:  savedAt,
+		Checksum: checksum(data),
+		Totals:   data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	// the file is written next to the state file and renamed over it, so that
+	// the state file is either the previous one or the new one
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create state file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+// Load reads the totals saved in the state file at path and when they were
+// saved; totals are nil if there is no state file
+func Load(path string) (*monitor.Totals, time.Time, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var f file
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	if f.Version != version {
+		return nil, time.Time{}, fmt.Errorf("invalid state file %s: unsupported version %d", path, f.Version)
+	}
+	// the totals are written compacted; they are compacted again in case the
+	// file was reformatted
+	var data bytes.Buffer
+	if err := json.Compact(&data, f.Totals); err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	if sum := checksum(data.Bytes()); sum != f.Checksum {
+		return nil, time.Time{}, fmt.Errorf("invalid state file %s: checksum %s doesn't match its totals, %s", path, f.Checksum, sum)
+	}
+
+	var totals monitor.Totals
+	if err := json.Unmarshal(data.Bytes(), &totals); err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	return &totals, f.SavedAt, nil
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+)
+
+func testTotals() *monitor.Totals {
+	return &monitor.Totals{
+		Node: map[string]monitor.NodeTotals{
+			"package": {EnergyTotal: 1000 * device.Joule, ActiveEnergyTotal: 600 * device.Joule, IdleEnergyTotal: 400 * device.Joule, EmissionsTotal: 0.2},
+		},
+		Containers: map[string]monitor.WorkloadTotals{
+			"c-1": {"package": {EnergyTotal: 300 * device.Joule, CostTotal: 0.01}},
+		},
+		Pods: map[string]monitor.WorkloadTotals{
+			"pod-1": {"package": {EnergyTotal: 300 * device.Joule}},
+		},
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	savedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, Save(path, testTotals(), savedAt))
+
+	totals, at, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, testTotals(), totals)
+	assert.True(t, savedAt.Equal(at))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left")
+
+	t.Run("reformatted", func(t *testing.T) {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var indented bytes.Buffer
+		require.NoError(t, json.Indent(&indented, content, "", "  "))
+		require.NoError(t, os.WriteFile(path, indented.Bytes(), 0o644))
+
+		totals, _, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, testTotals(), totals)
+	})
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	totals, _, err := Load(filepath.Join(dir, "missing.json"))
+	assert.NoError(t, err, "no state file is not an error")
+	assert.Nil(t, totals)
+
+	path := filepath.Join(dir, "state.json")
+	require.NoError(t, Save(path, testTotals(), time.Now()))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"truncated", string(content[:len(content)/2]), "invalid state file"},
+		{"tampered", strings.Replace(string(content), `"energyTotal":300000000`, `"energyTotal":900000000`, 1), "doesn't match its totals"},
+		{"version", strings.Replace(string(content), `"version":1`, `"version":2`, 1), "unsupported version 2"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o644))
+			totals, _, err := Load(path)
+			assert.ErrorContains(t, err, tc.err)
+			assert.Nil(t, totals)
+		})
+	}
+}
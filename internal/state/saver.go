@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	"github.com/sustainable-computing-io/kepler/internal/service"
+	"k8s.io/utils/clock"
+)
+
+type Monitor = monitor.Service
+
+type Opts struct {
+	logger   *slog.Logger
+	clock    clock.WithTicker
+	interval time.Duration
+	events   *event.Bus
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		logger:   slog.Default(),
+		clock:    clock.RealClock{},
+		interval: time.Minute,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithLogger sets the logger for the Saver
+func WithLogger(logger *slog.Logger) OptionFn {
+	return func(o *Opts) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock the state is saved with
+func WithClock(c clock.WithTicker) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithInterval sets the interval between saves
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithEventBus saves the counters of the first snapshot the monitor publishes
+// on bus after each interval, rather than the last one every interval
+func WithEventBus(bus *event.Bus) OptionFn {
+	return func(o *Opts) {
+		o.events = bus
+	}
+}
+
+// Saver saves the cumulative counters of the monitor to the state file every
+// interval and when Kepler stops, so that they are restored when it restarts
+type Saver struct {
+	logger    *slog.Logger
+	monitor   Monitor
+	path      string
+	clock     clock.WithTicker
+	interval  time.Duration
+	events    *event.Bus
+	snapshots *event.Subscription // nil if saved every interval
+
+	// guards lastSnapshot, as the counters are saved by Run and by Shutdown
+	mu           sync.Mutex
+	lastSnapshot time.Time
+}
+
+var (
+	_ service.Initializer = (*Saver)(nil)
+	_ service.Runner      = (*Saver)(nil)
+	_ service.Shutdowner  = (*Saver)(nil)
+	_ service.Dependent   = (*Saver)(nil)
+)
+
+// NewSaver creates a new Saver of the counters of pm to the state file at path
+func NewSaver(pm Monitor, path string, applyOpts ...OptionFn) *Saver {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Saver{
+		logger:   opts.logger.With("service", "state"),
+		monitor:  pm,
+		path:     path,
+		clock:    opts.clock,
+		interval: opts.interval,
+		events:   opts.events,
+	}
+}
+
+func (s *Saver) Name() string {
+	return "state"
+}
+
+// Dependencies returns the monitor whose counters are saved
+func (s *Saver) Dependencies() []service.Service {
+	return []service.Service{s.monitor}
+}
+
+func (s *Saver) Init() error {
+	if s.events != nil {
+		s.snapshots = s.events.Subscribe(s.Name(), 1, event.SnapshotReady)
+	}
+	return nil
+}
+
+// Run saves the counters every interval until ctx is cancelled
+func (s *Saver) Run(ctx context.Context) error {
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var ready <-chan event.Event
+	if s.snapshots != nil {
+		defer s.snapshots.Close()
+		ready = s.snapshots.C()
+	}
+
+	// due is set at each interval until the next snapshot is ready
+	due := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			if ready != nil {
+				due = true
+				continue
+			}
+		case <-ready:
+			if !due {
+				continue
+			}
+			due = false
+		}
+		s.save()
+	}
+}
+
+// Shutdown saves the counters of the last snapshot, which the monitor computes
+// on shutdown
+func (s *Saver) Shutdown() error {
+	s.save()
+	return nil
+}
+
+// save saves the counters of the last snapshot, unless they were already saved
+func (s *Saver) save() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, err := s.monitor.LatestSnapshot()
+	if err != nil {
+		s.logger.Warn("Failed to get snapshot", "error", err)
+		return
+	}
+	if !snapshot.Timestamp.After(s.lastSnapshot) {
+		return // already saved
+	}
+
+	if err := Save(s.path, monitor.TotalsOf(snapshot), s.clock.Now()); err != nil {
+		s.logger.Error("Failed to save state", "path", s.path, "error", err)
+		return
+	}
+	s.lastSnapshot = snapshot.Timestamp
+	s.logger.Debug("Saved state", "path", s.path)
+}
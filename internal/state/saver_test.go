@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"github.com/sustainable-computing-io/kepler/internal/event"
+	"github.com/sustainable-computing-io/kepler/internal/monitor"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var pkg = device.NewMockRaplZone("package", 0, "/sys/class/powercap/intel-rapl/intel-rapl:0", 1000*device.Joule)
+
+// snapshot returns a snapshot at ts of the node and a container with their
+// cumulative energy in joules
+func snapshot(ts time.Time, nodeJoules, containerJoules float64) *monitor.Snapshot {
+	s := monitor.NewSnapshot()
+	s.Timestamp = ts
+	s.Node = &monitor.Node{Timestamp: ts, Zones: monitor.NodeZoneUsageMap{
+		pkg: {EnergyTotal: monitor.Energy(nodeJoules) * device.Joule},
+	}}
+	s.Containers["c-1"] = &monitor.Container{ID: "c-1", Zones: monitor.ZoneUsageMap{
+		pkg: {EnergyTotal: monitor.Energy(containerJoules) * device.Joule},
+	}}
+	s.Containers[monitor.OtherWorkload] = &monitor.Container{ID: monitor.OtherWorkload, Zones: monitor.ZoneUsageMap{
+		pkg: {EnergyTotal: monitor.Energy(containerJoules) * device.Joule},
+	}}
+	return s
+}
+
+func TestSaver(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	pm := monitor.NewFakeMonitor(nil)
+	pm.Set(snapshot(fakeClock.Now(), 100, 10))
+	path := filepath.Join(t.TempDir(), "state.json")
+	saver := NewSaver(pm, path, WithClock(fakeClock), WithInterval(time.Minute))
+	assert.Equal(t, "state", saver.Name())
+	assert.Equal(t, pm, saver.Dependencies()[0])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- saver.Run(ctx) }()
+
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, 10*time.Millisecond)
+	fakeClock.Step(time.Minute)
+	require.Eventually(t, func() bool {
+		totals, _, err := Load(path)
+		return err == nil && totals != nil
+	}, time.Second, 10*time.Millisecond)
+
+	totals, _, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 100*device.Joule, totals.Node["package"].EnergyTotal)
+	assert.Equal(t, 10*device.Joule, totals.Containers["c-1"]["package"].EnergyTotal)
+	assert.NotContains(t, totals.Containers, monitor.OtherWorkload, "the other workloads have no stable ID")
+
+	// the counters are saved once more when Kepler stops
+	pm.Set(snapshot(fakeClock.Now().Add(time.Second), 200, 20))
+	cancel()
+	require.NoError(t, <-done)
+	require.NoError(t, saver.Shutdown())
+	totals, _, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 200*device.Joule, totals.Node["package"].EnergyTotal)
+	assert.Equal(t, 20*device.Joule, totals.Containers["c-1"]["package"].EnergyTotal)
+}
+
+func TestSaverEvents(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	bus := event.NewBus()
+	pm := monitor.NewFakeMonitor(nil)
+	pm.Set(snapshot(fakeClock.Now(), 100, 10))
+	path := filepath.Join(t.TempDir(), "state.json")
+	saver := NewSaver(pm, path, WithClock(fakeClock), WithInterval(time.Minute), WithEventBus(bus))
+	require.NoError(t, saver.Init())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- saver.Run(ctx) }()
+
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, 10*time.Millisecond)
+	fakeClock.Step(time.Minute)
+	assert.Never(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, 100*time.Millisecond, 10*time.Millisecond, "the counters are saved once the next snapshot is ready")
+
+	bus.Publish(event.Event{Kind: event.SnapshotReady})
+	assert.Eventually(t, func() bool {
+		totals, _, err := Load(path)
+		return err == nil && totals != nil
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
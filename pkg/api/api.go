@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package api defines the versioned wire format of a snapshot of the power of
+// a node and its workloads, serialized as JSON or protobuf (snapshot.proto),
+// for tools that read the power from Kepler.
+//
+// Fields are only added within a version: fields of the protobuf encoding are
+// never renumbered or reused, and fields unknown to a reader are ignored in
+// both encodings. Removing or changing the meaning of a field requires a new
+// Version, which readers of another version reject.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version is the version of the schema of Snapshot
+const Version = "v1"
+
+// Content types of the encodings of a Snapshot
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// Kinds of workloads
+const (
+	KindProcess     = "process"
+	KindContainer   = "container"
+	KindVM          = "vm"
+	KindPod         = "pod"
+	KindSystemdUnit = "systemd-unit"
+	KindGroup       = "group"
+	KindAggregate   = "aggregate"
+)
+
+// ErrUnsupportedVersion is returned when decoding a snapshot of a version
+// other than Version
+var ErrUnsupportedVersion = errors.New("unsupported snapshot version")
+
+// Snapshot is the power of a node and its workloads at a point in time
+type Snapshot struct {
+	Version   string     `json:"version"`
+	Timestamp time.Time  `json:"timestamp"`
+	Node      Node       `json:"node"`
+	Workloads []Workload `json:"workloads"`
+}
+
+// Node is the power of the node
+type Node struct {
+	UsageRatio      float64             `json:"usageRatio"`                // ratio of the CPU time used
+	CarbonIntensity float64             `json:"carbonIntensity,omitempty"` // gCO2e/kWh; 0 if unknown
+	Price           float64             `json:"price,omitempty"`           // per kWh; 0 if no tariff is set
+	Zones           map[string]NodeZone `json:"zones"`                     // keyed by zone name
+}
+
+// NodeZone is the energy and power of the node in a zone, split into the
+// active power of the workloads and the idle power of the node
+type NodeZone struct {
+	Joules       float64 `json:"joules"` // cumulative energy
+	Watts        float64 `json:"watts"`
+	ActiveJoules float64 `json:"activeJoules"`
+	ActiveWatts  float64 `json:"activeWatts"`
+	IdleJoules   float64 `json:"idleJoules"`
+	IdleWatts    float64 `json:"idleWatts"`
+	MinWatts     float64 `json:"minWatts"` // within the interval
+	MaxWatts     float64 `json:"maxWatts"` // within the interval
+}
+
+// Workload is the energy and power of a workload
+type Workload struct {
+	Kind       string          `json:"kind"`
+	ID         string          `json:"id"` // PID of processes
+	Name       string          `json:"name"`
+	Namespace  string          `json:"namespace,omitempty"`  // of pods
+	Terminated bool            `json:"terminated,omitempty"` // terminated since the previous snapshot
+	Container  string          `json:"container,omitempty"`  // ID of the container of processes
+	VM         string          `json:"vm,omitempty"`         // ID of the VM of processes
+	Pod        string          `json:"pod,omitempty"`        // ID of the pod of containers
+	CPUSeconds float64         `json:"cpuSeconds"`
+	Zones      map[string]Zone `json:"zones"` // keyed by zone name
+}
+
+// Zone is the energy and power of a workload in a zone
+type Zone struct {
+	Joules      float64 `json:"joules"` // cumulative energy
+	Watts       float64 `json:"watts"`
+	ActiveWatts float64 `json:"activeWatts"`
+	IdleWatts   float64 `json:"idleWatts"`
+}
+
+// EncodeJSON writes s to w as JSON
+func EncodeJSON(w io.Writer, s *Snapshot) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// DecodeJSON reads a snapshot from JSON, rejecting snapshots of other versions
+func DecodeJSON(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if err := checkVersion(s.Version); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func checkVersion(v string) error {
+	if v != Version {
+		return fmt.Errorf("%w: %q; expected %q", ErrUnsupportedVersion, v, Version)
+	}
+	return nil
+}
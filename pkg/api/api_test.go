@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sustainable-computing-io/kepler/pkg/api/apipb"
+)
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Version:   Version,
+		Timestamp: time.Date(2025, 6, 1, 12, 0, 0, 500, time.UTC),
+		Node: Node{
+			UsageRatio:      0.4,
+			CarbonIntensity: 250,
+			Zones: map[string]NodeZone{
+				"package": {Joules: 1000, Watts: 50, ActiveJoules: 400, ActiveWatts: 20, IdleJoules: 600, IdleWatts: 30, MinWatts: 45, MaxWatts: 60},
+				"dram":    {Joules: 100, Watts: 5},
+			},
+		},
+		Workloads: []Workload{{
+			Kind:       KindProcess,
+			ID:         "123",
+			Name:       "nginx",
+			Container:  "abc",
+			CPUSeconds: 12.5,
+			Zones:      map[string]Zone{"package": {Joules: 40, Watts: 2, ActiveWatts: 1.5, IdleWatts: 0.5}},
+		}, {
+			Kind:       KindPod,
+			ID:         "pod-uid",
+			Name:       "web",
+			Namespace:  "default",
+			Terminated: true,
+			Zones:      map[string]Zone{},
+		}},
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EncodeJSON(&buf, testSnapshot()))
+	assert.Contains(t, buf.String(), `"version":"v1"`)
+	assert.Contains(t, buf.String(), `"activeJoules":400`)
+
+	got, err := DecodeJSON(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, testSnapshot(), got)
+
+	t.Run("unknown fields are ignored", func(t *testing.T) {
+		got, err := DecodeJSON(strings.NewReader(`{"version":"v1","node":{"zones":{}},"future":1}`))
+		require.NoError(t, err)
+		assert.Equal(t, Version, got.Version)
+	})
+
+	t.Run("other versions are rejected", func(t *testing.T) {
+		_, err := DecodeJSON(strings.NewReader(`{"version":"v2"}`))
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DecodeJSON(strings.NewReader(`{`))
+		assert.ErrorContains(t, err, "invalid snapshot")
+	})
+}
+
+func TestProto(t *testing.T) {
+	b, err := MarshalProto(testSnapshot())
+	require.NoError(t, err)
+	again, err := MarshalProto(testSnapshot())
+	require.NoError(t, err)
+	assert.Equal(t, b, again, "equal snapshots encode equally")
+
+	got, err := UnmarshalProto(b)
+	require.NoError(t, err)
+	assert.Equal(t, testSnapshot(), got)
+
+	t.Run("unknown fields are ignored", func(t *testing.T) {
+		b := protowire.AppendTag(bytes.Clone(b), 99, protowire.VarintType)
+		b = protowire.AppendVarint(b, 42)
+		got, err := UnmarshalProto(b)
+		require.NoError(t, err)
+		assert.Equal(t, testSnapshot(), got)
+	})
+
+	t.Run("other versions are rejected", func(t *testing.T) {
+		s := testSnapshot()
+		s.Version = "v2"
+		b, err := MarshalProto(s)
+		require.NoError(t, err)
+		_, err = UnmarshalProto(b)
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, err := UnmarshalProto(b[:len(b)-3])
+		assert.ErrorContains(t, err, "invalid snapshot")
+	})
+
+	t.Run("invalid UTF-8", func(t *testing.T) {
+		s := testSnapshot()
+		s.Workloads[0].Name = "bad\xffname"
+		b, err := MarshalProto(s)
+		require.NoError(t, err)
+		got, err := UnmarshalProto(b)
+		require.NoError(t, err)
+		assert.Equal(t, "bad\uFFFDname", got.Workloads[0].Name)
+	})
+}
+
+// TestProtoSchema checks the conversions of proto.go between the types of
+// package api and those generated from snapshot.proto: what proto.go encodes
+// is decoded by protobuf into the same values, and what protobuf encodes is
+// decoded by proto.go. Values are compared through JSON, whose names match
+// the schema.
+func TestProtoSchema(t *testing.T) {
+	toJSON := protojson.MarshalOptions{EmitUnpopulated: true}
+
+	t.Run("snapshot", func(t *testing.T) {
+		b, err := MarshalProto(testSnapshot())
+		require.NoError(t, err)
+		msg := &apipb.Snapshot{}
+		require.NoError(t, proto.Unmarshal(b, msg))
+		b, err = toJSON.Marshal(msg)
+		require.NoError(t, err)
+		got, err := DecodeJSON(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Equal(t, testSnapshot(), got, "decoded from the schema")
+
+		var buf bytes.Buffer
+		require.NoError(t, EncodeJSON(&buf, testSnapshot()))
+		msg = &apipb.Snapshot{}
+		require.NoError(t, protojson.Unmarshal(buf.Bytes(), msg))
+		b, err = proto.Marshal(msg)
+		require.NoError(t, err)
+		got, err = UnmarshalProto(b)
+		require.NoError(t, err)
+		assert.Equal(t, testSnapshot(), got, "encoded from the schema")
+	})
+
+	t.Run("delta", func(t *testing.T) {
+		d := Diff(testSnapshot(), nextSnapshot())
+		b, err := MarshalDeltaProto(d)
+		require.NoError(t, err)
+		msg := &apipb.Delta{}
+		require.NoError(t, proto.Unmarshal(b, msg))
+		b, err = toJSON.Marshal(msg)
+		require.NoError(t, err)
+		got, err := DecodeDeltaJSON(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Equal(t, d, got, "decoded from the schema")
+
+		b, err = json.Marshal(d)
+		require.NoError(t, err)
+		msg = &apipb.Delta{}
+		require.NoError(t, protojson.Unmarshal(b, msg))
+		b, err = proto.Marshal(msg)
+		require.NoError(t, err)
+		got, err = UnmarshalDeltaProto(b)
+		require.NoError(t, err)
+		assert.Equal(t, d, got, "encoded from the schema")
+	})
+}
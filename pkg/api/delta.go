@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sustainable-computing-io/kepler/pkg/api/apipb"
+)
+
+// ErrBaseMismatch is returned when applying a delta to a snapshot other than
+// its base, e.g. after a delta was missed; readers wait for the next full
+// snapshot then
+var ErrBaseMismatch = errors.New("delta does not apply to snapshot")
+
+// WorkloadRef identifies a workload
+type WorkloadRef struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+// Delta is the change of a snapshot from the snapshot before it, its base.
+// On large nodes most workloads, e.g. idle processes, don't change between
+// snapshots, so transmitting deltas between full snapshots saves bandwidth
+// and serialization. Workloads are compared exactly, so applying the delta to
+// its base yields the snapshot, except for the order of its workloads.
+type Delta struct {
+	Version   string    `json:"version"`
+	Base      time.Time `json:"base"` // timestamp of the base snapshot
+	Timestamp time.Time `json:"timestamp"`
+
+	Node    *Node         `json:"node,omitempty"`    // nil if unchanged
+	Added   []Workload    `json:"added,omitempty"`   // workloads not in the base
+	Changed []Workload    `json:"changed,omitempty"` // workloads of the base that changed
+	Removed []WorkloadRef `json:"removed,omitempty"` // workloads of the base no longer in the snapshot
+}
+
+// Diff returns the delta of s from base
+func Diff(base, s *Snapshot) *Delta {
+	d := &Delta{Version: s.Version, Base: base.Timestamp, Timestamp: s.Timestamp}
+	if !nodeEqual(&base.Node, &s.Node) {
+		node := s.Node
+		d.Node = &node
+	}
+
+	prev := make(map[WorkloadRef]*Workload, len(base.Workloads))
+	for i := range base.Workloads {
+		w := &base.Workloads[i]
+		prev[WorkloadRef{w.Kind, w.ID}] = w
+	}
+	for _, w := range s.Workloads {
+		ref := WorkloadRef{w.Kind, w.ID}
+		p, ok := prev[ref]
+		delete(prev, ref)
+		switch {
+		case !ok:
+			d.Added = append(d.Added, w)
+		case !workloadEqual(p, &w):
+			d.Changed = append(d.Changed, w)
+		}
+	}
+	// removed workloads are listed in the order of the base
+	for _, w := range base.Workloads {
+		if ref := (WorkloadRef{w.Kind, w.ID}); prev[ref] != nil {
+			d.Removed = append(d.Removed, ref)
+		}
+	}
+	return d
+}
+
+// Apply returns the snapshot of d applied to base, which is not modified: the
+// workloads of base in their order, with the changed ones replaced and the
+// removed ones dropped, followed by the added ones. It returns ErrBaseMismatch
+// if base is not the base of d.
+func Apply(base *Snapshot, d *Delta) (*Snapshot, error) {
+	if err := checkVersion(d.Version); err != nil {
+		return nil, err
+	}
+	if base.Version != d.Version || !base.Timestamp.Equal(d.Base) {
+		return nil, fmt.Errorf("%w: base of delta is at %s; snapshot is at %s",
+			ErrBaseMismatch, d.Base.Format(time.RFC3339Nano), base.Timestamp.Format(time.RFC3339Nano))
+	}
+
+	s := &Snapshot{Version: d.Version, Timestamp: d.Timestamp, Node: base.Node}
+	if d.Node != nil {
+		s.Node = *d.Node
+	}
+	changed := make(map[WorkloadRef]*Workload, len(d.Changed))
+	for i := range d.Changed {
+		w := &d.Changed[i]
+		changed[WorkloadRef{w.Kind, w.ID}] = w
+	}
+	removed := make(map[WorkloadRef]bool, len(d.Removed))
+	for _, ref := range d.Removed {
+		removed[ref] = true
+	}
+
+	s.Workloads = make([]Workload, 0, len(base.Workloads)+len(d.Added)-len(d.Removed))
+	for _, w := range base.Workloads {
+		ref := WorkloadRef{w.Kind, w.ID}
+		if removed[ref] {
+			continue
+		}
+		if c, ok := changed[ref]; ok {
+			w = *c
+		}
+		s.Workloads = append(s.Workloads, w)
+	}
+	s.Workloads = append(s.Workloads, d.Added...)
+	return s, nil
+}
+
+func nodeEqual(a, b *Node) bool {
+	return a.UsageRatio == b.UsageRatio && a.CarbonIntensity == b.CarbonIntensity && a.Price == b.Price &&
+		maps.Equal(a.Zones, b.Zones)
+}
+
+func workloadEqual(a, b *Workload) bool {
+	return a.Kind == b.Kind && a.ID == b.ID && a.Name == b.Name && a.Namespace == b.Namespace &&
+		a.Terminated == b.Terminated && a.Container == b.Container && a.VM == b.VM && a.Pod == b.Pod &&
+		a.CPUSeconds == b.CPUSeconds && maps.Equal(a.Zones, b.Zones)
+}
+
+// DecodeDeltaJSON reads a delta from JSON, rejecting deltas of other versions
+func DecodeDeltaJSON(r io.Reader) (*Delta, error) {
+	var d Delta
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return nil, fmt.Errorf("invalid delta: %w", err)
+	}
+	if err := checkVersion(d.Version); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// MarshalDeltaProto encodes d as the Delta message of snapshot.proto
+func MarshalDeltaProto(d *Delta) ([]byte, error) {
+	msg := &apipb.Delta{
+		Version:   d.Version,
+		Base:      timestampToProto(d.Base),
+		Timestamp: timestampToProto(d.Timestamp),
+	}
+	if d.Node != nil {
+		msg.Node = nodeToProto(d.Node)
+	}
+	for i := range d.Added {
+		msg.Added = append(msg.Added, workloadToProto(&d.Added[i]))
+	}
+	for i := range d.Changed {
+		msg.Changed = append(msg.Changed, workloadToProto(&d.Changed[i]))
+	}
+	for _, ref := range d.Removed {
+		msg.Removed = append(msg.Removed, &apipb.WorkloadRef{Kind: validUTF8(ref.Kind), Id: validUTF8(ref.ID)})
+	}
+	b, err := marshalOptions.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode delta: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalDeltaProto decodes a Delta message of snapshot.proto, rejecting
+// deltas of other versions
+func UnmarshalDeltaProto(b []byte) (*Delta, error) {
+	var msg apipb.Delta
+	if err := proto.Unmarshal(b, &msg); err != nil {
+		return nil, fmt.Errorf("invalid delta: %w", err)
+	}
+	if err := checkVersion(msg.Version); err != nil {
+		return nil, err
+	}
+
+	d := &Delta{
+		Version:   msg.Version,
+		Base:      timestampFromProto(msg.Base),
+		Timestamp: timestampFromProto(msg.Timestamp),
+	}
+	if msg.Node != nil {
+		n := nodeFromProto(msg.Node)
+		d.Node = &n
+	}
+	for _, w := range msg.Added {
+		d.Added = append(d.Added, workloadFromProto(w))
+	}
+	for _, w := range msg.Changed {
+		d.Changed = append(d.Changed, workloadFromProto(w))
+	}
+	for _, ref := range msg.Removed {
+		d.Removed = append(d.Removed, WorkloadRef{Kind: ref.Kind, ID: ref.Id})
+	}
+	return d, nil
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextSnapshot returns the snapshot after testSnapshot: the process consumed
+// more energy, the terminated pod is gone and a container started
+func nextSnapshot() *Snapshot {
+	s := testSnapshot()
+	s.Timestamp = s.Timestamp.Add(5 * time.Second)
+	s.Node.Zones["package"] = NodeZone{Joules: 1250, Watts: 50}
+	s.Workloads[0].CPUSeconds = 13
+	s.Workloads[0].Zones = map[string]Zone{"package": {Joules: 50, Watts: 2}}
+	s.Workloads = []Workload{{
+		Kind:  KindContainer,
+		ID:    "abc",
+		Name:  "nginx",
+		Zones: map[string]Zone{"package": {Joules: 10, Watts: 2}},
+	}, s.Workloads[0], {
+		Kind:  KindProcess,
+		ID:    "1",
+		Name:  "init",
+		Zones: map[string]Zone{},
+	}}
+	return s
+}
+
+func TestDiff(t *testing.T) {
+	base := testSnapshot()
+	base.Workloads = append(base.Workloads, Workload{Kind: KindProcess, ID: "1", Name: "init", Zones: map[string]Zone{}})
+	s := nextSnapshot()
+
+	d := Diff(base, s)
+	assert.Equal(t, Version, d.Version)
+	assert.Equal(t, base.Timestamp, d.Base)
+	assert.Equal(t, s.Timestamp, d.Timestamp)
+	require.NotNil(t, d.Node)
+	assert.Equal(t, s.Node, *d.Node)
+	require.Len(t, d.Added, 1)
+	assert.Equal(t, "abc", d.Added[0].ID)
+	require.Len(t, d.Changed, 1, "unchanged workloads are left out")
+	assert.Equal(t, "123", d.Changed[0].ID)
+	assert.Equal(t, []WorkloadRef{{Kind: KindPod, ID: "pod-uid"}}, d.Removed)
+
+	got, err := Apply(base, d)
+	require.NoError(t, err)
+	assert.Equal(t, s.Timestamp, got.Timestamp)
+	assert.Equal(t, s.Node, got.Node)
+	assert.ElementsMatch(t, s.Workloads, got.Workloads)
+	assert.Len(t, base.Workloads, 3, "the base is not modified")
+
+	t.Run("unchanged", func(t *testing.T) {
+		next := testSnapshot()
+		next.Timestamp = next.Timestamp.Add(time.Second)
+		d := Diff(testSnapshot(), next)
+		assert.Nil(t, d.Node)
+		assert.Empty(t, d.Added)
+		assert.Empty(t, d.Changed)
+		assert.Empty(t, d.Removed)
+
+		got, err := Apply(testSnapshot(), d)
+		require.NoError(t, err)
+		assert.Equal(t, next, got)
+	})
+
+	t.Run("base mismatch", func(t *testing.T) {
+		_, err := Apply(s, d)
+		assert.ErrorIs(t, err, ErrBaseMismatch)
+	})
+
+	t.Run("other versions are rejected", func(t *testing.T) {
+		d := Diff(base, s)
+		d.Version = "v2"
+		_, err := Apply(base, d)
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+	})
+}
+
+func TestDeltaEncoding(t *testing.T) {
+	d := Diff(testSnapshot(), nextSnapshot())
+
+	t.Run("JSON", func(t *testing.T) {
+		b, err := json.Marshal(d)
+		require.NoError(t, err)
+		got, err := DecodeDeltaJSON(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Equal(t, d, got)
+
+		_, err = DecodeDeltaJSON(strings.NewReader(`{"version":"v2"}`))
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+		_, err = DecodeDeltaJSON(strings.NewReader(`{`))
+		assert.ErrorContains(t, err, "invalid delta")
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		b, err := MarshalDeltaProto(d)
+		require.NoError(t, err)
+		got, err := UnmarshalDeltaProto(b)
+		require.NoError(t, err)
+		assert.Equal(t, d, got)
+
+		unchanged := &Delta{Version: Version, Base: d.Base, Timestamp: d.Timestamp}
+		b2, err := MarshalDeltaProto(unchanged)
+		require.NoError(t, err)
+		got, err = UnmarshalDeltaProto(b2)
+		require.NoError(t, err)
+		assert.Equal(t, unchanged, got)
+
+		_, err = UnmarshalDeltaProto(b[:len(b)-3])
+		assert.ErrorContains(t, err, "invalid delta")
+	})
+}
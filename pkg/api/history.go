@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "time"
+
+// HistoryRow is the power of the node or of a workload in a zone in an
+// interval, as recorded by the history
+type HistoryRow struct {
+	Timestamp time.Time `json:"timestamp"`    // end of the interval
+	Kind      string    `json:"kind"`         // node or a kind of workload
+	ID        string    `json:"id,omitempty"` // empty for the node
+	Name      string    `json:"name,omitempty"`
+	Namespace string    `json:"namespace,omitempty"` // of pods and their containers
+	Zone      string    `json:"zone"`
+	Watts     float64   `json:"watts"`  // average power in the interval
+	Joules    float64   `json:"joules"` // energy consumed in the interval
+
+	// Uncertainty is the relative uncertainty of Watts, e.g. 0.2 if the power
+	// is estimated within ±20%; 0 if it is measured
+	Uncertainty float64 `json:"uncertainty,omitempty"`
+}
+
+// History is the response of the history endpoint
+type History struct {
+	Rows []HistoryRow `json:"rows"`
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/sustainable-computing-io/kepler/pkg/api snapshot.proto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sustainable-computing-io/kepler/pkg/api/apipb"
+)
+
+// marshalOptions sorts the entries of maps by key so that equal snapshots
+// encode equally
+var marshalOptions = proto.MarshalOptions{Deterministic: true}
+
+// MarshalProto encodes s as the Snapshot message of snapshot.proto
+func MarshalProto(s *Snapshot) ([]byte, error) {
+	workloads := make([]*apipb.Workload, 0, len(s.Workloads))
+	for i := range s.Workloads {
+		workloads = append(workloads, workloadToProto(&s.Workloads[i]))
+	}
+	b, err := marshalOptions.Marshal(&apipb.Snapshot{
+		Version:   s.Version,
+		Timestamp: timestampToProto(s.Timestamp),
+		Node:      nodeToProto(&s.Node),
+		Workloads: workloads,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalProto decodes a Snapshot message of snapshot.proto, rejecting
+// snapshots of other versions
+func UnmarshalProto(b []byte) (*Snapshot, error) {
+	var msg apipb.Snapshot
+	if err := proto.Unmarshal(b, &msg); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if err := checkVersion(msg.Version); err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{
+		Version:   msg.Version,
+		Timestamp: timestampFromProto(msg.Timestamp),
+	}
+	if msg.Node != nil {
+		s.Node = nodeFromProto(msg.Node)
+	}
+	for _, w := range msg.Workloads {
+		s.Workloads = append(s.Workloads, workloadFromProto(w))
+	}
+	return s, nil
+}
+
+func nodeToProto(n *Node) *apipb.Node {
+	zones := make(map[string]*apipb.NodeZone, len(n.Zones))
+	for name, z := range n.Zones {
+		zones[validUTF8(name)] = &apipb.NodeZone{
+			Joules:       z.Joules,
+			Watts:        z.Watts,
+			ActiveJoules: z.ActiveJoules,
+			ActiveWatts:  z.ActiveWatts,
+			IdleJoules:   z.IdleJoules,
+			IdleWatts:    z.IdleWatts,
+			MinWatts:     z.MinWatts,
+			MaxWatts:     z.MaxWatts,
+		}
+	}
+	return &apipb.Node{
+		UsageRatio:      n.UsageRatio,
+		CarbonIntensity: n.CarbonIntensity,
+		Price:           n.Price,
+		Zones:           zones,
+	}
+}
+
+func nodeFromProto(msg *apipb.Node) Node {
+	n := Node{
+		UsageRatio:      msg.UsageRatio,
+		CarbonIntensity: msg.CarbonIntensity,
+		Price:           msg.Price,
+		Zones:           make(map[string]NodeZone, len(msg.Zones)),
+	}
+	for name, z := range msg.Zones {
+		n.Zones[name] = NodeZone{
+			Joules:       z.GetJoules(),
+			Watts:        z.GetWatts(),
+			ActiveJoules: z.GetActiveJoules(),
+			ActiveWatts:  z.GetActiveWatts(),
+			IdleJoules:   z.GetIdleJoules(),
+			IdleWatts:    z.GetIdleWatts(),
+			MinWatts:     z.GetMinWatts(),
+			MaxWatts:     z.GetMaxWatts(),
+		}
+	}
+	return n
+}
+
+func workloadToProto(w *Workload) *apipb.Workload {
+	zones := make(map[string]*apipb.Zone, len(w.Zones))
+	for name, z := range w.Zones {
+		zones[validUTF8(name)] = &apipb.Zone{
+			Joules:      z.Joules,
+			Watts:       z.Watts,
+			ActiveWatts: z.ActiveWatts,
+			IdleWatts:   z.IdleWatts,
+		}
+	}
+	return &apipb.Workload{
+		Kind:       validUTF8(w.Kind),
+		Id:         validUTF8(w.ID),
+		Name:       validUTF8(w.Name),
+		Namespace:  validUTF8(w.Namespace),
+		Terminated: w.Terminated,
+		Container:  validUTF8(w.Container),
+		Vm:         validUTF8(w.VM),
+		Pod:        validUTF8(w.Pod),
+		CpuSeconds: w.CPUSeconds,
+		Zones:      zones,
+	}
+}
+
+func workloadFromProto(msg *apipb.Workload) Workload {
+	w := Workload{
+		Kind:       msg.Kind,
+		ID:         msg.Id,
+		Name:       msg.Name,
+		Namespace:  msg.Namespace,
+		Terminated: msg.Terminated,
+		Container:  msg.Container,
+		VM:         msg.Vm,
+		Pod:        msg.Pod,
+		CPUSeconds: msg.CpuSeconds,
+		Zones:      make(map[string]Zone, len(msg.Zones)),
+	}
+	for name, z := range msg.Zones {
+		w.Zones[name] = Zone{
+			Joules:      z.GetJoules(),
+			Watts:       z.GetWatts(),
+			ActiveWatts: z.GetActiveWatts(),
+			IdleWatts:   z.GetIdleWatts(),
+		}
+	}
+	return w
+}
+
+// timestampToProto returns t as a google.protobuf.Timestamp; zero times are
+// omitted
+func timestampToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timestampFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// validUTF8 replaces the invalid UTF-8 in s, e.g. in the names of processes,
+// which proto3 strings reject, like encoding/json does
+func validUTF8(s string) string {
+	return strings.ToValidUTF8(s, "\uFFFD")
+}
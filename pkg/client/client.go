@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client is a client of the REST API of Kepler, for Go programs such
+// as operators and schedulers that query the power of a node and its
+// workloads. Responses are in the versioned format of pkg/api.
+package client
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+)
+
+// Endpoints of the API of Kepler used by the client
+const (
+	SnapshotEndpoint = "/api/v1/snapshot"
+	HistoryEndpoint  = "/history"
+)
+
+// ErrNotFound is returned when a workload isn't in the snapshot
+var ErrNotFound = errors.New("workload not found")
+
+type Opts struct {
+	httpClient *http.Client
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithHTTPClient sets the HTTP client requests are sent with, e.g. one with
+// TLS configured
+func WithHTTPClient(c *http.Client) OptionFn {
+	return func(o *Opts) {
+		o.httpClient = c
+	}
+}
+
+// Client queries the Kepler at a base URL, e.g. http://node-1:28282
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// New creates a new Client of the Kepler at baseURL
+func New(baseURL string, applyOpts ...OptionFn) *Client {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    opts.httpClient,
+	}
+}
+
+// Snapshot returns the power of the node and of all workloads in the last
+// collection
+func (c *Client) Snapshot(ctx context.Context) (*api.Snapshot, error) {
+	body, err := c.get(ctx, SnapshotEndpoint, nil, api.ContentTypeProtobuf)
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalProto(body)
+}
+
+// TopConsumers returns the n running workloads of kind, e.g. api.KindPod,
+// that draw the most power in zone, or in all zones if zone is empty, highest
+// first. All are returned if n is not positive.
+func (c *Client) TopConsumers(ctx context.Context, kind, zone string, n int) ([]api.Workload, error) {
+	s, err := c.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []api.Workload
+	for _, w := range s.Workloads {
+		if w.Kind == kind && !w.Terminated {
+			ret = append(ret, w)
+		}
+	}
+	slices.SortStableFunc(ret, func(a, b api.Workload) int {
+		return cmp.Compare(watts(b, zone), watts(a, zone))
+	})
+	if n > 0 && len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret, nil
+}
+
+// watts returns the power of w in zone, or in all zones if zone is empty
+func watts(w api.Workload, zone string) float64 {
+	if zone != "" {
+		return w.Zones[zone].Watts
+	}
+	var total float64
+	for _, z := range w.Zones {
+		total += z.Watts
+	}
+	return total
+}
+
+// ResourcePower returns the power of the workload of kind with id, e.g. the
+// UID of a pod or the PID of a process; ErrNotFound if it isn't running
+func (c *Client) ResourcePower(ctx context.Context, kind, id string) (*api.Workload, error) {
+	s, err := c.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range s.Workloads {
+		if w.Kind == kind && w.ID == id && !w.Terminated {
+			return &w, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNotFound, kind, id)
+}
+
+// HistoryQuery selects the rows of the history; empty fields match all rows
+type HistoryQuery struct {
+	Kind      string // node or a kind of workload
+	ID        string
+	Namespace string
+	Zone      string
+	Start     time.Time // inclusive
+	End       time.Time // exclusive
+	Limit     int       // most recent rows returned; the server default if 0
+}
+
+// History returns the power recorded by the history of Kepler, which must be
+// enabled
+func (c *Client) History(ctx context.Context, q HistoryQuery) ([]api.HistoryRow, error) {
+	params := url.Values{}
+	for name, v := range map[string]string{"kind": q.Kind, "id": q.ID, "namespace": q.Namespace, "zone": q.Zone} {
+		if v != "" {
+			params.Set(name, v)
+		}
+	}
+	for name, t := range map[string]time.Time{"start": q.Start, "end": q.End} {
+		if !t.IsZero() {
+			params.Set(name, t.Format(time.RFC3339))
+		}
+	}
+	if q.Limit > 0 {
+		params.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	body, err := c.get(ctx, HistoryEndpoint, params, api.ContentTypeJSON)
+	if err != nil {
+		return nil, err
+	}
+	var h api.History
+	if err := json.Unmarshal(body, &h); err != nil {
+		return nil, fmt.Errorf("failed to decode history: %w", err)
+	}
+	return h.Rows, nil
+}
+
+// get returns the body of the response to a GET of endpoint
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values, accept string) ([]byte, error) {
+	u := c.baseURL + endpoint
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Kepler: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("kepler responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return io.ReadAll(resp.Body)
+}
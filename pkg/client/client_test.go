@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/pkg/api"
+)
+
+func testServer(t *testing.T) *httptest.Server {
+	snapshot := &api.Snapshot{
+		Version:   api.Version,
+		Timestamp: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Node:      api.Node{Zones: map[string]api.NodeZone{"package": {Watts: 100}}},
+		Workloads: []api.Workload{
+			{Kind: api.KindPod, ID: "a", Name: "web", Zones: map[string]api.Zone{"package": {Watts: 10}, "dram": {Watts: 1}}},
+			{Kind: api.KindPod, ID: "b", Name: "db", Zones: map[string]api.Zone{"package": {Watts: 5}, "dram": {Watts: 8}}},
+			{Kind: api.KindPod, ID: "c", Name: "job", Terminated: true, Zones: map[string]api.Zone{"package": {Watts: 50}}},
+			{Kind: api.KindContainer, ID: "c-1", Name: "nginx", Pod: "a", Zones: map[string]api.Zone{"package": {Watts: 10}}},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(SnapshotEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, api.ContentTypeProtobuf, r.Header.Get("Accept"))
+		b, err := api.MarshalProto(snapshot)
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", api.ContentTypeProtobuf)
+		_, _ = w.Write(b)
+	})
+	mux.HandleFunc(HistoryEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("kind") == "bogus" {
+			http.Error(w, `invalid kind: "bogus"`, http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, "kind=pod&limit=2&namespace=prod&start=2025-06-01T11%3A00%3A00Z", r.URL.RawQuery)
+		_ = json.NewEncoder(w).Encode(api.History{Rows: []api.HistoryRow{
+			{Timestamp: time.Date(2025, 6, 1, 11, 0, 5, 0, time.UTC), Kind: api.KindPod, ID: "a", Namespace: "prod", Zone: "package", Watts: 9, Joules: 45},
+		}})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient(t *testing.T) {
+	server := testServer(t)
+	c := New(server.URL+"/", WithHTTPClient(server.Client()))
+	ctx := context.Background()
+
+	s, err := c.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, s.Node.Zones["package"].Watts)
+	assert.Len(t, s.Workloads, 4)
+
+	t.Run("top consumers", func(t *testing.T) {
+		top, err := c.TopConsumers(ctx, api.KindPod, "", 0)
+		require.NoError(t, err)
+		require.Len(t, top, 2, "terminated pods are excluded")
+		assert.Equal(t, "db", top[0].Name, "13W in all zones")
+		assert.Equal(t, "web", top[1].Name)
+
+		top, err = c.TopConsumers(ctx, api.KindPod, "package", 1)
+		require.NoError(t, err)
+		require.Len(t, top, 1)
+		assert.Equal(t, "web", top[0].Name)
+	})
+
+	t.Run("resource power", func(t *testing.T) {
+		w, err := c.ResourcePower(ctx, api.KindContainer, "c-1")
+		require.NoError(t, err)
+		assert.Equal(t, "a", w.Pod)
+
+		_, err = c.ResourcePower(ctx, api.KindPod, "c")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		rows, err := c.History(ctx, HistoryQuery{
+			Kind: api.KindPod, Namespace: "prod", Start: time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC), Limit: 2,
+		})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, 45.0, rows[0].Joules)
+
+		_, err = c.History(ctx, HistoryQuery{Kind: "bogus"})
+		assert.ErrorContains(t, err, `kepler responded with 400 Bad Request: invalid kind: "bogus"`)
+	})
+
+	_, err = New("http://127.0.0.1:0").Snapshot(ctx)
+	assert.ErrorContains(t, err, "failed to connect to Kepler")
+}
@@ -0,0 +1,285 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package synthetic
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/resource"
+)
+
+// firstPID is the PID of the first process generated
+const firstPID = 1000
+
+// Informer is a resource.Informer of generated workloads: processes spread
+// over containers, each in its own pod, and the host. At each refresh, some
+// processes and containers terminate and are replaced by new ones, and the
+// busy processes use CPU.
+type Informer struct {
+	rng            *rand.Rand
+	processes      int
+	containers     int
+	busy           float64
+	processChurn   float64
+	containerChurn float64
+	cpus           int
+	interval       time.Duration
+
+	refreshed bool
+	nextPID   int
+	nextID    int
+
+	// pids are the running processes, so that generating them doesn't
+	// depend on the order of maps
+	pids  []int
+	procs map[int]*synthProcess // keyed by PID
+	// slots are the running containers; processes in the last slot, nil, run
+	// on the host
+	slots []*resource.Container
+
+	node    *resource.Node
+	running *resource.Processes
+	ctrs    *resource.Containers
+	pods    *resource.Pods
+	vms     *resource.VirtualMachines
+	units   *resource.SystemdUnits
+	groups  *resource.Groups
+}
+
+// synthProcess is a running process with where it is kept by the Informer
+type synthProcess struct {
+	*resource.Process
+	index int // in pids
+	slot  int
+}
+
+var _ resource.Informer = (*Informer)(nil)
+
+// NewInformer creates a new Informer that generates the workloads at the
+// first refresh
+func NewInformer(applyOpts ...OptionFn) *Informer {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	return &Informer{
+		rng:            rand.New(rand.NewSource(opts.seed)),
+		processes:      opts.processes,
+		containers:     opts.containers,
+		busy:           opts.busy,
+		processChurn:   opts.processChurn,
+		containerChurn: opts.containerChurn,
+		cpus:           opts.cpus,
+		interval:       opts.interval,
+		nextPID:        firstPID,
+		procs:          map[int]*synthProcess{},
+		node:           &resource.Node{},
+		running:        &resource.Processes{Running: map[int]*resource.Process{}, Terminated: map[int]*resource.Process{}},
+		ctrs:           &resource.Containers{Running: map[string]*resource.Container{}, Terminated: map[string]*resource.Container{}},
+		pods:           &resource.Pods{Running: map[string]*resource.Pod{}, Terminated: map[string]*resource.Pod{}},
+		vms:            &resource.VirtualMachines{Running: map[string]*resource.VirtualMachine{}, Terminated: map[string]*resource.VirtualMachine{}},
+		units:          &resource.SystemdUnits{Running: map[string]*resource.SystemdUnit{}, Terminated: map[string]*resource.SystemdUnit{}},
+		groups:         &resource.Groups{Running: map[string]*resource.Group{}, Terminated: map[string]*resource.Group{}},
+	}
+}
+
+func (i *Informer) Name() string {
+	return "synthetic-informer"
+}
+
+func (i *Informer) Init() error {
+	switch {
+	case i.processes < 0 || i.containers < 0:
+		return fmt.Errorf("invalid workloads: %d processes, %d containers; must not be negative", i.processes, i.containers)
+	case i.cpus <= 0:
+		return fmt.Errorf("invalid cpus %d; must be positive", i.cpus)
+	case i.interval <= 0:
+		return fmt.Errorf("invalid interval %s; must be positive", i.interval)
+	}
+	for _, r := range []float64{i.busy, i.processChurn, i.containerChurn} {
+		if r < 0 || r > 1 {
+			return fmt.Errorf("invalid ratio %g; must be between 0 and 1", r)
+		}
+	}
+	return nil
+}
+
+// Refresh generates the workloads at the first refresh and replaces some of
+// them and the CPU time they used at the next ones
+func (i *Informer) Refresh() error {
+	i.running.Terminated = map[int]*resource.Process{}
+	i.ctrs.Terminated = map[string]*resource.Container{}
+	i.pods.Terminated = map[string]*resource.Pod{}
+
+	if !i.refreshed {
+		i.refreshed = true
+		i.slots = make([]*resource.Container, i.containers+1)
+		for s := range i.containers {
+			i.slots[s] = i.startContainer()
+		}
+		for n := range i.processes {
+			i.startProcess(n % len(i.slots))
+		}
+	} else {
+		i.churn()
+	}
+	i.useCPU()
+	return nil
+}
+
+// churn replaces some containers, with their pods and processes, and some
+// processes by new ones
+func (i *Informer) churn() {
+	replaced := map[int]bool{}
+	for _, s := range i.rng.Perm(i.containers)[:ratioOf(i.containers, i.containerChurn)] {
+		old := i.slots[s]
+		delete(i.ctrs.Running, old.ID)
+		delete(i.pods.Running, old.Pod.ID)
+		i.ctrs.Terminated[old.ID] = old
+		i.pods.Terminated[old.Pod.ID] = old.Pod
+		i.slots[s] = i.startContainer()
+		replaced[s] = true
+	}
+	if len(replaced) > 0 {
+		// the processes of the replaced containers are replaced in the new
+		// ones; pids is iterated over a copy since stopProcess reorders it
+		for _, pid := range append([]int(nil), i.pids...) {
+			if s := i.procs[pid].slot; replaced[s] {
+				i.stopProcess(pid)
+				i.startProcess(s)
+			}
+		}
+	}
+
+	for range ratioOf(len(i.pids), i.processChurn) {
+		pid := i.pids[i.rng.Intn(len(i.pids))]
+		s := i.procs[pid].slot
+		i.stopProcess(pid)
+		i.startProcess(s)
+	}
+}
+
+// useCPU sets the CPU time used by the processes since the last refresh, and
+// that of their containers and pods and of the node
+func (i *Informer) useCPU() {
+	for _, c := range i.slots {
+		if c != nil {
+			c.CPUTimeDelta = 0
+			c.Pod.CPUTimeDelta = 0
+		}
+	}
+
+	total := 0.0
+	for _, pid := range i.pids {
+		p := i.procs[pid]
+		p.CPUTimeDelta = 0
+		if i.rng.Float64() < i.busy {
+			p.CPUTimeDelta = i.rng.Float64() * i.interval.Seconds()
+		}
+		p.CPUTotalTime += p.CPUTimeDelta
+		total += p.CPUTimeDelta
+
+		if c := p.Container; c != nil {
+			c.CPUTimeDelta += p.CPUTimeDelta
+			c.CPUTotalTime += p.CPUTimeDelta
+			c.Pod.CPUTimeDelta += p.CPUTimeDelta
+			c.Pod.CPUTotalTime += p.CPUTimeDelta
+		}
+	}
+
+	i.node.ProcessTotalCPUTimeDelta = total
+	i.node.CPUUsageRatio = min(total/(float64(i.cpus)*i.interval.Seconds()), 1)
+}
+
+func (i *Informer) startContainer() *resource.Container {
+	i.nextID++
+	pod := &resource.Pod{
+		ID:        fmt.Sprintf("pod-%d", i.nextID),
+		Name:      fmt.Sprintf("workload-%d", i.nextID),
+		Namespace: "synthetic",
+	}
+	c := &resource.Container{
+		ID:      fmt.Sprintf("container-%d", i.nextID),
+		Name:    fmt.Sprintf("app-%d", i.nextID),
+		Runtime: resource.ContainerDRuntime,
+		Pod:     pod,
+	}
+	i.ctrs.Running[c.ID] = c
+	i.pods.Running[pod.ID] = pod
+	return c
+}
+
+// startProcess starts a process in the container of slot s
+func (i *Informer) startProcess(s int) {
+	pid := i.nextPID
+	i.nextPID++
+
+	p := &resource.Process{
+		PID:  pid,
+		Comm: fmt.Sprintf("proc-%d", pid),
+		Exe:  "/usr/bin/synthetic",
+		Type: resource.RegularProcess,
+	}
+	if c := i.slots[s]; c != nil {
+		p.Type = resource.ContainerProcess
+		p.Container = c
+	}
+	i.procs[pid] = &synthProcess{Process: p, index: len(i.pids), slot: s}
+	i.pids = append(i.pids, pid)
+	i.running.Running[pid] = p
+}
+
+func (i *Informer) stopProcess(pid int) {
+	p := i.procs[pid]
+
+	// the last process takes the place of the stopped one
+	last := i.pids[len(i.pids)-1]
+	i.pids[p.index] = last
+	i.procs[last].index = p.index
+	i.pids = i.pids[:len(i.pids)-1]
+
+	delete(i.procs, pid)
+	delete(i.running.Running, pid)
+	i.running.Terminated[pid] = p.Process
+}
+
+// ratioOf returns the number of n in ratio r, rounded
+func ratioOf(n int, r float64) int {
+	return int(math.Round(float64(n) * r))
+}
+
+func (i *Informer) Node() *resource.Node {
+	return i.node
+}
+
+func (i *Informer) Processes() *resource.Processes {
+	return i.running
+}
+
+func (i *Informer) Containers() *resource.Containers {
+	return i.ctrs
+}
+
+// VirtualMachines returns no VMs
+func (i *Informer) VirtualMachines() *resource.VirtualMachines {
+	return i.vms
+}
+
+func (i *Informer) Pods() *resource.Pods {
+	return i.pods
+}
+
+// SystemdUnits returns no systemd units
+func (i *Informer) SystemdUnits() *resource.SystemdUnits {
+	return i.units
+}
+
+// Groups returns no groups
+func (i *Informer) Groups() *resource.Groups {
+	return i.groups
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package synthetic
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInformer(t *testing.T) {
+	i := NewInformer(WithProcesses(100), WithContainers(9), WithBusyRatio(0.5), WithCPUs(4), WithInterval(time.Second))
+	assert.Equal(t, "synthetic-informer", i.Name())
+	require.NoError(t, i.Init())
+	require.NoError(t, i.Refresh())
+
+	procs := i.Processes()
+	assert.Len(t, procs.Running, 100)
+	assert.Empty(t, procs.Terminated)
+	assert.Len(t, i.Containers().Running, 9)
+	assert.Len(t, i.Pods().Running, 9)
+	assert.Empty(t, i.VirtualMachines().Running)
+
+	host, busy, total := 0, 0, 0.0
+	containers := map[string]float64{}
+	for _, p := range procs.Running {
+		if p.Container == nil {
+			host++
+		} else {
+			containers[p.Container.ID] += p.CPUTimeDelta
+			assert.Same(t, i.Pods().Running[p.Container.Pod.ID], p.Container.Pod)
+		}
+		if p.CPUTimeDelta > 0 {
+			busy++
+		}
+		assert.LessOrEqual(t, p.CPUTimeDelta, 1.0, "a process uses up to a CPU")
+		assert.Equal(t, p.CPUTimeDelta, p.CPUTotalTime)
+		total += p.CPUTimeDelta
+	}
+	assert.Equal(t, 10, host, "processes are spread over the containers and the host")
+	assert.InDelta(t, 50, busy, 15)
+	for id, c := range i.Containers().Running {
+		assert.InDelta(t, containers[id], c.CPUTimeDelta, 1e-9)
+		assert.InDelta(t, c.CPUTimeDelta, c.Pod.CPUTimeDelta, 1e-9)
+	}
+	assert.InDelta(t, total, i.Node().ProcessTotalCPUTimeDelta, 1e-9)
+	assert.InDelta(t, min(total/4, 1), i.Node().CPUUsageRatio, 1e-9)
+
+	t.Run("no churn", func(t *testing.T) {
+		before := slices.Sorted(maps.Keys(procs.Running))
+		require.NoError(t, i.Refresh())
+		assert.Equal(t, before, slices.Sorted(maps.Keys(i.Processes().Running)))
+		assert.Empty(t, i.Processes().Terminated)
+	})
+}
+
+func TestInformerChurn(t *testing.T) {
+	i := NewInformer(WithProcesses(200), WithContainers(10), WithProcessChurn(0.05), WithContainerChurn(0.2))
+	require.NoError(t, i.Init())
+	require.NoError(t, i.Refresh())
+	first := maps.Clone(i.Processes().Running)
+	containers := maps.Clone(i.Containers().Running)
+
+	for range 3 {
+		require.NoError(t, i.Refresh())
+		procs := i.Processes()
+		assert.Len(t, procs.Running, 200, "terminated processes are replaced")
+		assert.Len(t, i.Containers().Running, 10)
+		assert.Len(t, i.Pods().Running, 10)
+		assert.Len(t, i.Containers().Terminated, 2)
+		assert.Len(t, i.Pods().Terminated, 2)
+		// the 18 or 19 processes of each terminated container and 10 others
+		assert.InDelta(t, 47, len(procs.Terminated), 1)
+		for pid := range procs.Terminated {
+			assert.NotContains(t, procs.Running, pid)
+		}
+		for _, p := range procs.Running {
+			if p.Container != nil {
+				assert.Contains(t, i.Containers().Running, p.Container.ID, "processes run in running containers")
+			}
+		}
+	}
+	assert.NotEqual(t, len(first), countRunning(first, i.Processes().Running))
+	assert.NotEqual(t, len(containers), countRunning(containers, i.Containers().Running))
+}
+
+// countRunning returns the number of keys of before in running
+func countRunning[K comparable, V any](before, running map[K]V) int {
+	n := 0
+	for k := range before {
+		if _, ok := running[k]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+func TestInformerSeed(t *testing.T) {
+	workloads := func(seed int64) []float64 {
+		i := NewInformer(WithSeed(seed), WithProcesses(50), WithProcessChurn(0.1))
+		require.NoError(t, i.Init())
+		for range 3 {
+			require.NoError(t, i.Refresh())
+		}
+		var deltas []float64
+		for _, pid := range slices.Sorted(maps.Keys(i.Processes().Running)) {
+			deltas = append(deltas, float64(pid), i.Processes().Running[pid].CPUTotalTime)
+		}
+		return deltas
+	}
+	assert.Equal(t, workloads(7), workloads(7), "workloads of a seed are the same")
+	assert.NotEqual(t, workloads(7), workloads(8))
+}
+
+func TestInformerInit(t *testing.T) {
+	tt := []struct {
+		name string
+		opts []OptionFn
+		err  string
+	}{
+		{"processes", []OptionFn{WithProcesses(-1)}, "invalid workloads"},
+		{"cpus", []OptionFn{WithCPUs(0)}, "invalid cpus"},
+		{"interval", []OptionFn{WithInterval(0)}, "invalid interval"},
+		{"busy", []OptionFn{WithBusyRatio(1.5)}, "invalid ratio 1.5"},
+		{"churn", []OptionFn{WithProcessChurn(-0.1)}, "invalid ratio -0.1"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorContains(t, NewInformer(tc.opts...).Init(), tc.err)
+		})
+	}
+}
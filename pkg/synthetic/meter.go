@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package synthetic
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	"k8s.io/utils/clock"
+)
+
+// maxEnergy is the energy the zones of a CPUMeter wrap around at, like RAPL
+// counters
+const maxEnergy = 1 << 40 * device.MicroJoule
+
+// CPUMeter is a CPU power meter whose zones, package and dram, draw a
+// constant power, so that their energy grows with the time of its clock; dram
+// draws a quarter of the power of package.
+type CPUMeter struct {
+	zones []device.EnergyZone
+}
+
+var _ device.CPUPowerMeter = (*CPUMeter)(nil)
+
+// NewCPUMeter creates a new CPUMeter whose energy starts at the time of its
+// clock
+func NewCPUMeter(applyOpts ...OptionFn) *CPUMeter {
+	opts := DefaultOpts()
+	for _, apply := range applyOpts {
+		apply(&opts)
+	}
+
+	start := opts.clock.Now()
+	return &CPUMeter{zones: []device.EnergyZone{
+		&energyZone{name: "package", clock: opts.clock, start: start, watts: opts.power},
+		&energyZone{name: "dram", clock: opts.clock, start: start, watts: opts.power / 4},
+	}}
+}
+
+func (m *CPUMeter) Name() string {
+	return "synthetic-cpu-meter"
+}
+
+func (m *CPUMeter) Zones() ([]device.EnergyZone, error) {
+	return m.zones, nil
+}
+
+// PrimaryEnergyZone returns the package zone
+func (m *CPUMeter) PrimaryEnergyZone() (device.EnergyZone, error) {
+	return m.zones[0], nil
+}
+
+// energyZone is a zone drawing watts since start
+type energyZone struct {
+	name  string
+	clock clock.PassiveClock
+	start time.Time
+	watts float64
+}
+
+func (z *energyZone) Name() string {
+	return z.name
+}
+
+func (z *energyZone) Index() int {
+	return 0
+}
+
+func (z *energyZone) Path() string {
+	return filepath.Join("/synthetic", z.name)
+}
+
+func (z *energyZone) Energy() (device.Energy, error) {
+	joules := z.watts * z.clock.Since(z.start).Seconds()
+	return device.Energy(joules*float64(device.Joule)) % maxEnergy, nil
+}
+
+func (z *energyZone) MaxEnergy() device.Energy {
+	return maxEnergy
+}
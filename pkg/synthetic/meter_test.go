@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package synthetic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sustainable-computing-io/kepler/internal/device"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestCPUMeter(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	m := NewCPUMeter(WithClock(fakeClock), WithPower(80))
+	assert.Equal(t, "synthetic-cpu-meter", m.Name())
+
+	zones, err := m.Zones()
+	require.NoError(t, err)
+	require.Len(t, zones, 2)
+	primary, err := m.PrimaryEnergyZone()
+	require.NoError(t, err)
+	assert.Equal(t, "package", primary.Name())
+	assert.Equal(t, "/synthetic/package", primary.Path())
+
+	fakeClock.Step(5 * time.Second)
+	energy, err := zones[0].Energy()
+	require.NoError(t, err)
+	assert.Equal(t, 400*device.Joule, energy, "package draws the power")
+	energy, err = zones[1].Energy()
+	require.NoError(t, err)
+	assert.Equal(t, 100*device.Joule, energy, "dram draws a quarter of it")
+
+	fakeClock.Step(time.Duration(float64(zones[0].MaxEnergy()) / float64(80*device.Joule) * float64(time.Second)))
+	energy, err = zones[0].Energy()
+	require.NoError(t, err)
+	assert.InDelta(t, float64(400*device.Joule), float64(energy), float64(device.Joule), "energy wraps around")
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2025 The Kepler Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package synthetic generates the workloads and the power of a node for
+// benchmarks and load tests of Kepler, without reading procfs or a power
+// meter. The Informer generates processes in containers and pods that start,
+// use CPU and terminate, and the CPUMeter draws a constant power, so that the
+// cost of the monitor and of the exporters can be measured for the number of
+// workloads and the churn of a deployment. Workloads are generated from a
+// seed, so that runs with the same options are comparable.
+//
+// The Informer is a resource.Informer and the CPUMeter a device.CPUPowerMeter,
+// passed to monitor.NewPowerMonitor in place of procfs and RAPL:
+//
+//	fakeClock := testingclock.NewFakeClock(time.Now())
+//	opts := []synthetic.OptionFn{
+//		synthetic.WithClock(fakeClock),
+//		synthetic.WithProcesses(10000),
+//		synthetic.WithContainers(1000),
+//	}
+//	pm := monitor.NewPowerMonitor(synthetic.NewCPUMeter(opts...),
+//		monitor.WithClock(fakeClock),
+//		monitor.WithResourceInformer(synthetic.NewInformer(opts...)),
+//	)
+package synthetic
+
+import (
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+type Opts struct {
+	seed           int64
+	processes      int
+	containers     int
+	busy           float64
+	processChurn   float64
+	containerChurn float64
+	cpus           int
+	interval       time.Duration
+	clock          clock.PassiveClock
+	power          float64
+}
+
+// DefaultOpts returns a new Opts with defaults set
+func DefaultOpts() Opts {
+	return Opts{
+		seed:       1,
+		processes:  1000,
+		containers: 100,
+		busy:       0.1,
+		cpus:       16,
+		interval:   5 * time.Second,
+		clock:      clock.RealClock{},
+		power:      100,
+	}
+}
+
+// OptionFn is a function sets one more more options in Opts struct
+type OptionFn func(*Opts)
+
+// WithSeed sets the seed the workloads are generated from
+func WithSeed(seed int64) OptionFn {
+	return func(o *Opts) {
+		o.seed = seed
+	}
+}
+
+// WithProcesses sets the number of running processes
+func WithProcesses(n int) OptionFn {
+	return func(o *Opts) {
+		o.processes = n
+	}
+}
+
+// WithContainers sets the number of running containers, each in its own pod;
+// the processes are spread evenly over the containers and the host
+func WithContainers(n int) OptionFn {
+	return func(o *Opts) {
+		o.containers = n
+	}
+}
+
+// WithBusyRatio sets the ratio of the processes using CPU at each refresh
+func WithBusyRatio(r float64) OptionFn {
+	return func(o *Opts) {
+		o.busy = r
+	}
+}
+
+// WithProcessChurn sets the ratio of the processes replaced by new ones at
+// each refresh
+func WithProcessChurn(r float64) OptionFn {
+	return func(o *Opts) {
+		o.processChurn = r
+	}
+}
+
+// WithContainerChurn sets the ratio of the containers, and of their pods and
+// processes, replaced by new ones at each refresh
+func WithContainerChurn(r float64) OptionFn {
+	return func(o *Opts) {
+		o.containerChurn = r
+	}
+}
+
+// WithCPUs sets the number of CPUs of the node, which bounds the CPU time
+// used by the processes
+func WithCPUs(n int) OptionFn {
+	return func(o *Opts) {
+		o.cpus = n
+	}
+}
+
+// WithInterval sets the interval between refreshes, e.g. the interval of the
+// monitor; a busy process uses up to a CPU during it
+func WithInterval(d time.Duration) OptionFn {
+	return func(o *Opts) {
+		o.interval = d
+	}
+}
+
+// WithClock sets the clock the energy of the CPUMeter grows with; it must be
+// the clock of the monitor
+func WithClock(c clock.PassiveClock) OptionFn {
+	return func(o *Opts) {
+		o.clock = c
+	}
+}
+
+// WithPower sets the power of the package zone of the CPUMeter in watts
+func WithPower(watts float64) OptionFn {
+	return func(o *Opts) {
+		o.power = watts
+	}
+}